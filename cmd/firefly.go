@@ -34,6 +34,7 @@ import (
 	"github.com/hyperledger/firefly/internal/apiserver"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -141,6 +142,13 @@ func run() error {
 		return i18n.WrapError(rootCtx, err, i18n.MsgConfigFailed)
 	}
 
+	// Install the OpenTelemetry exporter, if tracing is enabled
+	if err = tracing.Init(rootCtx); err != nil {
+		cancelRootCtx()
+		return err
+	}
+	defer tracing.Shutdown(context.Background())
+
 	// Setup signal handling to cancel the context, which shuts down the API Server
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
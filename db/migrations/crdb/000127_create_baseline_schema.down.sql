@@ -0,0 +1,34 @@
+DROP TABLE IF EXISTS locks;
+DROP TABLE IF EXISTS verifiers;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS tokentransfer;
+DROP TABLE IF EXISTS tokenpool;
+DROP TABLE IF EXISTS tokenbalance;
+DROP TABLE IF EXISTS tokenapproval;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS pins;
+DROP TABLE IF EXISTS operations;
+DROP TABLE IF EXISTS offsets;
+DROP TABLE IF EXISTS nonces;
+DROP TABLE IF EXISTS nextpins;
+DROP TABLE IF EXISTS namespaces;
+DROP TABLE IF EXISTS messages_data;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS members;
+DROP TABLE IF EXISTS identities;
+DROP TABLE IF EXISTS groups;
+DROP TABLE IF EXISTS ffimethods;
+DROP TABLE IF EXISTS ffievents;
+DROP TABLE IF EXISTS ffierrors;
+DROP TABLE IF EXISTS ffi;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS deadletters;
+DROP TABLE IF EXISTS datatypes;
+DROP TABLE IF EXISTS data;
+DROP TABLE IF EXISTS contractlisteners;
+DROP TABLE IF EXISTS contractapis;
+DROP TABLE IF EXISTS config;
+DROP TABLE IF EXISTS blockchainevents;
+DROP TABLE IF EXISTS blockchainevent_outputs;
+DROP TABLE IF EXISTS blobs;
+DROP TABLE IF EXISTS batches;
@@ -0,0 +1,540 @@
+-- CockroachDB starts from the schema at this version, rather than replaying the PostgreSQL history, as
+-- several of those migrations mix schema changes with writes to the new columns in a single transaction.
+-- Any later migration must be added here as well as to the postgres and sqlite migrations.
+
+-- Sequence columns must be allocated in order (rather than by unique_rowid) for event delivery
+SET serial_normalization = 'sql_sequence';
+
+CREATE TABLE batches (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  btype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  author                VARCHAR(1024)    NOT NULL,
+  group_hash            CHAR(64),
+  hash                  CHAR(64),
+  created               BIGINT           NOT NULL,
+  manifest              TEXT             NOT NULL,
+  confirmed             BIGINT,
+  tx_type               VARCHAR(64)      NOT NULL,
+  tx_id                 UUID,
+  key                   VARCHAR(1024),
+  node_id               UUID
+);
+CREATE INDEX batches_created ON batches(namespace,created);
+CREATE INDEX batches_fortx ON batches(namespace,tx_id);
+CREATE UNIQUE INDEX batches_id ON batches(namespace,id);
+
+CREATE TABLE blobs (
+  seq                   SERIAL           PRIMARY KEY,
+  namespace             VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  payload_ref           VARCHAR(1024)    NOT NULL,
+  created               BIGINT           NOT NULL,
+  peer                  VARCHAR(256)     NOT NULL,
+  size                  BIGINT,
+  data_id               UUID             NOT NULL
+);
+CREATE INDEX blobs_namespace_data_id ON blobs(namespace,data_id);
+CREATE INDEX blobs_payload_ref ON blobs(payload_ref);
+
+CREATE TABLE blockchainevent_outputs (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  output                TEXT
+);
+CREATE UNIQUE INDEX blockchainevent_outputs_id ON blockchainevent_outputs(namespace,id);
+
+CREATE TABLE blockchainevents (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  source                VARCHAR(256)     NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(256)     NOT NULL,
+  protocol_id           VARCHAR(256)     NOT NULL,
+  timestamp             BIGINT           NOT NULL,
+  listener_id           UUID,
+  output                TEXT,
+  info                  TEXT,
+  tx_type               VARCHAR(64),
+  tx_id                 UUID,
+  tx_blockchain_id      VARCHAR(1024),
+  output_truncated      BOOLEAN          DEFAULT false,
+  block_number          BIGINT
+);
+CREATE UNIQUE INDEX blockchainevents_id ON blockchainevents(id);
+CREATE INDEX blockchainevents_listener_block ON blockchainevents(listener_id,block_number);
+CREATE INDEX blockchainevents_listener_id ON blockchainevents(listener_id);
+CREATE UNIQUE INDEX blockchainevents_listener_protocolid ON blockchainevents(namespace,listener_id,protocol_id) WHERE listener_id IS NOT NULL;
+CREATE UNIQUE INDEX blockchainevents_protocolid ON blockchainevents(namespace,protocol_id) WHERE listener_id IS NULL;
+CREATE INDEX blockchainevents_tx ON blockchainevents(tx_id);
+CREATE INDEX blockchainevents_txblockchainid ON blockchainevents(tx_blockchain_id);
+
+CREATE TABLE config (
+  seq                   SERIAL           PRIMARY KEY,
+  config_key            VARCHAR(512)     NOT NULL,
+  config_value          TEXT             NOT NULL
+);
+CREATE UNIQUE INDEX config_config_key ON config(config_key);
+CREATE UNIQUE INDEX config_sequence ON config(seq);
+
+CREATE TABLE contractapis (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  interface_id          UUID             NOT NULL,
+  location              TEXT,
+  name                  VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  message_id            UUID,
+  published             BOOLEAN          DEFAULT false,
+  network_name          VARCHAR(64)
+);
+CREATE UNIQUE INDEX contractapis_id ON contractapis(namespace,id);
+CREATE UNIQUE INDEX contractapis_namespace_name ON contractapis(namespace,name);
+CREATE UNIQUE INDEX contractapis_networkname ON contractapis(namespace,network_name);
+
+CREATE TABLE contractlisteners (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  interface_id          UUID             NULL,
+  event                 TEXT             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NULL,
+  backend_id            VARCHAR(1024)    NOT NULL,
+  created               BIGINT           NOT NULL,
+  options               TEXT,
+  topic                 VARCHAR(64),
+  signature             VARCHAR(1024),
+  location              TEXT,
+  filters               TEXT,
+  state                 VARCHAR(64)
+);
+CREATE UNIQUE INDEX contractlisteners_name ON contractlisteners(namespace,name) WHERE state = 'active';
+CREATE INDEX contractlisteners_signature ON contractlisteners(signature);
+CREATE INDEX contractlisteners_state ON contractlisteners(state);
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners(backend_id);
+
+CREATE TABLE data (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  validator             VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  datatype_name         VARCHAR(64)      NOT NULL,
+  datatype_version      VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  created               BIGINT           NOT NULL,
+  blob_hash             CHAR(64),
+  blob_public           VARCHAR(1024),
+  blob_name             VARCHAR(1024),
+  blob_size             BIGINT,
+  value_size            BIGINT,
+  value                 TEXT,
+  public                VARCHAR(1024),
+  blob_path             VARCHAR(1024)
+);
+CREATE INDEX data_blob_name ON data(blob_name);
+CREATE INDEX data_blob_path ON data(blob_path);
+CREATE INDEX data_blob_size ON data(blob_size);
+CREATE INDEX data_blobs ON data(blob_hash);
+CREATE INDEX data_created ON data(namespace,created);
+CREATE INDEX data_hash ON data(namespace,hash);
+CREATE UNIQUE INDEX data_id ON data(namespace,id);
+
+CREATE TABLE datatypes (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  message_id            UUID             NOT NULL,
+  validator             VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  version               VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  created               BIGINT           NOT NULL,
+  value                 TEXT
+);
+CREATE INDEX datatypes_created ON datatypes(created);
+CREATE UNIQUE INDEX datatypes_id ON datatypes(namespace,id);
+CREATE UNIQUE INDEX datatypes_unique ON datatypes(namespace,name,version);
+
+CREATE TABLE deadletters (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  subscription_id       UUID             NOT NULL,
+  event_id              UUID             NOT NULL,
+  event_type            VARCHAR(64)      NOT NULL,
+  error                 TEXT,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT
+);
+CREATE UNIQUE INDEX deadletters_id ON deadletters(namespace,id);
+CREATE INDEX deadletters_subscription ON deadletters(namespace,subscription_id);
+
+CREATE TABLE events (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  etype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  ref                   UUID,
+  created               BIGINT           NOT NULL,
+  tx_id                 UUID,
+  cid                   UUID,
+  topic                 VARCHAR(64)
+);
+CREATE INDEX events_created ON events(created);
+CREATE UNIQUE INDEX events_id ON events(id);
+CREATE INDEX events_topic ON events(topic);
+
+CREATE TABLE ffi (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  version               VARCHAR(64)      NOT NULL,
+  description           TEXT             NOT NULL,
+  message_id            UUID,
+  published             BOOLEAN          DEFAULT false,
+  network_name          VARCHAR(64)
+);
+CREATE UNIQUE INDEX ffi_id ON ffi(namespace,id);
+CREATE UNIQUE INDEX ffi_name ON ffi(namespace,name,version);
+CREATE UNIQUE INDEX ffi_networkname ON ffi(namespace,network_name,version);
+
+CREATE TABLE ffierrors (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  interface_id          UUID             NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  pathname              VARCHAR(1024)    NOT NULL,
+  description           TEXT             NOT NULL,
+  params                TEXT             NOT NULL
+);
+CREATE UNIQUE INDEX ffierrors_pathname ON ffierrors(interface_id,pathname);
+
+CREATE TABLE ffievents (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  interface_id          UUID             NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  pathname              VARCHAR(1024)    NOT NULL,
+  description           TEXT             NOT NULL,
+  params                TEXT             NOT NULL,
+  details               TEXT
+);
+CREATE UNIQUE INDEX ffievents_pathname ON ffievents(interface_id,pathname);
+
+CREATE TABLE ffimethods (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  interface_id          UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  pathname              VARCHAR(1024)    NOT NULL,
+  description           TEXT             NOT NULL,
+  params                TEXT             NOT NULL,
+  returns               TEXT             NOT NULL,
+  details               TEXT
+);
+CREATE UNIQUE INDEX ffimethods_pathname ON ffimethods(interface_id,pathname);
+
+CREATE TABLE groups (
+  seq                   SERIAL           PRIMARY KEY,
+  message_id            UUID,
+  name                  VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  created               BIGINT           NOT NULL,
+  namespace_local       VARCHAR(64)
+);
+CREATE UNIQUE INDEX groups_hash ON groups(namespace_local,hash);
+
+CREATE TABLE identities (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  did                   VARCHAR(256)     NOT NULL,
+  parent                UUID,
+  messages_verification UUID,
+  messages_update       UUID,
+  itype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  description           VARCHAR(4096)    NOT NULL,
+  profile               TEXT,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT           NOT NULL,
+  messages_claim        UUID
+);
+CREATE UNIQUE INDEX identities_did ON identities(namespace,did);
+CREATE UNIQUE INDEX identities_id ON identities(namespace,id);
+CREATE UNIQUE INDEX identities_name ON identities(itype,namespace,name);
+
+CREATE TABLE members (
+  seq                   SERIAL           PRIMARY KEY,
+  group_hash            CHAR(64)         NOT NULL,
+  idx                   INTEGER          NOT NULL,
+  identity              VARCHAR(1024)    NOT NULL,
+  node_id               UUID             NOT NULL
+);
+CREATE INDEX members_group ON members(group_hash);
+
+CREATE TABLE messages (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  cid                   CHAR(36),
+  mtype                 VARCHAR(64)      NOT NULL,
+  author                VARCHAR(1024)    NOT NULL,
+  created               BIGINT           NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  topics                VARCHAR(1024)    NOT NULL,
+  tag                   VARCHAR(64)      NOT NULL,
+  group_hash            CHAR(64),
+  datahash              CHAR(64)         NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  pins                  VARCHAR(1024)    NOT NULL,
+  confirmed             BIGINT,
+  tx_type               VARCHAR(64)      NOT NULL,
+  batch_id              UUID,
+  key                   VARCHAR(1024),
+  state                 VARCHAR(64),
+  namespace_local       VARCHAR(64),
+  idempotency_key       VARCHAR(256),
+  tx_id                 UUID,
+  tx_parent_id          UUID,
+  reject_reason         TEXT             DEFAULT '',
+  tx_parent_type        VARCHAR(64)      DEFAULT '' NOT NULL,
+  send_after            BIGINT
+);
+CREATE UNIQUE INDEX messages_id ON messages(namespace_local,id);
+CREATE UNIQUE INDEX messages_idempotency_keys ON messages(namespace,idempotency_key);
+CREATE INDEX messages_sortorder ON messages(confirmed,created);
+CREATE INDEX messages_topics_tag ON messages(namespace,topics,tag);
+
+CREATE TABLE messages_data (
+  seq                   SERIAL           PRIMARY KEY,
+  message_id            UUID             NOT NULL,
+  data_id               UUID             NOT NULL,
+  data_hash             CHAR(64)         NOT NULL,
+  data_idx              INTEGER          NOT NULL,
+  namespace             VARCHAR(64)
+);
+CREATE INDEX messages_data_data ON messages_data(namespace,data_id);
+CREATE INDEX messages_data_message ON messages_data(namespace,message_id);
+
+CREATE TABLE namespaces (
+  seq                   SERIAL           PRIMARY KEY,
+  name                  VARCHAR(64)      NOT NULL,
+  description           VARCHAR(4096),
+  created               BIGINT           NOT NULL,
+  firefly_contracts     TEXT,
+  remote_name           VARCHAR(64)
+);
+CREATE UNIQUE INDEX namespaces_name ON namespaces(name);
+
+CREATE TABLE nextpins (
+  seq                   SERIAL           PRIMARY KEY,
+  context               CHAR(64)         NOT NULL,
+  identity              VARCHAR(1024)    NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  nonce                 BIGINT           NOT NULL,
+  namespace             VARCHAR(64)
+);
+CREATE INDEX nextpins_context ON nextpins(namespace,context);
+
+CREATE TABLE nonces (
+  seq                   SERIAL           PRIMARY KEY,
+  hash                  CHAR(64)         NOT NULL,
+  nonce                 BIGINT           NOT NULL
+);
+CREATE INDEX nonces_hash ON nonces(hash);
+
+CREATE TABLE offsets (
+  seq                   SERIAL           PRIMARY KEY,
+  otype                 VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  current               BIGINT           NOT NULL
+);
+CREATE UNIQUE INDEX offsets_unique ON offsets(otype,name);
+
+CREATE TABLE operations (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  tx_id                 UUID             NOT NULL,
+  optype                VARCHAR(64)      NOT NULL,
+  opstatus              VARCHAR(64)      NOT NULL,
+  plugin                VARCHAR(64)      NOT NULL,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT,
+  error                 TEXT             NOT NULL,
+  output                TEXT,
+  input                 TEXT,
+  retry_id              UUID,
+  retry_depth           BIGINT           DEFAULT 0,
+  retry_parent_id       UUID,
+  last_error            TEXT,
+  retry_history         TEXT
+);
+CREATE INDEX operations_created ON operations(created);
+CREATE UNIQUE INDEX operations_id ON operations(id);
+CREATE INDEX operations_retry_parent ON operations(retry_parent_id);
+CREATE INDEX operations_tx ON operations(tx_id);
+CREATE INDEX operations_type_status ON operations(optype,opstatus);
+
+CREATE TABLE pins (
+  seq                   SERIAL           PRIMARY KEY,
+  masked                BOOLEAN          NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  batch_id              UUID             NOT NULL,
+  idx                   BIGINT           NOT NULL,
+  dispatched            BOOLEAN          NOT NULL,
+  created               BIGINT           NOT NULL,
+  signer                TEXT,
+  batch_hash            VARCHAR(64),
+  namespace             VARCHAR(64)
+);
+CREATE INDEX pins_batch ON pins(batch_id);
+CREATE INDEX pins_dispatched ON pins(dispatched);
+CREATE UNIQUE INDEX pins_pin ON pins(namespace,hash,batch_id,idx);
+
+CREATE TABLE subscriptions (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  transport             VARCHAR(64)      NOT NULL,
+  options               TEXT             NOT NULL,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT,
+  filters               TEXT
+);
+CREATE UNIQUE INDEX subscriptions_id ON subscriptions(id);
+CREATE UNIQUE INDEX subscriptions_name ON subscriptions(namespace,name);
+
+CREATE TABLE tokenapproval (
+  seq                   SERIAL           PRIMARY KEY,
+  local_id              UUID             NOT NULL,
+  key                   VARCHAR(1024)    NOT NULL,
+  operator_key          VARCHAR(1024)    NOT NULL,
+  approved              BOOLEAN          NOT NULL,
+  protocol_id           VARCHAR(1024)    NOT NULL,
+  tx_type               VARCHAR(64),
+  connector             VARCHAR(64),
+  namespace             VARCHAR(64),
+  info                  TEXT,
+  tx_id                 UUID,
+  blockchain_event      UUID,
+  created               BIGINT           NOT NULL,
+  subject               VARCHAR(1024),
+  active                BOOLEAN,
+  pool_id               UUID,
+  message_id            UUID,
+  message_hash          CHAR(64)
+);
+CREATE UNIQUE INDEX tokenapproval_id ON tokenapproval(local_id);
+CREATE INDEX tokenapproval_messageid ON tokenapproval(message_id);
+CREATE UNIQUE INDEX tokenapproval_protocolid ON tokenapproval(namespace,pool_id,protocol_id);
+CREATE INDEX tokenapproval_subject ON tokenapproval(pool_id,subject);
+
+CREATE TABLE tokenbalance (
+  seq                   SERIAL           PRIMARY KEY,
+  token_index           VARCHAR(1024),
+  key                   VARCHAR(1024)    NOT NULL,
+  balance               VARCHAR(65),
+  connector             VARCHAR(64),
+  updated               BIGINT,
+  namespace             VARCHAR(64),
+  pool_id               UUID,
+  uri                   VARCHAR(1024)
+);
+CREATE UNIQUE INDEX tokenbalance_pool ON tokenbalance(namespace,key,pool_id,token_index);
+
+CREATE TABLE tokenpool (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  locator               VARCHAR(1024)    NOT NULL,
+  type                  VARCHAR(64)      NOT NULL,
+  tx_type               VARCHAR(64)      NOT NULL,
+  tx_id                 UUID,
+  connector             VARCHAR(64)      NOT NULL,
+  symbol                VARCHAR(64),
+  message_id            UUID,
+  created               BIGINT           NOT NULL,
+  standard              VARCHAR(64),
+  info                  TEXT,
+  decimals              INTEGER          DEFAULT 0,
+  interface             UUID,
+  interface_format      VARCHAR(64)      DEFAULT '',
+  methods               TEXT,
+  published             BOOLEAN          DEFAULT false,
+  network_name          VARCHAR(64),
+  plugin_data           TEXT,
+  active                BOOLEAN,
+  backfill              TEXT
+);
+CREATE INDEX tokenpool_fortx ON tokenpool(namespace,tx_id);
+CREATE UNIQUE INDEX tokenpool_id ON tokenpool(id);
+CREATE UNIQUE INDEX tokenpool_name ON tokenpool(namespace,name);
+CREATE UNIQUE INDEX tokenpool_networkname ON tokenpool(namespace,network_name);
+
+CREATE TABLE tokentransfer (
+  seq                   SERIAL           PRIMARY KEY,
+  local_id              UUID             NOT NULL,
+  type                  VARCHAR(64)      NOT NULL,
+  token_index           VARCHAR(1024),
+  key                   VARCHAR(1024),
+  from_key              VARCHAR(1024),
+  to_key                VARCHAR(1024),
+  amount                VARCHAR(65),
+  protocol_id           VARCHAR(1024)    NOT NULL,
+  message_hash          CHAR(64),
+  tx_type               VARCHAR(64),
+  tx_id                 UUID,
+  created               BIGINT           NOT NULL,
+  connector             VARCHAR(64),
+  namespace             VARCHAR(64),
+  pool_id               UUID,
+  message_id            UUID,
+  uri                   VARCHAR(1024),
+  blockchain_event      UUID
+);
+CREATE UNIQUE INDEX tokentransfer_id ON tokentransfer(local_id);
+CREATE INDEX tokentransfer_messageid ON tokentransfer(message_id);
+CREATE INDEX tokentransfer_pool ON tokentransfer(pool_id,token_index);
+CREATE UNIQUE INDEX tokentransfer_protocolid ON tokentransfer(namespace,pool_id,protocol_id);
+
+CREATE TABLE transactions (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  ttype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  created               BIGINT           NOT NULL,
+  blockchain_ids        VARCHAR(1024),
+  idempotency_key       VARCHAR(256)
+);
+CREATE INDEX transactions_blockchain_ids ON transactions(blockchain_ids);
+CREATE INDEX transactions_created ON transactions(created);
+CREATE UNIQUE INDEX transactions_id ON transactions(namespace,id);
+CREATE UNIQUE INDEX transactions_idempotency_keys ON transactions(namespace,idempotency_key);
+
+CREATE TABLE verifiers (
+  seq                   SERIAL           PRIMARY KEY,
+  hash                  CHAR(64)         NOT NULL,
+  identity              UUID             NOT NULL,
+  vtype                 VARCHAR(256)     NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  value                 TEXT             NOT NULL,
+  created               BIGINT           NOT NULL
+);
+CREATE UNIQUE INDEX verifiers_hash ON verifiers(namespace,hash);
+CREATE UNIQUE INDEX verifiers_identity ON verifiers(namespace,identity);
+CREATE UNIQUE INDEX verifiers_value ON verifiers(namespace,vtype,value);
+
+-- Row locks held for the duration of a transaction, in place of PostgreSQL advisory locks
+CREATE TABLE locks (
+  name                  VARCHAR(64)      PRIMARY KEY
+);
@@ -0,0 +1,4 @@
+ALTER TABLE verifiers ADD COLUMN message UUID;
+ALTER TABLE verifiers ADD COLUMN deprecated BIGINT;
+DROP INDEX verifiers@verifiers_identity CASCADE;
+CREATE INDEX verifiers_identity ON verifiers(namespace, identity);
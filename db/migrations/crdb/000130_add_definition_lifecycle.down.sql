@@ -0,0 +1,2 @@
+ALTER TABLE datatypes DROP COLUMN lifecycle;
+ALTER TABLE ffi DROP COLUMN lifecycle;
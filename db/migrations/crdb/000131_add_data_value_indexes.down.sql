@@ -0,0 +1 @@
+DROP INDEX data@data_value_json;
@@ -0,0 +1 @@
+CREATE INVERTED INDEX data_value_json ON data ((value::JSONB));
@@ -0,0 +1,2 @@
+DROP INDEX groups@groups_previous;
+ALTER TABLE groups DROP COLUMN previous;
@@ -0,0 +1,3 @@
+DELETE FROM contractlisteners WHERE state <> 'active';
+DROP INDEX contractlisteners@contractsubscriptions_protocolid CASCADE;
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners(backend_id);
@@ -0,0 +1,3 @@
+-- Deleted listeners are kept as tombstones, so backend IDs only need to be unique among active listeners
+DROP INDEX contractlisteners@contractsubscriptions_protocolid CASCADE;
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners(backend_id) WHERE state = 'active';
@@ -0,0 +1,12 @@
+CREATE TABLE opnotifications (
+  seq                   SERIAL           PRIMARY KEY,
+  id                    UUID             NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  operation_id          UUID             NOT NULL,
+  url                   VARCHAR(1024)    NOT NULL,
+  secret_name           VARCHAR(64)      NOT NULL,
+  inline_secret         BOOLEAN          NOT NULL,
+  created               BIGINT           NOT NULL
+);
+CREATE UNIQUE INDEX opnotifications_id ON opnotifications(namespace, id);
+CREATE INDEX opnotifications_operation ON opnotifications(namespace, operation_id);
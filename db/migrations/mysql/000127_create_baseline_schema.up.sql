@@ -0,0 +1,541 @@
+-- MySQL starts from the schema at this version, rather than replaying the PostgreSQL history.
+-- Any later migration must be added here as well as to the postgres and sqlite migrations.
+--
+-- Differences from the PostgreSQL schema:
+-- - UUIDs are stored as CHAR(36), TEXT as LONGTEXT, and sequences use AUTO_INCREMENT
+-- - Tables use a binary collation, so string comparisons are case sensitive as they are in PostgreSQL
+-- - Long VARCHAR and TEXT columns are indexed on a prefix, within the InnoDB 3072 byte key limit for utf8mb4
+-- - Partial unique indexes are expressed as functional indexes, as NULL values never conflict (MySQL 8.0.13+)
+
+CREATE TABLE batches (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  btype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  author                VARCHAR(1024)    NOT NULL,
+  group_hash            CHAR(64),
+  hash                  CHAR(64),
+  created               BIGINT           NOT NULL,
+  manifest              LONGTEXT         NOT NULL,
+  confirmed             BIGINT,
+  tx_type               VARCHAR(64)      NOT NULL,
+  tx_id                 CHAR(36),
+  `key`                 VARCHAR(1024),
+  node_id               CHAR(36)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX batches_created ON batches(namespace, created);
+CREATE INDEX batches_fortx ON batches(namespace, tx_id);
+CREATE UNIQUE INDEX batches_id ON batches(namespace, id);
+
+CREATE TABLE blobs (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  namespace             VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  payload_ref           VARCHAR(1024)    NOT NULL,
+  created               BIGINT           NOT NULL,
+  peer                  VARCHAR(256)     NOT NULL,
+  size                  BIGINT,
+  data_id               CHAR(36)         NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX blobs_namespace_data_id ON blobs(namespace, data_id);
+CREATE INDEX blobs_payload_ref ON blobs(payload_ref(768));
+
+CREATE TABLE blockchainevent_outputs (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  output                LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX blockchainevent_outputs_id ON blockchainevent_outputs(namespace, id);
+
+CREATE TABLE blockchainevents (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  source                VARCHAR(256)     NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(256)     NOT NULL,
+  protocol_id           VARCHAR(256)     NOT NULL,
+  timestamp             BIGINT           NOT NULL,
+  listener_id           CHAR(36),
+  output                LONGTEXT,
+  info                  LONGTEXT,
+  tx_type               VARCHAR(64),
+  tx_id                 CHAR(36),
+  tx_blockchain_id      VARCHAR(1024),
+  output_truncated      BOOLEAN          DEFAULT false,
+  block_number          BIGINT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX blockchainevents_id ON blockchainevents(id);
+CREATE INDEX blockchainevents_listener_block ON blockchainevents(listener_id, block_number);
+CREATE INDEX blockchainevents_listener_id ON blockchainevents(listener_id);
+CREATE UNIQUE INDEX blockchainevents_protocolid ON blockchainevents(namespace, (COALESCE(listener_id, '')), protocol_id);
+CREATE INDEX blockchainevents_tx ON blockchainevents(tx_id);
+CREATE INDEX blockchainevents_txblockchainid ON blockchainevents(tx_blockchain_id(768));
+
+CREATE TABLE config (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  config_key            VARCHAR(512)     NOT NULL,
+  config_value          LONGTEXT         NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX config_config_key ON config(config_key);
+CREATE UNIQUE INDEX config_sequence ON config(seq);
+
+CREATE TABLE contractapis (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  interface_id          CHAR(36)         NOT NULL,
+  location              LONGTEXT,
+  name                  VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  message_id            CHAR(36),
+  published             BOOLEAN          DEFAULT false,
+  network_name          VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX contractapis_id ON contractapis(namespace, id);
+CREATE UNIQUE INDEX contractapis_namespace_name ON contractapis(namespace, name);
+CREATE UNIQUE INDEX contractapis_networkname ON contractapis(namespace, network_name);
+
+CREATE TABLE contractlisteners (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  interface_id          CHAR(36)         NULL,
+  event                 LONGTEXT         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NULL,
+  backend_id            VARCHAR(1024)    NOT NULL,
+  created               BIGINT           NOT NULL,
+  options               LONGTEXT,
+  topic                 VARCHAR(64),
+  signature             VARCHAR(1024),
+  location              LONGTEXT,
+  filters               LONGTEXT,
+  state                 VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX contractlisteners_name ON contractlisteners(namespace, (CASE WHEN state = 'active' THEN name END));
+CREATE INDEX contractlisteners_signature ON contractlisteners(signature(768));
+CREATE INDEX contractlisteners_state ON contractlisteners(state);
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners(backend_id(768));
+
+CREATE TABLE data (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  validator             VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  datatype_name         VARCHAR(64)      NOT NULL,
+  datatype_version      VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  created               BIGINT           NOT NULL,
+  blob_hash             CHAR(64),
+  blob_public           VARCHAR(1024),
+  blob_name             VARCHAR(1024),
+  blob_size             BIGINT,
+  value_size            BIGINT,
+  value                 LONGTEXT,
+  public                VARCHAR(1024),
+  blob_path             VARCHAR(1024)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX data_blob_name ON data(blob_name(768));
+CREATE INDEX data_blob_path ON data(blob_path(768));
+CREATE INDEX data_blob_size ON data(blob_size);
+CREATE INDEX data_blobs ON data(blob_hash);
+CREATE INDEX data_created ON data(namespace, created);
+CREATE INDEX data_hash ON data(namespace, hash);
+CREATE UNIQUE INDEX data_id ON data(namespace, id);
+
+CREATE TABLE datatypes (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  message_id            CHAR(36)         NOT NULL,
+  validator             VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  version               VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  created               BIGINT           NOT NULL,
+  value                 LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX datatypes_created ON datatypes(created);
+CREATE UNIQUE INDEX datatypes_id ON datatypes(namespace, id);
+CREATE UNIQUE INDEX datatypes_unique ON datatypes(namespace, name, version);
+
+CREATE TABLE deadletters (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  subscription_id       CHAR(36)         NOT NULL,
+  event_id              CHAR(36)         NOT NULL,
+  event_type            VARCHAR(64)      NOT NULL,
+  error                 LONGTEXT,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX deadletters_id ON deadletters(namespace, id);
+CREATE INDEX deadletters_subscription ON deadletters(namespace, subscription_id);
+
+CREATE TABLE events (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  etype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  ref                   CHAR(36),
+  created               BIGINT           NOT NULL,
+  tx_id                 CHAR(36),
+  cid                   CHAR(36),
+  topic                 VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX events_created ON events(created);
+CREATE UNIQUE INDEX events_id ON events(id);
+CREATE INDEX events_topic ON events(topic);
+
+CREATE TABLE ffi (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  version               VARCHAR(64)      NOT NULL,
+  description           LONGTEXT         NOT NULL,
+  message_id            CHAR(36),
+  published             BOOLEAN          DEFAULT false,
+  network_name          VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX ffi_id ON ffi(namespace, id);
+CREATE UNIQUE INDEX ffi_name ON ffi(namespace, name(640), version);
+CREATE UNIQUE INDEX ffi_networkname ON ffi(namespace, network_name, version);
+
+CREATE TABLE ffierrors (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  interface_id          CHAR(36)         NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  pathname              VARCHAR(1024)    NOT NULL,
+  description           LONGTEXT         NOT NULL,
+  params                LONGTEXT         NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX ffierrors_pathname ON ffierrors(interface_id, pathname(732));
+
+CREATE TABLE ffievents (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  interface_id          CHAR(36)         NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  pathname              VARCHAR(1024)    NOT NULL,
+  description           LONGTEXT         NOT NULL,
+  params                LONGTEXT         NOT NULL,
+  details               LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX ffievents_pathname ON ffievents(interface_id, pathname(732));
+
+CREATE TABLE ffimethods (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  interface_id          CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(1024)    NOT NULL,
+  pathname              VARCHAR(1024)    NOT NULL,
+  description           LONGTEXT         NOT NULL,
+  params                LONGTEXT         NOT NULL,
+  returns               LONGTEXT         NOT NULL,
+  details               LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX ffimethods_pathname ON ffimethods(interface_id, pathname(732));
+
+CREATE TABLE `groups` (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  message_id            CHAR(36),
+  name                  VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  created               BIGINT           NOT NULL,
+  namespace_local       VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX groups_hash ON `groups`(namespace_local, hash);
+
+CREATE TABLE identities (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  did                   VARCHAR(256)     NOT NULL,
+  parent                CHAR(36),
+  messages_verification CHAR(36),
+  messages_update       CHAR(36),
+  itype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  description           VARCHAR(4096)    NOT NULL,
+  profile               LONGTEXT,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT           NOT NULL,
+  messages_claim        CHAR(36)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX identities_did ON identities(namespace, did);
+CREATE UNIQUE INDEX identities_id ON identities(namespace, id);
+CREATE UNIQUE INDEX identities_name ON identities(itype, namespace, name);
+
+CREATE TABLE members (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  group_hash            CHAR(64)         NOT NULL,
+  idx                   INTEGER          NOT NULL,
+  identity              VARCHAR(1024)    NOT NULL,
+  node_id               CHAR(36)         NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX members_group ON members(group_hash);
+
+CREATE TABLE messages (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  cid                   CHAR(36),
+  mtype                 VARCHAR(64)      NOT NULL,
+  author                VARCHAR(1024)    NOT NULL,
+  created               BIGINT           NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  topics                VARCHAR(1024)    NOT NULL,
+  tag                   VARCHAR(64)      NOT NULL,
+  group_hash            CHAR(64),
+  datahash              CHAR(64)         NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  pins                  VARCHAR(1024)    NOT NULL,
+  confirmed             BIGINT,
+  tx_type               VARCHAR(64)      NOT NULL,
+  batch_id              CHAR(36),
+  `key`                 VARCHAR(1024),
+  state                 VARCHAR(64),
+  namespace_local       VARCHAR(64),
+  idempotency_key       VARCHAR(256),
+  tx_id                 CHAR(36),
+  tx_parent_id          CHAR(36),
+  reject_reason         LONGTEXT         DEFAULT (''),
+  tx_parent_type        VARCHAR(64)      DEFAULT '' NOT NULL,
+  send_after            BIGINT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX messages_id ON messages(namespace_local, id);
+CREATE UNIQUE INDEX messages_idempotency_keys ON messages(namespace, idempotency_key);
+CREATE INDEX messages_sortorder ON messages(confirmed, created);
+CREATE INDEX messages_topics_tag ON messages(namespace, topics(640), tag);
+
+CREATE TABLE messages_data (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  message_id            CHAR(36)         NOT NULL,
+  data_id               CHAR(36)         NOT NULL,
+  data_hash             CHAR(64)         NOT NULL,
+  data_idx              INTEGER          NOT NULL,
+  namespace             VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX messages_data_data ON messages_data(namespace, data_id);
+CREATE INDEX messages_data_message ON messages_data(namespace, message_id);
+
+CREATE TABLE namespaces (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name                  VARCHAR(64)      NOT NULL,
+  description           VARCHAR(4096),
+  created               BIGINT           NOT NULL,
+  firefly_contracts     LONGTEXT,
+  remote_name           VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX namespaces_name ON namespaces(name);
+
+CREATE TABLE nextpins (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  context               CHAR(64)         NOT NULL,
+  identity              VARCHAR(1024)    NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  nonce                 BIGINT           NOT NULL,
+  namespace             VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX nextpins_context ON nextpins(namespace, context);
+
+CREATE TABLE nonces (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  hash                  CHAR(64)         NOT NULL,
+  nonce                 BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX nonces_hash ON nonces(hash);
+
+CREATE TABLE offsets (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  otype                 VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  current               BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX offsets_unique ON offsets(otype, name);
+
+CREATE TABLE operations (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  tx_id                 CHAR(36)         NOT NULL,
+  optype                VARCHAR(64)      NOT NULL,
+  opstatus              VARCHAR(64)      NOT NULL,
+  plugin                VARCHAR(64)      NOT NULL,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT,
+  error                 LONGTEXT         NOT NULL,
+  output                LONGTEXT,
+  input                 LONGTEXT,
+  retry_id              CHAR(36),
+  retry_depth           BIGINT           DEFAULT 0,
+  retry_parent_id       CHAR(36),
+  last_error            LONGTEXT,
+  retry_history         LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX operations_created ON operations(created);
+CREATE UNIQUE INDEX operations_id ON operations(id);
+CREATE INDEX operations_retry_parent ON operations(retry_parent_id);
+CREATE INDEX operations_tx ON operations(tx_id);
+CREATE INDEX operations_type_status ON operations(optype, opstatus);
+
+CREATE TABLE pins (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  masked                BOOLEAN          NOT NULL,
+  hash                  CHAR(64)         NOT NULL,
+  batch_id              CHAR(36)         NOT NULL,
+  idx                   BIGINT           NOT NULL,
+  dispatched            BOOLEAN          NOT NULL,
+  created               BIGINT           NOT NULL,
+  signer                LONGTEXT,
+  batch_hash            VARCHAR(64),
+  namespace             VARCHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX pins_batch ON pins(batch_id);
+CREATE INDEX pins_dispatched ON pins(dispatched);
+CREATE UNIQUE INDEX pins_pin ON pins(namespace, hash, batch_id, idx);
+
+CREATE TABLE subscriptions (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  transport             VARCHAR(64)      NOT NULL,
+  options               LONGTEXT         NOT NULL,
+  created               BIGINT           NOT NULL,
+  updated               BIGINT,
+  filters               LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX subscriptions_id ON subscriptions(id);
+CREATE UNIQUE INDEX subscriptions_name ON subscriptions(namespace, name);
+
+CREATE TABLE tokenapproval (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  local_id              CHAR(36)         NOT NULL,
+  `key`                 VARCHAR(1024)    NOT NULL,
+  operator_key          VARCHAR(1024)    NOT NULL,
+  approved              BOOLEAN          NOT NULL,
+  protocol_id           VARCHAR(1024)    NOT NULL,
+  tx_type               VARCHAR(64),
+  connector             VARCHAR(64),
+  namespace             VARCHAR(64),
+  info                  LONGTEXT,
+  tx_id                 CHAR(36),
+  blockchain_event      CHAR(36),
+  created               BIGINT           NOT NULL,
+  subject               VARCHAR(1024),
+  active                BOOLEAN,
+  pool_id               CHAR(36),
+  message_id            CHAR(36),
+  message_hash          CHAR(64)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX tokenapproval_id ON tokenapproval(local_id);
+CREATE INDEX tokenapproval_messageid ON tokenapproval(message_id);
+CREATE UNIQUE INDEX tokenapproval_protocolid ON tokenapproval(namespace, pool_id, protocol_id(668));
+CREATE INDEX tokenapproval_subject ON tokenapproval(pool_id, subject(732));
+
+CREATE TABLE tokenbalance (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  token_index           VARCHAR(1024),
+  `key`                 VARCHAR(1024)    NOT NULL,
+  balance               VARCHAR(65),
+  connector             VARCHAR(64),
+  updated               BIGINT,
+  namespace             VARCHAR(64),
+  pool_id               CHAR(36),
+  uri                   VARCHAR(1024)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX tokenbalance_pool ON tokenbalance(namespace, `key`(334), pool_id, token_index(334));
+
+CREATE TABLE tokenpool (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  name                  VARCHAR(64)      NOT NULL,
+  locator               VARCHAR(1024)    NOT NULL,
+  type                  VARCHAR(64)      NOT NULL,
+  tx_type               VARCHAR(64)      NOT NULL,
+  tx_id                 CHAR(36),
+  connector             VARCHAR(64)      NOT NULL,
+  symbol                VARCHAR(64),
+  message_id            CHAR(36),
+  created               BIGINT           NOT NULL,
+  standard              VARCHAR(64),
+  info                  LONGTEXT,
+  decimals              INTEGER          DEFAULT 0,
+  interface             CHAR(36),
+  interface_format      VARCHAR(64)      DEFAULT '',
+  methods               LONGTEXT,
+  published             BOOLEAN          DEFAULT false,
+  network_name          VARCHAR(64),
+  plugin_data           LONGTEXT,
+  active                BOOLEAN,
+  backfill              LONGTEXT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX tokenpool_fortx ON tokenpool(namespace, tx_id);
+CREATE UNIQUE INDEX tokenpool_id ON tokenpool(id);
+CREATE UNIQUE INDEX tokenpool_name ON tokenpool(namespace, name);
+CREATE UNIQUE INDEX tokenpool_networkname ON tokenpool(namespace, network_name);
+
+CREATE TABLE tokentransfer (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  local_id              CHAR(36)         NOT NULL,
+  type                  VARCHAR(64)      NOT NULL,
+  token_index           VARCHAR(1024),
+  `key`                 VARCHAR(1024),
+  from_key              VARCHAR(1024),
+  to_key                VARCHAR(1024),
+  amount                VARCHAR(65),
+  protocol_id           VARCHAR(1024)    NOT NULL,
+  message_hash          CHAR(64),
+  tx_type               VARCHAR(64),
+  tx_id                 CHAR(36),
+  created               BIGINT           NOT NULL,
+  connector             VARCHAR(64),
+  namespace             VARCHAR(64),
+  pool_id               CHAR(36),
+  message_id            CHAR(36),
+  uri                   VARCHAR(1024),
+  blockchain_event      CHAR(36)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX tokentransfer_id ON tokentransfer(local_id);
+CREATE INDEX tokentransfer_messageid ON tokentransfer(message_id);
+CREATE INDEX tokentransfer_pool ON tokentransfer(pool_id, token_index(732));
+CREATE UNIQUE INDEX tokentransfer_protocolid ON tokentransfer(namespace, pool_id, protocol_id(668));
+
+CREATE TABLE transactions (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  ttype                 VARCHAR(64)      NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  created               BIGINT           NOT NULL,
+  blockchain_ids        VARCHAR(1024),
+  idempotency_key       VARCHAR(256)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE INDEX transactions_blockchain_ids ON transactions(blockchain_ids(768));
+CREATE INDEX transactions_created ON transactions(created);
+CREATE UNIQUE INDEX transactions_id ON transactions(namespace, id);
+CREATE UNIQUE INDEX transactions_idempotency_keys ON transactions(namespace, idempotency_key);
+
+CREATE TABLE verifiers (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  hash                  CHAR(64)         NOT NULL,
+  identity              CHAR(36)         NOT NULL,
+  vtype                 VARCHAR(256)     NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  value                 LONGTEXT         NOT NULL,
+  created               BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX verifiers_hash ON verifiers(namespace, hash);
+CREATE UNIQUE INDEX verifiers_identity ON verifiers(namespace, identity);
+CREATE UNIQUE INDEX verifiers_value ON verifiers(namespace, vtype, value(448));
+
+-- Row locks held for the duration of a transaction, in place of PostgreSQL advisory locks
+CREATE TABLE locks (
+  name                  VARCHAR(64)      NOT NULL PRIMARY KEY
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
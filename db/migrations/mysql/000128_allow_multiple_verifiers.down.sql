@@ -0,0 +1,4 @@
+DROP INDEX verifiers_identity ON verifiers;
+CREATE UNIQUE INDEX verifiers_identity ON verifiers(namespace, identity);
+ALTER TABLE verifiers DROP COLUMN deprecated;
+ALTER TABLE verifiers DROP COLUMN message;
@@ -0,0 +1,4 @@
+ALTER TABLE verifiers ADD COLUMN message CHAR(36);
+ALTER TABLE verifiers ADD COLUMN deprecated BIGINT;
+DROP INDEX verifiers_identity ON verifiers;
+CREATE INDEX verifiers_identity ON verifiers(namespace, identity);
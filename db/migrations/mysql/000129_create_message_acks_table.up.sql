@@ -0,0 +1,12 @@
+CREATE TABLE message_acks (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  message_id            CHAR(36)         NOT NULL,
+  ack_type              VARCHAR(64)      NOT NULL,
+  author                VARCHAR(1024)    NOT NULL,
+  node_id               CHAR(36)         NOT NULL,
+  created               BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX message_acks_id ON message_acks(namespace, id);
+CREATE INDEX message_acks_message ON message_acks(namespace, message_id);
@@ -0,0 +1,2 @@
+DROP INDEX groups_previous ON `groups`;
+ALTER TABLE `groups` DROP COLUMN previous;
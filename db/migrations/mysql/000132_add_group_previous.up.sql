@@ -0,0 +1,2 @@
+ALTER TABLE `groups` ADD COLUMN previous CHAR(64);
+CREATE INDEX groups_previous ON `groups`(namespace_local, previous);
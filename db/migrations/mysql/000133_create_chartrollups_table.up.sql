@@ -0,0 +1,11 @@
+-- group_value is indexed on a prefix, within the InnoDB key limit for utf8mb4
+CREATE TABLE chartrollups (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  namespace             VARCHAR(64)      NOT NULL,
+  collection            VARCHAR(64)      NOT NULL,
+  group_by              VARCHAR(64)      NOT NULL,
+  group_value           VARCHAR(1024)    NOT NULL,
+  bucket                BIGINT           NOT NULL,
+  record_count          BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX chartrollups_bucket ON chartrollups(namespace, collection, group_by, bucket, group_value(512));
@@ -0,0 +1,7 @@
+CREATE TABLE leaderlocks (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  name                  VARCHAR(64)      NOT NULL,
+  holder                VARCHAR(1024)    NOT NULL,
+  expires               BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX leaderlocks_name ON leaderlocks(name);
@@ -0,0 +1 @@
+ALTER TABLE operations ADD COLUMN progress LONGTEXT;
@@ -0,0 +1,5 @@
+-- Deleted listeners are kept as tombstones, so backend IDs only need to be unique among active listeners.
+-- MySQL has no partial indexes, so the key is NULL for tombstones, as for contractlisteners_name.
+-- LEFT keeps the key within the index size limit, matching the 768 character prefix it replaces.
+DROP INDEX contractsubscriptions_protocolid ON contractlisteners;
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners((LEFT(CASE WHEN state = 'active' THEN backend_id END, 768)));
@@ -0,0 +1,12 @@
+CREATE TABLE opnotifications (
+  seq                   BIGINT           NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  id                    CHAR(36)         NOT NULL,
+  namespace             VARCHAR(64)      NOT NULL,
+  operation_id          CHAR(36)         NOT NULL,
+  url                   VARCHAR(1024)    NOT NULL,
+  secret_name           VARCHAR(64)      NOT NULL,
+  inline_secret         BOOLEAN          NOT NULL,
+  created               BIGINT           NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+CREATE UNIQUE INDEX opnotifications_id ON opnotifications(namespace, id);
+CREATE INDEX opnotifications_operation ON opnotifications(namespace, operation_id);
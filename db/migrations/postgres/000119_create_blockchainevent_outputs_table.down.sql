@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS blockchainevent_outputs;
+ALTER TABLE blockchainevents DROP COLUMN output_truncated;
+COMMIT;
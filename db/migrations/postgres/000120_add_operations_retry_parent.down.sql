@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX operations_retry_parent;
+ALTER TABLE operations DROP COLUMN retry_parent_id;
+COMMIT;
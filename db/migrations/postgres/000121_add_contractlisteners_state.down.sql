@@ -0,0 +1,7 @@
+BEGIN;
+DELETE FROM contractlisteners WHERE state <> 'active';
+DROP INDEX contractlisteners_state;
+DROP INDEX contractlisteners_name;
+CREATE UNIQUE INDEX contractsubscriptions_name ON contractlisteners(namespace,name);
+ALTER TABLE contractlisteners DROP COLUMN state;
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+ALTER TABLE contractlisteners ADD COLUMN state VARCHAR(64);
+UPDATE contractlisteners SET state = 'active';
+-- Deleted listeners are kept as tombstones, so names only need to be unique among active listeners
+DROP INDEX contractsubscriptions_name;
+CREATE UNIQUE INDEX contractlisteners_name ON contractlisteners(namespace,name) WHERE state = 'active';
+CREATE INDEX contractlisteners_state ON contractlisteners(state);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE operations ADD COLUMN last_error TEXT;
+COMMIT;
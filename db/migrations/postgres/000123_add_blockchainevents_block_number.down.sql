@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX blockchainevents_listener_block;
+ALTER TABLE blockchainevents DROP COLUMN block_number;
+COMMIT;
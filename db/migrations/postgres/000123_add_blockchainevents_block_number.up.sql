@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE blockchainevents ADD COLUMN block_number BIGINT;
+UPDATE blockchainevents SET block_number = CAST(SPLIT_PART(protocol_id, '/', 1) AS BIGINT) WHERE protocol_id ~ '^[0-9]+/';
+CREATE INDEX blockchainevents_listener_block ON blockchainevents(listener_id, block_number);
+COMMIT;
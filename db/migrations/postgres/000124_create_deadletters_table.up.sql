@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE deadletters (
+  seq               SERIAL          PRIMARY KEY,
+  id                UUID            NOT NULL,
+  namespace         VARCHAR(64)     NOT NULL,
+  subscription_id   UUID            NOT NULL,
+  event_id          UUID            NOT NULL,
+  event_type        VARCHAR(64)     NOT NULL,
+  error             TEXT,
+  created           BIGINT          NOT NULL,
+  updated           BIGINT
+);
+
+CREATE UNIQUE INDEX deadletters_id ON deadletters(namespace,id);
+CREATE INDEX deadletters_subscription ON deadletters(namespace,subscription_id);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN send_after BIGINT;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE tokenpool ADD COLUMN backfill TEXT;
+COMMIT;
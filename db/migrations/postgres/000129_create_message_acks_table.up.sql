@@ -0,0 +1,15 @@
+BEGIN;
+CREATE TABLE message_acks (
+  seq               SERIAL          PRIMARY KEY,
+  id                UUID            NOT NULL,
+  namespace         VARCHAR(64)     NOT NULL,
+  message_id        UUID            NOT NULL,
+  ack_type          VARCHAR(64)     NOT NULL,
+  author            VARCHAR(1024)   NOT NULL,
+  node_id           UUID            NOT NULL,
+  created           BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX message_acks_id ON message_acks(namespace,id);
+CREATE INDEX message_acks_message ON message_acks(namespace,message_id);
+COMMIT;
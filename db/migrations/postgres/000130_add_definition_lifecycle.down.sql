@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE datatypes DROP COLUMN lifecycle;
+ALTER TABLE ffi DROP COLUMN lifecycle;
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+ALTER TABLE datatypes ADD COLUMN lifecycle VARCHAR(64) DEFAULT 'active';
+UPDATE datatypes SET lifecycle = 'active';
+ALTER TABLE ffi ADD COLUMN lifecycle VARCHAR(64) DEFAULT 'active';
+UPDATE ffi SET lifecycle = 'active';
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX data_value_search;
+DROP INDEX data_value_json;
+COMMIT;
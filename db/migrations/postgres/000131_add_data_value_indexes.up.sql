@@ -0,0 +1,4 @@
+BEGIN;
+CREATE INDEX data_value_json ON data USING GIN ((value::jsonb) jsonb_path_ops);
+CREATE INDEX data_value_search ON data USING GIN (jsonb_to_tsvector('simple', (value::jsonb), '["string", "numeric"]'));
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX groups_previous;
+ALTER TABLE groups DROP COLUMN previous;
+COMMIT;
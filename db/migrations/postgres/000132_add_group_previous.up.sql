@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE groups ADD COLUMN previous CHAR(64);
+CREATE INDEX groups_previous ON groups(namespace_local, previous);
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+CREATE TABLE chartrollups (
+  seq               SERIAL          PRIMARY KEY,
+  namespace         VARCHAR(64)     NOT NULL,
+  collection        VARCHAR(64)     NOT NULL,
+  group_by          VARCHAR(64)     NOT NULL,
+  group_value       VARCHAR(1024)   NOT NULL,
+  bucket            BIGINT          NOT NULL,
+  record_count      BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX chartrollups_bucket ON chartrollups(namespace,collection,group_by,bucket,group_value);
+COMMIT;
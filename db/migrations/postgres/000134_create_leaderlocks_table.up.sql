@@ -0,0 +1,10 @@
+BEGIN;
+CREATE TABLE leaderlocks (
+  seq               SERIAL          PRIMARY KEY,
+  name              VARCHAR(64)     NOT NULL,
+  holder            VARCHAR(1024)   NOT NULL,
+  expires           BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX leaderlocks_name ON leaderlocks(name);
+COMMIT;
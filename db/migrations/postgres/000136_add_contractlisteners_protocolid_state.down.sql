@@ -0,0 +1,5 @@
+BEGIN;
+DELETE FROM contractlisteners WHERE state <> 'active';
+DROP INDEX contractsubscriptions_protocolid;
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners(backend_id);
+COMMIT;
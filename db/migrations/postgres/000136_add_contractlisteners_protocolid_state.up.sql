@@ -0,0 +1,5 @@
+BEGIN;
+-- Deleted listeners are kept as tombstones, so backend IDs only need to be unique among active listeners
+DROP INDEX contractsubscriptions_protocolid;
+CREATE UNIQUE INDEX contractsubscriptions_protocolid ON contractlisteners(backend_id) WHERE state = 'active';
+COMMIT;
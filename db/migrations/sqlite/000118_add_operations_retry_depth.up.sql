@@ -0,0 +1,9 @@
+ALTER TABLE operations ADD COLUMN retry_depth BIGINT DEFAULT 0;
+-- Backfill the depth of existing operations by walking the retry linkage from the head of each chain
+WITH RECURSIVE chain(id, depth) AS (
+  SELECT o.id, 0 FROM operations o WHERE NOT EXISTS (SELECT 1 FROM operations p WHERE p.retry_id = o.id)
+  UNION ALL
+  SELECT o.id, chain.depth + 1 FROM operations o JOIN operations p ON p.retry_id = o.id JOIN chain ON chain.id = p.id
+)
+UPDATE operations SET retry_depth = (SELECT MAX(chain.depth) FROM chain WHERE chain.id = operations.id)
+  WHERE id IN (SELECT id FROM chain WHERE depth > 0);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS blockchainevent_outputs;
+ALTER TABLE blockchainevents DROP COLUMN output_truncated;
@@ -0,0 +1,9 @@
+ALTER TABLE blockchainevents ADD COLUMN output_truncated BOOLEAN DEFAULT false;
+CREATE TABLE blockchainevent_outputs (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  output           TEXT
+);
+
+CREATE UNIQUE INDEX blockchainevent_outputs_id ON blockchainevent_outputs(namespace, id);
@@ -0,0 +1,2 @@
+DROP INDEX operations_retry_parent;
+ALTER TABLE operations DROP COLUMN retry_parent_id;
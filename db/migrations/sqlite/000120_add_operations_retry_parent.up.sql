@@ -0,0 +1,5 @@
+ALTER TABLE operations ADD COLUMN retry_parent_id UUID;
+-- Backfill the parent of existing retries from the forward retry linkage
+UPDATE operations SET retry_parent_id = (SELECT p.id FROM operations p WHERE p.retry_id = operations.id LIMIT 1)
+  WHERE id IN (SELECT retry_id FROM operations WHERE retry_id IS NOT NULL);
+CREATE INDEX operations_retry_parent ON operations(retry_parent_id);
@@ -0,0 +1 @@
+ALTER TABLE operations ADD COLUMN last_error TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX blockchainevents_listener_block;
+ALTER TABLE blockchainevents DROP COLUMN block_number;
@@ -0,0 +1,4 @@
+ALTER TABLE blockchainevents ADD COLUMN block_number BIGINT;
+UPDATE blockchainevents SET block_number = CAST(SUBSTR(protocol_id, 1, INSTR(protocol_id, '/') - 1) AS INTEGER)
+  WHERE INSTR(protocol_id, '/') > 1 AND SUBSTR(protocol_id, 1, INSTR(protocol_id, '/') - 1) NOT GLOB '*[^0-9]*';
+CREATE INDEX blockchainevents_listener_block ON blockchainevents(listener_id, block_number);
@@ -0,0 +1 @@
+ALTER TABLE tokenpool ADD COLUMN backfill TEXT;
@@ -0,0 +1,4 @@
+ALTER TABLE verifiers ADD COLUMN message UUID;
+ALTER TABLE verifiers ADD COLUMN deprecated BIGINT;
+DROP INDEX verifiers_identity;
+CREATE INDEX verifiers_identity ON verifiers(namespace, identity);
@@ -0,0 +1,155 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/batch"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// batchStatusStream pushes batch manager status snapshots to a websocket, on an interval
+// chosen by the client on connect, and whenever the batch manager reports a significant change
+type batchStatusStream struct {
+	ctx          context.Context
+	wsConn       *websocket.Conn
+	bm           batch.Manager
+	interval     time.Duration
+	senderDone   chan struct{}
+	receiverDone chan struct{}
+}
+
+func getBatchStatusWebSocketHandler(ctx context.Context, mgr namespace.Manager) ffapi.HandlerFunction {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			// Cors is handled by the API server that wraps this handler
+			return true
+		},
+	}
+	return func(res http.ResponseWriter, req *http.Request) (status int, err error) {
+		namespace := mux.Vars(req)["ns"]
+		or, err := mgr.Orchestrator(req.Context(), namespace, false)
+		if err != nil || or == nil {
+			return 404, i18n.NewError(req.Context(), coremsgs.Msg404NotFound)
+		}
+		authReq := &fftypes.AuthReq{
+			Method: req.Method,
+			URL:    req.URL,
+			Header: req.Header,
+		}
+		if err := or.Authorize(core.WithRequiredAPIRole(req.Context(), core.APIRoleReader), authReq); err != nil {
+			return 403, err
+		}
+		bm := or.BatchManager()
+		if bm == nil {
+			return 400, i18n.NewError(req.Context(), coremsgs.MsgActionNotSupported)
+		}
+		interval, err := getBatchStatusInterval(req)
+		if err != nil {
+			return 400, err
+		}
+
+		wsConn, err := upgrader.Upgrade(res, req, nil)
+		if err != nil {
+			// The upgrader has already written the error response
+			log.L(req.Context()).Errorf("WebSocket upgrade failed: %s", err)
+			return 400, nil
+		}
+		// The stream outlives the HTTP request, so is bound to the server context
+		newBatchStatusStream(log.WithLogField(ctx, "ns", namespace), wsConn, bm, interval)
+		return 200, nil
+	}
+}
+
+func getBatchStatusInterval(req *http.Request) (time.Duration, error) {
+	minInterval := config.GetDuration(coreconfig.BatchManagerStatusMinInterval)
+	intervalStr := req.URL.Query().Get("interval")
+	if intervalStr == "" {
+		return config.GetDuration(coreconfig.BatchManagerStatusInterval), nil
+	}
+	interval, err := fftypes.ParseDurationString(intervalStr, time.Millisecond)
+	if err != nil || time.Duration(interval) < minInterval {
+		return 0, i18n.NewError(req.Context(), coremsgs.MsgInvalidStatusInterval, intervalStr, minInterval)
+	}
+	return time.Duration(interval), nil
+}
+
+func newBatchStatusStream(ctx context.Context, wsConn *websocket.Conn, bm batch.Manager, interval time.Duration) *batchStatusStream {
+	s := &batchStatusStream{
+		ctx:          ctx,
+		wsConn:       wsConn,
+		bm:           bm,
+		interval:     interval,
+		senderDone:   make(chan struct{}),
+		receiverDone: make(chan struct{}),
+	}
+	go s.sendLoop()
+	go s.receiveLoop()
+	return s
+}
+
+func (s *batchStatusStream) sendLoop() {
+	l := log.L(s.ctx)
+	defer close(s.senderDone)
+	defer s.wsConn.Close()
+
+	changes, cancelWatch := s.bm.WatchStatus()
+	defer cancelWatch()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.wsConn.WriteJSON(s.bm.Status()); err != nil {
+			l.Errorf("Write failed on batch status socket: %s", err)
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-changes:
+			l.Tracef("Sending batch status on change")
+		case <-s.receiverDone:
+			l.Debugf("Batch status sender closing - receiver completed")
+			return
+		case <-s.ctx.Done():
+			l.Debugf("Batch status sender closing - context cancelled")
+			return
+		}
+	}
+}
+
+func (s *batchStatusStream) receiveLoop() {
+	defer close(s.receiverDone)
+	// We do not accept any commands on this socket, we just read until it is closed
+	for {
+		if _, _, err := s.wsConn.NextReader(); err != nil {
+			log.L(s.ctx).Debugf("Batch status socket closed: %s", err)
+			return
+		}
+	}
+}
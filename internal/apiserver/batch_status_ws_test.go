@@ -0,0 +1,159 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/batch"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBatchStatusServer(t *testing.T, ctx context.Context) (*namespacemocks.Manager, *orchestratormocks.Orchestrator, *httptest.Server) {
+	mgr, o, as := newTestServer()
+	r := mux.NewRouter()
+	hf := as.handlerFactory()
+	r.HandleFunc("/api/v1/namespaces/{ns}/status/batchmanager/ws", hf.APIWrapper(getBatchStatusWebSocketHandler(ctx, mgr)))
+	s := httptest.NewServer(r)
+	t.Cleanup(s.Close)
+	return mgr, o, s
+}
+
+func batchStatusWSURL(s *httptest.Server, ns, query string) string {
+	return fmt.Sprintf("ws://%s/api/v1/namespaces/%s/status/batchmanager/ws%s", s.Listener.Addr(), ns, query)
+}
+
+func TestBatchStatusWebSocketPushOnChange(t *testing.T) {
+	_, o, s := newTestBatchStatusServer(t, context.Background())
+	mbm := &batchmocks.Manager{}
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("BatchManager").Return(mbm)
+
+	changes := make(chan struct{})
+	watchCancelled := make(chan struct{})
+	mbm.On("WatchStatus").Return((<-chan struct{})(changes), func() { close(watchCancelled) })
+	mbm.On("Status").Return(&batch.ManagerStatus{
+		Processors: []*batch.ProcessorStatus{{Name: "proc1"}},
+	})
+
+	conn, _, err := websocket.DefaultDialer.Dial(batchStatusWSURL(s, "ns1", "?interval=1h"), nil)
+	assert.NoError(t, err)
+
+	var status batch.ManagerStatus
+	err = conn.ReadJSON(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, "proc1", status.Processors[0].Name)
+
+	changes <- struct{}{}
+	err = conn.ReadJSON(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, "proc1", status.Processors[0].Name)
+
+	conn.Close()
+	<-watchCancelled
+	mbm.AssertExpectations(t)
+}
+
+func TestBatchStatusWebSocketPushOnInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, o, s := newTestBatchStatusServer(t, ctx)
+	config.Set(coreconfig.BatchManagerStatusMinInterval, "1ms")
+	config.Set(coreconfig.BatchManagerStatusInterval, "1ms")
+	mbm := &batchmocks.Manager{}
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("BatchManager").Return(mbm)
+
+	watchCancelled := make(chan struct{})
+	mbm.On("WatchStatus").Return((<-chan struct{})(make(chan struct{})), func() { close(watchCancelled) })
+	mbm.On("Status").Return(&batch.ManagerStatus{})
+
+	conn, _, err := websocket.DefaultDialer.Dial(batchStatusWSURL(s, "ns1", ""), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	var status batch.ManagerStatus
+	for i := 0; i < 3; i++ {
+		err = conn.ReadJSON(&status)
+		assert.NoError(t, err)
+	}
+
+	cancel()
+	<-watchCancelled
+}
+
+func TestBatchStatusWebSocketBadInterval(t *testing.T) {
+	_, o, s := newTestBatchStatusServer(t, context.Background())
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("BatchManager").Return(&batchmocks.Manager{})
+
+	for _, interval := range []string{"wrong", "1ms"} {
+		_, res, err := websocket.DefaultDialer.Dial(batchStatusWSURL(s, "ns1", "?interval="+interval), nil)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func TestBatchStatusWebSocketUnknownNamespace(t *testing.T) {
+	mgr, _, s := newTestBatchStatusServer(t, context.Background())
+	mgr.On("Orchestrator", mock.Anything, "unknown", false).Return(nil, fmt.Errorf("pop"))
+
+	_, res, err := websocket.DefaultDialer.Dial(batchStatusWSURL(s, "unknown", ""), nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestBatchStatusWebSocketUnauthorized(t *testing.T) {
+	_, o, s := newTestBatchStatusServer(t, context.Background())
+	o.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, res, err := websocket.DefaultDialer.Dial(batchStatusWSURL(s, "ns1", ""), nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+}
+
+func TestBatchStatusWebSocketNoBatchManager(t *testing.T) {
+	_, o, s := newTestBatchStatusServer(t, context.Background())
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("BatchManager").Return(nil)
+
+	_, res, err := websocket.DefaultDialer.Dial(batchStatusWSURL(s, "ns1", ""), nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestBatchStatusWebSocketUpgradeFail(t *testing.T) {
+	_, o, s := newTestBatchStatusServer(t, context.Background())
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("BatchManager").Return(&batchmocks.Manager{})
+
+	res, err := http.Get(strings.Replace(batchStatusWSURL(s, "ns1", ""), "ws://", "http://", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
@@ -0,0 +1,107 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// parseByteRange parses a Range header of the form "bytes=start-end", "bytes=start-" or "bytes=-suffix"
+// against a blob of the given size, returning the inclusive start and end offsets.
+// As permitted by RFC 7233, any header that cannot be parsed, or requests multiple ranges, is ignored
+// and the whole blob is returned. A range that starts beyond the end of the blob, or any range of an empty blob,
+// is not satisfiable.
+func parseByteRange(header string, size int64) (start, end int64, ok, satisfiable bool) {
+	spec, isBytes := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !isBytes || strings.Contains(spec, ",") {
+		return 0, 0, false, false
+	}
+	startStr, endStr, hasDash := strings.Cut(strings.TrimSpace(spec), "-")
+	if !hasDash {
+		return 0, 0, false, false
+	}
+	if startStr == "" {
+		// Suffix range, for the last N bytes
+		suffix, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, 0, false, false
+		}
+		if suffix == 0 || size == 0 {
+			return 0, 0, true, false
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, size - 1, true, true
+	}
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, false
+	}
+	end = size - 1
+	if endStr != "" {
+		if end, err = strconv.ParseInt(endStr, 10, 64); err != nil || end < start {
+			return 0, 0, false, false
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end, true, start < size
+}
+
+type rangeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// applyBlobRange limits the blob reader to the range requested by the caller, setting the headers
+// for a partial response. The blob is streamed, so the bytes before the range are read and discarded
+// rather than buffered.
+func applyBlobRange(ctx context.Context, r *ffapi.APIRequest, size int64, reader io.ReadCloser) (io.ReadCloser, error) {
+	rangeHeader := r.Req.Header.Get("Range")
+	if rangeHeader == "" {
+		return reader, nil
+	}
+	start, end, ok, satisfiable := parseByteRange(rangeHeader, size)
+	if !ok {
+		return reader, nil
+	}
+	if !satisfiable {
+		reader.Close()
+		r.ResponseHeaders.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobRangeNotSatisfiable, rangeHeader, size)
+	}
+	if _, err := io.CopyN(io.Discard, reader, start); err != nil {
+		reader.Close()
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+	}
+	length := end - start + 1
+	r.ResponseHeaders.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	r.ResponseHeaders.Set("Content-Length", strconv.FormatInt(length, 10))
+	r.SuccessStatus = http.StatusPartialContent
+	return &rangeReadCloser{Reader: io.LimitReader(reader, length), Closer: reader}, nil
+}
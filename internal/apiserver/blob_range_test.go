@@ -0,0 +1,119 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		header      string
+		start, end  int64
+		ok          bool
+		satisfiable bool
+	}{
+		{header: "bytes=0-99", start: 0, end: 99, ok: true, satisfiable: true},
+		{header: "bytes=100-", start: 100, end: 999, ok: true, satisfiable: true},
+		{header: "bytes=900-2000", start: 900, end: 999, ok: true, satisfiable: true},
+		{header: "bytes=-100", start: 900, end: 999, ok: true, satisfiable: true},
+		{header: "bytes=-2000", start: 0, end: 999, ok: true, satisfiable: true},
+		{header: "bytes=-0", ok: true, satisfiable: false},
+		{header: "bytes=1000-", ok: true, satisfiable: false},
+		{header: "items=0-99"},
+		{header: "bytes=0-9,20-29"},
+		{header: "bytes=10"},
+		{header: "bytes=-x"},
+		{header: "bytes=x-"},
+		{header: "bytes=10-x"},
+		{header: "bytes=20-10"},
+	}
+	for _, test := range tests {
+		start, end, ok, satisfiable := parseByteRange(test.header, 1000)
+		assert.Equal(t, test.ok, ok, test.header)
+		assert.Equal(t, test.satisfiable, satisfiable, test.header)
+		if satisfiable {
+			assert.Equal(t, test.start, start, test.header)
+			assert.Equal(t, test.end, end, test.header)
+		}
+	}
+}
+
+func TestParseByteRangeEmptyBlob(t *testing.T) {
+	for _, header := range []string{"bytes=0-", "bytes=0-99", "bytes=-1", "bytes=-100", "bytes=-0"} {
+		_, _, ok, satisfiable := parseByteRange(header, 0)
+		assert.True(t, ok, header)
+		assert.False(t, satisfiable, header)
+	}
+}
+
+func newRangeRequest(rangeHeader string) *ffapi.APIRequest {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	return &ffapi.APIRequest{
+		Req:             req,
+		ResponseHeaders: http.Header{},
+		SuccessStatus:   http.StatusOK,
+	}
+}
+
+func TestApplyBlobRangeNoHeader(t *testing.T) {
+	r := newRangeRequest("")
+	reader := io.NopCloser(bytes.NewReader([]byte("hello")))
+	res, err := applyBlobRange(context.Background(), r, 5, reader)
+	assert.NoError(t, err)
+	assert.Equal(t, reader, res)
+	assert.Equal(t, http.StatusOK, r.SuccessStatus)
+}
+
+func TestApplyBlobRangeIgnored(t *testing.T) {
+	r := newRangeRequest("bytes=0-1,3-4")
+	reader := io.NopCloser(bytes.NewReader([]byte("hello")))
+	res, err := applyBlobRange(context.Background(), r, 5, reader)
+	assert.NoError(t, err)
+	assert.Equal(t, reader, res)
+	assert.Empty(t, r.ResponseHeaders.Get("Content-Range"))
+}
+
+func TestApplyBlobRangeEmptyBlob(t *testing.T) {
+	r := newRangeRequest("bytes=-10")
+	_, err := applyBlobRange(context.Background(), r, 0, io.NopCloser(bytes.NewReader([]byte{})))
+	assert.Regexp(t, "FF10564", err)
+	assert.Equal(t, "bytes */0", r.ResponseHeaders.Get("Content-Range"))
+	assert.Equal(t, http.StatusOK, r.SuccessStatus)
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) { return 0, fmt.Errorf("pop") }
+
+func TestApplyBlobRangeSkipFail(t *testing.T) {
+	r := newRangeRequest("bytes=2-")
+	_, err := applyBlobRange(context.Background(), r, 5, io.NopCloser(errReader{}))
+	assert.Regexp(t, "FF10217.*pop", err)
+}
@@ -0,0 +1,95 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const (
+	valueMatchParam  = "valuematch"
+	valueSearchParam = "search"
+)
+
+// The keys of a value match path are restricted, so they can be embedded in the JSON path syntax of every database
+var valueMatchKey = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// dataValueQueryParams are the query params of the routes that can match on the contents of data values
+var dataValueQueryParams = []*ffapi.QueryParam{
+	{Name: valueMatchParam, IsArray: true, Description: coremsgs.APIValueMatchParam},
+	{Name: valueSearchParam, Description: coremsgs.APIValueSearchParam},
+}
+
+// getDataValueQuery returns the query on the contents of data values of a request, or nil if there is none
+func getDataValueQuery(r *ffapi.APIRequest) (*database.DataValueQuery, error) {
+	query := &database.DataValueQuery{
+		Search: strings.TrimSpace(r.QP[valueSearchParam]),
+	}
+	for _, matchString := range r.QAP[valueMatchParam] {
+		match, err := parseValueMatch(r.Req.Context(), matchString)
+		if err != nil {
+			return nil, err
+		}
+		query.Matches = append(query.Matches, match)
+	}
+	if len(query.Matches) == 0 && query.Search == "" {
+		return nil, nil
+	}
+	return query, nil
+}
+
+// parseValueMatch parses a match of the form value.<path>==<value>, where the value is a JSON string, number or
+// boolean. Anything else is matched as a string, so quotes can be omitted from simple strings.
+func parseValueMatch(ctx context.Context, matchString string) (*database.JSONPathMatch, error) {
+	path, value, ok := strings.Cut(matchString, "==")
+	keys := strings.Split(strings.TrimSpace(path), ".")
+	if !ok || len(keys) < 2 || keys[0] != "value" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidValueMatch, matchString)
+	}
+	for _, key := range keys[1:] {
+		if !valueMatchKey.MatchString(key) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidValueMatch, matchString)
+		}
+	}
+
+	value = strings.TrimSpace(value)
+	var parsed interface{}
+	d := json.NewDecoder(strings.NewReader(value))
+	d.UseNumber()
+	if err := d.Decode(&parsed); err != nil || d.More() {
+		parsed = value
+	}
+	switch parsed.(type) {
+	case string, json.Number, bool:
+	default:
+		parsed = value
+	}
+	b, _ := json.Marshal(parsed)
+	return &database.JSONPathMatch{
+		Path:  keys[1:],
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}, nil
+}
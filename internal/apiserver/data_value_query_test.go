@@ -0,0 +1,56 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValueMatch(t *testing.T) {
+	values := map[string]string{
+		`"acme"`:               `"acme"`,
+		` acme corp `:          `"acme corp"`,
+		`-1.5e3`:               `-1.5e3`,
+		`false`:                `false`,
+		`null`:                 `"null"`,
+		`{"a":1}`:              `"{\"a\":1}"`,
+		`"a" "b"`:              `"\"a\" \"b\""`,
+		`12345678901234567890`: `12345678901234567890`,
+	}
+	for value, expected := range values {
+		match, err := parseValueMatch(context.Background(), "value.order.customer_id=="+value)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"order", "customer_id"}, match.Path)
+		assert.Equal(t, expected, match.Value.String())
+	}
+}
+
+func TestParseValueMatchInvalid(t *testing.T) {
+	for _, matchString := range []string{
+		`value.order`,
+		`value=="acme"`,
+		`order.customer=="acme"`,
+		`value.order..customer=="acme"`,
+		`value.order."customer"=="acme"`,
+	} {
+		_, err := parseValueMatch(context.Background(), matchString)
+		assert.Regexp(t, "FF10614", err)
+	}
+}
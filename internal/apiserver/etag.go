@@ -0,0 +1,78 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// versionToken identifies a version of a resource from its ID and the time it was last updated,
+// returning an empty token if the resource has not been versioned
+func versionToken(id *fftypes.UUID, updated *fftypes.FFTime) string {
+	if id == nil || updated == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%d", id, updated.UnixNano())
+}
+
+func operationVersion(output interface{}) string {
+	if op, ok := output.(*core.Operation); ok && op != nil {
+		return versionToken(op.ID, op.Updated)
+	}
+	return ""
+}
+
+func identityVersion(output interface{}) string {
+	if identity, ok := output.(*core.Identity); ok && identity != nil {
+		return versionToken(identity.ID, identity.Updated)
+	}
+	return ""
+}
+
+// etagMatches uses the weak comparison function of RFC 7232 to check the If-None-Match header
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyETag sets a weak ETag on the response for the version of the output, and returns true
+// if the caller already has that version so the body does not need to be sent
+func applyETag(r *ffapi.APIRequest, ce *coreExtensions, output interface{}) bool {
+	token := ce.CoreVersionToken(output)
+	if token == "" {
+		return false
+	}
+	etag := `W/"` + token + `"`
+	r.ResponseHeaders.Set("ETag", etag)
+	if !etagMatches(r.Req.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	r.ResponseHeaders.Set("Content-Type", "application/json")
+	r.SuccessStatus = http.StatusNotModified
+	return true
+}
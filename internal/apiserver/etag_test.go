@@ -0,0 +1,42 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionTokenUnversioned(t *testing.T) {
+	assert.Empty(t, versionToken(nil, fftypes.Now()))
+	assert.Empty(t, versionToken(fftypes.NewUUID(), nil))
+	assert.Empty(t, operationVersion((*core.Operation)(nil)))
+	assert.Empty(t, operationVersion(&core.OperationWithDetail{}))
+	assert.Empty(t, identityVersion((*core.Identity)(nil)))
+	assert.Empty(t, identityVersion(&core.IdentityWithVerifiers{}))
+}
+
+func TestETagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`*`, `W/"abc"`))
+	assert.True(t, etagMatches(`"abc"`, `W/"abc"`))
+	assert.True(t, etagMatches(`W/"xyz", W/"abc"`, `W/"abc"`))
+	assert.False(t, etagMatches(``, `W/"abc"`))
+	assert.False(t, etagMatches(`W/"xyz"`, `W/"abc"`))
+}
@@ -0,0 +1,143 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+type exportFormat string
+
+const (
+	exportFormatNDJSON exportFormat = "ndjson"
+	exportFormatCSV    exportFormat = "csv"
+)
+
+const exportFormatParam = "format"
+
+var exportContentTypes = map[exportFormat]string{
+	exportFormatNDJSON: "application/x-ndjson",
+	exportFormatCSV:    "text/csv",
+}
+
+// exportCSV defines the CSV format of the records of an export, as a header row and a function that
+// returns the values of the row for each record in the same order
+type exportCSV struct {
+	header []string
+	row    func(record interface{}) []string
+}
+
+// exportStream writes the records of a jsonIterator to the response as NDJSON or CSV, one record at a time.
+// As with jsonArrayStream, each record is flushed to the client as it is written, using chunked transfer
+// encoding as the length of the export is not known in advance.
+type exportStream struct {
+	ctx     context.Context
+	format  exportFormat
+	csv     *exportCSV
+	iterate jsonIterator
+	reader  *io.PipeReader
+}
+
+// getExportFormat returns the format requested for an export, which is NDJSON unless specified
+func getExportFormat(r *ffapi.APIRequest, ce *coreExtensions) (exportFormat, error) {
+	switch format := exportFormat(r.QP[exportFormatParam]); format {
+	case "", exportFormatNDJSON:
+		return exportFormatNDJSON, nil
+	case exportFormatCSV:
+		if ce.CoreExportCSV != nil {
+			return format, nil
+		}
+	}
+	return "", i18n.NewError(r.Req.Context(), coremsgs.MsgInvalidExportFormat, r.QP[exportFormatParam])
+}
+
+func newExportStream(ctx context.Context, format exportFormat, csv *exportCSV, iterate jsonIterator) *exportStream {
+	return &exportStream{
+		ctx:     ctx,
+		format:  format,
+		csv:     csv,
+		iterate: iterate,
+	}
+}
+
+func (s *exportStream) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	flusher, _ := w.(http.Flusher)
+	var writeRecord func(record interface{}) error
+	if s.format == exportFormatCSV {
+		csvWriter := csv.NewWriter(cw)
+		writeRecord = func(record interface{}) error {
+			return writeCSVRow(csvWriter, s.csv.row(record))
+		}
+		if err := writeCSVRow(csvWriter, s.csv.header); err != nil {
+			return cw.n, err
+		}
+	} else {
+		enc := json.NewEncoder(cw)
+		writeRecord = enc.Encode
+	}
+	err := s.iterate(func(record interface{}) error {
+		if err := writeRecord(record); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// The status has already been sent, so the client sees a truncated export
+		log.L(s.ctx).Errorf("Export failed after %d bytes: %s", cw.n, err)
+	}
+	return cw.n, err
+}
+
+func writeCSVRow(w *csv.Writer, row []string) error {
+	_ = w.Write(row) // the writer is buffered, so any error is returned after the flush
+	w.Flush()
+	return w.Error()
+}
+
+// Read is only used if the stream is not copied with WriteTo, and pipes the output of WriteTo
+func (s *exportStream) Read(p []byte) (int, error) {
+	if s.reader == nil {
+		var writer *io.PipeWriter
+		s.reader, writer = io.Pipe()
+		go func() {
+			_, err := s.WriteTo(writer)
+			_ = writer.CloseWithError(err)
+		}()
+	}
+	return s.reader.Read(p)
+}
+
+// Close stops the iteration of a stream that is being read, as the next write to the pipe fails
+func (s *exportStream) Close() error {
+	if s.reader != nil {
+		return s.reader.Close()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/stretchr/testify/assert"
+)
+
+var testExportCSV = &exportCSV{
+	header: []string{"value"},
+	row: func(record interface{}) []string {
+		return []string{fmt.Sprintf("%v", record)}
+	},
+}
+
+func TestExportStreamNDJSON(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newExportStream(context.Background(), exportFormatNDJSON, nil, testRecords(map[string]int{"a": 1}, map[string]int{"b": 2}))
+	n, err := s.WriteTo(res)
+	assert.NoError(t, err)
+	assert.True(t, res.Flushed)
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", res.Body.String())
+	assert.Equal(t, int64(res.Body.Len()), n)
+}
+
+func TestExportStreamCSV(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newExportStream(context.Background(), exportFormatCSV, testExportCSV, testRecords("a,b", 2))
+	n, err := s.WriteTo(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "value\n\"a,b\"\n2\n", res.Body.String())
+	assert.Equal(t, int64(res.Body.Len()), n)
+}
+
+func TestExportStreamCSVEmpty(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newExportStream(context.Background(), exportFormatCSV, testExportCSV, testRecords())
+	_, err := s.WriteTo(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "value\n", res.Body.String())
+}
+
+func TestExportStreamCSVFailHeader(t *testing.T) {
+	s := newExportStream(context.Background(), exportFormatCSV, testExportCSV, testRecords(1))
+	_, err := s.WriteTo(&failingWriter{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestExportStreamCSVFailRecord(t *testing.T) {
+	s := newExportStream(context.Background(), exportFormatCSV, testExportCSV, testRecords(1))
+	_, err := s.WriteTo(&failingWriter{failAfter: 1})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestExportStreamFailIterate(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newExportStream(context.Background(), exportFormatNDJSON, nil, func(emit func(record interface{}) error) error {
+		if err := emit(1); err != nil {
+			return err
+		}
+		return fmt.Errorf("pop")
+	})
+	_, err := s.WriteTo(res)
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, "1\n", res.Body.String())
+}
+
+func TestExportStreamRead(t *testing.T) {
+	s := newExportStream(context.Background(), exportFormatNDJSON, nil, testRecords(1, 2))
+	b, err := io.ReadAll(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n2\n", string(b))
+	assert.NoError(t, s.Close())
+}
+
+func TestExportStreamCloseStopsIteration(t *testing.T) {
+	stopped := make(chan error)
+	s := newExportStream(context.Background(), exportFormatNDJSON, nil, func(emit func(record interface{}) error) error {
+		var err error
+		for err == nil {
+			err = emit(1)
+		}
+		stopped <- err
+		return err
+	})
+	_, err := s.Read(make([]byte, 1))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Close())
+	assert.Regexp(t, "closed pipe", <-stopped)
+}
+
+func TestExportStreamCloseUnread(t *testing.T) {
+	s := newExportStream(context.Background(), exportFormatNDJSON, nil, testRecords())
+	assert.NoError(t, s.Close())
+}
+
+func TestGetExportFormatCSVUnsupported(t *testing.T) {
+	r := &ffapi.APIRequest{
+		Req: httptest.NewRequest("GET", "/export?format=csv", nil),
+		QP:  map[string]string{exportFormatParam: "csv"},
+	}
+	_, err := getExportFormat(r, &coreExtensions{})
+	assert.Regexp(t, "FF10521", err)
+}
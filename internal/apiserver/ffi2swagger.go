@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -51,6 +53,12 @@ type ContractListenerInputWithLocation struct {
 
 type ffiSwaggerGen struct{}
 
+var (
+	ffiIntegerTypeRegex = regexp.MustCompile(`^(u?)int(\d*)$`)
+	ffiBytesTypeRegex   = regexp.MustCompile(`^bytes(\d*)$`)
+	ffiArrayTypeRegex   = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+)
+
 func (swg *ffiSwaggerGen) Build(ctx context.Context, api *core.ContractAPI, ffi *fftypes.FFI) (*ffapi.SwaggerGenOptions, []*ffapi.Route) {
 	hasLocation := !api.Location.IsNil()
 
@@ -91,6 +99,7 @@ func addFFIMethod(ctx context.Context, routes []*ffapi.Route, method *fftypes.FF
 		Method: http.MethodPost,
 		QueryParams: []*ffapi.QueryParam{
 			{Name: "confirm", Description: coremsgs.APIConfirmInvokeQueryParam, IsBool: true, Example: "true"},
+			{Name: "confirmTimeout", Description: coremsgs.APIConfirmTimeoutQueryParam, Example: "30s"},
 		},
 		JSONInputSchema: func(ctx context.Context, schemaGen ffapi.SchemaGenerator) (*openapi3.SchemaRef, error) {
 			return contractRequestJSONSchema(ctx, &method.Params, hasLocation)
@@ -153,7 +162,11 @@ func addFFIEvent(ctx context.Context, routes []*ffapi.Route, event *fftypes.FFIE
 func contractRequestJSONSchema(ctx context.Context, params *fftypes.FFIParams, hasLocation bool) (*openapi3.SchemaRef, error) {
 	paramSchema := make(fftypes.JSONObject, len(*params))
 	for _, param := range *params {
-		paramSchema[param.Name] = param.Schema
+		schema, err := ffiParamJSONSchema(param)
+		if err != nil {
+			return nil, err
+		}
+		paramSchema[param.Name] = schema
 	}
 	inputSchema := fftypes.JSONObject{
 		"type":        "object",
@@ -182,12 +195,9 @@ func contractRequestJSONSchema(ctx context.Context, params *fftypes.FFIParams, h
 		"type":       "object",
 		"properties": properties,
 	}
-	b, err := json.Marshal(schema)
-	if err != nil {
-		return nil, err
-	}
+	b, _ := json.Marshal(schema)
 	s := openapi3.NewSchema()
-	err = s.UnmarshalJSON(b)
+	err := s.UnmarshalJSON(b)
 	if err != nil {
 		return nil, err
 	}
@@ -209,25 +219,146 @@ func contractQueryResponseJSONSchema(ctx context.Context, params *fftypes.FFIPar
 				paramName = "output"
 			}
 		}
-		paramSchema[paramName] = param.Schema
+		schema, err := ffiParamJSONSchema(param)
+		if err != nil {
+			return nil, err
+		}
+		paramSchema[paramName] = schema
 	}
 	outputSchema := fftypes.JSONObject{
 		"type":        "object",
 		"description": i18n.Expand(ctx, coremsgs.ContractCallRequestOutput),
 		"properties":  paramSchema,
 	}
-	b, err := json.Marshal(outputSchema)
-	if err != nil {
-		return nil, err
-	}
+	b, _ := json.Marshal(outputSchema)
 	s := openapi3.NewSchema()
-	err = s.UnmarshalJSON(b)
+	err := s.UnmarshalJSON(b)
 	if err != nil {
 		return nil, err
 	}
 	return openapi3.NewSchemaRef("", s), nil
 }
 
+/**
+ * Convert the JSON Schema of an FFI param into a fully typed schema for the OpenAPI document.
+ * Where the param carries blockchain specific "details" (such as the Solidity type generated from an ABI),
+ * these are used to add integer bounds, byte/address patterns, required struct fields and fixed array sizes.
+ * The details themselves are moved to an "x-ffi-details" extension, so the schema remains valid OpenAPI.
+ */
+func ffiParamJSONSchema(param *fftypes.FFIParam) (interface{}, error) {
+	if param.Schema == nil {
+		return fftypes.JSONObject{}, nil
+	}
+	var schema interface{}
+	if err := json.Unmarshal(param.Schema.Bytes(), &schema); err != nil {
+		return nil, err
+	}
+	return ffiStrongTypeSchema(schema, ""), nil
+}
+
+func ffiStrongTypeSchema(s interface{}, typeName string) interface{} {
+	schema, ok := s.(map[string]interface{})
+	if !ok {
+		return s
+	}
+	if details, ok := schema["details"].(map[string]interface{}); ok {
+		if t, ok := details["type"].(string); ok {
+			typeName = t
+		}
+		delete(schema, "details")
+		schema["x-ffi-details"] = details
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		var required []string
+		for name, property := range properties {
+			// Fields of a struct (tuple) are all required, and are identified by their index in the struct
+			if p, ok := property.(map[string]interface{}); ok {
+				if details, ok := p["details"].(map[string]interface{}); ok && details["index"] != nil {
+					required = append(required, name)
+				}
+			}
+			properties[name] = ffiStrongTypeSchema(property, "")
+		}
+		if _, ok := schema["required"]; !ok && len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+
+	// The details of an array describe the array type (such as "uint256[]" or "bytes32[2]"),
+	// so we pass the type of the child down to the items schema
+	childType := ""
+	if match := ffiArrayTypeRegex.FindStringSubmatch(typeName); match != nil {
+		childType = match[1]
+		if size, err := strconv.Atoi(match[2]); err == nil {
+			schema["minItems"] = size
+			schema["maxItems"] = size
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		schema["items"] = ffiStrongTypeSchema(items, childType)
+	}
+	if childType != "" {
+		return schema
+	}
+
+	addFFITypeConstraints(schema, typeName)
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		for _, option := range oneOf {
+			if o, ok := option.(map[string]interface{}); ok {
+				addFFITypeConstraints(o, typeName)
+			}
+		}
+	}
+	return schema
+}
+
+func addFFITypeConstraints(schema map[string]interface{}, typeName string) {
+	jsonType, _ := schema["type"].(string)
+	if match := ffiIntegerTypeRegex.FindStringSubmatch(typeName); match != nil {
+		unsigned := match[1] == "u"
+		bits := 256
+		if match[2] != "" {
+			bits, _ = strconv.Atoi(match[2])
+		}
+		switch jsonType {
+		case "integer":
+			if unsigned {
+				schema["minimum"] = 0
+			}
+			// Bounds beyond 53 bits cannot be represented exactly as a JSON number
+			if bits <= 53 {
+				if unsigned {
+					schema["maximum"] = uint64(1)<<bits - 1
+				} else {
+					schema["minimum"] = -(int64(1) << (bits - 1))
+					schema["maximum"] = int64(1)<<(bits-1) - 1
+				}
+			}
+		case "string":
+			if unsigned {
+				schema["pattern"] = `^(0x[0-9a-fA-F]+|[0-9]+)$`
+			} else {
+				schema["pattern"] = `^-?(0x[0-9a-fA-F]+|[0-9]+)$`
+			}
+		}
+		return
+	}
+	if jsonType != "string" {
+		return
+	}
+	if match := ffiBytesTypeRegex.FindStringSubmatch(typeName); match != nil {
+		if size, err := strconv.Atoi(match[1]); err == nil {
+			schema["pattern"] = fmt.Sprintf(`^(0x)?[0-9a-fA-F]{%d}$`, size*2)
+		} else {
+			schema["pattern"] = `^(0x)?([0-9a-fA-F]{2})*$`
+		}
+	} else if typeName == "address" {
+		schema["pattern"] = `^(0x)?[0-9a-fA-F]{40}$`
+	}
+}
+
 func buildDetailsTable(ctx context.Context, details map[string]interface{}) string {
 	keyHeader := i18n.Expand(ctx, coremsgs.APISmartContractDetailsKey)
 	valueHeader := i18n.Expand(ctx, coremsgs.APISmartContractDetailsKey)
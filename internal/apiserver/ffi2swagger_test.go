@@ -258,3 +258,85 @@ func TestBadSchema(t *testing.T) {
 	_, err = contractQueryResponseJSONSchema(ctx, params)
 	assert.Error(t, err)
 }
+
+func TestFFIParamStrongTypes(t *testing.T) {
+	ctx := context.Background()
+	params := &fftypes.FFIParams{
+		{Name: "small", Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"uint8"}}`)},
+		{Name: "signed", Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"int32"}}`)},
+		{Name: "big", Schema: fftypes.JSONAnyPtr(`{"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"uint"}}`)},
+		{Name: "data", Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"bytes"}}`)},
+		{Name: "hash", Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"bytes32"}}`)},
+		{Name: "owner", Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"address"}}`)},
+		{Name: "flag", Schema: fftypes.JSONAnyPtr(`{"type":"boolean","details":{"type":"bool"}}`)},
+		{Name: "fixed", Schema: fftypes.JSONAnyPtr(`{"type":"array","items":{"oneOf":[{"type":"string"},{"type":"integer"}]},"details":{"type":"uint16[3]"}}`)},
+		{Name: "orders", Schema: fftypes.JSONAnyPtr(`{
+			"type": "array",
+			"details": {"type": "tuple[]"},
+			"items": {
+				"type": "object",
+				"properties": {
+					"id": {"type":"string","details":{"type":"bytes4","index":0}},
+					"qty": {"oneOf":[{"type":"string"},{"type":"integer"}],"details":{"type":"int64","index":1}}
+				}
+			}
+		}`)},
+		{Name: "plain", Schema: fftypes.JSONAnyPtr(`{"type":"object","properties":{"name":{"type":"string"}}}`)},
+		{Name: "missing"},
+	}
+
+	ref, err := contractRequestJSONSchema(ctx, params, true)
+	assert.NoError(t, err)
+	input := ref.Value.Properties["input"].Value.Properties
+
+	small := input["small"].Value
+	assert.Equal(t, `^(0x[0-9a-fA-F]+|[0-9]+)$`, small.OneOf[0].Value.Pattern)
+	assert.Equal(t, float64(0), *small.OneOf[1].Value.Min)
+	assert.Equal(t, float64(255), *small.OneOf[1].Value.Max)
+	assert.Equal(t, map[string]interface{}{"type": "uint8"}, small.Extensions["x-ffi-details"])
+	assert.Nil(t, small.Extensions["details"])
+
+	signed := input["signed"].Value
+	assert.Equal(t, `^-?(0x[0-9a-fA-F]+|[0-9]+)$`, signed.OneOf[0].Value.Pattern)
+	assert.Equal(t, float64(-2147483648), *signed.OneOf[1].Value.Min)
+	assert.Equal(t, float64(2147483647), *signed.OneOf[1].Value.Max)
+
+	big := input["big"].Value
+	assert.Equal(t, float64(0), *big.OneOf[1].Value.Min)
+	assert.Nil(t, big.OneOf[1].Value.Max)
+
+	assert.Equal(t, `^(0x)?([0-9a-fA-F]{2})*$`, input["data"].Value.Pattern)
+	assert.Equal(t, `^(0x)?[0-9a-fA-F]{64}$`, input["hash"].Value.Pattern)
+	assert.Equal(t, `^(0x)?[0-9a-fA-F]{40}$`, input["owner"].Value.Pattern)
+	assert.Empty(t, input["flag"].Value.Pattern)
+
+	fixed := input["fixed"].Value
+	assert.Equal(t, uint64(3), fixed.MinItems)
+	assert.Equal(t, uint64(3), *fixed.MaxItems)
+	assert.Equal(t, float64(65535), *fixed.Items.Value.OneOf[1].Value.Max)
+
+	orders := input["orders"].Value
+	assert.Equal(t, "array", orders.Type)
+	order := orders.Items.Value
+	assert.Equal(t, []string{"id", "qty"}, order.Required)
+	assert.Equal(t, `^(0x)?[0-9a-fA-F]{8}$`, order.Properties["id"].Value.Pattern)
+	assert.Equal(t, `^-?(0x[0-9a-fA-F]+|[0-9]+)$`, order.Properties["qty"].Value.OneOf[0].Value.Pattern)
+	assert.Nil(t, order.Properties["qty"].Value.OneOf[1].Value.Min)
+	assert.Nil(t, order.Properties["qty"].Value.OneOf[1].Value.Max)
+
+	plain := input["plain"].Value
+	assert.Empty(t, plain.Required)
+	assert.Equal(t, "string", plain.Properties["name"].Value.Type)
+
+	assert.Equal(t, true, ffiStrongTypeSchema(true, "uint256"))
+}
+
+func TestFFIResponseStrongTypes(t *testing.T) {
+	ctx := context.Background()
+	params := &fftypes.FFIParams{
+		{Schema: fftypes.JSONAnyPtr(`{"type":"string","details":{"type":"address"}}`)},
+	}
+	ref, err := contractQueryResponseJSONSchema(ctx, params)
+	assert.NoError(t, err)
+	assert.Equal(t, `^(0x)?[0-9a-fA-F]{40}$`, ref.Value.Properties["output"].Value.Pattern)
+}
@@ -0,0 +1,33 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKey returns the idempotency key supplied in the body of a submission, or if that is empty
+// the key supplied in the Idempotency-Key header
+func idempotencyKey(r *ffapi.APIRequest, bodyKey core.IdempotencyKey) core.IdempotencyKey {
+	if bodyKey != "" {
+		return bodyKey
+	}
+	return core.IdempotencyKey(r.Req.Header.Get(idempotencyKeyHeader))
+}
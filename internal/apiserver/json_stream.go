@@ -0,0 +1,109 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/log"
+)
+
+// jsonIterator passes each record of a streamed result to emit, stopping at the first error it returns
+type jsonIterator func(emit func(record interface{}) error) error
+
+// jsonArrayStream writes the records of a jsonIterator to the response as a JSON array, one record at a time,
+// rather than marshalling the whole list in memory. ffapi copies stream output to the response with io.Copy,
+// which passes the response writer straight to WriteTo, so each record is flushed to the client as it is written.
+type jsonArrayStream struct {
+	ctx     context.Context
+	iterate jsonIterator
+	reader  *io.PipeReader
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func newJSONArrayStream(ctx context.Context, iterate jsonIterator) *jsonArrayStream {
+	return &jsonArrayStream{
+		ctx:     ctx,
+		iterate: iterate,
+	}
+}
+
+func (s *jsonArrayStream) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(cw)
+	if _, err := cw.Write([]byte("[")); err != nil {
+		return cw.n, err
+	}
+	first := true
+	err := s.iterate(func(record interface{}) error {
+		if !first {
+			if _, err := cw.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		first = false
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// The status and the start of the array have already been sent, so the client sees a truncated array
+		log.L(s.ctx).Errorf("Streamed response failed after %d bytes: %s", cw.n, err)
+		return cw.n, err
+	}
+	_, err = cw.Write([]byte("]"))
+	return cw.n, err
+}
+
+// Read is only used if the stream is not copied with WriteTo, and pipes the output of WriteTo
+func (s *jsonArrayStream) Read(p []byte) (int, error) {
+	if s.reader == nil {
+		var writer *io.PipeWriter
+		s.reader, writer = io.Pipe()
+		go func() {
+			_, err := s.WriteTo(writer)
+			_ = writer.CloseWithError(err)
+		}()
+	}
+	return s.reader.Read(p)
+}
+
+// Close stops the iteration of a stream that is being read, as the next write to the pipe fails
+func (s *jsonArrayStream) Close() error {
+	if s.reader != nil {
+		return s.reader.Close()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct {
+	failAfter int
+}
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	if fw.failAfter == 0 {
+		return 0, fmt.Errorf("pop")
+	}
+	fw.failAfter--
+	return len(p), nil
+}
+
+func testRecords(records ...interface{}) jsonIterator {
+	return func(emit func(record interface{}) error) error {
+		for _, r := range records {
+			if err := emit(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestJSONArrayStreamWriteTo(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newJSONArrayStream(context.Background(), testRecords(map[string]int{"a": 1}, map[string]int{"b": 2}))
+	n, err := s.WriteTo(res)
+	assert.NoError(t, err)
+	assert.True(t, res.Flushed)
+	assert.Equal(t, "[{\"a\":1}\n,{\"b\":2}\n]", res.Body.String())
+	assert.Equal(t, int64(res.Body.Len()), n)
+}
+
+func TestJSONArrayStreamWriteToEmpty(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newJSONArrayStream(context.Background(), testRecords())
+	_, err := s.WriteTo(res)
+	assert.NoError(t, err)
+	assert.JSONEq(t, "[]", res.Body.String())
+}
+
+func TestJSONArrayStreamWriteToFailStart(t *testing.T) {
+	s := newJSONArrayStream(context.Background(), testRecords(1))
+	_, err := s.WriteTo(&failingWriter{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestJSONArrayStreamWriteToFailRecord(t *testing.T) {
+	s := newJSONArrayStream(context.Background(), testRecords(1))
+	_, err := s.WriteTo(&failingWriter{failAfter: 1})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestJSONArrayStreamWriteToFailSeparator(t *testing.T) {
+	s := newJSONArrayStream(context.Background(), testRecords(1, 2))
+	_, err := s.WriteTo(&failingWriter{failAfter: 2})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestJSONArrayStreamWriteToFailIterate(t *testing.T) {
+	res := httptest.NewRecorder()
+	s := newJSONArrayStream(context.Background(), func(emit func(record interface{}) error) error {
+		return fmt.Errorf("pop")
+	})
+	_, err := s.WriteTo(res)
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, "[", res.Body.String())
+}
+
+func TestJSONArrayStreamRead(t *testing.T) {
+	s := newJSONArrayStream(context.Background(), testRecords(1, 2))
+	b, err := io.ReadAll(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "[1\n,2\n]", string(b))
+	assert.NoError(t, s.Close())
+}
+
+func TestJSONArrayStreamCloseStopsIteration(t *testing.T) {
+	stopped := make(chan error)
+	s := newJSONArrayStream(context.Background(), func(emit func(record interface{}) error) error {
+		var err error
+		for err == nil {
+			err = emit(1)
+		}
+		stopped <- err
+		return err
+	})
+	_, err := s.Read(make([]byte, 1))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Close())
+	assert.Regexp(t, "closed pipe", <-stopped)
+}
+
+func TestJSONArrayStreamCloseUnread(t *testing.T) {
+	s := newJSONArrayStream(context.Background(), testRecords())
+	assert.NoError(t, s.Close())
+}
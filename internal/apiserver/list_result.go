@@ -0,0 +1,83 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"reflect"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+)
+
+const countParam = "count"
+
+// listTotalType tells the client how the total in a list result should be interpreted
+type listTotalType string
+
+const (
+	// listTotalExact is set when count=true was requested, and the total is the result of a count query
+	listTotalExact listTotalType = "exact"
+	// listTotalOmitted is set when count=false was requested, so no count query was performed
+	listTotalOmitted listTotalType = "omitted"
+)
+
+// listResult is returned from list routes when the "count" query parameter is supplied (true or false).
+// When it is not supplied at all, the bare array of items is returned as before.
+type listResult struct {
+	Count     int64         `json:"count"`
+	Total     *int64        `json:"total,omitempty"`
+	TotalType listTotalType `json:"totalType"`
+	HasMore   bool          `json:"hasMore"`
+	Next      string        `json:"next,omitempty"`
+	Items     interface{}   `json:"items"`
+}
+
+func wantsListResult(r *ffapi.APIRequest) bool {
+	_, ok := r.Req.URL.Query()[countParam]
+	return ok
+}
+
+// applyListResultLimit asks for one more row than the page size, so we can tell whether another
+// page exists without needing a count query
+func applyListResultLimit(r *ffapi.APIRequest, limit uint64) {
+	if limit > 0 {
+		r.Filter.Limit(limit + 1)
+	}
+}
+
+// newListResult trims the extra row requested by applyListResultLimit from the output of a
+// list route, and wraps the page with the hasMore hint and the type of the total
+func newListResult(output interface{}, limit uint64) interface{} {
+	lr := &listResult{TotalType: listTotalOmitted}
+	if wc, ok := output.(*ffapi.FilterResultsWithCount); ok {
+		output = wc.Items
+		if wc.Total != nil {
+			lr.Total = wc.Total
+			lr.TotalType = listTotalExact
+		}
+	}
+	items := reflect.ValueOf(output)
+	if items.Kind() != reflect.Slice {
+		return output
+	}
+	if limit > 0 && uint64(items.Len()) > limit {
+		items = items.Slice(0, int(limit))
+		lr.HasMore = true
+	}
+	lr.Items = items.Interface()
+	lr.Count = int64(items.Len())
+	return lr
+}
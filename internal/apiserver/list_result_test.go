@@ -0,0 +1,116 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBatchesCountFalseHasMore(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?sort=created&limit=2&count=false", nil)
+	res := httptest.NewRecorder()
+
+	batches := testBatchPage(3)
+	o.On("GetBatches", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return fi.Limit == 3 && !fi.Count
+	})).Return(batches, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var result struct {
+		Count     int64                  `json:"count"`
+		Total     *int64                 `json:"total"`
+		TotalType string                 `json:"totalType"`
+		HasMore   bool                   `json:"hasMore"`
+		Items     []*core.BatchPersisted `json:"items"`
+	}
+	json.NewDecoder(res.Body).Decode(&result)
+	assert.Equal(t, int64(2), result.Count)
+	assert.Nil(t, result.Total)
+	assert.Equal(t, "omitted", result.TotalType)
+	assert.True(t, result.HasMore)
+	assert.Len(t, result.Items, 2)
+	assert.Equal(t, batches[1].ID, result.Items[1].ID)
+
+	pc, err := decodePageCursor(context.Background(), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, batches[1].Created.String(), pc.Value)
+}
+
+func TestGetBatchesCountTrueLastPage(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?sort=created&limit=2&count=true", nil)
+	res := httptest.NewRecorder()
+
+	total := int64(12)
+	o.On("GetBatches", mock.Anything, mock.Anything).Return(testBatchPage(2), &ffapi.FilterResult{TotalCount: &total}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var result map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&result)
+	assert.Equal(t, float64(2), result["count"])
+	assert.Equal(t, float64(12), result["total"])
+	assert.Equal(t, "exact", result["totalType"])
+	assert.Equal(t, false, result["hasMore"])
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetBatchesNoCountParamUnchanged(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?limit=2", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetBatches", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return fi.Limit == 2
+	})).Return(testBatchPage(2), nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var result []*core.BatchPersisted
+	err := json.NewDecoder(res.Body).Decode(&result)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+func TestNewListResultNotSlice(t *testing.T) {
+	output := map[string]interface{}{"some": "thing"}
+	assert.Equal(t, output, newListResult(output, 10))
+}
+
+func TestNewListResultNoLimit(t *testing.T) {
+	lr := newListResult([]string{"a", "b"}, 0).(*listResult)
+	assert.False(t, lr.HasMore)
+	assert.Equal(t, int64(2), lr.Count)
+	assert.Equal(t, listTotalOmitted, lr.TotalType)
+}
@@ -0,0 +1,197 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"golang.org/x/time/rate"
+)
+
+// Namespace rate limits stop the callers of one namespace starving those of another, on a multi-tenant node.
+// Every request to a namespace takes a token from the bucket of the namespace, and a request to a route
+// with its own limit also takes a token from the bucket for that route in the namespace. Unlike the limits
+// of route groups, which are per caller, these buckets are shared by all the callers of the namespace.
+
+var rateLimitNamespacesConfig = config.RootArray("api.rateLimit.namespaces")
+var rateLimitRoutesConfig = config.RootArray("api.rateLimit.routes")
+
+const (
+	rateLimitScopeNamespace = "namespace"
+	rateLimitScopeRoute     = "route"
+)
+
+func initRateLimitConfig() {
+	rateLimitNamespacesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaName)
+	rateLimitNamespacesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaRequestsPerSecond, 0)
+	rateLimitNamespacesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaBurst, 100)
+	rateLimitRoutesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaName)
+	rateLimitRoutesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaNamespace)
+	rateLimitRoutesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaRequestsPerSecond, 0)
+	rateLimitRoutesConfig.AddKnownKey(coreconfig.APIRateLimitQuotaBurst, 10)
+}
+
+// rateQuota is the configuration of a token bucket, where nil means unlimited
+type rateQuota struct {
+	limit rate.Limit
+	burst int
+}
+
+func newRateQuota(rps float64, burst int) *rateQuota {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateQuota{limit: rate.Limit(rps), burst: burst}
+}
+
+type namespaceRateLimiter struct {
+	namespaceDefault *rateQuota
+	namespaces       map[string]*rateQuota
+	routes           map[string]map[string]*rateQuota // by route name, then namespace ("" for every namespace)
+	mux              sync.Mutex
+	buckets          map[string]*rate.Limiter
+}
+
+// newNamespaceRateLimiter loads the limits from config, returning nil if there are none
+func newNamespaceRateLimiter() *namespaceRateLimiter {
+	nl := &namespaceRateLimiter{
+		namespaceDefault: newRateQuota(config.GetFloat64(coreconfig.APIRateLimitNamespaceRequestsPerSecond), config.GetInt(coreconfig.APIRateLimitNamespaceBurst)),
+		namespaces:       make(map[string]*rateQuota),
+		routes:           make(map[string]map[string]*rateQuota),
+		buckets:          make(map[string]*rate.Limiter),
+	}
+	limited := nl.namespaceDefault != nil
+	for i := 0; i < rateLimitNamespacesConfig.ArraySize(); i++ {
+		conf := rateLimitNamespacesConfig.ArrayEntry(i)
+		quota := newRateQuota(conf.GetFloat64(coreconfig.APIRateLimitQuotaRequestsPerSecond), conf.GetInt(coreconfig.APIRateLimitQuotaBurst))
+		nl.namespaces[conf.GetString(coreconfig.APIRateLimitQuotaName)] = quota
+		limited = limited || quota != nil
+	}
+	for i := 0; i < rateLimitRoutesConfig.ArraySize(); i++ {
+		conf := rateLimitRoutesConfig.ArrayEntry(i)
+		routeName := conf.GetString(coreconfig.APIRateLimitQuotaName)
+		if nl.routes[routeName] == nil {
+			nl.routes[routeName] = make(map[string]*rateQuota)
+		}
+		quota := newRateQuota(conf.GetFloat64(coreconfig.APIRateLimitQuotaRequestsPerSecond), conf.GetInt(coreconfig.APIRateLimitQuotaBurst))
+		nl.routes[routeName][conf.GetString(coreconfig.APIRateLimitQuotaNamespace)] = quota
+		limited = limited || quota != nil
+	}
+	if !limited {
+		return nil
+	}
+	return nl
+}
+
+func (nl *namespaceRateLimiter) namespaceQuota(ns string) *rateQuota {
+	if quota, ok := nl.namespaces[ns]; ok {
+		return quota
+	}
+	return nl.namespaceDefault
+}
+
+func (nl *namespaceRateLimiter) routeQuota(ns, routeName string) *rateQuota {
+	quotas := nl.routes[routeName]
+	if quota, ok := quotas[ns]; ok {
+		return quota
+	}
+	return quotas[""]
+}
+
+// reserve takes a token from each bucket the request counts against - or from none of them if any is empty,
+// so a request rejected by the limit of a route does not use up the limit of the namespace
+func (nl *namespaceRateLimiter) reserve(ns, routeName string, now time.Time) (scope string, delay time.Duration) {
+	nl.mux.Lock()
+	defer nl.mux.Unlock()
+
+	checks := []struct {
+		scope string
+		key   string
+		quota *rateQuota
+	}{
+		{scope: rateLimitScopeRoute, key: ns + "/" + routeName, quota: nl.routeQuota(ns, routeName)},
+		{scope: rateLimitScopeNamespace, key: ns, quota: nl.namespaceQuota(ns)},
+	}
+	reservations := make([]*rate.Reservation, 0, len(checks))
+	for _, check := range checks {
+		if check.quota == nil {
+			continue
+		}
+		bucket := nl.buckets[check.key]
+		if bucket == nil {
+			bucket = rate.NewLimiter(check.quota.limit, check.quota.burst)
+			nl.buckets[check.key] = bucket
+		}
+		res := bucket.ReserveN(now, 1)
+		reservations = append(reservations, res)
+		if resDelay := res.DelayFrom(now); resDelay > delay {
+			scope, delay = check.scope, resDelay
+		}
+	}
+	if delay > 0 {
+		for _, res := range reservations {
+			res.CancelAt(now)
+		}
+	}
+	return scope, delay
+}
+
+// check returns the scope of the limit that was exceeded, along with the error to return to the caller
+func (nl *namespaceRateLimiter) check(r *ffapi.APIRequest, ns, routeName string) (string, error) {
+	scope, delay := nl.reserve(ns, routeName, time.Now())
+	if delay <= 0 {
+		return "", nil
+	}
+	setRetryAfter(r, delay)
+	if scope == rateLimitScopeRoute {
+		return scope, i18n.NewError(r.Req.Context(), coremsgs.MsgRouteRateLimitExceeded, routeName, ns)
+	}
+	return scope, i18n.NewError(r.Req.Context(), coremsgs.MsgNamespaceRateLimitExceeded, ns)
+}
+
+// requestNamespace is the namespace a request is made to, resolved in the same way as the orchestrator for the request
+func requestNamespace(tag string, req *http.Request) string {
+	switch tag {
+	case routeTagDefaultNamespace:
+		return config.GetString(coreconfig.NamespacesDefault)
+	case routeTagNonDefaultNamespace:
+		return mux.Vars(req)["ns"]
+	default:
+		return ""
+	}
+}
+
+// rateLimitRouteName is the name of a route without the suffix of its namespaced copy,
+// so a limit on a route applies however the namespace is addressed
+func rateLimitRouteName(route *ffapi.Route) string {
+	if route.Tag == routeTagNonDefaultNamespace {
+		return strings.TrimSuffix(route.Name, "Namespace")
+	}
+	return route.Name
+}
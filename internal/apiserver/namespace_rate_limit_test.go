@@ -0,0 +1,196 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestNamespaceRateLimitedAPIServer(t *testing.T, conf string) (*orchestratormocks.Orchestrator, *metricsmocks.Manager, *mux.Router) {
+	mgr, o, as := newTestServer()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(conf))
+	assert.NoError(t, err)
+	mmm := &metricsmocks.Manager{}
+	as.metrics = mmm
+	as.metricsEnabled = true
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	mcm.On("GetContractListeners", mock.Anything, mock.Anything).Return([]*core.ContractListener{}, nil, nil)
+	return o, mmm, r
+}
+
+func TestNamespaceRateLimitSharedAcrossCallers(t *testing.T) {
+	_, mmm, r := newTestNamespaceRateLimitedAPIServer(t, `
+api:
+  rateLimit:
+    namespace:
+      requestsPerSecond: 0.001
+      burst: 1
+`)
+	mmm.On("APIRateLimited", "ns1", "getContractListeners", "namespace").Return()
+
+	res := rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "Basic user1")
+	assert.Equal(t, 200, res.Result().StatusCode)
+
+	// The burst is used up for all callers of the namespace
+	res = rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "Basic user2")
+	assert.Equal(t, http.StatusTooManyRequests, res.Result().StatusCode)
+	assert.NotEmpty(t, res.Result().Header.Get("Retry-After"))
+	var resErr map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resErr)
+	assert.Regexp(t, "FF10602.*ns1", resErr["error"])
+
+	// Other namespaces have their own limit
+	res = rateLimitTestRequest(r, "/api/v1/namespaces/mynamespace/contracts/listeners", "Basic user1")
+	assert.Equal(t, 200, res.Result().StatusCode)
+
+	mmm.AssertExpectations(t)
+}
+
+func TestNamespaceRateLimitOverride(t *testing.T) {
+	_, mmm, r := newTestNamespaceRateLimitedAPIServer(t, `
+api:
+  rateLimit:
+    namespace:
+      requestsPerSecond: 0.001
+      burst: 1
+    namespaces:
+    - name: ns1
+      requestsPerSecond: 0
+`)
+
+	// The namespace is exempt from the default limit
+	for i := 0; i < 3; i++ {
+		res := rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "")
+		assert.Equal(t, 200, res.Result().StatusCode)
+	}
+
+	mmm.AssertExpectations(t)
+}
+
+func TestNamespaceRateLimitRoute(t *testing.T) {
+	_, mmm, r := newTestNamespaceRateLimitedAPIServer(t, `
+namespaces:
+  default: ns1
+api:
+  rateLimit:
+    routes:
+    - name: getContractListeners
+      requestsPerSecond: 0.001
+      burst: 1
+    - name: getContractListeners
+      namespace: mynamespace
+      requestsPerSecond: 0.001
+      burst: 2
+`)
+	mmm.On("APIRateLimited", "ns1", "getContractListeners", "route").Return()
+
+	res := rateLimitTestRequest(r, "/api/v1/contracts/listeners", "")
+	assert.Equal(t, 200, res.Result().StatusCode)
+
+	// The default namespace shares the limit with its namespaced route
+	res = rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "")
+	assert.Equal(t, http.StatusTooManyRequests, res.Result().StatusCode)
+	var resErr map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resErr)
+	assert.Regexp(t, "FF10603.*getContractListeners.*ns1", resErr["error"])
+
+	// The limit for a single namespace takes precedence
+	for i := 0; i < 2; i++ {
+		res = rateLimitTestRequest(r, "/api/v1/namespaces/mynamespace/contracts/listeners", "")
+		assert.Equal(t, 200, res.Result().StatusCode)
+	}
+
+	mmm.AssertExpectations(t)
+}
+
+func TestNamespaceRateLimiterDisabled(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	assert.Nil(t, newNamespaceRateLimiter())
+}
+
+func TestNamespaceRateLimiterRouteDoesNotConsumeNamespace(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	config.Set(coreconfig.APIRateLimitNamespaceRequestsPerSecond, 1)
+	config.Set(coreconfig.APIRateLimitNamespaceBurst, 2)
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+api:
+  rateLimit:
+    routes:
+    - name: postTokenMint
+      requestsPerSecond: 1
+      burst: 0
+`))
+	assert.NoError(t, err)
+	nl := newNamespaceRateLimiter()
+
+	now := time.Now()
+	scope, delay := nl.reserve("ns1", "postTokenMint", now)
+	assert.Zero(t, delay)
+	assert.Empty(t, scope)
+
+	// Rejected by the route, without taking a token from the namespace
+	scope, delay = nl.reserve("ns1", "postTokenMint", now)
+	assert.Positive(t, delay)
+	assert.Equal(t, rateLimitScopeRoute, scope)
+	scope, delay = nl.reserve("ns1", "postNewMessageBroadcast", now)
+	assert.Zero(t, delay)
+	assert.Empty(t, scope)
+
+	scope, delay = nl.reserve("ns1", "postNewMessageBroadcast", now)
+	assert.Positive(t, delay)
+	assert.Equal(t, rateLimitScopeNamespace, scope)
+}
+
+func TestRequestNamespace(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.NamespacesDefault, "default")
+	req := httptest.NewRequest("GET", "/", nil)
+	assert.Equal(t, "default", requestNamespace(routeTagDefaultNamespace, req))
+	assert.Equal(t, "ns1", requestNamespace(routeTagNonDefaultNamespace, mux.SetURLVars(req, map[string]string{"ns": "ns1"})))
+	assert.Empty(t, requestNamespace("Global", req))
+}
+
+func TestRateLimitRouteName(t *testing.T) {
+	assert.Equal(t, "postTokenMint", rateLimitRouteName(&ffapi.Route{Name: "postTokenMintNamespace", Tag: routeTagNonDefaultNamespace}))
+	assert.Equal(t, "getNamespace", rateLimitRouteName(&ffapi.Route{Name: "getNamespace", Tag: routeTagGlobal}))
+}
@@ -0,0 +1,178 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// openAPIVersionParam is the query parameter on the swagger/openapi routes that selects
+// the version of the OpenAPI specification that is returned
+const openAPIVersionParam = "version"
+
+const openAPIVersion31 = "3.1.0"
+
+// openAPIHandler serves the OpenAPI 3.0 document generated from the route metadata by default,
+// or translates the same document to OpenAPI 3.1 when requested with ?version=3.1
+func openAPIHandler(oaf *ffapi.OpenAPIHandlerFactory, apiPath string, format ffapi.OpenAPIFormat, routes []*ffapi.Route) ffapi.HandlerFunction {
+	return func(res http.ResponseWriter, req *http.Request) (int, error) {
+		version := req.URL.Query().Get(openAPIVersionParam)
+		switch {
+		case version == "" || strings.HasPrefix(version, "3.0"):
+			return oaf.OpenAPIHandler(apiPath, format, routes)(res, req)
+		case version == "3.1" || version == openAPIVersion31:
+		default:
+			return -1, i18n.NewError(req.Context(), coremsgs.MsgUnsupportedOpenAPIVersion, version)
+		}
+
+		// Capture the OpenAPI 3.0 document from the common handler factory, so we translate
+		// exactly the same document that is served by default
+		capture := httptest.NewRecorder()
+		_, _ = oaf.OpenAPIHandler(apiPath, ffapi.OpenAPIFormatJSON, routes)(capture, req)
+		doc, err := convertOpenAPI31(capture.Body.Bytes())
+		if err != nil {
+			return -1, err
+		}
+
+		var b []byte
+		if format == ffapi.OpenAPIFormatJSON {
+			res.Header().Add("Content-Type", "application/json")
+			b, _ = json.Marshal(doc)
+		} else {
+			res.Header().Add("Content-Type", "application/x-yaml")
+			b, _ = yaml.Marshal(doc)
+		}
+		_, _ = res.Write(b)
+		return 200, nil
+	}
+}
+
+// convertOpenAPI31 translates an OpenAPI 3.0 document to OpenAPI 3.1, where nullable is expressed
+// as a type array, and examples are lists on schemas and maps on parameters
+func convertOpenAPI31(spec30 []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(spec30, &doc); err != nil {
+		return nil, err
+	}
+	doc["openapi"] = openAPIVersion31
+	walkOpenAPI31(doc)
+	return doc, nil
+}
+
+// walkOpenAPI31 searches the document for the parameters and schemas that need translating
+func walkOpenAPI31(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			switch k {
+			case "schema":
+				convertSchema31(child)
+			case "schemas":
+				if schemas, ok := child.(map[string]interface{}); ok {
+					for _, schema := range schemas {
+						convertSchema31(schema)
+					}
+				}
+			case "parameters":
+				if params, ok := child.([]interface{}); ok {
+					for _, param := range params {
+						convertParameter31(param)
+					}
+				}
+			default:
+				walkOpenAPI31(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			walkOpenAPI31(child)
+		}
+	}
+}
+
+// convertParameter31 moves the example on the parameter schema (such as the Example on a PathParam)
+// into the examples map of the parameter itself
+func convertParameter31(v interface{}) {
+	param, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	schema, ok := param["schema"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			delete(items, "example")
+		}
+		param["examples"] = map[string]interface{}{
+			"default": map[string]interface{}{"value": example},
+		}
+	}
+	convertSchema31(schema)
+}
+
+func convertSchema31(v interface{}) {
+	schema, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if example, ok := schema["example"]; ok {
+		delete(schema, "example")
+		schema["examples"] = []interface{}{example}
+	}
+	if nullable, ok := schema["nullable"]; ok {
+		delete(schema, "nullable")
+		if nullable == true {
+			if schemaType, ok := schema["type"].(string); ok {
+				schema["type"] = []interface{}{schemaType, "null"}
+			} else {
+				// There is no single type to extend (such as for a $ref), so allow null alongside the schema
+				inner := make(map[string]interface{}, len(schema))
+				for k, v := range schema {
+					inner[k] = v
+					delete(schema, k)
+				}
+				schema["anyOf"] = []interface{}{inner, map[string]interface{}{"type": "null"}}
+			}
+		}
+	}
+	for _, k := range []string{"items", "not", "additionalProperties"} {
+		convertSchema31(schema[k])
+	}
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		if schemas, ok := schema[k].([]interface{}); ok {
+			for _, s := range schemas {
+				convertSchema31(s)
+			}
+		}
+	}
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, p := range props {
+			convertSchema31(p)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSwaggerJSON31(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/api/swagger.json?version=3.1", s.Listener.Addr()))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(res.Body)
+	var doc map[string]interface{}
+	err = json.Unmarshal(b, &doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	var params []map[string]interface{}
+	getEventByID := doc["paths"].(map[string]interface{})["/events/{eid}"].(map[string]interface{})["get"].(map[string]interface{})
+	b, _ = json.Marshal(getEventByID["parameters"])
+	_ = json.Unmarshal(b, &params)
+	for _, p := range params {
+		if p["name"] == "fetchreference" {
+			assert.Equal(t, map[string]interface{}{"default": map[string]interface{}{"value": "true"}}, p["examples"])
+			assert.NotContains(t, p["schema"], "example")
+		}
+	}
+}
+
+func TestSwaggerYAML31(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/api/openapi.yaml?version=3.1.0", s.Listener.Addr()))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "application/x-yaml", res.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(res.Body)
+	var doc map[string]interface{}
+	err = yaml.Unmarshal(b, &doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.1.0", doc["openapi"])
+}
+
+func TestSwaggerJSON30Explicit(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/api/v1/namespaces/test/api/swagger.json?version=3.0.2", s.Listener.Addr()))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	b, _ := io.ReadAll(res.Body)
+	var doc map[string]interface{}
+	err = json.Unmarshal(b, &doc)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^3\\.0", doc["openapi"])
+}
+
+func TestSwaggerUnsupportedVersion(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	res, err := http.Get(fmt.Sprintf("http://%s/api/swagger.json?version=2.0", s.Listener.Addr()))
+	assert.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+	b, _ := io.ReadAll(res.Body)
+	assert.Regexp(t, "FF10507", string(b))
+}
+
+func TestOpenAPI31CaptureBadJSON(t *testing.T) {
+	_, err := convertOpenAPI31([]byte("!json"))
+	assert.Error(t, err)
+}
+
+func TestConvertOpenAPI31(t *testing.T) {
+	doc, err := convertOpenAPI31([]byte(`{
+		"openapi": "3.0.2",
+		"components": {
+			"schemas": {
+				"thing": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string", "nullable": true, "example": "fred"},
+						"ref": {"$ref": "#/components/schemas/other", "nullable": true},
+						"count": {"type": "integer", "nullable": false},
+						"tags": {"type": "array", "items": {"type": "string", "nullable": true}},
+						"choice": {"oneOf": [{"type": "string", "example": "a"}]}
+					}
+				}
+			}
+		},
+		"paths": {
+			"/things": {
+				"get": {
+					"parameters": [
+						{"name": "tag", "in": "query", "schema": {"type": "array", "example": "x", "items": {"type": "string", "example": "x"}}},
+						{"$ref": "#/components/parameters/p1"},
+						{"name": "noschema", "in": "query"},
+						"bad"
+					],
+					"responses": {"200": {"content": {"application/json": {"schema": {"type": "string", "nullable": true}}}}}
+				}
+			}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	b, _ := json.Marshal(doc)
+	assert.JSONEq(t, `{
+		"openapi": "3.1.0",
+		"components": {
+			"schemas": {
+				"thing": {
+					"type": "object",
+					"properties": {
+						"name": {"type": ["string", "null"], "examples": ["fred"]},
+						"ref": {"anyOf": [{"$ref": "#/components/schemas/other"}, {"type": "null"}]},
+						"count": {"type": "integer"},
+						"tags": {"type": "array", "items": {"type": ["string", "null"]}},
+						"choice": {"oneOf": [{"type": "string", "examples": ["a"]}]}
+					}
+				}
+			}
+		},
+		"paths": {
+			"/things": {
+				"get": {
+					"parameters": [
+						{"name": "tag", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}, "examples": {"default": {"value": "x"}}},
+						{"$ref": "#/components/parameters/p1"},
+						{"name": "noschema", "in": "query"},
+						"bad"
+					],
+					"responses": {"200": {"content": {"application/json": {"schema": {"type": ["string", "null"]}}}}}
+				}
+			}
+		}
+	}`, string(b))
+}
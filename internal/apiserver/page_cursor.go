@@ -0,0 +1,259 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const pageCursorParam = "after"
+
+// pageCursor is the decoded form of the opaque token used for keyset pagination.
+// It records the single field the results are sorted on, and the value of that
+// field in the last item of the previous page. Where the sort field is not unique,
+// it also records the value of the unique field of the collection that is used to
+// break ties, so items sharing a sort value are neither skipped nor repeated.
+type pageCursor struct {
+	Field      string `json:"f"`
+	Descending bool   `json:"d,omitempty"`
+	Value      string `json:"v"`
+	Tiebreaker string `json:"t,omitempty"`
+}
+
+func supportsPageCursor(route *ffapi.Route) bool {
+	if ce, ok := route.Extensions.(*coreExtensions); ok && (ce.CoreJSONStreamHandler != nil || ce.CoreExportHandler != nil) {
+		return false
+	}
+	return route.FilterFactory != nil && route.Method == http.MethodGet
+}
+
+// withPageCursorParam documents the "after" query parameter on all list routes
+func withPageCursorParam(route *ffapi.Route) {
+	if supportsPageCursor(route) {
+		route.QueryParams = append(route.QueryParams, &ffapi.QueryParam{
+			Name: pageCursorParam, Description: coremsgs.APIParamsPageCursorAfter,
+		})
+	}
+}
+
+func sortParam(field string, descending bool) string {
+	if descending {
+		return "-" + field
+	}
+	return field
+}
+
+func (pc *pageCursor) encode() string {
+	b, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// parsePageCursor accepts a plain sequence in place of an opaque cursor on sequenced collections,
+// which continues in the direction of the sort on the sequence (newest first if no sort is requested)
+func parsePageCursor(ctx context.Context, token string, qf ffapi.QueryFactory, sortField *ffapi.SortField) (*pageCursor, error) {
+	if _, err := strconv.ParseInt(token, 10, 64); err != nil || !database.IsSequenced(qf) {
+		return decodePageCursor(ctx, token)
+	}
+	pc := &pageCursor{Field: database.SequenceField, Descending: true, Value: token}
+	if sortField != nil && sortField.Field == database.SequenceField {
+		pc.Descending = sortField.Descending
+	}
+	return pc, nil
+}
+
+func decodePageCursor(ctx context.Context, token string) (*pageCursor, error) {
+	var pc pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(b, &pc)
+	}
+	if err != nil || pc.Field == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidPageCursor, token)
+	}
+	return &pc, nil
+}
+
+// pageTiebreaker returns the unique field used to break ties between items with the same
+// value in the sort field, or an empty string if the sort field is unique itself
+func pageTiebreaker(ctx context.Context, qf ffapi.QueryFactory, sortField *ffapi.SortField) (string, error) {
+	unique := database.UniqueField(qf)
+	switch {
+	case sortField.Field == unique:
+		return "", nil
+	case unique == "":
+		return "", i18n.NewError(ctx, coremsgs.MsgPageCursorNotSupported, sortField.Field)
+	}
+	return unique, nil
+}
+
+// applyPageCursor switches the filter on the request from skip based to keyset based
+// pagination when an "after" cursor is supplied, and returns the single field the
+// results are sorted on (if there is one) so the cursor for the next page can be built.
+// The unique tiebreaker field of the collection is appended to the sort on every page,
+// so the order is stable and the keyset condition can continue exactly where it left off.
+func applyPageCursor(ctx context.Context, r *ffapi.APIRequest, qf ffapi.QueryFactory) (sortField *ffapi.SortField, tiebreaker string, limit uint64, err error) {
+	fi, err := r.Filter.Finalize()
+	if err != nil {
+		return nil, "", 0, err
+	}
+	limit = fi.Limit
+	switch {
+	case len(fi.Sort) == 1:
+		sortField = fi.Sort[0]
+	case len(fi.Sort) == 0 && database.IsSequenced(qf):
+		sortField = &ffapi.SortField{Field: database.SequenceField, Descending: true}
+	}
+
+	token := r.Req.URL.Query().Get(pageCursorParam)
+	var pc *pageCursor
+	if token != "" {
+		if pc, err = parsePageCursor(ctx, token, qf, sortField); err != nil {
+			return nil, "", 0, err
+		}
+		switch {
+		case len(fi.Sort) == 0:
+			// Adopt the sort of the cursor, rather than the default sort of the collection
+			sortField = &ffapi.SortField{Field: pc.Field, Descending: pc.Descending}
+			r.Filter.Sort(sortParam(pc.Field, pc.Descending))
+		case sortField == nil || sortField.Field != pc.Field || sortField.Descending != pc.Descending:
+			return nil, "", 0, i18n.NewError(ctx, coremsgs.MsgPageCursorSortMismatch, sortParam(pc.Field, pc.Descending))
+		}
+	}
+	if sortField == nil {
+		return nil, "", limit, nil
+	}
+
+	if tiebreaker, err = pageTiebreaker(ctx, qf, sortField); err != nil {
+		if pc != nil {
+			return nil, "", 0, err
+		}
+		// Results are still returned, but without a cursor to the next page
+		log.L(ctx).Debugf("Page cursor not available: %s", err)
+		return nil, "", limit, nil
+	}
+	if tiebreaker != "" {
+		r.Filter.Sort(sortParam(tiebreaker, sortField.Descending))
+	}
+	if pc != nil {
+		r.Filter.Condition(pageCursorCondition(r.Filter.Builder(), pc, tiebreaker))
+		r.Filter.Skip(0)
+	}
+	return sortField, tiebreaker, limit, nil
+}
+
+// pageCursorCondition matches the items that follow the cursor in the order of the sort field,
+// and then the tiebreaker field for items that share the value of the sort field in the cursor
+func pageCursorCondition(fb ffapi.FilterBuilder, pc *pageCursor, tiebreaker string) ffapi.Filter {
+	after := fb.Gt
+	if pc.Descending {
+		after = fb.Lt
+	}
+	if tiebreaker == "" || pc.Tiebreaker == "" {
+		return after(pc.Field, pc.Value)
+	}
+	return fb.Or(
+		after(pc.Field, pc.Value),
+		fb.And(fb.Eq(pc.Field, pc.Value), after(tiebreaker, pc.Tiebreaker)),
+	)
+}
+
+// setNextPageCursor returns a cursor in the response headers when a full page of
+// results has been returned, so the client can request the page that follows.
+// The cursor is also returned in the list result, when one is requested.
+func setNextPageCursor(ctx context.Context, r *ffapi.APIRequest, sortField *ffapi.SortField, tiebreaker string, limit uint64, output interface{}) {
+	if sortField == nil || limit == 0 {
+		return
+	}
+	var lr *listResult
+	switch wrapped := output.(type) {
+	case *ffapi.FilterResultsWithCount:
+		output = wrapped.Items
+	case *listResult:
+		if !wrapped.HasMore {
+			return
+		}
+		lr = wrapped
+		output = wrapped.Items
+	}
+	items := reflect.ValueOf(output)
+	if items.Kind() != reflect.Slice || items.Len() == 0 || uint64(items.Len()) < limit {
+		return
+	}
+	b, err := json.Marshal(items.Index(items.Len() - 1).Interface())
+	if err != nil {
+		return
+	}
+	var last map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&last); err != nil {
+		return
+	}
+	pc := &pageCursor{Field: sortField.Field, Descending: sortField.Descending}
+	var ok bool
+	if pc.Value, ok = keysetValue(ctx, last, sortField.Field); !ok {
+		return
+	}
+	if tiebreaker != "" {
+		if pc.Tiebreaker, ok = keysetValue(ctx, last, tiebreaker); !ok {
+			return
+		}
+	}
+	next := pc.encode()
+	r.ResponseHeaders.Set(core.HTTPHeadersNextCursor, next)
+	if lr != nil {
+		lr.Next = next
+	}
+}
+
+// keysetValue returns the value of a field of a result as a string, matching the lower case
+// names of the query fields to the camel case names used in the JSON of the result
+func keysetValue(ctx context.Context, item map[string]interface{}, field string) (string, bool) {
+	value, ok := item[field]
+	if !ok {
+		for k, v := range item {
+			if strings.EqualFold(k, field) {
+				value = v
+				break
+			}
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	default:
+		// Missing fields, nested objects, arrays and nulls cannot be used as a keyset
+		log.L(ctx).Debugf("Unable to build page cursor from field '%s' of result", field)
+		return "", false
+	}
+}
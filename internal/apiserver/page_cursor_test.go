@@ -0,0 +1,358 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testBatchPage(count int) []*core.BatchPersisted {
+	batches := make([]*core.BatchPersisted, count)
+	for i := range batches {
+		batches[i] = &core.BatchPersisted{
+			BatchHeader: core.BatchHeader{ID: fftypes.NewUUID(), Created: fftypes.UnixTime(int64(1000 + i))},
+		}
+	}
+	return batches
+}
+
+func TestGetBatchesNextPageCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?sort=created&limit=2", nil)
+	res := httptest.NewRecorder()
+
+	batches := testBatchPage(2)
+	o.On("GetBatches", mock.Anything, mock.Anything).Return(batches, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	pc, err := decodePageCursor(context.Background(), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "created", pc.Field)
+	assert.False(t, pc.Descending)
+	assert.Equal(t, batches[1].Created.String(), pc.Value)
+	assert.Equal(t, batches[1].ID.String(), pc.Tiebreaker)
+}
+
+func TestGetBatchesPartialPageNoCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?sort=created&limit=2&count", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetBatches", mock.Anything, mock.Anything).Return(testBatchPage(1), &ffapi.FilterResult{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetBatchesAfterCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	pc := &pageCursor{Field: "created", Descending: true, Value: "2024-01-01T00:00:00Z", Tiebreaker: "4f1b5c3e-8d0a-4d8c-9b1e-1b2f0a3c4d5e"}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?limit=2&skip=10&after="+pc.encode(), nil)
+	res := httptest.NewRecorder()
+
+	batches := testBatchPage(2)
+	o.On("GetBatches", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), fi.Skip)
+		assert.Equal(t, "created", fi.Sort[0].Field)
+		assert.True(t, fi.Sort[0].Descending)
+		assert.Equal(t, "id", fi.Sort[1].Field)
+		assert.True(t, fi.Sort[1].Descending)
+		assert.Equal(t, "( ( created << 1704067200000000000 ) || ( ( created == 1704067200000000000 ) && ( id << '4f1b5c3e-8d0a-4d8c-9b1e-1b2f0a3c4d5e' ) ) ) sort=-created,-id limit=2", fi.String())
+		return true
+	})).Return(batches, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	next, err := decodePageCursor(context.Background(), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.True(t, next.Descending)
+	assert.Equal(t, batches[1].Created.String(), next.Value)
+	assert.Equal(t, batches[1].ID.String(), next.Tiebreaker)
+}
+
+func TestGetBatchesAfterCursorAscending(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	pc := &pageCursor{Field: "created", Value: "2024-01-01T00:00:00Z"}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?sort=created&after="+pc.encode(), nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetBatches", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Len(t, fi.Sort, 2)
+		assert.False(t, fi.Sort[1].Descending)
+		// A cursor without a tiebreaker value continues after the sort value alone
+		assert.Equal(t, ffapi.FilterOpGt, fi.Children[0].Op)
+		return true
+	})).Return([]*core.BatchPersisted{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetBatchesAfterCursorSortMismatch(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	pc := &pageCursor{Field: "created", Value: "2024-01-01T00:00:00Z"}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?sort=-created&after="+pc.encode(), nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10502", res.Body.String())
+}
+
+func TestGetBatchesAfterCursorInvalid(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?after=!!!", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10501", res.Body.String())
+}
+
+func TestGetBatchesBadFilterValue(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?created=notatime", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
+func TestSetNextPageCursorUnusableResults(t *testing.T) {
+	ctx := context.Background()
+	sortField := &ffapi.SortField{Field: "created"}
+	r := &ffapi.APIRequest{ResponseHeaders: make(map[string][]string)}
+
+	setNextPageCursor(ctx, r, sortField, "", 1, "not a slice")
+	setNextPageCursor(ctx, r, sortField, "", 1, []interface{}{map[bool]bool{true: true}})
+	setNextPageCursor(ctx, r, sortField, "", 1, []string{"not an object"})
+	setNextPageCursor(ctx, r, sortField, "", 1, []interface{}{map[string]interface{}{"created": nil}})
+	setNextPageCursor(ctx, r, sortField, "", 1, []interface{}{map[string]interface{}{"other": "value"}})
+	assert.Empty(t, r.ResponseHeaders.Get(core.HTTPHeadersNextCursor))
+
+	setNextPageCursor(ctx, r, sortField, "", 1, &ffapi.FilterResultsWithCount{
+		Items: []interface{}{map[string]interface{}{"created": json.Number("12345")}},
+	})
+	pc, err := decodePageCursor(ctx, r.ResponseHeaders.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", pc.Value)
+}
+
+func testEventPage(count int, firstSeq int64) []*core.Event {
+	events := make([]*core.Event, count)
+	for i := range events {
+		events[i] = &core.Event{ID: fftypes.NewUUID(), Sequence: firstSeq - int64(i)}
+	}
+	return events
+}
+
+func TestGetEventsDefaultSortNextPageCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?limit=2", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.Anything).Return(testEventPage(2, 100), nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	pc, err := decodePageCursor(context.Background(), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "sequence", pc.Field)
+	assert.True(t, pc.Descending)
+	assert.Equal(t, "99", pc.Value)
+}
+
+func TestGetEventsAfterSequence(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?limit=2&after=99", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( sequence << 99 ) sort=-sequence limit=2", fi.String())
+		return true
+	})).Return(testEventPage(2, 98), nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	pc, err := decodePageCursor(context.Background(), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "97", pc.Value)
+}
+
+func TestGetEventsAfterSequenceAscending(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?sort=sequence&limit=2&after=99", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( sequence >> 99 ) sort=sequence limit=2", fi.String())
+		return true
+	})).Return([]*core.Event{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetEventsAfterSequenceSortMismatch(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?sort=created&after=99", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10502", res.Body.String())
+}
+
+func TestGetBatchesAfterSequenceNotSequenced(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/batches?after=99", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10501", res.Body.String())
+}
+
+func TestGetEventsListResultNextCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?limit=2&count=false", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.Anything).Return(testEventPage(3, 100), nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var lr struct {
+		HasMore bool          `json:"hasMore"`
+		Next    string        `json:"next"`
+		Items   []*core.Event `json:"items"`
+	}
+	err := json.NewDecoder(res.Body).Decode(&lr)
+	assert.NoError(t, err)
+	assert.True(t, lr.HasMore)
+	assert.Len(t, lr.Items, 2)
+	assert.Equal(t, res.Result().Header.Get(core.HTTPHeadersNextCursor), lr.Next)
+	pc, err := decodePageCursor(context.Background(), lr.Next)
+	assert.NoError(t, err)
+	assert.Equal(t, "99", pc.Value)
+}
+
+func TestGetEventsSortCreatedTiebreakerSequence(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?sort=created&limit=2", nil)
+	res := httptest.NewRecorder()
+
+	events := testEventPage(2, 100)
+	for _, e := range events {
+		e.Created = fftypes.Now()
+	}
+
+	o.On("GetEvents", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, " sort=created,sequence limit=2", fi.String())
+		return true
+	})).Return(events, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	pc, err := decodePageCursor(context.Background(), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "99", pc.Tiebreaker)
+}
+
+func TestGetTokenBalancesNoTiebreaker(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/tokens/balances?sort=updated&limit=1", nil)
+	res := httptest.NewRecorder()
+
+	mam := &assetmocks.Manager{}
+	o.On("Assets").Return(mam)
+	mam.On("GetTokenBalances", mock.Anything, mock.Anything).Return([]*core.TokenBalance{{Key: "0x01", Updated: fftypes.Now()}}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetTokenBalancesAfterCursorNoTiebreaker(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	pc := &pageCursor{Field: "updated", Value: "2024-01-01T00:00:00Z"}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/tokens/balances?sort=updated&after="+pc.encode(), nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10652", res.Body.String())
+}
+
+func TestSetNextPageCursorTiebreaker(t *testing.T) {
+	ctx := context.Background()
+	sortField := &ffapi.SortField{Field: "created"}
+	r := &ffapi.APIRequest{ResponseHeaders: make(map[string][]string)}
+
+	setNextPageCursor(ctx, r, sortField, "localid", 1, []interface{}{map[string]interface{}{"created": "12345"}})
+	assert.Empty(t, r.ResponseHeaders.Get(core.HTTPHeadersNextCursor))
+
+	setNextPageCursor(ctx, r, sortField, "localid", 1, []interface{}{map[string]interface{}{"created": "12345", "localId": "abcd"}})
+	pc, err := decodePageCursor(ctx, r.ResponseHeaders.Get(core.HTTPHeadersNextCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd", pc.Tiebreaker)
+}
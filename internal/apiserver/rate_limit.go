@@ -0,0 +1,141 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitCallers is the number of callers tracked by a limiter before idle callers are swept
+const maxRateLimitCallers = 1000
+
+// rateLimitGroup is a set of routes that share a rate limit, so that all the routes that hit
+// the same backend subsystem can be protected together
+type rateLimitGroup struct {
+	name              string
+	requestsPerSecond config.RootKey
+	burst             config.RootKey
+}
+
+var rateLimitContractListeners = &rateLimitGroup{
+	name:              "contractListeners",
+	requestsPerSecond: coreconfig.APIRateLimitContractListenersRequestsPerSecond,
+	burst:             coreconfig.APIRateLimitContractListenersBurst,
+}
+
+// routeRateLimiter holds a token bucket for each caller of the routes in a group
+type routeRateLimiter struct {
+	group   *rateLimitGroup
+	limit   rate.Limit
+	burst   int
+	mux     sync.Mutex
+	callers map[string]*rate.Limiter
+}
+
+// rateLimiter returns the limiter for a group, shared across all the routes in the group,
+// or nil if the group is not limited
+func (as *apiServer) rateLimiter(group *rateLimitGroup) *routeRateLimiter {
+	if group == nil {
+		return nil
+	}
+	if rl, ok := as.rateLimiters[group]; ok {
+		return rl
+	}
+	var rl *routeRateLimiter
+	if rps := config.GetFloat64(group.requestsPerSecond); rps > 0 {
+		burst := config.GetInt(group.burst)
+		if burst < 1 {
+			burst = 1
+		}
+		rl = &routeRateLimiter{
+			group:   group,
+			limit:   rate.Limit(rps),
+			burst:   burst,
+			callers: make(map[string]*rate.Limiter),
+		}
+	}
+	as.rateLimiters[group] = rl
+	return rl
+}
+
+// rateLimitCaller identifies the caller by the identity its credentials were authenticated as, falling
+// back to its address when there is no auth plugin, or it does not report the identity of the caller
+func rateLimitCaller(req *http.Request, identity string) string {
+	if identity != "" {
+		return identity
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// reserve takes a token for the caller, returning how long to wait if none is available
+func (rl *routeRateLimiter) reserve(caller string, now time.Time) time.Duration {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	limiter := rl.callers[caller]
+	if limiter == nil {
+		if len(rl.callers) >= maxRateLimitCallers {
+			rl.sweep(now)
+		}
+		limiter = rate.NewLimiter(rl.limit, rl.burst)
+		rl.callers[caller] = limiter
+	}
+	res := limiter.ReserveN(now, 1)
+	delay := res.DelayFrom(now)
+	if delay > 0 {
+		res.CancelAt(now)
+	}
+	return delay
+}
+
+// sweep removes callers whose bucket has refilled, as they are equivalent to a new caller
+func (rl *routeRateLimiter) sweep(now time.Time) {
+	for caller, limiter := range rl.callers {
+		if limiter.TokensAt(now) >= float64(rl.burst) {
+			delete(rl.callers, caller)
+		}
+	}
+}
+
+func (rl *routeRateLimiter) check(r *ffapi.APIRequest, identity string) error {
+	delay := rl.reserve(rateLimitCaller(r.Req, identity), time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	setRetryAfter(r, delay)
+	return i18n.NewError(r.Req.Context(), coremsgs.MsgRateLimitExceeded, rl.group.name)
+}
+
+func setRetryAfter(r *ffapi.APIRequest, delay time.Duration) {
+	r.ResponseHeaders.Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+}
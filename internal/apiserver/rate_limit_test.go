@@ -0,0 +1,160 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/contracts"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRateLimitedAPIServer(rps float64, burst int) (*orchestratormocks.Orchestrator, *mux.Router) {
+	mgr, o, as := newTestServer()
+	config.Set(coreconfig.APIRateLimitContractListenersRequestsPerSecond, rps)
+	config.Set(coreconfig.APIRateLimitContractListenersBurst, burst)
+	r := as.createMuxRouter(context.Background(), mgr)
+	return o, r
+}
+
+// authorizeAsHeader records the Authorization header of each request as the authenticated identity
+func authorizeAsHeader(o *orchestratormocks.Orchestrator) {
+	o.On("Authorize", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		core.SetAuthenticatedIdentity(args[0].(context.Context), args[1].(*fftypes.AuthReq).Header.Get("Authorization"))
+	}).Return(nil)
+}
+
+func rateLimitTestRequest(r *mux.Router, path, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("Authorization", auth)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	return res
+}
+
+func TestRateLimitSharedAcrossRoutes(t *testing.T) {
+	o, r := newTestRateLimitedAPIServer(0.001, 1)
+	authorizeAsHeader(o)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	mcm.On("GetContractListeners", mock.Anything, mock.Anything).Return([]*core.ContractListener{}, nil, nil)
+
+	res := rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "Basic user1")
+	assert.Equal(t, 200, res.Result().StatusCode)
+
+	// The burst is used up, including for other routes in the same group
+	res = rateLimitTestRequest(r, "/api/v1/namespaces/ns1/apis/banana/listeners/peeled", "Basic user1")
+	assert.Equal(t, http.StatusTooManyRequests, res.Result().StatusCode)
+	assert.NotEmpty(t, res.Result().Header.Get("Retry-After"))
+	var resErr map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resErr)
+	assert.Regexp(t, "FF10518.*contractListeners", resErr["error"])
+
+	// Other callers have their own limit
+	res = rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "Basic user2")
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestRateLimitBurst(t *testing.T) {
+	o, r := newTestRateLimitedAPIServer(0.001, 2)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.Anything).
+		Return(contracts.ContractListenerIterator(emptyListenerIterator), nil)
+
+	for i := 0; i < 2; i++ {
+		res := rateLimitTestRequest(r, "/api/v1/namespaces/ns1/apis/banana/listeners/peeled", "")
+		assert.Equal(t, 200, res.Result().StatusCode)
+	}
+	res := rateLimitTestRequest(r, "/api/v1/namespaces/ns1/apis/banana/listeners/peeled", "")
+	assert.Equal(t, http.StatusTooManyRequests, res.Result().StatusCode)
+}
+
+func TestRateLimitUnauthorizedNotCounted(t *testing.T) {
+	o, r := newTestRateLimitedAPIServer(0.001, 1)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	mcm.On("GetContractListeners", mock.Anything, mock.Anything).Return([]*core.ContractListener{}, nil, nil)
+
+	res := rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "Basic user1")
+	assert.Equal(t, 500, res.Result().StatusCode)
+	res = rateLimitTestRequest(r, "/api/v1/namespaces/ns1/contracts/listeners", "Basic user1")
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	_, _, as := newTestServer()
+	assert.Nil(t, as.rateLimiter(nil))
+	assert.Nil(t, as.rateLimiter(rateLimitContractListeners))
+}
+
+func TestRateLimiterMinimumBurst(t *testing.T) {
+	_, _, as := newTestServer()
+	config.Set(coreconfig.APIRateLimitContractListenersRequestsPerSecond, 10)
+	config.Set(coreconfig.APIRateLimitContractListenersBurst, 0)
+	rl := as.rateLimiter(rateLimitContractListeners)
+	assert.Equal(t, 1, rl.burst)
+	assert.Equal(t, rl, as.rateLimiter(rateLimitContractListeners))
+}
+
+func TestRateLimitCaller(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	assert.Equal(t, "10.0.0.1", rateLimitCaller(req, ""))
+	req.RemoteAddr = "10.0.0.1"
+	assert.Equal(t, "10.0.0.1", rateLimitCaller(req, ""))
+	// Unauthenticated credentials are not used to identify the caller
+	req.Header.Set("Authorization", "Bearer token")
+	assert.Equal(t, "10.0.0.1", rateLimitCaller(req, ""))
+	assert.Equal(t, "jwt:user1", rateLimitCaller(req, "jwt:user1"))
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	_, _, as := newTestServer()
+	config.Set(coreconfig.APIRateLimitContractListenersRequestsPerSecond, 1)
+	config.Set(coreconfig.APIRateLimitContractListenersBurst, 1)
+	rl := as.rateLimiter(rateLimitContractListeners)
+
+	now := time.Now()
+	assert.Zero(t, rl.reserve("busy", now))
+	for i := 1; i < maxRateLimitCallers; i++ {
+		rl.reserve(fmt.Sprintf("idle%d", i), now.Add(-1*time.Hour))
+	}
+	assert.Len(t, rl.callers, maxRateLimitCallers)
+
+	// Idle callers have refilled their buckets, so are swept, but the busy caller is retained
+	assert.Zero(t, rl.reserve("new", now))
+	assert.Len(t, rl.callers, 2)
+	assert.Positive(t, rl.reserve("busy", now))
+}
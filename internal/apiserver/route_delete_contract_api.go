@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var deleteContractAPI = &ffapi.Route{
@@ -36,6 +37,7 @@ var deleteContractAPI = &ffapi.Route{
 	JSONOutputValue: nil,
 	JSONOutputCodes: []int{http.StatusNoContent}, // Sync operation, no output
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return nil, cr.or.Contracts().DeleteContractAPI(cr.ctx, r.PP["apiName"])
 		},
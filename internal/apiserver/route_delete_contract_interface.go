@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var deleteContractInterface = &ffapi.Route{
@@ -37,6 +38,7 @@ var deleteContractInterface = &ffapi.Route{
 	JSONOutputValue: nil,
 	JSONOutputCodes: []int{http.StatusNoContent}, // Sync operation, no output
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			interfaceID, err := fftypes.ParseUUID(cr.ctx, r.PP["interfaceId"])
 			if err != nil {
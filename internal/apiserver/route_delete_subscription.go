@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var deleteSubscription = &ffapi.Route{
@@ -36,6 +37,7 @@ var deleteSubscription = &ffapi.Route{
 	JSONOutputValue: nil,
 	JSONOutputCodes: []int{http.StatusNoContent}, // Sync operation, no output
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			err = cr.or.DeleteSubscription(cr.ctx, r.PP["subid"])
 			return nil, err
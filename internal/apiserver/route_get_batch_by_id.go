@@ -37,6 +37,7 @@ var getBatchByID = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.BatchPersisted{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.GetBatchByID(cr.ctx, r.PP["batchid"])
 			return output, err
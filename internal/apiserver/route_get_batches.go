@@ -37,6 +37,7 @@ var getBatches = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.BatchPersisted{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.GetBatches(cr.ctx, r.Filter))
 		},
@@ -37,6 +37,7 @@ var getBlockchainEventByID = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.BlockchainEvent{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return cr.or.GetBlockchainEventByID(cr.ctx, r.PP["id"])
 		},
@@ -0,0 +1,49 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var getBlockchainEventOutput = &ffapi.Route{
+	Name:   "getBlockchainEventOutput",
+	Path:   "blockchainevents/{id}/output",
+	Method: http.MethodGet,
+	PathParams: []*ffapi.PathParam{
+		{Name: "id", Description: coremsgs.APIParamsBlockchainEventID},
+	},
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsGetBlockchainEventOutput,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return make(map[string]interface{}) },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			eventOutput, err := cr.or.GetBlockchainEventOutput(cr.ctx, r.PP["id"])
+			if err != nil || eventOutput == nil {
+				return nil, err // a nil interface is required to return a 404
+			}
+			return eventOutput, nil
+		},
+	},
+}
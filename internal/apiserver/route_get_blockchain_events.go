@@ -37,6 +37,7 @@ var getBlockchainEvents = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.BlockchainEvent{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.GetBlockchainEvents(cr.ctx, r.Filter))
 		},
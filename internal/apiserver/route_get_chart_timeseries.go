@@ -0,0 +1,71 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var getChartTimeSeries = &ffapi.Route{
+	Name:   "getChartTimeSeries",
+	Path:   "charts/timeseries/{collection}",
+	Method: http.MethodGet,
+	PathParams: []*ffapi.PathParam{
+		{Name: "collection", Description: coremsgs.APIParamsCollectionID},
+	},
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "startTime", Description: coremsgs.APIHistogramStartTimeParam},
+		{Name: "endTime", Description: coremsgs.APIHistogramEndTimeParam},
+		{Name: "interval", Description: coremsgs.APITimeSeriesIntervalParam},
+		{Name: "groupBy", Description: coremsgs.APITimeSeriesGroupByParam},
+	},
+	Description:     coremsgs.APIEndpointsGetChartTimeSeries,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return []*core.ChartTimeSeriesBucket{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			startTime, err := fftypes.ParseTimeString(r.QP["startTime"])
+			if err != nil {
+				return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidChartNumberParam, "startTime")
+			}
+			endTime, err := fftypes.ParseTimeString(r.QP["endTime"])
+			if err != nil {
+				return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidChartNumberParam, "endTime")
+			}
+			interval, err := fftypes.ParseDurationString(r.QP["interval"], time.Millisecond)
+			if err != nil || interval <= 0 {
+				return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidChartInterval, r.QP["interval"])
+			}
+			return cr.or.GetChartTimeSeries(cr.ctx, database.CollectionName(r.PP["collection"]), &core.ChartTimeSeriesQuery{
+				StartTime: startTime,
+				EndTime:   endTime,
+				Interval:  interval,
+				GroupBy:   r.QP["groupBy"],
+			})
+		},
+	},
+}
@@ -17,9 +17,12 @@
 package apiserver
 
 import (
+	"database/sql/driver"
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/orchestrator"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -34,18 +37,97 @@ var getContractAPIListeners = &ffapi.Route{
 		{Name: "apiName", Description: coremsgs.APIParamsContractAPIName},
 		{Name: "eventPath", Description: coremsgs.APIParamsEventPath},
 	},
-	QueryParams:     []*ffapi.QueryParam{},
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "includeInactive", Example: "true", Description: coremsgs.APIParamsIncludeInactive, IsBool: true},
+	},
 	FilterFactory:   database.ContractListenerQueryFactory,
 	Description:     coremsgs.APIEndpointsGetContractListeners,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*core.ContractListener{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Contracts() != nil
 		},
-		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return r.FilterResult(cr.or.Contracts().GetContractAPIListeners(cr.ctx, r.PP["apiName"], r.PP["eventPath"], r.Filter))
+		RateLimit: rateLimitContractListeners,
+		// A contract API can have thousands of listeners, so they are streamed from the database
+		CoreJSONStreamHandler: func(r *ffapi.APIRequest, cr *coreRequest) (iterate jsonIterator, err error) {
+			if err := applyListenerStateScope(r); err != nil {
+				return nil, err
+			}
+			iterator, err := cr.or.Contracts().IterateContractAPIListeners(cr.ctx, r.PP["apiName"], r.PP["eventPath"], r.Filter)
+			if err != nil {
+				return nil, err
+			}
+			return func(emit func(record interface{}) error) error {
+				return iterator(cr.ctx, func(listener *core.ContractListener) error {
+					return emit(listener)
+				})
+			}, nil
 		},
 	},
 }
+
+// applyListenerStateScope validates the includeInactive param, and widens the filter to cover
+// deleted listeners when it is set. Without it only active listeners are returned, so an
+// explicit filter on any other state is rejected rather than silently returning nothing.
+func applyListenerStateScope(r *ffapi.APIRequest) error {
+	// Boolean params are normalized before they reach QP, so validate the raw value
+	for _, raw := range r.Req.URL.Query()["includeInactive"] {
+		if raw != "" && !strings.EqualFold(raw, "true") && !strings.EqualFold(raw, "false") {
+			return i18n.NewError(r.Req.Context(), coremsgs.MsgInvalidBoolQueryParam, raw, "includeInactive")
+		}
+	}
+	stateFilters := r.Req.Form["state"]
+	if !strings.EqualFold(r.QP["includeInactive"], "true") {
+		if len(stateFilters) == 0 {
+			return nil
+		}
+		// Parse the filter the same way it is applied, so operators such as "=active" are understood
+		var fi *ffapi.FilterInfo
+		stateFilter, err := ffapi.ParseFilterParam(r.Req.Context(), r.Filter.Builder(), "state", stateFilters)
+		if err == nil {
+			fi, err = stateFilter.Finalize()
+		}
+		if err != nil {
+			return err
+		}
+		if !matchesOnlyActiveState(fi) {
+			return i18n.NewError(r.Req.Context(), coremsgs.MsgListenerStateFilterConflict, strings.Join(stateFilters, ","))
+		}
+		return nil
+	}
+	if len(stateFilters) == 0 {
+		r.Filter.Condition(r.Filter.Builder().In("state", []driver.Value{
+			core.ContractListenerStateActive,
+			core.ContractListenerStateDeleted,
+		}))
+	}
+	return nil
+}
+
+// matchesOnlyActiveState checks a parsed state filter can only match active listeners
+func matchesOnlyActiveState(fi *ffapi.FilterInfo) bool {
+	switch fi.Op {
+	case ffapi.FilterOpAnd, ffapi.FilterOpOr:
+		for _, child := range fi.Children {
+			if !matchesOnlyActiveState(child) {
+				return false
+			}
+		}
+		return len(fi.Children) > 0
+	case ffapi.FilterOpEq, ffapi.FilterOpIEq:
+		if fi.Value == nil {
+			return false
+		}
+		v, _ := fi.Value.Value()
+		state, _ := v.(string)
+		if fi.Op == ffapi.FilterOpIEq {
+			return strings.EqualFold(state, string(core.ContractListenerStateActive))
+		}
+		return state == string(core.ContractListenerStateActive)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const defaultListenerHealthWindow = 1 * time.Hour
+
+var getContractAPIListenersHealth = &ffapi.Route{
+	Name:   "getContractAPIListenersHealth",
+	Path:   "apis/{apiName}/listeners/_health",
+	Method: http.MethodGet,
+	PathParams: []*ffapi.PathParam{
+		{Name: "apiName", Description: coremsgs.APIParamsContractAPIName},
+	},
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "window", Description: coremsgs.APIListenerHealthWindow},
+	},
+	Description:     coremsgs.APIEndpointsGetContractAPIListenersHealth,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &core.ContractAPIListenerHealth{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.Contracts() != nil
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			window := defaultListenerHealthWindow
+			if windowStr := r.QP["window"]; windowStr != "" {
+				d, err := fftypes.ParseDurationString(windowStr, time.Millisecond)
+				if err != nil || d <= 0 {
+					return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidListenerHealthWindow, windowStr)
+				}
+				window = time.Duration(d)
+			}
+			return cr.or.Contracts().GetContractAPIListenersHealth(cr.ctx, r.PP["apiName"], window)
+		},
+	},
+}
@@ -18,12 +18,19 @@ package apiserver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/contracts"
 	"github.com/hyperledger/firefly/mocks/contractmocks"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -40,9 +47,219 @@ func TestGetContractAPIListeners(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	mcm.On("GetContractAPIListeners", mock.Anything, "banana", "peeled", mock.Anything).
-		Return([]*core.ContractListener{}, nil, nil)
+	listeners := []*core.ContractListener{
+		{ID: fftypes.NewUUID()},
+		{ID: fftypes.NewUUID()},
+	}
+	mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.Anything).
+		Return(contracts.ContractListenerIterator(func(ctx context.Context, cb func(listener *core.ContractListener) error) error {
+			for _, l := range listeners {
+				if err := cb(l); err != nil {
+					return err
+				}
+			}
+			return nil
+		}), nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "application/json", res.Result().Header.Get("Content-Type"))
+	var output []*core.ContractListener
+	err := json.NewDecoder(res.Body).Decode(&output)
+	assert.NoError(t, err)
+	assert.Equal(t, listeners, output)
+}
+
+func TestGetContractAPIListenersNotFound(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.Anything).
+		Return(nil, fmt.Errorf("pop"))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
+
+func TestGetContractAPIListenersUnauthorized(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
+
+func emptyListenerIterator(ctx context.Context, cb func(listener *core.ContractListener) error) error {
+	return nil
+}
+
+func TestGetContractAPIListenersIncludeInactive(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?includeInactive=true", nil)
+	res := httptest.NewRecorder()
+
+	mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), "( state IN ['active','deleted'] )")
+	})).Return(contracts.ContractListenerIterator(emptyListenerIterator), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	mcm.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenersIncludeInactiveExplicitState(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?includeInactive=true&state=deleted", nil)
+	res := httptest.NewRecorder()
+
+	mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), "( state == 'deleted' )")
+	})).Return(contracts.ContractListenerIterator(emptyListenerIterator), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	mcm.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenersActiveStateFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?includeInactive=false&state=active", nil)
+	res := httptest.NewRecorder()
+
+	mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.Anything).
+		Return(contracts.ContractListenerIterator(emptyListenerIterator), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	mcm.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenersStateFilterConflict(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("Contracts").Return(&contractmocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?state=deleted", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	var resErr map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resErr)
+	assert.Regexp(t, "FF10517", resErr["error"])
+}
+
+func TestGetContractAPIListenersActiveStateFilterOperators(t *testing.T) {
+	for _, state := range []string{"%3Dactive", "%3A%3DACTIVE", "active&state=%3Dactive"} {
+		o, r := newTestAPIServer()
+		o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+		mcm := &contractmocks.Manager{}
+		o.On("Contracts").Return(mcm)
+		req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?state="+state, nil)
+		res := httptest.NewRecorder()
+
+		mcm.On("IterateContractAPIListeners", mock.Anything, "banana", "peeled", mock.Anything).
+			Return(contracts.ContractListenerIterator(emptyListenerIterator), nil)
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, 200, res.Result().StatusCode, state)
+		mcm.AssertExpectations(t)
+	}
+}
+
+func TestGetContractAPIListenersStateFilterOperatorConflict(t *testing.T) {
+	for _, state := range []string{"!active", "%3DACTIVE", "%40active", "active&state=deleted"} {
+		o, r := newTestAPIServer()
+		o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+		o.On("Contracts").Return(&contractmocks.Manager{})
+		req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?state="+state, nil)
+		res := httptest.NewRecorder()
+
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, 400, res.Result().StatusCode, state)
+		var resErr map[string]interface{}
+		json.NewDecoder(res.Body).Decode(&resErr)
+		assert.Regexp(t, "FF10517", resErr["error"])
+	}
+}
+
+func TestApplyListenerStateScopeBadStateFilter(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?state=!%3Eactive", nil)
+	req.ParseForm()
+	err := applyListenerStateScope(&ffapi.APIRequest{
+		Req:    req,
+		QP:     map[string]string{},
+		Filter: database.ContractListenerQueryFactory.NewFilter(context.Background()).And(),
+	})
+	assert.Regexp(t, "FF00193", err)
+}
+
+func TestMatchesOnlyActiveStateEmpty(t *testing.T) {
+	assert.False(t, matchesOnlyActiveState(&ffapi.FilterInfo{Op: ffapi.FilterOpOr}))
+	assert.False(t, matchesOnlyActiveState(&ffapi.FilterInfo{Op: ffapi.FilterOpEq}))
+}
+
+func TestGetContractAPIListenersBadIncludeInactive(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("Contracts").Return(&contractmocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?includeInactive=maybe", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	var resErr map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resErr)
+	assert.Regexp(t, "FF10516", resErr["error"])
+}
+
+func TestGetContractAPIListenersCountNotSupported(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?count=true", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10650", res.Body.String())
+	mcm.AssertNotCalled(t, "IterateContractAPIListeners", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetContractAPIListenersBadFilter(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/apis/banana/listeners/peeled?created=notatime", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	mcm.AssertNotCalled(t, "IterateContractAPIListeners", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
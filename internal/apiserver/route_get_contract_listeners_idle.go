@@ -0,0 +1,62 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var getContractListenersIdle = &ffapi.Route{
+	Name:       "getContractListenersIdle",
+	Path:       "contracts/listeners/_idle",
+	Method:     http.MethodGet,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "minage", Description: coremsgs.APIIdleListenerMinAgeParam},
+	},
+	FilterFactory:   database.ContractListenerQueryFactory,
+	Description:     coremsgs.APIEndpointsGetContractListenersIdle,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return []*core.IdleContractListener{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.Contracts() != nil
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			var minAge time.Duration
+			if minAgeStr := r.QP["minage"]; minAgeStr != "" {
+				d, err := fftypes.ParseDurationString(minAgeStr, time.Millisecond)
+				if err != nil || d < 0 {
+					return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidIdleListenerMinAge, minAgeStr)
+				}
+				minAge = time.Duration(d)
+			}
+			return r.FilterResult(cr.or.Contracts().GetIdleContractListeners(cr.ctx, minAge, r.Filter))
+		},
+	},
+}
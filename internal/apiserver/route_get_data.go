@@ -30,14 +30,22 @@ var getData = &ffapi.Route{
 	Path:            "data",
 	Method:          http.MethodGet,
 	PathParams:      nil,
-	QueryParams:     nil,
+	QueryParams:     dataValueQueryParams,
 	FilterFactory:   database.DataQueryFactory,
 	Description:     coremsgs.APIEndpointsGetData,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return core.DataArray{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			valueQuery, err := getDataValueQuery(r)
+			if err != nil {
+				return nil, err
+			}
+			if valueQuery != nil {
+				return r.FilterResult(cr.or.SearchData(cr.ctx, r.Filter, valueQuery))
+			}
 			return r.FilterResult(cr.or.GetData(cr.ctx, r.Filter))
 		},
 	},
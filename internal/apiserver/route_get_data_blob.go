@@ -39,8 +39,9 @@ var getDataBlob = &ffapi.Route{
 	Description:     coremsgs.APIEndpointsGetDataBlob,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []byte{} },
-	JSONOutputCodes: []int{http.StatusOK},
+	JSONOutputCodes: []int{http.StatusOK, http.StatusPartialContent},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Data().BlobsEnabled()
 		},
@@ -50,6 +51,9 @@ var getDataBlob = &ffapi.Route{
 				r.ResponseHeaders.Set(core.HTTPHeadersBlobHashSHA256, blob.Hash.String())
 				if blob.Size > 0 {
 					r.ResponseHeaders.Set(core.HTTPHeadersBlobSize, strconv.FormatInt(blob.Size, 10))
+					// Ranges can only be served when the size is known
+					r.ResponseHeaders.Set("Accept-Ranges", "bytes")
+					return applyBlobRange(cr.ctx, r, blob.Size, reader)
 				}
 			}
 			return reader, nil
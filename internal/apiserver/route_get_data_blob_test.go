@@ -56,3 +56,76 @@ func TestGetDataBlob(t *testing.T) {
 	assert.Equal(t, "12345", res.Result().Header.Get(core.HTTPHeadersBlobSize))
 	assert.Equal(t, blobHash.String(), res.Result().Header.Get(core.HTTPHeadersBlobHashSHA256))
 }
+
+func TestGetDataBlobRange(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Range", "bytes=6-")
+	res := httptest.NewRecorder()
+
+	mdm.On("DownloadBlob", mock.Anything, "abcd1234").
+		Return(&core.Blob{
+			Hash: fftypes.NewRandB32(),
+			Size: 11,
+		}, ioutil.NopCloser(bytes.NewReader([]byte("hello world"))), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 206, res.Result().StatusCode)
+	b, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", string(b))
+	assert.Equal(t, "bytes", res.Result().Header.Get("Accept-Ranges"))
+	assert.Equal(t, "bytes 6-10/11", res.Result().Header.Get("Content-Range"))
+	assert.Equal(t, "5", res.Result().Header.Get("Content-Length"))
+}
+
+func TestGetDataBlobRangeNotSatisfiable(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Range", "bytes=11-")
+	res := httptest.NewRecorder()
+
+	mdm.On("DownloadBlob", mock.Anything, "abcd1234").
+		Return(&core.Blob{
+			Hash: fftypes.NewRandB32(),
+			Size: 11,
+		}, ioutil.NopCloser(bytes.NewReader([]byte("hello world"))), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 416, res.Result().StatusCode)
+	assert.Equal(t, "bytes */11", res.Result().Header.Get("Content-Range"))
+}
+
+func TestGetDataBlobUnknownSizeIgnoresRange(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Range", "bytes=6-")
+	res := httptest.NewRecorder()
+
+	mdm.On("DownloadBlob", mock.Anything, "abcd1234").
+		Return(&core.Blob{
+			Hash: fftypes.NewRandB32(),
+		}, ioutil.NopCloser(bytes.NewReader([]byte("hello world"))), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	b, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+	assert.Empty(t, res.Result().Header.Get("Accept-Ranges"))
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var getDataSubPaths = &ffapi.Route{
@@ -36,6 +37,7 @@ var getDataSubPaths = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []string{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return cr.or.GetDataSubPaths(cr.ctx, r.PP["parent"])
 		},
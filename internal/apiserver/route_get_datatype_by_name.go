@@ -38,6 +38,7 @@ var getDatatypeByName = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Datatype{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.GetDatatypeByName(cr.ctx, r.PP["name"], r.PP["version"])
 			return output, err
@@ -37,6 +37,7 @@ var getDatatypes = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Datatype{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.GetDatatypes(cr.ctx, r.Filter))
 		},
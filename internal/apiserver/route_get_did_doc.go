@@ -0,0 +1,65 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/networkmap"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var getDIDDoc = &ffapi.Route{
+	Name:   "getDIDDoc",
+	Path:   "did/{did:.+}",
+	Method: http.MethodGet,
+	PathParams: []*ffapi.PathParam{
+		{Name: "did", Description: coremsgs.APIParamsDID},
+	},
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsResolveDIDDoc,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &networkmap.DIDDocument{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			did := r.PP["did"]
+			ns, err := core.ParseFireFlyDIDNamespace(cr.ctx, did)
+			if err != nil {
+				return nil, err
+			}
+			if ns == "" {
+				ns = config.GetString(coreconfig.NamespacesDefault)
+			}
+			or, err := cr.mgr.Orchestrator(cr.ctx, ns, false)
+			if err != nil {
+				return nil, err
+			}
+			// Global routes are not authorized by the server, so authorize against the namespace the DID resolved to
+			if err := or.Authorize(core.WithRequiredAPIRole(cr.ctx, core.APIRoleReader), &fftypes.AuthReq{Method: r.Req.Method, URL: r.Req.URL, Header: r.Req.Header}); err != nil {
+				return nil, err
+			}
+			return or.NetworkMap().GetDIDDocForIndentityByDID(cr.ctx, did)
+		},
+	},
+}
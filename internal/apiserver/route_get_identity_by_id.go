@@ -40,11 +40,13 @@ var getIdentityByID = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Identity{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			if strings.EqualFold(r.QP["fetchverifiers"], "true") {
 				return cr.or.NetworkMap().GetIdentityByIDWithVerifiers(cr.ctx, r.PP["iid"])
 			}
 			return cr.or.NetworkMap().GetIdentityByID(cr.ctx, r.PP["iid"])
 		},
+		CoreVersionToken: identityVersion,
 	},
 }
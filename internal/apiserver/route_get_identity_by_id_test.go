@@ -17,9 +17,11 @@
 package apiserver
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -55,3 +57,19 @@ func TestGetIdentityByIDWithVerifiers(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestGetIdentityByIDNotModified(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mnm := &networkmapmocks.Manager{}
+	o.On("NetworkMap").Return(mnm)
+	identity := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}, Updated: fftypes.Now()}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/identities/id1", nil)
+	req.Header.Set("If-None-Match", fmt.Sprintf(`W/"%s.%d"`, identity.ID, identity.Updated.UnixNano()))
+	res := httptest.NewRecorder()
+
+	mnm.On("GetIdentityByID", mock.Anything, "id1").Return(identity, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 304, res.Result().StatusCode)
+}
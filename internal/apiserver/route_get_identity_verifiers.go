@@ -39,6 +39,7 @@ var getIdentityVerifiers = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &[]*core.Verifier{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.NetworkMap().GetIdentityVerifiers(cr.ctx, r.PP["iid"], r.Filter))
 		},
@@ -31,20 +31,32 @@ var getMsgs = &ffapi.Route{
 	Path:       "messages",
 	Method:     http.MethodGet,
 	PathParams: nil,
-	QueryParams: []*ffapi.QueryParam{
+	QueryParams: append([]*ffapi.QueryParam{
 		{Name: "fetchdata", IsBool: true, Description: coremsgs.APIFetchDataDesc},
-	},
+	}, dataValueQueryParams...),
 	FilterFactory:   database.MessageQueryFactory,
 	Description:     coremsgs.APIEndpointsGetMsgs,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*core.Message{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			if strings.EqualFold(r.QP["fetchdata"], "true") {
+			valueQuery, err := getDataValueQuery(r)
+			if err != nil {
+				return nil, err
+			}
+			fetchData := strings.EqualFold(r.QP["fetchdata"], "true")
+			switch {
+			case valueQuery != nil && fetchData:
+				return r.FilterResult(cr.or.SearchMessagesWithData(cr.ctx, r.Filter, valueQuery))
+			case valueQuery != nil:
+				return r.FilterResult(cr.or.SearchMessages(cr.ctx, r.Filter, valueQuery))
+			case fetchData:
 				return r.FilterResult(cr.or.GetMessagesWithData(cr.ctx, r.Filter))
+			default:
+				return r.FilterResult(cr.or.GetMessages(cr.ctx, r.Filter))
 			}
-			return r.FilterResult(cr.or.GetMessages(cr.ctx, r.Filter))
 		},
 	},
 }
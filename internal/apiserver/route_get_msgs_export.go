@@ -0,0 +1,109 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var getMsgsExport = &ffapi.Route{
+	Name:       "getMsgsExport",
+	Path:       "messages/export",
+	Method:     http.MethodGet,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: exportFormatParam, Example: string(exportFormatNDJSON), Description: coremsgs.APIParamsExportFormat},
+		{Name: "fetchdata", IsBool: true, Description: coremsgs.APIFetchDataDesc},
+	},
+	FilterFactory:   database.MessageQueryFactory,
+	Description:     coremsgs.APIEndpointsGetMsgsExport,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &core.Message{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		CoreExportHandler: func(r *ffapi.APIRequest, cr *coreRequest) (iterate jsonIterator) {
+			fetchData := strings.EqualFold(r.QP["fetchdata"], "true")
+			return func(emit func(record interface{}) error) error {
+				return cr.or.ExportMessages(cr.ctx, r.Filter, fetchData, func(msg *core.Message, data core.DataArray) error {
+					if fetchData {
+						msgI := &core.MessageInOut{Message: *msg}
+						msgI.SetInlineData(data)
+						return emit(msgI)
+					}
+					return emit(msg)
+				})
+			}
+		},
+		CoreExportCSV: &exportCSV{
+			header: []string{
+				"id", "cid", "type", "txtype", "author", "key", "created", "group", "topics", "tag",
+				"datahash", "hash", "batch", "txid", "state", "confirmed", "rejectReason", "data",
+			},
+			row: messageExportCSVRow,
+		},
+	},
+}
+
+// messageExportCSVRow flattens a message into a CSV row. The data column holds the JSON of the
+// data references of the message, or of the data itself if it was fetched.
+func messageExportCSVRow(record interface{}) []string {
+	var msg *core.Message
+	var data []byte
+	if msgI, ok := record.(*core.MessageInOut); ok {
+		msg = &msgI.Message
+		data, _ = json.Marshal(msgI.InlineData)
+	} else {
+		msg = record.(*core.Message)
+		data, _ = json.Marshal(msg.Data)
+	}
+	return []string{
+		msg.Header.ID.String(),
+		msg.Header.CID.String(),
+		string(msg.Header.Type),
+		string(msg.Header.TxType),
+		msg.Header.Author,
+		msg.Header.Key,
+		exportTimeString(msg.Header.Created),
+		msg.Header.Group.String(),
+		msg.Header.Topics.String(),
+		msg.Header.Tag,
+		msg.Header.DataHash.String(),
+		msg.Hash.String(),
+		msg.BatchID.String(),
+		msg.TransactionID.String(),
+		string(msg.State),
+		exportTimeString(msg.Confirmed),
+		msg.RejectReason,
+		string(data),
+	}
+}
+
+func exportTimeString(t *fftypes.FFTime) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
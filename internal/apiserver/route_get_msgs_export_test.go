@@ -0,0 +1,167 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockExportMessages(msgs []*core.Message, data core.DataArray) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		cb := args[3].(func(msg *core.Message, data core.DataArray) error)
+		for _, msg := range msgs {
+			if err := cb(msg, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestGetMessagesExportNDJSON(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages/export?tag=tag1", nil)
+	res := httptest.NewRecorder()
+
+	msgs := []*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Data: core.DataRefs{}},
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Data: core.DataRefs{}},
+	}
+	o.On("ExportMessages", mock.Anything, mock.Anything, false, mock.Anything).
+		Run(mockExportMessages(msgs, nil)).
+		Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Result().Header.Get("Content-Type"))
+	dec := json.NewDecoder(res.Body)
+	for _, msg := range msgs {
+		var output core.Message
+		assert.NoError(t, dec.Decode(&output))
+		assert.Equal(t, msg.Header.ID, output.Header.ID)
+	}
+	assert.False(t, dec.More())
+}
+
+func TestGetMessagesExportNDJSONWithData(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages/export?format=ndjson&fetchdata", nil)
+	res := httptest.NewRecorder()
+
+	msgs := []*core.Message{{Header: core.MessageHeader{ID: fftypes.NewUUID()}}}
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)}}
+	o.On("ExportMessages", mock.Anything, mock.Anything, true, mock.Anything).
+		Run(mockExportMessages(msgs, data)).
+		Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var output core.MessageInOut
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+	assert.Equal(t, msgs[0].Header.ID, output.Header.ID)
+	assert.Equal(t, `"value1"`, output.InlineData[0].Value.String())
+}
+
+func TestGetMessagesExportCSV(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages/export?format=csv", nil)
+	res := httptest.NewRecorder()
+
+	dataID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      core.MessageTypeBroadcast,
+			SignerRef: core.SignerRef{Author: "did:firefly:org/org1", Key: "0x12345"},
+			Created:   fftypes.Now(),
+			Topics:    fftypes.FFStringArray{"topic1", "topic2"},
+			Tag:       "tag1",
+		},
+		State: core.MessageStateConfirmed,
+		Data:  core.DataRefs{{ID: dataID}},
+	}
+	o.On("ExportMessages", mock.Anything, mock.Anything, false, mock.Anything).
+		Run(mockExportMessages([]*core.Message{msg}, nil)).
+		Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "text/csv", res.Result().Header.Get("Content-Type"))
+	rows, err := csv.NewReader(res.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []string{
+		msg.Header.ID.String(), "", "broadcast", "", "did:firefly:org/org1", "0x12345", msg.Header.Created.String(), "",
+		"topic1,topic2", "tag1", "", "", "", "", "confirmed", "", "", fmt.Sprintf(`[{"id":"%s"}]`, dataID),
+	}, rows[1])
+}
+
+func TestGetMessagesExportCSVWithData(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages/export?format=csv&fetchdata=true", nil)
+	res := httptest.NewRecorder()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)}}
+	o.On("ExportMessages", mock.Anything, mock.Anything, true, mock.Anything).
+		Run(mockExportMessages([]*core.Message{msg}, data)).
+		Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	rows, err := csv.NewReader(res.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, fmt.Sprintf(`[{"id":"%s","value":"value1"}]`, data[0].ID), rows[1][17])
+}
+
+func TestGetMessagesExportBadFormat(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages/export?format=xml", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10521", res.Body.String())
+}
+
+func TestGetMessagesExportUnauthorized(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages/export", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+	assert.False(t, strings.Contains(res.Body.String(), "\n{"))
+}
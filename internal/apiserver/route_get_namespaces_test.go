@@ -33,7 +33,7 @@ func TestGetNamespaces(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	mgr.On("GetNamespaces", mock.Anything, true).
+	mgr.On("GetNamespaces", mock.Anything, true, false).
 		Return([]*core.NamespaceWithInitStatus{}, nil)
 	r.ServeHTTP(res, req)
 
@@ -37,6 +37,7 @@ var getNetworkNode = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Identity{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.NetworkMap().GetNodeByNameOrID(cr.ctx, r.PP["nameOrId"])
 			return output, err
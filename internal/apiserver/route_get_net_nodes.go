@@ -37,6 +37,7 @@ var getNetworkNodes = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Identity{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.NetworkMap().GetNodes(cr.ctx, r.Filter))
 		},
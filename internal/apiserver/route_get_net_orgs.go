@@ -37,6 +37,7 @@ var getNetworkOrgs = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Identity{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.NetworkMap().GetOrganizations(cr.ctx, r.Filter))
 		},
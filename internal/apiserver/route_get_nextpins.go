@@ -37,6 +37,7 @@ var getNextPins = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []core.NextPin{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.GetNextPins(cr.ctx, r.Filter))
 		},
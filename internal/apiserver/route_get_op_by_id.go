@@ -34,18 +34,38 @@ var getOpByID = &ffapi.Route{
 	},
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "fetchstatus", Example: "true", Description: coremsgs.APIParamsFetchStatus, IsBool: true},
+		{Name: "verbose", Example: "true", Description: coremsgs.APIParamsOperationVerbose, IsBool: true},
 	},
 	Description:     coremsgs.APIEndpointsGetOpByID,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return &core.OperationWithDetail{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			verbose := strings.EqualFold(r.QP["verbose"], "true")
 			if strings.EqualFold(r.QP["fetchstatus"], "true") {
-				return cr.or.GetOperationByIDWithStatus(cr.ctx, r.PP["opid"])
+				op, err := cr.or.GetOperationByIDWithStatus(cr.ctx, r.PP["opid"])
+				if err == nil && verbose {
+					op.LastError = op.Operation.LastError
+				}
+				return op, err
 			}
-			output, err = cr.or.GetOperationByID(cr.ctx, r.PP["opid"])
-			return output, err
+			op, err := cr.or.GetOperationByID(cr.ctx, r.PP["opid"])
+			if err == nil && verbose {
+				return withLastError(op), nil
+			}
+			return op, err
 		},
+		CoreVersionToken: operationVersion,
 	},
 }
+
+// withLastError returns the operation with the structured error from the connector, which is not
+// included by default as it can be large
+func withLastError(op *core.Operation) *core.OperationWithDetail {
+	return &core.OperationWithDetail{
+		Operation: *op,
+		LastError: op.LastError,
+	}
+}
@@ -17,9 +17,12 @@
 package apiserver
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -51,4 +54,96 @@ func TestGetOperationByIDFetchStatus(t *testing.T) {
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get("ETag"))
+}
+
+func TestGetOperationByIDETag(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345", nil)
+	req.Header.Set("If-None-Match", `W/"other"`)
+	res := httptest.NewRecorder()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Updated: fftypes.Now()}
+	o.On("GetOperationByID", mock.Anything, "abcd12345").Return(op, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, fmt.Sprintf(`W/"%s.%d"`, op.ID, op.Updated.UnixNano()), res.Result().Header.Get("ETag"))
+}
+
+func TestGetOperationByIDNotModified(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	op := &core.Operation{ID: fftypes.NewUUID(), Updated: fftypes.Now()}
+	etag := fmt.Sprintf(`W/"%s.%d"`, op.ID, op.Updated.UnixNano())
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345", nil)
+	req.Header.Set("If-None-Match", etag)
+	res := httptest.NewRecorder()
+
+	o.On("GetOperationByID", mock.Anything, "abcd12345").Return(op, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 304, res.Result().StatusCode)
+	assert.Equal(t, etag, res.Result().Header.Get("ETag"))
+	assert.Empty(t, res.Body.Bytes())
+}
+
+func TestGetOperationByIDFail(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345", nil)
+	req.Header.Set("If-None-Match", "*")
+	res := httptest.NewRecorder()
+
+	o.On("GetOperationByID", mock.Anything, "abcd12345").Return(nil, fmt.Errorf("pop"))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get("ETag"))
+}
+
+func TestGetOperationByIDVerbose(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	op := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Status:    core.OpStatusFailed,
+		LastError: &core.OperationError{StatusCode: 500, Code: "FF23021", Body: "reverted"},
+	}
+	o.On("GetOperationByID", mock.Anything, "abcd12345").Return(op, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var output map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&output)
+	assert.NotContains(t, output, "lastError")
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345?verbose=true", nil)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var verbose core.OperationWithDetail
+	json.NewDecoder(res.Body).Decode(&verbose)
+	assert.Equal(t, op.ID, verbose.ID)
+	assert.Equal(t, op.LastError, verbose.LastError)
+}
+
+func TestGetOperationByIDFetchStatusVerbose(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/abcd12345?fetchstatus&verbose", nil)
+	res := httptest.NewRecorder()
+
+	lastError := &core.OperationError{StatusCode: 500, Body: "reverted"}
+	o.On("GetOperationByIDWithStatus", mock.Anything, "abcd12345").
+		Return(&core.OperationWithDetail{Operation: core.Operation{LastError: lastError}}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var output core.OperationWithDetail
+	json.NewDecoder(res.Body).Decode(&output)
+	assert.Equal(t, lastError, output.LastError)
 }
@@ -35,6 +35,7 @@ var getStatus = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.NamespaceStatus{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.GetStatus(cr.ctx)
 			return output, err
@@ -0,0 +1,58 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const defaultErrorReportWindow = 1 * time.Hour
+
+var getStatusErrors = &ffapi.Route{
+	Name:       "getStatusErrors",
+	Path:       "status/errors",
+	Method:     http.MethodGet,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "window", Description: coremsgs.APIErrorReportWindowParam},
+	},
+	Description:     coremsgs.APIEndpointsGetStatusErrors,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &core.ErrorReport{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			window := defaultErrorReportWindow
+			if windowStr := r.QP["window"]; windowStr != "" {
+				d, err := fftypes.ParseDurationString(windowStr, time.Millisecond)
+				if err != nil || d <= 0 {
+					return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidErrorReportWindow, windowStr)
+				}
+				window = time.Duration(d)
+			}
+			return cr.or.GetErrorReport(cr.ctx, window)
+		},
+	},
+}
@@ -35,6 +35,7 @@ var getStatusMultiparty = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.NamespaceMultipartyStatus{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.GetMultipartyStatus(cr.ctx)
 			return output, err
@@ -0,0 +1,65 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetStatusReady(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/status/ready", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetReadiness", mock.Anything).
+		Return(&core.NamespaceReadiness{Ready: true, Plugins: []*core.PluginReadiness{}})
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetStatusReadyUnavailable(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/status/ready", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetReadiness", mock.Anything).
+		Return(&core.NamespaceReadiness{
+			Ready: false,
+			Plugins: []*core.PluginReadiness{
+				{Category: "database", Name: "database0", Critical: true, Error: "pop"},
+			},
+		})
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 503, res.Result().StatusCode)
+	var readiness core.NamespaceReadiness
+	err := json.NewDecoder(res.Body).Decode(&readiness)
+	assert.NoError(t, err)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, "pop", readiness.Plugins[0].Error)
+}
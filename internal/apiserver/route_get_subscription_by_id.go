@@ -40,6 +40,7 @@ var getSubscriptionByID = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Subscription{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			if strings.EqualFold(r.QP["fetchstatus"], "true") {
 				return cr.or.GetSubscriptionByIDWithStatus(cr.ctx, r.PP["subid"])
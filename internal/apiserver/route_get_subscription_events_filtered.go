@@ -45,6 +45,7 @@ var getSubscriptionEventsFiltered = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Event{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			subscription, _ := cr.or.GetSubscriptionByID(cr.ctx, r.PP["subid"])
 			var startSeq int
@@ -37,6 +37,7 @@ var getSubscriptions = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Subscription{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.GetSubscriptions(cr.ctx, r.Filter))
 		},
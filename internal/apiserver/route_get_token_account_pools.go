@@ -39,6 +39,7 @@ var getTokenAccountPools = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.TokenAccountPool{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.Assets().GetTokenAccountPools(cr.ctx, r.PP["key"], r.Filter))
 		},
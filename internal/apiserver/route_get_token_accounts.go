@@ -37,6 +37,7 @@ var getTokenAccounts = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.TokenAccount{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.Assets().GetTokenAccounts(cr.ctx, r.Filter))
 		},
@@ -36,6 +36,7 @@ var getTokenApprovals = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.TokenApproval{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			filter := r.Filter
 			return r.FilterResult(cr.or.Assets().GetTokenApprovals(cr.ctx, filter))
@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -26,18 +27,24 @@ import (
 )
 
 var getTokenBalances = &ffapi.Route{
-	Name:            "getTokenBalances",
-	Path:            "tokens/balances",
-	Method:          http.MethodGet,
-	PathParams:      nil,
-	QueryParams:     nil,
+	Name:       "getTokenBalances",
+	Path:       "tokens/balances",
+	Method:     http.MethodGet,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "fetchmetadata", Example: "true", Description: coremsgs.APIParamsFetchMetadata, IsBool: true},
+	},
 	FilterFactory:   database.TokenBalanceQueryFactory,
 	Description:     coremsgs.APIEndpointsGetTokenBalances,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []*core.TokenBalance{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			if strings.EqualFold(r.QP["fetchmetadata"], "true") {
+				return r.FilterResult(cr.or.Assets().GetTokenBalancesWithMetadata(cr.ctx, r.Filter))
+			}
 			return r.FilterResult(cr.or.Assets().GetTokenBalances(cr.ctx, r.Filter))
 		},
 	},
@@ -37,6 +37,7 @@ var getTokenPools = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.TokenPool{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.Assets().GetTokenPools(cr.ctx, r.Filter))
 		},
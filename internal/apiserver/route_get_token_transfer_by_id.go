@@ -37,6 +37,7 @@ var getTokenTransferByID = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.TokenTransfer{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.Assets().GetTokenTransferByID(cr.ctx, r.PP["transferId"])
 			return output, err
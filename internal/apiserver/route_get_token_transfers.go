@@ -39,6 +39,7 @@ var getTokenTransfers = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.TokenTransfer{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			filter := r.Filter
 			if fromOrTo, ok := r.QP["fromOrTo"]; ok {
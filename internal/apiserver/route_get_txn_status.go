@@ -37,6 +37,7 @@ var getTxnStatus = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.TransactionStatus{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return cr.or.GetTransactionStatus(cr.ctx, r.PP["txnid"])
 		},
@@ -37,6 +37,7 @@ var getVerifierByID = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Verifier{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return cr.or.NetworkMap().GetVerifierByHash(cr.ctx, r.PP["hash"])
 		},
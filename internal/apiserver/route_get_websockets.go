@@ -37,6 +37,7 @@ var getWebSockets = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.WebSocketStatus{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			ws, _ := eifactory.GetPlugin(cr.ctx, "websockets")
 			return ws.(*websockets.WebSockets).GetStatus(), nil
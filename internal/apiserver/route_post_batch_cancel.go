@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var postBatchCancel = &ffapi.Route{
@@ -36,6 +37,7 @@ var postBatchCancel = &ffapi.Route{
 	JSONOutputValue: nil,
 	JSONOutputCodes: []int{http.StatusNoContent},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return nil, cr.or.BatchManager().CancelBatch(cr.ctx, r.PP["batchid"])
 		},
@@ -35,22 +35,36 @@ var postContractAPIInvoke = &ffapi.Route{
 		{Name: "methodPath", Description: coremsgs.APIParamsMethodPath},
 	},
 	QueryParams: []*ffapi.QueryParam{
-		{Name: "confirm", Description: coremsgs.APIConfirmMsgQueryParam, IsBool: true, Example: "true"},
+		{Name: "confirm", Description: coremsgs.APIConfirmInvokeQueryParam, IsBool: true, Example: "true"},
+		{Name: "confirmTimeout", Description: coremsgs.APIConfirmTimeoutQueryParam, Example: "30s"},
+		{Name: "simulate", Description: coremsgs.APISimulateQueryParam, IsBool: true, Example: "true"},
 	},
 	Description:     coremsgs.APIEndpointsPostContractAPIInvoke,
 	JSONInputValue:  func() interface{} { return &core.ContractCallRequest{} },
 	JSONOutputValue: func() interface{} { return &core.Operation{} },
 	JSONOutputCodes: []int{http.StatusOK, http.StatusAccepted},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Contracts() != nil
 		},
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
+			if strings.EqualFold(r.QP["simulate"], "true") {
+				r.SuccessStatus = http.StatusOK
+				req := r.Input.(*core.ContractCallRequest)
+				req.Type = core.CallTypeInvoke
+				return cr.or.Contracts().SimulateContractAPI(cr.ctx, r.PP["apiName"], r.PP["methodPath"], req)
+			}
+			ctx, cancel, waitConfirm, err := confirmTimeout(r, cr, strings.EqualFold(r.QP["confirm"], "true"))
+			if err != nil {
+				return nil, err
+			}
+			defer cancel()
 			r.SuccessStatus = syncRetcode(waitConfirm)
 			req := r.Input.(*core.ContractCallRequest)
 			req.Type = core.CallTypeInvoke
-			return cr.or.Contracts().InvokeContractAPI(cr.ctx, r.PP["apiName"], r.PP["methodPath"], req, waitConfirm)
+			req.IdempotencyKey = idempotencyKey(r, req.IdempotencyKey)
+			return cr.or.Contracts().InvokeContractAPI(ctx, r.PP["apiName"], r.PP["methodPath"], req, waitConfirm)
 		},
 	},
 }
@@ -39,6 +39,7 @@ var postContractDeploy = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Operation{} },
 	JSONOutputCodes: []int{http.StatusOK, http.StatusAccepted},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Contracts() != nil
 		},
@@ -46,6 +47,7 @@ var postContractDeploy = &ffapi.Route{
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
 			req := r.Input.(*core.ContractDeployRequest)
+			req.IdempotencyKey = idempotencyKey(r, req.IdempotencyKey)
 			return cr.or.Contracts().DeployContract(cr.ctx, req, waitConfirm)
 		},
 	},
@@ -18,30 +18,43 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var postContractInterfaceGenerate = &ffapi.Route{
-	Name:            "postGenerateContractInterface",
-	Path:            "contracts/interfaces/generate",
-	Method:          http.MethodPost,
-	PathParams:      nil,
-	QueryParams:     []*ffapi.QueryParam{},
+	Name:       "postGenerateContractInterface",
+	Path:       "contracts/interfaces/generate",
+	Method:     http.MethodPost,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "format", Description: coremsgs.APIFFIGenerateFormatParam},
+	},
 	Description:     coremsgs.APIEndpointsPostContractInterfaceGenerate,
 	JSONInputValue:  func() interface{} { return &fftypes.FFIGenerationRequest{} },
 	JSONOutputValue: func() interface{} { return &fftypes.FFI{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Contracts() != nil
 		},
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			generationRequest := r.Input.(*fftypes.FFIGenerationRequest)
-			return cr.or.Contracts().GenerateFFI(cr.ctx, generationRequest)
+			switch format := r.QP["format"]; strings.ToLower(format) {
+			case "", "native":
+				return cr.or.Contracts().GenerateFFI(cr.ctx, generationRequest)
+			case "solidity":
+				return cr.or.Contracts().GenerateFFIFromSource(cr.ctx, generationRequest)
+			default:
+				return nil, i18n.NewError(cr.ctx, coremsgs.MsgUnknownFFIGenerationFormat, format)
+			}
 		},
 	},
 }
@@ -47,3 +47,42 @@ func TestPostContractInterfaceGenerate(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestPostContractInterfaceGenerateSolidity(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	input := fftypes.FFIGenerationRequest{
+		Input: fftypes.JSONAnyPtr(`{"source": "contract Simple {}"}`),
+	}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/contracts/interfaces/generate?format=solidity", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mcm.On("GenerateFFIFromSource", mock.Anything, mock.AnythingOfType("*fftypes.FFIGenerationRequest")).
+		Return(&fftypes.FFI{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	mcm.AssertExpectations(t)
+}
+
+func TestPostContractInterfaceGenerateUnknownFormat(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&fftypes.FFIGenerationRequest{})
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/contracts/interfaces/generate?format=vyper", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	mcm.AssertExpectations(t)
+}
@@ -43,6 +43,7 @@ var postContractListenerSignature = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.ContractListenerSignatureOutput{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleReader,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Contracts() != nil
 		},
@@ -48,6 +48,7 @@ var postData = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Data{} },
 	JSONOutputCodes: []int{http.StatusCreated},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Data() != nil
 		},
@@ -59,29 +60,33 @@ var postData = &ffapi.Route{
 			if !cr.or.Data().BlobsEnabled() {
 				return nil, i18n.NewError(r.Req.Context(), coremsgs.MsgActionNotSupported)
 			}
-
-			data := &core.DataRefOrValue{}
-			validator := r.FP["validator"]
-			if len(validator) > 0 {
-				data.Validator = core.ValidatorType(validator)
-			}
-			if r.FP["datatype.name"] != "" {
-				data.Datatype = &core.DatatypeRef{
-					Name:    r.FP["datatype.name"],
-					Version: r.FP["datatype.version"],
-				}
-			}
-			metadata := r.FP["metadata"]
-			if len(metadata) > 0 {
-				// The metadata might be JSON, or just a simple string. Try to unmarshal and see
-				var marshalCheck interface{}
-				if err := json.Unmarshal([]byte(metadata), &marshalCheck); err != nil {
-					metadata = fmt.Sprintf(`"%s"`, metadata)
-				}
-				data.Value = fftypes.JSONAnyPtr(metadata)
-			}
-			output, err = cr.or.Data().UploadBlob(cr.ctx, data, r.Part, strings.EqualFold(r.FP["autometa"], "true"))
+			output, err = cr.or.Data().UploadBlob(cr.ctx, dataFromUploadForm(r), r.Part, strings.EqualFold(r.FP["autometa"], "true"))
 			return output, err
 		},
 	},
 }
+
+// dataFromUploadForm builds the data item a blob is attached to, from the form fields of an upload
+func dataFromUploadForm(r *ffapi.APIRequest) *core.DataRefOrValue {
+	data := &core.DataRefOrValue{}
+	validator := r.FP["validator"]
+	if len(validator) > 0 {
+		data.Validator = core.ValidatorType(validator)
+	}
+	if r.FP["datatype.name"] != "" {
+		data.Datatype = &core.DatatypeRef{
+			Name:    r.FP["datatype.name"],
+			Version: r.FP["datatype.version"],
+		}
+	}
+	metadata := r.FP["metadata"]
+	if len(metadata) > 0 {
+		// The metadata might be JSON, or just a simple string. Try to unmarshal and see
+		var marshalCheck interface{}
+		if err := json.Unmarshal([]byte(metadata), &marshalCheck); err != nil {
+			metadata = fmt.Sprintf(`"%s"`, metadata)
+		}
+		data.Value = fftypes.JSONAnyPtr(metadata)
+	}
+	return data
+}
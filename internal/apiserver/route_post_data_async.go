@@ -0,0 +1,60 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var postDataAsync = &ffapi.Route{
+	Name:        "postDataAsync",
+	Path:        "data/async",
+	Method:      http.MethodPost,
+	PathParams:  nil,
+	QueryParams: nil,
+	FormParams: []*ffapi.FormParam{
+		{Name: "autometa", Description: coremsgs.APIParamsAutometa},
+		{Name: "metadata", Description: coremsgs.APIParamsMetadata},
+		{Name: "validator", Description: coremsgs.APIParamsValidator},
+		{Name: "datatype.name", Description: coremsgs.APIParamsDatatypeName},
+		{Name: "datatype.version", Description: coremsgs.APIParamsDatatypeVersion},
+	},
+	Description:     coremsgs.APIEndpointsPostDataAsync,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &core.BlobUpload{} },
+	JSONOutputCodes: []int{http.StatusAccepted},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.Data().BlobsEnabled()
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			return nil, i18n.NewError(cr.ctx, coremsgs.MsgAsyncUploadNotMultipart)
+		},
+		CoreFormUploadHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			output, err = cr.or.Data().UploadBlobAsync(cr.ctx, dataFromUploadForm(r), r.Part, strings.EqualFold(r.FP["autometa"], "true"))
+			return output, err
+		},
+	},
+}
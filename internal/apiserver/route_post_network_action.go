@@ -36,6 +36,7 @@ var postNetworkAction = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.NetworkAction{} },
 	JSONOutputCodes: []int{http.StatusAccepted},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.MultiParty() != nil
 		},
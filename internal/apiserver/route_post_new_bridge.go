@@ -0,0 +1,61 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var postNewBridge = &ffapi.Route{
+	Name:            "postNewBridge",
+	Path:            "bridges",
+	Method:          http.MethodPost,
+	PathParams:      nil,
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsPostNewBridge,
+	JSONInputValue:  func() interface{} { return &core.Bridge{} },
+	JSONOutputValue: func() interface{} { return &core.Bridge{} },
+	JSONOutputCodes: []int{http.StatusCreated}, // Sync operation
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			b := r.Input.(*core.Bridge)
+			if b.Target != "" {
+				// The bridge broadcasts into the target namespace, signed by the org of that namespace, so the
+				// caller must be authorized as an admin of the target namespace as well
+				target, err := cr.mgr.Orchestrator(cr.ctx, b.Target, false)
+				if err != nil {
+					return nil, err
+				}
+				authReq := &fftypes.AuthReq{
+					Method: r.Req.Method,
+					URL:    r.Req.URL,
+					Header: r.Req.Header,
+				}
+				if err := target.Authorize(core.WithRequiredAPIRole(cr.ctx, core.APIRoleAdmin), authReq); err != nil {
+					return nil, err
+				}
+			}
+			return cr.or.CreateBridge(cr.ctx, b)
+		},
+	},
+}
@@ -32,16 +32,22 @@ var postNewIdentity = &ffapi.Route{
 	PathParams: nil,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "confirm", Description: coremsgs.APIConfirmMsgQueryParam, IsBool: true},
+		{Name: "confirmTimeout", Description: coremsgs.APIConfirmTimeoutQueryParam},
 	},
 	Description:     coremsgs.APIEndpointsPostNewIdentity,
 	JSONInputValue:  func() interface{} { return &core.IdentityCreateDTO{} },
 	JSONOutputValue: func() interface{} { return &core.Identity{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
+			ctx, cancel, waitConfirm, err := confirmTimeout(r, cr, strings.EqualFold(r.QP["confirm"], "true"))
+			if err != nil {
+				return nil, err
+			}
+			defer cancel()
 			r.SuccessStatus = syncRetcode(waitConfirm)
-			org, err := cr.or.NetworkMap().RegisterIdentity(cr.ctx, r.Input.(*core.IdentityCreateDTO), waitConfirm)
+			org, err := cr.or.NetworkMap().RegisterIdentity(ctx, r.Input.(*core.IdentityCreateDTO), waitConfirm)
 			return org, err
 		},
 	},
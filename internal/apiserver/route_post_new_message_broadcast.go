@@ -39,13 +39,16 @@ var postNewMessageBroadcast = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Message{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.MultiParty() != nil
 		},
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
-			output, err = cr.or.Broadcast().BroadcastMessage(cr.ctx, r.Input.(*core.MessageInOut), waitConfirm)
+			msg := r.Input.(*core.MessageInOut)
+			msg.IdempotencyKey = idempotencyKey(r, msg.IdempotencyKey)
+			output, err = cr.or.Broadcast().BroadcastMessage(cr.ctx, msg, waitConfirm)
 			return output, err
 		},
 	},
@@ -35,6 +35,7 @@ var postNewSubscription = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Subscription{} },
 	JSONOutputCodes: []int{http.StatusCreated}, // Sync operation
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			output, err = cr.or.CreateSubscription(cr.ctx, r.Input.(*core.Subscription))
 			return output, err
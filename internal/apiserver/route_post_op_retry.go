@@ -38,6 +38,7 @@ var postOpRetry = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.Operation{} },
 	JSONOutputCodes: []int{http.StatusAccepted},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			opid, err := fftypes.ParseUUID(cr.ctx, r.PP["opid"])
 			if err != nil {
@@ -35,6 +35,7 @@ var postPinsRewind = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.PinRewind{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return cr.or.RewindPins(cr.ctx, r.Input.(*core.PinRewind))
 		},
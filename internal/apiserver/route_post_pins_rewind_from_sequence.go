@@ -0,0 +1,54 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var postPinsRewindFromSequence = &ffapi.Route{
+	Name:   "postPinsRewindFromSequence",
+	Path:   "pins/{sequence}/rewind",
+	Method: http.MethodPost,
+	PathParams: []*ffapi.PathParam{
+		{Name: "sequence", Description: coremsgs.APIParamsPinSequence},
+	},
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "dryrun", Example: "true", Description: coremsgs.APIParamsDryRun, IsBool: true},
+	},
+	Description:     coremsgs.APIEndpointsPostPinsRewindFromSequence,
+	JSONInputValue:  func() interface{} { return &core.EmptyInput{} },
+	JSONOutputValue: func() interface{} { return &core.PinRewindResult{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			sequence, err := strconv.ParseInt(r.PP["sequence"], 10, 64)
+			if err != nil {
+				return nil, i18n.NewError(cr.ctx, coremsgs.MsgInvalidPinSequence, r.PP["sequence"])
+			}
+			return cr.or.RewindPinsFromSequence(cr.ctx, sequence, strings.EqualFold(r.QP["dryrun"], "true"))
+		},
+	},
+}
@@ -0,0 +1,54 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/networkmap"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostResolveIdentityDIDDocs(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	nmn := &networkmapmocks.Manager{}
+	o.On("NetworkMap").Return(nmn)
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode([]string{"did:firefly:org/org_1", "did:firefly:org/org_2"})
+	req := httptest.NewRequest("POST", "/api/v1/identities/dids", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	nmn.On("ResolveDIDDocuments", mock.Anything, []string{"did:firefly:org/org_1", "did:firefly:org/org_2"}).
+		Return(map[string]*networkmap.DIDDocumentResult{
+			"did:firefly:org/org_1": {Document: &networkmap.DIDDocument{ID: "did:firefly:org/org_1"}},
+			"did:firefly:org/org_2": {Error: "FF10109: not found"},
+		}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var results map[string]*networkmap.DIDDocumentResult
+	json.NewDecoder(res.Body).Decode(&results)
+	assert.Equal(t, "did:firefly:org/org_1", results["did:firefly:org/org_1"].Document.ID)
+	assert.Equal(t, "FF10109: not found", results["did:firefly:org/org_2"].Error)
+}
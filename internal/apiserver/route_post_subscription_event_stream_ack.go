@@ -0,0 +1,56 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/eifactory"
+	"github.com/hyperledger/firefly/internal/events/sse"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var postSubscriptionEventStreamAck = &ffapi.Route{
+	Name:   "postSubscriptionEventStreamAck",
+	Path:   "subscriptions/{subid}/events/stream/ack",
+	Method: http.MethodPost,
+	PathParams: []*ffapi.PathParam{
+		{Name: "subid", Description: coremsgs.APIParamsSubscriptionID},
+	},
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsPostSubscriptionEventStreamAck,
+	JSONInputValue:  func() interface{} { return &core.SSEAck{} },
+	JSONOutputValue: nil,
+	JSONOutputCodes: []int{http.StatusNoContent},
+	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			sub, err := cr.or.GetSubscriptionByID(cr.ctx, r.PP["subid"])
+			if err != nil {
+				return nil, err
+			}
+			if sub == nil {
+				return nil, i18n.NewError(cr.ctx, coremsgs.Msg404NotFound)
+			}
+			plugin, _ := eifactory.GetPlugin(cr.ctx, "sse")
+			return nil, plugin.(*sse.SSE).Ack(cr.ctx, sub, r.Input.(*core.SSEAck))
+		},
+	},
+}
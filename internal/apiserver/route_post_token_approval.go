@@ -44,10 +44,13 @@ var postTokenApproval = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.TokenApproval{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
-			return cr.or.Assets().TokenApproval(cr.ctx, r.Input.(*core.TokenApprovalInput), waitConfirm)
+			approval := r.Input.(*core.TokenApprovalInput)
+			approval.IdempotencyKey = idempotencyKey(r, approval.IdempotencyKey)
+			return cr.or.Assets().TokenApproval(cr.ctx, approval, waitConfirm)
 		},
 	},
 }
@@ -38,10 +38,13 @@ var postTokenBurn = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.TokenTransfer{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
-			return cr.or.Assets().BurnTokens(cr.ctx, r.Input.(*core.TokenTransferInput), waitConfirm)
+			transfer := r.Input.(*core.TokenTransferInput)
+			transfer.IdempotencyKey = idempotencyKey(r, transfer.IdempotencyKey)
+			return cr.or.Assets().BurnTokens(cr.ctx, transfer, waitConfirm)
 		},
 	},
 }
@@ -46,3 +46,47 @@ func TestPostTokenMint(t *testing.T) {
 
 	assert.Equal(t, 202, res.Result().StatusCode)
 }
+
+func TestPostTokenMintIdempotencyKeyHeader(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mam := &assetmocks.Manager{}
+	o.On("Assets").Return(mam)
+	input := core.TokenTransferInput{}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/tokens/mint", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Idempotency-Key", "idem1")
+	res := httptest.NewRecorder()
+
+	mam.On("MintTokens", mock.Anything, mock.MatchedBy(func(transfer *core.TokenTransferInput) bool {
+		return transfer.IdempotencyKey == "idem1"
+	}), false).Return(&core.TokenTransfer{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	mam.AssertExpectations(t)
+}
+
+func TestPostTokenMintIdempotencyKeyBodyPreferred(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mam := &assetmocks.Manager{}
+	o.On("Assets").Return(mam)
+	input := core.TokenTransferInput{IdempotencyKey: "body1"}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/tokens/mint", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Idempotency-Key", "header1")
+	res := httptest.NewRecorder()
+
+	mam.On("MintTokens", mock.Anything, mock.MatchedBy(func(transfer *core.TokenTransferInput) bool {
+		return transfer.IdempotencyKey == "body1"
+	}), false).Return(&core.TokenTransfer{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	mam.AssertExpectations(t)
+}
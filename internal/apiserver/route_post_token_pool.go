@@ -39,11 +39,13 @@ var postTokenPool = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return &core.TokenPool{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
 			pool := r.Input.(*core.TokenPoolInput)
 			pool.Published = strings.EqualFold(r.QP["publish"], "true")
+			pool.IdempotencyKey = idempotencyKey(r, pool.IdempotencyKey)
 			return cr.or.Assets().CreateTokenPool(cr.ctx, pool, waitConfirm)
 		},
 	},
@@ -32,16 +32,24 @@ var postTokenTransfer = &ffapi.Route{
 	PathParams: nil,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "confirm", Description: coremsgs.APIConfirmMsgQueryParam, IsBool: true},
+		{Name: "simulate", Description: coremsgs.APISimulateQueryParam, IsBool: true},
 	},
 	Description:     coremsgs.APIEndpointsPostTokenTransfer,
 	JSONInputValue:  func() interface{} { return &core.TokenTransferInput{} },
 	JSONOutputValue: func() interface{} { return &core.TokenTransfer{} },
 	JSONOutputCodes: []int{http.StatusAccepted, http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleSubmitter,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			if strings.EqualFold(r.QP["simulate"], "true") {
+				r.SuccessStatus = http.StatusOK
+				return cr.or.Assets().SimulateTransferTokens(cr.ctx, r.Input.(*core.TokenTransferInput))
+			}
 			waitConfirm := strings.EqualFold(r.QP["confirm"], "true")
 			r.SuccessStatus = syncRetcode(waitConfirm)
-			return cr.or.Assets().TransferTokens(cr.ctx, r.Input.(*core.TokenTransferInput), waitConfirm)
+			transfer := r.Input.(*core.TokenTransferInput)
+			transfer.IdempotencyKey = idempotencyKey(r, transfer.IdempotencyKey)
+			return cr.or.Assets().TransferTokens(cr.ctx, transfer, waitConfirm)
 		},
 	},
 }
@@ -31,6 +31,7 @@ var spiGetNamespaces = &ffapi.Route{
 	Method: http.MethodGet,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "includeinitializing", Example: "true", Description: coremsgs.APIParamsNSIncludeInitializing, IsBool: true},
+		{Name: "includeplugins", Example: "true", Description: coremsgs.APIParamsNSIncludePlugins, IsBool: true},
 	},
 	FilterFactory:   nil,
 	Description:     coremsgs.APIEndpointsAdminGetNamespaces,
@@ -38,8 +39,11 @@ var spiGetNamespaces = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.NamespaceWithInitStatus{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return cr.mgr.GetNamespaces(cr.ctx, strings.EqualFold(r.QP["includeinitializing"], "true"))
+			return cr.mgr.GetNamespaces(cr.ctx,
+				strings.EqualFold(r.QP["includeinitializing"], "true"),
+				strings.EqualFold(r.QP["includeplugins"], "true"))
 		},
 	},
 }
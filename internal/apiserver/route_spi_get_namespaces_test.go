@@ -32,7 +32,21 @@ func TestSPIGetNamespaces(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	mgr.On("GetNamespaces", mock.Anything, false).
+	mgr.On("GetNamespaces", mock.Anything, false, false).
+		Return([]*core.NamespaceWithInitStatus{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestSPIGetNamespacesIncludePlugins(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createAdminMuxRouter(mgr)
+	req := httptest.NewRequest("GET", "/spi/v1/namespaces?includeinitializing&includeplugins", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, true, true).
 		Return([]*core.NamespaceWithInitStatus{}, nil, nil)
 	r.ServeHTTP(res, req)
 
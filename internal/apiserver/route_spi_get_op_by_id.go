@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -31,15 +32,22 @@ var spiGetOpByID = &ffapi.Route{
 	PathParams: []*ffapi.PathParam{
 		{Name: "nsopid", Description: coremsgs.APIParamsOperationNamespacedID},
 	},
-	QueryParams:     nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "verbose", Example: "true", Description: coremsgs.APIParamsOperationVerbose, IsBool: true},
+	},
 	Description:     coremsgs.APIEndpointsAdminGetOpByID,
 	JSONInputValue:  nil,
-	JSONOutputValue: func() interface{} { return &core.Operation{} },
+	JSONOutputValue: func() interface{} { return &core.OperationWithDetail{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			output, err = cr.mgr.GetOperationByNamespacedID(cr.ctx, r.PP["nsopid"])
-			return output, err
+			op, err := cr.mgr.GetOperationByNamespacedID(cr.ctx, r.PP["nsopid"])
+			if err == nil && strings.EqualFold(r.QP["verbose"], "true") {
+				return withLastError(op), nil
+			}
+			return op, err
 		},
+		CoreVersionToken: operationVersion,
 	},
 }
@@ -17,9 +17,12 @@
 package apiserver
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -38,3 +41,35 @@ func TestSPIGetOperationByID(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestSPIGetOperationByIDNotModified(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createAdminMuxRouter(mgr)
+	op := &core.Operation{ID: fftypes.NewUUID(), Updated: fftypes.Now()}
+	req := httptest.NewRequest("GET", "/spi/v1/operations/ns1:0df3d864-2646-4e5d-8585-51eb154a8d23", nil)
+	req.Header.Set("If-None-Match", fmt.Sprintf(`W/"other", "%s.%d"`, op.ID, op.Updated.UnixNano()))
+	res := httptest.NewRecorder()
+
+	mgr.On("GetOperationByNamespacedID", mock.Anything, "ns1:0df3d864-2646-4e5d-8585-51eb154a8d23").
+		Return(op, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 304, res.Result().StatusCode)
+}
+
+func TestSPIGetOperationByIDVerbose(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createAdminMuxRouter(mgr)
+	req := httptest.NewRequest("GET", "/spi/v1/operations/ns1:0df3d864-2646-4e5d-8585-51eb154a8d23?verbose=true", nil)
+	res := httptest.NewRecorder()
+
+	lastError := &core.OperationError{StatusCode: 500, Body: "reverted"}
+	mgr.On("GetOperationByNamespacedID", mock.Anything, "ns1:0df3d864-2646-4e5d-8585-51eb154a8d23").
+		Return(&core.Operation{LastError: lastError}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var output core.OperationWithDetail
+	json.NewDecoder(res.Body).Decode(&output)
+	assert.Equal(t, lastError, output.LastError)
+}
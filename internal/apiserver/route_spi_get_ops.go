@@ -37,6 +37,7 @@ var spiGetOps = &ffapi.Route{
 	JSONOutputCodes: []int{http.StatusOK},
 	Tag:             routeTagNonDefaultNamespace,
 	Extensions: &coreExtensions{
+		Permission: core.APIRoleAdmin,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			return r.FilterResult(cr.or.GetOperations(cr.ctx, r.Filter))
 		},
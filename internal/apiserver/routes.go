@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/namespace"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 type coreRequest struct {
@@ -34,9 +35,26 @@ type coreRequest struct {
 }
 
 type coreExtensions struct {
+	// Permission is the role a caller must hold to invoke the route, which is checked by the auth plugin of the
+	// namespace. Every route must declare one.
+	Permission            core.APIRole
 	EnabledIf             func(or orchestrator.Orchestrator) bool
 	CoreJSONHandler       func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error)
 	CoreFormUploadHandler func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error)
+	// CoreJSONStreamHandler opts a list route into streaming its output as a JSON array, written and flushed record by
+	// record. Streamed routes reject count=true as there is no place in the array for a total, and do not support
+	// paging with a cursor.
+	CoreJSONStreamHandler func(r *ffapi.APIRequest, cr *coreRequest) (iterate jsonIterator, err error)
+	// CoreExportHandler opts a route into a bulk export of every record that matches the filter, streamed as NDJSON
+	// or as CSV according to the format param. CSV is only available if CoreExportCSV is set.
+	CoreExportHandler func(r *ffapi.APIRequest, cr *coreRequest) (iterate jsonIterator)
+	CoreExportCSV     *exportCSV
+	// CoreVersionToken opts a single-resource route into conditional GET, by extracting a token for the version
+	// of the output that is returned as a weak ETag. Outputs without a version return an empty token.
+	CoreVersionToken func(output interface{}) string
+	// RateLimit opts a route into a rate limit per caller, checked after authorization. The limit is shared by
+	// all the routes in the group, and is only enforced when configured.
+	RateLimit *rateLimitGroup
 }
 
 const (
@@ -48,29 +66,45 @@ const (
 var nsRoutes = []*ffapi.Route{}
 var routes = append(
 	globalRoutes([]*ffapi.Route{
+		getDIDDoc,
 		getNamespace,
 		getNamespaces,
+		getStatusHA,
 		getWebSockets,
 	}),
 	namespacedRoutes([]*ffapi.Route{
+		deleteBridge,
 		deleteContractAPI,
+		deleteContractAPIListeners,
 		deleteContractInterface,
 		deleteContractListener,
 		deleteData,
+		deleteDataUpload,
 		deleteSubscription,
 		deleteTokenPool,
+		getAllContractAPIListeners,
 		getBatchByID,
 		getBatches,
 		getBlockchainEventByID,
+		getBlockchainEventOutput,
 		getBlockchainEvents,
+		getBridgeByNameOrID,
+		getBridges,
 		getChartHistogram,
+		getChartTimeSeries,
 		getContractAPIByName,
 		getContractAPIInterface,
 		getContractAPIs,
+		getContractAPIListenerEvents,
+		getContractAPIListenersHealth, // must precede getContractAPIListeners
 		getContractAPIListeners,
 		getContractInterface,
+		getContractInterfaceDiff,
+		getContractInterfaceLifecycle,
 		getContractInterfaceNameVersion,
+		getContractInterfaceSelector,
 		getContractInterfaces,
+		getContractListenersIdle, // must precede getContractListenerByNameOrID
 		getContractListenerByNameOrID,
 		getContractListeners,
 		getData,
@@ -79,7 +113,9 @@ var routes = append(
 		getDataValue,
 		getDataByID,
 		getDataMsgs,
+		getDataUpload,
 		getDatatypeByName,
+		getDatatypeDiff,
 		getDatatypes,
 		getEventByID,
 		getEvents,
@@ -89,10 +125,15 @@ var routes = append(
 		getIdentityByDID,
 		getIdentityByID,
 		getIdentityDID,
+		getIdentityVerifierHistory,
 		getIdentityVerifiers,
+		getMsgsExport,    // must precede getMsgByID
+		getMsgsScheduled, // must precede getMsgByID
 		getMsgByID,
 		getMsgData,
+		getMsgAcks,
 		getMsgEvents,
+		getMsgProof,
 		getMsgs,
 		getMsgTxn,
 		getNetworkDIDDocByDID,
@@ -104,14 +145,24 @@ var routes = append(
 		getNetworkOrgs,
 		getNextPins,
 		getOpByID,
+		getOpProgress,
+		getOpRetries,
 		getOps,
 		getPins,
 		getStatus,
+		getStatusAggregator,
+		getStatusErrors,
 		getStatusMultiparty,
+		getStatusPlugins,
+		getStatusReady,
 		getStatusBatchManager,
+		getStatusBatchManagerConfig,
+		getStatusRetention,
 		getSubscriptionByID,
+		getSubscriptionDeadLetters,
 		getSubscriptions,
 		getSubscriptionEventsFiltered,
+		getToken,
 		getTokenAccountPools,
 		getTokenAccounts,
 		getTokenApprovals,
@@ -128,8 +179,14 @@ var routes = append(
 		getTxnStatus,
 		getVerifierByID,
 		getVerifiers,
+		patchContractAPIListener,
+		patchContractInterfaceLifecycle,
+		patchDataUpload,
+		patchDatatypeLifecycle,
+		patchStatusBatchManagerConfig,
 		patchUpdateIdentity,
 		postBatchCancel,
+		postMsgCancel,
 		postContractAPIInvoke,
 		postContractAPIPublish,
 		postContractAPIQuery,
@@ -141,29 +198,54 @@ var routes = append(
 		postContractInvoke,
 		postContractQuery,
 		postData,
+		postDataAsync,
+		postDataUpload,
+		postDataUploadComplete,
 		postDataBlobPublish,
 		postDataValuePublish,
+		postGraphQL,
+		postGroupMembers,
+		postIdentityVerifiers,
 		postNetworkAction,
+		postNetworkDiagnostics,
+		postNetworkResync,
+		postNewBridge,
 		postNewContractAPI,
 		postNewContractInterface,
 		postNewContractListener,
 		postNewDatatype,
+		postNewGroup,
 		postNewIdentity,
 		postNewMessageBroadcast,
+		postNewMessageBroadcastBatch,
+		postPreviewBroadcastBatch,
 		postNewMessagePrivate,
+		postNewMessagePrivateBatch,
 		postNewMessageRequestReply,
 		postNewSubscription,
 		postNewOrganization,
 		postNewOrganizationSelf,
 		postNodesSelf,
+		postOpNotify,
 		postOpRetry,
+		postOpsRetry,
 		postPinsRewind,
+		postPinsRewindBatches,
+		postPinsRewindFromSequence,
+		postResolveIdentityDIDDocs,
+		postStatusBatchManagerFlush,
+		postStatusBatchManagerRestart,
+		postSubscriptionDeadLetterReplay,
+		postSubscriptionEventStreamAck,
+		postSubscriptionPreview,
+		postSubscriptionReplay,
 		postTokenApproval,
 		postTokenBurn,
 		postTokenMint,
 		postTokenPool,
 		postTokenPoolPublish,
 		postTokenTransfer,
+		postVerifyIdentityDIDDoc,
 		putContractAPI,
 		putSubscription,
 		postVerifiersResolve,
@@ -173,6 +255,7 @@ var routes = append(
 func globalRoutes(routes []*ffapi.Route) []*ffapi.Route {
 	for _, route := range routes {
 		route.Tag = routeTagGlobal
+		withPageCursorParam(route)
 	}
 	return routes
 }
@@ -181,6 +264,7 @@ func namespacedRoutes(routes []*ffapi.Route) []*ffapi.Route {
 	newRoutes := make([]*ffapi.Route, len(routes))
 	for i, route := range routes {
 		route.Tag = routeTagDefaultNamespace
+		withPageCursorParam(route)
 
 		routeCopy1 := *route
 		routeCopy1.Name += "Namespace"
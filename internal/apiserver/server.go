@@ -19,6 +19,7 @@ package apiserver
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -34,10 +35,13 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
+	"github.com/hyperledger/firefly/internal/events/sse"
 	"github.com/hyperledger/firefly/internal/events/websockets"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/namespace"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/internal/tracing"
+	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -62,6 +66,9 @@ type apiServer struct {
 	apiPublicURL           string
 	dynamicPublicURLHeader string
 	defaultNamespace       string
+	rateLimiters           map[*rateLimitGroup]*routeRateLimiter
+	namespaceLimiter       *namespaceRateLimiter
+	metrics                metrics.Manager
 }
 
 func InitConfig() {
@@ -70,6 +77,7 @@ func InitConfig() {
 	httpserver.InitHTTPConfig(metricsConfig, 6000)
 	httpserver.InitCORSConfig(corsConfig)
 	initMetricsConfig(metricsConfig)
+	initRateLimitConfig()
 }
 
 func NewAPIServer() Server {
@@ -80,6 +88,8 @@ func NewAPIServer() Server {
 		defaultNamespace:       config.GetString(coreconfig.NamespacesDefault),
 		metricsEnabled:         config.GetBool(coreconfig.MetricsEnabled),
 		ffiSwaggerGen:          &ffiSwaggerGen{},
+		rateLimiters:           make(map[*rateLimitGroup]*routeRateLimiter),
+		metrics:                metrics.NewMetricsManager(context.Background()),
 	}
 	as.apiPublicURL = as.getPublicURL(apiConfig, "")
 	return as
@@ -202,39 +212,152 @@ func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manage
 	// We extend the base ffapi functionality, with standardized DB filter support for all core resources.
 	// We also pass the Orchestrator context through
 	ce := route.Extensions.(*coreExtensions)
-	route.JSONHandler = func(r *ffapi.APIRequest) (output interface{}, err error) {
-		or, err := getOrchestrator(r.Req.Context(), mgr, route.Tag, r)
-		if err != nil {
-			return nil, err
+	limiter := as.rateLimiter(ce.RateLimit)
+	var namespaceLimiter *namespaceRateLimiter
+	if fixedBaseURL == "" {
+		// Namespace limits protect tenants from each other on the API server, and do not apply to the SPI
+		namespaceLimiter = as.namespaceLimiter
+	}
+	routeName := rateLimitRouteName(route)
+	checkRateLimits := func(r *ffapi.APIRequest, identity string) error {
+		ns := requestNamespace(route.Tag, r.Req)
+		var scope string
+		var err error
+		if limiter != nil {
+			scope, err = limiter.group.name, limiter.check(r, identity)
 		}
-
-		// Authorize the request
+		if err == nil && namespaceLimiter != nil && ns != "" {
+			scope, err = namespaceLimiter.check(r, ns, routeName)
+		}
+		if err != nil && as.metricsEnabled {
+			as.metrics.APIRateLimited(ns, routeName, scope)
+		}
+		return err
+	}
+	authorize := func(r *ffapi.APIRequest, or orchestrator.Orchestrator) (identity string, err error) {
+		// Authorize the request, for the role the route requires, and return the identity of the caller if known
 		authReq := &fftypes.AuthReq{
 			Method: r.Req.Method,
 			URL:    r.Req.URL,
 			Header: r.Req.Header,
 		}
 		if or != nil {
-			if err := or.Authorize(r.Req.Context(), authReq); err != nil {
-				return nil, err
-			}
+			ctx, caller := core.WithAuthenticatedCaller(core.WithRequiredAPIRole(r.Req.Context(), ce.Permission))
+			err = or.Authorize(ctx, authReq)
+			identity = caller.Identity
+		}
+		return identity, err
+	}
+	newCoreRequest := func(r *ffapi.APIRequest) (*coreRequest, error) {
+		or, err := getOrchestrator(r.Req.Context(), mgr, route.Tag, r)
+		if err != nil {
+			return nil, err
+		}
+		identity, err := authorize(r, or)
+		if err != nil {
+			return nil, err
 		}
 
 		if ce.EnabledIf != nil && !ce.EnabledIf(or) {
 			return nil, i18n.NewError(r.Req.Context(), coremsgs.MsgActionNotSupported)
 		}
 
+		// A passive node serves the routes that only need the reader role, including read-only POST routes such as
+		// queries, but all others must go to the active node (the SPI remains available)
+		if fixedBaseURL == "" && ce.Permission != core.APIRoleReader && !mgr.IsActive() {
+			return nil, i18n.NewError(r.Req.Context(), coremsgs.MsgHANodePassive)
+		}
+
+		if err := checkRateLimits(r, identity); err != nil {
+			return nil, err
+		}
+
+		if r.Filter != nil {
+			if err := validateSortFields(r.Req.Context(), route, r.Req); err != nil {
+				return nil, err
+			}
+		}
+
 		apiBaseURL := fixedBaseURL // for SPI
 		if apiBaseURL == "" {
 			apiBaseURL = as.getBaseURL(r.Req)
 		}
-		cr := &coreRequest{
+		return &coreRequest{
 			mgr:        mgr,
 			or:         or,
 			ctx:        r.Req.Context(),
 			apiBaseURL: apiBaseURL,
+		}, nil
+	}
+	route.JSONHandler = func(r *ffapi.APIRequest) (output interface{}, err error) {
+		cr, err := newCoreRequest(r)
+		if err != nil {
+			return nil, err
+		}
+		if ce.CoreVersionToken != nil {
+			output, err = ce.CoreJSONHandler(r, cr)
+			if err == nil && applyETag(r, ce, output) {
+				return http.NoBody, nil
+			}
+			return output, err
+		}
+		if !supportsPageCursor(route) || r.Filter == nil {
+			return ce.CoreJSONHandler(r, cr)
+		}
+		sortField, tiebreaker, limit, err := applyPageCursor(cr.ctx, r, route.FilterFactory)
+		if err != nil {
+			return nil, err
+		}
+		wrapListResult := wantsListResult(r)
+		if wrapListResult {
+			applyListResultLimit(r, limit)
+		}
+		output, err = ce.CoreJSONHandler(r, cr)
+		if err == nil {
+			if wrapListResult {
+				output = newListResult(output, limit)
+			}
+			setNextPageCursor(cr.ctx, r, sortField, tiebreaker, limit, output)
+		}
+		return output, err
+	}
+	if ce.CoreJSONStreamHandler != nil {
+		route.StreamHandler = func(r *ffapi.APIRequest) (output io.ReadCloser, err error) {
+			cr, err := newCoreRequest(r)
+			if err != nil {
+				return nil, err
+			}
+			if r.Filter != nil {
+				// The output is a plain array, with no place for a total
+				fi, err := r.Filter.Finalize()
+				if err != nil {
+					return nil, err
+				}
+				if fi.Count {
+					return nil, i18n.NewError(cr.ctx, coremsgs.MsgStreamedListNoCount)
+				}
+			}
+			iterate, err := ce.CoreJSONStreamHandler(r, cr)
+			if err != nil {
+				return nil, err
+			}
+			r.ResponseHeaders.Set("Content-Type", "application/json")
+			return newJSONArrayStream(cr.ctx, iterate), nil
+		}
+	}
+	if ce.CoreExportHandler != nil {
+		route.StreamHandler = func(r *ffapi.APIRequest) (output io.ReadCloser, err error) {
+			cr, err := newCoreRequest(r)
+			if err != nil {
+				return nil, err
+			}
+			format, err := getExportFormat(r, ce)
+			if err != nil {
+				return nil, err
+			}
+			r.ResponseHeaders.Set("Content-Type", exportContentTypes[format])
+			return newExportStream(cr.ctx, format, ce.CoreExportCSV, ce.CoreExportHandler(r, cr)), nil
 		}
-		return ce.CoreJSONHandler(r, cr)
 	}
 	if ce.CoreFormUploadHandler != nil {
 		route.FormUploadHandler = func(r *ffapi.APIRequest) (output interface{}, err error) {
@@ -242,6 +365,9 @@ func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manage
 			if err != nil {
 				return nil, err
 			}
+			if _, err := authorize(r, or); err != nil {
+				return nil, err
+			}
 			if ce.EnabledIf != nil && !ce.EnabledIf(or) {
 				return nil, i18n.NewError(r.Req.Context(), coremsgs.MsgActionNotSupported)
 			}
@@ -290,7 +416,7 @@ func (as *apiServer) nsOpenAPIHandlerFactory(req *http.Request, publicURL string
 
 func (as *apiServer) namespacedSwaggerHandler(hf *ffapi.HandlerFactory, r *mux.Router, publicURL, relativePath string, format ffapi.OpenAPIFormat) {
 	r.HandleFunc(`/api/v1/namespaces/{ns}`+relativePath, hf.APIWrapper(func(res http.ResponseWriter, req *http.Request) (status int, err error) {
-		return as.nsOpenAPIHandlerFactory(req, publicURL).OpenAPIHandler("", ffapi.OpenAPIFormatJSON, nsRoutes)(res, req)
+		return openAPIHandler(as.nsOpenAPIHandlerFactory(req, publicURL), "", ffapi.OpenAPIFormatJSON, nsRoutes)(res, req)
 	}))
 }
 
@@ -322,11 +448,11 @@ func (as *apiServer) namespacedContractSwaggerGenerator(hf *ffapi.HandlerFactory
 		}
 
 		options, routes := as.ffiSwaggerGen.Build(req.Context(), api, ffi)
-		return (&ffapi.OpenAPIHandlerFactory{
+		return openAPIHandler(&ffapi.OpenAPIHandlerFactory{
 			BaseSwaggerGenOptions:  *options,
 			StaticPublicURL:        apiBaseURL,
 			DynamicPublicURLHeader: as.dynamicPublicURLHeader,
-		}).OpenAPIHandler(fmt.Sprintf("/apis/%s", vars["apiName"]), format, routes)(res, req)
+		}, fmt.Sprintf("/apis/%s", vars["apiName"]), format, routes)(res, req)
 	}))
 }
 
@@ -344,14 +470,16 @@ func (as *apiServer) namespacedContractSwaggerUI(hf *ffapi.HandlerFactory, r *mu
 func (as *apiServer) createMuxRouter(ctx context.Context, mgr namespace.Manager) *mux.Router {
 	r := mux.NewRouter()
 	hf := as.handlerFactory()
+	as.namespaceLimiter = newNamespaceRateLimiter()
 
 	if as.metricsEnabled {
-		r.Use(metrics.GetRestServerInstrumentation().Middleware)
+		r.Use(withoutEventStreams(metrics.GetRestServerInstrumentation().Middleware))
 	}
+	r.Use(withoutEventStreams(tracing.Middleware))
 
 	for _, route := range routes {
 		if ce, ok := route.Extensions.(*coreExtensions); ok {
-			if ce.CoreJSONHandler != nil {
+			if ce.CoreJSONHandler != nil || ce.CoreJSONStreamHandler != nil || ce.CoreExportHandler != nil {
 				r.HandleFunc(fmt.Sprintf("/api/v1/%s", route.Path), as.routeHandler(hf, mgr, "", route)).
 					Methods(route.Method)
 			}
@@ -366,10 +494,10 @@ func (as *apiServer) createMuxRouter(ctx context.Context, mgr namespace.Manager)
 	}
 
 	// Root APIs
-	r.HandleFunc(`/api/swagger.json`, hf.APIWrapper(oaf.OpenAPIHandler(`/api/v1`, ffapi.OpenAPIFormatJSON, routes)))
-	r.HandleFunc(`/api/openapi.json`, hf.APIWrapper(oaf.OpenAPIHandler(`/api/v1`, ffapi.OpenAPIFormatJSON, routes)))
-	r.HandleFunc(`/api/swagger.yaml`, hf.APIWrapper(oaf.OpenAPIHandler(`/api/v1`, ffapi.OpenAPIFormatYAML, routes)))
-	r.HandleFunc(`/api/openapi.yaml`, hf.APIWrapper(oaf.OpenAPIHandler(`/api/v1`, ffapi.OpenAPIFormatYAML, routes)))
+	r.HandleFunc(`/api/swagger.json`, hf.APIWrapper(openAPIHandler(oaf, `/api/v1`, ffapi.OpenAPIFormatJSON, routes)))
+	r.HandleFunc(`/api/openapi.json`, hf.APIWrapper(openAPIHandler(oaf, `/api/v1`, ffapi.OpenAPIFormatJSON, routes)))
+	r.HandleFunc(`/api/swagger.yaml`, hf.APIWrapper(openAPIHandler(oaf, `/api/v1`, ffapi.OpenAPIFormatYAML, routes)))
+	r.HandleFunc(`/api/openapi.yaml`, hf.APIWrapper(openAPIHandler(oaf, `/api/v1`, ffapi.OpenAPIFormatYAML, routes)))
 	r.HandleFunc(`/api`, hf.APIWrapper(oaf.SwaggerUIHandler(`/api/openapi.yaml`)))
 	// Namespace relative APIs
 	as.namespacedSwaggerHandler(hf, r, as.apiPublicURL, `/api/swagger.json`, ffapi.OpenAPIFormatJSON)
@@ -392,6 +520,13 @@ func (as *apiServer) createMuxRouter(ctx context.Context, mgr namespace.Manager)
 
 	// namespace scoped web sockets
 	r.HandleFunc("/api/v1/namespaces/{ns}/ws", hf.APIWrapper(getNamespacedWebSocketHandler(ws.(*websockets.WebSockets), mgr)))
+	r.HandleFunc("/api/v1/namespaces/{ns}/status/batchmanager/ws", hf.APIWrapper(getBatchStatusWebSocketHandler(ctx, mgr)))
+
+	// server-sent events stream of a subscription
+	sseEvents, _ := eifactory.GetPlugin(ctx, "sse")
+	r.HandleFunc("/api/v1/namespaces/{ns}/subscriptions/{subid}/events/stream", getSubscriptionEventStreamHandler(hf, sseEvents.(*sse.SSE), mgr)).
+		Methods(http.MethodGet).
+		Name(subscriptionEventStreamRoute)
 
 	uiPath := config.GetString(coreconfig.UIPath)
 	if uiPath != "" && config.GetBool(coreconfig.UIEnabled) {
@@ -455,10 +590,10 @@ func (as *apiServer) createAdminMuxRouter(mgr namespace.Manager) *mux.Router {
 		StaticPublicURL:        publicURL,
 		DynamicPublicURLHeader: as.dynamicPublicURLHeader,
 	}
-	r.HandleFunc(`/spi/swagger.json`, hf.APIWrapper(oaf.OpenAPIHandler(`/spi/v1`, ffapi.OpenAPIFormatJSON, spiRoutes)))
-	r.HandleFunc(`/spi/openapi.json`, hf.APIWrapper(oaf.OpenAPIHandler(`/spi/v1`, ffapi.OpenAPIFormatJSON, spiRoutes)))
-	r.HandleFunc(`/spi/swagger.yaml`, hf.APIWrapper(oaf.OpenAPIHandler(`/spi/v1`, ffapi.OpenAPIFormatYAML, spiRoutes)))
-	r.HandleFunc(`/spi/openapi.yaml`, hf.APIWrapper(oaf.OpenAPIHandler(`/spi/v1`, ffapi.OpenAPIFormatYAML, spiRoutes)))
+	r.HandleFunc(`/spi/swagger.json`, hf.APIWrapper(openAPIHandler(oaf, `/spi/v1`, ffapi.OpenAPIFormatJSON, spiRoutes)))
+	r.HandleFunc(`/spi/openapi.json`, hf.APIWrapper(openAPIHandler(oaf, `/spi/v1`, ffapi.OpenAPIFormatJSON, spiRoutes)))
+	r.HandleFunc(`/spi/swagger.yaml`, hf.APIWrapper(openAPIHandler(oaf, `/spi/v1`, ffapi.OpenAPIFormatYAML, spiRoutes)))
+	r.HandleFunc(`/spi/openapi.yaml`, hf.APIWrapper(openAPIHandler(oaf, `/spi/v1`, ffapi.OpenAPIFormatYAML, spiRoutes)))
 	r.HandleFunc(`/spi`, hf.APIWrapper(oaf.SwaggerUIHandler(`/spi/openapi.yaml`)))
 
 	r.HandleFunc(`/favicon{any:.*}.png`, favIcons)
@@ -483,3 +618,18 @@ func syncRetcode(isSync bool) int {
 	}
 	return http.StatusAccepted
 }
+
+// confirmTimeout applies any confirmTimeout query parameter to the request context, bounding the wait
+// for confirmation within the overall request timeout. Supplying a timeout implies confirm=true.
+func confirmTimeout(r *ffapi.APIRequest, cr *coreRequest, waitConfirm bool) (context.Context, context.CancelFunc, bool, error) {
+	timeoutStr := r.QP["confirmTimeout"]
+	if timeoutStr == "" {
+		return cr.ctx, func() {}, waitConfirm, nil
+	}
+	timeout, err := fftypes.ParseDurationString(timeoutStr, time.Millisecond)
+	if err != nil || timeout <= 0 {
+		return nil, nil, false, i18n.NewError(cr.ctx, coremsgs.MsgInvalidConfirmTimeout, timeoutStr)
+	}
+	ctx, cancel := context.WithTimeout(cr.ctx, time.Duration(timeout))
+	return ctx, cancel, true, nil
+}
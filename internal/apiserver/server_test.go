@@ -60,6 +60,7 @@ func newTestServer() (*namespacemocks.Manager, *orchestratormocks.Orchestrator,
 	mgr.On("Orchestrator", mock.Anything, "default", false).Return(o, nil).Maybe()
 	mgr.On("Orchestrator", mock.Anything, "mynamespace", false).Return(o, nil).Maybe()
 	mgr.On("Orchestrator", mock.Anything, "ns1", false).Return(o, nil).Maybe()
+	mgr.On("IsActive").Return(true).Maybe()
 	config.Set(coreconfig.APIMaxFilterLimit, 100)
 	as := NewAPIServer().(*apiServer)
 	return mgr, o, as
@@ -224,6 +225,69 @@ func TestUnauthorized(t *testing.T) {
 	assert.Regexp(t, "FF00169", resJSON["error"])
 }
 
+func TestForbiddenRole(t *testing.T) {
+	mgr, o, as := newTestServer()
+	o.On("Authorize", mock.MatchedBy(func(ctx context.Context) bool {
+		return core.RequiredAPIRole(ctx) == core.APIRoleAdmin
+	}), mock.Anything).Return(i18n.NewError(context.Background(), coremsgs.MsgAPIRoleForbidden, core.APIRoleAdmin))
+	handler := as.routeHandler(as.handlerFactory(), mgr, "", postTokenPool)
+
+	req := httptest.NewRequest("POST", "http://localhost:12345/test", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 403, res.Result().StatusCode)
+	var resJSON map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resJSON)
+	assert.Regexp(t, "FF10567", resJSON["error"])
+}
+
+func TestPassiveNodeRejectsWrite(t *testing.T) {
+	_, o, as := newTestServer()
+	mgr := &namespacemocks.Manager{}
+	mgr.On("Orchestrator", mock.Anything, "default", false).Return(o, nil)
+	mgr.On("IsActive").Return(false)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	handler := as.routeHandler(as.handlerFactory(), mgr, "", postTokenPool)
+
+	req := httptest.NewRequest("POST", "http://localhost:12345/test", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 503, res.Result().StatusCode)
+	var resJSON map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resJSON)
+	assert.Regexp(t, "FF10638", resJSON["error"])
+	mgr.AssertExpectations(t)
+}
+
+func TestPassiveNodeServesReaderPost(t *testing.T) {
+	_, o, as := newTestServer()
+	mgr := &namespacemocks.Manager{}
+	mgr.On("Orchestrator", mock.Anything, "default", false).Return(o, nil)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	mcm.On("InvokeContract", mock.Anything, mock.Anything, true).Return(map[string]interface{}{"result": "ok"}, nil)
+	handler := as.routeHandler(as.handlerFactory(), mgr, "", postContractQuery)
+
+	req := httptest.NewRequest("POST", "http://localhost:12345/test", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	mgr.AssertNotCalled(t, "IsActive")
+	mgr.AssertExpectations(t)
+}
+
+func TestRoutesDeclarePermission(t *testing.T) {
+	for _, route := range append(routes, spiRoutes...) {
+		ce := route.Extensions.(*coreExtensions)
+		_, err := core.ParseAPIRole(context.Background(), string(ce.Permission))
+		assert.NoError(t, err, route.Name)
+	}
+}
+
 func TestSwaggerJSON(t *testing.T) {
 	o, r := newTestAPIServer()
 	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
@@ -469,6 +533,30 @@ func TestFormDataBadNamespace(t *testing.T) {
 	assert.Equal(t, 404, res.Result().StatusCode)
 }
 
+func TestFormDataUnauthorized(t *testing.T) {
+	mgr, o, as := newTestServer()
+	o.On("Authorize", mock.MatchedBy(func(ctx context.Context) bool {
+		return core.RequiredAPIRole(ctx) == core.APIRoleSubmitter
+	}), mock.Anything).Return(i18n.NewError(context.Background(), i18n.MsgUnauthorized))
+	r := as.createMuxRouter(context.Background(), mgr)
+	s := httptest.NewServer(r)
+	defer s.Close()
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	writer, err := w.CreateFormFile("file", "filename.ext")
+	assert.NoError(t, err)
+	writer.Write([]byte(`some data`))
+	w.Close()
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/data", &b)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 401, res.Result().StatusCode)
+}
+
 func TestJSONDisabledRoute(t *testing.T) {
 	mgr, o, as := newTestServer()
 	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
@@ -0,0 +1,54 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// validateSortFields rejects a request to sort on a field the query factory of the route does not know,
+// as the filter builder would otherwise silently drop it from the ORDER BY. Sorts can be compound,
+// such as sort=created,-id where a "-" prefix sorts that field in descending order.
+func validateSortFields(ctx context.Context, route *ffapi.Route, req *http.Request) error {
+	fields := route.FilterFactory.NewFilter(ctx).Fields()
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field] = true
+	}
+	for queryName, values := range req.Form {
+		if !strings.EqualFold(queryName, "sort") {
+			continue
+		}
+		for _, value := range values {
+			for _, field := range strings.Split(value, ",") {
+				field = strings.TrimPrefix(strings.TrimSpace(field), "-")
+				if field != "" && !known[field] {
+					sort.Strings(fields)
+					return i18n.NewError(ctx, coremsgs.MsgInvalidSortField, field, strings.Join(fields, ","))
+				}
+			}
+		}
+	}
+	return nil
+}
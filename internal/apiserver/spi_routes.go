@@ -25,11 +25,14 @@ import (
 // The Service Provider Interface (SPI) allows external microservices (such as the FireFly Transaction Manager)
 // to act as augmented components to the core.
 var spiRoutes = append(globalRoutes([]*ffapi.Route{
+	spiGetConfigSchema,
 	spiGetNamespaceByName,
 	spiGetNamespaces,
 	spiGetOpByID,
 	spiPatchOpByID,
+	spiPostPluginReset,
 	spiPostReset,
+	spiPutConfigReload,
 }),
 	namespacedSPIRoutes([]*ffapi.Route{
 		spiGetOps,
@@ -0,0 +1,89 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/sse"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// subscriptionEventStreamRoute names the mux route of the server-sent events stream, so middleware can recognize it
+const subscriptionEventStreamRoute = "subscriptionEventStream"
+
+func getSubscriptionEventStreamHandler(hf *ffapi.HandlerFactory, s *sse.SSE, mgr namespace.Manager) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		// The stream lasts for as long as the client stays connected, so must not be bound
+		// by the request timeout that the API wrapper applies to the context
+		clientCtx := req.Context()
+		hf.APIWrapper(func(res http.ResponseWriter, req *http.Request) (status int, err error) {
+			vars := mux.Vars(req)
+			or, err := mgr.Orchestrator(req.Context(), vars["ns"], false)
+			if err != nil || or == nil {
+				return 404, i18n.NewError(req.Context(), coremsgs.Msg404NotFound)
+			}
+			authReq := &fftypes.AuthReq{
+				Method: req.Method,
+				URL:    req.URL,
+				Header: req.Header,
+			}
+			if err := or.Authorize(core.WithRequiredAPIRole(req.Context(), core.APIRoleReader), authReq); err != nil {
+				return 403, err
+			}
+			sub, err := or.GetSubscriptionByID(req.Context(), vars["subid"])
+			if err != nil {
+				return 500, err
+			}
+			if sub == nil {
+				return 404, i18n.NewError(req.Context(), coremsgs.Msg404NotFound)
+			}
+
+			// Keep the logging fields of the request, but take cancellation from the client connection
+			streamCtx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+			defer cancel()
+			stop := context.AfterFunc(clientCtx, cancel)
+			defer stop()
+			if err := s.ServeSubscription(streamCtx, sub, res, req); err != nil {
+				return 400, err
+			}
+			return 200, nil
+		})(res, req)
+	}
+}
+
+// withoutEventStreams skips the supplied middleware for the server-sent events stream. This is used for metrics,
+// where the instrumented response writer does not allow each event to be flushed to the client as it is written.
+func withoutEventStreams(mw mux.MiddlewareFunc) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if route := mux.CurrentRoute(req); route != nil && route.GetName() == subscriptionEventStreamRoute {
+				next.ServeHTTP(res, req)
+				return
+			}
+			wrapped.ServeHTTP(res, req)
+		})
+	}
+}
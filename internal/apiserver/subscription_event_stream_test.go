@@ -0,0 +1,148 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/events/sse"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestEventStreamServer(t *testing.T) (*orchestratormocks.Orchestrator, *eventsmocks.Callbacks, *httptest.Server) {
+	mgr, o, as := newTestServer()
+	mgr.On("Orchestrator", mock.Anything, "unknown", false).Return(nil, fmt.Errorf("pop")).Maybe()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s := &sse.SSE{}
+	sseConfig := config.RootSection("ut.sse")
+	s.InitConfig(sseConfig)
+	err := s.Init(ctx, sseConfig)
+	assert.NoError(t, err)
+	cbs := &eventsmocks.Callbacks{}
+	s.SetHandler("ns1", cbs)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/v1/namespaces/{ns}/subscriptions/{subid}/events/stream", getSubscriptionEventStreamHandler(as.handlerFactory(), s, mgr))
+	svr := httptest.NewServer(r)
+	t.Cleanup(svr.Close)
+	return o, cbs, svr
+}
+
+func eventStreamURL(svr *httptest.Server, ns, subID string) string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/subscriptions/%s/events/stream", svr.URL, ns, subID)
+}
+
+func TestSubscriptionEventStream(t *testing.T) {
+	o, cbs, svr := newTestEventStreamServer(t)
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Transport:       "sse",
+	}
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetSubscriptionByID", mock.Anything, sub.ID.String()).Return(sub, nil)
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	closed := make(chan struct{})
+	cbs.On("ConnectionClosed", mock.Anything).Run(func(args mock.Arguments) {
+		close(closed)
+	}).Return(nil)
+
+	res, err := http.Get(eventStreamURL(svr, "ns1", sub.ID.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "event: connected\n", line)
+
+	// Disconnecting the client ends the stream
+	res.Body.Close()
+	<-closed
+}
+
+func TestSubscriptionEventStreamErrors(t *testing.T) {
+	o, _, svr := newTestEventStreamServer(t)
+	subID := fftypes.NewUUID().String()
+	o.On("Authorize", mock.Anything, mock.MatchedBy(func(authReq *fftypes.AuthReq) bool {
+		return authReq.Header.Get("Authorization") == ""
+	})).Return(nil)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	o.On("GetSubscriptionByID", mock.Anything, "bad").Return(nil, fmt.Errorf("pop"))
+	o.On("GetSubscriptionByID", mock.Anything, "missing").Return(nil, nil)
+	o.On("GetSubscriptionByID", mock.Anything, subID).Return(&core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Transport:       "websockets",
+	}, nil)
+
+	for _, tc := range []struct {
+		ns, subID, auth string
+		status          int
+		err             string
+	}{
+		{ns: "unknown", subID: subID, status: 404, err: "FF10109"},
+		{ns: "ns1", subID: subID, auth: "Bearer bad", status: 403, err: "pop"},
+		{ns: "ns1", subID: "bad", status: 500, err: "pop"},
+		{ns: "ns1", subID: "missing", status: 404, err: "FF10109"},
+		{ns: "ns1", subID: subID, status: 400, err: "FF10523"},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, eventStreamURL(svr, tc.ns, tc.subID), nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.status, res.StatusCode)
+		body, _ := io.ReadAll(res.Body)
+		assert.Regexp(t, tc.err, string(body))
+		res.Body.Close()
+	}
+}
+
+func TestWithoutEventStreams(t *testing.T) {
+	wrapped := withoutEventStreams(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Set("X-Wrapped", "true")
+			next.ServeHTTP(res, req)
+		})
+	})
+	r := mux.NewRouter()
+	r.Use(wrapped)
+	noop := func(res http.ResponseWriter, req *http.Request) {}
+	r.HandleFunc("/stream", noop).Name(subscriptionEventStreamRoute)
+	r.HandleFunc("/other", noop)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Empty(t, res.Header().Get("X-Wrapped"))
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/other", nil))
+	assert.Equal(t, "true", res.Header().Get("X-Wrapped"))
+}
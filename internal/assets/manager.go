@@ -19,6 +19,7 @@ package assets
 import (
 	"context"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -35,6 +36,7 @@ import (
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
 	"github.com/hyperledger/firefly/pkg/tokens"
 )
 
@@ -51,6 +53,8 @@ type Manager interface {
 	DeleteTokenPool(ctx context.Context, poolNameOrID string) error
 
 	GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)
+	GetTokenBalancesWithMetadata(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)
+	GetToken(ctx context.Context, poolNameOrID, tokenIndex string) (*core.Token, error)
 	GetTokenAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAccount, *ffapi.FilterResult, error)
 	GetTokenAccountPools(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)
 
@@ -61,6 +65,7 @@ type Manager interface {
 	MintTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
 	BurnTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
 	TransferTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
+	SimulateTransferTokens(ctx context.Context, transfer *core.TokenTransferInput) (*core.Simulation, error)
 
 	GetTokenConnectors(ctx context.Context) []*core.TokenConnector
 
@@ -91,12 +96,29 @@ type assetManager struct {
 	contracts        contracts.Manager
 	cache            cache.CInterface
 	keyNormalization int
+	poolConnectors   map[string]string
+	delegatedCheck   string
+	uriResolvers     map[string]TokenURIResolver
+	metadataCache    cache.CInterface
+	metadataEnabled  bool
+	metadataMaxSize  int64
 }
 
-func NewAssetManager(ctx context.Context, ns, keyNormalization string, di database.Plugin, ti map[string]tokens.Plugin, im identity.Manager, sa syncasync.Bridge, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, om operations.Manager, cm contracts.Manager, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
+func NewAssetManager(ctx context.Context, ns, keyNormalization string, poolConnectors map[string]string, di database.Plugin, ti map[string]tokens.Plugin, im identity.Manager, sa syncasync.Bridge, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, om operations.Manager, cm contracts.Manager, ss sharedstorage.Plugin, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
 	if di == nil || im == nil || sa == nil || ti == nil || mm == nil || om == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "AssetManager")
 	}
+	for _, connector := range poolConnectors {
+		if _, ok := ti[connector]; !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownTokensPlugin, connector)
+		}
+	}
+	delegatedCheck := config.GetString(coreconfig.AssetManagerDelegatedTransferValidation)
+	switch delegatedCheck {
+	case delegatedCheckConnector, delegatedCheckRecorded, delegatedCheckNone:
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidDelegatedTransferValidation, delegatedCheck)
+	}
 	var err error
 	am := &assetManager{
 		ctx:              ctx,
@@ -112,6 +134,13 @@ func NewAssetManager(ctx context.Context, ns, keyNormalization string, di databa
 		metrics:          mm,
 		operations:       om,
 		contracts:        cm,
+		poolConnectors:   poolConnectors,
+		delegatedCheck:   delegatedCheck,
+		metadataEnabled:  tokenMetadataConfig.GetBool(coreconfig.TokenMetadataEnabled),
+		metadataMaxSize:  tokenMetadataConfig.GetByteSize(coreconfig.TokenMetadataMaxSize),
+	}
+	if am.uriResolvers, err = newTokenURIResolvers(ctx, ss); err != nil {
+		return nil, err
 	}
 	if cacheManager != nil {
 		am.cache, err = cacheManager.GetCache(
@@ -125,6 +154,17 @@ func NewAssetManager(ctx context.Context, ns, keyNormalization string, di databa
 		if err != nil {
 			return nil, err
 		}
+		am.metadataCache, err = cacheManager.GetCache(
+			cache.NewCacheConfig(
+				ctx,
+				coreconfig.CacheTokenMetadataLimit,
+				coreconfig.CacheTokenMetadataTTL,
+				"",
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 	om.RegisterHandler(ctx, am, []core.OpType{
 		core.OpTypeTokenCreatePool,
@@ -148,6 +188,46 @@ func (am *assetManager) selectTokenPlugin(ctx context.Context, name string) (tok
 	return nil, i18n.NewError(ctx, coremsgs.MsgUnknownTokensPlugin, name)
 }
 
+// resolvePoolConnector returns the connector for a new pool. When pool bindings are configured for the
+// namespace, the pool must be bound, and any connector requested must match the binding.
+func (am *assetManager) resolvePoolConnector(ctx context.Context, poolName, connector string) (string, error) {
+	if len(am.poolConnectors) > 0 {
+		return am.boundPoolConnector(ctx, poolName, connector)
+	}
+	if connector == "" {
+		return am.getDefaultTokenConnector(ctx)
+	}
+	return connector, nil
+}
+
+func (am *assetManager) boundPoolConnector(ctx context.Context, poolName, connector string) (string, error) {
+	bound, ok := am.poolConnectors[poolName]
+	if !ok {
+		return "", i18n.NewError(ctx, coremsgs.MsgTokenPoolNoConnectorBinding, poolName)
+	}
+	if connector != "" && connector != bound {
+		return "", i18n.NewError(ctx, coremsgs.MsgTokenPoolConnectorMismatch, poolName, bound, connector)
+	}
+	return bound, nil
+}
+
+// validatePoolBinding rejects operations on pools that are not bound to their connector,
+// when pool bindings are configured for the namespace
+func (am *assetManager) validatePoolBinding(ctx context.Context, pool *core.TokenPool) error {
+	if len(am.poolConnectors) > 0 {
+		_, err := am.boundPoolConnector(ctx, pool.Name, pool.Connector)
+		return err
+	}
+	return nil
+}
+
+func (am *assetManager) selectPoolPlugin(ctx context.Context, pool *core.TokenPool) (tokens.Plugin, error) {
+	if err := am.validatePoolBinding(ctx, pool); err != nil {
+		return nil, err
+	}
+	return am.selectTokenPlugin(ctx, pool.Connector)
+}
+
 func (am *assetManager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
 	return am.database.GetTokenBalances(ctx, am.namespace, filter)
 }
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/txcommon"
@@ -34,6 +35,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
 	"github.com/hyperledger/firefly/mocks/tokenmocks"
 	"github.com/hyperledger/firefly/mocks/txcommonmocks"
@@ -54,6 +56,7 @@ func newTestAssetsWithMetrics(t *testing.T) (*assetManager, func()) {
 
 func newTestAssetsCommon(t *testing.T, metrics bool) (*assetManager, func()) {
 	coreconfig.Reset()
+	InitConfig()
 	mdi := &databasemocks.Plugin{}
 	mim := &identitymanagermocks.Manager{}
 	mdm := &datamocks.Manager{}
@@ -67,13 +70,14 @@ func newTestAssetsCommon(t *testing.T, metrics bool) (*assetManager, func()) {
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	mom := &operationmocks.Manager{}
 	mcm := &contractmocks.Manager{}
+	mss := &sharedstoragemocks.Plugin{}
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
 	mm.On("IsMetricsEnabled").Return(metrics)
 	mm.On("TransferSubmitted", mock.Anything)
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
 	mti.On("Name").Return("ut").Maybe()
 	ctx, cancel := context.WithCancel(ctx)
-	a, err := NewAssetManager(ctx, "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	a, err := NewAssetManager(ctx, "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, mss, txHelper, cmi)
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything).Maybe()
 	rag.RunFn = func(a mock.Arguments) {
 		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
@@ -85,13 +89,14 @@ func newTestAssetsCommon(t *testing.T, metrics bool) (*assetManager, func()) {
 }
 
 func TestInitFail(t *testing.T) {
-	_, err := NewAssetManager(context.Background(), "", "", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewAssetManager(context.Background(), "", "", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
 func TestCacheInitFail(t *testing.T) {
 	cacheInitError := errors.New("Initialization error.")
 	coreconfig.Reset()
+	InitConfig()
 	mdi := &databasemocks.Plugin{}
 	mdm := &datamocks.Manager{}
 	mim := &identitymanagermocks.Manager{}
@@ -102,15 +107,68 @@ func TestCacheInitFail(t *testing.T) {
 	mm := &metricsmocks.Manager{}
 	mom := &operationmocks.Manager{}
 	mcm := &contractmocks.Manager{}
+	mss := &sharedstoragemocks.Plugin{}
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
 	txHelper, _ := txcommon.NewTransactionHelper(context.Background(), "ns1", mdi, mdm, cmi)
 
-	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, mss, txHelper, cmi)
 
 	assert.Equal(t, cacheInitError, err)
 }
 
+func TestMetadataCacheInitFail(t *testing.T) {
+	cacheInitError := errors.New("Initialization error.")
+	coreconfig.Reset()
+	InitConfig()
+	mdi := &databasemocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	msa := &syncasyncmocks.Bridge{}
+	mti := &tokenmocks.Plugin{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute), nil).Once()
+	cmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
+
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, nil, nil, mm, mom, nil, nil, nil, cmi)
+
+	assert.Equal(t, cacheInitError, err)
+}
+
+func TestTokenURIResolversInitFail(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	tokenMetadataConfig.Set("tls.enabled", true)
+	tokenMetadataConfig.Set("tls.caFile", "!!!badness")
+	mdi := &databasemocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	msa := &syncasyncmocks.Bridge{}
+	mti := &tokenmocks.Plugin{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, nil, nil, mm, mom, nil, nil, nil, nil)
+
+	assert.Error(t, err)
+}
+
+func TestDelegatedTransferValidationInitFail(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	config.Set(coreconfig.AssetManagerDelegatedTransferValidation, "wrong")
+	mdi := &databasemocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	msa := &syncasyncmocks.Bridge{}
+	mti := &tokenmocks.Plugin{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, nil, nil, mm, mom, nil, nil, nil, nil)
+
+	assert.Regexp(t, "FF10659.*wrong", err)
+}
+
 func TestName(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -164,6 +222,7 @@ func TestGetTokenConnectors(t *testing.T) {
 
 func TestStart(t *testing.T) {
 	coreconfig.Reset()
+	InitConfig()
 	mdi := &databasemocks.Plugin{}
 	mdm := &datamocks.Manager{}
 	mim := &identitymanagermocks.Manager{}
@@ -174,6 +233,7 @@ func TestStart(t *testing.T) {
 	mm := &metricsmocks.Manager{}
 	mom := &operationmocks.Manager{}
 	mcm := &contractmocks.Manager{}
+	mss := &sharedstoragemocks.Plugin{}
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(nil, nil)
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
@@ -186,7 +246,7 @@ func TestStart(t *testing.T) {
 	mti.On("StartNamespace", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mti.On("ConnectorName").Return("hot_tokens")
 	txHelper, _ := txcommon.NewTransactionHelper(context.Background(), "ns1", mdi, mdm, cmi)
-	am, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	am, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, mss, txHelper, cmi)
 	assert.NoError(t, err)
 	err = am.Start()
 	assert.NoError(t, err)
@@ -194,6 +254,7 @@ func TestStart(t *testing.T) {
 
 func TestStartDBError(t *testing.T) {
 	coreconfig.Reset()
+	InitConfig()
 	mdi := &databasemocks.Plugin{}
 	mdm := &datamocks.Manager{}
 	mim := &identitymanagermocks.Manager{}
@@ -204,12 +265,13 @@ func TestStartDBError(t *testing.T) {
 	mm := &metricsmocks.Manager{}
 	mom := &operationmocks.Manager{}
 	mcm := &contractmocks.Manager{}
+	mss := &sharedstoragemocks.Plugin{}
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(nil, nil)
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
 	mdi.On("GetTokenPools", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 	txHelper, _ := txcommon.NewTransactionHelper(context.Background(), "ns1", mdi, mdm, cmi)
-	am, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	am, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, mss, txHelper, cmi)
 	assert.NoError(t, err)
 	err = am.Start()
 	assert.Regexp(t, "pop", err)
@@ -217,6 +279,7 @@ func TestStartDBError(t *testing.T) {
 
 func TestStartError(t *testing.T) {
 	coreconfig.Reset()
+	InitConfig()
 	mdi := &databasemocks.Plugin{}
 	mdm := &datamocks.Manager{}
 	mim := &identitymanagermocks.Manager{}
@@ -227,6 +290,7 @@ func TestStartError(t *testing.T) {
 	mm := &metricsmocks.Manager{}
 	mom := &operationmocks.Manager{}
 	mcm := &contractmocks.Manager{}
+	mss := &sharedstoragemocks.Plugin{}
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(nil, nil)
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
@@ -239,8 +303,61 @@ func TestStartError(t *testing.T) {
 	mti.On("StartNamespace", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	mti.On("ConnectorName").Return("hot_tokens")
 	txHelper, _ := txcommon.NewTransactionHelper(context.Background(), "ns1", mdi, mdm, cmi)
-	am, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	am, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", nil, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, mss, txHelper, cmi)
 	assert.NoError(t, err)
 	err = am.Start()
 	assert.Regexp(t, "pop", err)
 }
+
+func TestInitUnknownPoolConnector(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	mdi := &databasemocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	msa := &syncasyncmocks.Bridge{}
+	mti := &tokenmocks.Plugin{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", map[string]string{"pool1": "wrong"}, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, nil, nil, mm, mom, nil, nil, nil, nil)
+	assert.Regexp(t, "FF10272.*wrong", err)
+}
+
+func TestResolvePoolConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.tokens["other-tokens"] = &tokenmocks.Plugin{}
+	am.poolConnectors = map[string]string{"pool1": "magic-tokens"}
+
+	connector, err := am.resolvePoolConnector(context.Background(), "pool1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "magic-tokens", connector)
+
+	connector, err = am.resolvePoolConnector(context.Background(), "pool1", "magic-tokens")
+	assert.NoError(t, err)
+	assert.Equal(t, "magic-tokens", connector)
+
+	_, err = am.resolvePoolConnector(context.Background(), "pool1", "other-tokens")
+	assert.Regexp(t, "FF10484", err)
+
+	_, err = am.resolvePoolConnector(context.Background(), "pool2", "")
+	assert.Regexp(t, "FF10483", err)
+}
+
+func TestSelectPoolPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"pool1": "magic-tokens"}
+
+	plugin, err := am.selectPoolPlugin(context.Background(), &core.TokenPool{Name: "pool1", Connector: "magic-tokens"})
+	assert.NoError(t, err)
+	assert.Equal(t, am.tokens["magic-tokens"], plugin)
+
+	_, err = am.selectPoolPlugin(context.Background(), &core.TokenPool{Name: "pool2", Connector: "magic-tokens"})
+	assert.Regexp(t, "FF10483", err)
+
+	am.poolConnectors = nil
+	plugin, err = am.selectPoolPlugin(context.Background(), &core.TokenPool{Name: "pool2", Connector: "magic-tokens"})
+	assert.NoError(t, err)
+	assert.Equal(t, am.tokens["magic-tokens"], plugin)
+}
@@ -103,7 +103,7 @@ func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation
 func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
 	switch data := op.Data.(type) {
 	case createPoolData:
-		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		plugin, err := am.selectPoolPlugin(ctx, data.Pool)
 		if err != nil {
 			return nil, core.OpPhaseInitializing, err
 		}
@@ -119,7 +119,7 @@ func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOpera
 		return nil, phase, err
 
 	case transferData:
-		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		plugin, err := am.selectPoolPlugin(ctx, data.Pool)
 		if err != nil {
 			return nil, core.OpPhaseInitializing, err
 		}
@@ -136,7 +136,7 @@ func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOpera
 		return nil, operations.ErrTernary(err, core.OpPhaseInitializing, core.OpPhasePending), err
 
 	case approvalData:
-		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		plugin, err := am.selectPoolPlugin(ctx, data.Pool)
 		if err != nil {
 			return nil, core.OpPhaseInitializing, err
 		}
@@ -371,6 +371,23 @@ func TestRunOperationCreatePoolBadPlugin(t *testing.T) {
 	assert.Regexp(t, "FF10272", err)
 }
 
+func TestRunOperationCreatePoolUnbound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"otherpool": "magic-tokens"}
+
+	op := &core.Operation{}
+	pool := &core.TokenPool{
+		Name:      "pool1",
+		Connector: "magic-tokens",
+	}
+
+	_, phase, err := am.RunOperation(context.Background(), opCreatePool(op, pool))
+
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "FF10483", err)
+}
+
 func TestRunOperationCreatePool(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -407,6 +424,28 @@ func TestRunOperationActivatePoolBadPlugin(t *testing.T) {
 	assert.Regexp(t, "FF10272", err)
 }
 
+func TestRunOperationActivatePoolUnbound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"otherpool": "magic-tokens"}
+
+	op := &core.Operation{}
+	pool := &core.TokenPool{
+		Name:      "pool1",
+		Connector: "magic-tokens",
+	}
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("ActivateTokenPool", context.Background(), pool).Return(core.OpPhaseComplete, nil)
+
+	_, phase, err := am.RunOperation(context.Background(), opActivatePool(op, pool))
+
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.NoError(t, err)
+
+	mti.AssertExpectations(t)
+}
+
 func TestRunOperationTransferBadPlugin(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
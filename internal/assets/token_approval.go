@@ -129,6 +129,17 @@ func (s *approveSender) resolve(ctx context.Context) (opResubmitted bool, err er
 				s.approval.TX.ID = idemErr.ExistingTXID
 				s.approval.TX.Type = core.TransactionTypeTokenApproval
 				return true, nil
+			} else {
+				// Return the original request if it is within the idempotency window, rather than a 409 Conflict error
+				existing, txErr := s.mgr.txHelper.GetIdempotentTransaction(ctx, idemErr.ExistingTXID)
+				if txErr != nil {
+					return false, txErr
+				}
+				if existing != nil {
+					s.approval.TX.ID = existing.ID
+					s.approval.TX.Type = core.TransactionTypeTokenApproval
+					return true, nil
+				}
 			}
 		}
 		if !resubmitWholeTX {
@@ -223,6 +234,9 @@ func (am *assetManager) validateApproval(ctx context.Context, approval *core.Tok
 			return nil, err
 		}
 	}
+	if err = am.validatePoolBinding(ctx, pool); err != nil {
+		return nil, err
+	}
 	approval.TokenApproval.Pool = pool.ID
 	approval.TokenApproval.Connector = pool.Connector
 
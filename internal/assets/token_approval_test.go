@@ -337,6 +337,7 @@ func TestApprovalIdempotentNoOperationToResubmit(t *testing.T) {
 		ExistingTXID:  id,
 		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
 	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* one total */, nil /* none to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, nil)
 
 	// If ResubmitOperations returns nil it's because there was no operation in initialized state, so we expect the regular 409 error back
 	_, err := am.TokenApproval(context.Background(), approval, false)
@@ -347,6 +348,72 @@ func TestApprovalIdempotentNoOperationToResubmit(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestApprovalIdempotentWithinWindow(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	var id = fftypes.NewUUID()
+
+	approval := &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{
+			Approved: true,
+			Operator: "operator",
+			Key:      "key",
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	fb := database.TokenPoolQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	f.Limit(1).Count(true)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, core.IdempotencyKey("idem1")).Return(id, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* one total */, nil /* none to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(&core.Transaction{ID: id}, nil)
+
+	// The original request is within the idempotency window, so it is returned rather than a 409 Conflict error
+	result, err := am.TokenApproval(context.Background(), approval, false)
+	assert.NoError(t, err)
+	assert.Equal(t, id, result.TX.ID)
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestApprovalIdempotentWindowFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	var id = fftypes.NewUUID()
+
+	approval := &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{
+			Approved: true,
+			Operator: "operator",
+			Key:      "key",
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	fb := database.TokenPoolQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	f.Limit(1).Count(true)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, core.IdempotencyKey("idem1")).Return(id, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* one total */, nil /* none to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TokenApproval(context.Background(), approval, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestApprovalIdempotentOperationErrorOnResubmit(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -442,6 +509,37 @@ func TestApprovalBadPool(t *testing.T) {
 	mth.AssertExpectations(t)
 }
 
+func TestApprovalUnboundPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"pool2": "magic-tokens"}
+
+	approval := &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{
+			Approved: true,
+			Operator: "operator",
+			Key:      "key",
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&core.TokenPool{
+		Name:      "pool1",
+		Connector: "magic-tokens",
+		Active:    true,
+	}, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.TokenApproval(context.Background(), approval, false)
+	assert.Regexp(t, "FF10483", err)
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
 func TestApprovalUnconfirmedPool(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
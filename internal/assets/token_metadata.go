@@ -0,0 +1,271 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
+)
+
+var tokenMetadataConfig = config.RootSection("tokenMetadata")
+
+// tokenMetadataConcurrency is the number of token URIs resolved concurrently for a page of balances
+const tokenMetadataConcurrency = 10
+
+func InitConfig() {
+	ffresty.InitConfig(tokenMetadataConfig)
+	tokenMetadataConfig.AddKnownKey(ffresty.HTTPConfigRequestTimeout, "5s")
+	tokenMetadataConfig.AddKnownKey(coreconfig.TokenMetadataEnabled, true)
+	tokenMetadataConfig.AddKnownKey(coreconfig.TokenMetadataMaxSize, "1Mb")
+	tokenMetadataConfig.AddKnownKey(coreconfig.TokenMetadataAllowedHosts, []string{})
+}
+
+// TokenURIResolver reads the metadata document that a token URI refers to. A resolver is registered
+// for each URI scheme that can be resolved.
+type TokenURIResolver interface {
+	ResolveTokenURI(ctx context.Context, uri *url.URL) (io.ReadCloser, error)
+}
+
+// httpURIResolver fetches http(s) token URIs. Token URIs are set by whoever minted the token, so only the
+// configured hosts can be fetched, and redirects are not followed as they could lead to any other host.
+type httpURIResolver struct {
+	client       *resty.Client
+	allowedHosts map[string]bool
+}
+
+func (r *httpURIResolver) checkHost(ctx context.Context, uri *url.URL) error {
+	if uri.Scheme == "" {
+		// A relative URI is resolved against the configured base URL, so it must not name a host of its own
+		if uri.Host != "" {
+			return i18n.NewError(ctx, coremsgs.MsgTokenURIHostNotAllowed, uri)
+		}
+		return nil
+	}
+	if !r.allowedHosts["*"] && !r.allowedHosts[strings.ToLower(uri.Host)] && !r.allowedHosts[strings.ToLower(uri.Hostname())] {
+		return i18n.NewError(ctx, coremsgs.MsgTokenURIHostNotAllowed, uri)
+	}
+	return nil
+}
+
+func (r *httpURIResolver) ResolveTokenURI(ctx context.Context, uri *url.URL) (io.ReadCloser, error) {
+	if err := r.checkHost(ctx, uri); err != nil {
+		return nil, err
+	}
+	res, err := r.client.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(uri.String())
+	ffresty.OnAfterResponse(r.client, res) // required using SetDoNotParseResponse
+	if err != nil || !res.IsSuccess() {
+		if res != nil && res.RawBody() != nil {
+			_ = res.RawBody().Close()
+		}
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokenMetadataFetchFailed)
+	}
+	return res.RawBody(), nil
+}
+
+// ipfsURIResolver reads ipfs:// URIs through the shared storage plugin of the namespace, so that
+// they are fetched from the same IPFS gateway as other shared data
+type ipfsURIResolver struct {
+	sharedstorage sharedstorage.Plugin
+}
+
+func (r *ipfsURIResolver) ResolveTokenURI(ctx context.Context, uri *url.URL) (io.ReadCloser, error) {
+	// Some URIs are of the legacy form ipfs://ipfs/<cid>
+	ref := strings.TrimPrefix(uri.Host+uri.Path, "ipfs/")
+	return r.sharedstorage.DownloadData(ctx, ref)
+}
+
+func newTokenURIResolvers(ctx context.Context, ss sharedstorage.Plugin) (map[string]TokenURIResolver, error) {
+	restyConfig, err := ffresty.GenerateConfig(ctx, tokenMetadataConfig)
+	if err != nil {
+		return nil, err
+	}
+	// A failed fetch is cached and reported, rather than retried while the API request waits
+	restyConfig.Retry = false
+	httpResolver := &httpURIResolver{
+		client:       ffresty.NewWithConfig(ctx, *restyConfig),
+		allowedHosts: make(map[string]bool),
+	}
+	httpResolver.client.SetRedirectPolicy(resty.NoRedirectPolicy())
+	for _, host := range tokenMetadataConfig.GetStringSlice(coreconfig.TokenMetadataAllowedHosts) {
+		httpResolver.allowedHosts[strings.ToLower(host)] = true
+	}
+	resolvers := map[string]TokenURIResolver{
+		"":      httpResolver, // relative to the configured base URL
+		"http":  httpResolver,
+		"https": httpResolver,
+	}
+	if ss != nil {
+		resolvers["ipfs"] = &ipfsURIResolver{sharedstorage: ss}
+	}
+	return resolvers, nil
+}
+
+// expandTokenURI substitutes the index of a token into a URI that is shared by all the tokens in a pool.
+// As defined by ERC-1155, "{id}" is replaced with the index as 64 hex characters.
+func expandTokenURI(uri, tokenIndex string) string {
+	if !strings.Contains(uri, "{id}") {
+		return uri
+	}
+	index, ok := new(big.Int).SetString(tokenIndex, 10)
+	if !ok {
+		return uri
+	}
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", index))
+}
+
+// resolveTokenMetadata reads the metadata document of a token from its URI, and caches it. A failure is cached
+// too, so a URI that cannot be resolved is not fetched again on every request until the cache entry expires.
+func (am *assetManager) resolveTokenMetadata(ctx context.Context, uri string) (*core.TokenMetadata, error) {
+	if cached := am.metadataCache.Get(uri); cached != nil {
+		if err, ok := cached.(error); ok {
+			return nil, err
+		}
+		return cached.(*core.TokenMetadata), nil
+	}
+	metadata, err := am.fetchTokenMetadata(ctx, uri)
+	if err != nil {
+		if ctx.Err() == nil {
+			am.metadataCache.Set(uri, err)
+		}
+		return nil, err
+	}
+	log.L(ctx).Debugf("Resolved token metadata from %s", uri)
+	am.metadataCache.Set(uri, metadata)
+	return metadata, nil
+}
+
+func (am *assetManager) fetchTokenMetadata(ctx context.Context, uri string) (*core.TokenMetadata, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenURISchemeUnsupported, uri)
+	}
+	resolver, ok := am.uriResolvers[u.Scheme]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenURISchemeUnsupported, uri)
+	}
+	reader, err := resolver.ResolveTokenURI(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var doc fftypes.JSONObject
+	if err := json.NewDecoder(io.LimitReader(reader, am.metadataMaxSize)).Decode(&doc); err != nil || doc == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenMetadataInvalid, uri)
+	}
+	return &core.TokenMetadata{
+		Name:        doc.GetString("name"),
+		Description: doc.GetString("description"),
+		Image:       doc.GetString("image"),
+		Document:    doc,
+	}, nil
+}
+
+func (am *assetManager) GetTokenBalancesWithMetadata(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	balances, fr, err := am.GetTokenBalances(ctx, filter)
+	if err != nil || !am.metadataEnabled {
+		return balances, fr, err
+	}
+
+	// Each distinct URI on the page is resolved once, with a number of URIs resolved concurrently
+	byURI := make(map[string][]*core.TokenBalance)
+	for _, balance := range balances {
+		if balance.URI != "" {
+			uri := expandTokenURI(balance.URI, balance.TokenIndex)
+			byURI[uri] = append(byURI[uri], balance)
+		}
+	}
+	slots := make(chan struct{}, tokenMetadataConcurrency)
+	var wg sync.WaitGroup
+	for uri, uriBalances := range byURI {
+		wg.Add(1)
+		go func(uri string, uriBalances []*core.TokenBalance) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			// A token whose metadata cannot be resolved is listed without it, rather than failing the page
+			metadata, err := am.resolveTokenMetadata(ctx, uri)
+			if err != nil {
+				log.L(ctx).Warnf("Failed to resolve metadata of token %s in pool %s: %s", uriBalances[0].TokenIndex, uriBalances[0].Pool, err)
+				return
+			}
+			for _, balance := range uriBalances {
+				balance.Metadata = metadata
+			}
+		}(uri, uriBalances)
+	}
+	wg.Wait()
+	return balances, fr, nil
+}
+
+func (am *assetManager) GetToken(ctx context.Context, poolNameOrID, tokenIndex string) (*core.Token, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if pool.Type != core.TokenTypeNonFungible {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotNonFungible, poolNameOrID)
+	}
+
+	// The URI of a token is reported by the connector on each transfer of it
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", pool.ID),
+		fb.Eq("tokenindex", tokenIndex),
+	).Limit(1)
+	transfers, _, err := am.database.GetTokenTransfers(ctx, am.namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(transfers) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+
+	token := &core.Token{
+		Pool:       pool.ID,
+		TokenIndex: tokenIndex,
+		URI:        transfers[0].URI,
+	}
+	if am.metadataEnabled && token.URI != "" {
+		if token.Metadata, err = am.resolveTokenMetadata(ctx, expandTokenURI(token.URI, tokenIndex)); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,389 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestMetadataServer starts a metadata server, and allows the asset manager to fetch from it
+func newTestMetadataServer(t *testing.T, am *assetManager) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/token/1.json", http.StatusFound)
+		case "/token/1.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"Token 1","description":"The first token","image":"ipfs://QmImage"}`))
+		case "/token/bad.json":
+			w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	u, _ := url.Parse(server.URL)
+	am.uriResolvers["http"].(*httpURIResolver).allowedHosts[u.Host] = true
+	return server
+}
+
+func TestExpandTokenURI(t *testing.T) {
+	assert.Equal(t, "https://example.com/1.json", expandTokenURI("https://example.com/1.json", "1"))
+	assert.Equal(t, "https://example.com/000000000000000000000000000000000000000000000000000000000000001f.json", expandTokenURI("https://example.com/{id}.json", "31"))
+	assert.Equal(t, "https://example.com/{id}.json", expandTokenURI("https://example.com/{id}.json", "bad"))
+}
+
+func TestGetTokenBalancesWithMetadata(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	balances := []*core.TokenBalance{
+		{TokenIndex: "1", URI: server.URL + "/token/1.json"},
+		{TokenIndex: "2", URI: server.URL + "/token/2.json"},
+		{},
+		{TokenIndex: "1", URI: server.URL + "/token/1.json"},
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).And()
+	mdi.On("GetTokenBalances", context.Background(), "ns1", f).Return(balances, nil, nil)
+
+	result, _, err := am.GetTokenBalancesWithMetadata(context.Background(), f)
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", result[0].Metadata.Name)
+	assert.Equal(t, "The first token", result[0].Metadata.Description)
+	assert.Equal(t, "ipfs://QmImage", result[0].Metadata.Image)
+	assert.Equal(t, "Token 1", result[0].Metadata.Document.GetString("name"))
+	assert.Nil(t, result[1].Metadata)
+	assert.Nil(t, result[2].Metadata)
+	assert.Equal(t, "Token 1", result[3].Metadata.Name)
+
+	// Served from the cache once resolved
+	server.Close()
+	result, _, err = am.GetTokenBalancesWithMetadata(context.Background(), f)
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", result[0].Metadata.Name)
+}
+
+func TestGetTokenBalancesWithMetadataDisabled(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.metadataEnabled = false
+
+	balances := []*core.TokenBalance{{TokenIndex: "1", URI: "https://example.com/1.json"}}
+	mdi := am.database.(*databasemocks.Plugin)
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).And()
+	mdi.On("GetTokenBalances", context.Background(), "ns1", f).Return(balances, nil, nil)
+
+	result, _, err := am.GetTokenBalancesWithMetadata(context.Background(), f)
+	assert.NoError(t, err)
+	assert.Nil(t, result[0].Metadata)
+}
+
+func TestGetTokenBalancesWithMetadataFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).And()
+	mdi.On("GetTokenBalances", context.Background(), "ns1", f).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, _, err := am.GetTokenBalancesWithMetadata(context.Background(), f)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestResolveTokenMetadataBadURI(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.resolveTokenMetadata(context.Background(), "://bad")
+	assert.Regexp(t, "FF10632", err)
+}
+
+func TestResolveTokenMetadataRelative(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+	am.uriResolvers[""].(*httpURIResolver).client.SetBaseURL(server.URL)
+
+	metadata, err := am.resolveTokenMetadata(context.Background(), "token/1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", metadata.Name)
+}
+
+func TestResolveTokenMetadataUnsupportedScheme(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.resolveTokenMetadata(context.Background(), "ar://abcd")
+	assert.Regexp(t, "FF10632", err)
+}
+
+func TestResolveTokenMetadataInvalid(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	_, err := am.resolveTokenMetadata(context.Background(), server.URL+"/token/bad.json")
+	assert.Regexp(t, "FF10634", err)
+}
+
+func TestResolveTokenMetadataTooLarge(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+	am.metadataMaxSize = 10
+
+	_, err := am.resolveTokenMetadata(context.Background(), server.URL+"/token/1.json")
+	assert.Regexp(t, "FF10634", err)
+}
+
+func TestResolveTokenMetadataHTTPFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	_, err := am.resolveTokenMetadata(context.Background(), server.URL+"/token/2.json")
+	assert.Regexp(t, "FF10633", err)
+}
+
+func TestResolveTokenMetadataIPFS(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mss := &sharedstoragemocks.Plugin{}
+	resolvers, err := newTokenURIResolvers(context.Background(), mss)
+	assert.NoError(t, err)
+	am.uriResolvers = resolvers
+	mss.On("DownloadData", mock.Anything, "QmMetadata/1.json").
+		Return(io.NopCloser(strings.NewReader(`{"name":"Token 1"}`)), nil).Once()
+	mss.On("DownloadData", mock.Anything, "QmMetadata/2.json").
+		Return(nil, fmt.Errorf("pop"))
+
+	metadata, err := am.resolveTokenMetadata(context.Background(), "ipfs://QmMetadata/1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", metadata.Name)
+
+	_, err = am.resolveTokenMetadata(context.Background(), "ipfs://ipfs/QmMetadata/2.json")
+	assert.EqualError(t, err, "pop")
+
+	mss.AssertExpectations(t)
+}
+
+func TestGetTokenMetadataDisabled(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+	am.metadataEnabled = false
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeNonFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{
+		{URI: server.URL + "/token/{id}.json"},
+	}, nil, nil)
+
+	token, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, pool.ID, token.Pool)
+	assert.Equal(t, "1", token.TokenIndex)
+	assert.Equal(t, server.URL+"/token/{id}.json", token.URI)
+	assert.Nil(t, token.Metadata)
+}
+
+func TestGetTokenWithMetadata(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeNonFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{
+		{URI: server.URL + "/token/1.json"},
+	}, nil, nil)
+
+	token, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", token.Metadata.Name)
+}
+
+func TestGetTokenNoURI(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeNonFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{}}, nil, nil)
+
+	token, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.NoError(t, err)
+	assert.Nil(t, token.Metadata)
+}
+
+func TestGetTokenMetadataFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeNonFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{
+		{URI: server.URL + "/token/2.json"},
+	}, nil, nil)
+
+	_, err := am.GetToken(context.Background(), "pool1", "2")
+	assert.Regexp(t, "FF10633", err)
+}
+
+func TestGetTokenNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeNonFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+
+	_, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetTokenTransfersFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeNonFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenFungible(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Type: core.TokenTypeFungible}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+
+	_, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.Regexp(t, "FF10631", err)
+}
+
+func TestGetTokenPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.GetToken(context.Background(), "pool1", "1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestResolveTokenMetadataHostNotAllowed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"name":"Token 1"}`))
+	}))
+	defer server.Close()
+
+	_, err := am.resolveTokenMetadata(context.Background(), server.URL+"/token/1.json")
+	assert.Regexp(t, "FF10648", err)
+
+	// The failure is cached
+	_, err = am.resolveTokenMetadata(context.Background(), server.URL+"/token/1.json")
+	assert.Regexp(t, "FF10648", err)
+
+	// A relative URI cannot name its own host
+	_, err = am.resolveTokenMetadata(context.Background(), strings.TrimPrefix(server.URL, "http:")+"/token/1.json")
+	assert.Regexp(t, "FF10648", err)
+
+	// Any host is allowed with a wildcard
+	am.uriResolvers["http"].(*httpURIResolver).allowedHosts["*"] = true
+	metadata, err := am.resolveTokenMetadata(context.Background(), server.URL+"/token/2.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", metadata.Name)
+	assert.Equal(t, 1, requests)
+}
+
+func TestResolveTokenMetadataRedirectNotFollowed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	_, err := am.resolveTokenMetadata(context.Background(), server.URL+"/redirect")
+	assert.Regexp(t, "FF10633", err)
+}
+
+func TestResolveTokenMetadataCancelledNotCached(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	server := newTestMetadataServer(t, am)
+	defer server.Close()
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+	_, err := am.resolveTokenMetadata(ctx, server.URL+"/token/1.json")
+	assert.Error(t, err)
+
+	metadata, err := am.resolveTokenMetadata(context.Background(), server.URL+"/token/1.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", metadata.Name)
+}
+
+func TestNewTokenURIResolversAllowedHosts(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	tokenMetadataConfig.Set(coreconfig.TokenMetadataAllowedHosts, []string{"Example.com:8080"})
+	resolvers, err := newTokenURIResolvers(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.True(t, resolvers["http"].(*httpURIResolver).allowedHosts["example.com:8080"])
+}
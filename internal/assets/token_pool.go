@@ -35,6 +35,11 @@ func (am *assetManager) CreateTokenPool(ctx context.Context, pool *core.TokenPoo
 	if err := fftypes.ValidateFFNameFieldNoUUID(ctx, pool.Name, "name"); err != nil {
 		return nil, err
 	}
+	if pool.Backfill != nil {
+		if err := pool.Backfill.Validate(ctx); err != nil {
+			return nil, err
+		}
+	}
 	if existing, err := am.database.GetTokenPool(ctx, am.namespace, pool.Name); err != nil {
 		return nil, err
 	} else if existing != nil {
@@ -43,13 +48,11 @@ func (am *assetManager) CreateTokenPool(ctx context.Context, pool *core.TokenPoo
 	pool.ID = fftypes.NewUUID()
 	pool.Namespace = am.namespace
 
-	if pool.Connector == "" {
-		connector, err := am.getDefaultTokenConnector(ctx)
-		if err != nil {
-			return nil, err
-		}
-		pool.Connector = connector
+	connector, err := am.resolvePoolConnector(ctx, pool.Name, pool.Connector)
+	if err != nil {
+		return nil, err
 	}
+	pool.Connector = connector
 
 	if pool.Interface != nil {
 		if err := am.contracts.ResolveFFIReference(ctx, pool.Interface); err != nil {
@@ -57,7 +60,6 @@ func (am *assetManager) CreateTokenPool(ctx context.Context, pool *core.TokenPoo
 		}
 	}
 
-	var err error
 	pool.Key, err = am.identity.ResolveInputSigningKey(ctx, pool.Key, am.keyNormalization)
 	if err != nil {
 		return nil, err
@@ -81,6 +83,7 @@ func (am *assetManager) createTokenPoolInternal(ctx context.Context, pool *core.
 	var newOperation *core.Operation
 	var resubmitted []*core.Operation
 	var resubmitErr error
+	var duplicate bool
 	err = am.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
 		txid, err := am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenPool, pool.IdempotencyKey)
 		if err != nil {
@@ -103,6 +106,14 @@ func (am *assetManager) createTokenPoolInternal(ctx context.Context, pool *core.
 					pool.TX.ID = idemErr.ExistingTXID
 					pool.TX.Type = core.TransactionTypeTokenPool
 					err = nil
+				} else if existing, txErr := am.txHelper.GetIdempotentTransaction(ctx, idemErr.ExistingTXID); txErr != nil {
+					return txErr
+				} else if existing != nil {
+					// The original request is within the idempotency window - return it, rather than a 409 Conflict error
+					pool.TX.ID = existing.ID
+					pool.TX.Type = core.TransactionTypeTokenPool
+					duplicate = true
+					err = nil
 				}
 			}
 			if !resubmitWholeTX {
@@ -123,8 +134,8 @@ func (am *assetManager) createTokenPoolInternal(ctx context.Context, pool *core.
 		}
 		return err
 	})
-	if len(resubmitted) > 0 {
-		// We resubmitted a previously initialized operation, don't run a new one
+	if len(resubmitted) > 0 || duplicate {
+		// We resubmitted a previously initialized operation, or the original request is complete - don't run a new one
 		return &pool.TokenPool, nil
 	}
 	if err != nil {
@@ -137,6 +148,8 @@ func (am *assetManager) createTokenPoolInternal(ctx context.Context, pool *core.
 }
 
 func (am *assetManager) ActivateTokenPool(ctx context.Context, pool *core.TokenPool) error {
+	// Activation is driven by pool definitions from any member of the network, where the local name may
+	// not be bound (or may have been made unique with a suffix), so the pool bindings are not enforced here
 	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
 	if err != nil {
 		return err
@@ -50,6 +50,21 @@ func TestCreateTokenPoolBadName(t *testing.T) {
 	assert.Regexp(t, "FF00140", err)
 }
 
+func TestCreateTokenPoolBadBackfill(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name:     "testpool",
+			Backfill: &core.TokenPoolBackfill{FromBlock: "latest"},
+		},
+	}
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.Regexp(t, "FF10556", err)
+}
+
 func TestCreateTokenPoolGetError(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -225,6 +240,7 @@ func TestCreateTokenPoolIdempotentNoOperationToResubmit(t *testing.T) {
 			ExistingTXID:  id,
 			OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
 	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, nil)
 
 	_, err := am.CreateTokenPool(context.Background(), pool, false)
 
@@ -239,6 +255,76 @@ func TestCreateTokenPoolIdempotentNoOperationToResubmit(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestCreateTokenPoolIdempotentWithinWindow(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	var id = fftypes.NewUUID()
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name: "testpool",
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("resolved-key", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("idem1")).
+		Return(id, &sqlcommon.IdempotencyError{
+			ExistingTXID:  id,
+			OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(&core.Transaction{ID: id}, nil)
+
+	// The original request is within the idempotency window, so it is returned rather than a 409 Conflict error
+	result, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.NoError(t, err)
+	assert.Equal(t, id, result.TX.ID)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolIdempotentWindowFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	var id = fftypes.NewUUID()
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name: "testpool",
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("resolved-key", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("idem1")).
+		Return(id, &sqlcommon.IdempotencyError{
+			ExistingTXID:  id,
+			OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestCreateTokenPoolIdempotentErrorOnOperationResubmit(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -297,6 +383,51 @@ func TestCreateTokenPoolDefaultConnectorNoConnectors(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestCreateTokenPoolBoundConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"testpool": "magic-tokens"}
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name: "testpool",
+		},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, "magic-tokens", pool.Connector)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolUnboundConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"otherpool": "magic-tokens"}
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name:      "testpool",
+			Connector: "magic-tokens",
+		},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.Regexp(t, "FF10483", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestCreateTokenPoolDefaultConnectorMultipleConnectors(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -643,6 +774,35 @@ func TestActivateTokenPool(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestActivateTokenPoolUnboundName(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"pool1": "magic-tokens"}
+
+	// A pool broadcast by another member, renamed locally to avoid a name conflict
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Name:      "pool1-1",
+		Connector: "magic-tokens",
+		TX: core.TransactionRef{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	mom := am.operations.(*operationmocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mth.On("FindOperationInTransaction", context.Background(), pool.TX.ID, core.OpTypeTokenActivatePool).Return(nil, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything, false).Return(nil, nil)
+
+	err := am.ActivateTokenPool(context.Background(), pool)
+	assert.NoError(t, err)
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestActivateTokenPoolBadConnector(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -18,15 +18,21 @@ package assets
 
 import (
 	"context"
+	"errors"
+	"math/big"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
 )
 
 func (am *assetManager) GetTokenTransfers(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransfer, *ffapi.FilterResult, error) {
@@ -100,6 +106,9 @@ func (am *assetManager) validateTransfer(ctx context.Context, transfer *core.Tok
 			return nil, err
 		}
 	}
+	if err = am.validatePoolBinding(ctx, pool); err != nil {
+		return nil, err
+	}
 	transfer.TokenTransfer.Pool = pool.ID
 	transfer.TokenTransfer.Connector = pool.Connector
 
@@ -118,6 +127,82 @@ func (am *assetManager) validateTransfer(ctx context.Context, transfer *core.Tok
 	return pool, nil
 }
 
+// The ways that transfers and burns from the balance of a key other than the signing key can be validated
+const (
+	// delegatedCheckConnector simulates transfers through the token connector, so the token contract checks the
+	// allowance. Connectors can only simulate transfers, so burns are left to the token contract, as are transfers
+	// through a connector that does not support simulation.
+	delegatedCheckConnector = "connector"
+	// delegatedCheckRecorded checks against the approvals FireFly has recorded, which can be out of date if
+	// approvals are made outside of FireFly, as the recorded allowance is the amount at the time of the approval
+	delegatedCheckRecorded = "recorded"
+	// delegatedCheckNone submits without validation, for the token contract to accept or reject
+	delegatedCheckNone = "none"
+)
+
+// validateTransferApproval checks that when tokens are transferred or burned from a balance other than that of the
+// signing key, the signing key is allowed to spend the requested amount from that balance.
+func (am *assetManager) validateTransferApproval(ctx context.Context, pool *core.TokenPool, plugin tokens.Plugin, transfer *core.TokenTransfer) error {
+	if transfer.Type == core.TokenTransferTypeMint || strings.EqualFold(transfer.From, transfer.Key) || am.delegatedCheck == delegatedCheckNone {
+		return nil
+	}
+	if am.delegatedCheck == delegatedCheckConnector {
+		if transfer.Type != core.TokenTransferTypeTransfer {
+			return nil
+		}
+		sim, err := plugin.SimulateTransfer(ctx, pool.Locator, transfer, pool.Methods)
+		if isNotSupported(err) {
+			log.L(ctx).Warnf("Token connector '%s' cannot simulate transfers - submitting transfer from '%s' without validation", pool.Connector, transfer.From)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !sim.Success {
+			return i18n.NewError(ctx, coremsgs.MsgTokenDelegatedTransferRejected, transfer.Key, transfer.From, sim.RevertReason)
+		}
+		return nil
+	}
+	fb := database.TokenApprovalQueryFactory.NewFilter(ctx)
+	approvals, _, err := am.database.GetTokenApprovals(ctx, am.namespace, fb.And(
+		fb.Eq("pool", pool.ID),
+		fb.IEq("key", transfer.From),
+		fb.IEq("operator", transfer.Key),
+		fb.Eq("active", true),
+		fb.Eq("approved", true),
+	))
+	if err != nil {
+		return err
+	}
+	if len(approvals) == 0 {
+		return i18n.NewError(ctx, coremsgs.MsgTokenOperatorNotApproved, transfer.Key, transfer.From, pool.Name)
+	}
+	var allowance *big.Int
+	for _, approval := range approvals {
+		valueStr := approval.Info.GetString("value")
+		if valueStr == "" {
+			// An unlimited approval, as no value was reported
+			return nil
+		}
+		value, ok := new(big.Int).SetString(valueStr, 0)
+		if !ok {
+			return i18n.NewError(ctx, coremsgs.MsgTokenApprovalValueInvalid, valueStr, transfer.Key, transfer.From)
+		}
+		if value.Cmp(transfer.Amount.Int()) >= 0 {
+			return nil
+		}
+		if allowance == nil || value.Cmp(allowance) > 0 {
+			allowance = value
+		}
+	}
+	return i18n.NewError(ctx, coremsgs.MsgTokenAllowanceInsufficient, allowance.String(), transfer.Key, transfer.From, transfer.Amount.String())
+}
+
+func isNotSupported(err error) bool {
+	var ffErr i18n.FFError
+	return errors.As(err, &ffErr) && ffErr.MessageKey() == coremsgs.MsgActionNotSupported
+}
+
 func (am *assetManager) MintTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (out *core.TokenTransfer, err error) {
 	transfer.Type = core.TokenTransferTypeMint
 	if transfer.Namespace == "" {
@@ -172,6 +257,45 @@ func (am *assetManager) TransferTokens(ctx context.Context, transfer *core.Token
 	return &transfer.TokenTransfer, err
 }
 
+// SimulateTransferTokens validates a transfer and asks the token connector whether it would succeed,
+// without creating a transaction or operations
+func (am *assetManager) SimulateTransferTokens(ctx context.Context, transfer *core.TokenTransferInput) (*core.Simulation, error) {
+	transfer.Type = core.TokenTransferTypeTransfer
+	if transfer.Namespace == "" {
+		transfer.Namespace = am.namespace
+	}
+	if transfer.Message != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSimulateWithMessage)
+	}
+
+	// With connector validation, the simulation itself reports whether the signing key can spend from the balance
+	pool, plugin, err := am.prepareTransfer(ctx, transfer, am.delegatedCheck != delegatedCheckConnector)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.SimulateTransfer(ctx, pool.Locator, &transfer.TokenTransfer, pool.Methods)
+}
+
+func (am *assetManager) prepareTransfer(ctx context.Context, transfer *core.TokenTransferInput, validateApproval bool) (*core.TokenPool, tokens.Plugin, error) {
+	pool, err := am.validateTransfer(ctx, transfer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if transfer.Type == core.TokenTransferTypeTransfer && transfer.From == transfer.To {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgCannotTransferToSelf)
+	}
+	plugin, err := am.selectTokenPlugin(ctx, transfer.Connector)
+	if err != nil {
+		return nil, nil, err
+	}
+	if validateApproval {
+		if err = am.validateTransferApproval(ctx, pool, plugin, &transfer.TokenTransfer); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pool, plugin, nil
+}
+
 func (s *transferSender) resolveAndSend(ctx context.Context, method sendMethod) (err error) {
 	if !s.resolved {
 		var opResubmit bool
@@ -221,6 +345,17 @@ func (s *transferSender) resolve(ctx context.Context) (opResubmitted bool, err e
 				s.transfer.TX.ID = idemErr.ExistingTXID
 				s.transfer.TX.Type = core.TransactionTypeTokenTransfer
 				return true, nil
+			} else if resubmitErr == nil {
+				// Return the original request if it is within the idempotency window, rather than a 409 Conflict error
+				existing, txErr := s.mgr.txHelper.GetIdempotentTransaction(ctx, idemErr.ExistingTXID)
+				if txErr != nil {
+					return false, txErr
+				}
+				if existing != nil {
+					s.transfer.TX.ID = existing.ID
+					s.transfer.TX.Type = core.TransactionTypeTokenTransfer
+					return true, nil
+				}
 			}
 
 		}
@@ -262,15 +397,8 @@ func (s *transferSender) sendInternal(ctx context.Context, method sendMethod) (e
 	var op *core.Operation
 	var pool *core.TokenPool
 	err = s.mgr.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
-		pool, err = s.mgr.validateTransfer(ctx, s.transfer)
-		if err != nil {
-			return err
-		}
-		if s.transfer.Type == core.TokenTransferTypeTransfer && s.transfer.From == s.transfer.To {
-			return i18n.NewError(ctx, coremsgs.MsgCannotTransferToSelf)
-		}
-
-		plugin, err := s.mgr.selectTokenPlugin(ctx, s.transfer.Connector)
+		var plugin tokens.Plugin
+		pool, plugin, err = s.mgr.prepareTransfer(ctx, s.transfer, true)
 		if err != nil {
 			return err
 		}
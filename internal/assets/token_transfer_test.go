@@ -32,6 +32,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
 	"github.com/hyperledger/firefly/mocks/txcommonmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -200,6 +201,7 @@ func TestMintTokensIdempotentNoOperationToResubmit(t *testing.T) {
 		ExistingTXID:  id,
 		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
 	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resumit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, nil)
 
 	// If ResubmitOperations returns nil it's because there was no operation in initialized state, so we expect the regular 409 error back
 	_, err := am.MintTokens(context.Background(), mint, false)
@@ -210,6 +212,64 @@ func TestMintTokensIdempotentNoOperationToResubmit(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestMintTokensIdempotentWithinWindow(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+	var id = fftypes.NewUUID()
+
+	mint := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(id, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resumit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(&core.Transaction{ID: id}, nil)
+
+	// The original request is within the idempotency window, so it is returned rather than a 409 Conflict error
+	result, err := am.MintTokens(context.Background(), mint, false)
+	assert.NoError(t, err)
+	assert.Equal(t, id, result.TX.ID)
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMintTokensIdempotentWindowFail(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+	var id = fftypes.NewUUID()
+
+	mint := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(id, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resumit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokens(context.Background(), mint, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestMintTokensIdempotentErrorOnResubmit(t *testing.T) {
 	am, cancel := newTestAssetsWithMetrics(t)
 	defer cancel()
@@ -442,6 +502,35 @@ func TestMintTokensBadPool(t *testing.T) {
 	mth.AssertExpectations(t)
 }
 
+func TestMintTokensUnboundPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.poolConnectors = map[string]string{"pool1": "other-tokens"}
+
+	mint := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&core.TokenPool{
+		Name:      "pool1",
+		Connector: "magic-tokens",
+		Active:    true,
+	}, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.MintTokens(context.Background(), mint, false)
+	assert.Regexp(t, "FF10484", err)
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
 func TestMintTokensIdentityFail(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -495,35 +584,453 @@ func TestMintTokensFail(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &mint.TokenTransfer
+	}), true).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokens(context.Background(), mint, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMintTokensOperationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mint := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	pool := &core.TokenPool{
+		Locator:   "F1",
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.MintTokens(context.Background(), mint, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMintTokensConfirm(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mint := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	msa := am.syncasync.(*syncasyncmocks.Bridge)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.SendFunction)
+			send(context.Background())
+		}).
+		Return(&core.TokenTransfer{}, nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &mint.TokenTransfer
+	}), true).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokens(context.Background(), mint, true)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	msa.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestBurnTokensSuccess(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+
+	burn := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &burn.TokenTransfer
+	}), true).Return(nil, nil)
+
+	_, err := am.BurnTokens(context.Background(), burn, false)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestBurnTokensOperatorRecordedApproval(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
+
+	burn := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return([]*core.TokenApproval{}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.BurnTokens(context.Background(), burn, false)
+	assert.Regexp(t, "FF10589", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestBurnTokensIdentityFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	burn := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.BurnTokens(context.Background(), burn, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestBurnTokensConfirm(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	burn := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	msa := am.syncasync.(*syncasyncmocks.Bridge)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.SendFunction)
+			send(context.Background())
+		}).
+		Return(&core.TokenTransfer{}, nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &burn.TokenTransfer
+	}), true).Return(nil, nil)
+
+	_, err := am.BurnTokens(context.Background(), burn, true)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	msa.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensSuccess(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &transfer.TokenTransfer
+	}), true).Return(nil, nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensOperatorConnectorRejected(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Locator:   "F1",
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mti.On("SimulateTransfer", context.Background(), "F1", &transfer.TokenTransfer, pool.Methods).Return(&core.Simulation{
+		Success:      false,
+		RevertReason: "ERC20: insufficient allowance",
+	}, nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.Regexp(t, "FF10660.*insufficient allowance", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestTransferTokensOperatorConnectorFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestTransferTokensOperatorConnectorNotSupported(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(nil, i18n.NewError(context.Background(), coremsgs.MsgActionNotSupported))
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything, false).Return(nil, nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mti.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestBurnTokensOperatorConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	burn := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	// Connectors can only simulate transfers, so burns are left to the token contract
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
-	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(transferData)
-		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &mint.TokenTransfer
-	}), true).Return(nil, fmt.Errorf("pop"))
+	mom.On("RunOperation", context.Background(), mock.Anything, false).Return(nil, nil)
 
-	_, err := am.MintTokens(context.Background(), mint, false)
-	assert.EqualError(t, err, "pop")
+	_, err := am.BurnTokens(context.Background(), burn, false)
+	assert.NoError(t, err)
 
-	mdi.AssertExpectations(t)
 	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
 	mth.AssertExpectations(t)
 	mom.AssertExpectations(t)
 }
 
-func TestMintTokensOperationFail(t *testing.T) {
+func TestTransferTokensOperatorNoValidation(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
+	am.delegatedCheck = delegatedCheckNone
 
-	mint := &core.TokenTransferInput{
+	transfer := &core.TokenTransferInput{
 		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
 			Amount: *fftypes.NewFFBigInt(5),
 		},
-		Pool:           "pool1",
-		IdempotencyKey: "idem1",
+		Pool: "pool1",
 	}
 	pool := &core.TokenPool{
-		Locator:   "F1",
 		Connector: "magic-tokens",
 		Active:    true,
 	}
@@ -534,72 +1041,76 @@ func TestMintTokensOperationFail(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
-	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything, false).Return(nil, nil)
 
-	_, err := am.MintTokens(context.Background(), mint, false)
-	assert.EqualError(t, err, "pop")
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.NoError(t, err)
 
-	mdi.AssertExpectations(t)
 	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
 	mth.AssertExpectations(t)
 	mom.AssertExpectations(t)
 }
 
-func TestMintTokensConfirm(t *testing.T) {
+func TestTransferTokensOperatorAllowance(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
 
-	mint := &core.TokenTransferInput{
+	transfer := &core.TokenTransferInput{
 		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
 			Amount: *fftypes.NewFFBigInt(5),
 		},
-		Pool:           "pool1",
-		IdempotencyKey: "idem1",
+		Pool: "pool1",
 	}
 	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
 		Connector: "magic-tokens",
 		Active:    true,
 	}
 
 	mdi := am.database.(*databasemocks.Plugin)
-	msa := am.syncasync.(*syncasyncmocks.Bridge)
 	mim := am.identity.(*identitymanagermocks.Manager)
 	mth := am.txHelper.(*txcommonmocks.Helper)
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("( pool == '%s' ) && ( key := 'A' ) && ( operator := '0x12345' ) && ( active == true ) && ( approved == true )", pool.ID)
+	})).Return([]*core.TokenApproval{
+		{Key: "A", Operator: "0x12345", Approved: true, Active: true, Info: fftypes.JSONObject{"value": "3"}},
+		{Key: "A", Operator: "0x12345", Approved: true, Active: true, Info: fftypes.JSONObject{"value": "10"}},
+	}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
-	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
-		Run(func(args mock.Arguments) {
-			send := args[2].(syncasync.SendFunction)
-			send(context.Background())
-		}).
-		Return(&core.TokenTransfer{}, nil)
-	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(transferData)
-		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &mint.TokenTransfer
-	}), true).Return(nil, fmt.Errorf("pop"))
+	mom.On("RunOperation", context.Background(), mock.Anything, false).Return(nil, nil)
 
-	_, err := am.MintTokens(context.Background(), mint, true)
+	_, err := am.TransferTokens(context.Background(), transfer, false)
 	assert.NoError(t, err)
 
+	mim.AssertExpectations(t)
 	mdi.AssertExpectations(t)
-	msa.AssertExpectations(t)
+	mth.AssertExpectations(t)
 	mom.AssertExpectations(t)
 }
 
-func TestBurnTokensSuccess(t *testing.T) {
-	am, cancel := newTestAssetsWithMetrics(t)
+func TestTransferTokensOperatorAllowanceInsufficient(t *testing.T) {
+	am, cancel := newTestAssets(t)
 	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
 
-	burn := &core.TokenTransferInput{
+	transfer := &core.TokenTransferInput{
 		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
 			Amount: *fftypes.NewFFBigInt(5),
 		},
-		Pool:           "pool1",
-		IdempotencyKey: "idem1",
+		Pool: "pool1",
 	}
 	pool := &core.TokenPool{
 		Connector: "magic-tokens",
@@ -609,35 +1120,34 @@ func TestBurnTokensSuccess(t *testing.T) {
 	mdi := am.database.(*databasemocks.Plugin)
 	mim := am.identity.(*identitymanagermocks.Manager)
 	mth := am.txHelper.(*txcommonmocks.Helper)
-	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
-	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
-	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(transferData)
-		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &burn.TokenTransfer
-	}), true).Return(nil, nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return([]*core.TokenApproval{
+		{Key: "A", Operator: "0x12345", Approved: true, Active: true, Info: fftypes.JSONObject{"value": "3"}},
+		{Key: "A", Operator: "0x12345", Approved: true, Active: true, Info: fftypes.JSONObject{"value": "2"}},
+	}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
 
-	_, err := am.BurnTokens(context.Background(), burn, false)
-	assert.NoError(t, err)
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.Regexp(t, "FF10590.*'3'", err)
 
 	mim.AssertExpectations(t)
 	mdi.AssertExpectations(t)
 	mth.AssertExpectations(t)
-	mom.AssertExpectations(t)
 }
 
-func TestBurnTokensIdentityFail(t *testing.T) {
+func TestTransferTokensOperatorAllowanceInvalid(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
 
-	burn := &core.TokenTransferInput{
+	transfer := &core.TokenTransferInput{
 		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
 			Amount: *fftypes.NewFFBigInt(5),
 		},
-		Pool:           "pool1",
-		IdempotencyKey: "idem1",
+		Pool: "pool1",
 	}
 	pool := &core.TokenPool{
 		Connector: "magic-tokens",
@@ -647,28 +1157,33 @@ func TestBurnTokensIdentityFail(t *testing.T) {
 	mdi := am.database.(*databasemocks.Plugin)
 	mim := am.identity.(*identitymanagermocks.Manager)
 	mth := am.txHelper.(*txcommonmocks.Helper)
-	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return([]*core.TokenApproval{
+		{Key: "A", Operator: "0x12345", Approved: true, Active: true, Info: fftypes.JSONObject{"value": "lots"}},
+	}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
 
-	_, err := am.BurnTokens(context.Background(), burn, false)
-	assert.EqualError(t, err, "pop")
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.Regexp(t, "FF10665.*'lots'", err)
 
-	mdi.AssertExpectations(t)
 	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
 	mth.AssertExpectations(t)
 }
 
-func TestBurnTokensConfirm(t *testing.T) {
+func TestTransferTokensOperatorNotApproved(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
 
-	burn := &core.TokenTransferInput{
+	transfer := &core.TokenTransferInput{
 		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
 			Amount: *fftypes.NewFFBigInt(5),
 		},
-		Pool:           "pool1",
-		IdempotencyKey: "idem1",
+		Pool: "pool1",
 	}
 	pool := &core.TokenPool{
 		Connector: "magic-tokens",
@@ -676,37 +1191,25 @@ func TestBurnTokensConfirm(t *testing.T) {
 	}
 
 	mdi := am.database.(*databasemocks.Plugin)
-	msa := am.syncasync.(*syncasyncmocks.Bridge)
 	mim := am.identity.(*identitymanagermocks.Manager)
 	mth := am.txHelper.(*txcommonmocks.Helper)
-	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
-	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
-	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
-		Run(func(args mock.Arguments) {
-			send := args[2].(syncasync.SendFunction)
-			send(context.Background())
-		}).
-		Return(&core.TokenTransfer{}, nil)
-	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(transferData)
-		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &burn.TokenTransfer
-	}), true).Return(nil, nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return([]*core.TokenApproval{}, nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
 
-	_, err := am.BurnTokens(context.Background(), burn, true)
-	assert.NoError(t, err)
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.Regexp(t, "FF10589", err)
 
+	mim.AssertExpectations(t)
 	mdi.AssertExpectations(t)
-	msa.AssertExpectations(t)
 	mth.AssertExpectations(t)
-	mom.AssertExpectations(t)
 }
 
-func TestTransferTokensSuccess(t *testing.T) {
-	am, cancel := newTestAssetsWithMetrics(t)
+func TestTransferTokensOperatorApprovalQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
 	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
 
 	transfer := &core.TokenTransferInput{
 		TokenTransfer: core.TokenTransfer{
@@ -714,8 +1217,7 @@ func TestTransferTokensSuccess(t *testing.T) {
 			To:     "B",
 			Amount: *fftypes.NewFFBigInt(5),
 		},
-		Pool:           "pool1",
-		IdempotencyKey: "idem1",
+		Pool: "pool1",
 	}
 	pool := &core.TokenPool{
 		Connector: "magic-tokens",
@@ -725,23 +1227,17 @@ func TestTransferTokensSuccess(t *testing.T) {
 	mdi := am.database.(*databasemocks.Plugin)
 	mim := am.identity.(*identitymanagermocks.Manager)
 	mth := am.txHelper.(*txcommonmocks.Helper)
-	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
-	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
-	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
-	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(transferData)
-		return op.Type == core.OpTypeTokenTransfer && data.Pool == pool && data.Transfer == &transfer.TokenTransfer
-	}), true).Return(nil, nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
 
 	_, err := am.TransferTokens(context.Background(), transfer, false)
-	assert.NoError(t, err)
+	assert.EqualError(t, err, "pop")
 
 	mim.AssertExpectations(t)
 	mdi.AssertExpectations(t)
 	mth.AssertExpectations(t)
-	mom.AssertExpectations(t)
 }
 
 func TestTransferTokensUnconfirmedPool(t *testing.T) {
@@ -900,6 +1396,8 @@ func TestTransferTokensWithBroadcastMessage(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mbm.On("NewBroadcast", transfer.Message).Return(mms)
@@ -1003,6 +1501,8 @@ func TestTransferTokensWithBroadcastMessageSendFail(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mbm.On("NewBroadcast", transfer.Message).Return(mms)
@@ -1100,6 +1600,8 @@ func TestTransferTokensWithPrivateMessage(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mpm.On("NewMessage", transfer.Message).Return(mms)
@@ -1223,6 +1725,8 @@ func TestTransferTokensConfirm(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
@@ -1288,6 +1792,8 @@ func TestTransferTokensWithBroadcastConfirm(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mbm.On("NewBroadcast", transfer.Message).Return(mms)
@@ -1375,6 +1881,8 @@ func TestTransferPrepare(t *testing.T) {
 	mth := am.txHelper.(*txcommonmocks.Helper)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("SimulateTransfer", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(&core.Simulation{Success: true}, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 
 	err := sender.Prepare(context.Background())
@@ -1384,3 +1892,153 @@ func TestTransferPrepare(t *testing.T) {
 	mim.AssertExpectations(t)
 	mth.AssertExpectations(t)
 }
+
+func TestSimulateTransferTokens(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		Active:    true,
+	}
+	sim := &core.Simulation{Success: true, GasEstimate: fftypes.NewFFBigInt(21000)}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti.On("SimulateTransfer", context.Background(), "F1", &transfer.TokenTransfer, pool.Methods).Return(sim, nil)
+
+	res, err := am.SimulateTransferTokens(context.Background(), transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, sim, res)
+	assert.Equal(t, core.TokenTransferTypeTransfer, transfer.Type)
+	assert.Equal(t, "0x12345", transfer.From)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestSimulateTransferTokensOperator(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		Active:    true,
+	}
+	sim := &core.Simulation{Success: false, RevertReason: "ERC20: insufficient allowance"}
+
+	// The simulation reports the rejection, rather than failing validation
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti.On("SimulateTransfer", context.Background(), "F1", &transfer.TokenTransfer, pool.Methods).Return(sim, nil).Once()
+
+	res, err := am.SimulateTransferTokens(context.Background(), transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, sim, res)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestSimulateTransferTokensOperatorRecorded(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.delegatedCheck = delegatedCheckRecorded
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return([]*core.TokenApproval{}, nil, nil)
+
+	_, err := am.SimulateTransferTokens(context.Background(), transfer)
+	assert.Regexp(t, "FF10589", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestSimulateTransferTokensWithMessage(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool:    "pool1",
+		Message: &core.MessageInOut{},
+	}
+
+	_, err := am.SimulateTransferTokens(context.Background(), transfer)
+	assert.Regexp(t, "FF10643", err)
+}
+
+func TestSimulateTransferTokensToSelf(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "A",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		Active:    true,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+
+	_, err := am.SimulateTransferTokens(context.Background(), transfer)
+	assert.Regexp(t, "FF10280", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
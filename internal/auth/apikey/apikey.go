@@ -0,0 +1,93 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Auth authenticates callers with a static API key, which grants a role in a set of namespaces
+type Auth struct {
+	header string
+	keys   map[string]*apiKey
+}
+
+type apiKey struct {
+	name  string
+	grant core.APIGrant
+}
+
+func Name() string {
+	return "apikey"
+}
+
+func (a *Auth) Name() string {
+	return Name()
+}
+
+func (a *Auth) Init(ctx context.Context, name string, conf config.Section) error {
+	a.header = conf.GetString(APIKeyHeader)
+	a.keys = make(map[string]*apiKey)
+	keysConf := keysConfig(conf)
+	for i := 0; i < keysConf.ArraySize(); i++ {
+		keyConf := keysConf.ArrayEntry(i)
+		key := &apiKey{name: keyConf.GetString(APIKeyName)}
+		hash := strings.ToLower(keyConf.GetString(APIKeyHash))
+		if hash == "" || keyConf.GetString(APIKeyRole) == "" {
+			return i18n.NewError(ctx, coremsgs.MsgAPIKeyConfigInvalid, key.name)
+		}
+		role, err := core.ParseAPIRole(ctx, keyConf.GetString(APIKeyRole))
+		if err != nil {
+			return err
+		}
+		key.grant = core.APIGrant{
+			Role:       role,
+			Namespaces: keyConf.GetStringSlice(APIKeyNamespaces),
+		}
+		a.keys[hash] = key
+	}
+	log.L(ctx).Infof("API key auth plugin enabled (name=%s, keys=%d)", name, len(a.keys))
+	return nil
+}
+
+func (a *Auth) Authorize(ctx context.Context, req *fftypes.AuthReq) error {
+	value := req.Header.Get(a.header)
+	if value == "" {
+		return i18n.NewError(ctx, i18n.MsgUnauthorized)
+	}
+	hash := sha256.Sum256([]byte(value))
+	key, ok := a.keys[hex.EncodeToString(hash[:])]
+	if !ok {
+		return i18n.NewError(ctx, i18n.MsgUnauthorized)
+	}
+	if err := key.grant.Authorize(ctx, req); err != nil {
+		log.L(ctx).Warnf("API key '%s' rejected: %s", key.name, err)
+		return err
+	}
+	core.SetAuthenticatedIdentity(ctx, Name()+":"+key.name)
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func sha256Hex(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func newTestAuth(t *testing.T, keysYAML string) (*Auth, error) {
+	coreconfig.Reset()
+	conf := config.RootSection("apikey")
+	a := &Auth{}
+	a.InitConfig(conf)
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+apikey:
+  keys:
+` + keysYAML))
+	assert.NoError(t, err)
+	return a, a.Init(context.Background(), "test", conf)
+}
+
+func authReq(key, namespace string) *fftypes.AuthReq {
+	header := http.Header{}
+	if key != "" {
+		header.Set("X-API-Key", key)
+	}
+	return &fftypes.AuthReq{Method: http.MethodPost, Header: header, Namespace: namespace}
+}
+
+func TestAuthorizeOk(t *testing.T) {
+	a, err := newTestAuth(t, `
+  - name: app1
+    hash: `+strings.ToUpper(sha256Hex("key1"))+`
+    role: reader
+  - name: app2
+    hash: `+sha256Hex("key2")+`
+    role: submitter
+    namespaces: [ns1]`)
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey", a.Name())
+
+	ctx, caller := core.WithAuthenticatedCaller(core.WithRequiredAPIRole(context.Background(), core.APIRoleSubmitter))
+	assert.NoError(t, a.Authorize(ctx, authReq("key2", "ns1")))
+	assert.Equal(t, "apikey:app2", caller.Identity)
+	assert.Regexp(t, "FF10568", a.Authorize(ctx, authReq("key2", "ns2")))
+	assert.Regexp(t, "FF10567", a.Authorize(core.WithRequiredAPIRole(ctx, core.APIRoleAdmin), authReq("key2", "ns1")))
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq("wrong", "ns1")))
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq("", "ns1")))
+	assert.Regexp(t, "FF10567", a.Authorize(ctx, authReq("key1", "ns2")))
+	assert.NoError(t, a.Authorize(core.WithRequiredAPIRole(ctx, core.APIRoleReader), authReq("key1", "ns2")))
+}
+
+func TestInitMissingHash(t *testing.T) {
+	_, err := newTestAuth(t, `
+  - name: app1
+    role: reader`)
+	assert.Regexp(t, "FF10569.*app1", err)
+}
+
+func TestInitBadRole(t *testing.T) {
+	_, err := newTestAuth(t, `
+  - name: app1
+    hash: abcd
+    role: superuser`)
+	assert.Regexp(t, "FF10566", err)
+}
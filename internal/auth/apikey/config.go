@@ -0,0 +1,52 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// APIKeyHeader the HTTP header carrying the API key
+	APIKeyHeader = "header"
+	// APIKeyKeys the list of API keys that are accepted
+	APIKeyKeys = "keys"
+	// APIKeyName the name of the API key, used in logging
+	APIKeyName = "name"
+	// APIKeyHash the hex encoded SHA-256 hash of the API key
+	APIKeyHash = "hash"
+	// APIKeyRole the role granted to callers presenting the API key
+	APIKeyRole = "role"
+	// APIKeyNamespaces the namespaces the API key is valid for - all namespaces if empty
+	APIKeyNamespaces = "namespaces"
+)
+
+func (a *Auth) InitConfig(conf config.Section) {
+	conf.AddKnownKey(APIKeyHeader, "X-API-Key")
+	keysConfig(conf)
+}
+
+// keysConfig returns the array of API keys, with its known keys registered. This is called again on Init, as
+// the plugin instance that is initialized is not the one whose config was initialized.
+func keysConfig(conf config.Section) config.ArraySection {
+	keys := conf.SubArray(APIKeyKeys)
+	keys.AddKnownKey(APIKeyName)
+	keys.AddKnownKey(APIKeyHash)
+	keys.AddKnownKey(APIKeyRole)
+	keys.AddKnownKey(APIKeyNamespaces)
+	return keys
+}
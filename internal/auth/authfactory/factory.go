@@ -0,0 +1,51 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authfactory
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/auth"
+	"github.com/hyperledger/firefly-common/pkg/auth/basic"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/auth/apikey"
+	"github.com/hyperledger/firefly/internal/auth/jwt"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+)
+
+var pluginsByType = map[string]func() auth.Plugin{
+	basic.Name():  func() auth.Plugin { return &basic.Auth{} },
+	apikey.Name(): func() auth.Plugin { return &apikey.Auth{} },
+	jwt.Name():    func() auth.Plugin { return &jwt.Auth{} },
+}
+
+func InitConfigArray(config config.ArraySection) {
+	config.AddKnownKey(coreconfig.PluginConfigName)
+	config.AddKnownKey(coreconfig.PluginConfigType)
+	for name, plugin := range pluginsByType {
+		plugin().InitConfig(config.SubSection(name))
+	}
+}
+
+func GetPlugin(ctx context.Context, pluginType string) (auth.Plugin, error) {
+	plugin, ok := pluginsByType[pluginType]
+	if !ok {
+		return nil, i18n.NewError(ctx, i18n.MsgUnknownAuthPlugin, pluginType)
+	}
+	return plugin(), nil
+}
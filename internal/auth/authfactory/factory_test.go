@@ -0,0 +1,48 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authfactory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPluginUnknown(t *testing.T) {
+	ctx := context.Background()
+	_, err := GetPlugin(ctx, "foo")
+	assert.Error(t, err)
+	assert.Regexp(t, "FF00168", err)
+}
+
+func TestGetPlugin(t *testing.T) {
+	ctx := context.Background()
+	for _, pluginType := range []string{"basic", "apikey", "jwt"} {
+		plugin, err := GetPlugin(ctx, pluginType)
+		assert.NoError(t, err)
+		assert.Equal(t, pluginType, plugin.Name())
+	}
+}
+
+var root = config.RootSection("auth")
+
+func TestInitConfigArray(t *testing.T) {
+	conf := root.SubArray("plugins")
+	InitConfigArray(conf)
+}
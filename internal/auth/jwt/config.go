@@ -0,0 +1,54 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// JWTHMACSecret the shared secret used to verify tokens signed with HS256
+	JWTHMACSecret = "hmacSecret"
+	// JWTJWKSURL the URL of the JSON Web Key Set of an OIDC provider, used to verify tokens signed with RS256
+	JWTJWKSURL = "jwksURL"
+	// JWTJWKSRequestTimeout the maximum time to wait for the JSON Web Key Set to be fetched
+	JWTJWKSRequestTimeout = "jwksRequestTimeout"
+	// JWTJWKSRefreshInterval the minimum time between fetches of the JSON Web Key Set, when a token has an unknown key
+	JWTJWKSRefreshInterval = "jwksRefreshInterval"
+	// JWTIssuer the issuer that tokens must declare in the iss claim, if set
+	JWTIssuer = "issuer"
+	// JWTAudience the audience that tokens must declare in the aud claim, if set
+	JWTAudience = "audience"
+	// JWTRolesClaim the claim holding the role, or list of roles, granted to the caller
+	JWTRolesClaim = "rolesClaim"
+	// JWTNamespacesClaim the claim holding the list of namespaces the token is valid for
+	JWTNamespacesClaim = "namespacesClaim"
+	// JWTClockSkew the leeway allowed when checking the exp, nbf and iat claims, for clocks that are not in sync
+	JWTClockSkew = "clockSkew"
+)
+
+func (a *Auth) InitConfig(conf config.Section) {
+	conf.AddKnownKey(JWTHMACSecret)
+	conf.AddKnownKey(JWTJWKSURL)
+	conf.AddKnownKey(JWTJWKSRequestTimeout, "10s")
+	conf.AddKnownKey(JWTJWKSRefreshInterval, "1m")
+	conf.AddKnownKey(JWTIssuer)
+	conf.AddKnownKey(JWTAudience)
+	conf.AddKnownKey(JWTRolesClaim, "roles")
+	conf.AddKnownKey(JWTNamespacesClaim, "namespaces")
+	conf.AddKnownKey(JWTClockSkew, "30s")
+}
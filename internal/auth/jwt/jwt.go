@@ -0,0 +1,306 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	authHeaderName         = "Authorization"
+	bearerAuthHeaderPrefix = "Bearer "
+)
+
+// Auth authenticates callers with a JSON Web Token, either signed with a shared secret, or issued by an OIDC
+// provider and verified against its published keys. The claims of the token declare the role of the caller,
+// and the namespaces it is valid for.
+type Auth struct {
+	hmacSecret      []byte
+	jwksURL         string
+	jwksClient      *resty.Client
+	jwksRefresh     time.Duration
+	keysMux         sync.Mutex
+	rsaKeys         map[string]*rsa.PublicKey
+	keysLoaded      time.Time
+	issuer          string
+	audience        string
+	rolesClaim      string
+	namespacesClaim string
+	clockSkew       time.Duration
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+func Name() string {
+	return "jwt"
+}
+
+func (a *Auth) Name() string {
+	return Name()
+}
+
+func (a *Auth) Init(ctx context.Context, name string, conf config.Section) error {
+	a.hmacSecret = []byte(conf.GetString(JWTHMACSecret))
+	a.issuer = conf.GetString(JWTIssuer)
+	a.audience = conf.GetString(JWTAudience)
+	a.rolesClaim = conf.GetString(JWTRolesClaim)
+	a.namespacesClaim = conf.GetString(JWTNamespacesClaim)
+	a.clockSkew = conf.GetDuration(JWTClockSkew)
+	a.jwksURL = conf.GetString(JWTJWKSURL)
+	if a.jwksURL != "" {
+		a.jwksRefresh = conf.GetDuration(JWTJWKSRefreshInterval)
+		a.jwksClient = ffresty.NewWithConfig(ctx, ffresty.Config{
+			URL: a.jwksURL,
+			HTTPConfig: ffresty.HTTPConfig{
+				HTTPRequestTimeout: fftypes.FFDuration(conf.GetDuration(JWTJWKSRequestTimeout)),
+			},
+		})
+		if err := a.loadJWKS(ctx); err != nil {
+			return err
+		}
+	} else if len(a.hmacSecret) == 0 {
+		return i18n.NewError(ctx, coremsgs.MsgJWTKeyConfigMissing, name)
+	}
+	log.L(ctx).Infof("JWT auth plugin enabled (name=%s)", name)
+	return nil
+}
+
+// loadJWKS fetches the keys published by the OIDC provider, replacing any that were loaded before
+func (a *Auth) loadJWKS(ctx context.Context) error {
+	var jwks jsonWebKeySet
+	res, err := a.jwksClient.R().SetContext(ctx).Get("")
+	if err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgJWKSFetchFailed, a.jwksURL, err)
+	}
+	if res.StatusCode() != http.StatusOK {
+		return i18n.NewError(ctx, coremsgs.MsgJWKSFetchFailed, a.jwksURL, res.Status())
+	}
+	if err := json.Unmarshal(res.Body(), &jwks); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgJWKSFetchFailed, a.jwksURL, err)
+	}
+	rsaKeys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.KeyType != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil {
+			log.L(ctx).Warnf("Ignoring invalid RSA key '%s' in JSON Web Key Set", jwk.KeyID)
+			continue
+		}
+		rsaKeys[jwk.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	a.rsaKeys = rsaKeys
+	a.keysLoaded = time.Now()
+	return nil
+}
+
+// rsaKey returns the key with the ID. A key that is not known causes the key set to be fetched again, as the
+// provider may have rotated its keys - but no more often than the refresh interval, so that tokens with made
+// up key IDs cannot be used to flood the provider with requests.
+func (a *Auth) rsaKey(ctx context.Context, keyID string) (*rsa.PublicKey, bool) {
+	a.keysMux.Lock()
+	defer a.keysMux.Unlock()
+	key, ok := a.rsaKeys[keyID]
+	if !ok && a.jwksClient != nil && time.Since(a.keysLoaded) >= a.jwksRefresh {
+		log.L(ctx).Infof("Reloading JSON Web Key Set for unknown key '%s'", keyID)
+		if err := a.loadJWKS(ctx); err != nil {
+			log.L(ctx).Warnf("%s", err)
+			a.keysLoaded = time.Now() // the next attempt also waits for the refresh interval
+		}
+		key, ok = a.rsaKeys[keyID]
+	}
+	return key, ok
+}
+
+func (a *Auth) Authorize(ctx context.Context, req *fftypes.AuthReq) error {
+	authHeader := req.Header.Get(authHeaderName)
+	if !strings.HasPrefix(authHeader, bearerAuthHeaderPrefix) {
+		return i18n.NewError(ctx, i18n.MsgUnauthorized)
+	}
+	claims, err := a.verifyToken(ctx, strings.TrimPrefix(authHeader, bearerAuthHeaderPrefix))
+	if err != nil {
+		log.L(ctx).Warnf("JWT authentication failed: %s", err)
+		return i18n.NewError(ctx, i18n.MsgUnauthorized)
+	}
+	grant := &core.APIGrant{
+		Role:       highestRole(claims[a.rolesClaim]),
+		Namespaces: claimStrings(claims[a.namespacesClaim]),
+	}
+	if err := grant.Authorize(ctx, req); err != nil {
+		return err
+	}
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		core.SetAuthenticatedIdentity(ctx, Name()+":"+sub)
+	}
+	return nil
+}
+
+func (a *Auth) verifyToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "malformed token")
+	}
+	var header tokenHeader
+	if err := decodeSegment(ctx, parts[0], &header); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, err)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	// The algorithm is only trusted to pick between the verifications that are configured - each uses its
+	// own key, so a token cannot choose to be verified with no signature, or with a public key as a secret
+	switch header.Algorithm {
+	case "HS256":
+		if len(a.hmacSecret) == 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "HS256 is not enabled")
+		}
+		mac := hmac.New(sha256.New, a.hmacSecret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "invalid signature")
+		}
+	case "RS256":
+		key, ok := a.rsaKey(ctx, header.KeyID)
+		if !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "unknown key")
+		}
+		hash := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, err)
+		}
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "unsupported algorithm")
+	}
+	var claims map[string]interface{}
+	if err := decodeSegment(ctx, parts[1], &claims); err != nil {
+		return nil, err
+	}
+	return claims, a.verifyClaims(ctx, claims)
+}
+
+// verifyClaims checks the times in the token allowing for the clock skew, so a token is accepted for up to
+// the skew after it expires, and from up to the skew before it is valid or was issued
+func (a *Auth) verifyClaims(ctx context.Context, claims map[string]interface{}) error {
+	now := float64(time.Now().Unix())
+	skew := a.clockSkew.Seconds()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "token has no expiry")
+	}
+	if now >= exp+skew {
+		return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now+skew < nbf {
+		return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "token not yet valid")
+	}
+	if iat, ok := claims["iat"].(float64); ok && now+skew < iat {
+		return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "token issued in the future")
+	}
+	if a.issuer != "" && claims["iss"] != a.issuer {
+		return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "wrong issuer")
+	}
+	if a.audience != "" {
+		found := false
+		for _, aud := range claimStrings(claims["aud"]) {
+			found = found || aud == a.audience
+		}
+		if !found {
+			return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, "wrong audience")
+		}
+	}
+	return nil
+}
+
+// highestRole returns the highest of the valid roles in the claim, ignoring any roles that are not API roles
+func highestRole(claim interface{}) (role core.APIRole) {
+	for _, r := range claimStrings(claim) {
+		if candidate := core.APIRole(r); candidate.Grants(role) && !role.Grants(candidate) {
+			role = candidate
+		}
+	}
+	return role
+}
+
+// claimStrings returns a claim that can be a single string, or a list of strings, as a list
+func claimStrings(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		strs := make([]string, 0, len(v))
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+func decodeSegment(ctx context.Context, segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgJWTInvalid, err)
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "secret"
+
+func newTestAuth(t *testing.T, settings map[string]string) (*Auth, error) {
+	coreconfig.Reset()
+	conf := config.RootSection("jwt")
+	a := &Auth{}
+	a.InitConfig(conf)
+	for k, v := range settings {
+		conf.Set(k, v)
+	}
+	return a, a.Init(context.Background(), "test", conf)
+}
+
+func encodeSegment(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func hmacToken(header, claims interface{}) string {
+	return hmacTokenWithSecret([]byte(testSecret), header, claims)
+}
+
+func hmacTokenWithSecret(secret []byte, header, claims interface{}) string {
+	signed := encodeSegment(header) + "." + encodeSegment(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func rsaToken(key *rsa.PrivateKey, kid string, claims interface{}) string {
+	signed := encodeSegment(map[string]string{"alg": "RS256", "kid": kid}) + "." + encodeSegment(claims)
+	hash := sha256.Sum256([]byte(signed))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func authReq(token, namespace string) *fftypes.AuthReq {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	return &fftypes.AuthReq{Method: http.MethodGet, Header: header, Namespace: namespace}
+}
+
+func TestAuthorizeHMACOk(t *testing.T) {
+	a, err := newTestAuth(t, map[string]string{
+		JWTHMACSecret: testSecret,
+		JWTIssuer:     "issuer1",
+		JWTAudience:   "firefly",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt", a.Name())
+
+	hs256 := map[string]string{"alg": "HS256"}
+	token := hmacToken(hs256, map[string]interface{}{
+		"iss":        "issuer1",
+		"aud":        []string{"other", "firefly"},
+		"exp":        time.Now().Add(1 * time.Hour).Unix(),
+		"nbf":        time.Now().Add(-1 * time.Hour).Unix(),
+		"roles":      []interface{}{"reader", "unknown", "submitter", 12345},
+		"namespaces": "ns1",
+		"sub":        "user1",
+	})
+	ctx, caller := core.WithAuthenticatedCaller(core.WithRequiredAPIRole(context.Background(), core.APIRoleSubmitter))
+	assert.NoError(t, a.Authorize(ctx, authReq(token, "ns1")))
+	assert.Equal(t, "jwt:user1", caller.Identity)
+	assert.Regexp(t, "FF10568", a.Authorize(ctx, authReq(token, "ns2")))
+	assert.Regexp(t, "FF10567", a.Authorize(core.WithRequiredAPIRole(ctx, core.APIRoleAdmin), authReq(token, "ns1")))
+
+	token = hmacToken(hs256, map[string]interface{}{"iss": "issuer1", "aud": "firefly", "exp": time.Now().Add(1 * time.Hour).Unix()})
+	assert.Regexp(t, "FF10567", a.Authorize(ctx, authReq(token, "ns1")))
+}
+
+func TestAuthorizeHMACInvalid(t *testing.T) {
+	a, err := newTestAuth(t, map[string]string{JWTHMACSecret: testSecret, JWTIssuer: "issuer1", JWTAudience: "firefly"})
+	assert.NoError(t, err)
+	ctx := context.Background()
+	hs256 := map[string]string{"alg": "HS256"}
+	exp := time.Now().Add(1 * time.Hour).Unix()
+	valid := map[string]interface{}{"iss": "issuer1", "aud": "firefly", "exp": exp, "roles": "admin"}
+	assert.NoError(t, a.Authorize(ctx, authReq(hmacToken(hs256, valid), "ns1")))
+
+	tokens := []string{
+		"",
+		"not.a.jwt.token",
+		"!!!.claims.signature",
+		encodeSegment(hs256) + "." + encodeSegment(valid) + ".!!!",
+		encodeSegment(hs256) + "." + encodeSegment(valid) + "." + encodeSegment("wrong"),
+		hmacToken(map[string]string{"alg": "none"}, valid),
+		hmacToken(map[string]string{"alg": "RS256"}, valid),
+		hmacToken(hs256, "not an object"),
+		hmacToken(hs256, map[string]interface{}{"iss": "issuer1", "aud": "firefly", "roles": "admin"}),
+		hmacToken(hs256, map[string]interface{}{"iss": "issuer1", "aud": "firefly", "exp": time.Now().Add(-1 * time.Hour).Unix()}),
+		hmacToken(hs256, map[string]interface{}{"iss": "issuer1", "aud": "firefly", "exp": exp, "nbf": time.Now().Add(1 * time.Hour).Unix()}),
+		hmacToken(hs256, map[string]interface{}{"iss": "issuer2", "aud": "firefly", "exp": exp}),
+		hmacToken(hs256, map[string]interface{}{"iss": "issuer1", "aud": "other", "exp": exp}),
+	}
+	for i, token := range tokens {
+		assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(token, "ns1")), fmt.Sprintf("token %d", i))
+	}
+}
+
+func TestVerifyTokenAlgNoneRejected(t *testing.T) {
+	a, err := newTestAuth(t, map[string]string{JWTHMACSecret: testSecret})
+	assert.NoError(t, err)
+	claims := map[string]interface{}{"roles": "admin", "exp": time.Now().Add(1 * time.Hour).Unix()}
+
+	for _, alg := range []string{"none", "None", "NONE", ""} {
+		unsigned := encodeSegment(map[string]string{"alg": alg}) + "." + encodeSegment(claims) + "."
+		_, err := a.verifyToken(context.Background(), unsigned)
+		assert.Regexp(t, "FF10572.*unsupported algorithm", err, alg)
+		_, err = a.verifyToken(context.Background(), hmacToken(map[string]string{"alg": alg}, claims))
+		assert.Regexp(t, "FF10572.*unsupported algorithm", err, alg)
+	}
+}
+
+func TestVerifyTokenAlgConfusionRejected(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	server, _ := newTestJWKSServer(func() []map[string]string { return []map[string]string{jwk("key1", key)} })
+	defer server.Close()
+
+	// An HS256 token using the public key of the provider as the HMAC secret, in each form it could be known
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKeys := [][]byte{
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+		der,
+		key.N.Bytes(),
+	}
+	claims := map[string]interface{}{"roles": "admin", "exp": time.Now().Add(1 * time.Hour).Unix()}
+	hs256 := map[string]string{"alg": "HS256", "kid": "key1"}
+
+	a, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL})
+	assert.NoError(t, err)
+	for _, publicKey := range publicKeys {
+		_, err := a.verifyToken(context.Background(), hmacTokenWithSecret(publicKey, hs256, claims))
+		assert.Regexp(t, "FF10572.*HS256 is not enabled", err)
+	}
+
+	// With a secret configured as well, HS256 tokens are only verified with the secret
+	a, err = newTestAuth(t, map[string]string{JWTJWKSURL: server.URL, JWTHMACSecret: testSecret})
+	assert.NoError(t, err)
+	for _, publicKey := range publicKeys {
+		_, err := a.verifyToken(context.Background(), hmacTokenWithSecret(publicKey, hs256, claims))
+		assert.Regexp(t, "FF10572.*invalid signature", err)
+	}
+	_, err = a.verifyToken(context.Background(), rsaToken(key, "key1", claims))
+	assert.NoError(t, err)
+}
+
+func TestVerifyClaimsClockSkew(t *testing.T) {
+	a, err := newTestAuth(t, map[string]string{JWTHMACSecret: testSecret})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, a.clockSkew)
+	ctx := context.Background()
+	at := func(offset time.Duration) float64 { return float64(time.Now().Add(offset).Unix()) }
+	exp := at(1 * time.Hour)
+
+	// Within the skew
+	assert.NoError(t, a.verifyClaims(ctx, map[string]interface{}{"exp": at(-10 * time.Second)}))
+	assert.NoError(t, a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "nbf": at(10 * time.Second)}))
+	assert.NoError(t, a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "iat": at(10 * time.Second)}))
+
+	// Beyond the skew
+	assert.Regexp(t, "FF10572.*token expired", a.verifyClaims(ctx, map[string]interface{}{"exp": at(-1 * time.Minute)}))
+	assert.Regexp(t, "FF10572.*token not yet valid", a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "nbf": at(1 * time.Minute)}))
+	assert.Regexp(t, "FF10572.*token issued in the future", a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "iat": at(1 * time.Minute)}))
+
+	// With no skew
+	a, err = newTestAuth(t, map[string]string{JWTHMACSecret: testSecret, JWTClockSkew: "0"})
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10572.*token expired", a.verifyClaims(ctx, map[string]interface{}{"exp": at(-10 * time.Second)}))
+	assert.Regexp(t, "FF10572.*token not yet valid", a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "nbf": at(10 * time.Second)}))
+	assert.Regexp(t, "FF10572.*token issued in the future", a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "iat": at(10 * time.Second)}))
+	assert.NoError(t, a.verifyClaims(ctx, map[string]interface{}{"exp": exp, "nbf": at(-1 * time.Second), "iat": at(-1 * time.Second)}))
+}
+
+func jwk(kid string, key *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func newTestJWKSServer(keys func() []map[string]string) (*httptest.Server, *int) {
+	fetches := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys()})
+	})), &fetches
+}
+
+func TestAuthorizeJWKSOk(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	server, _ := newTestJWKSServer(func() []map[string]string {
+		return []map[string]string{
+			{"kty": "EC", "kid": "ec1"},
+			{"kty": "RSA", "kid": "bad", "n": "!!!", "e": "AQAB"},
+			jwk("key1", key),
+		}
+	})
+	defer server.Close()
+
+	a, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL})
+	assert.NoError(t, err)
+
+	ctx := core.WithRequiredAPIRole(context.Background(), core.APIRoleAdmin)
+	claims := map[string]interface{}{"roles": "admin", "exp": time.Now().Add(1 * time.Hour).Unix()}
+	assert.NoError(t, a.Authorize(ctx, authReq(rsaToken(key, "key1", claims), "ns1")))
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(rsaToken(key, "unknown", claims), "ns1")))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(rsaToken(otherKey, "key1", claims), "ns1")))
+
+	// HS256 is not enabled without a secret
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(hmacToken(map[string]string{"alg": "HS256"}, claims), "ns1")))
+}
+
+func TestAuthorizeJWKSRefetchUnknownKey(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	keys := []map[string]string{jwk("key1", key1)}
+	server, fetches := newTestJWKSServer(func() []map[string]string { return keys })
+	defer server.Close()
+
+	a, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL, JWTJWKSRefreshInterval: "0"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *fetches)
+
+	// The provider rotates its keys
+	keys = []map[string]string{jwk("key2", key2)}
+	ctx := core.WithRequiredAPIRole(context.Background(), core.APIRoleAdmin)
+	claims := map[string]interface{}{"roles": "admin", "exp": time.Now().Add(1 * time.Hour).Unix()}
+	assert.NoError(t, a.Authorize(ctx, authReq(rsaToken(key2, "key2", claims), "ns1")))
+	assert.Equal(t, 2, *fetches)
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(rsaToken(key1, "key1", claims), "ns1")))
+	assert.Equal(t, 3, *fetches)
+}
+
+func TestAuthorizeJWKSRefetchRateLimited(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	server, fetches := newTestJWKSServer(func() []map[string]string { return []map[string]string{jwk("key1", key)} })
+	defer server.Close()
+
+	a, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL})
+	assert.NoError(t, err)
+
+	ctx := core.WithRequiredAPIRole(context.Background(), core.APIRoleAdmin)
+	claims := map[string]interface{}{"roles": "admin", "exp": time.Now().Add(1 * time.Hour).Unix()}
+	for i := 0; i < 5; i++ {
+		assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(rsaToken(key, "unknown", claims), "ns1")))
+	}
+	assert.Equal(t, 1, *fetches)
+}
+
+func TestAuthorizeJWKSRefetchFailKeepsKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{jwk("key1", key)}})
+	}))
+	defer server.Close()
+
+	a, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL, JWTJWKSRefreshInterval: "0"})
+	assert.NoError(t, err)
+
+	fail = true
+	ctx := core.WithRequiredAPIRole(context.Background(), core.APIRoleAdmin)
+	claims := map[string]interface{}{"roles": "admin", "exp": time.Now().Add(1 * time.Hour).Unix()}
+	assert.Regexp(t, "FF00169", a.Authorize(ctx, authReq(rsaToken(key, "unknown", claims), "ns1")))
+	assert.NoError(t, a.Authorize(ctx, authReq(rsaToken(key, "key1", claims), "ns1")))
+}
+
+func TestInitJWKSTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	_, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL, JWTJWKSRequestTimeout: "10ms"})
+	assert.Regexp(t, "FF10571", err)
+}
+
+func TestInitMissingKeyConfig(t *testing.T) {
+	_, err := newTestAuth(t, map[string]string{})
+	assert.Regexp(t, "FF10570", err)
+}
+
+func TestInitJWKSBadURL(t *testing.T) {
+	_, err := newTestAuth(t, map[string]string{JWTJWKSURL: "://bad"})
+	assert.Regexp(t, "FF10571", err)
+}
+
+func TestInitJWKSUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	_, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL})
+	assert.Regexp(t, "FF10571", err)
+}
+
+func TestInitJWKSNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	_, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL})
+	assert.Regexp(t, "FF10571.*404", err)
+}
+
+func TestInitJWKSBadJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer server.Close()
+	_, err := newTestAuth(t, map[string]string{JWTJWKSURL: server.URL})
+	assert.Regexp(t, "FF10571", err)
+}
@@ -44,10 +44,16 @@ func NewBatchManager(ctx context.Context, ns string, di database.Plugin, dm data
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "BatchManager")
 	}
 	pCtx, cancelCtx := context.WithCancel(log.WithLogField(ctx, "role", "batchmgr"))
+	sequencerCtx, cancelSequencer := context.WithCancel(pCtx)
+	processorCtx, cancelProcessors := context.WithCancel(pCtx)
 	readPageSize := config.GetUint(coreconfig.BatchManagerReadPageSize)
 	bm := &batchManager{
 		ctx:                        pCtx,
 		cancelCtx:                  cancelCtx,
+		sequencerCtx:               sequencerCtx,
+		cancelSequencer:            cancelSequencer,
+		processorCtx:               processorCtx,
+		cancelProcessors:           cancelProcessors,
 		namespace:                  ns,
 		identity:                   im,
 		database:                   di,
@@ -57,6 +63,8 @@ func NewBatchManager(ctx context.Context, ns string, di database.Plugin, dm data
 		readPageSize:               uint64(readPageSize),
 		minimumPollDelay:           config.GetDuration(coreconfig.BatchManagerMinimumPollDelay),
 		messagePollTimeout:         config.GetDuration(coreconfig.BatchManagerReadPollTimeout),
+		flushStatsWindow:           config.GetDuration(coreconfig.BatchManagerFlushStatsWindow),
+		schedulePollInterval:       config.GetDuration(coreconfig.BatchManagerSchedulePollInterval),
 		startupOffsetRetryAttempts: config.GetInt(coreconfig.OrchestratorStartupAttempts),
 		dispatcherMap:              make(map[string]*dispatcher),
 		allDispatchers:             make([]*dispatcher, 0),
@@ -65,6 +73,8 @@ func NewBatchManager(ctx context.Context, ns string, di database.Plugin, dm data
 		shoulderTap:                make(chan bool, 1),
 		rewindOffset:               -1,
 		done:                       make(chan struct{}),
+		statusWatchers:             make(map[chan struct{}]bool),
+		adaptiveDefaults:           adaptiveConfigDefaults(),
 		retry: &retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.BatchRetryInitDelay),
 			MaximumDelay: config.GetDuration(coreconfig.BatchRetryMaxDelay),
@@ -78,26 +88,68 @@ type Manager interface {
 	RegisterDispatcher(name string, pinned bool, msgTypes []core.MessageType, handler DispatchHandler, batchOptions DispatcherOptions)
 	LoadContexts(ctx context.Context, payload *DispatchPayload) error
 	CancelBatch(ctx context.Context, batchID string) error
+	Flush(ctx context.Context) ([]*fftypes.UUID, error)
+	Restart(ctx context.Context) (*RestartResult, error)
 	NewMessages() chan<- int64
 	Start() error
 	Close()
 	WaitStop()
 	Status() *ManagerStatus
+	PreviewBatch(ctx context.Context, msg *core.Message, sizeEstimate int64) (*BatchPreview, error)
+	WatchStatus() (changes <-chan struct{}, cancel func())
+	GetConfig() []*DispatcherConfig
+	UpdateConfig(ctx context.Context, update *ConfigUpdate) ([]*DispatcherConfig, error)
 }
 
 type ManagerStatus struct {
-	Processors []*ProcessorStatus `ffstruct:"BatchManagerStatus" json:"processors"`
+	Processors  []*ProcessorStatus  `ffstruct:"BatchManagerStatus" json:"processors"`
+	Dispatchers []*DispatcherStatus `ffstruct:"BatchManagerStatus" json:"dispatchers"`
+}
+
+type DispatcherStatus struct {
+	Name            string `ffstruct:"BatchDispatcherStatus" json:"name"`
+	Processors      int    `ffstruct:"BatchDispatcherStatus" json:"processors"`
+	PendingMessages int    `ffstruct:"BatchDispatcherStatus" json:"pendingMessages"`
+	PendingBytes    int64  `ffstruct:"BatchDispatcherStatus" json:"pendingBytes"`
 }
 
 type ProcessorStatus struct {
-	Dispatcher string      `ffstruct:"BatchProcessorStatus" json:"dispatcher"`
-	Name       string      `ffstruct:"BatchProcessorStatus" json:"name"`
-	Status     FlushStatus `ffstruct:"BatchProcessorStatus" json:"status"`
+	Dispatcher string              `ffstruct:"BatchProcessorStatus" json:"dispatcher"`
+	Name       string              `ffstruct:"BatchProcessorStatus" json:"name"`
+	Status     FlushStatus         `ffstruct:"BatchProcessorStatus" json:"status"`
+	Backlog    ProcessorBacklog    `ffstruct:"BatchProcessorStatus" json:"backlog"`
+	Thresholds ProcessorThresholds `ffstruct:"BatchProcessorStatus" json:"thresholds"`
+}
+
+type RestartResult struct {
+	Stopped   int `ffstruct:"BatchManagerRestart" json:"stopped"`
+	Cancelled int `ffstruct:"BatchManagerRestart" json:"cancelled"`
+}
+
+type ProcessorBacklog struct {
+	PendingMessages          int   `ffstruct:"BatchProcessorBacklog" json:"pendingMessages"`
+	PendingBytes             int64 `ffstruct:"BatchProcessorBacklog" json:"pendingBytes"`
+	TimeSinceLastFlushMS     int64 `ffstruct:"BatchProcessorBacklog" json:"timeSinceLastFlushMS"`
+	RecentFlushes            int   `ffstruct:"BatchProcessorBacklog" json:"recentFlushes"`
+	RecentAverageFlushTimeMS int64 `ffstruct:"BatchProcessorBacklog" json:"recentAverageFlushTimeMS"`
+	WindowMS                 int64 `ffstruct:"BatchProcessorBacklog" json:"windowMS"`
+}
+
+type ProcessorThresholds struct {
+	MaxMessages uint  `ffstruct:"BatchProcessorThresholds" json:"maxMessages"`
+	MaxBytes    int64 `ffstruct:"BatchProcessorThresholds" json:"maxBytes"`
+	TimeoutMS   int64 `ffstruct:"BatchProcessorThresholds" json:"timeoutMS"`
 }
 
 type batchManager struct {
 	ctx                        context.Context
 	cancelCtx                  func()
+	sequencerCtx               context.Context
+	cancelSequencer            func()
+	processorCtx               context.Context // protected by dispatcherMux
+	cancelProcessors           func()
+	restartMux                 sync.Mutex
+	doneMux                    sync.Mutex
 	namespace                  string
 	identity                   identity.Manager
 	database                   database.Plugin
@@ -119,7 +171,12 @@ type batchManager struct {
 	readPageSize               uint64
 	minimumPollDelay           time.Duration
 	messagePollTimeout         time.Duration
+	flushStatsWindow           time.Duration
+	schedulePollInterval       time.Duration
 	startupOffsetRetryAttempts int
+	statusWatchMux             sync.Mutex
+	statusWatchers             map[chan struct{}]bool
+	adaptiveDefaults           AdaptiveConfig
 }
 
 type DispatchHandler func(context.Context, *DispatchPayload) error
@@ -133,10 +190,13 @@ type DispatcherOptions struct {
 }
 
 type dispatcher struct {
-	name       string
-	handler    DispatchHandler
-	processors map[string]*batchProcessor
-	options    DispatcherOptions
+	name            string
+	handler         DispatchHandler
+	processors      map[string]*batchProcessor
+	options         DispatcherOptions // the thresholds can be updated at runtime, under the dispatcherMux
+	maxPayloadLimit int64
+	adaptive        AdaptiveConfig
+	adaptiveStats   adaptiveStats
 }
 
 func (bm *batchManager) getProcessorKey(author string, groupID *fftypes.Bytes32) string {
@@ -156,10 +216,12 @@ func (bm *batchManager) RegisterDispatcher(name string, pinned bool, msgTypes []
 	defer bm.dispatcherMux.Unlock()
 
 	dispatcher := &dispatcher{
-		name:       name,
-		handler:    handler,
-		options:    options,
-		processors: make(map[string]*batchProcessor),
+		name:            name,
+		handler:         handler,
+		options:         options,
+		maxPayloadLimit: options.BatchMaxBytes,
+		adaptive:        bm.adaptiveDefaults,
+		processors:      make(map[string]*batchProcessor),
 	}
 	bm.allDispatchers = append(bm.allDispatchers, dispatcher)
 	for _, msgType := range msgTypes {
@@ -171,6 +233,7 @@ func (bm *batchManager) Start() error {
 	go bm.messageSequencer()
 	// We must be always ready to process DB events, or we block commits. So we have a dedicated worker for that
 	go bm.newMessageNotifier()
+	go bm.scheduledMessageReleaser()
 	return nil
 }
 
@@ -213,8 +276,8 @@ func (bm *batchManager) getProcessor(txType core.TransactionType, msgType core.M
 
 func (bm *batchManager) assembleMessageData(id *fftypes.UUID) (msg *core.Message, retData core.DataArray, err error) {
 	var foundAll = false
-	err = bm.retry.Do(bm.ctx, "retrieve message", func(attempt int) (retry bool, err error) {
-		msg, retData, foundAll, err = bm.data.GetMessageWithDataCached(bm.ctx, id)
+	err = bm.retry.Do(bm.sequencerCtx, "retrieve message", func(attempt int) (retry bool, err error) {
+		msg, retData, foundAll, err = bm.data.GetMessageWithDataCached(bm.sequencerCtx, id)
 		// continual retry for persistence error (distinct from not-found)
 		return true, err
 	})
@@ -278,9 +341,9 @@ func (bm *batchManager) readPage(lastPageFull bool) ([]*core.IDAndSequence, bool
 
 	// Read a page from the DB
 	var ids []*core.IDAndSequence
-	err := bm.retry.Do(bm.ctx, "retrieve messages", func(attempt int) (retry bool, err error) {
-		fb := database.MessageQueryFactory.NewFilterLimit(bm.ctx, bm.readPageSize)
-		ids, err = bm.database.GetMessageIDs(bm.ctx, bm.namespace, fb.And(
+	err := bm.retry.Do(bm.sequencerCtx, "retrieve messages", func(attempt int) (retry bool, err error) {
+		fb := database.MessageQueryFactory.NewFilterLimit(bm.sequencerCtx, bm.readPageSize)
+		ids, err = bm.database.GetMessageIDs(bm.sequencerCtx, bm.namespace, fb.And(
 			fb.Gt("sequence", bm.readOffset),
 			fb.Eq("state", core.MessageStateReady),
 		).Sort("sequence").Limit(bm.readPageSize))
@@ -327,6 +390,11 @@ func (bm *batchManager) messageSequencer() {
 				// the database store. Meaning we cannot rely on the sequence having been set.
 				msg.Sequence = entry.Sequence
 
+				if err := bm.rekeyToLatestGroup(bm.ctx, msg); err != nil {
+					l.Errorf("Failed to resolve latest group generation for message %s: %s", msg.Header.ID, err)
+					continue
+				}
+
 				processor, err := bm.getProcessor(msg.Header.TxType, msg.Header.Type, msg.Header.Group, msg.Header.SignerRef.Author, true)
 				if err != nil {
 					l.Errorf("Failed to dispatch message %s: %s", msg.Header.ID, err)
@@ -351,6 +419,44 @@ func (bm *batchManager) messageSequencer() {
 	}
 }
 
+// rekeyToLatestGroup moves a private message that has not yet been assigned pins onto the latest generation of
+// its group, so that messages staged or queued while the members of the group were changed are sent to (and
+// their nonces allocated in) the new generation. Messages already sealed into a batch keep their generation.
+func (bm *batchManager) rekeyToLatestGroup(ctx context.Context, msg *core.Message) error {
+	if msg.Header.Group == nil || len(msg.Pins) > 0 || msg.Header.Type == core.MessageTypeGroupInit {
+		return nil
+	}
+	latest := msg.Header.Group
+	visited := map[fftypes.Bytes32]bool{*latest: true}
+	for {
+		fb := database.GroupQueryFactory.NewFilterLimit(ctx, 1)
+		next, _, err := bm.database.GetGroups(ctx, bm.namespace, fb.And(fb.Eq("previous", latest)))
+		if err != nil {
+			return err
+		}
+		if len(next) == 0 || visited[*next[0].Hash] {
+			break
+		}
+		latest = next[0].Hash
+		visited[*latest] = true
+	}
+	if latest.Equals(msg.Header.Group) {
+		return nil
+	}
+
+	log.L(ctx).Infof("Moving message %s from group %s to latest generation %s", msg.Header.ID, msg.Header.Group, latest)
+	msg.Header.Group = latest
+	msg.Hash = msg.Header.Hash()
+	update := database.MessageQueryFactory.NewUpdate(ctx).
+		Set("group", msg.Header.Group).
+		Set("hash", msg.Hash)
+	if err := bm.database.UpdateMessage(ctx, bm.namespace, msg.Header.ID, update); err != nil {
+		return err
+	}
+	bm.data.UpdateMessageIfCached(ctx, msg)
+	return nil
+}
+
 func (bm *batchManager) newMessageNotification(seq int64) {
 	rewindToQueue := int64(-1)
 
@@ -399,7 +505,7 @@ func (bm *batchManager) waitForNewMessages() (done bool) {
 	case <-timeout.C:
 		l.Debugf("Woken after poll timeout")
 		return false
-	case <-bm.ctx.Done():
+	case <-bm.sequencerCtx.Done():
 		l.Debugf("Exiting due to cancelled context")
 		return true
 	}
@@ -417,7 +523,20 @@ func (bm *batchManager) dispatchMessage(processor *batchProcessor, msg *core.Mes
 		msg:  msg,
 		data: data,
 	}
-	processor.newWork <- work
+	select {
+	case processor.newWork <- work:
+	default:
+		// The processor has a full batch queued behind the one it is flushing, so we are
+		// about to block the sequencer until it catches up
+		l.Debugf("Batch processor %s applying backpressure", processor.conf.name)
+		bm.notifyStatusChange()
+		select {
+		case processor.newWork <- work:
+		case <-bm.sequencerCtx.Done():
+			// We are stopping, and the message is still ready in the database to be read again on restart
+			l.Debugf("Message %s not dispatched as sequencer is stopping", msg.Header.ID)
+		}
+	}
 }
 
 func (bm *batchManager) reapQuiescing() {
@@ -460,13 +579,56 @@ func (bm *batchManager) getProcessors() []*batchProcessor {
 }
 
 func (bm *batchManager) Status() *ManagerStatus {
-	processors := bm.getProcessors()
-	pStatus := make([]*ProcessorStatus, len(processors))
-	for i, p := range processors {
-		pStatus[i] = p.status()
+	bm.dispatcherMux.Lock()
+	dispatchers := make([]*DispatcherStatus, len(bm.allDispatchers))
+	processors := make([][]*batchProcessor, len(bm.allDispatchers))
+	for i, d := range bm.allDispatchers {
+		dispatchers[i] = &DispatcherStatus{Name: d.name}
+		for _, p := range d.processors {
+			processors[i] = append(processors[i], p)
+		}
+	}
+	bm.dispatcherMux.Unlock()
+
+	pStatus := make([]*ProcessorStatus, 0)
+	for i, dStatus := range dispatchers {
+		for _, p := range processors[i] {
+			ps := p.status()
+			dStatus.Processors++
+			dStatus.PendingMessages += ps.Backlog.PendingMessages
+			dStatus.PendingBytes += ps.Backlog.PendingBytes
+			pStatus = append(pStatus, ps)
+		}
 	}
 	return &ManagerStatus{
-		Processors: pStatus,
+		Processors:  pStatus,
+		Dispatchers: dispatchers,
+	}
+}
+
+// WatchStatus registers for notification of significant changes in the batch manager state,
+// such as a batch being sealed, or a processor applying backpressure. Notifications are coalesced,
+// so a slow watcher only sees one notification for multiple changes.
+func (bm *batchManager) WatchStatus() (<-chan struct{}, func()) {
+	changes := make(chan struct{}, 1)
+	bm.statusWatchMux.Lock()
+	bm.statusWatchers[changes] = true
+	bm.statusWatchMux.Unlock()
+	return changes, func() {
+		bm.statusWatchMux.Lock()
+		delete(bm.statusWatchers, changes)
+		bm.statusWatchMux.Unlock()
+	}
+}
+
+func (bm *batchManager) notifyStatusChange() {
+	bm.statusWatchMux.Lock()
+	defer bm.statusWatchMux.Unlock()
+	for changes := range bm.statusWatchers {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
 	}
 }
 
@@ -475,7 +637,10 @@ func (bm *batchManager) Close() {
 }
 
 func (bm *batchManager) WaitStop() {
-	<-bm.done
+	bm.doneMux.Lock()
+	done := bm.done
+	bm.doneMux.Unlock()
+	<-done
 	processors := bm.getProcessors()
 	for _, p := range processors {
 		<-p.done
@@ -600,6 +765,108 @@ func (bm *batchManager) LoadContexts(ctx context.Context, payload *DispatchPaylo
 	return nil
 }
 
+// Flush forces every active processor to seal and dispatch its in-flight assembly, returning the
+// IDs of the batches that were flushed. Processors are flushed in parallel, and each gives up
+// waiting if the context is cancelled, so a blocked dispatcher cannot hold up the caller forever.
+func (bm *batchManager) Flush(ctx context.Context) ([]*fftypes.UUID, error) {
+	processors := bm.getProcessors()
+	results := make(chan *flushResult, len(processors))
+	for _, p := range processors {
+		go func(p *batchProcessor) {
+			results <- p.requestFlush(ctx)
+		}(p)
+	}
+	var firstErr error
+	flushed := make([]*fftypes.UUID, 0)
+	for range processors {
+		res := <-results
+		if res.err != nil && firstErr == nil {
+			firstErr = res.err
+		}
+		if res.id != nil {
+			flushed = append(flushed, res.id)
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return flushed, nil
+}
+
+// Restart stops the message sequencer and every batch processor, then starts reading again from the
+// beginning of the messages that are ready in the database. It is the persisted state of messages, rather
+// than the in-memory assemblies, that determines what is batched after the restart - a message is only
+// moved out of the ready state once the batch containing it has been dispatched.
+//
+// Each processor is allowed to complete any flush in progress before it stops, so that a batch is not
+// dispatched a second time. Processors still flushing when the context ends (such as those blocked retrying
+// a failing dispatch) are cancelled, and their messages are batched again after the restart.
+func (bm *batchManager) Restart(ctx context.Context) (*RestartResult, error) {
+	bm.restartMux.Lock()
+	defer bm.restartMux.Unlock()
+
+	// Stop the sequencer first, so no more work is dispatched to the processors
+	bm.cancelSequencer()
+	bm.doneMux.Lock()
+	done := bm.done
+	bm.doneMux.Unlock()
+	select {
+	case <-done:
+	case <-bm.ctx.Done():
+		return nil, i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+	}
+
+	processors := bm.getProcessors()
+	stopped := make(chan bool, len(processors))
+	for _, p := range processors {
+		go func(p *batchProcessor) {
+			stopped <- p.requestStop(ctx)
+		}(p)
+	}
+	result := &RestartResult{}
+	for range processors {
+		if <-stopped {
+			result.Stopped++
+		} else {
+			result.Cancelled++
+		}
+	}
+	if result.Cancelled > 0 {
+		log.L(ctx).Warnf("Cancelling %d batch processors that did not stop before the restart timeout", result.Cancelled)
+	}
+
+	bm.dispatcherMux.Lock()
+	bm.cancelProcessors()
+	bm.processorCtx, bm.cancelProcessors = context.WithCancel(bm.ctx)
+	for _, d := range bm.allDispatchers {
+		d.processors = make(map[string]*batchProcessor)
+	}
+	bm.dispatcherMux.Unlock()
+	for _, p := range processors {
+		<-p.done
+	}
+
+	// Nothing is in-flight now, so we can safely trawl for all ready messages again
+	bm.inflightMux.Lock()
+	bm.inflightSequences = make(map[int64]*batchProcessor)
+	bm.inflightFlushed = nil
+	bm.inflightMux.Unlock()
+	bm.rewindOffsetMux.Lock()
+	bm.rewindOffset = -1
+	bm.rewindOffsetMux.Unlock()
+	bm.readOffset = -1
+
+	bm.doneMux.Lock()
+	bm.sequencerCtx, bm.cancelSequencer = context.WithCancel(bm.ctx)
+	bm.done = make(chan struct{})
+	bm.doneMux.Unlock()
+	go bm.messageSequencer()
+
+	log.L(ctx).Infof("Batch manager restarted: stopped=%d cancelled=%d", result.Stopped, result.Cancelled)
+	bm.notifyStatusChange()
+	return result, nil
+}
+
 func (bm *batchManager) CancelBatch(ctx context.Context, batchID string) error {
 	id, err := fftypes.ParseUUID(ctx, batchID)
 	if err != nil {
@@ -42,6 +42,7 @@ import (
 func testConfigReset() {
 	coreconfig.Reset()
 	config.Set(coreconfig.BatchManagerMinimumPollDelay, "0")
+	config.Set(coreconfig.BatchManagerSchedulePollInterval, "1h")
 	log.SetLevel("debug")
 }
 
@@ -254,6 +255,7 @@ func TestE2EDispatchPrivateUnpinned(t *testing.T) {
 	mdm.On("UpdateMessageIfCached", mock.Anything, mock.Anything).Return()
 	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{{ID: *msg.Header.ID}}, nil).Once()
 	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil)
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
 	mdi.On("UpdateMessage", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil) // pins
 	mdi.On("InsertOrGetBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	mdi.On("UpdateBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -356,7 +358,7 @@ func TestMessageSequencerCancelledContext(t *testing.T) {
 	defer bm.Close()
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
-	bm.(*batchManager).ctx = ctx
+	bm.(*batchManager).sequencerCtx = ctx
 	bm.(*batchManager).messageSequencer()
 	assert.Equal(t, 1, len(mdi.Calls))
 }
@@ -654,6 +656,113 @@ func TestDoubleTap(t *testing.T) {
 	}
 }
 
+func TestMessageSequencerRekeyFail(t *testing.T) {
+	bm, _ := newTestBatchManager(t)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      core.MessageTypePrivate,
+			Namespace: "ns1",
+			Group:     fftypes.NewRandB32(),
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).
+		Return([]*core.IDAndSequence{{ID: *msg.Header.ID}}, nil, nil).
+		Run(func(args mock.Arguments) {
+			bm.Close()
+		}).
+		Once()
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil, nil)
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdm.On("GetMessageWithDataCached", mock.Anything, mock.Anything).Return(msg, core.DataArray{}, true, nil)
+
+	bm.messageSequencer()
+	bm.WaitStop()
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestRekeyToLatestGroup(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	group1 := fftypes.NewRandB32()
+	group2 := &core.Group{Hash: fftypes.NewRandB32(), Previous: group1}
+	group3 := &core.Group{Hash: fftypes.NewRandB32(), Previous: group2.Hash}
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypePrivate,
+			Group: group1,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdm := bm.data.(*datamocks.Manager)
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return([]*core.Group{group2}, nil, nil).Once()
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return([]*core.Group{group3}, nil, nil).Once()
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return([]*core.Group{group2}, nil, nil).Once() // cycle
+	mdi.On("UpdateMessage", mock.Anything, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	mdm.On("UpdateMessageIfCached", mock.Anything, msg).Return()
+
+	err := bm.rekeyToLatestGroup(bm.ctx, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, group3.Hash, msg.Header.Group)
+	assert.Equal(t, msg.Header.Hash(), msg.Hash)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestRekeyToLatestGroupUpdateFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	group1 := fftypes.NewRandB32()
+	group2 := &core.Group{Hash: fftypes.NewRandB32(), Previous: group1}
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypePrivate,
+			Group: group1,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return([]*core.Group{group2}, nil, nil).Once()
+	mdi.On("GetGroups", mock.Anything, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil).Once()
+	mdi.On("UpdateMessage", mock.Anything, "ns1", msg.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := bm.rekeyToLatestGroup(bm.ctx, msg)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRekeyToLatestGroupPinned(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	group1 := fftypes.NewRandB32()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypePrivate,
+			Group: group1,
+		},
+		Pins: fftypes.FFStringArray{fftypes.NewRandB32().String()},
+	}
+
+	err := bm.rekeyToLatestGroup(bm.ctx, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, group1, msg.Header.Group)
+}
+
 func TestLoadContextsBroadcast(t *testing.T) {
 	bm, cancel := newTestBatchManager(t)
 	defer cancel()
@@ -1017,3 +1126,98 @@ func TestCancelBatch(t *testing.T) {
 	mdi.AssertExpectations(t)
 	mdm.AssertExpectations(t)
 }
+
+func TestWatchStatus(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	changes, cancelWatch := bm.WatchStatus()
+	bm.notifyStatusChange()
+	bm.notifyStatusChange() // coalesced
+	<-changes
+	select {
+	case <-changes:
+		assert.Fail(t, "notifications should be coalesced")
+	default:
+	}
+
+	cancelWatch()
+	assert.Empty(t, bm.statusWatchers)
+	bm.notifyStatusChange()
+	select {
+	case <-changes:
+		assert.Fail(t, "notification after cancel")
+	default:
+	}
+}
+
+func TestDispatchMessageBackpressure(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bp := &batchProcessor{
+		conf:    &batchProcessorConf{name: "test"},
+		newWork: make(chan *batchWork),
+	}
+	changes, cancelWatch := bm.WatchStatus()
+	defer cancelWatch()
+
+	received := make(chan *batchWork)
+	go func() {
+		<-changes
+		received <- <-bp.newWork
+	}()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Sequence: 12345}
+	bm.dispatchMessage(bp, msg, core.DataArray{})
+	work := <-received
+	assert.Equal(t, msg, work.msg)
+}
+
+func TestDispatchMessageBackpressureSequencerStopping(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bp := &batchProcessor{
+		conf:    &batchProcessorConf{name: "test"},
+		newWork: make(chan *batchWork),
+	}
+	bm.cancelSequencer()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Sequence: 12345}
+	bm.dispatchMessage(bp, msg, core.DataArray{})
+	assert.Empty(t, bp.newWork)
+}
+
+func TestStatusDispatcherBacklog(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bm.RegisterDispatcher("utdispatcher", true, []core.MessageType{core.MessageTypePrivate},
+		func(c context.Context, state *DispatchPayload) error {
+			return nil
+		},
+		DispatcherOptions{BatchType: core.BatchTypePrivate, BatchMaxSize: 10, BatchMaxBytes: 2048, BatchTimeout: 5 * time.Second},
+	)
+	bm.RegisterDispatcher("idledispatcher", true, []core.MessageType{core.MessageTypeBroadcast},
+		func(c context.Context, state *DispatchPayload) error {
+			return nil
+		},
+		DispatcherOptions{BatchType: core.BatchTypeBroadcast},
+	)
+	bp, err := bm.getProcessor(core.TransactionTypeBatchPin, core.MessageTypePrivate, fftypes.NewRandB32(), "did:firefly:org/abcd", true)
+	assert.NoError(t, err)
+	bp.statusMux.Lock()
+	bp.assembly.messages = 3
+	bp.assembly.bytes = 1024
+	bp.statusMux.Unlock()
+
+	status := bm.Status()
+	assert.Len(t, status.Processors, 1)
+	assert.Equal(t, 3, status.Processors[0].Backlog.PendingMessages)
+	assert.Equal(t, ProcessorThresholds{MaxMessages: 10, MaxBytes: 2048, TimeoutMS: 5000}, status.Processors[0].Thresholds)
+	assert.Equal(t, []*DispatcherStatus{
+		{Name: "utdispatcher", Processors: 1, PendingMessages: 3, PendingBytes: 1024},
+		{Name: "idledispatcher"},
+	}, status.Dispatchers)
+}
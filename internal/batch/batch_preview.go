@@ -0,0 +1,117 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// BatchPreview describes the batch a message would be assigned to, if it were submitted now
+type BatchPreview struct {
+	Dispatcher         string             `ffstruct:"BatchPreview" json:"dispatcher"`
+	Processor          string             `ffstruct:"BatchPreview" json:"processor"`
+	BatchID            *fftypes.UUID      `ffstruct:"BatchPreview" json:"batchId,omitempty"`
+	NewBatch           bool               `ffstruct:"BatchPreview" json:"newBatch"`
+	Messages           int                `ffstruct:"BatchPreview" json:"messages"`
+	MaxMessages        uint               `ffstruct:"BatchPreview" json:"maxMessages"`
+	Bytes              int64              `ffstruct:"BatchPreview" json:"bytes"`
+	MaxBytes           int64              `ffstruct:"BatchPreview" json:"maxBytes"`
+	FillPercent        float64            `ffstruct:"BatchPreview" json:"fillPercent"`
+	SealsImmediately   bool               `ffstruct:"BatchPreview" json:"sealsImmediately"`
+	EstimatedSealDelay fftypes.FFDuration `ffstruct:"BatchPreview" json:"estimatedSealDelay"`
+	EstimatedSealTime  *fftypes.FFTime    `ffstruct:"BatchPreview" json:"estimatedSealTime"`
+}
+
+// PreviewBatch reports which in-flight batch assembly the message would join, based on a read-only
+// view of the current processor state. The sizeEstimate must include the data of the message.
+func (bm *batchManager) PreviewBatch(ctx context.Context, msg *core.Message, sizeEstimate int64) (*BatchPreview, error) {
+	bm.dispatcherMux.Lock()
+	dispatcherKey := bm.getDispatcherKey(core.IsPinned(msg.Header.TxType), msg.Header.Type)
+	dispatcher, ok := bm.dispatcherMap[dispatcherKey]
+	var processor *batchProcessor
+	var options DispatcherOptions
+	name := bm.getProcessorKey(msg.Header.Author, msg.Header.Group)
+	if ok {
+		processor = dispatcher.processors[name]
+		options = dispatcher.options
+	}
+	bm.dispatcherMux.Unlock()
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnregisteredBatchType, dispatcherKey)
+	}
+
+	assembly := assemblySnapshot{bytes: batchSizeEstimateBase}
+	if processor != nil {
+		assembly = processor.assemblySnapshot()
+	}
+	preview := &BatchPreview{
+		Dispatcher:  dispatcher.name,
+		Processor:   name,
+		MaxMessages: options.BatchMaxSize,
+		MaxBytes:    options.BatchMaxBytes,
+	}
+
+	// Mirror the decisions made by the processor when it adds new work to the assembly
+	batchOfOne := msg.Header.TxType == core.TransactionTypeContractInvokePin
+	incompatible := assembly.messages > 0 &&
+		(msg.Header.TxType != assembly.txType || msg.Header.Key != assembly.key)
+	preview.Messages = assembly.messages + 1
+	preview.Bytes = assembly.bytes + sizeEstimate
+	overflow := preview.Messages > 1 && preview.Bytes > options.BatchMaxBytes
+	if batchOfOne || incompatible || overflow || assembly.messages == 0 {
+		preview.NewBatch = true
+		preview.Messages = 1
+		preview.Bytes = batchSizeEstimateBase + sizeEstimate
+	} else {
+		preview.BatchID = assembly.id
+	}
+	preview.SealsImmediately = batchOfOne ||
+		preview.Messages >= int(options.BatchMaxSize) ||
+		preview.Bytes >= options.BatchMaxBytes
+
+	preview.FillPercent = float64(preview.Messages) / float64(options.BatchMaxSize) * 100
+	if bytesFill := float64(preview.Bytes) / float64(options.BatchMaxBytes) * 100; bytesFill > preview.FillPercent {
+		preview.FillPercent = bytesFill
+	}
+	if preview.FillPercent > 100 {
+		preview.FillPercent = 100
+	}
+
+	now := time.Now()
+	var delay time.Duration
+	switch {
+	case preview.SealsImmediately:
+		delay = 0
+	case !preview.NewBatch && assembly.started != nil:
+		delay = time.Time(*assembly.started).Add(options.BatchTimeout).Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+	default:
+		delay = options.BatchTimeout
+	}
+	preview.EstimatedSealDelay = fftypes.FFDuration(delay)
+	sealTime := fftypes.FFTime(now.Add(delay))
+	preview.EstimatedSealTime = &sealTime
+	return preview, nil
+}
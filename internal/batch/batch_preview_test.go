@@ -0,0 +1,203 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPreviewBatchManager(t *testing.T) (*batchManager, func()) {
+	bm, cancel := newTestBatchManager(t)
+	bm.RegisterDispatcher("utdispatcher", true, []core.MessageType{core.MessageTypeBroadcast}, nil, DispatcherOptions{
+		BatchType:      core.BatchTypeBroadcast,
+		BatchMaxSize:   3,
+		BatchMaxBytes:  10000,
+		BatchTimeout:   time.Minute,
+		DisposeTimeout: time.Hour,
+	})
+	return bm, cancel
+}
+
+func newTestPreviewMessage(txType core.TransactionType) *core.Message {
+	return &core.Message{
+		Header: core.MessageHeader{
+			Type:   core.MessageTypeBroadcast,
+			TxType: txType,
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/abcd",
+				Key:    "0x12345",
+			},
+		},
+	}
+}
+
+func setTestAssembly(t *testing.T, bm *batchManager, msg *core.Message, assembly assemblySnapshot) {
+	bp, err := bm.getProcessor(msg.Header.TxType, msg.Header.Type, msg.Header.Group, msg.Header.Author, true)
+	assert.NoError(t, err)
+	bp.statusMux.Lock()
+	bp.assembly = assembly
+	bp.statusMux.Unlock()
+}
+
+func TestPreviewBatchUnregistered(t *testing.T) {
+	bm, cancel := newTestPreviewBatchManager(t)
+	defer cancel()
+
+	msg := newTestPreviewMessage(core.TransactionTypeBatchPin)
+	msg.Header.Type = core.MessageTypePrivate
+	_, err := bm.PreviewBatch(context.Background(), msg, 100)
+	assert.Regexp(t, "FF10126", err)
+}
+
+func TestPreviewBatchNoProcessor(t *testing.T) {
+	bm, cancel := newTestPreviewBatchManager(t)
+	defer cancel()
+
+	preview, err := bm.PreviewBatch(context.Background(), newTestPreviewMessage(core.TransactionTypeBatchPin), 488)
+	assert.NoError(t, err)
+	assert.Equal(t, "utdispatcher", preview.Dispatcher)
+	assert.Equal(t, "did:firefly:org/abcd|", preview.Processor)
+	assert.True(t, preview.NewBatch)
+	assert.Nil(t, preview.BatchID)
+	assert.Equal(t, 1, preview.Messages)
+	assert.Equal(t, int64(1000), preview.Bytes)
+	assert.InDelta(t, 33.33, preview.FillPercent, 0.01)
+	assert.False(t, preview.SealsImmediately)
+	assert.Equal(t, fftypes.FFDuration(time.Minute), preview.EstimatedSealDelay)
+	assert.NotNil(t, preview.EstimatedSealTime)
+
+	preview, err = bm.PreviewBatch(context.Background(), newTestPreviewMessage(core.TransactionTypeBatchPin), 20000)
+	assert.NoError(t, err)
+	assert.True(t, preview.NewBatch)
+	assert.Equal(t, float64(100), preview.FillPercent)
+	assert.True(t, preview.SealsImmediately)
+}
+
+func TestPreviewBatchJoinOpen(t *testing.T) {
+	bm, cancel := newTestPreviewBatchManager(t)
+	defer cancel()
+
+	msg := newTestPreviewMessage(core.TransactionTypeBatchPin)
+	assemblyID := fftypes.NewUUID()
+	started := fftypes.FFTime(time.Now().Add(-30 * time.Second))
+	setTestAssembly(t, bm, msg, assemblySnapshot{
+		id:       assemblyID,
+		messages: 1,
+		bytes:    1000,
+		started:  &started,
+		txType:   core.TransactionTypeBatchPin,
+		key:      "0x12345",
+	})
+
+	preview, err := bm.PreviewBatch(context.Background(), msg, 4000)
+	assert.NoError(t, err)
+	assert.False(t, preview.NewBatch)
+	assert.Equal(t, assemblyID, preview.BatchID)
+	assert.Equal(t, 2, preview.Messages)
+	assert.Equal(t, int64(5000), preview.Bytes)
+	assert.InDelta(t, 66.67, preview.FillPercent, 0.01)
+	assert.False(t, preview.SealsImmediately)
+	assert.LessOrEqual(t, time.Duration(preview.EstimatedSealDelay), 30*time.Second)
+	assert.Greater(t, time.Duration(preview.EstimatedSealDelay), time.Duration(0))
+}
+
+func TestPreviewBatchJoinFillsExpired(t *testing.T) {
+	bm, cancel := newTestPreviewBatchManager(t)
+	defer cancel()
+
+	msg := newTestPreviewMessage(core.TransactionTypeBatchPin)
+	started := fftypes.FFTime(time.Now().Add(-2 * time.Minute))
+	setTestAssembly(t, bm, msg, assemblySnapshot{
+		id:       fftypes.NewUUID(),
+		messages: 2,
+		bytes:    1000,
+		started:  &started,
+		txType:   core.TransactionTypeBatchPin,
+		key:      "0x12345",
+	})
+	preview, err := bm.PreviewBatch(context.Background(), msg, 100)
+	assert.NoError(t, err)
+	assert.False(t, preview.NewBatch)
+	assert.True(t, preview.SealsImmediately)
+	assert.Equal(t, float64(100), preview.FillPercent)
+	assert.Equal(t, fftypes.FFDuration(0), preview.EstimatedSealDelay)
+
+	// Timer already popped on the assembly
+	setTestAssembly(t, bm, msg, assemblySnapshot{
+		id:       fftypes.NewUUID(),
+		messages: 1,
+		bytes:    1000,
+		started:  &started,
+		txType:   core.TransactionTypeBatchPin,
+		key:      "0x12345",
+	})
+	preview, err = bm.PreviewBatch(context.Background(), msg, 100)
+	assert.NoError(t, err)
+	assert.False(t, preview.SealsImmediately)
+	assert.Equal(t, fftypes.FFDuration(0), preview.EstimatedSealDelay)
+}
+
+func TestPreviewBatchNewBatchCases(t *testing.T) {
+	bm, cancel := newTestPreviewBatchManager(t)
+	defer cancel()
+
+	msg := newTestPreviewMessage(core.TransactionTypeBatchPin)
+	started := fftypes.Now()
+	setTestAssembly(t, bm, msg, assemblySnapshot{
+		id:       fftypes.NewUUID(),
+		messages: 1,
+		bytes:    9000,
+		started:  started,
+		txType:   core.TransactionTypeBatchPin,
+		key:      "0x12345",
+	})
+
+	// Overflows the bytes of the open batch
+	preview, err := bm.PreviewBatch(context.Background(), msg, 2000)
+	assert.NoError(t, err)
+	assert.True(t, preview.NewBatch)
+	assert.Equal(t, 1, preview.Messages)
+	assert.Equal(t, int64(2512), preview.Bytes)
+	assert.InDelta(t, 33.33, preview.FillPercent, 0.01)
+
+	// Bytes dominate the fill level
+	preview, err = bm.PreviewBatch(context.Background(), msg, 6000)
+	assert.NoError(t, err)
+	assert.True(t, preview.NewBatch)
+	assert.InDelta(t, 65.12, preview.FillPercent, 0.01)
+
+	// Different signing key
+	msg.Header.Key = "0x67890"
+	preview, err = bm.PreviewBatch(context.Background(), msg, 100)
+	assert.NoError(t, err)
+	assert.True(t, preview.NewBatch)
+	assert.False(t, preview.SealsImmediately)
+
+	// Batch of one
+	msg.Header.TxType = core.TransactionTypeContractInvokePin
+	preview, err = bm.PreviewBatch(context.Background(), msg, 100)
+	assert.NoError(t, err)
+	assert.True(t, preview.NewBatch)
+	assert.True(t, preview.SealsImmediately)
+	assert.Equal(t, fftypes.FFDuration(0), preview.EstimatedSealDelay)
+}
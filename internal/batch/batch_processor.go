@@ -30,9 +30,11 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/operations"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type batchWork struct {
@@ -82,15 +84,47 @@ type batchProcessor struct {
 	done               chan struct{}
 	quiescing          chan bool
 	newWork            chan *batchWork
+	flushRequests      chan *flushRequest
 	assemblyID         *fftypes.UUID
 	assemblyQueue      []*batchWork
 	assemblyQueueBytes int64
 	statusMux          sync.Mutex
 	flushStatus        FlushStatus
+	recentFlushes      []flushRecord
+	assembly           assemblySnapshot
 	retry              *retry.Retry
 	conf               *batchProcessorConf
 }
 
+// assemblySnapshot is a copy of the state of the in-flight assembly, protected by the statusMux,
+// so that it can be read without interrupting the assembly loop
+type assemblySnapshot struct {
+	id       *fftypes.UUID
+	messages int
+	bytes    int64
+	started  *fftypes.FFTime
+	txType   core.TransactionType
+	key      string
+}
+
+// flushRecord is kept for each completed flush within the flush statistics window
+type flushRecord struct {
+	completed time.Time
+	duration  time.Duration
+}
+
+// flushRequest is passed into the assembly loop to force an immediate flush of the in-flight assembly
+type flushRequest struct {
+	stop   bool // exit the assembly loop, without flushing
+	result chan *flushResult
+}
+
+// flushResult contains the ID of the batch that was flushed, which is nil if there was nothing to flush
+type flushResult struct {
+	id  *fftypes.UUID
+	err error
+}
+
 type nonceState struct {
 	latest int64
 	new    bool
@@ -131,7 +165,7 @@ func (dp *DispatchPayload) addMessageUpdate(messages []*core.Message, fromState
 const batchSizeEstimateBase = int64(512)
 
 func newBatchProcessor(bm *batchManager, conf *batchProcessorConf, baseRetryConf *retry.Retry, txHelper txcommon.Helper) *batchProcessor {
-	pCtx := log.WithLogField(log.WithLogField(bm.ctx, "d", conf.dispatcherName), "p", conf.name)
+	pCtx := log.WithLogField(log.WithLogField(bm.processorCtx, "d", conf.dispatcherName), "p", conf.name)
 	pCtx, cancelCtx := context.WithCancel(pCtx)
 	bp := &batchProcessor{
 		ctx:       pCtx,
@@ -143,6 +177,8 @@ func newBatchProcessor(bm *batchManager, conf *batchProcessorConf, baseRetryConf
 		newWork:   make(chan *batchWork, conf.BatchMaxSize),
 		quiescing: make(chan bool, 1),
 		done:      make(chan struct{}),
+
+		flushRequests: make(chan *flushRequest),
 		retry: &retry.Retry{
 			InitialDelay: baseRetryConf.InitialDelay,
 			MaximumDelay: baseRetryConf.MaximumDelay,
@@ -156,6 +192,7 @@ func newBatchProcessor(bm *batchManager, conf *batchProcessorConf, baseRetryConf
 	// Capture flush errors for our status
 	bp.retry.ErrCallback = bp.captureFlushError
 	bp.newAssembly()
+	bp.updateAssemblySnapshot()
 	go bp.assemblyLoop()
 	log.L(pCtx).Infof("Batch processor created")
 	return bp
@@ -172,10 +209,88 @@ func (bw *batchWork) estimateSize() int64 {
 func (bp *batchProcessor) status() *ProcessorStatus {
 	bp.statusMux.Lock()
 	defer bp.statusMux.Unlock()
+	now := time.Now()
+	bp.pruneRecentFlushes(now)
+	backlog := ProcessorBacklog{
+		PendingMessages:      bp.assembly.messages,
+		TimeSinceLastFlushMS: now.Sub(*bp.flushStatus.LastFlushTime.Time()).Milliseconds(),
+		RecentFlushes:        len(bp.recentFlushes),
+		WindowMS:             bp.bm.flushStatsWindow.Milliseconds(),
+	}
+	if backlog.PendingMessages > 0 {
+		backlog.PendingBytes = bp.assembly.bytes
+	}
+	if len(bp.recentFlushes) > 0 {
+		var totalDuration time.Duration
+		for _, fr := range bp.recentFlushes {
+			totalDuration += fr.duration
+		}
+		backlog.RecentAverageFlushTimeMS = (totalDuration / time.Duration(len(bp.recentFlushes))).Milliseconds()
+	}
 	return &ProcessorStatus{
 		Dispatcher: bp.conf.dispatcherName,
 		Name:       bp.conf.name,
 		Status:     bp.flushStatus, // copy
+		Backlog:    backlog,
+		Thresholds: ProcessorThresholds{
+			MaxMessages: bp.conf.BatchMaxSize,
+			MaxBytes:    bp.conf.BatchMaxBytes,
+			TimeoutMS:   bp.conf.BatchTimeout.Milliseconds(),
+		},
+	}
+}
+
+// thresholds returns the size, payload limit and timeout at which the assembly is flushed, which can be updated at runtime
+func (bp *batchProcessor) thresholds() (maxSize uint, maxBytes int64, timeout time.Duration) {
+	bp.statusMux.Lock()
+	defer bp.statusMux.Unlock()
+	return bp.conf.BatchMaxSize, bp.conf.BatchMaxBytes, bp.conf.BatchTimeout
+}
+
+func (bp *batchProcessor) batchTimeout() time.Duration {
+	_, _, timeout := bp.thresholds()
+	return timeout
+}
+
+func (bp *batchProcessor) setThresholds(options DispatcherOptions) {
+	bp.statusMux.Lock()
+	defer bp.statusMux.Unlock()
+	bp.conf.BatchMaxSize = options.BatchMaxSize
+	bp.conf.BatchMaxBytes = options.BatchMaxBytes
+	bp.conf.BatchTimeout = options.BatchTimeout
+}
+
+// pruneRecentFlushes must be called with the statusMux held
+func (bp *batchProcessor) pruneRecentFlushes(now time.Time) {
+	cutoff := now.Add(-bp.bm.flushStatsWindow)
+	i := 0
+	for i < len(bp.recentFlushes) && bp.recentFlushes[i].completed.Before(cutoff) {
+		i++
+	}
+	bp.recentFlushes = bp.recentFlushes[i:]
+}
+
+func (bp *batchProcessor) assemblySnapshot() assemblySnapshot {
+	bp.statusMux.Lock()
+	defer bp.statusMux.Unlock()
+	return bp.assembly
+}
+
+// updateAssemblySnapshot must be called with the statusMux held (or before the assembly loop starts)
+func (bp *batchProcessor) updateAssemblySnapshot() {
+	prevStarted := bp.assembly.started
+	bp.assembly = assemblySnapshot{
+		id:       bp.assemblyID,
+		messages: len(bp.assemblyQueue),
+		bytes:    bp.assemblyQueueBytes,
+	}
+	if len(bp.assemblyQueue) > 0 {
+		bp.assembly.txType = bp.assemblyQueue[0].msg.Header.TxType
+		bp.assembly.key = bp.assemblyQueue[0].msg.Header.Key
+		bp.assembly.started = prevStarted
+		if prevStarted == nil {
+			bp.assembly.started = fftypes.Now()
+		}
 	}
 }
 
@@ -228,10 +343,15 @@ func (bp *batchProcessor) addWork(newWork *batchWork) (full, overflow bool) {
 		bp.assemblyQueueBytes += newWork.estimateSize()
 		bp.assemblyQueue = newQueue
 
-		full = len(bp.assemblyQueue) >= int(bp.conf.BatchMaxSize) || bp.assemblyQueueBytes >= bp.conf.BatchMaxBytes
-		overflow = len(bp.assemblyQueue) > 1 && (batchOfOne || bp.assemblyQueueBytes > bp.conf.BatchMaxBytes)
+		maxSize, maxBytes, _ := bp.thresholds()
+		full = len(bp.assemblyQueue) >= int(maxSize) || bp.assemblyQueueBytes >= maxBytes
+		overflow = len(bp.assemblyQueue) > 1 && (batchOfOne || bp.assemblyQueueBytes > maxBytes)
 	}
 
+	bp.statusMux.Lock()
+	bp.updateAssemblySnapshot()
+	bp.statusMux.Unlock()
+
 	log.L(bp.ctx).Debugf("Added message %s sequence=%d to in-flight batch assembly %s", newWork.msg.Header.ID, newWork.msg.Sequence, bp.assemblyID)
 	return full, overflow
 }
@@ -255,6 +375,8 @@ func (bp *batchProcessor) startFlush(overflow bool) (id *fftypes.UUID, flushAsse
 	byteSize = bp.assemblyQueueBytes
 	bp.flushStatus.Flushing = id
 	bp.newAssembly(overflowWork...)
+	bp.assembly.started = nil
+	bp.updateAssemblySnapshot()
 	return id, flushAssembly, byteSize
 }
 
@@ -266,7 +388,7 @@ func (bp *batchProcessor) notifyFlushComplete(flushWork []*batchWork) {
 	bp.bm.notifyFlushed(sequences)
 }
 
-func (bp *batchProcessor) updateFlushStats(payload *DispatchPayload, byteSize int64) {
+func (bp *batchProcessor) updateFlushStats(payload *DispatchPayload, byteSize int64) time.Duration {
 	bp.statusMux.Lock()
 	defer bp.statusMux.Unlock()
 	fs := &bp.flushStatus
@@ -279,6 +401,9 @@ func (bp *batchProcessor) updateFlushStats(payload *DispatchPayload, byteSize in
 	fs.TotalBatches++
 
 	fs.totalFlushDuration += duration
+	now := time.Now()
+	bp.recentFlushes = append(bp.recentFlushes, flushRecord{completed: now, duration: duration})
+	bp.pruneRecentFlushes(now)
 	fs.AverageFlushTimeMS = (fs.totalFlushDuration / time.Duration(fs.TotalBatches)).Milliseconds()
 
 	fs.totalBytesFlushed += byteSize
@@ -289,17 +414,20 @@ func (bp *batchProcessor) updateFlushStats(payload *DispatchPayload, byteSize in
 
 	fs.totalDataFlushed += int64(len(payload.Data))
 	fs.AverageBatchData = math.Round((float64(fs.totalDataFlushed)/float64(fs.TotalBatches))*100) / 100
+	return duration
 }
 
 func (bp *batchProcessor) captureFlushError(err error) {
 	bp.statusMux.Lock()
-	defer bp.statusMux.Unlock()
 	fs := &bp.flushStatus
 
 	fs.TotalErrors++
 	fs.Blocked = true
 	fs.LastFlushErrorTime = fftypes.Now()
 	fs.LastFlushError = err.Error()
+	bp.statusMux.Unlock()
+
+	bp.bm.notifyStatusChange()
 }
 
 func (bp *batchProcessor) cancelFlush(ctx context.Context, id *fftypes.UUID) error {
@@ -321,6 +449,41 @@ func (bp *batchProcessor) isCancelled() bool {
 	return fs.Cancelled
 }
 
+// requestFlush asks the assembly loop to seal and dispatch the in-flight assembly, and waits for it to complete.
+// The result channel has a slot for the response, so the assembly loop never blocks if we give up waiting.
+func (bp *batchProcessor) requestFlush(ctx context.Context) *flushResult {
+	req := &flushRequest{result: make(chan *flushResult, 1)}
+	select {
+	case bp.flushRequests <- req:
+	case <-bp.done:
+		// The processor has already shut down, so there is nothing to flush
+		return &flushResult{}
+	case <-ctx.Done():
+		return &flushResult{err: i18n.NewError(ctx, coremsgs.MsgContextCanceled)}
+	}
+	select {
+	case res := <-req.result:
+		return res
+	case <-ctx.Done():
+		return &flushResult{err: i18n.NewError(ctx, coremsgs.MsgContextCanceled)}
+	}
+}
+
+// requestStop asks the assembly loop to exit, which it can only do once any flush in progress is complete.
+// Returns false if the context ended before the processor stopped.
+func (bp *batchProcessor) requestStop(ctx context.Context) bool {
+	req := &flushRequest{stop: true, result: make(chan *flushResult, 1)}
+	select {
+	case bp.flushRequests <- req:
+		<-bp.done
+		return true
+	case <-bp.done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func (bp *batchProcessor) startQuiesce() {
 	// We are ready to quiesce, but we can't safely close our input channel.
 	// We just do a non-blocking pass (queue length is 1) to the manager to
@@ -345,6 +508,7 @@ func (bp *batchProcessor) assemblyLoop() {
 	for !quiescing {
 
 		var timedout, full, overflow bool
+		var forced *flushRequest
 		select {
 		case <-bp.ctx.Done():
 			l.Tracef("Batch processor shutting down")
@@ -366,22 +530,38 @@ func (bp *batchProcessor) assemblyLoop() {
 				if idle {
 					// We've hit a message while we were idle - we now need to wait for the batch to time out.
 					_ = batchTimeout.Stop()
-					batchTimeout = time.NewTimer(bp.conf.BatchTimeout)
+					batchTimeout = time.NewTimer(bp.batchTimeout())
 					idle = false
 				}
 			}
+		case req := <-bp.flushRequests:
+			if req.stop {
+				// Any work in the assembly is discarded - the messages are still ready in the database
+				l.Debugf("Batch processor stopping with %d messages unflushed", len(bp.assemblyQueue))
+				_ = batchTimeout.Stop()
+				req.result <- &flushResult{}
+				return
+			}
+			if len(bp.assemblyQueue) == 0 {
+				req.result <- &flushResult{}
+			} else {
+				forced = req
+			}
 		}
-		if (full || timedout || quiescing) && len(bp.assemblyQueue) > 0 {
+		if (full || timedout || quiescing || forced != nil) && len(bp.assemblyQueue) > 0 {
 			// Let Go GC the old timer
 			_ = batchTimeout.Stop()
 
 			// If we are in overflow, start the clock for the next batch to start before we do the flush
 			// (even though we won't check it until after).
 			if overflow {
-				batchTimeout = time.NewTimer(bp.conf.BatchTimeout)
+				batchTimeout = time.NewTimer(bp.batchTimeout())
 			}
 
-			err := bp.flush(overflow)
+			id, err := bp.flush(overflow)
+			if forced != nil {
+				forced.result <- &flushResult{id: id, err: err}
+			}
 			if err != nil {
 				l.Warnf("Batch processor shutting down: %s", err)
 				_ = batchTimeout.Stop()
@@ -398,7 +578,7 @@ func (bp *batchProcessor) assemblyLoop() {
 	}
 }
 
-func (bp *batchProcessor) flush(overflow bool) error {
+func (bp *batchProcessor) flush(overflow bool) (*fftypes.UUID, error) {
 	id, flushWork, byteSize := bp.startFlush(overflow)
 
 	log.L(bp.ctx).Debugf("Flushing batch %s", id)
@@ -407,16 +587,17 @@ func (bp *batchProcessor) flush(overflow bool) error {
 	// Sealing phase: assigns persisted pins to messages, and finalizes the manifest
 	err := bp.sealBatch(state)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.L(bp.ctx).Debugf("Sealed batch %s", id)
+	bp.bm.notifyStatusChange()
 
 	// Dispatch phase: the heavy lifting work - calling plugins to do the hard work of the batch.
 	//   The dispatcher can update the state, such as appending to the BlobsPublished array,
 	//   to affect DB updates as part of the finalization phase.
 	err = bp.dispatchBatch(state)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.L(bp.ctx).Debugf("Dispatched batch %s", id)
 
@@ -424,16 +605,17 @@ func (bp *batchProcessor) flush(overflow bool) error {
 	//   are all tagged as part of this batch, and won't be included in any future batches.
 	err = bp.markPayloadDispatched(state)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.L(bp.ctx).Debugf("Finalized batch %s", id)
 
 	// Notify the manager that we've flushed these sequences
 	bp.notifyFlushComplete(flushWork)
 
-	// Update our stats
-	bp.updateFlushStats(state, byteSize)
-	return nil
+	// Update our stats, and the adaptive sizing of the batches of our dispatcher
+	duration := bp.updateFlushStats(state, byteSize)
+	bp.bm.recordFlush(bp.conf.dispatcherName, len(state.Messages), duration)
+	return id, nil
 }
 
 func (bp *batchProcessor) initPayload(id *fftypes.UUID, flushWork []*batchWork) *DispatchPayload {
@@ -616,9 +798,17 @@ func (bp *batchProcessor) sealBatch(payload *DispatchPayload) (err error) {
 	return nil
 }
 
-func (bp *batchProcessor) dispatchBatch(payload *DispatchPayload) error {
+func (bp *batchProcessor) dispatchBatch(payload *DispatchPayload) (err error) {
+	spanCtx, span := tracing.StartSpan(bp.ctx, "batch dispatch",
+		attribute.String("firefly.namespace", payload.Batch.Namespace),
+		attribute.String("firefly.batch.id", payload.Batch.ID.String()),
+		attribute.String("firefly.batch.type", string(payload.Batch.Type)),
+		attribute.Int("firefly.batch.messages", len(payload.Messages)),
+	)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// Call the dispatcher to do the heavy lifting - will only exit if we're closed
-	return operations.RunWithOperationContext(bp.ctx, func(ctx context.Context) error {
+	return operations.RunWithOperationContext(spanCtx, func(ctx context.Context) error {
 		return bp.retry.Do(ctx, "batch dispatch", func(attempt int) (retry bool, err error) {
 			err = bp.conf.dispatch(ctx, payload)
 			if err != nil {
@@ -19,6 +19,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1158,3 +1160,274 @@ func TestGapFillMessageFail(t *testing.T) {
 
 	mdm.AssertExpectations(t)
 }
+
+func TestCaptureFlushErrorNotifiesStatusChange(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+
+	changes, cancelWatch := bp.bm.WatchStatus()
+	defer cancelWatch()
+
+	bp.captureFlushError(fmt.Errorf("pop"))
+	<-changes
+	status := bp.status()
+	assert.True(t, status.Status.Blocked)
+	assert.Equal(t, "pop", status.Status.LastFlushError)
+}
+
+func TestStatusBacklogRecentFlushes(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+
+	now := time.Now()
+	bp.statusMux.Lock()
+	bp.bm.flushStatsWindow = 1 * time.Minute
+	lastFlush := fftypes.FFTime(now.Add(-2 * time.Second))
+	bp.flushStatus.LastFlushTime = &lastFlush
+	bp.recentFlushes = []flushRecord{
+		{completed: now.Add(-5 * time.Minute), duration: 5 * time.Second},
+		{completed: now.Add(-30 * time.Second), duration: 10 * time.Millisecond},
+		{completed: now.Add(-1 * time.Second), duration: 30 * time.Millisecond},
+	}
+	bp.statusMux.Unlock()
+
+	status := bp.status()
+	assert.Equal(t, 0, status.Backlog.PendingMessages)
+	assert.Equal(t, int64(0), status.Backlog.PendingBytes)
+	assert.GreaterOrEqual(t, status.Backlog.TimeSinceLastFlushMS, int64(2000))
+	assert.Equal(t, 2, status.Backlog.RecentFlushes)
+	assert.Equal(t, int64(20), status.Backlog.RecentAverageFlushTimeMS)
+	assert.Equal(t, int64(60000), status.Backlog.WindowMS)
+	assert.Equal(t, ProcessorThresholds{MaxMessages: 10, MaxBytes: 1024 * 1024, TimeoutMS: 100}, status.Thresholds)
+
+	bp.statusMux.Lock()
+	bp.flushStatus.LastFlushTime = fftypes.Now()
+	bp.statusMux.Unlock()
+	bp.updateFlushStats(&DispatchPayload{}, 100)
+	assert.Equal(t, 3, bp.status().Backlog.RecentFlushes)
+}
+
+func mockFlushSucceeds(bp *batchProcessor) {
+	mdi := bp.database.(*databasemocks.Plugin)
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertOrGetBatch", mock.Anything, mock.Anything).Return(nil, nil)
+	mth := bp.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeBatchPin, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mdm := bp.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", mock.Anything, mock.Anything).Return()
+	mim := bp.bm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", mock.Anything).Return(&core.Identity{}, nil)
+}
+
+func addTestWorkForFlush(t *testing.T, bp *batchProcessor) {
+	// Use a long batch timeout, so only a forced flush will dispatch the work
+	bp.conf.BatchTimeout = 100 * time.Second
+	bp.bm.allDispatchers = append(bp.bm.allDispatchers, &dispatcher{
+		name:       "test",
+		processors: map[string]*batchProcessor{"test": bp},
+	})
+	bp.newWork <- &batchWork{
+		msg: &core.Message{
+			Header: core.MessageHeader{
+				ID:     fftypes.NewUUID(),
+				TxType: core.TransactionTypeBatchPin,
+			},
+			Sequence: int64(1000)},
+	}
+	assert.Eventually(t, func() bool { return bp.assemblySnapshot().messages == 1 }, 5*time.Second, time.Millisecond)
+}
+
+func TestFlushForcesDispatch(t *testing.T) {
+	dispatched := make(chan *DispatchPayload, 1)
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		dispatched <- state
+		return nil
+	})
+	defer cancel()
+	mockFlushSucceeds(bp)
+	addTestWorkForFlush(t, bp)
+
+	ids, err := bp.bm.Flush(context.Background())
+	assert.NoError(t, err)
+	payload := <-dispatched
+	assert.Equal(t, []*fftypes.UUID{payload.Batch.ID}, ids)
+	assert.Equal(t, 1, len(payload.Messages))
+
+	// Nothing left pending
+	ids, err = bp.bm.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestFlushNoProcessors(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	ids, err := bm.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, ids)
+	assert.Empty(t, ids)
+}
+
+func TestFlushProcessorStopped(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	bp.bm.allDispatchers = append(bp.bm.allDispatchers, &dispatcher{
+		name:       "test",
+		processors: map[string]*batchProcessor{"test": bp},
+	})
+	bp.cancelCtx()
+	<-bp.done
+
+	ids, err := bp.bm.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestFlushContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		<-release
+		return nil
+	})
+	defer cancel()
+	mockFlushSucceeds(bp)
+	addTestWorkForFlush(t, bp)
+
+	// The flush request is accepted, but the dispatch is blocked
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelCtx()
+	_, err := bp.bm.Flush(ctx)
+	assert.Regexp(t, "FF00154", err)
+
+	// The assembly loop is still busy, so the flush request cannot be accepted
+	ctx, cancelCtx = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelCtx()
+	_, err = bp.bm.Flush(ctx)
+	assert.Regexp(t, "FF00154", err)
+
+	close(release)
+}
+
+func TestFlushDispatchFail(t *testing.T) {
+	dispatching := make(chan struct{})
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		close(dispatching)
+		<-c.Done()
+		return fmt.Errorf("pop")
+	})
+	defer cancel()
+	mockFlushSucceeds(bp)
+	addTestWorkForFlush(t, bp)
+
+	go func() {
+		<-dispatching
+		bp.cancelCtx()
+	}()
+	_, err := bp.bm.Flush(context.Background())
+	assert.Error(t, err)
+	<-bp.done
+}
+
+func TestRestartStopsProcessors(t *testing.T) {
+	testConfigReset()
+	cancel, mdi, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	addTestWorkForFlush(t, bp)
+	var readMux sync.Mutex
+	var lastRead string
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil).Run(func(args mock.Arguments) {
+		f, _ := args[2].(ffapi.Filter).Finalize()
+		readMux.Lock()
+		lastRead = f.String()
+		readMux.Unlock()
+	})
+	readFrom := func(offset string) func() bool {
+		return func() bool {
+			readMux.Lock()
+			defer readMux.Unlock()
+			return strings.HasPrefix(lastRead, "( sequence >> "+offset+" )")
+		}
+	}
+	bm := bp.bm
+	bm.readOffset = 1000
+	go bm.messageSequencer()
+	assert.Eventually(t, readFrom("1000"), 5*time.Second, time.Millisecond)
+
+	res, err := bm.Restart(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &RestartResult{Stopped: 1}, res)
+	<-bp.done
+	assert.Empty(t, bm.getProcessors())
+
+	// The sequencer has started again, reading from the beginning
+	assert.Eventually(t, readFrom("-1"), 5*time.Second, time.Millisecond)
+}
+
+func TestRestartCancelsBlockedProcessor(t *testing.T) {
+	testConfigReset()
+	dispatching := make(chan struct{})
+	cancel, mdi, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		close(dispatching)
+		<-c.Done()
+		return fmt.Errorf("pop")
+	})
+	defer cancel()
+	mockFlushSucceeds(bp)
+	addTestWorkForFlush(t, bp)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil)
+	bm := bp.bm
+	go bm.messageSequencer()
+
+	flushed := make(chan error)
+	go func() {
+		_, err := bm.Flush(context.Background())
+		flushed <- err
+	}()
+	<-dispatching
+
+	ctx, cancelCtx := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelCtx()
+	res, err := bm.Restart(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &RestartResult{Cancelled: 1}, res)
+	assert.Error(t, <-flushed)
+	<-bp.done
+}
+
+func TestRestartProcessorAlreadyStopped(t *testing.T) {
+	testConfigReset()
+	cancel, mdi, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	bp.bm.allDispatchers = append(bp.bm.allDispatchers, &dispatcher{
+		name:       "test",
+		processors: map[string]*batchProcessor{"test": bp},
+	})
+	bp.cancelCtx()
+	<-bp.done
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil)
+	go bp.bm.messageSequencer()
+
+	res, err := bp.bm.Restart(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &RestartResult{Stopped: 1}, res)
+}
+
+func TestRestartManagerClosed(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	cancel()
+
+	_, err := bm.Restart(context.Background())
+	assert.Regexp(t, "FF00154", err)
+}
@@ -0,0 +1,267 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	// adaptiveGrowFill is the average fill rate at or above which adaptive sizing grows the batch size
+	adaptiveGrowFill = 0.9
+	// adaptiveShrinkFill is the average fill rate below which adaptive sizing shrinks the batch size
+	adaptiveShrinkFill = 0.5
+)
+
+// DispatcherConfig is the batch assembly config currently in use by a dispatcher
+type DispatcherConfig struct {
+	Name         string             `ffstruct:"BatchDispatcherConfig" json:"name"`
+	BatchSize    uint               `ffstruct:"BatchDispatcherConfig" json:"batchSize"`
+	PayloadLimit int64              `ffstruct:"BatchDispatcherConfig" json:"payloadLimit"`
+	Timeout      fftypes.FFDuration `ffstruct:"BatchDispatcherConfig" json:"timeout"`
+	Adaptive     AdaptiveConfig     `ffstruct:"BatchDispatcherConfig" json:"adaptive"`
+}
+
+// AdaptiveConfig controls the automatic adjustment of the batch size of a dispatcher
+type AdaptiveConfig struct {
+	Enabled       bool               `ffstruct:"BatchAdaptiveConfig" json:"enabled"`
+	MinSize       uint               `ffstruct:"BatchAdaptiveConfig" json:"minSize"`
+	MaxSize       uint               `ffstruct:"BatchAdaptiveConfig" json:"maxSize"`
+	TargetLatency fftypes.FFDuration `ffstruct:"BatchAdaptiveConfig" json:"targetLatency"`
+	Window        int                `ffstruct:"BatchAdaptiveConfig" json:"window"`
+}
+
+// ConfigUpdate changes the batch assembly config at runtime, for the named dispatcher or for
+// all dispatchers if no name is given. Only the fields that are set are changed.
+type ConfigUpdate struct {
+	Dispatcher   string                `ffstruct:"BatchConfigUpdate" json:"dispatcher,omitempty"`
+	BatchSize    *uint                 `ffstruct:"BatchConfigUpdate" json:"batchSize,omitempty"`
+	PayloadLimit *int64                `ffstruct:"BatchConfigUpdate" json:"payloadLimit,omitempty"`
+	Timeout      *fftypes.FFDuration   `ffstruct:"BatchConfigUpdate" json:"timeout,omitempty"`
+	Adaptive     *AdaptiveConfigUpdate `ffstruct:"BatchConfigUpdate" json:"adaptive,omitempty"`
+}
+
+// AdaptiveConfigUpdate changes the adaptive sizing of the batch size. Only the fields that are set are changed.
+type AdaptiveConfigUpdate struct {
+	Enabled       *bool               `ffstruct:"BatchAdaptiveConfig" json:"enabled,omitempty"`
+	MinSize       *uint               `ffstruct:"BatchAdaptiveConfig" json:"minSize,omitempty"`
+	MaxSize       *uint               `ffstruct:"BatchAdaptiveConfig" json:"maxSize,omitempty"`
+	TargetLatency *fftypes.FFDuration `ffstruct:"BatchAdaptiveConfig" json:"targetLatency,omitempty"`
+	Window        *int                `ffstruct:"BatchAdaptiveConfig" json:"window,omitempty"`
+}
+
+// adaptiveStats accumulates the flushes of a dispatcher, until there are enough to adjust the batch size
+type adaptiveStats struct {
+	flushes      int
+	totalFill    float64
+	totalLatency time.Duration
+}
+
+func adaptiveConfigDefaults() AdaptiveConfig {
+	return AdaptiveConfig{
+		Enabled:       config.GetBool(coreconfig.BatchAdaptiveEnabled),
+		MinSize:       config.GetUint(coreconfig.BatchAdaptiveMinSize),
+		MaxSize:       config.GetUint(coreconfig.BatchAdaptiveMaxSize),
+		TargetLatency: fftypes.FFDuration(config.GetDuration(coreconfig.BatchAdaptiveTargetLatency)),
+		Window:        config.GetInt(coreconfig.BatchAdaptiveWindow),
+	}
+}
+
+// config must be called with the dispatcherMux held
+func (d *dispatcher) config() *DispatcherConfig {
+	return &DispatcherConfig{
+		Name:         d.name,
+		BatchSize:    d.options.BatchMaxSize,
+		PayloadLimit: d.options.BatchMaxBytes,
+		Timeout:      fftypes.FFDuration(d.options.BatchTimeout),
+		Adaptive:     d.adaptive,
+	}
+}
+
+// applyThresholds must be called with the dispatcherMux held
+func (d *dispatcher) applyThresholds() {
+	for _, p := range d.processors {
+		p.setThresholds(d.options)
+	}
+}
+
+func (bm *batchManager) GetConfig() []*DispatcherConfig {
+	bm.dispatcherMux.Lock()
+	defer bm.dispatcherMux.Unlock()
+	return bm.dispatcherConfigs()
+}
+
+// dispatcherConfigs must be called with the dispatcherMux held
+func (bm *batchManager) dispatcherConfigs() []*DispatcherConfig {
+	configs := make([]*DispatcherConfig, len(bm.allDispatchers))
+	for i, d := range bm.allDispatchers {
+		configs[i] = d.config()
+	}
+	return configs
+}
+
+// findDispatcher must be called with the dispatcherMux held
+func (bm *batchManager) findDispatcher(name string) *dispatcher {
+	for _, d := range bm.allDispatchers {
+		if d.name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// UpdateConfig changes the thresholds at which batches are flushed, and the adaptive sizing of batches.
+// The new thresholds apply to the in-flight assemblies of existing processors, as well as to new processors.
+// The changes are not persisted, so the configured values are used again after a restart.
+func (bm *batchManager) UpdateConfig(ctx context.Context, update *ConfigUpdate) ([]*DispatcherConfig, error) {
+	bm.dispatcherMux.Lock()
+	defer bm.dispatcherMux.Unlock()
+
+	dispatchers := bm.allDispatchers
+	if update.Dispatcher != "" {
+		d := bm.findDispatcher(update.Dispatcher)
+		if d == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgBatchDispatcherNotFound, update.Dispatcher)
+		}
+		dispatchers = []*dispatcher{d}
+	}
+
+	// Validate the update against every dispatcher before changing any of them
+	options := make([]DispatcherOptions, len(dispatchers))
+	adaptive := make([]AdaptiveConfig, len(dispatchers))
+	for i, d := range dispatchers {
+		options[i], adaptive[i] = d.options, d.adaptive
+		if err := update.applyTo(ctx, d, &options[i], &adaptive[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, d := range dispatchers {
+		if adaptive[i] != d.adaptive {
+			d.adaptiveStats = adaptiveStats{}
+		}
+		d.options, d.adaptive = options[i], adaptive[i]
+		d.applyThresholds()
+		log.L(ctx).Infof("Updated batch config for dispatcher %s: size=%d payloadLimit=%d timeout=%s adaptive=%t",
+			d.name, d.options.BatchMaxSize, d.options.BatchMaxBytes, d.options.BatchTimeout, d.adaptive.Enabled)
+	}
+	bm.notifyStatusChange()
+	return bm.dispatcherConfigs(), nil
+}
+
+func (u *ConfigUpdate) applyTo(ctx context.Context, d *dispatcher, options *DispatcherOptions, adaptive *AdaptiveConfig) error {
+	invalid := func(field string) error {
+		return i18n.NewError(ctx, coremsgs.MsgBatchConfigInvalid, field, d.name)
+	}
+	if u.BatchSize != nil {
+		if *u.BatchSize < 1 {
+			return invalid("batchSize")
+		}
+		options.BatchMaxSize = *u.BatchSize
+	}
+	if u.PayloadLimit != nil {
+		// The payload limit the dispatcher registered with is a limit of the plugins that transfer the batch
+		if *u.PayloadLimit < 1 || *u.PayloadLimit > d.maxPayloadLimit {
+			return invalid("payloadLimit")
+		}
+		options.BatchMaxBytes = *u.PayloadLimit
+	}
+	if u.Timeout != nil {
+		if *u.Timeout <= 0 {
+			return invalid("timeout")
+		}
+		options.BatchTimeout = time.Duration(*u.Timeout)
+	}
+	if a := u.Adaptive; a != nil {
+		if a.Enabled != nil {
+			adaptive.Enabled = *a.Enabled
+		}
+		if a.MinSize != nil {
+			adaptive.MinSize = *a.MinSize
+		}
+		if a.MaxSize != nil {
+			adaptive.MaxSize = *a.MaxSize
+		}
+		if a.TargetLatency != nil {
+			adaptive.TargetLatency = *a.TargetLatency
+		}
+		if a.Window != nil {
+			adaptive.Window = *a.Window
+		}
+	}
+	switch {
+	case adaptive.MinSize < 1:
+		return invalid("adaptive.minSize")
+	case adaptive.MaxSize < adaptive.MinSize:
+		return invalid("adaptive.maxSize")
+	case adaptive.TargetLatency <= 0:
+		return invalid("adaptive.targetLatency")
+	case adaptive.Window < 1:
+		return invalid("adaptive.window")
+	}
+	return nil
+}
+
+// recordFlush feeds a completed flush into the adaptive sizing of the dispatcher. Once a full window
+// of flushes has been recorded, the batch size is grown if batches are filling up within the target
+// latency, or shrunk if they are slow to flush or are being dispatched part-full by the batch timeout.
+func (bm *batchManager) recordFlush(dispatcherName string, messages int, latency time.Duration) {
+	bm.dispatcherMux.Lock()
+	defer bm.dispatcherMux.Unlock()
+
+	d := bm.findDispatcher(dispatcherName)
+	if d == nil || !d.adaptive.Enabled {
+		return
+	}
+	stats := &d.adaptiveStats
+	stats.flushes++
+	stats.totalFill += float64(messages) / float64(d.options.BatchMaxSize)
+	stats.totalLatency += latency
+	if stats.flushes < d.adaptive.Window {
+		return
+	}
+	averageFill := stats.totalFill / float64(stats.flushes)
+	averageLatency := stats.totalLatency / time.Duration(stats.flushes)
+	d.adaptiveStats = adaptiveStats{}
+
+	size := d.options.BatchMaxSize
+	step := max(size/4, 1)
+	newSize := size
+	switch {
+	case averageLatency > time.Duration(d.adaptive.TargetLatency):
+		newSize = size - min(step, size)
+	case averageFill >= adaptiveGrowFill:
+		newSize = size + step
+	case averageFill < adaptiveShrinkFill:
+		newSize = size - min(step, size)
+	}
+	newSize = min(max(newSize, d.adaptive.MinSize), d.adaptive.MaxSize)
+	if newSize != size {
+		log.L(bm.ctx).Infof("Adaptive batch size for dispatcher %s changed from %d to %d (averageFill=%.2f averageLatency=%s)",
+			d.name, size, newSize, averageFill, averageLatency)
+		d.options.BatchMaxSize = newSize
+		d.applyThresholds()
+	}
+}
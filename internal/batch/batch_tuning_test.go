@@ -0,0 +1,201 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTuningBatchManager(t *testing.T) (*batchManager, func()) {
+	testConfigReset()
+	config.Set(coreconfig.BatchAdaptiveMinSize, 2)
+	config.Set(coreconfig.BatchAdaptiveMaxSize, 12)
+	config.Set(coreconfig.BatchAdaptiveTargetLatency, "1s")
+	config.Set(coreconfig.BatchAdaptiveWindow, 2)
+	bm, cancel := newTestBatchManager(t)
+	handler := func(c context.Context, state *DispatchPayload) error { return nil }
+	bm.RegisterDispatcher("d1", true, []core.MessageType{core.MessageTypeBroadcast}, handler,
+		DispatcherOptions{BatchType: core.BatchTypeBroadcast, BatchMaxSize: 8, BatchMaxBytes: 2048, BatchTimeout: 5 * time.Second, DisposeTimeout: time.Minute},
+	)
+	bm.RegisterDispatcher("d2", true, []core.MessageType{core.MessageTypePrivate}, handler,
+		DispatcherOptions{BatchType: core.BatchTypePrivate, BatchMaxSize: 10, BatchMaxBytes: 4096, BatchTimeout: time.Second, DisposeTimeout: time.Minute},
+	)
+	return bm, cancel
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestGetConfig(t *testing.T) {
+	bm, cancel := newTestTuningBatchManager(t)
+	defer cancel()
+
+	configs := bm.GetConfig()
+	assert.Equal(t, []*DispatcherConfig{
+		{
+			Name: "d1", BatchSize: 8, PayloadLimit: 2048, Timeout: fftypes.FFDuration(5 * time.Second),
+			Adaptive: AdaptiveConfig{MinSize: 2, MaxSize: 12, TargetLatency: fftypes.FFDuration(time.Second), Window: 2},
+		},
+		{
+			Name: "d2", BatchSize: 10, PayloadLimit: 4096, Timeout: fftypes.FFDuration(time.Second),
+			Adaptive: AdaptiveConfig{MinSize: 2, MaxSize: 12, TargetLatency: fftypes.FFDuration(time.Second), Window: 2},
+		},
+	}, configs)
+}
+
+func TestUpdateConfigOneDispatcher(t *testing.T) {
+	bm, cancel := newTestTuningBatchManager(t)
+	defer cancel()
+
+	bp, err := bm.getProcessor(core.TransactionTypeBatchPin, core.MessageTypeBroadcast, nil, "did:firefly:org/abcd", true)
+	assert.NoError(t, err)
+
+	payloadLimit := int64(1024)
+	timeout := fftypes.FFDuration(2 * time.Second)
+	configs, err := bm.UpdateConfig(context.Background(), &ConfigUpdate{
+		Dispatcher:   "d1",
+		BatchSize:    uintPtr(4),
+		PayloadLimit: &payloadLimit,
+		Timeout:      &timeout,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), configs[0].BatchSize)
+	assert.Equal(t, int64(1024), configs[0].PayloadLimit)
+	assert.Equal(t, timeout, configs[0].Timeout)
+	assert.Equal(t, uint(10), configs[1].BatchSize)
+
+	// The in-flight processor picks up the new thresholds
+	maxSize, maxBytes, batchTimeout := bp.thresholds()
+	assert.Equal(t, uint(4), maxSize)
+	assert.Equal(t, int64(1024), maxBytes)
+	assert.Equal(t, 2*time.Second, batchTimeout)
+
+	// As do new processors
+	bp, err = bm.getProcessor(core.TransactionTypeBatchPin, core.MessageTypeBroadcast, nil, "did:firefly:org/efgh", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, bp.batchTimeout())
+}
+
+func TestUpdateConfigAllDispatchers(t *testing.T) {
+	bm, cancel := newTestTuningBatchManager(t)
+	defer cancel()
+
+	enabled := true
+	configs, err := bm.UpdateConfig(context.Background(), &ConfigUpdate{
+		Adaptive: &AdaptiveConfigUpdate{
+			Enabled: &enabled,
+			MaxSize: uintPtr(20),
+		},
+	})
+	assert.NoError(t, err)
+	for _, c := range configs {
+		assert.True(t, c.Adaptive.Enabled)
+		assert.Equal(t, uint(20), c.Adaptive.MaxSize)
+	}
+}
+
+func TestUpdateConfigDispatcherNotFound(t *testing.T) {
+	bm, cancel := newTestTuningBatchManager(t)
+	defer cancel()
+
+	_, err := bm.UpdateConfig(context.Background(), &ConfigUpdate{Dispatcher: "unknown"})
+	assert.Regexp(t, "FF10607.*unknown", err)
+}
+
+func TestUpdateConfigInvalid(t *testing.T) {
+	bm, cancel := newTestTuningBatchManager(t)
+	defer cancel()
+
+	tooLarge := int64(4096)
+	zeroDuration := fftypes.FFDuration(0)
+	zero := 0
+	updates := map[string]*ConfigUpdate{
+		"batchSize":              {BatchSize: uintPtr(0)},
+		"payloadLimit":           {Dispatcher: "d1", PayloadLimit: &tooLarge},
+		"timeout":                {Timeout: &zeroDuration},
+		"adaptive.minSize":       {Adaptive: &AdaptiveConfigUpdate{MinSize: uintPtr(0)}},
+		"adaptive.maxSize":       {Adaptive: &AdaptiveConfigUpdate{MaxSize: uintPtr(1)}},
+		"adaptive.targetLatency": {Adaptive: &AdaptiveConfigUpdate{TargetLatency: &zeroDuration}},
+		"adaptive.window":        {Adaptive: &AdaptiveConfigUpdate{Window: &zero}},
+	}
+	for field, update := range updates {
+		_, err := bm.UpdateConfig(context.Background(), update)
+		assert.Regexp(t, "FF10608.*"+field, err)
+	}
+
+	// Nothing was changed by the invalid updates
+	assert.Equal(t, uint(8), bm.GetConfig()[0].BatchSize)
+	assert.Equal(t, uint(12), bm.GetConfig()[0].Adaptive.MaxSize)
+}
+
+func TestAdaptiveSizing(t *testing.T) {
+	bm, cancel := newTestTuningBatchManager(t)
+	defer cancel()
+
+	bp, err := bm.getProcessor(core.TransactionTypeBatchPin, core.MessageTypeBroadcast, nil, "did:firefly:org/abcd", true)
+	assert.NoError(t, err)
+
+	// Disabled by default
+	bm.recordFlush("d1", 8, time.Millisecond)
+	bm.recordFlush("d1", 8, time.Millisecond)
+	assert.Equal(t, uint(8), bm.GetConfig()[0].BatchSize)
+
+	enabled := true
+	_, err = bm.UpdateConfig(context.Background(), &ConfigUpdate{
+		Dispatcher: "d1",
+		Adaptive:   &AdaptiveConfigUpdate{Enabled: &enabled},
+	})
+	assert.NoError(t, err)
+
+	// Full batches within the target latency grow the size, up to the maximum
+	bm.recordFlush("d1", 8, time.Millisecond)
+	assert.Equal(t, uint(8), bm.GetConfig()[0].BatchSize)
+	bm.recordFlush("d1", 8, time.Millisecond)
+	assert.Equal(t, uint(10), bm.GetConfig()[0].BatchSize)
+	maxSize, _, _ := bp.thresholds()
+	assert.Equal(t, uint(10), maxSize)
+	bm.recordFlush("d1", 10, time.Millisecond)
+	bm.recordFlush("d1", 10, time.Millisecond)
+	assert.Equal(t, uint(12), bm.GetConfig()[0].BatchSize)
+
+	// Slow batches shrink the size, even if they are full
+	bm.recordFlush("d1", 12, 2*time.Second)
+	bm.recordFlush("d1", 12, 2*time.Second)
+	assert.Equal(t, uint(9), bm.GetConfig()[0].BatchSize)
+
+	// Part-full batches shrink the size, down to the minimum
+	for i := 0; i < 14; i++ {
+		bm.recordFlush("d1", 1, time.Millisecond)
+	}
+	assert.Equal(t, uint(2), bm.GetConfig()[0].BatchSize)
+
+	// Batches that are neither full nor mostly empty leave the size alone
+	bm.recordFlush("d1", 1, time.Millisecond)
+	bm.recordFlush("d1", 2, time.Millisecond)
+	assert.Equal(t, uint(2), bm.GetConfig()[0].BatchSize)
+
+	// The other dispatcher is unaffected, and unknown dispatchers are ignored
+	bm.recordFlush("unknown", 1, time.Millisecond)
+	assert.Equal(t, uint(10), bm.GetConfig()[1].BatchSize)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// scheduledMessageReleaser moves scheduled messages into the ready state once their sendAfter time has passed,
+// so the message sequencer picks them up. The schedule is held in the database, so messages scheduled before
+// a restart are released as normal once the node is running again.
+func (bm *batchManager) scheduledMessageReleaser() {
+	l := log.L(bm.ctx)
+
+	for {
+		select {
+		case <-time.After(bm.schedulePollInterval):
+		case <-bm.ctx.Done():
+			l.Debugf("Scheduled message releaser exiting")
+			return
+		}
+		fullPage := true
+		for fullPage {
+			var err error
+			if fullPage, err = bm.releaseScheduledMessages(bm.ctx); err != nil {
+				l.Errorf("Failed to release scheduled messages: %s", err)
+			}
+		}
+	}
+}
+
+func (bm *batchManager) releaseScheduledMessages(ctx context.Context) (fullPage bool, err error) {
+	fb := database.MessageQueryFactory.NewFilterLimit(ctx, bm.readPageSize)
+	ids, err := bm.database.GetMessageIDs(ctx, bm.namespace, fb.And(
+		fb.Eq("state", core.MessageStateScheduled),
+		fb.Lte("sendafter", fftypes.Now()),
+	).Sort("sequence").Limit(bm.readPageSize))
+	if err != nil || len(ids) == 0 {
+		return false, err
+	}
+
+	idList := make([]driver.Value, len(ids))
+	for i, id := range ids {
+		idList[i] = id.ID
+	}
+	// Only messages that are still scheduled are released, so any that were cancelled in the meantime are not sent
+	fb = database.MessageQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.In("id", idList),
+		fb.Eq("state", core.MessageStateScheduled),
+	)
+	update := database.MessageQueryFactory.NewUpdate(ctx).Set("state", core.MessageStateReady)
+	if err := bm.database.UpdateMessages(ctx, bm.namespace, filter, update); err != nil {
+		return false, err
+	}
+	log.L(ctx).Infof("Released %d scheduled messages", len(ids))
+
+	// The messages were written before the current read offset of the sequencer, so it must rewind to find them
+	bm.newMessageNotification(ids[0].Sequence)
+	return len(ids) == int(bm.readPageSize), nil
+}
@@ -0,0 +1,146 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReleaseScheduledMessages(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.readOffset = 22222
+
+	msgID1 := fftypes.NewUUID()
+	msgID2 := fftypes.NewUUID()
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return strings.HasPrefix(fi.String(), "( state == 'scheduled' ) && ( sendafter <= ")
+	})).Return([]*core.IDAndSequence{
+		{ID: *msgID1, Sequence: 12345},
+		{ID: *msgID2, Sequence: 12346},
+	}, nil)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( id IN ['%s','%s'] ) && ( state == 'scheduled' )", msgID1, msgID2), fi.String())
+		return true
+	}), mock.MatchedBy(func(u ffapi.Update) bool {
+		ui, err := u.Finalize()
+		assert.NoError(t, err)
+		v, _ := ui.SetOperations[0].Value.Value()
+		return ui.SetOperations[0].Field == "state" && v == "ready"
+	})).Return(nil)
+
+	fullPage, err := bm.releaseScheduledMessages(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, fullPage)
+	assert.Equal(t, int64(12344), bm.rewindOffset)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReleaseScheduledMessagesFullPage(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.readPageSize = 1
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{
+		{ID: *fftypes.NewUUID(), Sequence: 12345},
+	}, nil)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil)
+
+	fullPage, err := bm.releaseScheduledMessages(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, fullPage)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReleaseScheduledMessagesNone(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil)
+
+	fullPage, err := bm.releaseScheduledMessages(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, fullPage)
+	assert.Equal(t, int64(-1), bm.rewindOffset)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReleaseScheduledMessagesQueryFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.releaseScheduledMessages(context.Background())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReleaseScheduledMessagesUpdateFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{
+		{ID: *fftypes.NewUUID(), Sequence: 12345},
+	}, nil)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.releaseScheduledMessages(context.Background())
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, int64(-1), bm.rewindOffset)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestScheduledMessageReleaser(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	bm.schedulePollInterval = 1 * time.Millisecond
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop")).Once()
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{}, nil).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	bm.scheduledMessageReleaser()
+
+	mdi.AssertExpectations(t)
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
 	"sync"
 
@@ -117,6 +118,23 @@ func (ce *conflictError) IsConflictError() bool {
 	return true
 }
 
+// connectorErrorCode matches the FireFly style error code at the start of an error message from a connector
+var connectorErrorCode = regexp.MustCompile(`^([A-Z]{2}\d{5}):`)
+
+// connectorError preserves the response from the connector, for diagnosing failed operations
+type connectorError struct {
+	err    error
+	detail *core.OperationError
+}
+
+func (ce *connectorError) Error() string {
+	return ce.err.Error()
+}
+
+func (ce *connectorError) ConnectorErrorDetail() *core.OperationError {
+	return ce.detail
+}
+
 func NewBlockchainCallbacks() BlockchainCallbacks {
 	return &callbacks{
 		handlers:   make(map[string]blockchain.Callbacks),
@@ -436,10 +454,28 @@ func WrapRESTError(ctx context.Context, errRes *BlockchainRESTError, res *resty.
 		if res != nil && res.StatusCode() == http.StatusConflict {
 			return &conflictError{err: i18n.WrapError(ctx, err, coremsgs.MsgBlockchainConnectorRESTErrConflict, errRes.Error)}
 		}
-		return i18n.WrapError(ctx, err, defMsgKey, errRes.Error)
+		return withConnectorError(i18n.WrapError(ctx, err, defMsgKey, errRes.Error), errRes, res)
 	}
 	if res != nil && res.StatusCode() == http.StatusConflict {
 		return &conflictError{err: ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgBlockchainConnectorRESTErrConflict)}
 	}
-	return ffresty.WrapRestErr(ctx, res, err, defMsgKey)
+	return withConnectorError(ffresty.WrapRestErr(ctx, res, err, defMsgKey), errRes, res)
+}
+
+// withConnectorError attaches the status, error code and raw body of the connector response to the error,
+// as the error message alone is often a summary that loses the detail (such as an EVM revert reason)
+func withConnectorError(err error, errRes *BlockchainRESTError, res *resty.Response) error {
+	if res == nil || res.StatusCode() == 0 {
+		return err
+	}
+	detail := &core.OperationError{
+		StatusCode: res.StatusCode(),
+		Body:       string(res.Body()),
+	}
+	if errRes != nil {
+		if match := connectorErrorCode.FindStringSubmatch(errRes.Error); match != nil {
+			detail.Code = match[1]
+		}
+	}
+	return &connectorError{err: err, detail: detail}
 }
@@ -455,3 +455,42 @@ func TestErrorWrappingNonConflict(t *testing.T) {
 	_, conforms := err.(operations.ConflictError)
 	assert.False(t, conforms)
 }
+
+func TestErrorWrappingConnectorDetail(t *testing.T) {
+	ctx := context.Background()
+	res := &resty.Response{
+		RawResponse: &http.Response{StatusCode: 500},
+	}
+	res.SetBody([]byte(`{"error":"FF23021: EVM reverted: insufficient balance"}`))
+	err := WrapRESTError(ctx, &BlockchainRESTError{Error: "FF23021: EVM reverted: insufficient balance"}, res, fmt.Errorf("pop"), coremsgs.MsgEthConnectorRESTErr)
+	assert.Regexp(t, "FF10111.*insufficient balance", err)
+
+	connectorErr, conforms := err.(operations.ConnectorError)
+	assert.True(t, conforms)
+	assert.Equal(t, &core.OperationError{
+		StatusCode: 500,
+		Code:       "FF23021",
+		Body:       `{"error":"FF23021: EVM reverted: insufficient balance"}`,
+	}, connectorErr.ConnectorErrorDetail())
+}
+
+func TestErrorWrappingConnectorDetailNoCode(t *testing.T) {
+	ctx := context.Background()
+	res := &resty.Response{
+		RawResponse: &http.Response{StatusCode: 502},
+	}
+	res.SetBody([]byte(`bad gateway`))
+	err := WrapRESTError(ctx, nil, res, fmt.Errorf("pop"), coremsgs.MsgEthConnectorRESTErr)
+
+	connectorErr, conforms := err.(operations.ConnectorError)
+	assert.True(t, conforms)
+	assert.Equal(t, &core.OperationError{StatusCode: 502, Body: "bad gateway"}, connectorErr.ConnectorErrorDetail())
+}
+
+func TestErrorWrappingNoResponseNoConnectorDetail(t *testing.T) {
+	ctx := context.Background()
+	err := WrapRESTError(ctx, nil, &resty.Response{}, fmt.Errorf("pop"), coremsgs.MsgEthConnectorRESTErr)
+
+	_, conforms := err.(operations.ConnectorError)
+	assert.False(t, conforms)
+}
@@ -0,0 +1,283 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	// FailoverConfigURLs is a list of additional connector URLs to fail over to, when the primary URL becomes unhealthy
+	FailoverConfigURLs = "failover.urls"
+	// FailoverConfigHealthCheckInterval is how often the health of every connector URL is checked
+	FailoverConfigHealthCheckInterval = "failover.healthCheckInterval"
+	// FailoverConfigHealthCheckPath is the path on each connector URL that is requested to check its health
+	FailoverConfigHealthCheckPath = "failover.healthCheckPath"
+
+	defaultFailoverHealthCheckInterval = "10s"
+	defaultFailoverHealthCheckPath     = "/status"
+
+	// maxFailoverHistory bounds the number of failovers reported in the connector status
+	maxFailoverHistory = 25
+)
+
+// InitFailoverConfig adds the failover keys to the config section of a connector
+func InitFailoverConfig(conf config.Section) {
+	conf.AddKnownKey(FailoverConfigURLs)
+	conf.AddKnownKey(FailoverConfigHealthCheckInterval, defaultFailoverHealthCheckInterval)
+	conf.AddKnownKey(FailoverConfigHealthCheckPath, defaultFailoverHealthCheckPath)
+}
+
+type healthCheckKey struct{}
+
+type connectorEndpoint struct {
+	url    string
+	status core.ConnectorEndpointStatus
+}
+
+// ConnectorFailover switches the REST and websocket connections of a plugin between a set of equivalent
+// connector endpoints. Each endpoint is health checked in the background, and if the active endpoint is
+// found to be unhealthy the first healthy endpoint in the configured order becomes active. There is no
+// automatic fail back, so the plugin only moves again when the new active endpoint fails.
+//
+// A nil *ConnectorFailover is valid, and is returned when no failover URLs are configured.
+type ConnectorFailover struct {
+	ctx        context.Context
+	client     *resty.Client
+	healthPath string
+	interval   time.Duration
+	checkNow   chan struct{}
+
+	mux       sync.Mutex
+	endpoints []*connectorEndpoint
+	active    int
+	failovers []*core.ConnectorFailover
+}
+
+// NewConnectorFailover configures the client to send each request to the active endpoint, out of the primary
+// URL and the failover URLs of the connector config. It returns nil if there are no failover URLs.
+func NewConnectorFailover(ctx context.Context, conf config.Section, client *resty.Client) (*ConnectorFailover, error) {
+	failoverURLs := conf.GetStringSlice(FailoverConfigURLs)
+	if len(failoverURLs) == 0 {
+		return nil, nil
+	}
+	cf := &ConnectorFailover{
+		ctx:        ctx,
+		client:     client,
+		healthPath: "/" + strings.TrimPrefix(conf.GetString(FailoverConfigHealthCheckPath), "/"),
+		interval:   conf.GetDuration(FailoverConfigHealthCheckInterval),
+		checkNow:   make(chan struct{}, 1),
+	}
+	for _, u := range append([]string{conf.GetString(ffresty.HTTPConfigURL)}, failoverURLs...) {
+		parsed, err := url.Parse(u)
+		if err != nil || !parsed.IsAbs() {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidConnectorFailoverURL, u)
+		}
+		cf.endpoints = append(cf.endpoints, &connectorEndpoint{
+			url: strings.TrimSuffix(u, "/"),
+			status: core.ConnectorEndpointStatus{
+				URL: redactURL(parsed),
+				// Assumed healthy until the first check
+				Healthy: true,
+			},
+		})
+	}
+	client.OnBeforeRequest(cf.beforeRequest)
+	client.OnError(cf.onError)
+	return cf, nil
+}
+
+// redactURL removes any credentials from a URL, so it can be reported in the status
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	return redacted.String()
+}
+
+// Start runs the background health checks, until the context is cancelled
+func (cf *ConnectorFailover) Start() {
+	if cf != nil {
+		go cf.healthLoop()
+	}
+}
+
+func (cf *ConnectorFailover) activeURL() string {
+	cf.mux.Lock()
+	defer cf.mux.Unlock()
+	return cf.endpoints[cf.active].url
+}
+
+// beforeRequest points each request at the active endpoint. Retries of a request that was previously
+// sent to another endpoint are moved to the active endpoint.
+func (cf *ConnectorFailover) beforeRequest(_ *resty.Client, req *resty.Request) error {
+	if req.Context().Value(healthCheckKey{}) != nil {
+		return nil
+	}
+	path := req.URL
+	for _, ep := range cf.endpoints {
+		if rest := strings.TrimPrefix(path, ep.url); rest != path && (rest == "" || rest[0] == '/' || rest[0] == '?') {
+			path = rest
+			break
+		}
+	}
+	if parsed, err := url.Parse(path); err != nil || parsed.IsAbs() {
+		// Not a request to the connector
+		return nil
+	}
+	req.URL = cf.activeURL() + "/" + strings.TrimPrefix(path, "/")
+	return nil
+}
+
+// onError triggers an immediate health check when a request to the connector fails without a response
+func (cf *ConnectorFailover) onError(req *resty.Request, err error) {
+	if req.Context().Value(healthCheckKey{}) != nil {
+		return
+	}
+	if _, ok := err.(*resty.ResponseError); ok {
+		return
+	}
+	select {
+	case cf.checkNow <- struct{}{}:
+	default:
+	}
+}
+
+func (cf *ConnectorFailover) healthLoop() {
+	ticker := time.NewTicker(cf.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cf.ctx.Done():
+			log.L(cf.ctx).Debugf("Connector health checks exiting")
+			return
+		case <-ticker.C:
+		case <-cf.checkNow:
+		}
+		cf.checkEndpoints()
+	}
+}
+
+func (cf *ConnectorFailover) checkHealth(ep *connectorEndpoint) core.ConnectorEndpointStatus {
+	status := core.ConnectorEndpointStatus{
+		URL:         ep.status.URL,
+		Healthy:     true,
+		LastChecked: fftypes.Now(),
+	}
+	res, err := cf.client.R().
+		SetContext(context.WithValue(cf.ctx, healthCheckKey{}, true)).
+		Get(ep.url + cf.healthPath)
+	if err == nil && !res.IsSuccess() {
+		err = i18n.NewError(cf.ctx, coremsgs.MsgConnectorHealthCheckFailed, res.StatusCode())
+	}
+	if err != nil {
+		status.Healthy = false
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// checkEndpoints checks the health of every endpoint, and fails over if the active endpoint is unhealthy
+func (cf *ConnectorFailover) checkEndpoints() {
+	results := make([]core.ConnectorEndpointStatus, len(cf.endpoints))
+	for i, ep := range cf.endpoints {
+		results[i] = cf.checkHealth(ep)
+	}
+
+	cf.mux.Lock()
+	defer cf.mux.Unlock()
+	for i, ep := range cf.endpoints {
+		ep.status = results[i]
+	}
+	current := cf.endpoints[cf.active]
+	if current.status.Healthy {
+		return
+	}
+	for i := 1; i < len(cf.endpoints); i++ {
+		next := (cf.active + i) % len(cf.endpoints)
+		if cf.endpoints[next].status.Healthy {
+			log.L(cf.ctx).Warnf("Connector failover from %s to %s: %s", current.status.URL, cf.endpoints[next].status.URL, current.status.Error)
+			cf.failovers = append(cf.failovers, &core.ConnectorFailover{
+				From:      current.status.URL,
+				To:        cf.endpoints[next].status.URL,
+				Reason:    current.status.Error,
+				Timestamp: fftypes.Now(),
+			})
+			if len(cf.failovers) > maxFailoverHistory {
+				cf.failovers = cf.failovers[len(cf.failovers)-maxFailoverHistory:]
+			}
+			cf.active = next
+			return
+		}
+	}
+	log.L(cf.ctx).Errorf("Connector %s is unhealthy, and no healthy failover connector is available: %s", current.status.URL, current.status.Error)
+}
+
+// WSPreConnect returns a websocket pre-connect handler that points each connection and reconnection
+// at the active endpoint. It returns nil if there is no failover, or an explicit websocket URL is configured.
+func (cf *ConnectorFailover) WSPreConnect(wsConfig *wsclient.WSConfig) wsclient.WSPreConnectHandler {
+	if cf == nil || wsConfig.WebSocketURL != "" {
+		return nil
+	}
+	return func(ctx context.Context, w wsclient.WSClient) error {
+		u, _ := url.Parse(cf.activeURL()) // validated on creation
+		if wsConfig.WSKeyPath != "" {
+			u.Path = wsConfig.WSKeyPath
+		}
+		switch u.Scheme {
+		case "http":
+			u.Scheme = "ws"
+		case "https":
+			u.Scheme = "wss"
+		}
+		w.SetURL(u.String())
+		return nil
+	}
+}
+
+// Status reports the active endpoint, the latest health of every endpoint, and the failover history
+func (cf *ConnectorFailover) Status() *core.ConnectorStatus {
+	if cf == nil {
+		return nil
+	}
+	cf.mux.Lock()
+	defer cf.mux.Unlock()
+	status := &core.ConnectorStatus{
+		Active:    cf.endpoints[cf.active].status.URL,
+		Endpoints: make([]*core.ConnectorEndpointStatus, len(cf.endpoints)),
+		Failovers: make([]*core.ConnectorFailover, len(cf.failovers)),
+	}
+	for i, ep := range cf.endpoints {
+		epStatus := ep.status
+		status.Endpoints[i] = &epStatus
+	}
+	copy(status.Failovers, cf.failovers)
+	return status
+}
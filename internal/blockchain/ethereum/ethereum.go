@@ -39,6 +39,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/sirupsen/logrus"
@@ -66,6 +67,7 @@ type Ethereum struct {
 	capabilities         *blockchain.Capabilities
 	callbacks            common.BlockchainCallbacks
 	client               *resty.Client
+	failover             *common.ConnectorFailover
 	streams              *streamManager
 	streamID             map[string]string
 	wsconn               map[string]wsclient.WSClient
@@ -87,6 +89,10 @@ type queryOutput struct {
 	Output interface{} `json:"output"`
 }
 
+type gasEstimateResponse struct {
+	GasEstimate *fftypes.FFBigInt `json:"gasEstimate"`
+}
+
 type ethWSCommandPayload struct {
 	Type        string `json:"type"`
 	Topic       string `json:"topic,omitempty"`
@@ -126,6 +132,31 @@ type FFIGenerationInput struct {
 	ABI *abi.ABI `json:"abi,omitempty"`
 }
 
+// SolidityGenerationInput is the input to generate an FFI from Solidity - either source for the connector
+// to compile, or the standard JSON output of the Solidity compiler. The contract name is required if there
+// is more than one contract in the source.
+type SolidityGenerationInput struct {
+	Source         string                  `json:"source,omitempty"`
+	CompilerOutput *SolidityCompilerOutput `json:"compilerOutput,omitempty"`
+	Contract       string                  `json:"contract,omitempty"`
+}
+
+// SolidityCompilerOutput is the subset of the Solidity standard JSON output that is used to generate an FFI,
+// with contracts keyed by source file name then contract name
+type SolidityCompilerOutput struct {
+	Errors    []*SolidityCompilerError                        `json:"errors,omitempty"`
+	Contracts map[string]map[string]*SolidityCompiledContract `json:"contracts"`
+}
+
+type SolidityCompilerError struct {
+	Severity         string `json:"severity"`
+	FormattedMessage string `json:"formattedMessage"`
+}
+
+type SolidityCompiledContract struct {
+	ABI *abi.ABI `json:"abi"`
+}
+
 var addressVerify = regexp.MustCompile("^[0-9a-f]{40}$")
 
 func (e *Ethereum) Name() string {
@@ -164,6 +195,10 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 	if err == nil {
 		e.client, err = ffresty.New(e.ctx, ethconnectConf)
 	}
+	if err == nil {
+		tracing.InstrumentClient(e.client)
+		e.failover, err = common.NewConnectorFailover(e.ctx, ethconnectConf, e.client)
+	}
 
 	if err != nil {
 		return err
@@ -197,6 +232,7 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 	e.closed = make(map[string]chan struct{})
 	e.wsconn = make(map[string]wsclient.WSClient)
 	e.streams = newStreamManager(e.client, e.cache, e.ethconnectConf.GetUint(EthconnectConfigBatchSize), uint(e.ethconnectConf.GetDuration(EthconnectConfigBatchTimeout).Milliseconds()))
+	e.failover.Start()
 
 	return nil
 }
@@ -209,7 +245,7 @@ func (e *Ethereum) StartNamespace(ctx context.Context, namespace string) (err er
 	log.L(e.ctx).Debugf("Starting namespace: %s", namespace)
 	topic := e.getTopic(namespace)
 
-	e.wsconn[namespace], err = wsclient.New(ctx, e.wsConfig, nil, func(ctx context.Context, w wsclient.WSClient) error {
+	e.wsconn[namespace], err = wsclient.New(ctx, e.wsConfig, e.failover.WSPreConnect(e.wsConfig), func(ctx context.Context, w wsclient.WSClient) error {
 		// Send a subscribe to our topic after each connect/reconnect
 		b, _ := json.Marshal(&ethWSCommandPayload{
 			Type:  "listen",
@@ -271,6 +307,15 @@ func (e *Ethereum) Capabilities() *blockchain.Capabilities {
 	return e.capabilities
 }
 
+func (e *Ethereum) ConnectorStatus() *core.ConnectorStatus {
+	return e.failover.Status()
+}
+
+func (e *Ethereum) Ping(ctx context.Context) error {
+	_, err := e.streams.getEventStreams(ctx)
+	return err
+}
+
 func (e *Ethereum) AddFireflySubscription(ctx context.Context, namespace *core.Namespace, contract *blockchain.MultipartyContract, lastProtocolID string) (string, error) {
 	ethLocation, err := e.parseContractLocation(ctx, contract.Location)
 	if err != nil {
@@ -650,6 +695,52 @@ func (e *Ethereum) queryContractMethod(ctx context.Context, address, signingKey
 	return res, nil
 }
 
+// simulateContractMethod evaluates the method with an eth_call through the connector, and if that succeeds
+// asks the connector to estimate the gas the transaction would use. Connectors that cannot estimate gas
+// still return the outcome of the call.
+func (e *Ethereum) simulateContractMethod(ctx context.Context, address, signingKey string, abi *abi.Entry, input []interface{}, errors []*abi.Entry, options map[string]interface{}) (*core.Simulation, error) {
+	if e.metrics.IsMetricsEnabled() {
+		e.metrics.BlockchainQuery(address, abi.Name)
+	}
+	body, err := e.buildEthconnectRequestBody(ctx, "Query", address, signingKey, abi, "", input, errors, options)
+	if err != nil {
+		return nil, err
+	}
+	var resErr common.BlockchainRESTError
+	res, err := e.client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetError(&resErr).
+		Post("/")
+	if err == nil && !res.IsSuccess() && resErr.SubmissionRejected {
+		// The connector has decoded the revert against the supplied errors
+		return &core.Simulation{Success: false, RevertReason: resErr.Error}, nil
+	}
+	if err != nil || !res.IsSuccess() {
+		return nil, common.WrapRESTError(ctx, &resErr, res, err, coremsgs.MsgEthConnectorRESTErr)
+	}
+	sim := &core.Simulation{Success: true}
+	if err = json.Unmarshal(res.Body(), &sim.Output); err != nil {
+		return nil, err
+	}
+
+	body["headers"] = EthconnectMessageHeaders{Type: "EstimateGas"}
+	var estimate gasEstimateResponse
+	resErr = common.BlockchainRESTError{}
+	res, err = e.client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&estimate).
+		SetError(&resErr).
+		Post("/")
+	if err != nil || !res.IsSuccess() {
+		log.L(ctx).Warnf("Unable to estimate gas for '%s' on %s: %s", abi.Name, address, common.WrapRESTError(ctx, &resErr, res, err, coremsgs.MsgEthConnectorRESTErr))
+		return sim, nil
+	}
+	sim.GasEstimate = estimate.GasEstimate
+	return sim, nil
+}
+
 func (e *Ethereum) buildBatchPinInput(version int, namespace string, batch *blockchain.BatchPin) (*abi.Entry, []interface{}) {
 	ethHashes := make([]string, len(batch.Contexts))
 	for i, v := range batch.Contexts {
@@ -843,6 +934,18 @@ func (e *Ethereum) QueryContract(ctx context.Context, signingKey string, locatio
 	return output, nil // note UNLIKE fabric this is just `output`, not `output.Result` - but either way the top level of what we return to the end user, is whatever the Connector sent us
 }
 
+func (e *Ethereum) SimulateContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (*core.Simulation, error) {
+	ethereumLocation, err := e.parseContractLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	methodInfo, orderedInput, err := e.prepareRequest(ctx, parsedMethod, input)
+	if err != nil {
+		return nil, err
+	}
+	return e.simulateContractMethod(ctx, ethereumLocation.Address, signingKey, methodInfo.methodABI, orderedInput, methodInfo.errorsABI, options)
+}
+
 func (e *Ethereum) CheckOverlappingLocations(ctx context.Context, left *fftypes.JSONAny, right *fftypes.JSONAny) (bool, error) {
 	if left == nil || right == nil {
 		// No location on either side so overlapping
@@ -949,9 +1052,20 @@ func (e *Ethereum) AddContractListener(ctx context.Context, listener *core.Contr
 		return err
 	}
 	listener.BackendID = result.ID
+	if listener.Options != nil {
+		listener.Options.FromBlock = result.FromBlock
+	}
 	return nil
 }
 
+func (e *Ethereum) UpdateContractListener(ctx context.Context, listener *core.ContractListener) error {
+	firstEvent := string(core.SubOptsFirstEventNewest)
+	if listener.Options != nil {
+		firstEvent = listener.Options.FirstEvent
+	}
+	return e.streams.updateSubscription(ctx, listener.BackendID, listener.ID, firstEvent)
+}
+
 func (e *Ethereum) DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error {
 	return e.streams.deleteSubscription(ctx, subscription.BackendID, okNotFound)
 }
@@ -1045,6 +1159,18 @@ func (e *Ethereum) GenerateErrorSignature(ctx context.Context, errorDef *fftypes
 	return ffi2abi.ABIMethodToSignature(abi)
 }
 
+func (e *Ethereum) GenerateMethodSelector(ctx context.Context, method *fftypes.FFIMethod) (string, string, error) {
+	methodABI, err := ffi2abi.ConvertFFIMethodToABI(ctx, method)
+	if err != nil {
+		return "", "", err
+	}
+	signature, err := methodABI.SignatureCtx(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	return signature, methodABI.FunctionSelectorBytes().String(), nil
+}
+
 type parsedFFIMethod struct {
 	methodABI *abi.Entry
 	errorsABI []*abi.Entry
@@ -1108,6 +1234,123 @@ func (e *Ethereum) GenerateFFI(ctx context.Context, generationRequest *fftypes.F
 	return ffi2abi.ConvertABIToFFI(ctx, generationRequest.Namespace, generationRequest.Name, generationRequest.Version, generationRequest.Description, input.ABI)
 }
 
+// GenerateDeployDefinition builds the ABI to deploy a contract from an FFI, including a constructor with the given params
+func (e *Ethereum) GenerateDeployDefinition(ctx context.Context, ffi *fftypes.FFI, constructorParams fftypes.FFIParams) (*fftypes.JSONAny, error) {
+	constructor, err := ffi2abi.ConvertFFIMethodToABI(ctx, &fftypes.FFIMethod{Params: constructorParams})
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgContractDeployDefinitionInvalid, "constructor")
+	}
+	constructor.Type = abi.Constructor
+	contractABI := abi.ABI{constructor}
+	for _, method := range ffi.Methods {
+		entry, err := ffi2abi.ConvertFFIMethodToABI(ctx, method)
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgContractDeployDefinitionInvalid, method.Pathname)
+		}
+		contractABI = append(contractABI, entry)
+	}
+	for _, event := range ffi.Events {
+		entry, err := ffi2abi.ConvertFFIEventDefinitionToABI(ctx, &event.FFIEventDefinition)
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgContractDeployDefinitionInvalid, event.Pathname)
+		}
+		contractABI = append(contractABI, entry)
+	}
+	for _, ffiError := range ffi.Errors {
+		entry, err := ffi2abi.ConvertFFIErrorDefinitionToABI(ctx, &ffiError.FFIErrorDefinition)
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgContractDeployDefinitionInvalid, ffiError.Pathname)
+		}
+		contractABI = append(contractABI, entry)
+	}
+	b, _ := json.Marshal(contractABI)
+	return fftypes.JSONAnyPtrBytes(b), nil
+}
+
+func (e *Ethereum) CompileContractInterface(ctx context.Context, input *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	var solidityInput SolidityGenerationInput
+	err := json.Unmarshal(input.Bytes(), &solidityInput)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgFFIGenerationFailed, "unable to deserialize JSON as Solidity input")
+	}
+	output := solidityInput.CompilerOutput
+	if output == nil {
+		if solidityInput.Source == "" {
+			return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationFailed, "Solidity source or compiler output is required")
+		}
+		if output, err = e.compileSolidity(ctx, solidityInput.Source); err != nil {
+			return nil, err
+		}
+	}
+	contractABI, err := e.extractCompiledABI(ctx, output, solidityInput.Contract)
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.Marshal(&FFIGenerationInput{ABI: contractABI})
+	return fftypes.JSONAnyPtrBytes(b), nil
+}
+
+func (e *Ethereum) compileSolidity(ctx context.Context, source string) (*SolidityCompilerOutput, error) {
+	body := map[string]interface{}{
+		"headers": EthconnectMessageHeaders{
+			Type: core.CompileContract,
+		},
+		"source": source,
+	}
+	var resErr common.BlockchainRESTError
+	var output SolidityCompilerOutput
+	res, err := e.client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetError(&resErr).
+		SetResult(&output).
+		Post("/")
+	if err != nil || !res.IsSuccess() {
+		if strings.Contains(string(res.Body()), "FFEC100130") {
+			// This error is returned by connectors that do not support compiling contracts
+			return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+		}
+		return nil, common.WrapRESTError(ctx, &resErr, res, err, coremsgs.MsgEthConnectorRESTErr)
+	}
+	return &output, nil
+}
+
+// extractCompiledABI finds the ABI of the named contract in the compiler output, or the only contract if no name is supplied.
+// The name can be the bare contract name, or qualified with the source file name as "file.sol:Contract".
+func (e *Ethereum) extractCompiledABI(ctx context.Context, output *SolidityCompilerOutput, contractName string) (*abi.ABI, error) {
+	var compileErrors []string
+	for _, compileErr := range output.Errors {
+		if compileErr.Severity == "error" {
+			compileErrors = append(compileErrors, compileErr.FormattedMessage)
+		}
+	}
+	if len(compileErrors) > 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSolidityCompileFailed, strings.Join(compileErrors, "; "))
+	}
+
+	var contractABI *abi.ABI
+	total, matched := 0, 0
+	for fileName, contracts := range output.Contracts {
+		for name, contract := range contracts {
+			total++
+			if contractName == "" || contractName == name || contractName == fmt.Sprintf("%s:%s", fileName, name) {
+				contractABI = contract.ABI
+				matched++
+			}
+		}
+	}
+	switch {
+	case contractName == "" && total != 1:
+		return nil, i18n.NewError(ctx, coremsgs.MsgSolidityContractNotSpecified, total)
+	case matched == 0:
+		return nil, i18n.NewError(ctx, coremsgs.MsgSolidityContractNotFound, contractName)
+	case matched > 1:
+		// The same contract name is used in more than one source file
+		return nil, i18n.NewError(ctx, coremsgs.MsgSolidityContractNotSpecified, matched)
+	}
+	return contractABI, nil
+}
+
 func (e *Ethereum) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (version int, err error) {
 	ethLocation, err := e.parseContractLocation(ctx, location)
 	if err != nil {
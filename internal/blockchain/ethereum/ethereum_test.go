@@ -33,6 +33,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
 	"github.com/hyperledger/firefly/internal/blockchain/common"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
@@ -511,6 +512,40 @@ func TestDeleteStreamOKNotFound(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestInitFailoverBadURL(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(common.FailoverConfigURLs, []string{"not a url"})
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10596", err)
+}
+
+func TestInitFailoverConnectorStatus(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(common.FailoverConfigURLs, []string{"http://localhost:23456"})
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+
+	assert.Nil(t, e.ConnectorStatus())
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	status := e.ConnectorStatus()
+	assert.Equal(t, "http://localhost:12345", status.Active)
+	assert.Len(t, status.Endpoints, 2)
+}
+
 func TestStartNamespaceWSCreateFail(t *testing.T) {
 	e, cancel := newTestEthereum()
 	defer cancel()
@@ -2029,11 +2064,13 @@ func TestAddSubscription(t *testing.T) {
 	}
 
 	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
-		httpmock.NewJsonResponderOrPanic(200, &subscription{}))
+		httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1", FromBlock: "0"}))
 
 	err := e.AddContractListener(context.Background(), sub, "")
 
 	assert.NoError(t, err)
+	assert.Equal(t, "sub1", sub.BackendID)
+	assert.Equal(t, "0", sub.Options.FromBlock)
 }
 
 func TestAddSubscriptionWithoutLocation(t *testing.T) {
@@ -2384,6 +2421,128 @@ func TestAddSubscriptionFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestUpdateContractListener(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	sub := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		BackendID: "sb-1",
+		Options:   &core.ContractListenerOptions{FirstEvent: "oldest"},
+	}
+
+	httpmock.RegisterResponder("PATCH", `http://localhost:12345/subscriptions/sb-1`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{"fromBlock": "0"}, body)
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+
+	err := e.UpdateContractListener(context.Background(), sub)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateContractListenerDefaultNewest(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	sub := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		BackendID: "sb-1",
+	}
+
+	httpmock.RegisterResponder("PATCH", `http://localhost:12345/subscriptions/sb-1`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{"fromBlock": "latest"}, body)
+			return httpmock.NewStringResponse(200, "{}"), nil
+		})
+
+	err := e.UpdateContractListener(context.Background(), sub)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateContractListenerBadFirstEvent(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	sub := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		BackendID: "sb-1",
+		Options:   &core.ContractListenerOptions{FirstEvent: "bad"},
+	}
+
+	err := e.UpdateContractListener(context.Background(), sub)
+
+	assert.Regexp(t, "FF10473", err)
+}
+
+func TestUpdateContractListenerNotSupported(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	sub := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		BackendID: "sb-1",
+	}
+
+	httpmock.RegisterResponder("PATCH", `http://localhost:12345/subscriptions/sb-1`,
+		httpmock.NewStringResponder(405, ""))
+
+	err := e.UpdateContractListener(context.Background(), sub)
+
+	assert.Regexp(t, "FF10506", err)
+}
+
+func TestUpdateContractListenerFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	sub := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		BackendID: "sb-1",
+	}
+
+	httpmock.RegisterResponder("PATCH", `http://localhost:12345/subscriptions/sb-1`,
+		httpmock.NewStringResponder(500, ""))
+
+	err := e.UpdateContractListener(context.Background(), sub)
+
+	assert.Regexp(t, "FF10111", err)
+}
+
 func TestDeleteSubscription(t *testing.T) {
 	e, cancel := newTestEthereum()
 	defer cancel()
@@ -3545,6 +3704,210 @@ func TestQueryContractUnmarshalResponseError(t *testing.T) {
 	assert.Regexp(t, "invalid character", err)
 }
 
+func TestSimulateContractOK(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	options := map[string]interface{}{
+		"customOption": "customValue",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			headers := body["headers"].(map[string]interface{})
+			assert.Equal(t, "customValue", body["customOption"].(string))
+			assert.Equal(t, "0x12345", body["to"].(string))
+			assert.Equal(t, "0x01020304", body["from"].(string))
+			assert.Equal(t, []interface{}{float64(1), float64(2)}, body["params"])
+			assert.NotNil(t, body["errors"])
+			switch headers["type"] {
+			case "Query":
+				return httpmock.NewJsonResponderOrPanic(200, queryOutput{Output: "3"})(req)
+			default:
+				assert.Equal(t, "EstimateGas", headers["type"])
+				return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"gasEstimate": "21000"})(req)
+			}
+		})
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, options)
+	assert.NoError(t, err)
+	assert.True(t, sim.Success)
+	assert.Equal(t, int64(21000), sim.GasEstimate.Int64())
+	assert.Equal(t, map[string]interface{}{"output": "3"}, sim.Output)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestSimulateContractReverted(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{
+				Error:              "FF23021: EVM reverted: CustomError(\"bad\")",
+				SubmissionRejected: true,
+			})(req)
+		})
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.NoError(t, err)
+	assert.False(t, sim.Success)
+	assert.Equal(t, "FF23021: EVM reverted: CustomError(\"bad\")", sim.RevertReason)
+	assert.Nil(t, sim.GasEstimate)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestSimulateContractEstimateGasFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			headers := body["headers"].(map[string]interface{})
+			if headers["type"] == "Query" {
+				return httpmock.NewJsonResponderOrPanic(200, queryOutput{Output: "3"})(req)
+			}
+			return httpmock.NewJsonResponderOrPanic(400, common.BlockchainRESTError{Error: "unsupported request type"})(req)
+		})
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.NoError(t, err)
+	assert.True(t, sim.Success)
+	assert.Nil(t, sim.GasEstimate)
+}
+
+func TestSimulateContractEthconnectError(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{Error: "node unavailable"}))
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	_, err = e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "FF10111.*node unavailable", err)
+}
+
+func TestSimulateContractUnmarshalResponseError(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewStringResponder(200, "[definitely not JSON}"))
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	_, err = e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "invalid character", err)
+}
+
+func TestSimulateContractInvalidOption(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	options := map[string]interface{}{
+		"params": "shouldn't be allowed",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	_, err = e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, options)
+	assert.Regexp(t, "FF10398", err)
+}
+
+func TestSimulateContractErrorPrepare(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	location := &Location{
+		Address: "0x12345",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	_, err = e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), "wrong type", nil, nil)
+	assert.Regexp(t, "FF10457", err)
+}
+
+func TestSimulateContractAddressNotSet(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	_, err := e.SimulateContract(context.Background(), "0x01020304", fftypes.JSONAnyPtr("{}"), nil, nil, nil)
+	assert.Regexp(t, "'address' not set", err)
+}
+
 func TestNormalizeContractLocation(t *testing.T) {
 	e, cancel := newTestEthereum()
 	defer cancel()
@@ -3653,6 +4016,197 @@ func TestGenerateFFIBadABI(t *testing.T) {
 	assert.Regexp(t, "FF10346", err)
 }
 
+const testSolidityCompilerOutput = `{
+	"contracts": {
+		"simple.sol": {
+			"Simple": {
+				"abi": [{"type": "function", "name": "set", "inputs": [{"name": "x", "type": "uint256"}]}]
+			}
+		}
+	}
+}`
+
+func TestGenerateDeployDefinition(t *testing.T) {
+	e, _ := newTestEthereum()
+	definition, err := e.GenerateDeployDefinition(context.Background(), &fftypes.FFI{
+		Methods: []*fftypes.FFIMethod{
+			{
+				Name: "set",
+				Params: fftypes.FFIParams{
+					{Name: "newValue", Schema: fftypes.JSONAnyPtr(`{"type":"integer","details":{"type":"uint256"}}`)},
+				},
+			},
+		},
+		Events: []*fftypes.FFIEvent{
+			{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "Changed"}},
+		},
+		Errors: []*fftypes.FFIError{
+			{FFIErrorDefinition: fftypes.FFIErrorDefinition{Name: "Unauthorized"}},
+		},
+	}, fftypes.FFIParams{
+		{Name: "initialValue", Schema: fftypes.JSONAnyPtr(`{"type":"integer","details":{"type":"uint256"}}`)},
+	})
+	assert.NoError(t, err)
+
+	var contractABI abi.ABI
+	err = json.Unmarshal(definition.Bytes(), &contractABI)
+	assert.NoError(t, err)
+	assert.Len(t, contractABI, 4)
+	assert.Equal(t, abi.Constructor, contractABI[0].Type)
+	assert.Equal(t, "initialValue", contractABI[0].Inputs[0].Name)
+	assert.Equal(t, "set", contractABI[1].Name)
+	assert.Equal(t, abi.Event, contractABI[2].Type)
+	assert.Equal(t, abi.Error, contractABI[3].Type)
+}
+
+func TestGenerateDeployDefinitionBadSchema(t *testing.T) {
+	e, _ := newTestEthereum()
+	badParams := fftypes.FFIParams{{Name: "bad", Schema: fftypes.JSONAnyPtr(`{"type":`)}}
+
+	_, err := e.GenerateDeployDefinition(context.Background(), &fftypes.FFI{}, badParams)
+	assert.Regexp(t, "FF10613.*constructor", err)
+
+	_, err = e.GenerateDeployDefinition(context.Background(), &fftypes.FFI{
+		Methods: []*fftypes.FFIMethod{{Pathname: "set", Params: badParams}},
+	}, nil)
+	assert.Regexp(t, "FF10613.*set", err)
+
+	_, err = e.GenerateDeployDefinition(context.Background(), &fftypes.FFI{
+		Events: []*fftypes.FFIEvent{{Pathname: "Changed", FFIEventDefinition: fftypes.FFIEventDefinition{Params: badParams}}},
+	}, nil)
+	assert.Regexp(t, "FF10613.*Changed", err)
+
+	_, err = e.GenerateDeployDefinition(context.Background(), &fftypes.FFI{
+		Errors: []*fftypes.FFIError{{Pathname: "Unauthorized", FFIErrorDefinition: fftypes.FFIErrorDefinition{Params: badParams}}},
+	}, nil)
+	assert.Regexp(t, "FF10613.*Unauthorized", err)
+}
+
+func TestCompileContractInterfaceFromSource(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			headers := body["headers"].(map[string]interface{})
+			assert.Equal(t, core.CompileContract, headers["type"])
+			assert.Equal(t, "contract Simple {}", body["source"])
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONAnyPtr(testSolidityCompilerOutput))(req)
+		})
+
+	input, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{"source": "contract Simple {}"}`))
+	assert.NoError(t, err)
+
+	ffi, err := e.GenerateFFI(context.Background(), &fftypes.FFIGenerationRequest{
+		Name:    "Simple",
+		Version: "v0.0.1",
+		Input:   input,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "set", ffi.Methods[0].Name)
+}
+
+func TestCompileContractInterfaceFromSourceNotSupported(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONAnyPtr(`{"error":"FFEC100130: failure"}`)))
+
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{"source": "contract Simple {}"}`))
+	assert.Regexp(t, "FF10429", err)
+}
+
+func TestCompileContractInterfaceFromSourceFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONAnyPtr(`{"error":"pop"}`)))
+
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{"source": "contract Simple {}"}`))
+	assert.Regexp(t, "FF10111.*pop", err)
+}
+
+func TestCompileContractInterfaceFromCompilerOutput(t *testing.T) {
+	e, _ := newTestEthereum()
+	input, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{
+		"contract": "simple.sol:Simple",
+		"compilerOutput": `+testSolidityCompilerOutput+`
+	}`))
+	assert.NoError(t, err)
+	var generationInput FFIGenerationInput
+	err = json.Unmarshal(input.Bytes(), &generationInput)
+	assert.NoError(t, err)
+	assert.Equal(t, "set", (*generationInput.ABI)[0].Name)
+}
+
+func TestCompileContractInterfaceBadInput(t *testing.T) {
+	e, _ := newTestEthereum()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`[]`))
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestCompileContractInterfaceMissingSource(t *testing.T) {
+	e, _ := newTestEthereum()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10346", err)
+}
+
+func TestCompileContractInterfaceCompileErrors(t *testing.T) {
+	e, _ := newTestEthereum()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{
+		"compilerOutput": {
+			"errors": [
+				{"severity": "warning", "formattedMessage": "unused variable"},
+				{"severity": "error", "formattedMessage": "missing semicolon"}
+			]
+		}
+	}`))
+	assert.Regexp(t, "FF10586.*missing semicolon", err)
+}
+
+func TestCompileContractInterfaceContractNotFound(t *testing.T) {
+	e, _ := newTestEthereum()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{
+		"contract": "Other",
+		"compilerOutput": `+testSolidityCompilerOutput+`
+	}`))
+	assert.Regexp(t, "FF10587", err)
+}
+
+func TestCompileContractInterfaceContractNotSpecified(t *testing.T) {
+	e, _ := newTestEthereum()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{
+		"compilerOutput": {
+			"contracts": {
+				"a.sol": {"A": {"abi": []}},
+				"b.sol": {"B": {"abi": []}}
+			}
+		}
+	}`))
+	assert.Regexp(t, "FF10588", err)
+}
+
+func TestCompileContractInterfaceContractAmbiguous(t *testing.T) {
+	e, _ := newTestEthereum()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{
+		"contract": "A",
+		"compilerOutput": {
+			"contracts": {
+				"a.sol": {"A": {"abi": []}},
+				"b.sol": {"A": {"abi": []}}
+			}
+		}
+	}`))
+	assert.Regexp(t, "FF10588", err)
+}
+
 func TestGenerateEventSignature(t *testing.T) {
 	e, _ := newTestEthereum()
 	complexParam := fftypes.JSONObject{
@@ -3842,6 +4396,60 @@ func TestGenerateErrorSignatureInvalid(t *testing.T) {
 	assert.Equal(t, "", signature)
 }
 
+func TestGenerateMethodSelector(t *testing.T) {
+	e, _ := newTestEthereum()
+	method := &fftypes.FFIMethod{
+		Name: "transfer",
+		Params: []*fftypes.FFIParam{
+			{
+				Name:   "to",
+				Schema: fftypes.JSONAnyPtr(`{"type": "string", "details": {"type": "address"}}`),
+			},
+			{
+				Name:   "value",
+				Schema: fftypes.JSONAnyPtr(`{"type": "integer", "details": {"type": "uint256"}}`),
+			},
+		},
+	}
+
+	signature, selector, err := e.GenerateMethodSelector(context.Background(), method)
+	assert.NoError(t, err)
+	assert.Equal(t, "transfer(address,uint256)", signature)
+	assert.Equal(t, "0xa9059cbb", selector)
+}
+
+func TestGenerateMethodSelectorBadSchema(t *testing.T) {
+	e, _ := newTestEthereum()
+	method := &fftypes.FFIMethod{
+		Name: "set",
+		Params: []*fftypes.FFIParam{
+			{
+				Name:   "x",
+				Schema: fftypes.JSONAnyPtr(`{"!bad": "bad"`),
+			},
+		},
+	}
+
+	_, _, err := e.GenerateMethodSelector(context.Background(), method)
+	assert.Error(t, err)
+}
+
+func TestGenerateMethodSelectorBadType(t *testing.T) {
+	e, _ := newTestEthereum()
+	method := &fftypes.FFIMethod{
+		Name: "set",
+		Params: []*fftypes.FFIParam{
+			{
+				Name:   "x",
+				Schema: fftypes.JSONAnyPtr(`{"type": "integer", "details": {"type": "wrong"}}`),
+			},
+		},
+	}
+
+	_, _, err := e.GenerateMethodSelector(context.Background(), method)
+	assert.Error(t, err)
+}
+
 func TestSubmitNetworkAction(t *testing.T) {
 	e, _ := newTestEthereum()
 	httpmock.ActivateNonDefault(e.client.GetClient())
@@ -5002,3 +5610,29 @@ func TestCheckOverLappingLocationsSame(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, result)
 }
+
+func TestPing(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+
+	err := e.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPingFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewStringResponder(500, "pop"))
+
+	err := e.Ping(context.Background())
+	assert.Regexp(t, "FF10111", err)
+}
@@ -21,11 +21,13 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly-signer/pkg/abi"
@@ -277,6 +279,25 @@ func (s *streamManager) createSubscription(ctx context.Context, stream, subName,
 	return &sub, nil
 }
 
+func (s *streamManager) updateSubscription(ctx context.Context, subID string, listenerID *fftypes.UUID, firstEvent string) error {
+	fromBlock, err := resolveFromBlock(ctx, firstEvent, "")
+	if err != nil {
+		return err
+	}
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetBody(fftypes.JSONObject{"fromBlock": fromBlock}).
+		Patch("/subscriptions/" + subID)
+	if err != nil || !res.IsSuccess() {
+		// EthConnect does not implement PATCH on subscriptions, only EVMConnect does
+		if res.StatusCode() == http.StatusMethodNotAllowed || res.StatusCode() == http.StatusNotImplemented {
+			return i18n.NewError(ctx, coremsgs.MsgContractListenerUpdateNotSupported, listenerID)
+		}
+		return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgEthConnectorRESTErr)
+	}
+	return nil
+}
+
 func (s *streamManager) deleteSubscription(ctx context.Context, subID string, okNotFound bool) error {
 	res, err := s.client.R().
 		SetContext(ctx).
@@ -37,6 +37,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 )
@@ -56,6 +57,7 @@ type Fabric struct {
 	capabilities   *blockchain.Capabilities
 	callbacks      common.BlockchainCallbacks
 	client         *resty.Client
+	failover       *common.ConnectorFailover
 	streams        *streamManager
 	streamID       map[string]string
 	idCache        map[string]*fabIdentity
@@ -207,6 +209,10 @@ func (f *Fabric) Init(ctx context.Context, cancelCtx context.CancelFunc, conf co
 	if err == nil {
 		f.client, err = ffresty.New(f.ctx, fabconnectConf)
 	}
+	if err == nil {
+		tracing.InstrumentClient(f.client)
+		f.failover, err = common.NewConnectorFailover(f.ctx, fabconnectConf, f.client)
+	}
 
 	if err != nil {
 		return err
@@ -243,6 +249,7 @@ func (f *Fabric) Init(ctx context.Context, cancelCtx context.CancelFunc, conf co
 	f.closed = make(map[string]chan struct{})
 	f.wsconn = make(map[string]wsclient.WSClient)
 	f.streams = newStreamManager(f.client, f.signer, f.cache, f.fabconnectConf.GetUint(FabconnectConfigBatchSize), uint(f.fabconnectConf.GetDuration(FabconnectConfigBatchTimeout).Milliseconds()))
+	f.failover.Start()
 
 	return nil
 }
@@ -255,7 +262,7 @@ func (f *Fabric) StartNamespace(ctx context.Context, namespace string) (err erro
 	log.L(f.ctx).Debugf("Starting namespace: %s", namespace)
 	topic := f.getTopic(namespace)
 
-	f.wsconn[namespace], err = wsclient.New(ctx, f.wsConfig, nil, func(ctx context.Context, w wsclient.WSClient) error {
+	f.wsconn[namespace], err = wsclient.New(ctx, f.wsConfig, f.failover.WSPreConnect(f.wsConfig), func(ctx context.Context, w wsclient.WSClient) error {
 		// Send a subscribe to our topic after each connect/reconnect
 		b, _ := json.Marshal(&fabWSCommandPayload{
 			Type:  "listen",
@@ -317,6 +324,15 @@ func (f *Fabric) Capabilities() *blockchain.Capabilities {
 	return f.capabilities
 }
 
+func (f *Fabric) ConnectorStatus() *core.ConnectorStatus {
+	return f.failover.Status()
+}
+
+func (f *Fabric) Ping(ctx context.Context) error {
+	_, err := f.streams.getEventStreams(ctx)
+	return err
+}
+
 func decodeJSONPayload(ctx context.Context, payloadString string) *fftypes.JSONObject {
 	bytes, err := base64.StdEncoding.DecodeString(payloadString)
 	if err != nil {
@@ -871,6 +887,44 @@ func (f *Fabric) QueryContract(ctx context.Context, signingKey string, location
 	return output.Result, nil
 }
 
+func (f *Fabric) SimulateContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (*core.Simulation, error) {
+	method, _, err := f.recoverFFI(ctx, parsedMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	fabricOnChainLocation, err := parseContractLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixItems := make([]*PrefixItem, len(method.Params))
+	for i, param := range method.Params {
+		prefixItems[i] = &PrefixItem{
+			Name: param.Name,
+			Type: "string",
+		}
+	}
+
+	// Evaluating the transaction runs the chaincode on a peer without ordering it, so an error returned
+	// by the connector is the error the chaincode would return when the transaction is endorsed
+	res, err := f.queryContractMethod(ctx, fabricOnChainLocation.Channel, fabricOnChainLocation.Chaincode, method.Name, signingKey, "", prefixItems, input, options)
+	if err != nil {
+		if res != nil && res.StatusCode() != 0 {
+			var resErr common.BlockchainRESTError
+			if json.Unmarshal(res.Body(), &resErr) == nil && resErr.Error != "" {
+				return &core.Simulation{Success: false, RevertReason: resErr.Error}, nil
+			}
+		}
+		return nil, err
+	}
+	output := &fabQueryNamedOutput{}
+	if err = json.Unmarshal(res.Body(), output); err != nil {
+		return nil, err
+	}
+	return &core.Simulation{Success: true, Output: output.Result}, nil
+}
+
 func jsonEncodeInput(params map[string]interface{}) (output map[string]interface{}, err error) {
 	output = make(map[string]interface{}, len(params))
 	for field, value := range params {
@@ -989,9 +1043,15 @@ func (f *Fabric) AddContractListener(ctx context.Context, listener *core.Contrac
 		return err
 	}
 	listener.BackendID = result.ID
+	listener.Options.FromBlock = result.FromBlock
 	return nil
 }
 
+func (f *Fabric) UpdateContractListener(ctx context.Context, subscription *core.ContractListener) error {
+	// Fabconnect subscriptions cannot be changed once created
+	return i18n.NewError(ctx, coremsgs.MsgContractListenerUpdateNotSupported, subscription.ID)
+}
+
 func (f *Fabric) DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error {
 	return f.streams.deleteSubscription(ctx, subscription.BackendID, okNotFound)
 }
@@ -1016,6 +1076,14 @@ func (f *Fabric) GenerateFFI(ctx context.Context, generationRequest *fftypes.FFI
 	return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationUnsupported)
 }
 
+func (f *Fabric) GenerateDeployDefinition(ctx context.Context, ffi *fftypes.FFI, constructorParams fftypes.FFIParams) (*fftypes.JSONAny, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (f *Fabric) CompileContractInterface(ctx context.Context, input *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationUnsupported)
+}
+
 func (f *Fabric) GenerateEventSignature(ctx context.Context, event *fftypes.FFIEventDefinition) (string, error) {
 	return event.Name, nil
 }
@@ -1034,6 +1102,11 @@ func (f *Fabric) GenerateErrorSignature(ctx context.Context, event *fftypes.FFIE
 	return ""
 }
 
+func (f *Fabric) GenerateMethodSelector(ctx context.Context, method *fftypes.FFIMethod) (string, string, error) {
+	// Chaincode functions are invoked by name, so there is no separate selector
+	return method.Name, "", nil
+}
+
 func (f *Fabric) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (version int, err error) {
 	fabricOnChainLocation, err := parseContractLocation(ctx, location)
 	if err != nil {
@@ -223,6 +223,17 @@ func TestInitMissingURL(t *testing.T) {
 	assert.Regexp(t, "FF10138.*url", err)
 }
 
+func TestGenerateMethodSelector(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+
+	signature, selector, err := e.GenerateMethodSelector(context.Background(), &fftypes.FFIMethod{Name: "createAsset"})
+	assert.NoError(t, err)
+	assert.Equal(t, "createAsset", signature)
+	assert.Empty(t, selector)
+}
+
 func TestGenerateErrorSignatureNoOp(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -339,6 +350,41 @@ func TestInitAllNewStreamsAndWSEvent(t *testing.T) {
 
 }
 
+func TestInitFailoverBadURL(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(common.FailoverConfigURLs, []string{"not a url"})
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10596", err)
+}
+
+func TestInitFailoverConnectorStatus(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(common.FailoverConfigURLs, []string{"http://localhost:23456"})
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	assert.Nil(t, e.ConnectorStatus())
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+
+	status := e.ConnectorStatus()
+	assert.Equal(t, "http://localhost:12345", status.Active)
+	assert.Len(t, status.Endpoints, 2)
+}
+
 func TestWSInitFail(t *testing.T) {
 
 	e, cancel := newTestFabric()
@@ -1916,12 +1962,14 @@ func TestAddSubscription(t *testing.T) {
 			var body map[string]interface{}
 			json.NewDecoder(req.Body).Decode(&body)
 			assert.Equal(t, "0", body["fromBlock"])
-			return httpmock.NewJsonResponderOrPanic(200, &subscription{})(req)
+			return httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1", FromBlock: "0"})(req)
 		})
 
 	err := e.AddContractListener(context.Background(), sub, "")
 
 	assert.NoError(t, err)
+	assert.Equal(t, "sub1", sub.BackendID)
+	assert.Equal(t, "0", sub.Options.FromBlock)
 }
 
 func TestAddSubscriptionNoFiltersFail(t *testing.T) {
@@ -2093,6 +2141,15 @@ func TestAddSubscriptionFail(t *testing.T) {
 	assert.Regexp(t, "FF10284.*pop", err)
 }
 
+func TestUpdateContractListenerNotSupported(t *testing.T) {
+	f, cancel := newTestFabric()
+	defer cancel()
+
+	err := f.UpdateContractListener(context.Background(), &core.ContractListener{ID: fftypes.NewUUID()})
+
+	assert.Regexp(t, "FF10506", err)
+}
+
 func TestDeleteSubscription(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -2953,6 +3010,18 @@ func TestGenerateFFI(t *testing.T) {
 	assert.Regexp(t, "FF10347", err)
 }
 
+func TestCompileContractInterface(t *testing.T) {
+	e, _ := newTestFabric()
+	_, err := e.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10347", err)
+}
+
+func TestGenerateDeployDefinition(t *testing.T) {
+	e, _ := newTestFabric()
+	_, err := e.GenerateDeployDefinition(context.Background(), &fftypes.FFI{}, nil)
+	assert.Regexp(t, "FF10429", err)
+}
+
 func TestGenerateEventSignature(t *testing.T) {
 	e, _ := newTestFabric()
 	signature, err := e.GenerateEventSignature(context.Background(), &fftypes.FFIEventDefinition{Name: "Changed"})
@@ -3560,6 +3629,166 @@ func TestQueryContractBadFFI(t *testing.T) {
 	assert.Regexp(t, "FF10457", err)
 }
 
+func TestSimulateContractOK(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Channel:   "firefly",
+		Chaincode: "simplestorage",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "signer1", body["headers"].(map[string]interface{})["signer"])
+			assert.Equal(t, "firefly", body["headers"].(map[string]interface{})["channel"])
+			assert.Equal(t, "simplestorage", body["headers"].(map[string]interface{})["chaincode"])
+			assert.Equal(t, "1", body["args"].(map[string]interface{})["x"])
+			assert.Equal(t, "2", body["args"].(map[string]interface{})["y"])
+			return httpmock.NewJsonResponderOrPanic(200, &fabQueryNamedOutput{Result: "3"})(req)
+		})
+	var errors []*fftypes.FFIError
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "signer1", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.NoError(t, err)
+	assert.True(t, sim.Success)
+	assert.Equal(t, "3", sim.Output)
+}
+
+func TestSimulateContractChaincodeError(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Channel:   "firefly",
+		Chaincode: "simplestorage",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{
+			Error: "chaincode response 500, insufficient funds",
+		}))
+	var errors []*fftypes.FFIError
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "signer1", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.NoError(t, err)
+	assert.False(t, sim.Success)
+	assert.Equal(t, "chaincode response 500, insufficient funds", sim.RevertReason)
+}
+
+func TestSimulateContractFabconnectError(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Channel:   "firefly",
+		Chaincode: "simplestorage",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		httpmock.NewJsonResponderOrPanic(400, &fabQueryNamedOutput{}))
+	var errors []*fftypes.FFIError
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "signer1", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "FF10284", err)
+	assert.Nil(t, sim)
+}
+
+func TestSimulateContractFabconnectUnavailable(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Channel:   "firefly",
+		Chaincode: "simplestorage",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	var errors []*fftypes.FFIError
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "signer1", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "FF10284", err)
+	assert.Nil(t, sim)
+}
+
+func TestSimulateContractUnmarshalResponseError(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Channel:   "firefly",
+		Chaincode: "simplestorage",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		httpmock.NewStringResponder(200, "[definitely not JSON}"))
+	var errors []*fftypes.FFIError
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	sim, err := e.SimulateContract(context.Background(), "signer1", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "invalid character", err)
+	assert.Nil(t, sim)
+}
+
+func TestSimulateContractBadLocation(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	method := testFFIMethod()
+	var errors []*fftypes.FFIError
+	parsedMethod, err := e.ParseInterface(context.Background(), method, errors)
+	assert.NoError(t, err)
+	_, err = e.SimulateContract(context.Background(), "", fftypes.JSONAnyPtr(`{"validLocation": false}`), parsedMethod, nil, nil)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestSimulateContractBadFFI(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	_, err := e.SimulateContract(context.Background(), "", nil, nil, nil, nil)
+	assert.Regexp(t, "FF10457", err)
+}
+
 func TestCheckOverLappingLocationsEmpty(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -3659,3 +3888,31 @@ func TestCheckOverLappingLocationsSameChannelDifferentChaincode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, result)
 }
+
+func TestPing(t *testing.T) {
+	f, cancel := newTestFabric()
+	defer cancel()
+	f.streams = newTestStreamManager(f.client, "signer")
+	httpmock.ActivateNonDefault(f.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+
+	err := f.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPingFail(t *testing.T) {
+	f, cancel := newTestFabric()
+	defer cancel()
+	f.streams = newTestStreamManager(f.client, "signer")
+	httpmock.ActivateNonDefault(f.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewStringResponder(500, "pop"))
+
+	err := f.Ping(context.Background())
+	assert.Regexp(t, "FF10284", err)
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 )
@@ -175,6 +176,7 @@ func (t *Tezos) Init(ctx context.Context, cancelCtx context.CancelFunc, conf con
 	if err != nil {
 		return err
 	}
+	tracing.InstrumentClient(t.client)
 
 	t.pluginTopic = tezosconnectConf.GetString(TezosconnectConfigTopic)
 	if t.pluginTopic == "" {
@@ -261,6 +263,11 @@ func (t *Tezos) Capabilities() *blockchain.Capabilities {
 	return t.capabilities
 }
 
+func (t *Tezos) Ping(ctx context.Context) error {
+	_, err := t.streams.getEventStreams(ctx)
+	return err
+}
+
 func (t *Tezos) AddFireflySubscription(ctx context.Context,
 	namespace *core.Namespace,
 	contract *blockchain.MultipartyContract,
@@ -395,6 +402,36 @@ func (t *Tezos) QueryContract(ctx context.Context, signingKey string, location *
 	return output, nil
 }
 
+func (t *Tezos) SimulateContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (*core.Simulation, error) {
+	tezosLocation, err := t.parseContractLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	methodName, michelsonInput, err := t.prepareRequest(ctx, parsedMethod, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// The connector does not estimate fees, so the simulation is the outcome of evaluating the call
+	res, err := t.queryContractMethod(ctx, tezosLocation.Address, methodName, signingKey, michelsonInput, options)
+	if err != nil {
+		if res != nil && res.StatusCode() != 0 {
+			var resErr common.BlockchainRESTError
+			if json.Unmarshal(res.Body(), &resErr) == nil && resErr.SubmissionRejected {
+				return &core.Simulation{Success: false, RevertReason: resErr.Error}, nil
+			}
+		}
+		return nil, err
+	}
+
+	sim := &core.Simulation{Success: true}
+	if err = json.Unmarshal(res.Body(), &sim.Output); err != nil {
+		return nil, err
+	}
+	return sim, nil
+}
+
 func (t *Tezos) ParseInterface(ctx context.Context, method *fftypes.FFIMethod, errors []*fftypes.FFIError) (interface{}, error) {
 	return &ffiMethodAndErrors{
 		method: method,
@@ -473,9 +510,17 @@ func (t *Tezos) AddContractListener(
 		return err
 	}
 	listener.BackendID = result.ID
+	if listener.Options != nil {
+		listener.Options.FromBlock = result.FromBlock
+	}
 	return nil
 }
 
+func (t *Tezos) UpdateContractListener(ctx context.Context, subscription *core.ContractListener) error {
+	// Tezosconnect subscriptions cannot be changed once created
+	return i18n.NewError(ctx, coremsgs.MsgContractListenerUpdateNotSupported, subscription.ID)
+}
+
 func (t *Tezos) DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error {
 	return t.streams.deleteSubscription(ctx, subscription.BackendID, okNotFound)
 }
@@ -536,10 +581,23 @@ func (t *Tezos) GenerateErrorSignature(ctx context.Context, event *fftypes.FFIEr
 	return ""
 }
 
+func (t *Tezos) GenerateMethodSelector(ctx context.Context, method *fftypes.FFIMethod) (string, string, error) {
+	// Michelson entrypoints are invoked by name, so there is no separate selector
+	return method.Name, "", nil
+}
+
 func (t *Tezos) GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error) {
 	return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationUnsupported)
 }
 
+func (t *Tezos) GenerateDeployDefinition(ctx context.Context, ffi *fftypes.FFI, constructorParams fftypes.FFIParams) (*fftypes.JSONAny, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (t *Tezos) CompileContractInterface(ctx context.Context, input *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationUnsupported)
+}
+
 func (t *Tezos) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (version int, err error) {
 	// Part of the FIR-12. https://github.com/hyperledger/firefly-fir/pull/12
 	// Not actual for the Tezos as it's batch pin contract was after the proposal.
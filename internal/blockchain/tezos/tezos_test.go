@@ -829,11 +829,13 @@ func TestAddSubscription(t *testing.T) {
 	}
 
 	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
-		httpmock.NewJsonResponderOrPanic(200, &subscription{}))
+		httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1", FromBlock: "0"}))
 
 	err := tz.AddContractListener(context.Background(), sub, "")
 
 	assert.NoError(t, err)
+	assert.Equal(t, "sub1", sub.BackendID)
+	assert.Equal(t, "0", sub.Options.FromBlock)
 }
 
 func TestAddSubscriptionWithoutLocation(t *testing.T) {
@@ -980,6 +982,15 @@ func TestAddSubscriptionTwoManyFiltersFail(t *testing.T) {
 	assert.Regexp(t, "FF10476", err)
 }
 
+func TestUpdateContractListenerNotSupported(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	err := tz.UpdateContractListener(context.Background(), &core.ContractListener{ID: fftypes.NewUUID()})
+
+	assert.Regexp(t, "FF10506", err)
+}
+
 func TestDeleteSubscription(t *testing.T) {
 	tz, cancel := newTestTezos()
 	defer cancel()
@@ -1537,6 +1548,169 @@ func TestQueryContractUnmarshalResponseError(t *testing.T) {
 	assert.Regexp(t, "invalid character", err)
 }
 
+func TestSimulateContractOK(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	httpmock.ActivateNonDefault(tz.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "KT12345",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"varNat":       float64(1),
+		"varInt":       float64(2),
+		"varString":    "str",
+		"varStringOpt": "optional str",
+		"varBytes":     "0xAA",
+		"varBool":      true,
+		"varAddress":   "tz1Y6GnVhC4EpcDDSmD3ibcC4WX6DJ4Q1QLN",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			headers := body["headers"].(map[string]interface{})
+			assert.Equal(t, "Query", headers["type"])
+			assert.Equal(t, "KT12345", body["to"].(string))
+			assert.Equal(t, "tz12345", body["from"].(string))
+			return httpmock.NewJsonResponderOrPanic(200, "result")(req)
+		})
+
+	parsedMethod, err := tz.ParseInterface(context.Background(), method, nil)
+	assert.NoError(t, err)
+
+	sim, err := tz.SimulateContract(context.Background(), "tz12345", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.NoError(t, err)
+	assert.True(t, sim.Success)
+	assert.Equal(t, "result", sim.Output)
+	assert.Nil(t, sim.GasEstimate)
+}
+
+func TestSimulateContractRejected(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	httpmock.ActivateNonDefault(tz.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "KT12345",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"varNat":       float64(1),
+		"varInt":       float64(2),
+		"varString":    "str",
+		"varStringOpt": "optional str",
+		"varBytes":     "0xAA",
+		"varBool":      true,
+		"varAddress":   "tz1Y6GnVhC4EpcDDSmD3ibcC4WX6DJ4Q1QLN",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{
+			Error:              "script_rejected",
+			SubmissionRejected: true,
+		}))
+
+	parsedMethod, err := tz.ParseInterface(context.Background(), method, nil)
+	assert.NoError(t, err)
+
+	sim, err := tz.SimulateContract(context.Background(), "tz12345", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.NoError(t, err)
+	assert.False(t, sim.Success)
+	assert.Equal(t, "script_rejected", sim.RevertReason)
+}
+
+func TestSimulateContractTezosconnectError(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	httpmock.ActivateNonDefault(tz.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "KT12345",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"varNat":       float64(1),
+		"varInt":       float64(2),
+		"varString":    "str",
+		"varStringOpt": "optional str",
+		"varBytes":     "0xAA",
+		"varBool":      true,
+		"varAddress":   "tz1Y6GnVhC4EpcDDSmD3ibcC4WX6DJ4Q1QLN",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{Error: "node unavailable"}))
+
+	parsedMethod, err := tz.ParseInterface(context.Background(), method, nil)
+	assert.NoError(t, err)
+
+	sim, err := tz.SimulateContract(context.Background(), "tz12345", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "FF10283.*node unavailable", err)
+	assert.Nil(t, sim)
+}
+
+func TestSimulateContractUnmarshalResponseError(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	httpmock.ActivateNonDefault(tz.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	location := &Location{
+		Address: "KT12345",
+	}
+	method := testFFIMethod()
+	params := map[string]interface{}{
+		"varNat":       float64(1),
+		"varInt":       float64(2),
+		"varString":    "str",
+		"varStringOpt": "optional str",
+		"varBytes":     "0xAA",
+		"varBool":      true,
+		"varAddress":   "tz1Y6GnVhC4EpcDDSmD3ibcC4WX6DJ4Q1QLN",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		httpmock.NewStringResponder(200, "[definitely not JSON}"))
+
+	parsedMethod, err := tz.ParseInterface(context.Background(), method, nil)
+	assert.NoError(t, err)
+
+	sim, err := tz.SimulateContract(context.Background(), "tz12345", fftypes.JSONAnyPtrBytes(locationBytes), parsedMethod, params, nil)
+	assert.Regexp(t, "invalid character", err)
+	assert.Nil(t, sim)
+}
+
+func TestSimulateContractErrorPrepare(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	location := &Location{
+		Address: "KT12345",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+
+	_, err = tz.SimulateContract(context.Background(), "tz12345", fftypes.JSONAnyPtrBytes(locationBytes), "wrong", nil, nil)
+	assert.Regexp(t, "FF10457", err)
+}
+
+func TestSimulateContractAddressNotSet(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	_, err := tz.SimulateContract(context.Background(), "tz12345", fftypes.JSONAnyPtr("{}"), nil, nil, nil)
+	assert.Regexp(t, "'address' not set", err)
+}
+
 func TestGetFFIParamValidator(t *testing.T) {
 	tz, cancel := newTestTezos()
 	defer cancel()
@@ -1558,6 +1732,22 @@ func TestGenerateFFI(t *testing.T) {
 	assert.Regexp(t, "FF10347", err)
 }
 
+func TestCompileContractInterface(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	_, err := tz.CompileContractInterface(context.Background(), fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10347", err)
+}
+
+func TestGenerateDeployDefinition(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	_, err := tz.GenerateDeployDefinition(context.Background(), &fftypes.FFI{}, nil)
+	assert.Regexp(t, "FF10429", err)
+}
+
 func TestConvertDeprecatedContractConfigNoChaincode(t *testing.T) {
 	tz, cancel := newTestTezos()
 	defer cancel()
@@ -2075,6 +2265,16 @@ func TestValidateInvokeRequest(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGenerateMethodSelector(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	signature, selector, err := tz.GenerateMethodSelector(context.Background(), &fftypes.FFIMethod{Name: "transfer"})
+	assert.NoError(t, err)
+	assert.Equal(t, "transfer", signature)
+	assert.Empty(t, selector)
+}
+
 func TestGenerateErrorSignature(t *testing.T) {
 	tz, cancel := newTestTezos()
 	defer cancel()
@@ -2198,3 +2398,31 @@ func TestCheckOverlappingLocationsSameLocation(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, result)
 }
+
+func TestPing(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	tz.streams = newTestStreamManager(tz.client)
+	httpmock.ActivateNonDefault(tz.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+
+	err := tz.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPingFail(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+	tz.streams = newTestStreamManager(tz.client)
+	httpmock.ActivateNonDefault(tz.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewStringResponder(500, "pop"))
+
+	err := tz.Ping(context.Background())
+	assert.Regexp(t, "FF10283", err)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broadcast
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/encryption"
+)
+
+// dataKeys returns the ID of the key each data item must be encrypted with, based on the messages that
+// reference it. If data is referenced by messages that use different keys, the first key is used.
+func (bm *broadcastManager) dataKeys(ctx context.Context, messages []*core.Message) (map[fftypes.UUID]string, error) {
+	if bm.keys == nil {
+		return nil, nil
+	}
+	keys := make(map[fftypes.UUID]string)
+	for _, msg := range messages {
+		keyID, err := bm.keys.KeyForMessage(ctx, &msg.Header)
+		if err != nil {
+			return nil, err
+		}
+		if keyID == "" {
+			continue
+		}
+		for _, dataRef := range msg.Data {
+			if keys[*dataRef.ID] == "" {
+				keys[*dataRef.ID] = keyID
+			}
+		}
+	}
+	return keys, nil
+}
+
+// encryptBatch returns a copy of the batch to publish, in which the values that must be encrypted are replaced
+// with encrypted envelopes. The messages and the hashes in the batch are unchanged, so the batch hash is still
+// verified by every member of the network, while only members holding the keys can read the values.
+func (bm *broadcastManager) encryptBatch(ctx context.Context, batch *core.Batch) (*core.Batch, error) {
+	keys, err := bm.dataKeys(ctx, batch.Payload.Messages)
+	if err != nil || len(keys) == 0 {
+		return batch, err
+	}
+	encrypted := *batch
+	encrypted.Payload.Data = make(core.DataArray, len(batch.Payload.Data))
+	for i, d := range batch.Payload.Data {
+		keyID := keys[*d.ID]
+		if keyID == "" || d.Value == nil {
+			encrypted.Payload.Data[i] = d
+			continue
+		}
+		dataCopy := *d
+		if dataCopy.Value, err = encryption.EncryptValue(ctx, bm.keys, keyID, d.Value); err != nil {
+			return nil, err
+		}
+		encrypted.Payload.Data[i] = &dataCopy
+	}
+	return &encrypted, nil
+}
@@ -0,0 +1,267 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broadcast
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/batch"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/encryptionmocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBroadcastWithKeys(t *testing.T) (*broadcastManager, *encryptionmocks.KeyProvider, func()) {
+	bm, cancel := newTestBroadcast(t)
+	mkp := &encryptionmocks.KeyProvider{}
+	mkp.On("Key", mock.Anything, "key1").Return(make([]byte, 32), nil).Maybe()
+	mkp.On("Key", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	bm.keys = mkp
+	return bm, mkp, cancel
+}
+
+func TestNewBroadcastManagerWithKeys(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	mkp := &encryptionmocks.KeyProvider{}
+	mba := bm.batch.(*batchmocks.Manager)
+	b, err := NewBroadcastManager(bm.ctx, bm.namespace, bm.database, bm.blockchain, bm.exchange, bm.sharedstorage, bm.identity, bm.data, bm.batch, bm.syncasync, bm.multiparty, bm.metrics, bm.operations, bm.txHelper, bm.triggers, mkp)
+	assert.NoError(t, err)
+	assert.False(t, b.(*broadcastManager).deduplicate)
+	assert.Equal(t, mkp, b.(*broadcastManager).keys)
+	mba.AssertExpectations(t)
+}
+
+func TestDataKeys(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	data1 := fftypes.NewUUID()
+	data2 := fftypes.NewUUID()
+	data3 := fftypes.NewUUID()
+	messages := []*core.Message{
+		{Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic1"}}, Data: core.DataRefs{{ID: data1}}},
+		{Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic2"}}, Data: core.DataRefs{{ID: data1}, {ID: data2}}},
+		{Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic3"}}, Data: core.DataRefs{{ID: data3}}},
+	}
+	mkp.On("KeyForMessage", mock.Anything, &messages[0].Header).Return("key1", nil)
+	mkp.On("KeyForMessage", mock.Anything, &messages[1].Header).Return("key2", nil)
+	mkp.On("KeyForMessage", mock.Anything, &messages[2].Header).Return("", nil)
+
+	keys, err := bm.dataKeys(context.Background(), messages)
+	assert.NoError(t, err)
+	assert.Equal(t, map[fftypes.UUID]string{
+		*data1: "key1",
+		*data2: "key2",
+	}, keys)
+
+	mkp.AssertExpectations(t)
+}
+
+func TestDataKeysNoKeyProvider(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	keys, err := bm.dataKeys(context.Background(), []*core.Message{{}})
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestDataKeysFail(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	mkp.On("KeyForMessage", mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+
+	_, err := bm.dataKeys(context.Background(), []*core.Message{{}})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDispatchBatchDataKeysFail(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	mkp.On("KeyForMessage", mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+
+	err := bm.dispatchBatch(context.Background(), &batch.DispatchPayload{
+		Messages: []*core.Message{{}},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEncryptBatch(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	data1 := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"secret"`)}
+	data2 := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"public"`)}
+	data3 := &core.Data{ID: fftypes.NewUUID()}
+	b := &core.Batch{
+		Payload: core.BatchPayload{
+			Messages: []*core.Message{
+				{Data: core.DataRefs{{ID: data1.ID}, {ID: data3.ID}}},
+			},
+			Data: core.DataArray{data1, data2, data3},
+		},
+	}
+	mkp.On("KeyForMessage", mock.Anything, mock.Anything).Return("key1", nil)
+
+	encrypted, err := bm.encryptBatch(context.Background(), b)
+	assert.NoError(t, err)
+	assert.NotSame(t, b, encrypted)
+	assert.Equal(t, `"secret"`, b.Payload.Data[0].Value.String())
+	assert.Equal(t, data1.Hash, encrypted.Payload.Data[0].Hash)
+	ev := encryption.ParseEncryptedValue(encrypted.Payload.Data[0].Value)
+	assert.NotNil(t, ev)
+	assert.Equal(t, "key1", ev.KeyID)
+	assert.Same(t, data2, encrypted.Payload.Data[1])
+	assert.Same(t, data3, encrypted.Payload.Data[2])
+
+	mkp.AssertExpectations(t)
+}
+
+func TestEncryptBatchNoKeys(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	b := &core.Batch{
+		Payload: core.BatchPayload{
+			Messages: []*core.Message{{}},
+		},
+	}
+	mkp.On("KeyForMessage", mock.Anything, mock.Anything).Return("", nil)
+
+	encrypted, err := bm.encryptBatch(context.Background(), b)
+	assert.NoError(t, err)
+	assert.Same(t, b, encrypted)
+}
+
+func TestEncryptBatchKeyNotFound(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	data1 := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"secret"`)}
+	b := &core.Batch{
+		Payload: core.BatchPayload{
+			Messages: []*core.Message{{Data: core.DataRefs{{ID: data1.ID}}}},
+			Data:     core.DataArray{data1},
+		},
+	}
+	mkp.On("KeyForMessage", mock.Anything, mock.Anything).Return("unknown", nil)
+
+	_, err := bm.encryptBatch(context.Background(), b)
+	assert.Regexp(t, "FF10551", err)
+}
+
+func TestRunOperationBatchBroadcastEncryptFail(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	mkp.On("KeyForMessage", mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBatch(&core.Operation{}, &core.Batch{
+		Payload: core.BatchPayload{
+			Messages: []*core.Message{{}},
+		},
+	}))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRunOperationUploadBlobEncrypted(t *testing.T) {
+	bm, mkp, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+	bm.deduplicate = true // never applied to encrypted blobs
+
+	op := &core.Operation{}
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+	addUploadBlobInputs(op, data.ID, "key1")
+	assert.Equal(t, "key1", op.Input.GetString("encryptionKey"))
+
+	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	mdi := bm.database.(*databasemocks.Plugin)
+
+	reader := ioutil.NopCloser(strings.NewReader("some data"))
+	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, nil)
+	mps.On("UploadData", context.Background(), mock.MatchedBy(func(r io.Reader) bool {
+		encrypted, _ := io.ReadAll(r)
+		decrypter, err := encryption.DecryptBlob(context.Background(), mkp, bytes.NewReader(encrypted))
+		if err != nil {
+			return false
+		}
+		content, err := io.ReadAll(decrypter)
+		return err == nil && string(content) == "some data"
+	})).Return("123", nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(nil)
+
+	outputs, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, "key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, "123", outputs["payloadRef"])
+
+	mps.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadBlobEncryptFail(t *testing.T) {
+	bm, _, cancel := newTestBroadcastWithKeys(t)
+	defer cancel()
+
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+
+	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	reader := ioutil.NopCloser(strings.NewReader("some data"))
+	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, nil)
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(&core.Operation{}, data, blob, "unknown"))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "FF10551", err)
+
+	mdx.AssertExpectations(t)
+}
@@ -33,11 +33,13 @@ import (
 	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/internal/triggers"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/encryption"
 	"github.com/hyperledger/firefly/pkg/sharedstorage"
 )
 
@@ -48,6 +50,9 @@ type Manager interface {
 
 	NewBroadcast(in *core.MessageInOut) syncasync.Sender
 	BroadcastMessage(ctx context.Context, in *core.MessageInOut, waitConfirm bool) (out *core.Message, err error)
+	BroadcastBridgedMessage(ctx context.Context, in *core.MessageInOut) (out *core.Message, err error)
+	BroadcastMessages(ctx context.Context, in []*core.MessageInOut) ([]*core.MessageSubmitResult, error)
+	PreviewBatch(ctx context.Context, in *core.MessageInOut) (*batch.BatchPreview, error)
 	PublishDataValue(ctx context.Context, id string, idempotencyKey core.IdempotencyKey) (*core.Data, error)
 	PublishDataBlob(ctx context.Context, id string, idempotencyKey core.IdempotencyKey) (*core.Data, error)
 	Start() error
@@ -69,14 +74,18 @@ type broadcastManager struct {
 	sharedstorage         sharedstorage.Plugin
 	syncasync             syncasync.Bridge
 	multiparty            multiparty.Manager
+	batch                 batch.Manager
 	maxBatchPayloadLength int64
+	deduplicate           bool
 	metrics               metrics.Manager
 	operations            operations.Manager
 	txHelper              txcommon.Helper
+	triggers              triggers.Manager
+	keys                  encryption.KeyProvider
 }
 
-func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, dx dataexchange.Plugin, si sharedstorage.Plugin, im identity.Manager, dm data.Manager, ba batch.Manager, sa syncasync.Bridge, mult multiparty.Manager, mm metrics.Manager, om operations.Manager, txHelper txcommon.Helper) (Manager, error) {
-	if di == nil || im == nil || dm == nil || bi == nil || dx == nil || si == nil || mm == nil || om == nil || txHelper == nil {
+func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, dx dataexchange.Plugin, si sharedstorage.Plugin, im identity.Manager, dm data.Manager, ba batch.Manager, sa syncasync.Bridge, mult multiparty.Manager, mm metrics.Manager, om operations.Manager, txHelper txcommon.Helper, tm triggers.Manager, kp encryption.KeyProvider) (Manager, error) {
+	if di == nil || im == nil || dm == nil || bi == nil || dx == nil || si == nil || mm == nil || om == nil || txHelper == nil || tm == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "BroadcastManager")
 	}
 	bm := &broadcastManager{
@@ -90,10 +99,15 @@ func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Pl
 		sharedstorage:         si,
 		syncasync:             sa,
 		multiparty:            mult,
+		batch:                 ba,
 		maxBatchPayloadLength: config.GetByteSize(coreconfig.BroadcastBatchPayloadLimit),
-		metrics:               mm,
-		operations:            om,
-		txHelper:              txHelper,
+		// An upload cannot reuse published data when it must be encrypted with a different key, or not at all
+		deduplicate: config.GetBool(coreconfig.BroadcastDeduplicate) && kp == nil,
+		metrics:     mm,
+		operations:  om,
+		txHelper:    txHelper,
+		triggers:    tm,
+		keys:        kp,
 	}
 
 	if ba != nil && mult != nil {
@@ -104,6 +118,10 @@ func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Pl
 			BatchTimeout:   config.GetDuration(coreconfig.BroadcastBatchTimeout),
 			DisposeTimeout: config.GetDuration(coreconfig.BroadcastBatchAgentTimeout),
 		}
+		if kp != nil {
+			// Leave room for the base64 encoding of encrypted values, so encrypted batches can still be downloaded
+			bo.BatchMaxBytes = bm.maxBatchPayloadLength * 3 / 4
+		}
 
 		ba.RegisterDispatcher(broadcastDispatcherName,
 			true,
@@ -131,7 +149,11 @@ func (bm *broadcastManager) Name() string {
 func (bm *broadcastManager) dispatchBatch(ctx context.Context, payload *batch.DispatchPayload) error {
 
 	// Ensure all the blobs are published
-	if err := bm.uploadBlobs(ctx, payload.Batch.TX.ID, payload.Data, false /* batch processing does not currently use idempotency keys */); err != nil {
+	keys, err := bm.dataKeys(ctx, payload.Messages)
+	if err != nil {
+		return err
+	}
+	if err := bm.uploadBlobs(ctx, payload.Batch.TX.ID, payload.Data, keys, false /* batch processing does not currently use idempotency keys */); err != nil {
 		return err
 	}
 
@@ -159,11 +181,11 @@ func (bm *broadcastManager) dispatchBatch(ctx context.Context, payload *batch.Di
 	return bm.multiparty.SubmitBatchPin(ctx, &payload.Batch, payload.Pins, payloadRef, false /* batch processing does not currently use idempotency keys */)
 }
 
-func (bm *broadcastManager) uploadBlobs(ctx context.Context, tx *fftypes.UUID, data core.DataArray, idempotentSubmit bool) error {
+func (bm *broadcastManager) uploadBlobs(ctx context.Context, tx *fftypes.UUID, data core.DataArray, keys map[fftypes.UUID]string, idempotentSubmit bool) error {
 	for _, d := range data {
 		// We only need to send a blob if there is one, and it's not been uploaded to the shared storage
 		if d.Blob != nil && d.Blob.Hash != nil && d.Blob.Public == "" {
-			if err := bm.uploadDataBlob(ctx, tx, d, idempotentSubmit); err != nil {
+			if err := bm.uploadDataBlob(ctx, tx, d, keys[*d.ID], idempotentSubmit); err != nil {
 				return err
 			}
 		}
@@ -189,7 +211,7 @@ func (bm *broadcastManager) resolveData(ctx context.Context, id string) (*core.D
 	return d, nil
 }
 
-func (bm *broadcastManager) uploadDataBlob(ctx context.Context, tx *fftypes.UUID, d *core.Data, idempotentSubmit bool) error {
+func (bm *broadcastManager) uploadDataBlob(ctx context.Context, tx *fftypes.UUID, d *core.Data, keyID string, idempotentSubmit bool) error {
 	if d.Blob == nil || d.Blob.Hash == nil {
 		return i18n.NewError(ctx, coremsgs.MsgDataDoesNotHaveBlob)
 	}
@@ -199,7 +221,7 @@ func (bm *broadcastManager) uploadDataBlob(ctx context.Context, tx *fftypes.UUID
 		bm.namespace.Name,
 		tx,
 		core.OpTypeSharedStorageUploadBlob)
-	addUploadBlobInputs(op, d.ID)
+	addUploadBlobInputs(op, d.ID, keyID)
 	if err := bm.operations.AddOrReuseOperation(ctx, op); err != nil {
 		return err
 	}
@@ -212,7 +234,7 @@ func (bm *broadcastManager) uploadDataBlob(ctx context.Context, tx *fftypes.UUID
 		return i18n.NewError(ctx, coremsgs.MsgBlobNotFound, d.Blob.Hash)
 	}
 
-	_, err = bm.operations.RunOperation(ctx, opUploadBlob(op, d, blobs[0]), idempotentSubmit)
+	_, err = bm.operations.RunOperation(ctx, opUploadBlob(op, d, blobs[0], keyID), idempotentSubmit)
 	return err
 }
 
@@ -301,7 +323,7 @@ func (bm *broadcastManager) PublishDataBlob(ctx context.Context, id string, idem
 		}
 	}
 
-	if err = bm.uploadDataBlob(ctx, txid, d, idempotencyKey != ""); err != nil {
+	if err = bm.uploadDataBlob(ctx, txid, d, "" /* data published without a message is not encrypted */, idempotencyKey != ""); err != nil {
 		return nil, err
 	}
 
@@ -39,6 +39,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/triggermocks"
 	"github.com/hyperledger/firefly/mocks/txcommonmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
@@ -59,6 +60,7 @@ func newTestBroadcastCommon(t *testing.T, metricsEnabled bool) (*broadcastManage
 	mmi := &metricsmocks.Manager{}
 	mom := &operationmocks.Manager{}
 	mtx := &txcommonmocks.Helper{}
+	mtm := &triggermocks.Manager{}
 	mmi.On("IsMetricsEnabled").Return(metricsEnabled)
 	mbi.On("Name").Return("ut_blockchain").Maybe()
 	mpi.On("Name").Return("ut_sharedstorage").Maybe()
@@ -84,7 +86,7 @@ func newTestBroadcastCommon(t *testing.T, metricsEnabled bool) (*broadcastManage
 
 	ctx, cancel := context.WithCancel(context.Background())
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	b, err := NewBroadcastManager(ctx, ns, mdi, mbi, mdx, mpi, mim, mdm, mba, msa, mmp, mmi, mom, mtx)
+	b, err := NewBroadcastManager(ctx, ns, mdi, mbi, mdx, mpi, mim, mdm, mba, msa, mmp, mmi, mom, mtx, mtm, nil)
 	assert.NoError(t, err)
 	return b.(*broadcastManager), cancel
 }
@@ -101,7 +103,7 @@ func newTestBroadcastWithMetrics(t *testing.T) (*broadcastManager, func()) {
 }
 
 func TestInitFail(t *testing.T) {
-	_, err := NewBroadcastManager(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewBroadcastManager(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -511,7 +513,7 @@ func TestUploadBlobsGetBlobFail(t *testing.T) {
 				Hash: blob.Hash,
 			},
 		},
-	}, false)
+	}, nil, false)
 	assert.Regexp(t, "pop", err)
 
 	mdi.AssertExpectations(t)
@@ -543,7 +545,7 @@ func TestUploadBlobsGetBlobNotFound(t *testing.T) {
 				Hash: blob.Hash,
 			},
 		},
-	}, false)
+	}, nil, false)
 	assert.Regexp(t, "FF10239", err)
 
 	mdi.AssertExpectations(t)
@@ -572,7 +574,7 @@ func TestUploadBlobsGetBlobInsertOpFail(t *testing.T) {
 				Hash: blob.Hash,
 			},
 		},
-	}, true)
+	}, nil, true)
 	assert.EqualError(t, err, "pop")
 
 	mom.AssertExpectations(t)
@@ -18,10 +18,12 @@ package broadcast
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/syncasync"
@@ -56,10 +58,45 @@ func (bm *broadcastManager) BroadcastMessage(ctx context.Context, in *core.Messa
 	return &in.Message, err
 }
 
+// BroadcastBridgedMessage broadcasts a message submitted by a bridge, without waiting for confirmation. Only a bridged
+// message can use an idempotency key with the prefix reserved for bridges.
+func (bm *broadcastManager) BroadcastBridgedMessage(ctx context.Context, in *core.MessageInOut) (out *core.Message, err error) {
+	broadcast := bm.NewBroadcast(in).(*broadcastSender)
+	broadcast.bridged = true
+	in.Header.Type = core.MessageTypeBroadcast
+	err = broadcast.Send(ctx)
+	return &in.Message, err
+}
+
+// BroadcastMessages broadcasts a batch of messages without waiting for confirmation, returning the outcome of each
+func (bm *broadcastManager) BroadcastMessages(ctx context.Context, in []*core.MessageInOut) ([]*core.MessageSubmitResult, error) {
+	return syncasync.SendBatch(ctx, in, func(ctx context.Context, msg *core.MessageInOut) (*core.Message, error) {
+		return bm.BroadcastMessage(ctx, msg, false)
+	})
+}
+
+func (bm *broadcastManager) PreviewBatch(ctx context.Context, in *core.MessageInOut) (*batch.BatchPreview, error) {
+	if bm.batch == nil || bm.multiparty == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	broadcast := &broadcastSender{
+		mgr: bm,
+		msg: &data.NewMessage{
+			Message: in,
+		},
+	}
+	broadcast.setDefaults()
+	if err := broadcast.resolve(ctx); err != nil {
+		return nil, err
+	}
+	return bm.batch.PreviewBatch(ctx, &in.Message, in.Message.EstimateSize(true))
+}
+
 type broadcastSender struct {
 	mgr      *broadcastManager
 	msg      *data.NewMessage
 	resolved bool
+	bridged  bool
 }
 
 // sendMethod is the specific operation requested of the broadcastSender.
@@ -108,6 +145,24 @@ func (s *broadcastSender) setDefaults() {
 
 func (s *broadcastSender) resolveAndSend(ctx context.Context, method sendMethod) error {
 
+	if method == methodSendAndWait && s.msg.Message.SendAfter != nil {
+		return i18n.NewError(ctx, coremsgs.MsgScheduledMessageCannotWait)
+	}
+	if !s.bridged && s.msg.Message.IdempotencyKey.Bridged() {
+		return i18n.NewError(ctx, coremsgs.MsgIdempotencyKeyReserved, core.BridgedIdempotencyKeyPrefix)
+	}
+	if method != methodPrepare && !s.resolved && s.msg.Message.IdempotencyKey != "" {
+		// A retry of a message submitted within the idempotency window returns the original message
+		existing, err := s.mgr.data.GetIdempotentMessage(ctx, s.msg.Message.IdempotencyKey)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			log.L(ctx).Infof("Returning broadcast message %s for duplicate idempotency key '%s'", existing.Header.ID, existing.IdempotencyKey)
+			s.msg.Message.Message = *existing
+			return nil
+		}
+	}
 	if !s.resolved {
 		if err := s.resolve(ctx); err != nil {
 			return err
@@ -131,6 +186,12 @@ func (s *broadcastSender) resolve(ctx context.Context) error {
 		}
 	}
 
+	if len(msg.Triggers) > 0 {
+		if err := s.mgr.triggers.ValidateTriggers(ctx, msg.Triggers); err != nil {
+			return err
+		}
+	}
+
 	// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
 	err := s.mgr.data.ResolveInlineData(ctx, s.msg)
 	return err
@@ -154,11 +215,23 @@ func (s *broadcastSender) sendInternal(ctx context.Context, method sendMethod) (
 		return nil
 	}
 
+	// A message with a future sendAfter time is held back, until the batch manager releases it
+	if msg.SendAfter != nil && time.Time(*msg.SendAfter).After(time.Now()) {
+		msg.State = core.MessageStateScheduled
+	}
+
+	// Write the follow-up actions in the same transaction as the message, so they are waiting when it is confirmed
+	if len(msg.Triggers) > 0 {
+		s.msg.PreWrite = func(ctx context.Context) error {
+			return s.mgr.triggers.AddTriggers(ctx, &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: msg.Header.ID}, msg.Triggers)
+		}
+	}
+
 	// Write the message
 	if err := s.mgr.data.WriteNewMessage(ctx, s.msg); err != nil {
 		return err
 	}
-	log.L(ctx).Infof("Sent broadcast message %s sequence=%d datacount=%d", msg.Header.ID, msg.Sequence, len(s.msg.AllData))
+	log.L(ctx).Infof("Sent broadcast message %s sequence=%d datacount=%d state=%s", msg.Header.ID, msg.Sequence, len(s.msg.AllData), msg.State)
 
 	return err
 }
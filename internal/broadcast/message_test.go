@@ -20,13 +20,17 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/triggermocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -63,6 +67,184 @@ func TestBroadcastMessageOk(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func TestBroadcastMessageIdempotentWithinWindow(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+
+	ctx := context.Background()
+	existing := &core.Message{
+		Header:         core.MessageHeader{ID: fftypes.NewUUID()},
+		IdempotencyKey: "idem1",
+	}
+	mdm.On("GetIdempotentMessage", ctx, core.IdempotencyKey("idem1")).Return(existing, nil)
+
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "idem1",
+		},
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, existing.Header.ID, msg.Header.ID)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageIdempotentFail(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("GetIdempotentMessage", ctx, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "idem1",
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageIdempotentNotFound(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("GetIdempotentMessage", ctx, core.IdempotencyKey("idem1")).Return(nil, nil)
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "idem1",
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.IdempotencyKey("idem1"), msg.IdempotencyKey)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageReservedIdempotencyKey(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "bridge:sub1:msg1",
+		},
+	}, false)
+	assert.Regexp(t, "FF10663", err)
+}
+
+func TestBroadcastBridgedMessageOk(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("GetIdempotentMessage", ctx, core.IdempotencyKey("bridge:sub1:msg1")).Return(nil, nil)
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	msg, err := bm.BroadcastBridgedMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "bridge:sub1:msg1",
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageTypeBroadcast, msg.Header.Type)
+	assert.Equal(t, core.IdempotencyKey("bridge:sub1:msg1"), msg.IdempotencyKey)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageScheduled(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.MatchedBy(func(msg *data.NewMessage) bool {
+		return msg.Message.State == core.MessageStateScheduled
+	})).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	sendAfter := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			SendAfter: &sendAfter,
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateScheduled, msg.State)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageScheduledWaitConfirm(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		Message: core.Message{
+			SendAfter: fftypes.Now(),
+		},
+	}, true)
+	assert.Regexp(t, "FF10554", err)
+}
+
+func TestBroadcastMessagesOk(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	in := make([]*core.MessageInOut, 3)
+	for i := range in {
+		in[i] = &core.MessageInOut{
+			InlineData: core.InlineData{
+				{Value: fftypes.JSONAnyPtr(fmt.Sprintf(`{"index": %d}`, i))},
+			},
+		}
+	}
+	results, err := bm.BroadcastMessages(ctx, in)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	for i, result := range results {
+		assert.Empty(t, result.Error)
+		assert.Equal(t, &in[i].Message, result.Message)
+		assert.Equal(t, core.MessageTypeBroadcast, result.Message.Header.Type)
+	}
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
 func TestBroadcastMessageWriteFail(t *testing.T) {
 	bm, cancel := newTestBroadcastWithMetrics(t)
 	defer cancel()
@@ -140,6 +322,86 @@ func TestBroadcastMessageWaitConfirmOk(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func TestBroadcastMessageWithTriggers(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+	mtm := bm.triggers.(*triggermocks.Manager)
+
+	triggers := []*core.TriggerAction{
+		{Type: core.TriggerActionTypeInvoke, Invoke: &core.TriggerInvoke{API: "myapi", MethodPath: "set"}},
+	}
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", ctx, mock.Anything).Return(func(ctx context.Context, msg *data.NewMessage) error {
+		return msg.PreWrite(ctx)
+	})
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+	mtm.On("ValidateTriggers", ctx, triggers).Return(nil)
+	mtm.On("AddTriggers", ctx, mock.MatchedBy(func(source *core.TriggerSource) bool {
+		return source.Type == core.TriggerSourceTypeMessage && source.ID != nil
+	}), triggers).Return(nil)
+
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+		Triggers: triggers,
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", msg.Header.Namespace)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageTriggersInvalid(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mim := bm.identity.(*identitymanagermocks.Manager)
+	mtm := bm.triggers.(*triggermocks.Manager)
+
+	ctx := context.Background()
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+	mtm.On("ValidateTriggers", ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Triggers: []*core.TriggerAction{{Type: "unknown"}},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageAddTriggersFail(t *testing.T) {
+	bm, cancel := newTestBroadcastWithMetrics(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+	mtm := bm.triggers.(*triggermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", ctx, mock.Anything).Return(func(ctx context.Context, msg *data.NewMessage) error {
+		return msg.PreWrite(ctx)
+	})
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+	mtm.On("ValidateTriggers", ctx, mock.Anything).Return(nil)
+	mtm.On("AddTriggers", ctx, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Triggers: []*core.TriggerAction{{Type: core.TriggerActionTypeInvoke}},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+}
+
 func TestBroadcastMessageTooLarge(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	bm.maxBatchPayloadLength = 1000000
@@ -261,3 +523,55 @@ func TestNewMessageContractInvoke(t *testing.T) {
 	assert.NotNil(t, sender)
 
 }
+
+func TestPreviewBatchOk(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+	mba := bm.batch.(*batchmocks.Manager)
+
+	ctx := context.Background()
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mba.On("PreviewBatch", ctx, mock.MatchedBy(func(msg *core.Message) bool {
+		return msg.Header.Type == core.MessageTypeBroadcast &&
+			msg.Header.TxType == core.TransactionTypeBatchPin &&
+			msg.Header.Namespace == "ns1"
+	}), mock.Anything).Return(&batch.BatchPreview{Dispatcher: broadcastDispatcherName}, nil)
+
+	preview, err := bm.PreviewBatch(ctx, &core.MessageInOut{
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, broadcastDispatcherName, preview.Dispatcher)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+	mba.AssertExpectations(t)
+}
+
+func TestPreviewBatchResolveFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.PreviewBatch(ctx, &core.MessageInOut{})
+	assert.Regexp(t, "FF10206.*pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestPreviewBatchNotSupported(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.batch = nil
+
+	_, err := bm.PreviewBatch(context.Background(), &core.MessageInOut{})
+	assert.Regexp(t, "FF10414", err)
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -27,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/encryption"
 )
 
 type uploadBatchData struct {
@@ -34,8 +36,9 @@ type uploadBatchData struct {
 }
 
 type uploadBlobData struct {
-	Data *core.Data `json:"data"`
-	Blob *core.Blob `json:"blob"`
+	Data          *core.Data `json:"data"`
+	Blob          *core.Blob `json:"blob"`
+	EncryptionKey string     `json:"encryptionKey,omitempty"`
 }
 
 type uploadValue struct {
@@ -54,10 +57,13 @@ func getUploadBatchOutputs(payloadRef string) fftypes.JSONObject {
 	}
 }
 
-func addUploadBlobInputs(op *core.Operation, dataID *fftypes.UUID) {
+func addUploadBlobInputs(op *core.Operation, dataID *fftypes.UUID, keyID string) {
 	op.Input = fftypes.JSONObject{
 		"dataId": dataID.String(),
 	}
+	if keyID != "" {
+		op.Input["encryptionKey"] = keyID
+	}
 }
 
 func addUploadValueInputs(op *core.Operation, dataID *fftypes.UUID) {
@@ -72,12 +78,20 @@ func getUploadBlobOutputs(payloadRef string) fftypes.JSONObject {
 	}
 }
 
+func getDeduplicatedUploadOutputs(payloadRef string) fftypes.JSONObject {
+	return fftypes.JSONObject{
+		"payloadRef":   payloadRef,
+		"deduplicated": true,
+	}
+}
+
 func retrieveUploadBatchInputs(ctx context.Context, op *core.Operation) (*fftypes.UUID, error) {
 	return fftypes.ParseUUID(ctx, op.Input.GetString("id"))
 }
 
-func retrieveUploadBlobInputs(ctx context.Context, op *core.Operation) (*fftypes.UUID, error) {
-	return fftypes.ParseUUID(ctx, op.Input.GetString("dataId"))
+func retrieveUploadBlobInputs(ctx context.Context, op *core.Operation) (dataID *fftypes.UUID, keyID string, err error) {
+	dataID, err = fftypes.ParseUUID(ctx, op.Input.GetString("dataId"))
+	return dataID, op.Input.GetString("encryptionKey"), err
 }
 
 func retrieveUploadValueInputs(ctx context.Context, op *core.Operation) (*fftypes.UUID, error) {
@@ -104,7 +118,7 @@ func (bm *broadcastManager) PrepareOperation(ctx context.Context, op *core.Opera
 		return opUploadBatch(op, batch), nil
 
 	case core.OpTypeSharedStorageUploadBlob:
-		dataID, err := retrieveUploadBlobInputs(ctx, op)
+		dataID, keyID, err := retrieveUploadBlobInputs(ctx, op)
 		if err != nil {
 			return nil, err
 		}
@@ -121,7 +135,7 @@ func (bm *broadcastManager) PrepareOperation(ctx context.Context, op *core.Opera
 		} else if len(blobs) == 0 || blobs[0] == nil {
 			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
 		}
-		return opUploadBlob(op, d, blobs[0]), nil
+		return opUploadBlob(op, d, blobs[0], keyID), nil
 
 	case core.OpTypeSharedStorageUploadValue:
 		dataID, err := retrieveUploadValueInputs(ctx, op)
@@ -157,7 +171,11 @@ func (bm *broadcastManager) RunOperation(ctx context.Context, op *core.PreparedO
 func (bm *broadcastManager) uploadBatch(ctx context.Context, data uploadBatchData) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
 	// Serialize the full payload, which has already been sealed for us by the BatchManager
 	data.Batch.Namespace = bm.namespace.NetworkName
-	payload, err := json.Marshal(data.Batch)
+	batch, err := bm.encryptBatch(ctx, data.Batch)
+	if err != nil {
+		return nil, core.OpPhaseInitializing, err
+	}
+	payload, err := json.Marshal(batch)
 	if err != nil {
 		return nil, core.OpPhaseInitializing, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
 	}
@@ -171,9 +189,65 @@ func (bm *broadcastManager) uploadBatch(ctx context.Context, data uploadBatchDat
 	return getUploadBatchOutputs(payloadRef), core.OpPhaseComplete, nil
 }
 
+// findPublished looks for a payload reference already in shared storage for identical content, so that the
+// upload can be skipped. Each data record holding the reference counts as a reference to the shared object.
+func (bm *broadcastManager) findPublished(ctx context.Context, hashField, publicField string, hash *fftypes.Bytes32) (string, error) {
+	if !bm.deduplicate || hash == nil {
+		return "", nil
+	}
+	fb := database.DataQueryFactory.NewFilter(ctx)
+	existing, _, err := bm.database.GetData(ctx, bm.namespace.Name, fb.And(
+		fb.Eq(hashField, hash),
+		fb.Neq(publicField, ""),
+	).Limit(1))
+	if err != nil || len(existing) == 0 {
+		return "", err
+	}
+	if publicField == "blob.public" {
+		return existing[0].Blob.Public, nil
+	}
+	return existing[0].Public, nil
+}
+
+// unencryptedPublication checks the start of a published blob, returning an empty reference if it is encrypted
+func (bm *broadcastManager) unencryptedPublication(ctx context.Context, publicRef string) (string, error) {
+	reader, err := bm.sharedstorage.DownloadData(ctx, publicRef)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	start := make([]byte, encryption.BlobMagicLength())
+	n, _ := io.ReadFull(reader, start)
+	if encryption.IsEncryptedBlob(start[:n]) {
+		log.L(ctx).Infof("Blob published to shared storage '%s' is encrypted, and cannot be reused", publicRef)
+		return "", nil
+	}
+	return publicRef, nil
+}
+
 // uploadBlob streams a blob from the local data exchange, to public storage
 func (bm *broadcastManager) uploadBlob(ctx context.Context, data uploadBlobData) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
 
+	// The hash of a blob is of its original content, so a published copy is only reused if neither copy is
+	// encrypted - as the key of an earlier message might not be held by the readers of this one
+	var publicRef string
+	if data.EncryptionKey == "" {
+		if publicRef, err = bm.findPublished(ctx, "blob.hash", "blob.public", data.Blob.Hash); err == nil && publicRef != "" {
+			publicRef, err = bm.unencryptedPublication(ctx, publicRef)
+		}
+		if err != nil {
+			return nil, core.OpPhaseInitializing, err
+		}
+	}
+	if publicRef != "" {
+		data.Data.Blob.Public = publicRef
+		if err := bm.database.UpdateData(ctx, bm.namespace.Name, data.Data.ID, database.DataQueryFactory.NewUpdate(ctx).Set("blob.public", publicRef)); err != nil {
+			return nil, core.OpPhaseInitializing, err
+		}
+		log.L(ctx).Infof("Blob with hash '%s' for data '%s' already published to shared storage: '%s'", data.Blob.Hash, data.Data.ID, publicRef)
+		return getDeduplicatedUploadOutputs(publicRef), core.OpPhaseComplete, nil
+	}
+
 	// Stream from the local data exchange ...
 	reader, err := bm.exchange.DownloadBlob(ctx, data.Blob.PayloadRef)
 	if err != nil {
@@ -181,8 +255,14 @@ func (bm *broadcastManager) uploadBlob(ctx context.Context, data uploadBlobData)
 	}
 	defer reader.Close()
 
-	// ... to the shared storage
-	data.Data.Blob.Public, err = bm.sharedstorage.UploadData(ctx, reader)
+	// ... to the shared storage, encrypting it as it streams if required
+	var upload io.Reader = reader
+	if data.EncryptionKey != "" {
+		if upload, err = encryption.EncryptBlob(ctx, bm.keys, data.EncryptionKey, reader); err != nil {
+			return nil, core.OpPhaseInitializing, err
+		}
+	}
+	data.Data.Blob.Public, err = bm.sharedstorage.UploadData(ctx, upload)
 	if err != nil {
 		return nil, core.OpPhaseInitializing, err
 	}
@@ -200,6 +280,23 @@ func (bm *broadcastManager) uploadBlob(ctx context.Context, data uploadBlobData)
 // uploadValue streams the value JSON from a data record to public storage
 func (bm *broadcastManager) uploadValue(ctx context.Context, data uploadValue) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
 
+	var valueHash *fftypes.Bytes32
+	if data.Data.Value != nil {
+		valueHash = data.Data.Value.Hash()
+	}
+	publicRef, err := bm.findPublished(ctx, "hash", "public", valueHash)
+	if err != nil {
+		return nil, core.OpPhaseInitializing, err
+	}
+	if publicRef != "" {
+		data.Data.Public = publicRef
+		if err := bm.database.UpdateData(ctx, bm.namespace.Name, data.Data.ID, database.DataQueryFactory.NewUpdate(ctx).Set("public", publicRef)); err != nil {
+			return nil, core.OpPhaseInitializing, err
+		}
+		log.L(ctx).Infof("Value for data '%s' already published to shared storage: '%s'", data.Data.ID, publicRef)
+		return getDeduplicatedUploadOutputs(publicRef), core.OpPhaseComplete, nil
+	}
+
 	// Upload to shared storage
 	data.Data.Public, err = bm.sharedstorage.UploadData(ctx, bytes.NewReader(data.Data.Value.Bytes()))
 	if err != nil {
@@ -230,13 +327,13 @@ func opUploadBatch(op *core.Operation, batch *core.Batch) *core.PreparedOperatio
 	}
 }
 
-func opUploadBlob(op *core.Operation, data *core.Data, blob *core.Blob) *core.PreparedOperation {
+func opUploadBlob(op *core.Operation, data *core.Data, blob *core.Blob, keyID string) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
 		Namespace: op.Namespace,
 		Plugin:    op.Plugin,
 		Type:      op.Type,
-		Data:      uploadBlobData{Data: data, Blob: blob},
+		Data:      uploadBlobData{Data: data, Blob: blob, EncryptionKey: keyID},
 	}
 }
 
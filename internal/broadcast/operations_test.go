@@ -248,7 +248,7 @@ func TestPrepareAndRunUploadBlob(t *testing.T) {
 			Hash: blob.Hash,
 		},
 	}
-	addUploadBlobInputs(op, data.ID)
+	addUploadBlobInputs(op, data.ID, "")
 
 	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
 	mdx := bm.exchange.(*dataexchangemocks.Plugin)
@@ -273,7 +273,7 @@ func TestPrepareAndRunUploadBlob(t *testing.T) {
 	assert.Equal(t, data, po.Data.(uploadBlobData).Data)
 	assert.Equal(t, blob, po.Data.(uploadBlobData).Blob)
 
-	outputs, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob))
+	outputs, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
 	assert.Equal(t, "123", outputs["payloadRef"])
 
 	assert.Equal(t, core.OpPhaseComplete, phase)
@@ -344,7 +344,7 @@ func TestPrepareUploadBlobGetBlobMissing(t *testing.T) {
 			Hash: blob.Hash,
 		},
 	}
-	addUploadBlobInputs(op, data.ID)
+	addUploadBlobInputs(op, data.ID, "")
 
 	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
 	mdx := bm.exchange.(*dataexchangemocks.Plugin)
@@ -379,7 +379,7 @@ func TestPrepareUploadBlobGetBlobFailing(t *testing.T) {
 			Hash: blob.Hash,
 		},
 	}
-	addUploadBlobInputs(op, data.ID)
+	addUploadBlobInputs(op, data.ID, "")
 
 	mdi := bm.database.(*databasemocks.Plugin)
 
@@ -401,7 +401,7 @@ func TestPrepareUploadBlobGetDataMissing(t *testing.T) {
 		Type: core.OpTypeSharedStorageUploadBlob,
 	}
 	dataID := fftypes.NewUUID()
-	addUploadBlobInputs(op, dataID)
+	addUploadBlobInputs(op, dataID, "")
 
 	mdi := bm.database.(*databasemocks.Plugin)
 
@@ -422,7 +422,7 @@ func TestPrepareUploadValueGetDataMissing(t *testing.T) {
 		Type: core.OpTypeSharedStorageUploadValue,
 	}
 	dataID := fftypes.NewUUID()
-	addUploadBlobInputs(op, dataID)
+	addUploadBlobInputs(op, dataID, "")
 
 	mdi := bm.database.(*databasemocks.Plugin)
 
@@ -443,7 +443,7 @@ func TestPrepareUploadBlobGetDataFail(t *testing.T) {
 		Type: core.OpTypeSharedStorageUploadBlob,
 	}
 	dataID := fftypes.NewUUID()
-	addUploadBlobInputs(op, dataID)
+	addUploadBlobInputs(op, dataID, "")
 
 	mdi := bm.database.(*databasemocks.Plugin)
 
@@ -464,7 +464,7 @@ func TestPrepareUploadValueGetDataFail(t *testing.T) {
 		Type: core.OpTypeSharedStorageUploadValue,
 	}
 	dataID := fftypes.NewUUID()
-	addUploadBlobInputs(op, dataID)
+	addUploadBlobInputs(op, dataID, "")
 
 	mdi := bm.database.(*databasemocks.Plugin)
 
@@ -527,7 +527,7 @@ func TestRunOperationUploadBlobUpdateFail(t *testing.T) {
 	mps.On("UploadData", context.Background(), mock.Anything).Return("123", nil)
 	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(fmt.Errorf("pop"))
 
-	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob))
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
 
 	assert.Equal(t, core.OpPhaseInitializing, phase)
 	assert.Regexp(t, "pop", err)
@@ -584,7 +584,7 @@ func TestRunOperationUploadBlobUploadFail(t *testing.T) {
 	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, nil)
 	mps.On("UploadData", context.Background(), mock.Anything).Return("", fmt.Errorf("pop"))
 
-	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob))
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
 
 	assert.Equal(t, core.OpPhaseInitializing, phase)
 	assert.Regexp(t, "pop", err)
@@ -635,7 +635,7 @@ func TestRunOperationUploadBlobDownloadFail(t *testing.T) {
 	reader := ioutil.NopCloser(strings.NewReader("some data"))
 	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(reader, fmt.Errorf("pop"))
 
-	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob))
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
 
 	assert.Equal(t, core.OpPhaseInitializing, phase)
 	assert.Regexp(t, "pop", err)
@@ -644,6 +644,268 @@ func TestRunOperationUploadBlobDownloadFail(t *testing.T) {
 	mdx.AssertExpectations(t)
 }
 
+func TestRunOperationUploadValueDeduplicated(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	data := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"some":"data"}`),
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Public: "123"},
+	}, nil, nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(nil)
+
+	outputs, phase, err := bm.RunOperation(context.Background(), opUploadValue(op, data))
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, "123", outputs["payloadRef"])
+	assert.Equal(t, true, outputs["deduplicated"])
+	assert.Equal(t, "123", data.Public)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadValueDeduplicateNoMatch(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	data := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"some":"data"}`),
+	}
+
+	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{}, nil, nil)
+	mps.On("UploadData", context.Background(), mock.Anything).Return("123", nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(nil)
+
+	outputs, phase, err := bm.RunOperation(context.Background(), opUploadValue(op, data))
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, "123", outputs["payloadRef"])
+	assert.Nil(t, outputs["deduplicated"])
+
+	mps.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadValueDeduplicateQueryFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	data := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"some":"data"}`),
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadValue(op, data))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadValueDeduplicateUpdateFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	data := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"some":"data"}`),
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Public: "123"},
+	}, nil, nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadValue(op, data))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadBlobDeduplicated(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Blob: &core.BlobRef{Hash: blob.Hash, Public: "123"}},
+	}, nil, nil)
+	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mps.On("DownloadData", context.Background(), "123").Return(ioutil.NopCloser(strings.NewReader("some data")), nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(nil)
+
+	outputs, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, "123", outputs["payloadRef"])
+	assert.Equal(t, true, outputs["deduplicated"])
+	assert.Equal(t, "123", data.Blob.Public)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadBlobDeduplicateQueryFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadBlobDeduplicateUpdateFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Blob: &core.BlobRef{Hash: blob.Hash, Public: "123"}},
+	}, nil, nil)
+	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mps.On("DownloadData", context.Background(), "123").Return(ioutil.NopCloser(strings.NewReader("some data")), nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationUploadBlobDeduplicateEncryptedMatch(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Blob: &core.BlobRef{Hash: blob.Hash, Public: "123"}},
+	}, nil, nil)
+	mdi.On("UpdateData", context.Background(), "ns1", data.ID, mock.Anything).Return(nil)
+
+	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mps.On("DownloadData", context.Background(), "123").Return(ioutil.NopCloser(strings.NewReader("FFENC1:key1\nsecret")), nil)
+	mps.On("UploadData", context.Background(), mock.Anything).Return("456", nil)
+
+	mdx := bm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", context.Background(), mock.Anything).Return(ioutil.NopCloser(strings.NewReader("some data")), nil)
+
+	outputs, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, "456", outputs["payloadRef"])
+	assert.Nil(t, outputs["deduplicated"])
+
+	mdi.AssertExpectations(t)
+	mps.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestRunOperationUploadBlobDeduplicateCheckFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	bm.deduplicate = true
+
+	op := &core.Operation{}
+	blob := &core.Blob{
+		Hash: fftypes.NewRandB32(),
+	}
+	data := &core.Data{
+		ID: fftypes.NewUUID(),
+		Blob: &core.BlobRef{
+			Hash: blob.Hash,
+		},
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetData", context.Background(), "ns1", mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Blob: &core.BlobRef{Hash: blob.Hash, Public: "123"}},
+	}, nil, nil)
+
+	mps := bm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mps.On("DownloadData", context.Background(), "123").Return(nil, fmt.Errorf("pop"))
+
+	_, phase, err := bm.RunOperation(context.Background(), opUploadBlob(op, data, blob, ""))
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+	mps.AssertExpectations(t)
+}
+
 func TestOperationUpdate(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
@@ -0,0 +1,326 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package charts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// Manager builds time series of the records in a collection, and maintains pre-aggregated hourly counts of each
+// collection in the background, so that time series over long ranges do not need to count every record
+type Manager interface {
+	Start()
+	WaitStop()
+	GetTimeSeries(ctx context.Context, collection database.CollectionName, query *core.ChartTimeSeriesQuery) ([]*core.ChartTimeSeriesBucket, error)
+}
+
+// RollupBucket is the width of each pre-aggregated count. Time series with an interval that is a multiple of this,
+// and a start and end time on a boundary of it, are built from the rollups
+const RollupBucket = time.Hour
+
+// rollupGroups are the fields each collection is pre-aggregated by, in addition to the total count. Records are
+// counted once when they are created, so only fields that do not change after that can be rolled up.
+var rollupGroups = map[database.CollectionName][]string{
+	database.CollectionName(database.CollectionMessages):         {"type"},
+	database.CollectionName(database.CollectionTransactions):     {"type"},
+	database.CollectionName(database.CollectionOperations):       {"type"},
+	database.CollectionName(database.CollectionEvents):           {"type"},
+	database.CollectionName(database.CollectionTokenTransfers):   {"type", "pool"},
+	database.CollectionName(database.CollectionBlockchainEvents): {"listener"},
+}
+
+// rollupReadAttempts is how many times to read the rollups, if the rollup job updates them while they are read
+const rollupReadAttempts = 3
+
+type chartManager struct {
+	ctx       context.Context
+	cancelCtx func()
+	namespace string
+	database  database.Plugin
+	enabled   bool
+	interval  time.Duration
+	batchSize int
+	started   bool
+	done      chan struct{}
+	// settled is the last sequence of each collection that was read on the previous pass of the rollup job
+	settled map[database.CollectionName]int64
+}
+
+func NewChartManager(ctx context.Context, ns string, di database.Plugin) (Manager, error) {
+	if di == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "ChartManager")
+	}
+	cm := &chartManager{
+		namespace: ns,
+		database:  di,
+		enabled:   config.GetBool(coreconfig.HistogramsRollupEnabled),
+		interval:  config.GetDuration(coreconfig.HistogramsRollupInterval),
+		batchSize: config.GetInt(coreconfig.HistogramsRollupBatchSize),
+		done:      make(chan struct{}),
+		settled:   make(map[database.CollectionName]int64),
+	}
+	cm.ctx, cm.cancelCtx = context.WithCancel(log.WithLogField(ctx, "role", "charts"))
+	return cm, nil
+}
+
+func (cm *chartManager) Start() {
+	if !cm.enabled {
+		log.L(cm.ctx).Debugf("Chart rollups disabled")
+		return
+	}
+	cm.started = true
+	go cm.rollupLoop()
+}
+
+func (cm *chartManager) WaitStop() {
+	cm.cancelCtx()
+	if cm.started {
+		<-cm.done
+	}
+}
+
+func rollupOffsetName(ns string, collection database.CollectionName) string {
+	return fmt.Sprintf("%s:%s", ns, collection)
+}
+
+func rollupCollections() []database.CollectionName {
+	collections := make([]database.CollectionName, 0, len(rollupGroups))
+	for collection := range rollupGroups {
+		collections = append(collections, collection)
+	}
+	sort.Slice(collections, func(i, j int) bool { return collections[i] < collections[j] })
+	return collections
+}
+
+func (cm *chartManager) rollupLoop() {
+	defer close(cm.done)
+	for {
+		cm.rollupAll()
+		select {
+		case <-time.After(cm.interval):
+		case <-cm.ctx.Done():
+			log.L(cm.ctx).Debugf("Chart rollup loop exiting")
+			return
+		}
+	}
+}
+
+// rollupAll rolls up each collection to the last sequence read on the previous pass. A sequence is allocated
+// before the transaction that stores the record commits, so a record can become visible after records with a
+// higher sequence - but every transaction that was in flight when a sequence was read has committed one rollup
+// interval later, so all the records up to it can then be counted without missing any.
+func (cm *chartManager) rollupAll() {
+	for _, collection := range rollupCollections() {
+		settled := cm.settled[collection]
+		head, err := cm.database.GetChartNextSequence(cm.ctx, cm.namespace, collection, settled, 0)
+		if err != nil {
+			log.L(cm.ctx).Errorf("Chart rollup of %s failed: %s", collection, err)
+			continue
+		}
+		cm.settled[collection] = head
+		for {
+			caughtUp, err := cm.rollupPage(collection, settled)
+			if err != nil {
+				log.L(cm.ctx).Errorf("Chart rollup of %s failed: %s", collection, err)
+				break
+			}
+			if caughtUp || cm.ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+func (cm *chartManager) rollupOffset(ctx context.Context, collection database.CollectionName) (int64, error) {
+	offset, err := cm.database.GetOffset(ctx, core.OffsetTypeChartRollup, rollupOffsetName(cm.namespace, collection))
+	if err != nil || offset == nil {
+		return 0, err
+	}
+	return offset.Current, nil
+}
+
+// rollupPage adds the counts of the next page of records in a collection, up to the settled sequence, to the rollups.
+// Records are counted in the order they were stored, so records with an earlier timestamp that are stored late are
+// still counted
+func (cm *chartManager) rollupPage(collection database.CollectionName, settled int64) (caughtUp bool, err error) {
+	after, err := cm.rollupOffset(cm.ctx, collection)
+	if err != nil {
+		return false, err
+	}
+	last, err := cm.database.GetChartNextSequence(cm.ctx, cm.namespace, collection, after, cm.batchSize)
+	if last > settled {
+		last = settled
+	}
+	if err != nil || last <= after {
+		return err == nil, err
+	}
+
+	rollups := []*core.ChartRollup{}
+	for _, groupBy := range append([]string{""}, rollupGroups[collection]...) {
+		counts, err := cm.database.GetChartCounts(cm.ctx, cm.namespace, &database.ChartCountQuery{
+			Collection:    collection,
+			GroupBy:       groupBy,
+			Interval:      RollupBucket,
+			AfterSequence: after,
+			MaxSequence:   last,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, count := range counts {
+			rollups = append(rollups, &core.ChartRollup{
+				Collection: string(collection),
+				GroupBy:    groupBy,
+				ChartCount: *count,
+			})
+		}
+	}
+
+	err = cm.database.RunAsGroup(cm.ctx, func(ctx context.Context) error {
+		if err := cm.database.AddChartRollups(ctx, cm.namespace, rollups); err != nil {
+			return err
+		}
+		return cm.database.UpsertOffset(ctx, &core.Offset{
+			Type:    core.OffsetTypeChartRollup,
+			Name:    rollupOffsetName(cm.namespace, collection),
+			Current: last,
+		}, true)
+	})
+	if err != nil {
+		return false, err
+	}
+	log.L(cm.ctx).Debugf("Rolled up %s records %d to %d", collection, after+1, last)
+	return false, nil
+}
+
+func (cm *chartManager) GetTimeSeries(ctx context.Context, collection database.CollectionName, query *core.ChartTimeSeriesQuery) ([]*core.ChartTimeSeriesBucket, error) {
+	interval := time.Duration(query.Interval)
+	if interval <= 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidChartInterval, query.Interval)
+	}
+	if query.StartTime == nil || query.EndTime == nil || !query.StartTime.Time().Before(*query.EndTime.Time()) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgHistogramInvalidTimes)
+	}
+	start := query.StartTime.UnixNano()
+	end := query.EndTime.UnixNano()
+	numBuckets := (end - start + interval.Nanoseconds() - 1) / interval.Nanoseconds()
+	if numBuckets > core.ChartTimeSeriesMaxBuckets {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidNumberOfIntervals, core.ChartHistogramMinBuckets, core.ChartTimeSeriesMaxBuckets)
+	}
+
+	counts, err := cm.getCounts(ctx, collection, query, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]*core.ChartTimeSeriesBucket, numBuckets)
+	groups := make([]map[string]int64, numBuckets)
+	for i := range buckets {
+		t := fftypes.FFTime(time.Unix(0, start+int64(i)*interval.Nanoseconds()))
+		buckets[i] = &core.ChartTimeSeriesBucket{Timestamp: &t}
+		groups[i] = map[string]int64{}
+	}
+	for _, count := range counts {
+		i := (count.Timestamp.UnixNano() - start) / interval.Nanoseconds()
+		if i < 0 || i >= numBuckets {
+			continue
+		}
+		buckets[i].Count += count.Count
+		groups[i][count.Group] += count.Count
+	}
+	if query.GroupBy != "" {
+		for i, bucket := range buckets {
+			bucket.Groups = make([]*core.ChartTimeSeriesGroup, 0, len(groups[i]))
+			for group, count := range groups[i] {
+				bucket.Groups = append(bucket.Groups, &core.ChartTimeSeriesGroup{Group: group, Count: count})
+			}
+			sort.Slice(bucket.Groups, func(a, b int) bool { return bucket.Groups[a].Group < bucket.Groups[b].Group })
+		}
+	}
+	return buckets, nil
+}
+
+func (cm *chartManager) canUseRollups(collection database.CollectionName, query *core.ChartTimeSeriesQuery, interval time.Duration) bool {
+	if !cm.enabled ||
+		interval%RollupBucket != 0 ||
+		query.StartTime.UnixNano()%int64(RollupBucket) != 0 ||
+		query.EndTime.UnixNano()%int64(RollupBucket) != 0 {
+		return false
+	}
+	if query.GroupBy == "" {
+		_, ok := rollupGroups[collection]
+		return ok
+	}
+	for _, groupBy := range rollupGroups[collection] {
+		if groupBy == query.GroupBy {
+			return true
+		}
+	}
+	return false
+}
+
+// getCounts returns the counts of records in the range, in buckets no wider than the interval. The rollups hold the
+// counts of every record up to the offset of the rollup job, so only records after that are counted individually.
+func (cm *chartManager) getCounts(ctx context.Context, collection database.CollectionName, query *core.ChartTimeSeriesQuery, interval time.Duration) ([]*core.ChartCount, error) {
+	countQuery := &database.ChartCountQuery{
+		Collection: collection,
+		GroupBy:    query.GroupBy,
+		StartTime:  query.StartTime,
+		EndTime:    query.EndTime,
+		Interval:   interval,
+	}
+	if cm.canUseRollups(collection, query, interval) {
+		for attempt := 0; attempt < rollupReadAttempts; attempt++ {
+			offset, err := cm.rollupOffset(ctx, collection)
+			if err != nil {
+				return nil, err
+			}
+			rollups, err := cm.database.GetChartRollups(ctx, cm.namespace, collection, query.GroupBy, query.StartTime, query.EndTime)
+			if err != nil {
+				return nil, err
+			}
+			// The offset only moves forwards, and is committed with the rollups, so if it has not changed the
+			// rollups that were read contain exactly the records up to it
+			offsetAfter, err := cm.rollupOffset(ctx, collection)
+			if err != nil {
+				return nil, err
+			}
+			if offsetAfter == offset {
+				countQuery.AfterSequence = offset
+				counts, err := cm.database.GetChartCounts(ctx, cm.namespace, countQuery)
+				if err != nil {
+					return nil, err
+				}
+				return append(rollups, counts...), nil
+			}
+		}
+		log.L(ctx).Debugf("Rollups of %s updating, counting all records", collection)
+	}
+	return cm.database.GetChartCounts(ctx, cm.namespace, countQuery)
+}
@@ -0,0 +1,413 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package charts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testStart = int64(1699999200) * int64(time.Second) // on the hour
+
+func newTestChartManager(t *testing.T) (*chartManager, *databasemocks.Plugin, func()) {
+	coreconfig.Reset()
+	config.Set(coreconfig.HistogramsRollupBatchSize, 2)
+	mdi := &databasemocks.Plugin{}
+	cm, err := NewChartManager(context.Background(), "ns1", mdi)
+	assert.NoError(t, err)
+	return cm.(*chartManager), mdi, func() {
+		cm.WaitStop()
+		mdi.AssertExpectations(t)
+	}
+}
+
+func ts(offset time.Duration) *fftypes.FFTime {
+	t := fftypes.FFTime(time.Unix(0, testStart+int64(offset)))
+	return &t
+}
+
+func mockRunAsGroup(mdi *databasemocks.Plugin) {
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
+	}
+}
+
+func TestNewChartManagerMissingDeps(t *testing.T) {
+	_, err := NewChartManager(context.Background(), "ns1", nil)
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestStartDisabled(t *testing.T) {
+	cm, _, done := newTestChartManager(t)
+	defer done()
+	cm.enabled = false
+	cm.Start()
+	assert.False(t, cm.started)
+}
+
+func TestStartRollupStop(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+
+	rolledUp := make(chan struct{})
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, mock.Anything).Return(nil, nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", database.CollectionName("transactions"), int64(0), 2).Return(int64(0), nil).Run(func(args mock.Arguments) {
+		close(rolledUp)
+	}).Once()
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(0), mock.Anything).Return(int64(0), nil)
+
+	cm.Start()
+	<-rolledUp
+	done()
+	assert.True(t, cm.started)
+}
+
+func TestRollupPages(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	collection := database.CollectionName("tokentransfers")
+	offsetName := "ns1:tokentransfers"
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, offsetName).Return(nil, nil).Once()
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", collection, int64(0), 2).Return(int64(5), nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.MatchedBy(func(q *database.ChartCountQuery) bool {
+		return q.AfterSequence == 0 && q.MaxSequence == 5 && q.Interval == RollupBucket && q.StartTime == nil
+	})).Return([]*core.ChartCount{{Timestamp: ts(0), Count: 2}}, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("AddChartRollups", mock.Anything, "ns1", mock.MatchedBy(func(rollups []*core.ChartRollup) bool {
+		return len(rollups) == 3 &&
+			rollups[0].GroupBy == "" &&
+			rollups[1].GroupBy == "type" &&
+			rollups[2].GroupBy == "pool" &&
+			rollups[2].Collection == "tokentransfers" &&
+			rollups[2].Count == 2
+	})).Return(nil)
+	mdi.On("UpsertOffset", mock.Anything, &core.Offset{
+		Type:    core.OffsetTypeChartRollup,
+		Name:    offsetName,
+		Current: 5,
+	}, true).Return(nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, offsetName).Return(&core.Offset{Current: 5}, nil).Once()
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", collection, int64(5), 2).Return(int64(5), nil)
+
+	caughtUp, err := cm.rollupPage(collection, 5)
+	assert.NoError(t, err)
+	assert.False(t, caughtUp)
+	caughtUp, err = cm.rollupPage(collection, 5)
+	assert.NoError(t, err)
+	assert.True(t, caughtUp)
+}
+
+func TestRollupPageStopsAtSettled(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	collection := database.CollectionName("messages")
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(&core.Offset{Current: 1}, nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", collection, int64(1), 2).Return(int64(5), nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.MatchedBy(func(q *database.ChartCountQuery) bool {
+		return q.AfterSequence == 1 && q.MaxSequence == 3
+	})).Return([]*core.ChartCount{}, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("AddChartRollups", mock.Anything, "ns1", mock.Anything).Return(nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(offset *core.Offset) bool {
+		return offset.Current == 3
+	}), true).Return(nil)
+
+	caughtUp, err := cm.rollupPage(collection, 3)
+	assert.NoError(t, err)
+	assert.False(t, caughtUp)
+}
+
+func TestRollupPageNothingSettled(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	collection := database.CollectionName("messages")
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(&core.Offset{Current: 3}, nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", collection, int64(3), 2).Return(int64(5), nil)
+
+	caughtUp, err := cm.rollupPage(collection, 3)
+	assert.NoError(t, err)
+	assert.True(t, caughtUp)
+}
+
+func TestRollupAllSettlesOnNextPass(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	offsets := map[string]int64{}
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, mock.Anything).Return(func(ctx context.Context, t core.OffsetType, name string) (*core.Offset, error) {
+		return &core.Offset{Current: offsets[name]}, nil
+	})
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(0), 0).Return(int64(1), nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(1), 0).Return(int64(1), nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, mock.Anything, 2).Return(int64(1), nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.MatchedBy(func(q *database.ChartCountQuery) bool {
+		return q.MaxSequence == 1
+	})).Return([]*core.ChartCount{}, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("AddChartRollups", mock.Anything, "ns1", mock.Anything).Return(nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.Anything, true).Return(nil).Run(func(args mock.Arguments) {
+		offset := args[1].(*core.Offset)
+		offsets[offset.Name] = offset.Current
+	})
+
+	// The first pass only reads the last sequence of each collection
+	cm.rollupAll()
+	mdi.AssertNotCalled(t, "UpsertOffset", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, int64(1), cm.settled["messages"])
+
+	// The next pass rolls up to it
+	cm.rollupAll()
+	mdi.AssertNumberOfCalls(t, "UpsertOffset", len(rollupGroups))
+	assert.Equal(t, int64(1), offsets["ns1:messages"])
+}
+
+func TestRollupAllErrors(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	for collection := range rollupGroups {
+		cm.settled[collection] = 1
+	}
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", database.CollectionName("transactions"), int64(1), 0).Return(int64(0), fmt.Errorf("pop"))
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(1), 0).Return(int64(1), nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:blockchainevents").Return(nil, fmt.Errorf("pop"))
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, mock.Anything).Return(nil, nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", database.CollectionName("events"), int64(0), 2).Return(int64(0), fmt.Errorf("pop"))
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(0), 2).Return(int64(1), nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.MatchedBy(func(q *database.ChartCountQuery) bool {
+		return q.Collection == "messages"
+	})).Return(nil, fmt.Errorf("pop"))
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.Anything).Return([]*core.ChartCount{}, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("AddChartRollups", mock.Anything, "ns1", mock.Anything).Return(fmt.Errorf("pop")).Once()
+	mdi.On("AddChartRollups", mock.Anything, "ns1", mock.Anything).Return(nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.Anything, true).Return(fmt.Errorf("pop"))
+
+	// Every collection fails, so each is only attempted once
+	cm.rollupAll()
+	mdi.AssertNumberOfCalls(t, "GetOffset", len(rollupGroups)-1)
+}
+
+func TestRollupStopsOnCancel(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	cm.cancelCtx()
+	for collection := range rollupGroups {
+		cm.settled[collection] = 1
+	}
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(1), 0).Return(int64(1), nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, mock.Anything).Return(nil, nil)
+	mdi.On("GetChartNextSequence", mock.Anything, "ns1", mock.Anything, int64(0), 2).Return(int64(1), nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.Anything).Return([]*core.ChartCount{}, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("AddChartRollups", mock.Anything, "ns1", mock.Anything).Return(nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.Anything, true).Return(nil)
+
+	cm.rollupAll()
+	mdi.AssertNumberOfCalls(t, "GetOffset", len(rollupGroups))
+}
+
+func TestGetTimeSeriesLive(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	mdi.On("GetChartCounts", mock.Anything, "ns1", &database.ChartCountQuery{
+		Collection: "tokentransfers",
+		GroupBy:    "connector",
+		StartTime:  ts(time.Minute),
+		EndTime:    ts(time.Minute + 25*time.Minute),
+		Interval:   10 * time.Minute,
+	}).Return([]*core.ChartCount{
+		{Timestamp: ts(time.Minute), Group: "erc20", Count: 2},
+		{Timestamp: ts(time.Minute), Group: "erc1155", Count: 1},
+		{Timestamp: ts(21 * time.Minute), Group: "erc20", Count: 3},
+		{Timestamp: ts(time.Hour), Group: "erc20", Count: 3}, // out of range
+	}, nil)
+
+	buckets, err := cm.GetTimeSeries(context.Background(), "tokentransfers", &core.ChartTimeSeriesQuery{
+		StartTime: ts(time.Minute),
+		EndTime:   ts(time.Minute + 25*time.Minute),
+		Interval:  fftypes.FFDuration(10 * time.Minute),
+		GroupBy:   "connector",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ChartTimeSeriesBucket{
+		{Timestamp: ts(time.Minute), Count: 3, Groups: []*core.ChartTimeSeriesGroup{
+			{Group: "erc1155", Count: 1},
+			{Group: "erc20", Count: 2},
+		}},
+		{Timestamp: ts(11 * time.Minute), Count: 0, Groups: []*core.ChartTimeSeriesGroup{}},
+		{Timestamp: ts(21 * time.Minute), Count: 3, Groups: []*core.ChartTimeSeriesGroup{
+			{Group: "erc20", Count: 3},
+		}},
+	}, buckets)
+}
+
+func TestGetTimeSeriesRollups(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:blockchainevents").Return(&core.Offset{Current: 100}, nil)
+	mdi.On("GetChartRollups", mock.Anything, "ns1", database.CollectionName("blockchainevents"), "", ts(0), ts(4*time.Hour)).Return([]*core.ChartCount{
+		{Timestamp: ts(0), Count: 10},
+		{Timestamp: ts(time.Hour), Count: 5},
+		{Timestamp: ts(3 * time.Hour), Count: 1},
+	}, nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.MatchedBy(func(q *database.ChartCountQuery) bool {
+		return q.AfterSequence == 100 && q.Interval == 2*time.Hour
+	})).Return([]*core.ChartCount{
+		{Timestamp: ts(2 * time.Hour), Count: 2},
+	}, nil)
+
+	buckets, err := cm.GetTimeSeries(context.Background(), "blockchainevents", &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(4 * time.Hour),
+		Interval:  fftypes.FFDuration(2 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ChartTimeSeriesBucket{
+		{Timestamp: ts(0), Count: 15},
+		{Timestamp: ts(2 * time.Hour), Count: 3},
+	}, buckets)
+}
+
+func TestGetTimeSeriesRollupsUpdating(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	offset := int64(0)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:tokentransfers").Return(func(ctx context.Context, t core.OffsetType, n string) *core.Offset {
+		offset++
+		return &core.Offset{Current: offset}
+	}, nil)
+	mdi.On("GetChartRollups", mock.Anything, "ns1", database.CollectionName("tokentransfers"), "pool", ts(0), ts(time.Hour)).Return([]*core.ChartCount{}, nil)
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.MatchedBy(func(q *database.ChartCountQuery) bool {
+		return q.AfterSequence == 0
+	})).Return([]*core.ChartCount{
+		{Timestamp: ts(0), Group: "pool1", Count: 2},
+	}, nil)
+
+	buckets, err := cm.GetTimeSeries(context.Background(), "tokentransfers", &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(time.Hour),
+		Interval:  fftypes.FFDuration(time.Hour),
+		GroupBy:   "pool",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 1)
+	assert.Equal(t, int64(2), buckets[0].Count)
+	mdi.AssertNumberOfCalls(t, "GetChartRollups", rollupReadAttempts)
+}
+
+func TestGetTimeSeriesRollupErrors(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	query := &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(time.Hour),
+		Interval:  fftypes.FFDuration(time.Hour),
+		GroupBy:   "type",
+	}
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(nil, fmt.Errorf("pop")).Once()
+	_, err := cm.GetTimeSeries(context.Background(), "messages", query)
+	assert.EqualError(t, err, "pop")
+
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(nil, nil).Once()
+	mdi.On("GetChartRollups", mock.Anything, "ns1", database.CollectionName("messages"), "type", ts(0), ts(time.Hour)).Return(nil, fmt.Errorf("pop")).Once()
+	_, err = cm.GetTimeSeries(context.Background(), "messages", query)
+	assert.EqualError(t, err, "pop")
+
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(nil, nil).Once()
+	mdi.On("GetChartRollups", mock.Anything, "ns1", database.CollectionName("messages"), "type", ts(0), ts(time.Hour)).Return([]*core.ChartCount{}, nil).Once()
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(nil, fmt.Errorf("pop")).Once()
+	_, err = cm.GetTimeSeries(context.Background(), "messages", query)
+	assert.EqualError(t, err, "pop")
+
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeChartRollup, "ns1:messages").Return(nil, nil).Twice()
+	mdi.On("GetChartRollups", mock.Anything, "ns1", database.CollectionName("messages"), "type", ts(0), ts(time.Hour)).Return([]*core.ChartCount{}, nil).Once()
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop")).Once()
+	_, err = cm.GetTimeSeries(context.Background(), "messages", query)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTimeSeriesNotRollupGroup(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	_, err := cm.GetTimeSeries(context.Background(), "operations", &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(time.Hour),
+		Interval:  fftypes.FFDuration(time.Hour),
+		GroupBy:   "status",
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTimeSeriesUnknownCollection(t *testing.T) {
+	cm, mdi, done := newTestChartManager(t)
+	defer done()
+
+	mdi.On("GetChartCounts", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	_, err := cm.GetTimeSeries(context.Background(), "unknown", &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(time.Hour),
+		Interval:  fftypes.FFDuration(time.Hour),
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTimeSeriesBadQuery(t *testing.T) {
+	cm, _, done := newTestChartManager(t)
+	defer done()
+
+	_, err := cm.GetTimeSeries(context.Background(), "messages", &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(time.Hour),
+	})
+	assert.Regexp(t, "FF10630", err)
+
+	_, err = cm.GetTimeSeries(context.Background(), "messages", &core.ChartTimeSeriesQuery{
+		StartTime: ts(time.Hour),
+		EndTime:   ts(0),
+		Interval:  fftypes.FFDuration(time.Minute),
+	})
+	assert.Regexp(t, "FF10300", err)
+
+	_, err = cm.GetTimeSeries(context.Background(), "messages", &core.ChartTimeSeriesQuery{
+		StartTime: ts(0),
+		EndTime:   ts(24 * time.Hour),
+		Interval:  fftypes.FFDuration(time.Second),
+	})
+	assert.Regexp(t, "FF10298", err)
+}
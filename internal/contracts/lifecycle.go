@@ -0,0 +1,132 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contracts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+func methodCacheKey(iface *fftypes.UUID, methodPath string) string {
+	return fmt.Sprintf("method_%s_%s", iface, methodPath)
+}
+
+func (cm *contractManager) checkInterfaceLifecycle(ctx context.Context, iface *fftypes.UUID, lifecycle core.DefinitionLifecycle) error {
+	return core.CheckDefinitionLifecycle(ctx, "contract interface", iface.String(), lifecycle, cm.rejectDeprecated)
+}
+
+// CheckFFILifecycle resolves a reference to an FFI, and verifies it can be used for a new contract API
+func (cm *contractManager) CheckFFILifecycle(ctx context.Context, ref *fftypes.FFIReference) error {
+	if err := cm.ResolveFFIReference(ctx, ref); err != nil {
+		return err
+	}
+	lifecycle, err := cm.database.GetFFILifecycle(ctx, cm.namespace, ref.ID)
+	if err != nil {
+		return err
+	}
+	return cm.checkInterfaceLifecycle(ctx, ref.ID, lifecycle)
+}
+
+func (cm *contractManager) GetFFILifecycle(ctx context.Context, name, version string) (*core.ContractInterfaceLifecycle, error) {
+	ffi, err := cm.GetFFI(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	lifecycle, err := cm.database.GetFFILifecycle(ctx, cm.namespace, ffi.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ContractInterfaceLifecycle{
+		Interface: &fftypes.FFIReference{ID: ffi.ID, Name: ffi.Name, Version: ffi.Version},
+		Lifecycle: lifecycle,
+	}, nil
+}
+
+// UpdateFFILifecycle changes the local lifecycle state of an FFI, which controls whether it can be used for new
+// contract invocations and contract APIs
+func (cm *contractManager) UpdateFFILifecycle(ctx context.Context, name, version string, update *core.DefinitionLifecycleUpdate) (*core.ContractInterfaceLifecycle, error) {
+	lifecycle, err := fftypes.FFEnumParseString(ctx, "definitionlifecycle", string(update.Lifecycle))
+	if err != nil {
+		return nil, err
+	}
+	ffi, err := cm.GetFFI(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := cm.GetFFIMethods(ctx, ffi.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.database.UpdateFFILifecycle(ctx, cm.namespace, ffi.ID, lifecycle); err != nil {
+		return nil, err
+	}
+
+	// Cached method resolutions include the lifecycle, so must be evicted
+	for _, method := range methods {
+		cm.methodCache.Delete(methodCacheKey(ffi.ID, method.Pathname))
+		cm.methodCache.Delete(methodCacheKey(ffi.ID, method.Name))
+	}
+	return &core.ContractInterfaceLifecycle{
+		Interface: &fftypes.FFIReference{ID: ffi.ID, Name: ffi.Name, Version: ffi.Version},
+		Lifecycle: lifecycle,
+	}, nil
+}
+
+// ffiForDiff strips the identifiers that always differ between versions of an FFI, and keys each
+// method, event and error by its pathname so they are compared regardless of their order
+func ffiForDiff(ffi *fftypes.FFI) map[string]interface{} {
+	methods := make(map[string]*fftypes.FFIMethod, len(ffi.Methods))
+	for _, m := range ffi.Methods {
+		method := *m
+		method.ID, method.Interface, method.Namespace = nil, nil, ""
+		methods[m.Pathname] = &method
+	}
+	events := make(map[string]*fftypes.FFIEvent, len(ffi.Events))
+	for _, e := range ffi.Events {
+		event := *e
+		event.ID, event.Interface, event.Namespace = nil, nil, ""
+		events[e.Pathname] = &event
+	}
+	errors := make(map[string]*fftypes.FFIError, len(ffi.Errors))
+	for _, e := range ffi.Errors {
+		errorDef := *e
+		errorDef.ID, errorDef.Interface, errorDef.Namespace = nil, nil, ""
+		errors[e.Pathname] = &errorDef
+	}
+	return map[string]interface{}{
+		"description": ffi.Description,
+		"methods":     methods,
+		"events":      events,
+		"errors":      errors,
+	}
+}
+
+// GetFFIDiff compares the methods, events and errors of two versions of an FFI
+func (cm *contractManager) GetFFIDiff(ctx context.Context, name, fromVersion, toVersion string) (*core.DefinitionDiff, error) {
+	from, err := cm.GetFFIWithChildren(ctx, name, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := cm.GetFFIWithChildren(ctx, name, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return core.NewDefinitionDiff(name, fromVersion, toVersion, ffiForDiff(from), ffiForDiff(to)), nil
+}
@@ -0,0 +1,272 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contracts
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResolveInvokeContractRequestLifecycle(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	interfaceID := fftypes.NewUUID()
+	method := &fftypes.FFIMethod{Name: "doStuff", Pathname: "doStuff"}
+	mdb.On("GetFFIMethod", mock.Anything, "ns1", interfaceID, "doStuff").Return(method, nil).Once()
+	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{}, nil, nil).Once()
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", interfaceID).Return(core.DefinitionLifecycleDeprecated, nil).Once()
+
+	req := &core.ContractCallRequest{Interface: interfaceID, MethodPath: "doStuff"}
+	err := cm.resolveInvokeContractRequest(context.Background(), req)
+	assert.NoError(t, err)
+
+	// The lifecycle is cached with the method
+	cm.rejectDeprecated = true
+	req = &core.ContractCallRequest{Interface: interfaceID, MethodPath: "doStuff"}
+	err = cm.resolveInvokeContractRequest(context.Background(), req)
+	assert.Regexp(t, "FF10599", err)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestResolveInvokeContractRequestLifecycleFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	interfaceID := fftypes.NewUUID()
+	mdb.On("GetFFIMethod", mock.Anything, "ns1", interfaceID, "doStuff").Return(&fftypes.FFIMethod{}, nil)
+	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{}, nil, nil)
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", interfaceID).Return(core.DefinitionLifecycle(""), fmt.Errorf("pop"))
+
+	err := cm.resolveInvokeContractRequest(context.Background(), &core.ContractCallRequest{Interface: interfaceID, MethodPath: "doStuff"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckFFILifecycle(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	ffiID := fftypes.NewUUID()
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: ffiID}, nil)
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", ffiID).Return(core.DefinitionLifecycleRetired, nil)
+
+	ref := &fftypes.FFIReference{Name: "banana", Version: "v1"}
+	err := cm.CheckFFILifecycle(context.Background(), ref)
+	assert.Regexp(t, "FF10598", err)
+	assert.Equal(t, ffiID, ref.ID)
+}
+
+func TestCheckFFILifecycleResolveFail(t *testing.T) {
+	cm := newTestContractManager()
+
+	err := cm.CheckFFILifecycle(context.Background(), nil)
+	assert.Regexp(t, "FF10303", err)
+}
+
+func TestCheckFFILifecycleGetFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	ffiID := fftypes.NewUUID()
+	mdb.On("GetFFIByID", mock.Anything, "ns1", ffiID).Return(&fftypes.FFI{ID: ffiID}, nil)
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", ffiID).Return(core.DefinitionLifecycle(""), fmt.Errorf("pop"))
+
+	err := cm.CheckFFILifecycle(context.Background(), &fftypes.FFIReference{ID: ffiID})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetFFILifecycle(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	ffiID := fftypes.NewUUID()
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: ffiID, Name: "banana", Version: "v1"}, nil)
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", ffiID).Return(core.DefinitionLifecycleActive, nil)
+
+	lifecycle, err := cm.GetFFILifecycle(context.Background(), "banana", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, ffiID, lifecycle.Interface.ID)
+	assert.Equal(t, "banana", lifecycle.Interface.Name)
+	assert.Equal(t, core.DefinitionLifecycleActive, lifecycle.Lifecycle)
+}
+
+func TestGetFFILifecycleNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(nil, nil)
+
+	_, err := cm.GetFFILifecycle(context.Background(), "banana", "v1")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetFFILifecycleFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	ffiID := fftypes.NewUUID()
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: ffiID}, nil)
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", ffiID).Return(core.DefinitionLifecycle(""), fmt.Errorf("pop"))
+
+	_, err := cm.GetFFILifecycle(context.Background(), "banana", "v1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestUpdateFFILifecycle(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	ffiID := fftypes.NewUUID()
+	cm.methodCache.Set(methodCacheKey(ffiID, "set"), &methodCacheEntry{})
+	cm.methodCache.Set(methodCacheKey(ffiID, "set_1"), &methodCacheEntry{})
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: ffiID, Name: "banana", Version: "v1"}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{
+		{Name: "set", Pathname: "set_1"},
+	}, nil, nil)
+	mdb.On("UpdateFFILifecycle", mock.Anything, "ns1", ffiID, core.DefinitionLifecycleDeprecated).Return(nil)
+
+	lifecycle, err := cm.UpdateFFILifecycle(context.Background(), "banana", "v1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: core.DefinitionLifecycleDeprecated,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionLifecycleDeprecated, lifecycle.Lifecycle)
+	assert.Equal(t, "v1", lifecycle.Interface.Version)
+	assert.Nil(t, cm.methodCache.Get(methodCacheKey(ffiID, "set")))
+	assert.Nil(t, cm.methodCache.Get(methodCacheKey(ffiID, "set_1")))
+}
+
+func TestUpdateFFILifecycleBadLifecycle(t *testing.T) {
+	cm := newTestContractManager()
+
+	_, err := cm.UpdateFFILifecycle(context.Background(), "banana", "v1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: "unknown",
+	})
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestUpdateFFILifecycleNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(nil, nil)
+
+	_, err := cm.UpdateFFILifecycle(context.Background(), "banana", "v1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: core.DefinitionLifecycleRetired,
+	})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestUpdateFFILifecycleGetMethodsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := cm.UpdateFFILifecycle(context.Background(), "banana", "v1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: core.DefinitionLifecycleRetired,
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestUpdateFFILifecycleUpdateFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	ffiID := fftypes.NewUUID()
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: ffiID}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{}, nil, nil)
+	mdb.On("UpdateFFILifecycle", mock.Anything, "ns1", ffiID, core.DefinitionLifecycleRetired).Return(fmt.Errorf("pop"))
+
+	_, err := cm.UpdateFFILifecycle(context.Background(), "banana", "v1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: core.DefinitionLifecycleRetired,
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetFFIDiff(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+
+	v1 := &fftypes.FFI{ID: fftypes.NewUUID(), Name: "banana", Version: "v1", Description: "Bananas"}
+	v2 := &fftypes.FFI{ID: fftypes.NewUUID(), Name: "banana", Version: "v2", Description: "Bananas"}
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(v1, nil)
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v2").Return(v2, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{
+		{ID: fftypes.NewUUID(), Interface: v1.ID, Name: "peel", Pathname: "peel", Params: fftypes.FFIParams{}},
+	}, nil, nil).Once()
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{
+		{ID: fftypes.NewUUID(), Interface: v2.ID, Name: "peel", Pathname: "peel", Params: fftypes.FFIParams{}},
+		{ID: fftypes.NewUUID(), Interface: v2.ID, Name: "eat", Pathname: "eat"},
+	}, nil, nil).Once()
+	mdb.On("GetFFIEvents", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{ID: fftypes.NewUUID(), Pathname: "Peeled", FFIEventDefinition: fftypes.FFIEventDefinition{Name: "Peeled"}},
+	}, nil, nil).Once()
+	mdb.On("GetFFIEvents", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIEvent{}, nil, nil).Once()
+	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{}, nil, nil).Once()
+	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{
+		{ID: fftypes.NewUUID(), Pathname: "Bruised", FFIErrorDefinition: fftypes.FFIErrorDefinition{Name: "Bruised"}},
+	}, nil, nil).Once()
+	mbi.On("GenerateEventSignature", mock.Anything, mock.Anything).Return("Peeled()", nil)
+	mbi.On("GenerateErrorSignature", mock.Anything, mock.Anything).Return("Bruised()")
+
+	diff, err := cm.GetFFIDiff(context.Background(), "banana", "v1", "v2")
+	assert.NoError(t, err)
+	assert.Len(t, diff.Changes, 3)
+	assert.Equal(t, "/errors/Bruised", diff.Changes[0].Path)
+	assert.Equal(t, core.DefinitionChangeTypeAdded, diff.Changes[0].Type)
+	assert.Equal(t, "/events/Peeled", diff.Changes[1].Path)
+	assert.Equal(t, core.DefinitionChangeTypeRemoved, diff.Changes[1].Type)
+	assert.Equal(t, "/methods/eat", diff.Changes[2].Path)
+	assert.Equal(t, core.DefinitionChangeTypeAdded, diff.Changes[2].Type)
+}
+
+func TestGetFFIDiffFromFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetFFIDiff(context.Background(), "banana", "v1", "v2")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetFFIDiffToNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v1").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFI", mock.Anything, "ns1", "banana", "v2").Return(nil, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{}, nil, nil)
+	mdb.On("GetFFIEvents", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIEvent{}, nil, nil)
+	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{}, nil, nil)
+	mbi.On("GenerateErrorSignature", mock.Anything, mock.Anything).Return("").Maybe()
+
+	_, err := cm.GetFFIDiff(context.Background(), "banana", "v1", "v2")
+	assert.Regexp(t, "FF10109", err)
+}
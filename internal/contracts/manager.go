@@ -21,11 +21,18 @@ import (
 	"crypto/sha256"
 	"database/sql/driver"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"hash"
+	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -41,6 +48,7 @@ import (
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/internal/triggers"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/internal/txwriter"
 	"github.com/hyperledger/firefly/pkg/blockchain"
@@ -49,6 +57,18 @@ import (
 	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
+const (
+	// listenerHealthStatusConcurrency bounds how many listener status calls are made to the connector at once
+	listenerHealthStatusConcurrency = 10
+)
+
+// listenerHealthStatusTimeout bounds the total time spent checking the status of the listeners of a contract API,
+// after which the remaining listeners are counted as status errors
+var listenerHealthStatusTimeout = 30 * time.Second
+
+// ContractListenerIterator passes each listener matched by a query to the callback as it is read from the database
+type ContractListenerIterator func(ctx context.Context, cb func(listener *core.ContractListener) error) error
+
 type Manager interface {
 	core.Named
 
@@ -57,15 +77,22 @@ type Manager interface {
 	GetFFIByID(ctx context.Context, id *fftypes.UUID) (*fftypes.FFI, error)
 	GetFFIByIDWithChildren(ctx context.Context, id *fftypes.UUID) (*fftypes.FFI, error)
 	GetFFIMethods(ctx context.Context, id *fftypes.UUID) ([]*fftypes.FFIMethod, error)
+	GetFFIMethodSelector(ctx context.Context, name, version, methodPath string) (*core.ContractMethodSelector, error)
 	GetFFIEvents(ctx context.Context, id *fftypes.UUID) ([]*fftypes.FFIEvent, error)
 	GetFFIs(ctx context.Context, filter ffapi.AndFilter) ([]*fftypes.FFI, *ffapi.FilterResult, error)
 	ResolveFFI(ctx context.Context, ffi *fftypes.FFI) error
 	ResolveFFIReference(ctx context.Context, ref *fftypes.FFIReference) error
 	DeleteFFI(ctx context.Context, id *fftypes.UUID) error
+	UpdateFFILifecycle(ctx context.Context, name, version string, update *core.DefinitionLifecycleUpdate) (*core.ContractInterfaceLifecycle, error)
+	GetFFILifecycle(ctx context.Context, name, version string) (*core.ContractInterfaceLifecycle, error)
+	GetFFIDiff(ctx context.Context, name, fromVersion, toVersion string) (*core.DefinitionDiff, error)
+	CheckFFILifecycle(ctx context.Context, ref *fftypes.FFIReference) error
 
 	DeployContract(ctx context.Context, req *core.ContractDeployRequest, waitConfirm bool) (interface{}, error)
 	InvokeContract(ctx context.Context, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error)
 	InvokeContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error)
+	SimulateContract(ctx context.Context, req *core.ContractCallRequest) (*core.Simulation, error)
+	SimulateContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest) (*core.Simulation, error)
 	GetContractAPI(ctx context.Context, httpServerURL, apiName string) (*core.ContractAPI, error)
 	GetContractAPIInterface(ctx context.Context, apiName string) (*fftypes.FFI, error)
 	GetContractAPIs(ctx context.Context, httpServerURL string, filter ffapi.AndFilter) ([]*core.ContractAPI, *ffapi.FilterResult, error)
@@ -78,9 +105,18 @@ type Manager interface {
 	GetContractListenerByNameOrID(ctx context.Context, nameOrID string) (*core.ContractListener, error)
 	GetContractListenerByNameOrIDWithStatus(ctx context.Context, nameOrID string) (*core.ContractListenerWithStatus, error)
 	GetContractListeners(ctx context.Context, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error)
+	GetIdleContractListeners(ctx context.Context, minAge time.Duration, filter ffapi.AndFilter) ([]*core.IdleContractListener, *ffapi.FilterResult, error)
 	GetContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error)
+	IterateContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) (ContractListenerIterator, error)
+	GetContractAPIListenerEvents(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
+	GetContractAPIListenersHealth(ctx context.Context, apiName string, window time.Duration) (*core.ContractAPIListenerHealth, error)
+	GetAllContractAPIListeners(ctx context.Context, apiName string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error)
 	DeleteContractListenerByNameOrID(ctx context.Context, nameOrID string) error
+	DeleteContractListenerByID(ctx context.Context, id *fftypes.UUID) error
+	DeleteContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) ([]*core.ContractListenerDeletion, error)
+	UpdateContractAPIListener(ctx context.Context, apiName, eventPath string, update *core.ContractListenerUpdate) (*core.ContractListener, error)
 	GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error)
+	GenerateFFIFromSource(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error)
 
 	// From operations.OperationHandler
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
@@ -101,20 +137,24 @@ type contractManager struct {
 	ffiParamValidator fftypes.FFIParamValidator
 	operations        operations.Manager
 	syncasync         syncasync.Bridge
+	triggers          triggers.Manager
 	methodCache       cache.CInterface
+	listenerCache     cache.CInterface
+	rejectDeprecated  bool
 }
 
 type methodCacheEntry struct {
-	method *fftypes.FFIMethod
-	errors []*fftypes.FFIError
+	method    *fftypes.FFIMethod
+	errors    []*fftypes.FFIError
+	lifecycle core.DefinitionLifecycle
 }
 
 type schemaValidationEntry struct {
 	schema *jsonschema.Schema
 }
 
-func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi blockchain.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, bp batch.Manager, im identity.Manager, om operations.Manager, txHelper txcommon.Helper, txWriter txwriter.Writer, sa syncasync.Bridge, cacheManager cache.Manager) (Manager, error) {
-	if di == nil || im == nil || bi == nil || dm == nil || om == nil || txHelper == nil || txWriter == nil || sa == nil || cacheManager == nil {
+func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi blockchain.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, bp batch.Manager, im identity.Manager, om operations.Manager, txHelper txcommon.Helper, txWriter txwriter.Writer, sa syncasync.Bridge, tm triggers.Manager, cacheManager cache.Manager) (Manager, error) {
+	if di == nil || im == nil || bi == nil || dm == nil || om == nil || txHelper == nil || txWriter == nil || sa == nil || tm == nil || cacheManager == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "ContractManager")
 	}
 	v, err := bi.GetFFIParamValidator(ctx)
@@ -136,6 +176,8 @@ func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi b
 		ffiParamValidator: v,
 		operations:        om,
 		syncasync:         sa,
+		triggers:          tm,
+		rejectDeprecated:  config.GetString(coreconfig.DefinitionsDeprecatedPolicy) == coreconfig.DeprecatedPolicyReject,
 	}
 
 	cm.methodCache, err = cacheManager.GetCache(
@@ -150,6 +192,19 @@ func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi b
 		return nil, err
 	}
 
+	// The event manager caches listeners by backend ID, so updates must evict them from the same cache
+	cm.listenerCache, err = cacheManager.GetCache(
+		cache.NewCacheConfig(
+			ctx,
+			coreconfig.CacheEventListenerTopicLimit,
+			coreconfig.CacheEventListenerTopicTTL,
+			ns,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	om.RegisterHandler(ctx, cm, []core.OpType{
 		core.OpTypeBlockchainInvoke,
 		core.OpTypeBlockchainContractDeploy,
@@ -208,6 +263,56 @@ func (cm *contractManager) GetFFIMethods(ctx context.Context, id *fftypes.UUID)
 	return methods, err
 }
 
+func (cm *contractManager) GetFFIMethodSelector(ctx context.Context, name, version, methodPath string) (*core.ContractMethodSelector, error) {
+	ffi, err := cm.GetFFI(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := cm.GetFFIMethods(ctx, ffi.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Path names are unique, but we also accept a method name as long as it is not overloaded
+	var method *fftypes.FFIMethod
+	var byName []*fftypes.FFIMethod
+	for _, m := range methods {
+		if m.Pathname == methodPath {
+			method = m
+			break
+		}
+		if m.Name == methodPath {
+			byName = append(byName, m)
+		}
+	}
+	if method == nil {
+		switch len(byName) {
+		case 0:
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		case 1:
+			method = byName[0]
+		default:
+			pathnames := make([]string, len(byName))
+			for i, m := range byName {
+				pathnames[i] = m.Pathname
+			}
+			return nil, i18n.NewError(ctx, coremsgs.MsgFFIMethodOverloaded, methodPath, pathnames)
+		}
+	}
+
+	signature, selector, err := cm.blockchain.GenerateMethodSelector(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ContractMethodSelector{
+		Interface: ffi.ID,
+		Name:      method.Name,
+		Pathname:  method.Pathname,
+		Signature: signature,
+		Selector:  selector,
+	}, nil
+}
+
 func (cm *contractManager) GetFFIEvents(ctx context.Context, id *fftypes.UUID) ([]*fftypes.FFIEvent, error) {
 	fb := database.FFIMethodQueryFactory.NewFilter(ctx)
 	events, _, err := cm.database.GetFFIEvents(ctx, cm.namespace, fb.Eq("interface", id))
@@ -348,6 +453,15 @@ func (cm *contractManager) writeInvokeTransaction(ctx context.Context, req *core
 					req.Message.TransactionID = idemErr.ExistingTXID
 				}
 				return true, resubmitted[0], nil // only one operation, return existing one
+			} else if existing, opErr := cm.getIdempotentOperation(ctx, idemErr.ExistingTXID, core.OpTypeBlockchainInvoke); opErr != nil {
+				err = opErr
+			} else if existing != nil {
+				// The original request is within the idempotency window - return it, rather than a 409 Conflict error
+				if req.Message != nil {
+					req.Message.Header.TxType = txtype
+					req.Message.TransactionID = idemErr.ExistingTXID
+				}
+				return true, existing, nil
 			}
 		}
 		return false, op, err
@@ -385,6 +499,11 @@ func (cm *contractManager) writeDeployTransaction(ctx context.Context, req *core
 				// We successfully resubmitted an initialized operation, return the operation
 				// and the idempotent error. The caller will revert the 409 to 2xx
 				return true, resubmitted[0], nil // only one operation, return existing one
+			} else if existing, opErr := cm.getIdempotentOperation(ctx, idemErr.ExistingTXID, core.OpTypeBlockchainContractDeploy); opErr != nil {
+				err = opErr
+			} else if existing != nil {
+				// The original request is within the idempotency window - return it, rather than a 409 Conflict error
+				return true, existing, nil
 			}
 		}
 		return false, op, err
@@ -393,11 +512,55 @@ func (cm *contractManager) writeDeployTransaction(ctx context.Context, req *core
 	return false, op, err
 }
 
+// getIdempotentOperation returns the operation of the original request for a duplicate idempotency key,
+// if the original request was submitted within the idempotency window
+func (cm *contractManager) getIdempotentOperation(ctx context.Context, txid *fftypes.UUID, opType core.OpType) (*core.Operation, error) {
+	tx, err := cm.txHelper.GetIdempotentTransaction(ctx, txid)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	return cm.txHelper.FindOperationInTransaction(ctx, tx.ID, opType)
+}
+
+// resolveDeployInterface checks the interface of a deployment, and generates the definition from it if required
+func (cm *contractManager) resolveDeployInterface(ctx context.Context, req *core.ContractDeployRequest) error {
+	if req.Interface == nil {
+		if req.API != "" {
+			return i18n.NewError(ctx, coremsgs.MsgDeployAPIRequiresInterface, req.API)
+		}
+		if req.Definition == nil {
+			return i18n.NewError(ctx, coremsgs.MsgDeployDefinitionRequired)
+		}
+		return nil
+	}
+	if req.API != "" {
+		if err := fftypes.ValidateFFNameField(ctx, req.API, "api"); err != nil {
+			return err
+		}
+	}
+	if err := cm.ResolveFFIReference(ctx, req.Interface); err != nil {
+		return err
+	}
+	if req.Definition == nil {
+		ffi, err := cm.GetFFIByIDWithChildren(ctx, req.Interface.ID)
+		if err != nil {
+			return err
+		}
+		if req.Definition, err = cm.blockchain.GenerateDeployDefinition(ctx, ffi, req.ConstructorParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (cm *contractManager) DeployContract(ctx context.Context, req *core.ContractDeployRequest, waitConfirm bool) (res interface{}, err error) {
 	req.Key, err = cm.identity.ResolveInputSigningKey(ctx, req.Key, identity.KeyNormalizationBlockchainPlugin)
 	if err != nil {
 		return nil, err
 	}
+	if err := cm.resolveDeployInterface(ctx, req); err != nil {
+		return nil, err
+	}
 
 	resubmit, op, err := cm.writeDeployTransaction(ctx, req)
 	if err != nil {
@@ -443,6 +606,11 @@ func (cm *contractManager) InvokeContract(ctx context.Context, req *core.Contrac
 	if err != nil {
 		return nil, err
 	}
+	if req.Type == core.CallTypeInvoke && len(req.Triggers) > 0 {
+		if err := cm.triggers.ValidateTriggers(ctx, req.Triggers); err != nil {
+			return nil, err
+		}
+	}
 	if msgSender != nil {
 		if err := msgSender.Prepare(ctx); err != nil {
 			return nil, err
@@ -461,6 +629,12 @@ func (cm *contractManager) InvokeContract(ctx context.Context, req *core.Contrac
 		if resubmit {
 			return op, nil
 		}
+		// Write the follow-up actions before the invoke is submitted, so they are waiting when it succeeds
+		if len(req.Triggers) > 0 {
+			if err := cm.triggers.AddTriggers(ctx, &core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: op.ID}, req.Triggers); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	switch req.Type {
@@ -488,19 +662,53 @@ func (cm *contractManager) InvokeContract(ctx context.Context, req *core.Contrac
 	}
 }
 
+// SimulateContract validates an invoke request and asks the blockchain connector to evaluate it,
+// without creating a transaction or operations
+func (cm *contractManager) SimulateContract(ctx context.Context, req *core.ContractCallRequest) (res *core.Simulation, err error) {
+	if req.Message != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSimulateWithMessage)
+	}
+	req.Key, err = cm.identity.ResolveInputSigningKey(ctx, req.Key, identity.KeyNormalizationBlockchainPlugin)
+	if err != nil {
+		return nil, err
+	}
+	if err := cm.resolveInvokeContractRequest(ctx, req); err != nil {
+		return nil, err
+	}
+	bcParsedMethod, err := cm.validateInvokeContractRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	return cm.blockchain.SimulateContract(ctx, req.Key, req.Location, bcParsedMethod, req.Input, req.Options)
+}
+
 func (cm *contractManager) InvokeContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error) {
+	if err := cm.resolveContractAPIRequest(ctx, apiName, methodPath, req); err != nil {
+		return nil, err
+	}
+	return cm.InvokeContract(ctx, req, waitConfirm)
+}
+
+func (cm *contractManager) SimulateContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest) (*core.Simulation, error) {
+	if err := cm.resolveContractAPIRequest(ctx, apiName, methodPath, req); err != nil {
+		return nil, err
+	}
+	return cm.SimulateContract(ctx, req)
+}
+
+func (cm *contractManager) resolveContractAPIRequest(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest) error {
 	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
 	if err != nil {
-		return nil, err
+		return err
 	} else if api == nil || api.Interface == nil {
-		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		return i18n.NewError(ctx, coremsgs.Msg404NotFound)
 	}
 	req.Interface = api.Interface.ID
 	req.MethodPath = methodPath
 	if api.Location != nil {
 		req.Location = api.Location
 	}
-	return cm.InvokeContract(ctx, req, waitConfirm)
+	return nil
 }
 
 func (cm *contractManager) resolveInvokeContractRequest(ctx context.Context, req *core.ContractCallRequest) (err error) {
@@ -508,13 +716,13 @@ func (cm *contractManager) resolveInvokeContractRequest(ctx context.Context, req
 		if req.MethodPath == "" || req.Interface == nil {
 			return i18n.NewError(ctx, coremsgs.MsgContractMethodNotSet)
 		}
-		cacheKey := fmt.Sprintf("method_%s_%s", req.Interface, req.MethodPath)
+		cacheKey := methodCacheKey(req.Interface, req.MethodPath)
 		cached := cm.methodCache.Get(cacheKey)
 		if cached != nil {
 			cMethodDetails := cached.(*methodCacheEntry)
 			req.Method = cMethodDetails.method
 			req.Errors = cMethodDetails.errors
-			return nil
+			return cm.checkInterfaceLifecycle(ctx, req.Interface, cMethodDetails.lifecycle)
 		}
 		req.Method, err = cm.database.GetFFIMethod(ctx, cm.namespace, req.Interface, req.MethodPath)
 		if err != nil || req.Method == nil {
@@ -525,10 +733,16 @@ func (cm *contractManager) resolveInvokeContractRequest(ctx context.Context, req
 		if err != nil {
 			return i18n.NewError(ctx, coremsgs.MsgContractErrorsResolveError, err)
 		}
+		lifecycle, err := cm.database.GetFFILifecycle(ctx, cm.namespace, req.Interface)
+		if err != nil {
+			return err
+		}
 		cm.methodCache.Set(cacheKey, &methodCacheEntry{
-			method: req.Method,
-			errors: req.Errors,
+			method:    req.Method,
+			errors:    req.Errors,
+			lifecycle: lifecycle,
 		})
+		return cm.checkInterfaceLifecycle(ctx, req.Interface, lifecycle)
 	}
 	return nil
 }
@@ -1017,6 +1231,16 @@ func (cm *contractManager) verifyContractListener(ctx context.Context, listener
 		}
 	}
 
+	if listener.Options != nil && listener.Options.FromBlock != "" {
+		if err := applyListenerFromBlock(ctx, listener.Options); err != nil {
+			return nil, err
+		}
+	}
+	if listener.Options != nil {
+		if err := listener.Options.ValidateOutputFilters(ctx); err != nil {
+			return nil, err
+		}
+	}
 	if listener.Options == nil {
 		listener.Options = cm.getDefaultContractListenerOptions()
 	} else if listener.Options.FirstEvent == "" {
@@ -1109,6 +1333,9 @@ func (cm *contractManager) AddContractListener(ctx context.Context, listener *co
 	if err = cm.blockchain.AddContractListener(ctx, &listener.ContractListener, ""); err != nil {
 		return nil, err
 	}
+	if err = cm.checkListenerStartBlock(ctx, &listener.ContractListener); err != nil {
+		return nil, err
+	}
 	if listener.Name == "" {
 		listener.Name = listener.BackendID
 	}
@@ -1119,6 +1346,38 @@ func (cm *contractManager) AddContractListener(ctx context.Context, listener *co
 	return verifiedContractListener, err
 }
 
+// applyListenerFromBlock validates a requested historical start block, and passes it to the blockchain connector as the first event
+func applyListenerFromBlock(ctx context.Context, options *core.ContractListenerOptions) error {
+	if options.FromBlock != string(core.SubOptsFirstEventOldest) {
+		if _, err := strconv.ParseUint(options.FromBlock, 10, 64); err != nil {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidListenerFromBlock, options.FromBlock)
+		}
+	}
+	if options.FirstEvent != "" && options.FirstEvent != options.FromBlock {
+		return i18n.NewError(ctx, coremsgs.MsgListenerFromBlockConflict, options.FirstEvent, options.FromBlock)
+	}
+	options.FirstEvent = options.FromBlock
+	return nil
+}
+
+// checkListenerStartBlock compares an absolute block requested for a new listener, with the block the blockchain connector
+// reports it started from. A connector that could only start later, such as when the block is older than what its node
+// retains, would otherwise silently skip the events in between - so the listener is removed and an error returned instead.
+func (cm *contractManager) checkListenerStartBlock(ctx context.Context, listener *core.ContractListener) error {
+	requested, err := strconv.ParseUint(listener.Options.FirstEvent, 10, 64)
+	effective := listener.Options.FromBlock
+	if err != nil || effective == "" || effective == listener.Options.FirstEvent {
+		return nil
+	}
+	if started, err := strconv.ParseUint(effective, 10, 64); err == nil && started <= requested {
+		return nil
+	}
+	if err := cm.blockchain.DeleteContractListener(ctx, listener, true); err != nil {
+		log.L(ctx).Errorf("Failed to remove listener %s (BackendID=%s) that could not start from block %d: %s", listener.ID, listener.BackendID, requested, err)
+	}
+	return i18n.NewError(ctx, coremsgs.MsgListenerFromBlockPruned, listener.Options.FirstEvent, effective)
+}
+
 func (cm *contractManager) AddContractAPIListener(ctx context.Context, apiName, eventPath string, listener *core.ContractListener) (output *core.ContractListener, err error) {
 	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
 	if err != nil {
@@ -1201,34 +1460,291 @@ func (cm *contractManager) GetContractListeners(ctx context.Context, filter ffap
 	return cm.database.GetContractListeners(ctx, cm.namespace, filter)
 }
 
+// GetIdleContractListeners returns the listeners created at least minAge ago, that have not recorded
+// a single blockchain event. These are usually listening on the wrong address or event signature.
+// The skip and limit of the filter apply to the idle listeners, which are returned oldest first.
+func (cm *contractManager) GetIdleContractListeners(ctx context.Context, minAge time.Duration, filter ffapi.AndFilter) ([]*core.IdleContractListener, *ffapi.FilterResult, error) {
+	fi, err := filter.Finalize()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := fftypes.Now()
+	cutoff := fftypes.FFTime(now.Time().Add(-minAge))
+
+	var page uint64
+	var pageSize uint64 = 50
+	var skipped uint64
+	idle := []*core.IdleContractListener{}
+	for {
+		fb := database.ContractListenerQueryFactory.NewFilter(ctx)
+		f := fb.And(filter, fb.Lte("created", &cutoff)).Sort("created").Skip(page * pageSize).Limit(pageSize)
+		listeners, _, err := cm.database.GetContractListeners(ctx, cm.namespace, f)
+		if err != nil {
+			return nil, nil, err
+		}
+		listenerIDs := make([]*fftypes.UUID, len(listeners))
+		for i, l := range listeners {
+			listenerIDs[i] = l.ID
+		}
+		lastEvents, err := cm.database.GetBlockchainEventListenerTimestamps(ctx, cm.namespace, listenerIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, l := range listeners {
+			if _, fired := lastEvents[*l.ID]; fired {
+				continue
+			}
+			if skipped < fi.Skip {
+				skipped++
+				continue
+			}
+			var age fftypes.FFDuration
+			if l.Created != nil {
+				age = fftypes.FFDuration(now.Time().Sub(*l.Created.Time()))
+			}
+			idle = append(idle, &core.IdleContractListener{ContractListener: *l, Age: age})
+			if fi.Limit > 0 && uint64(len(idle)) >= fi.Limit {
+				return idle, nil, nil
+			}
+		}
+		if len(listeners) < int(pageSize) {
+			return idle, nil, nil
+		}
+		page++
+	}
+}
+
 func (cm *contractManager) GetContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error) {
-	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
+	f, err := cm.contractAPIListenersFilter(ctx, apiName, eventPath, filter)
 	if err != nil {
 		return nil, nil, err
-	} else if api == nil || api.Interface == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
 	}
-	event, err := cm.resolveEvent(ctx, api.Interface, eventPath)
+	return cm.database.GetContractListeners(ctx, cm.namespace, f)
+}
+
+// IterateContractAPIListeners resolves the same listeners as GetContractAPIListeners, but returns an iterator
+// that reads them from the database one at a time. The API and event are resolved before the iterator is returned.
+func (cm *contractManager) IterateContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) (ContractListenerIterator, error) {
+	f, err := cm.contractAPIListenersFilter(ctx, apiName, eventPath, filter)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, cb func(listener *core.ContractListener) error) error {
+		return cm.database.IterateContractListeners(ctx, cm.namespace, f, cb)
+	}, nil
+}
+
+// GetContractAPIListenerEvents returns the blockchain events delivered by the same listeners as GetContractAPIListeners.
+// The events are always sorted by block number, then protocol ID (which orders events within a block by log index),
+// so that a block range can be paged through deterministically.
+func (cm *contractManager) GetContractAPIListenerEvents(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
+	listenerFilter, err := cm.contractAPIListenersFilter(ctx, apiName, eventPath, database.ContractListenerQueryFactory.NewFilter(ctx).And())
 	if err != nil {
 		return nil, nil, err
 	}
-	signature, err := cm.blockchain.GenerateEventSignatureWithLocation(ctx, &event.FFIEventDefinition, api.Location)
+	var listenerIDs []driver.Value
+	err = cm.database.IterateContractListeners(ctx, cm.namespace, listenerFilter, func(listener *core.ContractListener) error {
+		listenerIDs = append(listenerIDs, listener.ID)
+		return nil
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	oldSignature, err := cm.blockchain.GenerateEventSignature(ctx, &event.FFIEventDefinition)
+	filter.Sort("blocknumber", "protocolid")
+	fi, err := filter.Finalize()
 	if err != nil {
 		return nil, nil, err
 	}
+	if len(fi.Sort) != 2 || fi.Sort[0].Descending || fi.Sort[1].Descending {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgListenerEventsSortFixed)
+	}
+	if len(listenerIDs) == 0 {
+		return []*core.BlockchainEvent{}, nil, nil
+	}
+	filter.Condition(filter.Builder().In("listener", listenerIDs))
+	return cm.database.GetBlockchainEvents(ctx, cm.namespace, filter)
+}
 
+func (cm *contractManager) contractAPIListenersFilter(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) (ffapi.Filter, error) {
+	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
+	if err != nil {
+		return nil, err
+	} else if api == nil || api.Interface == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	event, err := cm.resolveEvent(ctx, api.Interface, eventPath)
+	if err != nil {
+		return nil, err
+	}
 	fb := database.ContractListenerQueryFactory.NewFilter(ctx)
-	f := fb.And(
+	eventFilter, err := cm.apiEventListenerFilter(ctx, fb, api, &event.FFIEventDefinition)
+	if err != nil {
+		return nil, err
+	}
+	return fb.And(
 		fb.Eq("interface", api.Interface.ID),
-		fb.Or(fb.Contains("signature", signature), fb.Eq("signature", oldSignature)),
+		eventFilter,
 		filter,
-	)
-	return cm.database.GetContractListeners(ctx, cm.namespace, f)
+	), nil
+}
+
+// apiEventListenerFilter matches the listeners on an event at the location of a contract API, including
+// listeners created before signatures included the location
+func (cm *contractManager) apiEventListenerFilter(ctx context.Context, fb ffapi.FilterBuilder, api *core.ContractAPI, event *fftypes.FFIEventDefinition) (ffapi.Filter, error) {
+	signature, err := cm.blockchain.GenerateEventSignatureWithLocation(ctx, event, api.Location)
+	if err != nil {
+		return nil, err
+	}
+	oldSignature, err := cm.blockchain.GenerateEventSignature(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	return fb.Or(fb.Contains("signature", signature), fb.Eq("signature", oldSignature)), nil
+}
+
+// GetAllContractAPIListeners returns the listeners on all the events of a contract API
+func (cm *contractManager) GetAllContractAPIListeners(ctx context.Context, apiName string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error) {
+	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
+	if err != nil {
+		return nil, nil, err
+	} else if api == nil || api.Interface == nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	apiFilter, err := cm.apiListenersFilter(ctx, filter.Builder(), api)
+	if err != nil {
+		return nil, nil, err
+	} else if apiFilter == nil {
+		return []*core.ContractListener{}, nil, nil
+	}
+	return cm.database.GetContractListeners(ctx, cm.namespace, filter.Condition(apiFilter))
+}
+
+// apiListenersFilter matches the listeners on any of the events of a contract API, or returns nil if
+// the interface of the API has no events
+func (cm *contractManager) apiListenersFilter(ctx context.Context, fb ffapi.FilterBuilder, api *core.ContractAPI) (ffapi.Filter, error) {
+	if err := cm.ResolveFFIReference(ctx, api.Interface); err != nil {
+		return nil, err
+	}
+	events, err := cm.GetFFIEvents(ctx, api.Interface.ID)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	eventFilters := make([]ffapi.Filter, len(events))
+	for i, event := range events {
+		if eventFilters[i], err = cm.apiEventListenerFilter(ctx, fb, api, &event.FFIEventDefinition); err != nil {
+			return nil, err
+		}
+	}
+	return fb.And(
+		fb.Eq("interface", api.Interface.ID),
+		fb.Or(eventFilters...),
+	), nil
+}
+
+// GetContractAPIListenersHealth summarizes the health of the listeners on all the events of a contract API,
+// from the most recent blockchain event of each listener and its status in the blockchain connector
+func (cm *contractManager) GetContractAPIListenersHealth(ctx context.Context, apiName string, window time.Duration) (*core.ContractAPIListenerHealth, error) {
+	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
+	if err != nil {
+		return nil, err
+	} else if api == nil || api.Interface == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	fb := database.ContractListenerQueryFactory.NewFilter(ctx)
+	apiFilter, err := cm.apiListenersFilter(ctx, fb, api)
+	if err != nil {
+		return nil, err
+	}
+	health := &core.ContractAPIListenerHealth{}
+	if apiFilter == nil {
+		return health, nil
+	}
+
+	recentCutoff := time.Now().Add(-window)
+	statusCtx, cancel := context.WithTimeout(ctx, listenerHealthStatusTimeout)
+	defer cancel()
+	var page uint64
+	var pageSize uint64 = 50
+	for {
+		f := fb.And(apiFilter).Sort("created").Skip(page * pageSize).Limit(pageSize)
+		listeners, _, err := cm.database.GetContractListeners(ctx, cm.namespace, f)
+		if err != nil {
+			return nil, err
+		}
+		if err := cm.addListenersHealth(ctx, statusCtx, health, listeners, recentCutoff); err != nil {
+			return nil, err
+		}
+		if len(listeners) < int(pageSize) {
+			return health, nil
+		}
+		page++
+	}
+}
+
+// addListenersHealth adds a page of listeners to the health summary, reading the most recent event of every
+// listener in a single query, and checking their status in the blockchain connector concurrently
+func (cm *contractManager) addListenersHealth(ctx, statusCtx context.Context, health *core.ContractAPIListenerHealth, listeners []*core.ContractListener, recentCutoff time.Time) error {
+	listenerIDs := make([]*fftypes.UUID, len(listeners))
+	for i, l := range listeners {
+		listenerIDs[i] = l.ID
+	}
+	lastEvents, err := cm.database.GetBlockchainEventListenerTimestamps(ctx, cm.namespace, listenerIDs)
+	if err != nil {
+		return err
+	}
+
+	type listenerStatus struct {
+		found  bool
+		status core.ContractListenerStatus
+		err    error
+	}
+	statuses := make([]listenerStatus, len(listeners))
+	slots := make(chan struct{}, listenerHealthStatusConcurrency)
+	var wg sync.WaitGroup
+	for i, l := range listeners {
+		wg.Add(1)
+		go func(i int, l *core.ContractListener) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-statusCtx.Done():
+			}
+			if statusCtx.Err() != nil {
+				statuses[i].err = i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+				return
+			}
+			statuses[i].found, _, statuses[i].status, statuses[i].err = cm.blockchain.GetContractListenerStatus(statusCtx, l.Namespace, l.BackendID, true)
+		}(i, l)
+	}
+	wg.Wait()
+
+	for i, l := range listeners {
+		health.Total++
+		lastEvent := lastEvents[*l.ID]
+		if lastEvent == nil {
+			health.NeverFired++
+		} else {
+			if lastEvent.Time().After(recentCutoff) {
+				health.FiredRecently++
+			}
+			if health.OldestLastEvent == nil || lastEvent.Time().Before(*health.OldestLastEvent.Time()) {
+				health.OldestLastEvent = lastEvent
+			}
+		}
+
+		switch s := statuses[i]; {
+		case s.err != nil:
+			log.L(ctx).Warnf("Unable to get status of listener %s (BackendID=%s): %s", l.ID, l.BackendID, s.err)
+			health.StatusErrors++
+		case !s.found:
+			health.Drifted++
+		case s.status == core.ContractListenerStatusSyncing:
+			health.Syncing++
+		}
+	}
+	return nil
 }
 
 func (cm *contractManager) DeleteContractListenerByNameOrID(ctx context.Context, nameOrID string) error {
@@ -1244,6 +1760,195 @@ func (cm *contractManager) DeleteContractListenerByNameOrID(ctx context.Context,
 	})
 }
 
+// DeleteContractListenerByID deletes a contract listener, and deregisters it from the blockchain connector,
+// as long as no subscription is filtering blockchain events on that listener
+func (cm *contractManager) DeleteContractListenerByID(ctx context.Context, id *fftypes.UUID) error {
+	return cm.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
+		listener, err := cm.database.GetContractListenerByID(ctx, cm.namespace, id)
+		if err != nil {
+			return err
+		}
+		if listener == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		subs, err := cm.getSubscriptionsByListener(ctx)
+		if err != nil {
+			return err
+		}
+		if err = cm.checkListenerNotSubscribed(ctx, listener, subs); err != nil {
+			return err
+		}
+		return cm.deleteContractListener(ctx, listener)
+	})
+}
+
+// DeleteContractAPIListeners deletes the listeners on an event of a contract API that match the filter - the same
+// listeners that would be returned by GetContractAPIListeners. None are deleted if any are in use by a subscription.
+func (cm *contractManager) DeleteContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) (deleted []*core.ContractListenerDeletion, err error) {
+	err = cm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		listeners, _, err := cm.GetContractAPIListeners(ctx, apiName, eventPath, filter)
+		if err != nil {
+			return err
+		}
+		subs, err := cm.getSubscriptionsByListener(ctx)
+		if err != nil {
+			return err
+		}
+		for _, listener := range listeners {
+			if err := cm.checkListenerNotSubscribed(ctx, listener, subs); err != nil {
+				return err
+			}
+		}
+		deleted = make([]*core.ContractListenerDeletion, 0, len(listeners))
+		for _, listener := range listeners {
+			if err := cm.deleteContractListener(ctx, listener); err != nil {
+				return err
+			}
+			deleted = append(deleted, &core.ContractListenerDeletion{
+				ID:           listener.ID,
+				Name:         listener.Name,
+				BackendID:    listener.BackendID,
+				Deregistered: true,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// UpdateContractAPIListener changes the name or options of a listener on an event of a contract API in place, so
+// the listener keeps its ID and the ordering of its events. Output filters are applied by FireFly, so changing only
+// those is a database update. Other changed options are applied on the blockchain connector before the database,
+// so a connector that cannot update the listener leaves it unchanged.
+func (cm *contractManager) UpdateContractAPIListener(ctx context.Context, apiName, eventPath string, update *core.ContractListenerUpdate) (listener *core.ContractListener, err error) {
+	if update.ID == nil {
+		return nil, i18n.NewError(ctx, i18n.MsgNilID)
+	}
+	err = cm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		fb := database.ContractListenerQueryFactory.NewFilter(ctx)
+		listeners, _, err := cm.GetContractAPIListeners(ctx, apiName, eventPath, fb.And(fb.Eq("id", update.ID)))
+		if err != nil {
+			return err
+		} else if len(listeners) == 0 {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		listener = listeners[0]
+		if err := checkListenerImmutableFields(ctx, listener, update); err != nil {
+			return err
+		}
+
+		dbUpdate := database.ContractListenerQueryFactory.NewUpdate(ctx).S()
+		if update.Name != "" && update.Name != listener.Name {
+			if err := fftypes.ValidateFFNameField(ctx, update.Name, "name"); err != nil {
+				return err
+			}
+			if existing, err := cm.database.GetContractListener(ctx, cm.namespace, update.Name); err != nil {
+				return err
+			} else if existing != nil {
+				return i18n.NewError(ctx, coremsgs.MsgContractListenerNameExists, cm.namespace, update.Name)
+			}
+			listener.Name = update.Name
+			dbUpdate.Set("name", update.Name)
+		}
+		if update.Options != nil && !jsonEquivalent(update.Options, listener.Options) {
+			if err := update.Options.ValidateOutputFilters(ctx); err != nil {
+				return err
+			}
+			connectorChanged := !jsonEquivalent(withoutOutputFilters(update.Options), withoutOutputFilters(listener.Options))
+			listener.Options = update.Options
+			if connectorChanged {
+				if err := cm.blockchain.UpdateContractListener(ctx, listener); err != nil {
+					return err
+				}
+			}
+			options, _ := json.Marshal(update.Options)
+			dbUpdate.Set("options", fftypes.JSONAnyPtrBytes(options))
+		}
+		if dbUpdate.IsEmpty() {
+			return nil
+		}
+		return cm.database.UpdateContractListener(ctx, cm.namespace, listener.ID, dbUpdate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	cm.listenerCache.Delete(fmt.Sprintf("pid:%s", listener.BackendID))
+	return listener, nil
+}
+
+// withoutOutputFilters returns the options a blockchain connector is concerned with
+func withoutOutputFilters(options *core.ContractListenerOptions) *core.ContractListenerOptions {
+	if options == nil {
+		return &core.ContractListenerOptions{}
+	}
+	connectorOptions := *options
+	connectorOptions.OutputFilters = nil
+	return &connectorOptions
+}
+
+// checkListenerImmutableFields rejects an update that sets any field that cannot be changed to a different value
+func checkListenerImmutableFields(ctx context.Context, listener *core.ContractListener, update *core.ContractListenerUpdate) error {
+	switch {
+	case update.Topic != "" && update.Topic != listener.Topic:
+		return i18n.NewError(ctx, coremsgs.MsgContractListenerFieldImmutable, "topic", listener.ID)
+	case update.Signature != "" && update.Signature != listener.Signature:
+		return i18n.NewError(ctx, coremsgs.MsgContractListenerFieldImmutable, "signature", listener.ID)
+	case update.Location != nil && !jsonEquivalent(update.Location, listener.Location):
+		return i18n.NewError(ctx, coremsgs.MsgContractListenerFieldImmutable, "location", listener.ID)
+	case update.Event != nil && !jsonEquivalent(update.Event, listener.Event):
+		return i18n.NewError(ctx, coremsgs.MsgContractListenerFieldImmutable, "event", listener.ID)
+	}
+	return nil
+}
+
+// jsonEquivalent compares the JSON serialization of two values, ignoring formatting and the order of keys
+func jsonEquivalent(a, b interface{}) bool {
+	var aParsed, bParsed interface{}
+	aBytes, _ := json.Marshal(a)
+	bBytes, _ := json.Marshal(b)
+	_ = json.Unmarshal(aBytes, &aParsed)
+	_ = json.Unmarshal(bBytes, &bParsed)
+	return reflect.DeepEqual(aParsed, bParsed)
+}
+
+func (cm *contractManager) deleteContractListener(ctx context.Context, listener *core.ContractListener) error {
+	if err := cm.blockchain.DeleteContractListener(ctx, listener, true /* ok if not found */); err != nil {
+		return err
+	}
+	return cm.database.DeleteContractListenerByID(ctx, cm.namespace, listener.ID)
+}
+
+// getSubscriptionsByListener returns the subscriptions that filter blockchain events by listener
+func (cm *contractManager) getSubscriptionsByListener(ctx context.Context) ([]*core.Subscription, error) {
+	subs, _, err := cm.database.GetSubscriptions(ctx, cm.namespace, database.SubscriptionQueryFactory.NewFilter(ctx).And())
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*core.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.Filter.BlockchainEvent.Listener != "" {
+			filtered = append(filtered, sub)
+		}
+	}
+	return filtered, nil
+}
+
+// checkListenerNotSubscribed fails if the blockchain event listener filter of any of the subscriptions matches
+// the listener, as deleting the listener would leave that subscription with no events
+func (cm *contractManager) checkListenerNotSubscribed(ctx context.Context, listener *core.ContractListener, subs []*core.Subscription) error {
+	for _, sub := range subs {
+		// Subscriptions with an invalid filter cannot be started, so cannot be using the listener
+		listenerFilter, err := regexp.Compile(sub.Filter.BlockchainEvent.Listener)
+		if err == nil && listenerFilter.MatchString(listener.ID.String()) {
+			return i18n.NewError(ctx, coremsgs.MsgContractListenerSubscribed, listener.ID, sub.Name)
+		}
+	}
+	return nil
+}
+
 func (cm *contractManager) checkParamSchema(ctx context.Context, name string, input interface{}, schema *jsonschema.Schema) error {
 	if err := schema.Validate(input); err != nil {
 		return i18n.WrapError(ctx, err, coremsgs.MsgFFIValidationFail, name)
@@ -1266,6 +1971,16 @@ func (cm *contractManager) GenerateFFI(ctx context.Context, generationRequest *f
 	return ffi, err
 }
 
+// GenerateFFIFromSource compiles contract source (such as Solidity) via the blockchain plugin, then generates the FFI from the result
+func (cm *contractManager) GenerateFFIFromSource(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error) {
+	input, err := cm.blockchain.CompileContractInterface(ctx, generationRequest.Input)
+	if err != nil {
+		return nil, err
+	}
+	generationRequest.Input = input
+	return cm.GenerateFFI(ctx, generationRequest)
+}
+
 func (cm *contractManager) getDefaultContractListenerOptions() *core.ContractListenerOptions {
 	return &core.ContractListenerOptions{
 		FirstEvent: string(core.SubOptsFirstEventNewest),
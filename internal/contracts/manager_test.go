@@ -46,6 +46,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/triggermocks"
 	"github.com/hyperledger/firefly/mocks/txcommonmocks"
 	"github.com/hyperledger/firefly/mocks/txwritermocks"
 	"github.com/hyperledger/firefly/pkg/blockchain"
@@ -83,13 +84,13 @@ func newTestContractManager() *contractManager {
 			a[1].(func(context.Context) error)(a[0].(context.Context)),
 		}
 	}
-	cm, _ := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, cmi)
+	cm, _ := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, &triggermocks.Manager{}, cmi)
 	cm.(*contractManager).txHelper = &txcommonmocks.Helper{}
 	return cm.(*contractManager)
 }
 
 func TestNewContractManagerFail(t *testing.T) {
-	_, err := NewContractManager(context.Background(), "", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewContractManager(context.Background(), "", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -120,7 +121,7 @@ func TestNewContractManagerVerifyListenersFails(t *testing.T) {
 	mbi.On("Name").Return("mockblockchain").Maybe()
 	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("KABOOM!")).Once()
 
-	cm, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, cmi)
+	cm, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, &triggermocks.Manager{}, cmi)
 	assert.Nil(t, cm)
 	assert.NotNil(t, err)
 }
@@ -141,7 +142,7 @@ func TestNewContractManagerFFISchemaLoaderFail(t *testing.T) {
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
 	msa := &syncasyncmocks.Bridge{}
 	mbi.On("GetFFIParamValidator", mock.Anything).Return(nil, fmt.Errorf("pop"))
-	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, cmi)
+	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, &triggermocks.Manager{}, cmi)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -160,7 +161,27 @@ func TestNewContractManagerCacheConfigFail(t *testing.T) {
 	txHelper := &txcommonmocks.Helper{}
 	msa := &syncasyncmocks.Bridge{}
 	mbi.On("GetFFIParamValidator", mock.Anything).Return(nil, nil)
-	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, cmi)
+	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, &triggermocks.Manager{}, cmi)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestNewContractManagerListenerCacheInitFail(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	mbm := &broadcastmocks.Manager{}
+	mpm := &privatemessagingmocks.Manager{}
+	mbp := &batchmocks.Manager{}
+	mim := &identitymanagermocks.Manager{}
+	mbi := &blockchainmocks.Plugin{}
+	mom := &operationmocks.Manager{}
+	txw := &txwritermocks.Writer{}
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute), nil).Once()
+	cmi.On("GetCache", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	txHelper := &txcommonmocks.Helper{}
+	msa := &syncasyncmocks.Bridge{}
+	mbi.On("GetFFIParamValidator", mock.Anything).Return(nil, nil)
+	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, &triggermocks.Manager{}, cmi)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -182,7 +203,7 @@ func TestNewContractManagerFFISchemaLoader(t *testing.T) {
 	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return(nil, nil, nil)
 	mbi.On("GetFFIParamValidator", mock.Anything).Return(&ffi2abi.ParamValidator{}, nil)
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
-	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, cmi)
+	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, txw, msa, &triggermocks.Manager{}, cmi)
 	assert.NoError(t, err)
 }
 
@@ -1696,6 +1717,167 @@ func TestAddContractListenerUpsertSubFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func newTestFromBlockListener(cm *contractManager, options *core.ContractListenerOptions, effectiveFromBlock string) *core.ContractListenerInput {
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := &core.ContractListenerInput{
+		ContractListener: core.ContractListener{
+			Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+				"address": "0x123",
+			}.String()),
+			Event: &core.FFISerializedEvent{
+				FFIEventDefinition: fftypes.FFIEventDefinition{
+					Name: "changed",
+				},
+			},
+			Options: options,
+			Topic:   "test-topic",
+		},
+	}
+
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeListener, sub.Location).Return(sub.Location, nil).Maybe()
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil).Maybe()
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil).Maybe()
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(nil, nil, nil).Maybe()
+	mbi.On("AddContractListener", context.Background(), &sub.ContractListener, "").Run(func(args mock.Arguments) {
+		args[1].(*core.ContractListener).Options.FromBlock = effectiveFromBlock
+	}).Return(nil).Maybe()
+	return sub
+}
+
+func TestAddContractListenerFromBlock(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FromBlock: "100"}, "100")
+	mdi.On("InsertContractListener", context.Background(), &sub.ContractListener).Return(nil)
+
+	result, err := cm.AddContractListener(context.Background(), sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", result.Options.FirstEvent)
+	assert.Equal(t, "100", result.Options.FromBlock)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerFromBlockOldest(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FromBlock: "oldest"}, "0")
+	mdi.On("InsertContractListener", context.Background(), &sub.ContractListener).Return(nil)
+
+	result, err := cm.AddContractListener(context.Background(), sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "oldest", result.Options.FirstEvent)
+	assert.Equal(t, "0", result.Options.FromBlock)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerFirstEventBlockStartedEarlier(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FirstEvent: "100"}, "99")
+	mdi.On("InsertContractListener", context.Background(), &sub.ContractListener).Return(nil)
+
+	result, err := cm.AddContractListener(context.Background(), sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "99", result.Options.FromBlock)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerFromBlockInvalid(t *testing.T) {
+	cm := newTestContractManager()
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FromBlock: "latest"}, "")
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10513.*latest", err)
+}
+
+func TestAddContractListenerFromBlockConflict(t *testing.T) {
+	cm := newTestContractManager()
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FirstEvent: "newest", FromBlock: "100"}, "")
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10514", err)
+}
+
+func TestAddContractListenerFromBlockPruned(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FromBlock: "100"}, "5000")
+	mbi.On("DeleteContractListener", context.Background(), &sub.ContractListener, true).Return(fmt.Errorf("pop"))
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10515.*100.*5000", err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerFromBlockStartedLatest(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{FromBlock: "100"}, "latest")
+	mbi.On("DeleteContractListener", context.Background(), &sub.ContractListener, true).Return(nil)
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10515.*latest", err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerOutputFilters(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{
+		OutputFilters: []*core.ListenerOutputFilter{
+			{Field: "value", Op: core.ListenerOutputFilterOpGt, Value: "1000"},
+		},
+	}, "")
+	mdi.On("InsertContractListener", context.Background(), &sub.ContractListener).Return(nil)
+
+	result, err := cm.AddContractListener(context.Background(), sub)
+	assert.NoError(t, err)
+	assert.Len(t, result.Options.OutputFilters, 1)
+	assert.Equal(t, "newest", result.Options.FirstEvent)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerOutputFiltersInvalid(t *testing.T) {
+	cm := newTestContractManager()
+
+	sub := newTestFromBlockListener(cm, &core.ContractListenerOptions{
+		OutputFilters: []*core.ListenerOutputFilter{
+			{Field: "value", Op: "contains", Value: "1000"},
+		},
+	}, "")
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10547.*contains", err)
+}
+
 func TestAddContractAPIListener(t *testing.T) {
 	cm := newTestContractManager()
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
@@ -1802,6 +1984,110 @@ func TestGetFFIFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestGetFFIMethodSelector(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	ffiID := fftypes.NewUUID()
+	method := &fftypes.FFIMethod{Name: "set", Pathname: "set"}
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(&fftypes.FFI{ID: ffiID}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{
+		{Name: "get", Pathname: "get"},
+		method,
+	}, nil, nil)
+	mbi.On("GenerateMethodSelector", mock.Anything, method).Return("set(uint256)", "0x60fe47b1", nil)
+
+	selector, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.NoError(t, err)
+	assert.Equal(t, &core.ContractMethodSelector{
+		Interface: ffiID,
+		Name:      "set",
+		Pathname:  "set",
+		Signature: "set(uint256)",
+		Selector:  "0x60fe47b1",
+	}, selector)
+
+	mdb.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestGetFFIMethodSelectorByName(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	method := &fftypes.FFIMethod{Name: "set", Pathname: "set_1"}
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{method}, nil, nil)
+	mbi.On("GenerateMethodSelector", mock.Anything, method).Return("set(uint256)", "0x60fe47b1", nil)
+
+	selector, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.NoError(t, err)
+	assert.Equal(t, "set_1", selector.Pathname)
+
+	mdb.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestGetFFIMethodSelectorOverloaded(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{
+		{Name: "set", Pathname: "set_0"},
+		{Name: "set", Pathname: "set_1"},
+	}, nil, nil)
+
+	_, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.Regexp(t, "FF10482.*set_0 set_1", err)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestGetFFIMethodSelectorNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{}, nil, nil)
+
+	_, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.Regexp(t, "FF10109", err)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestGetFFIMethodSelectorFFIFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetFFIMethodSelectorMethodsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetFFIMethodSelectorGenerateFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdb.On("GetFFI", mock.Anything, "ns1", "ffi", "v1.0.0").Return(&fftypes.FFI{ID: fftypes.NewUUID()}, nil)
+	mdb.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{
+		{Name: "set", Pathname: "set"},
+	}, nil, nil)
+	mbi.On("GenerateMethodSelector", mock.Anything, mock.Anything).Return("", "", fmt.Errorf("pop"))
+
+	_, err := cm.GetFFIMethodSelector(context.Background(), "ffi", "v1.0.0", "set")
+	assert.EqualError(t, err, "pop")
+}
+
 func TestGetFFIWithChildren(t *testing.T) {
 	cm := newTestContractManager()
 	mdb := cm.database.(*databasemocks.Plugin)
@@ -2057,6 +2343,7 @@ func TestDeployContractIdempotentNoOperationToResubmit(t *testing.T) {
 		ExistingTXID:  id,
 		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
 	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, nil)
 	mim.On("ResolveInputSigningKey", mock.Anything, signingKey, identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
 
 	// If ResubmitOperations returns nil it's because there was no operation in initialized state, so we expect the regular 409 error back
@@ -2069,6 +2356,76 @@ func TestDeployContractIdempotentNoOperationToResubmit(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestDeployContractIdempotentWithinWindow(t *testing.T) {
+	cm := newTestContractManager()
+	var id = fftypes.NewUUID()
+	opID := fftypes.NewUUID()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+	signingKey := "0x2468"
+	req := &core.ContractDeployRequest{
+		Key:            signingKey,
+		Definition:     fftypes.JSONAnyPtr("[]"),
+		Contract:       fftypes.JSONAnyPtr("\"0x123456\""),
+		Input:          []interface{}{"one", "two", "three"},
+		IdempotencyKey: "idem1",
+	}
+
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractDeploy, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainContractDeploy && op.Plugin == "mockblockchain"
+	})).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(&core.Transaction{ID: id}, nil)
+	mth.On("FindOperationInTransaction", context.Background(), id, core.OpTypeBlockchainContractDeploy).Return(&core.Operation{ID: opID}, nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, signingKey, identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+
+	// The original request is within the idempotency window, so its operation is returned
+	op, err := cm.DeployContract(context.Background(), req, false)
+	assert.NoError(t, err)
+	assert.Equal(t, opID, op.(*core.Operation).ID)
+
+	mth.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestDeployContractIdempotentWindowFail(t *testing.T) {
+	cm := newTestContractManager()
+	var id = fftypes.NewUUID()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+	signingKey := "0x2468"
+	req := &core.ContractDeployRequest{
+		Key:            signingKey,
+		Definition:     fftypes.JSONAnyPtr("[]"),
+		Contract:       fftypes.JSONAnyPtr("\"0x123456\""),
+		Input:          []interface{}{"one", "two", "three"},
+		IdempotencyKey: "idem1",
+	}
+
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractDeploy, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainContractDeploy && op.Plugin == "mockblockchain"
+	})).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, fmt.Errorf("pop"))
+	mim.On("ResolveInputSigningKey", mock.Anything, signingKey, identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+
+	_, err := cm.DeployContract(context.Background(), req, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestDeployContractIdempotentErrorOnOperationResubmit(t *testing.T) {
 	cm := newTestContractManager()
 	var id = fftypes.NewUUID()
@@ -2192,11 +2549,145 @@ func TestDeployContractSubmitNewTransactionFail(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
-func TestInvokeContract(t *testing.T) {
+func TestDeployContractFromInterface(t *testing.T) {
 	cm := newTestContractManager()
 	mim := cm.identity.(*identitymanagermocks.Manager)
 	mdi := cm.database.(*databasemocks.Plugin)
-	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mom := cm.operations.(*operationmocks.Manager)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+	interfaceID := fftypes.NewUUID()
+	req := &core.ContractDeployRequest{
+		Key:       "0x2468",
+		Interface: &fftypes.FFIReference{Name: "simple", Version: "v1"},
+		ConstructorParams: fftypes.FFIParams{
+			{Name: "initial", Schema: fftypes.JSONAnyPtr(`{"type":"integer"}`)},
+		},
+		Contract: fftypes.JSONAnyPtr("\"0x123456\""),
+		Input:    []interface{}{1},
+		API:      "simple-api",
+	}
+	ffi := &fftypes.FFI{ID: interfaceID, Name: "simple", Version: "v1"}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "0x2468", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetFFI", mock.Anything, "ns1", "simple", "v1").Return(ffi, nil)
+	mdi.On("GetFFIByID", mock.Anything, "ns1", interfaceID).Return(ffi, nil)
+	mdi.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{}, nil, nil)
+	mdi.On("GetFFIEvents", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIEvent{}, nil, nil)
+	mdi.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{}, nil, nil)
+	mbi.On("GenerateDeployDefinition", mock.Anything, ffi, req.ConstructorParams).Return(fftypes.JSONAnyPtr(`[{"type":"constructor"}]`), nil)
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractDeploy, core.IdempotencyKey(""), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeBlockchainContractDeploy && op.Input.GetString("api") == "simple-api"
+	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	mom.On("RunOperation", mock.Anything, mock.Anything, false).Return(nil, nil)
+
+	_, err := cm.DeployContract(context.Background(), req, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaceID, req.Interface.ID)
+	assert.Equal(t, `[{"type":"constructor"}]`, req.Definition.String())
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestDeployContractDefinitionRequired(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+
+	_, err := cm.DeployContract(context.Background(), &core.ContractDeployRequest{}, false)
+	assert.Regexp(t, "FF10611", err)
+
+	_, err = cm.DeployContract(context.Background(), &core.ContractDeployRequest{
+		Definition: fftypes.JSONAnyPtr("[]"),
+		API:        "simple-api",
+	}, false)
+	assert.Regexp(t, "FF10612.*simple-api", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestDeployContractBadAPIName(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+
+	_, err := cm.DeployContract(context.Background(), &core.ContractDeployRequest{
+		Interface: &fftypes.FFIReference{ID: fftypes.NewUUID()},
+		API:       "!bad",
+	}, false)
+	assert.Regexp(t, "FF00140.*api", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestDeployContractInterfaceNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	interfaceID := fftypes.NewUUID()
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetFFIByID", mock.Anything, "ns1", interfaceID).Return(nil, nil)
+
+	_, err := cm.DeployContract(context.Background(), &core.ContractDeployRequest{
+		Interface: &fftypes.FFIReference{ID: interfaceID},
+	}, false)
+	assert.Regexp(t, "FF10303", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestDeployContractGetInterfaceFail(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	interfaceID := fftypes.NewUUID()
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetFFIByID", mock.Anything, "ns1", interfaceID).Return(&fftypes.FFI{ID: interfaceID}, nil).Once()
+	mdi.On("GetFFIByID", mock.Anything, "ns1", interfaceID).Return(nil, fmt.Errorf("pop")).Once()
+
+	_, err := cm.DeployContract(context.Background(), &core.ContractDeployRequest{
+		Interface: &fftypes.FFIReference{ID: interfaceID},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestDeployContractGenerateDefinitionFail(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	interfaceID := fftypes.NewUUID()
+	ffi := &fftypes.FFI{ID: interfaceID}
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetFFIByID", mock.Anything, "ns1", interfaceID).Return(ffi, nil)
+	mdi.On("GetFFIMethods", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIMethod{}, nil, nil)
+	mdi.On("GetFFIEvents", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIEvent{}, nil, nil)
+	mdi.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return([]*fftypes.FFIError{}, nil, nil)
+	mbi.On("GenerateDeployDefinition", mock.Anything, ffi, fftypes.FFIParams(nil)).Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.DeployContract(context.Background(), &core.ContractDeployRequest{
+		Interface: &fftypes.FFIReference{ID: interfaceID},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestInvokeContract(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
 	mom := cm.operations.(*operationmocks.Manager)
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
 	txw := cm.txWriter.(*txwritermocks.Writer)
@@ -2237,6 +2728,126 @@ func TestInvokeContract(t *testing.T) {
 	mbi.AssertExpectations(t)
 }
 
+func TestInvokeContractWithTriggers(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mom := cm.operations.(*operationmocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+	mtm := cm.triggers.(*triggermocks.Manager)
+
+	triggers := []*core.TriggerAction{
+		{Type: core.TriggerActionTypeInvoke, Invoke: &core.TriggerInvoke{API: "myapi", MethodPath: "set"}},
+	}
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+		Triggers: triggers,
+	}
+
+	var opID *fftypes.UUID
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey(""), mock.MatchedBy(func(op *core.Operation) bool {
+		opID = op.ID
+		return op.Type == core.OpTypeBlockchainInvoke
+	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mtm.On("ValidateTriggers", mock.Anything, triggers).Return(nil)
+	mtm.On("AddTriggers", mock.Anything, mock.MatchedBy(func(source *core.TriggerSource) bool {
+		return source.Type == core.TriggerSourceTypeOperation && source.ID.Equals(opID)
+	}), triggers).Return(nil)
+	mom.On("RunOperation", mock.Anything, mock.Anything, false).Return(nil, nil)
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+
+	_, err := cm.InvokeContract(context.Background(), req, false)
+
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+}
+
+func TestInvokeContractTriggersInvalid(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mtm := cm.triggers.(*triggermocks.Manager)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+		Triggers: []*core.TriggerAction{{Type: "unknown"}},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mtm.On("ValidateTriggers", mock.Anything, req.Triggers).Return(fmt.Errorf("pop"))
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+
+	_, err := cm.InvokeContract(context.Background(), req, false)
+
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+}
+
+func TestInvokeContractAddTriggersFail(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+	mtm := cm.triggers.(*triggermocks.Manager)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+		Triggers: []*core.TriggerAction{{Type: core.TriggerActionTypeInvoke}},
+	}
+
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey(""), mock.Anything).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mtm.On("ValidateTriggers", mock.Anything, req.Triggers).Return(nil)
+	mtm.On("AddTriggers", mock.Anything, mock.Anything, req.Triggers).Return(fmt.Errorf("pop"))
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+
+	_, err := cm.InvokeContract(context.Background(), req, false)
+
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+}
+
 func TestInvokeContractViaFFI(t *testing.T) {
 	cm := newTestContractManager()
 	mim := cm.identity.(*identitymanagermocks.Manager)
@@ -2276,6 +2887,7 @@ func TestInvokeContractViaFFI(t *testing.T) {
 	mdb := cm.database.(*databasemocks.Plugin)
 	mdb.On("GetFFIMethod", mock.Anything, "ns1", req.Interface, req.MethodPath).Return(method, nil)
 	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return(errors, nil, nil)
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", req.Interface).Return(core.DefinitionLifecycleActive, nil)
 
 	_, err := cm.InvokeContract(context.Background(), req, false)
 
@@ -2691,43 +3303,54 @@ func TestInvokeContractIdempotentResubmitOperation(t *testing.T) {
 	mbm.AssertExpectations(t)
 }
 
-func TestInvokeContractIdempotentNoOperationToResubmit(t *testing.T) {
+func TestInvokeContractIdempotentWithinWindowMessage(t *testing.T) {
 	cm := newTestContractManager()
 	var id = fftypes.NewUUID()
 	mim := cm.identity.(*identitymanagermocks.Manager)
 	mth := cm.txHelper.(*txcommonmocks.Helper)
 	mom := cm.operations.(*operationmocks.Manager)
 	mbm := cm.blockchain.(*blockchainmocks.Plugin)
+	mbrm := cm.broadcast.(*broadcastmocks.Manager)
 	txw := cm.txWriter.(*txwritermocks.Writer)
+	sender := &syncasyncmocks.Sender{}
 
 	req := &core.ContractCallRequest{
 		Type:      core.CallTypeInvoke,
 		Interface: fftypes.NewUUID(),
 		Location:  fftypes.JSONAnyPtr(""),
 		Method: &fftypes.FFIMethod{
-			Name:    "doStuff",
-			ID:      fftypes.NewUUID(),
-			Params:  fftypes.FFIParams{},
+			Name: "doStuff",
+			ID:   fftypes.NewUUID(),
+			Params: fftypes.FFIParams{
+				{
+					Name:   "data",
+					Schema: fftypes.JSONAnyPtr(`{"type":"string"}`),
+				}},
 			Returns: fftypes.FFIParams{},
 		},
+		Message:        &core.MessageInOut{},
 		IdempotencyKey: "idem1",
 	}
 
-	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+	mbrm.On("NewBroadcast", req.Message).Return(sender, nil)
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvokePin, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
 		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
 	})).Return(nil, &sqlcommon.IdempotencyError{
 		ExistingTXID:  id,
 		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
-	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mom.On("ResubmitOperations", context.Background(), id).Return(1, nil, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(&core.Transaction{ID: id}, nil)
+	mth.On("FindOperationInTransaction", context.Background(), id, core.OpTypeBlockchainInvoke).Return(&core.Operation{ID: fftypes.NewUUID()}, nil)
 	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
 	opaqueData := "anything"
 	mbm.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
-	mbm.On("ValidateInvokeRequest", context.Background(), opaqueData, req.Input, false).Return(nil)
+	sender.On("Prepare", mock.Anything).Return(nil) // we won't do send though
+	mbm.On("ValidateInvokeRequest", context.Background(), opaqueData, req.Input, true).Return(nil)
 
-	// If ResubmitOperations returns nil it's because there was no operation in initialized state, so we expect the regular 409 error back
+	// The original request is within the idempotency window, so the message refers to the original transaction
 	_, err := cm.InvokeContract(context.Background(), req, false)
-	assert.Error(t, err)
-	assert.ErrorContains(t, err, "FF10431")
+	assert.NoError(t, err)
+	assert.Equal(t, id, req.Message.TransactionID)
 
 	mth.AssertExpectations(t)
 	mim.AssertExpectations(t)
@@ -2735,7 +3358,7 @@ func TestInvokeContractIdempotentNoOperationToResubmit(t *testing.T) {
 	mbm.AssertExpectations(t)
 }
 
-func TestInvokeContractIdempotentErrorOnOperationResubmit(t *testing.T) {
+func TestInvokeContractIdempotentNoOperationToResubmit(t *testing.T) {
 	cm := newTestContractManager()
 	var id = fftypes.NewUUID()
 	mim := cm.identity.(*identitymanagermocks.Manager)
@@ -2762,16 +3385,17 @@ func TestInvokeContractIdempotentErrorOnOperationResubmit(t *testing.T) {
 	})).Return(nil, &sqlcommon.IdempotencyError{
 		ExistingTXID:  id,
 		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
-	mom.On("ResubmitOperations", context.Background(), id).Return(-1, nil, fmt.Errorf("pop"))
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, nil)
 	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
 	opaqueData := "anything"
 	mbm.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
 	mbm.On("ValidateInvokeRequest", context.Background(), opaqueData, req.Input, false).Return(nil)
 
-	// If ResubmitOperations returns an error trying to resubmit an operation we expect that back, not a 409 conflict
+	// If ResubmitOperations returns nil it's because there was no operation in initialized state, so we expect the regular 409 error back
 	_, err := cm.InvokeContract(context.Background(), req, false)
 	assert.Error(t, err)
-	assert.ErrorContains(t, err, "pop")
+	assert.ErrorContains(t, err, "FF10431")
 
 	mth.AssertExpectations(t)
 	mim.AssertExpectations(t)
@@ -2779,14 +3403,14 @@ func TestInvokeContractIdempotentErrorOnOperationResubmit(t *testing.T) {
 	mbm.AssertExpectations(t)
 }
 
-func TestInvokeContractConfirm(t *testing.T) {
+func TestInvokeContractIdempotentWithinWindow(t *testing.T) {
 	cm := newTestContractManager()
+	var id = fftypes.NewUUID()
+	opID := fftypes.NewUUID()
 	mim := cm.identity.(*identitymanagermocks.Manager)
-	mdi := cm.database.(*databasemocks.Plugin)
 	mth := cm.txHelper.(*txcommonmocks.Helper)
 	mom := cm.operations.(*operationmocks.Manager)
-	msa := cm.syncasync.(*syncasyncmocks.Bridge)
-	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbm := cm.blockchain.(*blockchainmocks.Plugin)
 	txw := cm.txWriter.(*txwritermocks.Writer)
 
 	req := &core.ContractCallRequest{
@@ -2804,41 +3428,35 @@ func TestInvokeContractConfirm(t *testing.T) {
 
 	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
 		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
-	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	})).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(&core.Transaction{ID: id}, nil)
+	mth.On("FindOperationInTransaction", context.Background(), id, core.OpTypeBlockchainInvoke).Return(&core.Operation{ID: opID}, nil)
 	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
-	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(txcommon.BlockchainInvokeData)
-		return op.Type == core.OpTypeBlockchainInvoke && data.Request == req
-	}), true).Return(nil, nil)
-	msa.On("WaitForInvokeOperation", mock.Anything, mock.Anything, mock.Anything).
-		Run(func(args mock.Arguments) {
-			send := args[2].(syncasync.SendFunction)
-			send(context.Background())
-		}).
-		Return(&core.Operation{}, nil)
 	opaqueData := "anything"
-	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
-	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
-
-	_, err := cm.InvokeContract(context.Background(), req, true)
+	mbm.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbm.On("ValidateInvokeRequest", context.Background(), opaqueData, req.Input, false).Return(nil)
 
+	// The original request is within the idempotency window, so its operation is returned
+	op, err := cm.InvokeContract(context.Background(), req, false)
 	assert.NoError(t, err)
+	assert.Equal(t, opID, op.(*core.Operation).ID)
 
 	mth.AssertExpectations(t)
 	mim.AssertExpectations(t)
-	mdi.AssertExpectations(t)
 	mom.AssertExpectations(t)
-	msa.AssertExpectations(t)
-	mbi.AssertExpectations(t)
+	mbm.AssertExpectations(t)
 }
 
-func TestInvokeContractFail(t *testing.T) {
+func TestInvokeContractIdempotentWindowFail(t *testing.T) {
 	cm := newTestContractManager()
+	var id = fftypes.NewUUID()
 	mim := cm.identity.(*identitymanagermocks.Manager)
-	mdi := cm.database.(*databasemocks.Plugin)
 	mth := cm.txHelper.(*txcommonmocks.Helper)
 	mom := cm.operations.(*operationmocks.Manager)
-	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbm := cm.blockchain.(*blockchainmocks.Plugin)
 	txw := cm.txWriter.(*txwritermocks.Writer)
 
 	req := &core.ContractCallRequest{
@@ -2856,15 +3474,155 @@ func TestInvokeContractFail(t *testing.T) {
 
 	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
 		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
-	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	})).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(1 /* total */, nil /* to resubmit */, nil)
+	mth.On("GetIdempotentTransaction", context.Background(), id).Return(nil, fmt.Errorf("pop"))
 	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
-	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(txcommon.BlockchainInvokeData)
-		return op.Type == core.OpTypeBlockchainInvoke && data.Request == req
-	}), true).Return(nil, fmt.Errorf("pop"))
 	opaqueData := "anything"
-	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
-	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+	mbm.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbm.On("ValidateInvokeRequest", context.Background(), opaqueData, req.Input, false).Return(nil)
+
+	_, err := cm.InvokeContract(context.Background(), req, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+}
+
+func TestInvokeContractIdempotentErrorOnOperationResubmit(t *testing.T) {
+	cm := newTestContractManager()
+	var id = fftypes.NewUUID()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	mbm := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
+	})).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  id,
+		OriginalError: i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateTransaction, "idem1", id)})
+	mom.On("ResubmitOperations", context.Background(), id).Return(-1, nil, fmt.Errorf("pop"))
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	opaqueData := "anything"
+	mbm.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbm.On("ValidateInvokeRequest", context.Background(), opaqueData, req.Input, false).Return(nil)
+
+	// If ResubmitOperations returns an error trying to resubmit an operation we expect that back, not a 409 conflict
+	_, err := cm.InvokeContract(context.Background(), req, false)
+	assert.Error(t, err)
+	assert.ErrorContains(t, err, "pop")
+
+	mth.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+}
+
+func TestInvokeContractConfirm(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	msa := cm.syncasync.(*syncasyncmocks.Bridge)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
+	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(txcommon.BlockchainInvokeData)
+		return op.Type == core.OpTypeBlockchainInvoke && data.Request == req
+	}), true).Return(nil, nil)
+	msa.On("WaitForInvokeOperation", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.SendFunction)
+			send(context.Background())
+		}).
+		Return(&core.Operation{}, nil)
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+
+	_, err := cm.InvokeContract(context.Background(), req, true)
+
+	assert.NoError(t, err)
+
+	mth.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+	msa.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestInvokeContractFail(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
+	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(txcommon.BlockchainInvokeData)
+		return op.Type == core.OpTypeBlockchainInvoke && data.Request == req
+	}), true).Return(nil, fmt.Errorf("pop"))
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
 
 	_, err := cm.InvokeContract(context.Background(), req, false)
 
@@ -3251,6 +4009,95 @@ func TestGetContractListenersFail(t *testing.T) {
 	assert.Regexp(t, "pop", err.Error())
 }
 
+func TestGetIdleContractListeners(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	created := fftypes.FFTime(time.Now().Add(-2 * time.Hour))
+	fired := &core.ContractListener{ID: fftypes.NewUUID(), Created: &created}
+	idle := &core.ContractListener{ID: fftypes.NewUUID(), Created: &created}
+	page1 := make([]*core.ContractListener, 50)
+	for i := range page1 {
+		page1[i] = fired
+	}
+	page1[49] = idle
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(page1, nil, nil).Once()
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return([]*core.ContractListener{
+		{ID: fftypes.NewUUID()},
+	}, nil, nil).Once()
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", mock.Anything).Return(map[fftypes.UUID]*fftypes.FFTime{
+		*fired.ID: fftypes.Now(),
+	}, nil)
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	listeners, _, err := cm.GetIdleContractListeners(context.Background(), time.Hour, fb.And())
+	assert.NoError(t, err)
+	assert.Len(t, listeners, 2)
+	assert.Equal(t, idle.ID, listeners[0].ID)
+	assert.GreaterOrEqual(t, time.Duration(listeners[0].Age), 2*time.Hour)
+	assert.Zero(t, listeners[1].Age)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetIdleContractListenersSkipLimit(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	page1 := make([]*core.ContractListener, 50)
+	for i := range page1 {
+		page1[i] = &core.ContractListener{ID: fftypes.NewUUID()}
+	}
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(page1, nil, nil).Once()
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", mock.Anything).Return(map[fftypes.UUID]*fftypes.FFTime{
+		*page1[0].ID: fftypes.Now(),
+	}, nil)
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	filter := fb.And()
+	filter.Skip(2).Limit(3)
+	listeners, _, err := cm.GetIdleContractListeners(context.Background(), 0, filter)
+	assert.NoError(t, err)
+	assert.Len(t, listeners, 3)
+	assert.Equal(t, page1[3].ID, listeners[0].ID)
+	assert.Equal(t, page1[5].ID, listeners[2].ID)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetIdleContractListenersBadFilter(t *testing.T) {
+	cm := newTestContractManager()
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetIdleContractListeners(context.Background(), 0, fb.And(fb.Eq("created", map[bool]bool{true: false})))
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestGetIdleContractListenersFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetIdleContractListeners(context.Background(), 0, fb.And())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetIdleContractListenersEventsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return([]*core.ContractListener{
+		{ID: fftypes.NewUUID()},
+	}, nil, nil)
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetIdleContractListeners(context.Background(), 0, fb.And())
+	assert.Regexp(t, "pop", err)
+}
+
 func TestGetContractAPIListeners(t *testing.T) {
 	cm := newTestContractManager()
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
@@ -3286,6 +4133,68 @@ func TestGetContractAPIListeners(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestIterateContractAPIListeners(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	interfaceID := fftypes.NewUUID()
+	api := &core.ContractAPI{
+		Interface: &fftypes.FFIReference{
+			ID: interfaceID,
+		},
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+	}
+	event := &fftypes.FFIEvent{
+		FFIEventDefinition: fftypes.FFIEventDefinition{
+			Name: "changed",
+		},
+	}
+	listener := &core.ContractListener{ID: fftypes.NewUUID()}
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(api, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", interfaceID).Return(&fftypes.FFI{}, nil)
+	mdi.On("GetFFIEvent", context.Background(), "ns1", interfaceID, "changed").Return(event, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	mdi.On("IterateContractListeners", context.Background(), "ns1", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cb := args[3].(func(listener *core.ContractListener) error)
+			cb(listener)
+		}).
+		Return(nil)
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	iterator, err := cm.IterateContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.NoError(t, err)
+
+	var iterated []*core.ContractListener
+	err = iterator(context.Background(), func(l *core.ContractListener) error {
+		iterated = append(iterated, l)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ContractListener{listener}, iterated)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestIterateContractAPIListenersNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, nil)
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, err := cm.IterateContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestGetContractAPIListenersSignatureFail(t *testing.T) {
 	cm := newTestContractManager()
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
@@ -3401,150 +4310,937 @@ func TestGetContractAPIListenersEventNotFound(t *testing.T) {
 	mdi.On("GetFFIByID", context.Background(), "ns1", interfaceID).Return(&fftypes.FFI{}, nil)
 	mdi.On("GetFFIEvent", context.Background(), "ns1", interfaceID, "changed").Return(nil, nil)
 
-	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
-	_, _, err := cm.GetContractAPIListeners(context.Background(), "simple", "changed", f.And())
-	assert.Regexp(t, "FF10370", err)
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.Regexp(t, "FF10370", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func newTestListenerHealthAPI(mdi *databasemocks.Plugin) *core.ContractAPI {
+	interfaceID := fftypes.NewUUID()
+	api := &core.ContractAPI{
+		Interface: &fftypes.FFIReference{
+			ID: interfaceID,
+		},
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+	}
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(api, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", interfaceID).Return(&fftypes.FFI{}, nil)
+	return api
+}
+
+func TestGetContractAPIListenersHealth(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "removed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+
+	recent, drifted, neverFired, statusErr := &core.ContractListener{ID: fftypes.NewUUID(), Namespace: "ns1", BackendID: "sub1"},
+		&core.ContractListener{ID: fftypes.NewUUID(), Namespace: "ns1", BackendID: "sub2"},
+		&core.ContractListener{ID: fftypes.NewUUID(), Namespace: "ns1", BackendID: "sub3"},
+		&core.ContractListener{ID: fftypes.NewUUID(), Namespace: "ns1", BackendID: "sub4"}
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return([]*core.ContractListener{
+		recent, drifted, neverFired, statusErr,
+	}, nil, nil)
+
+	oldest := fftypes.FFTime(time.Now().Add(-3 * time.Hour))
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", []*fftypes.UUID{recent.ID, drifted.ID, neverFired.ID, statusErr.ID}).Return(map[fftypes.UUID]*fftypes.FFTime{
+		*recent.ID:    fftypes.Now(),
+		*drifted.ID:   &oldest,
+		*statusErr.ID: fftypes.Now(),
+	}, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub1", true).Return(true, nil, core.ContractListenerStatusSynced, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub2", true).Return(false, nil, core.ContractListenerStatusUnknown, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub3", true).Return(true, nil, core.ContractListenerStatusSyncing, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub4", true).Return(false, nil, core.ContractListenerStatusUnknown, fmt.Errorf("pop"))
+
+	health, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.ContractAPIListenerHealth{
+		Total:           4,
+		FiredRecently:   2,
+		NeverFired:      1,
+		Syncing:         1,
+		Drifted:         1,
+		StatusErrors:    1,
+		OldestLastEvent: &oldest,
+	}, health)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenersHealthPaging(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	page1 := make([]*core.ContractListener, 50)
+	for i := range page1 {
+		page1[i] = &core.ContractListener{ID: fftypes.NewUUID(), Namespace: "ns1", BackendID: "sub1"}
+	}
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(page1, nil, nil).Once()
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return([]*core.ContractListener{}, nil, nil).Once()
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", mock.Anything).Return(map[fftypes.UUID]*fftypes.FFTime{}, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub1", true).Return(true, nil, core.ContractListenerStatusSynced, nil)
+
+	health, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 50, health.Total)
+	assert.Equal(t, 50, health.NeverFired)
+	assert.Nil(t, health.OldestLastEvent)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenersHealthNoEvents(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{}, nil, nil)
+
+	health, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.ContractAPIListenerHealth{}, health)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenersHealthNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, nil)
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetContractAPIListenersHealthAPIFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetContractAPIListenersHealthInterfaceFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(&core.ContractAPI{
+		Interface: &fftypes.FFIReference{ID: fftypes.NewUUID()},
+	}, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetContractAPIListenersHealthEventsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetContractAPIListenersHealthSignatureFail(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetContractAPIListenersHealthListenersFail(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetContractAPIListenersHealthLastEventFail(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return([]*core.ContractListener{
+		{ID: fftypes.NewUUID()},
+	}, nil, nil)
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetContractAPIListenersHealthStatusTimeout(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	defer func(timeout time.Duration) { listenerHealthStatusTimeout = timeout }(listenerHealthStatusTimeout)
+	listenerHealthStatusTimeout = 10 * time.Millisecond
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	listeners := make([]*core.ContractListener, listenerHealthStatusConcurrency+5)
+	for i := range listeners {
+		listeners[i] = &core.ContractListener{ID: fftypes.NewUUID(), Namespace: "ns1", BackendID: "sub1"}
+	}
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(listeners, nil, nil)
+	mdi.On("GetBlockchainEventListenerTimestamps", context.Background(), "ns1", mock.Anything).Return(map[fftypes.UUID]*fftypes.FFTime{}, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub1", true).
+		Run(func(args mock.Arguments) {
+			<-args[0].(context.Context).Done()
+		}).
+		Return(false, nil, core.ContractListenerStatusUnknown, fmt.Errorf("timeout"))
+
+	health, err := cm.GetContractAPIListenersHealth(context.Background(), "simple", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, len(listeners), health.Total)
+	assert.Equal(t, len(listeners), health.StatusErrors)
+	mbi.AssertNumberOfCalls(t, "GetContractListenerStatus", listenerHealthStatusConcurrency)
+}
+
+func TestDeleteContractListener(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := &core.ContractListener{
+		ID: fftypes.NewUUID(),
+	}
+
+	mdi.On("GetContractListener", context.Background(), "ns1", "sub1").Return(sub, nil)
+	mbi.On("DeleteContractListener", context.Background(), sub, true).Return(nil)
+	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", sub.ID).Return(nil)
+
+	err := cm.DeleteContractListenerByNameOrID(context.Background(), "sub1")
+	assert.NoError(t, err)
+}
+
+func TestDeleteContractListenerBlockchainFail(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := &core.ContractListener{
+		ID: fftypes.NewUUID(),
+	}
+
+	mdi.On("GetContractListener", context.Background(), "ns1", "sub1").Return(sub, nil)
+	mbi.On("DeleteContractListener", context.Background(), sub, true).Return(fmt.Errorf("pop"))
+	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", sub.ID).Return(nil)
+
+	err := cm.DeleteContractListenerByNameOrID(context.Background(), "sub1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteContractListenerNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractListener", context.Background(), "ns1", "sub1").Return(nil, nil)
+
+	err := cm.DeleteContractListenerByNameOrID(context.Background(), "sub1")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteContractListenerByID(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{
+		ID: fftypes.NewUUID(),
+	}
+
+	mdi.On("GetContractListenerByID", context.Background(), "ns1", listener.ID).Return(listener, nil)
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{Name: "sub1"}},
+		{SubscriptionRef: core.SubscriptionRef{Name: "sub2"}, Filter: core.SubscriptionFilter{
+			BlockchainEvent: core.BlockchainEventFilter{Listener: fftypes.NewUUID().String()},
+		}},
+		{SubscriptionRef: core.SubscriptionRef{Name: "sub3"}, Filter: core.SubscriptionFilter{
+			BlockchainEvent: core.BlockchainEventFilter{Listener: "["},
+		}},
+	}, nil, nil)
+	mbi.On("DeleteContractListener", context.Background(), listener, true).Return(nil)
+	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", listener.ID).Return(nil)
+
+	err := cm.DeleteContractListenerByID(context.Background(), listener.ID)
+	assert.NoError(t, err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestDeleteContractListenerByIDSubscribed(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{
+		ID: fftypes.NewUUID(),
+	}
+
+	mdi.On("GetContractListenerByID", context.Background(), "ns1", listener.ID).Return(listener, nil)
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{Name: "sub1"}, Filter: core.SubscriptionFilter{
+			BlockchainEvent: core.BlockchainEventFilter{Listener: listener.ID.String()},
+		}},
+	}, nil, nil)
+
+	err := cm.DeleteContractListenerByID(context.Background(), listener.ID)
+	assert.Regexp(t, "FF10500.*sub1", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeleteContractListenerByIDSubscriptionsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{
+		ID: fftypes.NewUUID(),
+	}
+
+	mdi.On("GetContractListenerByID", context.Background(), "ns1", listener.ID).Return(listener, nil)
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := cm.DeleteContractListenerByID(context.Background(), listener.ID)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteContractListenerByIDNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	id := fftypes.NewUUID()
+	mdi.On("GetContractListenerByID", context.Background(), "ns1", id).Return(nil, nil)
+
+	err := cm.DeleteContractListenerByID(context.Background(), id)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteContractListenerByIDFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	id := fftypes.NewUUID()
+	mdi.On("GetContractListenerByID", context.Background(), "ns1", id).Return(nil, fmt.Errorf("pop"))
+
+	err := cm.DeleteContractListenerByID(context.Background(), id)
+	assert.EqualError(t, err, "pop")
+}
+
+func newTestDeleteAPIListeners(cm *contractManager, listeners []*core.ContractListener) {
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+	api := newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvent", context.Background(), "ns1", api.Interface.ID, "changed").Return(&fftypes.FFIEvent{
+		FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"},
+	}, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(listeners, nil, nil)
+}
+
+func TestDeleteContractAPIListeners(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := &core.ContractListener{ID: fftypes.NewUUID(), Name: "l1", BackendID: "sb-1"}
+	l2 := &core.ContractListener{ID: fftypes.NewUUID(), Name: "l2", BackendID: "sb-2"}
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1, l2})
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mbi.On("DeleteContractListener", context.Background(), l1, true).Return(nil)
+	mbi.On("DeleteContractListener", context.Background(), l2, true).Return(nil)
+	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", l1.ID).Return(nil)
+	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", l2.ID).Return(nil)
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	deleted, err := cm.DeleteContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ContractListenerDeletion{
+		{ID: l1.ID, Name: "l1", BackendID: "sb-1", Deregistered: true},
+		{ID: l2.ID, Name: "l2", BackendID: "sb-2", Deregistered: true},
+	}, deleted)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestDeleteContractAPIListenersSubscribed(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := &core.ContractListener{ID: fftypes.NewUUID()}
+	l2 := &core.ContractListener{ID: fftypes.NewUUID()}
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1, l2})
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{Name: "sub1"}, Filter: core.SubscriptionFilter{
+			BlockchainEvent: core.BlockchainEventFilter{Listener: l2.ID.String()},
+		}},
+	}, nil, nil)
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, err := cm.DeleteContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.Regexp(t, "FF10500", err)
+
+	mdi.AssertNotCalled(t, "DeleteContractListenerByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteContractAPIListenersSubscriptionsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{{ID: fftypes.NewUUID()}})
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, err := cm.DeleteContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteContractAPIListenersBlockchainFail(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := &core.ContractListener{ID: fftypes.NewUUID()}
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+	mdi.On("GetSubscriptions", context.Background(), "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mbi.On("DeleteContractListener", context.Background(), l1, true).Return(fmt.Errorf("pop"))
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, err := cm.DeleteContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteContractAPIListenersNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, nil)
+
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, err := cm.DeleteContractAPIListeners(context.Background(), "simple", "changed", f.And())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func newTestUpdateAPIListener() *core.ContractListener {
+	return &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		Name:      "l1",
+		BackendID: "sb-1",
+		Topic:     "topic1",
+		Signature: "0x123:changed",
+		Location:  fftypes.JSONAnyPtr(`{"address":"0x123"}`),
+		Event:     &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+		Options:   &core.ContractListenerOptions{FirstEvent: "newest"},
+	}
+}
+
+func TestUpdateContractAPIListener(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+	mdi.On("GetContractListener", context.Background(), "ns1", "l2").Return(nil, nil)
+	mbi.On("UpdateContractListener", context.Background(), mock.MatchedBy(func(l *core.ContractListener) bool {
+		return l.Options.FirstEvent == "0"
+	})).Return(nil)
+	mdi.On("UpdateContractListener", context.Background(), "ns1", l1.ID, mock.Anything).Return(nil)
+
+	listener, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{
+		ID:        l1.ID,
+		Name:      "l2",
+		Options:   &core.ContractListenerOptions{FirstEvent: "0"},
+		Topic:     "topic1",
+		Signature: "0x123:changed",
+		Location:  fftypes.JSONAnyPtr(`{ "address": "0x123" }`),
+		Event:     &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "l2", listener.Name)
+	assert.Equal(t, "0", listener.Options.FirstEvent)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateContractAPIListenerOutputFiltersOnly(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	l1.Options = nil
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+	mdi.On("UpdateContractListener", context.Background(), "ns1", l1.ID, mock.Anything).Return(nil)
+	cm.listenerCache.Set("pid:sb-1", l1)
+
+	listener, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{
+		ID: l1.ID,
+		Options: &core.ContractListenerOptions{
+			OutputFilters: []*core.ListenerOutputFilter{{Field: "value", Op: core.ListenerOutputFilterOpEq, Value: "1"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, listener.Options.OutputFilters, 1)
+	assert.Nil(t, cm.listenerCache.Get("pid:sb-1"))
+
+	mbi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything)
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateContractAPIListenerNoChange(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+
+	listener, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{
+		ID:      l1.ID,
+		Name:    "l1",
+		Options: &core.ContractListenerOptions{FirstEvent: "newest"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, l1, listener)
+
+	mbi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything)
+	mdi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateContractAPIListenerNilID(t *testing.T) {
+	cm := newTestContractManager()
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{})
+	assert.Regexp(t, "FF00114", err)
+}
+
+func TestUpdateContractAPIListenerLookupFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{ID: fftypes.NewUUID()})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestUpdateContractAPIListenerNotFound(t *testing.T) {
+	cm := newTestContractManager()
+
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{})
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{ID: fftypes.NewUUID()})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestUpdateContractAPIListenerImmutableFields(t *testing.T) {
+	for field, update := range map[string]*core.ContractListenerUpdate{
+		"topic":     {Topic: "topic2"},
+		"signature": {Signature: "0x456:changed"},
+		"location":  {Location: fftypes.JSONAnyPtr(`{"address":"0x456"}`)},
+		"event":     {Event: &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "other"}}},
+	} {
+		cm := newTestContractManager()
+		mdi := cm.database.(*databasemocks.Plugin)
+
+		l1 := newTestUpdateAPIListener()
+		newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+
+		update.ID = l1.ID
+		_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", update)
+		assert.Regexp(t, "FF10505.*"+field, err)
+
+		mdi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestUpdateContractAPIListenerBadName(t *testing.T) {
+	cm := newTestContractManager()
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{ID: l1.ID, Name: "!bad"})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestUpdateContractAPIListenerNameExists(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+	mdi.On("GetContractListener", context.Background(), "ns1", "l2").Return(&core.ContractListener{}, nil)
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{ID: l1.ID, Name: "l2"})
+	assert.Regexp(t, "FF10312", err)
+}
+
+func TestUpdateContractAPIListenerNameLookupFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+	mdi.On("GetContractListener", context.Background(), "ns1", "l2").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{ID: l1.ID, Name: "l2"})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestUpdateContractAPIListenerBlockchainNotSupported(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+	mbi.On("UpdateContractListener", context.Background(), mock.Anything).
+		Return(i18n.NewError(context.Background(), coremsgs.MsgContractListenerUpdateNotSupported, l1.ID))
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{
+		ID:      l1.ID,
+		Options: &core.ContractListenerOptions{FirstEvent: "oldest"},
+	})
+	assert.Regexp(t, "FF10506", err)
+
+	mdi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateContractAPIListenerBadOutputFilters(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	l1 := newTestUpdateAPIListener()
+	newTestDeleteAPIListeners(cm, []*core.ContractListener{l1})
+
+	_, err := cm.UpdateContractAPIListener(context.Background(), "simple", "changed", &core.ContractListenerUpdate{
+		ID: l1.ID,
+		Options: &core.ContractListenerOptions{
+			FirstEvent:    "newest",
+			OutputFilters: []*core.ListenerOutputFilter{{Op: core.ListenerOutputFilterOpEq, Value: "1"}},
+		},
+	})
+	assert.Regexp(t, "FF10546", err)
+
+	mbi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything)
+	mdi.AssertNotCalled(t, "UpdateContractListener", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInvokeContractAPI(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			ID:   fftypes.NewUUID(),
+			Name: "peel",
+		},
+		IdempotencyKey: "idem1",
+	}
+
+	api := &core.ContractAPI{
+		Interface: &fftypes.FFIReference{
+			ID: fftypes.NewUUID(),
+		},
+		Location: fftypes.JSONAnyPtr(""),
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdb.On("GetContractAPIByName", mock.Anything, "ns1", "banana").Return(api, nil)
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
+	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
+	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(txcommon.BlockchainInvokeData)
+		return op.Type == core.OpTypeBlockchainInvoke && data.Request == req
+	}), true).Return(nil, nil)
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+
+	_, err := cm.InvokeContractAPI(context.Background(), "banana", "peel", req, false)
+
+	assert.NoError(t, err)
+
+	mdb.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestInvokeContractAPIFailContractLookup(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdb.On("GetContractAPIByName", mock.Anything, "ns1", "banana").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.InvokeContractAPI(context.Background(), "banana", "peel", req, false)
+
+	assert.Regexp(t, "pop", err)
+}
+
+func TestInvokeContractAPIContractNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdb := cm.database.(*databasemocks.Plugin)
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdb.On("GetContractAPIByName", mock.Anything, "ns1", "banana").Return(nil, nil)
+
+	_, err := cm.InvokeContractAPI(context.Background(), "banana", "peel", req, false)
+
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestSimulateContract(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+	}
+
+	sim := &core.Simulation{Success: false, RevertReason: "pop"}
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	opaqueData := "anything"
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+	mbi.On("SimulateContract", mock.Anything, "key-resolved", req.Location, opaqueData, req.Input, req.Options).Return(sim, nil)
+
+	res, err := cm.SimulateContract(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, sim, res)
+
+	mim.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestSimulateContractWithMessage(t *testing.T) {
+	cm := newTestContractManager()
+
+	req := &core.ContractCallRequest{
+		Type:    core.CallTypeInvoke,
+		Message: &core.MessageInOut{},
+	}
+
+	_, err := cm.SimulateContract(context.Background(), req)
+	assert.Regexp(t, "FF10643", err)
+}
+
+func TestSimulateContractBadKey(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+
+	req := &core.ContractCallRequest{
+		Type: core.CallTypeInvoke,
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
 
-	mdi.AssertExpectations(t)
+	_, err := cm.SimulateContract(context.Background(), req)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
 }
 
-func TestDeleteContractListener(t *testing.T) {
+func TestSimulateContractMethodNotSet(t *testing.T) {
 	cm := newTestContractManager()
-	mbi := cm.blockchain.(*blockchainmocks.Plugin)
-	mdi := cm.database.(*databasemocks.Plugin)
+	mim := cm.identity.(*identitymanagermocks.Manager)
 
-	sub := &core.ContractListener{
-		ID: fftypes.NewUUID(),
+	req := &core.ContractCallRequest{
+		Type:     core.CallTypeInvoke,
+		Location: fftypes.JSONAnyPtr(""),
 	}
 
-	mdi.On("GetContractListener", context.Background(), "ns1", "sub1").Return(sub, nil)
-	mbi.On("DeleteContractListener", context.Background(), sub, true).Return(nil)
-	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", sub.ID).Return(nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
 
-	err := cm.DeleteContractListenerByNameOrID(context.Background(), "sub1")
-	assert.NoError(t, err)
+	_, err := cm.SimulateContract(context.Background(), req)
+	assert.Regexp(t, "FF10313", err)
+
+	mim.AssertExpectations(t)
 }
 
-func TestDeleteContractListenerBlockchainFail(t *testing.T) {
+func TestSimulateContractValidateFail(t *testing.T) {
 	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
-	mdi := cm.database.(*databasemocks.Plugin)
 
-	sub := &core.ContractListener{
-		ID: fftypes.NewUUID(),
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
 	}
 
-	mdi.On("GetContractListener", context.Background(), "ns1", "sub1").Return(sub, nil)
-	mbi.On("DeleteContractListener", context.Background(), sub, true).Return(fmt.Errorf("pop"))
-	mdi.On("DeleteContractListenerByID", context.Background(), "ns1", sub.ID).Return(nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(nil, fmt.Errorf("pop"))
 
-	err := cm.DeleteContractListenerByNameOrID(context.Background(), "sub1")
+	_, err := cm.SimulateContract(context.Background(), req)
 	assert.EqualError(t, err, "pop")
-}
-
-func TestDeleteContractListenerNotFound(t *testing.T) {
-	cm := newTestContractManager()
-	mdi := cm.database.(*databasemocks.Plugin)
-
-	mdi.On("GetContractListener", context.Background(), "ns1", "sub1").Return(nil, nil)
 
-	err := cm.DeleteContractListenerByNameOrID(context.Background(), "sub1")
-	assert.Regexp(t, "FF10109", err)
+	mim.AssertExpectations(t)
+	mbi.AssertExpectations(t)
 }
 
-func TestInvokeContractAPI(t *testing.T) {
+func TestSimulateContractAPI(t *testing.T) {
 	cm := newTestContractManager()
 	mdb := cm.database.(*databasemocks.Plugin)
 	mim := cm.identity.(*identitymanagermocks.Manager)
-	mdi := cm.database.(*databasemocks.Plugin)
-	mth := cm.txHelper.(*txcommonmocks.Helper)
-	mom := cm.operations.(*operationmocks.Manager)
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
-	txw := cm.txWriter.(*txwritermocks.Writer)
 
 	req := &core.ContractCallRequest{
-		Type:      core.CallTypeInvoke,
-		Interface: fftypes.NewUUID(),
-		Location:  fftypes.JSONAnyPtr(""),
+		Type: core.CallTypeInvoke,
 		Method: &fftypes.FFIMethod{
 			ID:   fftypes.NewUUID(),
 			Name: "peel",
 		},
-		IdempotencyKey: "idem1",
 	}
 
 	api := &core.ContractAPI{
 		Interface: &fftypes.FFIReference{
 			ID: fftypes.NewUUID(),
 		},
-		Location: fftypes.JSONAnyPtr(""),
+		Location: fftypes.JSONAnyPtr(`{"address":"0x12345"}`),
 	}
 
+	sim := &core.Simulation{Success: true}
 	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
 	mdb.On("GetContractAPIByName", mock.Anything, "ns1", "banana").Return(api, nil)
-	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey("idem1"), mock.MatchedBy(func(op *core.Operation) bool {
-		return op.Namespace == "ns1" && op.Type == core.OpTypeBlockchainInvoke && op.Plugin == "mockblockchain"
-	})).Return(&core.Transaction{ID: fftypes.NewUUID()}, nil)
-	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(txcommon.BlockchainInvokeData)
-		return op.Type == core.OpTypeBlockchainInvoke && data.Request == req
-	}), true).Return(nil, nil)
 	opaqueData := "anything"
 	mbi.On("ParseInterface", context.Background(), req.Method, req.Errors).Return(opaqueData, nil)
 	mbi.On("ValidateInvokeRequest", mock.Anything, opaqueData, req.Input, false).Return(nil)
+	mbi.On("SimulateContract", mock.Anything, "key-resolved", api.Location, opaqueData, req.Input, req.Options).Return(sim, nil)
 
-	_, err := cm.InvokeContractAPI(context.Background(), "banana", "peel", req, false)
+	res, err := cm.SimulateContractAPI(context.Background(), "banana", "peel", req)
 
 	assert.NoError(t, err)
+	assert.Equal(t, sim, res)
+	assert.Equal(t, api.Interface.ID, req.Interface)
+	assert.Equal(t, "peel", req.MethodPath)
 
 	mdb.AssertExpectations(t)
 	mim.AssertExpectations(t)
-	mdi.AssertExpectations(t)
-	mth.AssertExpectations(t)
-	mom.AssertExpectations(t)
 	mbi.AssertExpectations(t)
 }
 
-func TestInvokeContractAPIFailContractLookup(t *testing.T) {
-	cm := newTestContractManager()
-	mdb := cm.database.(*databasemocks.Plugin)
-	mim := cm.identity.(*identitymanagermocks.Manager)
-	req := &core.ContractCallRequest{
-		Type:      core.CallTypeInvoke,
-		Interface: fftypes.NewUUID(),
-		Location:  fftypes.JSONAnyPtr(""),
-		Method: &fftypes.FFIMethod{
-			ID: fftypes.NewUUID(),
-		},
-	}
-
-	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
-	mdb.On("GetContractAPIByName", mock.Anything, "ns1", "banana").Return(nil, fmt.Errorf("pop"))
-
-	_, err := cm.InvokeContractAPI(context.Background(), "banana", "peel", req, false)
-
-	assert.Regexp(t, "pop", err)
-}
-
-func TestInvokeContractAPIContractNotFound(t *testing.T) {
+func TestSimulateContractAPIContractNotFound(t *testing.T) {
 	cm := newTestContractManager()
 	mdb := cm.database.(*databasemocks.Plugin)
-	mim := cm.identity.(*identitymanagermocks.Manager)
-	req := &core.ContractCallRequest{
-		Type:      core.CallTypeInvoke,
-		Interface: fftypes.NewUUID(),
-		Location:  fftypes.JSONAnyPtr(""),
-		Method: &fftypes.FFIMethod{
-			ID: fftypes.NewUUID(),
-		},
-	}
 
-	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
 	mdb.On("GetContractAPIByName", mock.Anything, "ns1", "banana").Return(nil, nil)
 
-	_, err := cm.InvokeContractAPI(context.Background(), "banana", "peel", req, false)
-
+	_, err := cm.SimulateContractAPI(context.Background(), "banana", "peel", &core.ContractCallRequest{})
 	assert.Regexp(t, "FF10109", err)
+
+	mdb.AssertExpectations(t)
 }
 
 func TestGetContractAPI(t *testing.T) {
@@ -3971,6 +5667,34 @@ func TestGenerateFFI(t *testing.T) {
 	assert.Equal(t, "method1_1", ffi.Methods[1].Pathname)
 }
 
+func TestGenerateFFIFromSource(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	source := fftypes.JSONAnyPtr(`{"source": "contract Simple {}"}`)
+	abiInput := fftypes.JSONAnyPtr(`{"abi": []}`)
+	mbi.On("CompileContractInterface", mock.Anything, source).Return(abiInput, nil)
+	mbi.On("GenerateFFI", mock.Anything, mock.MatchedBy(func(gf *fftypes.FFIGenerationRequest) bool {
+		return gf.Input == abiInput
+	})).Return(&fftypes.FFI{Name: "generated", Version: "0.0.1"}, nil)
+
+	ffi, err := cm.GenerateFFIFromSource(context.Background(), &fftypes.FFIGenerationRequest{Input: source})
+	assert.NoError(t, err)
+	assert.Equal(t, "generated", ffi.Name)
+
+	mbi.AssertExpectations(t)
+}
+
+func TestGenerateFFIFromSourceFail(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("CompileContractInterface", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.GenerateFFIFromSource(context.Background(), &fftypes.FFIGenerationRequest{})
+	assert.EqualError(t, err, "pop")
+
+	mbi.AssertExpectations(t)
+}
+
 type MockFFIParamValidator struct{}
 
 func (v MockFFIParamValidator) Compile(ctx jsonschema.CompilerContext, m map[string]interface{}) (jsonschema.ExtSchema, error) {
@@ -4124,6 +5848,7 @@ func TestResolveInvokeContractRequestCache(t *testing.T) {
 	mdb := cm.database.(*databasemocks.Plugin)
 	mdb.On("GetFFIMethod", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(method, nil).Once()
 	mdb.On("GetFFIErrors", mock.Anything, "ns1", mock.Anything).Return(errors, nil, nil).Once()
+	mdb.On("GetFFILifecycle", mock.Anything, "ns1", mock.Anything).Return(core.DefinitionLifecycleActive, nil).Once()
 
 	interfaceID := fftypes.NewUUID()
 	err := cm.resolveInvokeContractRequest(context.Background(), &core.ContractCallRequest{
@@ -5120,3 +6845,207 @@ func TestParseContractListenerFiltersFailEventSignatureDuplicateCheck(t *testing
 	mbi.AssertExpectations(t)
 	mdi.AssertExpectations(t)
 }
+
+func TestGetAllContractAPIListeners(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	api := newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "changed"}},
+		{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "removed"}},
+	}, nil, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+	listeners := []*core.ContractListener{{ID: fftypes.NewUUID()}}
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == fmt.Sprintf("( name == 'l1' ) && ( ( interface == '%s' ) && ( ( ( signature %%= '0x123:changed' ) || ( signature == 'changed' ) ) || ( ( signature %%= '0x123:changed' ) || ( signature == 'changed' ) ) ) ) limit=10", api.Interface.ID)
+	})).Return(listeners, nil, nil)
+
+	fb := database.ContractListenerQueryFactory.NewFilterLimit(context.Background(), 10)
+	res, _, err := cm.GetAllContractAPIListeners(context.Background(), "simple", fb.And(fb.Eq("name", "l1")))
+	assert.NoError(t, err)
+	assert.Equal(t, listeners, res)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestGetAllContractAPIListenersNoEvents(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return([]*fftypes.FFIEvent{}, nil, nil)
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	res, _, err := cm.GetAllContractAPIListeners(context.Background(), "simple", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetAllContractAPIListenersNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, nil)
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetAllContractAPIListeners(context.Background(), "simple", fb.And())
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetAllContractAPIListenersAPIFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, fmt.Errorf("pop"))
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetAllContractAPIListeners(context.Background(), "simple", fb.And())
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetAllContractAPIListenersEventsFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	newTestListenerHealthAPI(mdi)
+	mdi.On("GetFFIEvents", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	fb := database.ContractListenerQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetAllContractAPIListeners(context.Background(), "simple", fb.And())
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func mockContractAPIListenerLookup(cm *contractManager) {
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+	interfaceID := fftypes.NewUUID()
+	api := &core.ContractAPI{
+		Interface: &fftypes.FFIReference{
+			ID: interfaceID,
+		},
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+	}
+	event := &fftypes.FFIEvent{
+		FFIEventDefinition: fftypes.FFIEventDefinition{
+			Name: "changed",
+		},
+	}
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(api, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", interfaceID).Return(&fftypes.FFI{}, nil)
+	mdi.On("GetFFIEvent", context.Background(), "ns1", interfaceID, "changed").Return(event, nil)
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed", nil)
+	mbi.On("GenerateEventSignatureWithLocation", context.Background(), mock.Anything, mock.Anything).Return("0x123:changed", nil)
+}
+
+func TestGetContractAPIListenerEvents(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mockContractAPIListenerLookup(cm)
+
+	listener1 := &core.ContractListener{ID: fftypes.NewUUID()}
+	listener2 := &core.ContractListener{ID: fftypes.NewUUID()}
+	mdi.On("IterateContractListeners", context.Background(), "ns1", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cb := args[3].(func(listener *core.ContractListener) error)
+			cb(listener1)
+			cb(listener2)
+		}).
+		Return(nil)
+	events := []*core.BlockchainEvent{{ID: fftypes.NewUUID()}}
+	mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( blocknumber >= 100 ) && ( listener IN ['%s','%s'] ) sort=blocknumber,protocolid", listener1.ID, listener2.ID), fi.String())
+		return true
+	})).Return(events, nil, nil)
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(context.Background())
+	result, _, err := cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", fb.And(fb.Gte("blocknumber", 100)))
+	assert.NoError(t, err)
+	assert.Equal(t, events, result)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenerEventsNoListeners(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mockContractAPIListenerLookup(cm)
+
+	mdi.On("IterateContractListeners", context.Background(), "ns1", mock.Anything, mock.Anything).Return(nil)
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(context.Background())
+	result, _, err := cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetContractAPIListenerEventsSortNotAllowed(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mockContractAPIListenerLookup(cm)
+
+	mdi.On("IterateContractListeners", context.Background(), "ns1", mock.Anything, mock.Anything).Return(nil)
+
+	f := database.BlockchainEventQueryFactory.NewFilter(context.Background()).And()
+	f.Sort("timestamp")
+	_, _, err := cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", f)
+	assert.Regexp(t, "FF10520", err)
+
+	f = database.BlockchainEventQueryFactory.NewFilter(context.Background()).And()
+	f.Descending()
+	_, _, err = cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", f)
+	assert.Regexp(t, "FF10520", err)
+}
+
+func TestGetContractAPIListenerEventsBadFilter(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mockContractAPIListenerLookup(cm)
+
+	mdi.On("IterateContractListeners", context.Background(), "ns1", mock.Anything, mock.Anything).Return(nil)
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", fb.And(fb.Eq("blocknumber", map[bool]bool{true: false})))
+	assert.Regexp(t, "FF00", err)
+}
+
+func TestGetContractAPIListenerEventsIterateFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mockContractAPIListenerLookup(cm)
+
+	mdi.On("IterateContractListeners", context.Background(), "ns1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", fb.And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetContractAPIListenerEventsNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple").Return(nil, nil)
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(context.Background())
+	_, _, err := cm.GetContractAPIListenerEvents(context.Background(), "simple", "changed", fb.And())
+	assert.Regexp(t, "FF10109", err)
+}
@@ -174,6 +174,9 @@ func (cm *contractManager) OnOperationUpdate(ctx context.Context, op *core.Opera
 		}
 	case core.OpTypeBlockchainContractDeploy:
 		if update.Status == core.OpStatusSucceeded {
+			if err := cm.createDeployedContractAPI(ctx, op, update); err != nil {
+				return err
+			}
 			event := core.NewEvent(core.EventTypeBlockchainContractDeployOpSucceeded, op.Namespace, op.ID, op.Transaction, "")
 			if err := cm.database.InsertEvent(ctx, event); err != nil {
 				return err
@@ -189,6 +192,44 @@ func (cm *contractManager) OnOperationUpdate(ctx context.Context, op *core.Opera
 	return nil
 }
 
+// createDeployedContractAPI creates the contract API requested for a deployment, once the connector has reported
+// the location of the deployed contract. The API takes the ID of the operation, so repeated updates are ignored.
+// As the deployment itself has succeeded, only a failure to write to the database is returned.
+func (cm *contractManager) createDeployedContractAPI(ctx context.Context, op *core.Operation, update *core.OperationUpdate) error {
+	req, err := retrieveBlockchainDeployInputs(ctx, op)
+	if err != nil || req.API == "" {
+		return nil
+	}
+	location, ok := update.Output.GetObjectOk("contractLocation")
+	if !ok {
+		log.L(ctx).Warnf("Unable to create contract API '%s' for deploy operation %s, as no contract location was returned", req.API, op.ID)
+		return nil
+	}
+	api := &core.ContractAPI{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Name:      req.API,
+		Interface: req.Interface,
+		Location:  fftypes.JSONAnyPtr(location.String()),
+	}
+	if err := cm.ResolveContractAPI(ctx, "", api); err != nil {
+		log.L(ctx).Errorf("Unable to create contract API '%s' for deploy operation %s: %s", req.API, op.ID, err)
+		return nil
+	}
+	existing, err := cm.database.InsertOrGetContractAPI(ctx, api)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if !existing.ID.Equals(api.ID) {
+			log.L(ctx).Errorf("Unable to create contract API '%s' for deploy operation %s, as it conflicts with contract API %s", req.API, op.ID, existing.ID)
+		}
+		return nil
+	}
+	log.L(ctx).Infof("Created contract API '%s' for contract deployed by operation %s", api.Name, op.ID)
+	return cm.database.InsertEvent(ctx, core.NewEvent(core.EventTypeContractAPIConfirmed, api.Namespace, api.ID, op.Transaction, core.SystemTopicDefinitions))
+}
+
 func opBlockchainContractDeploy(op *core.Operation, req *core.ContractDeployRequest) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
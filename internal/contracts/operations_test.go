@@ -821,6 +821,131 @@ func TestOperationUpdateDeploySucceed(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func newTestDeployOperation() (*core.Operation, *core.OperationUpdate) {
+	op := &core.Operation{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Type:        core.OpTypeBlockchainContractDeploy,
+		Transaction: fftypes.NewUUID(),
+		Input: fftypes.JSONObject{
+			"interface": map[string]interface{}{"id": fftypes.NewUUID().String()},
+			"api":       "simple-api",
+		},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123456"},
+		},
+	}
+	return op, update
+}
+
+func TestOperationUpdateDeploySucceedCreateAPI(t *testing.T) {
+	cm := newTestContractManager()
+	op, update := newTestDeployOperation()
+
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, fftypes.JSONAnyPtr(`{"address":"0x123456"}`)).Return(fftypes.JSONAnyPtr(`{"address":"0x123456"}`), nil)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple-api").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", mock.Anything).Return(&fftypes.FFI{}, nil)
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.MatchedBy(func(api *core.ContractAPI) bool {
+		return api.ID.Equals(op.ID) && api.Name == "simple-api" && api.Interface.ID != nil
+	})).Return(nil, nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeContractAPIConfirmed && event.Reference.Equals(op.ID) && event.Topic == core.SystemTopicDefinitions
+	})).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedAPIExists(t *testing.T) {
+	cm := newTestContractManager()
+	op, update := newTestDeployOperation()
+
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, mock.Anything).Return(fftypes.JSONAnyPtr(`{"address":"0x123456"}`), nil)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple-api").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", mock.Anything).Return(&fftypes.FFI{}, nil)
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.Anything).Return(&core.ContractAPI{ID: op.ID}, nil).Once()
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.Anything).Return(&core.ContractAPI{ID: fftypes.NewUUID()}, nil).Once()
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil).Twice()
+
+	// Created by a previous update
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	// Conflicts with another contract API
+	err = cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedInsertAPIFail(t *testing.T) {
+	cm := newTestContractManager()
+	op, update := newTestDeployOperation()
+
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, mock.Anything).Return(fftypes.JSONAnyPtr(`{"address":"0x123456"}`), nil)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "simple-api").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", mock.Anything).Return(&fftypes.FFI{}, nil)
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.EqualError(t, err, "pop")
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedResolveAPIFail(t *testing.T) {
+	cm := newTestContractManager()
+	op, update := newTestDeployOperation()
+
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedNoLocation(t *testing.T) {
+	cm := newTestContractManager()
+	op, update := newTestDeployOperation()
+	update.Output = fftypes.JSONObject{}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestOperationUpdateDeployFail(t *testing.T) {
 	cm := newTestContractManager()
 
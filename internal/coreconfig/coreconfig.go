@@ -43,10 +43,26 @@ const (
 	NamespaceTLSConfigs = "tlsConfigs"
 	// NamespaceTLSConfigTLSSection is the section to provide the paths to CA , cert and key files
 	NamespaceTLSConfigTLSSection = "tls"
+	// NamespaceSigningSecrets is the list of named secrets used to sign webhook requests
+	NamespaceSigningSecrets = "signingSecrets"
+	// NamespaceSigningSecretName is the name subscriptions use to refer to the signing secret
+	NamespaceSigningSecretName = "name"
+	// NamespaceSigningSecretValue is the HMAC secret
+	NamespaceSigningSecretValue = "secret"
 	// NamespaceDefaultKey is the default signing key for blockchain transactions within this namespace
 	NamespaceDefaultKey = "defaultKey"
 	// NamespaceAssetKeyNormalization mechanism to normalize keys before using them. Valid options: "blockchain_plugin" - use blockchain plugin (default), "none" - do not attempt normalization
 	NamespaceAssetKeyNormalization = "asset.manager.keyNormalization"
+	// NamespaceAssetPoolConnectors is a list of bindings that route operations for each token pool to a specific token connector
+	NamespaceAssetPoolConnectors = "asset.manager.poolConnectors"
+	// NamespaceAssetPoolConnectorPool is the name of the token pool in a pool connector binding
+	NamespaceAssetPoolConnectorPool = "pool"
+	// NamespaceAssetPoolConnectorConnector is the name of the token plugin in a pool connector binding
+	NamespaceAssetPoolConnectorConnector = "connector"
+	// NamespaceRetentionWindow is the age after which records in this namespace are pruned, overriding retention.window
+	NamespaceRetentionWindow = "retention.window"
+	// NamespaceRetentionArchive if true, pruned records are uploaded to shared storage before they are deleted
+	NamespaceRetentionArchive = "retention.archive"
 	// NamespaceMultiparty contains the multiparty configuration for a namespace
 	NamespaceMultiparty = "multiparty"
 	// NamespaceMultipartyEnabled specifies if multi-party mode is enabled for a namespace
@@ -71,6 +87,48 @@ const (
 	NamespaceMultipartyContractLocation = "location"
 	// NamespaceMultipartyContractOptions is an object of additional blockchain-specific configuration
 	NamespaceMultipartyContractOptions = "options"
+	// OperationsOutputValidationType is the operation type that an output schema applies to
+	OperationsOutputValidationType = "type"
+	// OperationsOutputValidationSchema is the JSON schema that the output of the operation type must conform to
+	OperationsOutputValidationSchema = "schema"
+	// APIRateLimitQuotaName is the namespace a quota in api.rateLimit.namespaces applies to, or the route a quota in api.rateLimit.routes applies to
+	APIRateLimitQuotaName = "name"
+	// APIRateLimitQuotaNamespace restricts a route quota to a single namespace
+	APIRateLimitQuotaNamespace = "namespace"
+	// APIRateLimitQuotaRequestsPerSecond is the rate of requests allowed by a quota, or 0 for unlimited
+	APIRateLimitQuotaRequestsPerSecond = "requestsPerSecond"
+	// APIRateLimitQuotaBurst is the number of requests allowed in a burst by a quota, above the rate limit
+	APIRateLimitQuotaBurst = "burst"
+	// BroadcastEncryptionKeyID is the ID of a key used to encrypt broadcast data, which is published alongside the encrypted data
+	BroadcastEncryptionKeyID = "id"
+	// BroadcastEncryptionKeyValue is the hex encoded 32 byte AES-256 key
+	BroadcastEncryptionKeyValue = "key"
+	// BroadcastEncryptionKeyTopics is the list of message topics whose broadcast data is encrypted with the key
+	BroadcastEncryptionKeyTopics = "topics"
+	// OperationsRetryPolicyType is the operation type that an automatic retry policy applies to
+	OperationsRetryPolicyType = "type"
+	// OperationsRetryPolicyMaxRetries is the maximum number of automatic retries of a failed operation
+	OperationsRetryPolicyMaxRetries = "maxRetries"
+	// OperationsRetryPolicyInitialDelay is the delay before the first automatic retry
+	OperationsRetryPolicyInitialDelay = "initialDelay"
+	// OperationsRetryPolicyMaxDelay is the maximum delay before an automatic retry
+	OperationsRetryPolicyMaxDelay = "maxDelay"
+	// OperationsRetryPolicyFactor is the factor the delay is multiplied by for each automatic retry
+	OperationsRetryPolicyFactor = "factor"
+	// OperationsRetryPolicyRetryableErrors is a list of regular expressions, one of which an error must match to be retried
+	OperationsRetryPolicyRetryableErrors = "retryableErrors"
+	// OperationsRetryPolicyTerminalErrors is a list of regular expressions for errors that are never retried
+	OperationsRetryPolicyTerminalErrors = "terminalErrors"
+	// TokenMetadataEnabled enables the resolution of token metadata from the URIs of tokens
+	TokenMetadataEnabled = "enabled"
+	// TokenMetadataMaxSize is the maximum size of a metadata document that is read from a token URI
+	TokenMetadataMaxSize = "maxSize"
+	// TokenMetadataAllowedHosts lists the hosts that token metadata can be fetched from over http(s)
+	TokenMetadataAllowedHosts = "allowedHosts"
+	// DeprecatedPolicyWarn logs a warning on new usage of a deprecated datatype or contract interface
+	DeprecatedPolicyWarn = "warn"
+	// DeprecatedPolicyReject rejects new usage of a deprecated datatype or contract interface
+	DeprecatedPolicyReject = "reject"
 )
 
 // The following keys can be access from the root configuration.
@@ -90,14 +148,40 @@ var (
 	APIDynamicPublicURLHeader = ffc("api.dynamicPublicURLHeader")
 	// APIOASPanicOnMissingDescription controls whether the OpenAPI Spec generator will strongly enforce descriptions on every field or not
 	APIOASPanicOnMissingDescription = ffc("api.oas.panicOnMissingDescription")
+	// APIRateLimitContractListenersRequestsPerSecond is the rate each caller can query contract listeners at, or 0 for unlimited
+	APIRateLimitContractListenersRequestsPerSecond = ffc("api.rateLimit.contractListeners.requestsPerSecond")
+	// APIRateLimitContractListenersBurst is the number of contract listener queries a caller can make in a burst, above the rate limit
+	APIRateLimitContractListenersBurst = ffc("api.rateLimit.contractListeners.burst")
+	// APIRateLimitNamespaceRequestsPerSecond is the rate of requests each namespace can receive, shared by all callers, or 0 for unlimited
+	APIRateLimitNamespaceRequestsPerSecond = ffc("api.rateLimit.namespace.requestsPerSecond")
+	// APIRateLimitNamespaceBurst is the number of requests a namespace can receive in a burst, above the rate limit
+	APIRateLimitNamespaceBurst = ffc("api.rateLimit.namespace.burst")
 	// APIPassThroughHeaders is a list of HTTP request headers to pass through to requests made to dependency microservices
 	APIPassthroughHeaders = ffc("api.passthroughHeaders")
+	// BatchAdaptiveEnabled enables adaptive sizing of batches, based on the recent fill rate and flush latency
+	BatchAdaptiveEnabled = ffc("batch.adaptive.enabled")
+	// BatchAdaptiveMinSize is the smallest batch size that adaptive sizing will shrink to
+	BatchAdaptiveMinSize = ffc("batch.adaptive.minSize")
+	// BatchAdaptiveMaxSize is the largest batch size that adaptive sizing will grow to
+	BatchAdaptiveMaxSize = ffc("batch.adaptive.maxSize")
+	// BatchAdaptiveTargetLatency is the flush latency above which adaptive sizing shrinks batches
+	BatchAdaptiveTargetLatency = ffc("batch.adaptive.targetLatency")
+	// BatchAdaptiveWindow is the number of flushes over which the fill rate and latency are averaged, before the size is adjusted
+	BatchAdaptiveWindow = ffc("batch.adaptive.window")
 	// BatchManagerReadPageSize is the size of each page of messages read from the database into memory when assembling batches
 	BatchManagerReadPageSize = ffc("batch.manager.readPageSize")
 	// BatchManagerReadPollTimeout is how long without any notifications of new messages to wait, before doing a page query
 	BatchManagerReadPollTimeout = ffc("batch.manager.pollTimeout")
 	// BatchManagerMinimumPollDelay is the minimum time the batch manager waits between polls on the DB - to prevent thrashing
 	BatchManagerMinimumPollDelay = ffc("batch.manager.minimumPollDelay")
+	// BatchManagerStatusInterval is the default interval at which batch manager status is pushed to websocket listeners
+	BatchManagerStatusInterval = ffc("batch.manager.statusInterval")
+	// BatchManagerStatusMinInterval is the shortest push interval a websocket listener can request for batch manager status
+	BatchManagerStatusMinInterval = ffc("batch.manager.statusMinInterval")
+	// BatchManagerFlushStatsWindow is the rolling window over which recent flush counts and latency are reported in the batch manager status
+	BatchManagerFlushStatsWindow = ffc("batch.manager.flushStatsWindow")
+	// BatchManagerSchedulePollInterval is how often the batch manager checks for scheduled messages that are due to be sent
+	BatchManagerSchedulePollInterval = ffc("batch.manager.schedulePollInterval")
 	// BatchRetryFactor is the retry backoff factor for database operations performed by the batch manager
 	BatchRetryFactor = ffc("batch.retry.factor")
 	// BatchRetryInitDelay is the retry initial delay for database operations
@@ -116,6 +200,18 @@ var (
 	BlobReceiverWorkerBatchTimeout = ffc("blobreceiver.worker.batchTimeout")
 	// BlobReceiverWorkerBatchMaxInserts
 	BlobReceiverWorkerBatchMaxInserts = ffc("blobreceiver.worker.batchMaxInserts")
+	// BlobUploadStagingDirectory is where the chunks of resumable blob uploads are staged until the upload completes
+	BlobUploadStagingDirectory = ffc("blobupload.stagingDirectory")
+	// BlobUploadStagingTTL is how long an upload that is no longer being written to is kept in the staging directory
+	BlobUploadStagingTTL = ffc("blobupload.stagingTTL")
+	// BlobUploadStagingSweepInterval is how often the staging directory is swept for uploads older than the TTL
+	BlobUploadStagingSweepInterval = ffc("blobupload.stagingSweepInterval")
+	// BlobUploadStagingMaxSize is the maximum total size of the blobs staged for a namespace
+	BlobUploadStagingMaxSize = ffc("blobupload.stagingMaxSize")
+	// BlobUploadAsyncWorkerCount is the number of workers hashing and transferring async uploads to data exchange
+	BlobUploadAsyncWorkerCount = ffc("blobupload.async.worker.count")
+	// BlobUploadAsyncWorkerQueueLength is the length of the work queue in the channel to the workers - defaults to 2x the worker count
+	BlobUploadAsyncWorkerQueueLength = ffc("blobupload.async.worker.queueLength")
 
 	// BroadcastBatchAgentTimeout how long to keep around a batching agent for a sending identity before disposal
 	BroadcastBatchAgentTimeout = ffc("broadcast.batch.agentTimeout")
@@ -125,6 +221,8 @@ var (
 	BroadcastBatchPayloadLimit = ffc("broadcast.batch.payloadLimit")
 	// BroadcastBatchTimeout is the timeout to wait for a batch to fill, before sending
 	BroadcastBatchTimeout = ffc("broadcast.batch.timeout")
+	// BroadcastDeduplicate if true, values and blobs identical to ones already published to shared storage reuse the existing payload reference instead of uploading again
+	BroadcastDeduplicate = ffc("broadcast.deduplicate")
 
 	// ConfigAutoReload starts a filesystem listener against the config file, and if it changes analyzes the config file for changes that require individual namespaces to restart
 	ConfigAutoReload = ffc("config.autoReload")
@@ -147,6 +245,10 @@ var (
 	CacheTransactionSize = ffc("cache.transaction.size")
 	CacheTransactionTTL  = ffc("cache.transaction.ttl")
 
+	// EventEnrichment cache config
+	CacheEventEnrichmentLimit = ffc("cache.eventenrichment.limit")
+	CacheEventEnrichmentTTL   = ffc("cache.eventenrichment.ttl")
+
 	// EventListenerTopic cache config
 	CacheEventListenerTopicLimit = ffc("cache.eventlistenertopic.limit")
 	CacheEventListenerTopicTTL   = ffc("cache.eventlistenertopic.ttl")
@@ -167,6 +269,10 @@ var (
 	CacheTokenPoolTTL   = ffc("cache.tokenpool.ttl")
 	CacheTokenPoolLimit = ffc("cache.tokenpool.limit")
 
+	// Token metadata cache config
+	CacheTokenMetadataTTL   = ffc("cache.tokenmetadata.ttl")
+	CacheTokenMetadataLimit = ffc("cache.tokenmetadata.limit")
+
 	// DataManager Validator cache config
 	CacheValidatorSize = ffc("cache.validator.size")
 	CacheValidatorTTL  = ffc("cache.validator.ttl")
@@ -195,6 +301,12 @@ var (
 	DownloadRetryMaxDelay = ffc("download.retry.maxDelay")
 	// DownloadRetryFactor is the backoff factor to use for retries
 	DownloadRetryFactor = ffc("download.retry.factor")
+	// DownloadVerifyPayloadHash enables verification of batches retrieved from shared storage, against the hash pinned on-chain
+	DownloadVerifyPayloadHash = ffc("download.verifyPayloadHash")
+	// PrivateMessagingAcksEnabled whether to send acknowledgements back to the sender of received private messages
+	PrivateMessagingAcksEnabled = ffc("privatemessaging.acks.enabled")
+	// PrivateMessagingAcksQueueLength the number of sets of acknowledgements that can be queued for sending in the background
+	PrivateMessagingAcksQueueLength = ffc("privatemessaging.acks.queueLength")
 	// PrivateMessagingBatchAgentTimeout how long to keep around a batching agent for a sending identity before disposal
 	PrivateMessagingBatchAgentTimeout = ffc("privatemessaging.batch.agentTimeout")
 	// PrivateMessagingBatchSize is the maximum size of a batch for broadcast messages
@@ -209,8 +321,28 @@ var (
 	PrivateMessagingRetryInitDelay = ffc("privatemessaging.retry.initDelay")
 	// PrivateMessagingRetryMaxDelay the maximum delay to use for retry of data base operations
 	PrivateMessagingRetryMaxDelay = ffc("privatemessaging.retry.maxDelay")
+	// HAEnabled enables active/passive high availability, where only the node holding the leader lock processes events
+	HAEnabled = ffc("ha.enabled")
+	// HALockProvider is the type of the lock provider that holds the leader lock
+	HALockProvider = ffc("ha.lockProvider")
+	// HADatabase is the name of the database plugin that holds the leader lock, for the database lock provider
+	HADatabase = ffc("ha.database")
+	// HALockName is the name of the leader lock, which is shared by all the nodes in one deployment
+	HALockName = ffc("ha.lockName")
+	// HANodeID identifies this node as the holder of the leader lock
+	HANodeID = ffc("ha.nodeId")
+	// HALeaseDuration is how long the leader lock is held for, unless it is renewed
+	HALeaseDuration = ffc("ha.leaseDuration")
+	// HARenewInterval is how often the active node renews the leader lock, and a passive node tries to acquire it
+	HARenewInterval = ffc("ha.renewInterval")
 	// DatabaseType the type of the database interface plugin to use
 	HistogramsMaxChartRows = ffc("histograms.maxChartRows")
+	// HistogramsRollupEnabled enables the background job that pre-aggregates hourly counts of each chart collection
+	HistogramsRollupEnabled = ffc("histograms.rollup.enabled")
+	// HistogramsRollupInterval is how often the rollup job of each namespace checks for new records to count
+	HistogramsRollupInterval = ffc("histograms.rollup.interval")
+	// HistogramsRollupBatchSize is the number of records counted in each database transaction of the rollup job
+	HistogramsRollupBatchSize = ffc("histograms.rollup.batchSize")
 	// TokensList is the root key containing a list of supported token connectors
 	TokensList = ffc("tokens")
 	// PluginsTokensList is the key containing a list of supported tokens plugins
@@ -231,6 +363,8 @@ var (
 	DebugPort = ffc("debug.port")
 	// DebugAddress the HTTP interface for the debugger to listen on
 	DebugAddress = ffc("debug.address")
+	// DefinitionsDeprecatedPolicy whether new usage of a deprecated datatype or contract interface is warned (default), or rejected
+	DefinitionsDeprecatedPolicy = ffc("definitions.deprecatedPolicy")
 	// EventTransportsDefault the default event transport for new subscriptions
 	EventTransportsDefault = ffc("event.transports.default")
 	// EventTransportsEnabled which event interface plugins are enabled
@@ -255,6 +389,8 @@ var (
 	EventAggregatorRetryInitDelay = ffc("event.aggregator.retry.initDelay")
 	// EventAggregatorRetryMaxDelay the maximum delay to use for retry of data base operations
 	EventAggregatorRetryMaxDelay = ffc("event.aggregator.retry.maxDelay")
+	// EventAggregatorWorkers the number of workers that aggregate independent topic contexts in parallel
+	EventAggregatorWorkers = ffc("event.aggregator.workers")
 	// EventDispatcherPollTimeout the time to wait without a notification of new events, before trying a select on the table
 	EventDispatcherPollTimeout = ffc("event.dispatcher.pollTimeout")
 	// EventDispatcherBufferLength the number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription
@@ -267,8 +403,18 @@ var (
 	EventDispatcherRetryInitDelay = ffc("event.dispatcher.retry.initDelay")
 	// EventDispatcherRetryMaxDelay he maximum delay to use for retry of data base operations
 	EventDispatcherRetryMaxDelay = ffc("event.dispatcher.retry.maxDelay")
+	// EventDispatcherOffsetCommitRetryMaxAttempts the maximum number of attempts to commit a subscription offset, before giving up until the next commit
+	EventDispatcherOffsetCommitRetryMaxAttempts = ffc("event.dispatcher.offsetCommitRetry.maxAttempts")
+	// EventDispatcherOffsetCommitRetryFactor the backoff factor to use for retry of subscription offset commits
+	EventDispatcherOffsetCommitRetryFactor = ffc("event.dispatcher.offsetCommitRetry.factor")
+	// EventDispatcherOffsetCommitRetryInitDelay the initial delay to use for retry of subscription offset commits
+	EventDispatcherOffsetCommitRetryInitDelay = ffc("event.dispatcher.offsetCommitRetry.initDelay")
+	// EventDispatcherOffsetCommitRetryMaxDelay the maximum delay to use for retry of subscription offset commits
+	EventDispatcherOffsetCommitRetryMaxDelay = ffc("event.dispatcher.offsetCommitRetry.maxDelay")
 	// EventDBEventsBufferSize the size of the buffer of change events
 	EventDBEventsBufferSize = ffc("event.dbevents.bufferSize")
+	// EventMaxIndexedDataSize the maximum size of the output of a blockchain event stored on the event itself - zero for no limit
+	EventMaxIndexedDataSize = ffc("event.maxIndexedDataSize")
 	// LegacyAdminEnabled is the deprecated key that pre-dates spi.enabled
 	LegacyAdminEnabled = ffc("admin.enabled")
 	// SPIEnabled determines whether the admin interface will be enabled or not
@@ -305,6 +451,28 @@ var (
 	NodeName = ffc("node.name")
 	// NodeDescription is a description for the node
 	NodeDescription = ffc("node.description")
+	// OperationsCircuitBreakerFailureThreshold is the number of consecutive submission failures to a plugin that opens its circuit breaker - zero to disable
+	OperationsCircuitBreakerFailureThreshold = ffc("operations.circuitBreaker.failureThreshold")
+	// OperationsCircuitBreakerCooldown is how long an open circuit breaker fails submissions fast, before testing the plugin again
+	OperationsCircuitBreakerCooldown = ffc("operations.circuitBreaker.cooldown")
+	// OperationsErrorDetailMaxSize is the maximum size of the connector response body preserved on a failed operation
+	OperationsErrorDetailMaxSize = ffc("operations.errorDetail.maxSize")
+	// OperationsErrorDetailRedactFields is a list of JSON field names that are redacted from the connector response preserved on a failed operation
+	OperationsErrorDetailRedactFields = ffc("operations.errorDetail.redactFields")
+	// OperationsNotifyMaxAttempts is the number of attempts made to deliver an operation notification webhook, before giving up
+	OperationsNotifyMaxAttempts = ffc("operations.notify.maxAttempts")
+	// OperationsNotifyRetryInitDelay is the initial delay between attempts to deliver an operation notification webhook
+	OperationsNotifyRetryInitDelay = ffc("operations.notify.retry.initialDelay")
+	// OperationsNotifyRetryMaxDelay is the maximum delay between attempts to deliver an operation notification webhook
+	OperationsNotifyRetryMaxDelay = ffc("operations.notify.retry.maxDelay")
+	// OperationsNotifyRequestTimeout is the timeout for each attempt to deliver an operation notification webhook
+	OperationsNotifyRequestTimeout = ffc("operations.notify.requestTimeout")
+	// OperationsNotifyAllowedHosts is the list of hosts that operation notification webhooks can be delivered to
+	OperationsNotifyAllowedHosts = ffc("operations.notify.allowedHosts")
+	// OperationsNotifyMaxRegistrations is the maximum number of operation notification webhooks waiting for their operations to resolve
+	OperationsNotifyMaxRegistrations = ffc("operations.notify.maxRegistrations")
+	// OperationsTransferProgressInterval is the minimum time between progress updates stored for a data exchange blob transfer
+	OperationsTransferProgressInterval = ffc("operations.transferProgress.interval")
 	// OpUpdateRetryInitDelay is the initial retry delay
 	OpUpdateRetryInitDelay = ffc("opupdate.retry.initialDelay")
 	// OpUpdatedRetryMaxDelay is the maximum retry delay
@@ -319,6 +487,10 @@ var (
 	OpUpdateWorkerBatchMaxInserts = ffc("opupdate.worker.batchMaxInserts")
 	// OpUpdateWorkerQueueLength
 	OpUpdateWorkerQueueLength = ffc("opupdate.worker.queueLength")
+	// OpUpdateWorkerCoalesce if true, pending updates to the same operation within a batch are merged into one
+	OpUpdateWorkerCoalesce = ffc("opupdate.worker.coalesce")
+	// OpUpdateWorkerCoalesceWindow if coalescing, how long non-terminal updates to an operation are held to be merged across batches
+	OpUpdateWorkerCoalesceWindow = ffc("opupdate.worker.coalesceWindow")
 	// OrgName is the short name for the org
 	OrgName = ffc("org.name")
 	// OrgKey is the signing identity allocated to the organization (can be the same as the nodes)
@@ -327,6 +499,16 @@ var (
 	OrgDescription = ffc("org.description")
 	// OrchestratorStartupAttempts is how many time to attempt to connect to core infrastructure on startup
 	OrchestratorStartupAttempts = ffc("orchestrator.startupAttempts")
+	// OrchestratorReadinessTimeout is how long to wait for each plugin to respond to a readiness check
+	OrchestratorReadinessTimeout = ffc("orchestrator.readinessTimeout")
+	// OrchestratorDiagnosticsTimeout is how long to wait for each check of the network diagnostics to complete
+	OrchestratorDiagnosticsTimeout = ffc("orchestrator.diagnosticsTimeout")
+	// RetentionWindow is the default age after which events, confirmed messages and completed operations are pruned. Zero disables pruning
+	RetentionWindow = ffc("retention.window")
+	// RetentionInterval is how often the retention manager of each namespace checks for records to prune
+	RetentionInterval = ffc("retention.interval")
+	// RetentionBatchSize is the number of records read and deleted in each database transaction while pruning
+	RetentionBatchSize = ffc("retention.batchSize")
 	// SubscriptionDefaultsBatchSize default read ahead to enable for subscriptions that do not explicitly configure readahead
 	SubscriptionDefaultsBatchSize = ffc("subscription.defaults.batchSize")
 	// SubscriptionDefaultsBatchTimeout default batch timeout
@@ -341,6 +523,20 @@ var (
 	SubscriptionsRetryFactor = ffc("subscription.retry.factor")
 	// SubscriptionMaxHistoricalEventScanLength the maximum amount of historical events we scan for in the DB when indexing through old events against a subscription
 	SubscriptionMaxHistoricalEventScanLength = ffc("subscription.events.maxScanLength")
+	// SubscriptionDeliveryErrorHistory the number of rejected event deliveries kept in memory for the namespace error report
+	SubscriptionDeliveryErrorHistory = ffc("subscription.deliveryErrorHistory")
+	// TracingEnabled determines whether OpenTelemetry spans are recorded and exported to an OTLP collector
+	TracingEnabled = ffc("tracing.enabled")
+	// TracingServiceName is the service name reported on all exported spans
+	TracingServiceName = ffc("tracing.serviceName")
+	// TracingSampleRatio is the fraction of traces started by this node that are sampled
+	TracingSampleRatio = ffc("tracing.sampleRatio")
+	// TracingOTLPEndpoint is the URL of the OTLP/HTTP collector that spans are exported to
+	TracingOTLPEndpoint = ffc("tracing.otlp.endpoint")
+	// TracingOTLPInsecure disables TLS when exporting spans to the OTLP collector
+	TracingOTLPInsecure = ffc("tracing.otlp.insecure")
+	// TransactionIdempotencyWindow is how long after a submission a duplicate idempotency key returns the original submission, rather than a conflict
+	TransactionIdempotencyWindow = ffc("transaction.idempotencyWindow")
 	// TransactionWriterCount
 	TransactionWriterCount = ffc("transaction.writer.count")
 	// TransactionWriterBatchTimeout
@@ -350,6 +546,9 @@ var (
 
 	// AssetManagerKeyNormalization mechanism to normalize keys before using them. Valid options: "blockchain_plugin" - use blockchain plugin (default), "none" - do not attempt normalization
 	AssetManagerKeyNormalization = ffc("asset.manager.keyNormalization")
+	// AssetManagerDelegatedTransferValidation how transfers and burns from the balance of another key are validated before they are submitted. Valid options: "connector" (default), "recorded", "none".
+	// The connector option cannot validate burns, or transfers through a connector that does not support simulation
+	AssetManagerDelegatedTransferValidation = ffc("asset.manager.delegatedTransferValidation")
 	// UIEnabled set to false to disable the UI (default is true, so UI will be enabled if ui.path is valid)
 	UIEnabled = ffc("ui.enabled")
 	// UIPath the path on which to serve the UI
@@ -365,12 +564,26 @@ func setDefaults() {
 	viper.SetDefault(string(APIMaxFilterSkip), 1000) // protects database (skip+limit pagination is not for bulk operations)
 	viper.SetDefault(string(APIRequestTimeout), "120s")
 	viper.SetDefault(string(APIPassthroughHeaders), []string{})
+	viper.SetDefault(string(APIRateLimitContractListenersRequestsPerSecond), 0)
+	viper.SetDefault(string(APIRateLimitContractListenersBurst), 10)
+	viper.SetDefault(string(APIRateLimitNamespaceRequestsPerSecond), 0)
+	viper.SetDefault(string(APIRateLimitNamespaceBurst), 100)
 	viper.SetDefault(string(AssetManagerKeyNormalization), "blockchain_plugin")
+	viper.SetDefault(string(AssetManagerDelegatedTransferValidation), "connector")
 	viper.SetDefault(string(CacheBatchLimit), 100)
 	viper.SetDefault(string(CacheBatchTTL), "5m")
+	viper.SetDefault(string(BatchAdaptiveEnabled), false)
+	viper.SetDefault(string(BatchAdaptiveMinSize), 1)
+	viper.SetDefault(string(BatchAdaptiveMaxSize), 1000)
+	viper.SetDefault(string(BatchAdaptiveTargetLatency), "5s")
+	viper.SetDefault(string(BatchAdaptiveWindow), 10)
 	viper.SetDefault(string(BatchManagerReadPageSize), 100)
 	viper.SetDefault(string(BatchManagerReadPollTimeout), "30s")
 	viper.SetDefault(string(BatchManagerMinimumPollDelay), "100ms")
+	viper.SetDefault(string(BatchManagerStatusInterval), "5s")
+	viper.SetDefault(string(BatchManagerStatusMinInterval), "250ms")
+	viper.SetDefault(string(BatchManagerFlushStatsWindow), "1m")
+	viper.SetDefault(string(BatchManagerSchedulePollInterval), "1s")
 	viper.SetDefault(string(BatchRetryFactor), 2.0)
 	viper.SetDefault(string(BatchRetryFactor), 2.0)
 	viper.SetDefault(string(BatchRetryInitDelay), "250ms")
@@ -382,6 +595,10 @@ func setDefaults() {
 	viper.SetDefault(string(BlobReceiverRetryFactor), 2.0)
 	viper.SetDefault(string(BlobReceiverWorkerBatchTimeout), "50ms")
 	viper.SetDefault(string(BlobReceiverWorkerCount), 5)
+	viper.SetDefault(string(BlobUploadAsyncWorkerCount), 5)
+	viper.SetDefault(string(BlobUploadStagingTTL), "24h")
+	viper.SetDefault(string(BlobUploadStagingSweepInterval), "10m")
+	viper.SetDefault(string(BlobUploadStagingMaxSize), "10Gb")
 	viper.SetDefault(string(BlobReceiverWorkerBatchMaxInserts), 200)
 	viper.SetDefault(string(CacheBlockchainEventLimit), 1000)
 	viper.SetDefault(string(CacheBlockchainEventTTL), "5m")
@@ -389,6 +606,7 @@ func setDefaults() {
 	viper.SetDefault(string(BroadcastBatchSize), 200)
 	viper.SetDefault(string(BroadcastBatchPayloadLimit), "800Kb")
 	viper.SetDefault(string(BroadcastBatchTimeout), "1s")
+	viper.SetDefault(string(BroadcastDeduplicate), false)
 	viper.SetDefault(string(CacheBlockchainLimit), 100)
 	viper.SetDefault(string(CacheBlockchainTTL), "5m")
 	viper.SetDefault(string(CacheAddressResolverLimit), 1000)
@@ -398,14 +616,24 @@ func setDefaults() {
 	viper.SetDefault(string(CacheOperationsTTL), "5m")
 	viper.SetDefault(string(CacheMethodsLimit), 200)
 	viper.SetDefault(string(CacheMethodsTTL), "5m")
+	viper.SetDefault(string(HAEnabled), false)
+	viper.SetDefault(string(HALockProvider), "database")
+	viper.SetDefault(string(HALockName), "firefly")
+	viper.SetDefault(string(HALeaseDuration), "30s")
+	viper.SetDefault(string(HARenewInterval), "10s")
 	viper.SetDefault(string(HistogramsMaxChartRows), 100)
+	viper.SetDefault(string(HistogramsRollupEnabled), true)
+	viper.SetDefault(string(HistogramsRollupInterval), "1m")
+	viper.SetDefault(string(HistogramsRollupBatchSize), 10000)
 	viper.SetDefault(string(DebugPort), -1)
 	viper.SetDefault(string(DebugAddress), "localhost")
+	viper.SetDefault(string(DefinitionsDeprecatedPolicy), DeprecatedPolicyWarn)
 	viper.SetDefault(string(DownloadWorkerCount), 10)
 	viper.SetDefault(string(DownloadRetryMaxAttempts), 100)
 	viper.SetDefault(string(DownloadRetryInitDelay), "100ms")
 	viper.SetDefault(string(DownloadRetryMaxDelay), "1m")
 	viper.SetDefault(string(DownloadRetryFactor), 2.0)
+	viper.SetDefault(string(DownloadVerifyPayloadHash), true)
 	viper.SetDefault(string(EventAggregatorFirstEvent), core.SubOptsFirstEventOldest)
 	viper.SetDefault(string(EventAggregatorBatchSize), 200)
 	viper.SetDefault(string(EventAggregatorBatchTimeout), "0ms")
@@ -416,12 +644,20 @@ func setDefaults() {
 	viper.SetDefault(string(EventAggregatorRetryFactor), 2.0)
 	viper.SetDefault(string(EventAggregatorRetryInitDelay), "100ms")
 	viper.SetDefault(string(EventAggregatorRetryMaxDelay), "30s")
+	viper.SetDefault(string(EventAggregatorWorkers), 1)
 	viper.SetDefault(string(EventDBEventsBufferSize), 100)
+	viper.SetDefault(string(EventMaxIndexedDataSize), 0)
 	viper.SetDefault(string(EventDispatcherBufferLength), 5)
 	viper.SetDefault(string(EventDispatcherBatchTimeout), "0ms")
 	viper.SetDefault(string(EventDispatcherPollTimeout), "30s")
+	viper.SetDefault(string(EventDispatcherOffsetCommitRetryMaxAttempts), 5)
+	viper.SetDefault(string(EventDispatcherOffsetCommitRetryFactor), 2.0)
+	viper.SetDefault(string(EventDispatcherOffsetCommitRetryInitDelay), "250ms")
+	viper.SetDefault(string(EventDispatcherOffsetCommitRetryMaxDelay), "30s")
 	viper.SetDefault(string(EventTransportsEnabled), []string{"websockets", "webhooks"})
 	viper.SetDefault(string(EventTransportsDefault), "websockets")
+	viper.SetDefault(string(CacheEventEnrichmentLimit), 1000)
+	viper.SetDefault(string(CacheEventEnrichmentTTL), "1m")
 	viper.SetDefault(string(CacheEventListenerTopicLimit), 100)
 	viper.SetDefault(string(CacheEventListenerTopicTTL), "5m")
 	viper.SetDefault(string(CacheGroupLimit), 50)
@@ -441,6 +677,19 @@ func setDefaults() {
 	viper.SetDefault(string(NamespacesRetryMaxDelay), "1m")
 	viper.SetDefault(string(NamespacesRetryInitDelay), "5s")
 	viper.SetDefault(string(OrchestratorStartupAttempts), 5)
+	viper.SetDefault(string(OrchestratorReadinessTimeout), "5s")
+	viper.SetDefault(string(OrchestratorDiagnosticsTimeout), "30s")
+	viper.SetDefault(string(OperationsCircuitBreakerFailureThreshold), 0)
+	viper.SetDefault(string(OperationsCircuitBreakerCooldown), "30s")
+	viper.SetDefault(string(OperationsErrorDetailMaxSize), "4Kb")
+	viper.SetDefault(string(OperationsErrorDetailRedactFields), []string{})
+	viper.SetDefault(string(OperationsNotifyMaxAttempts), 5)
+	viper.SetDefault(string(OperationsNotifyRetryInitDelay), "250ms")
+	viper.SetDefault(string(OperationsNotifyRetryMaxDelay), "30s")
+	viper.SetDefault(string(OperationsNotifyRequestTimeout), "30s")
+	viper.SetDefault(string(OperationsNotifyAllowedHosts), []string{})
+	viper.SetDefault(string(OperationsNotifyMaxRegistrations), 1000)
+	viper.SetDefault(string(OperationsTransferProgressInterval), "1s")
 	viper.SetDefault(string(OpUpdateRetryInitDelay), "250ms")
 	viper.SetDefault(string(OpUpdateRetryMaxDelay), "1m")
 	viper.SetDefault(string(OpUpdateRetryFactor), 2.0)
@@ -448,13 +697,20 @@ func setDefaults() {
 	viper.SetDefault(string(OpUpdateWorkerCount), 5)
 	viper.SetDefault(string(OpUpdateWorkerBatchMaxInserts), 200)
 	viper.SetDefault(string(OpUpdateWorkerQueueLength), 50)
+	viper.SetDefault(string(OpUpdateWorkerCoalesce), false)
+	viper.SetDefault(string(OpUpdateWorkerCoalesceWindow), "0s")
 	viper.SetDefault(string(PrivateMessagingRetryFactor), 2.0)
 	viper.SetDefault(string(PrivateMessagingRetryInitDelay), "100ms")
 	viper.SetDefault(string(PrivateMessagingRetryMaxDelay), "30s")
+	viper.SetDefault(string(PrivateMessagingAcksEnabled), false)
+	viper.SetDefault(string(PrivateMessagingAcksQueueLength), 100)
 	viper.SetDefault(string(PrivateMessagingBatchAgentTimeout), "2m")
 	viper.SetDefault(string(PrivateMessagingBatchSize), 200)
 	viper.SetDefault(string(PrivateMessagingBatchTimeout), "1s")
 	viper.SetDefault(string(PrivateMessagingBatchPayloadLimit), "800Kb")
+	viper.SetDefault(string(RetentionWindow), "0")
+	viper.SetDefault(string(RetentionInterval), "1h")
+	viper.SetDefault(string(RetentionBatchSize), 1000)
 	viper.SetDefault(string(SubscriptionDefaultsBatchSize), 50)
 	viper.SetDefault(string(SubscriptionDefaultsBatchTimeout), "50ms")
 	viper.SetDefault(string(SubscriptionMax), 500)
@@ -462,6 +718,12 @@ func setDefaults() {
 	viper.SetDefault(string(SubscriptionsRetryMaxDelay), "30s")
 	viper.SetDefault(string(SubscriptionsRetryFactor), 2.0)
 	viper.SetDefault(string(SubscriptionMaxHistoricalEventScanLength), 1000)
+	viper.SetDefault(string(SubscriptionDeliveryErrorHistory), 1000)
+	viper.SetDefault(string(TracingEnabled), false)
+	viper.SetDefault(string(TracingServiceName), "firefly")
+	viper.SetDefault(string(TracingSampleRatio), 1.0)
+	viper.SetDefault(string(TracingOTLPInsecure), false)
+	viper.SetDefault(string(TransactionIdempotencyWindow), "0s")
 	viper.SetDefault(string(TransactionWriterBatchMaxTransactions), 100)
 	viper.SetDefault(string(TransactionWriterBatchTimeout), "10ms")
 	viper.SetDefault(string(TransactionWriterCount), 5)
@@ -474,6 +736,8 @@ func setDefaults() {
 	viper.SetDefault(string(CacheIdentityTTL), "1h")
 	viper.SetDefault(string(CacheTokenPoolLimit), 100)
 	viper.SetDefault(string(CacheTokenPoolTTL), "1h")
+	viper.SetDefault(string(CacheTokenMetadataLimit), 1000)
+	viper.SetDefault(string(CacheTokenMetadataTTL), "1h")
 }
 
 func Reset() {
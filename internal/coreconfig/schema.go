@@ -0,0 +1,144 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coreconfig
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// sensitiveKeySuffixes identify config keys holding secrets, by a case-insensitive match on the end of the key
+var sensitiveKeySuffixes = []string{"password", "secret", "token", "apikey", "privatekey"}
+
+// GenerateConfigSchema builds a JSON Schema describing every known config key, using the registered
+// config descriptions for the type and description of each. Default values are deliberately not
+// included, as the registry only holds the values in effect for this node.
+func GenerateConfigSchema(ctx context.Context) fftypes.JSONObject {
+	root := newSchemaObject()
+	for _, key := range config.GetKnownKeys() {
+		description, fieldType := configKeyDescription(ctx, key)
+		if fieldType == i18n.IgnoredType {
+			continue
+		}
+		parts := strings.Split(key, ".")
+		parent := root
+		for _, part := range parts[:len(parts)-1] {
+			parent = schemaChildObject(parent, part)
+		}
+		leafName := parts[len(parts)-1]
+		leaf := schemaForFieldType(fieldType)
+		if description != "" {
+			leaf["description"] = description
+		}
+		if isSensitiveConfigKey(leafName) {
+			leaf["x-sensitive"] = true
+		}
+		properties := parent["properties"].(fftypes.JSONObject)
+		if _, exists := properties[leafName]; !exists {
+			properties[leafName] = leaf
+		}
+	}
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "Hyperledger FireFly configuration"
+	return root
+}
+
+func newSchemaObject() fftypes.JSONObject {
+	return fftypes.JSONObject{
+		"type":       "object",
+		"properties": fftypes.JSONObject{},
+	}
+}
+
+// schemaChildObject returns the object schema for a section of the config, creating it if required.
+// Array sections are named with a "[]" suffix in the key registry.
+func schemaChildObject(parent fftypes.JSONObject, part string) fftypes.JSONObject {
+	properties := parent["properties"].(fftypes.JSONObject)
+	name := strings.TrimSuffix(part, "[]")
+	isArray := name != part
+	if child, ok := properties[name].(fftypes.JSONObject); ok {
+		if items, ok := child["items"].(fftypes.JSONObject); ok && items["properties"] != nil {
+			return items
+		}
+		if child["properties"] != nil {
+			return child
+		}
+	}
+	// New section, or replacing a key that has been registered as both a value and a section
+	section := newSchemaObject()
+	if isArray {
+		properties[name] = fftypes.JSONObject{
+			"type":  "array",
+			"items": section,
+		}
+	} else {
+		properties[name] = section
+	}
+	return section
+}
+
+func schemaForFieldType(fieldType string) fftypes.JSONObject {
+	switch fieldType {
+	case i18n.StringType, i18n.TimeDurationType, i18n.TimeFormatType, i18n.GoTemplateType:
+		return fftypes.JSONObject{"type": "string"}
+	case i18n.ByteSizeType:
+		return fftypes.JSONObject{"type": []string{"string", "integer"}}
+	case i18n.IntType:
+		return fftypes.JSONObject{"type": "integer"}
+	case i18n.FloatType:
+		return fftypes.JSONObject{"type": "number"}
+	case i18n.BooleanType:
+		return fftypes.JSONObject{"type": "boolean"}
+	case i18n.ArrayStringType:
+		return fftypes.JSONObject{"type": "array", "items": fftypes.JSONObject{"type": "string"}}
+	case i18n.MapStringStringType:
+		return fftypes.JSONObject{"type": "object", "additionalProperties": fftypes.JSONObject{"type": "string"}}
+	default:
+		return fftypes.JSONObject{}
+	}
+}
+
+func isSensitiveConfigKey(name string) bool {
+	lower := strings.ToLower(name)
+	for _, s := range sensitiveKeySuffixes {
+		if strings.HasSuffix(lower, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// configKeyDescription finds the description of a key, falling back to the global descriptions shared by
+// plugin config sections in the same way as the generated config reference
+func configKeyDescription(ctx context.Context, key string) (string, string) {
+	candidates := []string{"config." + key}
+	parts := strings.Split(key, ".")
+	for i := range parts {
+		candidates = append(candidates, "config.global."+strings.Join(parts[i:], "."))
+	}
+	for _, descriptionKey := range candidates {
+		description := i18n.Expand(ctx, i18n.MessageKey(descriptionKey))
+		if fieldType, ok := i18n.GetFieldType(descriptionKey); ok && description != descriptionKey {
+			return description, fieldType
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,87 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coreconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateConfigSchema(t *testing.T) {
+	Reset()
+	plugins := config.RootArray("plugins.blockchain")
+	plugins.AddKnownKey("name")
+	ethconnect := plugins.SubSection("ethereum").SubSection("ethconnect")
+	auth := ethconnect.SubSection("auth")
+	auth.AddKnownKey("username")
+	auth.AddKnownKey("password")
+	auth.AddKnownKey("passwordfile")
+
+	schema := GenerateConfigSchema(context.Background())
+	assert.Equal(t, "object", schema["type"])
+
+	properties := schema.GetObject("properties")
+	assert.Equal(t, "integer", properties.GetObject("api").GetObject("properties").GetObject("defaultFilterLimit")["type"])
+	assert.NotEmpty(t, properties.GetObject("debug").GetObject("properties").GetObject("port")["description"])
+	passthroughHeaders := properties.GetObject("api").GetObject("properties").GetObject("passthroughHeaders")
+	assert.Equal(t, "array", passthroughHeaders["type"])
+
+	blockchain := properties.GetObject("plugins").GetObject("properties").GetObject("blockchain")
+	assert.Equal(t, "array", blockchain["type"])
+	ethconnectSchema := blockchain.GetObject("items").GetObject("properties").
+		GetObject("ethereum").GetObject("properties").
+		GetObject("ethconnect").GetObject("properties")
+	authSchema := ethconnectSchema.GetObject("auth").GetObject("properties")
+	assert.Equal(t, "string", authSchema.GetObject("username")["type"])
+	assert.Nil(t, authSchema.GetObject("username")["x-sensitive"])
+	assert.Equal(t, true, authSchema.GetObject("password")["x-sensitive"])
+	assert.Nil(t, authSchema.GetObject("passwordfile")["x-sensitive"])
+}
+
+func TestSchemaChildObjectReplacesValue(t *testing.T) {
+	parent := newSchemaObject()
+	parent["properties"].(fftypes.JSONObject)["headers"] = schemaForFieldType("`[]string`")
+
+	child := schemaChildObject(parent, "headers")
+	assert.NotNil(t, child["properties"])
+	assert.Equal(t, child, schemaChildObject(parent, "headers"))
+
+	arrayChild := schemaChildObject(parent, "items[]")
+	assert.Equal(t, arrayChild, schemaChildObject(parent, "items[]"))
+}
+
+func TestSchemaForFieldType(t *testing.T) {
+	for fieldType, expected := range map[string]interface{}{
+		"`string`": "string",
+		"[`BytesSize`](https://pkg.go.dev/github.com/docker/go-units#BytesSize)": []string{"string", "integer"},
+		"`float32`":           "number",
+		"`map[string]string`": "object",
+		"unknown":             nil,
+	} {
+		assert.Equal(t, expected, schemaForFieldType(fieldType)["type"])
+	}
+}
+
+func TestConfigKeyDescriptionNotFound(t *testing.T) {
+	description, fieldType := configKeyDescription(context.Background(), "not.a.key")
+	assert.Empty(t, description)
+	assert.Empty(t, fieldType)
+}
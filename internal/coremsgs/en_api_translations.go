@@ -25,9 +25,11 @@ var (
 	APIParamsOperationIDGet                 = ffm("api.params.operationID.get", "The operation ID key to get")
 	APIParamsOperationNamespacedID          = ffm("api.params.spiOperationID", "The operation ID as passed to the connector when the operation was performed, including the 'namespace:' prefix")
 	APIParamsNamespace                      = ffm("api.params.namespace", "The namespace which scopes this request")
+	APIParamsPluginName                     = ffm("api.params.pluginName", "The name of the plugin, as configured in the plugins section of the config")
 	APIParamsContractListenerNameOrID       = ffm("api.params.contractListenerNameOrID", "The contract listener name or ID")
 	APIParamsContractListenerID             = ffm("api.params.contractListenerID", "The contract listener ID")
 	APIParamsSubscriptionID                 = ffm("api.params.subscriptionID", "The subscription ID")
+	APIParamsDeadLetterID                   = ffm("api.params.deadLetterID", "The ID of the dead-letter entry")
 	APIParamsBatchID                        = ffm("api.params.batchId", "The batch ID")
 	APIParamsBlockchainEventID              = ffm("api.params.blockchainEventID", "The blockchain event ID")
 	APIParamsCollectionID                   = ffm("api.params.collectionID", "The collection ID")
@@ -37,10 +39,14 @@ var (
 	APIParamsContractInterfaceID            = ffm("api.params.contractInterfaceID", "The ID of the contract interface")
 	APIParamsContractInterfaceFetchChildren = ffm("api.params.contractInterfaceFetchChildren", "When set, the API will return the full FireFly Interface document including all methods, events, and parameters")
 	APIParamsNSIncludeInitializing          = ffm("api.params.nsIncludeInitializing", "When set, the API will return namespaces even if they are not yet initialized, including in error cases where an initializationError is included")
+	APIParamsNSIncludePlugins               = ffm("api.params.nsIncludePlugins", "When set, the API will return the plugins bound to each namespace, along with its default signing key and multiparty mode")
 	APIParamsBlobID                         = ffm("api.params.blobID", "The blob ID")
 	APIParamsDataID                         = ffm("api.params.dataID", "The data item ID")
+	APIParamsUploadID                       = ffm("api.params.uploadID", "The blob upload ID")
+	APIParamsUploadOffset                   = ffm("api.params.uploadOffset", "The number of bytes of the blob already received, which the chunk must start from. Rejects the chunk if it does not match, so a retried chunk is never appended twice")
 	APIParamsDatatypeName                   = ffm("api.params.datatypeName", "The name of the datatype")
 	APIParamsDatatypeVersion                = ffm("api.params.datatypeVersion", "The version of the datatype")
+	APIParamsOtherVersion                   = ffm("api.params.otherVersion", "The other version to compare against")
 	APIParamsDataParentPath                 = ffm("api.params.dataParentPath", "The parent path to query")
 	APIParamsEventID                        = ffm("api.params.eventID", "The event ID")
 	APIParamsFetchReferences                = ffm("api.params.fetchReferences", "When set, the API will return the record that this item references in its 'reference' field")
@@ -50,10 +56,13 @@ var (
 	APIParamsIdentityID                     = ffm("api.params.identityID", "The identity ID, which is a UUID generated by FireFly")
 	APIParamsMessageID                      = ffm("api.params.messageID", "The message ID")
 	APIParamsDID                            = ffm("api.params.DID", "The identity DID")
+	APIParamsBridgeNameOrID                 = ffm("api.params.bridgeNameOrID", "The bridge name or ID")
 	APIParamsNodeNameOrID                   = ffm("api.params.nodeNameOrID", "The name or ID of the node")
 	APIParamsOrgNameOrID                    = ffm("api.params.orgNameOrID", "The name or ID of the org")
 	APIParamsTokenAccountKey                = ffm("api.params.tokenAccountKey", "The key for the token account. The exact format may vary based on the token connector use")
 	APIParamsTokenPoolNameOrID              = ffm("api.params.tokenPoolNameOrID", "The token pool name or ID")
+	APIParamsTokenIndex                     = ffm("api.params.tokenIndex", "The index of the token within the non-fungible token pool")
+	APIParamsFetchMetadata                  = ffm("api.params.fetchMetadata", "When set, the API will resolve the metadata of each token from its URI, and return it in the 'metadata' field")
 	APIParamsTokenTransferFromOrTo          = ffm("api.params.tokenTransferFromOrTo", "The sending or receiving token account for a token transfer")
 	APIParamsTokenTransferID                = ffm("api.params.tokenTransferID", "The token transfer ID")
 	APIParamsTransactionID                  = ffm("api.params.transactionID", "The transaction ID")
@@ -65,150 +74,233 @@ var (
 	APIParamsMetadata                       = ffm("api.params.metadata", "Metadata associated with this data item")
 	APIParamsAutometa                       = ffm("api.params.autometa", "When set, FireFly will automatically generate JSON metadata with the upload details")
 	APIParamsContractAPIID                  = ffm("api.params.contractAPIID", "The ID of the contract API")
+	APIParamsOperationVerbose               = ffm("api.params.operationVerbose", "When set, the structured error response from the connector is included in the lastError field, if the operation failed on submission")
 	APIParamsFetchStatus                    = ffm("api.params.fetchStatus", "When set, the API will return additional status information if available")
+	APIParamsIncludeInactive                = ffm("api.params.includeInactive", "When set, listeners that have been deleted are also returned, with their state populated")
+	APIParamsExportFormat                   = ffm("api.params.exportFormat", "The format of the export - ndjson (the default) for one JSON record per line, or csv")
+	APIParamsDryRun                         = ffm("api.params.dryRun", "When true, the result describes what would be re-evaluated, without performing the action")
+	APIParamsPinSequence                    = ffm("api.params.pinSequence", "The sequence of the pin")
+	APIParamsPageCursorAfter                = ffm("api.params.pageCursorAfter", "Opaque cursor returned in the x-ff-next-cursor header, or the next field of the list result, of a previous page. When set, results after the cursor are returned using keyset pagination instead of skip. On events and pins the sequence of the last item received can be supplied instead")
 
+	APIEndpointsAdminGetConfigSchema    = ffm("api.endpoints.adminGetConfigSchema", "Gets a JSON Schema describing all configuration options, for validating config files. Options holding secrets are marked with x-sensitive")
 	APIEndpointsAdminGetNamespaceByName = ffm("api.endpoints.adminGetNamespaceByName", "Gets a namespace by name")
 	APIEndpointsAdminGetNamespaces      = ffm("api.endpoints.adminGetNamespaces", "List namespaces")
 	APIEndpointsAdminGetOpByID          = ffm("api.endpoints.adminGetOpByID", "Gets an operation by ID")
 	APIEndpointsAdminGetOps             = ffm("api.endpoints.adminGetOps", "Lists operations")
 	APIEndpointsAdminPostReset          = ffm("api.endpoints.adminPostResetConfig", "Restarts FireFly Core HTTP servers and apply all configuration updates")
+	APIEndpointsAdminPutConfigReload    = ffm("api.endpoints.adminPutConfigReload", "Re-reads the configuration file, and restarts only the namespaces and plugins whose configuration has changed, without restarting the HTTP servers")
+	APIEndpointsAdminPostPluginReset    = ffm("api.endpoints.adminPostPluginReset", "Restarts the connection of a single blockchain, dataexchange or tokens plugin, along with the namespaces that use it. The configuration file is re-read first, so rotated URLs and credentials are picked up. Only the configuration of this plugin is applied - other changes wait for the next configuration reload")
 	APIEndpointsAdminPatchOpByID        = ffm("api.endpoints.adminPatchOpByID", "Updates an operation by ID")
 	APIEndpointsAdminGetListenerByID    = ffm("api.endpoints.adminGetListenerByID", "Gets a contract listener by ID")
 	APIEndpointsAdminGetListeners       = ffm("api.endpoints.adminGetListeners", "Lists contract listeners")
 
-	APIEndpointsDeleteContractAPI               = ffm("api.endpoints.deleteContractAPI", "Delete a contract API")
-	APIEndpointsDeleteContractInterface         = ffm("api.endpoints.deleteContractInterface", "Delete a contract interface")
-	APIEndpointsDeleteContractListener          = ffm("api.endpoints.deleteContractListener", "Deletes a contract listener referenced by its name or its ID")
-	APIEndpointsDeleteSubscription              = ffm("api.endpoints.deleteSubscription", "Deletes a subscription")
-	APIEndpointsDeleteTokenPool                 = ffm("api.endpoints.deleteTokenPool", "Delete a token pool")
-	APIEndpointsGetBatchBbyID                   = ffm("api.endpoints.getBatchByID", "Gets a message batch")
-	APIEndpointsGetBatches                      = ffm("api.endpoints.getBatches", "Gets a list of message batches")
-	APIEndpointsGetBlockchainEventByID          = ffm("api.endpoints.getBlockchainEventByID", "Gets a blockchain event")
-	APIEndpointsListBlockchainEvents            = ffm("api.endpoints.getBlockchainEvents", "Gets a list of blockchain events")
-	APIEndpointsGetChartHistogram               = ffm("api.endpoints.getChartHistogram", "Gets a JSON object containing statistics data that can be used to build a graphical representation of recent activity in a given database collection")
-	APIEndpointsGetContractAPIByName            = ffm("api.endpoints.getContractAPIByName", "Gets information about a contract API, including the URLs for the OpenAPI Spec and Swagger UI for the API")
-	APIEndpointsGetContractAPIs                 = ffm("api.endpoints.getContractAPIs", "Gets a list of contract APIs that have been published")
-	APIEndpointsGetContractInterfaceNameVersion = ffm("api.endpoints.getContractInterfaceNameVersion", "Gets a contract interface by its name and version")
-	APIEndpointsGetContractInterface            = ffm("api.endpoints.getContractInterface", "Gets a contract interface by its ID")
-	APIEndpointsGetContractInterfaces           = ffm("api.endpoints.getContractInterfaces", "Gets a list of contract interfaces that have been published")
-	APIEndpointsGetContractListenerByNameOrID   = ffm("api.endpoints.getContractListenerByNameOrID", "Gets a contract listener by its name or ID")
-	APIEndpointsGetContractListeners            = ffm("api.endpoints.getContractListeners", "Gets a list of contract listeners")
-	APIEndpointsGetDataBlob                     = ffm("api.endpoints.getDataBlob", "Downloads the original file that was previously uploaded or received")
-	APIEndpointsGetDataValue                    = ffm("api.endpoints.getDataValue", "Downloads the JSON value of the data resource, without the associated metadata")
-	APIEndpointsGetDataByID                     = ffm("api.endpoints.getDataByID", "Gets a data item by its ID, including metadata about this item")
-	APIEndpointsDeleteData                      = ffm("api.endpoints.deleteData", "Deletes a data item by its ID, including metadata about this item")
-	APIEndpointsGetDataMsgs                     = ffm("api.endpoints.getDataMsgs", "Gets a list of the messages associated with a data item")
-	APIEndpointsGetData                         = ffm("api.endpoints.getData", "Gets a list of data items")
-	APIEndpointsGetDataSubPaths                 = ffm("api.endpoints.getDataSubPaths", "Gets a list of path names of named blob data, underneath a given parent path ('/' path prefixes are automatically pre-prepended)")
-	APIEndpointsGetDatatypeByName               = ffm("api.endpoints.getDatatypeByName", "Gets a datatype by its name and version")
-	APIEndpointsGetDatatypes                    = ffm("api.endpoints.getDatatypes", "Gets a list of datatypes that have been published")
-	APIEndpointsGetEventByID                    = ffm("api.endpoints.eventID", "Gets an event by its ID")
-	APIEndpointsGetEvents                       = ffm("api.endpoints.getEvents", "Gets a list of events")
-	APIEndpointsGetGroupByHash                  = ffm("api.endpoints.getGroupByHash", "Gets a group by its ID (hash)")
-	APIEndpointsGetGroups                       = ffm("api.endpoints.getGroups", "Gets a list of groups")
-	APIEndpointsGetIdentities                   = ffm("api.endpoints.getIdentities", "Gets a list of all identities that have been registered in the namespace")
-	APIEndpointsGetIdentityByID                 = ffm("api.endpoints.getIdentityByID", "Gets an identity by its ID")
-	APIEndpointsGetIdentityDID                  = ffm("api.endpoints.getIdentityDID", "Gets the DID for an identity based on its ID")
-	APIEndpointsGetIdentityVerifiers            = ffm("api.endpoints.getIdentityVerifiers", "Gets the verifiers for an identity")
-	APIEndpointsGetMsgByID                      = ffm("api.endpoints.getMsgByID", "Gets a message by its ID")
-	APIEndpointsGetMsgData                      = ffm("api.endpoints.getMsgData", "Gets the list of data items that are attached to a message")
-	APIEndpointsGetMsgEvents                    = ffm("api.endpoints.getMsgEvents", "Gets the list of events for a message")
-	APIEndpointsGetMsgTxn                       = ffm("api.endpoints.getMsgTxn", "Gets the transaction for a message")
-	APIEndpointsGetMsgs                         = ffm("api.endpoints.getMsgs", "Gets a list of messages")
-	APIEndpointsGetNamespace                    = ffm("api.endpoints.getNamespace", "Gets a namespace")
-	APIEndpointsGetNamespaces                   = ffm("api.endpoints.getNamespaces", "Gets a list of namespaces")
-	APIEndpointsGetNetworkIdentityByDID         = ffm("api.endpoints.getNetworkIdentityByDID", "Gets an identity by its DID (deprecated - use /identities/{did} instead of /network/identities/{did})")
-	APIEndpointsGetIdentityByDID                = ffm("api.endpoints.getIdentityByDID", "Gets an identity by its DID")
-	APIEndpointsGetDIDDocByDID                  = ffm("api.endpoints.getDIDDocByDID", "Gets a DID document by its DID")
-	APIEndpointsGetNetworkIdentities            = ffm("api.endpoints.getNetworkIdentities", "Gets the list of identities in the network (deprecated - use /identities instead of /network/identities")
-	APIEndpointsGetNetworkNode                  = ffm("api.endpoints.getNetworkNode", "Gets information about a specific node in the network")
-	APIEndpointsGetNetworkNodes                 = ffm("api.endpoints.getNetworkNodes", "Gets a list of nodes in the network")
-	APIEndpointsGetNetworkOrg                   = ffm("api.endpoints.getNetworkOrg", "Gets information about a specific org in the network")
-	APIEndpointsGetNetworkOrgs                  = ffm("api.endpoints.APIEndpointsGetNetworkOrgs", "Gets a list of orgs in the network")
-	APIEndpointsGetOpByID                       = ffm("api.endpoints.getOpByID", "Gets an operation by ID")
-	APIEndpointsGetOps                          = ffm("api.endpoints.getOps", "Gets a a list of operations")
-	APIEndpointsGetStatusBatchManager           = ffm("api.endpoints.getStatusBatchManager", "Gets the status of the batch manager")
-	APIEndpointsGetPins                         = ffm("api.endpoints.getPins", "Queries the list of pins received from the blockchain")
-	APIEndpointsGetNextPins                     = ffm("api.endpoints.getNextPins", "Queries the list of next-pins that determine the next masked message sequence for each member of a privacy group, on each context/topic")
-	APIEndpointsGetWebSockets                   = ffm("api.endpoints.getStatusWebSockets", "Gets a list of the current WebSocket connections to this node")
-	APIEndpointsGetStatus                       = ffm("api.endpoints.getStatus", "Gets the status of this namespace")
-	APIEndpointsGetMultipartyStatus             = ffm("api.endpoints.getMultipartyStatus", "Gets the registration status of this organization and node on the configured multiparty network")
-	APIEndpointsGetSubscriptionByID             = ffm("api.endpoints.getSubscriptionByID", "Gets a subscription by its ID")
-	APIEndpointsGetSubscriptionEventsFiltered   = ffm("api.endpoints.getSubscriptionEventsFiltered", "Gets a collection of events filtered by the subscription for further filtering")
-	APIEndpointsGetSubscriptions                = ffm("api.endpoints.getSubscriptions", "Gets a list of subscriptions")
-	APIEndpointsGetTokenAccountPools            = ffm("api.endpoints.getTokenAccountPools", "Gets a list of token pools that contain a given token account key")
-	APIEndpointsGetTokenAccounts                = ffm("api.endpoints.getTokenAccounts", "Gets a list of token accounts")
-	APIEndpointsGetTokenApprovals               = ffm("api.endpoints.getTokenApprovals", "Gets a list of token approvals")
-	APIEndpointsGetTokenBalances                = ffm("api.endpoints.getTokenBalances", "Gets a list of token balances")
-	APIEndpointsGetTokenConnectors              = ffm("api.endpoints.getTokenConnectors", "Gets the list of token connectors currently in use")
-	APIEndpointsGetTokenPoolByNameOrID          = ffm("api.endpoints.getTokenPoolByNameOrID", "Gets a token pool by its name or its ID")
-	APIEndpointsGetTokenPools                   = ffm("api.endpoints.getTokenPools", "Gets a list of token pools")
-	APIEndpointsGetTokenTransferByID            = ffm("api.endpoints.getTokenTransferByID", "Gets a token transfer by its ID")
-	APIEndpointsGetTokenTransfers               = ffm("api.endpoints.getTokenTransfers", "Gets a list of token transfers")
-	APIEndpointsGetTxnBlockchainEvents          = ffm("api.endpoints.getTxnBlockchainEvents", "Gets a list blockchain events for a specific transaction")
-	APIEndpointsGetTxnByID                      = ffm("api.endpoints.getTxnByID", "Gets a transaction by its ID")
-	APIEndpointsGetTxnOps                       = ffm("api.endpoints.getTxnOps", "Gets a list of operations in a specific transaction")
-	APIEndpointsGetTxnStatus                    = ffm("api.endpoints.getTxnStatus", "Gets the status of a transaction")
-	APIEndpointsGetTxns                         = ffm("api.endpoints.getTxns", "Gets a list of transactions")
-	APIEndpointsGetVerifierByHash               = ffm("api.endpoints.getVerifierByHash", "Gets a verifier by its hash")
-	APIEndpointsGetVerifiers                    = ffm("api.endpoints.getVerifiers", "Gets a list of verifiers")
-	APIEndpointsPatchUpdateIdentity             = ffm("api.endpoints.patchUpdateIdentity", "Updates an identity")
-	APIEndpointsPostBatchCancel                 = ffm("api.endpoints.postBatchCancel", "Cancel a batch that has failed to dispatch")
-	APIEndpointsPostContractDeploy              = ffm("api.endpoints.postContractDeploy", "Deploy a new smart contract")
-	APIEndpointsPostContractAPIInvoke           = ffm("api.endpoints.postContractAPIInvoke", "Invokes a method on a smart contract API. Performs a blockchain transaction.")
-	APIEndpointsPostContractAPIPublish          = ffm("api.endpoints.postContractAPIPublish", "Publish a contract API to all other members of the multiparty network")
-	APIEndpointsPostContractAPIQuery            = ffm("api.endpoints.postContractAPIQuery", "Queries a method on a smart contract API. Performs a read-only query.")
-	APIEndpointsPostContractInterfaceGenerate   = ffm("api.endpoints.postContractInterfaceGenerate", "A convenience method to convert a blockchain specific smart contract format into a FireFly Interface format. The specific blockchain plugin in use must support this functionality.")
-	APIEndpointsPostContractInterfaceInvoke     = ffm("api.endpoints.postContractInterfaceInvoke", "Invokes a method on a smart contract that matches a given contract interface. Performs a blockchain transaction.")
-	APIEndpointsPostContractInterfaceQuery      = ffm("api.endpoints.postContractInterfaceQuery", "Queries a method on a smart contract that matches a given contract interface. Performs a read-only query.")
-	APIEndpointsPostContractInterfacePublish    = ffm("api.endpoints.postContractInterfacePublish", "Publish a contract interface to all other members of the multiparty network")
-	APIEndpointsPostContractInvoke              = ffm("api.endpoints.postContractInvoke", "Invokes a method on a smart contract. Performs a blockchain transaction.")
-	APIEndpointsPostContractQuery               = ffm("api.endpoints.postContractQuery", "Queries a method on a smart contract. Performs a read-only query.")
-	APIEndpointsPostData                        = ffm("api.endpoints.postData", "Creates a new data item in this FireFly node")
-	APIEndpointsPostDataValuePublish            = ffm("api.endpoints.postDataValuePublish", "Publishes the JSON value from the specified data resource, to shared storage")
-	APIEndpointsPostDataBlobPublish             = ffm("api.endpoints.postDataBlobPublish", "Publishes the binary blob attachment stored in your local data exchange, to shared storage")
-	APIEndpointsPostNewContractAPI              = ffm("api.endpoints.postNewContractAPI", "Creates and broadcasts a new custom smart contract API")
-	APIEndpointsPostNewContractInterface        = ffm("api.endpoints.postNewContractInterface", "Creates and broadcasts a new custom smart contract interface")
-	APIEndpointsPostNewContractListener         = ffm("api.endpoints.postNewContractListener", "Creates a new blockchain listener for events emitted by custom smart contracts")
-	APIEndpointsPostContractListenerHash        = ffm("api.endpoints.postContractListenerHash", "Calculates the hash of a blockchain listener filters and events")
-	APIEndpointsPostNewDatatype                 = ffm("api.endpoints.postNewDatatype", "Creates and broadcasts a new datatype")
-	APIEndpointsPostNewIdentity                 = ffm("api.endpoints.postNewIdentity", "Registers a new identity in the network")
-	APIEndpointsPostNewMessageBroadcast         = ffm("api.endpoints.postNewMessageBroadcast", "Broadcasts a message to all members in the network")
-	APIEndpointsPostNewMessagePrivate           = ffm("api.endpoints.postNewMessagePrivate", "Privately sends a message to one or more members in the network")
-	APIEndpointsPostNewMessageRequestReply      = ffm("api.endpoints.postNewMessageRequestReply", "Sends a message with a blocking HTTP request, waits for a reply to that message, then sends the reply as the HTTP response.")
-	APIEndpointsPostNewNamespace                = ffm("api.endpoints.postNewNamespace", "Creates and broadcasts a new namespace")
-	APIEndpointsPostNodesSelf                   = ffm("api.endpoints.postNodesSelf", "Instructs this FireFly node to register itself on the network")
-	APIEndpointsPostNewOrganizationSelf         = ffm("api.endpoints.postNewOrganizationSelf", "Instructs this FireFly node to register its org on the network")
-	APIEndpointsPostNewOrganization             = ffm("api.endpoints.postNewOrganization", "Registers a new org in the network")
-	APIEndpointsPostNewSubscription             = ffm("api.endpoints.postNewSubscription", "Creates a new subscription for an application to receive events from FireFly")
-	APIEndpointsPostOpRetry                     = ffm("api.endpoints.postOpRetry", "Retries a failed operation")
-	APIEndpointsPostPinsRewind                  = ffm("api.endpoints.postPinsRewind", "Force a rewind of the event aggregator to a previous position, to re-evaluate (and possibly dispatch) that pin and others after it. Only accepts a sequence or batch ID for a currently undispatched pin")
-	APIEndpointsPostTokenApproval               = ffm("api.endpoints.postTokenApproval", "Creates a token approval")
-	APIEndpointsPostTokenBurn                   = ffm("api.endpoints.postTokenBurn", "Burns some tokens")
-	APIEndpointsPostTokenMint                   = ffm("api.endpoints.postTokenMint", "Mints some tokens")
-	APIEndpointsPostTokenPool                   = ffm("api.endpoints.postTokenPool", "Creates a new token pool")
-	APIEndpointsPostTokenPoolPublish            = ffm("api.endpoints.postTokenPoolPublish", "Publish a token pool to all other members of the multiparty network")
-	APIEndpointsPostTokenTransfer               = ffm("api.endpoints.postTokenTransfer", "Transfers some tokens")
-	APIEndpointsPutContractAPI                  = ffm("api.endpoints.putContractAPI", "Updates an existing contract API")
-	APIEndpointsPutSubscription                 = ffm("api.endpoints.putSubscription", "Update an existing subscription")
-	APIEndpointsGetContractAPIInterface         = ffm("api.endpoints.getContractAPIInterface", "Gets a contract interface for a contract API")
-	APIEndpointsPostNetworkAction               = ffm("api.endpoints.postNetworkAction", "Notify all nodes in the network of a new governance action")
-	APIEndpointsPostVerifiersResolve            = ffm("api.endpoints.postVerifiersResolve", "Resolves an input key to a signing key")
+	APIEndpointsDeleteContractAPI                = ffm("api.endpoints.deleteContractAPI", "Delete a contract API")
+	APIEndpointsDeleteContractInterface          = ffm("api.endpoints.deleteContractInterface", "Delete a contract interface")
+	APIEndpointsDeleteContractListener           = ffm("api.endpoints.deleteContractListener", "Deletes a contract listener referenced by its name or its ID")
+	APIEndpointsDeleteContractAPIListeners       = ffm("api.endpoints.deleteContractAPIListeners", "Deletes the contract listeners on an event of a contract API that match the filter, deregistering them from the blockchain connector. Fails if any of them are in use by a subscription")
+	APIEndpointsPatchContractAPIListener         = ffm("api.endpoints.patchContractAPIListener", "Updates the name or options of a contract listener on an event of a contract API without recreating it. Changes to output filters only are applied by FireFly, and other changed options are also applied on the blockchain connector")
+	APIEndpointsDeleteBridge                     = ffm("api.endpoints.deleteBridge", "Deletes a bridge, and the durable subscription that backs it")
+	APIEndpointsDeleteSubscription               = ffm("api.endpoints.deleteSubscription", "Deletes a subscription")
+	APIEndpointsDeleteTokenPool                  = ffm("api.endpoints.deleteTokenPool", "Delete a token pool")
+	APIEndpointsGetBatchBbyID                    = ffm("api.endpoints.getBatchByID", "Gets a message batch")
+	APIEndpointsGetBatches                       = ffm("api.endpoints.getBatches", "Gets a list of message batches")
+	APIEndpointsGetBlockchainEventByID           = ffm("api.endpoints.getBlockchainEventByID", "Gets a blockchain event")
+	APIEndpointsGetBlockchainEventOutput         = ffm("api.endpoints.getBlockchainEventOutput", "Gets the full output of a blockchain event, including any fields not stored on the event due to its size")
+	APIEndpointsListBlockchainEvents             = ffm("api.endpoints.getBlockchainEvents", "Gets a list of blockchain events")
+	APIEndpointsGetChartHistogram                = ffm("api.endpoints.getChartHistogram", "Gets a JSON object containing statistics data that can be used to build a graphical representation of recent activity in a given database collection")
+	APIEndpointsGetChartTimeSeries               = ffm("api.endpoints.getChartTimeSeries", "Gets the count of records in a database collection in each interval of a time range, optionally grouped by the value of a field")
+	APIEndpointsGetContractAPIByName             = ffm("api.endpoints.getContractAPIByName", "Gets information about a contract API, including the URLs for the OpenAPI Spec and Swagger UI for the API")
+	APIEndpointsGetContractAPIs                  = ffm("api.endpoints.getContractAPIs", "Gets a list of contract APIs that have been published")
+	APIEndpointsGetContractInterfaceNameVersion  = ffm("api.endpoints.getContractInterfaceNameVersion", "Gets a contract interface by its name and version")
+	APIEndpointsGetContractInterfaceLifecycle    = ffm("api.endpoints.getContractInterfaceLifecycle", "Gets the lifecycle state of a version of a contract interface")
+	APIEndpointsGetContractInterfaceDiff         = ffm("api.endpoints.getContractInterfaceDiff", "Compares the methods, events and errors of two versions of a contract interface")
+	APIEndpointsPatchContractInterfaceLifecycle  = ffm("api.endpoints.patchContractInterfaceLifecycle", "Marks a version of a contract interface as active, deprecated or retired on this node")
+	APIEndpointsGetContractInterfaceSelector     = ffm("api.endpoints.getContractInterfaceSelector", "Gets the on-chain signature and selector of a method in a contract interface")
+	APIEndpointsGetContractInterface             = ffm("api.endpoints.getContractInterface", "Gets a contract interface by its ID")
+	APIEndpointsGetContractInterfaces            = ffm("api.endpoints.getContractInterfaces", "Gets a list of contract interfaces that have been published")
+	APIEndpointsGetContractListenerByNameOrID    = ffm("api.endpoints.getContractListenerByNameOrID", "Gets a contract listener by its name or ID")
+	APIEndpointsGetContractListeners             = ffm("api.endpoints.getContractListeners", "Gets a list of contract listeners")
+	APIEndpointsGetContractAPIListenerEvents     = ffm("api.endpoints.getContractAPIListenerEvents", "Gets the blockchain events delivered by the listeners on an event of a contract API, sorted by block number then log index. Use blocknumber and timestamp filters with the [ modifier to select a range, such as blocknumber=[>=100&blocknumber=[<200")
+	APIEndpointsGetContractListenersIdle         = ffm("api.endpoints.getContractListenersIdle", "Gets the contract listeners that have not delivered any blockchain events since they were created")
+	APIEndpointsGetAllContractAPIListeners       = ffm("api.endpoints.getAllContractAPIListeners", "Gets the contract listeners on all the events of a contract API")
+	APIEndpointsGetContractAPIListenersHealth    = ffm("api.endpoints.getContractAPIListenersHealth", "Gets a summary of the health of all the listeners on the events of a contract API")
+	APIEndpointsGetDataBlob                      = ffm("api.endpoints.getDataBlob", "Downloads the original file that was previously uploaded or received. Supports a single byte range in the Range header, to resume an interrupted download")
+	APIEndpointsGetDataValue                     = ffm("api.endpoints.getDataValue", "Downloads the JSON value of the data resource, without the associated metadata")
+	APIEndpointsGetDataByID                      = ffm("api.endpoints.getDataByID", "Gets a data item by its ID, including metadata about this item")
+	APIEndpointsDeleteData                       = ffm("api.endpoints.deleteData", "Deletes a data item by its ID, including metadata about this item")
+	APIEndpointsPostDataUpload                   = ffm("api.endpoints.postDataUpload", "Starts a resumable upload of a large blob, which is then sent in chunks")
+	APIEndpointsGetDataUpload                    = ffm("api.endpoints.getDataUpload", "Gets a resumable blob upload, including the number of bytes received so far to resume from")
+	APIEndpointsPatchDataUpload                  = ffm("api.endpoints.patchDataUpload", "Appends a chunk of the blob to a resumable upload, sent as a multipart/form-data file")
+	APIEndpointsPostDataUploadComplete           = ffm("api.endpoints.postDataUploadComplete", "Completes a resumable blob upload, storing the blob and creating the data item")
+	APIEndpointsDeleteDataUpload                 = ffm("api.endpoints.deleteDataUpload", "Aborts a resumable blob upload, discarding the chunks received so far")
+	APIEndpointsGetDataMsgs                      = ffm("api.endpoints.getDataMsgs", "Gets a list of the messages associated with a data item")
+	APIEndpointsGetData                          = ffm("api.endpoints.getData", "Gets a list of data items")
+	APIEndpointsGetDataSubPaths                  = ffm("api.endpoints.getDataSubPaths", "Gets a list of path names of named blob data, underneath a given parent path ('/' path prefixes are automatically pre-prepended)")
+	APIEndpointsGetDatatypeByName                = ffm("api.endpoints.getDatatypeByName", "Gets a datatype by its name and version")
+	APIEndpointsGetDatatypes                     = ffm("api.endpoints.getDatatypes", "Gets a list of datatypes that have been published")
+	APIEndpointsGetDatatypeDiff                  = ffm("api.endpoints.getDatatypeDiff", "Compares the schemas of two versions of a datatype")
+	APIEndpointsPatchDatatypeLifecycle           = ffm("api.endpoints.patchDatatypeLifecycle", "Marks a version of a datatype as active, deprecated or retired on this node")
+	APIEndpointsGetEventByID                     = ffm("api.endpoints.eventID", "Gets an event by its ID")
+	APIEndpointsGetEvents                        = ffm("api.endpoints.getEvents", "Gets a list of events")
+	APIEndpointsGetGroupByHash                   = ffm("api.endpoints.getGroupByHash", "Gets a group by its ID (hash)")
+	APIEndpointsGetGroups                        = ffm("api.endpoints.getGroups", "Gets a list of groups")
+	APIEndpointsPostNewGroup                     = ffm("api.endpoints.postNewGroup", "Creates a privacy group explicitly, before any messages are sent to it")
+	APIEndpointsPostGroupMembers                 = ffm("api.endpoints.postGroupMembers", "Adds and removes members of a group, creating a new generation of the group. Once the new generation is confirmed, messages sent to any previous generation are sent to the new generation")
+	APIEndpointsGetIdentities                    = ffm("api.endpoints.getIdentities", "Gets a list of all identities that have been registered in the namespace")
+	APIEndpointsGetIdentityByID                  = ffm("api.endpoints.getIdentityByID", "Gets an identity by its ID")
+	APIEndpointsGetIdentityDID                   = ffm("api.endpoints.getIdentityDID", "Gets the DID for an identity based on its ID")
+	APIEndpointsGetIdentityVerifierHistory       = ffm("api.endpoints.getIdentityVerifierHistory", "Gets the history of verifiers claimed by an identity, with the message and blockchain transaction that established each one")
+	APIEndpointsGetIdentityVerifiers             = ffm("api.endpoints.getIdentityVerifiers", "Gets the verifiers for an identity")
+	APIEndpointsGetMsgByID                       = ffm("api.endpoints.getMsgByID", "Gets a message by its ID")
+	APIEndpointsGetMsgData                       = ffm("api.endpoints.getMsgData", "Gets the list of data items that are attached to a message")
+	APIEndpointsGetMsgEvents                     = ffm("api.endpoints.getMsgEvents", "Gets the list of events for a message")
+	APIEndpointsGetMsgAcks                       = ffm("api.endpoints.getMsgAcks", "Gets the delivery and processing acknowledgements received from the recipients of a private message")
+	APIEndpointsGetMsgTxn                        = ffm("api.endpoints.getMsgTxn", "Gets the transaction for a message")
+	APIEndpointsGetMsgProof                      = ffm("api.endpoints.getMsgProof", "Gets a bundle proving a message was included in its batch, with the hashes and the blockchain pin event needed to verify it independently of this node")
+	APIEndpointsGetMsgs                          = ffm("api.endpoints.getMsgs", "Gets a list of messages")
+	APIEndpointsGetMsgsExport                    = ffm("api.endpoints.getMsgsExport", "Exports every message matching the filter as a stream, in the order the messages were written locally. Sort, skip and limit are ignored, so the whole result can be exported in a single request")
+	APIEndpointsGetMsgsScheduled                 = ffm("api.endpoints.getMsgsScheduled", "Gets a list of the messages that are waiting for their sendAfter time, before being batched and sent")
+	APIEndpointsGetNamespace                     = ffm("api.endpoints.getNamespace", "Gets a namespace")
+	APIEndpointsGetNamespaces                    = ffm("api.endpoints.getNamespaces", "Gets a list of namespaces")
+	APIEndpointsGetNetworkIdentityByDID          = ffm("api.endpoints.getNetworkIdentityByDID", "Gets an identity by its DID (deprecated - use /identities/{did} instead of /network/identities/{did})")
+	APIEndpointsGetIdentityByDID                 = ffm("api.endpoints.getIdentityByDID", "Gets an identity by its DID")
+	APIEndpointsGetDIDDocByDID                   = ffm("api.endpoints.getDIDDocByDID", "Gets a DID document by its DID")
+	APIEndpointsResolveDIDDoc                    = ffm("api.endpoints.resolveDIDDoc", "Resolves a FireFly DID to its DID document. Legacy custom identity DIDs of the form did:firefly:ns/{ns}/{name} are resolved in the namespace they name, all other DIDs in the default namespace")
+	APIEndpointsPostVerifyDIDDoc                 = ffm("api.endpoints.postVerifyDIDDoc", "Verifies each verification method in a DID document against the confirmed claim of the identity that owns the DID")
+	APIEndpointsPostResolveDIDDocs               = ffm("api.endpoints.postResolveDIDDocs", "Resolves the DID documents for an array of identity UUIDs and/or DIDs in a single call, returning a map from each input to its document, or the error resolving it")
+	APIEndpointsGetNetworkIdentities             = ffm("api.endpoints.getNetworkIdentities", "Gets the list of identities in the network (deprecated - use /identities instead of /network/identities")
+	APIEndpointsGetNetworkNode                   = ffm("api.endpoints.getNetworkNode", "Gets information about a specific node in the network")
+	APIEndpointsGetNetworkNodes                  = ffm("api.endpoints.getNetworkNodes", "Gets a list of nodes in the network")
+	APIEndpointsGetNetworkOrg                    = ffm("api.endpoints.getNetworkOrg", "Gets information about a specific org in the network")
+	APIEndpointsGetNetworkOrgs                   = ffm("api.endpoints.APIEndpointsGetNetworkOrgs", "Gets a list of orgs in the network")
+	APIEndpointsGetOpProgress                    = ffm("api.endpoints.getOpProgress", "Gets the progress of a data exchange blob transfer operation, with the average rate it has been sent at")
+	APIEndpointsGetOpRetries                     = ffm("api.endpoints.getOpRetries", "Gets the chain of operations linked to an operation by retries, from the original operation to the latest retry. If an operation was retried more than once, each of its retries follows it in the order they were created")
+	APIEndpointsGetOpByID                        = ffm("api.endpoints.getOpByID", "Gets an operation by ID")
+	APIEndpointsGetOps                           = ffm("api.endpoints.getOps", "Gets a a list of operations")
+	APIEndpointsGetStatusBatchManager            = ffm("api.endpoints.getStatusBatchManager", "Gets the status of the batch manager")
+	APIEndpointsGetStatusBatchManagerConfig      = ffm("api.endpoints.getStatusBatchManagerConfig", "Gets the batch assembly config currently in use by each batch dispatcher")
+	APIEndpointsGetStatusAggregator              = ffm("api.endpoints.getStatusAggregator", "Gets the load on each of the event aggregator workers")
+	APIEndpointsGetStatusHA                      = ffm("api.endpoints.getStatusHA", "Gets the high availability mode of this node, and the node that is active")
+	APIEndpointsGetStatusErrors                  = ffm("api.endpoints.getStatusErrors", "Gets a summary of recent failures across operations, subscription deliveries and blockchain indexing")
+	APIEndpointsGetStatusRetention               = ffm("api.endpoints.getStatusRetention", "Gets the data retention configuration of the namespace, and the results of the pruning runs since startup")
+	APIEndpointsGetStatusReady                   = ffm("api.endpoints.getStatusReady", "Checks that each plugin of the namespace can be reached, returning 503 if any critical plugin is down")
+	APIEndpointsGetPins                          = ffm("api.endpoints.getPins", "Queries the list of pins received from the blockchain")
+	APIEndpointsGetNextPins                      = ffm("api.endpoints.getNextPins", "Queries the list of next-pins that determine the next masked message sequence for each member of a privacy group, on each context/topic")
+	APIEndpointsGetWebSockets                    = ffm("api.endpoints.getStatusWebSockets", "Gets a list of the current WebSocket connections to this node")
+	APIEndpointsGetStatus                        = ffm("api.endpoints.getStatus", "Gets the status of this namespace")
+	APIEndpointsGetPluginsStatus                 = ffm("api.endpoints.getPluginsStatus", "Gets the plugins of this namespace, including the health and failover history of the blockchain connector endpoints")
+	APIEndpointsGetMultipartyStatus              = ffm("api.endpoints.getMultipartyStatus", "Gets the registration status of this organization and node on the configured multiparty network")
+	APIEndpointsGetBridgeByNameOrID              = ffm("api.endpoints.getBridgeByNameOrID", "Gets a bridge by its name or ID")
+	APIEndpointsGetBridges                       = ffm("api.endpoints.getBridges", "Gets a list of bridges")
+	APIEndpointsGetSubscriptionByID              = ffm("api.endpoints.getSubscriptionByID", "Gets a subscription by its ID")
+	APIEndpointsGetSubscriptionDeadLetters       = ffm("api.endpoints.getSubscriptionDeadLetters", "Gets the events that could not be delivered to a subscription, and were moved to its dead-letter queue")
+	APIEndpointsPostSubscriptionDeadLetterReplay = ffm("api.endpoints.postSubscriptionDeadLetterReplay", "Attempts to deliver an event from the dead-letter queue of a subscription again, removing it from the queue if successful")
+	APIEndpointsPostSubscriptionPreview          = ffm("api.endpoints.postSubscriptionPreview", "Tests a subscription definition against the most recent events, and returns the events that it would have matched, without creating the subscription")
+	APIEndpointsPostSubscriptionReplay           = ffm("api.endpoints.postSubscriptionReplay", "Rewinds a subscription to an event sequence or timestamp, so all events from that point are delivered again. Connected applications receive a subscription_replay event first")
+	APIEndpointsPostGraphQL                      = ffm("api.endpoints.postGraphQL", "Executes a read-only GraphQL query over the messages, transactions and token transfers in the namespace, including their related data, operations, events and token pools")
+	APIEndpointsGetSubscriptionEventsFiltered    = ffm("api.endpoints.getSubscriptionEventsFiltered", "Gets a collection of events filtered by the subscription for further filtering")
+	APIEndpointsPostSubscriptionEventStreamAck   = ffm("api.endpoints.postSubscriptionEventStreamAck", "Acknowledges an event received on the server-sent events stream of a subscription, so the next event can be delivered")
+	APIEndpointsGetSubscriptions                 = ffm("api.endpoints.getSubscriptions", "Gets a list of subscriptions")
+	APIEndpointsGetTokenAccountPools             = ffm("api.endpoints.getTokenAccountPools", "Gets a list of token pools that contain a given token account key")
+	APIEndpointsGetTokenAccounts                 = ffm("api.endpoints.getTokenAccounts", "Gets a list of token accounts")
+	APIEndpointsGetTokenApprovals                = ffm("api.endpoints.getTokenApprovals", "Gets a list of token approvals")
+	APIEndpointsGetTokenBalances                 = ffm("api.endpoints.getTokenBalances", "Gets a list of token balances")
+	APIEndpointsGetToken                         = ffm("api.endpoints.getToken", "Gets a single token in a non-fungible token pool, with its metadata resolved from the token URI")
+	APIEndpointsGetTokenConnectors               = ffm("api.endpoints.getTokenConnectors", "Gets the list of token connectors currently in use")
+	APIEndpointsGetTokenPoolByNameOrID           = ffm("api.endpoints.getTokenPoolByNameOrID", "Gets a token pool by its name or its ID")
+	APIEndpointsGetTokenPools                    = ffm("api.endpoints.getTokenPools", "Gets a list of token pools")
+	APIEndpointsGetTokenTransferByID             = ffm("api.endpoints.getTokenTransferByID", "Gets a token transfer by its ID")
+	APIEndpointsGetTokenTransfers                = ffm("api.endpoints.getTokenTransfers", "Gets a list of token transfers")
+	APIEndpointsGetTxnBlockchainEvents           = ffm("api.endpoints.getTxnBlockchainEvents", "Gets a list blockchain events for a specific transaction")
+	APIEndpointsGetTxnByID                       = ffm("api.endpoints.getTxnByID", "Gets a transaction by its ID")
+	APIEndpointsGetTxnOps                        = ffm("api.endpoints.getTxnOps", "Gets a list of operations in a specific transaction")
+	APIEndpointsGetTxnStatus                     = ffm("api.endpoints.getTxnStatus", "Gets the status of a transaction, composed from its operations, blockchain events, batch and pins, and token pools, transfers or approvals, with an overall state of Pending, Succeeded or Failed")
+	APIEndpointsGetTxns                          = ffm("api.endpoints.getTxns", "Gets a list of transactions")
+	APIEndpointsGetVerifierByHash                = ffm("api.endpoints.getVerifierByHash", "Gets a verifier by its hash")
+	APIEndpointsGetVerifiers                     = ffm("api.endpoints.getVerifiers", "Gets a list of verifiers")
+	APIEndpointsPatchUpdateIdentity              = ffm("api.endpoints.patchUpdateIdentity", "Updates an identity")
+	APIEndpointsPostBatchCancel                  = ffm("api.endpoints.postBatchCancel", "Cancel a batch that has failed to dispatch")
+	APIEndpointsPostMsgCancel                    = ffm("api.endpoints.postMsgCancel", "Cancel a scheduled message before its sendAfter time, so it is never sent")
+	APIEndpointsPatchStatusBatchManagerConfig    = ffm("api.endpoints.patchStatusBatchManagerConfig", "Updates the batch size, payload limit, timeout and adaptive sizing of one or all batch dispatchers. Changes apply immediately to in-flight batches, and last until the namespace is restarted")
+	APIEndpointsPostStatusBatchManagerFlush      = ffm("api.endpoints.postStatusBatchManagerFlush", "Forces all active batch processors to seal and dispatch their in-flight batches, returning the IDs of the batches flushed")
+	APIEndpointsPostStatusBatchManagerRestart    = ffm("api.endpoints.postStatusBatchManagerRestart", "Stops all batch processors and restarts batch assembly from the messages that are ready in the database. Processors are given until the request timeout to finish any dispatch in progress, before they are cancelled")
+	APIEndpointsPostContractDeploy               = ffm("api.endpoints.postContractDeploy", "Deploy a new smart contract")
+	APIEndpointsPostContractAPIInvoke            = ffm("api.endpoints.postContractAPIInvoke", "Invokes a method on a smart contract API. Performs a blockchain transaction.")
+	APIEndpointsPostContractAPIPublish           = ffm("api.endpoints.postContractAPIPublish", "Publish a contract API to all other members of the multiparty network")
+	APIEndpointsPostContractAPIQuery             = ffm("api.endpoints.postContractAPIQuery", "Queries a method on a smart contract API. Performs a read-only query.")
+	APIEndpointsPostContractInterfaceGenerate    = ffm("api.endpoints.postContractInterfaceGenerate", "A convenience method to convert a blockchain specific smart contract format into a FireFly Interface format. The specific blockchain plugin in use must support this functionality.")
+	APIEndpointsPostContractInterfaceInvoke      = ffm("api.endpoints.postContractInterfaceInvoke", "Invokes a method on a smart contract that matches a given contract interface. Performs a blockchain transaction.")
+	APIEndpointsPostContractInterfaceQuery       = ffm("api.endpoints.postContractInterfaceQuery", "Queries a method on a smart contract that matches a given contract interface. Performs a read-only query.")
+	APIEndpointsPostContractInterfacePublish     = ffm("api.endpoints.postContractInterfacePublish", "Publish a contract interface to all other members of the multiparty network")
+	APIEndpointsPostContractInvoke               = ffm("api.endpoints.postContractInvoke", "Invokes a method on a smart contract. Performs a blockchain transaction.")
+	APIEndpointsPostContractQuery                = ffm("api.endpoints.postContractQuery", "Queries a method on a smart contract. Performs a read-only query.")
+	APIEndpointsPostData                         = ffm("api.endpoints.postData", "Creates a new data item in this FireFly node")
+	APIEndpointsPostDataAsync                    = ffm("api.endpoints.postDataAsync", "Uploads a blob, returning immediately with an upload that tracks the hashing and storage of the blob. A data_upload_completed event is emitted when the data item is ready")
+	APIEndpointsPostDataValuePublish             = ffm("api.endpoints.postDataValuePublish", "Publishes the JSON value from the specified data resource, to shared storage")
+	APIEndpointsPostDataBlobPublish              = ffm("api.endpoints.postDataBlobPublish", "Publishes the binary blob attachment stored in your local data exchange, to shared storage")
+	APIEndpointsPostNewContractAPI               = ffm("api.endpoints.postNewContractAPI", "Creates and broadcasts a new custom smart contract API")
+	APIEndpointsPostNewContractInterface         = ffm("api.endpoints.postNewContractInterface", "Creates and broadcasts a new custom smart contract interface")
+	APIEndpointsPostNewContractListener          = ffm("api.endpoints.postNewContractListener", "Creates a new blockchain listener for events emitted by custom smart contracts")
+	APIEndpointsPostContractListenerHash         = ffm("api.endpoints.postContractListenerHash", "Calculates the hash of a blockchain listener filters and events")
+	APIEndpointsPostNewDatatype                  = ffm("api.endpoints.postNewDatatype", "Creates and broadcasts a new datatype")
+	APIEndpointsPostNewIdentity                  = ffm("api.endpoints.postNewIdentity", "Registers a new identity in the network")
+	APIEndpointsPostIdentityVerifiers            = ffm("api.endpoints.postIdentityVerifiers", "Claims an additional blockchain signing key for an identity, signed by an existing key of the identity, optionally deprecating its existing keys")
+	APIEndpointsPostNewMessageBroadcast          = ffm("api.endpoints.postNewMessageBroadcast", "Broadcasts a message to all members in the network")
+	APIEndpointsPostNewMessageBroadcastBatch     = ffm("api.endpoints.postNewMessageBroadcastBatch", "Broadcasts a batch of messages in a single call, returning the outcome of each message in the order they were supplied")
+	APIEndpointsPostPreviewBroadcastBatch        = ffm("api.endpoints.postPreviewBroadcastBatch", "Previews the batch a broadcast message would be assigned to if submitted now, with its fill level and estimated time to seal. Does not submit the message")
+	APIEndpointsPostNewMessagePrivate            = ffm("api.endpoints.postNewMessagePrivate", "Privately sends a message to one or more members in the network")
+	APIEndpointsPostNewMessagePrivateBatch       = ffm("api.endpoints.postNewMessagePrivateBatch", "Privately sends a batch of messages in a single call, returning the outcome of each message in the order they were supplied")
+	APIEndpointsPostNewMessageRequestReply       = ffm("api.endpoints.postNewMessageRequestReply", "Sends a message with a blocking HTTP request, waits for a reply to that message, then sends the reply as the HTTP response.")
+	APIEndpointsPostNewNamespace                 = ffm("api.endpoints.postNewNamespace", "Creates and broadcasts a new namespace")
+	APIEndpointsPostNodesSelf                    = ffm("api.endpoints.postNodesSelf", "Instructs this FireFly node to register itself on the network")
+	APIEndpointsPostNewOrganizationSelf          = ffm("api.endpoints.postNewOrganizationSelf", "Instructs this FireFly node to register its org on the network")
+	APIEndpointsPostNewOrganization              = ffm("api.endpoints.postNewOrganization", "Registers a new org in the network")
+	APIEndpointsPostNewBridge                    = ffm("api.endpoints.postNewBridge", "Creates a bridge, that re-broadcasts the confirmed broadcast messages of this namespace matching a topic filter into another namespace on this node")
+	APIEndpointsPostNewSubscription              = ffm("api.endpoints.postNewSubscription", "Creates a new subscription for an application to receive events from FireFly")
+	APIEndpointsPostOpRetry                      = ffm("api.endpoints.postOpRetry", "Retries a failed operation")
+	APIEndpointsPostOpNotify                     = ffm("api.endpoints.postOpNotify", "Registers a one-shot webhook that is called with the operation when it reaches a terminal state. A failure that will be retried automatically is not terminal, and the webhook of a retried operation is called with the outcome of the latest retry. Registrations are stored, so survive a restart - except those signed with an inline secret, which is only held in memory")
+	APIEndpointsPostOpsRetry                     = ffm("api.endpoints.postOpsRetry", "Retries a list of failed operations, or all failed operations matching the filter, reporting the outcome for each")
+	APIEndpointsPostPinsRewindFromSequence       = ffm("api.endpoints.postPinsRewindFromSequence", "Force a rewind of the event aggregator to re-evaluate all undispatched pins from the given sequence onwards, returning the batches and messages that are re-evaluated")
+	APIEndpointsPostPinsRewindBatches            = ffm("api.endpoints.postPinsRewindBatches", "Force a rewind of the event aggregator to re-evaluate the undispatched pins of a list of batches, returning the batches and messages that are re-evaluated")
+	APIEndpointsPostPinsRewind                   = ffm("api.endpoints.postPinsRewind", "Force a rewind of the event aggregator to a previous position, to re-evaluate (and possibly dispatch) that pin and others after it. Only accepts a sequence or batch ID for a currently undispatched pin")
+	APIEndpointsPostTokenApproval                = ffm("api.endpoints.postTokenApproval", "Creates a token approval")
+	APIEndpointsPostTokenBurn                    = ffm("api.endpoints.postTokenBurn", "Burns some tokens")
+	APIEndpointsPostTokenMint                    = ffm("api.endpoints.postTokenMint", "Mints some tokens")
+	APIEndpointsPostTokenPool                    = ffm("api.endpoints.postTokenPool", "Creates a new token pool")
+	APIEndpointsPostTokenPoolPublish             = ffm("api.endpoints.postTokenPoolPublish", "Publish a token pool to all other members of the multiparty network")
+	APIEndpointsPostTokenTransfer                = ffm("api.endpoints.postTokenTransfer", "Transfers some tokens")
+	APIEndpointsPutContractAPI                   = ffm("api.endpoints.putContractAPI", "Updates an existing contract API")
+	APIEndpointsPutSubscription                  = ffm("api.endpoints.putSubscription", "Update an existing subscription")
+	APIEndpointsGetContractAPIInterface          = ffm("api.endpoints.getContractAPIInterface", "Gets a contract interface for a contract API")
+	APIEndpointsPostNetworkAction                = ffm("api.endpoints.postNetworkAction", "Notify all nodes in the network of a new governance action")
+	APIEndpointsPostNetworkDiagnostics           = ffm("api.endpoints.postNetworkDiagnostics", "Actively probe the connectivity of this node to the network - pinging each member over data exchange, writing and reading back shared storage, and checking the blockchain connector is in sync")
+	APIEndpointsPostNetworkResync                = ffm("api.endpoints.postNetworkResync", "Rebuild the local network map by replaying all identity definitions received by this node, and report what changed")
+	APIEndpointsPostVerifiersResolve             = ffm("api.endpoints.postVerifiersResolve", "Resolves an input key to a signing key")
 
-	APIFilterParamDesc         = ffm("api.filterParam", "Data filter field. Prefixes supported: > >= < <= @ ^ ! !@ !^")
-	APIFilterSortDesc          = ffm("api.filterSort", "Sort field. For multi-field sort use comma separated values (or multiple query values) with '-' prefix for descending")
-	APIFilterAscendingDesc     = ffm("api.filterAscending", "Ascending sort order (overrides all fields in a multi-field sort)")
-	APIFilterDescendingDesc    = ffm("api.filterDescending", "Descending sort order (overrides all fields in a multi-field sort)")
-	APIFilterSkipDesc          = ffm("api.filterSkip", "The number of records to skip (max: %d). Unsuitable for bulk operations")
-	APIFilterLimitDesc         = ffm("api.filterLimit", "The maximum number of records to return (max: %d)")
-	APIFilterCountDesc         = ffm("api.filterCount", "Return a total count as well as items (adds extra database processing)")
-	APIFetchDataDesc           = ffm("api.fetchData", "Fetch the data and include it in the messages returned")
-	APIConfirmMsgQueryParam    = ffm("api.confirmMsgQueryParam", "When true the HTTP request blocks until the message is confirmed")
-	APIConfirmInvokeQueryParam = ffm("api.confirmInvokeQueryParam", "When true the HTTP request blocks until the blockchain transaction is confirmed")
-	APIPublishQueryParam       = ffm("api.publishQueryParam", "When true the definition will be published to all other members of the multiparty network")
-	APIHistogramStartTimeParam = ffm("api.histogramStartTime", "Start time of the data to be fetched")
-	APIHistogramEndTimeParam   = ffm("api.histogramEndTime", "End time of the data to be fetched")
-	APIHistogramBucketsParam   = ffm("api.histogramBuckets", "Number of buckets between start time and end time")
+	APIFilterParamDesc          = ffm("api.filterParam", "Data filter field. Prefixes supported: > >= < <= @ ^ ! !@ !^")
+	APIFilterSortDesc           = ffm("api.filterSort", "Sort field. For multi-field sort use comma separated values (or multiple query values) with '-' prefix for descending")
+	APIFilterAscendingDesc      = ffm("api.filterAscending", "Ascending sort order (overrides all fields in a multi-field sort)")
+	APIFilterDescendingDesc     = ffm("api.filterDescending", "Descending sort order (overrides all fields in a multi-field sort)")
+	APIFilterSkipDesc           = ffm("api.filterSkip", "The number of records to skip (max: %d). Unsuitable for bulk operations")
+	APIFilterLimitDesc          = ffm("api.filterLimit", "The maximum number of records to return (max: %d)")
+	APIFilterCountDesc          = ffm("api.filterCount", "Return a total count as well as items (adds extra database processing)")
+	APIFetchDataDesc            = ffm("api.fetchData", "Fetch the data and include it in the messages returned")
+	APIConfirmMsgQueryParam     = ffm("api.confirmMsgQueryParam", "When true the HTTP request blocks until the message is confirmed")
+	APIConfirmInvokeQueryParam  = ffm("api.confirmInvokeQueryParam", "When true the HTTP request blocks until the blockchain transaction is confirmed")
+	APISimulateQueryParam       = ffm("api.simulateQueryParam", "When true the request is evaluated by the connector and the outcome returned, without creating a transaction or submitting it to the blockchain")
+	APIConfirmTimeoutQueryParam = ffm("api.confirmTimeoutQueryParam", "Maximum time to block waiting for confirmation, such as '30s'. Implies confirm=true. Bounded by the overall request timeout")
+	APIPublishQueryParam        = ffm("api.publishQueryParam", "When true the definition will be published to all other members of the multiparty network")
+	APIFFIGenerateFormatParam   = ffm("api.ffiGenerateFormat", "The format of the input. 'native' (default) is the blockchain specific interface format, such as an Ethereum ABI. 'solidity' is Solidity source, or the standard JSON output of the Solidity compiler, along with the contract name")
+	APIErrorReportWindowParam   = ffm("api.errorReportWindow", "How far back to report on errors, such as '30m' or '24h'. Defaults to '1h'")
+	APIIdleListenerMinAgeParam  = ffm("api.idleListenerMinAge", "Only include listeners created at least this long ago, such as '1h' or '7d', to exclude listeners that are new")
+	APIListenerHealthWindow     = ffm("api.listenerHealthWindow", "How recently a listener must have delivered an event to count as recently fired, such as '30m' or '24h'. Defaults to '1h'")
+	APIHistogramStartTimeParam  = ffm("api.histogramStartTime", "Start time of the data to be fetched")
+	APIHistogramEndTimeParam    = ffm("api.histogramEndTime", "End time of the data to be fetched")
+	APIHistogramBucketsParam    = ffm("api.histogramBuckets", "Number of buckets between start time and end time")
+	APITimeSeriesIntervalParam  = ffm("api.timeSeriesInterval", "The width of each bucket between start time and end time, such as '5m' or '1h'. Time series with an interval that is a whole number of hours, and start and end times on the hour, are served from pre-aggregated counts")
+	APITimeSeriesGroupByParam   = ffm("api.timeSeriesGroupBy", "A string field of the collection to count each value of separately within each bucket, such as 'type' or 'pool'")
+	APIValueMatchParam          = ffm("api.valueMatch", "Match on the JSON value of the data, such as value.order.customer==\"acme\". The value must be a JSON string, number or boolean - anything else is matched as a string. Can be specified multiple times, and all must match")
+	APIValueSearchParam         = ffm("api.valueSearch", "Search terms that must all be in the JSON value of the data. Matched as words against a full-text index if the database supports it, otherwise as a case-insensitive substring")
 
 	APISmartContractDetails      = ffm("api.smartContractDetails", "Additional smart contract details")
 	APISmartContractDetailsKey   = ffm("api.smartContractDetailsKey", "Key")
@@ -53,16 +53,48 @@ var (
 	ConfigAPIRequestMaxTimeout  = ffc("config.api.requestMaxTimeout", "The maximum amount of time that an HTTP client can specify in a `Request-Timeout` header to keep a specific request open", i18n.TimeDurationType)
 	ConfigAPIPassthroughHeaders = ffc("config.api.passthroughHeaders", "A list of HTTP request headers to pass through to dependency microservices", i18n.ArrayStringType)
 
-	ConfigAssetManagerKeyNormalization = ffc("config.asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization (deprecated - use namespaces.predefined[].asset.manager.keyNormalization)", i18n.StringType)
-
-	ConfigBatchManagerMinimumPollDelay = ffc("config.batch.manager.minimumPollDelay", "The minimum time the batch manager waits between polls on the DB - to prevent thrashing", i18n.TimeDurationType)
-	ConfigBatchManagerPollTimeout      = ffc("config.batch.manager.pollTimeout", "How long to wait without any notifications of new messages before doing a page query", i18n.TimeDurationType)
-	ConfigBatchManagerReadPageSize     = ffc("config.batch.manager.readPageSize", "The size of each page of messages read from the database into memory when assembling batches", i18n.IntType)
+	ConfigAPIRateLimitContractListenersRequestsPerSecond = ffc("config.api.rateLimit.contractListeners.requestsPerSecond", "The number of requests per second each caller can make to the contract listener query APIs. Callers are identified by the identity the auth plugin authenticated, or by their address when there is none. Set to 0 to disable the limit", i18n.FloatType)
+	ConfigAPIRateLimitContractListenersBurst             = ffc("config.api.rateLimit.contractListeners.burst", "The number of requests to the contract listener query APIs that each caller can make in a burst, above the configured rate", i18n.IntType)
+	ConfigAPIRateLimitNamespaceRequestsPerSecond         = ffc("config.api.rateLimit.namespace.requestsPerSecond", "The number of requests per second each namespace can receive, shared by all the callers of the namespace. Set to 0 to disable the limit", i18n.FloatType)
+	ConfigAPIRateLimitNamespaceBurst                     = ffc("config.api.rateLimit.namespace.burst", "The number of requests each namespace can receive in a burst, above the configured rate", i18n.IntType)
+	ConfigAPIRateLimitNamespaces                         = ffc("config.api.rateLimit.namespaces", "A list of namespaces with their own request rate limit, overriding api.rateLimit.namespace", i18n.StringType)
+	ConfigAPIRateLimitNamespacesName                     = ffc("config.api.rateLimit.namespaces[].name", "The name of the namespace", i18n.StringType)
+	ConfigAPIRateLimitNamespacesRequestsPerSecond        = ffc("config.api.rateLimit.namespaces[].requestsPerSecond", "The number of requests per second the namespace can receive. Set to 0 to exempt the namespace from the default limit", i18n.FloatType)
+	ConfigAPIRateLimitNamespacesBurst                    = ffc("config.api.rateLimit.namespaces[].burst", "The number of requests the namespace can receive in a burst, above the configured rate", i18n.IntType)
+	ConfigAPIRateLimitRoutes                             = ffc("config.api.rateLimit.routes", "A list of rate limits for individual API routes. Each namespace has its own limit for the route, in addition to the limit for the namespace as a whole", i18n.StringType)
+	ConfigAPIRateLimitRoutesName                         = ffc("config.api.rateLimit.routes[].name", "The name of the route, such as postTokenMint, as shown in the operationId of the OpenAPI specification", i18n.StringType)
+	ConfigAPIRateLimitRoutesNamespace                    = ffc("config.api.rateLimit.routes[].namespace", "Restricts the limit to a single namespace. A limit for a namespace takes precedence over a limit for the same route with no namespace", i18n.StringType)
+	ConfigAPIRateLimitRoutesRequestsPerSecond            = ffc("config.api.rateLimit.routes[].requestsPerSecond", "The number of requests per second each namespace can make to the route. Set to 0 to disable the limit", i18n.FloatType)
+	ConfigAPIRateLimitRoutesBurst                        = ffc("config.api.rateLimit.routes[].burst", "The number of requests each namespace can make to the route in a burst, above the configured rate", i18n.IntType)
+
+	ConfigAssetManagerDelegatedTransferValidation = ffc("config.asset.manager.delegatedTransferValidation", "How transfers and burns from the balance of a key other than the signing key are validated before they are submitted. Valid options are `connector` - simulate transfers through the token connector, so the token contract checks the allowance (default). Burns cannot be simulated, and neither can transfers through a connector that does not support simulation, so these are submitted without validation, `recorded` - check transfers and burns against the approvals FireFly has recorded, or `none` - submit without validation, for the token contract to accept or reject", i18n.StringType)
+	ConfigAssetManagerKeyNormalization            = ffc("config.asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization (deprecated - use namespaces.predefined[].asset.manager.keyNormalization)", i18n.StringType)
+
+	ConfigBatchAdaptiveEnabled       = ffc("config.batch.adaptive.enabled", "Whether the batch size of each dispatcher is adjusted automatically, growing when batches are filling up and shrinking when they are dispatched part-full or are slow to flush. Can be changed at runtime through the batch manager config API", i18n.BooleanType)
+	ConfigBatchAdaptiveMinSize       = ffc("config.batch.adaptive.minSize", "The smallest batch size that adaptive sizing will shrink to", i18n.IntType)
+	ConfigBatchAdaptiveMaxSize       = ffc("config.batch.adaptive.maxSize", "The largest batch size that adaptive sizing will grow to", i18n.IntType)
+	ConfigBatchAdaptiveTargetLatency = ffc("config.batch.adaptive.targetLatency", "The average time to seal, pin and dispatch a batch, above which adaptive sizing shrinks the batch size", i18n.TimeDurationType)
+	ConfigBatchAdaptiveWindow        = ffc("config.batch.adaptive.window", "The number of batches flushed by a dispatcher between each adjustment of its batch size", i18n.IntType)
+
+	ConfigBatchManagerFlushStatsWindow     = ffc("config.batch.manager.flushStatsWindow", "The rolling window over which recent flush counts and average flush latency are reported in the batch manager status", i18n.TimeDurationType)
+	ConfigBatchManagerMinimumPollDelay     = ffc("config.batch.manager.minimumPollDelay", "The minimum time the batch manager waits between polls on the DB - to prevent thrashing", i18n.TimeDurationType)
+	ConfigBatchManagerPollTimeout          = ffc("config.batch.manager.pollTimeout", "How long to wait without any notifications of new messages before doing a page query", i18n.TimeDurationType)
+	ConfigBatchManagerReadPageSize         = ffc("config.batch.manager.readPageSize", "The size of each page of messages read from the database into memory when assembling batches", i18n.IntType)
+	ConfigBatchManagerSchedulePollInterval = ffc("config.batch.manager.schedulePollInterval", "How often to check for scheduled messages that have reached their sendAfter time, and release them to be batched and sent", i18n.TimeDurationType)
+	ConfigBatchManagerStatusInterval       = ffc("config.batch.manager.statusInterval", "The default interval at which batch manager status is pushed to websocket listeners, if they do not request one on connect", i18n.TimeDurationType)
+	ConfigBatchManagerStatusMinInterval    = ffc("config.batch.manager.statusMinInterval", "The shortest push interval a websocket listener can request for batch manager status", i18n.TimeDurationType)
 
 	ConfigBlobreceiverWorkerBatchMaxInserts = ffc("config.blobreceiver.worker.batchMaxInserts", "The maximum number of items the blob receiver worker will insert in a batch", i18n.IntType)
 	ConfigBlobreceiverWorkerBatchTimeout    = ffc("config.blobreceiver.worker.batchTimeout", "The maximum amount of the the blob receiver worker will wait", i18n.TimeDurationType)
 	ConfigBlobreceiverWorkerCount           = ffc("config.blobreceiver.worker.count", "The number of blob receiver workers", i18n.IntType)
 
+	ConfigBlobuploadAsyncWorkerCount       = ffc("config.blobupload.async.worker.count", "The number of workers hashing and transferring async uploads to data exchange", i18n.IntType)
+	ConfigBlobuploadAsyncWorkerQueueLength = ffc("config.blobupload.async.worker.queueLength", "The length of the work queue in the channel to the async upload workers - defaults to 2x the worker count", i18n.IntType)
+	ConfigBlobuploadStagingTTL             = ffc("config.blobupload.stagingTTL", "How long a resumable upload that has not received a chunk, or an async upload that has finished, is kept before it is removed from the staging directory", i18n.TimeDurationType)
+	ConfigBlobuploadStagingSweepInterval   = ffc("config.blobupload.stagingSweepInterval", "How often the staging directory is swept for uploads that have passed the stagingTTL", i18n.TimeDurationType)
+	ConfigBlobuploadStagingMaxSize         = ffc("config.blobupload.stagingMaxSize", "The maximum total size of the blobs staged for a namespace. Chunks and uploads that would exceed it are rejected", i18n.ByteSizeType)
+	ConfigBlobuploadStagingDirectory       = ffc("config.blobupload.stagingDirectory", "The local directory where the chunks of resumable blob uploads are staged until the upload is completed. Defaults to a directory under the system temporary directory. Use a persistent volume for uploads to survive restarts", i18n.StringType)
+
 	ConfigBlockchainType = ffc("config.blockchain.type", "A string defining which type of blockchain plugin to use. This tells FireFly which type of configuration to load for the rest of the `blockchain` section", i18n.StringType)
 
 	ConfigBlockchainEthereumAddressResolverAlwaysResolve         = ffc("config.blockchain.ethereum.addressResolver.alwaysResolve", "Causes the address resolver to be invoked on every API call that submits a signing key, regardless of whether the input string conforms to an 0x address. Also disables any result caching", i18n.BooleanType)
@@ -114,6 +146,8 @@ var (
 	ConfigCacheBlockchainEventTTL      = ffc("config.cache.blockchainevent.ttl", "Time to live of cached blockchain events for transactions", i18n.StringType)
 	ConfigCacheTransactionSize         = ffc("config.cache.transaction.size", "Max size of cached transactions", i18n.ByteSizeType)
 	ConfigCacheTransactionTTL          = ffc("config.cache.transaction.ttl", "Time to live of cached transactions", i18n.StringType)
+	ConfigCacheEventEnrichmentLimit    = ffc("config.cache.eventenrichment.limit", "Max number of cached enriched events, shared by all subscriptions delivering the same event", i18n.IntType)
+	ConfigCacheEventEnrichmentTTL      = ffc("config.cache.eventenrichment.ttl", "Time to live of cached enriched events", i18n.StringType)
 	ConfigCacheEventListenerTopicLimit = ffc("config.cache.eventlistenertopic.limit", "Max number of cached items for blockchain listener topics", i18n.IntType)
 	ConfigCacheEventListenerTopicTTL   = ffc("config.cache.eventlistenertopic.ttl", "Time to live of cached items for blockchain listener topics", i18n.StringType)
 	ConfigCacheGroupLimit              = ffc("config.cache.group.limit", "Max number of cached items for groups", i18n.IntType)
@@ -132,6 +166,8 @@ var (
 	ConfigCacheOperationsTTL           = ffc("config.cache.operations.ttl", "Time to live of cached items for operations", i18n.StringType)
 	ConfigCacheTokenPoolLimit          = ffc("config.cache.tokenpool.limit", "Max number of cached items for token pools", i18n.IntType)
 	ConfigCacheTokenPoolTTL            = ffc("config.cache.tokenpool.ttl", "Time to live of cached items for token pool", i18n.StringType)
+	ConfigCacheTokenMetadataLimit      = ffc("config.cache.tokenmetadata.limit", "Max number of cached items for token metadata resolved from token URIs", i18n.IntType)
+	ConfigCacheTokenMetadataTTL        = ffc("config.cache.tokenmetadata.ttl", "Time to live of cached items for token metadata resolved from token URIs", i18n.StringType)
 	ConfigCacheMethodsLimit            = ffc("config.cache.methods.limit", "Max number of cached items for schema validations on blockchain methods", i18n.IntType)
 	ConfigCacheMethodsTTL              = ffc("config.cache.methods.ttl", "Time to live of cached items for schema validations on blockchain methods", i18n.StringType)
 
@@ -145,6 +181,18 @@ var (
 	ConfigPluginDatabasePostgresMaxIdleConns    = ffc("config.plugins.database[].postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
 	ConfigPluginDatabasePostgresURL             = ffc("config.plugins.database[].postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
 
+	ConfigPluginDatabaseMysqlMaxConnIdleTime = ffc("config.plugins.database[].mysql.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigPluginDatabaseMysqlMaxConnLifetime = ffc("config.plugins.database[].mysql.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigPluginDatabaseMysqlMaxConns        = ffc("config.plugins.database[].mysql.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigPluginDatabaseMysqlMaxIdleConns    = ffc("config.plugins.database[].mysql.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigPluginDatabaseMysqlURL             = ffc("config.plugins.database[].mysql.url", "The MySQL data source name for the database, such as 'user:password@tcp(host:3306)/firefly'. Requires MySQL 8.0.13 or later", i18n.StringType)
+
+	ConfigPluginDatabaseCrdbMaxConnIdleTime = ffc("config.plugins.database[].crdb.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigPluginDatabaseCrdbMaxConnLifetime = ffc("config.plugins.database[].crdb.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigPluginDatabaseCrdbMaxConns        = ffc("config.plugins.database[].crdb.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigPluginDatabaseCrdbMaxIdleConns    = ffc("config.plugins.database[].crdb.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigPluginDatabaseCrdbURL             = ffc("config.plugins.database[].crdb.url", "The CockroachDB connection string for the database, in PostgreSQL format", i18n.StringType)
+
 	ConfigPluginDatabaseSqlite3MaxConnIdleTime = ffc("config.plugins.database[].sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
 	ConfigPluginDatabaseSqlite3MaxConnLifetime = ffc("config.plugins.database[].sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
 	ConfigPluginDatabaseSqlite3MaxConns        = ffc("config.plugins.database[].sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
@@ -181,6 +229,9 @@ var (
 	ConfigPluginBlockchainEthereumEthconnectFromBlock                   = ffc("config.plugins.blockchain[].ethereum.ethconnect.fromBlock", "The first event this FireFly instance should listen to from the BatchPin smart contract. Default=0. Only affects initial creation of the event stream", addressStringType)
 	ConfigPluginBlockchainEthereumEthconnectPrefixLong                  = ffc("config.plugins.blockchain[].ethereum.ethconnect.prefixLong", "The prefix that will be used for Ethconnect specific HTTP headers when FireFly makes requests to Ethconnect", i18n.StringType)
 	ConfigPluginBlockchainEthereumEthconnectPrefixShort                 = ffc("config.plugins.blockchain[].ethereum.ethconnect.prefixShort", "The prefix that will be used for Ethconnect specific query parameters when FireFly makes requests to Ethconnect", i18n.StringType)
+	ConfigPluginBlockchainEthereumEthconnectFailoverURLs                = ffc("config.plugins.blockchain[].ethereum.ethconnect.failover.urls", "Additional Ethconnect URLs to fail over to when the primary URL is unhealthy, in order of preference. All of the URLs must share the same event streams and listeners, such as replicas of a single Ethconnect deployment", i18n.ArrayStringType)
+	ConfigPluginBlockchainEthereumEthconnectFailoverHealthCheckInterval = ffc("config.plugins.blockchain[].ethereum.ethconnect.failover.healthCheckInterval", "How often the health of each Ethconnect URL is checked, when failover URLs are configured", i18n.TimeDurationType)
+	ConfigPluginBlockchainEthereumEthconnectFailoverHealthCheckPath     = ffc("config.plugins.blockchain[].ethereum.ethconnect.failover.healthCheckPath", "The path requested on each Ethconnect URL to check its health, when failover URLs are configured. Any 2xx response is healthy", i18n.StringType)
 	ConfigPluginBlockchainEthereumEthconnectTopic                       = ffc("config.plugins.blockchain[].ethereum.ethconnect.topic", "The websocket listen topic that the node should register on, which is important if there are multiple nodes using a single ethconnect", i18n.StringType)
 	ConfigPluginBlockchainEthereumEthconnectURL                         = ffc("config.plugins.blockchain[].ethereum.ethconnect.url", "The URL of the Ethconnect instance", urlStringType)
 	ConfigPluginBlockchainEthereumEthconnectProxyURL                    = ffc("config.plugins.blockchain[].ethereum.ethconnect.proxy.url", "Optional HTTP proxy server to use when connecting to Ethconnect", urlStringType)
@@ -217,6 +268,9 @@ var (
 	ConfigPluginBlockchainFabricFabconnectBatchTimeout                = ffc("config.plugins.blockchain[].fabric.fabconnect.batchTimeout", "The maximum amount of time to wait for a batch to complete", i18n.TimeDurationType)
 	ConfigPluginBlockchainFabricFabconnectPrefixLong                  = ffc("config.plugins.blockchain[].fabric.fabconnect.prefixLong", "The prefix that will be used for Fabconnect specific HTTP headers when FireFly makes requests to Fabconnect", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectPrefixShort                 = ffc("config.plugins.blockchain[].fabric.fabconnect.prefixShort", "The prefix that will be used for Fabconnect specific query parameters when FireFly makes requests to Fabconnect", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectFailoverURLs                = ffc("config.plugins.blockchain[].fabric.fabconnect.failover.urls", "Additional Fabconnect URLs to fail over to when the primary URL is unhealthy, in order of preference. All of the URLs must share the same event streams and listeners, such as replicas of a single Fabconnect deployment", i18n.ArrayStringType)
+	ConfigPluginBlockchainFabricFabconnectFailoverHealthCheckInterval = ffc("config.plugins.blockchain[].fabric.fabconnect.failover.healthCheckInterval", "How often the health of each Fabconnect URL is checked, when failover URLs are configured", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectFailoverHealthCheckPath     = ffc("config.plugins.blockchain[].fabric.fabconnect.failover.healthCheckPath", "The path requested on each Fabconnect URL to check its health, when failover URLs are configured. Any 2xx response is healthy", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectSigner                      = ffc("config.plugins.blockchain[].fabric.fabconnect.signer", "The Fabric signing key to use when submitting transactions to Fabconnect", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectTopic                       = ffc("config.plugins.blockchain[].fabric.fabconnect.topic", "The websocket listen topic that the node should register on, which is important if there are multiple nodes using a single Fabconnect", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectURL                         = ffc("config.plugins.blockchain[].fabric.fabconnect.url", "The URL of the Fabconnect instance", urlStringType)
@@ -224,10 +278,15 @@ var (
 	ConfigPluginBlockchainFabricFabconnectChaincode                   = ffc("config.plugins.blockchain[].fabric.fabconnect.chaincode", "The name of the Fabric chaincode that FireFly will use for BatchPin transactions (deprecated - use fireflyContract[].chaincode)", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectChannel                     = ffc("config.plugins.blockchain[].fabric.fabconnect.channel", "The Fabric channel that FireFly will use for BatchPin transactions", i18n.StringType)
 
-	ConfigBroadcastBatchAgentTimeout = ffc("config.broadcast.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.StringType)
-	ConfigBroadcastBatchPayloadLimit = ffc("config.broadcast.batch.payloadLimit", "The maximum payload size of a batch for broadcast messages", i18n.ByteSizeType)
-	ConfigBroadcastBatchSize         = ffc("config.broadcast.batch.size", "The maximum number of messages that can be packed into a batch", i18n.IntType)
-	ConfigBroadcastBatchTimeout      = ffc("config.broadcast.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigBroadcastBatchAgentTimeout    = ffc("config.broadcast.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.StringType)
+	ConfigBroadcastBatchPayloadLimit    = ffc("config.broadcast.batch.payloadLimit", "The maximum payload size of a batch for broadcast messages", i18n.ByteSizeType)
+	ConfigBroadcastBatchSize            = ffc("config.broadcast.batch.size", "The maximum number of messages that can be packed into a batch", i18n.IntType)
+	ConfigBroadcastBatchTimeout         = ffc("config.broadcast.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigBroadcastEncryptionKeys       = ffc("config.broadcast.encryption.keys", "A list of keys used to encrypt the data values and blobs of broadcast messages before they are published to shared storage. Deduplication of published data is disabled when keys are configured", i18n.StringType)
+	ConfigBroadcastEncryptionKeysID     = ffc("config.broadcast.encryption.keys[].id", "The ID of the key, which is published alongside the encrypted data so the members holding the key can decrypt it", i18n.StringType)
+	ConfigBroadcastEncryptionKeysKey    = ffc("config.broadcast.encryption.keys[].key", "The hex encoded 32 byte AES-256 key", i18n.StringType)
+	ConfigBroadcastEncryptionKeysTopics = ffc("config.broadcast.encryption.keys[].topics", "The message topics whose broadcast data is encrypted with the key. A key with no topics is only used to decrypt data, such as a key that has been rotated out", i18n.ArrayStringType)
+	ConfigBroadcastDeduplicate          = ffc("config.broadcast.deduplicate", "Whether a value or blob with the same hash as one already published to shared storage in the namespace should reuse the existing payload reference, rather than being uploaded again", i18n.BooleanType)
 
 	ConfigDatabaseType = ffc("config.database.type", "The type of the database interface plugin to use", i18n.IntType)
 
@@ -262,14 +321,19 @@ var (
 	ConfigPluginDataexchangeFfdxBackgroundStartInitialDelay = ffc("config.plugins.dataexchange[].ffdx.backgroundStart.initialDelay", "Delay between restarts in the case where we retry to restart the data exchange plugin", i18n.TimeDurationType)
 	ConfigPluginDataexchangeFfdxBackgroundStartMaxDelay     = ffc("config.plugins.dataexchange[].ffdx.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the data exchange plugin", i18n.TimeDurationType)
 	ConfigPluginDataexchangeFfdxBackgroundStartFactor       = ffc("config.plugins.dataexchange[].ffdx.backgroundStart.factor", "Set the factor by which the delay increases when retrying", i18n.FloatType)
+	ConfigPluginDataexchangeFfdxTransferMaxConcurrent       = ffc("config.plugins.dataexchange[].ffdx.transfer.maxConcurrent", "The number of blob transfers each namespace can have in flight at once. Further transfers are queued in memory, with their operations pending, until one completes. 0 for no limit", i18n.IntType)
+	ConfigPluginDataexchangeFfdxTransferMaxBandwidth        = ffc("config.plugins.dataexchange[].ffdx.transfer.maxBandwidth", "The total bytes per second each namespace can send in blob transfers, split evenly between the concurrent transfers. Passed to data exchange as maxBytesPerSecond on each transfer, so only applies if the data exchange implementation supports that field. Requires transfer.maxConcurrent. 0 for no limit", i18n.ByteSizeType)
 
 	ConfigPluginDataexchangeFfdxProxyURL = ffc("config.plugins.dataexchange[].ffdx.proxy.url", "Optional HTTP proxy server to use when connecting to the Data Exchange", urlStringType)
 
 	ConfigDebugPort    = ffc("config.debug.port", "An HTTP port on which to enable the go debugger", i18n.IntType)
 	ConfigDebugAddress = ffc("config.debug.address", "The HTTP interface the go debugger binds to", i18n.StringType)
 
+	ConfigDefinitionsDeprecatedPolicy = ffc("config.definitions.deprecatedPolicy", "How to handle new usage of a deprecated datatype or contract interface. Options are: `warn` to log a warning, or `reject` to reject the request", i18n.StringType)
+
 	ConfigDownloadWorkerCount       = ffc("config.download.worker.count", "The number of download workers", i18n.IntType)
 	ConfigDownloadWorkerQueueLength = ffc("config.download.worker.queueLength", "The length of the work queue in the channel to the workers - defaults to 2x the worker count", i18n.IntType)
+	ConfigDownloadVerifyPayloadHash = ffc("config.download.verifyPayloadHash", "Verify batches retrieved from shared storage against the hash pinned on-chain, before accepting them. Can be disabled for trusted private storage", i18n.BooleanType)
 
 	ConfigEventAggregatorBatchSize         = ffc("config.event.aggregator.batchSize", "The maximum number of records to read from the DB before performing an aggregation run", i18n.ByteSizeType)
 	ConfigEventAggregatorBatchTimeout      = ffc("config.event.aggregator.batchTimeout", "How long to wait for new events to arrive before performing aggregation on a page of events", i18n.TimeDurationType)
@@ -278,16 +342,34 @@ var (
 	ConfigEventAggregatorRewindQueueLength = ffc("config.event.aggregator.rewindQueueLength", "The size of the queue into the rewind dispatcher", i18n.IntType)
 	ConfigEventAggregatorRewindTimout      = ffc("config.event.aggregator.rewindTimeout", "The minimum time to wait for rewinds to accumulate before resolving them", i18n.TimeDurationType)
 	ConfigEventAggregatorRewindQueryLimit  = ffc("config.event.aggregator.rewindQueryLimit", "Safety limit on the maximum number of records to search when performing queries to search for rewinds", i18n.IntType)
+	ConfigEventAggregatorWorkers           = ffc("config.event.aggregator.workers", "The number of workers used to aggregate pins on independent topic contexts in parallel. Ordering is preserved within each context", i18n.IntType)
 	ConfigEventDbeventsBufferSize          = ffc("config.event.dbevents.bufferSize", "The size of the buffer of change events", i18n.ByteSizeType)
+	ConfigEventMaxIndexedDataSize          = ffc("config.event.maxIndexedDataSize", "The maximum size of the output of a blockchain event stored on the event itself. Larger outputs are stored separately, with a subset of the fields kept on the event. Zero means no limit", i18n.ByteSizeType)
 
 	ConfigEventDispatcherBatchTimeout = ffc("config.event.dispatcher.batchTimeout", "A short time to wait for new events to arrive before re-polling for new events", i18n.TimeDurationType)
 	ConfigEventDispatcherBufferLength = ffc("config.event.dispatcher.bufferLength", "The number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription", i18n.IntType)
 	ConfigEventDispatcherPollTimeout  = ffc("config.event.dispatcher.pollTimeout", "The time to wait without a notification of new events, before trying a select on the table", i18n.TimeDurationType)
 
+	ConfigEventDispatcherOffsetCommitRetryFactor      = ffc("config.event.dispatcher.offsetCommitRetry.factor", "The retry backoff factor, for committing subscription offsets", i18n.FloatType)
+	ConfigEventDispatcherOffsetCommitRetryInitDelay   = ffc("config.event.dispatcher.offsetCommitRetry.initDelay", "The initial retry delay, for committing subscription offsets", i18n.TimeDurationType)
+	ConfigEventDispatcherOffsetCommitRetryMaxAttempts = ffc("config.event.dispatcher.offsetCommitRetry.maxAttempts", "The maximum number of attempts to commit a subscription offset, before a subscription_offset_commit_failed event is emitted. Zero to retry indefinitely", i18n.IntType)
+	ConfigEventDispatcherOffsetCommitRetryMaxDelay    = ffc("config.event.dispatcher.offsetCommitRetry.maxDelay", "The maximum retry delay, for committing subscription offsets", i18n.TimeDurationType)
+
 	ConfigEventTransportsDefault = ffc("config.event.transports.default", "The default event transport for new subscriptions", i18n.StringType)
 	ConfigEventTransportsEnabled = ffc("config.event.transports.enabled", "Which event interface plugins are enabled", i18n.BooleanType)
 
-	ConfigHistogramsMaxChartRows = ffc("config.histograms.maxChartRows", "The maximum rows to fetch for each histogram bucket", i18n.IntType)
+	ConfigHAEnabled       = ffc("config.ha.enabled", "Enables active/passive high availability, for multiple nodes that share one database. Only the active node, which holds the leader lock, processes events and submits transactions. Passive nodes serve read-only API requests", i18n.BooleanType)
+	ConfigHALockProvider  = ffc("config.ha.lockProvider", "The type of lock provider that holds the leader lock. Only 'database' is currently supported", i18n.StringType)
+	ConfigHADatabase      = ffc("config.ha.database", "The name of the database plugin that holds the leader lock. Required if more than one database plugin is configured", i18n.StringType)
+	ConfigHALockName      = ffc("config.ha.lockName", "The name of the leader lock. All the nodes of one deployment must use the same name", i18n.StringType)
+	ConfigHANodeID        = ffc("config.ha.nodeId", "A unique identifier of this node, recorded as the holder of the leader lock. Defaults to the hostname with a random suffix", i18n.StringType)
+	ConfigHALeaseDuration = ffc("config.ha.leaseDuration", "How long the leader lock is held without being renewed. A passive node takes over after the active node fails to renew it for this long. The active node shuts down if it cannot renew the lock before it expires", i18n.TimeDurationType)
+	ConfigHARenewInterval = ffc("config.ha.renewInterval", "How often the active node renews the leader lock, and passive nodes try to acquire it. Must be less than the lease duration", i18n.TimeDurationType)
+
+	ConfigHistogramsMaxChartRows    = ffc("config.histograms.maxChartRows", "The maximum rows to fetch for each histogram bucket", i18n.IntType)
+	ConfigHistogramsRollupEnabled   = ffc("config.histograms.rollup.enabled", "Enables pre-aggregation of hourly counts of each chart collection, so time series over long ranges do not need to count every record", i18n.BooleanType)
+	ConfigHistogramsRollupInterval  = ffc("config.histograms.rollup.interval", "How often new records are added to the pre-aggregated counts. Records are added on the pass after the one that first sees them, so that records stored by transactions that were still committing are not missed", i18n.TimeDurationType)
+	ConfigHistogramsRollupBatchSize = ffc("config.histograms.rollup.batchSize", "The maximum number of records added to the pre-aggregated counts in each database transaction", i18n.IntType)
 
 	ConfigHTTPAddress      = ffc("config.http.address", "The IP address on which the HTTP API should listen", "IP Address "+i18n.StringType)
 	ConfigHTTPPort         = ffc("config.http.port", "The port on which the HTTP API should listen", i18n.IntType)
@@ -316,6 +398,13 @@ var (
 	ConfigMessageWriterBatchTimeout    = ffc("config.message.writer.batchTimeout", "How long to wait for more messages to arrive before flushing the batch", i18n.TimeDurationType)
 	ConfigMessageWriterCount           = ffc("config.message.writer.count", "The number of message writer workers", i18n.IntType)
 
+	ConfigTracingEnabled      = ffc("config.tracing.enabled", "Enables OpenTelemetry tracing of API requests, batch dispatch, event aggregation and calls to plugin connectors", i18n.BooleanType)
+	ConfigTracingServiceName  = ffc("config.tracing.serviceName", "The service name reported on all spans exported by this node", i18n.StringType)
+	ConfigTracingSampleRatio  = ffc("config.tracing.sampleRatio", "The fraction of traces started by this node that are sampled, between 0 and 1. Traces started by a caller follow the sampling decision of the caller", i18n.FloatType)
+	ConfigTracingOtlpEndpoint = ffc("config.tracing.otlp.endpoint", "The URL of the OTLP/HTTP collector that spans are exported to. If not set, the standard OTEL_EXPORTER_OTLP_* environment variables are used", urlStringType)
+	ConfigTracingOtlpInsecure = ffc("config.tracing.otlp.insecure", "Disables TLS when exporting spans to the OTLP collector", i18n.BooleanType)
+
+	ConfigTransactionIdempotencyWindow          = ffc("config.transaction.idempotencyWindow", "How long after a message or transaction is submitted that a resubmission with the same idempotency key returns the original submission, rather than a conflict error. Set to 0 to always return a conflict error", i18n.TimeDurationType)
 	ConfigTransactionWriterBatchMaxTransactions = ffc("config.transaction.writer.batchMaxTransactions", "The maximum number of transaction inserts to include in a batch", i18n.IntType)
 	ConfigTransactionWriterBatchTimeout         = ffc("config.transaction.writer.batchTimeout", "How long to wait for more transactions to arrive before flushing the batch", i18n.TimeDurationType)
 	ConfigTransactionWriterCount                = ffc("config.transaction.writer.count", "The number of message writer workers", i18n.IntType)
@@ -328,15 +417,23 @@ var (
 	ConfigMetricsReadTimeout  = ffc("config.metrics.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
 	ConfigMetricsWriteTimeout = ffc("config.metrics.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
 
-	ConfigNamespacesDefault                    = ffc("config.namespaces.default", "The default namespace - must be in the predefined list", i18n.StringType)
-	ConfigNamespacesPredefined                 = ffc("config.namespaces.predefined", "A list of namespaces to ensure exists, without requiring a broadcast from the network", "List "+i18n.StringType)
-	ConfigNamespacesPredefinedName             = ffc("config.namespaces.predefined[].name", "The name of the namespace (must be unique)", i18n.StringType)
-	ConfigNamespacesPredefinedDescription      = ffc("config.namespaces.predefined[].description", "A description for the namespace", i18n.StringType)
-	ConfigNamespacesPredefinedPlugins          = ffc("config.namespaces.predefined[].plugins", "The list of plugins for this namespace", i18n.StringType)
-	ConfigNamespacesPredefinedDefaultKey       = ffc("config.namespaces.predefined[].defaultKey", "A default signing key for blockchain transactions within this namespace", i18n.StringType)
-	ConfigNamespacesPredefinedKeyNormalization = ffc("config.namespaces.predefined[].asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization", i18n.StringType)
-	ConfigNamespacesPredefinedTLSConfigs       = ffc("config.namespaces.predefined[].tlsConfigs", "Supply a set of tls certificates to be used by subscriptions for this namespace", "List "+i18n.StringType)
-	ConfigNamespacesPredefinedTLSConfigsName   = ffc("config.namespaces.predefined[].tlsConfigs[].name", "Name of the TLS Config", i18n.StringType)
+	ConfigNamespacesDefault                           = ffc("config.namespaces.default", "The default namespace - must be in the predefined list", i18n.StringType)
+	ConfigNamespacesPredefined                        = ffc("config.namespaces.predefined", "A list of namespaces to ensure exists, without requiring a broadcast from the network", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedName                    = ffc("config.namespaces.predefined[].name", "The name of the namespace (must be unique)", i18n.StringType)
+	ConfigNamespacesPredefinedDescription             = ffc("config.namespaces.predefined[].description", "A description for the namespace", i18n.StringType)
+	ConfigNamespacesPredefinedPlugins                 = ffc("config.namespaces.predefined[].plugins", "The list of plugins for this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedDefaultKey              = ffc("config.namespaces.predefined[].defaultKey", "A default signing key for blockchain transactions within this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedKeyNormalization        = ffc("config.namespaces.predefined[].asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization", i18n.StringType)
+	ConfigNamespacesPredefinedPoolConnectors          = ffc("config.namespaces.predefined[].asset.manager.poolConnectors", "Bindings of token pools to token connectors. When set, operations for each pool are routed to the bound connector, and operations for pools with no binding are rejected", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedPoolConnectorsPool      = ffc("config.namespaces.predefined[].asset.manager.poolConnectors[].pool", "The name of the token pool", i18n.StringType)
+	ConfigNamespacesPredefinedPoolConnectorsConnector = ffc("config.namespaces.predefined[].asset.manager.poolConnectors[].connector", "The name of the token plugin that handles operations for the pool", i18n.StringType)
+	ConfigNamespacesPredefinedRetentionWindow         = ffc("config.namespaces.predefined[].retention.window", "The age after which events, confirmed messages and completed operations in this namespace are pruned. Definition messages, and messages with events still to be delivered, are retained. Overrides retention.window", i18n.TimeDurationType)
+	ConfigNamespacesPredefinedRetentionArchive        = ffc("config.namespaces.predefined[].retention.archive", "Upload pruned records to the shared storage plugin of the namespace before they are deleted. Private messages and their data are not archived, and events and operations are archived as an ID and hash only", i18n.BooleanType)
+	ConfigNamespacesPredefinedTLSConfigs              = ffc("config.namespaces.predefined[].tlsConfigs", "Supply a set of tls certificates to be used by subscriptions for this namespace", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedTLSConfigsName          = ffc("config.namespaces.predefined[].tlsConfigs[].name", "Name of the TLS Config", i18n.StringType)
+	ConfigNamespacesPredefinedSigningSecrets          = ffc("config.namespaces.predefined[].signingSecrets", "Supply a set of named secrets that subscriptions in this namespace can use to sign webhook requests", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedSigningSecretsName      = ffc("config.namespaces.predefined[].signingSecrets[].name", "Name of the signing secret, which subscriptions use to refer to it", i18n.StringType)
+	ConfigNamespacesPredefinedSigningSecretsSecret    = ffc("config.namespaces.predefined[].signingSecrets[].secret", "The HMAC secret used to sign webhook requests", i18n.StringType)
 	// ConfigNamespacesPredefinedTLSConfigsTLS      = ffc("config.namespaces.predefined[].tlsConfigs[].tls", "Specify the path to a CA, Cert and Key for TLS communication", i18n.StringType)
 	ConfigNamespacesMultipartyEnabled            = ffc("config.namespaces.predefined[].multiparty.enabled", "Enables multi-party mode for this namespace (defaults to true if an org name or key is configured, either here or at the root level)", i18n.BooleanType)
 	ConfigNamespacesMultipartyNetworkNamespace   = ffc("config.namespaces.predefined[].multiparty.networknamespace", "The shared namespace name to be sent in multiparty messages, if it differs from the local namespace name", i18n.StringType)
@@ -353,17 +450,51 @@ var (
 	ConfigNodeDescription = ffc("config.node.description", "The description of this FireFly node", i18n.StringType)
 	ConfigNodeName        = ffc("config.node.name", "The name of this FireFly node", i18n.StringType)
 
-	ConfigOpupdateWorkerBatchMaxInserts = ffc("config.opupdate.worker.batchMaxInserts", "The maximum number of database inserts to include when writing a single batch of messages + data", i18n.IntType)
-	ConfigOpupdateWorkerBatchTimeout    = ffc("config.opupdate.worker.batchTimeout", "How long to wait for more messages to arrive before flushing the batch", i18n.TimeDurationType)
-	ConfigOpupdateWorkerCount           = ffc("config.opupdate.worker.count", "The number of operation update works", i18n.IntType)
-	ConfigOpupdateWorkerQueueLength     = ffc("config.opupdate.worker.queueLength", "The size of the queue for the Operation Update worker", i18n.IntType)
-
-	ConfigOrchestratorStartupAttempts = ffc("config.orchestrator.startupAttempts", "The number of times to attempt to connect to core infrastructure on startup", i18n.StringType)
+	ConfigOperationsCircuitBreakerFailureThreshold = ffc("config.operations.circuitBreaker.failureThreshold", "The number of consecutive operation submission failures to a plugin, after which further submissions fail fast until the cooldown expires. Only connection errors and 5xx responses count as failures, not client errors such as 4xx responses. Zero disables the circuit breaker", i18n.IntType)
+	ConfigOperationsCircuitBreakerCooldown         = ffc("config.operations.circuitBreaker.cooldown", "How long submissions to a plugin fail fast after its circuit breaker opens, before a single submission is allowed through to test for recovery", i18n.TimeDurationType)
+	ConfigOperationsErrorDetailMaxSize             = ffc("config.operations.errorDetail.maxSize", "The maximum size of the connector response body preserved as the last error on a failed operation. Larger responses are truncated", i18n.ByteSizeType)
+	ConfigOperationsErrorDetailRedactFields        = ffc("config.operations.errorDetail.redactFields", "A list of JSON field names, matched case-insensitively at any depth, whose values are redacted from the connector response before it is preserved on a failed operation", i18n.ArrayStringType)
+	ConfigOperationsNotifyMaxAttempts              = ffc("config.operations.notify.maxAttempts", "The number of attempts made to deliver an operation notification webhook, before giving up and logging the failure", i18n.IntType)
+	ConfigOperationsNotifyRetryInitialDelay        = ffc("config.operations.notify.retry.initialDelay", "The initial delay between attempts to deliver an operation notification webhook", i18n.TimeDurationType)
+	ConfigOperationsNotifyRetryMaxDelay            = ffc("config.operations.notify.retry.maxDelay", "The maximum delay between attempts to deliver an operation notification webhook", i18n.TimeDurationType)
+	ConfigOperationsNotifyRequestTimeout           = ffc("config.operations.notify.requestTimeout", "The timeout for each attempt to deliver an operation notification webhook", i18n.TimeDurationType)
+	ConfigOperationsNotifyAllowedHosts             = ffc("config.operations.notify.allowedHosts", "The hosts that operation notification webhooks can be delivered to, each a hostname or host:port. A '*' entry allows any host. Notifications are rejected when no hosts are configured", i18n.ArrayStringType)
+	ConfigOperationsNotifyMaxRegistrations         = ffc("config.operations.notify.maxRegistrations", "The maximum number of operation notification webhooks that can be waiting for their operations to resolve, across the namespace", i18n.IntType)
+	ConfigOperationsTransferProgressInterval       = ffc("config.operations.transferProgress.interval", "The minimum time between progress updates stored for a data exchange blob transfer. More frequent reports from the data exchange are dropped, apart from the report that the transfer is complete", i18n.TimeDurationType)
+	ConfigOperationsOutputValidation               = ffc("config.operations.outputValidation", "A list of JSON schemas that the output reported by connectors must conform to, each applying to one operation type. Operations whose output does not conform are marked as failed, and the output is not stored", i18n.StringType)
+	ConfigOperationsOutputValidationType           = ffc("config.operations.outputValidation[].type", "The operation type, such as 'blockchain_invoke', that the schema applies to", i18n.StringType)
+	ConfigOperationsRetryPolicies                  = ffc("config.operations.retryPolicies", "A list of policies for automatically retrying failed operations, each applying to one operation type. Failed operations of other types are only retried on request", i18n.StringType)
+	ConfigOperationsRetryPoliciesType              = ffc("config.operations.retryPolicies[].type", "The operation type, such as 'blockchain_invoke', that the policy applies to", i18n.StringType)
+	ConfigOperationsRetryPoliciesMaxRetries        = ffc("config.operations.retryPolicies[].maxRetries", "The maximum number of automatic retries in the retry chain of an operation", i18n.IntType)
+	ConfigOperationsRetryPoliciesInitialDelay      = ffc("config.operations.retryPolicies[].initialDelay", "The delay before the first automatic retry of a failed operation", i18n.TimeDurationType)
+	ConfigOperationsRetryPoliciesMaxDelay          = ffc("config.operations.retryPolicies[].maxDelay", "The maximum delay before an automatic retry of a failed operation", i18n.TimeDurationType)
+	ConfigOperationsRetryPoliciesFactor            = ffc("config.operations.retryPolicies[].factor", "The factor the delay is multiplied by after each automatic retry", i18n.FloatType)
+	ConfigOperationsRetryPoliciesRetryableErrors   = ffc("config.operations.retryPolicies[].retryableErrors", "A list of regular expressions. If set, only errors matching one of them are retried. If empty, all errors not matching a terminal error are retried", i18n.ArrayStringType)
+	ConfigOperationsRetryPoliciesTerminalErrors    = ffc("config.operations.retryPolicies[].terminalErrors", "A list of regular expressions for errors that are never retried automatically", i18n.ArrayStringType)
+	ConfigOperationsOutputValidationSchema         = ffc("config.operations.outputValidation[].schema", "The JSON schema the output of the operation type must conform to, as a JSON string so that the case of property names is preserved", i18n.StringType)
+	ConfigTokenMetadataEnabled                     = ffc("config.tokenMetadata.enabled", "Whether the metadata of tokens is resolved from their token URIs, when requested on the API", i18n.BooleanType)
+	ConfigTokenMetadataAllowedHosts                = ffc("config.tokenMetadata.allowedHosts", "The hosts that token metadata can be fetched from over http(s), each a hostname or host:port. A '*' entry allows any host. Token URIs are set by whoever minted the token, so when no hosts are configured only URIs relative to the configured url are fetched", i18n.ArrayStringType)
+	ConfigTokenMetadataRequestTimeout              = ffc("config.tokenMetadata.requestTimeout", "The maximum amount of time to wait for the metadata of a token to be fetched", i18n.TimeDurationType)
+	ConfigTokenMetadataMaxSize                     = ffc("config.tokenMetadata.maxSize", "The maximum size of a metadata document read from a token URI", i18n.ByteSizeType)
+	ConfigTokenMetadataURL                         = ffc("config.tokenMetadata.url", "An optional base URL that token URIs with a relative path are resolved against", i18n.StringType)
+	ConfigTokenMetadataProxyURL                    = ffc("config.tokenMetadata.proxy.url", "Optional HTTP proxy server to use when fetching token metadata", urlStringType)
+	ConfigOpupdateWorkerBatchMaxInserts            = ffc("config.opupdate.worker.batchMaxInserts", "The maximum number of database inserts to include when writing a single batch of messages + data", i18n.IntType)
+	ConfigOpupdateWorkerBatchTimeout               = ffc("config.opupdate.worker.batchTimeout", "How long to wait for more messages to arrive before flushing the batch", i18n.TimeDurationType)
+	ConfigOpupdateWorkerCoalesce                   = ffc("config.opupdate.worker.coalesce", "Whether to merge multiple non-terminal updates to the same operation that arrive within one batch, so only the latest state is processed. Succeeded and Failed updates are never merged", i18n.BooleanType)
+	ConfigOpupdateWorkerCoalesceWindow             = ffc("config.opupdate.worker.coalesceWindow", "When coalesce is enabled, how long a non-terminal update to an operation is held before it is processed, so that later non-terminal updates to the same operation are merged into it even when they arrive in a later batch. Succeeded and Failed updates are never held. Zero only merges updates within a batch", i18n.TimeDurationType)
+	ConfigOpupdateWorkerCount                      = ffc("config.opupdate.worker.count", "The number of operation update works", i18n.IntType)
+	ConfigOpupdateWorkerQueueLength                = ffc("config.opupdate.worker.queueLength", "The size of the queue for the Operation Update worker", i18n.IntType)
+
+	ConfigOrchestratorStartupAttempts    = ffc("config.orchestrator.startupAttempts", "The number of times to attempt to connect to core infrastructure on startup", i18n.StringType)
+	ConfigOrchestratorReadinessTimeout   = ffc("config.orchestrator.readinessTimeout", "How long to wait for each plugin to respond to a readiness check, before reporting it as not ready", i18n.TimeDurationType)
+	ConfigOrchestratorDiagnosticsTimeout = ffc("config.orchestrator.diagnosticsTimeout", "How long to wait for each check of the network diagnostics, including delivery of a ping to each member over data exchange, before reporting it as unhealthy", i18n.TimeDurationType)
 
 	ConfigOrgDescription = ffc("config.org.description", "A description of the organization to which this FireFly node belongs (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 	ConfigOrgKey         = ffc("config.org.key", "The signing key allocated to the organization (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 	ConfigOrgName        = ffc("config.org.name", "The name of the organization to which this FireFly node belongs (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 
+	ConfigPrivatemessagingAcksEnabled       = ffc("config.privatemessaging.acks.enabled", "Whether to send delivery and processing acknowledgements back to the sender of each private message received by this node", i18n.BooleanType)
+	ConfigPrivatemessagingAcksQueueLength   = ffc("config.privatemessaging.acks.queueLength", "The number of sets of acknowledgements that can be queued for sending in the background. Acknowledgements are dropped with a warning when the queue is full", i18n.IntType)
 	ConfigPrivatemessagingBatchAgentTimeout = ffc("config.privatemessaging.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.TimeDurationType)
 	ConfigPrivatemessagingBatchPayloadLimit = ffc("config.privatemessaging.batch.payloadLimit", "The maximum payload size of a private message Data Exchange payload", i18n.ByteSizeType)
 	ConfigPrivatemessagingBatchSize         = ffc("config.privatemessaging.batch.size", "The maximum number of messages in a batch for private messages", i18n.IntType)
@@ -383,10 +514,15 @@ var (
 	ConfigPluginSharedstorageIpfsGatewayURL      = ffc("config.plugins.sharedstorage[].ipfs.gateway.url", "The URL for the IPFS Gateway", urlStringType)
 	ConfigPluginSharedstorageIpfsGatewayProxyURL = ffc("config.plugins.sharedstorage[].ipfs.gateway.proxy.url", "Optional HTTP proxy server to use when connecting to the IPFS Gateway", urlStringType)
 
+	ConfigRetentionWindow    = ffc("config.retention.window", "The default age after which events, confirmed messages and completed operations are pruned from the database. Definition messages, and messages with events still to be delivered, are retained. Zero disables pruning", i18n.TimeDurationType)
+	ConfigRetentionInterval  = ffc("config.retention.interval", "How often each namespace checks for records to prune", i18n.TimeDurationType)
+	ConfigRetentionBatchSize = ffc("config.retention.batchSize", "The number of records read and deleted at a time while pruning", i18n.IntType)
+
 	ConfigSubscriptionMax                          = ffc("config.subscription.max", "The maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)", i18n.IntType)
 	ConfigSubscriptionDefaultsBatchSize            = ffc("config.subscription.defaults.batchSize", "Default read ahead to enable for subscriptions that do not explicitly configure readahead", i18n.IntType)
 	ConfigSubscriptionDefaultsBatchTimeout         = ffc("config.subscription.defaults.batchTimeout", "Default batch timeout", i18n.IntType)
 	ConfigSubscriptionMaxHistoricalEventScanLength = ffc("config.subscription.events.maxScanLength", "The maximum number of events a search for historical events matching a subscription will index from the database", i18n.IntType)
+	ConfigSubscriptionDeliveryErrorHistory         = ffc("config.subscription.deliveryErrorHistory", "The number of rejected event deliveries kept in memory, to report on in the namespace error report", i18n.IntType)
 
 	ConfigTokensName     = ffc("config.tokens[].name", "A name to identify this token plugin", i18n.StringType)
 	ConfigTokensPlugin   = ffc("config.tokens[].plugin", "The type of the token plugin to use", i18n.StringType)
@@ -416,8 +552,43 @@ var (
 	ConfigPluginsAuthName = ffc("config.plugins.auth[].name", "The name of the auth plugin to use", i18n.StringType)
 	ConfigPluginsAuthType = ffc("config.plugins.auth[].type", "The type of the auth plugin to use", i18n.StringType)
 
+	ConfigPluginsAuthAPIKeyHeader           = ffc("config.plugins.auth[].apikey.header", "The HTTP header that callers pass their API key in", i18n.StringType)
+	ConfigPluginsAuthAPIKeyKeys             = ffc("config.plugins.auth[].apikey.keys", "The API keys that are accepted, each granting a role in a set of namespaces", i18n.ArrayStringType)
+	ConfigPluginsAuthAPIKeyKeysName         = ffc("config.plugins.auth[].apikey.keys[].name", "A name for the API key, used in logging", i18n.StringType)
+	ConfigPluginsAuthAPIKeyKeysHash         = ffc("config.plugins.auth[].apikey.keys[].hash", "The hex encoded SHA-256 hash of the API key, so the key itself is not stored in the configuration", i18n.StringType)
+	ConfigPluginsAuthAPIKeyKeysRole         = ffc("config.plugins.auth[].apikey.keys[].role", "The role granted to callers with the API key - one of `reader`, `submitter` or `admin`", i18n.StringType)
+	ConfigPluginsAuthAPIKeyKeysNamespaces   = ffc("config.plugins.auth[].apikey.keys[].namespaces", "The namespaces the API key can be used in. A key with no namespaces can be used in every namespace", i18n.ArrayStringType)
+	ConfigPluginsAuthJWTHMACSecret          = ffc("config.plugins.auth[].jwt.hmacSecret", "The shared secret used to verify tokens signed with HS256", i18n.StringType)
+	ConfigPluginsAuthJWTJWKSURL             = ffc("config.plugins.auth[].jwt.jwksURL", "The URL of the JSON Web Key Set published by an OIDC provider, used to verify tokens signed with RS256. The keys are loaded on startup, and loaded again when a token is signed with an unknown key", i18n.StringType)
+	ConfigPluginsAuthJWTJWKSRequestTimeout  = ffc("config.plugins.auth[].jwt.jwksRequestTimeout", "The maximum amount of time to wait for the JSON Web Key Set to be fetched", i18n.TimeDurationType)
+	ConfigPluginsAuthJWTJWKSRefreshInterval = ffc("config.plugins.auth[].jwt.jwksRefreshInterval", "The minimum amount of time between fetches of the JSON Web Key Set, when tokens are signed with a key that is not in the set", i18n.TimeDurationType)
+	ConfigPluginsAuthJWTIssuer              = ffc("config.plugins.auth[].jwt.issuer", "If set, tokens must have an iss claim matching this issuer", i18n.StringType)
+	ConfigPluginsAuthJWTAudience            = ffc("config.plugins.auth[].jwt.audience", "If set, tokens must have an aud claim that includes this audience", i18n.StringType)
+	ConfigPluginsAuthJWTRolesClaim          = ffc("config.plugins.auth[].jwt.rolesClaim", "The claim holding the role, or list of roles, granted to the caller. The highest of the `reader`, `submitter` and `admin` roles is used", i18n.StringType)
+	ConfigPluginsAuthJWTNamespacesClaim     = ffc("config.plugins.auth[].jwt.namespacesClaim", "The claim holding the list of namespaces the token can be used in. A token without the claim can be used in every namespace", i18n.StringType)
+	ConfigPluginsAuthJWTClockSkew           = ffc("config.plugins.auth[].jwt.clockSkew", "The leeway allowed for differences between the clocks of the token issuer and this node, when checking the exp, nbf and iat claims of a token", i18n.TimeDurationType)
+
+	ConfigPluginsEventKafkaBrokers              = ffc("config.events.kafka.brokers", "The list of bootstrap brokers, such as `localhost:9092`. Required if the kafka transport is enabled", i18n.ArrayStringType)
+	ConfigPluginsEventKafkaClientID             = ffc("config.events.kafka.clientId", "The client ID to connect to the brokers with. A unique ID is generated if not set", i18n.StringType)
+	ConfigPluginsEventKafkaUsername             = ffc("config.events.kafka.username", "The username to authenticate with using SASL/PLAIN. No authentication is performed if not set", i18n.StringType)
+	ConfigPluginsEventKafkaPassword             = ffc("config.events.kafka.password", "The password to authenticate with using SASL/PLAIN", i18n.StringType)
+	ConfigPluginsEventKafkaProduceTimeout       = ffc("config.events.kafka.produceTimeout", "How long to wait for all in-sync replicas to acknowledge the events of a delivery, before the delivery is failed and retried", i18n.TimeDurationType)
+	ConfigPluginsEventKafkaBatchTimeout         = ffc("config.events.kafka.batchTimeout", "How long the producer waits for more events before sending a batch to a partition. Keep this low, as each delivery waits for its events to be written", i18n.TimeDurationType)
+	ConfigPluginsEventKafkaTopicTemplate        = ffc("config.events.kafka.topicTemplate", "The topic events are published to, for subscriptions that do not set the `topic` option. The `{namespace}`, `{subscription}` (name) and `{id}` placeholders are substituted", i18n.StringType)
+	ConfigPluginsEventKafkaKeyTemplate          = ffc("config.events.kafka.keyTemplate", "The partition key of each event, for subscriptions that do not set the `key` option. Supports the placeholders of topicTemplate, plus the `{topic}`, `{type}` and `{author}` of the event. Events with the same key are kept in order on one partition", i18n.StringType)
+	ConfigPluginsEventMQTTURL                   = ffc("config.events.mqtt.url", "The URL of the MQTT broker, such as `tcp://localhost:1883` or `ssl://broker:8883`. Required if the mqtt transport is enabled", i18n.StringType)
+	ConfigPluginsEventMQTTClientID              = ffc("config.events.mqtt.clientId", "The client ID to connect to the broker with. A unique ID is generated if not set", i18n.StringType)
+	ConfigPluginsEventMQTTUsername              = ffc("config.events.mqtt.username", "The username to connect to the broker with", i18n.StringType)
+	ConfigPluginsEventMQTTPassword              = ffc("config.events.mqtt.password", "The password to connect to the broker with", i18n.StringType)
+	ConfigPluginsEventMQTTConnectTimeout        = ffc("config.events.mqtt.connectTimeout", "How long to wait for each attempt to connect to the broker. Connection attempts are retried until successful", i18n.TimeDurationType)
+	ConfigPluginsEventMQTTPublishTimeout        = ffc("config.events.mqtt.publishTimeout", "How long to wait for the broker to confirm an event was published, or a reply topic subscribed, before the delivery is failed", i18n.TimeDurationType)
+	ConfigPluginsEventMQTTTopicTemplate         = ffc("config.events.mqtt.topicTemplate", "The topic events are published to, for subscriptions that do not set the `topic` option. The `{namespace}`, `{subscription}` (name) and `{id}` placeholders are substituted", i18n.StringType)
+	ConfigPluginsEventMQTTReplyTopicTemplate    = ffc("config.events.mqtt.replyTopicTemplate", "The topic acknowledgements are received on, for subscriptions that do not set the `replyTopic` option. Supports the same placeholders as topicTemplate", i18n.StringType)
+	ConfigPluginsEventSSEPingInterval           = ffc("config.events.sse.pingInterval", "How often to send a keepalive comment on an idle server-sent events stream", i18n.TimeDurationType)
 	ConfigPluginsEventSystemReadAhead           = ffc("config.events.system.readAhead", "", i18n.IgnoredType)
 	ConfigPluginsEventWebhooksURL               = ffc("config.events.webhooks.url", "", i18n.IgnoredType)
 	ConfigPluginsEventWebSocketsReadBufferSize  = ffc("config.events.websockets.readBufferSize", "WebSocket read buffer size", i18n.ByteSizeType)
 	ConfigPluginsEventWebSocketsWriteBufferSize = ffc("config.events.websockets.writeBufferSize", "WebSocket write buffer size", i18n.ByteSizeType)
+	ConfigPluginsEventWebSocketsMaxMessageSize  = ffc("config.events.websockets.maxMessageSize", "The maximum size of a single message written to a WebSocket client. Larger messages are handled according to oversizeMode. 0 means unlimited, otherwise it must be at least 512 bytes", i18n.ByteSizeType)
+	ConfigPluginsEventWebSocketsOversizeMode    = ffc("config.events.websockets.oversizeMode", "How to deliver messages larger than maxMessageSize - 'reference' sends a reference to the event for the client to fetch via the REST API, 'fragment' splits the message into fragments for the client to reassemble. Clients can override this with the 'oversize' query parameter when connecting", i18n.StringType)
 )
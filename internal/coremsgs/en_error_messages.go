@@ -316,4 +316,192 @@ var (
 	MsgFiltersEmpty                            = ffe("FF10475", "No filters specified in contract listener: %s.", 500)
 	MsgContractListenerBlockchainFilterLimit   = ffe("FF10476", "Blockchain plugin only supports one filter for contract listener: %s.", 500)
 	MsgDuplicateContractListenerFilterLocation = ffe("FF10477", "Duplicate filter provided for contract listener for location", 400)
+	MsgDIDDocumentMissingID                    = ffe("FF10478", "DID document must include an 'id'", 400)
+	MsgDIDVerificationMethodUnknown            = ffe("FF10479", "Verification method '%s' is not registered in the confirmed claim for '%s'")
+	MsgDIDVerificationMethodMismatch           = ffe("FF10480", "Verification method '%s' does not match the verifier registered in the confirmed claim for '%s'")
+	MsgWSInvalidOversizeMode                   = ffe("FF10481", "Invalid websocket oversize mode '%s' - must be 'reference' or 'fragment'")
+	MsgFFIMethodOverloaded                     = ffe("FF10482", "Method '%s' is overloaded - use one of the path names %s to select a method", 400)
+	MsgTokenPoolNoConnectorBinding             = ffe("FF10483", "No token connector is bound to pool '%s' in this namespace", 400)
+	MsgTokenPoolConnectorMismatch              = ffe("FF10484", "Token pool '%s' is bound to connector '%s' - cannot use connector '%s'", 400)
+	MsgDuplicatePoolConnector                  = ffe("FF10485", "Duplicate connector binding for token pool '%s'")
+	MsgInvalidPoolConnector                    = ffe("FF10486", "Token pool connector binding %d must specify both 'pool' and 'connector'")
+	MsgInvalidStatusInterval                   = ffe("FF10487", "Invalid status interval '%s' - must be a duration of at least %s", 400)
+	MsgInvalidConfirmTimeout                   = ffe("FF10488", "Invalid confirm timeout '%s' - must be a positive duration", 400)
+	MsgDownloadBatchHashMismatch               = ffe("FF10489", "Batch downloaded from shared storage '%s' does not match the pinned batch hash. Expected=%s Found=%v")
+	MsgInvalidErrorReportWindow                = ffe("FF10490", "Invalid error report window '%s' - must be a positive duration", 400)
+	MsgCircuitBreakerOpen                      = ffe("FF10491", "Submission to plugin '%s' suspended after %d consecutive failures. Retry after %s", 503)
+	MsgInvalidIdleListenerMinAge               = ffe("FF10492", "Invalid minimum listener age '%s' - must be a duration of zero or more", 400)
+	MsgNetworkResyncInProgress                 = ffe("FF10493", "A network resync is already in progress for this namespace", 409)
+	MsgInvalidOutputSchema                     = ffe("FF10494", "Invalid output schema for operation type '%s': %s")
+	MsgOperationOutputInvalid                  = ffe("FF10495", "Output of '%s' operation does not conform to its schema: %s")
+	MsgInvalidListenerHealthWindow             = ffe("FF10496", "Invalid listener health window '%s' - must be a positive duration", 400)
+	MsgMessageNotBatched                       = ffe("FF10497", "Message '%s' has not been included in a batch", 404)
+	MsgMessageProofUnavailable                 = ffe("FF10498", "Proof of message '%s' is unavailable, as it cannot be found in the manifest of batch '%s' matching the batch hash", 409)
+	MsgOperationNotRetryable                   = ffe("FF10499", "Operation '%s' is in status '%s' - only failed operations can be retried", 409)
+	MsgContractListenerSubscribed              = ffe("FF10500", "Contract listener '%s' is referenced by the blockchain event filter of subscription '%s'", 409)
+	MsgInvalidPageCursor                       = ffe("FF10501", "Invalid page cursor '%s'", 400)
+	MsgPageCursorSortMismatch                  = ffe("FF10502", "Page cursor was issued for a sort on '%s' and cannot be used with a different sort", 400)
+	MsgOperationRetryChainCycle                = ffe("FF10503", "Retry chain of operation '%s' is corrupt - operation '%s' appears more than once", 500)
+	MsgOperationRetryChainTooLong              = ffe("FF10504", "Retry chain of operation '%s' exceeds the maximum length of %d", 500)
+	MsgContractListenerFieldImmutable          = ffe("FF10505", "Field '%s' of contract listener '%s' cannot be changed", 400)
+	MsgContractListenerUpdateNotSupported      = ffe("FF10506", "The blockchain connector does not support updating contract listener '%s' in place", 501)
+	MsgUnsupportedOpenAPIVersion               = ffe("FF10507", "Unsupported OpenAPI version '%s' - supported versions are 3.0 and 3.1", 400)
+	MsgInvalidOperationNotifyURL               = ffe("FF10508", "Invalid operation notification URL '%s' - must be an absolute http or https URL", 400)
+	MsgOperationNotifyNotTerminal              = ffe("FF10509", "Operation '%s' has not yet reached a terminal state", 409)
+	MsgOperationNotifyFailed                   = ffe("FF10510", "Operation notification to '%s' failed with status %d", 502)
+	MsgInvalidSortField                        = ffe("FF10511", "Invalid sort field '%s' - must be one of: %s", 400)
+	MsgPluginReadinessTimeout                  = ffe("FF10512", "Plugin did not respond within %s")
+	MsgInvalidListenerFromBlock                = ffe("FF10513", "Invalid fromBlock '%s' - must be 'oldest' or a block number", 400)
+	MsgListenerFromBlockConflict               = ffe("FF10514", "Listener options firstEvent '%s' and fromBlock '%s' conflict - only one should be set", 400)
+	MsgListenerFromBlockPruned                 = ffe("FF10515", "Block %s is older than the earliest block available from the blockchain connector, which is %s", 400)
+	MsgInvalidBoolQueryParam                   = ffe("FF10516", "Invalid value '%s' for query param '%s' - must be 'true' or 'false'", 400)
+	MsgListenerStateFilterConflict             = ffe("FF10517", "Filtering on state '%s' requires includeInactive=true", 400)
+	MsgRateLimitExceeded                       = ffe("FF10518", "Rate limit exceeded for %s APIs", 429)
+	MsgDIDDocumentBatchTooLarge                = ffe("FF10519", "Too many identities to resolve - %d were supplied, and the maximum is %d", 400)
+	MsgListenerEventsSortFixed                 = ffe("FF10520", "Events for contract API listeners are always sorted by block number then log index, and cannot be sorted differently", 400)
+	MsgInvalidExportFormat                     = ffe("FF10521", "Invalid export format '%s' - must be 'ndjson' or 'csv'", 400)
+	MsgSSENotEnabled                           = ffe("FF10522", "Server-sent events are not enabled for namespace '%s'", 400)
+	MsgSSEWrongTransport                       = ffe("FF10523", "Subscription '%s' uses transport '%s' - only subscriptions with transport 'sse' can be streamed", 400)
+	MsgSSEConnectionNotActive                  = ffe("FF10524", "Server-sent events connection '%s' no longer active", 404)
+	MsgSSEAckNotMatched                        = ffe("FF10525", "Acknowledgment does not match an inflight event on server-sent events connection '%s'", 400)
+	MsgSSEAutoAckEnabled                       = ffe("FF10526", "The autoack option is enabled on server-sent events connection '%s'", 400)
+	MsgSSENoData                               = ffe("FF10527", "Server-sent events subscriptions do not support streaming the full data payload, just the references (withData must be false)", 400)
+	MsgSSEInvalidLastEventID                   = ffe("FF10528", "Invalid Last-Event-ID '%s' - must be the sequence of the last event received", 400)
+	MsgMessageBatchTooLarge                    = ffe("FF10529", "Too many messages in batch - %d were supplied, and the maximum is %d", 400)
+	MsgMessageBatchNullEntry                   = ffe("FF10530", "Message %d of batch is null", 400)
+	MsgWebhookFailedStatus                     = ffe("FF10531", "Webhook returned HTTP status %d")
+	MsgWebhookDeadLetterFastAck                = ffe("FF10532", "Webhook subscriptions cannot combine fastack with retry.deadLetter, as events are acknowledged before they are delivered", 400)
+	MsgWebhookInvalidRetryFactor               = ffe("FF10533", "Invalid retry factor %v - must be at least 1", 400)
+	MsgWebhookInvalidRetryJitter               = ffe("FF10534", "Invalid retry jitter %v - must be between 0 and 1", 400)
+	MsgDeadLetterReplayNotSupported            = ffe("FF10535", "Transport '%s' does not support replaying dead-lettered events", 400)
+	MsgDeadLetterSubscriptionNotActive         = ffe("FF10536", "Subscription '%s' is not active", 409)
+	MsgDeadLetterReplayFailed                  = ffe("FF10537", "Replay of event '%s' failed: %s", 502)
+	MsgGraphQLSyntaxError                      = ffe("FF10538", "GraphQL syntax error at line %d, column %d: unexpected '%s'", 400)
+	MsgGraphQLUnsupported                      = ffe("FF10539", "GraphQL %s is not supported - the GraphQL endpoint is read-only, and supports queries without fragments or directives", 400)
+	MsgGraphQLOperationNameRequired            = ffe("FF10540", "An operationName must be supplied, as the GraphQL document contains multiple operations", 400)
+	MsgGraphQLOperationNotFound                = ffe("FF10541", "GraphQL operation '%s' not found in the document", 400)
+	MsgGraphQLUnknownField                     = ffe("FF10542", "Unknown field '%s' on type '%s'", 400)
+	MsgGraphQLVariableUndefined                = ffe("FF10543", "Variable '$%s' is not defined, and has no default value", 400)
+	MsgGraphQLInvalidArgument                  = ffe("FF10544", "Invalid value for argument '%s': %v", 400)
+	MsgGraphQLMissingArgument                  = ffe("FF10545", "Missing required argument '%s'", 400)
+	MsgListenerOutputFilterNoField             = ffe("FF10546", "Output filter %d must specify a field", 400)
+	MsgListenerOutputFilterBadOp               = ffe("FF10547", "Output filter %d has invalid op '%s' - must be one of: %v", 400)
+	MsgListenerOutputFilterNotNumeric          = ffe("FF10548", "Output filter %d uses op '%s', which requires a numeric value, but the value is '%s'", 400)
+	MsgInvalidRetryPolicy                      = ffe("FF10549", "Invalid retry policy for operation type '%s': %s")
+	MsgInvalidEncryptionKey                    = ffe("FF10550", "Invalid encryption key '%s' - must have a unique ID, and be a hex encoded 32 byte AES-256 key")
+	MsgEncryptionKeyNotFound                   = ffe("FF10551", "Encryption key '%s' is not available on this node")
+	MsgDecryptionFailed                        = ffe("FF10552", "Failed to decrypt data encrypted with key '%s': %s")
+	MsgEncryptionTopicConflict                 = ffe("FF10553", "Topic '%s' is configured to be encrypted with both key '%s' and key '%s'")
+	MsgScheduledMessageCannotWait              = ffe("FF10554", "A message with a sendAfter time cannot be submitted with confirm=true, or as a request/reply", 400)
+	MsgMessageNotScheduled                     = ffe("FF10555", "Message '%s' is not scheduled to be sent", 409)
+	MsgInvalidBackfillFromBlock                = ffe("FF10556", "Invalid backfill fromBlock '%s' - must be a non-negative block number", 400)
+	MsgBlobUploadNotFound                      = ffe("FF10557", "Blob upload '%s' not found", 404)
+	MsgBlobUploadInProgress                    = ffe("FF10558", "Blob upload '%s' is already being written to by another request", 409)
+	MsgBlobUploadOffsetMismatch                = ffe("FF10559", "Chunk offset %d does not match the %d bytes already received for blob upload '%s'", 409)
+	MsgBlobUploadExceedsSize                   = ffe("FF10560", "Chunk exceeds the declared size of %d bytes for blob upload '%s' - only the bytes up to the declared size were kept", 400)
+	MsgBlobUploadIncomplete                    = ffe("FF10561", "Blob upload '%s' has received %d of the declared %d bytes", 400)
+	MsgBlobUploadStagingFailed                 = ffe("FF10562", "Failed to stage blob upload: %s", 500)
+	MsgBlobUploadChunkNotMultipart             = ffe("FF10563", "Blob upload chunks must be sent as a multipart/form-data file", 400)
+	MsgBlobRangeNotSatisfiable                 = ffe("FF10564", "Range '%s' cannot be satisfied for a blob of %d bytes", 416)
+	MsgInvalidBlobUploadOffset                 = ffe("FF10565", "Invalid offset '%s' - must be the number of bytes already received for the upload", 400)
+	MsgUnknownAPIRole                          = ffe("FF10566", "Unknown API role '%s' - must be one of: reader, submitter, admin", 400)
+	MsgAPIRoleForbidden                        = ffe("FF10567", "The '%s' role is required to perform this action", 403)
+	MsgAPINamespaceForbidden                   = ffe("FF10568", "Not authorized for namespace '%s'", 403)
+	MsgAPIKeyConfigInvalid                     = ffe("FF10569", "API key '%s' must be configured with a SHA-256 hash of the key, and a role")
+	MsgJWTKeyConfigMissing                     = ffe("FF10570", "JWT auth plugin '%s' must be configured with either an HMAC secret or a JWKS URL")
+	MsgJWKSFetchFailed                         = ffe("FF10571", "Failed to load JSON Web Key Set from '%s': %s")
+	MsgJWTInvalid                              = ffe("FF10572", "Invalid JSON Web Token: %s", 401)
+	MsgConfigReloadFailed                      = ffe("FF10573", "Failed to re-read the configuration: %s", 400)
+	MsgPluginNotFound                          = ffe("FF10574", "Plugin '%s' not found", 404)
+	MsgPluginResetNotSupported                 = ffe("FF10575", "Plugin '%s' is a %s plugin - only blockchain, dataexchange and tokens plugins can be reset", 400)
+	MsgSubscriptionReplayInvalidInput          = ffe("FF10576", "Exactly one of 'sequence' or 'timestamp' must be specified to replay a subscription", 400)
+	MsgInvalidRetentionWindow                  = ffe("FF10577", "Invalid retention window '%s' for namespace '%s'")
+	MsgRetentionArchiveNoSharedStorage         = ffe("FF10578", "Namespace '%s' cannot archive pruned records, as it has no shared storage plugin")
+	MsgMQTTNotConnected                        = ffe("FF10579", "Not connected to MQTT broker '%s'")
+	MsgMQTTInvalidTopic                        = ffe("FF10580", "Invalid MQTT topic '%s' - the topics of a subscription cannot contain the '+' or '#' wildcards", 400)
+	MsgMQTTTimeout                             = ffe("FF10581", "Timed out waiting for MQTT broker to confirm %s on topic '%s' after %s")
+	MsgMQTTRequestFailed                       = ffe("FF10582", "MQTT %s on topic '%s' failed")
+	MsgVerifierClaimNodeIdentity               = ffe("FF10583", "Additional verifiers cannot be claimed for node identity '%s'", 400)
+	MsgDefRejectedVerifierNotFound             = ffe("FF10584", "Rejected %s '%s' - verifier not found for identity: %s")
+	MsgUnknownFFIGenerationFormat              = ffe("FF10585", "Unknown contract interface generation format '%s'", 400)
+	MsgSolidityCompileFailed                   = ffe("FF10586", "Solidity compilation failed: %s", 400)
+	MsgSolidityContractNotFound                = ffe("FF10587", "Contract '%s' not found in Solidity compiler output", 400)
+	MsgSolidityContractNotSpecified            = ffe("FF10588", "Solidity compiler output contains %d matching contracts - specify the contract name, qualified with the source file name as 'file.sol:Contract' if required", 400)
+	MsgTokenOperatorNotApproved                = ffe("FF10589", "Signing key '%s' is not an approved operator for the balance of '%s' in pool '%s'", 400)
+	MsgTokenAllowanceInsufficient              = ffe("FF10590", "Approved allowance of '%s' for signing key '%s' on the balance of '%s' is less than the requested amount '%s'", 400)
+	MsgInvalidPinSequence                      = ffe("FF10591", "Invalid pin sequence '%s' - must be a number", 400)
+	MsgPinRewindBatchesRequired                = ffe("FF10592", "At least one batch ID must be supplied to rewind", 400)
+	MsgBridgeTargetRequired                    = ffe("FF10593", "A target namespace must be specified for the bridge", 400)
+	MsgBridgeTargetSameNamespace               = ffe("FF10594", "The target namespace of a bridge must be different to namespace '%s'", 400)
+	MsgBridgeTargetNoBroadcast                 = ffe("FF10595", "Namespace '%s' does not support broadcast messages, so cannot be the target of a bridge", 400)
+	MsgInvalidConnectorFailoverURL             = ffe("FF10596", "Invalid connector failover URL '%s'")
+	MsgConnectorHealthCheckFailed              = ffe("FF10597", "Connector health check returned HTTP status %d")
+	MsgDefinitionRetired                       = ffe("FF10598", "The %s '%s' is retired and cannot be used", 400)
+	MsgDefinitionDeprecated                    = ffe("FF10599", "The %s '%s' is deprecated and cannot be used", 400)
+	MsgBlobUploadNotReceiving                  = ffe("FF10600", "Blob upload '%s' is %s, and can no longer be written to or completed", 409)
+	MsgAsyncUploadNotMultipart                 = ffe("FF10601", "Async data uploads must be sent as a multipart/form-data file", 400)
+	MsgNamespaceRateLimitExceeded              = ffe("FF10602", "Rate limit exceeded for namespace '%s'", 429)
+	MsgRouteRateLimitExceeded                  = ffe("FF10603", "Rate limit exceeded for the %s API in namespace '%s'", 429)
+	MsgTriggerActionTypeInvalid                = ffe("FF10604", "Trigger action %d has unsupported type '%s'", 400)
+	MsgTriggerInvokeInvalid                    = ffe("FF10605", "Trigger action %d must specify an invoke with a methodPath, and either an api or an interface", 400)
+	MsgTriggerSourceNotConfirmed               = ffe("FF10606", "The %s '%s' that declared this trigger action was not confirmed")
+	MsgBatchDispatcherNotFound                 = ffe("FF10607", "Batch dispatcher '%s' not found", 404)
+	MsgBatchConfigInvalid                      = ffe("FF10608", "Invalid value for '%s' in the batch config of dispatcher '%s'", 400)
+	MsgKafkaInvalidTopic                       = ffe("FF10609", "Invalid Kafka topic '%s' - topics can only contain letters, numbers, '.', '_', '-' and placeholders", 400)
+	MsgKafkaProduceFailed                      = ffe("FF10610", "Failed to publish %d events to Kafka topic '%s'")
+	MsgDeployDefinitionRequired                = ffe("FF10611", "Either a definition or an interface is required to deploy a contract", 400)
+	MsgDeployAPIRequiresInterface              = ffe("FF10612", "An interface is required to create contract API '%s' for the deployed contract", 400)
+	MsgContractDeployDefinitionInvalid         = ffe("FF10613", "Failed to generate the definition of '%s' to deploy the contract", 400)
+	MsgInvalidValueMatch                       = ffe("FF10614", "Invalid value match '%s' - must be of the form value.<path>==<value>, with a path of object keys", 400)
+	MsgNetworkPingTimeout                      = ffe("FF10615", "Ping to node '%s' was not delivered within %s")
+	MsgSharedStorageProbeMismatch              = ffe("FF10616", "Data read back from shared storage with reference '%s' did not match the data written")
+	MsgMultipartyListenerNotFound              = ffe("FF10617", "Listener '%s' for the multiparty contract was not found by the blockchain connector")
+	MsgMultipartyListenerSyncing               = ffe("FF10618", "Listener '%s' for the multiparty contract is catching up with the chain")
+	MsgNetworkPingFailed                       = ffe("FF10619", "Ping to node '%s' failed: %s")
+	MsgGroupMemberNotFound                     = ffe("FF10620", "Member '%s' cannot be removed, as it is not a member of group '%s'", 400)
+	MsgGroupGenerationExists                   = ffe("FF10621", "Group '%s' already exists, so cannot be created as a new generation of group '%s'", 409)
+	MsgTracingInitFailed                       = ffe("FF10622", "Failed to initialize OpenTelemetry tracing")
+	MsgTracingInvalidEndpoint                  = ffe("FF10623", "Invalid OTLP endpoint '%s' for tracing")
+	MsgUnknownTLSConfig                        = ffe("FF10624", "Unknown TLS config '%s'", 400)
+	MsgUnknownSigningSecret                    = ffe("FF10625", "Unknown signing secret '%s'", 400)
+	MsgDuplicateSigningSecret                  = ffe("FF10626", "Found duplicate signing secret '%s'", 400)
+	MsgInvalidSigningSecret                    = ffe("FF10627", "Signing secret '%s' must have a name and a non-empty secret", 400)
+	MsgWebhookSigningSecretRequired            = ffe("FF10628", "A secretName is required to sign webhook requests", 400)
+	MsgInvalidChartGroupBy                     = ffe("FF10629", "Field '%s' cannot be used to group the %s collection", 400)
+	MsgInvalidChartInterval                    = ffe("FF10630", "Invalid chart interval '%s'. Must be a positive duration", 400)
+	MsgTokenPoolNotNonFungible                 = ffe("FF10631", "Token pool '%s' is not a non-fungible token pool", 400)
+	MsgTokenURISchemeUnsupported               = ffe("FF10632", "Cannot resolve token URI '%s' with an unsupported scheme")
+	MsgTokenMetadataFetchFailed                = ffe("FF10633", "Failed to fetch token metadata: %s")
+	MsgTokenMetadataInvalid                    = ffe("FF10634", "Token metadata from '%s' is not a valid JSON object")
+	MsgHARenewIntervalInvalid                  = ffe("FF10635", "HA renew interval '%s' must be less than the lease duration '%s'")
+	MsgHAUnknownLockProvider                   = ffe("FF10636", "Unknown HA lock provider '%s'")
+	MsgHADatabaseRequired                      = ffe("FF10637", "The name of the database plugin to hold the HA leader lock must be configured when there is more than one")
+	MsgHANodePassive                           = ffe("FF10638", "This node is passive, and only serves read requests. Send the request to the active node", 503)
+	MsgSubscriptionPreviewLimitInvalid         = ffe("FF10639", "Subscription preview limit must be between 1 and %d", 400)
+	MsgDXTransferBandwidthNoConcurrency        = ffe("FF10640", "The data exchange transfer bandwidth limit is shared between concurrent transfers, so transfer.maxConcurrent must also be set")
+	MsgOperationNotBlobTransfer                = ffe("FF10641", "Operation '%s' is of type '%s' - progress is only reported for blob transfers", 400)
+	MsgUnknownSubscriptionEnricher             = ffe("FF10642", "Unknown subscription enricher '%s'", 400)
+	MsgSimulateWithMessage                     = ffe("FF10643", "Simulation is not supported for requests that include a message", 400)
+	MsgBlobUploadStagingFull                   = ffe("FF10644", "Blob upload staging is full - %d bytes of the %d byte limit are already staged", 413)
+	MsgOperationNotifyHostNotAllowed           = ffe("FF10645", "Operation notification URL '%s' is not allowed - the host must be listed in operations.notify.allowedHosts", 400)
+	MsgOperationNotifyTooMany                  = ffe("FF10646", "Too many operation notifications are waiting for their operations to resolve - the limit is %d", 429)
+	MsgWSInvalidMaxMessageSize                 = ffe("FF10647", "Invalid websocket maxMessageSize %d - must be 0 for unlimited, or at least %d bytes")
+	MsgTokenURIHostNotAllowed                  = ffe("FF10648", "Cannot resolve token URI '%s' - the host must be listed in tokenMetadata.allowedHosts", 400)
+	MsgPluginResetNamespaceChanged             = ffe("FF10649", "Cannot reset plugin '%s' as the configuration of namespace '%s' that uses it has changed - reload the configuration to apply the change", 409)
+	MsgStreamedListNoCount                     = ffe("FF10650", "count=true is not supported, as the results of this route are streamed", 400)
+	MsgGroupUpdateNotMember                    = ffe("FF10651", "Identity '%s' on the local node must be a member of group '%s' to change its members, and cannot be removed from it", 400)
+	MsgPageCursorNotSupported                  = ffe("FF10652", "Page cursors are not supported when sorting on '%s', as the collection has no unique field to order items with the same value", 400)
+	MsgNetworkResyncDataMissing                = ffe("FF10653", "The data of the definition is not available on this node")
+	MsgNetworkResyncNotAccepted                = ffe("FF10654", "The definition was not accepted by the definition handler (%s): %v")
+	MsgOperationNotifySecretConflict           = ffe("FF10655", "Only one of 'secret' and 'secretName' can be set on an operation notification", 400)
+	MsgOperationNotifySecretLost               = ffe("FF10656", "The secret for the notification to '%s' was only held in memory, and was lost when the node restarted")
+	MsgGraphQLQueryTooLarge                    = ffe("FF10657", "GraphQL query is too large - the maximum length is %d bytes", 413)
+	MsgGraphQLTooDeep                          = ffe("FF10658", "GraphQL query at line %d, column %d is nested too deeply - the maximum depth is %d", 400)
+	MsgInvalidDelegatedTransferValidation      = ffe("FF10659", "Invalid delegated transfer validation '%s' - valid options are 'connector', 'recorded' or 'none'")
+	MsgTokenDelegatedTransferRejected          = ffe("FF10660", "The token connector rejected the transfer by signing key '%s' from the balance of '%s': %s", 400)
+	MsgOperationAlreadyRetried                 = ffe("FF10661", "Operation '%s' has already been retried", 409)
+	MsgBridgeTransportInternal                 = ffe("FF10662", "Subscriptions with the bridge transport can only be managed with the bridges API", 400)
+	MsgIdempotencyKeyReserved                  = ffe("FF10663", "Idempotency keys starting with '%s' are reserved for messages submitted by bridges", 400)
+	MsgGraphQLTooManyFields                    = ffe("FF10664", "GraphQL query selects too many fields - the maximum is %d", 400)
+	MsgTokenApprovalValueInvalid               = ffe("FF10665", "Recorded approval value '%s' for signing key '%s' on the balance of '%s' is not a valid amount", 400)
 )
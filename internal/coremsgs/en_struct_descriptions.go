@@ -71,11 +71,17 @@ var (
 	MessageData           = ffm("Message.data", "The list of data elements attached to the message")
 	MessagePins           = ffm("Message.pins", "For private messages, a unique pin hash:nonce is assigned for each topic")
 	MessageTransactionID  = ffm("Message.txid", "The ID of the transaction used to order/deliver this message")
-	MessageIdempotencyKey = ffm("Message.idempotencyKey", "An optional unique identifier for a message. Cannot be duplicated within a namespace, thus allowing idempotent submission of messages to the API. Local only - not transferred when the message is sent to other members of the network")
+	MessageIdempotencyKey = ffm("Message.idempotencyKey", "An optional unique identifier for a message. Cannot be duplicated within a namespace, thus allowing idempotent submission of messages to the API. Can also be set with the Idempotency-Key header. Local only - not transferred when the message is sent to other members of the network")
+	MessageSendAfter      = ffm("Message.sendAfter", "An optional time to send the message. The message is stored in the scheduled state, and is not sealed into a batch and sent until this time. Local only - not transferred when the message is sent to other members of the network")
 
 	// MessageInOut field descriptions
-	MessageInOutData  = ffm("MessageInOut.data", "For input allows you to specify data in-line in the message, that will be turned into data attachments. For output when fetchdata is used on API calls, includes the in-line data payloads of all data attachments")
-	MessageInOutGroup = ffm("MessageInOut.group", "Allows you to specify details of the private group of recipients in-line in the message. Alternative to using the header.group to specify the hash of a group that has been previously resolved")
+	MessageInOutData     = ffm("MessageInOut.data", "For input allows you to specify data in-line in the message, that will be turned into data attachments. For output when fetchdata is used on API calls, includes the in-line data payloads of all data attachments")
+	MessageInOutGroup    = ffm("MessageInOut.group", "Allows you to specify details of the private group of recipients in-line in the message. Alternative to using the header.group to specify the hash of a group that has been previously resolved")
+	MessageInOutTriggers = ffm("MessageInOut.triggers", "Follow-up actions to run once the message is confirmed. Each action is recorded as an operation in a transaction of type 'trigger', and is failed if the message is rejected")
+
+	// MessageSubmitResult field descriptions
+	MessageSubmitResultMessage = ffm("MessageSubmitResult.message", "The message as accepted for sending, including its assigned ID. Not set if the message was rejected")
+	MessageSubmitResultError   = ffm("MessageSubmitResult.error", "The reason the message was rejected, if it could not be accepted for sending")
 
 	// InputGroup field descriptions
 	InputGroupName    = ffm("InputGroup.name", "Optional name for the group. Allows you to have multiple separate groups with the same list of participants")
@@ -98,6 +104,7 @@ var (
 	GroupMembers        = ffm("Group.members", "The list of members in this privacy group")
 	GroupMessage        = ffm("Group.message", "The message used to broadcast this group privately to the members")
 	GroupHash           = ffm("Group.hash", "The identifier hash of this group. Derived from the name and group members")
+	GroupPrevious       = ffm("Group.previous", "The hash of the previous generation of this group, when this group was created by changing the members of an existing group")
 	GroupCreated        = ffm("Group.created", "The time when the group was first used to send a message in the network")
 
 	// MemberInput field descriptions
@@ -112,6 +119,25 @@ var (
 	DataRefID   = ffm("DataRef.id", "The UUID of the referenced data resource")
 	DataRefHash = ffm("DataRef.hash", "The hash of the referenced data")
 
+	// BlobUploadInput field descriptions
+	BlobUploadInputValidator = ffm("BlobUploadInput.validator", "The data validator type to use for the value of the data item")
+	BlobUploadInputDatatype  = ffm("BlobUploadInput.datatype", "The optional datatype to use for validation of the value of the data item")
+	BlobUploadInputValue     = ffm("BlobUploadInput.value", "The metadata value for the data item, which the blob is attached to")
+	BlobUploadInputFilename  = ffm("BlobUploadInput.filename", "The filename of the blob, added to the value when autometa is set")
+	BlobUploadInputMimetype  = ffm("BlobUploadInput.mimetype", "The mimetype of the blob, added to the value when autometa is set")
+	BlobUploadInputAutoMeta  = ffm("BlobUploadInput.autometa", "When set, the filename and mimetype are added to the value of the data item")
+	BlobUploadInputSize      = ffm("BlobUploadInput.size", "The optional total size of the blob in bytes. When set, chunks beyond this size are rejected, and the upload cannot be completed until all the bytes are received")
+
+	// BlobUpload field descriptions
+	BlobUploadID        = ffm("BlobUpload.id", "The UUID of the blob upload")
+	BlobUploadNamespace = ffm("BlobUpload.namespace", "The namespace of the blob upload")
+	BlobUploadCreated   = ffm("BlobUpload.created", "The time the blob upload was started")
+	BlobUploadOffset    = ffm("BlobUpload.offset", "The number of bytes received so far, which the next chunk must start from")
+	BlobUploadStatus    = ffm("BlobUpload.status", "The status of the upload. Resumable uploads are receiving until completed, and async uploads move from pending to processing, then succeeded or failed")
+	BlobUploadProcessed = ffm("BlobUpload.processed", "For an async upload, the number of bytes hashed and transferred to data exchange so far")
+	BlobUploadData      = ffm("BlobUpload.data", "For a succeeded async upload, the UUID of the data item that was created")
+	BlobUploadError     = ffm("BlobUpload.error", "For a failed async upload, the error that occurred")
+
 	// BlobRef field descriptions
 	BlobRefHash   = ffm("BlobRef.hash", "The hash of the binary blob data")
 	BlobRefSize   = ffm("BlobRef.size", "The size of the binary data")
@@ -144,6 +170,7 @@ var (
 	DatatypeHash      = ffm("Datatype.hash", "The hash of the value, such as the JSON schema. Allows all parties to be confident they have the exact same rules for verifying data created against a datatype")
 	DatatypeCreated   = ffm("Datatype.created", "The time the datatype was created")
 	DatatypeValue     = ffm("Datatype.value", "The definition of the datatype, in the syntax supported by the validator (such as a JSON Schema definition)")
+	DatatypeLifecycle = ffm("Datatype.lifecycle", "The local lifecycle state of this version of the datatype - active, deprecated or retired. Retired datatypes cannot be used to validate new data")
 
 	// SignerRef field descriptions
 	SignerRefAuthor = ffm("SignerRef.author", "The DID of identity of the submitter")
@@ -167,6 +194,17 @@ var (
 	BatchManifestMessages = ffm("BatchManifest.messages", "Array of manifest entries, succinctly summarizing the messages in the batch")
 	BatchManifestData     = ffm("BatchManifest.data", "Array of manifest entries, succinctly summarizing the data in the batch")
 
+	// MessageProof field descriptions
+	MessageProofMessage   = ffm("MessageProof.message", "The ID and hash of the message")
+	MessageProofHeader    = ffm("MessageProof.header", "The header of the message. The SHA-256 hash of the header is the hash of the message")
+	MessageProofData      = ffm("MessageProof.data", "The list of data references of the message. The SHA-256 hash of the list is the dataHash in the header")
+	MessageProofBatch     = ffm("MessageProof.batch", "The UUID of the batch the message was included in")
+	MessageProofBatchHash = ffm("MessageProof.batchHash", "The hash of the batch, pinned to the blockchain for pinned batches. This is the SHA-256 hash of the manifest")
+	MessageProofIndex     = ffm("MessageProof.index", "The index of the message in the messages array of the manifest")
+	MessageProofManifest  = ffm("MessageProof.manifest", "The exact manifest of the batch, containing the hashes of all messages and data in the batch")
+	MessageProofTX        = ffm("MessageProof.tx", "The FireFly transaction associated with the batch")
+	MessageProofPin       = ffm("MessageProof.pin", "The blockchain event that pinned the batch hash, including the blockchain transaction to look it up independently. Not set for unpinned batches, or batches that are not yet confirmed")
+
 	// BatchPersisted field descriptions
 	BatchPersistedHash       = ffm("Batch.hash", "The hash of the manifest of the batch")
 	BatchPersistedManifest   = ffm("Batch.manifest", "The manifest of the batch")
@@ -184,33 +222,81 @@ var (
 	TransactionBlockchainIDs  = ffm("Transaction.blockchainIds", "The blockchain transaction ID, in the format specific to the blockchain involved in the transaction. Not all FireFly transactions include a blockchain. FireFly transactions are extensible to support multiple blockchain transactions")
 
 	// Operation field description
-	OperationID          = ffm("Operation.id", "The UUID of the operation")
-	OperationNamespace   = ffm("Operation.namespace", "The namespace of the operation")
-	OperationTransaction = ffm("Operation.tx", "The UUID of the FireFly transaction the operation is part of")
-	OperationType        = ffm("Operation.type", "The type of the operation")
-	OperationStatus      = ffm("Operation.status", "The current status of the operation")
-	OperationPlugin      = ffm("Operation.plugin", "The plugin responsible for performing the operation")
-	OperationInput       = ffm("Operation.input", "The input to this operation")
-	OperationOutput      = ffm("Operation.output", "Any output reported back from the plugin for this operation")
-	OperationError       = ffm("Operation.error", "Any error reported back from the plugin for this operation")
-	OperationCreated     = ffm("Operation.created", "The time the operation was created")
-	OperationUpdated     = ffm("Operation.updated", "The last update time of the operation")
-	OperationRetry       = ffm("Operation.retry", "If this operation was initiated as a retry to a previous operation, this field points to the UUID of the operation being retried")
+	OperationID           = ffm("Operation.id", "The UUID of the operation")
+	OperationNamespace    = ffm("Operation.namespace", "The namespace of the operation")
+	OperationTransaction  = ffm("Operation.tx", "The UUID of the FireFly transaction the operation is part of")
+	OperationType         = ffm("Operation.type", "The type of the operation")
+	OperationStatus       = ffm("Operation.status", "The current status of the operation")
+	OperationPlugin       = ffm("Operation.plugin", "The plugin responsible for performing the operation")
+	OperationInput        = ffm("Operation.input", "The input to this operation")
+	OperationOutput       = ffm("Operation.output", "Any output reported back from the plugin for this operation")
+	OperationError        = ffm("Operation.error", "Any error reported back from the plugin for this operation")
+	OperationCreated      = ffm("Operation.created", "The time the operation was created")
+	OperationUpdated      = ffm("Operation.updated", "The last update time of the operation")
+	OperationRetry        = ffm("Operation.retry", "If this operation was initiated as a retry to a previous operation, this field points to the UUID of the operation being retried")
+	OperationRetryParent  = ffm("Operation.retryParent", "If this operation was initiated as a retry, this field points to the UUID of the operation that was retried to create it")
+	OperationRetryDepth   = ffm("Operation.retryDepth", "The number of retries that preceded this operation in its retry chain. Zero for an operation that is not a retry")
+	OperationRetryHistory = ffm("Operation.retryHistory", "The failures earlier in the retry chain of the operation, and of the operation itself, with the decision made by the automatic retry policy for the operation type")
+	OperationRetryAt      = ffm("Operation.retryAt", "The time an automatic retry of the failed operation is due, set by the retry policy for the operation type and cleared once the operation is retried")
+	OperationProgress     = ffm("Operation.progress", "The progress reported by the data exchange for a blob transfer operation")
+
+	// TransferProgress field descriptions
+	TransferProgressTransferred = ffm("TransferProgress.transferred", "The number of bytes of the blob sent to the recipient so far")
+	TransferProgressSize        = ffm("TransferProgress.size", "The total size of the blob in bytes")
+	TransferProgressUpdated     = ffm("TransferProgress.updated", "The time the data exchange last reported progress")
+
+	// OperationProgress field descriptions
+	OperationProgressID             = ffm("OperationProgress.id", "The UUID of the blob transfer operation")
+	OperationProgressStatus         = ffm("OperationProgress.status", "The current status of the operation")
+	OperationProgressTransferred    = ffm("OperationProgress.transferred", "The number of bytes of the blob sent to the recipient so far")
+	OperationProgressSize           = ffm("OperationProgress.size", "The total size of the blob in bytes, if reported by the data exchange")
+	OperationProgressPercent        = ffm("OperationProgress.percent", "The percentage of the blob sent to the recipient so far")
+	OperationProgressBytesPerSecond = ffm("OperationProgress.bytesPerSecond", "The average rate the blob has been sent at, since the operation was created")
+	OperationProgressUpdated        = ffm("OperationProgress.updated", "The time the data exchange last reported progress")
+
+	// OperationRetryRequest field descriptions
+	OperationRetryRequestIDs = ffm("OperationRetryRequest.ids", "The UUIDs of the operations to retry. If empty, all failed operations matching the filter query parameters are retried")
+
+	// OperationRetryResult field descriptions
+	OperationRetryResultID        = ffm("OperationRetryResult.id", "The UUID of the operation requested to be retried")
+	OperationRetryResultRetried   = ffm("OperationRetryResult.retried", "True if a retry of the operation was submitted")
+	OperationRetryResultOperation = ffm("OperationRetryResult.operation", "The new operation created to perform the retry")
+	OperationRetryResultError     = ffm("OperationRetryResult.error", "The reason the operation was not retried, or the retry failed to submit")
+
+	// OperationNotifyInput field descriptions
+	OperationNotifyInputURL        = ffm("OperationNotifyInput.url", "The http or https URL that the operation is POSTed to, once it reaches a terminal state. The host must be listed in the operations.notify.allowedHosts config")
+	OperationNotifyInputSecret     = ffm("OperationNotifyInput.secret", "An optional secret used to sign the callback body with HMAC-SHA256, in the X-FireFly-Signature header. It is only held in memory, so if the node restarts before the notification is delivered it is not sent. Use secretName for a signed notification that survives a restart")
+	OperationNotifyInputSecretName = ffm("OperationNotifyInput.secretName", "The name of a signing secret configured on the namespace, used instead of secret to sign the callback body")
 
 	// OperationWithDetail field description
-	OperationWithDetail = ffm("OperationWithDetail.detail", "Additional detailed information about an operation provided by the connector")
+	OperationWithDetail          = ffm("OperationWithDetail.detail", "Additional detailed information about an operation provided by the connector")
+	OperationWithDetailLastError = ffm("OperationWithDetail.lastError", "The structured error returned by the connector on the last failure of the operation. Only included when verbose=true is requested")
+
+	// OperationError field descriptions
+	OperationErrorStatusCode = ffm("OperationError.statusCode", "The HTTP status code returned by the connector")
+	OperationErrorCode       = ffm("OperationError.code", "The error code reported by the connector, if one could be extracted from the error")
+	OperationErrorBody       = ffm("OperationError.body", "The raw response body returned by the connector, with configured secret fields redacted")
+	OperationErrorTruncated  = ffm("OperationError.truncated", "True if the body was truncated to the configured maximum size")
+
+	// OperationRetryRecord field descriptions
+	OperationRetryRecordOperation = ffm("OperationRetryRecord.operation", "The UUID of the operation that failed")
+	OperationRetryRecordFailed    = ffm("OperationRetryRecord.failed", "The time the failure was recorded")
+	OperationRetryRecordError     = ffm("OperationRetryRecord.error", "The error the operation failed with")
+	OperationRetryRecordOutcome   = ffm("OperationRetryRecord.outcome", "The decision made by the automatic retry policy - 'retry' if a retry was scheduled, 'terminal' if the error is not retryable, or 'exhausted' if the maximum number of retries had been reached")
+	OperationRetryRecordRetryAt   = ffm("OperationRetryRecord.retryAt", "The time the automatic retry was scheduled for")
 
 	// BlockchainEvent field descriptions
-	BlockchainEventID         = ffm("BlockchainEvent.id", "The UUID assigned to the event by FireFly")
-	BlockchainEventSource     = ffm("BlockchainEvent.source", "The blockchain plugin or token service that detected the event")
-	BlockchainEventNamespace  = ffm("BlockchainEvent.namespace", "The namespace of the listener that detected this blockchain event")
-	BlockchainEventName       = ffm("BlockchainEvent.name", "The name of the event in the blockchain smart contract")
-	BlockchainEventListener   = ffm("BlockchainEvent.listener", "The UUID of the listener that detected this event, or nil for built-in events in the system namespace")
-	BlockchainEventProtocolID = ffm("BlockchainEvent.protocolId", "An alphanumerically sortable string that represents this event uniquely on the blockchain (convention for plugins is zero-padded values BLOCKNUMBER/TXN_INDEX/EVENT_INDEX)")
-	BlockchainEventOutput     = ffm("BlockchainEvent.output", "The data output by the event, parsed to JSON according to the interface of the smart contract")
-	BlockchainEventInfo       = ffm("BlockchainEvent.info", "Detailed blockchain specific information about the event, as generated by the blockchain connector")
-	BlockchainEventTimestamp  = ffm("BlockchainEvent.timestamp", "The time allocated to this event by the blockchain. This is the block timestamp for most blockchain connectors")
-	BlockchainEventTX         = ffm("BlockchainEvent.tx", "If this blockchain event is coorelated to FireFly transaction such as a FireFly submitted token transfer, this field is set to the UUID of the FireFly transaction")
+	BlockchainEventID              = ffm("BlockchainEvent.id", "The UUID assigned to the event by FireFly")
+	BlockchainEventSource          = ffm("BlockchainEvent.source", "The blockchain plugin or token service that detected the event")
+	BlockchainEventNamespace       = ffm("BlockchainEvent.namespace", "The namespace of the listener that detected this blockchain event")
+	BlockchainEventName            = ffm("BlockchainEvent.name", "The name of the event in the blockchain smart contract")
+	BlockchainEventListener        = ffm("BlockchainEvent.listener", "The UUID of the listener that detected this event, or nil for built-in events in the system namespace")
+	BlockchainEventProtocolID      = ffm("BlockchainEvent.protocolId", "An alphanumerically sortable string that represents this event uniquely on the blockchain (convention for plugins is zero-padded values BLOCKNUMBER/TXN_INDEX/EVENT_INDEX)")
+	BlockchainEventOutput          = ffm("BlockchainEvent.output", "The data output by the event, parsed to JSON according to the interface of the smart contract")
+	BlockchainEventOutputTruncated = ffm("BlockchainEvent.outputTruncated", "True if the output exceeded the configured maximum indexed size, so only a subset of the fields are stored on the event. The full output can be retrieved from the output endpoint of the event")
+	BlockchainEventInfo            = ffm("BlockchainEvent.info", "Detailed blockchain specific information about the event, as generated by the blockchain connector")
+	BlockchainEventTimestamp       = ffm("BlockchainEvent.timestamp", "The time allocated to this event by the blockchain. This is the block timestamp for most blockchain connectors")
+	BlockchainEventTX              = ffm("BlockchainEvent.tx", "If this blockchain event is coorelated to FireFly transaction such as a FireFly submitted token transfer, this field is set to the UUID of the FireFly transaction")
 
 	// ChartHistogram field descriptions
 	ChartHistogramCount     = ffm("ChartHistogram.count", "Total count of entries in this time bucket within the histogram")
@@ -222,6 +308,23 @@ var (
 	ChartHistogramTypeCount = ffm("ChartHistogramType.count", "Count of entries of a given type within a bucket")
 	ChartHistogramTypeType  = ffm("ChartHistogramType.type", "Name of the type")
 
+	// ChartTimeSeriesBucket field descriptions
+	ChartTimeSeriesBucketTimestamp = ffm("ChartTimeSeriesBucket.timestamp", "Starting timestamp of the bucket")
+	ChartTimeSeriesBucketCount     = ffm("ChartTimeSeriesBucket.count", "Total count of records in the bucket")
+	ChartTimeSeriesBucketGroups    = ffm("ChartTimeSeriesBucket.groups", "Separate counts for each value of the group by field within the bucket")
+
+	// ChartTimeSeriesGroup field descriptions
+	ChartTimeSeriesGroupGroup = ffm("ChartTimeSeriesGroup.group", "The value of the group by field")
+	ChartTimeSeriesGroupCount = ffm("ChartTimeSeriesGroup.count", "Count of records in the bucket with this value")
+
+	// HAStatus field descriptions
+	HAStatusEnabled     = ffm("HAStatus.enabled", "True if this node is one of a set of nodes, of which only one is active")
+	HAStatusNodeID      = ffm("HAStatus.nodeId", "The unique ID of this node in the election of the active node")
+	HAStatusMode        = ffm("HAStatus.mode", "Active if this node runs batching, aggregation and event delivery, or passive if it only serves read requests")
+	HAStatusLeader      = ffm("HAStatus.leader", "The ID of the node that holds the leader lock, and is active")
+	HAStatusLeaseExpiry = ffm("HAStatus.leaseExpiry", "The time the leader lock expires, unless the active node renews it")
+	HAStatusModeChanged = ffm("HAStatus.modeChanged", "The time this node entered its current mode")
+
 	// ContractAPI field descriptions
 	ContractAPIID          = ffm("ContractAPI.id", "The UUID of the contract API")
 	ContractAPINamespace   = ffm("ContractAPI.namespace", "The namespace of the contract API")
@@ -314,10 +417,44 @@ var (
 	ContractListenerOptions   = ffm("ContractListener.options", "Options that control how the listener subscribes to events from the underlying blockchain")
 	ContractListenerEventPath = ffm("ContractListener.eventPath", "Deprecated: Please use 'eventPath' in the array of 'filters' instead")
 	ContractListenerSignature = ffm("ContractListener.signature", "A concatenation of all the stringified signature of the event and location, as computed by the blockchain plugin")
-	ContractListenerState     = ffm("ContractListener.state", "This field is provided for the event listener implementation of the blockchain provider to record state, such as checkpoint information")
+	ContractListenerState     = ffm("ContractListener.state", "Whether the listener is active, or has been deleted and is only kept for audit")
+
+	// IdleContractListener field descriptions
+	IdleContractListenerAge = ffm("IdleContractListener.age", "The time since the contract listener was created")
+
+	// ContractAPIListenerHealth field descriptions
+	ContractAPIListenerHealthTotal           = ffm("ContractAPIListenerHealth.total", "The number of listeners on events of the contract API")
+	ContractAPIListenerHealthFiredRecently   = ffm("ContractAPIListenerHealth.firedRecently", "The number of listeners that delivered a blockchain event within the requested window")
+	ContractAPIListenerHealthNeverFired      = ffm("ContractAPIListenerHealth.neverFired", "The number of listeners that have not delivered any blockchain events since they were created")
+	ContractAPIListenerHealthSyncing         = ffm("ContractAPIListenerHealth.syncing", "The number of listeners that the blockchain connector reports are still catching up with the chain")
+	ContractAPIListenerHealthDrifted         = ffm("ContractAPIListenerHealth.drifted", "The number of listeners that no longer exist in the blockchain connector")
+	ContractAPIListenerHealthStatusErrors    = ffm("ContractAPIListenerHealth.statusErrors", "The number of listeners whose status could not be retrieved from the blockchain connector")
+	ContractAPIListenerHealthOldestLastEvent = ffm("ContractAPIListenerHealth.oldestLastEvent", "The oldest of the most recent event times of each listener that has fired. A listener that stopped firing long ago shows here")
+
+	// ContractListenerDeletion field descriptions
+	ContractListenerDeletionID           = ffm("ContractListenerDeletion.id", "The UUID of the deleted contract listener")
+	ContractListenerDeletionName         = ffm("ContractListenerDeletion.name", "The name of the deleted contract listener")
+	ContractListenerDeletionBackendID    = ffm("ContractListenerDeletion.backendId", "The ID the blockchain connector assigned to the listener")
+	ContractListenerDeletionDeregistered = ffm("ContractListenerDeletion.deregistered", "True once the listener has been removed from the blockchain connector")
+
+	// ContractListenerUpdate field descriptions
+	ContractListenerUpdateID        = ffm("ContractListenerUpdate.id", "The UUID of the contract listener to update")
+	ContractListenerUpdateName      = ffm("ContractListenerUpdate.name", "A new name for the listener")
+	ContractListenerUpdateOptions   = ffm("ContractListenerUpdate.options", "New options for the listener. Output filters are applied by FireFly, and other options are applied on the blockchain connector")
+	ContractListenerUpdateTopic     = ffm("ContractListenerUpdate.topic", "Cannot be changed. If set, must match the topic of the listener")
+	ContractListenerUpdateSignature = ffm("ContractListenerUpdate.signature", "Cannot be changed. If set, must match the signature of the listener")
+	ContractListenerUpdateLocation  = ffm("ContractListenerUpdate.location", "Cannot be changed. If set, must match the location of the listener")
+	ContractListenerUpdateEvent     = ffm("ContractListenerUpdate.event", "Cannot be changed. If set, must match the event of the listener")
 
 	// ContractListenerOptions field descriptions
-	ContractListenerOptionsFirstEvent = ffm("ContractListenerOptions.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors. Default is 'newest'")
+	ContractListenerOptionsFirstEvent    = ffm("ContractListenerOptions.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors. Default is 'newest'")
+	ContractListenerOptionsFromBlock     = ffm("ContractListenerOptions.fromBlock", "A historical block number, or 'oldest', to replay events from when creating the listener. Once created, this is the block the blockchain connector started the listener from")
+	ContractListenerOptionsOutputFilters = ffm("ContractListenerOptions.outputFilters", "Conditions on the decoded output of an event. Only events that meet all of the conditions are recorded and delivered to subscriptions")
+
+	// ListenerOutputFilter field descriptions
+	ListenerOutputFilterField = ffm("ListenerOutputFilter.field", "The field in the decoded event output to test. Nested fields are separated with '.', and array entries are selected by index")
+	ListenerOutputFilterOp    = ffm("ListenerOutputFilter.op", "The comparison to make between the field and the value. The gt, gte, lt and lte comparisons only match numeric fields")
+	ListenerOutputFilterValue = ffm("ListenerOutputFilter.value", "The value to compare the field to, as a string. Numbers may be decimal or 0x prefixed hex")
 
 	ListenerFilterInterface = ffm("ListenerFilter.interface", "A reference to an existing FFI, containing pre-registered type information for the event")
 	ListenerFilterEvent     = ffm("ListenerFilter.event", "The definition of the event, either provided in-line when creating the listener, or extracted from the referenced FFI")
@@ -330,6 +467,28 @@ var (
 	DIDDocumentID                 = ffm("DIDDocument.id", "See https://www.w3.org/TR/did-core/#did-document-properties")
 	DIDDocumentAuthentication     = ffm("DIDDocument.authentication", "See https://www.w3.org/TR/did-core/#did-document-properties")
 	DIDDocumentVerificationMethod = ffm("DIDDocument.verificationMethod", "See https://www.w3.org/TR/did-core/#did-document-properties")
+	DIDDocumentAssertionMethod    = ffm("DIDDocument.assertionMethod", "See https://www.w3.org/TR/did-core/#assertion")
+	DIDDocumentService            = ffm("DIDDocument.service", "See https://www.w3.org/TR/did-core/#services")
+
+	// DIDService field descriptions
+	DIDServiceID              = ffm("DIDService.id", "See https://www.w3.org/TR/did-core/#services")
+	DIDServiceType            = ffm("DIDService.type", "See https://www.w3.org/TR/did-core/#services")
+	DIDServiceServiceEndpoint = ffm("DIDService.serviceEndpoint", "The endpoint from the profile of a FireFly node belonging to the org that owns the identity")
+
+	// DIDDocumentVerification field descriptions
+	DIDDocumentVerificationID                  = ffm("DIDDocumentVerification.id", "The DID of the document that was verified")
+	DIDDocumentVerificationIdentity            = ffm("DIDDocumentVerification.identity", "The UUID of the identity that owns the DID")
+	DIDDocumentVerificationValid               = ffm("DIDDocumentVerification.valid", "True if the document contains at least one verification method, and every verification method matches the confirmed identity claim")
+	DIDDocumentVerificationVerificationMethods = ffm("DIDDocumentVerification.verificationMethods", "The result of verifying each verification method in the document")
+
+	// DIDDocumentResult field descriptions
+	DIDDocumentResultDocument = ffm("DIDDocumentResult.document", "The resolved DID document")
+	DIDDocumentResultError    = ffm("DIDDocumentResult.error", "The reason the identity could not be resolved")
+
+	// DIDVerificationMethodVerification field descriptions
+	DIDVerificationMethodVerificationID    = ffm("DIDVerificationMethodVerification.id", "The ID of the verification method, as supplied in the document")
+	DIDVerificationMethodVerificationValid = ffm("DIDVerificationMethodVerification.valid", "True if the verification method matches a verifier registered for the identity")
+	DIDVerificationMethodVerificationError = ffm("DIDVerificationMethodVerification.error", "The reason the verification method failed verification")
 
 	// DIDVerificationMethod field descriptions
 	DIDVerificationMethodID                  = ffm("DIDVerificationMethod.id", "See https://www.w3.org/TR/did-core/#service-properties")
@@ -338,6 +497,17 @@ var (
 	DIDVerificationMethodBlockchainAccountID = ffm("DIDVerificationMethod.blockchainAcountId", "For blockchains like Ethereum that represent signing identities directly by their public key summarized in an account string")
 	DIDVerificationMethodMSPIdentityString   = ffm("DIDVerificationMethod.mspIdentityString", "For Hyperledger Fabric where the signing identity is represented by an MSP identifier (containing X509 certificate DN strings) that were validated by your local MSP")
 	DIDVerificationMethodDataExchangePeerID  = ffm("DIDVerificationMethod.dataExchangePeerID", "A string provided by your Data Exchange plugin, that it uses a technology specific mechanism to validate against when messages arrive from this identity")
+	DIDVerificationMethodValidFrom           = ffm("DIDVerificationMethod.validFrom", "The time the verifier was established for the identity on this node")
+	DIDVerificationMethodValidUntil          = ffm("DIDVerificationMethod.validUntil", "The time the verifier was deprecated on this node. A deprecated verification method is not listed for authentication or assertion")
+
+	// VerifierHistoryEntry field descriptions
+	VerifierHistoryEntryAction        = ffm("VerifierHistoryEntry.action", "The change to the verifier recorded by this entry")
+	VerifierHistoryEntryHash          = ffm("VerifierHistoryEntry.hash", "Hash used as a globally consistent identifier for the verifier")
+	VerifierHistoryEntryVerifier      = ffm("VerifierHistoryEntry.verifier", "The type and value of the verifier")
+	VerifierHistoryEntryTimestamp     = ffm("VerifierHistoryEntry.timestamp", "The time the message that established the verifier was confirmed, from which the verifier is valid")
+	VerifierHistoryEntryMessage       = ffm("VerifierHistoryEntry.message", "The UUID of the identity claim message that established the verifier")
+	VerifierHistoryEntryTransaction   = ffm("VerifierHistoryEntry.tx", "The UUID of the FireFly transaction that pinned the identity claim message")
+	VerifierHistoryEntryBlockchainIDs = ffm("VerifierHistoryEntry.blockchainIds", "The blockchain transaction IDs that pinned the identity claim message, which can be checked independently on the chain")
 
 	// Event field descriptions
 	EventID          = ffm("Event.id", "The UUID assigned to this event by your local FireFly node")
@@ -360,6 +530,7 @@ var (
 	EnrichedEventNamespaceDetails  = ffm("EnrichedEvent.namespaceDetails", "Full resource detail of a Namespace if referenced by the FireFly event")
 	EnrichedEventTokenApproval     = ffm("EnrichedEvent.tokenApproval", "A Token Approval if referenced by the FireFly event")
 	EnrichedEventTokenPool         = ffm("EnrichedEvent.tokenPool", "A Token Pool if referenced by the FireFly event")
+	EnrichedEventOperation         = ffm("EnrichedEvent.operation", "An Operation if referenced by the FireFly event")
 	EnrichedEventTokenTransfer     = ffm("EnrichedEvent.tokenTransfer", "A Token Transfer if referenced by the FireFly event")
 	EnrichedEventTransaction       = ffm("EnrichedEvent.transaction", "A Transaction if associated with the FireFly event")
 
@@ -401,13 +572,24 @@ var (
 	IdentityUpdateIdentity = ffm("IdentityUpdate.identity", "The identity being updated")
 	IdentityUpdateProfile  = ffm("IdentityUpdate.profile", "The new profile, which is replaced in its entirety when the update is confirmed")
 
+	// IdentityVerifierClaim field descriptions
+	IdentityVerifierClaimIdentity  = ffm("IdentityVerifierClaim.identity", "The identity that is claiming the verifier")
+	IdentityVerifierClaimVerifier  = ffm("IdentityVerifierClaim.verifier", "The new verifier being claimed by the identity")
+	IdentityVerifierClaimDeprecate = ffm("IdentityVerifierClaim.deprecate", "Existing verifiers of the identity that are deprecated when the claim is confirmed")
+
+	// IdentityVerifierClaimDTO field descriptions
+	IdentityVerifierClaimDTOKey       = ffm("IdentityVerifierClaimDTO.key", "The new blockchain signing key to claim for the identity")
+	IdentityVerifierClaimDTODeprecate = ffm("IdentityVerifierClaimDTO.deprecate", "When true, all existing blockchain signing keys of the identity are deprecated when the claim is confirmed")
+
 	// Verifier field descriptions
-	VerifierHash      = ffm("Verifier.hash", "Hash used as a globally consistent identifier for this namespace + type + value combination on every node in the network")
-	VerifierIdentity  = ffm("Verifier.identity", "The UUID of the parent identity that has claimed this verifier")
-	VerifierType      = ffm("Verifier.type", "The type of the verifier")
-	VerifierValue     = ffm("Verifier.value", "The verifier string, such as an Ethereum address, or Fabric MSP identifier")
-	VerifierNamespace = ffm("Verifier.namespace", "The namespace of the verifier")
-	VerifierCreated   = ffm("Verifier.created", "The time this verifier was created on this node")
+	VerifierHash       = ffm("Verifier.hash", "Hash used as a globally consistent identifier for this namespace + type + value combination on every node in the network")
+	VerifierIdentity   = ffm("Verifier.identity", "The UUID of the parent identity that has claimed this verifier")
+	VerifierType       = ffm("Verifier.type", "The type of the verifier")
+	VerifierValue      = ffm("Verifier.value", "The verifier string, such as an Ethereum address, or Fabric MSP identifier")
+	VerifierNamespace  = ffm("Verifier.namespace", "The namespace of the verifier")
+	VerifierMessage    = ffm("Verifier.message", "The UUID of the verifier claim message that added this verifier to an existing identity. Not set for the verifier established by the identity claim")
+	VerifierCreated    = ffm("Verifier.created", "The time this verifier was created on this node")
+	VerifierDeprecated = ffm("Verifier.deprecated", "The time this verifier was deprecated on this node, after which it can no longer be used to sign on behalf of the identity")
 
 	// Namespace field descriptions
 	NamespaceName                  = ffm("Namespace.name", "The local namespace name")
@@ -426,16 +608,43 @@ var (
 	MultipartyContractInfo         = ffm("MultipartyContract.info", "Additional info about the current status of the multi-party contract")
 	NetworkActionType              = ffm("NetworkAction.type", "The action to be performed")
 
+	// OperationNotification field descriptions
+	OperationNotificationID           = ffm("OperationNotification.id", "The UUID of the operation notification registration")
+	OperationNotificationNamespace    = ffm("OperationNotification.namespace", "The namespace of the operation")
+	OperationNotificationOperation    = ffm("OperationNotification.operation", "The UUID of the operation the notification is registered against")
+	OperationNotificationURL          = ffm("OperationNotification.url", "The URL the operation is posted to when it reaches a terminal state")
+	OperationNotificationSecretName   = ffm("OperationNotification.secretName", "The name of the namespace signing secret used to sign the notification")
+	OperationNotificationInlineSecret = ffm("OperationNotification.inlineSecret", "True if the notification is signed with a secret that was supplied on registration, and is only held in memory")
+	OperationNotificationCreated      = ffm("OperationNotification.created", "The time the notification was registered")
+
+	// NetworkResync field descriptions
+	NetworkResyncMessages           = ffm("NetworkResync.messages", "The number of identity definition messages that were replayed")
+	NetworkResyncSkipped            = ffm("NetworkResync.skipped", "The number of identity definition messages that could not be replayed, because their data is missing or they were not accepted")
+	NetworkResyncSkippedDefinitions = ffm("NetworkResync.skippedDefinitions", "The identity definition messages that could not be replayed, with the reason for each. Identities defined by these messages may still be missing or out of date in the network map")
+	NetworkResyncCreated            = ffm("NetworkResync.created", "The IDs of identities that were missing from the network map, and have been added")
+	NetworkResyncUpdated            = ffm("NetworkResync.updated", "The IDs of identities whose profile or verification differed from the definitions, and have been updated")
+	NetworkResyncUnchanged          = ffm("NetworkResync.unchanged", "The number of identities that already matched their definitions")
+
+	// NetworkResyncSkipped field descriptions
+	NetworkResyncSkippedMessage = ffm("NetworkResyncSkipped.message", "The ID of the identity definition message that was skipped")
+	NetworkResyncSkippedTag     = ffm("NetworkResyncSkipped.tag", "The tag of the identity definition message, which identifies the type of definition")
+	NetworkResyncSkippedReason  = ffm("NetworkResyncSkipped.reason", "Why the definition could not be replayed")
+
 	// NamespaceWithInitStatus field descriptions
 	NamespaceWithInitStatusInitializing        = ffm("NamespaceWithInitStatus.initializing", "Set to true if the namespace is still initializing")
 	NamespaceWithInitStatusInitializationError = ffm("NamespaceWithInitStatus.initializationError", "Set to a non-empty string in the case that the namespace is currently failing to initialize")
+	NamespaceWithInitStatusMultiparty          = ffm("NamespaceWithInitStatus.multiparty", "Set to true if multiparty mode is enabled for the namespace. Only returned when plugin details are requested")
+	NamespaceWithInitStatusDefaultKey          = ffm("NamespaceWithInitStatus.defaultKey", "The default signing key configured for the namespace. Only returned when plugin details are requested")
+	NamespaceWithInitStatusPlugins             = ffm("NamespaceWithInitStatus.plugins", "The plugins bound to the namespace. Only returned when plugin details are requested")
 
 	// NamespaceStatus field descriptions
-	NodeNamespace       = ffm("NamespaceStatus.namespace", "The namespace that this status applies to")
-	NamespaceStatusNode = ffm("NamespaceStatus.node", "Details of the local node")
-	NamespaceStatusOrg  = ffm("NamespaceStatus.org", "Details of the root organization identity registered for this namespace on the local node")
-	NamespacePlugins    = ffm("NamespaceStatus.plugins", "Information about plugins configured on this namespace")
-	NamespaceMultiparty = ffm("NamespaceStatus.multiparty", "Information about the multi-party system configured on this namespace")
+	NodeNamespace                = ffm("NamespaceStatus.namespace", "The namespace that this status applies to")
+	NamespaceStatusNode          = ffm("NamespaceStatus.node", "Details of the local node")
+	NamespaceStatusOrg           = ffm("NamespaceStatus.org", "Details of the root organization identity registered for this namespace on the local node")
+	NamespacePlugins             = ffm("NamespaceStatus.plugins", "Information about plugins configured on this namespace")
+	NamespaceMultiparty          = ffm("NamespaceStatus.multiparty", "Information about the multi-party system configured on this namespace")
+	NamespaceTokenPoolConnectors = ffm("NamespaceStatus.tokenPoolConnectors", "The bindings of token pools to token connectors configured on this namespace, if any")
+	NamespaceCircuitBreakers     = ffm("NamespaceStatus.circuitBreakers", "The state of the circuit breakers protecting operation submission to each plugin, if enabled")
 
 	// NamespaceStatusNode field descriptions
 	NamespaceStatusNodeName                  = ffm("NamespaceStatusNode.name", "The name of this node, as specified in the local configuration")
@@ -466,8 +675,50 @@ var (
 	NamespaceStatusPluginsTokens        = ffm("NamespaceStatusPlugins.tokens", "The token plugins on this namespace")
 
 	// NamespaceStatusPlugin field descriptions
-	NamespaceStatusPluginName = ffm("NamespaceStatusPlugin.name", "The name of the plugin")
-	NamespaceStatusPluginType = ffm("NamespaceStatusPlugin.pluginType", "The type of the plugin")
+	NamespaceStatusPluginName      = ffm("NamespaceStatusPlugin.name", "The name of the plugin")
+	NamespaceStatusPluginType      = ffm("NamespaceStatusPlugin.pluginType", "The type of the plugin")
+	NamespaceStatusPluginConnector = ffm("NamespaceStatusPlugin.connector", "The health of the connector endpoints, for plugins configured to fail over between multiple connectors")
+
+	// ConnectorStatus field descriptions
+	ConnectorStatusActive    = ffm("ConnectorStatus.active", "The URL of the connector endpoint currently in use")
+	ConnectorStatusEndpoints = ffm("ConnectorStatus.endpoints", "The result of the latest health check of each configured connector endpoint")
+	ConnectorStatusFailovers = ffm("ConnectorStatus.failovers", "The most recent switches of the active connector endpoint, oldest first")
+
+	// ConnectorEndpointStatus field descriptions
+	ConnectorEndpointStatusURL         = ffm("ConnectorEndpointStatus.url", "The URL of the connector endpoint")
+	ConnectorEndpointStatusHealthy     = ffm("ConnectorEndpointStatus.healthy", "Whether the latest health check of the endpoint succeeded")
+	ConnectorEndpointStatusLastChecked = ffm("ConnectorEndpointStatus.lastChecked", "The time of the latest health check of the endpoint")
+	ConnectorEndpointStatusError       = ffm("ConnectorEndpointStatus.error", "The error returned by the latest health check of the endpoint, if it failed")
+
+	// ConnectorFailover field descriptions
+	ConnectorFailoverFrom      = ffm("ConnectorFailover.from", "The URL of the connector endpoint that was active before the failover")
+	ConnectorFailoverTo        = ffm("ConnectorFailover.to", "The URL of the connector endpoint that became active")
+	ConnectorFailoverReason    = ffm("ConnectorFailover.reason", "The health check error that caused the failover")
+	ConnectorFailoverTimestamp = ffm("ConnectorFailover.timestamp", "The time of the failover")
+
+	// TokenPoolConnectorBinding field descriptions
+	TokenPoolConnectorBindingPool      = ffm("TokenPoolConnectorBinding.pool", "The name of the token pool")
+	TokenPoolConnectorBindingConnector = ffm("TokenPoolConnectorBinding.connector", "The name of the token connector that operations for the pool are routed to")
+
+	// NamespaceReadiness field descriptions
+	NamespaceReadinessReady   = ffm("NamespaceReadiness.ready", "True if every critical plugin of the namespace could be reached")
+	NamespaceReadinessPlugins = ffm("NamespaceReadiness.plugins", "The result of checking each plugin of the namespace")
+
+	// PluginReadiness field descriptions
+	PluginReadinessCategory   = ffm("PluginReadiness.category", "The type of plugin - database, blockchain, dataexchange or tokens")
+	PluginReadinessName       = ffm("PluginReadiness.name", "The name of the plugin")
+	PluginReadinessPluginType = ffm("PluginReadiness.pluginType", "The type of the plugin implementation")
+	PluginReadinessCritical   = ffm("PluginReadiness.critical", "True if the namespace is not ready when this plugin cannot be reached")
+	PluginReadinessReady      = ffm("PluginReadiness.ready", "True if the plugin responded successfully within the readiness timeout")
+	PluginReadinessLatency    = ffm("PluginReadiness.latency", "How long the plugin took to respond to the check")
+	PluginReadinessError      = ffm("PluginReadiness.error", "The error from the check, if the plugin is not ready")
+
+	// CircuitBreakerStatus field descriptions
+	CircuitBreakerStatusPlugin              = ffm("CircuitBreakerStatus.plugin", "The name of the plugin that operations are submitted to")
+	CircuitBreakerStatusState               = ffm("CircuitBreakerStatus.state", "Whether operations are being submitted to the plugin (closed), failing fast (open), or a single operation is testing for recovery (half_open)")
+	CircuitBreakerStatusConsecutiveFailures = ffm("CircuitBreakerStatus.consecutiveFailures", "The number of operation submissions to the plugin that have failed with a connection error or 5xx response since the last success")
+	CircuitBreakerStatusOpened              = ffm("CircuitBreakerStatus.opened", "The time the circuit breaker last opened")
+	CircuitBreakerStatusLastError           = ffm("CircuitBreakerStatus.lastError", "The error from the most recent failed submission to the plugin")
 
 	// NamespaceStatusMultiparty field descriptions
 	NamespaceMultipartyEnabled  = ffm("NamespaceStatusMultiparty.enabled", "Whether multi-party mode is enabled for this namespace")
@@ -479,13 +730,118 @@ var (
 	NamespaceMultipartyStatusOrg       = ffm("NamespaceMultipartyStatus.org", "Details of the root organization identity registered for this namespace on the local node")
 	NamespaceMultipartyStatusContracts = ffm("NamespaceMultipartyStatus.contracts", "Information about the active and terminated multi-party smart contracts configured for this namespace")
 
+	// BatchManagerRestart field descriptions
+	BatchManagerRestartStopped   = ffm("BatchManagerRestart.stopped", "The number of batch processors that stopped cleanly, after completing any dispatch in progress")
+	BatchManagerRestartCancelled = ffm("BatchManagerRestart.cancelled", "The number of batch processors that were cancelled as they did not stop before the request timeout. The messages they were dispatching are batched again")
+
+	// RetentionStatus field descriptions
+	RetentionStatusEnabled     = ffm("RetentionStatus.enabled", "True if a retention window is configured for the namespace, and records older than it are being pruned")
+	RetentionStatusWindow      = ffm("RetentionStatus.window", "The age after which events, confirmed messages and completed operations are pruned")
+	RetentionStatusArchive     = ffm("RetentionStatus.archive", "True if each page of pruned records is uploaded to shared storage before it is deleted")
+	RetentionStatusLastRun     = ffm("RetentionStatus.lastRun", "The time the last pruning run completed")
+	RetentionStatusLastError   = ffm("RetentionStatus.lastError", "The error from the last pruning run, if it failed")
+	RetentionStatusLastArchive = ffm("RetentionStatus.lastArchive", "The shared storage reference of the most recently uploaded archive")
+	RetentionStatusPruned      = ffm("RetentionStatus.pruned", "The number of records pruned since this node started")
+
+	// RetentionPrunedCounts field descriptions
+	RetentionPrunedCountsEvents     = ffm("RetentionPrunedCounts.events", "The number of events pruned")
+	RetentionPrunedCountsMessages   = ffm("RetentionPrunedCounts.messages", "The number of confirmed messages pruned, along with any data no longer referenced by another message")
+	RetentionPrunedCountsOperations = ffm("RetentionPrunedCounts.operations", "The number of succeeded or failed operations pruned")
+
 	// BatchManagerStatus field descriptions
-	BatchManagerStatusProcessors = ffm("BatchManagerStatus.processors", "An array of currently active batch processors")
+	BatchManagerStatusProcessors  = ffm("BatchManagerStatus.processors", "An array of currently active batch processors")
+	BatchManagerStatusDispatchers = ffm("BatchManagerStatus.dispatchers", "The backlog of messages waiting to be batched, summarized for each registered dispatcher")
+
+	// BatchDispatcherStatus field descriptions
+	BatchDispatcherStatusName            = ffm("BatchDispatcherStatus.name", "The name of the dispatcher")
+	BatchDispatcherStatusProcessors      = ffm("BatchDispatcherStatus.processors", "The number of batch processors currently active for this dispatcher")
+	BatchDispatcherStatusPendingMessages = ffm("BatchDispatcherStatus.pendingMessages", "The total number of messages waiting in the assembly buffers of the processors of this dispatcher")
+	BatchDispatcherStatusPendingBytes    = ffm("BatchDispatcherStatus.pendingBytes", "The total estimated size in bytes of the messages waiting in the assembly buffers of the processors of this dispatcher")
 
 	// BatchProcessorStatus field descriptions
 	BatchProcessorStatusDispatcher = ffm("BatchProcessorStatus.dispatcher", "The type of dispatcher for this processor")
 	BatchProcessorStatusName       = ffm("BatchProcessorStatus.name", "The name of the processor, which includes details of the attributes of message are allocated to this processor")
 	BatchProcessorStatusStatus     = ffm("BatchProcessorStatus.status", "The flush status for this batch processor")
+	BatchProcessorStatusBacklog    = ffm("BatchProcessorStatus.backlog", "The work waiting in the assembly buffer of this batch processor, and its recent flush activity")
+	BatchProcessorStatusThresholds = ffm("BatchProcessorStatus.thresholds", "The configured thresholds at which this batch processor flushes a batch")
+
+	// BatchProcessorBacklog field descriptions
+	BatchProcessorBacklogPendingMessages          = ffm("BatchProcessorBacklog.pendingMessages", "The number of messages waiting in the assembly buffer")
+	BatchProcessorBacklogPendingBytes             = ffm("BatchProcessorBacklog.pendingBytes", "The estimated size in bytes of the messages waiting in the assembly buffer")
+	BatchProcessorBacklogTimeSinceLastFlushMS     = ffm("BatchProcessorBacklog.timeSinceLastFlushMS", "The time since the last flush was started, or since the processor was created if it has not flushed")
+	BatchProcessorBacklogRecentFlushes            = ffm("BatchProcessorBacklog.recentFlushes", "The number of flushes completed within the flush statistics window")
+	BatchProcessorBacklogRecentAverageFlushTimeMS = ffm("BatchProcessorBacklog.recentAverageFlushTimeMS", "The rolling average time spent flushing each batch within the flush statistics window")
+	BatchProcessorBacklogWindowMS                 = ffm("BatchProcessorBacklog.windowMS", "The length of the flush statistics window")
+
+	// BatchProcessorThresholds field descriptions
+	BatchProcessorThresholdsMaxMessages = ffm("BatchProcessorThresholds.maxMessages", "The number of messages at which a batch is flushed")
+	BatchProcessorThresholdsMaxBytes    = ffm("BatchProcessorThresholds.maxBytes", "The estimated size in bytes at which a batch is flushed")
+	BatchProcessorThresholdsTimeoutMS   = ffm("BatchProcessorThresholds.timeoutMS", "The time after the first message is added to a batch at which it is flushed, regardless of size")
+
+	// BatchDispatcherConfig field descriptions
+	BatchDispatcherConfigName         = ffm("BatchDispatcherConfig.name", "The name of the dispatcher")
+	BatchDispatcherConfigBatchSize    = ffm("BatchDispatcherConfig.batchSize", "The number of messages at which a batch is flushed. Adjusted automatically when adaptive sizing is enabled")
+	BatchDispatcherConfigPayloadLimit = ffm("BatchDispatcherConfig.payloadLimit", "The estimated size in bytes at which a batch is flushed")
+	BatchDispatcherConfigTimeout      = ffm("BatchDispatcherConfig.timeout", "The time after the first message is added to a batch at which it is flushed, regardless of size")
+	BatchDispatcherConfigAdaptive     = ffm("BatchDispatcherConfig.adaptive", "The adaptive sizing of the batch size of the dispatcher")
+
+	// BatchAdaptiveConfig field descriptions
+	BatchAdaptiveConfigEnabled       = ffm("BatchAdaptiveConfig.enabled", "Whether the batch size grows when batches are filling up, and shrinks when they are dispatched part-full or are slow to flush")
+	BatchAdaptiveConfigMinSize       = ffm("BatchAdaptiveConfig.minSize", "The smallest batch size that adaptive sizing will shrink to")
+	BatchAdaptiveConfigMaxSize       = ffm("BatchAdaptiveConfig.maxSize", "The largest batch size that adaptive sizing will grow to")
+	BatchAdaptiveConfigTargetLatency = ffm("BatchAdaptiveConfig.targetLatency", "The average time to seal, pin and dispatch a batch, above which the batch size shrinks")
+	BatchAdaptiveConfigWindow        = ffm("BatchAdaptiveConfig.window", "The number of batches flushed between each adjustment of the batch size")
+
+	// BatchConfigUpdate field descriptions
+	BatchConfigUpdateDispatcher   = ffm("BatchConfigUpdate.dispatcher", "The name of the dispatcher to update. All dispatchers are updated if not set")
+	BatchConfigUpdateBatchSize    = ffm("BatchConfigUpdate.batchSize", "The number of messages at which a batch is flushed")
+	BatchConfigUpdatePayloadLimit = ffm("BatchConfigUpdate.payloadLimit", "The estimated size in bytes at which a batch is flushed. Cannot be raised above the limit of the plugins that transfer the batch")
+	BatchConfigUpdateTimeout      = ffm("BatchConfigUpdate.timeout", "The time after the first message is added to a batch at which it is flushed, regardless of size")
+	BatchConfigUpdateAdaptive     = ffm("BatchConfigUpdate.adaptive", "Changes to the adaptive sizing of the batch size")
+
+	// AggregatorStatus field descriptions
+	AggregatorStatusWorkers = ffm("AggregatorStatus.workers", "An array of the event aggregator workers, with the load on each")
+
+	// AggregatorWorkerStatus field descriptions
+	AggregatorWorkerStatusIndex            = ffm("AggregatorWorkerStatus.index", "The index of the worker")
+	AggregatorWorkerStatusBusy             = ffm("AggregatorWorkerStatus.busy", "True if the worker is currently aggregating a set of pins")
+	AggregatorWorkerStatusTotalPins        = ffm("AggregatorWorkerStatus.totalPins", "The total count of pins processed by this worker since it started")
+	AggregatorWorkerStatusTotalContexts    = ffm("AggregatorWorkerStatus.totalContexts", "The total count of independent contexts allocated to this worker since it started. Only tracked when running multiple workers")
+	AggregatorWorkerStatusTotalRuns        = ffm("AggregatorWorkerStatus.totalRuns", "The total count of aggregation runs performed by this worker since it started")
+	AggregatorWorkerStatusLastRunPins      = ffm("AggregatorWorkerStatus.lastRunPins", "The count of pins processed in the most recent aggregation run")
+	AggregatorWorkerStatusLastRunDuration  = ffm("AggregatorWorkerStatus.lastRunDuration", "The time taken for the most recent aggregation run")
+	AggregatorWorkerStatusLastRunStartTime = ffm("AggregatorWorkerStatus.lastRunStartTime", "The time the most recent aggregation run started")
+
+	// ErrorReport field descriptions
+	ErrorReportSince      = ffm("ErrorReport.since", "The start of the window the report covers")
+	ErrorReportTotal      = ffm("ErrorReport.total", "The total count of errors across all categories")
+	ErrorReportCategories = ffm("ErrorReport.categories", "The errors reported by each subsystem")
+
+	// ErrorReportCategoryStatus field descriptions
+	ErrorReportCategoryStatusCategory  = ffm("ErrorReportCategoryStatus.category", "The subsystem the errors were collected from")
+	ErrorReportCategoryStatusCount     = ffm("ErrorReportCategoryStatus.count", "The count of errors in this category")
+	ErrorReportCategoryStatusTruncated = ffm("ErrorReportCategoryStatus.truncated", "True if there were more errors in the window than could be included in the report")
+	ErrorReportCategoryStatusGroups    = ffm("ErrorReportCategoryStatus.groups", "The errors in this category, grouped by source and sorted most frequent first")
+
+	// ErrorReportGroup field descriptions
+	ErrorReportGroupName     = ffm("ErrorReportGroup.name", "The source of the errors - an operation type, subscription name, or contract listener name")
+	ErrorReportGroupCount    = ffm("ErrorReportGroup.count", "The count of errors from this source")
+	ErrorReportGroupLastSeen = ffm("ErrorReportGroup.lastSeen", "The time of the most recent error from this source")
+	ErrorReportGroupMessages = ffm("ErrorReportGroup.messages", "A sample of distinct error messages from this source")
+
+	// BatchPreview field descriptions
+	BatchPreviewDispatcher         = ffm("BatchPreview.dispatcher", "The batch dispatcher the message would be assigned to")
+	BatchPreviewProcessor          = ffm("BatchPreview.processor", "The name of the batch processor within the dispatcher, based on the author and group of the message")
+	BatchPreviewBatchID            = ffm("BatchPreview.batchId", "The ID of the open batch the message would join. Not set if the message would start a new batch")
+	BatchPreviewNewBatch           = ffm("BatchPreview.newBatch", "True if the message would start a new batch, rather than joining an open batch")
+	BatchPreviewMessages           = ffm("BatchPreview.messages", "The number of messages in the batch, including this message")
+	BatchPreviewMaxMessages        = ffm("BatchPreview.maxMessages", "The maximum number of messages in a batch for this dispatcher")
+	BatchPreviewBytes              = ffm("BatchPreview.bytes", "The estimated size of the batch in bytes, including this message")
+	BatchPreviewMaxBytes           = ffm("BatchPreview.maxBytes", "The maximum size of a batch in bytes for this dispatcher")
+	BatchPreviewFillPercent        = ffm("BatchPreview.fillPercent", "How full the batch would be including this message, as a percentage of the message count or byte size limit (whichever is greater)")
+	BatchPreviewSealsImmediately   = ffm("BatchPreview.sealsImmediately", "True if adding this message would cause the batch to seal immediately")
+	BatchPreviewEstimatedSealDelay = ffm("BatchPreview.estimatedSealDelay", "The estimated time until the batch seals, based on the current batch timeout settings")
+	BatchPreviewEstimatedSealTime  = ffm("BatchPreview.estimatedSealTime", "The estimated time at which the batch will seal")
 
 	// BatchFlushStatus field descriptions
 	BatchFlushStatusLastFlushTime        = ffm("BatchFlushStatus.lastFlushStartTime", "The last time a flush was performed")
@@ -515,6 +871,22 @@ var (
 	PinRewindSequence = ffm("PinRewind.sequence", "The sequence of the pin to which the event aggregator should rewind. Either sequence or batch must be specified")
 	PinRewindBatch    = ffm("PinRewind.batch", "The ID of the batch to which the event aggregator should rewind. Either sequence or batch must be specified")
 
+	// PinRewindBatches field descriptions
+	PinRewindBatchesBatches = ffm("PinRewindBatches.batches", "The IDs of the batches with undispatched pins that the event aggregator should re-evaluate")
+
+	// PinRewindResult field descriptions
+	PinRewindResultSequence = ffm("PinRewindResult.sequence", "The sequence of the earliest undispatched pin the event aggregator rewinds to. Zero if there are no undispatched pins to re-evaluate")
+	PinRewindResultDryRun   = ffm("PinRewindResult.dryRun", "True if this was a dry-run, and no rewind was performed")
+	PinRewindResultPins     = ffm("PinRewindResult.pins", "The number of undispatched pins that are re-evaluated, up to the configured rewind query limit")
+	PinRewindResultBatches  = ffm("PinRewindResult.batches", "The IDs of the batches that are re-evaluated, in pin sequence order")
+	PinRewindResultMessages = ffm("PinRewindResult.messages", "The IDs of the messages in those batches that have not yet been confirmed or rejected")
+
+	// Simulation field descriptions
+	SimulationSuccess      = ffm("Simulation.success", "True if the connector reports that the request would succeed if submitted")
+	SimulationGasEstimate  = ffm("Simulation.gasEstimate", "The gas the transaction is estimated to use, if reported by the connector")
+	SimulationRevertReason = ffm("Simulation.revertReason", "The reason the request would be rejected, decoded by the connector using the errors in the interface where available")
+	SimulationOutput       = ffm("Simulation.output", "The value returned by the method when it is evaluated, if reported by the connector")
+
 	// NextPin field descriptions
 	NextPinNamespace = ffm("NextPin.namespace", "The namespace of the next-pin")
 	NextPinContext   = ffm("NextPin.context", "The context the next-pin applies to - the hash of the privacy group-hash + topic. The group-hash is only known to the participants (can itself contain a salt in the group-name). This context is combined with the member and nonce to determine the final hash that is written on-chain")
@@ -522,6 +894,15 @@ var (
 	NextPinHash      = ffm("NextPin.hash", "The unique masked pin string")
 	NextPinNonce     = ffm("NextPin.nonce", "The numeric index - which is monotonically increasing for each member of the privacy group")
 
+	// Bridge field descriptions
+	BridgeID        = ffm("Bridge.id", "The UUID of the bridge, which is also the ID of the durable subscription that backs it")
+	BridgeNamespace = ffm("Bridge.namespace", "The namespace messages are bridged from")
+	BridgeName      = ffm("Bridge.name", "The name of the bridge, which must be unique across the bridges and subscriptions of the namespace")
+	BridgeTarget    = ffm("Bridge.target", "The namespace on this node that matching messages are re-broadcast into")
+	BridgeTopic     = ffm("Bridge.topic", "Regular expression to apply to the topics of the message. Only broadcast messages with a matching topic are bridged")
+	BridgeTag       = ffm("Bridge.tag", "Regular expression to apply to the tag of the message. Only broadcast messages with a matching tag are bridged")
+	BridgeCreated   = ffm("Bridge.created", "Creation time of the bridge")
+
 	// Subscription field descriptions
 	SubscriptionID        = ffm("Subscription.id", "The UUID of the subscription")
 	SubscriptionNamespace = ffm("Subscription.namespace", "The namespace of the subscription. A subscription will only receive events generated in the namespace of the subscription")
@@ -533,6 +914,56 @@ var (
 	SubscriptionCreated   = ffm("Subscription.created", "Creation time of the subscription")
 	SubscriptionUpdated   = ffm("Subscription.updated", "Last time the subscription was updated")
 
+	// DeadLetter field descriptions
+	DeadLetterID           = ffm("DeadLetter.id", "The UUID of the dead-letter entry")
+	DeadLetterNamespace    = ffm("DeadLetter.namespace", "The namespace of the subscription")
+	DeadLetterSubscription = ffm("DeadLetter.subscription", "The UUID of the subscription the event could not be delivered to")
+	DeadLetterEvent        = ffm("DeadLetter.event", "The UUID of the event that could not be delivered")
+	DeadLetterEventType    = ffm("DeadLetter.eventType", "The type of the event that could not be delivered")
+	DeadLetterError        = ffm("DeadLetter.error", "The error from the last attempt to deliver the event")
+	DeadLetterCreated      = ffm("DeadLetter.created", "The time the event was parked in the dead-letter queue")
+	DeadLetterUpdated      = ffm("DeadLetter.updated", "The time of the last failed attempt to replay the event, if any")
+
+	// MessageAck field descriptions
+	MessageAckID        = ffm("MessageAck.id", "The UUID of the acknowledgement")
+	MessageAckNamespace = ffm("MessageAck.namespace", "The namespace of the message")
+	MessageAckMessage   = ffm("MessageAck.message", "The UUID of the private message that was acknowledged")
+	MessageAckType      = ffm("MessageAck.type", "The type of acknowledgement - 'delivered' once the recipient has received the message, then 'confirmed' or 'rejected' once the recipient has processed it")
+	MessageAckAuthor    = ffm("MessageAck.author", "The DID of the recipient org that sent the acknowledgement. Acknowledgements are not signed - the author is checked against the data exchange peer the acknowledgement was received from")
+	MessageAckNode      = ffm("MessageAck.node", "The UUID of the recipient node that sent the acknowledgement")
+	MessageAckCreated   = ffm("MessageAck.created", "The time the acknowledgement was generated by the recipient")
+
+	// GraphQLRequest field descriptions
+	GraphQLRequestQuery         = ffm("GraphQLRequest.query", "The GraphQL query document")
+	GraphQLRequestOperationName = ffm("GraphQLRequest.operationName", "The name of the operation to execute, required if the query document contains multiple operations")
+	GraphQLRequestVariables     = ffm("GraphQLRequest.variables", "Values for the variables declared by the operation")
+
+	// GraphQLResponse field descriptions
+	GraphQLResponseData   = ffm("GraphQLResponse.data", "The data resolved for the query. Fields that could not be resolved are null")
+	GraphQLResponseErrors = ffm("GraphQLResponse.errors", "Errors resolving individual fields of the query")
+
+	// GraphQLError field descriptions
+	GraphQLErrorMessage = ffm("GraphQLError.message", "The error message")
+	GraphQLErrorPath    = ffm("GraphQLError.path", "The path of the field in the response data that could not be resolved")
+
+	// SubscriptionReplayInput field descriptions
+	SubscriptionReplayInputSequence  = ffm("SubscriptionReplayInput.sequence", "The sequence of the first event to deliver again. Mutually exclusive with timestamp")
+	SubscriptionReplayInputTimestamp = ffm("SubscriptionReplayInput.timestamp", "Deliver again all events created at or after this time. Mutually exclusive with sequence")
+
+	// SubscriptionReplay field descriptions
+	SubscriptionReplaySubscription = ffm("SubscriptionReplay.subscription", "The subscription that was rewound")
+	SubscriptionReplayOffset       = ffm("SubscriptionReplay.offset", "The new offset of the subscription. Delivery resumes from the first event with a higher sequence")
+	SubscriptionReplayMarker       = ffm("SubscriptionReplay.marker", "The ID of the subscription_replay event delivered to connected applications before the replayed events")
+
+	// SubscriptionPreviewInput field descriptions
+	SubscriptionPreviewInputFilter  = ffm("SubscriptionPreviewInput.filter", "The filters of the subscription to test")
+	SubscriptionPreviewInputOptions = ffm("SubscriptionPreviewInput.options", "The options of the subscription to test, which are validated as they would be when the subscription is created")
+	SubscriptionPreviewInputLimit   = ffm("SubscriptionPreviewInput.limit", "The maximum number of matching events to return. Defaults to 25")
+
+	// SubscriptionPreview field descriptions
+	SubscriptionPreviewScanned = ffm("SubscriptionPreview.scanned", "The number of recent events that were tested against the subscription")
+	SubscriptionPreviewEvents  = ffm("SubscriptionPreview.events", "The most recent events that match the subscription, newest first")
+
 	// SubscriptionFilter field descriptions
 	SubscriptionFilterEvents           = ffm("SubscriptionFilter.events", "Regular expression to apply to the event type, to subscribe to a subset of event types")
 	SubscriptionFilterTopic            = ffm("SubscriptionFilter.topic", "Regular expression to apply to the topic of the event, to subscribe to a subset of topics. Note for messages sent with multiple topics, a separate event is emitted for each topic")
@@ -562,6 +993,7 @@ var (
 	SubscriptionCoreOptionsWithData     = ffm("SubscriptionCoreOptions.withData", "Whether message events delivered over the subscription, should be packaged with the full data of those messages in-line as part of the event JSON payload. Or if the application should make separate REST calls to download that data. May not be supported on some transports.")
 	SubscriptionCoreOptionsBatch        = ffm("SubscriptionCoreOptions.batch", "Events are delivered in batches in an ordered array. The batch size is capped to the readAhead limit. The event payload is always an array even if there is a single event in the batch, allowing client-side optimizations when processing the events in a group. Available for both Webhooks and WebSockets.")
 	SubscriptionCoreOptionsBatchTimeout = ffm("SubscriptionCoreOptions.batchTimeout", "When batching is enabled, the optional timeout to send events even when the batch hasn't filled.")
+	SubscriptionCoreOptionsEnrich       = ffm("SubscriptionCoreOptions.enrich", "Additional lookups to make on each event before it is delivered, added to the 'enrichment' field of the event. 'message_data' includes the data of a message inline, 'author_did' includes the DID document of the author of a message, and 'blockchain_event_ffi' includes the FFI event definition a blockchain event was decoded with")
 
 	// TokenApproval field descriptions
 	TokenApprovalLocalID         = ffm("TokenApproval.localId", "The UUID of this token approval, in the local FireFly node")
@@ -585,7 +1017,7 @@ var (
 	// TokenApprovalInput field descriptions
 	TokenApprovalInputMessage        = ffm("TokenApprovalInput.message", "You can specify a message to correlate with the approval, which can be of type broadcast or private. Your chosen token connector and on-chain smart contract must support on-chain/off-chain correlation by taking a `data` input on the approval")
 	TokenApprovalInputPool           = ffm("TokenApprovalInput.pool", "The name or UUID of a token pool. Required if more than one pool exists.")
-	TokenApprovalInputIdempotencyKey = ffm("TokenApprovalInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+	TokenApprovalInputIdempotencyKey = ffm("TokenApprovalInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace. Can also be set with the Idempotency-Key header")
 
 	// TokenBalance field descriptions
 	TokenBalancePool       = ffm("TokenBalance.pool", "The UUID the token pool this balance entry applies to")
@@ -596,6 +1028,19 @@ var (
 	TokenBalanceKey        = ffm("TokenBalance.key", "The blockchain signing identity this balance applies to")
 	TokenBalanceBalance    = ffm("TokenBalance.balance", "The numeric balance. For non-fungible tokens will always be 1. For fungible tokens, the number of decimals for the token pool should be considered when interpreting the balance. For example, with 18 decimals a fractional balance of 10.234 will be returned as 10,234,000,000,000,000,000")
 	TokenBalanceUpdated    = ffm("TokenBalance.updated", "The last time the balance was updated by applying a transfer event")
+	TokenBalanceMetadata   = ffm("TokenBalance.metadata", "The metadata of the token, resolved from its URI. Only returned when requested")
+
+	// TokenMetadata field descriptions
+	TokenMetadataName        = ffm("TokenMetadata.name", "The name of the token, from the metadata document")
+	TokenMetadataDescription = ffm("TokenMetadata.description", "The description of the token, from the metadata document")
+	TokenMetadataImage       = ffm("TokenMetadata.image", "The URI of an image of the token, from the metadata document")
+	TokenMetadataDocument    = ffm("TokenMetadata.document", "The full metadata document the token URI refers to")
+
+	// Token field descriptions
+	TokenPool       = ffm("Token.pool", "The UUID of the token pool the token belongs to")
+	TokenTokenIndex = ffm("Token.tokenIndex", "The index of the token within the pool")
+	TokenURI        = ffm("Token.uri", "The URI of the token, as reported by the token connector when it was minted")
+	TokenMetadata   = ffm("Token.metadata", "The metadata of the token, resolved from its URI")
 
 	// TokenBalance field descriptions
 	TokenConnectorName = ffm("TokenConnector.name", "The name of the token connector, as configured in the FireFly core configuration file")
@@ -622,9 +1067,13 @@ var (
 	TokenPoolInterfaceFormat = ffm("TokenPool.interfaceFormat", "The interface encoding format supported by the connector for this token pool")
 	TokenPoolMethods         = ffm("TokenPool.methods", "The method definitions resolved by the token connector to be used by each token operation")
 	TokenPoolPublished       = ffm("TokenPool.published", "Indicates if the token pool is published to other members of the multiparty network")
+	TokenPoolBackfill        = ffm("TokenPool.backfill", "Opt-in to replay the historical transfer events of an existing token contract when the pool is activated, so that transfers and balances from before the pool was created are indexed")
+
+	// TokenPoolBackfill field descriptions
+	TokenPoolBackfillFromBlock = ffm("TokenPoolBackfill.fromBlock", "The block number to replay historical transfer events from. Defaults to 0, to replay from the first block of the chain")
 
 	// TokenPoolInput field descriptions
-	TokenPoolInputIdempotencyKey = ffm("TokenPoolInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+	TokenPoolInputIdempotencyKey = ffm("TokenPoolInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace. Can also be set with the Idempotency-Key header")
 
 	// TokenTransfer field descriptions
 	TokenTransferType            = ffm("TokenTransfer.type", "The type of transfer such as mint/burn/transfer")
@@ -649,14 +1098,14 @@ var (
 	// TokenTransferInput field descriptions
 	TokenTransferInputMessage        = ffm("TokenTransferInput.message", "You can specify a message to correlate with the transfer, which can be of type broadcast or private. Your chosen token connector and on-chain smart contract must support on-chain/off-chain correlation by taking a `data` input on the transfer")
 	TokenTransferInputPool           = ffm("TokenTransferInput.pool", "The name or UUID of a token pool")
-	TokenTransferInputIdempotencyKey = ffm("TokenTransferInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+	TokenTransferInputIdempotencyKey = ffm("TokenTransferInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace. Can also be set with the Idempotency-Key header")
 
 	// TransactionStatus field descriptions
 	TransactionStatusStatus  = ffm("TransactionStatus.status", "The overall computed status of the transaction, after analyzing the details during the API call")
 	TransactionStatusDetails = ffm("TransactionStatus.details", "A set of records describing the activities within the transaction known by the local FireFly node")
 
 	// TransactionStatusDetails field descriptions
-	TransactionStatusDetailsType      = ffm("TransactionStatusDetails.type", "The type of the transaction status detail record")
+	TransactionStatusDetailsType      = ffm("TransactionStatusDetails.type", "The type of the transaction status detail record - Operation, BlockchainEvent, Batch, Pins, TokenPool, TokenTransfer or TokenApproval")
 	TransactionStatusDetailsSubType   = ffm("TransactionStatusDetails.subtype", "A sub-type, such as an operation type, or an event type")
 	TransactionStatusDetailsStatus    = ffm("TransactionStatusDetails.status", "The status of the detail record. Cases where an event is required for completion, but has not arrived yet are marked with a 'pending' record")
 	TransactionStatusDetailsTimestamp = ffm("TransactionStatusDetails.timestamp", "The time relevant to when the record was updated, such as the time an event was created, or the last update time of an operation")
@@ -665,13 +1114,23 @@ var (
 	TransactionStatusDetailsInfo      = ffm("TransactionStatusDetails.info", "Output details for this entry")
 
 	// ContractDeployRequest field descriptions
-	ContractDeployRequestKey            = ffm("ContractDeployRequest.key", "The blockchain signing key that will be used to deploy the contract. Defaults to the first signing key of the organization that operates the node")
-	ContractDeployRequestInput          = ffm("ContractDeployRequest.input", "An optional array of inputs passed to the smart contract's constructor, if applicable")
-	ContractDeployRequestDefinition     = ffm("ContractDeployRequest.definition", "The definition of the smart contract")
-	ContractDeployRequestContract       = ffm("ContractDeployRequest.contract", "The smart contract to deploy. This should be pre-compiled if required by the blockchain connector")
-	ContractDeployRequestErrors         = ffm("ContractDeployRequest.errors", "An in-line FFI errors definition for the constructor")
-	ContractDeployRequestOptions        = ffm("ContractDeployRequest.options", "A map of named inputs that will be passed through to the blockchain connector")
-	ContractDeployRequestIdempotencyKey = ffm("ContractDeployRequest.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+	ContractDeployRequestKey               = ffm("ContractDeployRequest.key", "The blockchain signing key that will be used to deploy the contract. Defaults to the first signing key of the organization that operates the node")
+	ContractDeployRequestInput             = ffm("ContractDeployRequest.input", "An optional array of inputs passed to the smart contract's constructor, if applicable")
+	ContractDeployRequestDefinition        = ffm("ContractDeployRequest.definition", "The definition of the smart contract. Generated from the interface if not set")
+	ContractDeployRequestInterface         = ffm("ContractDeployRequest.interface", "A reference to the FFI the smart contract implements. Required if there is no definition, or if a contract API is to be created")
+	ContractDeployRequestConstructorParams = ffm("ContractDeployRequest.constructorParams", "The FFI params of the constructor, used when generating the definition from the interface")
+	ContractDeployRequestContract          = ffm("ContractDeployRequest.contract", "The smart contract to deploy. This should be pre-compiled if required by the blockchain connector")
+	ContractDeployRequestAPI               = ffm("ContractDeployRequest.api", "The name of a contract API to create for the deployed contract, using the interface and the location of the contract once the deployment is confirmed")
+	ContractDeployRequestErrors            = ffm("ContractDeployRequest.errors", "An in-line FFI errors definition for the constructor")
+	ContractDeployRequestOptions           = ffm("ContractDeployRequest.options", "A map of named inputs that will be passed through to the blockchain connector")
+	ContractDeployRequestIdempotencyKey    = ffm("ContractDeployRequest.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace. Can also be set with the Idempotency-Key header")
+
+	// ContractMethodSelector field descriptions
+	ContractMethodSelectorInterface = ffm("ContractMethodSelector.interface", "The UUID of the contract interface (FFI) that defines the method")
+	ContractMethodSelectorName      = ffm("ContractMethodSelector.name", "The name of the method")
+	ContractMethodSelectorPathname  = ffm("ContractMethodSelector.pathname", "The unique path name of the method within the interface, which distinguishes overloaded methods")
+	ContractMethodSelectorSignature = ffm("ContractMethodSelector.signature", "The full signature of the method, as understood by the blockchain")
+	ContractMethodSelectorSelector  = ffm("ContractMethodSelector.selector", "The selector that identifies calls to the method in raw transaction data, if the blockchain uses one. For Ethereum this is the 4-byte function selector")
 
 	// ContractCallRequest field descriptions
 	ContractCallRequestType       = ffm("ContractCallRequest.type", "Invocations cause transactions on the blockchain. Whereas queries simply execute logic in your local node to query data at a given current/historical block")
@@ -685,17 +1144,33 @@ var (
 	ContractCallRequestOutput     = ffm("ContractCallRequest.output", "A map of named outputs")
 	ContractCallRequestOptions    = ffm("ContractCallRequest.options", "A map of named inputs that will be passed through to the blockchain connector")
 	ContractCallMessage           = ffm("ContractCallRequest.message", "You can specify a message to correlate with the invocation, which can be of type broadcast or private. Your specified method must support on-chain/off-chain correlation by taking a data input on the call")
-	ContractCallIdempotencyKey    = ffm("ContractCallRequest.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+	ContractCallIdempotencyKey    = ffm("ContractCallRequest.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace. Can also be set with the Idempotency-Key header")
+	ContractCallTriggers          = ffm("ContractCallRequest.triggers", "Follow-up actions to run once the blockchain invoke operation succeeds. Each action is recorded as an operation in a transaction of type 'trigger', and is failed if the invoke fails")
+
+	// TriggerAction field descriptions
+	TriggerActionType   = ffm("TriggerAction.type", "The type of the action")
+	TriggerActionInvoke = ffm("TriggerAction.invoke", "The smart contract method to invoke, for actions of type 'invoke'")
+
+	// TriggerInvoke field descriptions
+	TriggerInvokeAPI        = ffm("TriggerInvoke.api", "The name of a contract API to invoke the method on. Alternative to specifying the interface and location")
+	TriggerInvokeInterface  = ffm("TriggerInvoke.interface", "The UUID of the FireFly interface (FFI) that contains the method. Required if the API is omitted")
+	TriggerInvokeLocation   = ffm("TriggerInvoke.location", "A blockchain specific contract identifier. For example an Ethereum contract address, or a Fabric chaincode name and channel")
+	TriggerInvokeMethodPath = ffm("TriggerInvoke.methodPath", "The pathname of the method on the interface or API")
+	TriggerInvokeKey        = ffm("TriggerInvoke.key", "The blockchain signing key that will sign the invocation. Defaults to the first signing key of the organization that operates the node")
+	TriggerInvokeInput      = ffm("TriggerInvoke.input", "A map of named inputs to the method")
+	TriggerInvokeOptions    = ffm("TriggerInvoke.options", "A map of named inputs that will be passed through to the blockchain connector")
 
 	// WebSocketStatus field descriptions
 	WebSocketStatusEnabled     = ffm("WebSocketStatus.enabled", "Indicates whether the websockets plugin is enabled")
 	WebSocketStatusConnections = ffm("WebSocketStatus.connections", "List of currently active websocket client connections")
 
 	// WSConnectionStatus field descriptions
-	WSConnectionStatusID            = ffm("WSConnectionStatus.id", "The unique ID assigned to this client connection")
-	WSConnectionStatusRemoteAddress = ffm("WSConnectionStatus.remoteAddress", "The remote address of the connected client (if available)")
-	WSConnectionStatusUserAgent     = ffm("WSConnectionStatus.userAgent", "The user agent of the connected client (if available)")
-	WSConnectionStatusSubscriptions = ffm("WSConnectionStatus.subscriptions", "List of subscriptions currently started by this client")
+	WSConnectionStatusID             = ffm("WSConnectionStatus.id", "The unique ID assigned to this client connection")
+	WSConnectionStatusRemoteAddress  = ffm("WSConnectionStatus.remoteAddress", "The remote address of the connected client (if available)")
+	WSConnectionStatusUserAgent      = ffm("WSConnectionStatus.userAgent", "The user agent of the connected client (if available)")
+	WSConnectionStatusMaxMessageSize = ffm("WSConnectionStatus.maxMessageSize", "The maximum size of a single message written to this client, if limited")
+	WSConnectionStatusOversizeMode   = ffm("WSConnectionStatus.oversizeMode", "How messages larger than maxMessageSize are delivered to this client - 'reference' or 'fragment'")
+	WSConnectionStatusSubscriptions  = ffm("WSConnectionStatus.subscriptions", "List of subscriptions currently started by this client")
 
 	// WSSubscriptionStatus field descriptions
 	WSSubscriptionStatusEphemeral = ffm("WSSubscriptionStatus.ephemeral", "Indicates whether the subscription is ephemeral (vs durable)")
@@ -704,6 +1179,10 @@ var (
 	WSSubscriptionStatusFilter    = ffm("WSSubscriptionStatus.filter", "The subscription filter specification")
 	WSSubscriptionStatusStartTime = ffm("WSSubscriptionStatus.startTime", "The time the subscription started (reset on dynamic namespace reload)")
 
+	// SSEAck field descriptions
+	SSEAckConnection = ffm("SSEAck.connection", "The ID of the server-sent events connection the event was received on, as sent in the connected event at the start of the stream")
+	SSEAckID         = ffm("SSEAck.id", "The ID of the event to acknowledge. If omitted, the oldest unacknowledged event on the connection is acknowledged")
+
 	WebhooksOptJSON                     = ffm("WebhookSubOptions.json", "Webhooks only: Whether to assume the response body is JSON, regardless of the returned Content-Type")
 	WebhooksOptReply                    = ffm("WebhookSubOptions.reply", "Webhooks only: Whether to automatically send a reply event, using the body returned by the webhook")
 	WebhooksOptHeaders                  = ffm("WebhookSubOptions.headers", "Webhooks only: Static headers to set on the webhook request")
@@ -715,6 +1194,7 @@ var (
 	WebhooksOptReplyTag                 = ffm("WebhookSubOptions.replytag", "Webhooks only: The tag to set on the reply message")
 	WebhooksOptReplyTx                  = ffm("WebhookSubOptions.replytx", "Webhooks only: The transaction type to set on the reply message")
 	WebhooksOptTLSConfigName            = ffm("WebhookSubOptions.tlsConfigName", "The name of an existing TLS configuration associated to the namespace to use")
+	WebhooksOptSigning                  = ffm("WebhookSubOptions.signing", "Webhooks only: Signs each webhook request with an HMAC-SHA256 of the request body, so the receiver can verify it came from this node")
 	WebhooksOptHTTPOptions              = ffm("WebhookSubOptions.httpOptions", "Webhooks only: a set of options for HTTP")
 	WebhooksOptHTTPRetry                = ffm("WebhookSubOptions.retry", "Webhooks only: a set of options for retrying the webhook call")
 	WebhooksOptInputQuery               = ffm("WebhookInputOptions.query", "A top-level property of the first data input, to use for query parameters")
@@ -722,10 +1202,15 @@ var (
 	WebhooksOptInputBody                = ffm("WebhookInputOptions.body", "A top-level property of the first data input, to use for the request body. Default is the whole first body")
 	WebhooksOptInputPath                = ffm("WebhookInputOptions.path", "A top-level property of the first data input, to use for a path to append with escaping to the webhook path")
 	WebhooksOptInputReplyTx             = ffm("WebhookInputOptions.replytx", "A top-level property of the first data input, to use to dynamically set whether to pin the response (so the requester can choose)")
+	WebhooksOptSigningSecretName        = ffm("WebhookSigningOptions.secretName", "The name of a signing secret configured on the namespace, used as the HMAC key")
+	WebhooksOptSigningHeader            = ffm("WebhookSigningOptions.header", "The header to set the signature in, as 't=<unix timestamp>,v1=<hex HMAC-SHA256 of the timestamp, a '.' and the body>'. Default=X-FireFly-Signature")
 	WebhooksOptRetryEnabled             = ffm("WebhookRetryOptions.enabled", "Enables retry on HTTP calls, defaults to false")
 	WebhooksOptRetryCount               = ffm("WebhookRetryOptions.count", "Number of times to retry the webhook call in case of failure")
 	WebhooksOptRetryInitialDelay        = ffm("WebhookRetryOptions.initialDelay", "Initial delay between retries when we retry the webhook call")
 	WebhooksOptRetryMaxDelay            = ffm("WebhookRetryOptions.maxDelay", "Max delay between retries when we retry the webhookcall")
+	WebhooksOptRetryFactor              = ffm("WebhookRetryOptions.factor", "Factor the delay is multiplied by after each failed attempt, defaults to 2")
+	WebhooksOptRetryJitter              = ffm("WebhookRetryOptions.jitter", "Fraction between 0 and 1 of each delay that is randomized, to spread out retries from many subscriptions. Defaults to 0")
+	WebhooksOptRetryDeadLetter          = ffm("WebhookRetryOptions.deadLetter", "When true, events that still cannot be delivered once all retries are exhausted are parked in the dead-letter queue of the subscription, rather than acknowledged with an error reply")
 	WebhookOptHTTPExpectContinueTimeout = ffm("WebhookHTTPOptions.expectContinueTimeout", "See [ExpectContinueTimeout in the Go docs](https://pkg.go.dev/net/http#Transport)")
 	WebhookOptHTTPIdleTimeout           = ffm("WebhookHTTPOptions.idleTimeout", "The max duration to hold a HTTP keepalive connection between calls")
 	WebhookOptHTTPMaxIdleConns          = ffm("WebhookHTTPOptions.maxIdleConns", "The max number of idle connections to hold pooled")
@@ -739,4 +1224,49 @@ var (
 
 	// DefinitionPublish field descriptions
 	DefinitionPublishNetworkName = ffm("DefinitionPublish.networkName", "An optional name to be used for publishing this definition to the multiparty network, which may differ from the local name")
+
+	// DefinitionLifecycleUpdate field descriptions
+	DefinitionLifecycleUpdateLifecycle = ffm("DefinitionLifecycleUpdate.lifecycle", "The new lifecycle state of this version - active, deprecated or retired")
+
+	// ContractInterfaceLifecycle field descriptions
+	ContractInterfaceLifecycleInterface = ffm("ContractInterfaceLifecycle.interface", "A reference to the version of the contract interface")
+	ContractInterfaceLifecycleLifecycle = ffm("ContractInterfaceLifecycle.lifecycle", "The local lifecycle state of this version of the contract interface - active, deprecated or retired. Retired contract interfaces cannot be invoked, or used for new contract APIs")
+
+	// DefinitionDiff field descriptions
+	DefinitionDiffName    = ffm("DefinitionDiff.name", "The name of the definition that was compared")
+	DefinitionDiffFrom    = ffm("DefinitionDiff.from", "The version being compared from")
+	DefinitionDiffTo      = ffm("DefinitionDiff.to", "The version being compared to")
+	DefinitionDiffChanges = ffm("DefinitionDiff.changes", "The list of differences between the two versions")
+
+	// DefinitionChange field descriptions
+	DefinitionChangePath = ffm("DefinitionChange.path", "The JSON pointer to the value that differs between the two versions")
+	DefinitionChangeType = ffm("DefinitionChange.type", "Whether the value was added, removed or changed in the newer version")
+	DefinitionChangeFrom = ffm("DefinitionChange.from", "The value in the version being compared from, unless it was added")
+	DefinitionChangeTo   = ffm("DefinitionChange.to", "The value in the version being compared to, unless it was removed")
+
+	// NetworkDiagnostics field descriptions
+	NetworkDiagnosticsHealthy       = ffm("NetworkDiagnostics.healthy", "True if every check of the diagnostics passed")
+	NetworkDiagnosticsBlockchain    = ffm("NetworkDiagnostics.blockchain", "Whether the blockchain connector is in sync with the chain, for events from the multiparty contract")
+	NetworkDiagnosticsSharedStorage = ffm("NetworkDiagnostics.sharedStorage", "Whether a probe could be written to, and read back from, shared storage")
+	NetworkDiagnosticsMembers       = ffm("NetworkDiagnostics.members", "The result of sending a ping over data exchange to each node in the network")
+
+	// DiagnosticCheck field descriptions
+	DiagnosticCheckHealthy = ffm("DiagnosticCheck.healthy", "True if the check passed within the diagnostics timeout")
+	DiagnosticCheckLatency = ffm("DiagnosticCheck.latency", "How long the check took to complete")
+	DiagnosticCheckError   = ffm("DiagnosticCheck.error", "The error from the check, if it did not pass")
+
+	// BlockchainDiagnostics field descriptions
+	BlockchainDiagnosticsStatus     = ffm("BlockchainDiagnostics.status", "The sync status of the listener for the multiparty contract - synced, syncing or unknown")
+	BlockchainDiagnosticsCheckpoint = ffm("BlockchainDiagnostics.checkpoint", "The position in the chain the listener has reached, as reported by the blockchain connector")
+
+	// MemberDiagnostics field descriptions
+	MemberDiagnosticsNode      = ffm("MemberDiagnostics.node", "The UUID of the node")
+	MemberDiagnosticsName      = ffm("MemberDiagnostics.name", "The name of the node")
+	MemberDiagnosticsOrg       = ffm("MemberDiagnostics.org", "The UUID of the org that owns the node")
+	MemberDiagnosticsLocal     = ffm("MemberDiagnostics.local", "True for the local node, which is checked by pinging the local data exchange rather than sending a message")
+	MemberDiagnosticsOperation = ffm("MemberDiagnostics.operation", "The UUID of the data exchange operation that sent the ping")
+
+	// GroupMembershipUpdate field descriptions
+	GroupMembershipUpdateAdd    = ffm("GroupMembershipUpdate.add", "Members to add to the group")
+	GroupMembershipUpdateRemove = ffm("GroupMembershipUpdate.remove", "Members to remove from the group. Each entry is matched on identity, and on node if specified")
 )
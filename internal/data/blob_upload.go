@@ -0,0 +1,286 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Resumable uploads are staged on the local filesystem, as a JSON file describing the upload
+// and a file containing the bytes received so far. The size of that file is the offset the
+// next chunk must start from, so an upload can be resumed after a failure (or a restart).
+// Only once the upload is completed is the blob streamed to data exchange, and the data created.
+// Async uploads (see blob_upload_async.go) are staged in the same way, but are completed by a worker.
+
+func blobUploadStagingDir(ns string) string {
+	dir := config.GetString(coreconfig.BlobUploadStagingDirectory)
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "firefly-blob-uploads")
+	}
+	return filepath.Join(dir, ns)
+}
+
+func (bs *blobStore) uploadPaths(id *fftypes.UUID) (metaPath, blobPath string) {
+	return filepath.Join(bs.uploadDir, id.String()+".json"), filepath.Join(bs.uploadDir, id.String()+".blob")
+}
+
+func stagingError(ctx context.Context, err error) error {
+	return i18n.NewError(ctx, coremsgs.MsgBlobUploadStagingFailed, err)
+}
+
+// lockUpload ensures only one request at a time is writing to, completing, or deleting an upload
+func (bs *blobStore) lockUpload(ctx context.Context, uploadID string) (*fftypes.UUID, func(), error) {
+	id, err := fftypes.ParseUUID(ctx, uploadID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bs.uploadLock.Lock()
+	defer bs.uploadLock.Unlock()
+	if bs.uploadsActive[*id] {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadInProgress, id)
+	}
+	bs.uploadsActive[*id] = true
+	return id, func() {
+		bs.uploadLock.Lock()
+		defer bs.uploadLock.Unlock()
+		delete(bs.uploadsActive, *id)
+	}, nil
+}
+
+func (bs *blobStore) loadBlobUpload(ctx context.Context, id *fftypes.UUID) (*core.BlobUpload, error) {
+	metaPath, blobPath := bs.uploadPaths(id)
+	b, err := os.ReadFile(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadNotFound, id)
+	}
+	if err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	var upload core.BlobUpload
+	if err := json.Unmarshal(b, &upload); err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	switch upload.Status {
+	case core.BlobUploadStatusSucceeded, core.BlobUploadStatusFailed:
+		// The staged blob is removed once an async upload has finished, so the recorded offset is final
+		return &upload, nil
+	case core.BlobUploadStatusProcessing:
+		upload.Processed = bs.asyncProgress(id)
+	case "":
+		upload.Status = core.BlobUploadStatusReceiving
+	}
+	info, err := os.Stat(blobPath)
+	if err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	upload.Offset = info.Size()
+	return &upload, nil
+}
+
+// loadReceivingBlobUpload loads an upload that is still accepting chunks from the caller
+func (bs *blobStore) loadReceivingBlobUpload(ctx context.Context, id *fftypes.UUID) (*core.BlobUpload, error) {
+	upload, err := bs.loadBlobUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != core.BlobUploadStatusReceiving {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadNotReceiving, id, upload.Status)
+	}
+	return upload, nil
+}
+
+// writeBlobUploadMeta replaces the metadata of an upload, via a rename so it is never left part written
+func (bs *blobStore) writeBlobUploadMeta(upload *core.BlobUpload) error {
+	metaPath, _ := bs.uploadPaths(upload.ID)
+	b, _ := json.Marshal(upload)
+	tmpPath := metaPath + ".tmp"
+	err := os.WriteFile(tmpPath, b, 0600)
+	if err == nil {
+		err = os.Rename(tmpPath, metaPath)
+	}
+	return err
+}
+
+// storeStagedBlob streams the staged bytes of an upload to data exchange, and creates the data
+func (bs *blobStore) storeStagedBlob(ctx context.Context, upload *core.BlobUpload, wrapReader func(io.Reader) io.Reader) (*core.Data, error) {
+	_, blobPath := bs.uploadPaths(upload.ID)
+	f, err := os.Open(blobPath)
+	if err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	defer f.Close()
+	return bs.storeBlob(ctx, &core.DataRefOrValue{
+		Validator: upload.Validator,
+		Datatype:  upload.Datatype,
+		Value:     upload.Value,
+	}, wrapReader(f), upload.Filename, upload.Mimetype, upload.AutoMeta)
+}
+
+func (bs *blobStore) CreateBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+
+	available, err := bs.stagingAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if input.Size > available {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadStagingFull, bs.staging.maxSize-available, bs.staging.maxSize)
+	}
+
+	upload := &core.BlobUpload{
+		ID:              fftypes.NewUUID(),
+		Namespace:       bs.dm.namespace.Name,
+		Created:         fftypes.Now(),
+		BlobUploadInput: *input,
+		Status:          core.BlobUploadStatusReceiving,
+	}
+	_, blobPath := bs.uploadPaths(upload.ID)
+	err = os.MkdirAll(bs.uploadDir, 0700)
+	if err == nil {
+		err = os.WriteFile(blobPath, []byte{}, 0600)
+	}
+	if err == nil {
+		// The metadata is written last, as its existence is what makes the upload visible
+		err = bs.writeBlobUploadMeta(upload)
+	}
+	if err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	log.L(ctx).Infof("Started blob upload %s", upload.ID)
+	return upload, nil
+}
+
+func (bs *blobStore) GetBlobUpload(ctx context.Context, uploadID string) (*core.BlobUpload, error) {
+	id, err := fftypes.ParseUUID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return bs.loadBlobUpload(ctx, id)
+}
+
+func (bs *blobStore) AppendBlobUpload(ctx context.Context, uploadID string, offset int64, chunk io.Reader) (*core.BlobUpload, error) {
+	id, unlock, err := bs.lockUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	upload, err := bs.loadReceivingBlobUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadOffsetMismatch, offset, upload.Offset, id)
+	}
+
+	available, err := bs.stagingAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, blobPath := bs.uploadPaths(id)
+	f, err := os.OpenFile(blobPath, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	defer f.Close()
+
+	// Nothing beyond the declared size, or beyond what fits in the staging directory, is ever written
+	limit, limitedBySize := available, false
+	if upload.Size > 0 && upload.Size-upload.Offset <= available {
+		limit, limitedBySize = upload.Size-upload.Offset, true
+	}
+	written, err := io.Copy(f, io.LimitReader(chunk, limit))
+	// Whatever was received before a failure is kept, and the caller can query the offset to resume from
+	upload.Offset += written
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+	}
+	if written == limit {
+		if n, _ := io.ReadFull(chunk, make([]byte, 1)); n > 0 {
+			if limitedBySize {
+				return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadExceedsSize, upload.Size, id)
+			}
+			return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadStagingFull, bs.staging.maxSize-available, bs.staging.maxSize)
+		}
+	}
+	log.L(ctx).Debugf("Blob upload %s received %d bytes (offset=%d)", id, written, upload.Offset)
+	return upload, nil
+}
+
+func (bs *blobStore) CompleteBlobUpload(ctx context.Context, uploadID string) (*core.Data, error) {
+	id, unlock, err := bs.lockUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	upload, err := bs.loadReceivingBlobUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Size > 0 && upload.Offset != upload.Size {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadIncomplete, id, upload.Offset, upload.Size)
+	}
+
+	data, err := bs.storeStagedBlob(ctx, upload, func(r io.Reader) io.Reader { return r })
+	if err != nil {
+		return nil, err
+	}
+
+	bs.removeBlobUpload(ctx, id)
+	return data, nil
+}
+
+func (bs *blobStore) DeleteBlobUpload(ctx context.Context, uploadID string) error {
+	id, unlock, err := bs.lockUpload(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := bs.loadBlobUpload(ctx, id); err != nil {
+		return err
+	}
+	bs.removeBlobUpload(ctx, id)
+	return nil
+}
+
+func (bs *blobStore) removeBlobUpload(ctx context.Context, id *fftypes.UUID) {
+	metaPath, blobPath := bs.uploadPaths(id)
+	// The metadata is removed first, so a failure part way through never leaves a visible upload without its blob
+	for _, path := range []string{metaPath, blobPath} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.L(ctx).Warnf("Failed to remove staged blob upload file %s: %s", path, err)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Async uploads receive the whole blob in a single request, and stage it exactly like a resumable upload.
+// The request returns as soon as the bytes are on disk, and a pool of workers performs the (potentially slow)
+// hashing and transfer to data exchange. Progress is reported on the upload, and an event is emitted when
+// the data is ready (or the upload has failed). Uploads still pending when the node stops are picked up
+// from the staging directory on restart.
+
+type asyncUploads struct {
+	workerCount int
+	work        chan *fftypes.UUID
+	progress    sync.Map // map[fftypes.UUID]*atomic.Int64
+	workersDone sync.WaitGroup
+}
+
+type progressReader struct {
+	reader    io.Reader
+	processed *atomic.Int64
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.reader.Read(p)
+	pr.processed.Add(int64(n))
+	return n, err
+}
+
+func newAsyncUploads() asyncUploads {
+	workerCount := config.GetInt(coreconfig.BlobUploadAsyncWorkerCount)
+	queueLength := config.GetInt(coreconfig.BlobUploadAsyncWorkerQueueLength)
+	if queueLength <= 0 {
+		queueLength = 2 * workerCount
+	}
+	return asyncUploads{
+		workerCount: workerCount,
+		work:        make(chan *fftypes.UUID, queueLength),
+	}
+}
+
+func (bs *blobStore) startAsyncUploads(ctx context.Context) {
+	if bs.exchange == nil {
+		return
+	}
+	for i := 0; i < bs.async.workerCount; i++ {
+		bs.async.workersDone.Add(1)
+		go bs.asyncUploadWorker(ctx, bs.async.work)
+	}
+	if bs.staging.sweepInterval > 0 {
+		bs.async.workersDone.Add(1)
+		go bs.stagingSweeper(ctx)
+	}
+	// The staging directory is scanned before any new uploads can be received, but queuing might block
+	go bs.queueAsyncUploads(ctx, bs.recoverAsyncUploads(ctx))
+}
+
+func (bs *blobStore) waitAsyncUploadsStopped() {
+	bs.async.workersDone.Wait()
+}
+
+func (bs *blobStore) asyncProgress(id *fftypes.UUID) int64 {
+	if processed, ok := bs.async.progress.Load(*id); ok {
+		return processed.(*atomic.Int64).Load()
+	}
+	return 0
+}
+
+func (bs *blobStore) UploadBlobAsync(ctx context.Context, inData *core.DataRefOrValue, mpart *ffapi.Multipart, autoMeta bool) (*core.BlobUpload, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+
+	upload := &core.BlobUpload{
+		ID:        fftypes.NewUUID(),
+		Namespace: bs.dm.namespace.Name,
+		Created:   fftypes.Now(),
+		BlobUploadInput: core.BlobUploadInput{
+			Validator: inData.Validator,
+			Datatype:  inData.Datatype,
+			Value:     inData.Value,
+			Filename:  mpart.Filename,
+			Mimetype:  mpart.Mimetype,
+			AutoMeta:  autoMeta,
+		},
+		Status: core.BlobUploadStatusPending,
+	}
+	available, err := bs.stagingAvailable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, blobPath := bs.uploadPaths(upload.ID)
+	err = os.MkdirAll(bs.uploadDir, 0700)
+	var f *os.File
+	if err == nil {
+		f, err = os.Create(blobPath)
+	}
+	if err != nil {
+		return nil, stagingError(ctx, err)
+	}
+	// Only what fits in the staging directory is written, and the upload is rejected if there is more
+	written, err := io.Copy(f, io.LimitReader(mpart.Data, available))
+	f.Close()
+	if err == nil && written == available {
+		if n, _ := io.ReadFull(mpart.Data, make([]byte, 1)); n > 0 {
+			_ = os.Remove(blobPath)
+			return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadStagingFull, bs.staging.maxSize-available, bs.staging.maxSize)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(blobPath)
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+	}
+
+	// As with resumable uploads, the metadata is written last as its existence is what makes the upload visible
+	upload.Size = written
+	upload.Offset = written
+	if err := bs.writeBlobUploadMeta(upload); err != nil {
+		_ = os.Remove(blobPath)
+		return nil, stagingError(ctx, err)
+	}
+	log.L(ctx).Infof("Received async blob upload %s (size=%d)", upload.ID, written)
+
+	// Once pending, the upload is processed even if this request goes away before it is queued - at the latest on restart
+	select {
+	case bs.async.work <- upload.ID:
+	case <-ctx.Done():
+		return nil, i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+	}
+	return upload, nil
+}
+
+// recoverAsyncUploads finds the uploads that were pending or processing when the node last stopped
+func (bs *blobStore) recoverAsyncUploads(ctx context.Context) (ids []*fftypes.UUID) {
+	entries, err := os.ReadDir(bs.uploadDir)
+	if err != nil {
+		log.L(ctx).Debugf("No blob uploads to recover from %s: %s", bs.uploadDir, err)
+		return nil
+	}
+	for _, entry := range entries {
+		id, err := fftypes.ParseUUID(ctx, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		upload, err := bs.loadBlobUpload(ctx, id)
+		if err != nil || (upload.Status != core.BlobUploadStatusPending && upload.Status != core.BlobUploadStatusProcessing) {
+			continue
+		}
+		log.L(ctx).Infof("Recovering async blob upload %s", id)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (bs *blobStore) queueAsyncUploads(ctx context.Context, ids []*fftypes.UUID) {
+	for _, id := range ids {
+		select {
+		case bs.async.work <- id:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (bs *blobStore) asyncUploadWorker(ctx context.Context, work <-chan *fftypes.UUID) {
+	defer bs.async.workersDone.Done()
+	for {
+		select {
+		case id := <-work:
+			bs.processAsyncUpload(ctx, id)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (bs *blobStore) processAsyncUpload(ctx context.Context, id *fftypes.UUID) {
+	ctx = log.WithLogField(ctx, "upload", id.String())
+	_, unlock, err := bs.lockUpload(ctx, id.String())
+	if err != nil {
+		log.L(ctx).Warnf("Unable to process async blob upload: %s", err)
+		return
+	}
+	defer unlock()
+
+	upload, err := bs.loadBlobUpload(ctx, id)
+	if err != nil {
+		// Deleted while it was queued
+		log.L(ctx).Warnf("Unable to process async blob upload: %s", err)
+		return
+	}
+	if upload.Status != core.BlobUploadStatusPending && upload.Status != core.BlobUploadStatusProcessing {
+		// Already processed, after being queued more than once
+		return
+	}
+	upload.Status = core.BlobUploadStatusProcessing
+	if err := bs.writeBlobUploadMeta(upload); err != nil {
+		// Left pending, so it is retried on restart
+		log.L(ctx).Errorf("Failed to start processing async blob upload: %s", err)
+		return
+	}
+
+	processed := &atomic.Int64{}
+	bs.async.progress.Store(*id, processed)
+	defer bs.async.progress.Delete(*id)
+	data, err := bs.storeStagedBlob(ctx, upload, func(r io.Reader) io.Reader {
+		return &progressReader{reader: r, processed: processed}
+	})
+
+	var event *core.Event
+	upload.Processed = processed.Load()
+	if err != nil {
+		log.L(ctx).Errorf("Async blob upload failed: %s", err)
+		upload.Status = core.BlobUploadStatusFailed
+		upload.Error = err.Error()
+		event = core.NewEvent(core.EventTypeDataUploadFailed, upload.Namespace, upload.ID, nil, upload.ID.String())
+	} else {
+		log.L(ctx).Infof("Async blob upload created data %s", data.ID)
+		upload.Status = core.BlobUploadStatusSucceeded
+		upload.Data = data.ID
+		event = core.NewEvent(core.EventTypeDataUploadCompleted, upload.Namespace, upload.ID, nil, upload.ID.String())
+		event.Correlator = data.ID
+	}
+	if err := bs.writeBlobUploadMeta(upload); err != nil {
+		// The staged blob is kept, as the upload is still recorded as processing
+		log.L(ctx).Errorf("Failed to record the result of async blob upload: %s", err)
+	} else {
+		_, blobPath := bs.uploadPaths(id)
+		if err := os.Remove(blobPath); err != nil {
+			log.L(ctx).Warnf("Failed to remove staged blob upload file %s: %s", blobPath, err)
+		}
+	}
+	if err := bs.database.InsertEvent(ctx, event); err != nil {
+		log.L(ctx).Errorf("Failed to emit %s event for async blob upload: %s", event.Type, err)
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestAsyncUploads replaces the work queue of the started workers, so the tests drive the processing
+func newTestAsyncUploads(t *testing.T) (*dataManager, context.Context, func()) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	dm.async.work = make(chan *fftypes.UUID, 10)
+	return dm, ctx, cancel
+}
+
+func mockAsyncUploadStored(t *testing.T, dm *dataManager, b []byte) {
+	mdi := dm.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("UpsertData", mock.Anything, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("InsertBlob", mock.Anything, mock.Anything).Return(nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		readBytes, err := io.ReadAll(a[3].(io.Reader))
+		assert.NoError(t, err)
+		assert.Equal(t, b, readBytes)
+		var hash fftypes.Bytes32 = sha256.Sum256(b)
+		dxUpload.ReturnArguments = mock.Arguments{fmt.Sprintf("ns1/%s", a[2].(fftypes.UUID)), &hash, int64(len(b)), nil}
+	}
+}
+
+func writeTestAsyncUpload(t *testing.T, dm *dataManager, status core.BlobUploadStatus) *core.BlobUpload {
+	upload := &core.BlobUpload{ID: fftypes.NewUUID(), Namespace: "ns1", Status: status}
+	_, blobPath := dm.uploadPaths(upload.ID)
+	assert.NoError(t, os.MkdirAll(dm.uploadDir, 0700))
+	assert.NoError(t, os.WriteFile(blobPath, []byte("hello"), 0600))
+	assert.NoError(t, dm.writeBlobUploadMeta(upload))
+	return upload
+}
+
+func TestUploadBlobAsyncE2E(t *testing.T) {
+	config.Set(coreconfig.BlobUploadAsyncWorkerCount, 1)
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	b := []byte("hello world")
+	mockAsyncUploadStored(t, dm, b)
+	mdi := dm.database.(*databasemocks.Plugin)
+	events := make(chan *core.Event, 1)
+	mdi.On("InsertEvent", mock.Anything, mock.Anything).Run(func(a mock.Arguments) {
+		events <- a[1].(*core.Event)
+	}).Return(nil)
+
+	upload, err := dm.UploadBlobAsync(ctx, &core.DataRefOrValue{
+		Value: fftypes.JSONAnyPtr(`{"some":"value"}`),
+	}, &ffapi.Multipart{
+		Data:     bytes.NewReader(b),
+		Filename: "hello.txt",
+		Mimetype: "text/plain",
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStatusPending, upload.Status)
+	assert.Equal(t, int64(len(b)), upload.Size)
+
+	event := <-events
+	assert.Equal(t, core.EventTypeDataUploadCompleted, event.Type)
+	assert.Equal(t, upload.ID, event.Reference)
+	assert.Equal(t, upload.ID.String(), event.Topic)
+
+	// The result is kept for the caller to query, but the staged bytes are gone
+	completed, err := dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStatusSucceeded, completed.Status)
+	assert.Equal(t, int64(len(b)), completed.Processed)
+	assert.Equal(t, event.Correlator, completed.Data)
+	assert.Equal(t, "hello.txt", completed.Filename)
+	_, blobPath := dm.uploadPaths(upload.ID)
+	_, err = os.Stat(blobPath)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10600.*succeeded", err)
+	err = dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUploadBlobAsyncDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{}, false)
+	assert.Regexp(t, "FF10414", err)
+	dm.startAsyncUploads(ctx)
+}
+
+func TestUploadBlobAsyncStagingFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	notADir := filepath.Join(dm.uploadDir, "file")
+	err := os.WriteFile(notADir, []byte{}, 0600)
+	assert.NoError(t, err)
+	dm.uploadDir = filepath.Join(notADir, "uploads")
+
+	_, err = dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{}, false)
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestUploadBlobAsyncStreamFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	_, err := dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{
+		Data: iotest.ErrReader(fmt.Errorf("pop")),
+	}, false)
+	assert.Regexp(t, "FF10217.*pop", err)
+	entries, _ := os.ReadDir(dm.uploadDir)
+	assert.Empty(t, entries)
+}
+
+type blockMetaReader struct {
+	t   *testing.T
+	dir string
+}
+
+func (r *blockMetaReader) Read(p []byte) (int, error) {
+	// Put a directory where the metadata will be written, once the blob is staged
+	entries, err := os.ReadDir(r.dir)
+	assert.NoError(r.t, err)
+	assert.NoError(r.t, os.Mkdir(filepath.Join(r.dir, entries[0].Name()[0:36]+".json.tmp"), 0700))
+	return 0, io.EOF
+}
+
+func TestUploadBlobAsyncMetaFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	_, err := dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{
+		Data: &blockMetaReader{t: t, dir: dm.uploadDir},
+	}, false)
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestUploadBlobAsyncQueueContextCancelled(t *testing.T) {
+	dm, _, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	dm.async.work = make(chan *fftypes.UUID)
+
+	ctx, cancelReq := context.WithCancel(context.Background())
+	cancelReq()
+	_, err := dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{
+		Data: bytes.NewReader([]byte("hello")),
+	}, false)
+	assert.Regexp(t, "FF00154", err)
+
+	// The upload remains pending, to be recovered on restart
+	entries, _ := os.ReadDir(dm.uploadDir)
+	assert.Len(t, entries, 2)
+}
+
+func TestRecoverAsyncUploads(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	pending := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	processing := writeTestAsyncUpload(t, dm, core.BlobUploadStatusProcessing)
+	writeTestAsyncUpload(t, dm, core.BlobUploadStatusSucceeded)
+	writeTestAsyncUpload(t, dm, core.BlobUploadStatusReceiving)
+	assert.NoError(t, os.WriteFile(filepath.Join(dm.uploadDir, "other.json"), []byte{}, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dm.uploadDir, fftypes.NewUUID().String()+".json"), []byte("!json"), 0600))
+
+	recovered := dm.recoverAsyncUploads(ctx)
+	assert.ElementsMatch(t, []*fftypes.UUID{pending.ID, processing.ID}, recovered)
+
+	dm.queueAsyncUploads(ctx, recovered)
+	assert.Len(t, dm.async.work, 2)
+}
+
+func TestRecoverAsyncUploadsNoDir(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	dm.uploadDir = filepath.Join(dm.uploadDir, "missing")
+
+	assert.Empty(t, dm.recoverAsyncUploads(ctx))
+}
+
+func TestQueueAsyncUploadsContextCancelled(t *testing.T) {
+	dm, _, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	dm.async.work = make(chan *fftypes.UUID)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancelCtx()
+	dm.queueAsyncUploads(ctx, []*fftypes.UUID{fftypes.NewUUID()})
+}
+
+func TestProcessAsyncUploadFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(0), fmt.Errorf("pop"))
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeDataUploadFailed
+	})).Return(fmt.Errorf("pop"))
+
+	upload := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	dm.processAsyncUpload(ctx, upload.ID)
+
+	failed, err := dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStatusFailed, failed.Status)
+	assert.Equal(t, "pop", failed.Error)
+	assert.Nil(t, failed.Data)
+
+	mdx.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestProcessAsyncUploadResultFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	upload := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	metaPath, blobPath := dm.uploadPaths(upload.ID)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything).Run(func(a mock.Arguments) {
+		assert.NoError(t, os.Mkdir(metaPath+".tmp", 0700))
+	}).Return("", nil, int64(0), fmt.Errorf("pop"))
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	dm.processAsyncUpload(ctx, upload.ID)
+
+	// The staged blob is kept, as the result could not be recorded
+	_, err := os.Stat(blobPath)
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestProcessAsyncUploadRemoveFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	upload := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	_, blobPath := dm.uploadPaths(upload.ID)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything).Run(func(a mock.Arguments) {
+		assert.NoError(t, os.Remove(blobPath))
+	}).Return("", nil, int64(0), fmt.Errorf("pop"))
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	dm.processAsyncUpload(ctx, upload.ID)
+
+	mdx.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestProcessAsyncUploadStartFail(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	upload := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	metaPath, _ := dm.uploadPaths(upload.ID)
+	assert.NoError(t, os.Mkdir(metaPath+".tmp", 0700))
+
+	dm.processAsyncUpload(ctx, upload.ID)
+
+	pending, err := dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStatusPending, pending.Status)
+}
+
+func TestProcessAsyncUploadSkipped(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	// Locked, deleted, or already processed uploads are all skipped without any action
+	locked := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	dm.uploadsActive[*locked.ID] = true
+	dm.processAsyncUpload(ctx, locked.ID)
+	dm.processAsyncUpload(ctx, fftypes.NewUUID())
+	succeeded := writeTestAsyncUpload(t, dm, core.BlobUploadStatusSucceeded)
+	dm.processAsyncUpload(ctx, succeeded.ID)
+}
+
+func TestGetBlobUploadAsyncProgress(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+
+	upload := writeTestAsyncUpload(t, dm, core.BlobUploadStatusProcessing)
+	processed := &atomic.Int64{}
+	processed.Store(3)
+	dm.async.progress.Store(*upload.ID, processed)
+
+	inProgress, err := dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), inProgress.Processed)
+	assert.Equal(t, int64(5), inProgress.Offset)
+}
@@ -0,0 +1,133 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// The staging directory is bounded in two ways. The total size of the staged blobs is capped, with chunks and
+// uploads that would exceed the cap rejected. Uploads that are abandoned part way through by the caller, or
+// async uploads that finished long ago, are swept from the directory once they are older than the TTL.
+
+type uploadStaging struct {
+	ttl           time.Duration
+	sweepInterval time.Duration
+	maxSize       int64
+}
+
+func newUploadStaging() uploadStaging {
+	return uploadStaging{
+		ttl:           config.GetDuration(coreconfig.BlobUploadStagingTTL),
+		sweepInterval: config.GetDuration(coreconfig.BlobUploadStagingSweepInterval),
+		maxSize:       config.GetByteSize(coreconfig.BlobUploadStagingMaxSize),
+	}
+}
+
+// stagingAvailable returns the number of bytes that can still be staged, or an error if the staging directory is full
+func (bs *blobStore) stagingAvailable(ctx context.Context) (int64, error) {
+	staged := int64(0)
+	matches, _ := filepath.Glob(filepath.Join(bs.uploadDir, "*.blob"))
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil {
+			staged += info.Size()
+		}
+	}
+	if staged >= bs.staging.maxSize {
+		return 0, i18n.NewError(ctx, coremsgs.MsgBlobUploadStagingFull, staged, bs.staging.maxSize)
+	}
+	return bs.staging.maxSize - staged, nil
+}
+
+func (bs *blobStore) stagingSweeper(ctx context.Context) {
+	defer bs.async.workersDone.Done()
+	ticker := time.NewTicker(bs.staging.sweepInterval)
+	defer ticker.Stop()
+	for {
+		bs.sweepStagedUploads(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweepStagedUploads removes uploads that are still receiving but have not been written to within the TTL,
+// and the records of async uploads that finished before the TTL. Pending and processing async uploads are kept.
+func (bs *blobStore) sweepStagedUploads(ctx context.Context) (removed int) {
+	entries, err := os.ReadDir(bs.uploadDir)
+	if err != nil {
+		return 0
+	}
+	cutoff := time.Now().Add(-bs.staging.ttl)
+	for _, entry := range entries {
+		id, err := fftypes.ParseUUID(ctx, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if bs.sweepStagedUpload(ctx, id, cutoff) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.L(ctx).Infof("Removed %d stale blob uploads from %s", removed, bs.uploadDir)
+	}
+	return removed
+}
+
+func (bs *blobStore) sweepStagedUpload(ctx context.Context, id *fftypes.UUID, cutoff time.Time) bool {
+	_, unlock, err := bs.lockUpload(ctx, id.String())
+	if err != nil {
+		// Being written to right now
+		return false
+	}
+	defer unlock()
+
+	upload, err := bs.loadBlobUpload(ctx, id)
+	if err != nil {
+		return false
+	}
+	metaPath, blobPath := bs.uploadPaths(id)
+	lastActive := metaPath
+	switch upload.Status {
+	case core.BlobUploadStatusReceiving:
+		lastActive = blobPath
+	case core.BlobUploadStatusSucceeded, core.BlobUploadStatusFailed:
+	default:
+		return false
+	}
+	info, err := os.Stat(lastActive)
+	if err != nil || info.ModTime().After(cutoff) {
+		return false
+	}
+	log.L(ctx).Infof("Removing %s blob upload %s, last active %s", upload.Status, id, info.ModTime())
+	bs.removeBlobUpload(ctx, id)
+	return true
+}
@@ -0,0 +1,171 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func stageTestBytes(t *testing.T, dm *dataManager, size int) {
+	_, blobPath := dm.uploadPaths(fftypes.NewUUID())
+	assert.NoError(t, os.MkdirAll(dm.uploadDir, 0700))
+	assert.NoError(t, os.WriteFile(blobPath, make([]byte, size), 0600))
+}
+
+func ageTestUpload(t *testing.T, dm *dataManager, id *fftypes.UUID, age time.Duration) {
+	metaPath, blobPath := dm.uploadPaths(id)
+	when := time.Now().Add(-age)
+	for _, path := range []string{metaPath, blobPath} {
+		if _, err := os.Stat(path); err == nil {
+			assert.NoError(t, os.Chtimes(path, when, when))
+		}
+	}
+}
+
+func TestCreateBlobUploadStagingFull(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+	dm.staging.maxSize = 10
+
+	_, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{Size: 11})
+	assert.Regexp(t, "FF10644.*0 bytes of the 10", err)
+
+	stageTestBytes(t, dm, 10)
+	_, err = dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10644.*10 bytes of the 10", err)
+}
+
+func TestAppendBlobUploadStagingFull(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+	dm.staging.maxSize = 5
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader([]byte("hello world")))
+	assert.Regexp(t, "FF10644.*0 bytes of the 5", err)
+
+	// What fitted is kept, but nothing more can be appended
+	upload, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), upload.Offset)
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 5, bytes.NewReader([]byte(" world")))
+	assert.Regexp(t, "FF10644.*5 bytes of the 5", err)
+}
+
+func TestAppendBlobUploadStagingExactFit(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+	dm.staging.maxSize = 5
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+
+	upload, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader([]byte("hello")))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), upload.Offset)
+}
+
+func TestUploadBlobAsyncStagingFull(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	dm.staging.maxSize = 5
+
+	_, err := dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{
+		Data: bytes.NewReader([]byte("hello world")),
+	}, false)
+	assert.Regexp(t, "FF10644.*0 bytes of the 5", err)
+	entries, _ := os.ReadDir(dm.uploadDir)
+	assert.Empty(t, entries)
+
+	stageTestBytes(t, dm, 5)
+	_, err = dm.UploadBlobAsync(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{
+		Data: bytes.NewReader([]byte("hello world")),
+	}, false)
+	assert.Regexp(t, "FF10644.*5 bytes of the 5", err)
+}
+
+func TestSweepStagedUploads(t *testing.T) {
+	dm, ctx, cancel := newTestAsyncUploads(t)
+	defer cancel()
+	dm.staging.ttl = time.Hour
+
+	staleReceiving, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	ageTestUpload(t, dm, staleReceiving.ID, 2*time.Hour)
+	activeReceiving, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	staleSucceeded := writeTestAsyncUpload(t, dm, core.BlobUploadStatusSucceeded)
+	ageTestUpload(t, dm, staleSucceeded.ID, 2*time.Hour)
+	recentFailed := writeTestAsyncUpload(t, dm, core.BlobUploadStatusFailed)
+	stalePending := writeTestAsyncUpload(t, dm, core.BlobUploadStatusPending)
+	ageTestUpload(t, dm, stalePending.ID, 2*time.Hour)
+	staleLocked, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	ageTestUpload(t, dm, staleLocked.ID, 2*time.Hour)
+	_, unlock, err := dm.lockUpload(ctx, staleLocked.ID.String())
+	assert.NoError(t, err)
+	defer unlock()
+	assert.NoError(t, os.WriteFile(dm.uploadDir+"/other.json", []byte{}, 0600))
+	assert.NoError(t, os.WriteFile(dm.uploadDir+"/"+fftypes.NewUUID().String()+".json", []byte("!json"), 0600))
+
+	assert.Equal(t, 2, dm.sweepStagedUploads(ctx))
+
+	for _, upload := range []*core.BlobUpload{staleReceiving, staleSucceeded} {
+		_, err := dm.GetBlobUpload(ctx, upload.ID.String())
+		assert.Regexp(t, "FF10557", err)
+	}
+	for _, upload := range []*core.BlobUpload{activeReceiving, recentFailed, stalePending, staleLocked} {
+		_, err := dm.GetBlobUpload(ctx, upload.ID.String())
+		assert.NoError(t, err)
+	}
+}
+
+func TestSweepStagedUploadsNoDir(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+	dm.uploadDir = dm.uploadDir + "/missing"
+
+	assert.Zero(t, dm.sweepStagedUploads(ctx))
+}
+
+func TestStagingSweeperStops(t *testing.T) {
+	dm, _, cancel := newTestBlobUploads(t)
+	defer cancel()
+	dm.staging.sweepInterval = time.Millisecond
+
+	ctx, stop := context.WithCancel(context.Background())
+	upload := writeTestAsyncUpload(t, dm, core.BlobUploadStatusSucceeded)
+	ageTestUpload(t, dm, upload.ID, 48*time.Hour)
+	dm.async.workerCount = 0
+	dm.startAsyncUploads(ctx)
+	assert.Eventually(t, func() bool {
+		_, err := dm.GetBlobUpload(ctx, upload.ID.String())
+		return err != nil
+	}, time.Second, time.Millisecond)
+	stop() // waited for by the deferred cancel
+}
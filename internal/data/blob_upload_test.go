@@ -0,0 +1,399 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBlobUploads(t *testing.T) (*dataManager, context.Context, func()) {
+	dm, ctx, cancel := newTestDataManager(t)
+	dm.uploadDir = t.TempDir()
+	return dm, ctx, cancel
+}
+
+func TestBlobUploadStagingDir(t *testing.T) {
+	coreconfig.Reset()
+	assert.Equal(t, filepath.Join(os.TempDir(), "firefly-blob-uploads", "ns1"), blobUploadStagingDir("ns1"))
+	config.Set(coreconfig.BlobUploadStagingDirectory, "/data/uploads")
+	assert.Equal(t, "/data/uploads/ns1", blobUploadStagingDir("ns1"))
+}
+
+func TestBlobUploadE2E(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	b := []byte("hello world")
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("UpsertData", mock.Anything, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("InsertBlob", mock.Anything, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.Size == int64(len(b))
+	})).Return(nil)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		readBytes, err := io.ReadAll(a[3].(io.Reader))
+		assert.NoError(t, err)
+		assert.Equal(t, b, readBytes)
+		var hash fftypes.Bytes32 = sha256.Sum256(b)
+		dxUpload.ReturnArguments = mock.Arguments{fmt.Sprintf("ns1/%s", a[2].(fftypes.UUID)), &hash, int64(len(b)), nil}
+	}
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{
+		Filename: "hello.txt",
+		Mimetype: "text/plain",
+		AutoMeta: true,
+		Size:     int64(len(b)),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", upload.Namespace)
+	assert.Zero(t, upload.Offset)
+
+	upload, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader(b[0:6]))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), upload.Offset)
+
+	// A retry of the same chunk is rejected, rather than appended twice
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader(b[0:6]))
+	assert.Regexp(t, "FF10559", err)
+
+	upload, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), upload.Offset)
+	assert.Equal(t, "hello.txt", upload.Filename)
+
+	upload, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 6, bytes.NewReader(b[6:]))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(b)), upload.Offset)
+
+	data, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, [32]byte(sha256.Sum256(b)), [32]byte(*data.Blob.Hash))
+	assert.Equal(t, "hello.txt", data.Value.JSONObject().GetString("filename"))
+	assert.Equal(t, "text/plain", data.Value.JSONObject().GetString("mimetype"))
+
+	_, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10557", err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestCreateBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestCreateBlobUploadStagingFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+	notADir := filepath.Join(dm.uploadDir, "file")
+	err := os.WriteFile(notADir, []byte{}, 0600)
+	assert.NoError(t, err)
+	dm.uploadDir = filepath.Join(notADir, "uploads")
+
+	_, err = dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestGetBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	_, err := dm.GetBlobUpload(ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetBlobUploadReadFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	metaPath, _ := dm.uploadPaths(upload.ID)
+	assert.NoError(t, os.Remove(metaPath))
+	assert.NoError(t, os.Mkdir(metaPath, 0700))
+
+	_, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestGetBlobUploadBadMetadata(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	metaPath, _ := dm.uploadPaths(upload.ID)
+	assert.NoError(t, os.WriteFile(metaPath, []byte("!json"), 0600))
+
+	_, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestGetBlobUploadStatFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	_, blobPath := dm.uploadPaths(upload.ID)
+	assert.NoError(t, os.Remove(blobPath))
+	assert.NoError(t, os.Symlink(blobPath, blobPath))
+
+	_, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10562", err)
+}
+
+// replaceWithSocket swaps the staged blob for a file that can be stat'd, but not opened
+func replaceWithSocket(t *testing.T, path string) {
+	assert.NoError(t, os.Remove(path))
+	l, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+}
+
+func TestAppendBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	_, err := dm.AppendBlobUpload(ctx, "bad", 0, bytes.NewReader([]byte{}))
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestAppendBlobUploadInProgress(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	id := fftypes.NewUUID()
+	dm.uploadsActive[*id] = true
+	_, err := dm.AppendBlobUpload(ctx, id.String(), 0, bytes.NewReader([]byte{}))
+	assert.Regexp(t, "FF10558", err)
+}
+
+func TestAppendBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	_, err := dm.AppendBlobUpload(ctx, fftypes.NewUUID().String(), 0, bytes.NewReader([]byte{}))
+	assert.Regexp(t, "FF10557", err)
+}
+
+func TestAppendBlobUploadOpenFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	_, blobPath := dm.uploadPaths(upload.ID)
+	replaceWithSocket(t, blobPath)
+
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader([]byte("hello")))
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestAppendBlobUploadStreamFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+
+	chunk := io.MultiReader(bytes.NewReader([]byte("hello")), iotest.ErrReader(fmt.Errorf("pop")))
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, chunk)
+	assert.Regexp(t, "FF10217.*pop", err)
+
+	// The bytes received before the failure are kept to resume from
+	upload, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), upload.Offset)
+}
+
+func TestAppendBlobUploadExceedsSize(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{Size: 5})
+	assert.NoError(t, err)
+
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader([]byte("hello world")))
+	assert.Regexp(t, "FF10560", err)
+
+	upload, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), upload.Offset)
+}
+
+func TestCompleteBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	_, err := dm.CompleteBlobUpload(ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestCompleteBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	_, err := dm.CompleteBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10557", err)
+}
+
+func TestCompleteBlobUploadIncomplete(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{Size: 100})
+	assert.NoError(t, err)
+
+	_, err = dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10561", err)
+}
+
+func TestCompleteBlobUploadOpenFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+	_, blobPath := dm.uploadPaths(upload.ID)
+	replaceWithSocket(t, blobPath)
+
+	_, err = dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10562", err)
+}
+
+func TestCompleteBlobUploadStoreFail(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(0), fmt.Errorf("pop"))
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+
+	_, err = dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	// The upload is kept, so completion can be retried
+	_, err = dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
+}
+
+func TestDeleteBlobUpload(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload, err := dm.CreateBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.NoError(t, err)
+
+	err = dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+
+	err = dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10557", err)
+}
+
+func TestDeleteBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	err := dm.DeleteBlobUpload(ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestRemoveBlobUploadMissingFiles(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	// Missing files are ignored, and other failures are logged but not returned
+	id := fftypes.NewUUID()
+	_, blobPath := dm.uploadPaths(id)
+	err := os.MkdirAll(filepath.Join(blobPath, "notempty"), 0700)
+	assert.NoError(t, err)
+	dm.removeBlobUpload(ctx, id)
+}
+
+func TestGetBlobUploadLegacyStatus(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	// Uploads staged before the status was recorded are still receiving
+	id := fftypes.NewUUID()
+	metaPath, blobPath := dm.uploadPaths(id)
+	err := os.WriteFile(metaPath, []byte(`{"id":"`+id.String()+`"}`), 0600)
+	assert.NoError(t, err)
+	err = os.WriteFile(blobPath, []byte("hello"), 0600)
+	assert.NoError(t, err)
+
+	upload, err := dm.GetBlobUpload(ctx, id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.BlobUploadStatusReceiving, upload.Status)
+	assert.Equal(t, int64(5), upload.Offset)
+}
+
+func TestAppendCompleteBlobUploadNotReceiving(t *testing.T) {
+	dm, ctx, cancel := newTestBlobUploads(t)
+	defer cancel()
+
+	upload := &core.BlobUpload{ID: fftypes.NewUUID(), Status: core.BlobUploadStatusPending}
+	_, blobPath := dm.uploadPaths(upload.ID)
+	err := os.WriteFile(blobPath, []byte{}, 0600)
+	assert.NoError(t, err)
+	err = dm.writeBlobUploadMeta(upload)
+	assert.NoError(t, err)
+
+	_, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 0, bytes.NewReader([]byte("hello")))
+	assert.Regexp(t, "FF10600.*pending", err)
+	_, err = dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10600.*pending", err)
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"io"
+	"sync"
 
 	"github.com/docker/go-units"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -34,9 +35,14 @@ import (
 )
 
 type blobStore struct {
-	dm       *dataManager
-	database database.Plugin
-	exchange dataexchange.Plugin // optional
+	dm            *dataManager
+	database      database.Plugin
+	exchange      dataexchange.Plugin // optional
+	uploadDir     string
+	uploadLock    sync.Mutex
+	uploadsActive map[fftypes.UUID]bool
+	async         asyncUploads
+	staging       uploadStaging
 }
 
 func (bs *blobStore) uploadVerifyBlob(ctx context.Context, id *fftypes.UUID, reader io.Reader) (hash *fftypes.Bytes32, written int64, payloadRef string, err error) {
@@ -83,6 +89,10 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
 
+	return bs.storeBlob(ctx, inData, mpart.Data, mpart.Filename, mpart.Mimetype, autoMeta)
+}
+
+func (bs *blobStore) storeBlob(ctx context.Context, inData *core.DataRefOrValue, reader io.Reader, filename, mimetype string, autoMeta bool) (*core.Data, error) {
 	data := &core.Data{
 		ID:        fftypes.NewUUID(),
 		Namespace: bs.dm.namespace.Name,
@@ -92,7 +102,7 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 		Value:     inData.Value,
 	}
 
-	hash, blobSize, payloadRef, err := bs.uploadVerifyBlob(ctx, data.ID, mpart.Data)
+	hash, blobSize, payloadRef, err := bs.uploadVerifyBlob(ctx, data.ID, reader)
 	if err != nil {
 		return nil, err
 	}
@@ -101,8 +111,8 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 	// autoMeta will create/update JSON metadata with the upload details
 	if autoMeta {
 		do := data.Value.JSONObject()
-		do["filename"] = mpart.Filename
-		do["mimetype"] = mpart.Mimetype
+		do["filename"] = filename
+		do["mimetype"] = mimetype
 		b, _ := json.Marshal(&do)
 		data.Value = fftypes.JSONAnyPtrBytes(b)
 	}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -36,6 +37,8 @@ import (
 
 type Manager interface {
 	CheckDatatype(ctx context.Context, datatype *core.Datatype) error
+	UpdateDatatypeLifecycle(ctx context.Context, name, version string, update *core.DefinitionLifecycleUpdate) (*core.Datatype, error)
+	GetDatatypeDiff(ctx context.Context, name, fromVersion, toVersion string) (*core.DefinitionDiff, error)
 	ValidateAll(ctx context.Context, data core.DataArray) (valid bool, err error)
 	GetMessageWithDataCached(ctx context.Context, msgID *fftypes.UUID, options ...CacheReadOption) (msg *core.Message, data core.DataArray, foundAllData bool, err error)
 	GetMessageDataCached(ctx context.Context, msg *core.Message, options ...CacheReadOption) (data core.DataArray, foundAll bool, err error)
@@ -45,11 +48,18 @@ type Manager interface {
 	UpdateMessageStateIfCached(ctx context.Context, id *fftypes.UUID, state core.MessageState, confirmed *fftypes.FFTime, rejectReason string)
 	ResolveInlineData(ctx context.Context, msg *NewMessage) error
 	WriteNewMessage(ctx context.Context, newMsg *NewMessage) error
+	GetIdempotentMessage(ctx context.Context, idempotencyKey core.IdempotencyKey) (*core.Message, error)
 	BlobsEnabled() bool
 
 	UploadJSON(ctx context.Context, inData *core.DataRefOrValue) (*core.Data, error)
 	UploadBlob(ctx context.Context, inData *core.DataRefOrValue, blob *ffapi.Multipart, autoMeta bool) (*core.Data, error)
+	UploadBlobAsync(ctx context.Context, inData *core.DataRefOrValue, blob *ffapi.Multipart, autoMeta bool) (*core.BlobUpload, error)
 	DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error)
+	CreateBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error)
+	GetBlobUpload(ctx context.Context, uploadID string) (*core.BlobUpload, error)
+	AppendBlobUpload(ctx context.Context, uploadID string, offset int64, chunk io.Reader) (*core.BlobUpload, error)
+	CompleteBlobUpload(ctx context.Context, uploadID string) (*core.Data, error)
+	DeleteBlobUpload(ctx context.Context, uploadID string) error
 	DeleteData(ctx context.Context, dataID string) error
 	HydrateBatch(ctx context.Context, persistedBatch *core.BatchPersisted) (*core.Batch, error)
 	Start()
@@ -58,11 +68,14 @@ type Manager interface {
 
 type dataManager struct {
 	blobStore
-	namespace      *core.Namespace
-	database       database.Plugin
-	validatorCache cache.CInterface
-	messageCache   cache.CInterface
-	messageWriter  *messageWriter
+	ctx               context.Context
+	namespace         *core.Namespace
+	database          database.Plugin
+	validatorCache    cache.CInterface
+	messageCache      cache.CInterface
+	messageWriter     *messageWriter
+	rejectDeprecated  bool
+	idempotencyWindow time.Duration
 }
 
 type messageCacheEntry struct {
@@ -98,13 +111,20 @@ func NewDataManager(ctx context.Context, ns *core.Namespace, di database.Plugin,
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "DataManager")
 	}
 	dm := &dataManager{
-		namespace: ns,
-		database:  di,
+		ctx:               ctx,
+		namespace:         ns,
+		database:          di,
+		rejectDeprecated:  config.GetString(coreconfig.DefinitionsDeprecatedPolicy) == coreconfig.DeprecatedPolicyReject,
+		idempotencyWindow: config.GetDuration(coreconfig.TransactionIdempotencyWindow),
 	}
 	dm.blobStore = blobStore{
-		dm:       dm,
-		database: di,
-		exchange: dx,
+		dm:            dm,
+		database:      di,
+		exchange:      dx,
+		uploadDir:     blobUploadStagingDir(ns.Name),
+		uploadsActive: make(map[fftypes.UUID]bool),
+		async:         newAsyncUploads(),
+		staging:       newUploadStaging(),
 	}
 
 	validatorCache, err := cacheManager.GetCache(
@@ -142,6 +162,7 @@ func NewDataManager(ctx context.Context, ns *core.Namespace, di database.Plugin,
 
 func (dm *dataManager) Start() {
 	dm.messageWriter.start()
+	dm.startAsyncUploads(dm.ctx)
 }
 
 func (dm *dataManager) BlobsEnabled() bool {
@@ -153,6 +174,52 @@ func (dm *dataManager) CheckDatatype(ctx context.Context, datatype *core.Datatyp
 	return err
 }
 
+func (dm *dataManager) validatorCacheKey(validator core.ValidatorType, datatypeRef *core.DatatypeRef) string {
+	return fmt.Sprintf("%s:%s:%s", validator, dm.namespace.Name, datatypeRef)
+}
+
+func (dm *dataManager) getDatatypeByName(ctx context.Context, name, version string) (*core.Datatype, error) {
+	datatype, err := dm.database.GetDatatypeByName(ctx, dm.namespace.Name, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if datatype == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	return datatype, nil
+}
+
+// UpdateDatatypeLifecycle changes the local lifecycle state of a datatype, which controls whether it can be used to validate new data
+func (dm *dataManager) UpdateDatatypeLifecycle(ctx context.Context, name, version string, update *core.DefinitionLifecycleUpdate) (*core.Datatype, error) {
+	lifecycle, err := fftypes.FFEnumParseString(ctx, "definitionlifecycle", string(update.Lifecycle))
+	if err != nil {
+		return nil, err
+	}
+	datatype, err := dm.getDatatypeByName(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := dm.database.UpdateDatatypeLifecycle(ctx, dm.namespace.Name, datatype.ID, lifecycle); err != nil {
+		return nil, err
+	}
+	datatype.Lifecycle = lifecycle
+	dm.validatorCache.Delete(dm.validatorCacheKey(datatype.Validator, &core.DatatypeRef{Name: name, Version: version}))
+	return datatype, nil
+}
+
+// GetDatatypeDiff compares the schemas of two versions of a datatype
+func (dm *dataManager) GetDatatypeDiff(ctx context.Context, name, fromVersion, toVersion string) (*core.DefinitionDiff, error) {
+	from, err := dm.getDatatypeByName(ctx, name, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := dm.getDatatypeByName(ctx, name, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return core.NewDefinitionDiff(name, fromVersion, toVersion, from.Value, to.Value), nil
+}
+
 // getValidatorForDatatype only returns database errors - not found (of all kinds) is a nil
 func (dm *dataManager) getValidatorForDatatype(ctx context.Context, validator core.ValidatorType, datatypeRef *core.DatatypeRef) (Validator, error) {
 	if validator == "" {
@@ -164,7 +231,7 @@ func (dm *dataManager) getValidatorForDatatype(ctx context.Context, validator co
 		return nil, nil
 	}
 
-	key := fmt.Sprintf("%s:%s:%s", validator, dm.namespace.Name, datatypeRef)
+	key := dm.validatorCacheKey(validator, datatypeRef)
 	if cachedValue := dm.validatorCache.Get(key); cachedValue != nil {
 		return cachedValue.(Validator), nil
 	}
@@ -391,6 +458,9 @@ func (dm *dataManager) checkValidation(ctx context.Context, validator core.Valid
 			if v == nil {
 				return i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, datatype)
 			}
+			if err := core.CheckDefinitionLifecycle(ctx, "datatype", datatype.String(), v.Lifecycle(), dm.rejectDeprecated); err != nil {
+				return err
+			}
 			err = v.ValidateValue(ctx, value, nil)
 			if err != nil {
 				return err
@@ -517,6 +587,26 @@ func (dm *dataManager) HydrateBatch(ctx context.Context, persistedBatch *core.Ba
 	return batch, nil
 }
 
+// GetIdempotentMessage looks for an existing message with the supplied idempotency key, which was submitted within the
+// idempotency window. If one is found, it is the original submission that should be returned to the caller.
+// Outside of the window nil is returned, and the duplicate is reported as a conflict when the message is written.
+func (dm *dataManager) GetIdempotentMessage(ctx context.Context, idempotencyKey core.IdempotencyKey) (*core.Message, error) {
+	if dm.idempotencyWindow <= 0 || idempotencyKey == "" {
+		return nil, nil
+	}
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	existing, _, err := dm.database.GetMessages(ctx, dm.namespace.Name, fb.Eq("idempotencykey", (string)(idempotencyKey)))
+	if err != nil || len(existing) == 0 {
+		return nil, err
+	}
+	msg := existing[0]
+	if msg.Header.Created == nil || time.Since(*msg.Header.Created.Time()) > dm.idempotencyWindow {
+		log.L(ctx).Infof("Message %s with idempotency key '%s' is outside of the idempotency window", msg.Header.ID, idempotencyKey)
+		return nil, nil
+	}
+	return msg, nil
+}
+
 // WriteNewMessage dispatches the writing of the message and assocated data, then blocks until the background
 // worker (or foreground if no DB concurrency) has written. The caller MUST NOT call this inside of a
 // DB RunAsGroup - because if a large number of routines enter the same function they could starve the background
@@ -541,6 +631,7 @@ func (dm *dataManager) WriteNewMessage(ctx context.Context, newMsg *NewMessage)
 
 func (dm *dataManager) WaitStop() {
 	dm.messageWriter.close()
+	dm.waitAsyncUploadsStopped()
 }
 
 func (dm *dataManager) DeleteData(ctx context.Context, dataID string) error {
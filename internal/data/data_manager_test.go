@@ -82,6 +82,8 @@ func TestCacheInitFail(t *testing.T) {
 func newTestDataManager(t *testing.T) (*dataManager, context.Context, func()) {
 	coreconfig.Reset()
 	config.Set(coreconfig.MessageWriterCount, 1)
+	config.Set(coreconfig.BlobUploadStagingDirectory, t.TempDir())
+	config.Set(coreconfig.BlobUploadStagingSweepInterval, "0") // tests drive the sweeping
 	ctx, cancel := context.WithCancel(context.Background())
 	mdi := &databasemocks.Plugin{}
 	mdi.On("Capabilities").Return(&database.Capabilities{
@@ -1173,6 +1175,62 @@ func TestWriteNewMessageFailClosed(t *testing.T) {
 	assert.Regexp(t, "FF00154", err)
 }
 
+func TestGetIdempotentMessageWithinWindow(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.idempotencyWindow = 1 * time.Hour
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Created: fftypes.Now()}}
+	mdb.On("GetMessages", ctx, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+
+	existing, err := dm.GetIdempotentMessage(ctx, "idem1")
+	assert.NoError(t, err)
+	assert.Equal(t, msg, existing)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestGetIdempotentMessageOutsideWindow(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.idempotencyWindow = 1 * time.Hour
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	created := fftypes.FFTime(time.Now().Add(-2 * time.Hour))
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Created: &created}}
+	mdb.On("GetMessages", ctx, "ns1", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+
+	existing, err := dm.GetIdempotentMessage(ctx, "idem1")
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	mdb.AssertExpectations(t)
+}
+
+func TestGetIdempotentMessageQueryFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.idempotencyWindow = 1 * time.Hour
+	mdb := dm.database.(*databasemocks.Plugin)
+
+	mdb.On("GetMessages", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := dm.GetIdempotentMessage(ctx, "idem1")
+	assert.EqualError(t, err, "pop")
+
+	mdb.AssertExpectations(t)
+}
+
+func TestGetIdempotentMessageNoWindow(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	existing, err := dm.GetIdempotentMessage(ctx, "idem1")
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
 func TestDeleteData(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
@@ -1379,3 +1437,151 @@ func TestDeleteDataFailGetMessages(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 	mdb.AssertExpectations(t)
 }
+
+func TestCheckValidationDatatypeLifecycle(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(&core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Name:      "customer",
+		Version:   "0.0.1",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Lifecycle: core.DefinitionLifecycleDeprecated,
+	}, nil)
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.2").Return(&core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Name:      "customer",
+		Version:   "0.0.2",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Lifecycle: core.DefinitionLifecycleRetired,
+	}, nil)
+
+	deprecated := &core.DatatypeRef{Name: "customer", Version: "0.0.1"}
+	err := dm.checkValidation(ctx, core.ValidatorTypeJSON, deprecated, fftypes.JSONAnyPtr(`{}`))
+	assert.NoError(t, err)
+
+	dm.rejectDeprecated = true
+	err = dm.checkValidation(ctx, core.ValidatorTypeJSON, deprecated, fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10599", err)
+
+	retired := &core.DatatypeRef{Name: "customer", Version: "0.0.2"}
+	err = dm.checkValidation(ctx, core.ValidatorTypeJSON, retired, fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10598", err)
+}
+
+func TestUpdateDatatypeLifecycle(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	dt := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Name:      "customer",
+		Version:   "0.0.1",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+		Lifecycle: core.DefinitionLifecycleActive,
+	}
+	ref := &core.DatatypeRef{Name: "customer", Version: "0.0.1"}
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(dt, nil).Twice()
+	v, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionLifecycleActive, v.Lifecycle())
+
+	mdi.On("UpdateDatatypeLifecycle", ctx, "ns1", dt.ID, core.DefinitionLifecycleRetired).Return(nil)
+	updated, err := dm.UpdateDatatypeLifecycle(ctx, "customer", "0.0.1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: "Retired",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionLifecycleRetired, updated.Lifecycle)
+
+	// The cached validator is evicted, so the new lifecycle is loaded from the database
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(dt, nil).Once()
+	v, err = dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionLifecycleRetired, v.Lifecycle())
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateDatatypeLifecycleBadLifecycle(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.UpdateDatatypeLifecycle(ctx, "customer", "0.0.1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: "unknown",
+	})
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestUpdateDatatypeLifecycleNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(nil, nil)
+	_, err := dm.UpdateDatatypeLifecycle(ctx, "customer", "0.0.1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: core.DefinitionLifecycleDeprecated,
+	})
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestUpdateDatatypeLifecycleUpdateFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	dt := &core.Datatype{ID: fftypes.NewUUID()}
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(dt, nil)
+	mdi.On("UpdateDatatypeLifecycle", ctx, "ns1", dt.ID, core.DefinitionLifecycleDeprecated).Return(fmt.Errorf("pop"))
+	_, err := dm.UpdateDatatypeLifecycle(ctx, "customer", "0.0.1", &core.DefinitionLifecycleUpdate{
+		Lifecycle: core.DefinitionLifecycleDeprecated,
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDatatypeDiff(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(&core.Datatype{
+		Value: fftypes.JSONAnyPtr(`{"type":"object","required":["name"]}`),
+	}, nil)
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.2").Return(&core.Datatype{
+		Value: fftypes.JSONAnyPtr(`{"type":"object","required":["name","id"]}`),
+	}, nil)
+
+	diff, err := dm.GetDatatypeDiff(ctx, "customer", "0.0.1", "0.0.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.1", diff.From)
+	assert.Equal(t, "0.0.2", diff.To)
+	assert.Len(t, diff.Changes, 1)
+	assert.Equal(t, "/required/1", diff.Changes[0].Path)
+	assert.Equal(t, core.DefinitionChangeTypeAdded, diff.Changes[0].Type)
+}
+
+func TestGetDatatypeDiffFromFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(nil, fmt.Errorf("pop"))
+	_, err := dm.GetDatatypeDiff(ctx, "customer", "0.0.1", "0.0.2")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDatatypeDiffToNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.1").Return(&core.Datatype{}, nil)
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.2").Return(nil, nil)
+	_, err := dm.GetDatatypeDiff(ctx, "customer", "0.0.1", "0.0.2")
+	assert.Regexp(t, "FF10143", err)
+}
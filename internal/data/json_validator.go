@@ -30,11 +30,12 @@ import (
 )
 
 type jsonValidator struct {
-	id       *fftypes.UUID
-	size     int64
-	ns       string
-	datatype *core.DatatypeRef
-	schema   *jsonschema.Schema
+	id        *fftypes.UUID
+	size      int64
+	ns        string
+	datatype  *core.DatatypeRef
+	schema    *jsonschema.Schema
+	lifecycle core.DefinitionLifecycle
 }
 
 func newJSONValidator(ctx context.Context, ns string, datatype *core.Datatype) (*jsonValidator, error) {
@@ -45,6 +46,7 @@ func newJSONValidator(ctx context.Context, ns string, datatype *core.Datatype) (
 			Name:    datatype.Name,
 			Version: datatype.Version,
 		},
+		lifecycle: datatype.Lifecycle,
 	}
 
 	var schemaBytes []byte
@@ -103,6 +105,10 @@ func (jv *jsonValidator) Size() int64 {
 	return jv.size
 }
 
+func (jv *jsonValidator) Lifecycle() core.DefinitionLifecycle {
+	return jv.lifecycle
+}
+
 func jsonDecode(input string) (interface{}, error) {
 	var output interface{}
 	if err := json.Unmarshal([]byte(input), &output); err != nil {
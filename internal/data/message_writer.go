@@ -32,6 +32,9 @@ type NewMessage struct {
 	Message *core.MessageInOut
 	AllData core.DataArray
 	NewData core.DataArray
+	// PreWrite makes other writes that must be committed in the same database transaction as the message,
+	// such as the follow-up actions of the message. A message with a PreWrite is always written in-line.
+	PreWrite func(ctx context.Context) error
 }
 
 // writeRequest is a combination of a message and a list of data that is new and needs to be
@@ -92,7 +95,7 @@ func newMessageWriter(ctx context.Context, di database.Plugin, conf *messageWrit
 // transaction, or just run it in-line on the context passed ini.
 func (mw *messageWriter) WriteNewMessage(ctx context.Context, newMsg *NewMessage) error {
 	log.L(ctx).Debugf("Writing message type=%s id=%s hash=%s idempotencyKey=%s concurrency=%d", newMsg.Message.Header.Type, newMsg.Message.Header.ID, newMsg.Message.Hash, newMsg.Message.IdempotencyKey, mw.conf.workerCount)
-	if mw.conf.workerCount > 0 {
+	if mw.conf.workerCount > 0 && newMsg.PreWrite == nil {
 		// Dispatch to background worker
 		nmi := &writeRequest{
 			id:         newMsg.Message.Message.Header.ID,
@@ -109,6 +112,11 @@ func (mw *messageWriter) WriteNewMessage(ctx context.Context, newMsg *NewMessage
 	}
 	// Otherwise do it in-line on this context
 	err := mw.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if newMsg.PreWrite != nil {
+			if err := newMsg.PreWrite(ctx); err != nil {
+				return err
+			}
+		}
 		return mw.writeMessages(ctx, []*core.Message{&newMsg.Message.Message}, newMsg.NewData)
 	})
 	if err != nil {
@@ -100,6 +100,57 @@ func TestWriteNewMessageSyncFallback(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestWriteNewMessagePreWriteInline(t *testing.T) {
+	mw := newTestMessageWriter(t)
+	mw.start()
+	defer mw.close()
+	customCtx := context.WithValue(context.Background(), "dbtx", "on this context")
+
+	msg1 := &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				ID: fftypes.NewUUID(),
+			},
+		},
+	}
+
+	mdi := mw.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", customCtx, mock.Anything).Run(func(args mock.Arguments) {
+		err := args[1].(func(context.Context) error)(customCtx)
+		assert.NoError(t, err)
+	}).Return(nil)
+	mdi.On("InsertMessages", customCtx, []*core.Message{&msg1.Message}).Return(nil)
+
+	preWritten := false
+	err := mw.WriteNewMessage(customCtx, &NewMessage{
+		Message: msg1,
+		PreWrite: func(ctx context.Context) error {
+			assert.Equal(t, customCtx, ctx)
+			preWritten = true
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, preWritten)
+}
+
+func TestWriteNewMessagePreWriteFail(t *testing.T) {
+	mw := newTestMessageWriterNoConcurrency(t)
+
+	mdi := mw.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", mw.ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+
+	err := mw.WriteNewMessage(mw.ctx, &NewMessage{
+		Message: &core.MessageInOut{},
+		PreWrite: func(ctx context.Context) error {
+			return fmt.Errorf("pop")
+		},
+	})
+	assert.Regexp(t, "pop", err)
+}
+
 func TestWriteDataSyncFallback(t *testing.T) {
 	mw := newTestMessageWriterNoConcurrency(t)
 	customCtx := context.WithValue(context.Background(), "dbtx", "on this context")
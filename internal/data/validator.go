@@ -27,4 +27,5 @@ type Validator interface {
 	Validate(ctx context.Context, data *core.Data) error
 	ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error
 	Size() int64 // for cache management
+	Lifecycle() core.DefinitionLifecycle
 }
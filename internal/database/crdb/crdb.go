@@ -0,0 +1,98 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/cockroachdb"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/dbsql"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/pkg/database"
+
+	// Import pq driver, as CockroachDB uses the PostgreSQL wire protocol
+	_ "github.com/lib/pq"
+)
+
+type CockroachDB struct {
+	sqlcommon.SQLCommon
+}
+
+// The JSON condition matches the expression of the inverted index on the data values
+var dialect = &sqlcommon.Dialect{
+	ReturningSequence: true,
+	ConflictDoNothing: "ON CONFLICT DO NOTHING",
+	JSONContains:      "(%s::jsonb) @> ?::jsonb",
+	IntegerDivide:     "//",
+}
+
+func (crdb *CockroachDB) Init(ctx context.Context, config config.Section) error {
+	capabilities := &database.Capabilities{}
+	if config.GetInt(dbsql.SQLConfMaxConnections) > 1 {
+		capabilities.Concurrency = true
+	}
+	return crdb.SQLCommon.Init(ctx, crdb, dialect, config, capabilities)
+}
+
+func (crdb *CockroachDB) SetHandler(namespace string, handler database.Callbacks) {
+	crdb.SQLCommon.SetHandler(namespace, handler)
+}
+
+func (crdb *CockroachDB) Name() string {
+	return "crdb"
+}
+
+func (crdb *CockroachDB) SequenceColumn() string {
+	return "seq"
+}
+
+func (crdb *CockroachDB) MigrationsDir() string {
+	return crdb.Name()
+}
+
+func (crdb *CockroachDB) Features() dbsql.SQLFeatures {
+	features := dbsql.DefaultSQLProviderFeatures()
+	features.PlaceholderFormat = sq.Dollar
+	features.UseILIKE = false // slower than lower()
+	features.AcquireLock = func(lockName string) string {
+		// CockroachDB does not support advisory locks, so we write to a row in the locks table,
+		// which blocks any other transaction doing the same until this one completes
+		return fmt.Sprintf(`UPSERT INTO locks (name) VALUES ('%s');`, strings.ReplaceAll(lockName, "'", "''"))
+	}
+	// The order of the rows returned from a multi-row INSERT ... RETURNING is not guaranteed
+	features.MultiRowInsert = false
+	return features
+}
+
+func (crdb *CockroachDB) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, requestConflictEmptyResult bool) (sq.InsertBuilder, bool) {
+	return dialect.ApplyInsertQueryCustomizations(insert, crdb.SequenceColumn(), requestConflictEmptyResult)
+}
+
+func (crdb *CockroachDB) Open(url string) (*sql.DB, error) {
+	return sql.Open("postgres", url)
+}
+
+func (crdb *CockroachDB) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
+	return cockroachdb.WithInstance(db, &cockroachdb.Config{})
+}
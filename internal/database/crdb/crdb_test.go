@@ -0,0 +1,57 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdb
+
+import (
+	"context"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCockroachDBProvider(t *testing.T) {
+	crdb := &CockroachDB{}
+	crdb.SetHandler("ns", &databasemocks.Callbacks{})
+	config := config.RootSection("unittest")
+	crdb.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	err := crdb.Init(context.Background(), config)
+	assert.NoError(t, err)
+	assert.True(t, crdb.Capabilities().Concurrency)
+	assert.False(t, crdb.Capabilities().FullTextSearch)
+	_, err = crdb.GetMigrationDriver(crdb.DB())
+	assert.Error(t, err)
+
+	assert.Equal(t, "crdb", crdb.Name())
+	assert.Equal(t, "crdb", crdb.MigrationsDir())
+	assert.Equal(t, "seq", crdb.SequenceColumn())
+	assert.Equal(t, sq.Dollar, crdb.Features().PlaceholderFormat)
+	assert.False(t, crdb.Features().MultiRowInsert)
+	assert.Equal(t, `UPSERT INTO locks (name) VALUES ('ns1');`, crdb.Features().AcquireLock("ns1"))
+	assert.Equal(t, `UPSERT INTO locks (name) VALUES ('it''s');`, crdb.Features().AcquireLock("it's"))
+
+	insert := sq.Insert("test").Columns("col1").Values("val1")
+	insert, query := crdb.ApplyInsertQueryCustomizations(insert, true)
+	sql, _, err := insert.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)  ON CONFLICT DO NOTHING RETURNING seq", sql)
+	assert.True(t, query)
+}
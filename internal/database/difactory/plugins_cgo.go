@@ -20,6 +20,8 @@
 package difactory
 
 import (
+	"github.com/hyperledger/firefly/internal/database/crdb"
+	"github.com/hyperledger/firefly/internal/database/mysql"
 	"github.com/hyperledger/firefly/internal/database/postgres"
 	"github.com/hyperledger/firefly/internal/database/sqlite3"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -27,5 +29,7 @@ import (
 
 var pluginsByName = map[string]func() database.Plugin{
 	(*postgres.Postgres)(nil).Name(): func() database.Plugin { return &postgres.Postgres{} },
+	(*mysql.MySQL)(nil).Name():       func() database.Plugin { return &mysql.MySQL{} },
+	(*crdb.CockroachDB)(nil).Name():  func() database.Plugin { return &crdb.CockroachDB{} },
 	(*sqlite3.SQLite3)(nil).Name():   func() database.Plugin { return &sqlite3.SQLite3{} }, // wrapper to the SQLite 3 C library
 }
@@ -20,10 +20,14 @@
 package difactory
 
 import (
+	"github.com/hyperledger/firefly/internal/database/crdb"
+	"github.com/hyperledger/firefly/internal/database/mysql"
 	"github.com/hyperledger/firefly/internal/database/postgres"
 	"github.com/hyperledger/firefly/pkg/database"
 )
 
 var pluginsByName = map[string]func() database.Plugin{
 	(*postgres.Postgres)(nil).Name(): func() database.Plugin { return &postgres.Postgres{} },
+	(*mysql.MySQL)(nil).Name():       func() database.Plugin { return &mysql.MySQL{} },
+	(*crdb.CockroachDB)(nil).Name():  func() database.Plugin { return &crdb.CockroachDB{} },
 }
@@ -0,0 +1,108 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	gomysql "github.com/go-sql-driver/mysql"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	migratemysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/dbsql"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type MySQL struct {
+	sqlcommon.SQLCommon
+}
+
+// Sequences are read from the LastInsertId, and conflicts are returned as errors for upserts to handle.
+// INSERT IGNORE is not used to return an empty result, as it also suppresses errors other than conflicts.
+var dialect = &sqlcommon.Dialect{
+	JSONContains:  "JSON_CONTAINS(%s, ?)",
+	IntegerDivide: "DIV",
+}
+
+func (my *MySQL) Init(ctx context.Context, config config.Section) error {
+	capabilities := &database.Capabilities{}
+	if config.GetInt(dbsql.SQLConfMaxConnections) > 1 {
+		capabilities.Concurrency = true
+	}
+	return my.SQLCommon.Init(ctx, my, dialect, config, capabilities)
+}
+
+func (my *MySQL) SetHandler(namespace string, handler database.Callbacks) {
+	my.SQLCommon.SetHandler(namespace, handler)
+}
+
+func (my *MySQL) Name() string {
+	return "mysql"
+}
+
+func (my *MySQL) SequenceColumn() string {
+	return "seq"
+}
+
+func (my *MySQL) MigrationsDir() string {
+	return my.Name()
+}
+
+func (my *MySQL) Features() dbsql.SQLFeatures {
+	features := dbsql.DefaultSQLProviderFeatures()
+	features.PlaceholderFormat = sq.Question
+	features.UseILIKE = false // Not supported
+	features.AcquireLock = func(lockName string) string {
+		// GET_LOCK is held by the session rather than the transaction, so we instead take the row lock
+		// on an entry in the locks table, which InnoDB holds until the transaction completes
+		return fmt.Sprintf(`INSERT INTO locks (name) VALUES ('%s') ON DUPLICATE KEY UPDATE name = name;`, strings.ReplaceAll(lockName, "'", "''"))
+	}
+	// Only the first sequence of a multi-row insert is available from the LastInsertId
+	features.MultiRowInsert = false
+	return features
+}
+
+func (my *MySQL) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, requestConflictEmptyResult bool) (sq.InsertBuilder, bool) {
+	return dialect.ApplyInsertQueryCustomizations(insert, my.SequenceColumn(), requestConflictEmptyResult)
+}
+
+func (my *MySQL) Open(url string) (*sql.DB, error) {
+	cfg, err := gomysql.ParseDSN(url)
+	if err != nil {
+		return nil, err
+	}
+	// Migrations are files of multiple statements
+	cfg.MultiStatements = true
+	// Table and column names that are reserved words in MySQL are double quoted by sqlcommon, per the SQL standard
+	if cfg.Params == nil {
+		cfg.Params = map[string]string{}
+	}
+	cfg.Params["sql_mode"] = "CONCAT(@@sql_mode, ',ANSI_QUOTES')"
+	// Cannot fail, as ParseDSN has already validated the configuration
+	connector, _ := gomysql.NewConnector(cfg)
+	return sql.OpenDB(&textConnector{Connector: connector}), nil
+}
+
+func (my *MySQL) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
+	return migratemysql.WithInstance(db, &migratemysql.Config{})
+}
@@ -0,0 +1,65 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMySQLProvider(t *testing.T) {
+	my := &MySQL{}
+	my.SetHandler("ns", &databasemocks.Callbacks{})
+	config := config.RootSection("unittest")
+	my.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "firefly:pass@tcp(127.0.0.1:1)/firefly?timeout=1s")
+	err := my.Init(context.Background(), config)
+	assert.NoError(t, err)
+	assert.True(t, my.Capabilities().Concurrency)
+	_, err = my.GetMigrationDriver(my.DB())
+	assert.Error(t, err)
+
+	assert.Equal(t, "mysql", my.Name())
+	assert.Equal(t, "mysql", my.MigrationsDir())
+	assert.Equal(t, "seq", my.SequenceColumn())
+	assert.Equal(t, sq.Question, my.Features().PlaceholderFormat)
+	assert.False(t, my.Features().MultiRowInsert)
+	assert.Equal(t, `INSERT INTO locks (name) VALUES ('ns1') ON DUPLICATE KEY UPDATE name = name;`, my.Features().AcquireLock("ns1"))
+	assert.Equal(t, `INSERT INTO locks (name) VALUES ('it''s') ON DUPLICATE KEY UPDATE name = name;`, my.Features().AcquireLock("it's"))
+
+	insert := sq.Insert("test").Columns("col1").Values("val1")
+	insert, query := my.ApplyInsertQueryCustomizations(insert, true)
+	sql, _, err := insert.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)", sql)
+	assert.False(t, query)
+}
+
+func TestMySQLBadURL(t *testing.T) {
+	my := &MySQL{}
+	config := config.RootSection("unittest")
+	my.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	err := my.Init(context.Background(), config)
+	assert.Regexp(t, "invalid DSN", err)
+}
@@ -0,0 +1,178 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+)
+
+// nullsOrderingRegex matches the NULLS FIRST/LAST sort modifiers generated by the common filter layer,
+// which MySQL does not support
+var nullsOrderingRegex = regexp.MustCompile(`([\w."]+)( DESC)? NULLS (FIRST|LAST)`)
+
+// textConnector wraps the MySQL driver so that text columns are returned as strings, as they are by the
+// PostgreSQL and SQLite drivers, rather than as []byte. Some of the types stored in text columns, such as
+// big integers, only restore from a string. The schema has no binary columns, so all []byte values are text.
+type textConnector struct {
+	driver.Connector
+}
+
+type textConn struct {
+	driver.Conn
+}
+
+type textStmt struct {
+	driver.Stmt
+}
+
+type textRows struct {
+	driver.Rows
+}
+
+func (tc *textConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := tc.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &textConn{Conn: conn}, nil
+}
+
+func (c *textConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	rows, err := queryer.QueryContext(ctx, rewriteNullsOrdering(query), args)
+	if err != nil {
+		return nil, err
+	}
+	return &textRows{Rows: rows}, nil
+}
+
+func (c *textConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	query = rewriteNullsOrdering(query)
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &textStmt{Stmt: stmt}, nil
+}
+
+func (c *textConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *textConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin() //nolint:staticcheck
+}
+
+func (c *textConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *textConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *textConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *textConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (s *textStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(namedValuesToValues(args)) //nolint:staticcheck
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &textRows{Rows: rows}, nil
+}
+
+func (s *textStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedValuesToValues(args)) //nolint:staticcheck
+}
+
+func (r *textRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, v := range dest {
+		if b, ok := v.([]byte); ok {
+			dest[i] = string(b)
+		}
+	}
+	return nil
+}
+
+// rewriteNullsOrdering replaces "col [DESC] NULLS FIRST|LAST" with an equivalent MySQL ordering,
+// sorting on "col IS NULL" first. MySQL otherwise sorts NULLs as the lowest value.
+func rewriteNullsOrdering(query string) string {
+	return nullsOrderingRegex.ReplaceAllStringFunc(query, func(match string) string {
+		m := nullsOrderingRegex.FindStringSubmatch(match)
+		nullsDirection := "ASC"
+		if m[3] == "FIRST" {
+			nullsDirection = "DESC"
+		}
+		return m[1] + " IS NULL " + nullsDirection + ", " + m[1] + m[2]
+	})
+}
+
+func namedValuesToValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
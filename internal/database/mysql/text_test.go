@@ -0,0 +1,221 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnector struct {
+	conn driver.Conn
+	err  error
+}
+
+func (tc *testConnector) Connect(ctx context.Context) (driver.Conn, error) { return tc.conn, tc.err }
+func (tc *testConnector) Driver() driver.Driver                            { return nil }
+
+// basicConn implements only the required driver.Conn functions
+type basicConn struct {
+	stmt driver.Stmt
+	err  error
+}
+
+func (c *basicConn) Prepare(query string) (driver.Stmt, error) { return c.stmt, c.err }
+func (c *basicConn) Close() error                              { return nil }
+func (c *basicConn) Begin() (driver.Tx, error)                 { return nil, c.err }
+
+// fullConn implements all the optional driver.Conn interfaces
+type fullConn struct {
+	basicConn
+	rows driver.Rows
+}
+
+func (c *fullConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.rows, c.err
+}
+func (c *fullConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.stmt, c.err
+}
+func (c *fullConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), c.err
+}
+func (c *fullConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return nil, c.err
+}
+func (c *fullConn) Ping(ctx context.Context) error              { return c.err }
+func (c *fullConn) ResetSession(ctx context.Context) error      { return c.err }
+func (c *fullConn) IsValid() bool                               { return false }
+func (c *fullConn) CheckNamedValue(nv *driver.NamedValue) error { return c.err }
+
+// basicStmt implements only the required driver.Stmt functions
+type basicStmt struct {
+	rows driver.Rows
+	err  error
+	args []driver.Value
+}
+
+func (s *basicStmt) Close() error  { return nil }
+func (s *basicStmt) NumInput() int { return -1 }
+func (s *basicStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.args = args
+	return driver.RowsAffected(1), s.err
+}
+func (s *basicStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.args = args
+	return s.rows, s.err
+}
+
+// fullStmt implements the context aware driver.Stmt interfaces
+type fullStmt struct {
+	basicStmt
+}
+
+func (s *fullStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.rows, s.err
+}
+func (s *fullStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), s.err
+}
+
+type testRows struct {
+	values [][]driver.Value
+}
+
+func (r *testRows) Columns() []string { return []string{"col1", "col2"} }
+func (r *testRows) Close() error      { return nil }
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newTestRows() *testRows {
+	return &testRows{values: [][]driver.Value{{[]byte("text"), int64(12345)}}}
+}
+
+func assertTextRows(t *testing.T, rows driver.Rows) {
+	dest := make([]driver.Value, 2)
+	err := rows.Next(dest)
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{"text", int64(12345)}, dest)
+	err = rows.Next(dest)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestTextConnectorConnect(t *testing.T) {
+	tc := &textConnector{Connector: &testConnector{conn: &basicConn{}}}
+	conn, err := tc.Connect(context.Background())
+	assert.NoError(t, err)
+	assert.IsType(t, &textConn{}, conn)
+
+	tc = &textConnector{Connector: &testConnector{err: fmt.Errorf("pop")}}
+	_, err = tc.Connect(context.Background())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestTextConnFull(t *testing.T) {
+	ctx := context.Background()
+	stmt := &fullStmt{basicStmt{rows: newTestRows()}}
+	c := &textConn{Conn: &fullConn{basicConn: basicConn{stmt: stmt}, rows: newTestRows()}}
+
+	rows, err := c.QueryContext(ctx, "SELECT", nil)
+	assert.NoError(t, err)
+	assertTextRows(t, rows)
+
+	s, err := c.PrepareContext(ctx, "SELECT")
+	assert.NoError(t, err)
+	rows, err = s.(driver.StmtQueryContext).QueryContext(ctx, nil)
+	assert.NoError(t, err)
+	assertTextRows(t, rows)
+	_, err = s.(driver.StmtExecContext).ExecContext(ctx, nil)
+	assert.NoError(t, err)
+
+	_, err = c.ExecContext(ctx, "INSERT", nil)
+	assert.NoError(t, err)
+	_, err = c.BeginTx(ctx, driver.TxOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Ping(ctx))
+	assert.NoError(t, c.ResetSession(ctx))
+	assert.False(t, c.IsValid())
+	assert.NoError(t, c.CheckNamedValue(&driver.NamedValue{}))
+}
+
+func TestTextConnBasic(t *testing.T) {
+	ctx := context.Background()
+	stmt := &basicStmt{rows: newTestRows()}
+	c := &textConn{Conn: &basicConn{stmt: stmt}}
+
+	_, err := c.QueryContext(ctx, "SELECT", nil)
+	assert.Equal(t, driver.ErrSkip, err)
+
+	s, err := c.PrepareContext(ctx, "SELECT")
+	assert.NoError(t, err)
+	args := []driver.NamedValue{{Ordinal: 1, Value: "val1"}}
+	rows, err := s.(driver.StmtQueryContext).QueryContext(ctx, args)
+	assert.NoError(t, err)
+	assertTextRows(t, rows)
+	assert.Equal(t, []driver.Value{"val1"}, stmt.args)
+	_, err = s.(driver.StmtExecContext).ExecContext(ctx, args)
+	assert.NoError(t, err)
+
+	_, err = c.ExecContext(ctx, "INSERT", nil)
+	assert.Equal(t, driver.ErrSkip, err)
+	_, err = c.BeginTx(ctx, driver.TxOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Ping(ctx))
+	assert.NoError(t, c.ResetSession(ctx))
+	assert.True(t, c.IsValid())
+	assert.Equal(t, driver.ErrSkip, c.CheckNamedValue(&driver.NamedValue{}))
+}
+
+func TestTextConnErrors(t *testing.T) {
+	ctx := context.Background()
+	stmt := &fullStmt{basicStmt{err: fmt.Errorf("pop")}}
+	c := &textConn{Conn: &fullConn{basicConn: basicConn{stmt: stmt, err: fmt.Errorf("pop")}}}
+
+	_, err := c.QueryContext(ctx, "SELECT", nil)
+	assert.Regexp(t, "pop", err)
+	_, err = c.PrepareContext(ctx, "SELECT")
+	assert.Regexp(t, "pop", err)
+
+	s := &textStmt{Stmt: stmt}
+	_, err = s.QueryContext(ctx, nil)
+	assert.Regexp(t, "pop", err)
+
+	rows := &textRows{Rows: &testRows{}}
+	err = rows.Next(make([]driver.Value, 2))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRewriteNullsOrdering(t *testing.T) {
+	assert.Equal(t,
+		"SELECT id FROM messages ORDER BY confirmed IS NULL DESC, confirmed DESC, created",
+		rewriteNullsOrdering("SELECT id FROM messages ORDER BY confirmed DESC NULLS FIRST, created"))
+	assert.Equal(t,
+		"SELECT id FROM messages ORDER BY m.confirmed IS NULL ASC, m.confirmed",
+		rewriteNullsOrdering("SELECT id FROM messages ORDER BY m.confirmed NULLS LAST"))
+	assert.Equal(t, "SELECT 1", rewriteNullsOrdering("SELECT 1"))
+}
@@ -39,12 +39,22 @@ type Postgres struct {
 	sqlcommon.SQLCommon
 }
 
+// The JSON and full-text conditions match the expressions of the GIN indexes on the data values
+var dialect = &sqlcommon.Dialect{
+	ReturningSequence: true,
+	ConflictDoNothing: "ON CONFLICT DO NOTHING",
+	JSONContains:      "(%s::jsonb) @> ?::jsonb",
+	FullTextSearch:    `jsonb_to_tsvector('simple', (%s::jsonb), '["string", "numeric"]') @@ plainto_tsquery('simple', ?)`,
+}
+
 func (psql *Postgres) Init(ctx context.Context, config config.Section) error {
-	capabilities := &database.Capabilities{}
+	capabilities := &database.Capabilities{
+		FullTextSearch: true,
+	}
 	if config.GetInt(dbsql.SQLConfMaxConnections) > 1 {
 		capabilities.Concurrency = true
 	}
-	return psql.SQLCommon.Init(ctx, psql, config, capabilities)
+	return psql.SQLCommon.Init(ctx, psql, dialect, config, capabilities)
 }
 
 func (psql *Postgres) SetHandler(namespace string, handler database.Callbacks) {
@@ -84,12 +94,7 @@ func (psql *Postgres) Features() dbsql.SQLFeatures {
 }
 
 func (psql *Postgres) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, requestConflictEmptyResult bool) (sq.InsertBuilder, bool) {
-	suffix := " RETURNING seq"
-	if requestConflictEmptyResult {
-		// Caller wants us to return an empty result set on insert conflict, rather than an error
-		suffix = fmt.Sprintf(" ON CONFLICT DO NOTHING%s", suffix)
-	}
-	return insert.Suffix(suffix), true
+	return dialect.ApplyInsertQueryCustomizations(insert, psql.SequenceColumn(), requestConflictEmptyResult)
 }
 
 func (psql *Postgres) Open(url string) (*sql.DB, error) {
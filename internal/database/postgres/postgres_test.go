@@ -35,6 +35,7 @@ func TestPostgresProvider(t *testing.T) {
 	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
 	err := psql.Init(context.Background(), config)
 	assert.NoError(t, err)
+	assert.True(t, psql.Capabilities().FullTextSearch)
 	_, err = psql.GetMigrationDriver(psql.DB())
 	assert.Error(t, err)
 
@@ -19,6 +19,8 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly-common/pkg/dbsql"
@@ -40,22 +42,44 @@ var (
 		"protocol_id",
 		"listener_id",
 		"output",
+		"output_truncated",
 		"info",
 		"timestamp",
 		"tx_type",
 		"tx_id",
 		"tx_blockchain_id",
 	}
+
+	// The block number is only stored to allow filtering on block ranges - it is derived from the protocol ID
+	blockchainEventInsertColumns = append(append([]string{}, blockchainEventColumns...), "block_number")
+
 	blockchainEventFilterFieldMap = map[string]string{
 		"protocolid":      "protocol_id",
+		"blocknumber":     "block_number",
 		"listener":        "listener_id",
 		"tx.type":         "tx_type",
 		"tx.id":           "tx_id",
 		"tx.blockchainid": "tx_blockchain_id",
+		"outputtruncated": "output_truncated",
 	}
 )
 
 const blockchaineventsTable = "blockchainevents"
+const blockchaineventOutputsTable = "blockchainevent_outputs"
+
+// protocolIDBlockNumber returns the block number from the zero-padded prefix that the protocol ID
+// of events from each blockchain plugin begins with, or nil if the protocol ID has no such prefix
+func protocolIDBlockNumber(protocolID string) *int64 {
+	prefix, _, found := strings.Cut(protocolID, "/")
+	if !found {
+		return nil
+	}
+	blockNumber, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &blockNumber
+}
 
 func (s *SQLCommon) setBlockchainEventInsertValues(query sq.InsertBuilder, event *core.BlockchainEvent) sq.InsertBuilder {
 	return query.Values(
@@ -66,17 +90,19 @@ func (s *SQLCommon) setBlockchainEventInsertValues(query sq.InsertBuilder, event
 		event.ProtocolID,
 		event.Listener,
 		event.Output,
+		event.OutputTruncated,
 		event.Info,
 		event.Timestamp,
 		event.TX.Type,
 		event.TX.ID,
 		event.TX.BlockchainID,
+		protocolIDBlockNumber(event.ProtocolID),
 	)
 }
 
 func (s *SQLCommon) attemptBlockchainEventInsert(ctx context.Context, tx *dbsql.TXWrapper, event *core.BlockchainEvent, requestConflictEmptyResult bool) (err error) {
 	_, err = s.InsertTxExt(ctx, blockchaineventsTable, tx,
-		s.setBlockchainEventInsertValues(sq.Insert(blockchaineventsTable).Columns(blockchainEventColumns...), event),
+		s.setBlockchainEventInsertValues(sq.Insert(blockchaineventsTable).Columns(blockchainEventInsertColumns...), event),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionBlockchainEvents, core.ChangeEventTypeCreated, event.Namespace, event.ID)
 		}, requestConflictEmptyResult)
@@ -91,7 +117,7 @@ func (s *SQLCommon) InsertBlockchainEvents(ctx context.Context, events []*core.B
 	}
 	defer s.RollbackTx(ctx, tx, autoCommit)
 	if s.Features().MultiRowInsert {
-		query := sq.Insert(blockchaineventsTable).Columns(blockchainEventColumns...)
+		query := sq.Insert(blockchaineventsTable).Columns(blockchainEventInsertColumns...)
 		for _, event := range events {
 			query = s.setBlockchainEventInsertValues(query, event)
 		}
@@ -156,6 +182,7 @@ func (s *SQLCommon) blockchainEventResult(ctx context.Context, row *sql.Rows) (*
 		&event.ProtocolID,
 		&event.Listener,
 		&event.Output,
+		&event.OutputTruncated,
 		&event.Info,
 		&event.Timestamp,
 		&event.TX.Type,
@@ -230,3 +257,74 @@ func (s *SQLCommon) GetBlockchainEvents(ctx context.Context, namespace string, f
 
 	return events, s.QueryRes(ctx, blockchaineventsTable, tx, fop, nil, fi), err
 }
+
+func (s *SQLCommon) GetBlockchainEventListenerTimestamps(ctx context.Context, namespace string, listenerIDs []*fftypes.UUID) (map[fftypes.UUID]*fftypes.FFTime, error) {
+	timestamps := make(map[fftypes.UUID]*fftypes.FFTime, len(listenerIDs))
+	if len(listenerIDs) == 0 {
+		return timestamps, nil
+	}
+
+	rows, _, err := s.Query(ctx, blockchaineventsTable,
+		sq.Select("listener_id", "MAX(timestamp) AS timestamp").
+			From(blockchaineventsTable).
+			Where(sq.Eq{"namespace": namespace, "listener_id": listenerIDs}).
+			GroupBy("listener_id"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var listenerID fftypes.UUID
+		var timestamp *fftypes.FFTime
+		if err := rows.Scan(&listenerID, &timestamp); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, blockchaineventsTable)
+		}
+		timestamps[listenerID] = timestamp
+	}
+	return timestamps, nil
+}
+
+func (s *SQLCommon) InsertBlockchainEventOutput(ctx context.Context, namespace string, id *fftypes.UUID, output fftypes.JSONObject) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	_, err = s.InsertTx(ctx, blockchaineventOutputsTable, tx,
+		sq.Insert(blockchaineventOutputsTable).
+			Columns("id", "namespace", "output").
+			Values(id, namespace, output),
+		nil, // no change events for blockchain event outputs
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) GetBlockchainEventOutput(ctx context.Context, namespace string, id *fftypes.UUID) (fftypes.JSONObject, error) {
+	rows, _, err := s.Query(ctx, blockchaineventOutputsTable,
+		sq.Select("output").
+			From(blockchaineventOutputsTable).
+			Where(sq.Eq{"id": id, "namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Blockchain event output '%s' not found", id)
+		return nil, nil
+	}
+
+	var output fftypes.JSONObject
+	if err := rows.Scan(&output); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, blockchaineventOutputsTable)
+	}
+	return output, nil
+}
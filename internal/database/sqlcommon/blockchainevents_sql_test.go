@@ -21,12 +21,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestBlockchainEventsE2EWithDB(t *testing.T) {
@@ -116,6 +118,62 @@ func TestBlockchainEventsE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, event3.ID, existing.ID)
 
+	// Store and retrieve the full output of a truncated event
+	fullOutput := fftypes.JSONObject{"value": 1, "data": "0xfeedbeef"}
+	err = s.InsertBlockchainEventOutput(ctx, "ns", event3.ID, fullOutput)
+	assert.NoError(t, err)
+	outputRead, err := s.GetBlockchainEventOutput(ctx, "ns", event3.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, fullOutput.String(), outputRead.String())
+	outputRead, err = s.GetBlockchainEventOutput(ctx, "ns", event4.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, outputRead)
+
+}
+
+func TestBlockchainEventsBlockRangeWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	listener := fftypes.NewUUID()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBlockchainEvents, core.ChangeEventTypeCreated, "ns", mock.Anything).Return()
+	for _, protocolID := range []string{
+		"000000000012/000000/000001",
+		"000000000011/000002/000004",
+		"000000000011/000001/000003",
+		"000000000010/000000/000000",
+		"not-a-block",
+	} {
+		err := s.InsertBlockchainEvents(ctx, []*core.BlockchainEvent{{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns",
+			Listener:   listener,
+			ProtocolID: protocolID,
+			Timestamp:  fftypes.Now(),
+		}})
+		assert.NoError(t, err)
+	}
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("listener", listener),
+		fb.Gte("blocknumber", 11),
+		fb.Lte("blocknumber", 12),
+	).Sort("blocknumber", "protocolid")
+	events, _, err := s.GetBlockchainEvents(ctx, "ns", filter)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, "000000000011/000001/000003", events[0].ProtocolID)
+	assert.Equal(t, "000000000011/000002/000004", events[1].ProtocolID)
+	assert.Equal(t, "000000000012/000000/000001", events[2].ProtocolID)
+}
+
+func TestProtocolIDBlockNumber(t *testing.T) {
+	assert.Equal(t, int64(12), *protocolIDBlockNumber("000000000012/000000/000001"))
+	assert.Equal(t, int64(5), *protocolIDBlockNumber("000000000005/0x1234"))
+	assert.Nil(t, protocolIDBlockNumber("tx1"))
+	assert.Nil(t, protocolIDBlockNumber("abc/def"))
 }
 
 func TestInsertBlockchainEventFailBegin(t *testing.T) {
@@ -257,3 +315,93 @@ func TestGetBlockchainEventsScanFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetBlockchainEventListenerTimestampsWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	listener1 := fftypes.NewUUID()
+	listener2 := fftypes.NewUUID()
+	listener3 := fftypes.NewUUID()
+	earlier := fftypes.FFTime(time.Now().Add(-1 * time.Hour))
+	later := fftypes.Now()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBlockchainEvents, core.ChangeEventTypeCreated, "ns", mock.Anything).Return()
+	for i, e := range []struct {
+		listener  *fftypes.UUID
+		timestamp *fftypes.FFTime
+	}{
+		{listener1, later},
+		{listener1, &earlier},
+		{listener2, &earlier},
+	} {
+		err := s.InsertBlockchainEvents(ctx, []*core.BlockchainEvent{{
+			ID:         fftypes.NewUUID(),
+			Namespace:  "ns",
+			Listener:   e.listener,
+			ProtocolID: fmt.Sprintf("000000000010/000000/%.6d", i),
+			Timestamp:  e.timestamp,
+		}})
+		assert.NoError(t, err)
+	}
+
+	timestamps, err := s.GetBlockchainEventListenerTimestamps(ctx, "ns", []*fftypes.UUID{listener1, listener2, listener3})
+	assert.NoError(t, err)
+	assert.Len(t, timestamps, 2)
+	assert.Equal(t, later.UnixNano(), timestamps[*listener1].UnixNano())
+	assert.Equal(t, earlier.UnixNano(), timestamps[*listener2].UnixNano())
+
+	timestamps, err = s.GetBlockchainEventListenerTimestamps(ctx, "ns", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, timestamps)
+}
+
+func TestGetBlockchainEventListenerTimestampsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBlockchainEventListenerTimestamps(context.Background(), "ns", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlockchainEventListenerTimestampsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"listener_id"}).AddRow("only one"))
+	_, err := s.GetBlockchainEventListenerTimestamps(context.Background(), "ns", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertBlockchainEventOutputFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertBlockchainEventOutput(context.Background(), "ns", fftypes.NewUUID(), fftypes.JSONObject{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertBlockchainEventOutputFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertBlockchainEventOutput(context.Background(), "ns", fftypes.NewUUID(), fftypes.JSONObject{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlockchainEventOutputSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBlockchainEventOutput(context.Background(), "ns", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlockchainEventOutputScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"output"}).AddRow("!json"))
+	_, err := s.GetBlockchainEventOutput(context.Background(), "ns", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -19,10 +19,14 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -30,6 +34,28 @@ import (
 	"github.com/hyperledger/firefly/pkg/database"
 )
 
+var (
+	chartRollupColumns = []string{
+		"namespace",
+		"collection",
+		"group_by",
+		"group_value",
+		"bucket",
+		"record_count",
+	}
+)
+
+const chartRollupsTable = "chartrollups"
+
+var chartQueryFactories = map[database.CollectionName]*ffapi.QueryFields{
+	database.CollectionName(database.CollectionMessages):         database.MessageQueryFactory,
+	database.CollectionName(database.CollectionTransactions):     database.TransactionQueryFactory,
+	database.CollectionName(database.CollectionOperations):       database.OperationQueryFactory,
+	database.CollectionName(database.CollectionEvents):           database.EventQueryFactory,
+	database.CollectionName(database.CollectionTokenTransfers):   database.TokenTransferQueryFactory,
+	database.CollectionName(database.CollectionBlockchainEvents): database.BlockchainEventQueryFactory,
+}
+
 func (s *SQLCommon) getTableNameFromCollection(ctx context.Context, collection database.CollectionName) (tableName string, fieldMap map[string]string, err error) {
 	switch collection {
 	case database.CollectionName(database.CollectionMessages):
@@ -49,6 +75,32 @@ func (s *SQLCommon) getTableNameFromCollection(ctx context.Context, collection d
 	}
 }
 
+func chartTimestampColumn(tableName string) string {
+	if tableName == "blockchainevents" {
+		// Blockchain Events have a `timestamp` column name
+		return "timestamp"
+	}
+	return "created"
+}
+
+// chartGroupByColumn returns the column for a field of a collection that counts can be grouped by. Only string
+// and UUID fields can be grouped on, so the values are a small set of categories, rather than unique numbers or times
+func chartGroupByColumn(ctx context.Context, collection database.CollectionName, fieldMap map[string]string, groupBy string) (string, error) {
+	var field ffapi.Field
+	if qf := chartQueryFactories[collection]; qf != nil {
+		field = (*qf)[groupBy]
+	}
+	switch field.(type) {
+	case *ffapi.StringField, *ffapi.UUIDField:
+	default:
+		return "", i18n.NewError(ctx, coremsgs.MsgInvalidChartGroupBy, groupBy, collection)
+	}
+	if column, ok := fieldMap[groupBy]; ok {
+		return column, nil
+	}
+	return groupBy, nil
+}
+
 func (s *SQLCommon) getSelectStatements(ns string, tableName string, intervals []core.ChartHistogramInterval, timestampKey string, sql sq.SelectBuilder) (queries []sq.SelectBuilder) {
 	for _, interval := range intervals {
 		queries = append(queries, sql.
@@ -109,11 +161,7 @@ func (s *SQLCommon) GetChartHistogram(ctx context.Context, ns string, intervals
 	}
 
 	// Timestamp column name
-	timestampKey := "created"
-	if tableName == "blockchainevents" {
-		// Blockchain Events have a `timestamp` column name
-		timestampKey = "timestamp"
-	}
+	timestampKey := chartTimestampColumn(tableName)
 
 	// Number of columns to read.
 	// Some tables don't have a `type` field and therefore
@@ -164,3 +212,186 @@ func (s *SQLCommon) GetChartHistogram(ctx context.Context, ns string, intervals
 
 	return histogramList, nil
 }
+
+// GetChartCounts counts records in the database by grouping on the interval each timestamp falls in, so that only
+// the counts are returned however many records are in the range
+func (s *SQLCommon) GetChartCounts(ctx context.Context, ns string, query *database.ChartCountQuery) ([]*core.ChartCount, error) {
+	tableName, fieldMap, err := s.getTableNameFromCollection(ctx, query.Collection)
+	if err != nil {
+		return nil, err
+	}
+	if query.Interval <= 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidChartInterval, query.Interval)
+	}
+	timestampKey := chartTimestampColumn(tableName)
+
+	var origin int64
+	where := sq.And{sq.Eq{"namespace": ns}}
+	if query.StartTime != nil {
+		origin = query.StartTime.UnixNano()
+		where = append(where, sq.GtOrEq{timestampKey: query.StartTime})
+	}
+	if query.EndTime != nil {
+		where = append(where, sq.Lt{timestampKey: query.EndTime})
+	}
+	if query.AfterSequence > 0 {
+		where = append(where, sq.Gt{s.SequenceColumn(): query.AfterSequence})
+	}
+	if query.MaxSequence > 0 {
+		where = append(where, sq.LtOrEq{s.SequenceColumn(): query.MaxSequence})
+	}
+
+	// Both the values are integers, so are safe to build into the expression
+	bucketExpr := s.dialect.integerDivide(fmt.Sprintf("(%s - %d)", timestampKey, origin), fmt.Sprintf("%d", query.Interval.Nanoseconds()))
+	cols := []string{bucketExpr, "COUNT(*)"}
+	groupBy := []string{bucketExpr}
+	if query.GroupBy != "" {
+		groupByColumn, err := chartGroupByColumn(ctx, query.Collection, fieldMap, query.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, groupByColumn)
+		groupBy = append(groupBy, groupByColumn)
+	}
+
+	rows, _, err := s.Query(ctx, tableName, sq.Select(cols...).From(tableName).Where(where).GroupBy(groupBy...))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*core.ChartCount{}
+	for rows.Next() {
+		var bucket int64
+		var group sql.NullString
+		count := &core.ChartCount{}
+		dest := []interface{}{&bucket, &count.Count}
+		if query.GroupBy != "" {
+			dest = append(dest, &group)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tableName)
+		}
+		count.Timestamp = chartTimestamp(origin + bucket*query.Interval.Nanoseconds())
+		count.Group = group.String
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// GetChartNextSequence returns the sequence of the last record in the next page of limit records after a sequence,
+// or of the last record in the collection if the limit is 0
+func (s *SQLCommon) GetChartNextSequence(ctx context.Context, ns string, collection database.CollectionName, after int64, limit int) (int64, error) {
+	tableName, _, err := s.getTableNameFromCollection(ctx, collection)
+	if err != nil {
+		return -1, err
+	}
+	query := sq.Select(s.SequenceColumn()).
+		From(tableName).
+		Where(sq.And{
+			sq.Eq{"namespace": ns},
+			sq.Gt{s.SequenceColumn(): after},
+		})
+	if limit > 0 {
+		query = query.OrderBy(s.SequenceColumn()).Limit(uint64(limit))
+	} else {
+		query = query.OrderBy(s.SequenceColumn() + " DESC").Limit(1)
+	}
+	rows, _, err := s.Query(ctx, tableName, query)
+	if err != nil {
+		return -1, err
+	}
+	defer rows.Close()
+
+	last := after
+	for rows.Next() {
+		if err := rows.Scan(&last); err != nil {
+			return -1, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tableName)
+		}
+	}
+	return last, nil
+}
+
+// AddChartRollups increments the count of each existing rollup, or inserts it if it does not exist
+func (s *SQLCommon) AddChartRollups(ctx context.Context, ns string, rollups []*core.ChartRollup) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	for _, rollup := range rollups {
+		updated, err := s.UpdateTx(ctx, chartRollupsTable, tx,
+			sq.Update(chartRollupsTable).
+				Set("record_count", sq.Expr("record_count + ?", rollup.Count)).
+				Where(sq.Eq{
+					"namespace":   ns,
+					"collection":  rollup.Collection,
+					"group_by":    rollup.GroupBy,
+					"group_value": rollup.Group,
+					"bucket":      rollup.Timestamp,
+				}),
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+		if updated > 0 {
+			continue
+		}
+		if _, err = s.InsertTx(ctx, chartRollupsTable, tx,
+			sq.Insert(chartRollupsTable).
+				Columns(chartRollupColumns...).
+				Values(
+					ns,
+					rollup.Collection,
+					rollup.GroupBy,
+					rollup.Group,
+					rollup.Timestamp,
+					rollup.Count,
+				),
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) GetChartRollups(ctx context.Context, ns string, collection database.CollectionName, groupBy string, startTime, endTime *fftypes.FFTime) ([]*core.ChartCount, error) {
+	rows, _, err := s.Query(ctx, chartRollupsTable,
+		sq.Select("bucket", "group_value", "record_count").
+			From(chartRollupsTable).
+			Where(sq.And{
+				sq.Eq{
+					"namespace":  ns,
+					"collection": collection,
+					"group_by":   groupBy,
+				},
+				sq.GtOrEq{"bucket": startTime},
+				sq.Lt{"bucket": endTime},
+			}).
+			OrderBy("bucket"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*core.ChartCount{}
+	for rows.Next() {
+		var bucket int64
+		count := &core.ChartCount{}
+		if err := rows.Scan(&bucket, &count.Group, &count.Count); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, chartRollupsTable)
+		}
+		count.Timestamp = chartTimestamp(bucket)
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+func chartTimestamp(unixNanos int64) *fftypes.FFTime {
+	t := fftypes.FFTime(time.Unix(0, unixNanos))
+	return &t
+}
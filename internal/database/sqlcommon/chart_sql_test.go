@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hyperledger/firefly-common/pkg/config"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var (
@@ -252,3 +254,235 @@ func TestGetChartHistogramSuccessNoRowsOnlyTimestamp(t *testing.T) {
 	assert.Equal(t, emptyHistogramResult, histogram)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestChartCountsE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	hour := int64(time.Hour)
+	start := (time.Now().UnixNano()/hour - 2) * hour
+	ops := []*core.Operation{
+		{Type: core.OpTypeBlockchainPinBatch, Created: chartTimestamp(start)},
+		{Type: core.OpTypeBlockchainPinBatch, Created: chartTimestamp(start + hour/2)},
+		{Type: core.OpTypeDataExchangeSendBatch, Created: chartTimestamp(start + hour + 1)},
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+	for _, op := range ops {
+		op.ID = fftypes.NewUUID()
+		op.Namespace = "ns1"
+		op.Transaction = fftypes.NewUUID()
+		op.Status = core.OpStatusSucceeded
+		op.Updated = op.Created
+		err := s.InsertOperation(ctx, op)
+		assert.NoError(t, err)
+	}
+
+	// Count in hour buckets from the start
+	counts, err := s.GetChartCounts(ctx, "ns1", &database.ChartCountQuery{
+		Collection: database.CollectionName(database.CollectionOperations),
+		StartTime:  chartTimestamp(start),
+		EndTime:    chartTimestamp(start + 2*hour),
+		Interval:   time.Hour,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	assert.Equal(t, start, counts[0].Timestamp.UnixNano())
+	assert.Equal(t, int64(2), counts[0].Count)
+	assert.Equal(t, start+hour, counts[1].Timestamp.UnixNano())
+	assert.Equal(t, int64(1), counts[1].Count)
+
+	// Count by type from the epoch, after the first record
+	seq, err := s.GetChartNextSequence(ctx, "ns1", database.CollectionName(database.CollectionOperations), 0, 1)
+	assert.NoError(t, err)
+	last, err := s.GetChartNextSequence(ctx, "ns1", database.CollectionName(database.CollectionOperations), seq, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, seq+2, last)
+	head, err := s.GetChartNextSequence(ctx, "ns1", database.CollectionName(database.CollectionOperations), 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, last, head)
+	counts, err = s.GetChartCounts(ctx, "ns1", &database.ChartCountQuery{
+		Collection:    database.CollectionName(database.CollectionOperations),
+		GroupBy:       "type",
+		Interval:      time.Hour,
+		AfterSequence: seq,
+		MaxSequence:   last,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	byType := map[string]int64{}
+	for _, c := range counts {
+		byType[c.Group] += c.Count
+	}
+	assert.Equal(t, map[string]int64{
+		string(core.OpTypeBlockchainPinBatch):    1,
+		string(core.OpTypeDataExchangeSendBatch): 1,
+	}, byType)
+
+	// Add the rollups twice, to check they are incremented
+	rollups := []*core.ChartRollup{
+		{Collection: "operations", GroupBy: "type", ChartCount: core.ChartCount{Timestamp: chartTimestamp(start), Group: "typeA", Count: 2}},
+		{Collection: "operations", GroupBy: "type", ChartCount: core.ChartCount{Timestamp: chartTimestamp(start + hour), Group: "typeA", Count: 1}},
+	}
+	err = s.AddChartRollups(ctx, "ns1", rollups)
+	assert.NoError(t, err)
+	err = s.AddChartRollups(ctx, "ns1", rollups[0:1])
+	assert.NoError(t, err)
+	counts, err = s.GetChartRollups(ctx, "ns1", database.CollectionName(database.CollectionOperations), "type", chartTimestamp(start), chartTimestamp(start+2*hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ChartCount{
+		{Timestamp: chartTimestamp(start), Group: "typeA", Count: 4},
+		{Timestamp: chartTimestamp(start + hour), Group: "typeA", Count: 1},
+	}, counts)
+}
+
+func TestGetChartCountsInvalidCollection(t *testing.T) {
+	s, _ := newMockProvider().init()
+	_, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "wrong",
+		Interval:   time.Hour,
+	})
+	assert.Regexp(t, "FF10301", err)
+}
+
+func TestGetChartCountsInvalidInterval(t *testing.T) {
+	s, _ := newMockProvider().init()
+	_, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "messages",
+	})
+	assert.Regexp(t, "FF10630", err)
+}
+
+func TestGetChartCountsInvalidGroupBy(t *testing.T) {
+	s, _ := newMockProvider().init()
+	_, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "messages",
+		Interval:   time.Hour,
+		GroupBy:    "created",
+	})
+	assert.Regexp(t, "FF10629", err)
+	_, err = s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "messages",
+		Interval:   time.Hour,
+		GroupBy:    "type; DROP TABLE messages",
+	})
+	assert.Regexp(t, "FF10629", err)
+}
+
+func TestGetChartCountsGroupByMappedField(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*tx_type.*GROUP BY .*tx_type").WillReturnRows(sqlmock.NewRows([]string{"bucket", "count", "tx_type"}).
+		AddRow(0, 5, nil))
+	counts, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "blockchainevents",
+		Interval:   time.Hour,
+		GroupBy:    "tx.type",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ChartCount{{Timestamp: chartTimestamp(0), Count: 5}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartCountsIntegerDivide(t *testing.T) {
+	mp := newMockProvider()
+	mp.dialect = &Dialect{IntegerDivide: "DIV"}
+	s, mock := mp.init()
+	mock.ExpectQuery(`SELECT \(created - 0\) DIV 3600000000000, COUNT\(\*\) FROM messages .*GROUP BY \(created - 0\) DIV 3600000000000`).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "count"}).AddRow(1, 5))
+	counts, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "messages",
+		Interval:   time.Hour,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ChartCount{{Timestamp: chartTimestamp(int64(time.Hour)), Count: 5}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartCountsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "messages",
+		Interval:   time.Hour,
+	})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartCountsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"bucket"}).AddRow("bad"))
+	_, err := s.GetChartCounts(context.Background(), "ns1", &database.ChartCountQuery{
+		Collection: "messages",
+		Interval:   time.Hour,
+	})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartNextSequenceInvalidCollection(t *testing.T) {
+	s, _ := newMockProvider().init()
+	_, err := s.GetChartNextSequence(context.Background(), "ns1", "wrong", 0, 10)
+	assert.Regexp(t, "FF10301", err)
+}
+
+func TestGetChartNextSequenceQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetChartNextSequence(context.Background(), "ns1", "messages", 0, 10)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartNextSequenceScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow("bad"))
+	_, err := s.GetChartNextSequence(context.Background(), "ns1", "messages", 0, 10)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddChartRollupsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.AddChartRollups(context.Background(), "ns1", []*core.ChartRollup{{}})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddChartRollupsFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.AddChartRollups(context.Background(), "ns1", []*core.ChartRollup{{}})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddChartRollupsFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.AddChartRollups(context.Background(), "ns1", []*core.ChartRollup{{}})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartRollupsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetChartRollups(context.Background(), "ns1", "messages", "", fftypes.Now(), fftypes.Now())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChartRollupsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"bucket"}).AddRow("bad"))
+	_, err := s.GetChartRollups(context.Background(), "ns1", "messages", "", fftypes.Now(), fftypes.Now())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
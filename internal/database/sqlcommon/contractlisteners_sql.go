@@ -45,6 +45,7 @@ var (
 		"options",
 		"created",
 		"filters",
+		"state",
 	}
 	contractListenerFilterFieldMap = map[string]string{
 		"interface": "interface_id",
@@ -54,6 +55,9 @@ var (
 
 const contractlistenersTable = "contractlisteners"
 
+// iteratePageSize is the number of rows read per query by IterateContractListeners
+var iteratePageSize uint64 = 100
+
 func (s *SQLCommon) UpsertContractListener(ctx context.Context, listener *core.ContractListener, allowExisting bool) (err error) {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
@@ -70,6 +74,7 @@ func (s *SQLCommon) UpsertContractListener(ctx context.Context, listener *core.C
 				Where(sq.Eq{
 					"namespace": listener.Namespace,
 					"name":      listener.Name,
+					"state":     core.ContractListenerStateActive,
 				}),
 		)
 		if err != nil {
@@ -111,6 +116,7 @@ func (s *SQLCommon) UpsertContractListener(ctx context.Context, listener *core.C
 				Where(sq.Eq{
 					"namespace": listener.Namespace,
 					"name":      listener.Name,
+					"state":     core.ContractListenerStateActive,
 				}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionContractListeners, core.ChangeEventTypeUpdated, listener.Namespace, listener.ID)
@@ -145,6 +151,7 @@ func (s *SQLCommon) InsertContractListener(ctx context.Context, listener *core.C
 	}
 
 	listener.Created = fftypes.Now()
+	listener.State = core.ContractListenerStateActive
 	if _, err = s.InsertTx(ctx, contractlistenersTable, tx,
 		sq.Insert(contractlistenersTable).
 			Columns(contractListenerColumns...).
@@ -161,6 +168,7 @@ func (s *SQLCommon) InsertContractListener(ctx context.Context, listener *core.C
 				listener.Options,
 				listener.Created,
 				listener.Filters,
+				listener.State,
 			),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionContractListeners, core.ChangeEventTypeCreated, listener.Namespace, listener.ID)
@@ -189,6 +197,7 @@ func (s *SQLCommon) contractListenerResult(ctx context.Context, row *sql.Rows) (
 		&listener.Options,
 		&listener.Created,
 		&listener.Filters,
+		&listener.State,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, contractlistenersTable)
@@ -199,7 +208,9 @@ func (s *SQLCommon) contractListenerResult(ctx context.Context, row *sql.Rows) (
 	return &listener, nil
 }
 
-func (s *SQLCommon) getContractListenerPred(ctx context.Context, desc string, pred interface{}) (*core.ContractListener, error) {
+// getContractListenerPred only returns an active listener, so a deleted listener is not found by its name, ID or backend ID
+func (s *SQLCommon) getContractListenerPred(ctx context.Context, desc string, pred sq.Eq) (*core.ContractListener, error) {
+	pred["state"] = core.ContractListenerStateActive
 	rows, _, err := s.Query(ctx, contractlistenersTable,
 		sq.Select(contractListenerColumns...).
 			From(contractlistenersTable).
@@ -235,10 +246,31 @@ func (s *SQLCommon) GetContractListenerByBackendID(ctx context.Context, namespac
 	return s.getContractListenerPred(ctx, id, sq.Eq{"backend_id": id, "namespace": namespace})
 }
 
+// contractListenerScope restricts a query to the active listeners in a namespace, unless the filter explicitly selects on state
+func contractListenerScope(namespace string, filter ffapi.Filter) sq.Eq {
+	scope := sq.Eq{"namespace": namespace}
+	if fi, err := filter.Finalize(); err != nil || !filterReferencesField(fi, "state") {
+		scope["state"] = core.ContractListenerStateActive
+	}
+	return scope
+}
+
+func filterReferencesField(fi *ffapi.FilterInfo, field string) bool {
+	if fi.Field == field {
+		return true
+	}
+	for _, child := range fi.Children {
+		if filterReferencesField(child, field) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SQLCommon) GetContractListeners(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.ContractListener, *ffapi.FilterResult, error) {
 	query, fop, fi, err := s.FilterSelect(ctx, "",
 		sq.Select(contractListenerColumns...).From(contractlistenersTable),
-		filter, contractListenerFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+		filter, contractListenerFilterFieldMap, []interface{}{"sequence"}, contractListenerScope(namespace, filter))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -261,6 +293,65 @@ func (s *SQLCommon) GetContractListeners(ctx context.Context, namespace string,
 	return subs, s.QueryRes(ctx, contractlistenersTable, tx, fop, nil, fi), err
 }
 
+// IterateContractListeners reads the matching listeners in pages of iteratePageSize, closing the cursor on each page
+// before its listeners are passed to the callback, so that a slow consumer does not hold a database connection open.
+// The limit and skip of the filter apply to the whole iteration.
+func (s *SQLCommon) IterateContractListeners(ctx context.Context, namespace string, filter ffapi.Filter, cb func(listener *core.ContractListener) error) error {
+	query, _, fi, err := s.FilterSelect(ctx, "",
+		sq.Select(contractListenerColumns...).From(contractlistenersTable),
+		filter, contractListenerFilterFieldMap, []interface{}{"sequence"}, contractListenerScope(namespace, filter))
+	if err != nil {
+		return err
+	}
+
+	skip, remaining := fi.Skip, fi.Limit
+	for {
+		pageSize := iteratePageSize
+		if fi.Limit > 0 && remaining < pageSize {
+			pageSize = remaining
+		}
+		page, err := s.getContractListenersPage(ctx, query.Offset(skip).Limit(pageSize))
+		if err != nil {
+			return err
+		}
+		for _, listener := range page {
+			if err := cb(listener); err != nil {
+				return err
+			}
+		}
+		if uint64(len(page)) < pageSize {
+			return nil
+		}
+		skip += pageSize
+		if fi.Limit > 0 {
+			if remaining -= pageSize; remaining == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (s *SQLCommon) getContractListenersPage(ctx context.Context, query sq.SelectBuilder) ([]*core.ContractListener, error) {
+	rows, _, err := s.Query(ctx, contractlistenersTable, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := []*core.ContractListener{}
+	for rows.Next() {
+		sub, err := s.contractListenerResult(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, contractlistenersTable)
+	}
+	return page, nil
+}
+
 func (s *SQLCommon) UpdateContractListener(ctx context.Context, ns string, id *fftypes.UUID, update ffapi.Update) (err error) {
 
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
@@ -298,9 +389,13 @@ func (s *SQLCommon) DeleteContractListenerByID(ctx context.Context, namespace st
 	}
 	defer s.RollbackTx(ctx, tx, autoCommit)
 
+	// The listener is kept as a tombstone for audit, so is only marked as deleted
 	sub, err := s.GetContractListenerByID(ctx, namespace, id)
 	if err == nil && sub != nil {
-		err = s.DeleteTx(ctx, contractlistenersTable, tx, sq.Delete(contractlistenersTable).Where(sq.Eq{"id": id}),
+		_, err = s.UpdateTx(ctx, contractlistenersTable, tx,
+			sq.Update(contractlistenersTable).
+				Set("state", core.ContractListenerStateDeleted).
+				Where(sq.Eq{"id": id}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionContractListeners, core.ChangeEventTypeDeleted, sub.Namespace, sub.ID)
 			},
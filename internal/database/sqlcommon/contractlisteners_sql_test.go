@@ -21,13 +21,16 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestContractListenerLegacyE2EWithDB(t *testing.T) {
@@ -114,12 +117,65 @@ func TestContractListenerLegacyE2EWithDB(t *testing.T) {
 	subReadJson, _ = json.Marshal(subs[0])
 	assert.Equal(t, string(subJson), string(subReadJson))
 
+	// Update the options
+	err = s.UpdateContractListener(ctx, "ns", sub.ID, database.ContractListenerQueryFactory.NewUpdate(ctx).
+		Set("options", fftypes.JSONAnyPtr(`{"firstEvent":"oldest"}`)))
+	assert.NoError(t, err)
+	subRead, err = s.GetContractListenerByID(ctx, "ns", sub.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "oldest", subRead.Options.FirstEvent)
+	sub.Options.FirstEvent = "oldest"
+	subJson, _ = json.Marshal(&sub)
+
+	// Iterate the listeners
+	var iterated []*core.ContractListener
+	err = s.IterateContractListeners(ctx, "ns", filter, func(listener *core.ContractListener) error {
+		iterated = append(iterated, listener)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(iterated))
+	subReadJson, _ = json.Marshal(iterated[0])
+	assert.Equal(t, string(subJson), string(subReadJson))
+
 	// Test delete, and refind no return
 	err = s.DeleteContractListenerByID(ctx, "ns", sub.ID)
 	assert.NoError(t, err)
 	subs, _, err = s.GetContractListeners(ctx, "ns", filter)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(subs))
+	subRead, err = s.GetContractListenerByID(ctx, "ns", sub.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, subRead)
+
+	// The deleted listener is kept as a tombstone, only returned when explicitly filtering on state
+	subs, _, err = s.GetContractListeners(ctx, "ns", fb.And(
+		fb.Eq("backendid", sub.BackendID),
+		fb.In("state", []driver.Value{core.ContractListenerStateActive, core.ContractListenerStateDeleted}),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(subs))
+	assert.Equal(t, core.ContractListenerStateDeleted, subs[0].State)
+
+	// The name and backend ID can be reused by a new listener
+	sub2 := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns",
+		Name:      "sub1",
+		BackendID: sub.BackendID,
+		Topic:     "topic1",
+		Event:     sub.Event,
+		Options:   sub.Options,
+	}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionContractListeners, core.ChangeEventTypeCreated, "ns", sub2.ID).Return()
+	err = s.InsertContractListener(ctx, sub2)
+	assert.NoError(t, err)
+	subRead, err = s.GetContractListener(ctx, "ns", "sub1")
+	assert.NoError(t, err)
+	assert.Equal(t, sub2.ID, subRead.ID)
+	subRead, err = s.GetContractListenerByBackendID(ctx, "ns", sub.BackendID)
+	assert.NoError(t, err)
+	assert.Equal(t, sub2.ID, subRead.ID)
 }
 
 func TestInsertContractListenerFailBegin(t *testing.T) {
@@ -200,6 +256,98 @@ func TestGetContractListenersScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestIterateContractListenersPaged(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	defer func(size uint64) { iteratePageSize = size }(iteratePageSize)
+	iteratePageSize = 2
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionContractListeners, core.ChangeEventTypeCreated, "ns", mock.Anything).Return()
+	for i := 0; i < 7; i++ {
+		err := s.InsertContractListener(ctx, &core.ContractListener{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns",
+			Name:      fmt.Sprintf("listener%d", i),
+			BackendID: fmt.Sprintf("sb-%d", i),
+			Event:     &core.FFISerializedEvent{},
+			Location:  fftypes.JSONAnyPtr("{}"),
+		})
+		assert.NoError(t, err)
+	}
+
+	iterate := func(filter ffapi.Filter) (names []string) {
+		err := s.IterateContractListeners(ctx, "ns", filter, func(listener *core.ContractListener) error {
+			names = append(names, listener.Name)
+			return nil
+		})
+		assert.NoError(t, err)
+		return names
+	}
+	newFilter := func() ffapi.AndFilter { return database.ContractListenerQueryFactory.NewFilter(ctx).And() }
+	assert.Equal(t, []string{"listener6", "listener5", "listener4", "listener3", "listener2", "listener1", "listener0"},
+		iterate(newFilter()))
+	assert.Equal(t, []string{"listener5", "listener4", "listener3"},
+		iterate(newFilter().Skip(1).Limit(3)))
+	assert.Equal(t, []string{"listener6", "listener5", "listener4", "listener3"},
+		iterate(newFilter().Limit(4)))
+	assert.Equal(t, []string{"listener6"},
+		iterate(newFilter().Limit(1)))
+}
+
+func TestIterateContractListenersBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background()).Eq("backendid", map[bool]bool{true: false})
+	err := s.IterateContractListeners(context.Background(), "ns", f, func(listener *core.ContractListener) error { return nil })
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestIterateContractListenersQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background()).Eq("backendid", "")
+	err := s.IterateContractListeners(context.Background(), "ns", f, func(listener *core.ContractListener) error { return nil })
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIterateContractListenersScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"backendid"}).AddRow("only one"))
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background()).Eq("backendid", "")
+	err := s.IterateContractListeners(context.Background(), "ns", f, func(listener *core.ContractListener) error { return nil })
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIterateContractListenersCallbackFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(contractListenerColumns).
+		AddRow(fftypes.NewUUID(), nil, []byte("{}"), "ns1", "sub1", "123", "{}", "sig", "topic1", nil, fftypes.Now(), "[]", "active").
+		AddRow(fftypes.NewUUID(), nil, []byte("{}"), "ns1", "sub2", "234", "{}", "sig", "topic1", nil, fftypes.Now(), "[]", "active"),
+	)
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background()).Eq("backendid", "")
+	calls := 0
+	err := s.IterateContractListeners(context.Background(), "ns", f, func(listener *core.ContractListener) error {
+		calls++
+		return fmt.Errorf("pop")
+	})
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIterateContractListenersRowsFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(contractListenerColumns).
+		AddRow(fftypes.NewUUID(), nil, []byte("{}"), "ns1", "sub1", "123", "{}", "sig", "topic1", nil, fftypes.Now(), "[]", "active").
+		RowError(0, fmt.Errorf("pop")),
+	)
+	f := database.ContractListenerQueryFactory.NewFilter(context.Background()).Eq("backendid", "")
+	err := s.IterateContractListeners(context.Background(), "ns", f, func(listener *core.ContractListener) error { return nil })
+	assert.Regexp(t, "FF10121.*pop", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestContractListenerDeleteBeginFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -211,11 +359,11 @@ func TestContractListenerDeleteFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(contractListenerColumns).AddRow(
-		fftypes.NewUUID(), nil, []byte("{}"), "ns1", "sub1", "123", "{}", "sig", "topic1", nil, fftypes.Now(), "[]"),
+		fftypes.NewUUID(), nil, []byte("{}"), "ns1", "sub1", "123", "{}", "sig", "topic1", nil, fftypes.Now(), "[]", "active"),
 	)
-	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
 	err := s.DeleteContractListenerByID(context.Background(), "ns", fftypes.NewUUID())
-	assert.Regexp(t, "FF00179", err)
+	assert.Regexp(t, "FF00178", err)
 }
 
 func TestContractListenerOptions(t *testing.T) {
@@ -244,6 +392,42 @@ func TestContractListenerOptions(t *testing.T) {
 	assert.Equal(t, l.Options, li.Options)
 }
 
+func TestContractListenerCompoundSort(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	ids := []*fftypes.UUID{fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	for i, id := range []*fftypes.UUID{ids[2], ids[0], ids[1]} {
+		topic := "topic2"
+		if i == 0 {
+			topic = "topic1"
+		}
+		l := &core.ContractListener{
+			ID:        id,
+			Name:      fmt.Sprintf("listener%d", i),
+			BackendID: fmt.Sprintf("sb-%d", i),
+			Namespace: "ns",
+			Event:     &core.FFISerializedEvent{},
+			Location:  fftypes.JSONAnyPtr("{}"),
+			Topic:     topic,
+		}
+		s.callbacks.On("UUIDCollectionNSEvent", database.CollectionContractListeners, core.ChangeEventTypeCreated, "ns", l.ID).Return()
+		err := s.InsertContractListener(ctx, l)
+		assert.NoError(t, err)
+	}
+
+	// Ties on topic are broken by the ID, so the order is deterministic
+	fb := database.ContractListenerQueryFactory.NewFilter(ctx)
+	listeners, _, err := s.GetContractListeners(ctx, "ns", fb.And().Sort("-topic", "id"))
+	assert.NoError(t, err)
+	assert.Len(t, listeners, 3)
+	assert.Equal(t, ids[0], listeners[0].ID)
+	assert.Equal(t, ids[1], listeners[1].ID)
+	assert.Equal(t, ids[2], listeners[2].ID)
+}
+
 func TestUpdateContractListenerFailFilter(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin()
@@ -19,6 +19,8 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
@@ -64,6 +66,9 @@ var (
 
 const dataTable = "data"
 
+// likeEscaper escapes the wildcards of a LIKE pattern, using the escape character given with ESCAPE '!'
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 func (s *SQLCommon) attemptDataUpdate(ctx context.Context, tx *dbsql.TXWrapper, data *core.Data) (int64, error) {
 	datatype := data.Datatype
 	if datatype == nil {
@@ -299,10 +304,60 @@ func (s *SQLCommon) GetDataByID(ctx context.Context, namespace string, id *fftyp
 }
 
 func (s *SQLCommon) GetData(ctx context.Context, namespace string, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error) {
+	return s.getData(ctx, filter, sq.Eq{"namespace": namespace})
+}
+
+func (s *SQLCommon) SearchData(ctx context.Context, namespace string, filter ffapi.Filter, valueQuery *database.DataValueQuery) (message core.DataArray, res *ffapi.FilterResult, err error) {
+	return s.getData(ctx, filter, sq.Eq{"namespace": namespace}, s.dataValueConditions("value", valueQuery))
+}
+
+// dataValueConditions returns the conditions for a query on the contents of a column of JSON data values
+func (s *SQLCommon) dataValueConditions(column string, valueQuery *database.DataValueQuery) sq.And {
+	conditions := sq.And{}
+	for _, match := range valueQuery.Matches {
+		if s.dialect.JSONContains != "" {
+			conditions = append(conditions, sq.Expr(fmt.Sprintf(s.dialect.JSONContains, column), jsonPathDocument(match)))
+		} else {
+			conditions = append(conditions, sq.Expr(fmt.Sprintf("json_extract(%s, ?) = json_extract(?, '$')", column), jsonPathExpression(match.Path), match.Value.String()))
+		}
+	}
+	if valueQuery.Search != "" {
+		if s.capabilities.FullTextSearch {
+			conditions = append(conditions, sq.Expr(fmt.Sprintf(s.dialect.FullTextSearch, column), valueQuery.Search))
+		} else {
+			conditions = append(conditions, sq.Expr(fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '!'", column), "%"+likeEscaper.Replace(strings.ToLower(valueQuery.Search))+"%"))
+		}
+	}
+	return conditions
+}
+
+// jsonPathDocument returns the smallest JSON document that contains the value of a match at its path
+func jsonPathDocument(match *database.JSONPathMatch) string {
+	var doc interface{} = json.RawMessage(match.Value.String())
+	for i := len(match.Path) - 1; i >= 0; i-- {
+		doc = map[string]interface{}{match.Path[i]: doc}
+	}
+	b, _ := json.Marshal(doc)
+	return string(b)
+}
+
+// jsonPathExpression returns the path of a match in the path syntax of the SQL JSON functions
+func jsonPathExpression(path []string) string {
+	var buff strings.Builder
+	buff.WriteString("$")
+	for _, key := range path {
+		buff.WriteString(`."`)
+		buff.WriteString(key)
+		buff.WriteString(`"`)
+	}
+	return buff.String()
+}
+
+func (s *SQLCommon) getData(ctx context.Context, filter ffapi.Filter, preconditions ...sq.Sqlizer) (message core.DataArray, res *ffapi.FilterResult, err error) {
 
 	query, fop, fi, err := s.FilterSelect(
 		ctx, "", sq.Select(dataColumnsWithValue...).From(dataTable),
-		filter, dataFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+		filter, dataFilterFieldMap, []interface{}{"sequence"}, preconditions...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -437,3 +492,20 @@ func (s *SQLCommon) DeleteData(ctx context.Context, namespace string, id *fftype
 
 	return s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteUnreferencedData(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, dataTable, tx, sq.Delete(dataTable).
+		Where(sq.Eq{"namespace": namespace, "id": ids}).
+		Where(sq.Expr("id NOT IN (SELECT data_id FROM "+messagesDataJoinTable+" WHERE namespace = ?)", namespace)), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
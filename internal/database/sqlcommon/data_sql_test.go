@@ -235,6 +235,88 @@ func TestDataSubPaths(t *testing.T) {
 
 }
 
+func newSearchTestData(t *testing.T, s *sqliteGoTestProvider, value string) *core.Data {
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Hash:      fftypes.NewRandB32(),
+		Created:   fftypes.Now(),
+		Value:     fftypes.JSONAnyPtr(value),
+	}
+	err := s.UpsertData(context.Background(), data, database.UpsertOptimizationNew)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestDataSearchWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+	data1 := newSearchTestData(t, s, `{"order":{"customer":"acme","total":10,"paid":true},"notes":"Widgets 50% off"}`)
+	data2 := newSearchTestData(t, s, `{"order":{"customer":"globex","total":20.0}}`)
+	newSearchTestData(t, s, `"just a string"`)
+
+	search := func(query *database.DataValueQuery) []*fftypes.UUID {
+		fb := database.DataQueryFactory.NewFilter(ctx)
+		data, res, err := s.SearchData(ctx, "ns1", fb.And().Count(true), query)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(data)), *res.TotalCount)
+		ids := make([]*fftypes.UUID, len(data))
+		for i, d := range data {
+			ids[i] = d.ID
+		}
+		return ids
+	}
+	match := func(value string, path ...string) *database.JSONPathMatch {
+		return &database.JSONPathMatch{Path: path, Value: fftypes.JSONAnyPtr(value)}
+	}
+
+	assert.Equal(t, []*fftypes.UUID{data1.ID}, search(&database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{match(`"acme"`, "order", "customer")},
+	}))
+	assert.Equal(t, []*fftypes.UUID{data2.ID}, search(&database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{match(`20`, "order", "total")},
+	}))
+	assert.Equal(t, []*fftypes.UUID{data1.ID}, search(&database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{match(`true`, "order", "paid"), match(`10`, "order", "total")},
+	}))
+	assert.Empty(t, search(&database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{match(`"acme"`, "order", "missing")},
+	}))
+
+	// Without full-text search the terms are matched as a literal, case-insensitive substring
+	assert.Equal(t, []*fftypes.UUID{data1.ID}, search(&database.DataValueQuery{Search: "WIDGETS 50%"}))
+	assert.Empty(t, search(&database.DataValueQuery{Search: "widgets_50"}))
+	assert.Empty(t, search(&database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{match(`"globex"`, "order", "customer")},
+		Search:  "widgets",
+	}))
+}
+
+func TestSearchDataJSONContainsFullText(t *testing.T) {
+	mp := newMockProvider()
+	mp.dialect = &Dialect{
+		JSONContains:   "(%s::jsonb) @> ?::jsonb",
+		FullTextSearch: "fts(%s, ?)",
+	}
+	mp.capabilities.FullTextSearch = true
+	s, mock := mp.init()
+	mock.ExpectQuery(`SELECT .* FROM data WHERE \(namespace = \$1 AND \(\(value::jsonb\) @> \$2::jsonb AND fts\(value, \$3\)\) AND \(1=1\)\)`).
+		WithArgs("ns1", `{"order":{"customer":"acme"}}`, "widgets").
+		WillReturnRows(sqlmock.NewRows(dataColumnsWithValue))
+	fb := database.DataQueryFactory.NewFilter(context.Background())
+	_, _, err := s.SearchData(context.Background(), "ns1", fb.And(), &database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{
+			{Path: []string{"order", "customer"}, Value: fftypes.JSONAnyPtr(`"acme"`)},
+		},
+		Search: "widgets",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpsertDataFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -484,3 +566,21 @@ func TestGetDataSubPathsReadFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteUnreferencedDataFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteUnreferencedData(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteUnreferencedDataFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteUnreferencedData(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
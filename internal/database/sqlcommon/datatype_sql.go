@@ -42,6 +42,7 @@ var (
 		"hash",
 		"created",
 		"value",
+		"lifecycle",
 	}
 	datatypeFilterFieldMap = map[string]string{
 		"message": "message_id",
@@ -95,6 +96,9 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 			return err
 		}
 	} else {
+		if datatype.Lifecycle == "" {
+			datatype.Lifecycle = core.DefinitionLifecycleActive
+		}
 		if _, err = s.InsertTx(ctx, datatypesTable, tx,
 			sq.Insert(datatypesTable).
 				Columns(datatypeColumns...).
@@ -108,6 +112,7 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 					datatype.Hash,
 					datatype.Created,
 					datatype.Value,
+					datatype.Lifecycle,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionDataTypes, core.ChangeEventTypeCreated, datatype.Namespace, datatype.ID)
@@ -120,6 +125,27 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 	return s.CommitTx(ctx, tx, autoCommit)
 }
 
+func (s *SQLCommon) UpdateDatatypeLifecycle(ctx context.Context, namespace string, id *fftypes.UUID, lifecycle core.DefinitionLifecycle) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.UpdateTx(ctx, datatypesTable, tx,
+		sq.Update(datatypesTable).
+			Set("lifecycle", lifecycle).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionDataTypes, core.ChangeEventTypeUpdated, namespace, id)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
 func (s *SQLCommon) datatypeResult(ctx context.Context, row *sql.Rows) (*core.Datatype, error) {
 	var datatype core.Datatype
 	err := row.Scan(
@@ -132,6 +158,7 @@ func (s *SQLCommon) datatypeResult(ctx context.Context, row *sql.Rows) (*core.Da
 		&datatype.Hash,
 		&datatype.Created,
 		&datatype.Value,
+		&datatype.Lifecycle,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, datatypesTable)
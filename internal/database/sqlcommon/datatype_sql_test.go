@@ -89,6 +89,7 @@ func TestDatatypeE2EWithDB(t *testing.T) {
 		Hash:      randB32,
 		Created:   fftypes.Now(),
 		Value:     fftypes.JSONAnyPtr(val2.String()),
+		Lifecycle: core.DefinitionLifecycleActive,
 	}
 	err = s.UpsertDatatype(context.Background(), datatypeUpdated, true)
 	assert.NoError(t, err)
@@ -116,6 +117,14 @@ func TestDatatypeE2EWithDB(t *testing.T) {
 	datatypeReadJson, _ = json.Marshal(datatypes[0])
 	assert.Equal(t, string(datatypeJson), string(datatypeReadJson))
 
+	// Deprecate the datatype
+	err = s.UpdateDatatypeLifecycle(ctx, "ns1", datatypeID, core.DefinitionLifecycleDeprecated)
+	assert.NoError(t, err)
+	datatypes, _, err = s.GetDatatypes(ctx, "ns1", fb.Eq("lifecycle", core.DefinitionLifecycleDeprecated))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(datatypes))
+	assert.Equal(t, core.DefinitionLifecycleDeprecated, datatypes[0].Lifecycle)
+
 	s.callbacks.AssertExpectations(t)
 }
 
@@ -234,3 +243,21 @@ func TestGetDatatypesReadMessageFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateDatatypeLifecycleFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpdateDatatypeLifecycle(context.Background(), "ns1", fftypes.NewUUID(), core.DefinitionLifecycleRetired)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDatatypeLifecycleFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpdateDatatypeLifecycle(context.Background(), "ns1", fftypes.NewUUID(), core.DefinitionLifecycleRetired)
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
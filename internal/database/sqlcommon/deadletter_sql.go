@@ -0,0 +1,193 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var (
+	deadLetterColumns = []string{
+		"id",
+		"namespace",
+		"subscription_id",
+		"event_id",
+		"event_type",
+		"error",
+		"created",
+		"updated",
+	}
+	deadLetterFilterFieldMap = map[string]string{
+		"subscription": "subscription_id",
+		"event":        "event_id",
+		"eventtype":    "event_type",
+	}
+)
+
+const deadLettersTable = "deadletters"
+
+func (s *SQLCommon) InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, deadLettersTable, tx,
+		sq.Insert(deadLettersTable).
+			Columns(deadLetterColumns...).
+			Values(
+				deadLetter.ID,
+				deadLetter.Namespace,
+				deadLetter.Subscription,
+				deadLetter.Event,
+				deadLetter.EventType,
+				deadLetter.Error,
+				deadLetter.Created,
+				deadLetter.Updated,
+			),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionDeadLetters, core.ChangeEventTypeCreated, deadLetter.Namespace, deadLetter.ID)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) deadLetterResult(ctx context.Context, row *sql.Rows) (*core.DeadLetter, error) {
+	deadLetter := core.DeadLetter{}
+	err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.Namespace,
+		&deadLetter.Subscription,
+		&deadLetter.Event,
+		&deadLetter.EventType,
+		&deadLetter.Error,
+		&deadLetter.Created,
+		&deadLetter.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, deadLettersTable)
+	}
+	return &deadLetter, nil
+}
+
+func (s *SQLCommon) GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (deadLetter *core.DeadLetter, err error) {
+
+	rows, _, err := s.Query(ctx, deadLettersTable,
+		sq.Select(deadLetterColumns...).
+			From(deadLettersTable).
+			Where(sq.Eq{"id": id, "namespace": namespace}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Dead letter '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.deadLetterResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) (deadLetters []*core.DeadLetter, fr *ffapi.FilterResult, err error) {
+
+	query, fop, fi, err := s.FilterSelect(
+		ctx, "", sq.Select(deadLetterColumns...).From(deadLettersTable),
+		filter, deadLetterFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, deadLettersTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	deadLetters = []*core.DeadLetter{}
+	for rows.Next() {
+		d, err := s.deadLetterResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		deadLetters = append(deadLetters, d)
+	}
+
+	return deadLetters, s.QueryRes(ctx, deadLettersTable, tx, fop, nil, fi), err
+
+}
+
+func (s *SQLCommon) UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(deadLettersTable), update, deadLetterFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	_, err = s.UpdateTx(ctx, deadLettersTable, tx, query,
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionDeadLetters, core.ChangeEventTypeUpdated, namespace, id)
+		})
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, deadLettersTable, tx, sq.Delete(deadLettersTable).Where(sq.Eq{
+		"id": id, "namespace": namespace,
+	}),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionDeadLetters, core.ChangeEventTypeDeleted, namespace, id)
+		})
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,195 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLettersE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new dead-letter entry
+	deadLetter := &core.DeadLetter{
+		ID:           fftypes.NewUUID(),
+		Namespace:    "ns1",
+		Subscription: fftypes.NewUUID(),
+		Event:        fftypes.NewUUID(),
+		EventType:    core.EventTypeMessageConfirmed,
+		Error:        "pop",
+		Created:      fftypes.Now(),
+	}
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDeadLetters, core.ChangeEventTypeCreated, "ns1", deadLetter.ID).Return()
+	err := s.InsertDeadLetter(ctx, deadLetter)
+	assert.NoError(t, err)
+
+	// Check we get the exact same entry back
+	deadLetterRead, err := s.GetDeadLetterByID(ctx, "ns1", deadLetter.ID)
+	assert.NoError(t, err)
+	deadLetterJson, _ := json.Marshal(&deadLetter)
+	deadLetterReadJson, _ := json.Marshal(&deadLetterRead)
+	assert.Equal(t, string(deadLetterJson), string(deadLetterReadJson))
+
+	// Update the entry
+	updateTime := fftypes.Now()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDeadLetters, core.ChangeEventTypeUpdated, "ns1", deadLetter.ID).Return()
+	up := database.DeadLetterQueryFactory.NewUpdate(ctx).
+		Set("error", "pop again").
+		Set("updated", updateTime)
+	err = s.UpdateDeadLetter(ctx, "ns1", deadLetter.ID, up)
+	assert.NoError(t, err)
+
+	// Query back the entry by subscription
+	fb := database.DeadLetterQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("subscription", deadLetter.Subscription),
+		fb.Eq("eventtype", core.EventTypeMessageConfirmed),
+	)
+	deadLetters, res, err := s.GetDeadLetters(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(deadLetters))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	assert.Equal(t, "pop again", deadLetters[0].Error)
+	assert.Equal(t, updateTime.String(), deadLetters[0].Updated.String())
+
+	// Test delete, and refind no return
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDeadLetters, core.ChangeEventTypeDeleted, "ns1", deadLetter.ID).Return()
+	err = s.DeleteDeadLetter(ctx, "ns1", deadLetter.ID)
+	assert.NoError(t, err)
+	deadLetterRead, err = s.GetDeadLetterByID(ctx, "ns1", deadLetter.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, deadLetterRead)
+
+	s.callbacks.AssertExpectations(t)
+}
+
+func TestInsertDeadLetterFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDeadLetterByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetDeadLetterByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLettersQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("error", "")
+	_, _, err := s.GetDeadLetters(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLettersBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("error", map[bool]bool{true: false})
+	_, _, err := s.GetDeadLetters(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*type", err)
+}
+
+func TestGetDeadLettersReadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("error", "")
+	_, _, err := s.GetDeadLetters(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDeadLetterBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("error", "anything")
+	err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateDeadLetterBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("error", map[bool]bool{true: false})
+	err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*error", err)
+}
+
+func TestUpdateDeadLetterFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("error", "anything")
+	err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDeadLetterBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteDeadLetter(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDeadLetterFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteDeadLetter(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
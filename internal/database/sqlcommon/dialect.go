@@ -0,0 +1,72 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Dialect describes the SQL semantics that differ between the databases sqlcommon runs against,
+// for the parts of the dbsql.Provider interface that each database plugin must otherwise implement itself
+type Dialect struct {
+	// ReturningSequence is true if the generated sequence can be returned from the INSERT statement itself,
+	// otherwise the sequence is read from the LastInsertId of the driver result
+	ReturningSequence bool
+	// ConflictDoNothing is the clause to return an empty result on an insert conflict, rather than an error,
+	// so an upsert can detect the existing row without a failed statement. Requires ReturningSequence
+	ConflictDoNothing string
+	// JSONContains is the condition that the JSON text in a column (%s) contains the JSON document of the parameter,
+	// which the database can evaluate using an index on the column. If empty, the value at each path is extracted
+	// and compared using the JSON functions of SQLite
+	JSONContains string
+	// FullTextSearch is the condition that the JSON text in a column (%s) matches the search terms of the parameter,
+	// for plugins that report the FullTextSearch capability
+	FullTextSearch string
+	// IntegerDivide is the operator that divides one integer by another to give an integer, discarding the remainder.
+	// If empty "/" is used, which does this for integers in SQLite and PostgreSQL
+	IntegerDivide string
+}
+
+// ApplyInsertQueryCustomizations implements the dbsql.Provider function of the same name, for the dialect
+func (d *Dialect) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, sequenceColumn string, requestConflictEmptyResult bool) (sq.InsertBuilder, bool) {
+	if !d.ReturningSequence {
+		return insert, false
+	}
+	suffix := fmt.Sprintf(" RETURNING %s", sequenceColumn)
+	if requestConflictEmptyResult && d.ConflictDoNothing != "" {
+		// Caller wants us to return an empty result set on insert conflict, rather than an error
+		suffix = fmt.Sprintf(" %s%s", d.ConflictDoNothing, suffix)
+	}
+	return insert.Suffix(suffix), true
+}
+
+// integerDivide returns the expression that divides the integer expression a by b, discarding the remainder
+func (d *Dialect) integerDivide(a, b string) string {
+	op := d.IntegerDivide
+	if op == "" {
+		op = "/"
+	}
+	return fmt.Sprintf("%s %s %s", a, op, b)
+}
+
+// quoted returns the SQL standard (double quoted) form of a table or column name that is a reserved word
+// in one of the dialects. The MySQL plugin enables ANSI_QUOTES so it interprets these the same way.
+func quoted(identifier string) string {
+	return fmt.Sprintf(`"%s"`, identifier)
+}
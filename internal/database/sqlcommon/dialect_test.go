@@ -0,0 +1,59 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectReturningSequence(t *testing.T) {
+	d := &Dialect{ReturningSequence: true, ConflictDoNothing: "ON CONFLICT DO NOTHING"}
+
+	insert, query := d.ApplyInsertQueryCustomizations(sq.Insert("test").Columns("col1").Values("val1"), "seq", false)
+	sql, _, err := insert.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)  RETURNING seq", sql)
+	assert.True(t, query)
+
+	insert, query = d.ApplyInsertQueryCustomizations(sq.Insert("test").Columns("col1").Values("val1"), "seq", true)
+	sql, _, err = insert.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)  ON CONFLICT DO NOTHING RETURNING seq", sql)
+	assert.True(t, query)
+}
+
+func TestDialectLastInsertID(t *testing.T) {
+	d := &Dialect{}
+
+	insert, query := d.ApplyInsertQueryCustomizations(sq.Insert("test").Columns("col1").Values("val1"), "seq", true)
+	sql, _, err := insert.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)", sql)
+	assert.False(t, query)
+}
+
+func TestDialectIntegerDivide(t *testing.T) {
+	assert.Equal(t, "a / b", (&Dialect{}).integerDivide("a", "b"))
+	assert.Equal(t, "a // b", (&Dialect{IntegerDivide: "//"}).integerDivide("a", "b"))
+}
+
+func TestQuoted(t *testing.T) {
+	assert.Equal(t, `"key"`, quoted("key"))
+}
@@ -246,3 +246,21 @@ func (s *SQLCommon) GetEventsInSequenceRange(ctx context.Context, namespace stri
 
 	return s.getEventsGeneric(ctx, namespace, query, filter)
 }
+
+func (s *SQLCommon) DeleteEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, eventsTable, tx, sq.Delete(eventsTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        ids,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
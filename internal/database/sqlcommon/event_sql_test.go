@@ -329,3 +329,44 @@ func TestGetEventsInSequenceRangeShouldCallGetEventsWhenNoSequencedProvidedAndTh
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestDeleteEvents(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionEvents, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+	event1 := &core.Event{ID: fftypes.NewUUID(), Namespace: "ns1", Type: core.EventTypeMessageConfirmed, Created: fftypes.Now()}
+	event2 := &core.Event{ID: fftypes.NewUUID(), Namespace: "ns1", Type: core.EventTypeMessageConfirmed, Created: fftypes.Now()}
+	assert.NoError(t, s.InsertEvent(ctx, event1))
+	assert.NoError(t, s.InsertEvent(ctx, event2))
+
+	err := s.DeleteEvents(ctx, "ns1", []*fftypes.UUID{event1.ID})
+	assert.NoError(t, err)
+	err = s.DeleteEvents(ctx, "ns1", []*fftypes.UUID{event1.ID})
+	assert.NoError(t, err)
+
+	eventRead, err := s.GetEventByID(ctx, "ns1", event1.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, eventRead)
+	eventRead, err = s.GetEventByID(ctx, "ns1", event2.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, eventRead)
+}
+
+func TestDeleteEventsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteEvents(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteEventsFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteEvents(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -290,3 +290,45 @@ func (s *SQLCommon) DeleteFFI(ctx context.Context, namespace string, id *fftypes
 
 	return s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) UpdateFFILifecycle(ctx context.Context, namespace string, id *fftypes.UUID, lifecycle core.DefinitionLifecycle) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.UpdateTx(ctx, ffiTable, tx,
+		sq.Update(ffiTable).
+			Set("lifecycle", lifecycle).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionFFIs, core.ChangeEventTypeUpdated, namespace, id)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) GetFFILifecycle(ctx context.Context, namespace string, id *fftypes.UUID) (lifecycle core.DefinitionLifecycle, err error) {
+	rows, _, err := s.Query(ctx, ffiTable,
+		sq.Select("lifecycle").
+			From(ffiTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("FFI '%s' not found", id)
+		return "", nil
+	}
+	if err = rows.Scan(&lifecycle); err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, ffiTable)
+	}
+	return lifecycle, nil
+}
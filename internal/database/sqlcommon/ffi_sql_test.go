@@ -128,6 +128,20 @@ func TestFFIE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, ffi.ID, existing.ID)
 
+	// Check the lifecycle defaults to active, and can be updated
+	lifecycle, err := s.GetFFILifecycle(ctx, "ns1", id)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionLifecycleActive, lifecycle)
+	err = s.UpdateFFILifecycle(ctx, "ns1", id, core.DefinitionLifecycleRetired)
+	assert.NoError(t, err)
+	lifecycle, err = s.GetFFILifecycle(ctx, "ns1", id)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DefinitionLifecycleRetired, lifecycle)
+	fb := database.FFIQueryFactory.NewFilter(ctx)
+	ffis, _, err := s.GetFFIs(ctx, "ns1", fb.Eq("lifecycle", core.DefinitionLifecycleRetired))
+	assert.NoError(t, err)
+	assert.Len(t, ffis, 1)
+
 	// Delete the FFI
 	err = s.DeleteFFI(ctx, "ns1", ffi.ID)
 	assert.NoError(t, err)
@@ -292,3 +306,46 @@ func TestDeleteFFIFailDelete(t *testing.T) {
 	assert.Regexp(t, "FF00179", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateFFILifecycleFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpdateFFILifecycle(context.Background(), "ns1", fftypes.NewUUID(), core.DefinitionLifecycleRetired)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateFFILifecycleFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpdateFFILifecycle(context.Background(), "ns1", fftypes.NewUUID(), core.DefinitionLifecycleRetired)
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFFILifecycleSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetFFILifecycle(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFFILifecycleNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"lifecycle"}))
+	lifecycle, err := s.GetFFILifecycle(context.Background(), "ns1", fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Empty(t, lifecycle)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFFILifecycleScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"lifecycle", "extra"}).AddRow("active", "extra"))
+	_, err := s.GetFFILifecycle(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -38,6 +38,7 @@ var (
 		"namespace_local",
 		"name",
 		"hash",
+		"previous",
 		"created",
 	}
 	groupFilterFieldMap = map[string]string{
@@ -45,7 +46,8 @@ var (
 	}
 )
 
-const groupsTable = "groups"
+// groups is a reserved word in MySQL
+var groupsTable = quoted("groups")
 
 func (s *SQLCommon) UpsertGroup(ctx context.Context, group *core.Group, optimization database.UpsertOptimization) (err error) {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
@@ -109,6 +111,7 @@ func (s *SQLCommon) attemptGroupUpdate(ctx context.Context, tx *dbsql.TXWrapper,
 			Set("message_id", group.Message).
 			Set("name", group.Name).
 			Set("hash", group.Hash).
+			Set("previous", group.Previous).
 			Set("created", group.Created).
 			Where(sq.Eq{"hash": group.Hash, "namespace_local": group.LocalNamespace}),
 		func() {
@@ -127,6 +130,7 @@ func (s *SQLCommon) attemptGroupInsert(ctx context.Context, tx *dbsql.TXWrapper,
 				group.LocalNamespace,
 				group.Name,
 				group.Hash,
+				group.Previous,
 				group.Created,
 			),
 		func() {
@@ -239,6 +243,7 @@ func (s *SQLCommon) groupResult(ctx context.Context, row *sql.Rows) (*core.Group
 		&group.LocalNamespace,
 		&group.Name,
 		&group.Hash,
+		&group.Previous,
 		&group.Created,
 	)
 	if err != nil {
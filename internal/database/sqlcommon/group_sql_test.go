@@ -79,6 +79,7 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 		Created:        fftypes.Now(),
 		Message:        fftypes.NewUUID(),
 		Hash:           groupHash,
+		Previous:       fftypes.NewRandB32(),
 	}
 
 	err = s.UpsertGroup(context.Background(), groupUpdated, database.UpsertOptimizationExisting)
@@ -96,6 +97,7 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 	filter := fb.And(
 		fb.Eq("hash", groupUpdated.Hash),
 		fb.Eq("message", groupUpdated.Message),
+		fb.Eq("previous", groupUpdated.Previous),
 		fb.Gt("created", "0"),
 	)
 	groups, _, err := s.GetGroups(ctx, "ns1", filter)
@@ -332,7 +334,7 @@ func TestGetGroupByIDLoadMembersFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	groupID := fftypes.NewRandB32()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(groupColumns).
-		AddRow(nil, "ns1", "ns1", "name1", fftypes.NewRandB32(), fftypes.Now()))
+		AddRow(nil, "ns1", "ns1", "name1", fftypes.NewRandB32(), nil, fftypes.Now()))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetGroupByHash(context.Background(), "ns1", groupID)
 	assert.Regexp(t, "FF00176", err)
@@ -367,7 +369,7 @@ func TestGetGroupsReadGroupFail(t *testing.T) {
 func TestGetGroupsLoadMembersFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(groupColumns).
-		AddRow(nil, "ns1", "ns1", "group1", fftypes.NewRandB32(), fftypes.Now()))
+		AddRow(nil, "ns1", "ns1", "group1", fftypes.NewRandB32(), nil, fftypes.Now()))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.GroupQueryFactory.NewFilter(context.Background()).Gt("created", "0")
 	_, _, err := s.GetGroups(context.Background(), "ns1", f)
@@ -0,0 +1,141 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	leaderLockColumns = []string{
+		"name",
+		"holder",
+		"expires",
+	}
+)
+
+const leaderLocksTable = "leaderlocks"
+
+func (s *SQLCommon) AcquireLeaderLock(ctx context.Context, lock *core.LeaderLock, now *fftypes.FFTime) (acquired bool, err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	// Renew the lock if we hold it, or take it over if it has expired
+	updated, err := s.UpdateTx(ctx, leaderLocksTable, tx,
+		sq.Update(leaderLocksTable).
+			Set("holder", lock.Holder).
+			Set("expires", lock.Expires).
+			Where(sq.And{
+				sq.Eq{"name": lock.Name},
+				sq.Or{
+					sq.Eq{"holder": lock.Holder},
+					sq.Lt{"expires": now},
+				},
+			}),
+		nil, // no change events for leader locks
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if updated == 0 {
+		existing, err := s.GetLeaderLock(ctx, lock.Name)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil {
+			log.L(ctx).Debugf("Leader lock '%s' held by '%s' until %s", lock.Name, existing.Holder, existing.Expires)
+			return false, nil
+		}
+		// A concurrent insert by another node fails on the unique index, and that node holds the lock
+		if _, err = s.InsertTx(ctx, leaderLocksTable, tx,
+			sq.Insert(leaderLocksTable).
+				Columns(leaderLockColumns...).
+				Values(
+					lock.Name,
+					lock.Holder,
+					lock.Expires,
+				),
+			nil, // no change events for leader locks
+		); err != nil {
+			return false, err
+		}
+	}
+
+	return true, s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) leaderLockResult(ctx context.Context, row *sql.Rows) (*core.LeaderLock, error) {
+	lock := core.LeaderLock{}
+	err := row.Scan(
+		&lock.Name,
+		&lock.Holder,
+		&lock.Expires,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, leaderLocksTable)
+	}
+	return &lock, nil
+}
+
+func (s *SQLCommon) GetLeaderLock(ctx context.Context, name string) (*core.LeaderLock, error) {
+	rows, _, err := s.Query(ctx, leaderLocksTable,
+		sq.Select(leaderLockColumns...).
+			From(leaderLocksTable).
+			Where(sq.Eq{"name": name}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Leader lock '%s' not found", name)
+		return nil, nil
+	}
+
+	return s.leaderLockResult(ctx, rows)
+}
+
+func (s *SQLCommon) ReleaseLeaderLock(ctx context.Context, name, holder string) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, leaderLocksTable, tx, sq.Delete(leaderLocksTable).Where(sq.Eq{
+		"name":   name,
+		"holder": holder,
+	}), nil /* no change events for leader locks */)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,145 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderLocksE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := fftypes.Now()
+	expires := fftypes.FFTime(time.Time(*now).Add(30 * time.Second))
+
+	// The first node takes the lock
+	acquired, err := s.AcquireLeaderLock(ctx, &core.LeaderLock{Name: "firefly", Holder: "node1", Expires: &expires}, now)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// The second node cannot take it before it expires
+	acquired, err = s.AcquireLeaderLock(ctx, &core.LeaderLock{Name: "firefly", Holder: "node2", Expires: &expires}, now)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// The first node can renew it
+	acquired, err = s.AcquireLeaderLock(ctx, &core.LeaderLock{Name: "firefly", Holder: "node1", Expires: &expires}, now)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	lock, err := s.GetLeaderLock(ctx, "firefly")
+	assert.NoError(t, err)
+	assert.Equal(t, "node1", lock.Holder)
+	assert.Equal(t, expires.UnixNano(), lock.Expires.UnixNano())
+
+	// The second node can take it once it expires
+	later := fftypes.FFTime(time.Time(expires).Add(time.Second))
+	laterExpires := fftypes.FFTime(time.Time(later).Add(30 * time.Second))
+	acquired, err = s.AcquireLeaderLock(ctx, &core.LeaderLock{Name: "firefly", Holder: "node2", Expires: &laterExpires}, &later)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// Only the holder can release it
+	err = s.ReleaseLeaderLock(ctx, "firefly", "node1")
+	assert.NoError(t, err)
+	lock, err = s.GetLeaderLock(ctx, "firefly")
+	assert.NoError(t, err)
+	assert.Equal(t, "node2", lock.Holder)
+
+	err = s.ReleaseLeaderLock(ctx, "firefly", "node2")
+	assert.NoError(t, err)
+	lock, err = s.GetLeaderLock(ctx, "firefly")
+	assert.NoError(t, err)
+	assert.Nil(t, lock)
+}
+
+func TestAcquireLeaderLockFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.AcquireLeaderLock(context.Background(), &core.LeaderLock{}, fftypes.Now())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcquireLeaderLockFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.AcquireLeaderLock(context.Background(), &core.LeaderLock{Expires: fftypes.Now()}, fftypes.Now())
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcquireLeaderLockFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.AcquireLeaderLock(context.Background(), &core.LeaderLock{Expires: fftypes.Now()}, fftypes.Now())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcquireLeaderLockFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(leaderLockColumns))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.AcquireLeaderLock(context.Background(), &core.LeaderLock{Expires: fftypes.Now()}, fftypes.Now())
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLeaderLockScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("only one"))
+	_, err := s.GetLeaderLock(context.Background(), "firefly")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseLeaderLockFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.ReleaseLeaderLock(context.Background(), "firefly", "node1")
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseLeaderLockFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.ReleaseLeaderLock(context.Background(), "firefly", "node1")
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,122 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var (
+	messageAckColumns = []string{
+		"id",
+		"namespace",
+		"message_id",
+		"ack_type",
+		"author",
+		"node_id",
+		"created",
+	}
+	messageAckFilterFieldMap = map[string]string{
+		"message": "message_id",
+		"type":    "ack_type",
+		"node":    "node_id",
+	}
+)
+
+const messageAcksTable = "message_acks"
+
+func (s *SQLCommon) InsertMessageAck(ctx context.Context, ack *core.MessageAck) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, messageAcksTable, tx,
+		sq.Insert(messageAcksTable).
+			Columns(messageAckColumns...).
+			Values(
+				ack.ID,
+				ack.Namespace,
+				ack.Message,
+				ack.Type,
+				ack.Author,
+				ack.Node,
+				ack.Created,
+			),
+		func() {
+			s.callbacks.UUIDCollectionNSEvent(database.CollectionMessageAcks, core.ChangeEventTypeCreated, ack.Namespace, ack.ID)
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) messageAckResult(ctx context.Context, row *sql.Rows) (*core.MessageAck, error) {
+	ack := core.MessageAck{}
+	err := row.Scan(
+		&ack.ID,
+		&ack.Namespace,
+		&ack.Message,
+		&ack.Type,
+		&ack.Author,
+		&ack.Node,
+		&ack.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, messageAcksTable)
+	}
+	return &ack, nil
+}
+
+func (s *SQLCommon) GetMessageAcks(ctx context.Context, namespace string, filter ffapi.Filter) (acks []*core.MessageAck, fr *ffapi.FilterResult, err error) {
+
+	query, fop, fi, err := s.FilterSelect(
+		ctx, "", sq.Select(messageAckColumns...).From(messageAcksTable),
+		filter, messageAckFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, messageAcksTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	acks = []*core.MessageAck{}
+	for rows.Next() {
+		a, err := s.messageAckResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		acks = append(acks, a)
+	}
+
+	return acks, s.QueryRes(ctx, messageAcksTable, tx, fop, nil, fi), err
+
+}
@@ -38,7 +38,7 @@ var (
 		"cid",
 		"mtype",
 		"author",
-		"key",
+		quoted("key"),
 		"created",
 		"namespace",
 		"namespace_local",
@@ -57,6 +57,7 @@ var (
 		"tx_parent_id",
 		"batch_id",
 		"idempotency_key",
+		"send_after",
 	}
 	msgFilterFieldMap = map[string]string{
 		"type":           "mtype",
@@ -68,6 +69,8 @@ var (
 		"group":          "group_hash",
 		"idempotencykey": "idempotency_key",
 		"rejectreason":   "reject_reason",
+		"sendafter":      "send_after",
+		"key":            quoted("key"),
 	}
 )
 
@@ -87,7 +90,7 @@ func (s *SQLCommon) attemptMessageUpdate(ctx context.Context, tx *dbsql.TXWrappe
 			Set("cid", message.Header.CID).
 			Set("mtype", string(message.Header.Type)).
 			Set("author", message.Header.Author).
-			Set("key", message.Header.Key).
+			Set(quoted("key"), message.Header.Key).
 			Set("created", message.Header.Created).
 			Set("topics", message.Header.Topics).
 			Set("tag", message.Header.Tag).
@@ -104,6 +107,7 @@ func (s *SQLCommon) attemptMessageUpdate(ctx context.Context, tx *dbsql.TXWrappe
 			Set("tx_parent_id", txParentID).
 			Set("batch_id", message.BatchID).
 			Set("idempotency_key", message.IdempotencyKey).
+			Set("send_after", message.SendAfter).
 			Where(sq.Eq{
 				"id":              message.Header.ID,
 				"hash":            message.Hash,
@@ -147,6 +151,7 @@ func (s *SQLCommon) setMessageInsertValues(query sq.InsertBuilder, message *core
 		txParentID,
 		message.BatchID,
 		message.IdempotencyKey,
+		message.SendAfter,
 	)
 }
 
@@ -461,6 +466,7 @@ func (s *SQLCommon) msgResult(ctx context.Context, row *sql.Rows) (*core.Message
 		&txParent.ID,
 		&msg.BatchID,
 		&msg.IdempotencyKey,
+		&msg.SendAfter,
 		// Must be added to the list of columns in all selects
 		&msg.Sequence,
 	)
@@ -596,13 +602,25 @@ func (s *SQLCommon) queryBatchIDs(ctx context.Context, query sq.SelectBuilder) (
 }
 
 func (s *SQLCommon) GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Message, fr *ffapi.FilterResult, err error) {
+	return s.getMessages(ctx, namespace, filter, sq.Eq{"namespace_local": namespace})
+}
+
+func (s *SQLCommon) SearchMessages(ctx context.Context, namespace string, filter ffapi.Filter, valueQuery *database.DataValueQuery) (message []*core.Message, fr *ffapi.FilterResult, err error) {
+	matchingData := sq.Select("md.message_id").From("messages_data AS md").
+		Join("data AS d ON d.id = md.data_id").
+		Where(sq.Eq{"md.namespace": namespace}).
+		Where(s.dataValueConditions("d.value", valueQuery))
+	return s.getMessages(ctx, namespace, filter, sq.Eq{"namespace_local": namespace}, sq.Expr("id IN (?)", matchingData))
+}
+
+func (s *SQLCommon) getMessages(ctx context.Context, namespace string, filter ffapi.Filter, preconditions ...sq.Sqlizer) (message []*core.Message, fr *ffapi.FilterResult, err error) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, s.SequenceColumn())
 	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(messagesTable), filter, msgFilterFieldMap,
 		[]interface{}{
 			&ffapi.SortField{Field: "confirmed", Descending: true, Nulls: ffapi.NullsFirst},
 			&ffapi.SortField{Field: "created", Descending: true},
-		}, sq.Eq{"namespace_local": namespace})
+		}, preconditions...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -656,3 +674,29 @@ func (s *SQLCommon) UpdateMessages(ctx context.Context, namespace string, filter
 
 	return s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteMessages(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, messagesDataJoinTable, tx, sq.Delete(messagesDataJoinTable).Where(sq.Eq{
+		"namespace":  namespace,
+		"message_id": ids,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	err = s.DeleteTx(ctx, messagesTable, tx, sq.Delete(messagesTable).Where(sq.Eq{
+		"namespace_local": namespace,
+		"id":              ids,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
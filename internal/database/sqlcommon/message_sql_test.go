@@ -124,6 +124,7 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		Confirmed:      fftypes.Now(),
 		BatchID:        bid,
 		IdempotencyKey: "myBusinessIdentifier",
+		SendAfter:      fftypes.Now(),
 		Data: []*core.DataRef{
 			{ID: dataID1, Hash: rand1},
 			{ID: dataID2, Hash: rand2}, // Note the data refs cannot change, as it would affect the hash, and the hash is immutable
@@ -167,6 +168,7 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		fb.Eq("idempotencykey", msgUpdated.IdempotencyKey),
 		fb.Gt("created", "0"),
 		fb.Gt("confirmed", "0"),
+		fb.Gt("sendafter", "0"),
 	)
 	msgs, res, err := s.GetMessages(ctx, "ns12345", filter.Count(true))
 	assert.NoError(t, err)
@@ -251,6 +253,57 @@ func TestUpsertE2EWithDB(t *testing.T) {
 	s.callbacks.AssertExpectations(t)
 }
 
+func TestSearchMessagesWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+	newMessage := func(data ...*core.Data) *core.Message {
+		msg := &core.Message{
+			LocalNamespace: "ns1",
+			Header: core.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				Type:      core.MessageTypeBroadcast,
+				Namespace: "ns1",
+				Created:   fftypes.Now(),
+				DataHash:  fftypes.NewRandB32(),
+			},
+			Hash: fftypes.NewRandB32(),
+		}
+		for _, d := range data {
+			msg.Data = append(msg.Data, &core.DataRef{ID: d.ID, Hash: d.Hash})
+		}
+		err := s.UpsertMessage(ctx, msg, database.UpsertOptimizationNew)
+		assert.NoError(t, err)
+		return msg
+	}
+	acme := newSearchTestData(t, s, `{"customer":"acme"}`)
+	globex := newSearchTestData(t, s, `{"customer":"globex"}`)
+	msg1 := newMessage(acme, globex)
+	msg2 := newMessage(globex)
+	newMessage()
+
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	msgs, _, err := s.SearchMessages(ctx, "ns1", fb.And(), &database.DataValueQuery{
+		Matches: []*database.JSONPathMatch{{Path: []string{"customer"}, Value: fftypes.JSONAnyPtr(`"acme"`)}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, msg1.Header.ID, msgs[0].Header.ID)
+
+	msgs, _, err = s.SearchMessages(ctx, "ns1", fb.And().Sort("sequence"), &database.DataValueQuery{Search: "GLOBEX"})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, msg1.Header.ID, msgs[0].Header.ID)
+	assert.Equal(t, msg2.Header.ID, msgs[1].Header.ID)
+
+	msgs, _, err = s.SearchMessages(ctx, "ns2", fb.And(), &database.DataValueQuery{Search: "globex"})
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+}
+
 func TestUpsertMessageFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -576,7 +629,7 @@ func TestGetMessageByIDLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", 0))
+		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", nil, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetMessageByID(context.Background(), "ns1", msgID)
 	assert.Regexp(t, "FF00176", err)
@@ -599,6 +652,16 @@ func TestGetMessagesQueryFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestSearchMessagesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .* FROM messages WHERE .*id IN \\(SELECT md.message_id FROM messages_data AS md JOIN data AS d ON d.id = md.data_id .*LOWER\\(d.value\\) LIKE").
+		WillReturnError(fmt.Errorf("pop"))
+	f := database.MessageQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.SearchMessages(context.Background(), "ns1", f, &database.DataValueQuery{Search: "acme"})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetMessagesForDataBadQuery(t *testing.T) {
 	s, mock := newMockProvider().init()
 	f := database.MessageQueryFactory.NewFilter(context.Background()).Eq("!wrong", "")
@@ -623,7 +686,7 @@ func TestGetMessagesLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", 0))
+		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", nil, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.MessageQueryFactory.NewFilter(context.Background()).Gt("confirmed", "0")
 	_, _, err := s.GetMessages(context.Background(), "ns1", f)
@@ -709,3 +772,87 @@ func TestGetBatchIDsForMessagesScanFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDeleteMessagesAndUnreferencedData(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionData, core.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	data1 := &core.Data{ID: fftypes.NewUUID(), Namespace: "ns1", Hash: fftypes.NewRandB32(), Created: fftypes.Now()}
+	data2 := &core.Data{ID: fftypes.NewUUID(), Namespace: "ns1", Hash: fftypes.NewRandB32(), Created: fftypes.Now()}
+	assert.NoError(t, s.UpsertData(ctx, data1, database.UpsertOptimizationNew))
+	assert.NoError(t, s.UpsertData(ctx, data2, database.UpsertOptimizationNew))
+
+	// msg1 and msg2 share data1, and only msg1 refers to data2
+	msg1 := &core.Message{
+		Header:         core.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Type: core.MessageTypeBroadcast, Created: fftypes.Now(), DataHash: fftypes.NewRandB32()},
+		LocalNamespace: "ns1",
+		Hash:           fftypes.NewRandB32(),
+		Data:           core.DataRefs{{ID: data1.ID, Hash: data1.Hash}, {ID: data2.ID, Hash: data2.Hash}},
+	}
+	msg2 := &core.Message{
+		Header:         core.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Type: core.MessageTypeBroadcast, Created: fftypes.Now(), DataHash: fftypes.NewRandB32()},
+		LocalNamespace: "ns1",
+		Hash:           fftypes.NewRandB32(),
+		Data:           core.DataRefs{{ID: data1.ID, Hash: data1.Hash}},
+	}
+	assert.NoError(t, s.UpsertMessage(ctx, msg1, database.UpsertOptimizationNew))
+	assert.NoError(t, s.UpsertMessage(ctx, msg2, database.UpsertOptimizationNew))
+
+	err := s.DeleteMessages(ctx, "ns1", []*fftypes.UUID{msg1.Header.ID})
+	assert.NoError(t, err)
+	err = s.DeleteUnreferencedData(ctx, "ns1", []*fftypes.UUID{data1.ID, data2.ID})
+	assert.NoError(t, err)
+
+	msgRead, err := s.GetMessageByID(ctx, "ns1", msg1.Header.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, msgRead)
+	msgRead, err = s.GetMessageByID(ctx, "ns1", msg2.Header.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, msgRead)
+
+	dataRead, err := s.GetDataByID(ctx, "ns1", data1.ID, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, dataRead)
+	dataRead, err = s.GetDataByID(ctx, "ns1", data2.ID, false)
+	assert.NoError(t, err)
+	assert.Nil(t, dataRead)
+
+	// Nothing left to delete
+	err = s.DeleteMessages(ctx, "ns1", []*fftypes.UUID{msg1.Header.ID})
+	assert.NoError(t, err)
+	err = s.DeleteUnreferencedData(ctx, "ns1", []*fftypes.UUID{data2.ID})
+	assert.NoError(t, err)
+}
+
+func TestDeleteMessagesFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteMessages(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessagesFailDeleteRefs(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessages(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMessagesFailDeleteMessages(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteMessages(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -45,12 +45,23 @@ var (
 		"input",
 		"output",
 		"retry_id",
+		"retry_depth",
+		"retry_parent_id",
+		"last_error",
+		"retry_history",
+		"progress",
+		"retry_at",
 	}
 	opFilterFieldMap = map[string]string{
-		"tx":     "tx_id",
-		"type":   "optype",
-		"status": "opstatus",
-		"retry":  "retry_id",
+		"tx":           "tx_id",
+		"type":         "optype",
+		"status":       "opstatus",
+		"retry":        "retry_id",
+		"retrydepth":   "retry_depth",
+		"retryparent":  "retry_parent_id",
+		"lasterror":    "last_error",
+		"retryhistory": "retry_history",
+		"retryat":      "retry_at",
 	}
 )
 
@@ -70,6 +81,12 @@ func (s *SQLCommon) setOperationInsertValues(query sq.InsertBuilder, operation *
 		operation.Input,
 		operation.Output,
 		operation.Retry,
+		operation.RetryDepth,
+		operation.RetryParent,
+		operation.LastError,
+		operation.RetryHistory,
+		operation.Progress,
+		operation.RetryAt,
 	)
 }
 
@@ -156,6 +173,12 @@ func (s *SQLCommon) opResult(ctx context.Context, row *sql.Rows) (*core.Operatio
 		&op.Input,
 		&op.Output,
 		&op.Retry,
+		&op.RetryDepth,
+		&op.RetryParent,
+		&op.LastError,
+		&op.RetryHistory,
+		&op.Progress,
+		&op.RetryAt,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, operationsTable)
@@ -247,3 +270,21 @@ func (s *SQLCommon) UpdateOperation(ctx context.Context, ns string, id *fftypes.
 	}
 	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) DeleteOperations(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, operationsTable, tx, sq.Delete(operationsTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        ids,
+	}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
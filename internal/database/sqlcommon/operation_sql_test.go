@@ -49,6 +49,14 @@ func TestOperationE2EWithDB(t *testing.T) {
 		Output:      fftypes.JSONObject{"some": "output-info"},
 		Created:     fftypes.Now(),
 		Updated:     fftypes.Now(),
+		RetryDepth:  2,
+		RetryParent: fftypes.NewUUID(),
+		LastError:   &core.OperationError{StatusCode: 500, Body: `{"error":"pop"}`},
+		RetryHistory: core.OperationRetryHistory{
+			{Operation: fftypes.NewUUID(), Failed: fftypes.Now(), Error: "pop", Outcome: core.OpRetryOutcomeRetry, RetryAt: fftypes.Now()},
+		},
+		Progress: &core.TransferProgress{Transferred: 1024, Size: 4096, Updated: fftypes.Now()},
+		RetryAt:  fftypes.Now(),
 	}
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", operationID).Return()
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeUpdated, "ns1", operationID).Return()
@@ -65,6 +73,7 @@ func TestOperationE2EWithDB(t *testing.T) {
 	operationJson, _ := json.Marshal(operation)
 	operationReadJson, _ := json.Marshal(operationRead)
 	assert.Equal(t, string(operationJson), string(operationReadJson))
+	assert.Equal(t, operation.LastError, operationRead.LastError)
 
 	// Query back the operation (by query filter)
 	fb := database.OperationQueryFactory.NewFilter(ctx)
@@ -77,6 +86,9 @@ func TestOperationE2EWithDB(t *testing.T) {
 		fb.Eq("plugin", operation.Plugin),
 		fb.Gt("created", 0),
 		fb.Gt("updated", 0),
+		fb.Gte("retrydepth", 2),
+		fb.Eq("retryparent", operation.RetryParent),
+		fb.Gt("retryat", 0),
 	)
 	operations, res, err := s.GetOperations(ctx, "ns1", filter.Count(true))
 	assert.NoError(t, err)
@@ -100,9 +112,21 @@ func TestOperationE2EWithDB(t *testing.T) {
 	update := database.OperationQueryFactory.NewUpdate(ctx).S()
 	update.Set("status", core.OpStatusFailed)
 	update.Set("error", errMsg)
+	update.Set("lasterror", fftypes.JSONAnyPtr(`{"statusCode":400,"code":"FF23021"}`))
+	update.Set("retryhistory", fftypes.JSONAnyPtr(`[{"operation":"`+operationID.String()+`","error":"FF10143","outcome":"terminal"}]`))
+	update.Set("progress", fftypes.JSONAnyPtr(`{"transferred":2048,"size":4096}`))
+	update.Set("retryat", nil)
 	updated, err := s.UpdateOperation(ctx, operation.Namespace, operation.ID, nil, update)
 	assert.True(t, updated)
 	assert.NoError(t, err)
+	operationRead, err = s.GetOperationByID(ctx, "ns1", operationID)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.OperationError{StatusCode: 400, Code: "FF23021"}, operationRead.LastError)
+	assert.Equal(t, core.OperationRetryHistory{
+		{Operation: operationID, Error: "FF10143", Outcome: core.OpRetryOutcomeTerminal},
+	}, operationRead.RetryHistory)
+	assert.Equal(t, &core.TransferProgress{Transferred: 2048, Size: 4096}, operationRead.Progress)
+	assert.Nil(t, operationRead.RetryAt)
 
 	// Update not found
 	updateFilter := fb.And(fb.Eq("status", core.OpStatusPending))
@@ -110,6 +134,16 @@ func TestOperationE2EWithDB(t *testing.T) {
 	assert.False(t, updated)
 	assert.NoError(t, err)
 
+	// Link a retry, only while no retry has been linked
+	retryFilter := fb.And(fb.Eq("retry", nil))
+	retryUpdate := database.OperationQueryFactory.NewUpdate(ctx).Set("retry", fftypes.NewUUID())
+	updated, err = s.UpdateOperation(ctx, operation.Namespace, operation.ID, retryFilter, retryUpdate)
+	assert.True(t, updated)
+	assert.NoError(t, err)
+	updated, err = s.UpdateOperation(ctx, operation.Namespace, operation.ID, retryFilter, retryUpdate)
+	assert.False(t, updated)
+	assert.NoError(t, err)
+
 	// Test find updated value
 	filter = fb.And(
 		fb.Eq("id", operation.ID.String()),
@@ -306,3 +340,46 @@ func TestInsertOperationsSingleRowFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 	s.callbacks.AssertExpectations(t)
 }
+
+func TestDeleteOperations(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	op1 := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Transaction: fftypes.NewUUID(), Type: core.OpTypeBlockchainPinBatch, Status: core.OpStatusSucceeded, Created: fftypes.Now()}
+	op2 := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Transaction: fftypes.NewUUID(), Type: core.OpTypeBlockchainPinBatch, Status: core.OpStatusPending, Created: fftypes.Now()}
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", op1.ID).Return()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", op2.ID).Return()
+	assert.NoError(t, s.InsertOperation(ctx, op1))
+	assert.NoError(t, s.InsertOperation(ctx, op2))
+
+	err := s.DeleteOperations(ctx, "ns1", []*fftypes.UUID{op1.ID})
+	assert.NoError(t, err)
+	err = s.DeleteOperations(ctx, "ns1", []*fftypes.UUID{op1.ID})
+	assert.NoError(t, err)
+
+	opRead, err := s.GetOperationByID(ctx, "ns1", op1.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, opRead)
+	opRead, err = s.GetOperationByID(ctx, "ns1", op2.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, opRead)
+}
+
+func TestDeleteOperationsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteOperations(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationsFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteOperations(context.Background(), "ns1", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,136 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	opNotificationColumns = []string{
+		"id",
+		"namespace",
+		"operation_id",
+		"url",
+		"secret_name",
+		"inline_secret",
+		"created",
+	}
+	opNotificationFilterFieldMap = map[string]string{
+		"operation": "operation_id",
+	}
+)
+
+const opNotificationsTable = "opnotifications"
+
+func (s *SQLCommon) InsertOperationNotification(ctx context.Context, notification *core.OperationNotification) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, opNotificationsTable, tx,
+		sq.Insert(opNotificationsTable).
+			Columns(opNotificationColumns...).
+			Values(
+				notification.ID,
+				notification.Namespace,
+				notification.Operation,
+				notification.URL,
+				notification.SecretName,
+				notification.InlineSecret,
+				notification.Created,
+			),
+		nil, // no change events for operation notifications
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) opNotificationResult(ctx context.Context, row *sql.Rows) (*core.OperationNotification, error) {
+	notification := core.OperationNotification{}
+	err := row.Scan(
+		&notification.ID,
+		&notification.Namespace,
+		&notification.Operation,
+		&notification.URL,
+		&notification.SecretName,
+		&notification.InlineSecret,
+		&notification.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, opNotificationsTable)
+	}
+	return &notification, nil
+}
+
+func (s *SQLCommon) GetOperationNotifications(ctx context.Context, namespace string, filter ffapi.Filter) (notifications []*core.OperationNotification, fr *ffapi.FilterResult, err error) {
+
+	query, fop, fi, err := s.FilterSelect(
+		ctx, "", sq.Select(opNotificationColumns...).From(opNotificationsTable),
+		filter, opNotificationFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, opNotificationsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	notifications = []*core.OperationNotification{}
+	for rows.Next() {
+		n, err := s.opNotificationResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, s.QueryRes(ctx, opNotificationsTable, tx, fop, nil, fi), err
+
+}
+
+func (s *SQLCommon) DeleteOperationNotification(ctx context.Context, namespace string, id *fftypes.UUID) (err error) {
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, opNotificationsTable, tx, sq.Delete(opNotificationsTable).Where(sq.Eq{
+		"id": id, "namespace": namespace,
+	}), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
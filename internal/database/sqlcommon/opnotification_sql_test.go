@@ -0,0 +1,128 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationNotificationsE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Register a new notification
+	notification := &core.OperationNotification{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Operation:  fftypes.NewUUID(),
+		URL:        "https://example.com/hook",
+		SecretName: "hooks",
+		Created:    fftypes.Now(),
+	}
+	err := s.InsertOperationNotification(ctx, notification)
+	assert.NoError(t, err)
+
+	// Query back the notification by operation
+	fb := database.OperationNotificationQueryFactory.NewFilter(ctx)
+	notifications, res, err := s.GetOperationNotifications(ctx, "ns1", fb.Eq("operation", notification.Operation).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	assert.Equal(t, "hooks", notifications[0].SecretName)
+	notificationJson, _ := json.Marshal(&notification)
+	notificationReadJson, _ := json.Marshal(&notifications[0])
+	assert.Equal(t, string(notificationJson), string(notificationReadJson))
+
+	// Delete it once delivered
+	err = s.DeleteOperationNotification(ctx, "ns1", notification.ID)
+	assert.NoError(t, err)
+	notifications, _, err = s.GetOperationNotifications(ctx, "ns1", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+}
+
+func TestInsertOperationNotificationFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertOperationNotification(context.Background(), &core.OperationNotification{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertOperationNotificationFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertOperationNotification(context.Background(), &core.OperationNotification{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationNotificationsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.OperationNotificationQueryFactory.NewFilter(context.Background()).Eq("url", "")
+	_, _, err := s.GetOperationNotifications(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationNotificationsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.OperationNotificationQueryFactory.NewFilter(context.Background()).Eq("url", map[bool]bool{true: false})
+	_, _, err := s.GetOperationNotifications(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*type", err)
+}
+
+func TestGetOperationNotificationsReadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.OperationNotificationQueryFactory.NewFilter(context.Background()).Eq("url", "")
+	_, _, err := s.GetOperationNotifications(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationNotificationBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteOperationNotification(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteOperationNotificationFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteOperationNotification(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -35,6 +35,7 @@ import (
 type mockProvider struct {
 	SQLCommon
 	callbacks    *databasemocks.Callbacks
+	dialect      *Dialect
 	capabilities *database.Capabilities
 	config       config.Section
 
@@ -53,6 +54,7 @@ func newMockProvider() *mockProvider {
 	conf := config.RootSection("unittest.db")
 	conf.AddKnownKey("url", "test")
 	mp := &mockProvider{
+		dialect:      &Dialect{},
 		capabilities: &database.Capabilities{},
 		callbacks:    &databasemocks.Callbacks{},
 		config:       conf,
@@ -66,7 +68,7 @@ func newMockProvider() *mockProvider {
 
 // init is a convenience to init for tests that aren't testing init itself
 func (mp *mockProvider) init() (*mockProvider, sqlmock.Sqlmock) {
-	_ = mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	_ = mp.Init(context.Background(), mp, mp.dialect, mp.config, mp.capabilities)
 	mp.SetHandler(database.GlobalHandler, mp.callbacks)
 	return mp, mp.mdb
 }
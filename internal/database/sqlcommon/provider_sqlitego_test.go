@@ -64,7 +64,7 @@ func newSQLiteTestProvider(t *testing.T) (*sqliteGoTestProvider, func()) {
 	tp.config.Set(SQLConfMigrationsDirectory, "../../../db/migrations/sqlite")
 	tp.config.Set(SQLConfMaxConnections, 1)
 
-	err = tp.Init(context.Background(), tp, tp.config, tp.capabilities)
+	err = tp.Init(context.Background(), tp, &Dialect{}, tp.config, tp.capabilities)
 	assert.NoError(tp.t, err)
 	tp.SetHandler(database.GlobalHandler, tp.callbacks)
 
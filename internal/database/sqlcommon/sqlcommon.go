@@ -23,6 +23,8 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/dbsql"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 
@@ -32,6 +34,7 @@ import (
 
 type SQLCommon struct {
 	dbsql.Database
+	dialect      *Dialect
 	capabilities *database.Capabilities
 	callbacks    callbacks
 }
@@ -77,7 +80,8 @@ func (cb *callbacks) HashCollectionNSEvent(resType database.HashCollectionNS, ev
 	}
 }
 
-func (s *SQLCommon) Init(ctx context.Context, provider dbsql.Provider, config config.Section, capabilities *database.Capabilities) (err error) {
+func (s *SQLCommon) Init(ctx context.Context, provider dbsql.Provider, dialect *Dialect, config config.Section, capabilities *database.Capabilities) (err error) {
+	s.dialect = dialect
 	s.capabilities = capabilities
 	return s.Database.Init(ctx, provider, config)
 }
@@ -96,3 +100,10 @@ func (s *SQLCommon) SetHandler(namespace string, handler database.Callbacks) {
 }
 
 func (s *SQLCommon) Capabilities() *database.Capabilities { return s.capabilities }
+
+func (s *SQLCommon) Ping(ctx context.Context) error {
+	if err := s.DB().PingContext(ctx); err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgDBQueryFailed)
+	}
+	return nil
+}
@@ -46,6 +46,22 @@ func TestMigrationUpDown(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestPing(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+
+	err := s.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPingFail(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	cleanup()
+
+	err := s.Ping(context.Background())
+	assert.Regexp(t, "FF10115", err)
+}
+
 func TestTXConcurrency(t *testing.T) {
 	s, cleanup := newSQLiteTestProvider(t)
 	defer cleanup()
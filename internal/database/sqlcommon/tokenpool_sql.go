@@ -54,6 +54,7 @@ var (
 		"methods",
 		"published",
 		"plugin_data",
+		"backfill",
 	}
 	tokenPoolFilterFieldMap = map[string]string{
 		"message":         "message_id",
@@ -95,6 +96,7 @@ func (s *SQLCommon) attemptTokenPoolUpdate(ctx context.Context, tx *dbsql.TXWrap
 			Set("methods", pool.Methods).
 			Set("published", pool.Published).
 			Set("plugin_data", pool.PluginData).
+			Set("backfill", pool.Backfill).
 			Where(sq.Eq{"id": pool.ID}),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenPools, core.ChangeEventTypeUpdated, pool.Namespace, pool.ID)
@@ -133,6 +135,7 @@ func (s *SQLCommon) setTokenPoolInsertValues(query sq.InsertBuilder, pool *core.
 		pool.Methods,
 		pool.Published,
 		pool.PluginData,
+		pool.Backfill,
 	)
 }
 
@@ -254,6 +257,7 @@ func (s *SQLCommon) tokenPoolResult(ctx context.Context, row *sql.Rows) (*core.T
 		&pool.Methods,
 		&pool.Published,
 		&pool.PluginData,
+		&pool.Backfill,
 	)
 	if iface.ID != nil {
 		pool.Interface = &iface
@@ -61,6 +61,9 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 			ID: fftypes.NewUUID(),
 		},
 		InterfaceFormat: "abi",
+		Backfill: &core.TokenPoolBackfill{
+			FromBlock: "100",
+		},
 	}
 
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenPools, core.ChangeEventTypeCreated, "ns1", poolID, mock.Anything).
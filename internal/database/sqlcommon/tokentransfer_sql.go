@@ -40,7 +40,7 @@ var (
 		"uri",
 		"connector",
 		"namespace",
-		"key",
+		quoted("key"),
 		"from_key",
 		"to_key",
 		"amount",
@@ -65,6 +65,7 @@ var (
 		"tx.type":         "tx_type",
 		"tx.id":           "tx_id",
 		"blockchainevent": "blockchain_event",
+		"key":             quoted("key"),
 	}
 )
 
@@ -38,7 +38,9 @@ var (
 		"vtype",
 		"namespace",
 		"value",
+		"message",
 		"created",
+		"deprecated",
 	}
 	verifierFilterFieldMap = map[string]string{
 		"type": "vtype",
@@ -53,6 +55,7 @@ func (s *SQLCommon) attemptVerifierUpdate(ctx context.Context, tx *dbsql.TXWrapp
 			Set("identity", verifier.Identity).
 			Set("vtype", verifier.Type).
 			Set("value", verifier.Value).
+			Set("deprecated", verifier.Deprecated).
 			Where(sq.Eq{
 				"hash": verifier.Hash,
 			}),
@@ -72,7 +75,9 @@ func (s *SQLCommon) attemptVerifierInsert(ctx context.Context, tx *dbsql.TXWrapp
 				verifier.Type,
 				verifier.Namespace,
 				verifier.Value,
+				verifier.Message,
 				verifier.Created,
+				verifier.Deprecated,
 			),
 		func() {
 			s.callbacks.HashCollectionNSEvent(database.CollectionVerifiers, core.ChangeEventTypeCreated, verifier.Namespace, verifier.Hash)
@@ -135,7 +140,9 @@ func (s *SQLCommon) verifierResult(ctx context.Context, row *sql.Rows) (*core.Ve
 		&verifier.Type,
 		&verifier.Namespace,
 		&verifier.Value,
+		&verifier.Message,
 		&verifier.Created,
+		&verifier.Deprecated,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, verifiersTable)
@@ -45,6 +45,7 @@ func TestVerifiersE2EWithDB(t *testing.T) {
 			Type:  core.VerifierTypeEthAddress,
 			Value: "0x12345",
 		},
+		Message: fftypes.NewUUID(),
 	}
 	verifier.Seal()
 
@@ -72,6 +73,8 @@ func TestVerifiersE2EWithDB(t *testing.T) {
 			Type:  core.VerifierTypeEthAddress,
 			Value: "0x12345",
 		},
+		Message:    verifier.Message,
+		Deprecated: fftypes.Now(),
 	}
 	verifierUpdated.Seal()
 	err = s.UpsertVerifier(context.Background(), verifierUpdated, database.UpsertOptimizationExisting)
@@ -96,6 +99,30 @@ func TestVerifiersE2EWithDB(t *testing.T) {
 	verifierReadJson, _ = json.Marshal(verifierRes[0])
 	assert.Equal(t, string(verifierJson), string(verifierReadJson))
 
+	// Multiple verifiers can be established for the same identity
+	verifier2 := &core.Verifier{
+		Identity:  verifierUpdated.Identity,
+		Namespace: "ns1",
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x67890",
+		},
+	}
+	verifier2.Seal()
+	s.callbacks.On("HashCollectionNSEvent", database.CollectionVerifiers, core.ChangeEventTypeCreated, "ns1", verifier2.Hash).Return()
+	err = s.UpsertVerifier(ctx, verifier2, database.UpsertOptimizationNew)
+	assert.NoError(t, err)
+
+	// Query back only the active verifiers of the identity
+	filter = fb.And(
+		fb.Eq("identity", verifierUpdated.Identity),
+		fb.Eq("deprecated", nil),
+	)
+	verifierRes, _, err = s.GetVerifiers(ctx, "ns1", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(verifierRes))
+	assert.Equal(t, "0x67890", verifierRes[0].Value)
+
 	s.callbacks.AssertExpectations(t)
 }
 
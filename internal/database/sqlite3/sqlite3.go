@@ -42,6 +42,9 @@ type SQLite3 struct {
 	sqlcommon.SQLCommon
 }
 
+// Sequences are read from the LastInsertId, and conflicts are returned as errors for upserts to handle
+var dialect = &sqlcommon.Dialect{}
+
 func connHook(conn *sqlite3.SQLiteConn) error {
 	_, err := conn.Exec(`
 		PRAGMA case_sensitive_like=ON;
@@ -59,7 +62,7 @@ func (sqlite *SQLite3) Init(ctx context.Context, config config.Section) error {
 			})
 		ffSQLiteRegistered = true
 	}
-	return sqlite.SQLCommon.Init(ctx, sqlite, config, capabilities)
+	return sqlite.SQLCommon.Init(ctx, sqlite, dialect, config, capabilities)
 }
 
 func (sqlite *SQLite3) SetHandler(namespace string, handler database.Callbacks) {
@@ -86,7 +89,7 @@ func (sqlite *SQLite3) Features() dbsql.SQLFeatures {
 }
 
 func (sqlite *SQLite3) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, requestConflictEmptyResult bool) (sq.InsertBuilder, bool) {
-	return insert, false
+	return dialect.ApplyInsertQueryCustomizations(insert, sqlite.SequenceColumn(), requestConflictEmptyResult)
 }
 
 func (sqlite *SQLite3) Open(url string) (*sql.DB, error) {
@@ -40,6 +40,11 @@ const (
 	defaultBackgroundInitialDelay           = "5s"
 	defaultBackgroundRetryFactor            = 2.0
 	defaultBackgroundMaxDelay               = "1m"
+
+	// DataExchangeTransferMaxConcurrent is the number of blob transfers each namespace can have in flight at once
+	DataExchangeTransferMaxConcurrent = "transfer.maxConcurrent"
+	// DataExchangeTransferMaxBandwidth is the total bytes per second each namespace can send in blob transfers
+	DataExchangeTransferMaxBandwidth = "transfer.maxBandwidth"
 )
 
 func (h *FFDX) InitConfig(config config.Section) {
@@ -53,4 +58,6 @@ func (h *FFDX) InitConfig(config config.Section) {
 	config.AddKnownKey(DataExchangeBackgroundStartInitialDelay, defaultBackgroundInitialDelay)
 	config.AddKnownKey(DataExchangeBackgroundStartMaxDelay, defaultBackgroundMaxDelay)
 	config.AddKnownKey(DataExchangeBackgroundStartFactor, defaultBackgroundRetryFactor)
+	config.AddKnownKey(DataExchangeTransferMaxConcurrent, 0)
+	config.AddKnownKey(DataExchangeTransferMaxBandwidth, 0)
 }
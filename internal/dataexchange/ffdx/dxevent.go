@@ -29,18 +29,19 @@ import (
 )
 
 type wsEvent struct {
-	Type      msgType            `json:"type"`
-	EventID   string             `json:"id"`
-	Sender    string             `json:"sender"`
-	Recipient string             `json:"recipient"`
-	RequestID string             `json:"requestId"`
-	Path      string             `json:"path"`
-	Message   string             `json:"message"`
-	Hash      string             `json:"hash"`
-	Size      int64              `json:"size"`
-	Error     string             `json:"error"`
-	Manifest  string             `json:"manifest"`
-	Info      fftypes.JSONObject `json:"info"`
+	Type        msgType            `json:"type"`
+	EventID     string             `json:"id"`
+	Sender      string             `json:"sender"`
+	Recipient   string             `json:"recipient"`
+	RequestID   string             `json:"requestId"`
+	Path        string             `json:"path"`
+	Message     string             `json:"message"`
+	Hash        string             `json:"hash"`
+	Size        int64              `json:"size"`
+	Transferred int64              `json:"transferred"`
+	Error       string             `json:"error"`
+	Manifest    string             `json:"manifest"`
+	Info        fftypes.JSONObject `json:"info"`
 }
 
 type dxEvent struct {
@@ -123,7 +124,12 @@ func (h *FFDX) dispatchEvent(msg *wsEvent) {
 			OnComplete:     e.Ack,
 		})
 		return
+	case blobProgress:
+		h.callbacks.TransferProgress(h.ctx, msg.RequestID, msg.Transferred, msg.Size)
+		e.Ack()
+		return
 	case blobFailed:
+		h.releaseTransfer(h.ctx, msg.RequestID)
 		h.callbacks.OperationUpdate(h.ctx, &core.OperationUpdate{
 			Plugin:         h.Name(),
 			NamespacedOpID: msg.RequestID,
@@ -134,6 +140,7 @@ func (h *FFDX) dispatchEvent(msg *wsEvent) {
 		})
 		return
 	case blobDelivered:
+		h.releaseTransfer(h.ctx, msg.RequestID)
 		status := core.OpStatusSucceeded
 		if h.capabilities.Manifest {
 			status = core.OpStatusPending
@@ -147,6 +154,7 @@ func (h *FFDX) dispatchEvent(msg *wsEvent) {
 		})
 		return
 	case blobAcknowledged:
+		h.releaseTransfer(h.ctx, msg.RequestID)
 		h.callbacks.OperationUpdate(h.ctx, &core.OperationUpdate{
 			Plugin:         h.Name(),
 			NamespacedOpID: msg.RequestID,
@@ -33,6 +33,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
 )
@@ -54,6 +55,8 @@ type FFDX struct {
 	retry           *retry.Retry
 	backgroundStart bool
 	backgroundRetry *retry.Retry
+	transfers       *transferQueue
+	transferRate    int64
 }
 
 type dxNode struct {
@@ -65,7 +68,7 @@ type callbacks struct {
 	plugin     *FFDX
 	writeLock  sync.Mutex
 	handlers   map[string]dataexchange.Callbacks
-	opHandlers map[string]core.OperationCallbacks
+	opHandlers map[string]dataexchange.OperationCallbacks
 }
 
 func (cb *callbacks) OperationUpdate(ctx context.Context, update *core.OperationUpdate) {
@@ -78,6 +81,15 @@ func (cb *callbacks) OperationUpdate(ctx context.Context, update *core.Operation
 	}
 }
 
+func (cb *callbacks) TransferProgress(ctx context.Context, nsOpID string, transferred, size int64) {
+	namespace, _, _ := core.ParseNamespacedOpID(ctx, nsOpID)
+	if handler, ok := cb.opHandlers[namespace]; ok {
+		handler.TransferProgress(nsOpID, transferred, size)
+	} else {
+		log.L(ctx).Debugf("No handler found for DX transfer progress '%s'", nsOpID)
+	}
+}
+
 func (cb *callbacks) DXEvent(ctx context.Context, namespace, recipient string, event dataexchange.DXEvent) error {
 	node := cb.plugin.findNode(namespace, recipient)
 	if node != nil {
@@ -123,6 +135,7 @@ const (
 	blobDelivered       msgType = "blob-delivered"
 	blobAcknowledged    msgType = "blob-acknowledged"
 	blobFailed          msgType = "blob-failed"
+	blobProgress        msgType = "blob-progress"
 )
 
 type responseWithRequestID struct {
@@ -143,10 +156,11 @@ type sendMessage struct {
 }
 
 type transferBlob struct {
-	Path      string `json:"path"`
-	Recipient string `json:"recipient"`
-	RequestID string `json:"requestId"`
-	Sender    string `json:"sender"`
+	Path              string `json:"path"`
+	Recipient         string `json:"recipient"`
+	RequestID         string `json:"requestId"`
+	Sender            string `json:"sender"`
+	MaxBytesPerSecond int64  `json:"maxBytesPerSecond,omitempty"`
 }
 
 type wsAck struct {
@@ -164,6 +178,33 @@ type ack struct {
 	manifest string
 }
 
+// connectorError preserves the response from the data exchange, for diagnosing failed operations
+type connectorError struct {
+	err    error
+	detail *core.OperationError
+}
+
+func (ce *connectorError) Error() string {
+	return ce.err.Error()
+}
+
+func (ce *connectorError) ConnectorErrorDetail() *core.OperationError {
+	return ce.detail
+}
+
+// wrapOperationError attaches the status and body of the data exchange response to the error from submitting
+// an operation, so client errors can be told apart from failures of the data exchange
+func wrapOperationError(ctx context.Context, res *resty.Response, err error) error {
+	err = ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDXRESTErr)
+	if res == nil || res.StatusCode() == 0 {
+		return err
+	}
+	return &connectorError{err: err, detail: &core.OperationError{
+		StatusCode: res.StatusCode(),
+		Body:       string(res.Body()),
+	}}
+}
+
 func (h *FFDX) Name() string {
 	return "ffdx"
 }
@@ -175,7 +216,7 @@ func (h *FFDX) Init(ctx context.Context, cancelCtx context.CancelFunc, config co
 	h.callbacks = callbacks{
 		plugin:     h,
 		handlers:   make(map[string]dataexchange.Callbacks),
-		opHandlers: make(map[string]core.OperationCallbacks),
+		opHandlers: make(map[string]dataexchange.OperationCallbacks),
 	}
 	h.needsInit = config.GetBool(DataExchangeInitEnabled)
 	h.nodes = make(map[string]*dxNode)
@@ -192,6 +233,18 @@ func (h *FFDX) Init(ctx context.Context, cancelCtx context.CancelFunc, config co
 	if err != nil {
 		return err
 	}
+	tracing.InstrumentClient(h.client)
+
+	// The bandwidth of each namespace is shared evenly between the transfers it can have in flight
+	maxConcurrent := config.GetInt(DataExchangeTransferMaxConcurrent)
+	maxBandwidth := config.GetByteSize(DataExchangeTransferMaxBandwidth)
+	if maxBandwidth > 0 {
+		if maxConcurrent <= 0 {
+			return i18n.NewError(ctx, coremsgs.MsgDXTransferBandwidthNoConcurrency)
+		}
+		h.transferRate = maxBandwidth / int64(maxConcurrent)
+	}
+	h.transfers = newTransferQueue(maxConcurrent)
 
 	h.capabilities = &dataexchange.Capabilities{
 		Manifest: config.GetBool(DataExchangeManifestEnabled),
@@ -238,7 +291,7 @@ func (h *FFDX) SetHandler(networkNamespace, nodeName string, handler dataexchang
 	}
 }
 
-func (h *FFDX) SetOperationHandler(namespace string, handler core.OperationCallbacks) {
+func (h *FFDX) SetOperationHandler(namespace string, handler dataexchange.OperationCallbacks) {
 	h.callbacks.writeLock.Lock()
 	defer h.callbacks.writeLock.Unlock()
 	if handler == nil {
@@ -273,6 +326,15 @@ func (h *FFDX) Capabilities() *dataexchange.Capabilities {
 	return h.capabilities
 }
 
+func (h *FFDX) Ping(ctx context.Context) error {
+	res, err := h.client.R().SetContext(ctx).
+		Get("/api/v1/id")
+	if err != nil || !res.IsSuccess() {
+		return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDXRESTErr)
+	}
+	return nil
+}
+
 func (h *FFDX) beforeConnect(ctx context.Context, w wsclient.WSClient) error {
 	h.initMutex.Lock()
 	defer h.initMutex.Unlock()
@@ -422,7 +484,7 @@ func (h *FFDX) SendMessage(ctx context.Context, nsOpID string, peer, sender ffty
 		SetResult(&responseData).
 		Post("/api/v1/messages")
 	if err != nil || !res.IsSuccess() {
-		return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDXRESTErr)
+		return wrapOperationError(ctx, res, err)
 	}
 	return nil
 }
@@ -432,22 +494,68 @@ func (h *FFDX) TransferBlob(ctx context.Context, nsOpID string, peer, sender fft
 		return err
 	}
 
+	qt := &queuedTransfer{
+		nsOpID: nsOpID,
+		submit: func(ctx context.Context) error {
+			return h.submitTransfer(ctx, nsOpID, peer, sender, payloadRef)
+		},
+	}
+	if !h.transfers.start(ctx, qt) {
+		// The operation stays pending until a slot frees up, and the transfer is submitted
+		log.L(ctx).Infof("Queued DX transfer '%s' until a transfer slot is free", nsOpID)
+		return nil
+	}
+	if err := qt.submit(ctx); err != nil {
+		h.releaseTransfer(ctx, nsOpID)
+		return err
+	}
+	return nil
+}
+
+func (h *FFDX) submitTransfer(ctx context.Context, nsOpID string, peer, sender fftypes.JSONObject, payloadRef string) error {
 	var responseData responseWithRequestID
 	res, err := h.client.R().SetContext(ctx).
 		SetBody(&transferBlob{
-			Path:      fmt.Sprintf("/%s", payloadRef),
-			Recipient: h.GetPeerID(peer),
-			RequestID: nsOpID,
-			Sender:    h.GetPeerID(sender),
+			Path:              fmt.Sprintf("/%s", payloadRef),
+			Recipient:         h.GetPeerID(peer),
+			RequestID:         nsOpID,
+			Sender:            h.GetPeerID(sender),
+			MaxBytesPerSecond: h.transferRate,
 		}).
 		SetResult(&responseData).
 		Post("/api/v1/transfers")
 	if err != nil || !res.IsSuccess() {
-		return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDXRESTErr)
+		return wrapOperationError(ctx, res, err)
 	}
 	return nil
 }
 
+// releaseTransfer gives back the transfer slot held by an operation, and submits the next queued
+// transfer in the namespace (if any) in the background
+func (h *FFDX) releaseTransfer(ctx context.Context, nsOpID string) {
+	if next := h.transfers.release(ctx, nsOpID); next != nil {
+		go h.submitQueuedTransfer(next)
+	}
+}
+
+// submitQueuedTransfer submits a transfer that was waiting for a slot. Its operation has already been
+// reported as pending, so a failure to submit is reported back through an operation update.
+func (h *FFDX) submitQueuedTransfer(qt *queuedTransfer) {
+	err := qt.submit(h.ctx)
+	if err == nil {
+		return
+	}
+	log.L(h.ctx).Errorf("Failed to submit queued DX transfer '%s': %s", qt.nsOpID, err)
+	h.releaseTransfer(h.ctx, qt.nsOpID)
+	h.callbacks.OperationUpdate(h.ctx, &core.OperationUpdate{
+		Plugin:         h.Name(),
+		NamespacedOpID: qt.nsOpID,
+		Status:         core.OpStatusFailed,
+		ErrorMessage:   err.Error(),
+		OnComplete:     func() {},
+	})
+}
+
 func (h *FFDX) ackLoop() {
 	for {
 		select {
@@ -33,7 +33,6 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
 	"github.com/hyperledger/firefly/internal/coreconfig"
-	"github.com/hyperledger/firefly/mocks/coremocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/wsmocks"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -131,6 +130,32 @@ func TestInitBadTLS(t *testing.T) {
 	assert.Regexp(t, "FF00153", err)
 }
 
+func TestInitBandwidthNoConcurrency(t *testing.T) {
+	coreconfig.Reset()
+	h := &FFDX{}
+	h.InitConfig(utConfig)
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utConfig.Set(DataExchangeTransferMaxBandwidth, "1Mb")
+	ctx, cancel := context.WithCancel(context.Background())
+	err := h.Init(ctx, cancel, utConfig)
+	assert.Regexp(t, "FF10640", err)
+}
+
+func TestInitTransferLimits(t *testing.T) {
+	h, _, _, _, done := newTestFFDX(t, false)
+	defer done()
+	utConfig.Set(DataExchangeBackgroundStart, true)
+	utConfig.Set(DataExchangeTransferMaxConcurrent, 4)
+	utConfig.Set(DataExchangeTransferMaxBandwidth, "1Mb")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := h.Init(ctx, cancel, utConfig)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, h.transfers.maxConcurrent)
+	assert.Equal(t, int64(256*1024), h.transferRate)
+}
+
 func TestInitMissingURL(t *testing.T) {
 	coreconfig.Reset()
 	h := &FFDX{}
@@ -205,6 +230,28 @@ func TestGetEndpointMissingID(t *testing.T) {
 	assert.Regexp(t, "FF10367", err)
 }
 
+func TestPing(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/id", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"id": "peer1"}))
+
+	err := h.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPingError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/id", httpURL),
+		httpmock.NewStringResponder(500, "pop"))
+
+	err := h.Ping(context.Background())
+	assert.Regexp(t, "FF10229", err)
+}
+
 func TestGetEndpointInfoError(t *testing.T) {
 	h, _, _, httpURL, done := newTestFFDX(t, false)
 	defer done()
@@ -397,6 +444,142 @@ func TestTransferBlobError(t *testing.T) {
 	sender := fftypes.JSONObject{"id": "sender1"}
 	err := h.TransferBlob(context.Background(), "ns1:"+fftypes.NewUUID().String(), peer, sender, "ns1/id1")
 	assert.Regexp(t, "FF10229", err)
+	assert.Equal(t, 500, err.(*connectorError).ConnectorErrorDetail().StatusCode)
+}
+
+func TestTransferBlobLimits(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+	h.transfers = newTransferQueue(1)
+	h.transferRate = 1024
+
+	posted := make(chan *transferBlob, 4)
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfers", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body transferBlob
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			posted <- &body
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{})(req)
+		})
+
+	peer := fftypes.JSONObject{"id": "peer1"}
+	sender := fftypes.JSONObject{"id": "sender1"}
+	op1 := "ns1:" + fftypes.NewUUID().String()
+	op2 := "ns1:" + fftypes.NewUUID().String()
+	err := h.TransferBlob(context.Background(), op1, peer, sender, "ns1/id1")
+	assert.NoError(t, err)
+	body := <-posted
+	assert.Equal(t, op1, body.RequestID)
+	assert.Equal(t, int64(1024), body.MaxBytesPerSecond)
+
+	// A retry of the same transfer does not need another slot
+	err = h.TransferBlob(context.Background(), op1, peer, sender, "ns1/id1")
+	assert.NoError(t, err)
+	assert.Equal(t, op1, (<-posted).RequestID)
+
+	// Other namespaces have their own slots
+	op3 := "ns2:" + fftypes.NewUUID().String()
+	err = h.TransferBlob(context.Background(), op3, peer, sender, "ns2/id1")
+	assert.NoError(t, err)
+	assert.Equal(t, op3, (<-posted).RequestID)
+
+	// The next transfer is queued without blocking, and re-queuing it does not add a second entry
+	err = h.TransferBlob(context.Background(), op2, peer, sender, "ns1/id2")
+	assert.NoError(t, err)
+	err = h.TransferBlob(context.Background(), op2, peer, sender, "ns1/id2")
+	assert.NoError(t, err)
+	assert.Len(t, h.transfers.queued["ns1"], 1)
+
+	// Releasing a slot that is not held does nothing
+	h.releaseTransfer(context.Background(), "ns1:"+fftypes.NewUUID().String())
+	assert.Len(t, h.transfers.queued["ns1"], 1)
+
+	// Releasing the slot submits the queued transfer
+	h.releaseTransfer(context.Background(), op1)
+	assert.Equal(t, op2, (<-posted).RequestID)
+	assert.Empty(t, h.transfers.queued["ns1"])
+	assert.True(t, h.transfers.inFlight["ns1"][op2])
+}
+
+func TestTransferBlobQueueOrder(t *testing.T) {
+	tq := newTransferQueue(1)
+	ctx := context.Background()
+	op1 := "ns1:" + fftypes.NewUUID().String()
+	op2 := "ns1:" + fftypes.NewUUID().String()
+	op3 := "ns1:" + fftypes.NewUUID().String()
+	assert.True(t, tq.start(ctx, &queuedTransfer{nsOpID: op1}))
+	assert.False(t, tq.start(ctx, &queuedTransfer{nsOpID: op2}))
+	assert.False(t, tq.start(ctx, &queuedTransfer{nsOpID: op3}))
+	assert.Equal(t, op2, tq.release(ctx, op1).nsOpID)
+	assert.Equal(t, op3, tq.release(ctx, op2).nsOpID)
+	assert.Nil(t, tq.release(ctx, op3))
+}
+
+func TestTransferBlobUnlimited(t *testing.T) {
+	tq := newTransferQueue(0)
+	assert.True(t, tq.start(context.Background(), &queuedTransfer{nsOpID: "ns1:op1"}))
+	assert.Nil(t, tq.release(context.Background(), "ns1:op1"))
+}
+
+func TestTransferBlobErrorReleasesSlot(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+	h.transfers = newTransferQueue(1)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfers", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	peer := fftypes.JSONObject{"id": "peer1"}
+	sender := fftypes.JSONObject{"id": "sender1"}
+	err := h.TransferBlob(context.Background(), "ns1:"+fftypes.NewUUID().String(), peer, sender, "ns1/id1")
+	assert.Regexp(t, "FF10229", err)
+	assert.Empty(t, h.transfers.inFlight["ns1"])
+}
+
+func TestTransferBlobQueuedSubmitFails(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+	h.transfers = newTransferQueue(1)
+
+	ocb := &dataexchangemocks.OperationCallbacks{}
+	h.SetOperationHandler("ns1", ocb)
+
+	op1 := "ns1:" + fftypes.NewUUID().String()
+	op2 := "ns1:" + fftypes.NewUUID().String()
+	failed := make(chan struct{})
+	ocb.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == op2 &&
+			update.Status == core.OpStatusFailed &&
+			update.ErrorMessage != ""
+	})).Run(func(args mock.Arguments) {
+		args[0].(*core.OperationUpdate).OnComplete()
+		close(failed)
+	}).Return()
+
+	posts := 0
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfers", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			posts++
+			if posts == 1 {
+				return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{})(req)
+			}
+			return httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{})(req)
+		})
+
+	peer := fftypes.JSONObject{"id": "peer1"}
+	sender := fftypes.JSONObject{"id": "sender1"}
+	err := h.TransferBlob(context.Background(), op1, peer, sender, "ns1/id1")
+	assert.NoError(t, err)
+	err = h.TransferBlob(context.Background(), op2, peer, sender, "ns1/id2")
+	assert.NoError(t, err)
+
+	h.releaseTransfer(context.Background(), op1)
+	<-failed
+	h.transfers.mux.Lock()
+	assert.Empty(t, h.transfers.inFlight["ns1"])
+	h.transfers.mux.Unlock()
+
+	ocb.AssertExpectations(t)
 }
 
 func TestBadEvents(t *testing.T) {
@@ -484,7 +667,7 @@ func TestMessageEventsBackgroundStart(t *testing.T) {
 
 	mcb := &dataexchangemocks.Callbacks{}
 	h.SetHandler("ns1", "node1", mcb)
-	ocb := &coremocks.OperationCallbacks{}
+	ocb := &dataexchangemocks.OperationCallbacks{}
 	h.SetOperationHandler("ns1", ocb)
 	h.AddNode(context.Background(), "ns1", "node1", fftypes.JSONObject{"id": "peer1"})
 
@@ -544,7 +727,7 @@ func TestMessageEvents(t *testing.T) {
 
 	mcb := &dataexchangemocks.Callbacks{}
 	h.SetHandler("ns1", "node1", mcb)
-	ocb := &coremocks.OperationCallbacks{}
+	ocb := &dataexchangemocks.OperationCallbacks{}
 	h.SetOperationHandler("ns1", ocb)
 	h.AddNode(context.Background(), "ns1", "node1", fftypes.JSONObject{"id": "peer1"})
 
@@ -609,13 +792,23 @@ func TestBlobEvents(t *testing.T) {
 
 	mcb := &dataexchangemocks.Callbacks{}
 	h.SetHandler("ns1", "node1", mcb)
-	ocb := &coremocks.OperationCallbacks{}
+	ocb := &dataexchangemocks.OperationCallbacks{}
 	h.SetOperationHandler("ns1", ocb)
 	h.AddNode(context.Background(), "ns1", "node1", fftypes.JSONObject{"id": "peer1"})
 
 	err := h.Start()
 	assert.NoError(t, err)
 
+	namespacedID4 := fmt.Sprintf("ns1:%s", fftypes.NewUUID())
+	ocb.On("TransferProgress", namespacedID4, int64(1024), int64(4096)).Return()
+	fromServer <- `{"id":"4","type":"blob-progress","requestID":"` + namespacedID4 + `","transferred":1024,"size":4096}`
+	msg := <-toServer
+	assert.Equal(t, `{"action":"ack","id":"4"}`, string(msg))
+
+	fromServer <- `{"id":"4a","type":"blob-progress","requestID":"ns2:` + fftypes.NewUUID().String() + `","transferred":1024,"size":4096}`
+	msg = <-toServer
+	assert.Equal(t, `{"action":"ack","id":"4a"}`, string(msg))
+
 	namespacedID5 := fmt.Sprintf("ns1:%s", fftypes.NewUUID())
 	ocb.On("OperationUpdate", mock.MatchedBy(func(ev *core.OperationUpdate) bool {
 		return ev.NamespacedOpID == namespacedID5 &&
@@ -624,7 +817,7 @@ func TestBlobEvents(t *testing.T) {
 			ev.Plugin == "ffdx"
 	})).Run(opAcker()).Return(nil)
 	fromServer <- `{"id":"5","type":"blob-failed","requestID":"` + namespacedID5 + `","error":"pop"}`
-	msg := <-toServer
+	msg = <-toServer
 	assert.Equal(t, `{"action":"ack","id":"5"}`, string(msg))
 
 	namespacedID6 := fmt.Sprintf("ns1:%s", fftypes.NewUUID())
@@ -679,7 +872,7 @@ func TestEventsWithManifest(t *testing.T) {
 
 	mcb := &dataexchangemocks.Callbacks{}
 	h.SetHandler("ns1", "node1", mcb)
-	ocb := &coremocks.OperationCallbacks{}
+	ocb := &dataexchangemocks.OperationCallbacks{}
 	h.SetOperationHandler("ns1", ocb)
 
 	namespacedID1 := fmt.Sprintf("ns1:%s", fftypes.NewUUID())
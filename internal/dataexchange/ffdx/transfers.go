@@ -0,0 +1,106 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ffdx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// queuedTransfer is a blob transfer waiting for a slot, along with the function that submits it to DX
+type queuedTransfer struct {
+	nsOpID string
+	submit func(ctx context.Context) error
+}
+
+// transferQueue limits the number of blob transfers each namespace has in flight. A transfer takes a
+// slot when it is submitted to DX, and gives it back when DX reports the transfer has finished.
+// Transfers that arrive while every slot is taken wait in memory, without blocking the caller, and
+// are submitted in order as slots free up. Queued transfers are not persisted - if the node stops
+// they are never submitted, and their operations remain pending until they are retried.
+type transferQueue struct {
+	mux           sync.Mutex
+	maxConcurrent int
+	inFlight      map[string]map[string]bool
+	queued        map[string][]*queuedTransfer
+}
+
+func newTransferQueue(maxConcurrent int) *transferQueue {
+	return &transferQueue{
+		maxConcurrent: maxConcurrent,
+		inFlight:      make(map[string]map[string]bool),
+		queued:        make(map[string][]*queuedTransfer),
+	}
+}
+
+// start takes a slot for the transfer and returns true if it can be submitted now, or queues it
+// and returns false. A transfer for an operation that already holds a slot (such as on a retry)
+// can always be submitted.
+func (tq *transferQueue) start(ctx context.Context, qt *queuedTransfer) bool {
+	if tq.maxConcurrent <= 0 {
+		return true
+	}
+	namespace, _, _ := core.ParseNamespacedOpID(ctx, qt.nsOpID)
+	tq.mux.Lock()
+	defer tq.mux.Unlock()
+	ops := tq.inFlight[namespace]
+	if ops == nil {
+		ops = make(map[string]bool)
+		tq.inFlight[namespace] = ops
+	}
+	if ops[qt.nsOpID] || len(ops) < tq.maxConcurrent {
+		ops[qt.nsOpID] = true
+		return true
+	}
+	for _, waiting := range tq.queued[namespace] {
+		if waiting.nsOpID == qt.nsOpID {
+			waiting.submit = qt.submit
+			return false
+		}
+	}
+	tq.queued[namespace] = append(tq.queued[namespace], qt)
+	return false
+}
+
+// release gives back the slot held by an operation, if it has one. If another transfer in the
+// namespace is waiting, the slot passes straight to it and it is returned for the caller to submit.
+func (tq *transferQueue) release(ctx context.Context, nsOpID string) *queuedTransfer {
+	if tq.maxConcurrent <= 0 {
+		return nil
+	}
+	namespace, _, _ := core.ParseNamespacedOpID(ctx, nsOpID)
+	tq.mux.Lock()
+	defer tq.mux.Unlock()
+	if !tq.inFlight[namespace][nsOpID] {
+		return nil
+	}
+	delete(tq.inFlight[namespace], nsOpID)
+	queued := tq.queued[namespace]
+	if len(queued) == 0 {
+		return nil
+	}
+	next := queued[0]
+	if len(queued) == 1 {
+		delete(tq.queued, namespace)
+	} else {
+		tq.queued[namespace] = queued[1:]
+	}
+	tq.inFlight[namespace][next.nsOpID] = true
+	return next
+}
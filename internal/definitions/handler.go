@@ -91,6 +91,8 @@ func (dh *definitionHandler) HandleDefinitionBroadcast(ctx context.Context, stat
 		return dh.handleIdentityVerificationBroadcast(ctx, state, msg, data)
 	case core.SystemTagIdentityUpdate:
 		return dh.handleIdentityUpdateBroadcast(ctx, state, msg, data)
+	case core.SystemTagIdentityVerifierClaim:
+		return dh.handleIdentityVerifierClaimBroadcast(ctx, state, msg, data)
 	case core.SystemTagDefinePool:
 		return dh.handleTokenPoolBroadcast(ctx, state, msg, data)
 	case core.SystemTagDefineFFI:
@@ -32,6 +32,8 @@ func (dh *definitionHandler) handleDatatypeBroadcast(ctx context.Context, state
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "datatype", msg.Header.ID)
 	}
 	dt.Namespace = dh.namespace.Name
+	// Lifecycle is managed independently by each member, so always starts as active
+	dt.Lifecycle = core.DefinitionLifecycleActive
 	if err := dt.Validate(ctx, true); err != nil {
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedValidateFail, "datatype", dt.ID, err)
 	}
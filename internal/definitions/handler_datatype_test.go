@@ -39,6 +39,7 @@ func TestHandleDefinitionBroadcastDatatypeOk(t *testing.T) {
 		Name:      "name1",
 		Version:   "ver1",
 		Value:     fftypes.JSONAnyPtr(`{}`),
+		Lifecycle: core.DefinitionLifecycleRetired,
 	}
 	dt.Hash = dt.Value.Hash()
 	b, err := json.Marshal(&dt)
@@ -49,7 +50,9 @@ func TestHandleDefinitionBroadcastDatatypeOk(t *testing.T) {
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
-	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
+	dh.mdi.On("UpsertDatatype", mock.Anything, mock.MatchedBy(func(dt *core.Datatype) bool {
+		return dt.Lifecycle == core.DefinitionLifecycleActive
+	}), false).Return(nil)
 	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
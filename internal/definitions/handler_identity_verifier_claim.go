@@ -0,0 +1,136 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (dh *definitionHandler) handleIdentityVerifierClaimBroadcast(ctx context.Context, state *core.BatchState, msg *core.Message, data core.DataArray) (HandlerResult, error) {
+	var claim core.IdentityVerifierClaim
+	if valid := dh.getSystemBroadcastPayload(ctx, msg, data, &claim); !valid {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedBadPayload, "identity verifier claim", msg.Header.ID)
+	}
+	return dh.handleIdentityVerifierClaim(ctx, state, &identityUpdateMsgInfo{
+		ID:     msg.Header.ID,
+		Author: msg.Header.Author,
+	}, &claim)
+}
+
+func (dh *definitionHandler) handleIdentityVerifierClaim(ctx context.Context, state *core.BatchState, msg *identityUpdateMsgInfo, claim *core.IdentityVerifierClaim) (HandlerResult, error) {
+	if err := claim.Identity.Validate(ctx); err != nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.WrapError(ctx, err, coremsgs.MsgDefRejectedValidateFail, "identity verifier claim", claim.Identity.ID)
+	}
+	if claim.Verifier.Type != dh.blockchain.VerifierType() || claim.Verifier.Value == "" {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedValidateFail, "identity verifier claim", claim.Identity.ID)
+	}
+
+	// Get the existing identity (must be a confirmed identity at the point a verifier is claimed)
+	identity, err := dh.identity.CachedIdentityLookupByID(ctx, claim.Identity.ID)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if identity == nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedIdentityNotFound, "identity verifier claim", claim.Identity.ID, claim.Identity.ID)
+	}
+	if identity.Type == core.IdentityTypeNode {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgVerifierClaimNodeIdentity, identity.DID)
+	}
+
+	if dh.multiparty {
+
+		_, retryable, err := dh.identity.VerifyIdentityChain(ctx, identity)
+		if err != nil && retryable {
+			return HandlerResult{Action: core.ActionRetry}, err
+		} else if err != nil {
+			log.L(ctx).Infof("Unable to process identity verifier claim (parked) %s: %s", msg.ID, err)
+			return HandlerResult{Action: core.ActionWait}, nil
+		}
+
+		// The claim must be signed by the identity itself, using one of its existing verifiers.
+		// The message processing has already checked the signing key resolves to the author.
+		if identity.DID != msg.Author {
+			return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedWrongAuthor, "identity verifier claim", claim.Identity.ID, msg.Author)
+		}
+
+	}
+
+	// Check uniqueness of the new verifier - replay of a claim already applied to this identity is idempotent
+	verifier := &core.Verifier{
+		Identity:    identity.ID,
+		Namespace:   identity.Namespace,
+		VerifierRef: claim.Verifier,
+		Message:     msg.ID,
+	}
+	verifier.Seal()
+	verifierLabel := fmt.Sprintf("%s:%s", verifier.Type, verifier.Value)
+	existingVerifier, err := dh.database.GetVerifierByValue(ctx, verifier.Type, identity.Namespace, verifier.Value)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err // retry database errors
+	}
+	if existingVerifier != nil && (!existingVerifier.Identity.Equals(identity.ID) || existingVerifier.Deprecated != nil) {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "identity verifier", verifierLabel, existingVerifier.Identity)
+	}
+
+	// Find the verifiers to deprecate, which must all belong to this identity
+	deprecated := make([]*core.Verifier, 0, len(claim.Deprecate))
+	for _, ref := range claim.Deprecate {
+		if ref.Type == verifier.Type && ref.Value == verifier.Value {
+			return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedValidateFail, "identity verifier claim", claim.Identity.ID)
+		}
+		old, err := dh.database.GetVerifierByValue(ctx, ref.Type, identity.Namespace, ref.Value)
+		if err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err // retry database errors
+		}
+		if old == nil || !old.Identity.Equals(identity.ID) {
+			return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedVerifierNotFound, "identity verifier claim", claim.Identity.ID, fmt.Sprintf("%s:%s", ref.Type, ref.Value))
+		}
+		if old.Deprecated == nil {
+			old.Deprecated = fftypes.Now()
+			deprecated = append(deprecated, old)
+		}
+	}
+
+	if existingVerifier == nil {
+		if err = dh.database.UpsertVerifier(ctx, verifier, database.UpsertOptimizationNew); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+	}
+	for _, old := range deprecated {
+		if err = dh.database.UpsertVerifier(ctx, old, database.UpsertOptimizationExisting); err != nil {
+			return HandlerResult{Action: core.ActionRetry}, err
+		}
+	}
+
+	state.AddFinalize(func(ctx context.Context) error {
+		for _, old := range deprecated {
+			dh.identity.InvalidateVerifierCache(ctx, &old.VerifierRef)
+		}
+		event := core.NewEvent(core.EventTypeIdentityUpdated, identity.Namespace, identity.ID, nil, core.SystemTopicDefinitions)
+		return dh.database.InsertEvent(ctx, event)
+	})
+	return HandlerResult{Action: core.ActionConfirm}, nil
+
+}
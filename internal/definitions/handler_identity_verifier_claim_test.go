@@ -0,0 +1,410 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package definitions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testIdentityVerifierClaim(t *testing.T) (*core.Identity, *core.Verifier, *core.Message, *core.Data, *core.IdentityVerifierClaim) {
+	org1 := testOrgIdentity(t, "org1")
+	oldVerifier := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: "ns1",
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x12345",
+		},
+	}).Seal()
+
+	ivc := &core.IdentityVerifierClaim{
+		Identity: org1.IdentityBase,
+		Verifier: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x67890",
+		},
+		Deprecate: []*core.VerifierRef{&oldVerifier.VerifierRef},
+	}
+	b, err := json.Marshal(&ivc)
+	assert.NoError(t, err)
+	claimData := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+
+	claimMsg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeDefinition,
+			Tag:    core.SystemTagIdentityVerifierClaim,
+			Topics: fftypes.FFStringArray{org1.Topic()},
+			SignerRef: core.SignerRef{
+				Author: org1.DID,
+				Key:    "0x12345",
+			},
+		},
+	}
+
+	return org1, oldVerifier, claimMsg, claimData, ivc
+}
+
+func TestHandleDefinitionIdentityVerifierClaimOk(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, oldVerifier, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(oldVerifier, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Value == "0x67890" &&
+			verifier.Identity.Equals(org1.ID) &&
+			verifier.Message.Equals(claimMsg.Header.ID) &&
+			verifier.Deprecated == nil
+	}), database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.MatchedBy(func(verifier *core.Verifier) bool {
+		return verifier.Value == "0x12345" && verifier.Deprecated != nil
+	}), database.UpsertOptimizationExisting).Return(nil)
+	dh.mim.On("InvalidateVerifierCache", mock.Anything, &oldVerifier.VerifierRef).Return()
+	dh.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeIdentityUpdated && event.Reference.Equals(org1.ID)
+	})).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+
+	err = bs.RunFinalize(ctx)
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionIdentityVerifierClaimReplay(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, oldVerifier, claimMsg, claimData, ivc := testIdentityVerifierClaim(t)
+	oldVerifier.Deprecated = fftypes.Now()
+	newVerifier := &core.Verifier{Identity: org1.ID, VerifierRef: ivc.Verifier}
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(newVerifier, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(oldVerifier, nil)
+	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionConfirm}, action)
+	assert.NoError(t, err)
+
+	err = bs.RunFinalize(ctx)
+	assert.NoError(t, err)
+}
+
+func TestHandleDefinitionIdentityVerifierClaimBadPayload(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	_, _, claimMsg, _, _ := testIdentityVerifierClaim(t)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10400", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimInvalidIdentity(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	_, _, _, _, ivc := testIdentityVerifierClaim(t)
+	ivc.Identity = core.IdentityBase{}
+
+	action, err := dh.handleIdentityVerifierClaim(ctx, &bs.BatchState, &identityUpdateMsgInfo{}, ivc)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimWrongVerifierType(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	_, _, _, _, ivc := testIdentityVerifierClaim(t)
+	ivc.Verifier.Type = core.VerifierTypeMSPIdentity
+
+	action, err := dh.handleIdentityVerifierClaim(ctx, &bs.BatchState, &identityUpdateMsgInfo{}, ivc)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimIdentityNotFound(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(nil, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10408", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimNode(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+	node := *org1
+	node.Type = core.IdentityTypeNode
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(&node, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10583", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimVerifyChainRetry(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, true, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimVerifyChainPark(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionWait}, action)
+	assert.NoError(t, err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimWrongAuthor(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+	dh.multiparty = true
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+	claimMsg.Header.Author = "did:firefly:org/org2"
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mim.On("VerifyIdentityChain", ctx, org1).Return(nil, false, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10409", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimGetVerifierFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimVerifierConflict(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, ivc := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(&core.Verifier{
+		Identity:    fftypes.NewUUID(),
+		VerifierRef: ivc.Verifier,
+	}, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10407", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimVerifierDeprecated(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, ivc := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(&core.Verifier{
+		Identity:    org1.ID,
+		VerifierRef: ivc.Verifier,
+		Deprecated:  fftypes.Now(),
+	}, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10407", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimDeprecateSelf(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, _, _, ivc := testIdentityVerifierClaim(t)
+	ivc.Deprecate = []*core.VerifierRef{&ivc.Verifier}
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, nil)
+
+	action, err := dh.handleIdentityVerifierClaim(ctx, &bs.BatchState, &identityUpdateMsgInfo{}, ivc)
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10403", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimDeprecateLookupFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, _, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(nil, fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimDeprecateOtherIdentity(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, oldVerifier, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+	oldVerifier.Identity = fftypes.NewUUID()
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(oldVerifier, nil)
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10584", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimInsertFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, oldVerifier, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(oldVerifier, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionIdentityVerifierClaimDeprecateFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	ctx := context.Background()
+
+	org1, oldVerifier, claimMsg, claimData, _ := testIdentityVerifierClaim(t)
+
+	dh.mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x67890").Return(nil, nil)
+	dh.mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(oldVerifier, nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	dh.mdi.On("UpsertVerifier", ctx, mock.Anything, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	action, err := dh.HandleDefinitionBroadcast(ctx, &bs.BatchState, claimMsg, core.DataArray{claimData}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.Regexp(t, "pop", err)
+
+	bs.assertNoFinalizers()
+}
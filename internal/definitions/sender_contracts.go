@@ -126,6 +126,10 @@ func (ds *definitionSender) DefineContractAPI(ctx context.Context, httpServerURL
 	}
 	api.Namespace = ds.namespace
 
+	if err := ds.contracts.CheckFFILifecycle(ctx, api.Interface); err != nil {
+		return err
+	}
+
 	if api.Published {
 		if !ds.multiparty {
 			return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
@@ -240,6 +240,8 @@ func TestDefineContractAPIResolveFail(t *testing.T) {
 
 	ds.mcm.On("ResolveContractAPI", context.Background(), url, api).Return(fmt.Errorf("pop"))
 
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(nil)
+
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.EqualError(t, err, "pop")
 }
@@ -259,6 +261,8 @@ func TestDefineContractAPIFail(t *testing.T) {
 	ds.mim.On("GetRootOrg", context.Background()).Return(nil, fmt.Errorf("pop"))
 	ds.mdi.On("GetContractAPIByNetworkName", context.Background(), "ns1", "banana").Return(nil, nil)
 
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(nil)
+
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.EqualError(t, err, "pop")
 }
@@ -287,6 +291,8 @@ func TestDefineContractAPIOk(t *testing.T) {
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Send", context.Background()).Return(nil)
 
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(nil)
+
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.NoError(t, err)
 
@@ -304,6 +310,8 @@ func TestDefineContractAPINonMultiparty(t *testing.T) {
 	ds.mdi.On("InsertOrGetContractAPI", mock.Anything, mock.Anything).Return(nil, nil)
 	ds.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(nil)
+
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.NoError(t, err)
 }
@@ -319,6 +327,8 @@ func TestDefineContractAPIPublishNonMultiparty(t *testing.T) {
 		Published: true,
 	}
 
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(nil)
+
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.Regexp(t, "FF10414", err)
 }
@@ -340,6 +350,8 @@ func TestDefineContractAPINonMultipartyUpdate(t *testing.T) {
 	ds.mdi.On("UpsertContractAPI", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	ds.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(nil)
+
 	err := ds.DefineContractAPI(context.Background(), url, api, false)
 	assert.NoError(t, err)
 }
@@ -691,3 +703,19 @@ func TestPublishContractAPIInterfaceNotPublished(t *testing.T) {
 	_, err := ds.PublishContractAPI(context.Background(), url, "api", "api-shared", false)
 	assert.Regexp(t, "FF10451", err)
 }
+
+func TestDefineContractAPIInterfaceRetired(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	url := "http://firefly"
+	api := &core.ContractAPI{
+		Name:      "banana",
+		Interface: &fftypes.FFIReference{Name: "banana", Version: "v1"},
+	}
+
+	ds.mcm.On("CheckFFILifecycle", context.Background(), api.Interface).Return(fmt.Errorf("FF10598"))
+
+	err := ds.DefineContractAPI(context.Background(), url, api, false)
+	assert.Regexp(t, "FF10598", err)
+}
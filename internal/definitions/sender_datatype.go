@@ -33,6 +33,7 @@ func (ds *definitionSender) DefineDatatype(ctx context.Context, datatype *core.D
 		datatype.Validator = core.ValidatorTypeJSON
 	}
 	datatype.Hash = datatype.Value.Hash()
+	datatype.Lifecycle = ""
 
 	if ds.multiparty {
 		if err := datatype.Validate(ctx, false); err != nil {
@@ -75,3 +75,17 @@ func (ds *definitionSender) UpdateIdentity(ctx context.Context, identity *core.I
 		return ds.handler.handleIdentityUpdate(ctx, state, &identityUpdateMsgInfo{}, def)
 	})
 }
+
+func (ds *definitionSender) ClaimIdentityVerifier(ctx context.Context, verifier *core.Verifier, def *core.IdentityVerifierClaim, signingIdentity *core.SignerRef, waitConfirm bool) error {
+	if ds.multiparty {
+		claimMsg, err := ds.getSender(ctx, def, signingIdentity, core.SystemTagIdentityVerifierClaim).send(ctx, waitConfirm)
+		if claimMsg != nil {
+			verifier.Message = claimMsg.Header.ID
+		}
+		return err
+	}
+
+	return fakeBatch(ctx, func(ctx context.Context, state *core.BatchState) (HandlerResult, error) {
+		return ds.handler.handleIdentityVerifierClaim(ctx, state, &identityUpdateMsgInfo{}, def)
+	})
+}
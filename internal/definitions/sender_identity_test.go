@@ -200,3 +200,54 @@ func TestUpdateIdentityNonMultiparty(t *testing.T) {
 	}, false)
 	assert.Regexp(t, "FF10403", err)
 }
+
+func TestClaimIdentityVerifier(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	mms := &syncasyncmocks.Sender{}
+
+	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
+	mms.On("SendAndWait", mock.Anything).Return(nil)
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.MatchedBy(func(signer *core.SignerRef) bool {
+		return signer.Key == "0x1234"
+	})).Return(nil)
+
+	ds.multiparty = true
+
+	verifier := &core.Verifier{}
+	err := ds.ClaimIdentityVerifier(ds.ctx, verifier, &core.IdentityVerifierClaim{
+		Verifier: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x2345"},
+	}, &core.SignerRef{
+		Key: "0x1234",
+	}, true)
+	assert.NoError(t, err)
+
+	mms.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierResolveFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ds.multiparty = true
+
+	verifier := &core.Verifier{}
+	err := ds.ClaimIdentityVerifier(ds.ctx, verifier, &core.IdentityVerifierClaim{}, &core.SignerRef{
+		Key: "0x1234",
+	}, false)
+	assert.EqualError(t, err, "pop")
+	assert.Nil(t, verifier.Message)
+}
+
+func TestClaimIdentityVerifierNonMultiparty(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+
+	ds.multiparty = false
+
+	err := ds.ClaimIdentityVerifier(ds.ctx, &core.Verifier{}, &core.IdentityVerifierClaim{}, nil, false)
+	assert.Regexp(t, "FF10403", err)
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -37,9 +38,11 @@ import (
 	"github.com/hyperledger/firefly/internal/identity"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -60,6 +63,9 @@ type aggregator struct {
 	metrics      metrics.Manager
 	batchCache   cache.CInterface
 	rewinder     *rewinder
+	workers      *aggregatorWorkers
+	// processing is held while each batch of pins is processed, so it can be taken to pause the aggregator
+	processing *sync.Mutex
 }
 
 type batchCacheEntry struct {
@@ -93,6 +99,14 @@ func privatePinHash(topic string, group *fftypes.Bytes32, identity string, nonce
 
 func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockchain.Plugin, pm privatemessaging.Manager, sh definitions.Handler, im identity.Manager, dm data.Manager, en *eventNotifier, mm metrics.Manager, cacheManager cache.Manager) (*aggregator, error) {
 	batchSize := config.GetInt(coreconfig.EventAggregatorBatchSize)
+	workerCount := config.GetInt(coreconfig.EventAggregatorWorkers)
+	if workerCount < 1 {
+		workerCount = 1
+	}
+	if workerCount > 1 && !di.Capabilities().Concurrency {
+		log.L(ctx).Infof("Database plugin not configured for concurrency. Parallel aggregation disabled")
+		workerCount = 1
+	}
 	ag := &aggregator{
 		ctx:          log.WithLogField(ctx, "role", "aggregator"),
 		namespace:    ns,
@@ -103,6 +117,8 @@ func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockc
 		data:         dm,
 		verifierType: bi.VerifierType(),
 		metrics:      mm,
+		workers:      newAggregatorWorkers(workerCount),
+		processing:   &sync.Mutex{},
 	}
 
 	batchCache, err := cacheManager.GetCache(
@@ -215,10 +231,12 @@ func (ag *aggregator) rewindOffchainBatches() (bool, int64) {
 	return rewindBatch != nil, offset
 }
 
-func (ag *aggregator) processWithBatchState(callback func(ctx context.Context, state *batchState) error) error {
+func (ag *aggregator) processWithBatchState(callback func(ctx context.Context, state *batchState) error) (err error) {
+	spanCtx, span := tracing.StartSpan(ag.ctx, "aggregate events", attribute.String("firefly.namespace", ag.namespace))
+	defer func() { tracing.EndSpan(span, err) }()
 	state := newBatchState(ag)
 
-	err := ag.database.RunAsGroup(ag.ctx, func(ctx context.Context) (err error) {
+	err = ag.database.RunAsGroup(spanCtx, func(ctx context.Context) (err error) {
 		if err := callback(ctx, state); err != nil {
 			return err
 		}
@@ -232,10 +250,10 @@ func (ag *aggregator) processWithBatchState(callback func(ctx context.Context, s
 	}
 
 	if len(state.PreFinalize) > 0 {
-		if err := state.RunPreFinalize(ag.ctx); err != nil {
+		if err := state.RunPreFinalize(spanCtx); err != nil {
 			return err
 		}
-		err = ag.database.RunAsGroup(ag.ctx, func(ctx context.Context) error {
+		err = ag.database.RunAsGroup(spanCtx, func(ctx context.Context) error {
 			return state.RunFinalize(ctx)
 		})
 		if err != nil {
@@ -243,18 +261,37 @@ func (ag *aggregator) processWithBatchState(callback func(ctx context.Context, s
 		}
 	}
 	state.queueRewinds(ag)
+	state.queueMessageAcks(spanCtx)
 	return nil
 }
 
 func (ag *aggregator) processPinsEventsHandler(items []core.LocallySequenced) (repoll bool, err error) {
+	ag.processing.Lock()
+	defer ag.processing.Unlock()
+
 	pins := make([]*core.Pin, len(items))
 	for i, item := range items {
 		pins[i] = item.(*core.Pin)
 	}
 
-	return false, ag.processWithBatchState(func(ctx context.Context, state *batchState) error {
+	if ag.workers.count() > 1 {
+		return false, ag.processPinsParallel(pins)
+	}
+
+	startTime := ag.workers.runStarted(0)
+	err = ag.processWithBatchState(func(ctx context.Context, state *batchState) error {
 		return ag.processPins(ctx, pins, state)
 	})
+	ag.workers.runComplete(0, startTime, len(pins), 0)
+	return false, err
+}
+
+// runPaused runs the function in a database group, between batches of pins, so that it cannot interleave
+// with the aggregator processing the same definitions
+func (ag *aggregator) runPaused(ctx context.Context, fn func(ctx context.Context) error) error {
+	ag.processing.Lock()
+	defer ag.processing.Unlock()
+	return ag.database.RunAsGroup(ctx, fn)
 }
 
 func (ag *aggregator) getPins(ctx context.Context, filter ffapi.Filter, offset int64) ([]core.LocallySequenced, error) {
@@ -358,6 +395,14 @@ func (ag *aggregator) cacheBatch(cacheKey string, batch *core.BatchPersisted, ma
 }
 
 func (ag *aggregator) processPins(ctx context.Context, pins []*core.Pin, state *batchState) (err error) {
+	if err := ag.processPinsInOrder(ctx, pins, state); err != nil {
+		return err
+	}
+	ag.eventPoller.commitOffset(pins[len(pins)-1].Sequence)
+	return nil
+}
+
+func (ag *aggregator) processPinsInOrder(ctx context.Context, pins []*core.Pin, state *batchState) (err error) {
 	l := log.L(ctx)
 
 	localCache := make(map[fftypes.UUID]*batchCacheEntry)
@@ -403,8 +448,6 @@ func (ag *aggregator) processPins(ctx context.Context, pins []*core.Pin, state *
 			return err
 		}
 	}
-
-	ag.eventPoller.commitOffset(pins[len(pins)-1].Sequence)
 	return nil
 }
 
@@ -545,6 +588,13 @@ func (ag *aggregator) processMessage(ctx context.Context, manifest *core.BatchMa
 	}
 
 	newState := ag.completeDispatch(action, correlator, msg, manifest.TX.ID, state)
+	if msg.Header.Group != nil {
+		ackType := core.MessageAckTypeConfirmed
+		if newState == core.MessageStateRejected {
+			ackType = core.MessageAckTypeRejected
+		}
+		state.addMessageAck(batch.Node, ackType, msg.Header.ID)
+	}
 
 	// Mark all message pins dispatched, and increment all nextPins
 	for _, np := range nextPins {
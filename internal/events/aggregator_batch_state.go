@@ -79,6 +79,13 @@ type dispatchedMessage struct {
 	rejectReason  string
 }
 
+// pendingMessageAcks are acknowledgements to send back to the node that sent a set of private messages
+type pendingMessageAcks struct {
+	node    *fftypes.UUID
+	ackType core.MessageAckType
+	msgIDs  []*fftypes.UUID
+}
+
 // batchState is the object that tracks the in-memory state that builds up while processing a batch of pins,
 // that needs to be reconciled at the point the batch closes.
 // There are three phases:
@@ -103,6 +110,7 @@ type batchState struct {
 	maskedContexts     map[fftypes.Bytes32]*nextPinGroupState
 	unmaskedContexts   map[fftypes.Bytes32]*contextState
 	dispatchedMessages []*dispatchedMessage
+	messageAcks        []*pendingMessageAcks
 }
 
 func (bs *batchState) RunPreFinalize(ctx context.Context) error {
@@ -122,6 +130,28 @@ func (bs *batchState) queueRewinds(ag *aggregator) {
 	}
 }
 
+func (bs *batchState) addMessageAck(node *fftypes.UUID, ackType core.MessageAckType, msgID *fftypes.UUID) {
+	for _, pa := range bs.messageAcks {
+		if pa.node.Equals(node) && pa.ackType == ackType {
+			pa.msgIDs = append(pa.msgIDs, msgID)
+			return
+		}
+	}
+	bs.messageAcks = append(bs.messageAcks, &pendingMessageAcks{
+		node:    node,
+		ackType: ackType,
+		msgIDs:  []*fftypes.UUID{msgID},
+	})
+}
+
+// queueMessageAcks is called once the batch is committed, so acknowledgements are only sent for
+// messages whose final state has been recorded. They are sent to data exchange in the background.
+func (bs *batchState) queueMessageAcks(ctx context.Context) {
+	for _, pa := range bs.messageAcks {
+		bs.messaging.QueueMessageAcks(ctx, pa.node, pa.ackType, pa.msgIDs)
+	}
+}
+
 func (bs *batchState) checkUnmaskedContextReady(ctx context.Context, contextUnmasked *fftypes.Bytes32, msg *core.Message, firstMsgPinSequence int64) (bool, error) {
 
 	ucs, found := bs.unmaskedContexts[*contextUnmasked]
@@ -21,11 +21,36 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+func TestQueueMessageAcks(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	mpm := &privatemessagingmocks.Manager{}
+	bs.messaging = mpm
+
+	node1 := fftypes.NewUUID()
+	node2 := fftypes.NewUUID()
+	msg1 := fftypes.NewUUID()
+	msg2 := fftypes.NewUUID()
+	msg3 := fftypes.NewUUID()
+	bs.addMessageAck(node1, core.MessageAckTypeConfirmed, msg1)
+	bs.addMessageAck(node2, core.MessageAckTypeConfirmed, msg2)
+	bs.addMessageAck(node1, core.MessageAckTypeConfirmed, msg3)
+
+	mpm.On("QueueMessageAcks", ag.ctx, node1, core.MessageAckTypeConfirmed, []*fftypes.UUID{msg1, msg3}).Return()
+	mpm.On("QueueMessageAcks", ag.ctx, node2, core.MessageAckTypeConfirmed, []*fftypes.UUID{msg2}).Return()
+
+	bs.queueMessageAcks(ag.ctx)
+
+	mpm.AssertExpectations(t)
+}
+
 func TestFlushPinsFailUpdatePins(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
@@ -89,6 +89,7 @@ func newTestAggregatorCommon(metrics bool) *testAggregator {
 	}
 	mmi.On("IsMetricsEnabled").Return(metrics).Maybe()
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
+	mpm.On("QueueMessageAcks", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
 	ag, _ := newAggregator(ctx, "ns1", mdi, mbi, mpm, mdh, mim, mdm, newEventNotifier(ctx, "ut"), mmi, cmi)
 	cancel := func() {
 		ctxCancel()
@@ -349,6 +350,9 @@ func TestAggregationMaskedZeroNonceMatch(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.NotNil(t, bs.PendingConfirms[*msgID])
+	assert.Equal(t, []*pendingMessageAcks{
+		{node: member2node.ID, ackType: core.MessageAckTypeConfirmed, msgIDs: []*fftypes.UUID{msgID}},
+	}, bs.messageAcks)
 
 	// Confirm the offset
 	assert.Equal(t, int64(10001), <-ag.eventPoller.offsetCommitted)
@@ -1250,6 +1254,59 @@ func TestProcessMsgGapFill(t *testing.T) {
 
 }
 
+func TestProcessMsgRejectedPrivateAck(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	pin := fftypes.NewRandB32()
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+
+	groupID := fftypes.NewRandB32()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Group:     groupID,
+			Topics:    fftypes.FFStringArray{"topic1"},
+			Namespace: "ns1",
+			SignerRef: core.SignerRef{
+				Author: org1.DID,
+				Key:    "0x12345",
+			},
+		},
+		Pins: fftypes.FFStringArray{pin.String()},
+		Data: core.DataRefs{
+			{ID: fftypes.NewUUID()},
+		},
+	}
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, []core.IdentityType{core.IdentityTypeOrg, core.IdentityTypeCustom}, &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x12345",
+	}).Return(org1, nil)
+	ag.mdi.On("GetNextPinsForContext", ag.ctx, "ns1", mock.Anything).Return([]*core.NextPin{
+		{Context: fftypes.NewRandB32(), Hash: pin, Identity: org1.DID},
+	}, nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything, data.CRORequirePins).Return(msg, core.DataArray{}, true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(false, nil)
+
+	err := ag.processMessage(ag.ctx, &core.BatchManifest{
+		ID: fftypes.NewUUID(),
+	}, &core.Pin{Masked: true, Sequence: 12345, Signer: "0x12345"}, 10, &core.MessageManifestEntry{
+		MessageRef: core.MessageRef{
+			ID:   msg.Header.ID,
+			Hash: msg.Hash,
+		},
+		Topics: len(msg.Header.Topics),
+	}, &core.BatchPersisted{BatchHeader: core.BatchHeader{Node: node1.ID}}, bs)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*pendingMessageAcks{
+		{node: node1.ID, ackType: core.MessageAckTypeRejected, msgIDs: []*fftypes.UUID{msg.Header.ID}},
+	}, bs.messageAcks)
+
+}
+
 func TestCheckMaskedContextReadyMismatchedAuthor(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
@@ -0,0 +1,253 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type AggregatorStatus struct {
+	Workers []*AggregatorWorkerStatus `ffstruct:"AggregatorStatus" json:"workers"`
+}
+
+type AggregatorWorkerStatus struct {
+	Index            int                `ffstruct:"AggregatorWorkerStatus" json:"index"`
+	Busy             bool               `ffstruct:"AggregatorWorkerStatus" json:"busy"`
+	TotalPins        int64              `ffstruct:"AggregatorWorkerStatus" json:"totalPins"`
+	TotalContexts    int64              `ffstruct:"AggregatorWorkerStatus" json:"totalContexts"`
+	TotalRuns        int64              `ffstruct:"AggregatorWorkerStatus" json:"totalRuns"`
+	LastRunPins      int                `ffstruct:"AggregatorWorkerStatus" json:"lastRunPins"`
+	LastRunDuration  fftypes.FFDuration `ffstruct:"AggregatorWorkerStatus" json:"lastRunDuration"`
+	LastRunStartTime *fftypes.FFTime    `ffstruct:"AggregatorWorkerStatus" json:"lastRunStartTime,omitempty"`
+}
+
+// aggregatorWorkers tracks the load on each of the workers the aggregator uses to process
+// independent contexts in parallel
+type aggregatorWorkers struct {
+	mux    sync.Mutex
+	status []*AggregatorWorkerStatus
+}
+
+func newAggregatorWorkers(count int) *aggregatorWorkers {
+	aw := &aggregatorWorkers{
+		status: make([]*AggregatorWorkerStatus, count),
+	}
+	for i := range aw.status {
+		aw.status[i] = &AggregatorWorkerStatus{Index: i}
+	}
+	return aw
+}
+
+func (aw *aggregatorWorkers) count() int {
+	return len(aw.status)
+}
+
+func (aw *aggregatorWorkers) runStarted(idx int) time.Time {
+	aw.mux.Lock()
+	defer aw.mux.Unlock()
+	ws := aw.status[idx]
+	ws.Busy = true
+	ws.LastRunStartTime = fftypes.Now()
+	return time.Time(*ws.LastRunStartTime)
+}
+
+func (aw *aggregatorWorkers) runComplete(idx int, startTime time.Time, pins, contexts int) {
+	aw.mux.Lock()
+	defer aw.mux.Unlock()
+	ws := aw.status[idx]
+	ws.Busy = false
+	ws.TotalRuns++
+	ws.TotalPins += int64(pins)
+	ws.TotalContexts += int64(contexts)
+	ws.LastRunPins = pins
+	ws.LastRunDuration = fftypes.FFDuration(time.Since(startTime))
+}
+
+func (aw *aggregatorWorkers) getStatus() *AggregatorStatus {
+	aw.mux.Lock()
+	defer aw.mux.Unlock()
+	status := &AggregatorStatus{
+		Workers: make([]*AggregatorWorkerStatus, len(aw.status)),
+	}
+	for i, ws := range aw.status {
+		wsCopy := *ws
+		status.Workers[i] = &wsCopy
+	}
+	return status
+}
+
+// pinPartitioner performs a union-find across the contexts of the pins, so that any pins that
+// could affect each other's ordering (same context, or same message) end up in the same partition
+type pinPartitioner struct {
+	parent map[string]string
+}
+
+func (pp *pinPartitioner) find(key string) string {
+	p, ok := pp.parent[key]
+	if !ok {
+		pp.parent[key] = key
+		return key
+	}
+	if p != key {
+		p = pp.find(p)
+		pp.parent[key] = p
+	}
+	return p
+}
+
+func (pp *pinPartitioner) union(a, b string) {
+	ra, rb := pp.find(a), pp.find(b)
+	if ra != rb {
+		pp.parent[rb] = ra
+	}
+}
+
+// partitionPins splits a page of pins into groups that can be safely aggregated in parallel.
+// Each group preserves the sequence order of the pins within it.
+func (ag *aggregator) partitionPins(ctx context.Context, pins []*core.Pin) ([][]*core.Pin, error) {
+	pp := &pinPartitioner{parent: make(map[string]string)}
+	localCache := make(map[fftypes.UUID]*batchCacheEntry)
+	pinKeys := make([]string, len(pins))
+	serial := false
+	for i, pin := range pins {
+		pinKey := "ctx:" + pin.Hash.String()
+		pinKeys[i] = pinKey
+		pp.find(pinKey)
+
+		found, ok := localCache[*pin.Batch]
+		if !ok {
+			batch, manifest, err := ag.GetBatchForPin(ctx, pin)
+			if err != nil {
+				return nil, err
+			}
+			found = &batchCacheEntry{manifest: manifest, batch: batch}
+			localCache[*pin.Batch] = found
+		}
+		if found.manifest == nil {
+			continue
+		}
+		_, msgEntry, _ := ag.extractBatchMessagePin(found.manifest, pin.Index)
+		if msgEntry == nil {
+			continue
+		}
+
+		// All pins for the same message must be processed together
+		msgKey := "msg:" + msgEntry.ID.String()
+		pp.union(msgKey, pinKey)
+		msg, _, _, err := ag.data.GetMessageWithDataCached(ctx, msgEntry.ID, data.CRORequirePins)
+		if err != nil {
+			return nil, err
+		}
+		if msg == nil {
+			continue
+		}
+		switch msg.Header.Type {
+		case core.MessageTypeDefinition, core.MessageTypeGroupInit:
+			// Definitions change the identities, groups and datatypes that messages on any context are
+			// validated against, so a page containing one is processed in order as a single partition
+			serial = true
+		}
+		if pin.Masked && msg.Header.Group != nil {
+			// Masked pins are unique per sender+nonce, so we need to resolve the private contexts from the message
+			for _, topic := range msg.Header.Topics {
+				pp.union(msgKey, "ctx:"+privateContext(topic, msg.Header.Group).String())
+			}
+		}
+	}
+	if serial {
+		return [][]*core.Pin{pins}, nil
+	}
+
+	partitionIdx := make(map[string]int)
+	partitions := make([][]*core.Pin, 0)
+	for i, pin := range pins {
+		root := pp.find(pinKeys[i])
+		idx, ok := partitionIdx[root]
+		if !ok {
+			idx = len(partitions)
+			partitionIdx[root] = idx
+			partitions = append(partitions, []*core.Pin{})
+		}
+		partitions[idx] = append(partitions[idx], pin)
+	}
+	return partitions, nil
+}
+
+// processPinsParallel allocates the independent partitions of pins across the workers, each of which
+// processes its pins in sequence order in a separate database transaction.
+// The offset is only committed once all workers have completed successfully.
+func (ag *aggregator) processPinsParallel(pins []*core.Pin) error {
+	partitions, err := ag.partitionPins(ag.ctx, pins)
+	if err != nil {
+		return err
+	}
+
+	workerCount := ag.workers.count()
+	if len(partitions) < workerCount {
+		workerCount = len(partitions)
+	}
+	workerPins := make([][]*core.Pin, workerCount)
+	workerContexts := make([]int, workerCount)
+	workerForPin := make(map[int64]int, len(pins))
+	for i, partition := range partitions {
+		w := i % workerCount
+		workerContexts[w]++
+		for _, pin := range partition {
+			workerForPin[pin.Sequence] = w
+		}
+	}
+	// Re-merge in original sequence order, so pins within each worker are processed in order
+	for _, pin := range pins {
+		w := workerForPin[pin.Sequence]
+		workerPins[w] = append(workerPins[w], pin)
+	}
+
+	errs := make([]error, workerCount)
+	var wg sync.WaitGroup
+	for i := range workerPins {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			startTime := ag.workers.runStarted(idx)
+			log.L(ag.ctx).Debugf("Aggregator worker %d processing %d pins across %d contexts", idx, len(workerPins[idx]), workerContexts[idx])
+			errs[idx] = ag.processWithBatchState(func(ctx context.Context, state *batchState) error {
+				return ag.processPinsInOrder(ctx, workerPins[idx], state)
+			})
+			ag.workers.runComplete(idx, startTime, len(workerPins[idx]), workerContexts[idx])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	ag.eventPoller.commitOffset(pins[len(pins)-1].Sequence)
+	return nil
+}
+
+func (ag *aggregator) status() *AggregatorStatus {
+	return ag.workers.getStatus()
+}
@@ -0,0 +1,269 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPartitionBatch(ag *testAggregator) (*core.BatchPersisted, []*core.Message) {
+	group := fftypes.NewRandB32()
+	msgs := []*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topicA", "topicB"}}},
+		{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topicC"}}},
+		{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topicA"}}},
+		{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topicD"}, Group: group}},
+		{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topicD"}, Group: group}},
+	}
+	batch := &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{ID: fftypes.NewUUID()},
+		Hash:        fftypes.NewRandB32(),
+	}
+	manifest := &core.BatchManifest{ID: batch.ID}
+	for _, msg := range msgs {
+		manifest.Messages = append(manifest.Messages, &core.MessageManifestEntry{
+			MessageRef: core.MessageRef{ID: msg.Header.ID},
+			Topics:     len(msg.Header.Topics),
+		})
+	}
+	ag.cacheBatch(ag.getBatchCacheKey(batch.ID, batch.Hash), batch, manifest)
+	return batch, msgs
+}
+
+func TestPartitionPins(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	batch, msgs := newTestPartitionBatch(ag)
+	missingBatchID := fftypes.NewUUID()
+	ag.mdi.On("GetBatchByID", ag.ctx, "ns1", missingBatchID).Return(nil, nil)
+	for _, msg := range msgs {
+		ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePins).Return(msg, nil, true, nil)
+	}
+
+	pins := []*core.Pin{
+		{Sequence: 1, Batch: batch.ID, BatchHash: batch.Hash, Index: 0, Hash: broadcastContext("topicA")},
+		{Sequence: 2, Batch: batch.ID, BatchHash: batch.Hash, Index: 1, Hash: broadcastContext("topicB")},
+		{Sequence: 3, Batch: batch.ID, BatchHash: batch.Hash, Index: 2, Hash: broadcastContext("topicC")},
+		{Sequence: 4, Batch: batch.ID, BatchHash: batch.Hash, Index: 3, Hash: broadcastContext("topicA")},
+		{Sequence: 5, Batch: batch.ID, BatchHash: batch.Hash, Index: 4, Hash: fftypes.NewRandB32(), Masked: true},
+		{Sequence: 6, Batch: missingBatchID, Index: 0, Hash: broadcastContext("topicE")},
+		{Sequence: 7, Batch: batch.ID, BatchHash: batch.Hash, Index: 5, Hash: fftypes.NewRandB32(), Masked: true},
+		{Sequence: 8, Batch: batch.ID, BatchHash: batch.Hash, Index: 99, Hash: broadcastContext("topicF")},
+	}
+
+	partitions, err := ag.partitionPins(ag.ctx, pins)
+	assert.NoError(t, err)
+	assert.Len(t, partitions, 5)
+	assert.Equal(t, []*core.Pin{pins[0], pins[1], pins[3]}, partitions[0])
+	assert.Equal(t, []*core.Pin{pins[2]}, partitions[1])
+	assert.Equal(t, []*core.Pin{pins[4], pins[6]}, partitions[2])
+	assert.Equal(t, []*core.Pin{pins[5]}, partitions[3])
+	assert.Equal(t, []*core.Pin{pins[7]}, partitions[4])
+}
+
+func TestPartitionPinsDefinitionSerial(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	batch, msgs := newTestPartitionBatch(ag)
+	msgs[1].Header.Type = core.MessageTypeDefinition
+	for _, msg := range msgs[0:2] {
+		ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePins).Return(msg, nil, true, nil)
+	}
+
+	pins := []*core.Pin{
+		{Sequence: 1, Batch: batch.ID, BatchHash: batch.Hash, Index: 0, Hash: broadcastContext("topicA")},
+		{Sequence: 2, Batch: batch.ID, BatchHash: batch.Hash, Index: 2, Hash: broadcastContext("topicC")},
+	}
+
+	partitions, err := ag.partitionPins(ag.ctx, pins)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]*core.Pin{pins}, partitions)
+}
+
+func TestPartitionPinsMessageNotFound(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	batch, msgs := newTestPartitionBatch(ag)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msgs[0].Header.ID, data.CRORequirePins).Return(nil, nil, false, nil)
+
+	pins := []*core.Pin{
+		{Sequence: 1, Batch: batch.ID, BatchHash: batch.Hash, Index: 0, Hash: broadcastContext("topicA")},
+	}
+
+	partitions, err := ag.partitionPins(ag.ctx, pins)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]*core.Pin{pins}, partitions)
+}
+
+func TestPartitionPinsGetBatchFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	ag.mdi.On("GetBatchByID", ag.ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ag.partitionPins(ag.ctx, []*core.Pin{
+		{Sequence: 1, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPartitionPinsGetMessageFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	batch, msgs := newTestPartitionBatch(ag)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msgs[3].Header.ID, data.CRORequirePins).Return(nil, nil, false, fmt.Errorf("pop"))
+
+	_, err := ag.partitionPins(ag.ctx, []*core.Pin{
+		{Sequence: 1, Batch: batch.ID, BatchHash: batch.Hash, Index: 4, Hash: fftypes.NewRandB32(), Masked: true},
+	})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestProcessPinsParallel(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.workers = newAggregatorWorkers(2)
+
+	mockRunAsGroupPassthrough(ag.mdi)
+	ag.mdi.On("GetBatchByID", ag.ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := ag.processPinsEventsHandler([]core.LocallySequenced{
+		&core.Pin{Sequence: 1, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+		&core.Pin{Sequence: 2, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+		&core.Pin{Sequence: 3, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), <-ag.eventPoller.offsetCommitted)
+
+	status := ag.status()
+	assert.Len(t, status.Workers, 2)
+	assert.Equal(t, int64(2), status.Workers[0].TotalPins)
+	assert.Equal(t, int64(2), status.Workers[0].TotalContexts)
+	assert.Equal(t, int64(1), status.Workers[1].TotalPins)
+	assert.Equal(t, int64(1), status.Workers[1].TotalContexts)
+	for _, ws := range status.Workers {
+		assert.False(t, ws.Busy)
+		assert.Equal(t, int64(1), ws.TotalRuns)
+		assert.NotNil(t, ws.LastRunStartTime)
+	}
+}
+
+func TestProcessPinsParallelPartitionFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.workers = newAggregatorWorkers(2)
+
+	ag.mdi.On("GetBatchByID", ag.ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ag.processPinsEventsHandler([]core.LocallySequenced{
+		&core.Pin{Sequence: 1, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestProcessPinsParallelWorkerFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.workers = newAggregatorWorkers(2)
+
+	mockRunAsGroupPassthrough(ag.mdi)
+	ag.mdi.On("GetBatchByID", ag.ctx, "ns1", mock.Anything).Return(nil, nil).Twice()
+	ag.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ag.processPinsEventsHandler([]core.LocallySequenced{
+		&core.Pin{Sequence: 1, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+		&core.Pin{Sequence: 2, Batch: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
+	})
+	assert.Regexp(t, "pop", err)
+	assert.Empty(t, ag.eventPoller.offsetCommitted)
+}
+
+func TestNewAggregatorWorkersNoConcurrency(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.EventAggregatorWorkers, 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mdi := &databasemocks.Plugin{}
+	mdi.On("Capabilities").Return(&database.Capabilities{Concurrency: false})
+	mbi := &blockchainmocks.Plugin{}
+	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 0), nil)
+
+	ag, err := newAggregator(ctx, "ns1", mdi, mbi, nil, nil, nil, nil, newEventNotifier(ctx, "ut"), nil, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ag.workers.count())
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNewAggregatorWorkersConcurrency(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.EventAggregatorWorkers, 5)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mdi := &databasemocks.Plugin{}
+	mdi.On("Capabilities").Return(&database.Capabilities{Concurrency: true})
+	mbi := &blockchainmocks.Plugin{}
+	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 0), nil)
+
+	ag, err := newAggregator(ctx, "ns1", mdi, mbi, nil, nil, nil, nil, newEventNotifier(ctx, "ut"), nil, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, ag.workers.count())
+	assert.Len(t, ag.status().Workers, 5)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNewAggregatorWorkersInvalid(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.EventAggregatorWorkers, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mbi := &blockchainmocks.Plugin{}
+	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 0), nil)
+
+	ag, err := newAggregator(ctx, "ns1", &databasemocks.Plugin{}, mbi, nil, nil, nil, nil, newEventNotifier(ctx, "ut"), nil, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ag.workers.count())
+}
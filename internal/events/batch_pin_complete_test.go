@@ -126,7 +126,7 @@ func TestBatchPinCompleteOkBroadcast(t *testing.T) {
 	})).Return(nil).Once()
 	em.mdi.On("InsertPins", mock.Anything, mock.Anything).Return(nil).Once()
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
-	em.msd.On("InitiateDownloadBatch", mock.Anything, batchPin.TransactionID, batchPin.BatchPayloadRef, false).Return(nil)
+	em.msd.On("InitiateDownloadBatch", mock.Anything, batchPin.TransactionID, batchPin.BatchPayloadRef, batchPin.BatchHash, false).Return(nil)
 
 	err := em.BlockchainEventBatch([]*blockchain.EventToDispatch{
 		{
@@ -369,7 +369,7 @@ func TestSequencedBroadcastInitiateDownloadFail(t *testing.T) {
 	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 	em.mdi.On("InsertPins", mock.Anything, mock.Anything).Return(nil)
 	em.mdi.On("GetBatchByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
-	em.msd.On("InitiateDownloadBatch", mock.Anything, batchPin.TransactionID, batchPin.BatchPayloadRef, false).Return(fmt.Errorf("pop"))
+	em.msd.On("InitiateDownloadBatch", mock.Anything, batchPin.TransactionID, batchPin.BatchPayloadRef, batchPin.BatchHash, false).Return(fmt.Errorf("pop"))
 
 	err := em.BlockchainEventBatch([]*blockchain.EventToDispatch{
 		{
@@ -83,11 +83,25 @@ func (em *eventManager) maybePersistBlockchainEvent(ctx context.Context, chainEv
 		chainEvent.ID = existing.ID
 		return false, nil
 	}
+	em.trackBlock(ctx, chainEvent)
 	topic := em.getTopicForChainListener(listener)
 	ffEvent := core.NewEvent(core.EventTypeBlockchainEventReceived, chainEvent.Namespace, chainEvent.ID, chainEvent.TX.ID, topic)
 	return true, em.database.InsertEvent(ctx, ffEvent)
 }
 
+// trackBlock passes the block of each new blockchain event to the enricher, which detects a listener that has
+// been rewound by its connector after a re-org. Events from tokens connectors are tracked by the source plugin.
+func (em *eventManager) trackBlock(ctx context.Context, chainEvent *core.BlockchainEvent) {
+	if _, ok := chainEvent.Info["blockNumber"]; !ok {
+		return
+	}
+	source := chainEvent.Source
+	if chainEvent.Listener != nil {
+		source = chainEvent.Listener.String()
+	}
+	em.enricher.blockReceived(ctx, source, chainEvent.Info.GetInt64("blockNumber"))
+}
+
 func (em *eventManager) getChainListenerCached(cacheKey string, getter func() (*core.ContractListener, error)) (*core.ContractListener, error) {
 
 	if cachedValue := em.chainListenerCache.Get(cacheKey); cachedValue != nil {
@@ -122,6 +136,7 @@ func (em *eventManager) maybePersistBlockchainEvents(ctx context.Context, bc *ev
 	}
 	// Only the ones newly inserted need events emitting
 	for _, chainEvent := range inserted {
+		em.trackBlock(ctx, chainEvent)
 		topic := bc.topicsByEventID[chainEvent.ID.String()] // bc.addEvent() ensures this is there
 		ffEvent := core.NewEvent(core.EventTypeBlockchainEventReceived, chainEvent.Namespace, chainEvent.ID, chainEvent.TX.ID, topic)
 		if err := em.database.InsertEvent(ctx, ffEvent); err != nil {
@@ -192,11 +207,15 @@ func (em *eventManager) handleBlockchainEventForListener(ctx context.Context, ev
 		return nil
 	}
 	listener.Namespace = em.namespace.Name
+	em.emitBlockchainEventMetric(event.Event)
+	if !listener.Options.MatchesOutput(event.Event.Output) {
+		log.L(ctx).Debugf("Ignoring blockchain event '%s' that does not match the output filters of listener %s", event.Event.ProtocolID, listener.ID)
+		return nil
+	}
 
 	chainEvent := buildBlockchainEvent(listener.Namespace, listener.ID, event.Event, &core.BlockchainTransactionRef{
 		BlockchainID: event.BlockchainTXID,
 	})
 	bc.addEventToInsert(chainEvent, em.getTopicForChainListener(listener))
-	em.emitBlockchainEventMetric(event.Event)
 	return nil
 }
@@ -151,6 +151,48 @@ func TestContractEventWrongNS(t *testing.T) {
 
 }
 
+func TestContractEventFilteredByOutput(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	ev := &blockchain.EventForListener{
+		ListenerID: "sb-1",
+		Event: &blockchain.Event{
+			BlockchainTXID: "0xabcd1234",
+			ProtocolID:     "10/20/30",
+			Name:           "Changed",
+			Output: fftypes.JSONObject{
+				"value": "1",
+			},
+			Info: fftypes.JSONObject{
+				"blockNumber": "10",
+			},
+		},
+	}
+	sub := &core.ContractListener{
+		Namespace: "ns1",
+		ID:        fftypes.NewUUID(),
+		Topic:     "topic1",
+		Options: &core.ContractListenerOptions{
+			OutputFilters: []*core.ListenerOutputFilter{
+				{Field: "value", Op: core.ListenerOutputFilterOpGt, Value: "1000"},
+			},
+		},
+	}
+
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(sub, nil)
+
+	err := em.BlockchainEventBatch([]*blockchain.EventToDispatch{
+		{
+			Type:        blockchain.EventTypeForListener,
+			ForListener: ev,
+		},
+	})
+	assert.NoError(t, err)
+
+	em.mth.AssertNotCalled(t, "InsertNewBlockchainEvents", mock.Anything, mock.Anything)
+}
+
 // TODO: Add test case for event not existing
 func TestPersistBlockchainEventDuplicate(t *testing.T) {
 	em := newTestEventManager(t)
@@ -181,6 +223,40 @@ func TestPersistBlockchainEventDuplicate(t *testing.T) {
 
 }
 
+func TestPersistBlockchainEventRewindInvalidatesEnrichment(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	cachedID := fftypes.NewUUID()
+	em.enricher.cacheEnrichment(&core.EnrichedEvent{
+		Event:           core.Event{ID: cachedID},
+		BlockchainEvent: &core.BlockchainEvent{Info: fftypes.JSONObject{"blockNumber": "12"}},
+	})
+
+	newEvent := func(blockNumber string) *core.BlockchainEvent {
+		return &core.BlockchainEvent{
+			ID:        fftypes.NewUUID(),
+			Source:    "erc1155",
+			Namespace: "ns1",
+			Info:      fftypes.JSONObject{"blockNumber": blockNumber},
+		}
+	}
+	em.mth.On("InsertOrGetBlockchainEvent", mock.Anything, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := em.maybePersistBlockchainEvent(em.ctx, newEvent("12"), nil)
+	assert.NoError(t, err)
+	_, err = em.maybePersistBlockchainEvent(em.ctx, &core.BlockchainEvent{ID: fftypes.NewUUID(), Namespace: "ns1"}, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, em.enricher.enrichCache.Get(cachedID.String()))
+
+	_, err = em.maybePersistBlockchainEvent(em.ctx, newEvent("11"), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, em.enricher.enrichCache.Get(cachedID.String()))
+	assert.Equal(t, int64(11), em.enricher.latestBlock["erc1155"])
+
+}
+
 func TestGetTopicForChainListenerFallback(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
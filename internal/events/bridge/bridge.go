@@ -0,0 +1,257 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+const (
+	// BridgeTransport is the transport of the durable subscriptions that back bridges
+	BridgeTransport = "bridge"
+
+	// TargetOption is the subscription transport option holding the namespace to bridge into
+	TargetOption = "target"
+)
+
+// Bridge re-broadcasts the confirmed broadcast messages delivered to durable subscriptions with transport "bridge",
+// into another namespace on the same node. Each event is acknowledged only once the message has been submitted
+// in the target namespace, so with a read-ahead of zero the messages are bridged in order, and any failure
+// causes the subscription to redeliver from the failed message.
+type Bridge struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	namespaces   events.LocalNamespaces
+	connID       string
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// Provenance is appended as the final data item of each bridged message, under a "bridge" key. It records the
+// message that was first bridged, and every hop from there, so a message is never bridged into a namespace it has
+// already passed through. Any member can send a message with a data item of the same form, so the provenance is
+// only read from a message that a bridge on this node submitted - which is known from its idempotency key, as that
+// is never transferred to other members, and the key prefix is reserved for bridges.
+type Provenance struct {
+	OriginNamespace string           `json:"originNamespace"`
+	OriginMessage   *fftypes.UUID    `json:"originMessage"`
+	Hops            []*ProvenanceHop `json:"hops"`
+}
+
+// ProvenanceHop is a bridge a message passed through, and the message in the namespace it was bridged from.
+// Blob data cannot be bridged, so the IDs of any data items that were left out of the bridged message are listed.
+type ProvenanceHop struct {
+	Bridge      string          `json:"bridge"`
+	Namespace   string          `json:"namespace"`
+	Message     *fftypes.UUID   `json:"message"`
+	Author      string          `json:"author,omitempty"`
+	Key         string          `json:"key,omitempty"`
+	OmittedData []*fftypes.UUID `json:"omittedData,omitempty"`
+}
+
+type provenanceData struct {
+	Bridge *Provenance `json:"bridge"`
+}
+
+func (b *Bridge) Name() string { return BridgeTransport }
+
+func (b *Bridge) InitConfig(config config.Section) {}
+
+func (b *Bridge) Init(ctx context.Context, config config.Section) error {
+	*b = Bridge{
+		ctx:          ctx,
+		capabilities: &events.Capabilities{},
+		connID:       fftypes.ShortID(),
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+	}
+	return nil
+}
+
+func (b *Bridge) SetLocalNamespaces(namespaces events.LocalNamespaces) {
+	b.namespaces = namespaces
+}
+
+func (b *Bridge) SetHandler(namespace string, handler events.Callbacks) error {
+	b.callbacks.writeLock.Lock()
+	defer b.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(b.callbacks.handlers, namespace)
+		return nil
+	}
+	b.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(b.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (b *Bridge) getHandler(namespace string) (events.Callbacks, bool) {
+	b.callbacks.writeLock.Lock()
+	defer b.callbacks.writeLock.Unlock()
+	cb, ok := b.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (b *Bridge) Capabilities() *events.Capabilities {
+	return b.capabilities
+}
+
+func (b *Bridge) ValidateOptions(ctx context.Context, options *core.SubscriptionOptions) error {
+	target := options.TransportOptions().GetString(TargetOption)
+	if target == "" {
+		return i18n.NewError(ctx, coremsgs.MsgBridgeTargetRequired)
+	}
+	if err := b.namespaces.CheckBroadcastNamespace(ctx, target); err != nil {
+		return err
+	}
+	// The data is always required to re-broadcast the message, and we must process one event at a time to preserve order
+	withData := true
+	noReadAhead := uint16(0)
+	options.WithData = &withData
+	options.ReadAhead = &noReadAhead
+	return nil
+}
+
+// bridgedProvenance returns the provenance of a message that was itself submitted by a bridge on this node
+func bridgedProvenance(msg *core.Message, data core.DataArray) *Provenance {
+	if !msg.IdempotencyKey.Bridged() || len(data) == 0 || data[len(data)-1].Value == nil {
+		return nil
+	}
+	var pd provenanceData
+	if err := json.Unmarshal(data[len(data)-1].Value.Bytes(), &pd); err != nil || pd.Bridge == nil || pd.Bridge.OriginMessage == nil {
+		return nil
+	}
+	return pd.Bridge
+}
+
+// passedThrough checks if a message with the provenance has already been in the namespace
+func (p *Provenance) passedThrough(namespace string) bool {
+	for _, hop := range p.Hops {
+		if hop.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bridge) bridgeMessage(ctx context.Context, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	msg := event.Message
+	target := sub.Options.TransportOptions().GetString(TargetOption)
+	if event.Type != core.EventTypeMessageConfirmed || msg == nil || msg.Header.Type != core.MessageTypeBroadcast {
+		// Only confirmed broadcast messages are bridged - private messages must never be re-broadcast
+		return nil
+	}
+	provenance := &Provenance{
+		OriginNamespace: sub.Namespace,
+		OriginMessage:   msg.Header.ID,
+	}
+	if prev := bridgedProvenance(msg, data); prev != nil {
+		// The provenance of the previous hops is carried forwards, rather than copied as a data item
+		provenance = prev
+		data = data[:len(data)-1]
+	}
+	hop := &ProvenanceHop{
+		Bridge:    sub.Name,
+		Namespace: sub.Namespace,
+		Message:   msg.Header.ID,
+		Author:    msg.Header.Author,
+		Key:       msg.Header.Key,
+	}
+	provenance.Hops = append(provenance.Hops, hop)
+	if provenance.passedThrough(target) {
+		log.L(ctx).Debugf("Bridge '%s' skipping message %s, which has already passed through namespace '%s'", sub.Name, msg.Header.ID, target)
+		return nil
+	}
+
+	inlineData := make(core.InlineData, 0, len(data)+1)
+	for _, d := range data {
+		if d.Blob != nil || d.Value == nil {
+			// Blobs can never be bridged, so rejecting the event would stall the bridge on this message forever.
+			// The value data is bridged instead, with the omitted data recorded in the provenance.
+			hop.OmittedData = append(hop.OmittedData, d.ID)
+			continue
+		}
+		inlineData = append(inlineData, &core.DataRefOrValue{Value: d.Value})
+	}
+	if len(hop.OmittedData) > 0 {
+		log.L(ctx).Warnf("Bridge '%s' omitting blob data %v of message %s, which cannot be bridged", sub.Name, hop.OmittedData, msg.Header.ID)
+	}
+	provenanceJSON, _ := json.Marshal(&provenanceData{Bridge: provenance})
+	inlineData = append(inlineData, &core.DataRefOrValue{Value: fftypes.JSONAnyPtrBytes(provenanceJSON)})
+
+	bridged := &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				CID:    msg.Header.ID,
+				Topics: msg.Header.Topics,
+				Tag:    msg.Header.Tag,
+			},
+			// Ensures a redelivery after a restart does not bridge the same message twice, and marks the message
+			// as submitted by a bridge
+			IdempotencyKey: core.IdempotencyKey(core.BridgedIdempotencyKeyPrefix + sub.ID.String() + ":" + msg.Header.ID.String()),
+		},
+		InlineData: inlineData,
+	}
+	out, err := b.namespaces.BroadcastMessage(ctx, target, bridged)
+	if err != nil {
+		var ffErr i18n.FFError
+		if errors.As(err, &ffErr) && ffErr.MessageKey() == coremsgs.MsgIdempotencyKeyDuplicateMessage {
+			log.L(ctx).Infof("Bridge '%s' already bridged message %s into namespace '%s'", sub.Name, msg.Header.ID, target)
+			return nil
+		}
+		return err
+	}
+	log.L(ctx).Infof("Bridge '%s' bridged message %s into namespace '%s' as message %s", sub.Name, msg.Header.ID, target, out.Header.ID)
+	return nil
+}
+
+func (b *Bridge) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	if err := b.bridgeMessage(ctx, sub, event, data); err != nil {
+		return err
+	}
+	if cb, ok := b.getHandler(sub.Namespace); ok {
+		cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
+			ID:           event.ID,
+			Subscription: event.Subscription,
+		})
+	}
+	return nil
+}
+
+func (b *Bridge) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(ctx, coremsgs.MsgBatchDeliveryNotSupported, b.Name()) // should never happen
+}
+
+func (b *Bridge) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
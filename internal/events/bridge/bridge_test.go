@@ -0,0 +1,375 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBridge(t *testing.T) (*Bridge, *eventsmocks.Callbacks, *eventsmocks.LocalNamespaces) {
+	coreconfig.Reset()
+
+	cbs := &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	lns := &eventsmocks.LocalNamespaces{}
+	b := &Bridge{}
+	config := config.RootSection("ut.events")
+	b.InitConfig(config)
+	err := b.Init(context.Background(), config)
+	assert.NoError(t, err)
+	b.SetLocalNamespaces(lns)
+	err = b.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "bridge", b.Name())
+	assert.NotNil(t, b.Capabilities())
+	t.Cleanup(func() {
+		cbs.AssertExpectations(t)
+		lns.AssertExpectations(t)
+	})
+	return b, cbs, lns
+}
+
+func newTestSubscription() *core.Subscription {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "bridge1",
+		},
+	}
+	sub.Options.TransportOptions()[TargetOption] = "ns2"
+	return sub
+}
+
+func newTestEvent(sub *core.Subscription, msgType core.MessageType) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:   fftypes.NewUUID(),
+				Type: core.EventTypeMessageConfirmed,
+			},
+			Message: &core.Message{
+				Header: core.MessageHeader{
+					ID:   fftypes.NewUUID(),
+					Type: msgType,
+					Tag:  "tag1",
+					SignerRef: core.SignerRef{
+						Author: "did:firefly:org/org1",
+						Key:    "0x12345",
+					},
+					Topics: fftypes.FFStringArray{"topic1"},
+				},
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+}
+
+func TestValidateOptionsOk(t *testing.T) {
+	b, _, lns := newTestBridge(t)
+
+	lns.On("CheckBroadcastNamespace", mock.Anything, "ns2").Return(nil)
+
+	options := &core.SubscriptionOptions{}
+	options.TransportOptions()[TargetOption] = "ns2"
+	err := b.ValidateOptions(context.Background(), options)
+	assert.NoError(t, err)
+	assert.True(t, *options.WithData)
+	assert.Equal(t, uint16(0), *options.ReadAhead)
+}
+
+func TestValidateOptionsNoTarget(t *testing.T) {
+	b, _, _ := newTestBridge(t)
+
+	err := b.ValidateOptions(context.Background(), &core.SubscriptionOptions{})
+	assert.Regexp(t, "FF10593", err)
+}
+
+func TestValidateOptionsBadTarget(t *testing.T) {
+	b, _, lns := newTestBridge(t)
+
+	lns.On("CheckBroadcastNamespace", mock.Anything, "ns2").Return(fmt.Errorf("pop"))
+
+	options := &core.SubscriptionOptions{}
+	options.TransportOptions()[TargetOption] = "ns2"
+	err := b.ValidateOptions(context.Background(), options)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeliveryRequestBridged(t *testing.T) {
+	b, cbs, lns := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypeBroadcast)
+	data := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"some":"data"}`)},
+	}
+
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		assert.Equal(t, event.Message.Header.ID, msg.Header.CID)
+		assert.Equal(t, "tag1", msg.Header.Tag)
+		assert.Equal(t, fftypes.FFStringArray{"topic1"}, msg.Header.Topics)
+		assert.Equal(t, core.IdempotencyKey("bridge:"+sub.ID.String()+":"+event.Message.Header.ID.String()), msg.IdempotencyKey)
+		assert.True(t, msg.IdempotencyKey.Bridged())
+		assert.Len(t, msg.InlineData, 2)
+		assert.Equal(t, `{"some":"data"}`, msg.InlineData[0].Value.String())
+		var pd provenanceData
+		err := json.Unmarshal(msg.InlineData[1].Value.Bytes(), &pd)
+		assert.NoError(t, err)
+		assert.Equal(t, &Provenance{
+			OriginNamespace: "ns1",
+			OriginMessage:   event.Message.Header.ID,
+			Hops: []*ProvenanceHop{{
+				Bridge:    "bridge1",
+				Namespace: "ns1",
+				Message:   event.Message.Header.ID,
+				Author:    "did:firefly:org/org1",
+				Key:       "0x12345",
+			}},
+		}, pd.Bridge)
+		return true
+	})).Return(&core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}, nil)
+	cbs.On("DeliveryResponse", "conn1", mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return()
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, data)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestBlobData(t *testing.T) {
+	b, cbs, lns := newTestBridge(t)
+
+	sub := newTestSubscription()
+	blobEvent := newTestEvent(sub, core.MessageTypeBroadcast)
+	blobID := fftypes.NewUUID()
+	data := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"some":"data"}`)},
+		{ID: blobID, Value: fftypes.JSONAnyPtr(`{"name":"file.txt"}`), Blob: &core.BlobRef{Hash: fftypes.NewRandB32()}},
+	}
+	nextEvent := newTestEvent(sub, core.MessageTypeBroadcast)
+	nextData := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"more":"data"}`)},
+	}
+
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.CID.Equals(blobEvent.Message.Header.ID)
+	})).Run(func(args mock.Arguments) {
+		// The value data is bridged, and the blob data is recorded as omitted in the provenance
+		msg := args[2].(*core.MessageInOut)
+		assert.Len(t, msg.InlineData, 2)
+		assert.Equal(t, `{"some":"data"}`, msg.InlineData[0].Value.String())
+		var pd provenanceData
+		err := json.Unmarshal(msg.InlineData[1].Value.Bytes(), &pd)
+		assert.NoError(t, err)
+		assert.Equal(t, []*fftypes.UUID{blobID}, pd.Bridge.Hops[0].OmittedData)
+	}).Return(&core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}, nil)
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.CID.Equals(nextEvent.Message.Header.ID)
+	})).Run(func(args mock.Arguments) {
+		msg := args[2].(*core.MessageInOut)
+		var pd provenanceData
+		err := json.Unmarshal(msg.InlineData[1].Value.Bytes(), &pd)
+		assert.NoError(t, err)
+		assert.Empty(t, pd.Bridge.Hops[0].OmittedData)
+	}).Return(&core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}, nil)
+	cbs.On("DeliveryResponse", "conn1", mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(blobEvent.ID) && !r.Rejected
+	})).Return()
+	cbs.On("DeliveryResponse", "conn1", mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(nextEvent.ID) && !r.Rejected
+	})).Return()
+
+	// The message with the blob is acknowledged, so the bridge moves on to the next message
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, blobEvent, data)
+	assert.NoError(t, err)
+	err = b.DeliveryRequest(context.Background(), "conn1", sub, nextEvent, nextData)
+	assert.NoError(t, err)
+	lns.AssertNumberOfCalls(t, "BroadcastMessage", 2)
+}
+
+func TestDeliveryRequestDuplicate(t *testing.T) {
+	b, cbs, lns := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypeBroadcast)
+
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.Anything).
+		Return(nil, i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateMessage, "key1", fftypes.NewUUID()))
+	cbs.On("DeliveryResponse", "conn1", mock.Anything).Return()
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, core.DataArray{})
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestBroadcastFail(t *testing.T) {
+	b, _, lns := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypeBroadcast)
+
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, core.DataArray{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeliveryRequestSkipPrivate(t *testing.T) {
+	b, cbs, _ := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypePrivate)
+
+	cbs.On("DeliveryResponse", "conn1", mock.Anything).Return()
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, core.DataArray{})
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestBridgedHop(t *testing.T) {
+	b, cbs, lns := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypeBroadcast)
+	event.Message.IdempotencyKey = core.IdempotencyKey("bridge:" + fftypes.NewUUID().String() + ":" + fftypes.NewUUID().String())
+	origin := fftypes.NewUUID()
+	data := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"some":"data"}`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"bridge":{"originNamespace":"ns0","originMessage":"` + origin.String() + `",` +
+			`"hops":[{"bridge":"bridge0","namespace":"ns0","message":"` + origin.String() + `"}]}}`)},
+	}
+
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		// The provenance of the previous hop is replaced, rather than copied
+		assert.Len(t, msg.InlineData, 2)
+		var pd provenanceData
+		err := json.Unmarshal(msg.InlineData[1].Value.Bytes(), &pd)
+		assert.NoError(t, err)
+		assert.Equal(t, "ns0", pd.Bridge.OriginNamespace)
+		assert.Equal(t, origin, pd.Bridge.OriginMessage)
+		assert.Len(t, pd.Bridge.Hops, 2)
+		assert.Equal(t, "bridge0", pd.Bridge.Hops[0].Bridge)
+		assert.Equal(t, "ns1", pd.Bridge.Hops[1].Namespace)
+		assert.Equal(t, event.Message.Header.ID, pd.Bridge.Hops[1].Message)
+		return true
+	})).Return(&core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}, nil)
+	cbs.On("DeliveryResponse", "conn1", mock.Anything).Return()
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, data)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestProvenanceNotBridged(t *testing.T) {
+	b, cbs, lns := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypeBroadcast)
+	forged := fftypes.JSONAnyPtr(`{"bridge":{"originNamespace":"ns2","originMessage":"` + fftypes.NewUUID().String() + `",` +
+		`"hops":[{"bridge":"bridge2","namespace":"ns2"}]}}`)
+	data := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: forged},
+	}
+
+	lns.On("BroadcastMessage", mock.Anything, "ns2", mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		// The message was not submitted by a bridge on this node, so the data item is copied as data,
+		// and the provenance starts from this namespace
+		assert.Len(t, msg.InlineData, 2)
+		assert.Equal(t, forged, msg.InlineData[0].Value)
+		var pd provenanceData
+		err := json.Unmarshal(msg.InlineData[1].Value.Bytes(), &pd)
+		assert.NoError(t, err)
+		assert.Equal(t, "ns1", pd.Bridge.OriginNamespace)
+		assert.Len(t, pd.Bridge.Hops, 1)
+		return true
+	})).Return(&core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}, nil)
+	cbs.On("DeliveryResponse", "conn1", mock.Anything).Return()
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, data)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestSkipPassedThroughTarget(t *testing.T) {
+	b, cbs, _ := newTestBridge(t)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypeBroadcast)
+	event.Message.IdempotencyKey = core.IdempotencyKey("bridge:" + fftypes.NewUUID().String() + ":" + fftypes.NewUUID().String())
+	data := core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"not provenance"`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"bridge":{"originNamespace":"ns2","originMessage":"` + fftypes.NewUUID().String() + `",` +
+			`"hops":[{"bridge":"bridge2","namespace":"ns2"},{"bridge":"bridge3","namespace":"ns3"}]}}`)},
+	}
+
+	cbs.On("DeliveryResponse", "conn1", mock.Anything).Return()
+
+	err := b.DeliveryRequest(context.Background(), "conn1", sub, event, data)
+	assert.NoError(t, err)
+}
+
+func TestBridgedProvenance(t *testing.T) {
+	bridged := &core.Message{IdempotencyKey: "bridge:sub1:msg1"}
+	provenance := core.DataArray{{Value: fftypes.JSONAnyPtr(`{"bridge":{"originNamespace":"ns2","originMessage":"` + fftypes.NewUUID().String() + `"}}`)}}
+	assert.Nil(t, bridgedProvenance(bridged, core.DataArray{}))
+	assert.Nil(t, bridgedProvenance(bridged, core.DataArray{{}}))
+	assert.Nil(t, bridgedProvenance(bridged, core.DataArray{{Value: fftypes.JSONAnyPtr(`!json`)}}))
+	assert.Nil(t, bridgedProvenance(bridged, core.DataArray{{Value: fftypes.JSONAnyPtr(`{"other":"data"}`)}}))
+	assert.Nil(t, bridgedProvenance(bridged, core.DataArray{{Value: fftypes.JSONAnyPtr(`{"bridge":{"namespace":"ns2"}}`)}}))
+	assert.Nil(t, bridgedProvenance(&core.Message{}, provenance))
+	assert.Nil(t, bridgedProvenance(&core.Message{IdempotencyKey: "sub1:msg1"}, provenance))
+	assert.Equal(t, "ns2", bridgedProvenance(bridged, provenance).OriginNamespace)
+}
+
+func TestDeliveryRequestNoHandler(t *testing.T) {
+	b, _, _ := newTestBridge(t)
+	err := b.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, core.MessageTypePrivate)
+
+	err = b.DeliveryRequest(context.Background(), "conn1", sub, event, core.DataArray{})
+	assert.NoError(t, err)
+}
+
+func TestBatchDeliveryRequestNotSupported(t *testing.T) {
+	b, _, _ := newTestBridge(t)
+	err := b.BatchDeliveryRequest(context.Background(), "conn1", newTestSubscription(), []*core.CombinedEventDataDelivery{})
+	assert.Regexp(t, "FF10461", err)
+}
+
+func TestNamespaceRestarted(t *testing.T) {
+	b, _, _ := newTestBridge(t)
+	b.NamespaceRestarted("ns1", time.Now())
+}
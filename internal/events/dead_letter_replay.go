@@ -0,0 +1,98 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// replayDeadLetter makes a synchronous attempt to deliver an event from the dead-letter queue of a subscription.
+// The entry is removed if delivery succeeds, and updated with the latest error if it fails.
+func (sm *subscriptionManager) replayDeadLetter(ctx context.Context, subID, id *fftypes.UUID) error {
+	namespace := sm.namespace.Name
+	deadLetter, err := sm.database.GetDeadLetterByID(ctx, namespace, id)
+	if err != nil {
+		return err
+	}
+	if deadLetter == nil || !deadLetter.Subscription.Equals(subID) {
+		return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+
+	sm.mux.Lock()
+	sub := sm.durableSubs[*subID]
+	sm.mux.Unlock()
+	if sub == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDeadLetterSubscriptionNotActive, subID)
+	}
+	transport, err := sm.getTransport(ctx, sub.definition.Transport)
+	if err != nil {
+		return err
+	}
+	replayer, ok := transport.(events.DeadLetterReplayer)
+	if !ok {
+		return i18n.NewError(ctx, coremsgs.MsgDeadLetterReplayNotSupported, transport.Name())
+	}
+
+	event, err := sm.database.GetEventByID(ctx, namespace, deadLetter.Event)
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	enrichedEvent, err := sm.enricher.enrichEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	delivery := &core.EventDelivery{
+		EnrichedEvent: *enrichedEvent,
+		Subscription:  sub.definition.SubscriptionRef,
+	}
+	if err := enrichDelivery(ctx, sm.enricher.subscriptionEnrichers(&sub.definition.Options), newEnrichmentBatch(), delivery); err != nil {
+		return err
+	}
+	var data core.DataArray
+	withData := sub.definition.Options.WithData != nil && *sub.definition.Options.WithData
+	if withData && delivery.Message != nil {
+		if data, _, err = sm.data.GetMessageDataCached(ctx, delivery.Message); err != nil {
+			return err
+		}
+	}
+
+	reply, err := replayer.ReplayDelivery(ctx, sub.definition, delivery, data)
+	if err != nil {
+		update := database.DeadLetterQueryFactory.NewUpdate(ctx).
+			Set("error", err.Error()).
+			Set("updated", fftypes.Now())
+		if updateErr := sm.database.UpdateDeadLetter(ctx, namespace, id, update); updateErr != nil {
+			log.L(ctx).Errorf("Failed to record replay error on dead letter %s: %s", id, updateErr)
+		}
+		return i18n.NewError(ctx, coremsgs.MsgDeadLetterReplayFailed, event.ID, err)
+	}
+	if reply != nil {
+		sendReply(ctx, sm.broadcast, sm.messaging, event, reply)
+	}
+	return sm.database.DeleteDeadLetter(ctx, namespace, id)
+}
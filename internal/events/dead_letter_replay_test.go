@@ -0,0 +1,263 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type replayingTransport struct {
+	*eventsmocks.Plugin
+	*eventsmocks.DeadLetterReplayer
+}
+
+type testDeadLetterReplay struct {
+	sm         *subscriptionManager
+	sub        *core.Subscription
+	event      *core.Event
+	deadLetter *core.DeadLetter
+	mdi        *databasemocks.Plugin
+	mdm        *datamocks.Manager
+	mdr        *eventsmocks.DeadLetterReplayer
+	cancel     func()
+}
+
+func newTestDeadLetterReplay(t *testing.T) *testDeadLetterReplay {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	mdr := &eventsmocks.DeadLetterReplayer{}
+	sm.transports["replay"] = &replayingTransport{Plugin: mei, DeadLetterReplayer: mdr}
+
+	yes := true
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Transport:       "replay",
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{WithData: &yes},
+		},
+	}
+	sm.durableSubs[*sub.ID] = &subscription{definition: sub}
+
+	event := &core.Event{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.EventTypeMessageConfirmed,
+		Reference: fftypes.NewUUID(),
+	}
+	return &testDeadLetterReplay{
+		sm:    sm,
+		sub:   sub,
+		event: event,
+		deadLetter: &core.DeadLetter{
+			ID:           fftypes.NewUUID(),
+			Namespace:    "ns1",
+			Subscription: sub.ID,
+			Event:        event.ID,
+			EventType:    event.Type,
+		},
+		mdi:    sm.database.(*databasemocks.Plugin),
+		mdm:    sm.data.(*datamocks.Manager),
+		mdr:    mdr,
+		cancel: cancel,
+	}
+}
+
+func (tr *testDeadLetterReplay) cleanup(t *testing.T) {
+	tr.cancel()
+	tr.mdi.AssertExpectations(t)
+	tr.mdm.AssertExpectations(t)
+	tr.mdr.AssertExpectations(t)
+}
+
+func (tr *testDeadLetterReplay) expectEvent() *core.Message {
+	msg := &core.Message{Header: core.MessageHeader{ID: tr.event.Reference}}
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+	tr.mdi.On("GetEventByID", mock.Anything, "ns1", tr.event.ID).Return(tr.event, nil)
+	tr.mdm.On("GetMessageWithDataCached", mock.Anything, tr.event.Reference).Return(msg, nil, true, nil)
+	return msg
+}
+
+func TestReplayDeadLetterOk(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	msg := tr.expectEvent()
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	tr.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(data, true, nil)
+	tr.mdr.On("ReplayDelivery", mock.Anything, tr.sub, mock.MatchedBy(func(ed *core.EventDelivery) bool {
+		return ed.ID.Equals(tr.event.ID) && ed.Subscription.ID.Equals(tr.sub.ID)
+	}), data).Return(nil, nil)
+	tr.mdi.On("DeleteDeadLetter", mock.Anything, "ns1", tr.deadLetter.ID).Return(nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.NoError(t, err)
+}
+
+func TestReplayDeadLetterWithReply(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	msg := tr.expectEvent()
+	tr.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(core.DataArray{}, true, nil)
+	reply := &core.MessageInOut{Message: core.Message{Header: core.MessageHeader{CID: msg.Header.ID}}}
+	tr.mdr.On("ReplayDelivery", mock.Anything, tr.sub, mock.Anything, core.DataArray{}).Return(reply, nil)
+	mms := &syncasyncmocks.Sender{}
+	mbm := tr.sm.broadcast.(*broadcastmocks.Manager)
+	mbm.On("NewBroadcast", reply).Return(mms)
+	mms.On("Send", mock.Anything).Return(nil)
+	tr.mdi.On("DeleteDeadLetter", mock.Anything, "ns1", tr.deadLetter.ID).Return(nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.NoError(t, err)
+
+	mbm.AssertExpectations(t)
+	mms.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterDeliveryFail(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	msg := tr.expectEvent()
+	tr.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(core.DataArray{}, true, nil)
+	tr.mdr.On("ReplayDelivery", mock.Anything, tr.sub, mock.Anything, core.DataArray{}).Return(nil, fmt.Errorf("pop"))
+	tr.mdi.On("UpdateDeadLetter", mock.Anything, "ns1", tr.deadLetter.ID, mock.Anything).Return(fmt.Errorf("bang"))
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.Regexp(t, "FF10537.*pop", err)
+}
+
+func TestReplayDeadLetterGetDataFail(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	msg := tr.expectEvent()
+	tr.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(nil, false, fmt.Errorf("pop"))
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayDeadLetterEnrichFail(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+	tr.mdi.On("GetEventByID", mock.Anything, "ns1", tr.event.ID).Return(tr.event, nil)
+	tr.mdm.On("GetMessageWithDataCached", mock.Anything, tr.event.Reference).Return(nil, nil, false, fmt.Errorf("pop"))
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayDeadLetterEnrichersFail(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+	tr.sub.Options.Enrich = []core.SubOptsEnricher{core.SubOptsEnricherMessageData}
+
+	msg := tr.expectEvent()
+	tr.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(nil, false, fmt.Errorf("pop"))
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayDeadLetterEventNotFound(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+	tr.mdi.On("GetEventByID", mock.Anything, "ns1", tr.event.ID).Return(nil, nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReplayDeadLetterGetEventFail(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+	tr.mdi.On("GetEventByID", mock.Anything, "ns1", tr.event.ID).Return(nil, fmt.Errorf("pop"))
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayDeadLetterNotSupported(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.sub.Transport = "ut"
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.Regexp(t, "FF10535", err)
+}
+
+func TestReplayDeadLetterUnknownTransport(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.sub.Transport = "wrong"
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.Regexp(t, "FF10172", err)
+}
+
+func TestReplayDeadLetterSubscriptionNotActive(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	delete(tr.sm.durableSubs, *tr.sub.ID)
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.Regexp(t, "FF10536", err)
+}
+
+func TestReplayDeadLetterWrongSubscription(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(tr.deadLetter, nil)
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, fftypes.NewUUID(), tr.deadLetter.ID)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReplayDeadLetterGetFail(t *testing.T) {
+	tr := newTestDeadLetterReplay(t)
+	defer tr.cleanup(t)
+
+	tr.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", tr.deadLetter.ID).Return(nil, fmt.Errorf("pop"))
+
+	err := tr.sm.replayDeadLetter(tr.sm.ctx, tr.sub.ID, tr.deadLetter.ID)
+	assert.EqualError(t, err, "pop")
+}
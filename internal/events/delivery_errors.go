@@ -0,0 +1,81 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// deliveryErrors keeps a bounded, in-memory history of events rejected by subscription transports,
+// as these are not persisted anywhere else
+type deliveryErrors struct {
+	mux     sync.Mutex
+	records []*deliveryError
+	next    int
+	full    bool
+}
+
+type deliveryError struct {
+	subscription string
+	time         *fftypes.FFTime
+	info         string
+}
+
+func newDeliveryErrors(size int) *deliveryErrors {
+	if size < 1 {
+		size = 1
+	}
+	return &deliveryErrors{
+		records: make([]*deliveryError, size),
+	}
+}
+
+func (de *deliveryErrors) record(sub *core.Subscription, info string) {
+	name := sub.Name
+	if name == "" {
+		name = sub.ID.String() // ephemeral subscriptions have no name
+	}
+	de.mux.Lock()
+	defer de.mux.Unlock()
+	de.records[de.next] = &deliveryError{
+		subscription: name,
+		time:         fftypes.Now(),
+		info:         info,
+	}
+	de.next = (de.next + 1) % len(de.records)
+	if de.next == 0 {
+		de.full = true
+	}
+}
+
+func (de *deliveryErrors) summary(since *fftypes.FFTime) *core.ErrorReportCategoryStatus {
+	cs := core.NewErrorReportCategoryStatus(core.ErrorReportCategorySubscriptionDeliveries)
+	de.mux.Lock()
+	defer de.mux.Unlock()
+	for _, r := range de.records {
+		if r != nil && !r.time.Time().Before(*since.Time()) {
+			cs.AddError(r.subscription, r.time, r.info)
+		}
+	}
+	// If we have wrapped, and the oldest record is still in the window, we've lost some
+	cs.Truncated = de.full && !de.records[de.next].time.Time().Before(*since.Time())
+	cs.SortGroups()
+	return cs
+}
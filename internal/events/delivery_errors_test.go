@@ -0,0 +1,71 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryErrorsSummary(t *testing.T) {
+	de := newDeliveryErrors(10)
+	since := fftypes.Now()
+
+	sub1 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Name: "sub1"}}
+	ephemeral := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	de.record(sub1, "pop")
+	de.record(sub1, "pop")
+	de.record(sub1, "bang")
+	de.record(ephemeral, "fizz")
+
+	cs := de.summary(since)
+	assert.Equal(t, core.ErrorReportCategorySubscriptionDeliveries, cs.Category)
+	assert.Equal(t, int64(4), cs.Count)
+	assert.False(t, cs.Truncated)
+	assert.Len(t, cs.Groups, 2)
+	assert.Equal(t, "sub1", cs.Groups[0].Name)
+	assert.Equal(t, int64(3), cs.Groups[0].Count)
+	assert.Equal(t, []string{"pop", "bang"}, cs.Groups[0].Messages)
+	assert.Equal(t, ephemeral.ID.String(), cs.Groups[1].Name)
+
+	future := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	cs = de.summary(&future)
+	assert.Equal(t, int64(0), cs.Count)
+	assert.Empty(t, cs.Groups)
+}
+
+func TestDeliveryErrorsWrap(t *testing.T) {
+	de := newDeliveryErrors(0)
+	since := fftypes.FFTime(time.Now().Add(-1 * time.Hour))
+
+	sub1 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Name: "sub1"}}
+	de.record(sub1, "pop")
+	de.record(sub1, "bang")
+
+	cs := de.summary(&since)
+	assert.Equal(t, int64(1), cs.Count)
+	assert.True(t, cs.Truncated)
+	assert.Equal(t, []string{"bang"}, cs.Groups[0].Messages)
+
+	future := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	cs = de.summary(&future)
+	assert.False(t, cs.Truncated)
+}
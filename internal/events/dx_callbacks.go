@@ -130,6 +130,98 @@ func (em *eventManager) privateBatchReceived(peerID string, batch *core.Batch, w
 	return manifest, err
 }
 
+func (em *eventManager) messageAcksReceived(peerID string, acks []*core.MessageAck) error {
+	if em.multiparty == nil {
+		log.L(em.ctx).Errorf("Ignoring message acks from non-multiparty network!")
+		return nil
+	}
+
+	return em.retry.Do(em.ctx, "message acks received", func(attempt int) (bool, error) {
+		return true, em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
+			for _, ack := range acks {
+				if err := em.persistMessageAck(ctx, peerID, ack); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// persistMessageAck records an acknowledgement from a recipient of a private message sent by this node,
+// after checking the recipient is a member of the group the message was sent to.
+// Only returns retryable errors - invalid acks are logged and ignored.
+func (em *eventManager) persistMessageAck(ctx context.Context, peerID string, ack *core.MessageAck) error {
+	l := log.L(ctx)
+	if ack.Namespace != em.namespace.NetworkName {
+		l.Debugf("Ignoring message ack from different namespace '%s'", ack.Namespace)
+		return nil
+	}
+
+	if valid, err := em.checkReceivedOffchainIdentity(ctx, peerID, ack.Author, ack.Node); err != nil {
+		return err
+	} else if !valid {
+		l.Errorf("Message ack '%s' received from invalid author '%s' for peer '%s'", ack.ID, ack.Author, peerID)
+		return nil
+	}
+
+	msg, err := em.database.GetMessageByID(ctx, em.namespace.Name, ack.Message)
+	if err != nil {
+		return err
+	}
+	if msg == nil || msg.Header.Group == nil {
+		l.Errorf("Message ack '%s' received for unknown private message '%s'", ack.ID, ack.Message)
+		return nil
+	}
+	group, err := em.database.GetGroupByHash(ctx, em.namespace.Name, msg.Header.Group)
+	if err != nil {
+		return err
+	}
+	isMember := false
+	if group != nil {
+		for _, member := range group.Members {
+			if member.Identity == ack.Author && member.Node.Equals(ack.Node) {
+				isMember = true
+				break
+			}
+		}
+	}
+	if !isMember {
+		l.Errorf("Message ack '%s' received from '%s' who is not a member of group '%s'", ack.ID, ack.Author, msg.Header.Group)
+		return nil
+	}
+
+	// Acks are re-sent if the data exchange send is retried, so ignore any we have already recorded
+	fb := database.MessageAckQueryFactory.NewFilter(ctx)
+	existing, _, err := em.database.GetMessageAcks(ctx, em.namespace.Name, fb.And(fb.Eq("id", ack.ID)))
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		l.Debugf("Ignoring duplicate message ack '%s'", ack.ID)
+		return nil
+	}
+
+	ack.Namespace = em.namespace.Name
+	return em.database.InsertMessageAck(ctx, ack)
+}
+
+// queueMessageAcks acknowledges receipt of a private batch to the sending node, in the background.
+// Unpinned messages are confirmed on receipt, so are acknowledged as confirmed at the same time.
+func (em *eventManager) queueMessageAcks(batch *core.Batch) {
+	msgIDs := make([]*fftypes.UUID, len(batch.Payload.Messages))
+	for i, msg := range batch.Payload.Messages {
+		msgIDs[i] = msg.Header.ID
+	}
+	ackTypes := []core.MessageAckType{core.MessageAckTypeDelivered}
+	if !core.IsPinned(batch.Payload.TX.Type) {
+		ackTypes = append(ackTypes, core.MessageAckTypeConfirmed)
+	}
+	for _, ackType := range ackTypes {
+		em.messaging.QueueMessageAcks(em.ctx, batch.Node, ackType, msgIDs)
+	}
+}
+
 func (em *eventManager) markUnpinnedMessagesConfirmed(ctx context.Context, batch *core.Batch) error {
 
 	// Update all the messages in the batch with the batch ID
@@ -185,6 +277,22 @@ func (em *eventManager) messageReceived(dx dataexchange.Plugin, event dataexchan
 	l := log.L(em.ctx)
 
 	mr := event.MessageReceived()
+	if mr.Transport.Ping != nil {
+		// The sender only needs to know the ping was delivered, so there is nothing to process
+		l.Infof("Network ping '%s' received from %s peer '%s'", mr.Transport.Ping.ID, dx.Name(), mr.PeerID)
+		event.Ack()
+		return
+	}
+	if mr.Transport.Batch == nil && len(mr.Transport.Acks) > 0 {
+		l.Infof("Message acks received from %s peer '%s'", dx.Name(), mr.PeerID)
+		if err := em.messageAcksReceived(mr.PeerID, mr.Transport.Acks); err != nil {
+			l.Warnf("Exited while persisting message acks: %s", err)
+			return
+		}
+		event.Ack()
+		return
+	}
+
 	l.Infof("Private batch received from %s peer '%s'", dx.Name(), mr.PeerID)
 
 	manifestString, err := em.privateBatchReceived(mr.PeerID, mr.Transport.Batch, mr.Transport.Group)
@@ -194,6 +302,9 @@ func (em *eventManager) messageReceived(dx dataexchange.Plugin, event dataexchan
 		return
 	}
 	event.AckWithManifest(manifestString)
+	if manifestString != "" {
+		em.queueMessageAcks(mr.Transport.Batch)
+	}
 }
 
 func (em *eventManager) privateBlobReceived(dx dataexchange.Plugin, event dataexchange.DXEvent) {
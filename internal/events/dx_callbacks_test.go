@@ -700,6 +700,218 @@ func TestMessageReceiveUnpinnedBatchOk(t *testing.T) {
 
 	mde.AssertExpectations(t)
 	mdx.AssertExpectations(t)
+	msgIDs := []*fftypes.UUID{batch.Payload.Messages[0].Header.ID}
+	em.mpm.AssertCalled(t, "QueueMessageAcks", em.ctx, node1.ID, core.MessageAckTypeDelivered, msgIDs)
+	em.mpm.AssertCalled(t, "QueueMessageAcks", em.ctx, node1.ID, core.MessageAckTypeConfirmed, msgIDs)
+}
+
+func newTestMessageAck(org, node *core.Identity, msgID *fftypes.UUID) *core.MessageAck {
+	return &core.MessageAck{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Message:   msgID,
+		Type:      core.MessageAckTypeDelivered,
+		Author:    org.DID,
+		Node:      node.ID,
+		Created:   fftypes.Now(),
+	}
+}
+
+func TestMessageAcksReceivedOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	groupHash := fftypes.NewRandB32()
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Group: groupHash}}
+	ack1 := newTestMessageAck(org1, node1, msg.Header.ID)
+	ack2 := newTestMessageAck(org1, node1, msg.Header.ID)
+	ack2.Type = core.MessageAckTypeConfirmed
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, &core.VerifierRef{
+		Type:  core.VerifierTypeFFDXPeerID,
+		Value: "peer1",
+	}).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, org1.DID).Return(org1, false, nil)
+	em.mim.On("ValidateNodeOwner", em.ctx, node1, org1).Return(true, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", groupHash).Return(&core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Members: core.Members{
+				{Identity: "did:firefly:org/org2", Node: fftypes.NewUUID()},
+				{Identity: org1.DID, Node: node1.ID},
+			},
+		},
+	}, nil)
+	em.mdi.On("GetMessageAcks", em.ctx, "ns1", mock.Anything).Return([]*core.MessageAck{}, nil, nil).Once()
+	em.mdi.On("GetMessageAcks", em.ctx, "ns1", mock.Anything).Return([]*core.MessageAck{ack2}, nil, nil).Once()
+	em.mdi.On("InsertMessageAck", em.ctx, ack1).Return(nil)
+
+	mde := newMessageReceivedNoAck("peer1", &core.TransportWrapper{Acks: []*core.MessageAck{ack1, ack2}})
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	em.mdi.AssertNotCalled(t, "InsertMessageAck", em.ctx, ack2)
+}
+
+func TestNetworkPingReceived(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mde := newMessageReceivedNoAck("peer1", &core.TransportWrapper{Ping: &core.NetworkPing{ID: fftypes.NewUUID()}})
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageAcksReceivedIgnored(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	groupHash := fftypes.NewRandB32()
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Group: groupHash}}
+	broadcastMsg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	otherGroupMsg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Group: fftypes.NewRandB32()}}
+
+	ackWrongNS := newTestMessageAck(org1, node1, msg.Header.ID)
+	ackWrongNS.Namespace = "ns2"
+	ackBadAuthor := newTestMessageAck(org1, node1, msg.Header.ID)
+	ackBadAuthor.Author = "did:firefly:org/unknown"
+	ackUnknownMsg := newTestMessageAck(org1, node1, fftypes.NewUUID())
+	ackBroadcast := newTestMessageAck(org1, node1, broadcastMsg.Header.ID)
+	ackNoGroup := newTestMessageAck(org1, node1, otherGroupMsg.Header.ID)
+	ackNotMember := newTestMessageAck(org1, node1, msg.Header.ID)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, &core.VerifierRef{
+		Type:  core.VerifierTypeFFDXPeerID,
+		Value: "peer1",
+	}).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, org1.DID).Return(org1, false, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, "did:firefly:org/unknown").Return(nil, false, fmt.Errorf("not found"))
+	em.mim.On("ValidateNodeOwner", em.ctx, node1, org1).Return(true, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", ackUnknownMsg.Message).Return(nil, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", broadcastMsg.Header.ID).Return(broadcastMsg, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", otherGroupMsg.Header.ID).Return(otherGroupMsg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", otherGroupMsg.Header.Group).Return(nil, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", groupHash).Return(&core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Members: core.Members{
+				{Identity: org1.DID, Node: fftypes.NewUUID()},
+			},
+		},
+	}, nil)
+
+	mde := newMessageReceivedNoAck("peer1", &core.TransportWrapper{Acks: []*core.MessageAck{
+		ackWrongNS, ackBadAuthor, ackUnknownMsg, ackBroadcast, ackNoGroup, ackNotMember,
+	}})
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	em.mdi.AssertNotCalled(t, "InsertMessageAck", mock.Anything, mock.Anything)
+}
+
+func TestMessageAcksReceivedNonMultiparty(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.multiparty = nil
+
+	err := em.messageAcksReceived("peer1", []*core.MessageAck{{}})
+	assert.NoError(t, err)
+}
+
+func TestMessageAcksReceivedIdentityFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // retryable error
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newMessageReceivedNoAck("peer1", &core.TransportWrapper{Acks: []*core.MessageAck{
+		newTestMessageAck(org1, node1, fftypes.NewUUID()),
+	}})
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestPersistMessageAckGetMessageFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	ack := newTestMessageAck(org1, node1, fftypes.NewUUID())
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, mock.Anything).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, org1.DID).Return(org1, false, nil)
+	em.mim.On("ValidateNodeOwner", em.ctx, node1, org1).Return(true, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", ack.Message).Return(nil, fmt.Errorf("pop"))
+
+	err := em.persistMessageAck(em.ctx, "peer1", ack)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPersistMessageAckGetGroupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Group: fftypes.NewRandB32()}}
+	ack := newTestMessageAck(org1, node1, msg.Header.ID)
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, mock.Anything).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, org1.DID).Return(org1, false, nil)
+	em.mim.On("ValidateNodeOwner", em.ctx, node1, org1).Return(true, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", ack.Message).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", msg.Header.Group).Return(nil, fmt.Errorf("pop"))
+
+	err := em.persistMessageAck(em.ctx, "peer1", ack)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPersistMessageAckGetAcksFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Group: fftypes.NewRandB32()}}
+	ack := newTestMessageAck(org1, node1, msg.Header.ID)
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, mock.Anything).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, org1.DID).Return(org1, false, nil)
+	em.mim.On("ValidateNodeOwner", em.ctx, node1, org1).Return(true, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", ack.Message).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", msg.Header.Group).Return(&core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Members: core.Members{{Identity: org1.DID, Node: node1.ID}},
+		},
+	}, nil)
+	em.mdi.On("GetMessageAcks", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.persistMessageAck(em.ctx, "peer1", ack)
+	assert.EqualError(t, err, "pop")
 }
 
 func TestMessageReceiveUnpinnedBatchConfirmMessagesFail(t *testing.T) {
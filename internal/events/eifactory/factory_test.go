@@ -45,6 +45,13 @@ func TestGetPluginWebHooks(t *testing.T) {
 	assert.NotNil(t, plugin)
 }
 
+func TestGetPluginSSE(t *testing.T) {
+	ctx := context.Background()
+	plugin, err := GetPlugin(ctx, "sse")
+	assert.NoError(t, err)
+	assert.NotNil(t, plugin)
+}
+
 func TestGetPluginEvents(t *testing.T) {
 	ctx := context.Background()
 	plugin, err := GetPlugin(ctx, "system")
@@ -32,6 +32,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -51,30 +52,35 @@ type ackNack struct {
 }
 
 type eventDispatcher struct {
-	acksNacks     chan ackNack
-	cancelCtx     func()
-	closed        chan struct{}
-	connID        string
-	ctx           context.Context
-	enricher      *eventEnricher
-	data          data.Manager
-	database      database.Plugin
-	transport     events.Plugin
-	broadcast     broadcast.Manager        // optional
-	messaging     privatemessaging.Manager // optional
-	elected       bool
-	eventPoller   *eventPoller
-	inflight      map[fftypes.UUID]*core.Event
-	eventDelivery chan []*core.EventDelivery
-	mux           sync.Mutex
-	namespace     string
-	readAhead     int
-	batch         bool
-	subscription  *subscription
-	txHelper      txcommon.Helper
+	acksNacks      chan ackNack
+	cancelCtx      func()
+	closed         chan struct{}
+	connID         string
+	ctx            context.Context
+	enricher       *eventEnricher
+	enrichers      []subscriptionEnricher
+	data           data.Manager
+	database       database.Plugin
+	transport      events.Plugin
+	broadcast      broadcast.Manager        // optional
+	messaging      privatemessaging.Manager // optional
+	elected        bool
+	eventPoller    *eventPoller
+	inflight       map[fftypes.UUID]*core.Event
+	dispatchTimes  map[fftypes.UUID]time.Time // only populated when metrics are enabled
+	eventDelivery  chan []*core.EventDelivery
+	mux            sync.Mutex
+	namespace      string
+	readAhead      int
+	batch          bool
+	subscription   *subscription
+	txHelper       txcommon.Helper
+	deliveryErrors *deliveryErrors
+	metrics        metrics.Manager
+	replayMarker   *core.Event
 }
 
-func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.Plugin, di database.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, connID string, sub *subscription, en *eventNotifier, txHelper txcommon.Helper) *eventDispatcher {
+func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.Plugin, di database.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, connID string, sub *subscription, en *eventNotifier, txHelper txcommon.Helper, de *deliveryErrors) *eventDispatcher {
 	ctx, cancelCtx := context.WithCancel(ctx)
 	readAhead := uint(0)
 	if sub.definition.Options.ReadAhead != nil {
@@ -93,23 +99,28 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 		ctx: log.WithLogField(log.WithLogField(ctx,
 			"role", fmt.Sprintf("ed[%s]", connID)),
 			"sub", fmt.Sprintf("%s/%s:%s", sub.definition.ID, sub.definition.Namespace, sub.definition.Name)),
-		enricher:      enricher,
-		database:      di,
-		transport:     ei,
-		broadcast:     bm,
-		messaging:     pm,
-		data:          dm,
-		connID:        connID,
-		cancelCtx:     cancelCtx,
-		subscription:  sub,
-		namespace:     sub.definition.Namespace,
-		inflight:      make(map[fftypes.UUID]*core.Event),
-		eventDelivery: make(chan []*core.EventDelivery, readAhead+1),
-		readAhead:     int(readAhead),
-		acksNacks:     make(chan ackNack),
-		closed:        make(chan struct{}),
-		txHelper:      txHelper,
-		batch:         batch,
+		enricher:       enricher,
+		enrichers:      enricher.subscriptionEnrichers(&sub.definition.Options),
+		database:       di,
+		transport:      ei,
+		broadcast:      bm,
+		messaging:      pm,
+		data:           dm,
+		connID:         connID,
+		cancelCtx:      cancelCtx,
+		subscription:   sub,
+		namespace:      sub.definition.Namespace,
+		inflight:       make(map[fftypes.UUID]*core.Event),
+		dispatchTimes:  make(map[fftypes.UUID]time.Time),
+		eventDelivery:  make(chan []*core.EventDelivery, readAhead+1),
+		readAhead:      int(readAhead),
+		acksNacks:      make(chan ackNack),
+		closed:         make(chan struct{}),
+		txHelper:       txHelper,
+		batch:          batch,
+		deliveryErrors: de,
+		metrics:        enricher.metrics,
+		replayMarker:   sub.replayMarker,
 	}
 
 	pollerConf := &eventPollerConf{
@@ -122,15 +133,26 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 			MaximumDelay: config.GetDuration(coreconfig.EventDispatcherRetryMaxDelay),
 			Factor:       config.GetFloat64(coreconfig.EventDispatcherRetryFactor),
 		},
-		namespace:        sub.definition.Namespace,
-		offsetType:       core.OffsetTypeSubscription,
-		offsetName:       sub.definition.ID.String(),
-		addCriteria:      func(af ffapi.AndFilter) ffapi.AndFilter { return af },
-		queryFactory:     database.EventQueryFactory,
-		getItems:         ed.getEvents,
-		newEventsHandler: ed.bufferedDelivery,
-		ephemeral:        sub.definition.Ephemeral,
-		firstEvent:       sub.definition.Options.FirstEvent,
+		offsetCommitRetry: &retry.Retry{
+			InitialDelay: config.GetDuration(coreconfig.EventDispatcherOffsetCommitRetryInitDelay),
+			MaximumDelay: config.GetDuration(coreconfig.EventDispatcherOffsetCommitRetryMaxDelay),
+			Factor:       config.GetFloat64(coreconfig.EventDispatcherOffsetCommitRetryFactor),
+			ErrCallback:  ed.offsetCommitError,
+		},
+		offsetCommitRetryAttempts:  config.GetInt(coreconfig.EventDispatcherOffsetCommitRetryMaxAttempts),
+		offsetCommitRetryExhausted: ed.offsetCommitRetryExhausted,
+		namespace:                  sub.definition.Namespace,
+		offsetType:                 core.OffsetTypeSubscription,
+		offsetName:                 sub.definition.ID.String(),
+		addCriteria:                func(af ffapi.AndFilter) ffapi.AndFilter { return af },
+		queryFactory:               database.EventQueryFactory,
+		getItems:                   ed.getEvents,
+		newEventsHandler:           ed.bufferedDelivery,
+		ephemeral:                  sub.definition.Ephemeral,
+		firstEvent:                 sub.definition.Options.FirstEvent,
+	}
+	if ed.metrics.IsMetricsEnabled() {
+		pollerConf.reportLag = ed.subscriptionLag
 	}
 
 	// Users can tune the batch related settings.
@@ -311,6 +333,7 @@ func (ed *eventDispatcher) handleNackOffsetUpdate(nack ackNack) {
 		ed.eventPoller.rewindPollingOffset(nack.offset - 1)
 	}
 	ed.inflight = map[fftypes.UUID]*core.Event{}
+	ed.dispatchTimes = map[fftypes.UUID]time.Time{}
 }
 
 func (ed *eventDispatcher) handleAckOffsetUpdate(ack ackNack) {
@@ -332,6 +355,9 @@ func (ed *eventDispatcher) handleAckOffsetUpdate(ack ackNack) {
 
 func (ed *eventDispatcher) deliverEvents() {
 	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	if ed.replayMarker != nil {
+		ed.deliverReplayMarker()
+	}
 	for {
 		select {
 		case events, ok := <-ed.eventDelivery:
@@ -341,6 +367,7 @@ func (ed *eventDispatcher) deliverEvents() {
 
 			// As soon as we hit an error, we need to trigger into nack mode
 			var err error
+			enrichments := newEnrichmentBatch()
 
 			// Loop through the events enriching them, and dispatching individually in non-batch mode
 			eventsWithData := make([]*core.CombinedEventDataDelivery, len(events))
@@ -355,16 +382,20 @@ func (ed *eventDispatcher) deliverEvents() {
 					if withData && e.Event.Message != nil {
 						e.Data, _, err = ed.data.GetMessageDataCached(ed.ctx, e.Event.Message)
 					}
+					if err == nil {
+						err = enrichDelivery(ed.ctx, ed.enrichers, enrichments, e.Event)
+					}
 				}
 				// If we are non-batched, we have to deliver each event individually...
 				if !ed.batch {
 					// .. only attempt to deliver if we've not triggered into an error scenario for one of the events already
 					if err == nil {
+						ed.eventDispatched(e.Event)
 						err = ed.transport.DeliveryRequest(ed.ctx, ed.connID, ed.subscription.definition, e.Event, e.Data)
 					}
 					// ... if we've triggered into an error scenario, we need to nack immediately for this and all the rest of the events
 					if err != nil {
-						ed.deliveryResponse(&core.EventDeliveryResponse{ID: e.Event.ID, Rejected: true})
+						ed.deliveryResponse(&core.EventDeliveryResponse{ID: e.Event.ID, Rejected: true, Info: err.Error()})
 					}
 				}
 			}
@@ -373,12 +404,15 @@ func (ed *eventDispatcher) deliverEvents() {
 			if ed.batch {
 				// Only attempt to deliver if we're in a non error case (enrich might have failed above)
 				if err == nil {
+					for _, e := range events {
+						ed.eventDispatched(e)
+					}
 					err = ed.transport.BatchDeliveryRequest(ed.ctx, ed.connID, ed.subscription.definition, eventsWithData)
 				}
 				// If we're in an error case we have to nack everything immediately
 				if err != nil {
 					for _, e := range events {
-						ed.deliveryResponse(&core.EventDeliveryResponse{ID: e.Event.ID, Rejected: true})
+						ed.deliveryResponse(&core.EventDeliveryResponse{ID: e.Event.ID, Rejected: true, Info: err.Error()})
 					}
 				}
 			}
@@ -389,12 +423,37 @@ func (ed *eventDispatcher) deliverEvents() {
 	}
 }
 
+// deliverReplayMarker tells the application the subscription has been rewound, before any of the replayed events.
+// The marker is not persisted, so it is not redelivered if it is rejected.
+func (ed *eventDispatcher) deliverReplayMarker() {
+	delivery := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{Event: *ed.replayMarker},
+		Subscription:  ed.subscription.definition.SubscriptionRef,
+	}
+	var err error
+	if ed.batch {
+		err = ed.transport.BatchDeliveryRequest(ed.ctx, ed.connID, ed.subscription.definition, []*core.CombinedEventDataDelivery{{Event: delivery}})
+	} else {
+		err = ed.transport.DeliveryRequest(ed.ctx, ed.connID, ed.subscription.definition, delivery, nil)
+	}
+	if err != nil {
+		log.L(ed.ctx).Warnf("Failed to deliver replay marker %s: %s", ed.replayMarker.ID, err)
+	}
+}
+
 func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse) {
 	l := log.L(ed.ctx)
 
+	if ed.replayMarker != nil && ed.replayMarker.ID.Equals(response.ID) {
+		l.Debugf("Response for replay marker %s rejected=%t", response.ID, response.Rejected)
+		return
+	}
+
 	ed.mux.Lock()
 	var an ackNack
 	event, found := ed.inflight[*response.ID]
+	dispatchTime, timed := ed.dispatchTimes[*response.ID]
+	delete(ed.dispatchTimes, *response.ID)
 	if found {
 		an.id = *response.ID
 		an.offset = event.Sequence
@@ -402,6 +461,10 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 	}
 	ed.mux.Unlock()
 
+	if found && timed {
+		ed.metrics.EventAcknowledged(ed.namespace, ed.subscription.definition.Name, ed.transport.Name(), time.Since(dispatchTime))
+	}
+
 	// Do some extra logging and persistent actions now we're out of lock
 	if !found {
 		l.Warnf("Response for event not in flight: %s rejected=%t info='%s' (likely previous reject)", response.ID, response.Rejected, response.Info)
@@ -414,7 +477,17 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 		ed.sendReply(ed.ctx, event, response.Reply)
 	}
 
-	l.Debugf("Response for %s event: %.10d/%s [%s]: ref=%s/%s rejected=%t info='%s'", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference, response.Rejected, response.Info)
+	l.Debugf("Response for %s event: %.10d/%s [%s]: ref=%s/%s rejected=%t deadLetter=%t info='%s'", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference, response.Rejected, response.DeadLetter, response.Info)
+	if response.DeadLetter && !response.Rejected {
+		// The event is only acknowledged once it is safely parked in the dead-letter queue
+		if err := ed.insertDeadLetter(event, response.Info); err != nil {
+			l.Errorf("Failed to move event %s to dead-letter queue: %s", event.ID, err)
+			an.isNack = true
+		}
+	}
+	if response.Rejected || response.DeadLetter {
+		ed.deliveryErrors.record(ed.subscription.definition, response.Info)
+	}
 	// We don't do any meaningful work in this call, we just set things up so the right thing
 	// will happen when the poller wakes up. So we need to pass it over
 	select {
@@ -425,6 +498,49 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 	}
 }
 
+// eventDispatched records the time an event was handed to the transport, so the latency of its ack can be measured
+func (ed *eventDispatcher) eventDispatched(event *core.EventDelivery) {
+	if !ed.metrics.IsMetricsEnabled() {
+		return
+	}
+	ed.mux.Lock()
+	ed.dispatchTimes[*event.ID] = time.Now()
+	ed.mux.Unlock()
+	ed.metrics.EventDispatched(ed.namespace, ed.subscription.definition.Name, ed.transport.Name())
+}
+
+func (ed *eventDispatcher) subscriptionLag(lag int64) {
+	ed.metrics.SubscriptionLag(ed.namespace, ed.subscription.definition.Name, lag)
+}
+
+func (ed *eventDispatcher) insertDeadLetter(event *core.Event, info string) error {
+	return ed.database.InsertDeadLetter(ed.ctx, &core.DeadLetter{
+		ID:           fftypes.NewUUID(),
+		Namespace:    ed.namespace,
+		Subscription: ed.subscription.definition.ID,
+		Event:        event.ID,
+		EventType:    event.Type,
+		Error:        info,
+		Created:      fftypes.Now(),
+	})
+}
+
+func (ed *eventDispatcher) offsetCommitError(err error) {
+	if ed.metrics.IsMetricsEnabled() {
+		ed.metrics.SubscriptionOffsetCommitFailure(ed.namespace, ed.subscription.definition.Name)
+	}
+}
+
+// offsetCommitRetryExhausted emits an event to warn applications that the offset could not be
+// committed, so events since the last committed offset will be re-delivered on restart
+func (ed *eventDispatcher) offsetCommitRetryExhausted(offset int64) {
+	sub := ed.subscription.definition
+	event := core.NewEvent(core.EventTypeSubscriptionOffsetCommitFailed, ed.namespace, sub.ID, nil, sub.ID.String())
+	if err := ed.database.InsertEvent(ed.ctx, event); err != nil {
+		log.L(ed.ctx).Errorf("Failed to emit offset commit failure event for offset %d: %s", offset, err)
+	}
+}
+
 func (ed *eventDispatcher) close() {
 	log.L(ed.ctx).Infof("Dispatcher closing for conn=%s subscription=%s", ed.connID, ed.subscription.definition.ID)
 	ed.cancelCtx()
@@ -33,6 +33,9 @@ import (
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
@@ -56,9 +59,11 @@ func newTestEventDispatcher(sub *subscription) (*eventDispatcher, func()) {
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
-	enricher := newEventEnricher("ns1", mdi, mdm, mom, txHelper)
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(false).Maybe()
+	enricher := newEventEnricher("ns1", mdi, mdm, mom, &identitymanagermocks.Manager{}, &networkmapmocks.Manager{}, txHelper, mmi, cache.NewUmanagedCache(ctx, 100, 5*time.Minute))
 	ctx, cancel := context.WithCancel(context.Background())
-	return newEventDispatcher(ctx, enricher, mei, mdi, mdm, mbm, mpm, fftypes.NewUUID().String(), sub, newEventNotifier(ctx, "ut"), txHelper), func() {
+	return newEventDispatcher(ctx, enricher, mei, mdi, mdm, mbm, mpm, fftypes.NewUUID().String(), sub, newEventNotifier(ctx, "ut"), txHelper, newDeliveryErrors(10)), func() {
 		cancel()
 		coreconfig.Reset()
 	}
@@ -1093,6 +1098,117 @@ func TestBufferedDeliveryFailNack(t *testing.T) {
 
 }
 
+func TestOffsetCommitErrorMetrics(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("SubscriptionOffsetCommitFailure", "ns1", "sub1").Return()
+	ed.metrics = mmi
+
+	ed.offsetCommitError(fmt.Errorf("pop"))
+
+	mmi.AssertExpectations(t)
+}
+
+func TestEventDispatchAckMetrics(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("EventDispatched", "ns1", "sub1", "ut").Return()
+	mmi.On("EventAcknowledged", "ns1", "sub1", "ut", mock.Anything).Return()
+	mmi.On("SubscriptionLag", "ns1", "sub1", int64(0)).Return()
+	ed.metrics = mmi
+	ed.eventPoller.conf.reportLag = ed.subscriptionLag
+
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID(), Sequence: 10},
+		},
+	}
+	ed.inflight[*event.ID] = &event.Event
+	ed.eventDispatched(event)
+
+	go ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID})
+	an := <-ed.acksNacks
+	ed.handleAckOffsetUpdate(an)
+
+	assert.Empty(t, ed.dispatchTimes)
+	assert.Equal(t, int64(10), ed.eventPoller.getPollingOffset())
+	mmi.AssertExpectations(t)
+}
+
+func TestEventDispatchMetricsDisabled(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	ed.eventDispatched(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID()},
+		},
+	})
+	assert.Empty(t, ed.dispatchTimes)
+	assert.Nil(t, ed.eventPoller.conf.reportLag)
+}
+
+func TestOffsetCommitRetryExhausted(t *testing.T) {
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeSubscriptionOffsetCommitFailed &&
+			e.Namespace == "ns1" &&
+			e.Reference.Equals(subID) &&
+			e.Topic == subID.String()
+	})).Return(nil)
+
+	ed.offsetCommitRetryExhausted(12345)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOffsetCommitRetryExhaustedInsertFail(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ed.offsetCommitRetryExhausted(12345)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestAckNotInFlightNoop(t *testing.T) {
 
 	sub := &subscription{
@@ -1200,6 +1316,95 @@ func TestDeliverEventsWithDataFail(t *testing.T) {
 
 }
 
+func TestDeliverEventsWithEnrichers(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Enrich: []core.SubOptsEnricher{core.SubOptsEnricherMessageData},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", ed.ctx, mock.Anything).Return(data, true, nil)
+
+	id1 := fftypes.NewUUID()
+	delivered := make(chan *core.EventDelivery, 1)
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("DeliveryRequest", mock.Anything, ed.connID, sub.definition, mock.Anything, core.DataArray(nil)).
+		Run(func(args mock.Arguments) {
+			delivered <- args[3].(*core.EventDelivery)
+		}).
+		Return(nil)
+
+	ed.eventDelivery <- []*core.EventDelivery{
+		{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{
+					ID: id1,
+				},
+				Message: &core.Message{
+					Header: core.MessageHeader{
+						ID: fftypes.NewUUID(),
+					},
+				},
+			},
+		},
+	}
+
+	ed.inflight[*id1] = &core.Event{ID: id1}
+	go ed.deliverEvents()
+
+	event := <-delivered
+	assert.Equal(t, data, event.Enrichment.Data)
+}
+
+func TestDeliverEventsEnrichersFail(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Enrich: []core.SubOptsEnricher{core.SubOptsEnricherMessageData},
+				},
+			},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", ed.ctx, mock.Anything).Return(nil, false, fmt.Errorf("pop"))
+
+	id1 := fftypes.NewUUID()
+	ed.eventDelivery <- []*core.EventDelivery{
+		{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{
+					ID: id1,
+				},
+				Message: &core.Message{
+					Header: core.MessageHeader{
+						ID: fftypes.NewUUID(),
+					},
+				},
+			},
+		},
+	}
+
+	ed.inflight[*id1] = &core.Event{ID: id1}
+	go ed.deliverEvents()
+
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+}
+
 func TestEventDispatcherWithReply(t *testing.T) {
 	log.SetLevel("debug")
 	var two = uint16(5)
@@ -1303,3 +1508,120 @@ func TestEventDeliveryBatch(t *testing.T) {
 	mbm.AssertExpectations(t)
 	mms.AssertExpectations(t)
 }
+
+func TestEventDispatcherDeadLetter(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.acksNacks = make(chan ackNack, 1)
+
+	event1 := fftypes.NewUUID()
+	ed.inflight[*event1] = &core.Event{
+		ID:        event1,
+		Namespace: "ns1",
+		Type:      core.EventTypeMessageConfirmed,
+	}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertDeadLetter", ed.ctx, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.Subscription.Equals(sub.definition.ID) &&
+			dl.Event.Equals(event1) &&
+			dl.EventType == core.EventTypeMessageConfirmed &&
+			dl.Error == "pop"
+	})).Return(nil)
+
+	since := fftypes.Now()
+	ed.deliveryResponse(&core.EventDeliveryResponse{
+		ID:         event1,
+		DeadLetter: true,
+		Info:       "pop",
+	})
+
+	an := <-ed.acksNacks
+	assert.False(t, an.isNack)
+	assert.Equal(t, int64(1), ed.deliveryErrors.summary(since).Count)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEventDispatcherDeadLetterInsertFail(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.acksNacks = make(chan ackNack, 1)
+
+	event1 := fftypes.NewUUID()
+	ed.inflight[*event1] = &core.Event{ID: event1}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("InsertDeadLetter", ed.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ed.deliveryResponse(&core.EventDeliveryResponse{
+		ID:         event1,
+		DeadLetter: true,
+		Info:       "pop",
+	})
+
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEventDispatcherReplayMarker(t *testing.T) {
+	marker := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeSubscriptionReplay, Sequence: 9}
+	ed, cancel := newTestEventDispatcher(&subscription{
+		definition:   &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}},
+		replayMarker: marker,
+	})
+	defer cancel()
+	assert.Equal(t, marker, ed.replayMarker)
+
+	delivered := make(chan struct{})
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("DeliveryRequest", mock.Anything, ed.connID, mock.Anything, mock.MatchedBy(func(e *core.EventDelivery) bool {
+		return e.ID.Equals(marker.ID) && e.Type == core.EventTypeSubscriptionReplay && e.Subscription.Name == "sub1"
+	}), core.DataArray(nil)).Return(nil).Run(func(args mock.Arguments) {
+		close(delivered)
+	})
+
+	go ed.deliverEvents()
+	<-delivered
+
+	// The response for the marker is not treated as an ack
+	ed.deliveryResponse(&core.EventDeliveryResponse{ID: marker.ID})
+	mei.AssertExpectations(t)
+}
+
+func TestEventDispatcherReplayMarkerBatchFail(t *testing.T) {
+	yes := true
+	marker := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeSubscriptionReplay}
+	ed, cancel := newTestEventDispatcher(&subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{Batch: &yes},
+			},
+		},
+		replayMarker: marker,
+	})
+	defer cancel()
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("BatchDeliveryRequest", mock.Anything, ed.connID, mock.Anything, mock.MatchedBy(func(events []*core.CombinedEventDataDelivery) bool {
+		return len(events) == 1 && events[0].Event.ID.Equals(marker.ID)
+	})).Return(fmt.Errorf("pop"))
+
+	ed.deliverReplayMarker()
+	mei.AssertExpectations(t)
+}
@@ -18,8 +18,16 @@ package events
 
 import (
 	"context"
+	"sync"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/networkmap"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -27,20 +35,36 @@ import (
 )
 
 type eventEnricher struct {
-	namespace  string
-	data       data.Manager
-	database   database.Plugin
-	operations operations.Manager
-	txHelper   txcommon.Helper
+	namespace   string
+	data        data.Manager
+	database    database.Plugin
+	operations  operations.Manager
+	identity    identity.Manager
+	networkmap  networkmap.Manager
+	txHelper    txcommon.Helper
+	metrics     metrics.Manager
+	enrichCache cache.CInterface
+	blockLock   sync.Mutex
+	blockIndex  map[int64][]string
+	indexed     int
+	indexLimit  int
+	latestBlock map[string]int64
 }
 
-func newEventEnricher(ns string, di database.Plugin, dm data.Manager, om operations.Manager, txHelper txcommon.Helper) *eventEnricher {
+func newEventEnricher(ns string, di database.Plugin, dm data.Manager, om operations.Manager, im identity.Manager, nm networkmap.Manager, txHelper txcommon.Helper, mm metrics.Manager, enrichCache cache.CInterface) *eventEnricher {
 	return &eventEnricher{
-		namespace:  ns,
-		data:       dm,
-		database:   di,
-		operations: om,
-		txHelper:   txHelper,
+		namespace:   ns,
+		data:        dm,
+		database:    di,
+		operations:  om,
+		identity:    im,
+		networkmap:  nm,
+		txHelper:    txHelper,
+		metrics:     mm,
+		enrichCache: enrichCache,
+		blockIndex:  make(map[int64][]string),
+		indexLimit:  config.GetInt(coreconfig.CacheEventEnrichmentLimit),
+		latestBlock: make(map[string]int64),
 	}
 }
 
@@ -56,7 +80,101 @@ func (em *eventEnricher) enrichEvents(ctx context.Context, events []*core.Event)
 	return enriched, nil
 }
 
+// enrichEvent is called by every dispatcher that delivers an event, so the same event is
+// commonly enriched once per subscription. The result is cached by event ID to avoid
+// repeating the lookups.
 func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error) {
+	if event.ID == nil {
+		return em.loadEnrichment(ctx, event)
+	}
+	if cached := em.enrichCache.Get(event.ID.String()); cached != nil {
+		em.recordCacheResult(true)
+		return cached.(*core.EnrichedEvent), nil
+	}
+	em.recordCacheResult(false)
+	e, err := em.loadEnrichment(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	em.cacheEnrichment(e)
+	return e, nil
+}
+
+func (em *eventEnricher) recordCacheResult(hit bool) {
+	if em.metrics.IsMetricsEnabled() {
+		em.metrics.EventEnrichmentCache(hit)
+	}
+}
+
+func (em *eventEnricher) cacheEnrichment(e *core.EnrichedEvent) {
+	key := e.ID.String()
+	em.enrichCache.Set(key, e)
+	if e.BlockchainEvent != nil && e.BlockchainEvent.Info != nil {
+		if _, ok := e.BlockchainEvent.Info["blockNumber"]; ok {
+			blockNumber := e.BlockchainEvent.Info.GetInt64("blockNumber")
+			em.blockLock.Lock()
+			em.blockIndex[blockNumber] = append(em.blockIndex[blockNumber], key)
+			em.indexed++
+			em.trimBlockIndex()
+			em.blockLock.Unlock()
+		}
+	}
+}
+
+// trimBlockIndex keeps the index no larger than the cache. The cache does not report evictions, so the
+// entries for the earliest blocks are dropped from both, as those are the most likely to have been evicted.
+// Must be called holding blockLock.
+func (em *eventEnricher) trimBlockIndex() {
+	for em.indexed > em.indexLimit {
+		earliest := int64(-1)
+		for blockNumber := range em.blockIndex {
+			if earliest < 0 || blockNumber < earliest {
+				earliest = blockNumber
+			}
+		}
+		em.dropBlock(earliest)
+	}
+}
+
+// dropBlock removes a block from the index, and the enrichments cached from it. Must be called holding blockLock.
+func (em *eventEnricher) dropBlock(blockNumber int64) (dropped int) {
+	for _, key := range em.blockIndex[blockNumber] {
+		if em.enrichCache.Delete(key) {
+			dropped++
+		}
+	}
+	em.indexed -= len(em.blockIndex[blockNumber])
+	delete(em.blockIndex, blockNumber)
+	return dropped
+}
+
+// blockReceived is called for each new blockchain event. A listener delivering an event from an earlier block
+// than one it has already delivered means the connector has rewound the stream after a re-org, so any cached
+// enrichment derived from that block onwards is dropped.
+func (em *eventEnricher) blockReceived(ctx context.Context, listener string, blockNumber int64) {
+	em.blockLock.Lock()
+	defer em.blockLock.Unlock()
+	if latest, ok := em.latestBlock[listener]; ok && blockNumber < latest {
+		dropped := em.invalidateBlocks(blockNumber)
+		log.L(ctx).Infof("Listener '%s' rewound from block %d to %d. Dropped %d cached event enrichments", listener, latest, blockNumber, dropped)
+	}
+	em.latestBlock[listener] = blockNumber
+}
+
+// invalidateBlocks drops any cached enrichment that was derived from a block at or
+// above the supplied block number, so that events re-delivered after a re-org are
+// enriched from the updated state. Must be called holding blockLock.
+func (em *eventEnricher) invalidateBlocks(fromBlock int64) int {
+	dropped := 0
+	for blockNumber := range em.blockIndex {
+		if blockNumber >= fromBlock {
+			dropped += em.dropBlock(blockNumber)
+		}
+	}
+	return dropped
+}
+
+func (em *eventEnricher) loadEnrichment(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error) {
 	e := &core.EnrichedEvent{
 		Event: *event,
 	}
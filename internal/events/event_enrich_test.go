@@ -28,7 +28,11 @@ import (
 	"github.com/hyperledger/firefly/mocks/cachemocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -42,7 +46,9 @@ func newTestEventEnricher() *eventEnricher {
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
-	return newEventEnricher("ns1", mdi, mdm, mom, txHelper)
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(false).Maybe()
+	return newEventEnricher("ns1", mdi, mdm, mom, &identitymanagermocks.Manager{}, &networkmapmocks.Manager{}, txHelper, mmi, cache.NewUmanagedCache(ctx, 100, 5*time.Minute))
 }
 
 func TestEnrichMessageConfirmed(t *testing.T) {
@@ -700,3 +706,119 @@ func TestEnrichEventsOK(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(result))
 }
+
+func TestEnrichEventCached(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	mmi := em.metrics.(*metricsmocks.Manager)
+	mmi.On("IsMetricsEnabled").Unset()
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("EventEnrichmentCache", false).Once()
+	mmi.On("EventEnrichmentCache", true).Once()
+
+	mdm := em.data.(*datamocks.Manager)
+	mdm.On("GetMessageWithDataCached", mock.Anything, ref1).Return(&core.Message{
+		Header: core.MessageHeader{ID: ref1},
+	}, nil, true, nil).Once()
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeMessageConfirmed,
+		Reference: ref1,
+	}
+
+	enriched1, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	enriched2, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Same(t, enriched1, enriched2)
+
+	mdm.AssertExpectations(t)
+	mmi.AssertExpectations(t)
+}
+
+func TestEnrichEventNoIDNotCached(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	ref1 := fftypes.NewUUID()
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", mock.Anything, "ns1", ref1).Return(&core.Identity{}, nil)
+
+	enriched, err := em.enrichEvent(ctx, &core.Event{Type: core.EventTypeIdentityConfirmed, Reference: ref1})
+	assert.NoError(t, err)
+	assert.Nil(t, enriched.ID)
+	assert.Empty(t, em.blockIndex)
+}
+
+func TestEnrichEventInvalidateBlocks(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	mth := &txcommonmocks.Helper{}
+	em.txHelper = mth
+	events := make([]*core.Event, 3)
+	for i := range events {
+		events[i] = &core.Event{
+			ID:        fftypes.NewUUID(),
+			Type:      core.EventTypeBlockchainEventReceived,
+			Reference: fftypes.NewUUID(),
+		}
+		mth.On("GetBlockchainEventByIDCached", mock.Anything, events[i].Reference).Return(&core.BlockchainEvent{
+			ID:   events[i].Reference,
+			Info: fftypes.JSONObject{"blockNumber": fmt.Sprintf("%d", 100+i)},
+		}, nil).Twice()
+		_, err := em.enrichEvent(ctx, events[i])
+		assert.NoError(t, err)
+	}
+
+	// A listener moving forwards keeps the cache, while a rewind to an earlier block invalidates it
+	em.blockReceived(ctx, "listener1", 101)
+	em.blockReceived(ctx, "listener1", 102)
+	em.blockReceived(ctx, "listener2", 100)
+	assert.Len(t, em.blockIndex, 3)
+	em.blockReceived(ctx, "listener1", 101)
+	assert.Len(t, em.blockIndex, 1)
+	assert.Equal(t, 1, em.indexed)
+
+	// Invalidated events are re-enriched, while earlier blocks are still served from cache
+	for _, event := range events {
+		_, err := em.enrichEvent(ctx, event)
+		assert.NoError(t, err)
+	}
+	mth.AssertNumberOfCalls(t, "GetBlockchainEventByIDCached", 5)
+}
+
+func TestEnrichEventTrimBlockIndex(t *testing.T) {
+	em := newTestEventEnricher()
+	em.indexLimit = 2
+	ctx := context.Background()
+
+	mth := &txcommonmocks.Helper{}
+	em.txHelper = mth
+	events := make([]*core.Event, 3)
+	for i := range events {
+		events[i] = &core.Event{
+			ID:        fftypes.NewUUID(),
+			Type:      core.EventTypeBlockchainEventReceived,
+			Reference: fftypes.NewUUID(),
+		}
+		mth.On("GetBlockchainEventByIDCached", mock.Anything, events[i].Reference).Return(&core.BlockchainEvent{
+			ID:   events[i].Reference,
+			Info: fftypes.JSONObject{"blockNumber": fmt.Sprintf("%d", 102-i)},
+		}, nil)
+		_, err := em.enrichEvent(ctx, events[i])
+		assert.NoError(t, err)
+	}
+
+	// The earliest block is dropped from the index and the cache
+	assert.Equal(t, 2, em.indexed)
+	assert.Len(t, em.blockIndex, 2)
+	assert.Nil(t, em.blockIndex[100])
+	assert.Nil(t, em.enrichCache.Get(events[2].ID.String()))
+	assert.NotNil(t, em.enrichCache.Get(events[0].ID.String()))
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/hyperledger/firefly/internal/identity"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/multiparty"
+	"github.com/hyperledger/firefly/internal/networkmap"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/shareddownload"
@@ -84,6 +85,11 @@ type EventManager interface {
 	TokensApproved(ti tokens.Plugin, approval *tokens.TokenApproval) error
 
 	GetPlugins() []*core.NamespaceStatusPlugin
+	AggregatorStatus() *AggregatorStatus
+	DeliveryErrors(since *fftypes.FFTime) *core.ErrorReportCategoryStatus
+	ReplayDeadLetter(ctx context.Context, subID, id *fftypes.UUID) error
+	ReplaySubscription(ctx context.Context, subID *fftypes.UUID, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error)
+	RunWithAggregatorPaused(ctx context.Context, fn func(ctx context.Context) error) error
 
 	// Internal events
 	system.EventInterface
@@ -116,8 +122,8 @@ type eventManager struct {
 	multiparty         multiparty.Manager // optional
 }
 
-func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, im identity.Manager, dh definitions.Handler, dm data.Manager, ds definitions.Sender, bm broadcast.Manager, pm privatemessaging.Manager, am assets.Manager, sd shareddownload.Manager, mm metrics.Manager, om operations.Manager, txHelper txcommon.Helper, transports map[string]events.Plugin, mp multiparty.Manager, cacheManager cache.Manager) (EventManager, error) {
-	if di == nil || im == nil || dh == nil || dm == nil || om == nil || ds == nil || am == nil {
+func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, im identity.Manager, nm networkmap.Manager, dh definitions.Handler, dm data.Manager, ds definitions.Sender, bm broadcast.Manager, pm privatemessaging.Manager, am assets.Manager, sd shareddownload.Manager, mm metrics.Manager, om operations.Manager, txHelper txcommon.Helper, transports map[string]events.Plugin, mp multiparty.Manager, cacheManager cache.Manager) (EventManager, error) {
+	if di == nil || im == nil || nm == nil || dh == nil || dm == nil || om == nil || ds == nil || am == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "EventManager")
 	}
 	newPinNotifier := newEventNotifier(ctx, "pins")
@@ -135,6 +141,18 @@ func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin
 		return nil, err
 	}
 
+	enrichmentCache, err := cacheManager.GetCache(
+		cache.NewCacheConfig(
+			ctx,
+			coreconfig.CacheEventEnrichmentLimit,
+			coreconfig.CacheEventEnrichmentTTL,
+			ns.Name,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	em := &eventManager{
 		ctx:            log.WithLogField(ctx, "role", "event-manager"),
 		namespace:      ns,
@@ -171,7 +189,7 @@ func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin
 		em.blobReceiver = newBlobReceiver(ctx, em.aggregator)
 	}
 
-	em.enricher = newEventEnricher(ns.Name, di, dm, om, txHelper)
+	em.enricher = newEventEnricher(ns.Name, di, dm, om, im, nm, txHelper, mm, enrichmentCache)
 
 	if em.subManager, err = newSubscriptionManager(ctx, ns, em.enricher, di, dm, newEventNotifier, bm, pm, txHelper, transports); err != nil {
 		return nil, err
@@ -306,6 +324,33 @@ func (em *eventManager) EnrichEvents(ctx context.Context, events []*core.Event)
 	return em.enricher.enrichEvents(ctx, events)
 }
 
+func (em *eventManager) AggregatorStatus() *AggregatorStatus {
+	if em.aggregator == nil {
+		return &AggregatorStatus{Workers: []*AggregatorWorkerStatus{}}
+	}
+	return em.aggregator.status()
+}
+
+func (em *eventManager) DeliveryErrors(since *fftypes.FFTime) *core.ErrorReportCategoryStatus {
+	return em.subManager.deliveryErrors.summary(since)
+}
+
+func (em *eventManager) ReplayDeadLetter(ctx context.Context, subID, id *fftypes.UUID) error {
+	return em.subManager.replayDeadLetter(ctx, subID, id)
+}
+
+func (em *eventManager) ReplaySubscription(ctx context.Context, subID *fftypes.UUID, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error) {
+	return em.subManager.replaySubscription(ctx, subID, input)
+}
+
+// RunWithAggregatorPaused runs the function in a database group, while the aggregator is paused between batches
+func (em *eventManager) RunWithAggregatorPaused(ctx context.Context, fn func(ctx context.Context) error) error {
+	if em.aggregator == nil {
+		return em.database.RunAsGroup(ctx, fn)
+	}
+	return em.aggregator.runPaused(ctx, fn)
+}
+
 func (em *eventManager) QueueBatchRewind(batchID *fftypes.UUID) {
 	em.aggregator.queueBatchRewind(batchID)
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
@@ -144,11 +145,12 @@ func newTestEventManagerCommon(t *testing.T, metrics, dbconcurrency bool) *testE
 	}
 	met.On("Name").Return("ut").Maybe()
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress).Maybe()
+	mpm.On("QueueMessageAcks", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
 	mdi.On("Capabilities").Return(&database.Capabilities{Concurrency: dbconcurrency}).Maybe()
 	mev.On("SetHandler", "ns1", mock.Anything).Return(nil).Maybe()
 	mev.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil).Maybe()
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	emi, err := NewEventManager(ctx, ns, mdi, mbi, mim, msh, mdm, mds, mbm, mpm, mam, msd, mmi, mom, txHelper, events, mmp, cmi)
+	emi, err := NewEventManager(ctx, ns, mdi, mbi, mim, &networkmapmocks.Manager{}, msh, mdm, mds, mbm, mpm, mam, msd, mmi, mom, txHelper, events, mmp, cmi)
 	em := emi.(*eventManager)
 	mockRunAsGroupPassthrough(mdi)
 	assert.NoError(t, err)
@@ -207,7 +209,7 @@ func TestStartStop(t *testing.T) {
 }
 
 func TestStartStopBadDependencies(t *testing.T) {
-	_, err := NewEventManager(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewEventManager(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 
 }
@@ -240,6 +242,12 @@ func TestAggregatorCacheInitFail(t *testing.T) {
 		coreconfig.CacheEventListenerTopicTTL,
 		ns.Name,
 	)).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	cmi.On("GetCache", cache.NewCacheConfig(
+		ctx,
+		coreconfig.CacheEventEnrichmentLimit,
+		coreconfig.CacheEventEnrichmentTTL,
+		ns.Name,
+	)).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	cmi.On("GetCache", cache.NewCacheConfig(
 		ctx,
 		coreconfig.CacheTransactionSize,
@@ -263,7 +271,7 @@ func TestAggregatorCacheInitFail(t *testing.T) {
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
 	mev.On("SetHandler", "ns1", mock.Anything).Return(nil).Maybe()
 	mev.On("ValidateOptions", mock.Anything).Return(nil).Maybe()
-	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
+	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, &networkmapmocks.Manager{}, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
 	assert.Equal(t, cacheInitError, err)
 }
 
@@ -312,7 +320,38 @@ func TestEventCacheInitFail(t *testing.T) {
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
 	mev.On("SetHandler", "ns1", mock.Anything).Return(nil).Maybe()
 	mev.On("ValidateOptions", mock.Anything).Return(nil).Maybe()
-	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
+	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, &networkmapmocks.Manager{}, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
+	assert.Equal(t, cacheInitError, err)
+}
+
+func TestEventEnrichmentCacheInitFail(t *testing.T) {
+	cacheInitError := errors.New("Initialization error.")
+	mdi := &databasemocks.Plugin{}
+	mbi := &blockchainmocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	mdm := &datamocks.Manager{}
+	msh := &definitionsmocks.Handler{}
+	mds := &definitionsmocks.Sender{}
+	mam := &assetmocks.Manager{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+	mth := &txcommonmocks.Helper{}
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	cmi.On("GetCache", cache.NewCacheConfig(
+		ctx,
+		coreconfig.CacheEventListenerTopicLimit,
+		coreconfig.CacheEventListenerTopicTTL,
+		ns.Name,
+	)).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	cmi.On("GetCache", cache.NewCacheConfig(
+		ctx,
+		coreconfig.CacheEventEnrichmentLimit,
+		coreconfig.CacheEventEnrichmentTTL,
+		ns.Name,
+	)).Return(nil, cacheInitError)
+	_, err := NewEventManager(ctx, ns, mdi, mbi, mim, &networkmapmocks.Manager{}, msh, mdm, mds, nil, nil, mam, nil, mm, mom, mth, nil, nil, cmi)
 	assert.Equal(t, cacheInitError, err)
 }
 
@@ -342,7 +381,7 @@ func TestStartStopEventListenerFail(t *testing.T) {
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
 	mev.On("SetHandler", "ns1", mock.Anything).Return(fmt.Errorf("pop"))
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
+	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, &networkmapmocks.Manager{}, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
 	assert.EqualError(t, err, "pop")
 }
 
@@ -612,6 +651,74 @@ func TestGetPlugins(t *testing.T) {
 	assert.ElementsMatch(t, em.GetPlugins(), expectedPlugins)
 }
 
+func TestAggregatorStatus(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	status := em.AggregatorStatus()
+	assert.Len(t, status.Workers, 1)
+	assert.Equal(t, 0, status.Workers[0].Index)
+
+	em.aggregator = nil
+	assert.Empty(t, em.AggregatorStatus().Workers)
+}
+
+func TestRunWithAggregatorPaused(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	called := 0
+	err := em.RunWithAggregatorPaused(em.ctx, func(ctx context.Context) error {
+		called++
+		// The aggregator cannot start processing a batch of pins
+		assert.False(t, em.aggregator.processing.TryLock())
+		return nil
+	})
+	assert.NoError(t, err)
+
+	em.aggregator = nil
+	err = em.RunWithAggregatorPaused(em.ctx, func(ctx context.Context) error {
+		called++
+		return fmt.Errorf("pop")
+	})
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 2, called)
+}
+
+func TestDeliveryErrors(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	since := fftypes.Now()
+	em.subManager.deliveryErrors.record(&core.Subscription{SubscriptionRef: core.SubscriptionRef{Name: "sub1"}}, "pop")
+
+	status := em.DeliveryErrors(since)
+	assert.Equal(t, core.ErrorReportCategorySubscriptionDeliveries, status.Category)
+	assert.Equal(t, int64(1), status.Count)
+}
+
+func TestReplayDeadLetterNotFound(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	id := fftypes.NewUUID()
+	em.mdi.On("GetDeadLetterByID", mock.Anything, "ns1", id).Return(nil, nil)
+
+	err := em.ReplayDeadLetter(em.ctx, fftypes.NewUUID(), id)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestEventManagerReplaySubscriptionNotFound(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	id := fftypes.NewUUID()
+	em.mdi.On("GetSubscriptionByID", mock.Anything, "ns1", id).Return(nil, nil)
+
+	_, err := em.ReplaySubscription(em.ctx, id, &core.SubscriptionReplayInput{})
+	assert.Regexp(t, "FF10109", err)
+}
+
 func TestResolveTransportAndCapabilities(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -63,6 +63,12 @@ func (en *eventNotifier) waitNext(lastSequence int64) error {
 	return nil
 }
 
+func (en *eventNotifier) getLatestSequence() int64 {
+	en.cond.L.Lock()
+	defer en.cond.L.Unlock()
+	return en.latestSequence
+}
+
 func (en *eventNotifier) close() {
 	en.cond.L.Lock()
 	en.closed = true
@@ -38,6 +38,7 @@ type eventPoller struct {
 	offsetCommitted chan int64
 	offsetID        int64
 	pollingOffset   int64
+	headSequence    int64
 	mux             sync.Mutex
 	conf            *eventPollerConf
 }
@@ -60,6 +61,10 @@ type eventPollerConf struct {
 	offsetType                 core.OffsetType
 	retry                      retry.Retry
 	startupOffsetRetryAttempts int
+	offsetCommitRetry          *retry.Retry       // optional - uses retry, if not set
+	offsetCommitRetryAttempts  int                // zero to retry indefinitely
+	offsetCommitRetryExhausted func(offset int64) // optional
+	reportLag                  func(lag int64)    // optional - number of events between the polling offset and the head
 }
 
 func newEventPoller(ctx context.Context, di database.Plugin, en *eventNotifier, conf *eventPollerConf) *eventPoller {
@@ -70,6 +75,7 @@ func newEventPoller(ctx context.Context, di database.Plugin, en *eventNotifier,
 		offsetCommitted: make(chan int64, 1),
 		eventNotifier:   en,
 		closed:          make(chan struct{}),
+		headSequence:    -1,
 		conf:            conf,
 	}
 	if ep.conf.maybeRewind == nil {
@@ -148,6 +154,7 @@ func (ep *eventPoller) commitOffset(offset int64) {
 	ep.mux.Lock()
 	ep.pollingOffset = offset
 	ep.mux.Unlock()
+	ep.updateLag(-1)
 
 	// No persistence for ephemeral (non-durable) subscriptions
 	if !ep.conf.ephemeral {
@@ -185,9 +192,33 @@ func (ep *eventPoller) readPage() ([]core.LocallySequenced, error) {
 		}
 		return false, nil
 	})
+	if len(items) > 0 {
+		ep.updateLag(items[len(items)-1].LocalSequence())
+	}
 	return items, err
 }
 
+// updateLag moves the head forwards to the latest sequence we know about, from either the notifier or
+// a page we have read, and reports how far the polling offset is behind it
+func (ep *eventPoller) updateLag(seen int64) {
+	if ep.conf.reportLag == nil {
+		return
+	}
+	if latest := ep.eventNotifier.getLatestSequence(); latest > seen {
+		seen = latest
+	}
+	ep.mux.Lock()
+	if seen > ep.headSequence {
+		ep.headSequence = seen
+	}
+	lag := ep.headSequence - ep.pollingOffset
+	ep.mux.Unlock()
+	if lag < 0 {
+		lag = 0
+	}
+	ep.conf.reportLag(lag)
+}
+
 func (ep *eventPoller) eventLoop() {
 	l := log.L(ep.ctx)
 	l.Debugf("Started event detector")
@@ -242,18 +273,31 @@ func (ep *eventPoller) eventLoop() {
 
 func (ep *eventPoller) offsetCommitLoop() {
 	l := log.L(ep.ctx)
+	commitRetry := &ep.conf.retry
+	if ep.conf.offsetCommitRetry != nil {
+		commitRetry = ep.conf.offsetCommitRetry
+	}
 	for range ep.offsetCommitted {
-		_ = ep.conf.retry.Do(ep.ctx, "process events", func(attempt int) (retry bool, err error) {
+		var pollingOffset int64
+		err := commitRetry.Do(ep.ctx, "commit offset", func(attempt int) (retry bool, err error) {
 			ep.mux.Lock()
-			pollingOffset := ep.pollingOffset
+			pollingOffset = ep.pollingOffset
 			ep.mux.Unlock()
 			u := database.OffsetQueryFactory.NewUpdate(ep.ctx).Set("current", pollingOffset)
 			if err := ep.database.UpdateOffset(ep.ctx, ep.offsetID, u); err != nil {
-				return true, err
+				return ep.conf.offsetCommitRetryAttempts == 0 || attempt < ep.conf.offsetCommitRetryAttempts, err
 			}
 			l.Debugf("Event polling offset committed %d", pollingOffset)
 			return false, nil
 		})
+		// The in-memory polling offset is unaffected, so delivery continues, and the
+		// next offset commit will persist the latest offset
+		if err != nil && ep.ctx.Err() == nil {
+			l.Warnf("Gave up committing event polling offset %d: %s", pollingOffset, err)
+			if ep.conf.offsetCommitRetryExhausted != nil {
+				ep.conf.offsetCommitRetryExhausted(pollingOffset)
+			}
+		}
 	}
 }
 
@@ -281,6 +325,7 @@ func (ep *eventPoller) newEventNotifications() {
 			log.L(ep.ctx).Debugf("event notifier closing")
 			return
 		}
+		ep.updateLag(-1)
 		ep.shoulderTap()
 		lastNotified = latestSequence
 	}
@@ -421,3 +421,85 @@ func TestOffsetCommitLoopFail(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func TestOffsetCommitLoopRetryExhausted(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+
+	ep, cancel := newTestEventPoller(mdi, nil, nil)
+	defer cancel()
+	failures := 0
+	var exhaustedOffset int64
+	ep.conf.offsetCommitRetry = &retry.Retry{
+		InitialDelay: 1 * time.Microsecond,
+		MaximumDelay: 1 * time.Microsecond,
+		ErrCallback:  func(err error) { failures++ },
+	}
+	ep.conf.offsetCommitRetryAttempts = 3
+	ep.conf.offsetCommitRetryExhausted = func(offset int64) { exhaustedOffset = offset }
+	ep.pollingOffset = 12345
+
+	mdi.On("UpdateOffset", mock.Anything, ep.offsetID, mock.Anything).Return(fmt.Errorf("pop")).Times(3)
+
+	ep.offsetCommitted <- int64(12345)
+	close(ep.offsetCommitted)
+	ep.offsetCommitLoop()
+
+	assert.Equal(t, 3, failures)
+	assert.Equal(t, int64(12345), exhaustedOffset)
+	mdi.AssertExpectations(t)
+}
+
+func TestOffsetCommitLoopRetryRecovers(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+
+	ep, cancel := newTestEventPoller(mdi, nil, nil)
+	defer cancel()
+	ep.conf.offsetCommitRetryAttempts = 3
+	ep.conf.offsetCommitRetryExhausted = func(offset int64) { assert.Fail(t, "should not be exhausted") }
+
+	mdi.On("UpdateOffset", mock.Anything, ep.offsetID, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	mdi.On("UpdateOffset", mock.Anything, ep.offsetID, mock.Anything).Return(nil).Once()
+
+	ep.offsetCommitted <- int64(12345)
+	close(ep.offsetCommitted)
+	ep.offsetCommitLoop()
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReportLag(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	ep, cancel := newTestEventPoller(mdi, nil, nil)
+	defer cancel()
+	var lags []int64
+	ep.conf.reportLag = func(lag int64) { lags = append(lags, lag) }
+	ep.conf.ephemeral = true
+
+	ep.pollingOffset = 5
+	ep.updateLag(-1) // no head known yet
+	ep.eventNotifier.latestSequence = 20
+	ep.updateLag(-1)
+	ep.updateLag(30)
+	ep.updateLag(25) // head never moves backwards
+	ep.commitOffset(30)
+
+	assert.Equal(t, []int64{0, 15, 25, 25, 0}, lags)
+}
+
+func TestReportLagOnReadPage(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	ep, cancel := newTestEventPoller(mdi, nil, nil)
+	defer cancel()
+	var lag int64
+	ep.conf.reportLag = func(l int64) { lag = l }
+	ep.pollingOffset = 10
+
+	mdi.On("GetEvents", mock.Anything, "unit", mock.Anything).Return([]*core.Event{
+		{ID: fftypes.NewUUID(), Sequence: 11},
+		{ID: fftypes.NewUUID(), Sequence: 12},
+	}, nil, nil)
+	items, err := ep.readPage()
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, int64(2), lag)
+}
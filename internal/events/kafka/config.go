@@ -0,0 +1,60 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+)
+
+const (
+	produceTimeoutDefault = "30s"
+	batchTimeoutDefault   = "10ms"
+	topicTemplateDefault  = "firefly.{namespace}.{subscription}"
+	keyTemplateDefault    = "{subscription}"
+)
+
+const (
+	// Brokers is the list of bootstrap brokers, such as localhost:9092
+	Brokers = "brokers"
+	// ClientID is the Kafka client ID to connect with - a unique ID is generated if not set
+	ClientID = "clientId"
+	// Username is the username to authenticate with using SASL/PLAIN - no authentication is performed if not set
+	Username = "username"
+	// Password is the password to authenticate with using SASL/PLAIN
+	Password = "password"
+	// ProduceTimeout is how long to wait for the brokers to acknowledge the events of a delivery
+	ProduceTimeout = "produceTimeout"
+	// BatchTimeout is how long the producer waits for more events, before sending a batch to a partition
+	BatchTimeout = "batchTimeout"
+	// TopicTemplate is the default template for the topic events are published to, if not set on the subscription
+	TopicTemplate = "topicTemplate"
+	// KeyTemplate is the default template for the partition key of each event, if not set on the subscription
+	KeyTemplate = "keyTemplate"
+)
+
+func (k *Kafka) InitConfig(config config.Section) {
+	config.AddKnownKey(Brokers)
+	config.AddKnownKey(ClientID)
+	config.AddKnownKey(Username)
+	config.AddKnownKey(Password)
+	config.AddKnownKey(ProduceTimeout, produceTimeoutDefault)
+	config.AddKnownKey(BatchTimeout, batchTimeoutDefault)
+	config.AddKnownKey(TopicTemplate, topicTemplateDefault)
+	config.AddKnownKey(KeyTemplate, keyTemplateDefault)
+	fftls.InitTLSConfig(config.SubSection("tls"))
+}
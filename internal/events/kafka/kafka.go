@@ -0,0 +1,249 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// Placeholders are allowed in topic templates, so we only check the literal characters
+var validTopicTemplate = regexp.MustCompile(`^[a-zA-Z0-9._\-{}]*$`)
+
+// producer is the part of the Kafka writer we use, so it can be replaced in unit tests
+type producer interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+	Close() error
+}
+
+// newProducer is replaced in unit tests
+var newProducer = func(w *kafkago.Writer) producer { return w }
+
+// Kafka publishes the events of durable subscriptions with transport "kafka" to a Kafka topic.
+// Each event is acknowledged once the brokers have confirmed it was written, so the offset of the
+// subscription only moves past events that are safely stored in Kafka.
+type Kafka struct {
+	ctx            context.Context
+	capabilities   *events.Capabilities
+	callbacks      callbacks
+	producer       producer
+	connID         string
+	produceTimeout time.Duration
+	topicTemplate  string
+	keyTemplate    string
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// kafkaPayload is published for each event, with the data inline if the subscription has withData set
+type kafkaPayload struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+func (k *Kafka) Name() string { return "kafka" }
+
+func (k *Kafka) Init(ctx context.Context, config config.Section) error {
+	brokers := config.GetStringSlice(Brokers)
+	if len(brokers) == 0 {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, Brokers, "events.kafka")
+	}
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, config.SubSection("tls"), fftls.ClientType)
+	if err != nil {
+		return err
+	}
+
+	connID := fftypes.ShortID()
+	*k = Kafka{
+		ctx:            log.WithLogField(ctx, "kafka", connID),
+		capabilities:   &events.Capabilities{BatchDelivery: true},
+		connID:         connID,
+		produceTimeout: config.GetDuration(ProduceTimeout),
+		topicTemplate:  config.GetString(TopicTemplate),
+		keyTemplate:    config.GetString(KeyTemplate),
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+	}
+
+	clientID := config.GetString(ClientID)
+	if clientID == "" {
+		clientID = "firefly-" + connID
+	}
+	transport := &kafkago.Transport{
+		ClientID: clientID,
+		TLS:      tlsConfig,
+	}
+	if username := config.GetString(Username); username != "" {
+		transport.SASL = plain.Mechanism{
+			Username: username,
+			Password: config.GetString(Password),
+		}
+	}
+	// The hash balancer keeps all events with the same key on the same partition, so they stay in order
+	k.producer = newProducer(&kafkago.Writer{
+		Addr:         kafkago.TCP(brokers...),
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		BatchTimeout: config.GetDuration(BatchTimeout),
+		Transport:    transport,
+	})
+	go func() {
+		<-ctx.Done()
+		if err := k.producer.Close(); err != nil {
+			log.L(k.ctx).Warnf("Failed to close Kafka producer: %s", err)
+		}
+	}()
+	return nil
+}
+
+func (k *Kafka) SetHandler(namespace string, handler events.Callbacks) error {
+	k.callbacks.writeLock.Lock()
+	defer k.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(k.callbacks.handlers, namespace)
+		return nil
+	}
+	k.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(k.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (k *Kafka) getHandler(namespace string) (events.Callbacks, bool) {
+	k.callbacks.writeLock.Lock()
+	defer k.callbacks.writeLock.Unlock()
+	cb, ok := k.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (k *Kafka) Capabilities() *events.Capabilities {
+	return k.capabilities
+}
+
+func (k *Kafka) ValidateOptions(ctx context.Context, options *core.SubscriptionOptions) error {
+	if options.WithData == nil {
+		defaultTrue := true
+		options.WithData = &defaultTrue
+	}
+	if topic := options.TransportOptions().GetString("topic"); !validTopicTemplate.MatchString(topic) {
+		return i18n.NewError(ctx, coremsgs.MsgKafkaInvalidTopic, topic)
+	}
+	return nil
+}
+
+// expandTemplate substitutes the {namespace}, {subscription} and {id} placeholders of the subscription in a
+// template, and for partition keys the {topic}, {type} and {author} placeholders of the event
+func expandTemplate(template string, sub *core.Subscription, event *core.EventDelivery) string {
+	var author string
+	if event != nil && event.Message != nil {
+		author = event.Message.Header.Author
+	}
+	var topic, eventType string
+	if event != nil {
+		topic, eventType = event.Topic, event.Type.String()
+	}
+	return strings.NewReplacer(
+		"{namespace}", sub.Namespace,
+		"{subscription}", sub.Name,
+		"{id}", sub.ID.String(),
+		"{topic}", topic,
+		"{type}", eventType,
+		"{author}", author,
+	).Replace(template)
+}
+
+func (k *Kafka) templates(sub *core.Subscription) (topicTemplate, keyTemplate string) {
+	topicTemplate = sub.Options.TransportOptions().GetString("topic")
+	if topicTemplate == "" {
+		topicTemplate = k.topicTemplate
+	}
+	keyTemplate = sub.Options.TransportOptions().GetString("key")
+	if keyTemplate == "" {
+		keyTemplate = k.keyTemplate
+	}
+	return topicTemplate, keyTemplate
+}
+
+// publish writes the events to the topic of the subscription, and acknowledges them only once all have been
+// written. If any fail the whole delivery is rejected, and redelivered - so delivery is at-least-once.
+func (k *Kafka) publish(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	topicTemplate, keyTemplate := k.templates(sub)
+	topic := expandTemplate(topicTemplate, sub, nil)
+	msgs := make([]kafkago.Message, len(events))
+	for i, combinedEvent := range events {
+		event := combinedEvent.Event
+		b, _ := json.Marshal(&kafkaPayload{EventDelivery: event, Data: combinedEvent.Data})
+		msgs[i] = kafkago.Message{
+			Topic: topic,
+			Value: b,
+			Headers: []kafkago.Header{
+				{Key: "id", Value: []byte(event.ID.String())},
+				{Key: "type", Value: []byte(event.Type)},
+			},
+		}
+		if key := expandTemplate(keyTemplate, sub, event); key != "" {
+			msgs[i].Key = []byte(key)
+		}
+	}
+
+	log.L(ctx).Debugf("Publishing %d events to Kafka topic '%s'", len(msgs), topic)
+	produceCtx, cancel := context.WithTimeout(ctx, k.produceTimeout)
+	defer cancel()
+	if err := k.producer.WriteMessages(produceCtx, msgs...); err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgKafkaProduceFailed, len(msgs), topic)
+	}
+
+	if cb, ok := k.getHandler(sub.Namespace); ok {
+		for _, combinedEvent := range events {
+			cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
+				ID:           combinedEvent.Event.ID,
+				Subscription: combinedEvent.Event.Subscription,
+			})
+		}
+	}
+	return nil
+}
+
+func (k *Kafka) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	return k.publish(ctx, connID, sub, []*core.CombinedEventDataDelivery{{Event: event, Data: data}})
+}
+
+func (k *Kafka) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return k.publish(ctx, connID, sub, events)
+}
+
+func (k *Kafka) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
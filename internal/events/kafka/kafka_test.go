@@ -0,0 +1,285 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testProducer struct {
+	writer   *kafkago.Writer
+	closed   chan struct{}
+	closeErr error
+	writeErr error
+	written  []kafkago.Message
+}
+
+func (tp *testProducer) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	if tp.writeErr != nil {
+		return tp.writeErr
+	}
+	tp.written = append(tp.written, msgs...)
+	return nil
+}
+
+func (tp *testProducer) Close() error {
+	close(tp.closed)
+	return tp.closeErr
+}
+
+func newTestKafka(t *testing.T, extraConf ...func(conf config.Section)) (*Kafka, *testProducer, *eventsmocks.Callbacks, func()) {
+	coreconfig.Reset()
+
+	tp := &testProducer{closed: make(chan struct{})}
+	origProducer := newProducer
+	newProducer = func(w *kafkago.Writer) producer {
+		tp.writer = w
+		return tp
+	}
+
+	cbs := &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	k := &Kafka{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	conf := config.RootSection("ut.kafka")
+	k.InitConfig(conf)
+	conf.Set(Brokers, []string{"localhost:9092"})
+	for _, fn := range extraConf {
+		fn(conf)
+	}
+	err := k.Init(ctx, conf)
+	assert.NoError(t, err)
+	err = k.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka", k.Name())
+	assert.True(t, k.Capabilities().BatchDelivery)
+	return k, tp, cbs, func() {
+		cancelCtx()
+		<-tp.closed
+		newProducer = origProducer
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestSubscription() *core.Subscription {
+	return &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "kafka",
+	}
+}
+
+func newTestEvent(sub *core.Subscription) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:    fftypes.NewUUID(),
+				Type:  core.EventTypeMessageConfirmed,
+				Topic: "topic1",
+			},
+			Message: &core.Message{
+				Header: core.MessageHeader{
+					SignerRef: core.SignerRef{Author: "did:firefly:org/org1"},
+				},
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+}
+
+func TestInitMissingBrokers(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	conf := config.RootSection("ut.kafka")
+	k.InitConfig(conf)
+	err := k.Init(context.Background(), conf)
+	assert.Regexp(t, "FF10138.*brokers", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	conf := config.RootSection("ut.kafka")
+	k.InitConfig(conf)
+	conf.Set(Brokers, []string{"localhost:9093"})
+	tlsConf := conf.SubSection("tls")
+	tlsConf.Set("enabled", true)
+	tlsConf.Set("caFile", "badfile")
+	err := k.Init(context.Background(), conf)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestInitWriterOptions(t *testing.T) {
+	k, tp, _, done := newTestKafka(t, func(conf config.Section) {
+		conf.Set(Username, "user1")
+		conf.Set(Password, "pass1")
+	})
+	defer done()
+
+	assert.Equal(t, "localhost:9092", tp.writer.Addr.String())
+	assert.Equal(t, kafkago.RequireAll, tp.writer.RequiredAcks)
+	assert.Equal(t, 10*time.Millisecond, tp.writer.BatchTimeout)
+	transport := tp.writer.Transport.(*kafkago.Transport)
+	assert.Equal(t, "firefly-"+k.connID, transport.ClientID)
+	assert.Equal(t, plain.Mechanism{Username: "user1", Password: "pass1"}, transport.SASL)
+}
+
+func TestInitCloseFail(t *testing.T) {
+	_, tp, _, done := newTestKafka(t, func(conf config.Section) {
+		conf.Set(ClientID, "client1")
+	})
+	assert.Equal(t, "client1", tp.writer.Transport.(*kafkago.Transport).ClientID)
+	assert.Nil(t, tp.writer.Transport.(*kafkago.Transport).SASL)
+	tp.closeErr = fmt.Errorf("pop")
+	done()
+}
+
+func TestNewProducer(t *testing.T) {
+	w := &kafkago.Writer{}
+	assert.Equal(t, w, newProducer(w))
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	k, _, _, done := newTestKafka(t)
+	defer done()
+
+	err := k.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	_, ok := k.getHandler("ns1")
+	assert.False(t, ok)
+}
+
+func TestValidateOptions(t *testing.T) {
+	k, _, _, done := newTestKafka(t)
+	defer done()
+
+	options := &core.SubscriptionOptions{}
+	options.TransportOptions()["topic"] = "events.{namespace}-{subscription}"
+	err := k.ValidateOptions(context.Background(), options)
+	assert.NoError(t, err)
+	assert.True(t, *options.WithData)
+
+	options = &core.SubscriptionOptions{}
+	options.TransportOptions()["topic"] = "events/{subscription}"
+	err = k.ValidateOptions(context.Background(), options)
+	assert.Regexp(t, "FF10609", err)
+}
+
+func TestDeliveryAndAck(t *testing.T) {
+	k, tp, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub)
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"hello"`)}}
+
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && r.Subscription.ID.Equals(sub.ID) && !r.Rejected
+	})).Return().Once()
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event, data)
+	assert.NoError(t, err)
+
+	assert.Len(t, tp.written, 1)
+	msg := tp.written[0]
+	assert.Equal(t, "firefly.ns1.sub1", msg.Topic)
+	assert.Equal(t, "sub1", string(msg.Key))
+	assert.Equal(t, []kafkago.Header{
+		{Key: "id", Value: []byte(event.ID.String())},
+		{Key: "type", Value: []byte("message_confirmed")},
+	}, msg.Headers)
+	var payload fftypes.JSONObject
+	err = json.Unmarshal(msg.Value, &payload)
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID.String(), payload.GetString("id"))
+	assert.Equal(t, "hello", payload.GetObjectArray("data")[0].GetString("value"))
+}
+
+func TestBatchDeliveryCustomTemplates(t *testing.T) {
+	k, tp, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub := newTestSubscription()
+	sub.Options.TransportOptions()["topic"] = "events-{id}"
+	sub.Options.TransportOptions()["key"] = "{topic}/{author}"
+	event1 := newTestEvent(sub)
+	event2 := newTestEvent(sub)
+	event2.Message = nil
+
+	cbs.On("DeliveryResponse", k.connID, mock.Anything).Return().Twice()
+
+	err := k.BatchDeliveryRequest(context.Background(), k.connID, sub, []*core.CombinedEventDataDelivery{
+		{Event: event1},
+		{Event: event2},
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, tp.written, 2)
+	assert.Equal(t, "events-"+sub.ID.String(), tp.written[0].Topic)
+	assert.Equal(t, "topic1/did:firefly:org/org1", string(tp.written[0].Key))
+	assert.Equal(t, "topic1/", string(tp.written[1].Key))
+}
+
+func TestDeliveryNoKey(t *testing.T) {
+	k, tp, _, done := newTestKafka(t, func(conf config.Section) {
+		conf.Set(KeyTemplate, "{author}")
+	})
+	defer done()
+
+	// No handler for the namespace, so nothing is acknowledged
+	sub := newTestSubscription()
+	sub.Namespace = "ns2"
+	event := newTestEvent(sub)
+	event.Message = nil
+
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tp.written[0].Key)
+
+	k.NamespaceRestarted("ns1", time.Now())
+}
+
+func TestDeliveryProduceFail(t *testing.T) {
+	k, tp, _, done := newTestKafka(t)
+	defer done()
+
+	tp.writeErr = fmt.Errorf("pop")
+	sub := newTestSubscription()
+	err := k.DeliveryRequest(context.Background(), k.connID, sub, newTestEvent(sub), nil)
+	assert.Regexp(t, "FF10610.*firefly.ns1.sub1.*pop", err)
+}
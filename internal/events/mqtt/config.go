@@ -0,0 +1,60 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+)
+
+const (
+	connectTimeoutDefault     = "10s"
+	publishTimeoutDefault     = "30s"
+	topicTemplateDefault      = "firefly/{namespace}/{subscription}"
+	replyTopicTemplateDefault = "firefly/{namespace}/{subscription}/ack"
+)
+
+const (
+	// URL is the URL of the MQTT broker, such as tcp://localhost:1883
+	URL = "url"
+	// ClientID is the MQTT client ID to connect with - a unique ID is generated if not set
+	ClientID = "clientId"
+	// Username is the username to connect to the broker with
+	Username = "username"
+	// Password is the password to connect to the broker with
+	Password = "password"
+	// ConnectTimeout is how long to wait for each attempt to connect to the broker
+	ConnectTimeout = "connectTimeout"
+	// PublishTimeout is how long to wait for the broker to confirm a publish, or a subscription to a reply topic
+	PublishTimeout = "publishTimeout"
+	// TopicTemplate is the default template for the topic events are published to, if not set on the subscription
+	TopicTemplate = "topicTemplate"
+	// ReplyTopicTemplate is the default template for the topic acknowledgements are received on, if not set on the subscription
+	ReplyTopicTemplate = "replyTopicTemplate"
+)
+
+func (m *MQTT) InitConfig(config config.Section) {
+	config.AddKnownKey(URL)
+	config.AddKnownKey(ClientID)
+	config.AddKnownKey(Username)
+	config.AddKnownKey(Password)
+	config.AddKnownKey(ConnectTimeout, connectTimeoutDefault)
+	config.AddKnownKey(PublishTimeout, publishTimeoutDefault)
+	config.AddKnownKey(TopicTemplate, topicTemplateDefault)
+	config.AddKnownKey(ReplyTopicTemplate, replyTopicTemplateDefault)
+	fftls.InitTLSConfig(config.SubSection("tls"))
+}
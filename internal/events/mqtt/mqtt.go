@@ -0,0 +1,317 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// Events are published, and reply topics subscribed, with at-least-once delivery
+const qosAtLeastOnce = 1
+
+// newClient is replaced in unit tests
+var newClient = paho.NewClient
+
+// MQTT publishes the events of durable subscriptions with transport "mqtt" to a topic on a broker.
+// Applications acknowledge each event by publishing {"id":"<event id>"} to the reply topic of the subscription.
+type MQTT struct {
+	ctx                context.Context
+	capabilities       *events.Capabilities
+	callbacks          callbacks
+	client             paho.Client
+	connID             string
+	url                string
+	publishTimeout     time.Duration
+	topicTemplate      string
+	replyTopicTemplate string
+	replyMux           sync.Mutex
+	replyTopics        map[string][]*core.SubscriptionRef
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// mqttPayload is published for each event, with the data inline if the subscription has withData set
+type mqttPayload struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+type mqttAck struct {
+	ID *fftypes.UUID `json:"id"`
+}
+
+func (m *MQTT) Name() string { return "mqtt" }
+
+func (m *MQTT) Init(ctx context.Context, config config.Section) error {
+	url := config.GetString(URL)
+	if url == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, URL, "events.mqtt")
+	}
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, config.SubSection("tls"), fftls.ClientType)
+	if err != nil {
+		return err
+	}
+
+	connID := fftypes.ShortID()
+	*m = MQTT{
+		ctx:                log.WithLogField(ctx, "mqtt", connID),
+		capabilities:       &events.Capabilities{},
+		connID:             connID,
+		url:                url,
+		publishTimeout:     config.GetDuration(PublishTimeout),
+		topicTemplate:      config.GetString(TopicTemplate),
+		replyTopicTemplate: config.GetString(ReplyTopicTemplate),
+		replyTopics:        make(map[string][]*core.SubscriptionRef),
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+	}
+
+	clientID := config.GetString(ClientID)
+	if clientID == "" {
+		clientID = "firefly-" + connID
+	}
+	opts := paho.NewClientOptions().
+		AddBroker(url).
+		SetClientID(clientID).
+		SetUsername(config.GetString(Username)).
+		SetPassword(config.GetString(Password)).
+		SetTLSConfig(tlsConfig).
+		SetConnectTimeout(config.GetDuration(ConnectTimeout)).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(m.onConnect).
+		SetConnectionLostHandler(m.onConnectionLost)
+	client := newClient(opts)
+	m.client = client
+
+	// With retry enabled the connect token only completes once connected, so we do not block startup
+	// on the broker being available. Deliveries fail (and are retried) until the connection is up.
+	client.Connect()
+	go func() {
+		<-ctx.Done()
+		client.Disconnect(250)
+	}()
+	return nil
+}
+
+func (m *MQTT) SetHandler(namespace string, handler events.Callbacks) error {
+	m.callbacks.writeLock.Lock()
+	defer m.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(m.callbacks.handlers, namespace)
+		return nil
+	}
+	m.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(m.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (m *MQTT) getHandler(namespace string) (events.Callbacks, bool) {
+	m.callbacks.writeLock.Lock()
+	defer m.callbacks.writeLock.Unlock()
+	cb, ok := m.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (m *MQTT) Capabilities() *events.Capabilities {
+	return m.capabilities
+}
+
+func (m *MQTT) ValidateOptions(ctx context.Context, options *core.SubscriptionOptions) error {
+	if options.WithData == nil {
+		defaultTrue := true
+		options.WithData = &defaultTrue
+	}
+	for _, key := range []string{"topic", "replyTopic"} {
+		if topic := options.TransportOptions().GetString(key); strings.ContainsAny(topic, "+#") {
+			return i18n.NewError(ctx, coremsgs.MsgMQTTInvalidTopic, topic)
+		}
+	}
+	return nil
+}
+
+// expandTopic substitutes the {namespace}, {subscription} and {id} placeholders in a topic template
+func expandTopic(template string, sub *core.Subscription) string {
+	return strings.NewReplacer(
+		"{namespace}", sub.Namespace,
+		"{subscription}", sub.Name,
+		"{id}", sub.ID.String(),
+	).Replace(template)
+}
+
+func (m *MQTT) topics(sub *core.Subscription) (topic, replyTopic string) {
+	topicTemplate := sub.Options.TransportOptions().GetString("topic")
+	if topicTemplate == "" {
+		topicTemplate = m.topicTemplate
+	}
+	replyTopicTemplate := sub.Options.TransportOptions().GetString("replyTopic")
+	if replyTopicTemplate == "" {
+		replyTopicTemplate = m.replyTopicTemplate
+	}
+	return expandTopic(topicTemplate, sub), expandTopic(replyTopicTemplate, sub)
+}
+
+// waitToken waits up to the publish timeout for the broker to confirm an action
+func (m *MQTT) waitToken(ctx context.Context, token paho.Token, action, topic string) error {
+	if !token.WaitTimeout(m.publishTimeout) {
+		return i18n.NewError(ctx, coremsgs.MsgMQTTTimeout, action, topic, m.publishTimeout)
+	}
+	if err := token.Error(); err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgMQTTRequestFailed, action, topic)
+	}
+	return nil
+}
+
+// subscribeReplyTopic ensures we are listening for acknowledgements for the subscription, before we publish
+// the first event to it
+func (m *MQTT) subscribeReplyTopic(ctx context.Context, sub *core.Subscription, replyTopic string) error {
+	m.replyMux.Lock()
+	defer m.replyMux.Unlock()
+	subRefs, subscribed := m.replyTopics[replyTopic]
+	for _, subRef := range subRefs {
+		if subRef.ID.Equals(sub.ID) {
+			return nil
+		}
+	}
+	// The subscription might have been updated to use a different reply topic
+	m.releaseReplyTopicsLocked(ctx, sub.ID, replyTopic)
+	if !subscribed {
+		if err := m.waitToken(ctx, m.client.Subscribe(replyTopic, qosAtLeastOnce, m.handleAck), "subscribe", replyTopic); err != nil {
+			return err
+		}
+		log.L(ctx).Infof("Subscribed to MQTT reply topic '%s'", replyTopic)
+	}
+	subRef := sub.SubscriptionRef
+	m.replyTopics[replyTopic] = append(subRefs, &subRef)
+	return nil
+}
+
+// releaseReplyTopicsLocked removes the subscription from every reply topic other than the one it is now using,
+// unsubscribing from any topic that no other subscription is listening on
+func (m *MQTT) releaseReplyTopicsLocked(ctx context.Context, subID *fftypes.UUID, keepTopic string) {
+	for replyTopic, subRefs := range m.replyTopics {
+		if replyTopic == keepTopic {
+			continue
+		}
+		remaining := make([]*core.SubscriptionRef, 0, len(subRefs))
+		for _, subRef := range subRefs {
+			if !subRef.ID.Equals(subID) {
+				remaining = append(remaining, subRef)
+			}
+		}
+		if len(remaining) == len(subRefs) {
+			continue
+		}
+		if len(remaining) > 0 {
+			m.replyTopics[replyTopic] = remaining
+			continue
+		}
+		delete(m.replyTopics, replyTopic)
+		if err := m.waitToken(ctx, m.client.Unsubscribe(replyTopic), "unsubscribe", replyTopic); err != nil {
+			// We no longer route acknowledgements on the topic, so the subscription only costs the broker
+			log.L(ctx).Warnf("Failed to unsubscribe from MQTT reply topic '%s': %s", replyTopic, err)
+			continue
+		}
+		log.L(ctx).Infof("Unsubscribed from MQTT reply topic '%s'", replyTopic)
+	}
+}
+
+// SubscriptionDeleted stops listening on the reply topic of a deleted subscription, once no other subscription uses it
+func (m *MQTT) SubscriptionDeleted(sub *core.Subscription) {
+	m.replyMux.Lock()
+	defer m.replyMux.Unlock()
+	m.releaseReplyTopicsLocked(m.ctx, sub.ID, "")
+}
+
+func (m *MQTT) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	if !m.client.IsConnectionOpen() {
+		return i18n.NewError(ctx, coremsgs.MsgMQTTNotConnected, m.url)
+	}
+	topic, replyTopic := m.topics(sub)
+	if err := m.subscribeReplyTopic(ctx, sub, replyTopic); err != nil {
+		return err
+	}
+	b, _ := json.Marshal(&mqttPayload{EventDelivery: event, Data: data})
+	log.L(ctx).Debugf("Publishing event %s to MQTT topic '%s'", event.ID, topic)
+	return m.waitToken(ctx, m.client.Publish(topic, qosAtLeastOnce, false, b), "publish", topic)
+}
+
+func (m *MQTT) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(ctx, coremsgs.MsgBatchDeliveryNotSupported, m.Name())
+}
+
+// handleAck passes an acknowledgement received on a reply topic to each subscription listening on that topic.
+// The event is only in flight on one of them - the others ignore it.
+func (m *MQTT) handleAck(_ paho.Client, msg paho.Message) {
+	var ack mqttAck
+	if err := json.Unmarshal(msg.Payload(), &ack); err != nil || ack.ID == nil {
+		log.L(m.ctx).Warnf("Ignoring invalid acknowledgement on MQTT topic '%s': %s", msg.Topic(), msg.Payload())
+		return
+	}
+	m.replyMux.Lock()
+	subRefs := m.replyTopics[msg.Topic()]
+	m.replyMux.Unlock()
+	for _, subRef := range subRefs {
+		if cb, ok := m.getHandler(subRef.Namespace); ok {
+			cb.DeliveryResponse(m.connID, &core.EventDeliveryResponse{
+				ID:           ack.ID,
+				Subscription: *subRef,
+			})
+		}
+	}
+}
+
+// onConnect re-subscribes to the reply topics after a reconnect, as the broker might not have kept our session
+func (m *MQTT) onConnect(client paho.Client) {
+	log.L(m.ctx).Infof("Connected to MQTT broker '%s'", m.url)
+	m.replyMux.Lock()
+	replyTopics := make([]string, 0, len(m.replyTopics))
+	for replyTopic := range m.replyTopics {
+		replyTopics = append(replyTopics, replyTopic)
+	}
+	m.replyMux.Unlock()
+	for _, replyTopic := range replyTopics {
+		// We cannot wait for the token on the connect callback, as that would block the client
+		client.Subscribe(replyTopic, qosAtLeastOnce, m.handleAck)
+	}
+}
+
+func (m *MQTT) onConnectionLost(_ paho.Client, err error) {
+	log.L(m.ctx).Warnf("Lost connection to MQTT broker '%s': %s", m.url, err)
+}
+
+func (m *MQTT) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
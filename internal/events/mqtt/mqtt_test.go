@@ -0,0 +1,376 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testToken struct {
+	timeout bool
+	err     error
+}
+
+func (tt *testToken) Wait() bool                     { return !tt.timeout }
+func (tt *testToken) WaitTimeout(time.Duration) bool { return !tt.timeout }
+func (tt *testToken) Done() <-chan struct{}          { return nil }
+func (tt *testToken) Error() error                   { return tt.err }
+
+type testMessage struct {
+	paho.Message
+	topic   string
+	payload []byte
+}
+
+func (tm *testMessage) Topic() string   { return tm.topic }
+func (tm *testMessage) Payload() []byte { return tm.payload }
+
+type testPublish struct {
+	topic   string
+	qos     byte
+	payload []byte
+}
+
+type testClient struct {
+	paho.Client
+	opts         *paho.ClientOptions
+	mux          sync.Mutex
+	connected    bool
+	disconnected chan struct{}
+	publishToken *testToken
+	subToken     *testToken
+	unsubToken   *testToken
+	published    []*testPublish
+	subscribed   map[string]paho.MessageHandler
+}
+
+func (tc *testClient) IsConnectionOpen() bool { return tc.connected }
+func (tc *testClient) Connect() paho.Token    { return &testToken{} }
+func (tc *testClient) Disconnect(quiesce uint) {
+	close(tc.disconnected)
+}
+
+func (tc *testClient) Publish(topic string, qos byte, retained bool, payload interface{}) paho.Token {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+	tc.published = append(tc.published, &testPublish{topic: topic, qos: qos, payload: payload.([]byte)})
+	return tc.publishToken
+}
+
+func (tc *testClient) Subscribe(topic string, qos byte, callback paho.MessageHandler) paho.Token {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+	tc.subscribed[topic] = callback
+	return tc.subToken
+}
+
+func (tc *testClient) Unsubscribe(topics ...string) paho.Token {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+	for _, topic := range topics {
+		delete(tc.subscribed, topic)
+	}
+	return tc.unsubToken
+}
+
+func newTestMQTT(t *testing.T) (*MQTT, *testClient, *eventsmocks.Callbacks, func()) {
+	coreconfig.Reset()
+
+	tc := &testClient{
+		connected:    true,
+		disconnected: make(chan struct{}),
+		publishToken: &testToken{},
+		subToken:     &testToken{},
+		unsubToken:   &testToken{},
+		subscribed:   make(map[string]paho.MessageHandler),
+	}
+	newClient = func(opts *paho.ClientOptions) paho.Client {
+		tc.opts = opts
+		return tc
+	}
+
+	cbs := &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	m := &MQTT{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	conf := config.RootSection("ut.mqtt")
+	m.InitConfig(conf)
+	conf.Set(URL, "tcp://localhost:1883")
+	err := m.Init(ctx, conf)
+	assert.NoError(t, err)
+	err = m.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "mqtt", m.Name())
+	assert.NotNil(t, m.Capabilities())
+	return m, tc, cbs, func() {
+		cancelCtx()
+		<-tc.disconnected
+		newClient = paho.NewClient
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestSubscription() *core.Subscription {
+	return &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "mqtt",
+	}
+}
+
+func TestInitMissingURL(t *testing.T) {
+	coreconfig.Reset()
+	m := &MQTT{}
+	conf := config.RootSection("ut.mqtt")
+	m.InitConfig(conf)
+	err := m.Init(context.Background(), conf)
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	m := &MQTT{}
+	conf := config.RootSection("ut.mqtt")
+	m.InitConfig(conf)
+	conf.Set(URL, "ssl://localhost:8883")
+	tlsConf := conf.SubSection("tls")
+	tlsConf.Set("enabled", true)
+	tlsConf.Set("caFile", "badfile")
+	err := m.Init(context.Background(), conf)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestInitClientOptions(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	assert.Equal(t, "tcp://localhost:1883", tc.opts.Servers[0].String())
+	assert.Equal(t, "firefly-"+m.connID, tc.opts.ClientID)
+	assert.True(t, tc.opts.AutoReconnect)
+	assert.True(t, tc.opts.ConnectRetry)
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	m, _, _, done := newTestMQTT(t)
+	defer done()
+
+	err := m.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	_, ok := m.getHandler("ns1")
+	assert.False(t, ok)
+}
+
+func TestValidateOptions(t *testing.T) {
+	m, _, _, done := newTestMQTT(t)
+	defer done()
+
+	options := &core.SubscriptionOptions{}
+	options.TransportOptions()["topic"] = "devices/{subscription}"
+	err := m.ValidateOptions(context.Background(), options)
+	assert.NoError(t, err)
+	assert.True(t, *options.WithData)
+
+	options = &core.SubscriptionOptions{}
+	options.TransportOptions()["replyTopic"] = "devices/+/ack"
+	err = m.ValidateOptions(context.Background(), options)
+	assert.Regexp(t, "FF10580", err)
+}
+
+func TestDeliveryAndAck(t *testing.T) {
+	m, tc, cbs, done := newTestMQTT(t)
+	defer done()
+
+	sub := newTestSubscription()
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID()},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"hello"`)}}
+
+	err := m.DeliveryRequest(context.Background(), m.connID, sub, event, data)
+	assert.NoError(t, err)
+	err = m.DeliveryRequest(context.Background(), m.connID, sub, event, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, tc.published, 2)
+	assert.Equal(t, "firefly/ns1/sub1", tc.published[0].topic)
+	assert.Equal(t, byte(1), tc.published[0].qos)
+	var payload fftypes.JSONObject
+	err = json.Unmarshal(tc.published[0].payload, &payload)
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID.String(), payload.GetString("id"))
+	assert.Equal(t, "hello", payload.GetObjectArray("data")[0].GetString("value"))
+
+	// Subscribed to the reply topic once
+	handler := tc.subscribed["firefly/ns1/sub1/ack"]
+	assert.NotNil(t, handler)
+	assert.Len(t, m.replyTopics["firefly/ns1/sub1/ack"], 1)
+
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && r.Subscription.ID.Equals(sub.ID) && !r.Rejected
+	})).Return().Once()
+	handler(tc, &testMessage{topic: "firefly/ns1/sub1/ack", payload: []byte(`{"id":"` + event.ID.String() + `"}`)})
+
+	// Invalid acks are ignored
+	handler(tc, &testMessage{topic: "firefly/ns1/sub1/ack", payload: []byte(`!json`)})
+	handler(tc, &testMessage{topic: "firefly/ns1/sub1/ack", payload: []byte(`{}`)})
+}
+
+func TestDeliverySharedReplyTopic(t *testing.T) {
+	m, tc, cbs, done := newTestMQTT(t)
+	defer done()
+
+	sub1 := newTestSubscription()
+	sub1.Options.TransportOptions()["topic"] = "devices/{id}"
+	sub1.Options.TransportOptions()["replyTopic"] = "devices/acks"
+	sub2 := newTestSubscription()
+	sub2.Namespace = "ns2"
+	sub2.Options.TransportOptions()["replyTopic"] = "devices/acks"
+	event := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+
+	err := m.DeliveryRequest(context.Background(), m.connID, sub1, event, nil)
+	assert.NoError(t, err)
+	err = m.DeliveryRequest(context.Background(), m.connID, sub2, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "devices/"+sub1.ID.String(), tc.published[0].topic)
+	assert.Len(t, m.replyTopics["devices/acks"], 2)
+
+	// Only ns1 has a handler
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Subscription.ID.Equals(sub1.ID)
+	})).Return().Once()
+	tc.subscribed["devices/acks"](tc, &testMessage{topic: "devices/acks", payload: []byte(`{"id":"` + event.ID.String() + `"}`)})
+}
+
+func TestSubscriptionDeletedReleasesReplyTopic(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	sub1 := newTestSubscription()
+	sub1.Options.TransportOptions()["replyTopic"] = "devices/acks"
+	sub2 := newTestSubscription()
+	sub2.Options.TransportOptions()["replyTopic"] = "devices/acks"
+	event := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+
+	err := m.DeliveryRequest(context.Background(), m.connID, sub1, event, nil)
+	assert.NoError(t, err)
+	err = m.DeliveryRequest(context.Background(), m.connID, sub2, event, nil)
+	assert.NoError(t, err)
+
+	// The topic is still in use by the second subscription
+	m.SubscriptionDeleted(sub1)
+	assert.Len(t, m.replyTopics["devices/acks"], 1)
+	assert.NotNil(t, tc.subscribed["devices/acks"])
+
+	m.SubscriptionDeleted(sub2)
+	assert.Empty(t, m.replyTopics)
+	assert.Nil(t, tc.subscribed["devices/acks"])
+
+	// Reconnecting does not subscribe to the topic again
+	m.onConnect(tc)
+	assert.Nil(t, tc.subscribed["devices/acks"])
+}
+
+func TestSubscriptionUpdatedReplyTopic(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	tc.unsubToken.err = fmt.Errorf("pop")
+	sub := newTestSubscription()
+	err := m.DeliveryRequest(context.Background(), m.connID, sub, &core.EventDelivery{}, nil)
+	assert.NoError(t, err)
+
+	sub.Options.TransportOptions()["replyTopic"] = "devices/acks"
+	err = m.DeliveryRequest(context.Background(), m.connID, sub, &core.EventDelivery{}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, m.replyTopics, 1)
+	assert.Len(t, m.replyTopics["devices/acks"], 1)
+}
+
+func TestDeliveryNotConnected(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	tc.connected = false
+	err := m.DeliveryRequest(context.Background(), m.connID, newTestSubscription(), &core.EventDelivery{}, nil)
+	assert.Regexp(t, "FF10579", err)
+}
+
+func TestDeliverySubscribeTimeout(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	tc.subToken.timeout = true
+	err := m.DeliveryRequest(context.Background(), m.connID, newTestSubscription(), &core.EventDelivery{}, nil)
+	assert.Regexp(t, "FF10581.*subscribe", err)
+	assert.Empty(t, tc.published)
+}
+
+func TestDeliveryPublishFail(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	tc.publishToken.err = fmt.Errorf("pop")
+	err := m.DeliveryRequest(context.Background(), m.connID, newTestSubscription(), &core.EventDelivery{}, nil)
+	assert.Regexp(t, "FF10582.*publish.*pop", err)
+}
+
+func TestBatchDeliveryNotSupported(t *testing.T) {
+	m, _, _, done := newTestMQTT(t)
+	defer done()
+
+	err := m.BatchDeliveryRequest(context.Background(), m.connID, newTestSubscription(), nil)
+	assert.Regexp(t, "FF10461", err)
+}
+
+func TestReconnectResubscribes(t *testing.T) {
+	m, tc, _, done := newTestMQTT(t)
+	defer done()
+
+	err := m.DeliveryRequest(context.Background(), m.connID, newTestSubscription(), &core.EventDelivery{}, nil)
+	assert.NoError(t, err)
+
+	tc.subscribed = make(map[string]paho.MessageHandler)
+	m.onConnectionLost(tc, fmt.Errorf("pop"))
+	m.onConnect(tc)
+	assert.NotNil(t, tc.subscribed["firefly/ns1/sub1/ack"])
+
+	m.NamespaceRestarted("ns1", time.Now())
+}
@@ -364,3 +364,18 @@ func TestPersistBatchInvalidTXType(t *testing.T) {
 	assert.False(t, ok)
 
 }
+
+func TestValidateBatchDataEncryptedEnvelopeRejected(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	batch := &core.Batch{BatchHeader: core.BatchHeader{ID: fftypes.NewUUID()}}
+	data := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"$ffencrypted":{"keyId":"key1","ciphertext":"AAAA"}}`),
+		Hash:  fftypes.NewRandB32(),
+	}
+	assert.False(t, em.validateBatchData(em.ctx, batch, 0, data))
+
+}
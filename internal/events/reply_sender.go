@@ -21,22 +21,28 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/broadcast"
+	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
 func (ed *eventDispatcher) sendReply(ctx context.Context, event *core.Event, reply *core.MessageInOut) {
+	sendReply(ctx, ed.broadcast, ed.messaging, event, reply)
+}
+
+func sendReply(ctx context.Context, bm broadcast.Manager, pm privatemessaging.Manager, event *core.Event, reply *core.MessageInOut) {
 	var err error
 	if reply.Header.Group != nil {
-		if ed.messaging == nil {
+		if pm == nil {
 			err = fmt.Errorf("private messaging manager not initialized")
 		} else {
-			err = ed.messaging.NewMessage(reply).Send(ctx)
+			err = pm.NewMessage(reply).Send(ctx)
 		}
 	} else {
-		if ed.broadcast == nil {
+		if bm == nil {
 			err = fmt.Errorf("broadcast manager not initialized")
 		} else {
-			err = ed.broadcast.NewBroadcast(reply).Send(ctx)
+			err = bm.NewBroadcast(reply).Send(ctx)
 		}
 	}
 	if err != nil {
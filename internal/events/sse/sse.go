@@ -0,0 +1,208 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// SSE delivers the events of durable subscriptions with transport "sse" over a server-sent events stream,
+// opened by the client with a GET on the subscription. Each stream serves exactly one subscription.
+type SSE struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	connections  map[string]*sseConnection
+	connMux      sync.Mutex
+	pingInterval time.Duration
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+func (s *SSE) Name() string { return "sse" }
+
+func (s *SSE) Init(ctx context.Context, config config.Section) error {
+	*s = SSE{
+		ctx:          ctx,
+		connections:  make(map[string]*sseConnection),
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		pingInterval: config.GetDuration(PingInterval),
+	}
+	return nil
+}
+
+func (s *SSE) SetHandler(namespace string, handler events.Callbacks) error {
+	s.callbacks.writeLock.Lock()
+	defer s.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(s.callbacks.handlers, namespace)
+		return nil
+	}
+	s.callbacks.handlers[namespace] = handler
+	return nil
+}
+
+func (s *SSE) getHandler(namespace string) (events.Callbacks, bool) {
+	s.callbacks.writeLock.Lock()
+	defer s.callbacks.writeLock.Unlock()
+	cb, ok := s.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (s *SSE) Capabilities() *events.Capabilities {
+	return s.capabilities
+}
+
+func (s *SSE) ValidateOptions(ctx context.Context, options *core.SubscriptionOptions) error {
+	// As with websockets, we only stream references to the data
+	if options.WithData != nil && *options.WithData {
+		return i18n.NewError(ctx, coremsgs.MsgSSENoData)
+	}
+	forceFalse := false
+	options.WithData = &forceFalse
+	return nil
+}
+
+func (s *SSE) DeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	s.connMux.Lock()
+	sc, ok := s.connections[connID]
+	s.connMux.Unlock()
+	if !ok {
+		return i18n.NewError(ctx, coremsgs.MsgSSEConnectionNotActive, connID)
+	}
+	return sc.dispatch(event)
+}
+
+func (s *SSE) BatchDeliveryRequest(ctx context.Context, connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(ctx, coremsgs.MsgBatchDeliveryNotSupported, s.Name())
+}
+
+// ServeSubscription streams the events of a subscription to the client until it disconnects.
+// The context must be that of the client connection, rather than one bound by a request timeout.
+// An error is only returned if the stream could not be started, before anything is written to the response.
+func (s *SSE) ServeSubscription(ctx context.Context, sub *core.Subscription, res http.ResponseWriter, req *http.Request) error {
+	if sub.Transport != s.Name() {
+		return i18n.NewError(ctx, coremsgs.MsgSSEWrongTransport, sub.ID, sub.Transport)
+	}
+	if _, ok := s.getHandler(sub.Namespace); !ok {
+		return i18n.NewError(ctx, coremsgs.MsgSSENotEnabled, sub.Namespace)
+	}
+
+	// Sequences start at 1, so zero means the client is not resuming a previous stream
+	var lastEventID int64
+	if lastEventIDStr := req.Header.Get("Last-Event-ID"); lastEventIDStr != "" {
+		var err error
+		lastEventID, err = strconv.ParseInt(lastEventIDStr, 10, 64)
+		if err != nil || lastEventID < 0 {
+			return i18n.NewError(ctx, coremsgs.MsgSSEInvalidLastEventID, lastEventIDStr)
+		}
+	}
+
+	s.connMux.Lock()
+	sc := newConnection(ctx, s, sub, req, lastEventID)
+	s.connections[sc.connID] = sc
+	s.connMux.Unlock()
+	defer s.connClosed(sc)
+
+	if err := s.start(sc); err != nil {
+		return err
+	}
+	sc.stream(res)
+	return nil
+}
+
+// Ack acknowledges an event sent over a stream of the given subscription, so the next event can be delivered
+func (s *SSE) Ack(ctx context.Context, sub *core.Subscription, ack *core.SSEAck) error {
+	s.connMux.Lock()
+	sc, ok := s.connections[ack.Connection]
+	s.connMux.Unlock()
+	if !ok || !sc.sub.ID.Equals(sub.ID) {
+		return i18n.NewError(ctx, coremsgs.MsgSSEConnectionNotActive, ack.Connection)
+	}
+
+	// Perform a locked set of checks
+	inflight, err := sc.checkAck(ctx, ack)
+	if err != nil {
+		return err
+	}
+
+	// Deliver the ack to the core, now we're unlocked
+	s.ack(sc.connID, inflight)
+	return nil
+}
+
+func (s *SSE) ack(connID string, inflight *core.EventDeliveryResponse) {
+	if cb, ok := s.getHandler(inflight.Subscription.Namespace); ok {
+		cb.DeliveryResponse(connID, inflight)
+	}
+}
+
+func (s *SSE) start(sc *sseConnection) error {
+	cb, ok := s.getHandler(sc.sub.Namespace)
+	if !ok {
+		return i18n.NewError(sc.ctx, coremsgs.MsgSSENotEnabled, sc.sub.Namespace)
+	}
+	return cb.RegisterConnection(sc.connID, sc.subMatcher)
+}
+
+func (s *SSE) connClosed(sc *sseConnection) {
+	sc.cancelCtx()
+	s.connMux.Lock()
+	delete(s.connections, sc.connID)
+	s.connMux.Unlock()
+	// Drop lock before calling back
+	s.callbacks.writeLock.Lock()
+	handlers := make([]events.Callbacks, 0, len(s.callbacks.handlers))
+	for _, cb := range s.callbacks.handlers {
+		handlers = append(handlers, cb)
+	}
+	s.callbacks.writeLock.Unlock()
+	for _, cb := range handlers {
+		cb.ConnectionClosed(sc.connID)
+	}
+}
+
+func (s *SSE) NamespaceRestarted(ns string, startTime time.Time) {
+
+	s.connMux.Lock()
+	connections := make([]*sseConnection, 0, len(s.connections))
+	for _, sc := range s.connections {
+		connections = append(connections, sc)
+	}
+	s.connMux.Unlock()
+
+	for _, sc := range connections {
+		sc.restartForNamespace(ns, startTime)
+	}
+
+}
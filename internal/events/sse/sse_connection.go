@@ -0,0 +1,217 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type sseConnection struct {
+	ctx          context.Context
+	sse          *SSE
+	cancelCtx    func()
+	connID       string
+	sub          *core.Subscription
+	sendMessages chan *sseMessage
+	autoAck      bool
+	lastEventID  int64
+	inflight     []*core.EventDeliveryResponse
+	startTime    time.Time
+	mux          sync.Mutex
+}
+
+type sseMessage struct {
+	event    *core.EventDelivery
+	inflight *core.EventDeliveryResponse
+}
+
+func newConnection(pCtx context.Context, s *SSE, sub *core.Subscription, req *http.Request, lastEventID int64) *sseConnection {
+	connID := fftypes.NewUUID().String()
+	ctx := log.WithLogField(pCtx, "sse", connID)
+	ctx, cancelCtx := context.WithCancel(ctx)
+	return &sseConnection{
+		ctx:          ctx,
+		sse:          s,
+		cancelCtx:    cancelCtx,
+		connID:       connID,
+		sub:          sub,
+		sendMessages: make(chan *sseMessage),
+		autoAck:      isBoolQuerySet(req.URL.Query(), "autoack"),
+		lastEventID:  lastEventID,
+		startTime:    time.Now(),
+	}
+}
+
+func isBoolQuerySet(query url.Values, boolOption string) bool {
+	optionValues, hasOptionValues := query[boolOption]
+	return hasOptionValues && (len(optionValues) == 0 || optionValues[0] != "false")
+}
+
+func (sc *sseConnection) subMatcher(sr core.SubscriptionRef) bool {
+	return sr.Namespace == sc.sub.Namespace && sr.Name == sc.sub.Name
+}
+
+// stream writes events to the client until it disconnects, or the server shuts down
+func (sc *sseConnection) stream(res http.ResponseWriter) {
+	l := log.L(sc.ctx)
+	rc := http.NewResponseController(res)
+	// The stream is long lived, so must not be cut off by the write timeout of the server
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		l.Debugf("Unable to clear write deadline: %s", err)
+	}
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.WriteHeader(http.StatusOK)
+
+	connected, _ := json.Marshal(&core.SSEConnected{Connection: sc.connID})
+	if err := sc.write(res, rc, fmt.Sprintf("event: connected\ndata: %s\n\n", connected)); err != nil {
+		l.Errorf("Write failed on stream: %s", err)
+		return
+	}
+
+	var ping <-chan time.Time
+	if sc.sse.pingInterval > 0 {
+		ticker := time.NewTicker(sc.sse.pingInterval)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+	for {
+		var frame string
+		var autoAck *core.EventDeliveryResponse
+		select {
+		case msg := <-sc.sendMessages:
+			data, err := json.Marshal(msg.event)
+			if err != nil {
+				l.Errorf("Failed to serialize event %s: %s", msg.event.ID, err)
+				return
+			}
+			l.Tracef("Sending: %s", data)
+			// The sequence is the ID of the event on the stream, so clients resume from it on reconnect
+			frame = fmt.Sprintf("id: %d\ndata: %s\n\n", msg.event.Sequence, data)
+			if sc.autoAck {
+				autoAck = msg.inflight
+			}
+		case <-ping:
+			frame = ": ping\n\n"
+		case <-sc.ctx.Done():
+			l.Debugf("Stream closing - client disconnected")
+			return
+		case <-sc.sse.ctx.Done():
+			l.Debugf("Stream closing - server shutting down")
+			return
+		}
+		if err := sc.write(res, rc, frame); err != nil {
+			l.Errorf("Write failed on stream: %s", err)
+			return
+		}
+		if autoAck != nil {
+			sc.sse.ack(sc.connID, autoAck)
+		}
+	}
+}
+
+func (sc *sseConnection) write(res http.ResponseWriter, rc *http.ResponseController, frame string) error {
+	if _, err := res.Write([]byte(frame)); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
+
+func (sc *sseConnection) dispatch(event *core.EventDelivery) error {
+	inflight := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+
+	if event.Sequence <= sc.lastEventID {
+		// The client told us on reconnect that it had already received this event
+		log.L(sc.ctx).Debugf("Acknowledging event %s (sequence=%d) from Last-Event-ID=%d", event.ID, event.Sequence, sc.lastEventID)
+		sc.sse.ack(sc.connID, inflight)
+		return nil
+	}
+
+	if !sc.autoAck {
+		sc.mux.Lock()
+		sc.inflight = append(sc.inflight, inflight)
+		sc.mux.Unlock()
+	}
+
+	select {
+	case sc.sendMessages <- &sseMessage{event: event, inflight: inflight}:
+		return nil
+	case <-sc.ctx.Done():
+		return i18n.NewError(sc.ctx, coremsgs.MsgSSEConnectionNotActive, sc.connID)
+	}
+}
+
+func (sc *sseConnection) checkAck(ctx context.Context, ack *core.SSEAck) (*core.EventDeliveryResponse, error) {
+	var inflight *core.EventDeliveryResponse
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+
+	if sc.autoAck {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSSEAutoAckEnabled, sc.connID)
+	}
+
+	if ack.ID != nil {
+		newInflight := make([]*core.EventDeliveryResponse, 0, len(sc.inflight))
+		for _, candidate := range sc.inflight {
+			if inflight == nil && candidate.ID.Equals(ack.ID) {
+				inflight = candidate
+			} else {
+				newInflight = append(newInflight, candidate)
+			}
+		}
+		sc.inflight = newInflight
+	} else if len(sc.inflight) > 0 {
+		// Just ack the front of the queue
+		inflight = sc.inflight[0]
+		sc.inflight = sc.inflight[1:]
+	}
+	if inflight == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSSEAckNotMatched, sc.connID)
+	}
+	return inflight, nil
+}
+
+func (sc *sseConnection) restartForNamespace(ns string, startTime time.Time) {
+	sc.mux.Lock()
+	restart := sc.sub.Namespace == ns && sc.startTime.Before(startTime)
+	if restart {
+		sc.startTime = time.Now()
+	}
+	sc.mux.Unlock()
+	if restart {
+		log.L(sc.ctx).Infof("Restarting subscription '%s:%s'", sc.sub.Namespace, sc.sub.Name)
+		if err := sc.sse.start(sc); err != nil {
+			log.L(sc.ctx).Errorf("Failed restart subscription '%s:%s' (closing): %s", sc.sub.Namespace, sc.sub.Name, err)
+			sc.cancelCtx()
+		}
+	}
+}
@@ -0,0 +1,492 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSSE(t *testing.T, cbs *eventsmocks.Callbacks) (s *SSE, cancel func()) {
+	coreconfig.Reset()
+
+	s = &SSE{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	svrConfig := config.RootSection("ut.sse")
+	s.InitConfig(svrConfig)
+	err := s.Init(ctx, svrConfig)
+	assert.NoError(t, err)
+	s.SetHandler("ns1", cbs)
+	return s, cancelCtx
+}
+
+func newTestSub() *core.Subscription {
+	return &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "sse",
+	}
+}
+
+func newTestEvent(sub *core.Subscription, sequence int64) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Sequence:  sequence,
+				Namespace: "ns1",
+				Type:      core.EventTypeMessageConfirmed,
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+}
+
+type testStream struct {
+	svr    *httptest.Server
+	res    *http.Response
+	reader *bufio.Reader
+	done   chan struct{}
+}
+
+func (ts *testStream) close() {
+	ts.res.Body.Close()
+	<-ts.done
+	ts.svr.Close()
+}
+
+func openTestStream(t *testing.T, s *SSE, sub *core.Subscription, query string, header http.Header) *testStream {
+	ts := &testStream{done: make(chan struct{})}
+	ts.svr = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		defer close(ts.done)
+		if err := s.ServeSubscription(req.Context(), sub, res, req); err != nil {
+			res.WriteHeader(http.StatusBadRequest)
+			_, _ = res.Write([]byte(err.Error()))
+		}
+	}))
+	req, err := http.NewRequest(http.MethodGet, ts.svr.URL+query, nil)
+	assert.NoError(t, err)
+	if header != nil {
+		req.Header = header
+	}
+	ts.res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	ts.reader = bufio.NewReader(ts.res.Body)
+	return ts
+}
+
+// readFrame reads the fields of the next event from the stream, with comments under the empty field name
+func (ts *testStream) readFrame(t *testing.T) map[string]string {
+	frame := make(map[string]string)
+	for {
+		line, err := ts.reader.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return frame
+		}
+		field, value, _ := strings.Cut(line, ":")
+		frame[field] = strings.TrimPrefix(value, " ")
+	}
+}
+
+func (ts *testStream) readConnected(t *testing.T) string {
+	frame := ts.readFrame(t)
+	assert.Equal(t, "connected", frame["event"])
+	var connected core.SSEConnected
+	err := fftypes.JSONAnyPtr(frame["data"]).Unmarshal(context.Background(), &connected)
+	assert.NoError(t, err)
+	return connected.Connection
+}
+
+func (ts *testStream) readEvent(t *testing.T) (int64, *core.EventDelivery) {
+	frame := ts.readFrame(t)
+	var event core.EventDelivery
+	err := fftypes.JSONAnyPtr(frame["data"]).Unmarshal(context.Background(), &event)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d", event.Sequence), frame["id"])
+	return event.Sequence, &event
+}
+
+func mockRegister(cbs *eventsmocks.Callbacks, sub *core.Subscription) chan string {
+	registered := make(chan string, 1)
+	cbs.On("RegisterConnection", mock.Anything, mock.MatchedBy(func(matcher events.SubscriptionMatcher) bool {
+		return matcher(sub.SubscriptionRef) && !matcher(core.SubscriptionRef{Namespace: "ns1", Name: "sub2"})
+	})).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	}).Return(nil)
+	return registered
+}
+
+func mockClosed(cbs *eventsmocks.Callbacks) chan string {
+	closed := make(chan string, 1)
+	cbs.On("ConnectionClosed", mock.Anything).Run(func(args mock.Arguments) {
+		closed <- args[0].(string)
+	}).Return(nil)
+	return closed
+}
+
+func mockDeliveryResponse(cbs *eventsmocks.Callbacks, connID string) chan *core.EventDeliveryResponse {
+	acks := make(chan *core.EventDeliveryResponse, 10)
+	cbs.On("DeliveryResponse", connID, mock.Anything).Run(func(args mock.Arguments) {
+		acks <- args[1].(*core.EventDeliveryResponse)
+	}).Return(nil)
+	return acks
+}
+
+func TestSSEPluginBasics(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	assert.Equal(t, "sse", s.Name())
+	assert.False(t, s.Capabilities().BatchDelivery)
+
+	err := s.BatchDeliveryRequest(s.ctx, "conn1", newTestSub(), nil)
+	assert.Regexp(t, "FF10461", err)
+
+	err = s.DeliveryRequest(s.ctx, "conn1", newTestSub(), newTestEvent(newTestSub(), 1), nil)
+	assert.Regexp(t, "FF10524", err)
+
+	s.SetHandler("ns1", nil)
+	_, ok := s.getHandler("ns1")
+	assert.False(t, ok)
+}
+
+func TestValidateOptions(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	yes := true
+	err := s.ValidateOptions(s.ctx, &core.SubscriptionOptions{
+		SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+			WithData: &yes,
+		},
+	})
+	assert.Regexp(t, "FF10527", err)
+
+	opts := &core.SubscriptionOptions{}
+	err = s.ValidateOptions(s.ctx, opts)
+	assert.NoError(t, err)
+	assert.False(t, *opts.WithData)
+}
+
+func TestStreamDeliverAndAck(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	registered := mockRegister(cbs, sub)
+	closed := mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "", nil)
+	assert.Equal(t, http.StatusOK, ts.res.StatusCode)
+	assert.Equal(t, "text/event-stream", ts.res.Header.Get("Content-Type"))
+	connID := ts.readConnected(t)
+	assert.Equal(t, connID, <-registered)
+	acks := mockDeliveryResponse(cbs, connID)
+
+	// Ack without an ID takes the oldest event in flight
+	event1 := newTestEvent(sub, 10)
+	err := s.DeliveryRequest(s.ctx, connID, sub, event1, nil)
+	assert.NoError(t, err)
+	event2 := newTestEvent(sub, 11)
+	err = s.DeliveryRequest(s.ctx, connID, sub, event2, nil)
+	assert.NoError(t, err)
+	seq, received := ts.readEvent(t)
+	assert.Equal(t, int64(10), seq)
+	assert.Equal(t, event1.ID, received.ID)
+	seq, _ = ts.readEvent(t)
+	assert.Equal(t, int64(11), seq)
+
+	err = s.Ack(s.ctx, sub, &core.SSEAck{Connection: connID, ID: event2.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, event2.ID, (<-acks).ID)
+	err = s.Ack(s.ctx, sub, &core.SSEAck{Connection: connID})
+	assert.NoError(t, err)
+	assert.Equal(t, event1.ID, (<-acks).ID)
+
+	// Nothing left in flight
+	err = s.Ack(s.ctx, sub, &core.SSEAck{Connection: connID})
+	assert.Regexp(t, "FF10525", err)
+	err = s.Ack(s.ctx, sub, &core.SSEAck{Connection: connID, ID: event1.ID})
+	assert.Regexp(t, "FF10525", err)
+
+	// Acks must come for the subscription of the stream
+	err = s.Ack(s.ctx, newTestSub(), &core.SSEAck{Connection: connID})
+	assert.Regexp(t, "FF10524", err)
+
+	ts.close()
+	assert.Equal(t, connID, <-closed)
+	err = s.Ack(s.ctx, sub, &core.SSEAck{Connection: connID})
+	assert.Regexp(t, "FF10524", err)
+	cbs.AssertExpectations(t)
+}
+
+func TestStreamAutoAck(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	registered := mockRegister(cbs, sub)
+	mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "?autoack", nil)
+	defer ts.close()
+	connID := ts.readConnected(t)
+	<-registered
+	acks := mockDeliveryResponse(cbs, connID)
+
+	event := newTestEvent(sub, 1)
+	err := s.DeliveryRequest(s.ctx, connID, sub, event, nil)
+	assert.NoError(t, err)
+	ts.readEvent(t)
+	assert.Equal(t, event.ID, (<-acks).ID)
+
+	err = s.Ack(s.ctx, sub, &core.SSEAck{Connection: connID})
+	assert.Regexp(t, "FF10526", err)
+}
+
+func TestStreamResumeLastEventID(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	registered := mockRegister(cbs, sub)
+	mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "", http.Header{"Last-Event-ID": []string{"5"}})
+	defer ts.close()
+	connID := ts.readConnected(t)
+	<-registered
+	acks := mockDeliveryResponse(cbs, connID)
+
+	// The client already has the redelivered event, so it is acknowledged without being sent
+	redelivered := newTestEvent(sub, 5)
+	err := s.DeliveryRequest(s.ctx, connID, sub, redelivered, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, redelivered.ID, (<-acks).ID)
+
+	err = s.DeliveryRequest(s.ctx, connID, sub, newTestEvent(sub, 6), nil)
+	assert.NoError(t, err)
+	seq, _ := ts.readEvent(t)
+	assert.Equal(t, int64(6), seq)
+}
+
+func TestStreamPing(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	s.pingInterval = 1 * time.Millisecond
+	sub := newTestSub()
+	mockRegister(cbs, sub)
+	mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "", nil)
+	defer ts.close()
+	ts.readConnected(t)
+	frame := ts.readFrame(t)
+	assert.Equal(t, map[string]string{"": "ping"}, frame)
+}
+
+func TestStreamServerShutdown(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	sub := newTestSub()
+	mockRegister(cbs, sub)
+	closed := mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "", nil)
+	defer ts.close()
+	connID := ts.readConnected(t)
+	cancel()
+	assert.Equal(t, connID, <-closed)
+	_, err := io.ReadAll(ts.reader)
+	assert.NoError(t, err)
+}
+
+func TestStreamInvalidLastEventID(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	ts := openTestStream(t, s, newTestSub(), "", http.Header{"Last-Event-ID": []string{"bad"}})
+	defer ts.close()
+	assert.Equal(t, http.StatusBadRequest, ts.res.StatusCode)
+	body, _ := io.ReadAll(ts.reader)
+	assert.Regexp(t, "FF10528", string(body))
+}
+
+func TestStreamWrongTransport(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	sub.Transport = "websockets"
+
+	ts := openTestStream(t, s, sub, "", nil)
+	defer ts.close()
+	body, _ := io.ReadAll(ts.reader)
+	assert.Regexp(t, "FF10523", string(body))
+}
+
+func TestStreamNotEnabled(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	sub.Namespace = "ns2"
+
+	ts := openTestStream(t, s, sub, "", nil)
+	defer ts.close()
+	body, _ := io.ReadAll(ts.reader)
+	assert.Regexp(t, "FF10522", string(body))
+}
+
+func TestStreamRegisterFail(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	closed := mockClosed(cbs)
+
+	ts := openTestStream(t, s, newTestSub(), "", nil)
+	defer ts.close()
+	body, _ := io.ReadAll(ts.reader)
+	assert.Regexp(t, "pop", string(body))
+	<-closed
+}
+
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestStreamFlushFail(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	mockRegister(cbs, sub)
+	closed := mockClosed(cbs)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	err := s.ServeSubscription(context.Background(), sub, &noFlushWriter{httptest.NewRecorder()}, req)
+	assert.NoError(t, err)
+	<-closed
+}
+
+type failWriter struct {
+	http.ResponseWriter
+}
+
+func (w *failWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("pop")
+}
+
+func TestStreamWriteFail(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	mockRegister(cbs, sub)
+	closed := mockClosed(cbs)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	err := s.ServeSubscription(context.Background(), sub, &failWriter{httptest.NewRecorder()}, req)
+	assert.NoError(t, err)
+	<-closed
+}
+
+func TestStreamSerializeFail(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	registered := mockRegister(cbs, sub)
+	closed := mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "", nil)
+	defer ts.close()
+	connID := ts.readConnected(t)
+	<-registered
+
+	event := newTestEvent(sub, 1)
+	event.Datatype = &core.Datatype{Value: fftypes.JSONAnyPtr("!json")}
+	err := s.DeliveryRequest(s.ctx, connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, connID, <-closed)
+}
+
+func TestDispatchConnectionClosed(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	sc := newConnection(context.Background(), s, sub, req, 0)
+	sc.cancelCtx()
+	err := sc.dispatch(newTestEvent(sub, 1))
+	assert.Regexp(t, "FF10524", err)
+}
+
+func TestNamespaceRestarted(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	s, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	sub := newTestSub()
+	registered := mockRegister(cbs, sub)
+	mockClosed(cbs)
+
+	ts := openTestStream(t, s, sub, "", nil)
+	defer ts.close()
+	connID := ts.readConnected(t)
+	<-registered
+
+	// Other namespaces, and restarts older than the stream, are ignored
+	s.NamespaceRestarted("ns2", time.Now())
+	s.NamespaceRestarted("ns1", time.Now().Add(-1*time.Hour))
+
+	s.NamespaceRestarted("ns1", time.Now())
+	assert.Equal(t, connID, <-registered)
+
+	// The stream is closed if the subscription cannot be restarted
+	s.SetHandler("ns1", nil)
+	s.NamespaceRestarted("ns1", time.Now())
+	_, err := io.ReadAll(ts.reader)
+	assert.NoError(t, err)
+}
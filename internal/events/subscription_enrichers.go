@@ -0,0 +1,168 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// subscriptionEnricher makes one additional lookup for an event that is about to be delivered to a subscription,
+// and adds the result to the enrichment of the event. The lookups are specific to the subscription, so unlike
+// enrichEvent the result is not cached with the event.
+type subscriptionEnricher func(ctx context.Context, event *core.EventDelivery, batch *enrichmentBatch) error
+
+// enrichmentBatch caches the lookups made while enriching one batch of events, as consecutive events commonly
+// share an author or a listener
+type enrichmentBatch struct {
+	authorDIDs map[string]*fftypes.JSONAny
+	listeners  map[fftypes.UUID]*core.ContractListener
+}
+
+func newEnrichmentBatch() *enrichmentBatch {
+	return &enrichmentBatch{
+		authorDIDs: make(map[string]*fftypes.JSONAny),
+		listeners:  make(map[fftypes.UUID]*core.ContractListener),
+	}
+}
+
+func validateEnrichers(ctx context.Context, options *core.SubscriptionOptions) error {
+	for i, e := range options.Enrich {
+		enricher, err := fftypes.FFEnumParseString(ctx, "subenricher", string(e))
+		if err != nil {
+			return i18n.WrapError(ctx, err, coremsgs.MsgUnknownSubscriptionEnricher, e)
+		}
+		options.Enrich[i] = enricher
+	}
+	return nil
+}
+
+// subscriptionEnrichers returns the pipeline of enrichers configured on a subscription, in the order they are listed
+func (em *eventEnricher) subscriptionEnrichers(options *core.SubscriptionOptions) []subscriptionEnricher {
+	enrichers := make([]subscriptionEnricher, 0, len(options.Enrich))
+	for _, e := range options.Enrich {
+		switch e {
+		case core.SubOptsEnricherMessageData:
+			enrichers = append(enrichers, em.enrichMessageData)
+		case core.SubOptsEnricherAuthorDID:
+			enrichers = append(enrichers, em.enrichAuthorDID)
+		case core.SubOptsEnricherBlockchainEventFFI:
+			enrichers = append(enrichers, em.enrichBlockchainEventFFI)
+		}
+	}
+	return enrichers
+}
+
+// enrichDelivery runs each enricher of the pipeline in turn on an event, stopping at the first error
+func enrichDelivery(ctx context.Context, enrichers []subscriptionEnricher, batch *enrichmentBatch, event *core.EventDelivery) error {
+	for _, enrich := range enrichers {
+		if err := enrich(ctx, event, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func enrichment(event *core.EventDelivery) *core.EventEnrichment {
+	if event.Enrichment == nil {
+		event.Enrichment = &core.EventEnrichment{}
+	}
+	return event.Enrichment
+}
+
+func (em *eventEnricher) enrichMessageData(ctx context.Context, event *core.EventDelivery, batch *enrichmentBatch) error {
+	if event.Message == nil {
+		return nil
+	}
+	data, _, err := em.data.GetMessageDataCached(ctx, event.Message)
+	if err != nil {
+		return err
+	}
+	enrichment(event).Data = data
+	return nil
+}
+
+func (em *eventEnricher) enrichAuthorDID(ctx context.Context, event *core.EventDelivery, batch *enrichmentBatch) error {
+	if event.Message == nil || event.Message.Header.Author == "" {
+		return nil
+	}
+	author := event.Message.Header.Author
+	doc, cached := batch.authorDIDs[author]
+	if !cached {
+		identity, retryable, err := em.identity.CachedIdentityLookupMustExist(ctx, author)
+		if err != nil {
+			if retryable {
+				return err
+			}
+			// The author might not be registered as an identity in this namespace, which does not stop the delivery
+			log.L(ctx).Warnf("Unable to resolve DID document of author '%s' of message %s: %s", author, event.Message.Header.ID, err)
+		} else if identity.Namespace != em.namespace {
+			// The lookup can fall back to the legacy system namespace, where DID documents are not available
+			log.L(ctx).Warnf("No DID document for author '%s' of message %s, in namespace '%s'", author, event.Message.Header.ID, identity.Namespace)
+		} else {
+			didDoc, err := em.networkmap.GetDIDDocForIndentityByDID(ctx, author)
+			if err != nil {
+				return err
+			}
+			b, _ := json.Marshal(didDoc)
+			doc = fftypes.JSONAnyPtrBytes(b)
+		}
+		batch.authorDIDs[author] = doc
+	}
+	if doc != nil {
+		enrichment(event).AuthorDID = doc
+	}
+	return nil
+}
+
+func (em *eventEnricher) enrichBlockchainEventFFI(ctx context.Context, event *core.EventDelivery, batch *enrichmentBatch) error {
+	be := event.BlockchainEvent
+	if be == nil || be.Listener == nil {
+		return nil
+	}
+	listener, cached := batch.listeners[*be.Listener]
+	if !cached {
+		var err error
+		if listener, err = em.database.GetContractListenerByID(ctx, em.namespace, be.Listener); err != nil {
+			return err
+		}
+		batch.listeners[*be.Listener] = listener
+	}
+	if listener == nil {
+		return nil
+	}
+
+	// A listener can have a filter for each of a number of events, so find the definition with the name of the event
+	for _, filter := range listener.Filters {
+		if filter.Event != nil && filter.Event.Name == be.Name {
+			enrichment(event).FFIEvent = &filter.Event.FFIEventDefinition
+			event.Enrichment.Interface = filter.Interface
+			return nil
+		}
+	}
+	if listener.Event != nil && listener.Event.Name == be.Name {
+		enrichment(event).FFIEvent = &listener.Event.FFIEventDefinition
+		event.Enrichment.Interface = listener.Interface
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/networkmap"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func messageDelivery(author string) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed},
+			Message: &core.Message{
+				Header: core.MessageHeader{
+					ID:        fftypes.NewUUID(),
+					SignerRef: core.SignerRef{Author: author},
+				},
+			},
+		},
+	}
+}
+
+func blockchainEventDelivery(name string, listener *fftypes.UUID) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeBlockchainEventReceived},
+			BlockchainEvent: &core.BlockchainEvent{
+				ID:       fftypes.NewUUID(),
+				Name:     name,
+				Listener: listener,
+			},
+		},
+	}
+}
+
+func TestSubscriptionEnrichersPipeline(t *testing.T) {
+	em := newTestEventEnricher()
+	enrichers := em.subscriptionEnrichers(&core.SubscriptionOptions{
+		SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+			Enrich: []core.SubOptsEnricher{
+				core.SubOptsEnricherMessageData,
+				core.SubOptsEnricherAuthorDID,
+				core.SubOptsEnricherBlockchainEventFFI,
+			},
+		},
+	})
+	assert.Len(t, enrichers, 3)
+
+	// Enrichers that do not apply to an event leave it without an enrichment
+	event := blockchainEventDelivery("Changed", nil)
+	err := enrichDelivery(context.Background(), enrichers, newEnrichmentBatch(), event)
+	assert.NoError(t, err)
+	assert.Nil(t, event.Enrichment)
+}
+
+func TestEnrichMessageData(t *testing.T) {
+	em := newTestEventEnricher()
+	mdm := em.data.(*datamocks.Manager)
+	event := messageDelivery("did:firefly:org/org1")
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	mdm.On("GetMessageDataCached", context.Background(), event.Message).Return(data, true, nil)
+
+	err := em.enrichMessageData(context.Background(), event, newEnrichmentBatch())
+	assert.NoError(t, err)
+	assert.Equal(t, data, event.Enrichment.Data)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestEnrichMessageDataFail(t *testing.T) {
+	em := newTestEventEnricher()
+	mdm := em.data.(*datamocks.Manager)
+	event := messageDelivery("did:firefly:org/org1")
+	mdm.On("GetMessageDataCached", context.Background(), event.Message).Return(nil, false, fmt.Errorf("pop"))
+
+	err := em.enrichMessageData(context.Background(), event, newEnrichmentBatch())
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+}
+
+func TestEnrichAuthorDID(t *testing.T) {
+	em := newTestEventEnricher()
+	mim := em.identity.(*identitymanagermocks.Manager)
+	mnm := em.networkmap.(*networkmapmocks.Manager)
+	ctx := context.Background()
+	author := "did:firefly:org/org1"
+	mim.On("CachedIdentityLookupMustExist", ctx, author).Return(&core.Identity{IdentityBase: core.IdentityBase{Namespace: "ns1"}}, false, nil).Once()
+	mnm.On("GetDIDDocForIndentityByDID", ctx, author).Return(&networkmap.DIDDocument{ID: author}, nil).Once()
+
+	batch := newEnrichmentBatch()
+	event1 := messageDelivery(author)
+	err := em.enrichAuthorDID(ctx, event1, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, author, event1.Enrichment.AuthorDID.JSONObject().GetString("id"))
+
+	// The document is only resolved once for each batch
+	event2 := messageDelivery(author)
+	err = em.enrichAuthorDID(ctx, event2, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, event1.Enrichment.AuthorDID, event2.Enrichment.AuthorDID)
+
+	err = em.enrichAuthorDID(ctx, messageDelivery(""), batch)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mnm.AssertExpectations(t)
+}
+
+func TestEnrichAuthorDIDUnknownAuthor(t *testing.T) {
+	em := newTestEventEnricher()
+	mim := em.identity.(*identitymanagermocks.Manager)
+	ctx := context.Background()
+	author := "did:firefly:org/unknown"
+	mim.On("CachedIdentityLookupMustExist", ctx, author).Return(nil, false, fmt.Errorf("not found")).Once()
+
+	batch := newEnrichmentBatch()
+	event := messageDelivery(author)
+	err := em.enrichAuthorDID(ctx, event, batch)
+	assert.NoError(t, err)
+	assert.Nil(t, event.Enrichment)
+
+	err = em.enrichAuthorDID(ctx, messageDelivery(author), batch)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestEnrichAuthorDIDOtherNamespace(t *testing.T) {
+	em := newTestEventEnricher()
+	mim := em.identity.(*identitymanagermocks.Manager)
+	ctx := context.Background()
+	author := "did:firefly:org/org1"
+	mim.On("CachedIdentityLookupMustExist", ctx, author).Return(&core.Identity{IdentityBase: core.IdentityBase{Namespace: core.LegacySystemNamespace}}, false, nil).Once()
+
+	event := messageDelivery(author)
+	err := em.enrichAuthorDID(ctx, event, newEnrichmentBatch())
+	assert.NoError(t, err)
+	assert.Nil(t, event.Enrichment)
+
+	mim.AssertExpectations(t)
+}
+
+func TestEnrichAuthorDIDLookupFail(t *testing.T) {
+	em := newTestEventEnricher()
+	mim := em.identity.(*identitymanagermocks.Manager)
+	ctx := context.Background()
+	author := "did:firefly:org/org1"
+	mim.On("CachedIdentityLookupMustExist", ctx, author).Return(nil, true, fmt.Errorf("pop"))
+
+	err := em.enrichAuthorDID(ctx, messageDelivery(author), newEnrichmentBatch())
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestEnrichAuthorDIDDocumentFail(t *testing.T) {
+	em := newTestEventEnricher()
+	mim := em.identity.(*identitymanagermocks.Manager)
+	mnm := em.networkmap.(*networkmapmocks.Manager)
+	ctx := context.Background()
+	author := "did:firefly:org/org1"
+	mim.On("CachedIdentityLookupMustExist", ctx, author).Return(&core.Identity{IdentityBase: core.IdentityBase{Namespace: "ns1"}}, false, nil)
+	mnm.On("GetDIDDocForIndentityByDID", ctx, author).Return(nil, fmt.Errorf("pop"))
+
+	err := em.enrichAuthorDID(ctx, messageDelivery(author), newEnrichmentBatch())
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mnm.AssertExpectations(t)
+}
+
+func TestEnrichBlockchainEventFFIFilters(t *testing.T) {
+	em := newTestEventEnricher()
+	mdi := em.database.(*databasemocks.Plugin)
+	ctx := context.Background()
+	listenerID := fftypes.NewUUID()
+	iface := &fftypes.FFIReference{ID: fftypes.NewUUID()}
+	mdi.On("GetContractListenerByID", ctx, "ns1", listenerID).Return(&core.ContractListener{
+		ID: listenerID,
+		Filters: core.ListenerFilters{
+			{Event: &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "Created"}}},
+			{Event: &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "Changed"}}, Interface: iface},
+		},
+	}, nil).Once()
+
+	batch := newEnrichmentBatch()
+	event := blockchainEventDelivery("Changed", listenerID)
+	err := em.enrichBlockchainEventFFI(ctx, event, batch)
+	assert.NoError(t, err)
+	assert.Equal(t, "Changed", event.Enrichment.FFIEvent.Name)
+	assert.Equal(t, iface, event.Enrichment.Interface)
+
+	// The listener is only read once for each batch
+	event = blockchainEventDelivery("Other", listenerID)
+	err = em.enrichBlockchainEventFFI(ctx, event, batch)
+	assert.NoError(t, err)
+	assert.Nil(t, event.Enrichment)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEnrichBlockchainEventFFIListenerEvent(t *testing.T) {
+	em := newTestEventEnricher()
+	mdi := em.database.(*databasemocks.Plugin)
+	ctx := context.Background()
+	listenerID := fftypes.NewUUID()
+	iface := &fftypes.FFIReference{ID: fftypes.NewUUID()}
+	mdi.On("GetContractListenerByID", ctx, "ns1", listenerID).Return(&core.ContractListener{
+		ID:        listenerID,
+		Interface: iface,
+		Event:     &core.FFISerializedEvent{FFIEventDefinition: fftypes.FFIEventDefinition{Name: "Changed"}},
+	}, nil)
+
+	event := blockchainEventDelivery("Changed", listenerID)
+	err := em.enrichBlockchainEventFFI(ctx, event, newEnrichmentBatch())
+	assert.NoError(t, err)
+	assert.Equal(t, "Changed", event.Enrichment.FFIEvent.Name)
+	assert.Equal(t, iface, event.Enrichment.Interface)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEnrichBlockchainEventFFIListenerNotFound(t *testing.T) {
+	em := newTestEventEnricher()
+	mdi := em.database.(*databasemocks.Plugin)
+	ctx := context.Background()
+	listenerID := fftypes.NewUUID()
+	mdi.On("GetContractListenerByID", ctx, "ns1", listenerID).Return(nil, nil)
+
+	event := blockchainEventDelivery("Changed", listenerID)
+	err := em.enrichBlockchainEventFFI(ctx, event, newEnrichmentBatch())
+	assert.NoError(t, err)
+	assert.Nil(t, event.Enrichment)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEnrichBlockchainEventFFIListenerFail(t *testing.T) {
+	em := newTestEventEnricher()
+	mdi := em.database.(*databasemocks.Plugin)
+	ctx := context.Background()
+	listenerID := fftypes.NewUUID()
+	mdi.On("GetContractListenerByID", ctx, "ns1", listenerID).Return(nil, fmt.Errorf("pop"))
+
+	err := em.enrichBlockchainEventFFI(ctx, blockchainEventDelivery("Changed", listenerID), newEnrichmentBatch())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
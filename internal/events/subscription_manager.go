@@ -47,6 +47,7 @@ type subscription struct {
 	blockchainFilter   *blockchainFilter
 	transactionFilter  *transactionFilter
 	topicFilter        *regexp.Regexp
+	replayMarker       *core.Event // set only while dispatchers are created after the subscription is rewound
 }
 
 type messageFilter struct {
@@ -91,6 +92,7 @@ type subscriptionManager struct {
 	newOrUpdatedSubscriptions chan *fftypes.UUID
 	deletedSubscriptions      chan *fftypes.UUID
 	retry                     retry.Retry
+	deliveryErrors            *deliveryErrors
 
 	defaultBatchSize    uint16
 	defaultBatchTimeout time.Duration
@@ -122,6 +124,7 @@ func newSubscriptionManager(ctx context.Context, ns *core.Namespace, enricher *e
 		},
 		defaultBatchSize:    uint16(config.GetInt(coreconfig.SubscriptionDefaultsBatchSize)),
 		defaultBatchTimeout: config.GetDuration(coreconfig.SubscriptionDefaultsBatchTimeout),
+		deliveryErrors:      newDeliveryErrors(config.GetInt(coreconfig.SubscriptionDeliveryErrorHistory)),
 	}
 
 	for _, ei := range sm.transports {
@@ -239,6 +242,7 @@ func (sm *subscriptionManager) closeDurableSubscriptionLocked(id *fftypes.UUID)
 
 func (sm *subscriptionManager) deletedDurableSubscription(id *fftypes.UUID) {
 	sm.mux.Lock()
+	sub := sm.durableSubs[*id]
 	loaded, dispatchers := sm.closeDurableSubscriptionLocked(id)
 	sm.mux.Unlock()
 
@@ -248,6 +252,12 @@ func (sm *subscriptionManager) deletedDurableSubscription(id *fftypes.UUID) {
 	for _, dispatcher := range dispatchers {
 		dispatcher.close()
 	}
+	// Let the transport release anything it holds for the subscription
+	if sub != nil {
+		if handler, ok := sm.transports[sub.definition.Transport].(events.DeletedSubscriptionHandler); ok {
+			handler.SubscriptionDeleted(sub.definition)
+		}
+	}
 	// Delete the offsets, as the durable subscriptions are gone
 	err := sm.database.DeleteOffset(sm.ctx, core.OffsetTypeSubscription, id.String())
 	if err != nil {
@@ -272,8 +282,21 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		return nil, err
 	}
 
-	if subDef.Options.TLSConfigName != "" && sm.namespace.TLSConfigs[subDef.Options.TLSConfigName] != nil {
-		subDef.Options.TLSConfig = sm.namespace.TLSConfigs[subDef.Options.TLSConfigName]
+	if subDef.Options.TLSConfigName != "" {
+		// The TLS config can supply a client certificate, for mutual TLS with the receiver
+		tlsConfig := sm.namespace.TLSConfigs[subDef.Options.TLSConfigName]
+		if tlsConfig == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownTLSConfig, subDef.Options.TLSConfigName)
+		}
+		subDef.Options.TLSConfig = tlsConfig
+	}
+
+	if subDef.Options.Signing != nil && subDef.Options.Signing.SecretName != "" {
+		secret := sm.namespace.SigningSecrets[subDef.Options.Signing.SecretName]
+		if secret == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownSigningSecret, subDef.Options.Signing.SecretName)
+		}
+		subDef.Options.SigningSecret = secret
 	}
 
 	// Defaults that only apply in batch mode
@@ -292,6 +315,10 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		return nil, err
 	}
 
+	if err := validateEnrichers(ctx, &subDef.Options); err != nil {
+		return nil, err
+	}
+
 	var eventFilter *regexp.Regexp
 	if filter.Events != "" {
 		eventFilter, err = regexp.Compile(filter.Events)
@@ -483,7 +510,7 @@ func (sm *subscriptionManager) matchSubToConnLocked(conn *connection, sub *subsc
 	}
 	if conn.transport == sub.definition.Transport && conn.matcher(sub.definition.SubscriptionRef) {
 		if _, ok := conn.dispatchers[*sub.definition.ID]; !ok {
-			dispatcher := newEventDispatcher(sm.ctx, sm.enricher, conn.ei, sm.database, sm.data, sm.broadcast, sm.messaging, conn.id, sub, sm.eventNotifier, sm.txHelper)
+			dispatcher := newEventDispatcher(sm.ctx, sm.enricher, conn.ei, sm.database, sm.data, sm.broadcast, sm.messaging, conn.id, sub, sm.eventNotifier, sm.txHelper, sm.deliveryErrors)
 			conn.dispatchers[*sub.definition.ID] = dispatcher
 			dispatcher.start()
 		}
@@ -520,7 +547,7 @@ func (sm *subscriptionManager) ephemeralSubscription(ei events.Plugin, connID, n
 	}
 
 	// Create the dispatcher, and start immediately
-	dispatcher := newEventDispatcher(sm.ctx, sm.enricher, ei, sm.database, sm.data, sm.broadcast, sm.messaging, connID, newSub, sm.eventNotifier, sm.txHelper)
+	dispatcher := newEventDispatcher(sm.ctx, sm.enricher, ei, sm.database, sm.data, sm.broadcast, sm.messaging, connID, newSub, sm.eventNotifier, sm.txHelper, sm.deliveryErrors)
 	dispatcher.start()
 
 	conn.dispatchers[*subID] = dispatcher
@@ -34,6 +34,9 @@ import (
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -55,7 +58,9 @@ func newTestSubManager(t *testing.T, mei *eventsmocks.Plugin) (*subscriptionMana
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
-	enricher := newEventEnricher("ns1", mdi, mdm, mom, txHelper)
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(false).Maybe()
+	enricher := newEventEnricher("ns1", mdi, mdm, mom, &identitymanagermocks.Manager{}, &networkmapmocks.Manager{}, txHelper, mmi, cache.NewUmanagedCache(ctx, 100, 5*time.Minute))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	mei.On("Name").Return("ut")
@@ -297,6 +302,39 @@ func TestCreateSubscriptionBadTransportOptions(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestCreateSubscriptionEnrichers(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Enrich: []core.SubOptsEnricher{"Author_DID", "message_data"},
+			},
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []core.SubOptsEnricher{core.SubOptsEnricherAuthorDID, core.SubOptsEnricherMessageData}, sub.definition.Options.Enrich)
+}
+
+func TestCreateSubscriptionUnknownEnricher(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Enrich: []core.SubOptsEnricher{"wrong"},
+			},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10642.*wrong", err)
+}
+
 func TestCreateSubscriptionBadEventilter(t *testing.T) {
 	mei := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -551,6 +589,70 @@ func TestCreateSubscriptionSuccessTLSConfig(t *testing.T) {
 	assert.NotNil(t, sub.definition.Options.TLSConfig)
 }
 
+func TestCreateSubscriptionUnknownTLSConfig(t *testing.T) {
+	coreconfig.Reset()
+
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	mei.On("GetFFRestyConfig", mock.Anything).Return(&ffresty.Config{})
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			WebhookSubOptions: core.WebhookSubOptions{
+				TLSConfigName: "unknown",
+			},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10624.*unknown", err)
+}
+
+func TestCreateSubscriptionSuccessSigningSecret(t *testing.T) {
+	coreconfig.Reset()
+
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	sm.namespace.SigningSecrets = map[string][]byte{
+		"mysecret": []byte("shh"),
+	}
+
+	mei.On("GetFFRestyConfig", mock.Anything).Return(&ffresty.Config{})
+	mei.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			WebhookSubOptions: core.WebhookSubOptions{
+				Signing: &core.WebhookSigningOptions{SecretName: "mysecret"},
+			},
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte("shh"), sub.definition.Options.SigningSecret)
+}
+
+func TestCreateSubscriptionUnknownSigningSecret(t *testing.T) {
+	coreconfig.Reset()
+
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	mei.On("GetFFRestyConfig", mock.Anything).Return(&ffresty.Config{})
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			WebhookSubOptions: core.WebhookSubOptions{
+				Signing: &core.WebhookSigningOptions{SecretName: "unknown"},
+			},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10625.*unknown", err)
+}
+
 func TestCreateSubscriptionSuccessBatch(t *testing.T) {
 	coreconfig.Reset()
 
@@ -907,3 +1009,31 @@ func TestDeleteDurableSubscriptionOk(t *testing.T) {
 	assert.Empty(t, sm.durableSubs)
 	<-ed.closed
 }
+
+type deletedSubscriptionTransport struct {
+	*eventsmocks.Plugin
+	*eventsmocks.DeletedSubscriptionHandler
+}
+
+func TestDeleteDurableSubscriptionNotifiesTransport(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mdh := &eventsmocks.DeletedSubscriptionHandler{}
+	sm.transports["deleting"] = &deletedSubscriptionTransport{Plugin: mei, DeletedSubscriptionHandler: mdh}
+
+	subDef := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Transport:       "deleting",
+	}
+	sm.durableSubs[*subDef.ID] = &subscription{definition: subDef}
+
+	mdi.On("DeleteOffset", mock.Anything, fftypes.FFEnum("subscription"), subDef.ID.String()).Return(nil)
+	mdh.On("SubscriptionDeleted", subDef).Return()
+	sm.deletedDurableSubscription(subDef.ID)
+
+	assert.Empty(t, sm.durableSubs)
+	mdi.AssertExpectations(t)
+	mdh.AssertExpectations(t)
+}
@@ -0,0 +1,128 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// replaySubscription rewinds the offset of a durable subscription, so events from the requested point are delivered again.
+// Active dispatchers are stopped while the offset is rewound, so they cannot commit an offset over the top of it,
+// and the new dispatchers deliver a replay marker event to connected applications before the replayed events.
+func (sm *subscriptionManager) replaySubscription(ctx context.Context, subID *fftypes.UUID, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error) {
+	namespace := sm.namespace.Name
+	subDef, err := sm.database.GetSubscriptionByID(ctx, namespace, subID)
+	if err != nil {
+		return nil, err
+	}
+	if subDef == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	offset, err := sm.calcReplayOffset(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	sm.mux.Lock()
+	sub := sm.durableSubs[*subID]
+	_, dispatchers := sm.closeDurableSubscriptionLocked(subID)
+	sm.mux.Unlock()
+	for _, dispatcher := range dispatchers {
+		dispatcher.close()
+	}
+
+	marker := &core.Event{
+		ID:        fftypes.NewUUID(),
+		Sequence:  offset,
+		Type:      core.EventTypeSubscriptionReplay,
+		Namespace: namespace,
+		Reference: subID,
+		Created:   fftypes.Now(),
+	}
+	err = sm.setSubscriptionOffset(ctx, subID, offset)
+
+	// Whether or not the offset was updated, the subscription must be restarted (unless it was
+	// updated or deleted while we were working, in which case it has been restarted already)
+	sm.mux.Lock()
+	if _, restarted := sm.durableSubs[*subID]; sub != nil && !restarted {
+		if err == nil {
+			sub.replayMarker = marker
+		}
+		sm.durableSubs[*subID] = sub
+		for _, conn := range sm.connections {
+			sm.matchSubToConnLocked(conn, sub)
+		}
+		sub.replayMarker = nil
+	}
+	sm.mux.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	log.L(ctx).Infof("Subscription %s:%s [%s] rewound to offset %d", subDef.Namespace, subDef.Name, subDef.ID, offset)
+	return &core.SubscriptionReplay{
+		Subscription: subDef.SubscriptionRef,
+		Offset:       offset,
+		Marker:       marker.ID,
+	}, nil
+}
+
+// calcReplayOffset returns the offset to set on the subscription, which is one lower than the first event to deliver
+func (sm *subscriptionManager) calcReplayOffset(ctx context.Context, input *core.SubscriptionReplayInput) (int64, error) {
+	if (input.Sequence == nil) == (input.Timestamp == nil) {
+		return -1, i18n.NewError(ctx, coremsgs.MsgSubscriptionReplayInvalidInput)
+	}
+	if input.Sequence != nil {
+		if *input.Sequence < 0 {
+			return -1, i18n.NewError(ctx, coremsgs.MsgNumberMustBeGreaterEqual, 0)
+		}
+		return *input.Sequence - 1, nil
+	}
+	fb := database.EventQueryFactory.NewFilter(ctx)
+	firstEvents, _, err := sm.database.GetEvents(ctx, sm.namespace.Name, fb.And(fb.Gte("created", input.Timestamp)).Sort("sequence").Limit(1))
+	if err != nil {
+		return -1, err
+	}
+	if len(firstEvents) > 0 {
+		return firstEvents[0].Sequence - 1, nil
+	}
+	// No events since the timestamp, so there is nothing to replay
+	return calcFirstOffset(ctx, sm.namespace.Name, sm.database, nil)
+}
+
+func (sm *subscriptionManager) setSubscriptionOffset(ctx context.Context, subID *fftypes.UUID, offset int64) error {
+	existing, err := sm.database.GetOffset(ctx, core.OffsetTypeSubscription, subID.String())
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return sm.database.UpsertOffset(ctx, &core.Offset{
+			Type:    core.OffsetTypeSubscription,
+			Name:    subID.String(),
+			Current: offset,
+		}, true)
+	}
+	u := database.OffsetQueryFactory.NewUpdate(ctx).Set("current", offset)
+	return sm.database.UpdateOffset(ctx, existing.RowID, u)
+}
@@ -0,0 +1,189 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSubscriptionReplay(t *testing.T) (*subscriptionManager, *databasemocks.Plugin, *core.Subscription, func()) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	mdi := &databasemocks.Plugin{}
+	sm.database = mdi
+	subDef := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Transport:       "ut",
+	}
+	return sm, mdi, subDef, func() {
+		cancel()
+		mdi.AssertExpectations(t)
+	}
+}
+
+func TestReplaySubscriptionSequence(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	sub := &subscription{definition: subDef}
+	sm.durableSubs[*subDef.ID] = sub
+	existing, cancelExisting := newTestEventDispatcher(sub)
+	defer cancelExisting()
+	existing.start()
+	conn := &connection{
+		id:          "conn1",
+		transport:   "ut",
+		matcher:     func(core.SubscriptionRef) bool { return true },
+		dispatchers: map[fftypes.UUID]*eventDispatcher{*subDef.ID: existing},
+		ei:          sm.transports["ut"],
+	}
+	sm.connections["conn1"] = conn
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(subDef, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subDef.ID.String()).Return(&core.Offset{RowID: 12345, Current: 100}, nil)
+	mdi.On("UpdateOffset", mock.Anything, int64(12345), mock.Anything).Return(nil)
+
+	seq := int64(10)
+	replay, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Sequence: &seq})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), replay.Offset)
+	assert.Equal(t, *subDef.ID, *replay.Subscription.ID)
+
+	dispatcher := conn.dispatchers[*subDef.ID]
+	assert.NotEqual(t, existing, dispatcher)
+	assert.Equal(t, replay.Marker, dispatcher.replayMarker.ID)
+	assert.Equal(t, core.EventTypeSubscriptionReplay, dispatcher.replayMarker.Type)
+	assert.Equal(t, int64(9), dispatcher.replayMarker.Sequence)
+	assert.Nil(t, sub.replayMarker)
+	assert.Equal(t, sub, sm.durableSubs[*subDef.ID])
+}
+
+func TestReplaySubscriptionTimestamp(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(subDef, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 20}}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subDef.ID.String()).Return(nil, nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(o *core.Offset) bool {
+		return o.Current == 19 && o.Name == subDef.ID.String()
+	}), true).Return(nil)
+
+	replay, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Timestamp: fftypes.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(19), replay.Offset)
+	assert.Empty(t, sm.durableSubs)
+}
+
+func TestReplaySubscriptionTimestampNoEvents(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(subDef, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil).Once()
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 30}}, nil, nil).Once()
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subDef.ID.String()).Return(nil, nil)
+	mdi.On("UpsertOffset", mock.Anything, mock.Anything, true).Return(nil)
+
+	replay, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Timestamp: fftypes.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), replay.Offset)
+}
+
+func TestReplaySubscriptionTimestampFail(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(subDef, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Timestamp: fftypes.Now()})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestReplaySubscriptionBadInput(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(subDef, nil)
+
+	_, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{})
+	assert.Regexp(t, "FF10576", err)
+
+	seq := int64(10)
+	_, err = sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Sequence: &seq, Timestamp: fftypes.Now()})
+	assert.Regexp(t, "FF10576", err)
+
+	seq = -1
+	_, err = sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Sequence: &seq})
+	assert.Regexp(t, "FF10192", err)
+}
+
+func TestReplaySubscriptionNotFound(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(nil, nil)
+
+	_, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReplaySubscriptionGetSubscriptionFail(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestReplaySubscriptionGetOffsetFail(t *testing.T) {
+	sm, mdi, subDef, done := newTestSubscriptionReplay(t)
+	defer done()
+
+	sub := &subscription{definition: subDef}
+	sm.durableSubs[*subDef.ID] = sub
+	conn := &connection{
+		id:          "conn1",
+		transport:   "ut",
+		matcher:     func(core.SubscriptionRef) bool { return true },
+		dispatchers: map[fftypes.UUID]*eventDispatcher{},
+		ei:          sm.transports["ut"],
+	}
+	sm.connections["conn1"] = conn
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subDef.ID).Return(subDef, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subDef.ID.String()).Return(nil, fmt.Errorf("pop"))
+
+	seq := int64(10)
+	_, err := sm.replaySubscription(sm.ctx, subDef.ID, &core.SubscriptionReplayInput{Sequence: &seq})
+	assert.Regexp(t, "pop", err)
+
+	// The subscription is restarted, without a replay marker
+	assert.Equal(t, sub, sm.durableSubs[*subDef.ID])
+	assert.Nil(t, conn.dispatchers[*subDef.ID].replayMarker)
+}
@@ -19,11 +19,18 @@ package webhooks
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -35,10 +42,13 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/events"
 )
 
+const defaultSignatureHeader = "X-FireFly-Signature"
+
 type WebHooks struct {
 	ctx           context.Context
 	capabilities  *events.Capabilities
@@ -46,6 +56,7 @@ type WebHooks struct {
 	client        *resty.Client
 	connID        string
 	ffrestyConfig *ffresty.Config
+	metrics       metrics.Manager // optional
 }
 
 type callbacks struct {
@@ -101,6 +112,10 @@ func (wh *WebHooks) Init(ctx context.Context, config config.Section) (err error)
 	return nil
 }
 
+func (wh *WebHooks) SetMetrics(metrics metrics.Manager) {
+	wh.metrics = metrics
+}
+
 func (wh *WebHooks) SetHandler(namespace string, handler events.Callbacks) error {
 	wh.callbacks.writeLock.Lock()
 	defer wh.callbacks.writeLock.Unlock()
@@ -274,6 +289,11 @@ func (wh *WebHooks) ValidateOptions(ctx context.Context, options *core.Subscript
 		// Take a copy of the webhooks global resty config
 		newFFRestyConfig = *wh.ffrestyConfig
 	}
+	if options.Retry.DeadLetter && options.Fastack {
+		return i18n.NewError(ctx, coremsgs.MsgWebhookDeadLetterFastAck)
+	}
+
+	factor := 2.0
 	if options.Retry.Enabled {
 		newFFRestyConfig.Retry = true
 		if options.Retry.Count > 0 {
@@ -295,6 +315,17 @@ func (wh *WebHooks) ValidateOptions(ctx context.Context, options *core.Subscript
 			}
 			newFFRestyConfig.RetryMaximumDelay = fftypes.FFDuration(time.Duration(ffd))
 		}
+
+		if options.Retry.Factor != 0 {
+			if options.Retry.Factor < 1 {
+				return i18n.NewError(ctx, coremsgs.MsgWebhookInvalidRetryFactor, options.Retry.Factor)
+			}
+			factor = options.Retry.Factor
+		}
+
+		if options.Retry.Jitter < 0 || options.Retry.Jitter > 1 {
+			return i18n.NewError(ctx, coremsgs.MsgWebhookInvalidRetryJitter, options.Retry.Jitter)
+		}
 	}
 
 	if options.HTTPOptions.HTTPMaxIdleConns > 0 {
@@ -349,15 +380,65 @@ func (wh *WebHooks) ValidateOptions(ctx context.Context, options *core.Subscript
 		newFFRestyConfig.TLSClientConfig = options.TLSConfig
 	}
 
+	if options.Signing != nil {
+		if options.SigningSecret == nil {
+			return i18n.NewError(ctx, coremsgs.MsgWebhookSigningSecretRequired)
+		}
+		if options.Signing.Header == "" {
+			options.Signing.Header = defaultSignatureHeader
+		}
+	}
+
 	// NOTE: this is the plugin context, as the context passed through can be terminated as part of a
 	// API call or anything else and we want to use this client later on!!
 	// So these clients should live as long as the plugin exists
 	options.RestyClient = ffresty.NewWithConfig(wh.ctx, newFFRestyConfig)
+	if options.Retry.Enabled {
+		options.RestyClient.SetRetryAfter(retryBackoff(time.Duration(newFFRestyConfig.RetryInitialDelay), time.Duration(newFFRestyConfig.RetryMaximumDelay), factor, options.Retry.Jitter))
+	}
 
 	_, err := wh.buildRequest(ctx, options.RestyClient, options.TransportOptions(), nil)
 	return err
 }
 
+// signRequest sets a signature header of the form "t=<timestamp>,v1=<signature>", where the signature is the
+// hex HMAC-SHA256 of the unix timestamp, a '.' and the exact body sent. The receiver recomputes the signature
+// with the shared secret to verify the request came from this node, and checks the timestamp to reject replays.
+func signRequest(req *whRequest, header string, secret []byte, now time.Time) {
+	var body []byte
+	switch b := req.r.Body.(type) {
+	case nil:
+	case string:
+		// resty sends strings (such as a field selected with input.body) and bytes as they are
+		body = []byte(b)
+	case []byte:
+		body = b
+	default:
+		// Anything else resty would serialize as JSON, so do that here to sign the exact bytes sent
+		body, _ = json.Marshal(b) // the body is built from JSON data
+		req.r.SetBody(body)
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	req.r.SetHeader(header, fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil))))
+}
+
+// retryBackoff multiplies the delay between attempts by the factor after each failure, up to the maximum,
+// then takes a random amount off each delay up to the jitter fraction of it
+func retryBackoff(initialDelay, maxDelay time.Duration, factor, jitter float64) resty.RetryAfterFunc {
+	return func(_ *resty.Client, res *resty.Response) (time.Duration, error) {
+		delay := float64(initialDelay) * math.Pow(factor, float64(res.Request.Attempt-1))
+		if maxDelay > 0 && delay > float64(maxDelay) {
+			delay = float64(maxDelay)
+		}
+		//nolint:gosec // jitter does not need a secure random source
+		delay -= delay * jitter * rand.Float64()
+		return time.Duration(delay), nil
+	}
+}
+
 func (wh *WebHooks) attemptRequest(ctx context.Context, sub *core.Subscription, events []*core.CombinedEventDataDelivery, batch bool) (req *whRequest, res *whResponse, err error) {
 
 	var payloadForBuildingRequest *whPayload // only set for a single event delivery
@@ -390,6 +471,9 @@ func (wh *WebHooks) attemptRequest(ctx context.Context, sub *core.Subscription,
 	if req.method == http.MethodPost || req.method == http.MethodPatch || req.method == http.MethodPut {
 		req.r.SetBody(requestBody)
 	}
+	if sub.Options.Signing != nil && sub.Options.SigningSecret != nil {
+		signRequest(req, sub.Options.Signing.Header, sub.Options.SigningSecret, time.Now())
+	}
 
 	resp, err := req.r.Execute(req.method, req.url)
 	if err != nil {
@@ -435,8 +519,71 @@ func (wh *WebHooks) attemptRequest(ctx context.Context, sub *core.Subscription,
 	return req, res, nil
 }
 
+// deliveryError returns an error if the webhook could not be invoked, or did not return a 2xx status
+func deliveryError(ctx context.Context, res *whResponse, gwErr error) error {
+	if gwErr != nil {
+		return gwErr
+	}
+	if res.Status < 200 || res.Status >= 300 {
+		return i18n.NewError(ctx, coremsgs.MsgWebhookFailedStatus, res.Status)
+	}
+	return nil
+}
+
+func (wh *WebHooks) buildReply(sub *core.Subscription, req *whRequest, event *core.EventDelivery, body []byte) *core.MessageInOut {
+	txType := fftypes.FFEnum(strings.ToLower(sub.Options.TransportOptions().GetString("replytx")))
+	if req != nil && req.replyTx != "" {
+		txType = fftypes.FFEnum(strings.ToLower(req.replyTx))
+	}
+	return &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				CID:    event.Message.Header.ID,
+				Group:  event.Message.Header.Group,
+				Type:   event.Message.Header.Type,
+				Topics: event.Message.Header.Topics,
+				Tag:    sub.Options.TransportOptions().GetString("replytag"),
+				TxType: txType,
+			},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtrBytes(body)},
+		},
+	}
+}
+
+// recordResponse counts the status code of a webhook response, or "error" if the webhook could not be invoked
+func (wh *WebHooks) recordResponse(sub *core.Subscription, res *whResponse) {
+	if wh.metrics == nil || !wh.metrics.IsMetricsEnabled() {
+		return
+	}
+	status := "error"
+	if res != nil {
+		status = strconv.Itoa(res.Status)
+	}
+	wh.metrics.WebhookResponse(sub.Namespace, sub.Name, status)
+}
+
 func (wh *WebHooks) doDelivery(ctx context.Context, connID string, reply bool, sub *core.Subscription, events []*core.CombinedEventDataDelivery, fastAck, batched bool) {
 	req, res, gwErr := wh.attemptRequest(ctx, sub, events, batched)
+	wh.recordResponse(sub, res)
+	if sub.Options.Retry.DeadLetter && !fastAck {
+		if err := deliveryError(ctx, res, gwErr); err != nil {
+			// Park the events for later replay, rather than acknowledging them with an error reply
+			log.L(wh.ctx).Errorf("Webhook delivery failed on subscription %s - moving %d events to dead-letter queue: %s", sub.ID, len(events), err)
+			if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
+				for _, combinedEvent := range events {
+					cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
+						ID:           combinedEvent.Event.ID,
+						DeadLetter:   true,
+						Info:         err.Error(),
+						Subscription: combinedEvent.Event.Subscription,
+					})
+				}
+			}
+			return
+		}
+	}
 	if gwErr != nil {
 		// Generate a bad-gateway error response - we always want to send something back,
 		// rather than just causing timeouts
@@ -460,31 +607,13 @@ func (wh *WebHooks) doDelivery(ctx context.Context, connID string, reply bool, s
 		event := combinedEvent.Event
 		// Emit the response
 		if reply && event.Message != nil {
-			txType := fftypes.FFEnum(strings.ToLower(sub.Options.TransportOptions().GetString("replytx")))
-			if req != nil && req.replyTx != "" {
-				txType = fftypes.FFEnum(strings.ToLower(req.replyTx))
-			}
 			if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
 				log.L(wh.ctx).Debugf("Sending reply message for %s CID=%s", event.ID, event.Message.Header.ID)
 				cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
 					ID:           event.ID,
 					Rejected:     false,
 					Subscription: event.Subscription,
-					Reply: &core.MessageInOut{
-						Message: core.Message{
-							Header: core.MessageHeader{
-								CID:    event.Message.Header.ID,
-								Group:  event.Message.Header.Group,
-								Type:   event.Message.Header.Type,
-								Topics: event.Message.Header.Topics,
-								Tag:    sub.Options.TransportOptions().GetString("replytag"),
-								TxType: txType,
-							},
-						},
-						InlineData: core.InlineData{
-							{Value: fftypes.JSONAnyPtrBytes(b)},
-						},
-					},
+					Reply:        wh.buildReply(sub, req, event, b),
 				})
 			}
 		} else if !fastAck {
@@ -588,6 +717,21 @@ func (wh *WebHooks) BatchDeliveryRequest(ctx context.Context, connID string, sub
 	return nil
 }
 
+// ReplayDelivery makes a single synchronous attempt (with any configured retries) to deliver an
+// event that was previously moved to the dead-letter queue, returning the reply to send if any
+func (wh *WebHooks) ReplayDelivery(ctx context.Context, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) (*core.MessageInOut, error) {
+	req, res, gwErr := wh.attemptRequest(ctx, sub, []*core.CombinedEventDataDelivery{{Event: event, Data: data}}, false)
+	wh.recordResponse(sub, res)
+	if err := deliveryError(ctx, res, gwErr); err != nil {
+		return nil, err
+	}
+	if sub.Options.TransportOptions().GetBool("reply") && event.Message != nil && event.Message.Header.CID == nil {
+		b, _ := json.Marshal(&res)
+		return wh.buildReply(sub, req, event, b), nil
+	}
+	return nil, nil
+}
+
 func (wh *WebHooks) NamespaceRestarted(ns string, startTime time.Time) {
 	// no-op
 }
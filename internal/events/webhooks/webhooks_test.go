@@ -17,24 +17,31 @@
 package webhooks
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/gorilla/mux"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
@@ -42,6 +49,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/events"
 	"github.com/stretchr/testify/assert"
@@ -175,6 +183,84 @@ func TestValidateOptionsBadMaxDelayDuration(t *testing.T) {
 	assert.Regexp(t, "FF00137", err)
 }
 
+func TestValidateOptionsDeadLetterFastAck(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.Fastack = true
+	opts.Retry = core.WebhookRetryOptions{
+		DeadLetter: true,
+	}
+	err := wh.ValidateOptions(wh.ctx, opts)
+	assert.Regexp(t, "FF10532", err)
+}
+
+func TestValidateOptionsSigningNoSecret(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/some/path"
+	opts.Signing = &core.WebhookSigningOptions{SecretName: "secret1"}
+	err := wh.ValidateOptions(wh.ctx, opts)
+	assert.Regexp(t, "FF10628", err)
+}
+
+func TestValidateOptionsSigningDefaultHeader(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/some/path"
+	opts.Signing = &core.WebhookSigningOptions{SecretName: "secret1"}
+	opts.SigningSecret = []byte("shh")
+	err := wh.ValidateOptions(wh.ctx, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "X-FireFly-Signature", opts.Signing.Header)
+}
+
+func TestValidateOptionsBadRetryFactor(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.Retry = core.WebhookRetryOptions{
+		Enabled: true,
+		Factor:  0.5,
+	}
+	err := wh.ValidateOptions(wh.ctx, opts)
+	assert.Regexp(t, "FF10533", err)
+}
+
+func TestValidateOptionsBadRetryJitter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.Retry = core.WebhookRetryOptions{
+		Enabled: true,
+		Jitter:  1.5,
+	}
+	err := wh.ValidateOptions(wh.ctx, opts)
+	assert.Regexp(t, "FF10534", err)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	backoff := retryBackoff(1*time.Second, 5*time.Second, 2, 0)
+	for attempt, expected := range []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		delay, err := backoff(nil, &resty.Response{Request: &resty.Request{Attempt: attempt + 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, delay)
+	}
+
+	backoff = retryBackoff(1*time.Second, 0, 3, 0.5)
+	delay, err := backoff(nil, &resty.Response{Request: &resty.Request{Attempt: 3}})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, delay, 9*time.Second)
+	assert.GreaterOrEqual(t, delay, 4500*time.Millisecond)
+}
+
 func TestValidateOptionsBadHTTPRequestTimeout(t *testing.T) {
 	wh, cancel := newTestWebHooks(t)
 	defer cancel()
@@ -1474,3 +1560,331 @@ func TestRequestWithBodyReplyEndToEndWithBatch(t *testing.T) {
 func TestFirstDataNeverNil(t *testing.T) {
 	assert.NotNil(t, (&whPayload{}).firstData())
 }
+
+func newTestDeadLetterSub(t *testing.T, wh *WebHooks, url string) *core.Subscription {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	sub.Options.TransportOptions()["url"] = url
+	sub.Options.Retry = core.WebhookRetryOptions{
+		Enabled:      true,
+		Count:        2,
+		InitialDelay: "1ms",
+		MaximumDelay: "10ms",
+		Factor:       1.5,
+		Jitter:       0.1,
+		DeadLetter:   true,
+	}
+	err := wh.ValidateOptions(wh.ctx, &sub.Options)
+	assert.NoError(t, err)
+	return sub
+}
+
+func newTestDeadLetterEvent(sub *core.Subscription) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID: fftypes.NewUUID(),
+			},
+			Message: &core.Message{
+				Header: core.MessageHeader{
+					ID:   fftypes.NewUUID(),
+					Type: core.MessageTypeBroadcast,
+				},
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+}
+
+func TestDeliveryRequestDeadLetterAfterRetries(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.WriteHeader(503)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	event := newTestDeadLetterEvent(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.DeadLetter &&
+			!response.Rejected &&
+			response.ID.Equals(event.ID) &&
+			response.Subscription.ID.Equals(sub.ID)
+	})).Return(nil).Run(func(a mock.Arguments) {
+		assert.Regexp(t, "FF10531.*503", a[1].(*core.EventDeliveryResponse).Info)
+	})
+
+	err := wh.DeliveryRequest(wh.ctx, mock.Anything, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRequestDeadLetterConnectionFail(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(mux.NewRouter())
+	server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	event1 := newTestDeadLetterEvent(sub)
+	event2 := newTestDeadLetterEvent(sub)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.DeadLetter && response.Info != ""
+	})).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(wh.ctx, mock.Anything, sub, []*core.CombinedEventDataDelivery{
+		{Event: event1},
+		{Event: event2},
+	})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestReplayDeliveryWithReply(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(200)
+		res.Write([]byte(`{"replied": true}`))
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	sub.Options.TransportOptions()["reply"] = true
+	sub.Options.TransportOptions()["replytag"] = "myreply"
+	event := newTestDeadLetterEvent(sub)
+
+	reply, err := wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Equal(t, *event.Message.Header.ID, *reply.Header.CID)
+	assert.Equal(t, "myreply", reply.Header.Tag)
+	var body fftypes.JSONObject
+	err = json.Unmarshal(reply.InlineData[0].Value.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(200), body["status"])
+	assert.Equal(t, true, body.GetObject("body")["replied"])
+}
+
+func TestReplayDeliveryNoReply(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(204)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	event := newTestDeadLetterEvent(sub)
+
+	reply, err := wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.Nil(t, reply)
+}
+
+func TestReplayDeliveryFail(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(404)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	event := newTestDeadLetterEvent(sub)
+
+	_, err := wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.Regexp(t, "FF10531.*404", err)
+}
+
+func TestReplayDeliveryRecordsResponseMetrics(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(404)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	event := newTestDeadLetterEvent(sub)
+
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("WebhookResponse", sub.Namespace, sub.Name, "404").Return()
+	wh.SetMetrics(mmi)
+
+	_, err := wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.Regexp(t, "FF10531.*404", err)
+
+	mmi.AssertExpectations(t)
+}
+
+func TestReplayDeliveryRecordsErrorMetrics(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	sub := newTestDeadLetterSub(t, wh, "http://localhost:0/myapi")
+	event := newTestDeadLetterEvent(sub)
+
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("WebhookResponse", sub.Namespace, sub.Name, "error").Return()
+	wh.SetMetrics(mmi)
+
+	_, err := wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.Error(t, err)
+
+	mmi.AssertExpectations(t)
+}
+
+func checkTestSignature(t *testing.T, req *http.Request, header string, secret []byte) {
+	var timestamp, signature string
+	_, err := fmt.Sscanf(strings.Replace(req.Header.Get(header), ",", " ", 1), "t=%s v1=%s", &timestamp, &signature)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signature)
+}
+
+func TestReplayDeliverySigned(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	secret := []byte("shh")
+	signed := false
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		checkTestSignature(t, req, "X-FireFly-Signature", secret)
+		signed = true
+		res.WriteHeader(204)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	sub.Options.Signing = &core.WebhookSigningOptions{SecretName: "secret1"}
+	sub.Options.SigningSecret = secret
+	err := wh.ValidateOptions(wh.ctx, &sub.Options)
+	assert.NoError(t, err)
+	event := newTestDeadLetterEvent(sub)
+
+	_, err = wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.True(t, signed)
+}
+
+func TestReplayDeliverySignedNoBody(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	secret := []byte("shh")
+	signed := false
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		checkTestSignature(t, req, "X-Custom-Sig", secret)
+		signed = true
+		res.WriteHeader(204)
+	}).Methods(http.MethodGet)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	sub.Options.TransportOptions()["method"] = http.MethodGet
+	sub.Options.Signing = &core.WebhookSigningOptions{SecretName: "secret1", Header: "X-Custom-Sig"}
+	sub.Options.SigningSecret = secret
+	err := wh.ValidateOptions(wh.ctx, &sub.Options)
+	assert.NoError(t, err)
+	event := newTestDeadLetterEvent(sub)
+
+	_, err = wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{})
+	assert.NoError(t, err)
+	assert.True(t, signed)
+}
+
+func TestReplayDeliverySignedInputBody(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	secret := []byte("shh")
+	signed := false
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"a":"b"}`, string(body))
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		checkTestSignature(t, req, "X-FireFly-Signature", secret)
+		signed = true
+		res.WriteHeader(204)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub := newTestDeadLetterSub(t, wh, fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	sub.Options.TransportOptions()["input"] = map[string]interface{}{
+		"body": "in_body",
+	}
+	sub.Options.Signing = &core.WebhookSigningOptions{SecretName: "secret1"}
+	sub.Options.SigningSecret = secret
+	err := wh.ValidateOptions(wh.ctx, &sub.Options)
+	assert.NoError(t, err)
+	event := newTestDeadLetterEvent(sub)
+
+	_, err = wh.ReplayDelivery(wh.ctx, sub, event, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"in_body":{"a":"b"}}`)},
+	})
+	assert.NoError(t, err)
+	assert.True(t, signed)
+}
+
+func TestSignRequestBytes(t *testing.T) {
+	req := &whRequest{r: resty.New().R()}
+	req.r.SetBody([]byte("raw"))
+	signRequest(req, "X-Sig", []byte("shh"), time.Unix(1000, 0))
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write([]byte("1000.raw"))
+	assert.Equal(t, "t=1000,v1="+hex.EncodeToString(mac.Sum(nil)), req.r.Header.Get("X-Sig"))
+	assert.Equal(t, []byte("raw"), req.r.Body)
+}
+
+func TestSignRequestJSONKeepsContentType(t *testing.T) {
+	req := &whRequest{r: resty.New().R()}
+	req.r.SetHeader("Content-Type", "application/vnd.custom+json")
+	req.r.SetBody(map[string]interface{}{"a": "b"})
+	signRequest(req, "X-Sig", []byte("shh"), time.Unix(1000, 0))
+	assert.Equal(t, []byte(`{"a":"b"}`), req.r.Body)
+	assert.Equal(t, "application/vnd.custom+json", req.r.Header.Get("Content-Type"))
+}
@@ -19,7 +19,9 @@ package websockets
 import "github.com/hyperledger/firefly-common/pkg/config"
 
 const (
-	bufferSizeDefault = "16Kb"
+	bufferSizeDefault     = "16Kb"
+	maxMessageSizeDefault = "0"
+	oversizeModeDefault   = OversizeModeReference
 )
 
 const (
@@ -27,9 +29,22 @@ const (
 	ReadBufferSize = "readBufferSize"
 	// WriteBufferSize is the write buffer size for the socket
 	WriteBufferSize = "writeBufferSize"
+	// MaxMessageSize is the largest message that will be written to a client in a single frame (0 for unlimited)
+	MaxMessageSize = "maxMessageSize"
+	// OversizeMode is the default handling for messages larger than MaxMessageSize
+	OversizeMode = "oversizeMode"
+)
+
+const (
+	// OversizeModeReference replaces oversized event payloads with a reference the client fetches via REST
+	OversizeModeReference = "reference"
+	// OversizeModeFragment splits oversized messages into fragments the client must reassemble
+	OversizeModeFragment = "fragment"
 )
 
 func (ws *WebSockets) InitConfig(config config.Section) {
 	config.AddKnownKey(ReadBufferSize, bufferSizeDefault)
 	config.AddKnownKey(WriteBufferSize, bufferSizeDefault)
+	config.AddKnownKey(MaxMessageSize, maxMessageSizeDefault)
+	config.AddKnownKey(OversizeMode, oversizeModeDefault)
 }
@@ -60,8 +60,16 @@ type websocketConnection struct {
 	auth            core.Authorizer
 	namespaceScoped bool // if true then any request to listen is asserted to be in the context of namespace
 	namespace       string
+	maxMsgSize      int64
+	oversizeMode    string
 }
 
+// fragmentOverhead is the space reserved in each fragment for the JSON envelope around the data
+const fragmentOverhead = 256
+
+// minMaxMessageSize is the smallest non-zero max message size, leaving room for data in each fragment
+const minMaxMessageSize = 2 * fragmentOverhead
+
 func newConnection(pCtx context.Context, ws *WebSockets, wsConn *websocket.Conn, req *http.Request, auth core.Authorizer) *websocketConnection {
 	connID := fftypes.NewUUID().String()
 	ctx := log.WithLogField(pCtx, "websocket", connID)
@@ -79,6 +87,12 @@ func newConnection(pCtx context.Context, ws *WebSockets, wsConn *websocket.Conn,
 		userAgent:    req.UserAgent(),
 		header:       req.Header,
 		auth:         auth,
+		maxMsgSize:   ws.maxMsgSize,
+		oversizeMode: ws.oversizeMode,
+	}
+	// Clients can choose how they would like oversized messages to be delivered
+	if oversizeMode := req.URL.Query().Get("oversize"); isValidOversizeMode(oversizeMode) {
+		wc.oversizeMode = oversizeMode
 	}
 	go wc.sendLoop()
 	go wc.receiveLoop()
@@ -104,6 +118,10 @@ func isBoolQuerySet(query url.Values, boolOption string) bool {
 
 func (wc *websocketConnection) getReadAhead(query url.Values, isBatch bool) *uint16 {
 	readaheadStr := query.Get("readahead")
+	if isBatch && query.Get("batchsize") != "" {
+		// In batch mode the readahead is the maximum number of events in each batch frame
+		readaheadStr = query.Get("batchsize")
+	}
 	if readaheadStr != "" {
 		readAheadInt, err := strconv.ParseUint(readaheadStr, 10, 16)
 		if err == nil {
@@ -165,12 +183,7 @@ func (wc *websocketConnection) sendLoop() {
 		select {
 		case msg := <-wc.sendMessages:
 			l.Tracef("Sending: %+v", msg)
-			writer, err := wc.wsConn.NextWriter(websocket.TextMessage)
-			if err == nil {
-				err = json.NewEncoder(writer).Encode(msg)
-				_ = writer.Close()
-			}
-			if err != nil {
+			if err := wc.writeMessage(msg); err != nil {
 				l.Errorf("Write failed on socket: %s", err)
 				return
 			}
@@ -184,6 +197,86 @@ func (wc *websocketConnection) sendLoop() {
 	}
 }
 
+func (wc *websocketConnection) writeMessage(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if wc.maxMsgSize > 0 && int64(len(data)) > wc.maxMsgSize {
+		return wc.writeOversize(msg, data)
+	}
+	return wc.writeFrame(data)
+}
+
+func (wc *websocketConnection) writeFrame(data []byte) error {
+	return wc.wsConn.WriteMessage(websocket.TextMessage, append(data, '\n'))
+}
+
+func (wc *websocketConnection) writeOversize(msg interface{}, data []byte) error {
+	if wc.oversizeMode == OversizeModeReference {
+		if ref := eventReferenceFor(msg, int64(len(data))); ref != nil {
+			refData, _ := json.Marshal(ref)
+			if int64(len(refData)) <= wc.maxMsgSize {
+				log.L(wc.ctx).Debugf("Sending reference in place of message of size %d", len(data))
+				return wc.writeFrame(refData)
+			}
+		}
+	}
+	// Anything we cannot send by reference is sent in fragments
+	return wc.writeFragments(data)
+}
+
+func (wc *websocketConnection) writeFragments(data []byte) error {
+	// Data is base64 encoded in each fragment, so allow for that expansion
+	chunkSize := int((wc.maxMsgSize - fragmentOverhead) * 3 / 4)
+	fragment := &core.WSMessageFragment{
+		Type:  core.WSMessageFragmentType,
+		ID:    fftypes.NewUUID(),
+		Count: (len(data) + chunkSize - 1) / chunkSize,
+	}
+	log.L(wc.ctx).Debugf("Sending message of size %d in %d fragments id=%s", len(data), fragment.Count, fragment.ID)
+	for fragment.Index = 0; fragment.Index < fragment.Count; fragment.Index++ {
+		start := fragment.Index * chunkSize
+		end := start + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		fragment.Data = data[start:end]
+		fragmentData, _ := json.Marshal(fragment)
+		if err := wc.writeFrame(fragmentData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventReferenceFor strips the enriched data from events, so the client can retrieve them via the REST API
+func eventReferenceFor(msg interface{}, size int64) interface{} {
+	switch m := msg.(type) {
+	case *core.EventDelivery:
+		event := m.Event
+		return &core.WSEventReference{
+			Type:         core.WSEventReferenceType,
+			Event:        &event,
+			Subscription: m.Subscription,
+			Size:         size,
+		}
+	case *core.WSEventBatch:
+		batch := *m
+		batch.References = true
+		batch.Events = make([]*core.EventDelivery, len(m.Events))
+		for i, e := range m.Events {
+			batch.Events[i] = &core.EventDelivery{
+				EnrichedEvent: core.EnrichedEvent{Event: e.Event},
+				Subscription:  e.Subscription,
+			}
+		}
+		return &batch
+	default:
+		return nil
+	}
+}
+
 func (wc *websocketConnection) receiveLoop() {
 	l := log.L(wc.ctx)
 	defer close(wc.receiverDone)
@@ -507,7 +600,7 @@ func (wc *websocketConnection) authorizeMessage(ns string) error {
 		Header:    wc.header,
 	}
 	if wc.auth != nil {
-		if err := wc.auth.Authorize(wc.ctx, authReq); err != nil {
+		if err := wc.auth.Authorize(core.WithRequiredAPIRole(wc.ctx, core.APIRoleReader), authReq); err != nil {
 			return err
 		}
 	}
@@ -43,6 +43,8 @@ type WebSockets struct {
 	connMux      sync.Mutex
 	upgrader     websocket.Upgrader
 	auth         core.Authorizer
+	maxMsgSize   int64
+	oversizeMode string
 }
 
 type callbacks struct {
@@ -53,6 +55,15 @@ type callbacks struct {
 func (ws *WebSockets) Name() string { return "websockets" }
 
 func (ws *WebSockets) Init(ctx context.Context, config config.Section) error {
+	oversizeMode := config.GetString(OversizeMode)
+	if !isValidOversizeMode(oversizeMode) {
+		return i18n.NewError(ctx, coremsgs.MsgWSInvalidOversizeMode, oversizeMode)
+	}
+	// Fragments must have room for some data after the envelope
+	maxMsgSize := config.GetByteSize(MaxMessageSize)
+	if maxMsgSize != 0 && maxMsgSize < minMaxMessageSize {
+		return i18n.NewError(ctx, coremsgs.MsgWSInvalidMaxMessageSize, maxMsgSize, minMaxMessageSize)
+	}
 	*ws = WebSockets{
 		ctx:         ctx,
 		connections: make(map[string]*websocketConnection),
@@ -70,10 +81,16 @@ func (ws *WebSockets) Init(ctx context.Context, config config.Section) error {
 				return true
 			},
 		},
+		maxMsgSize:   maxMsgSize,
+		oversizeMode: oversizeMode,
 	}
 	return nil
 }
 
+func isValidOversizeMode(mode string) bool {
+	return mode == OversizeModeReference || mode == OversizeModeFragment
+}
+
 func (ws *WebSockets) SetAuthorizer(auth core.Authorizer) {
 	ws.auth = auth
 }
@@ -222,10 +239,12 @@ func (ws *WebSockets) GetStatus() *core.WebSocketStatus {
 	for _, wc := range connections {
 		wc.mux.Lock()
 		conn := &core.WSConnectionStatus{
-			ID:            wc.connID,
-			RemoteAddress: wc.remoteAddr,
-			UserAgent:     wc.userAgent,
-			Subscriptions: make([]*core.WSSubscriptionStatus, 0),
+			ID:             wc.connID,
+			RemoteAddress:  wc.remoteAddr,
+			UserAgent:      wc.userAgent,
+			MaxMessageSize: wc.maxMsgSize,
+			OversizeMode:   wc.oversizeMode,
+			Subscriptions:  make([]*core.WSSubscriptionStatus, 0),
 		}
 		status.Connections = append(status.Connections, conn)
 		for _, s := range wc.started {
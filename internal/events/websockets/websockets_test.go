@@ -703,6 +703,28 @@ func TestAutoStartCustomReadAheadBatch(t *testing.T) {
 
 }
 
+func TestAutoStartBatchSize(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	subscribedConn := make(chan string, 1)
+	cbs.On("EphemeralSubscription",
+		mock.MatchedBy(func(s string) bool {
+			subscribedConn <- s
+			return true
+		}),
+		"ns1",
+		mock.Anything,
+		mock.MatchedBy(func(o *core.SubscriptionOptions) bool {
+			return *o.Batch && *o.ReadAhead == 500
+		}),
+	).Return(nil)
+
+	_, _, cancel := newTestWebsockets(t, cbs, nil, "namespace=ns1", "ephemeral", "batch", "batchsize=500", "readahead=42")
+	defer cancel()
+
+	<-subscribedConn
+
+}
+
 func TestAutoStartBadNamespace(t *testing.T) {
 	cbs := &eventsmocks.Callbacks{}
 	_, wsc, cancel := newTestWebsockets(t, cbs, nil, "ephemeral", "namespace=ns2")
@@ -879,6 +901,185 @@ func TestSendLoopBadData(t *testing.T) {
 
 }
 
+func startOversizeTestConnection(t *testing.T, cbs *eventsmocks.Callbacks, ws *WebSockets, wsc wsclient.WSClient) *websocketConnection {
+	subscribedConn := make(chan string, 1)
+	cbs.On("EphemeralSubscription",
+		mock.MatchedBy(func(s string) bool {
+			subscribedConn <- s
+			return true
+		}),
+		"ns1", mock.Anything, mock.Anything).Return(nil)
+
+	err := wsc.Send(context.Background(), []byte(`{"type":"start","namespace":"ns1","ephemeral":true}`))
+	assert.NoError(t, err)
+
+	connID := <-subscribedConn
+	ws.connMux.Lock()
+	defer ws.connMux.Unlock()
+	return ws.connections[connID]
+}
+
+func TestInitBadOversizeMode(t *testing.T) {
+	coreconfig.Reset()
+	ws := &WebSockets{}
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.Set(OversizeMode, "wrong")
+	err := ws.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10481", err)
+}
+
+func TestInitBadMaxMessageSize(t *testing.T) {
+	coreconfig.Reset()
+	ws := &WebSockets{}
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.Set(MaxMessageSize, "255")
+	err := ws.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10647.*255", err)
+
+	svrConfig.Set(MaxMessageSize, "512")
+	err = ws.Init(context.Background(), svrConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(512), ws.maxMsgSize)
+}
+
+func TestOversizeQueryParam(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil, "oversize=fragment")
+	defer cancel()
+
+	connection := startOversizeTestConnection(t, cbs, ws, wsc)
+	assert.Equal(t, OversizeModeFragment, connection.oversizeMode)
+	assert.Equal(t, OversizeModeReference, ws.oversizeMode)
+}
+
+func TestOversizeEventReference(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	connection := startOversizeTestConnection(t, cbs, ws, wsc)
+	connection.maxMsgSize = 1024
+
+	eventID := fftypes.NewUUID()
+	connection.sendMessages <- &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: eventID},
+			Message: &core.Message{
+				Header: core.MessageHeader{Tag: strings.Repeat("a", 2048)},
+			},
+		},
+		Subscription: core.SubscriptionRef{Namespace: "ns1"},
+	}
+
+	b := <-wsc.Receive()
+	var ref core.WSEventReference
+	err := json.Unmarshal(b, &ref)
+	assert.NoError(t, err)
+	assert.Equal(t, core.WSEventReferenceType, ref.Type)
+	assert.Equal(t, eventID, ref.Event.ID)
+	assert.Equal(t, "ns1", ref.Subscription.Namespace)
+	assert.Greater(t, ref.Size, int64(2048))
+}
+
+func TestOversizeBatchReference(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	connection := startOversizeTestConnection(t, cbs, ws, wsc)
+	connection.maxMsgSize = 1024
+
+	eventID := fftypes.NewUUID()
+	connection.sendMessages <- &core.WSEventBatch{
+		Type: core.WSEventBatchType,
+		ID:   fftypes.NewUUID(),
+		Events: []*core.EventDelivery{{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{ID: eventID},
+				Message: &core.Message{
+					Header: core.MessageHeader{Tag: strings.Repeat("a", 2048)},
+				},
+			},
+		}},
+	}
+
+	b := <-wsc.Receive()
+	var batch core.WSEventBatch
+	err := json.Unmarshal(b, &batch)
+	assert.NoError(t, err)
+	assert.True(t, batch.References)
+	assert.Len(t, batch.Events, 1)
+	assert.Equal(t, eventID, batch.Events[0].ID)
+	assert.Nil(t, batch.Events[0].Message)
+}
+
+func TestOversizeFragments(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	connection := startOversizeTestConnection(t, cbs, ws, wsc)
+	connection.maxMsgSize = 512
+	// Reference mode falls back to fragments for messages that are not events
+	msg := map[string]string{"big": strings.Repeat("b", 2048)}
+	connection.sendMessages <- msg
+
+	var reassembled []byte
+	for {
+		b := <-wsc.Receive()
+		assert.LessOrEqual(t, len(b), 512)
+		var fragment core.WSMessageFragment
+		err := json.Unmarshal(b, &fragment)
+		assert.NoError(t, err)
+		assert.Equal(t, core.WSMessageFragmentType, fragment.Type)
+		reassembled = append(reassembled, fragment.Data...)
+		if fragment.Index == fragment.Count-1 {
+			break
+		}
+	}
+	var res map[string]string
+	err := json.Unmarshal(reassembled, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, res)
+}
+
+func TestOversizeReferenceTooLarge(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	connection := startOversizeTestConnection(t, cbs, ws, wsc)
+	connection.maxMsgSize = 512
+
+	connection.sendMessages <- &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID(), Topic: strings.Repeat("t", 1024)},
+		},
+	}
+
+	b := <-wsc.Receive()
+	var fragment core.WSMessageFragment
+	err := json.Unmarshal(b, &fragment)
+	assert.NoError(t, err)
+	assert.Equal(t, core.WSMessageFragmentType, fragment.Type)
+	assert.Greater(t, fragment.Count, 1)
+}
+
+func TestOversizeFragmentWriteFail(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	connection := startOversizeTestConnection(t, cbs, ws, wsc)
+	connection.maxMsgSize = minMaxMessageSize
+	connection.wsConn.Close()
+
+	err := connection.writeFragments([]byte("some data"))
+	assert.Error(t, err)
+}
+
 func TestUpgradeFail(t *testing.T) {
 	cbs := &eventsmocks.Callbacks{}
 	_, wsc, cancel := newTestWebsockets(t, cbs, nil)
@@ -993,8 +1194,10 @@ func TestWebsocketStatus(t *testing.T) {
 		started: []*websocketStartedSub{{WSStart: core.WSStart{
 			Ephemeral: false, Name: "name1", Namespace: "ns1",
 		}}},
-		remoteAddr: "otherhost",
-		userAgent:  "user",
+		remoteAddr:   "otherhost",
+		userAgent:    "user",
+		maxMsgSize:   1024,
+		oversizeMode: OversizeModeFragment,
 	}
 
 	status := ws.GetStatus()
@@ -1004,6 +1207,8 @@ func TestWebsocketStatus(t *testing.T) {
 	assert.Equal(t, "id1", status.Connections[0].ID)
 	assert.Equal(t, "otherhost", status.Connections[0].RemoteAddress)
 	assert.Equal(t, "user", status.Connections[0].UserAgent)
+	assert.Equal(t, int64(1024), status.Connections[0].MaxMessageSize)
+	assert.Equal(t, OversizeModeFragment, status.Connections[0].OversizeMode)
 	assert.Len(t, status.Connections[0].Subscriptions, 1)
 	assert.Equal(t, false, status.Connections[0].Subscriptions[0].Ephemeral)
 	assert.Equal(t, "ns1", status.Connections[0].Subscriptions[0].Namespace)
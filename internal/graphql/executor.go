@@ -0,0 +1,273 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Limits on the operations that are executed. Each nested field can perform a lookup for every item in the
+// list it is nested in, so the depth and number of the fields selected are limited well below what the parser allows.
+const (
+	maxSelectionDepth = 10
+	maxFields         = 200
+)
+
+// ResolveFn resolves the value of a field, given the object it is a field of (nil for the root query type),
+// and the arguments supplied in the query. Variables have already been substituted into the arguments.
+type ResolveFn func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// Object is a type in the schema. Fields that are not explicitly declared are resolved from the
+// JSON serialization of the source object, so only fields that require a lookup need declaring.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Field is a field that requires resolving, such as a query on the root type, or a nested lookup of
+// a related resource. The resolved value can be a single object, or a slice of them.
+type Field struct {
+	Type    *Object // nil if the resolved value is returned as JSON
+	Resolve ResolveFn
+}
+
+type Schema struct {
+	Query *Object
+}
+
+type executor struct {
+	schema    *Schema
+	variables map[string]interface{}
+	errors    []*core.GraphQLError
+}
+
+// Execute parses and executes a read-only query. An error is returned if the query cannot be executed at all,
+// while errors resolving individual fields are returned in the response alongside the data that could be resolved.
+func Execute(ctx context.Context, schema *Schema, req *core.GraphQLRequest) (*core.GraphQLResponse, error) {
+	doc, err := parse(ctx, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	op, err := selectOperation(ctx, doc, req.OperationName)
+	if err != nil {
+		return nil, err
+	}
+	fields := 0
+	if err := checkSelections(ctx, op.selections, 1, &fields); err != nil {
+		return nil, err
+	}
+	ex := &executor{
+		schema:    schema,
+		variables: make(map[string]interface{}),
+	}
+	for _, def := range op.variables {
+		if v, ok := req.Variables[def.name]; ok {
+			ex.variables[def.name] = v
+		} else if def.hasDefault {
+			ex.variables[def.name] = def.defaultValue
+		}
+	}
+	data := ex.resolveObject(ctx, schema.Query, nil, op.selections, nil)
+	return &core.GraphQLResponse{
+		Data:   data,
+		Errors: ex.errors,
+	}, nil
+}
+
+func selectOperation(ctx context.Context, doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLOperationNameRequired)
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLOperationNotFound, name)
+}
+
+// checkSelections rejects an operation that selects fields nested too deeply, or too many fields in total
+func checkSelections(ctx context.Context, selections []*selection, depth int, fields *int) error {
+	for _, sel := range selections {
+		if depth > maxSelectionDepth {
+			return i18n.NewError(ctx, coremsgs.MsgGraphQLTooDeep, sel.line, sel.column, maxSelectionDepth)
+		}
+		if *fields++; *fields > maxFields {
+			return i18n.NewError(ctx, coremsgs.MsgGraphQLTooManyFields, maxFields)
+		}
+		if err := checkSelections(ctx, sel.selections, depth+1, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ex *executor) addError(err error, path []interface{}) {
+	ex.errors = append(ex.errors, &core.GraphQLError{
+		Message: err.Error(),
+		Path:    path,
+	})
+}
+
+func (ex *executor) resolveObject(ctx context.Context, t *Object, source interface{}, selections []*selection, path []interface{}) fftypes.JSONObject {
+	var sourceJSON fftypes.JSONObject
+	result := fftypes.JSONObject{}
+	for _, sel := range selections {
+		key := sel.key()
+		fieldPath := append(append([]interface{}{}, path...), key)
+		if sel.name == "__typename" {
+			result[key] = t.Name
+			continue
+		}
+		field, ok := t.Fields[sel.name]
+		if !ok {
+			if source == nil {
+				ex.addError(i18n.NewError(ctx, coremsgs.MsgGraphQLUnknownField, sel.name, t.Name), fieldPath)
+				result[key] = nil
+				continue
+			}
+			if sourceJSON == nil {
+				sourceJSON, _ = toJSON(source).(map[string]interface{})
+			}
+			result[key] = project(sourceJSON[sel.name], sel.selections)
+			continue
+		}
+		args, err := ex.resolveArguments(ctx, sel)
+		var value interface{}
+		if err == nil {
+			value, err = field.Resolve(ctx, source, args)
+		}
+		if err != nil {
+			ex.addError(err, fieldPath)
+			result[key] = nil
+			continue
+		}
+		result[key] = ex.completeValue(ctx, field.Type, value, sel.selections, fieldPath)
+	}
+	return result
+}
+
+func (ex *executor) completeValue(ctx context.Context, t *Object, value interface{}, selections []*selection, path []interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+	case reflect.Slice:
+		if t != nil {
+			list := make([]interface{}, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				list[i] = ex.completeValue(ctx, t, rv.Index(i).Interface(), selections, append(append([]interface{}{}, path...), i))
+			}
+			return list
+		}
+	}
+	if t == nil || len(selections) == 0 {
+		return project(toJSON(value), selections)
+	}
+	return ex.resolveObject(ctx, t, value, selections, path)
+}
+
+func (ex *executor) resolveArguments(ctx context.Context, sel *selection) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(sel.arguments))
+	for _, arg := range sel.arguments {
+		v, err := ex.resolveValue(ctx, arg.value)
+		if err != nil {
+			return nil, err
+		}
+		args[arg.name] = v
+	}
+	return args, nil
+}
+
+func (ex *executor) resolveValue(ctx context.Context, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case variableRef:
+		resolved, ok := ex.variables[string(v)]
+		if !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLVariableUndefined, string(v))
+		}
+		return resolved, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if list[i], err = ex.resolveValue(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			var err error
+			if obj[k], err = ex.resolveValue(ctx, item); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	default:
+		return v, nil
+	}
+}
+
+// toJSON converts a resolved value to its generic JSON representation, so it can be projected
+func toJSON(value interface{}) interface{} {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	_ = json.Unmarshal(b, &generic)
+	return generic
+}
+
+// project selects the requested fields from a JSON value, applying aliases
+func project(value interface{}, selections []*selection) interface{} {
+	if len(selections) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := fftypes.JSONObject{}
+		for _, sel := range selections {
+			result[sel.key()] = project(v[sel.name], sel.selections)
+		}
+		return result
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = project(item, selections)
+		}
+		return list
+	default:
+		return v
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuthor struct {
+	Name string `json:"name"`
+}
+
+type testBook struct {
+	ID     string           `json:"id"`
+	Title  string           `json:"title"`
+	Author string           `json:"author"`
+	Tags   []string         `json:"tags"`
+	Meta   *fftypes.JSONAny `json:"meta,omitempty"`
+}
+
+func newTestSchema() *Schema {
+	books := []*testBook{
+		{ID: "b1", Title: "First", Author: "alice", Tags: []string{"x"}, Meta: fftypes.JSONAnyPtr(`{"pages":10,"isbn":"123"}`)},
+		{ID: "b2", Title: "Second", Author: "bob"},
+	}
+	author := &Object{Name: "Author"}
+	book := &Object{Name: "Book", Fields: map[string]*Field{
+		"author": {Type: author, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			name := source.(*testBook).Author
+			if name == "bob" {
+				return nil, fmt.Errorf("pop")
+			}
+			return &testAuthor{Name: name}, nil
+		}},
+		"related": {Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return []string{"r1", "r2"}, nil
+		}},
+	}}
+	return &Schema{
+		Query: &Object{Name: "Query", Fields: map[string]*Field{
+			"book": {Type: book, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				for _, b := range books {
+					if b.ID == args["id"] {
+						return b, nil
+					}
+				}
+				return (*testBook)(nil), nil
+			}},
+			"books": {Type: book, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				if args["fail"] == true {
+					return nil, fmt.Errorf("pop")
+				}
+				if args["empty"] == true {
+					return []*testBook(nil), nil
+				}
+				return books, nil
+			}},
+			"echo": {Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				return args, nil
+			}},
+			"nothing": {Type: book, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				return nil, nil
+			}},
+			"bad": {Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				return map[bool]bool{true: true}, nil
+			}},
+		}},
+	}
+}
+
+func execute(t *testing.T, req *core.GraphQLRequest) string {
+	res, err := Execute(context.Background(), newTestSchema(), req)
+	assert.NoError(t, err)
+	b, err := json.Marshal(res)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestExecuteNestedQuery(t *testing.T) {
+	res := execute(t, &core.GraphQLRequest{
+		Query: `{
+			__typename
+			first: book(id: "b1") { __typename id title author { name } meta { pages } tags related }
+			missing: book(id: "b3") { id }
+			nothing { id }
+		}`,
+	})
+	assert.JSONEq(t, `{"data": {
+		"__typename": "Query",
+		"first": {"__typename": "Book", "id": "b1", "title": "First", "author": {"name": "alice"}, "meta": {"pages": 10}, "tags": ["x"], "related": ["r1", "r2"]},
+		"missing": null,
+		"nothing": null
+	}}`, res)
+}
+
+func TestExecuteListWithFieldErrors(t *testing.T) {
+	res := execute(t, &core.GraphQLRequest{
+		Query: `{ books { id author { name } } failed: books(fail: true) { id } empty: books(empty: true) { id } unknown { id } bad }`,
+	})
+	assert.JSONEq(t, `{
+		"data": {
+			"books": [{"id": "b1", "author": {"name": "alice"}}, {"id": "b2", "author": null}],
+			"failed": null,
+			"empty": [],
+			"unknown": null,
+			"bad": null
+		},
+		"errors": [
+			{"message": "pop", "path": ["books", 1, "author"]},
+			{"message": "pop", "path": ["failed"]},
+			{"message": "FF10542: Unknown field 'unknown' on type 'Query'", "path": ["unknown"]}
+		]
+	}`, res)
+}
+
+func TestExecuteWholeObjectWithoutSelections(t *testing.T) {
+	res := execute(t, &core.GraphQLRequest{
+		Query: `{ book(id: "b2") }`,
+	})
+	assert.JSONEq(t, `{"data": {"book": {"id": "b2", "title": "Second", "author": "bob", "tags": null}}}`, res)
+}
+
+func TestExecuteVariables(t *testing.T) {
+	res := execute(t, &core.GraphQLRequest{
+		Query: `
+			query Other { book(id: "b2") { id } }
+			query Echo($a: String, $b: [Int] = [1], $c: Boolean) {
+				echo(a: $a, b: $b, c: {nested: [$a]})
+				undefined: echo(d: $d)
+				undefinedInList: echo(d: [$d])
+				undefinedInObject: echo(d: {e: $d})
+			}`,
+		OperationName: "Echo",
+		Variables:     fftypes.JSONObject{"a": "hello"},
+	})
+	assert.JSONEq(t, `{
+		"data": {
+			"echo": {"a": "hello", "b": [1], "c": {"nested": ["hello"]}},
+			"undefined": null,
+			"undefinedInList": null,
+			"undefinedInObject": null
+		},
+		"errors": [
+			{"message": "FF10543: Variable '$d' is not defined, and has no default value", "path": ["undefined"]},
+			{"message": "FF10543: Variable '$d' is not defined, and has no default value", "path": ["undefinedInList"]},
+			{"message": "FF10543: Variable '$d' is not defined, and has no default value", "path": ["undefinedInObject"]}
+		]
+	}`, res)
+}
+
+func TestExecuteOperationSelection(t *testing.T) {
+	ctx := context.Background()
+	schema := newTestSchema()
+
+	_, err := Execute(ctx, schema, &core.GraphQLRequest{Query: `query A { books { id } } query B { books { id } }`})
+	assert.Regexp(t, "FF10540", err)
+
+	_, err = Execute(ctx, schema, &core.GraphQLRequest{Query: `query A { books { id } }`, OperationName: "C"})
+	assert.Regexp(t, "FF10541.*C", err)
+
+	_, err = Execute(ctx, schema, &core.GraphQLRequest{Query: `{`})
+	assert.Regexp(t, "FF10538", err)
+}
+
+func TestProjectList(t *testing.T) {
+	sels := []*selection{{name: "a"}, {alias: "x", name: "b"}}
+	assert.Equal(t, []interface{}{
+		fftypes.JSONObject{"a": float64(1), "x": nil},
+		"scalar",
+	}, project([]interface{}{
+		map[string]interface{}{"a": float64(1)},
+		"scalar",
+	}, sels))
+}
+
+func TestExecuteSelectionsTooDeep(t *testing.T) {
+	res := execute(t, &core.GraphQLRequest{
+		Query: `{ book(id: "b1") ` + strings.Repeat("{ meta ", maxSelectionDepth-1) + strings.Repeat("}", maxSelectionDepth),
+	})
+	assert.Contains(t, res, `"data"`)
+
+	_, err := Execute(context.Background(), newTestSchema(), &core.GraphQLRequest{
+		Query: `{ book(id: "b1") ` + strings.Repeat("{ meta ", maxSelectionDepth) + strings.Repeat("}", maxSelectionDepth+1),
+	})
+	assert.Regexp(t, "FF10658.*line 1, column 83", err)
+}
+
+func TestExecuteTooManyFields(t *testing.T) {
+	res := execute(t, &core.GraphQLRequest{
+		Query: "{ " + strings.Repeat("echo ", maxFields) + "}",
+	})
+	assert.Contains(t, res, `"data"`)
+
+	_, err := Execute(context.Background(), newTestSchema(), &core.GraphQLRequest{
+		Query: "{ " + strings.Repeat("echo ", maxFields-1) + "book(id: \"b1\") { id } }",
+	})
+	assert.Regexp(t, "FF10664", err)
+}
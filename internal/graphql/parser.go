@@ -0,0 +1,541 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// Limits on the documents that are parsed, so that a single request cannot exhaust the memory or stack of the node.
+// The parser is recursive, and a stack overflow cannot be recovered from.
+const (
+	maxQueryLength = 64 * 1024
+	maxDepth       = 32
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	line   int
+	column int
+}
+
+type document struct {
+	operations []*operation
+}
+
+type operation struct {
+	opType     string
+	name       string
+	variables  []*variableDefinition
+	selections []*selection
+}
+
+type variableDefinition struct {
+	name         string
+	defaultValue interface{}
+	hasDefault   bool
+}
+
+type selection struct {
+	alias      string
+	name       string
+	arguments  []*argument
+	selections []*selection
+	line       int
+	column     int
+}
+
+type argument struct {
+	name  string
+	value interface{}
+}
+
+// variableRef is a reference to a variable within an argument value, resolved at execution time
+type variableRef string
+
+func (s *selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type parser struct {
+	ctx    context.Context
+	src    string
+	pos    int
+	line   int
+	column int
+	tok    token
+	depth  int
+}
+
+// parse reads an executable GraphQL document. Only the subset of the language that makes
+// sense for a read-only query endpoint is supported - fragments and directives are rejected.
+func parse(ctx context.Context, src string) (doc *document, err error) {
+	if len(src) > maxQueryLength {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLQueryTooLarge, maxQueryLength)
+	}
+	p := &parser{ctx: ctx, src: src, line: 1, column: 1}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	doc = &document{}
+	for p.tok.kind != tokenEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.operations = append(doc.operations, op)
+	}
+	if len(doc.operations) == 0 {
+		return nil, p.unexpected()
+	}
+	return doc, nil
+}
+
+func (p *parser) unexpected() error {
+	value := p.tok.value
+	if p.tok.kind == tokenEOF && value == "" {
+		value = "<EOF>"
+	}
+	return i18n.NewError(p.ctx, coremsgs.MsgGraphQLSyntaxError, p.tok.line, p.tok.column, value)
+}
+
+// nest is called on entering a nested selection set, list, object or type, and returns a function to call on leaving it
+func (p *parser) nest() (leave func(), err error) {
+	if p.depth >= maxDepth {
+		return nil, i18n.NewError(p.ctx, coremsgs.MsgGraphQLTooDeep, p.tok.line, p.tok.column, maxDepth)
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *parser) expect(kind tokenKind, value string) error {
+	if !p.peek(kind, value) {
+		return p.unexpected()
+	}
+	return p.next()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.next()
+}
+
+func (p *parser) parseOperation() (op *operation, err error) {
+	op = &operation{opType: "query"}
+	if p.peek(tokenPunctuator, "{") {
+		// Query shorthand
+		op.selections, err = p.parseSelectionSet()
+		return op, err
+	}
+	if p.tok.kind != tokenName {
+		return nil, p.unexpected()
+	}
+	switch p.tok.value {
+	case "query":
+	case "mutation", "subscription", "fragment":
+		return nil, i18n.NewError(p.ctx, coremsgs.MsgGraphQLUnsupported, p.tok.value)
+	default:
+		return nil, p.unexpected()
+	}
+	if err = p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek(tokenPunctuator, "(") {
+		if op.variables, err = p.parseVariableDefinitions(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek(tokenPunctuator, "@") {
+		return nil, i18n.NewError(p.ctx, coremsgs.MsgGraphQLUnsupported, "directive")
+	}
+	op.selections, err = p.parseSelectionSet()
+	return op, err
+}
+
+func (p *parser) parseVariableDefinitions() (defs []*variableDefinition, err error) {
+	if err = p.expect(tokenPunctuator, "("); err != nil {
+		return nil, err
+	}
+	for !p.peek(tokenPunctuator, ")") {
+		if err = p.expect(tokenPunctuator, "$"); err != nil {
+			return nil, err
+		}
+		def := &variableDefinition{}
+		if def.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(tokenPunctuator, ":"); err != nil {
+			return nil, err
+		}
+		// Types are not checked - values are passed through to the resolvers, which validate them
+		if err = p.skipType(); err != nil {
+			return nil, err
+		}
+		if p.peek(tokenPunctuator, "=") {
+			if err = p.next(); err != nil {
+				return nil, err
+			}
+			if def.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+			def.hasDefault = true
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.next()
+}
+
+func (p *parser) skipType() (err error) {
+	if p.peek(tokenPunctuator, "[") {
+		leave, err := p.nest()
+		if err != nil {
+			return err
+		}
+		defer leave()
+		if err = p.next(); err != nil {
+			return err
+		}
+		if err = p.skipType(); err != nil {
+			return err
+		}
+		if err = p.expect(tokenPunctuator, "]"); err != nil {
+			return err
+		}
+	} else if _, err = p.expectName(); err != nil {
+		return err
+	}
+	if p.peek(tokenPunctuator, "!") {
+		return p.next()
+	}
+	return nil
+}
+
+func (p *parser) parseSelectionSet() (selections []*selection, err error) {
+	leave, err := p.nest()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+	if err = p.expect(tokenPunctuator, "{"); err != nil {
+		return nil, err
+	}
+	for !p.peek(tokenPunctuator, "}") {
+		if p.peek(tokenPunctuator, "...") {
+			return nil, i18n.NewError(p.ctx, coremsgs.MsgGraphQLUnsupported, "fragment")
+		}
+		sel := &selection{line: p.tok.line, column: p.tok.column}
+		if sel.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if p.peek(tokenPunctuator, ":") {
+			if err = p.next(); err != nil {
+				return nil, err
+			}
+			sel.alias = sel.name
+			if sel.name, err = p.expectName(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek(tokenPunctuator, "(") {
+			if sel.arguments, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek(tokenPunctuator, "@") {
+			return nil, i18n.NewError(p.ctx, coremsgs.MsgGraphQLUnsupported, "directive")
+		}
+		if p.peek(tokenPunctuator, "{") {
+			if sel.selections, err = p.parseSelectionSet(); err != nil {
+				return nil, err
+			}
+		}
+		selections = append(selections, sel)
+	}
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+	return selections, p.next()
+}
+
+func (p *parser) parseArguments() (args []*argument, err error) {
+	if err = p.expect(tokenPunctuator, "("); err != nil {
+		return nil, err
+	}
+	for !p.peek(tokenPunctuator, ")") {
+		arg := &argument{}
+		if arg.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(tokenPunctuator, ":"); err != nil {
+			return nil, err
+		}
+		if arg.value, err = p.parseValue(false); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 0 {
+		return nil, p.unexpected()
+	}
+	return args, p.next()
+}
+
+func (p *parser) parseValue(constant bool) (v interface{}, err error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenPunctuator && tok.value == "$" && !constant:
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		return variableRef(name), err
+	case tok.kind == tokenPunctuator && tok.value == "[":
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		list := []interface{}{}
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		for !p.peek(tokenPunctuator, "]") {
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.next()
+	case tok.kind == tokenPunctuator && tok.value == "{":
+		leave, err := p.nest()
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		obj := map[string]interface{}{}
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		for !p.peek(tokenPunctuator, "}") {
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(tokenPunctuator, ":"); err != nil {
+				return nil, err
+			}
+			if obj[name], err = p.parseValue(constant); err != nil {
+				return nil, err
+			}
+		}
+		return obj, p.next()
+	case tok.kind == tokenInt:
+		v, err = strconv.ParseInt(tok.value, 10, 64)
+	case tok.kind == tokenFloat:
+		v, err = strconv.ParseFloat(tok.value, 64)
+	case tok.kind == tokenString:
+		v = tok.value
+	case tok.kind == tokenName && tok.value == "true":
+		v = true
+	case tok.kind == tokenName && tok.value == "false":
+		v = false
+	case tok.kind == tokenName && tok.value == "null":
+		v = nil
+	case tok.kind == tokenName:
+		// Enum values are passed through as strings
+		v = tok.value
+	default:
+		return nil, p.unexpected()
+	}
+	if err != nil {
+		return nil, p.unexpected()
+	}
+	return v, p.next()
+}
+
+func (p *parser) advance(n int) {
+	for i := 0; i < n; i++ {
+		if p.src[p.pos] == '\n' {
+			p.line++
+			p.column = 1
+		} else {
+			p.column++
+		}
+		p.pos++
+	}
+}
+
+// next reads the next lexical token, skipping whitespace, commas and comments
+func (p *parser) next() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.advance(1)
+		} else if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.advance(1)
+			}
+		} else if strings.HasPrefix(p.src[p.pos:], "\uFEFF") {
+			p.pos += len("\uFEFF")
+		} else {
+			break
+		}
+	}
+	p.tok = token{line: p.line, column: p.column}
+	if p.pos >= len(p.src) {
+		p.tok.kind = tokenEOF
+		return nil
+	}
+	start := p.pos
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.tok.kind = tokenPunctuator
+		p.advance(3)
+	case strings.ContainsRune("!$()&:=@[]{}|", rune(c)):
+		p.tok.kind = tokenPunctuator
+		p.advance(1)
+	case c == '_' || isLetter(c):
+		p.tok.kind = tokenName
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.advance(1)
+		}
+	case c == '-' || isDigit(c):
+		p.tok.kind = tokenInt
+		p.advance(1)
+		for p.pos < len(p.src) {
+			c = p.src[p.pos]
+			if c == '.' || c == 'e' || c == 'E' || ((c == '+' || c == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E')) {
+				p.tok.kind = tokenFloat
+			} else if !isDigit(c) {
+				break
+			}
+			p.advance(1)
+		}
+	case c == '"':
+		return p.readString()
+	default:
+		p.tok.value = string(c)
+		return p.unexpected()
+	}
+	p.tok.value = p.src[start:p.pos]
+	return nil
+}
+
+func (p *parser) readString() error {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		p.advance(3)
+		end := strings.Index(p.src[p.pos:], `"""`)
+		if end < 0 {
+			p.tok.value = `"""`
+			return p.unexpected()
+		}
+		p.tok.kind = tokenString
+		p.tok.value = p.src[p.pos : p.pos+end]
+		p.advance(end + 3)
+		return nil
+	}
+	p.advance(1)
+	var value strings.Builder
+	for p.pos < len(p.src) && p.src[p.pos] != '"' && p.src[p.pos] != '\n' {
+		c := p.src[p.pos]
+		if c != '\\' {
+			value.WriteByte(c)
+			p.advance(1)
+			continue
+		}
+		if p.pos+1 >= len(p.src) {
+			break
+		}
+		switch e := p.src[p.pos+1]; e {
+		case '"', '\\', '/':
+			value.WriteByte(e)
+		case 'b':
+			value.WriteByte('\b')
+		case 'f':
+			value.WriteByte('\f')
+		case 'n':
+			value.WriteByte('\n')
+		case 'r':
+			value.WriteByte('\r')
+		case 't':
+			value.WriteByte('\t')
+		case 'u':
+			r, err := strconv.ParseUint(p.src[p.pos+2:min(p.pos+6, len(p.src))], 16, 32)
+			if err != nil {
+				p.tok.value = p.src[p.pos:min(p.pos+6, len(p.src))]
+				return p.unexpected()
+			}
+			value.WriteRune(rune(r))
+			p.advance(4)
+		default:
+			p.tok.value = p.src[p.pos : p.pos+2]
+			return p.unexpected()
+		}
+		p.advance(2)
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+		p.tok.value = `"`
+		return p.unexpected()
+	}
+	p.advance(1)
+	p.tok.kind = tokenString
+	p.tok.value = value.String()
+	return nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
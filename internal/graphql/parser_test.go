@@ -0,0 +1,171 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShorthandQuery(t *testing.T) {
+	doc, err := parse(context.Background(), `
+		# fetch some messages
+		{
+			first: messages(limit: 1, topics: ["a", "b"], pending: false, score: 1.5e2, cid: null, state: confirmed) {
+				header { id }
+			}
+		}
+	`)
+	assert.NoError(t, err)
+	assert.Len(t, doc.operations, 1)
+	op := doc.operations[0]
+	assert.Equal(t, "query", op.opType)
+	assert.Len(t, op.selections, 1)
+	sel := op.selections[0]
+	assert.Equal(t, "first", sel.key())
+	assert.Equal(t, "messages", sel.name)
+	assert.Equal(t, 4, sel.line)
+	assert.Equal(t, 4, sel.column)
+	assert.Equal(t, int64(1), sel.arguments[0].value)
+	assert.Equal(t, []interface{}{"a", "b"}, sel.arguments[1].value)
+	assert.Equal(t, false, sel.arguments[2].value)
+	assert.Equal(t, float64(150), sel.arguments[3].value)
+	assert.Nil(t, sel.arguments[4].value)
+	assert.Equal(t, "confirmed", sel.arguments[5].value)
+	assert.Equal(t, "header", sel.selections[0].key())
+	assert.Equal(t, "id", sel.selections[0].selections[0].key())
+}
+
+func TestParseNamedQueryWithVariables(t *testing.T) {
+	doc, err := parse(context.Background(), `query Msgs($id: String!, $limit: Int = 10, $tags: [String!]) {
+		message(id: $id) { header { id } }
+		messages(limit: $limit, where: {tag: $tags}) { header { tag } }
+	}`)
+	assert.NoError(t, err)
+	op := doc.operations[0]
+	assert.Equal(t, "Msgs", op.name)
+	assert.Len(t, op.variables, 3)
+	assert.False(t, op.variables[0].hasDefault)
+	assert.True(t, op.variables[1].hasDefault)
+	assert.Equal(t, int64(10), op.variables[1].defaultValue)
+	assert.Equal(t, variableRef("id"), op.selections[0].arguments[0].value)
+	assert.Equal(t, map[string]interface{}{"tag": variableRef("tags")}, op.selections[1].arguments[1].value)
+}
+
+func TestParseStrings(t *testing.T) {
+	doc, err := parse(context.Background(), `{ a(s: "q\"\\\/\b\f\n\r\té\u00e9", b: """block "quoted" string""") }`)
+	assert.NoError(t, err)
+	args := doc.operations[0].selections[0].arguments
+	assert.Equal(t, "q\"\\/\b\f\n\r\téé", args[0].value)
+	assert.Equal(t, `block "quoted" string`, args[1].value)
+}
+
+func TestParseBOM(t *testing.T) {
+	doc, err := parse(context.Background(), "\uFEFF{ a }")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", doc.operations[0].selections[0].name)
+}
+
+func TestParseSyntaxErrors(t *testing.T) {
+	ctx := context.Background()
+	for query, expected := range map[string]string{
+		``:                               "FF10538.*line 1, column 1.*<EOF>",
+		`{`:                              "FF10538.*<EOF>",
+		`{ }`:                            "FF10538.*'}'",
+		"{\n  a(\n  b: ?) }":             "FF10538.*line 3, column 6.*'\\?'",
+		`{ a() }`:                        "FF10538.*'\\)'",
+		`{ a: 1 }`:                       "FF10538.*'1'",
+		`{ a(b: -) }`:                    "FF10538.*'-'",
+		`{ a(b: 1.2.3) }`:                "FF10538.*'1.2.3'",
+		`{ a(b: "unterminated) }`:        "FF10538.*'\"'",
+		`{ a(b: """unterminated) }`:      "FF10538.*'\"\"\"'",
+		`{ a(b: "bad \x") }`:             "FF10538.*'\\\\x'",
+		`{ a(b: "bad \u12") }`:           "FF10538.*'\\\\u12\"\\)'",
+		`{ a(b: "trailing \`:             "FF10538.*'\"'",
+		`{ a(b: $v) }`:                   "",
+		`?`:                              "FF10538.*'\\?'",
+		`{ a(b: $ ?) }`:                  "FF10538.*'\\?'",
+		`query { a(b: [}) }`:             "FF10538.*'}'",
+		`query { a(b: {c 1}) }`:          "FF10538.*'1'",
+		`query { a(b: {1: 1}) }`:         "FF10538.*'1'",
+		`query { a(b: $1) }`:             "FF10538.*'1'",
+		`query Q(v: Int) { a }`:          "FF10538.*'v'",
+		`query Q($v Int) { a }`:          "FF10538.*'Int'",
+		`query Q($v: [Int) { a }`:        "FF10538.*'\\)'",
+		`query Q($v: [) { a }`:           "FF10538.*'\\)'",
+		`query Q($v: Int = $w) { a }`:    "FF10538.*'\\$'",
+		`query Q($v: Int = ) { a }`:      "FF10538.*'\\)'",
+		`query Q($v: Int!) a`:            "FF10538.*'a'",
+		`other { a }`:                    "FF10538.*'other'",
+		`1`:                              "FF10538.*'1'",
+		`mutation { a }`:                 "FF10539.*mutation",
+		`subscription { a }`:             "FF10539.*subscription",
+		`fragment F on Message { a }`:    "FF10539.*fragment",
+		`{ ...F }`:                       "FF10539.*fragment",
+		`{ a @include(if: true) }`:       "FF10539.*directive",
+		`query Q @live { a }`:            "FF10539.*directive",
+		`query Q { a { b { } } }`:        "FF10538.*'}'",
+		`query Q { a(x: 1, y: [1 }) }`:   "FF10538.*'}'",
+		`query Q { a(x: 1) { b(y: ) } }`: "FF10538.*'\\)'",
+	} {
+		_, err := parse(ctx, query)
+		if expected == "" {
+			assert.NoError(t, err, query)
+		} else {
+			assert.Regexp(t, expected, err, query)
+		}
+	}
+}
+
+func TestParseBadCharacterAfterEachToken(t *testing.T) {
+	tokens := strings.Split(`query Q ( $a : [ Int ! ] = [ 1 ] ) { x : a ( b : $a , c : { d : "e" } , f : [ true ] ) { g } }`, " ")
+	for i := range tokens {
+		query := strings.Join(tokens[:i+1], " ") + " ? " + strings.Join(tokens[i+1:], " ")
+		_, err := parse(context.Background(), query)
+		assert.Regexp(t, "FF10538.*'\\?'", err, query)
+	}
+}
+
+func TestParseQueryTooLarge(t *testing.T) {
+	_, err := parse(context.Background(), "{ messages { header { id } } }"+strings.Repeat(" ", maxQueryLength))
+	assert.Regexp(t, "FF10657", err)
+}
+
+func TestParseSelectionsTooDeep(t *testing.T) {
+	_, err := parse(context.Background(), strings.Repeat("{ a ", maxDepth)+strings.Repeat("}", maxDepth))
+	assert.NoError(t, err)
+	_, err = parse(context.Background(), strings.Repeat("{ a ", maxDepth+1)+strings.Repeat("}", maxDepth+1))
+	assert.Regexp(t, "FF10658", err)
+}
+
+func TestParseValuesTooDeep(t *testing.T) {
+	// The selection set counts towards the depth of the values within it
+	_, err := parse(context.Background(), "{ a(b: "+strings.Repeat("[", maxDepth-1)+strings.Repeat("]", maxDepth-1)+") }")
+	assert.NoError(t, err)
+	_, err = parse(context.Background(), "{ a(b: "+strings.Repeat("[", 20000)+") }")
+	assert.Regexp(t, "FF10658", err)
+	_, err = parse(context.Background(), "{ a(b: "+strings.Repeat("{c: ", maxDepth+1)+") }")
+	assert.Regexp(t, "FF10658", err)
+}
+
+func TestParseVariableTypeTooDeep(t *testing.T) {
+	_, err := parse(context.Background(), "query ($v: "+strings.Repeat("[", maxDepth+1)+") { a }")
+	assert.Regexp(t, "FF10658", err)
+}
@@ -0,0 +1,273 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ha
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// Manager elects a single active node, from multiple nodes that share one database. The active node holds a
+// leader lock, which it renews before it expires. Passive nodes try to acquire the lock, and take over from the
+// active node if it fails to renew it.
+type Manager interface {
+	Start()
+	WaitStop()
+	IsActive() bool
+	WaitActive(ctx context.Context) error
+	GetStatus(ctx context.Context) (*core.HAStatus, error)
+}
+
+// LockProvider holds a named lock with an expiry, on behalf of one holder at a time
+type LockProvider interface {
+	AcquireLock(ctx context.Context, name, holder string, expires *fftypes.FFTime) (bool, error)
+	GetLock(ctx context.Context, name string) (*core.LeaderLock, error)
+	ReleaseLock(ctx context.Context, name, holder string) error
+}
+
+type databaseLockProvider struct {
+	database database.Plugin
+}
+
+// NewDatabaseLockProvider holds the leader lock in a table of the database
+func NewDatabaseLockProvider(di database.Plugin) LockProvider {
+	return &databaseLockProvider{database: di}
+}
+
+func (dl *databaseLockProvider) AcquireLock(ctx context.Context, name, holder string, expires *fftypes.FFTime) (bool, error) {
+	return dl.database.AcquireLeaderLock(ctx, &core.LeaderLock{
+		Name:    name,
+		Holder:  holder,
+		Expires: expires,
+	}, fftypes.Now())
+}
+
+func (dl *databaseLockProvider) GetLock(ctx context.Context, name string) (*core.LeaderLock, error) {
+	return dl.database.GetLeaderLock(ctx, name)
+}
+
+func (dl *databaseLockProvider) ReleaseLock(ctx context.Context, name, holder string) error {
+	return dl.database.ReleaseLeaderLock(ctx, name, holder)
+}
+
+type haManager struct {
+	ctx           context.Context
+	cancelCtx     func()
+	locks         LockProvider
+	onDemoted     func()
+	lockName      string
+	nodeID        string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	mux           sync.Mutex
+	active        bool
+	activeChan    chan struct{}
+	leaseExpiry   *fftypes.FFTime
+	expiryTimer   *time.Timer
+	demoted       bool
+	modeChanged   *fftypes.FFTime
+	started       bool
+	done          chan struct{}
+}
+
+// NewHAManager creates an election of the active node. Once this node is active, it remains active until it fails to
+// renew the leader lock, at which point onDemoted is called. It is not safe for a demoted node to continue
+// processing, so onDemoted is expected to shut the node down.
+func NewHAManager(ctx context.Context, locks LockProvider, onDemoted func()) (Manager, error) {
+	if locks == nil || onDemoted == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "HAManager")
+	}
+	hm := &haManager{
+		locks:         locks,
+		onDemoted:     onDemoted,
+		lockName:      config.GetString(coreconfig.HALockName),
+		nodeID:        config.GetString(coreconfig.HANodeID),
+		leaseDuration: config.GetDuration(coreconfig.HALeaseDuration),
+		renewInterval: config.GetDuration(coreconfig.HARenewInterval),
+		activeChan:    make(chan struct{}),
+		modeChanged:   fftypes.Now(),
+		done:          make(chan struct{}),
+	}
+	if hm.renewInterval <= 0 || hm.renewInterval >= hm.leaseDuration {
+		return nil, i18n.NewError(ctx, coremsgs.MsgHARenewIntervalInvalid, hm.renewInterval, hm.leaseDuration)
+	}
+	if hm.nodeID == "" {
+		hostname, _ := os.Hostname()
+		hm.nodeID = fmt.Sprintf("%s-%s", hostname, fftypes.NewUUID().String()[0:8])
+	}
+	hm.ctx, hm.cancelCtx = context.WithCancel(log.WithLogField(ctx, "role", "ha"))
+	return hm, nil
+}
+
+func (hm *haManager) Start() {
+	log.L(hm.ctx).Infof("Node '%s' is passive until it acquires leader lock '%s'", hm.nodeID, hm.lockName)
+	hm.started = true
+	go hm.electionLoop()
+}
+
+func (hm *haManager) WaitStop() {
+	hm.cancelCtx()
+	if !hm.started {
+		return
+	}
+	<-hm.done
+	hm.mux.Lock()
+	if hm.expiryTimer != nil {
+		hm.expiryTimer.Stop()
+	}
+	hm.mux.Unlock()
+	if hm.IsActive() {
+		// Release the lock, so a passive node can take over without waiting for it to expire
+		ctx := log.WithLogField(context.Background(), "role", "ha")
+		if err := hm.locks.ReleaseLock(ctx, hm.lockName, hm.nodeID); err != nil {
+			log.L(ctx).Warnf("Failed to release leader lock '%s': %s", hm.lockName, err)
+		}
+	}
+}
+
+func (hm *haManager) IsActive() bool {
+	hm.mux.Lock()
+	defer hm.mux.Unlock()
+	return hm.active
+}
+
+// WaitActive blocks until this node is the active node
+func (hm *haManager) WaitActive(ctx context.Context) error {
+	select {
+	case <-hm.activeChan:
+		return nil
+	case <-ctx.Done():
+		return i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+	}
+}
+
+func (hm *haManager) GetStatus(ctx context.Context) (*core.HAStatus, error) {
+	hm.mux.Lock()
+	status := &core.HAStatus{
+		Enabled:     true,
+		NodeID:      hm.nodeID,
+		Mode:        core.HAModePassive,
+		ModeChanged: hm.modeChanged,
+	}
+	if hm.active {
+		status.Mode = core.HAModeActive
+		status.Leader = hm.nodeID
+		status.LeaseExpiry = hm.leaseExpiry
+	}
+	hm.mux.Unlock()
+
+	if status.Mode == core.HAModePassive {
+		lock, err := hm.locks.GetLock(ctx, hm.lockName)
+		if err != nil {
+			return nil, err
+		}
+		if lock != nil {
+			status.Leader = lock.Holder
+			status.LeaseExpiry = lock.Expires
+		}
+	}
+	return status, nil
+}
+
+func (hm *haManager) electionLoop() {
+	defer close(hm.done)
+	for hm.elect() {
+		select {
+		case <-time.After(hm.renewInterval):
+		case <-hm.ctx.Done():
+			log.L(hm.ctx).Debugf("HA election loop exiting")
+			return
+		}
+	}
+}
+
+// elect acquires or renews the leader lock, and returns false if this node has been demoted
+func (hm *haManager) elect() bool {
+	now := time.Now()
+	expires := fftypes.FFTime(now.Add(hm.leaseDuration))
+
+	// A lock confirmed after the current lease has expired is no use, as another node may have taken over,
+	// so the lock provider is only waited on until then
+	deadline := time.Time(expires)
+	hm.mux.Lock()
+	if hm.active {
+		deadline = time.Time(*hm.leaseExpiry)
+	}
+	hm.mux.Unlock()
+	ctx, cancel := context.WithDeadline(hm.ctx, deadline)
+	acquired, err := hm.locks.AcquireLock(ctx, hm.lockName, hm.nodeID, &expires)
+	cancel()
+
+	hm.mux.Lock()
+	defer hm.mux.Unlock()
+	switch {
+	case hm.demoted:
+		// The lease expired while the lock provider was being called
+		return false
+	case err == nil && acquired:
+		hm.leaseExpiry = &expires
+		if hm.expiryTimer == nil {
+			hm.expiryTimer = time.AfterFunc(time.Until(time.Time(expires)), hm.checkLeaseExpiry)
+		} else {
+			hm.expiryTimer.Reset(time.Until(time.Time(expires)))
+		}
+		if !hm.active {
+			log.L(hm.ctx).Infof("Node '%s' acquired leader lock '%s' and is now active", hm.nodeID, hm.lockName)
+			hm.active = true
+			hm.modeChanged = fftypes.Now()
+			close(hm.activeChan)
+		}
+	case hm.active && (err == nil || !now.Add(hm.renewInterval).Before(time.Time(*hm.leaseExpiry))):
+		// Another node has taken the lock, or the lock will expire before the next attempt to renew it
+		log.L(hm.ctx).Errorf("Node '%s' lost leader lock '%s' (err=%v)", hm.nodeID, hm.lockName, err)
+		hm.demote()
+		return false
+	case err != nil:
+		log.L(hm.ctx).Warnf("Failed to acquire leader lock '%s': %s", hm.lockName, err)
+	}
+	return true
+}
+
+// checkLeaseExpiry demotes this node as soon as its lease expires without a confirmed renewal, as another node
+// can take over from that point - even if the election loop is still waiting for the lock provider
+func (hm *haManager) checkLeaseExpiry() {
+	hm.mux.Lock()
+	defer hm.mux.Unlock()
+	if hm.active && !time.Now().Before(time.Time(*hm.leaseExpiry)) {
+		log.L(hm.ctx).Errorf("Node '%s' lost leader lock '%s' - the lease expired at %s without a confirmed renewal", hm.nodeID, hm.lockName, hm.leaseExpiry)
+		hm.demote()
+	}
+}
+
+func (hm *haManager) demote() {
+	hm.active = false
+	hm.demoted = true
+	hm.modeChanged = fftypes.Now()
+	hm.onDemoted()
+}
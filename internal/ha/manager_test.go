@@ -0,0 +1,245 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ha
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestHAManager(t *testing.T) (*haManager, *databasemocks.Plugin, *bool) {
+	coreconfig.Reset()
+	config.Set(coreconfig.HANodeID, "node1")
+	config.Set(coreconfig.HALeaseDuration, "1s")
+	config.Set(coreconfig.HARenewInterval, "10ms")
+	mdi := &databasemocks.Plugin{}
+	demoted := false
+	hm, err := NewHAManager(context.Background(), NewDatabaseLockProvider(mdi), func() { demoted = true })
+	assert.NoError(t, err)
+	t.Cleanup(func() { mdi.AssertExpectations(t) })
+	return hm.(*haManager), mdi, &demoted
+}
+
+func TestNewHAManagerMissingDeps(t *testing.T) {
+	_, err := NewHAManager(context.Background(), nil, nil)
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestNewHAManagerBadRenewInterval(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.HALeaseDuration, "10s")
+	config.Set(coreconfig.HARenewInterval, "10s")
+	_, err := NewHAManager(context.Background(), NewDatabaseLockProvider(&databasemocks.Plugin{}), func() {})
+	assert.Regexp(t, "FF10635", err)
+}
+
+func TestNewHAManagerDefaultNodeID(t *testing.T) {
+	coreconfig.Reset()
+	hm, err := NewHAManager(context.Background(), NewDatabaseLockProvider(&databasemocks.Plugin{}), func() {})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hm.(*haManager).nodeID)
+	assert.Equal(t, "firefly", hm.(*haManager).lockName)
+}
+
+func TestStartActiveStopRelease(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.MatchedBy(func(lock *core.LeaderLock) bool {
+		return lock.Name == "firefly" && lock.Holder == "node1"
+	}), mock.Anything).Return(true, nil)
+	mdi.On("ReleaseLeaderLock", mock.Anything, "firefly", "node1").Return(fmt.Errorf("pop"))
+
+	assert.False(t, hm.IsActive())
+	hm.Start()
+	err := hm.WaitActive(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, hm.IsActive())
+
+	status, err := hm.GetStatus(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, core.HAModeActive, status.Mode)
+	assert.Equal(t, "node1", status.Leader)
+	assert.NotNil(t, status.LeaseExpiry)
+
+	hm.WaitStop()
+	assert.False(t, *demoted)
+}
+
+func TestStopNotStarted(t *testing.T) {
+	hm, _, _ := newTestHAManager(t)
+	hm.WaitStop()
+}
+
+func TestPassiveStatus(t *testing.T) {
+	hm, mdi, _ := newTestHAManager(t)
+	expires := fftypes.Now()
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	mdi.On("GetLeaderLock", mock.Anything, "firefly").Return(&core.LeaderLock{
+		Name:    "firefly",
+		Holder:  "node2",
+		Expires: expires,
+	}, nil)
+
+	hm.Start()
+	defer hm.WaitStop()
+	assert.True(t, hm.elect())
+	assert.False(t, hm.IsActive())
+
+	status, err := hm.GetStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, core.HAModePassive, status.Mode)
+	assert.Equal(t, "node2", status.Leader)
+	assert.Equal(t, expires, status.LeaseExpiry)
+}
+
+func TestPassiveStatusNoLock(t *testing.T) {
+	hm, mdi, _ := newTestHAManager(t)
+	mdi.On("GetLeaderLock", mock.Anything, "firefly").Return(nil, nil)
+
+	status, err := hm.GetStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, core.HAModePassive, status.Mode)
+	assert.Empty(t, status.Leader)
+}
+
+func TestPassiveStatusFail(t *testing.T) {
+	hm, mdi, _ := newTestHAManager(t)
+	mdi.On("GetLeaderLock", mock.Anything, "firefly").Return(nil, fmt.Errorf("pop"))
+
+	_, err := hm.GetStatus(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestWaitActiveCancelled(t *testing.T) {
+	hm, _, _ := newTestHAManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := hm.WaitActive(ctx)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestElectAcquireFailPassive(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	assert.True(t, hm.elect())
+	assert.False(t, hm.IsActive())
+	assert.False(t, *demoted)
+}
+
+func TestElectRenewFailWithinLease(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop")).Once()
+
+	assert.True(t, hm.elect())
+	assert.True(t, hm.IsActive())
+	assert.True(t, hm.elect())
+	assert.True(t, hm.IsActive())
+	assert.False(t, *demoted)
+}
+
+func TestElectRenewFailLeaseExpiring(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop")).Once()
+
+	assert.True(t, hm.elect())
+	expired := fftypes.FFTime(time.Now())
+	hm.leaseExpiry = &expired
+	assert.False(t, hm.elect())
+	assert.False(t, hm.IsActive())
+	assert.True(t, *demoted)
+}
+
+func TestElectLockTakenDemotes(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+
+	hm.Start()
+	assert.NoError(t, hm.WaitActive(context.Background()))
+	<-hm.done
+	assert.False(t, hm.IsActive())
+	assert.True(t, *demoted)
+	hm.WaitStop()
+}
+
+func TestElectRenewBoundedByLease(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// The lock provider hangs until the deadline of the lease
+		ctx := args[0].(context.Context)
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, time.Time(*hm.leaseExpiry), deadline)
+		<-ctx.Done()
+	}).Return(false, context.DeadlineExceeded).Once()
+
+	assert.True(t, hm.elect())
+	hm.mux.Lock()
+	expiring := fftypes.FFTime(time.Now().Add(50 * time.Millisecond))
+	hm.leaseExpiry = &expiring
+	hm.expiryTimer.Reset(50 * time.Millisecond)
+	hm.mux.Unlock()
+	assert.False(t, hm.elect())
+	assert.False(t, hm.IsActive())
+	assert.True(t, *demoted)
+}
+
+func TestLeaseExpiryDemotes(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	assert.True(t, hm.elect())
+	hm.checkLeaseExpiry()
+	assert.True(t, hm.IsActive())
+
+	hm.mux.Lock()
+	expired := fftypes.FFTime(time.Now())
+	hm.leaseExpiry = &expired
+	hm.mux.Unlock()
+	hm.checkLeaseExpiry()
+	assert.False(t, hm.IsActive())
+	assert.True(t, *demoted)
+	hm.WaitStop()
+}
+
+func TestElectRenewExtendsLease(t *testing.T) {
+	hm, mdi, demoted := newTestHAManager(t)
+	mdi.On("AcquireLeaderLock", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Twice()
+
+	assert.True(t, hm.elect())
+	firstExpiry := hm.leaseExpiry
+	time.Sleep(time.Millisecond)
+	assert.True(t, hm.elect())
+	assert.True(t, time.Time(*hm.leaseExpiry).After(time.Time(*firstExpiry)))
+	assert.True(t, hm.IsActive())
+	assert.False(t, *demoted)
+	hm.WaitStop()
+}
@@ -55,6 +55,7 @@ type Manager interface {
 	GetRootOrg(ctx context.Context) (org *core.Identity, err error)
 	VerifyIdentityChain(ctx context.Context, identity *core.Identity) (immediateParent *core.Identity, retryable bool, err error)
 	ValidateNodeOwner(ctx context.Context, node *core.Identity, identity *core.Identity) (valid bool, err error)
+	InvalidateVerifierCache(ctx context.Context, verifierRef *core.VerifierRef)
 }
 
 type identityManager struct {
@@ -278,6 +279,7 @@ func (im *identityManager) firstVerifierForIdentity(ctx context.Context, vType c
 	filter := fb.And(
 		fb.Eq("type", vType),
 		fb.Eq("identity", identity.ID),
+		fb.Eq("deprecated", nil),
 	)
 	verifiers, _, err := im.database.GetVerifiers(ctx, identity.Namespace, filter)
 	if err != nil {
@@ -410,7 +412,29 @@ func (im *identityManager) ResolveIdentitySigner(ctx context.Context, identity *
 		return nil, i18n.NewError(ctx, coremsgs.MsgParentIdentityMissingClaim, identity.DID, identity.ID)
 	}
 	// Return the signing identity from that claim
-	return &msg.Header.SignerRef, nil
+	signer = &msg.Header.SignerRef
+	if im.blockchain != nil {
+		// The key that signed the claim might since have been deprecated, in which case we use an active key of the same identity
+		verifier, err := im.database.GetVerifierByValue(ctx, im.blockchain.VerifierType(), im.namespace, signer.Key)
+		if err != nil {
+			return nil, err
+		}
+		if verifier != nil && verifier.Deprecated != nil {
+			signerIdentity, err := im.CachedIdentityLookupByID(ctx, verifier.Identity)
+			if err != nil {
+				return nil, err
+			}
+			if signerIdentity == nil {
+				return nil, i18n.NewError(ctx, i18n.MsgEmptyMemberIdentity, verifier.Identity)
+			}
+			activeVerifier, _, err := im.firstVerifierForIdentity(ctx, verifier.Type, signerIdentity)
+			if err != nil {
+				return nil, err
+			}
+			signer.Key = activeVerifier.Value
+		}
+	}
+	return signer, nil
 }
 
 func (im *identityManager) validateParentType(ctx context.Context, child *core.Identity, parent *core.Identity) error {
@@ -455,11 +479,24 @@ func (im *identityManager) cachedIdentityLookupByVerifierRef(ctx context.Context
 	if identity == nil {
 		return nil, i18n.NewError(ctx, i18n.MsgEmptyMemberIdentity, verifier.Identity)
 	}
+	if verifier.Deprecated != nil {
+		// A deprecated verifier can no longer be used to sign on behalf of the identity
+		log.L(ctx).Debugf("Verifier %s:%s of identity %s was deprecated at %s", verifierRef.Type, verifierRef.Value, identity.DID, verifier.Deprecated)
+		return nil, nil
+	}
 	// Cache the result
 	im.identityCache.Set(cacheKey, identity)
 	return identity, nil
 }
 
+// InvalidateVerifierCache removes any cached identity lookup for a verifier, such as when the verifier is deprecated
+func (im *identityManager) InvalidateVerifierCache(ctx context.Context, verifierRef *core.VerifierRef) {
+	cacheKey := fmt.Sprintf("ns=%s,type=%s,verifier=%s", im.namespace, verifierRef.Type, verifierRef.Value)
+	if im.identityCache.Delete(cacheKey) {
+		log.L(ctx).Debugf("Invalidated cached identity for verifier %s:%s", verifierRef.Type, verifierRef.Value)
+	}
+}
+
 func (im *identityManager) cachedIdentityLookup(ctx context.Context, namespace, didLookupStr string) (identity *core.Identity, retryable bool, err error) {
 	// Use an LRU cache for the author identity, as it's likely for the same identity to be re-used over and over
 	cacheKey := fmt.Sprintf("ns=%s,did=%s", namespace, didLookupStr)
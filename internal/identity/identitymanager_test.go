@@ -20,9 +20,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
@@ -952,6 +954,82 @@ func TestCachedIdentityLookupByVerifierRefNotFound(t *testing.T) {
 
 }
 
+func TestCachedIdentityLookupByVerifierRefDeprecated(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	id := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "org1",
+			Type:      core.IdentityTypeOrg,
+		},
+	}
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").
+		Return((&core.Verifier{
+			Identity:  id.ID,
+			Namespace: "ns1",
+			VerifierRef: core.VerifierRef{
+				Type:  core.VerifierTypeEthAddress,
+				Value: "0x12345",
+			},
+			Deprecated: fftypes.Now(),
+		}).Seal(), nil)
+	mdi.On("GetIdentityByID", ctx, "ns1", id.ID).Return(id, nil)
+
+	identity, err := im.cachedIdentityLookupByVerifierRef(ctx, "ns1", &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x12345",
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, identity)
+
+	mdi.AssertExpectations(t)
+
+}
+
+func TestInvalidateVerifierCache(t *testing.T) {
+
+	ctx, im := newTestIdentityManager(t)
+
+	id := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "org1",
+			Type:      core.IdentityTypeOrg,
+		},
+	}
+	verifierRef := &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x12345",
+	}
+	mdi := im.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").
+		Return((&core.Verifier{
+			Identity:    id.ID,
+			Namespace:   "ns1",
+			VerifierRef: *verifierRef,
+		}).Seal(), nil).Twice()
+	mdi.On("GetIdentityByID", ctx, "ns1", id.ID).Return(id, nil).Twice()
+
+	_, err := im.cachedIdentityLookupByVerifierRef(ctx, "ns1", verifierRef)
+	assert.NoError(t, err)
+
+	im.InvalidateVerifierCache(ctx, verifierRef)
+	im.InvalidateVerifierCache(ctx, verifierRef) // no-op when not cached
+
+	_, err = im.cachedIdentityLookupByVerifierRef(ctx, "ns1", verifierRef)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+
+}
+
 func TestCachedIdentityLookupMustExistCaching(t *testing.T) {
 
 	ctx, im := newTestIdentityManager(t)
@@ -1345,6 +1423,7 @@ func TestResolveIdentitySignerOk(t *testing.T) {
 			},
 		},
 	}, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(nil, nil)
 
 	signerRef, err := im.ResolveIdentitySigner(ctx, &core.Identity{
 		IdentityBase: core.IdentityBase{
@@ -1364,6 +1443,125 @@ func TestResolveIdentitySignerOk(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func testDeprecatedClaimSigner(ctx context.Context, im *identityManager) *core.Identity {
+	mdi := im.database.(*databasemocks.Plugin)
+	signer := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			DID:       "did:firefly:org/org1",
+			Namespace: "ns1",
+			Name:      "org1",
+			Type:      core.IdentityTypeOrg,
+		},
+		Messages: core.IdentityMessages{
+			Claim: fftypes.NewUUID(),
+		},
+	}
+	mdi.On("GetMessageByID", ctx, "ns1", signer.Messages.Claim).Return(&core.Message{
+		Header: core.MessageHeader{
+			SignerRef: core.SignerRef{
+				Author: signer.DID,
+				Key:    "0x12345",
+			},
+		},
+	}, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(&core.Verifier{
+		Identity:    signer.ID,
+		Namespace:   "ns1",
+		VerifierRef: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x12345"},
+		Deprecated:  fftypes.Now(),
+	}, nil)
+	return signer
+}
+
+func TestResolveIdentitySignerDeprecatedKey(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	signer := testDeprecatedClaimSigner(ctx, im)
+	mdi.On("GetIdentityByID", ctx, "ns1", signer.ID).Return(signer, nil)
+	mdi.On("GetVerifiers", ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return strings.Contains(fi.String(), "deprecated == null")
+	})).Return([]*core.Verifier{
+		{VerifierRef: core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x67890"}},
+	}, nil, nil)
+
+	signerRef, err := im.ResolveIdentitySigner(ctx, signer)
+	assert.NoError(t, err)
+	assert.Equal(t, signer.DID, signerRef.Author)
+	assert.Equal(t, "0x67890", signerRef.Key)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveIdentitySignerDeprecatedKeyNoActiveKey(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	signer := testDeprecatedClaimSigner(ctx, im)
+	mdi.On("GetIdentityByID", ctx, "ns1", signer.ID).Return(signer, nil)
+	mdi.On("GetVerifiers", ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+
+	_, err := im.ResolveIdentitySigner(ctx, signer)
+	assert.Regexp(t, "FF10353", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveIdentitySignerDeprecatedKeyIdentityNotFound(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	signer := testDeprecatedClaimSigner(ctx, im)
+	mdi.On("GetIdentityByID", ctx, "ns1", signer.ID).Return(nil, nil)
+	mmp := im.multiparty.(*multipartymocks.Manager)
+	mmp.On("GetNetworkVersion").Return(2)
+
+	_, err := im.ResolveIdentitySigner(ctx, signer)
+	assert.Regexp(t, "FF00116", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveIdentitySignerDeprecatedKeyIdentityFail(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	signer := testDeprecatedClaimSigner(ctx, im)
+	mdi.On("GetIdentityByID", ctx, "ns1", signer.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := im.ResolveIdentitySigner(ctx, signer)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveIdentitySignerGetVerifierFail(t *testing.T) {
+	ctx, im := newTestIdentityManager(t)
+	mdi := im.database.(*databasemocks.Plugin)
+
+	msgID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", msgID).Return(&core.Message{
+		Header: core.MessageHeader{
+			SignerRef: core.SignerRef{
+				Key: "0x12345",
+			},
+		},
+	}, nil)
+	mdi.On("GetVerifierByValue", ctx, core.VerifierTypeEthAddress, "ns1", "0x12345").Return(nil, fmt.Errorf("pop"))
+
+	_, err := im.ResolveIdentitySigner(ctx, &core.Identity{
+		Messages: core.IdentityMessages{
+			Claim: msgID,
+		},
+	})
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestResolveIdentitySignerFail(t *testing.T) {
 	ctx, im := newTestIdentityManager(t)
 	mdi := im.database.(*databasemocks.Plugin)
@@ -0,0 +1,88 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyprovider
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/encryption"
+)
+
+var keysConfig = config.RootArray("broadcast.encryption.keys")
+
+// configKeyProvider is the default key provider, which holds a static set of keys from configuration.
+// Each topic is encrypted with at most one key, and keys with no topics are only used for decryption.
+type configKeyProvider struct {
+	keys      map[string][]byte
+	topicKeys map[string]string
+}
+
+func InitConfig() {
+	keysConfig.AddKnownKey(coreconfig.BroadcastEncryptionKeyID)
+	keysConfig.AddKnownKey(coreconfig.BroadcastEncryptionKeyValue)
+	keysConfig.AddKnownKey(coreconfig.BroadcastEncryptionKeyTopics)
+}
+
+// NewConfigKeyProvider loads the configured keys, returning nil if there are none so that broadcast data is not encrypted
+func NewConfigKeyProvider(ctx context.Context) (encryption.KeyProvider, error) {
+	if keysConfig.ArraySize() == 0 {
+		return nil, nil
+	}
+	kp := &configKeyProvider{
+		keys:      make(map[string][]byte),
+		topicKeys: make(map[string]string),
+	}
+	for i := 0; i < keysConfig.ArraySize(); i++ {
+		conf := keysConfig.ArrayEntry(i)
+		keyID := conf.GetString(coreconfig.BroadcastEncryptionKeyID)
+		if keyID == "" || kp.keys[keyID] != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEncryptionKey, keyID)
+		}
+		key, err := hex.DecodeString(strings.TrimPrefix(conf.GetString(coreconfig.BroadcastEncryptionKeyValue), "0x"))
+		if err != nil || len(key) != 32 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEncryptionKey, keyID)
+		}
+		kp.keys[keyID] = key
+		for _, topic := range conf.GetStringSlice(coreconfig.BroadcastEncryptionKeyTopics) {
+			if existing, ok := kp.topicKeys[topic]; ok {
+				return nil, i18n.NewError(ctx, coremsgs.MsgEncryptionTopicConflict, topic, existing, keyID)
+			}
+			kp.topicKeys[topic] = keyID
+		}
+	}
+	return kp, nil
+}
+
+func (kp *configKeyProvider) KeyForMessage(ctx context.Context, header *core.MessageHeader) (string, error) {
+	for _, topic := range header.Topics {
+		if keyID, ok := kp.topicKeys[topic]; ok {
+			return keyID, nil
+		}
+	}
+	return "", nil
+}
+
+func (kp *configKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	return kp.keys[keyID], nil
+}
@@ -0,0 +1,128 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyprovider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKey1 = "0x000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	testKey2 = "202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f"
+)
+
+func setTestKeys(keysYAML string) {
+	coreconfig.Reset()
+	InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+broadcast:
+  encryption:
+    keys:
+` + keysYAML))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestNewConfigKeyProviderNoKeys(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+
+	kp, err := NewConfigKeyProvider(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, kp)
+}
+
+func TestConfigKeyProviderOk(t *testing.T) {
+	setTestKeys(`
+    - id: key1
+      key: ` + testKey1 + `
+      topics: [topic1, topic2]
+    - id: key2
+      key: ` + testKey2)
+
+	ctx := context.Background()
+	kp, err := NewConfigKeyProvider(ctx)
+	assert.NoError(t, err)
+
+	keyID, err := kp.KeyForMessage(ctx, &core.MessageHeader{Topics: fftypes.FFStringArray{"other", "topic2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "key1", keyID)
+
+	keyID, err = kp.KeyForMessage(ctx, &core.MessageHeader{Topics: fftypes.FFStringArray{"other"}})
+	assert.NoError(t, err)
+	assert.Empty(t, keyID)
+
+	key, err := kp.Key(ctx, "key2")
+	assert.NoError(t, err)
+	assert.Len(t, key, 32)
+	assert.Equal(t, byte(0x20), key[0])
+
+	key, err = kp.Key(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, key)
+}
+
+func TestNewConfigKeyProviderMissingID(t *testing.T) {
+	setTestKeys(`
+    - key: ` + testKey1)
+
+	_, err := NewConfigKeyProvider(context.Background())
+	assert.Regexp(t, "FF10550", err)
+}
+
+func TestNewConfigKeyProviderDuplicateID(t *testing.T) {
+	setTestKeys(`
+    - id: key1
+      key: ` + testKey1 + `
+    - id: key1
+      key: ` + testKey2)
+
+	_, err := NewConfigKeyProvider(context.Background())
+	assert.Regexp(t, "FF10550.*key1", err)
+}
+
+func TestNewConfigKeyProviderBadKey(t *testing.T) {
+	setTestKeys(`
+    - id: key1
+      key: 0x0001`)
+
+	_, err := NewConfigKeyProvider(context.Background())
+	assert.Regexp(t, "FF10550.*key1", err)
+}
+
+func TestNewConfigKeyProviderTopicConflict(t *testing.T) {
+	setTestKeys(`
+    - id: key1
+      key: ` + testKey1 + `
+      topics: [topic1]
+    - id: key2
+      key: ` + testKey2 + `
+      topics: [topic1]`)
+
+	_, err := NewConfigKeyProvider(context.Background())
+	assert.Regexp(t, "FF10553.*topic1", err)
+}
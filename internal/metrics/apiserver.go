@@ -0,0 +1,40 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var APIRateLimitedCounter *prometheus.CounterVec
+
+// APIRateLimitedCounterName is the prometheus metric for tracking API requests rejected by a rate limit
+var APIRateLimitedCounterName = "ff_api_rate_limited_total"
+
+var RouteLabelName = "route"
+var LimitLabelName = "limit"
+
+func InitAPIServerMetrics() {
+	APIRateLimitedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: APIRateLimitedCounterName,
+		Help: "Number of API requests rejected by a rate limit, by namespace, route and the limit that was exceeded",
+	}, []string{NamespaceLabelName, RouteLabelName, LimitLabelName})
+}
+
+func RegisterAPIServerMetrics() {
+	registry.MustRegister(APIRateLimitedCounter)
+}
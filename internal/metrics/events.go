@@ -0,0 +1,94 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var EventEnrichmentCacheCounter *prometheus.CounterVec
+var SubscriptionOffsetCommitFailuresCounter *prometheus.CounterVec
+var EventDispatchedCounter *prometheus.CounterVec
+var EventAckLatencyHistogram *prometheus.HistogramVec
+var WebhookResponsesCounter *prometheus.CounterVec
+var SubscriptionLagGauge *prometheus.GaugeVec
+
+// EventEnrichmentCacheCounterName is the prometheus metric for tracking hits and misses on the event enrichment cache
+var EventEnrichmentCacheCounterName = "ff_event_enrichment_cache_total"
+
+// SubscriptionOffsetCommitFailuresCounterName is the prometheus metric for tracking failed attempts to commit subscription offsets
+var SubscriptionOffsetCommitFailuresCounterName = "ff_subscription_offset_commit_failures_total"
+
+// EventDispatchedCounterName is the prometheus metric for tracking events dispatched to subscriptions
+var EventDispatchedCounterName = "ff_event_dispatched_total"
+
+// EventAckLatencyHistogramName is the prometheus metric for tracking the time between dispatching an event and receiving its ack
+var EventAckLatencyHistogramName = "ff_event_ack_latency_seconds"
+
+// WebhookResponsesCounterName is the prometheus metric for tracking webhook responses, by status code
+var WebhookResponsesCounterName = "ff_webhook_responses_total"
+
+// SubscriptionLagGaugeName is the prometheus metric for tracking how many events a subscription is behind the head
+var SubscriptionLagGaugeName = "ff_subscription_lag_events"
+
+var CacheResultLabelName = "result"
+var NamespaceLabelName = "ns"
+var SubscriptionLabelName = "subscription"
+var TransportLabelName = "transport"
+var StatusLabelName = "status"
+
+const (
+	CacheResultHit  = "hit"
+	CacheResultMiss = "miss"
+)
+
+func InitEventMetrics() {
+	EventEnrichmentCacheCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: EventEnrichmentCacheCounterName,
+		Help: "Number of event enrichment cache lookups, by result",
+	}, []string{CacheResultLabelName})
+	SubscriptionOffsetCommitFailuresCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: SubscriptionOffsetCommitFailuresCounterName,
+		Help: "Number of failed attempts to commit subscription offsets, by subscription",
+	}, []string{NamespaceLabelName, SubscriptionLabelName})
+	EventDispatchedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: EventDispatchedCounterName,
+		Help: "Number of events dispatched to subscriptions, by subscription and transport",
+	}, []string{NamespaceLabelName, SubscriptionLabelName, TransportLabelName})
+	EventAckLatencyHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    EventAckLatencyHistogramName,
+		Help:    "Time between dispatching an event and receiving its acknowledgement, by subscription and transport",
+		Buckets: prometheus.DefBuckets,
+	}, []string{NamespaceLabelName, SubscriptionLabelName, TransportLabelName})
+	WebhookResponsesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: WebhookResponsesCounterName,
+		Help: "Number of webhook responses, by subscription and HTTP status code",
+	}, []string{NamespaceLabelName, SubscriptionLabelName, StatusLabelName})
+	SubscriptionLagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: SubscriptionLagGaugeName,
+		Help: "Number of events a subscription is behind the latest event, by subscription",
+	}, []string{NamespaceLabelName, SubscriptionLabelName})
+}
+
+func RegisterEventMetrics() {
+	registry.MustRegister(EventEnrichmentCacheCounter)
+	registry.MustRegister(SubscriptionOffsetCommitFailuresCounter)
+	registry.MustRegister(EventDispatchedCounter)
+	registry.MustRegister(EventAckLatencyHistogram)
+	registry.MustRegister(WebhookResponsesCounter)
+	registry.MustRegister(SubscriptionLagGauge)
+}
@@ -39,6 +39,13 @@ type Manager interface {
 	BlockchainTransaction(location, methodName string)
 	BlockchainQuery(location, methodName string)
 	BlockchainEvent(location, signature string)
+	EventEnrichmentCache(hit bool)
+	SubscriptionOffsetCommitFailure(namespace, subscription string)
+	EventDispatched(namespace, subscription, transport string)
+	EventAcknowledged(namespace, subscription, transport string, latency time.Duration)
+	WebhookResponse(namespace, subscription, status string)
+	SubscriptionLag(namespace, subscription string, lag int64)
+	APIRateLimited(namespace, route, limit string)
 	AddTime(id string)
 	GetTime(id string) time.Time
 	DeleteTime(id string)
@@ -162,6 +169,38 @@ func (mm *metricsManager) BlockchainEvent(location, signature string) {
 	BlockchainEventsCounter.WithLabelValues(location, signature).Inc()
 }
 
+func (mm *metricsManager) EventEnrichmentCache(hit bool) {
+	result := CacheResultMiss
+	if hit {
+		result = CacheResultHit
+	}
+	EventEnrichmentCacheCounter.WithLabelValues(result).Inc()
+}
+
+func (mm *metricsManager) SubscriptionOffsetCommitFailure(namespace, subscription string) {
+	SubscriptionOffsetCommitFailuresCounter.WithLabelValues(namespace, subscription).Inc()
+}
+
+func (mm *metricsManager) EventDispatched(namespace, subscription, transport string) {
+	EventDispatchedCounter.WithLabelValues(namespace, subscription, transport).Inc()
+}
+
+func (mm *metricsManager) EventAcknowledged(namespace, subscription, transport string, latency time.Duration) {
+	EventAckLatencyHistogram.WithLabelValues(namespace, subscription, transport).Observe(latency.Seconds())
+}
+
+func (mm *metricsManager) WebhookResponse(namespace, subscription, status string) {
+	WebhookResponsesCounter.WithLabelValues(namespace, subscription, status).Inc()
+}
+
+func (mm *metricsManager) SubscriptionLag(namespace, subscription string, lag int64) {
+	SubscriptionLagGauge.WithLabelValues(namespace, subscription).Set(float64(lag))
+}
+
+func (mm *metricsManager) APIRateLimited(namespace, route, limit string) {
+	APIRateLimitedCounter.WithLabelValues(namespace, route, limit).Inc()
+}
+
 func (mm *metricsManager) AddTime(id string) {
 	mutex.Lock()
 	mm.timeMap[id] = time.Now()
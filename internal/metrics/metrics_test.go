@@ -215,6 +215,72 @@ func TestBlockchainEvents(t *testing.T) {
 	assert.Equal(t, float64(1), v)
 }
 
+func TestEventEnrichmentCache(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.EventEnrichmentCache(true)
+	mm.EventEnrichmentCache(false)
+	mm.EventEnrichmentCache(false)
+	m, err := EventEnrichmentCacheCounter.GetMetricWith(prometheus.Labels{CacheResultLabelName: CacheResultHit})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m))
+	m, err = EventEnrichmentCacheCounter.GetMetricWith(prometheus.Labels{CacheResultLabelName: CacheResultMiss})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m))
+}
+
+func TestSubscriptionOffsetCommitFailure(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.SubscriptionOffsetCommitFailure("ns1", "sub1")
+	mm.SubscriptionOffsetCommitFailure("ns1", "sub1")
+	m, err := SubscriptionOffsetCommitFailuresCounter.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", SubscriptionLabelName: "sub1"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m))
+}
+
+func TestEventDispatchedAndAcknowledged(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.EventDispatched("ns1", "sub1", "webhooks")
+	mm.EventDispatched("ns1", "sub1", "webhooks")
+	mm.EventAcknowledged("ns1", "sub1", "webhooks", 50*time.Millisecond)
+	m, err := EventDispatchedCounter.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", SubscriptionLabelName: "sub1", TransportLabelName: "webhooks"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m))
+	assert.Equal(t, 1, testutil.CollectAndCount(EventAckLatencyHistogram))
+}
+
+func TestWebhookResponse(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.WebhookResponse("ns1", "sub1", "200")
+	mm.WebhookResponse("ns1", "sub1", "500")
+	mm.WebhookResponse("ns1", "sub1", "500")
+	m, err := WebhookResponsesCounter.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", SubscriptionLabelName: "sub1", StatusLabelName: "500"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m))
+}
+
+func TestSubscriptionLag(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.SubscriptionLag("ns1", "sub1", 10)
+	mm.SubscriptionLag("ns1", "sub1", 3)
+	m, err := SubscriptionLagGauge.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", SubscriptionLabelName: "sub1"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), testutil.ToFloat64(m))
+}
+
+func TestAPIRateLimited(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.APIRateLimited("ns1", "postTokenMint", "route")
+	m, err := APIRateLimitedCounter.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", RouteLabelName: "postTokenMint", LimitLabelName: "route"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m))
+}
+
 func TestIsMetricsEnabledTrue(t *testing.T) {
 	mm, cancel := newTestMetricsManager(t)
 	defer cancel()
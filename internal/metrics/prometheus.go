@@ -88,6 +88,8 @@ func initMetricsCollectors() {
 	InitTokenBurnMetrics()
 	InitBatchPinMetrics()
 	InitBlockchainMetrics()
+	InitEventMetrics()
+	InitAPIServerMetrics()
 }
 
 func registerMetricsCollectors() {
@@ -101,4 +103,6 @@ func registerMetricsCollectors() {
 	RegisterTokenTransferMetrics()
 	RegisterTokenBurnMetrics()
 	RegisterBlockchainMetrics()
+	RegisterEventMetrics()
+	RegisterAPIServerMetrics()
 }
@@ -17,15 +17,18 @@
 package namespace
 
 import (
-	"github.com/hyperledger/firefly-common/pkg/auth/authfactory"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly/internal/assets"
+	"github.com/hyperledger/firefly/internal/auth/authfactory"
 	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/database/difactory"
 	"github.com/hyperledger/firefly/internal/dataexchange/dxfactory"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
+	"github.com/hyperledger/firefly/internal/keyprovider"
+	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -57,6 +60,8 @@ func InitConfig() {
 	namespacePredefined.AddKnownKey(coreconfig.NamespacePlugins)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceDefaultKey)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceAssetKeyNormalization)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceRetentionWindow)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceRetentionArchive, false)
 
 	multipartyConf := namespacePredefined.SubSection(coreconfig.NamespaceMultiparty)
 	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyEnabled)
@@ -72,6 +77,14 @@ func InitConfig() {
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractLocation)
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractOptions)
 
+	poolConnectors := namespacePredefined.SubArray(coreconfig.NamespaceAssetPoolConnectors)
+	poolConnectors.AddKnownKey(coreconfig.NamespaceAssetPoolConnectorPool)
+	poolConnectors.AddKnownKey(coreconfig.NamespaceAssetPoolConnectorConnector)
+
+	signingSecrets := namespacePredefined.SubArray(coreconfig.NamespaceSigningSecrets)
+	signingSecrets.AddKnownKey(coreconfig.NamespaceSigningSecretName)
+	signingSecrets.AddKnownKey(coreconfig.NamespaceSigningSecretValue)
+
 	tlsConfigs := namespacePredefined.SubArray(coreconfig.NamespaceTLSConfigs)
 	tlsConfigs.AddKnownKey(coreconfig.NamespaceTLSConfigName)
 	tlsConf := tlsConfigs.SubSection(coreconfig.NamespaceTLSConfigTLSSection)
@@ -85,4 +98,7 @@ func InitConfig() {
 	tifactory.InitConfig(tokensConfig)
 	authfactory.InitConfigArray(authConfig)
 	eifactory.InitConfig(eventsConfig)
+	operations.InitConfig()
+	assets.InitConfig()
+	keyprovider.InitConfig()
 }
@@ -20,11 +20,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/orchestrator"
 	"github.com/spf13/viper"
 )
@@ -45,6 +48,8 @@ func (nm *namespaceManager) startConfigListener() error {
 
 func (nm *namespaceManager) configFileChanged() {
 	log.L(nm.ctx).Infof("Detected configuration file reload")
+	nm.reloadMux.Lock()
+	defer nm.reloadMux.Unlock()
 
 	// Because of the things we do to make defaults work with arrays, we have to reset
 	// the config when it changes and re-read it.
@@ -57,10 +62,125 @@ func (nm *namespaceManager) configFileChanged() {
 		return
 	}
 
-	nm.configReloaded(nm.ctx)
+	_ = nm.configReloaded(nm.ctx)
 }
 
-func (nm *namespaceManager) configReloaded(ctx context.Context) {
+// ReloadConfig re-reads the configuration on demand, and restarts any namespaces and plugins whose configuration
+// has changed, in the same way as when a change to the config file is detected
+func (nm *namespaceManager) ReloadConfig(ctx context.Context) error {
+	log.L(ctx).Infof("Configuration reload requested")
+	nm.reloadMux.Lock()
+	defer nm.reloadMux.Unlock()
+
+	if err := nm.reloadConfig(); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgConfigReloadFailed, err)
+	}
+	return nm.configReloaded(ctx)
+}
+
+// ResetPlugin restarts the connection of a single connector plugin, by stopping the namespaces that use it,
+// initializing a new instance of the plugin, and starting those namespaces again. Other namespaces are unaffected.
+// The configuration is re-read first, so the new instance picks up any rotated URLs or credentials. Only the
+// configuration of the named plugin is applied - any other changes wait for the next configuration reload.
+func (nm *namespaceManager) ResetPlugin(ctx context.Context, name string) error {
+	nm.reloadMux.Lock()
+	defer nm.reloadMux.Unlock()
+
+	nm.nsMux.Lock()
+	p := nm.plugins[name]
+	nm.nsMux.Unlock()
+	if p == nil {
+		return i18n.NewError(ctx, coremsgs.MsgPluginNotFound, name)
+	}
+	switch p.category {
+	case pluginCategoryBlockchain, pluginCategoryDataexchange, pluginCategoryTokens:
+	default:
+		return i18n.NewError(ctx, coremsgs.MsgPluginResetNotSupported, name, p.category)
+	}
+	log.L(ctx).Infof("Reset requested for %s plugin '%s'", p.category, name)
+	if err := nm.reloadConfig(); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgConfigReloadFailed, err)
+	}
+	return nm.resetPlugin(ctx, name)
+}
+
+// resetPlugin replaces the running instance of a plugin with a new one, built from the current configuration.
+// Every other plugin keeps its running instance, and the namespaces that use the plugin are restarted with
+// the same configuration they are running with.
+func (nm *namespaceManager) resetPlugin(ctx context.Context, name string) error {
+	rawConfig := nm.dumpRootConfig()
+	allPluginsInNewConf, err := nm.loadPlugins(ctx, rawConfig)
+	if err != nil {
+		return err
+	}
+	newPlugin := allPluginsInNewConf[name]
+	if newPlugin == nil {
+		return i18n.NewError(ctx, coremsgs.MsgPluginNotFound, name)
+	}
+
+	nm.nsMux.Lock()
+	availablePlugins := make(map[string]*plugin, len(nm.plugins))
+	for pluginName, existingPlugin := range nm.plugins {
+		availablePlugins[pluginName] = existingPlugin
+	}
+	availableNS := make(map[string]*namespace, len(nm.namespaces))
+	for nsName, existingNS := range nm.namespaces {
+		availableNS[nsName] = existingNS
+	}
+	nm.nsMux.Unlock()
+	availablePlugins[name] = newPlugin
+
+	allNewNamespaces, err := nm.loadNamespaces(ctx, rawConfig, availablePlugins)
+	if err != nil {
+		newPlugin.cancelCtx()
+		return err
+	}
+	updatedNamespaces := make(map[string]*namespace)
+	for nsName, existingNS := range availableNS {
+		if !slices.Contains(existingNS.pluginNames, name) {
+			continue
+		}
+		newNS := allNewNamespaces[nsName]
+		if newNS == nil || !newNS.configHash.Equals(existingNS.configHash) {
+			newPlugin.cancelCtx()
+			return i18n.NewError(ctx, coremsgs.MsgPluginResetNamespaceChanged, name, nsName)
+		}
+		availableNS[nsName] = newNS
+		updatedNamespaces[nsName] = newNS
+	}
+
+	// Initialize the new instance before anything is stopped, so the running instance is left in place if it fails
+	if err := nm.initPlugin(name, newPlugin); err != nil {
+		log.L(ctx).Errorf("Failed to initialize new instance of plugin '%s' - the existing instance is still running: %s", name, err)
+		newPlugin.cancelCtx()
+		return err
+	}
+
+	nm.nsMux.Lock()
+	defer nm.nsMux.Unlock()
+
+	for nsName := range updatedNamespaces {
+		existingNS := nm.namespaces[nsName]
+		log.L(ctx).Debugf("Stopping namespace '%s' to reset plugin '%s'. Loaded at %s", nsName, name, existingNS.loadTime)
+		nm.stopNamespace(ctx, existingNS)
+		nm.cacheManager.ResetCachesForNamespace(nsName)
+	}
+	nm.stopDefunctPlugins(ctx, map[string]*plugin{name: nm.plugins[name]})
+
+	nm.plugins = availablePlugins
+	nm.namespaces = availableNS
+
+	// The old instance is stopped at this point, so a failure to start leaves the namespaces without the plugin
+	if err = nm.startNamespacesAndPlugins(updatedNamespaces, map[string]*plugin{name: newPlugin}); err != nil {
+		log.L(ctx).Errorf("Failed to start namespaces after resetting plugin '%s': %s", name, err)
+		nm.cancelCtx() // stop the world
+		return err
+	}
+	return nil
+}
+
+// configReloaded applies the current configuration, restarting the namespaces and plugins that have changed
+func (nm *namespaceManager) configReloaded(ctx context.Context) error {
 	// Always make sure log level is up to date
 	log.SetLevel(config.GetString(config.LogLevel))
 
@@ -73,9 +193,8 @@ func (nm *namespaceManager) configReloaded(ctx context.Context) {
 	allPluginsInNewConf, err := nm.loadPlugins(ctx, rawConfig)
 	if err != nil {
 		log.L(ctx).Errorf("Failed to initialize plugins after config reload: %s", err)
-		return
+		return err
 	}
-
 	// Analyze the new list to see which plugins need to be updated,
 	// so we load the namespaces against the correct list of plugins
 	availablePlugins, updatedPlugins, pluginsToStop := nm.analyzePluginChanges(ctx, allPluginsInNewConf)
@@ -84,7 +203,7 @@ func (nm *namespaceManager) configReloaded(ctx context.Context) {
 	allNewNamespaces, err := nm.loadNamespaces(ctx, rawConfig, availablePlugins)
 	if err != nil {
 		log.L(ctx).Errorf("Failed to load namespaces after config reload: %s", err)
-		return
+		return err
 	}
 
 	// From this point we need to block any API calls resolving namespaces,
@@ -115,16 +234,16 @@ func (nm *namespaceManager) configReloaded(ctx context.Context) {
 	if err = nm.initPlugins(updatedPlugins); err != nil {
 		log.L(ctx).Errorf("Failed to initialize plugins after config reload: %s", err)
 		nm.cancelCtx() // stop the world
-		return
+		return err
 	}
 
 	// Now we can start all the new things
 	if err = nm.startNamespacesAndPlugins(updatedNamespaces, updatedPlugins); err != nil {
 		log.L(ctx).Errorf("Failed to initialize namespaces after config reload: %s", err)
 		nm.cancelCtx() // stop the world
-		return
+		return err
 	}
-
+	return nil
 }
 
 func (nm *namespaceManager) stopDefunctNamespaces(ctx context.Context, newPlugins map[string]*plugin, newNamespaces map[string]*namespace) (availableNamespaces, updatedNamespaces map[string]*namespace) {
@@ -419,6 +419,7 @@ func mockInitConfig(nmm *nmMocks) {
 	nmm.mei[0].On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mei[1].On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mei[2].On("Init", mock.Anything, mock.Anything).Return(nil)
+	nmm.mei[3].On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mdi.On("GetNamespace", mock.Anything, "ns1").Return(nil, nil)
 	nmm.mdi.On("GetNamespace", mock.Anything, "ns2").Return(nil, nil)
 	nmm.mdi.On("GetNamespace", mock.Anything, "ns3").Return(nil, nil).Maybe()
@@ -1070,3 +1071,247 @@ namespaces:
 	nm.WaitStop()
 
 }
+
+func TestReloadConfigOk(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(exampleConfig1base))
+	assert.NoError(t, err)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	mockInitConfig(nmm)
+	waitInit := namespaceInitWaiter(t, nmm, []string{"ns1", "ns2"})
+
+	err = nm.Init(ctx, cancelCtx, make(chan bool), func() error {
+		coreconfig.Reset()
+		InitConfig()
+		viper.SetConfigType("yaml")
+		return viper.ReadConfig(strings.NewReader(exampleConfig2extraNS))
+	})
+	assert.NoError(t, err)
+
+	err = nm.Start()
+	assert.NoError(t, err)
+	waitInit.Wait()
+
+	originalNS := nm.namespaces
+	waitInit = namespaceInitWaiter(t, nmm, []string{"ns3"})
+	err = nm.ReloadConfig(context.Background())
+	assert.NoError(t, err)
+	waitInit.Wait()
+
+	assert.True(t, originalNS["ns1"] == nm.namespaces["ns1"])
+	assert.True(t, originalNS["ns2"] == nm.namespaces["ns2"])
+	assert.NotNil(t, nm.namespaces["ns3"])
+}
+
+func TestReloadConfigReadFail(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	nm.reloadConfig = func() error { return fmt.Errorf("pop") }
+	err := nm.ReloadConfig(context.Background())
+	assert.Regexp(t, "FF10573.*pop", err)
+}
+
+func TestResetPluginRestartsNamespaces(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(exampleConfig1base))
+	assert.NoError(t, err)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+
+	mockInitConfig(nmm)
+	waitInit := namespaceInitWaiter(t, nmm, []string{"ns1", "ns2"})
+
+	err = nm.Init(ctx, cancelCtx, make(chan bool), func() error {
+		coreconfig.Reset()
+		InitConfig()
+		viper.SetConfigType("yaml")
+		return viper.ReadConfig(strings.NewReader(exampleConfig1base))
+	})
+	assert.NoError(t, err)
+
+	err = nm.Start()
+	assert.NoError(t, err)
+	waitInit.Wait()
+
+	originalPlugins := nm.plugins
+	originalNS := nm.namespaces
+	waitInit = namespaceInitWaiter(t, nmm, []string{"ns2"})
+	err = nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.NoError(t, err)
+	waitInit.Wait()
+
+	// Only the reset plugin, and the namespace using it, are restarted
+	for name := range originalPlugins {
+		assert.Equal(t, name != "blockchain-ns2", originalPlugins[name] == nm.plugins[name], name)
+	}
+	assert.True(t, originalNS["ns1"] == nm.namespaces["ns1"])
+	assert.False(t, originalNS["ns2"] == nm.namespaces["ns2"])
+	assert.Error(t, originalPlugins["blockchain-ns2"].ctx.Err())
+	assert.NoError(t, originalPlugins["blockchain-ns1"].ctx.Err())
+}
+
+// newTestResetPluginManager starts the namespace manager on exampleConfig1base, with the configuration
+// re-read on a reset coming from the supplied YAML
+func newTestResetPluginManager(t *testing.T, reloadedConfig string) (*namespaceManager, *nmMocks, func()) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, false)
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(exampleConfig1base))
+	assert.NoError(t, err)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	mockInitConfig(nmm)
+	waitInit := namespaceInitWaiter(t, nmm, []string{"ns1", "ns2"})
+	err = nm.Init(ctx, cancelCtx, make(chan bool), func() error {
+		coreconfig.Reset()
+		InitConfig()
+		viper.SetConfigType("yaml")
+		return viper.ReadConfig(strings.NewReader(reloadedConfig))
+	})
+	assert.NoError(t, err)
+	err = nm.Start()
+	assert.NoError(t, err)
+	waitInit.Wait()
+	return nm, nmm, func() {
+		cancelCtx()
+		cleanup()
+	}
+}
+
+func setMockReturn(m *mock.Mock, method string, ret ...interface{}) {
+	for _, c := range m.ExpectedCalls {
+		if c.Method == method {
+			c.ReturnArguments = ret
+		}
+	}
+}
+
+func TestResetPluginIgnoresOtherChanges(t *testing.T) {
+	reloaded := strings.ReplaceAll(exampleConfig1base, "ethconnect1.example.com", "ethconnect1-rotated.example.com")
+	reloaded = strings.ReplaceAll(reloaded, "ethconnect2.example.com", "ethconnect2-rotated.example.com")
+	nm, nmm, cleanup := newTestResetPluginManager(t, reloaded)
+	defer cleanup()
+
+	originalPlugins := nm.plugins
+	originalNS := nm.namespaces
+	waitInit := namespaceInitWaiter(t, nmm, []string{"ns2"})
+	err := nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.NoError(t, err)
+	waitInit.Wait()
+
+	// The pending change to blockchain-ns1 is not applied
+	assert.True(t, originalPlugins["blockchain-ns1"] == nm.plugins["blockchain-ns1"])
+	assert.True(t, originalNS["ns1"] == nm.namespaces["ns1"])
+	assert.False(t, originalPlugins["blockchain-ns2"].configHash.Equals(nm.plugins["blockchain-ns2"].configHash))
+	assert.False(t, originalNS["ns2"] == nm.namespaces["ns2"])
+}
+
+func TestResetPluginInitFailKeepsRunning(t *testing.T) {
+	nm, nmm, cleanup := newTestResetPluginManager(t, exampleConfig1base)
+	defer cleanup()
+
+	setMockReturn(&nmm.mbi.Mock, "Init", fmt.Errorf("pop"))
+	originalPlugins := nm.plugins
+	originalNS := nm.namespaces
+	err := nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.EqualError(t, err, "pop")
+
+	assert.Equal(t, originalPlugins, nm.plugins)
+	assert.Equal(t, originalNS, nm.namespaces)
+	assert.NoError(t, originalPlugins["blockchain-ns2"].ctx.Err())
+	assert.NoError(t, nm.ctx.Err())
+}
+
+func TestResetPluginNamespaceChanged(t *testing.T) {
+	reloaded := strings.Replace(exampleConfig1base, "0x630659A26fa005d50Fa9706D8a4e242fd4169A61", "0x0000000000000000000000000000000000000001", 1)
+	nm, _, cleanup := newTestResetPluginManager(t, reloaded)
+	defer cleanup()
+
+	originalPlugins := nm.plugins
+	err := nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.Regexp(t, "FF10649.*blockchain-ns2.*ns2", err)
+	assert.Equal(t, originalPlugins, nm.plugins)
+}
+
+func TestResetPluginRemovedFromConfig(t *testing.T) {
+	reloaded := strings.Replace(exampleConfig1base, "name: blockchain-ns2", "name: blockchain-other", 1)
+	nm, _, cleanup := newTestResetPluginManager(t, reloaded)
+	defer cleanup()
+
+	err := nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.Regexp(t, "FF10574", err)
+}
+
+func TestResetPluginBadPluginConfig(t *testing.T) {
+	reloaded := strings.Replace(exampleConfig1base, "name: blockchain-ns1", "name: database0", 1)
+	nm, _, cleanup := newTestResetPluginManager(t, reloaded)
+	defer cleanup()
+
+	err := nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.Regexp(t, "FF10", err)
+}
+
+func TestResetPluginBadNamespaceConfig(t *testing.T) {
+	reloaded := strings.Replace(exampleConfig1base, "default: ns1", "default: ns9", 1)
+	nm, _, cleanup := newTestResetPluginManager(t, reloaded)
+	defer cleanup()
+
+	err := nm.ResetPlugin(context.Background(), "blockchain-ns2")
+	assert.Regexp(t, "FF10166", err)
+}
+
+func TestResetPluginStartFail(t *testing.T) {
+	nm, nmm, cleanup := newTestResetPluginManager(t, exampleConfig1base)
+	defer cleanup()
+
+	setMockReturn(&nmm.mdx.Mock, "Start", fmt.Errorf("pop"))
+	for _, mei := range nmm.mei {
+		mei.On("NamespaceRestarted", "ns1", mock.Anything).Return().Maybe()
+	}
+	err := nm.ResetPlugin(context.Background(), "ff-dx")
+	assert.EqualError(t, err, "pop")
+	assert.Error(t, nm.ctx.Err())
+}
+
+func TestResetPluginNotFound(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	nm.plugins = map[string]*plugin{}
+	err := nm.ResetPlugin(context.Background(), "blockchain0")
+	assert.Regexp(t, "FF10574", err)
+}
+
+func TestResetPluginReadFail(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	nm.plugins = map[string]*plugin{
+		"tokens0": {name: "tokens0", category: pluginCategoryTokens},
+	}
+	nm.reloadConfig = func() error { return fmt.Errorf("pop") }
+	err := nm.ResetPlugin(context.Background(), "tokens0")
+	assert.Regexp(t, "FF10573.*pop", err)
+}
+
+func TestResetPluginNotSupported(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	nm.plugins = map[string]*plugin{
+		"database0": {name: "database0", category: pluginCategoryDatabase},
+	}
+	err := nm.ResetPlugin(context.Background(), "database0")
+	assert.Regexp(t, "FF10575.*database", err)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// localNamespaces gives event plugins that bridge between namespaces access to the other namespaces on this node
+type localNamespaces struct {
+	nm *namespaceManager
+}
+
+func (ln *localNamespaces) CheckBroadcastNamespace(ctx context.Context, namespace string) error {
+	or, err := ln.nm.Orchestrator(ctx, namespace, true)
+	if err != nil {
+		return err
+	}
+	if or.Broadcast() == nil {
+		return i18n.NewError(ctx, coremsgs.MsgBridgeTargetNoBroadcast, namespace)
+	}
+	return nil
+}
+
+func (ln *localNamespaces) BroadcastMessage(ctx context.Context, namespace string, msg *core.MessageInOut) (*core.Message, error) {
+	or, err := ln.nm.Orchestrator(ctx, namespace, false)
+	if err != nil {
+		return nil, err
+	}
+	if or.Broadcast() == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBridgeTargetNoBroadcast, namespace)
+	}
+	return or.Broadcast().BroadcastBridgedMessage(ctx, msg)
+}
@@ -0,0 +1,112 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/broadcast"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLocalNamespaces(started bool) (*localNamespaces, *orchestratormocks.Orchestrator) {
+	mo := &orchestratormocks.Orchestrator{}
+	nm := &namespaceManager{
+		namespaces: map[string]*namespace{
+			"ns2": {orchestrator: mo, started: started},
+		},
+	}
+	return &localNamespaces{nm: nm}, mo
+}
+
+func TestCheckBroadcastNamespaceOk(t *testing.T) {
+	ln, mo := newTestLocalNamespaces(false)
+	mo.On("Broadcast").Return(&broadcastmocks.Manager{})
+
+	err := ln.CheckBroadcastNamespace(context.Background(), "ns2")
+	assert.NoError(t, err)
+
+	mo.AssertExpectations(t)
+}
+
+func TestCheckBroadcastNamespaceUnknown(t *testing.T) {
+	ln, _ := newTestLocalNamespaces(true)
+
+	err := ln.CheckBroadcastNamespace(context.Background(), "ns3")
+	assert.Regexp(t, "FF10436", err)
+}
+
+func TestCheckBroadcastNamespaceNoBroadcast(t *testing.T) {
+	ln, mo := newTestLocalNamespaces(true)
+	mo.On("Broadcast").Return((broadcast.Manager)(nil))
+
+	err := ln.CheckBroadcastNamespace(context.Background(), "ns2")
+	assert.Regexp(t, "FF10595", err)
+
+	mo.AssertExpectations(t)
+}
+
+func TestBroadcastMessageOk(t *testing.T) {
+	ln, mo := newTestLocalNamespaces(true)
+	mbm := &broadcastmocks.Manager{}
+	mo.On("Broadcast").Return(mbm)
+	msg := &core.MessageInOut{}
+	mbm.On("BroadcastBridgedMessage", context.Background(), msg).Return(&core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}, nil)
+
+	out, err := ln.BroadcastMessage(context.Background(), "ns2", msg)
+	assert.NoError(t, err)
+	assert.NotNil(t, out.Header.ID)
+
+	mo.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageNotStarted(t *testing.T) {
+	ln, _ := newTestLocalNamespaces(false)
+
+	_, err := ln.BroadcastMessage(context.Background(), "ns2", &core.MessageInOut{})
+	assert.Regexp(t, "FF10441", err)
+}
+
+func TestBroadcastMessageNoBroadcast(t *testing.T) {
+	ln, mo := newTestLocalNamespaces(true)
+	mo.On("Broadcast").Return((broadcast.Manager)(nil))
+
+	_, err := ln.BroadcastMessage(context.Background(), "ns2", &core.MessageInOut{})
+	assert.Regexp(t, "FF10595", err)
+
+	mo.AssertExpectations(t)
+}
+
+func TestBroadcastMessageFail(t *testing.T) {
+	ln, mo := newTestLocalNamespaces(true)
+	mbm := &broadcastmocks.Manager{}
+	mo.On("Broadcast").Return(mbm)
+	mbm.On("BroadcastBridgedMessage", context.Background(), &core.MessageInOut{}).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ln.BroadcastMessage(context.Background(), "ns2", &core.MessageInOut{})
+	assert.EqualError(t, err, "pop")
+
+	mo.AssertExpectations(t)
+	mbm.AssertExpectations(t)
+}
@@ -25,24 +25,27 @@ import (
 	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/auth"
-	"github.com/hyperledger/firefly-common/pkg/auth/authfactory"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftls"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/auth/authfactory"
 	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/database/difactory"
 	"github.com/hyperledger/firefly/internal/dataexchange/dxfactory"
+	"github.com/hyperledger/firefly/internal/events/bridge"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
 	"github.com/hyperledger/firefly/internal/events/system"
+	"github.com/hyperledger/firefly/internal/ha"
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/spievents"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
@@ -62,14 +65,18 @@ type Manager interface {
 	Start() error
 	WaitStop()
 	Reset(ctx context.Context) error
+	ReloadConfig(ctx context.Context) error
+	ResetPlugin(ctx context.Context, name string) error
 
 	Orchestrator(ctx context.Context, ns string, includeInitializing bool) (orchestrator.Orchestrator, error)
 	MustOrchestrator(ns string) orchestrator.Orchestrator
 	SPIEvents() spievents.Manager
-	GetNamespaces(ctx context.Context, includeInitializing bool) ([]*core.NamespaceWithInitStatus, error)
+	GetNamespaces(ctx context.Context, includeInitializing, includePlugins bool) ([]*core.NamespaceWithInitStatus, error)
 	GetOperationByNamespacedID(ctx context.Context, nsOpID string) (*core.Operation, error)
 	ResolveOperationByNamespacedID(ctx context.Context, nsOpID string, op *core.OperationUpdateDTO) error
 	Authorize(ctx context.Context, authReq *fftypes.AuthReq) error
+	IsActive() bool
+	GetHAStatus(ctx context.Context) (*core.HAStatus, error)
 }
 
 type namespace struct {
@@ -83,6 +90,7 @@ type namespace struct {
 	pluginNames  []string
 	plugins      *orchestrator.Plugins
 	started      bool
+	standby      bool // initialized and serving reads, but waiting for this node to be active before it starts
 	initError    string
 }
 
@@ -92,6 +100,7 @@ type namespaceManager struct {
 	ctx                 context.Context
 	cancelCtx           context.CancelFunc
 	nsMux               sync.Mutex
+	reloadMux           sync.Mutex
 	namespaces          map[string]*namespace
 	plugins             map[string]*plugin
 	metricsEnabled      bool
@@ -101,6 +110,7 @@ type namespaceManager struct {
 	tokenBroadcastNames map[string]string
 	watchConfig         func() // indirect from viper.WatchConfig for testing
 	nsStartupRetry      *retry.Retry
+	ha                  ha.Manager // nil unless high availability is enabled
 
 	orchestratorFactory  func(ns *core.Namespace, config orchestrator.Config, plugins *orchestrator.Plugins, metrics metrics.Manager, cacheManager cache.Manager) orchestrator.Orchestrator
 	blockchainFactory    func(ctx context.Context, pluginType string) (blockchain.Plugin, error)
@@ -111,6 +121,7 @@ type namespaceManager struct {
 	identityFactory      func(ctx context.Context, pluginType string) (identity.Plugin, error)
 	eventsFactory        func(ctx context.Context, pluginType string) (events.Plugin, error)
 	authFactory          func(ctx context.Context, pluginType string) (auth.Plugin, error)
+	haFactory            func(ctx context.Context, locks ha.LockProvider, onDemoted func()) (ha.Manager, error)
 }
 
 type pluginCategory string
@@ -174,6 +185,7 @@ func NewNamespaceManager() Manager {
 		identityFactory:      iifactory.GetPlugin,
 		eventsFactory:        eifactory.GetPlugin,
 		authFactory:          authfactory.GetPlugin,
+		haFactory:            ha.NewHAManager,
 		nsStartupRetry: &retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.NamespacesRetryInitDelay),
 			MaximumDelay: config.GetDuration(coreconfig.NamespacesRetryMaxDelay),
@@ -216,6 +228,12 @@ func (nm *namespaceManager) initComponents() (err error) {
 		return err
 	}
 
+	if config.GetBool(coreconfig.HAEnabled) {
+		if err = nm.initHA(); err != nil {
+			return err
+		}
+	}
+
 	return nm.startConfigListener()
 }
 
@@ -337,6 +355,16 @@ func (nm *namespaceManager) initAndStartNamespace(ns *namespace) error {
 	}
 	log.L(nm.ctx).Infof("Initialized namespace '%s' multiparty=%s version=%s", ns.Name, strconv.FormatBool(multiparty), version)
 
+	if nm.ha != nil && !nm.ha.IsActive() {
+		nm.nsMux.Lock()
+		ns.standby = true
+		nm.nsMux.Unlock()
+		log.L(nm.ctx).Infof("Namespace '%s' serving read requests until this node is active", ns.Name)
+		if err := nm.ha.WaitActive(ns.ctx); err != nil {
+			return err
+		}
+	}
+
 	// Check if we need to start up a V1 system namespace as a side effect of having initialized this namespace
 	// Note we do that start synchronous to this namespace starting.
 	if err := nm.startV1NamespaceIfRequired(ns); err != nil {
@@ -390,6 +418,9 @@ func (nm *namespaceManager) stopNamespace(ctx context.Context, ns *namespace) {
 }
 
 func (nm *namespaceManager) Start() error {
+	if nm.ha != nil {
+		nm.ha.Start()
+	}
 	// On initial start, we need to start everything
 	return nm.startNamespacesAndPlugins(nm.namespaces, nm.plugins)
 }
@@ -408,6 +439,25 @@ func (nm *namespaceManager) startNamespacesAndPlugins(namespacesToStart map[stri
 		}
 		go nm.namespaceStarter(ns)
 	}
+	if nm.ha != nil && !nm.ha.IsActive() {
+		go nm.pluginStarter(pluginsToStart)
+		return nil
+	}
+	return nm.startPlugins(pluginsToStart)
+}
+
+// pluginStarter starts the plugins once this node is active
+func (nm *namespaceManager) pluginStarter(pluginsToStart map[string]*plugin) {
+	if err := nm.ha.WaitActive(nm.ctx); err != nil {
+		return
+	}
+	if err := nm.startPlugins(pluginsToStart); err != nil {
+		log.L(nm.ctx).Errorf("Failed to start plugins: %s", err)
+		nm.cancelCtx()
+	}
+}
+
+func (nm *namespaceManager) startPlugins(pluginsToStart map[string]*plugin) error {
 	for _, plugin := range pluginsToStart {
 		if plugin.category == pluginCategoryDataexchange {
 			if err := plugin.dataexchange.Start(); err != nil {
@@ -429,6 +479,9 @@ func (nm *namespaceManager) WaitStop() {
 	for _, ns := range namespaces {
 		nm.stopNamespace(nm.ctx, ns)
 	}
+	if nm.ha != nil {
+		nm.ha.WaitStop()
+	}
 	nm.adminEvents.WaitStop()
 }
 
@@ -465,6 +518,44 @@ func (nm *namespaceManager) loadManagers(ctx context.Context) {
 	}
 }
 
+// initHA creates the election of the active node, with the leader lock held by the configured lock provider
+func (nm *namespaceManager) initHA() (err error) {
+	var locks ha.LockProvider
+	switch lockProvider := config.GetString(coreconfig.HALockProvider); lockProvider {
+	case "database":
+		db, err := nm.haDatabase()
+		if err != nil {
+			return err
+		}
+		locks = ha.NewDatabaseLockProvider(db)
+	default:
+		return i18n.NewError(nm.ctx, coremsgs.MsgHAUnknownLockProvider, lockProvider)
+	}
+	// A node that loses the lock shuts down, so that it rejoins as a passive node when it restarts
+	nm.ha, err = nm.haFactory(nm.ctx, locks, nm.cancelCtx)
+	return err
+}
+
+func (nm *namespaceManager) haDatabase() (database.Plugin, error) {
+	name := config.GetString(coreconfig.HADatabase)
+	var db database.Plugin
+	for _, p := range nm.plugins {
+		if p.category != pluginCategoryDatabase {
+			continue
+		}
+		if name == "" && db != nil {
+			return nil, i18n.NewError(nm.ctx, coremsgs.MsgHADatabaseRequired)
+		}
+		if name == "" || name == p.name {
+			db = p.database
+		}
+	}
+	if db == nil {
+		return nil, i18n.NewError(nm.ctx, coremsgs.MsgUnknownDatabasePlugin, name)
+	}
+	return db, nil
+}
+
 func (nm *namespaceManager) loadPlugins(ctx context.Context, rawConfig fftypes.JSONObject) (newPlugins map[string]*plugin, err error) {
 
 	newPlugins = make(map[string]*plugin)
@@ -691,36 +782,49 @@ func (nm *namespaceManager) initPlugins(pluginsToStart map[string]*plugin) (err
 		if pluginsToStart[name] == nil {
 			continue
 		}
-		switch p.category {
-		case pluginCategoryDatabase:
-			if err = p.database.Init(p.ctx, p.config); err != nil {
-				return err
-			}
-			p.database.SetHandler(database.GlobalHandler, nm)
-		case pluginCategoryBlockchain:
-			if err = p.blockchain.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, p.config, nm.metrics, nm.cacheManager); err != nil {
-				return err
-			}
-		case pluginCategoryDataexchange:
-			if err = p.dataexchange.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, p.config); err != nil {
-				return err
-			}
-		case pluginCategorySharedstorage:
-			if err = p.sharedstorage.Init(p.ctx, p.config); err != nil {
-				return err
-			}
-		case pluginCategoryTokens:
-			if err = p.tokens.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, name, p.config); err != nil {
-				return err
-			}
-		case pluginCategoryEvents:
-			if err = p.events.Init(p.ctx, p.config); err != nil {
-				return err
-			}
-		case pluginCategoryAuth:
-			if err = p.auth.Init(p.ctx, name, p.config); err != nil {
-				return err
-			}
+		if err = nm.initPlugin(name, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (nm *namespaceManager) initPlugin(name string, p *plugin) (err error) {
+	switch p.category {
+	case pluginCategoryDatabase:
+		if err = p.database.Init(p.ctx, p.config); err != nil {
+			return err
+		}
+		p.database.SetHandler(database.GlobalHandler, nm)
+	case pluginCategoryBlockchain:
+		if err = p.blockchain.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, p.config, nm.metrics, nm.cacheManager); err != nil {
+			return err
+		}
+	case pluginCategoryDataexchange:
+		if err = p.dataexchange.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, p.config); err != nil {
+			return err
+		}
+	case pluginCategorySharedstorage:
+		if err = p.sharedstorage.Init(p.ctx, p.config); err != nil {
+			return err
+		}
+	case pluginCategoryTokens:
+		if err = p.tokens.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, name, p.config); err != nil {
+			return err
+		}
+	case pluginCategoryEvents:
+		if err = p.events.Init(p.ctx, p.config); err != nil {
+			return err
+		}
+		if mr, ok := p.events.(events.MetricsReporter); ok {
+			mr.SetMetrics(nm.metrics)
+		}
+		if nb, ok := p.events.(events.NamespaceBridge); ok {
+			nb.SetLocalNamespaces(&localNamespaces{nm: nm})
+		}
+	case pluginCategoryAuth:
+		if err = p.auth.Init(p.ctx, name, p.config); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -789,6 +893,55 @@ func (nm *namespaceManager) loadTLSConfig(ctx context.Context, tlsConfigs map[st
 	return nil
 }
 
+func (nm *namespaceManager) loadSigningSecrets(ctx context.Context, conf config.ArraySection) (map[string][]byte, error) {
+	signingSecrets := make(map[string][]byte)
+	for i := 0; i < conf.ArraySize(); i++ {
+		entry := conf.ArrayEntry(i)
+		name := entry.GetString(coreconfig.NamespaceSigningSecretName)
+		secret := entry.GetString(coreconfig.NamespaceSigningSecretValue)
+		if name == "" || secret == "" {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidSigningSecret, name)
+		}
+		if signingSecrets[name] != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDuplicateSigningSecret, name)
+		}
+		signingSecrets[name] = []byte(secret)
+	}
+	return signingSecrets, nil
+}
+
+func (nm *namespaceManager) loadPoolConnectors(ctx context.Context, conf config.ArraySection) (map[string]string, error) {
+	poolConnectors := make(map[string]string)
+	for i := 0; i < conf.ArraySize(); i++ {
+		entry := conf.ArrayEntry(i)
+		pool := entry.GetString(coreconfig.NamespaceAssetPoolConnectorPool)
+		connector := entry.GetString(coreconfig.NamespaceAssetPoolConnectorConnector)
+		if pool == "" || connector == "" {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidPoolConnector, i)
+		}
+		if _, ok := poolConnectors[pool]; ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDuplicatePoolConnector, pool)
+		}
+		poolConnectors[pool] = connector
+	}
+	return poolConnectors, nil
+}
+
+func (nm *namespaceManager) loadRetentionConfig(ctx context.Context, name string, conf config.Section) (retention.Config, error) {
+	window := conf.GetString(coreconfig.NamespaceRetentionWindow)
+	if window == "" {
+		window = config.GetString(coreconfig.RetentionWindow)
+	}
+	windowDuration, err := fftypes.ParseDurationString(window, time.Millisecond)
+	if err != nil {
+		return retention.Config{}, i18n.WrapError(ctx, err, coremsgs.MsgInvalidRetentionWindow, window, name)
+	}
+	return retention.Config{
+		Window:  time.Duration(windowDuration),
+		Archive: conf.GetBool(coreconfig.NamespaceRetentionArchive),
+	}, nil
+}
+
 // nolint: gocyclo
 func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, index int, conf config.Section, rawNSConfig fftypes.JSONObject, availablePlugins map[string]*plugin) (ns *namespace, err error) {
 	if err := fftypes.ValidateFFNameField(ctx, name, fmt.Sprintf("namespaces.predefined[%d].name", index)); err != nil {
@@ -802,6 +955,14 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 	if keyNormalization == "" {
 		keyNormalization = config.GetString(coreconfig.AssetManagerKeyNormalization)
 	}
+	poolConnectors, err := nm.loadPoolConnectors(ctx, conf.SubArray(coreconfig.NamespaceAssetPoolConnectors))
+	if err != nil {
+		return nil, err
+	}
+	retentionConf, err := nm.loadRetentionConfig(ctx, name, conf)
+	if err != nil {
+		return nil, err
+	}
 
 	multipartyConf := conf.SubSection(coreconfig.NamespaceMultiparty)
 	// If any multiparty org information is configured (here or at the root), assume multiparty mode by default
@@ -881,11 +1042,18 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 		return nil, err
 	}
 
+	signingSecrets, err := nm.loadSigningSecrets(ctx, conf.SubArray(coreconfig.NamespaceSigningSecrets))
+	if err != nil {
+		return nil, err
+	}
+
 	config := orchestrator.Config{
 		DefaultKey:                  conf.GetString(coreconfig.NamespaceDefaultKey),
 		TokenBroadcastNames:         nm.tokenBroadcastNames,
 		KeyNormalization:            keyNormalization,
+		TokenPoolConnectors:         poolConnectors,
 		MaxHistoricalEventScanLimit: config.GetInt(coreconfig.SubscriptionMaxHistoricalEventScanLength),
+		Retention:                   retentionConf,
 	}
 	if multipartyEnabled.(bool) {
 		contractsConf := multipartyConf.SubArray(coreconfig.NamespaceMultipartyContract)
@@ -915,10 +1083,11 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 
 	ns = &namespace{
 		Namespace: core.Namespace{
-			Name:        name,
-			NetworkName: networkName,
-			Description: conf.GetString(coreconfig.NamespaceDescription),
-			TLSConfigs:  tlsConfigs,
+			Name:           name,
+			NetworkName:    networkName,
+			Description:    conf.GetString(coreconfig.NamespaceDescription),
+			TLSConfigs:     tlsConfigs,
+			SigningSecrets: signingSecrets,
 		},
 		loadTime:    fftypes.Now(),
 		config:      config,
@@ -1046,7 +1215,7 @@ func (nm *namespaceManager) Orchestrator(ctx context.Context, ns string, include
 	defer nm.nsMux.Unlock()
 	// Only return started namespaces from this call
 	if namespace, ok := nm.namespaces[ns]; ok && namespace != nil {
-		if !includeInitializing && !namespace.started {
+		if !includeInitializing && !namespace.started && !namespace.standby {
 			return nil, i18n.NewError(ctx, coremsgs.MsgNamespaceInitializing, ns)
 		}
 		return namespace.orchestrator, nil
@@ -1063,17 +1232,27 @@ func (nm *namespaceManager) MustOrchestrator(ns string) orchestrator.Orchestrato
 	return or
 }
 
-func (nm *namespaceManager) GetNamespaces(ctx context.Context, includeInitializing bool) ([]*core.NamespaceWithInitStatus, error) {
+func (nm *namespaceManager) GetNamespaces(ctx context.Context, includeInitializing, includePlugins bool) ([]*core.NamespaceWithInitStatus, error) {
 	nm.nsMux.Lock()
 	defer nm.nsMux.Unlock()
 	results := make([]*core.NamespaceWithInitStatus, 0, len(nm.namespaces))
 	for _, ns := range nm.namespaces {
-		if includeInitializing || ns.started {
-			results = append(results, &core.NamespaceWithInitStatus{
+		if includeInitializing || ns.started || ns.standby {
+			result := &core.NamespaceWithInitStatus{
 				Namespace:           &ns.Namespace,
-				Initializing:        !ns.started,
+				Initializing:        !ns.started && !ns.standby,
 				InitializationError: ns.initError,
-			})
+			}
+			if includePlugins {
+				multiparty := ns.config.Multiparty.Enabled
+				result.Multiparty = &multiparty
+				result.DefaultKey = ns.config.DefaultKey
+				if ns.plugins != nil {
+					plugins := ns.plugins.Status()
+					result.Plugins = &plugins
+				}
+			}
+			results = append(results, result)
 		}
 	}
 	return results, nil
@@ -1110,8 +1289,9 @@ func (nm *namespaceManager) getEventPlugins(ctx context.Context, plugins map[str
 	for _, transport := range enabledTransports {
 		uniqueTransports[transport] = true
 	}
-	// Cannot disable the internal listener
+	// Cannot disable the internal listener, or the transport used by bridges
 	uniqueTransports[system.SystemEventsTransport] = true
+	uniqueTransports[bridge.BridgeTransport] = true
 	for transport := range uniqueTransports {
 
 		eventsPlugin, err := nm.eventsFactory(ctx, transport)
@@ -1162,3 +1342,15 @@ func (nm *namespaceManager) Authorize(ctx context.Context, authReq *fftypes.Auth
 	}
 	return or.Authorize(ctx, authReq)
 }
+
+// IsActive is true unless high availability is enabled, and another node is active
+func (nm *namespaceManager) IsActive() bool {
+	return nm.ha == nil || nm.ha.IsActive()
+}
+
+func (nm *namespaceManager) GetHAStatus(ctx context.Context) (*core.HAStatus, error) {
+	if nm.ha == nil {
+		return &core.HAStatus{Enabled: false, Mode: core.HAModeActive}, nil
+	}
+	return nm.ha.GetStatus(ctx)
+}
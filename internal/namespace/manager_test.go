@@ -45,6 +45,7 @@ import (
 	"github.com/hyperledger/firefly/internal/database/difactory"
 	"github.com/hyperledger/firefly/internal/dataexchange/dxfactory"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
+	"github.com/hyperledger/firefly/internal/ha"
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/orchestrator"
@@ -55,6 +56,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/hamocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
 	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
@@ -158,6 +160,7 @@ func (nmm *nmMocks) cleanup(t *testing.T) {
 	nmm.mei[0].AssertExpectations(t)
 	nmm.mei[1].AssertExpectations(t)
 	nmm.mei[2].AssertExpectations(t)
+	nmm.mei[3].AssertExpectations(t)
 	nmm.mo.AssertExpectations(t)
 }
 
@@ -177,7 +180,7 @@ func mockPluginFactories(inm Manager) (nmm *nmMocks) {
 		mdx: &dataexchangemocks.Plugin{},
 		mps: &sharedstoragemocks.Plugin{},
 		mti: []*tokenmocks.Plugin{{}, {}},
-		mei: []*eventsmocks.Plugin{{}, {}, {}},
+		mei: []*eventsmocks.Plugin{{}, {}, {}, {}},
 		mai: &authmocks.Plugin{},
 		mii: &identitymocks.Plugin{},
 		mo:  &orchestratormocks.Orchestrator{},
@@ -191,6 +194,7 @@ func mockPluginFactories(inm Manager) (nmm *nmMocks) {
 	factoryMocks(&nmm.mei[0].Mock, "system")
 	factoryMocks(&nmm.mei[1].Mock, "websockets")
 	factoryMocks(&nmm.mei[2].Mock, "webhooks")
+	factoryMocks(&nmm.mei[3].Mock, "bridge")
 	factoryMocks(&nmm.mai.Mock, "basicauth")
 
 	nm.orchestratorFactory = func(ns *core.Namespace, config orchestrator.Config, plugins *orchestrator.Plugins, metrics metrics.Manager, cacheManager cache.Manager) orchestrator.Orchestrator {
@@ -225,6 +229,8 @@ func mockPluginFactories(inm Manager) (nmm *nmMocks) {
 			return nmm.mei[1], nil
 		case "webhooks":
 			return nmm.mei[2], nil
+		case "bridge":
+			return nmm.mei[3], nil
 		default:
 			panic(fmt.Errorf("Add plugin type %s to test", pluginType))
 		}
@@ -280,6 +286,7 @@ func newTestNamespaceManager(t *testing.T, initConfig bool) (*namespaceManager,
 		nmm.mei[0].On("Init", mock.Anything, mock.Anything).Return(nil)
 		nmm.mei[1].On("Init", mock.Anything, mock.Anything).Return(nil)
 		nmm.mei[2].On("Init", mock.Anything, mock.Anything).Return(nil)
+		nmm.mei[3].On("Init", mock.Anything, mock.Anything).Return(nil)
 		nmm.mai.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 
 		err = nmm.nm.Init(nmm.nm.ctx, nmm.nm.cancelCtx, nmm.nm.reset, nmm.nm.reloadConfig)
@@ -308,11 +315,12 @@ func TestInitEmpty(t *testing.T) {
 	nmm.mei[0].On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mei[1].On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mei[2].On("Init", mock.Anything, mock.Anything).Return(nil)
+	nmm.mei[3].On("Init", mock.Anything, mock.Anything).Return(nil)
 
 	err := nm.Init(nm.ctx, nm.cancelCtx, nm.reset, nm.reloadConfig)
 	assert.NoError(t, err)
 
-	assert.Len(t, nm.plugins, 3) // events
+	assert.Len(t, nm.plugins, 4) // events
 	assert.Empty(t, nm.namespaces)
 }
 
@@ -408,6 +416,52 @@ func TestInitEventsFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+type testMetricsReporterPlugin struct {
+	*eventsmocks.Plugin
+	*eventsmocks.MetricsReporter
+}
+
+func TestInitEventsSetMetrics(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mei := &eventsmocks.Plugin{}
+	mei.On("Init", mock.Anything, mock.Anything).Return(nil)
+	mmr := &eventsmocks.MetricsReporter{}
+	mmr.On("SetMetrics", nmm.mmi).Return()
+	nm.plugins["websockets"].events = &testMetricsReporterPlugin{Plugin: mei, MetricsReporter: mmr}
+	err := nm.initPlugins(map[string]*plugin{
+		"websockets": nm.plugins["websockets"],
+	})
+	assert.NoError(t, err)
+
+	mmr.AssertExpectations(t)
+}
+
+type testNamespaceBridgePlugin struct {
+	*eventsmocks.Plugin
+	lns events.LocalNamespaces
+}
+
+func (tp *testNamespaceBridgePlugin) SetLocalNamespaces(lns events.LocalNamespaces) {
+	tp.lns = lns
+}
+
+func TestInitEventsSetLocalNamespaces(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mei := &eventsmocks.Plugin{}
+	mei.On("Init", mock.Anything, mock.Anything).Return(nil)
+	tp := &testNamespaceBridgePlugin{Plugin: mei}
+	nm.plugins["bridge"].events = tp
+	err := nm.initPlugins(map[string]*plugin{
+		"bridge": nm.plugins["bridge"],
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &localNamespaces{nm: nm}, tp.lns)
+}
+
 func TestInitAuthFail(t *testing.T) {
 	nm, nmm, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1046,7 +1100,7 @@ func TestEventsPluginDefaults(t *testing.T) {
 	defer cleanup()
 	plugins := make(map[string]*plugin)
 	err := nm.getEventPlugins(context.Background(), plugins, nm.dumpRootConfig())
-	assert.Equal(t, 3, len(plugins))
+	assert.Equal(t, 4, len(plugins))
 	assert.NoError(t, err)
 }
 
@@ -1315,6 +1369,65 @@ func TestLoadNamespacesUseDefaults(t *testing.T) {
 	assert.Equal(t, "oldest", newNS["ns1"].config.Multiparty.Contracts[0].FirstEvent)
 }
 
+func TestLoadNamespacesRetention(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  retention:
+    window: 720h
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      multiparty:
+        contract:
+        - location:
+          address: 0x1234
+    - name: ns2
+      plugins: [postgres]
+      multiparty:
+        enabled: false
+      retention:
+        window: 24h
+        archive: true
+  org:
+    name: org1
+  node:
+    name: node1
+  `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+	assert.Equal(t, 720*time.Hour, newNS["ns1"].config.Retention.Window)
+	assert.False(t, newNS["ns1"].config.Retention.Archive)
+	assert.Equal(t, 24*time.Hour, newNS["ns2"].config.Retention.Window)
+	assert.True(t, newNS["ns2"].config.Retention.Archive)
+}
+
+func TestLoadNamespacesRetentionBadWindow(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      retention:
+        window: forever
+  `))
+	assert.NoError(t, err)
+
+	nm.namespaces, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF10577.*forever.*ns1", err)
+}
+
 func TestLoadTLSConfigsBadTLS(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1517,6 +1630,152 @@ namespaces:
 	assert.Regexp(t, "FF00153", err)
 }
 
+func TestLoadSigningSecrets(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+namespaces:
+  default: ns1
+  predefined:
+  - name: ns1
+    signingSecrets:
+    - name: secret1
+      secret: shh
+    - name: secret2
+      secret: quiet
+  `))
+	assert.NoError(t, err)
+
+	signingSecrets, err := nm.loadSigningSecrets(nm.ctx, namespacePredefined.ArrayEntry(0).SubArray(coreconfig.NamespaceSigningSecrets))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"secret1": []byte("shh"),
+		"secret2": []byte("quiet"),
+	}, signingSecrets)
+}
+
+func TestLoadSigningSecretsDuplicate(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+namespaces:
+  default: ns1
+  predefined:
+  - name: ns1
+    signingSecrets:
+    - name: secret1
+      secret: shh
+    - name: secret1
+      secret: quiet
+  `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadSigningSecrets(nm.ctx, namespacePredefined.ArrayEntry(0).SubArray(coreconfig.NamespaceSigningSecrets))
+	assert.Regexp(t, "FF10626", err)
+}
+
+func TestLoadNamespacesInvalidSigningSecret(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+namespaces:
+  default: ns1
+  predefined:
+  - name: ns1
+    signingSecrets:
+    - name: secret1
+  `))
+	assert.NoError(t, err)
+
+	nm.namespaces, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF10627", err)
+}
+
+func TestLoadPoolConnectors(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+namespaces:
+  default: ns1
+  predefined:
+  - name: ns1
+    asset:
+      manager:
+        poolConnectors:
+        - pool: pool1
+          connector: erc20_erc721
+        - pool: pool2
+          connector: erc1155
+  `))
+	assert.NoError(t, err)
+
+	poolConnectors, err := nm.loadPoolConnectors(nm.ctx, namespacePredefined.ArrayEntry(0).SubArray(coreconfig.NamespaceAssetPoolConnectors))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"pool1": "erc20_erc721",
+		"pool2": "erc1155",
+	}, poolConnectors)
+}
+
+func TestLoadPoolConnectorsDuplicate(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+namespaces:
+  default: ns1
+  predefined:
+  - name: ns1
+    asset:
+      manager:
+        poolConnectors:
+        - pool: pool1
+          connector: erc20_erc721
+        - pool: pool1
+          connector: erc1155
+  `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadPoolConnectors(nm.ctx, namespacePredefined.ArrayEntry(0).SubArray(coreconfig.NamespaceAssetPoolConnectors))
+	assert.Regexp(t, "FF10485", err)
+}
+
+func TestLoadNamespacesInvalidPoolConnector(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+namespaces:
+  default: ns1
+  predefined:
+  - name: ns1
+    asset:
+      manager:
+        poolConnectors:
+        - pool: pool1
+  `))
+	assert.NoError(t, err)
+
+	nm.namespaces, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF10486", err)
+}
+
 func TestLoadNamespacesNonMultipartyNoDatabase(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1978,9 +2237,38 @@ func TestGetNamespaces(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
 
-	results, err := nm.GetNamespaces(context.Background(), true)
+	results, err := nm.GetNamespaces(context.Background(), true, false)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Nil(t, results[0].Plugins)
+	assert.Nil(t, results[0].Multiparty)
+}
+
+func TestGetNamespacesIncludePlugins(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nmm.mdi.On("Name").Return("postgres")
+	nmm.mbi.On("Name").Return("ethereum")
+
+	ns := nm.namespaces["default"]
+	ns.config.DefaultKey = "0x12345"
+	ns.config.Multiparty.Enabled = true
+	ns.plugins = &orchestrator.Plugins{
+		Blockchain: orchestrator.BlockchainPlugin{Name: "ethereum", Plugin: nmm.mbi},
+		Database:   orchestrator.DatabasePlugin{Name: "postgres", Plugin: nmm.mdi},
+		Events:     map[string]events.Plugin{"websockets": nmm.mei[0]},
+	}
+
+	results, err := nm.GetNamespaces(context.Background(), true, true)
 	assert.Nil(t, err)
 	assert.Len(t, results, 1)
+	assert.True(t, *results[0].Multiparty)
+	assert.Equal(t, "0x12345", results[0].DefaultKey)
+	assert.Equal(t, "ethereum", results[0].Plugins.Blockchain[0].PluginType)
+	assert.Equal(t, "postgres", results[0].Plugins.Database[0].Name)
+	assert.Equal(t, "websockets", results[0].Plugins.Events[0].PluginType)
+	assert.Empty(t, results[0].Plugins.Tokens)
 }
 
 func TestGetOperationByNamespacedID(t *testing.T) {
@@ -2135,3 +2423,210 @@ func TestOrchestratorWhileInitializing(t *testing.T) {
 	_, err := nm.Orchestrator(nm.ctx, "default", false)
 	assert.Regexp(t, "FF10441", err)
 }
+
+func TestInitHADatabase(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nm.haFactory = ha.NewHAManager
+	config.Set(coreconfig.HADatabase, "postgres")
+	err := nm.initHA()
+	assert.NoError(t, err)
+	assert.NotNil(t, nm.ha)
+}
+
+func TestInitHAFactoryFail(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nm.haFactory = func(ctx context.Context, locks ha.LockProvider, onDemoted func()) (ha.Manager, error) {
+		return nil, fmt.Errorf("pop")
+	}
+	err := nm.initHA()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestInitHAUnknownLockProvider(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	config.Set(coreconfig.HAEnabled, true)
+	config.Set(coreconfig.HALockProvider, "wrong")
+	nm.plugins = map[string]*plugin{}
+	err := nm.initComponents()
+	assert.Regexp(t, "FF10636", err)
+}
+
+func TestInitHAUnknownDatabase(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	config.Set(coreconfig.HADatabase, "wrong")
+	err := nm.initHA()
+	assert.Regexp(t, "FF10122", err)
+}
+
+func TestInitHADatabaseRequired(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nm.plugins["sqlite3"] = &plugin{name: "sqlite3", category: pluginCategoryDatabase, database: nmm.mdi}
+	err := nm.initHA()
+	assert.Regexp(t, "FF10637", err)
+}
+
+func TestStartPassiveNamespace(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	waitInit := namespaceInitWaiter(t, nmm, []string{"default"})
+
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(nil, nil)
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+	nmm.mo.On("Init").Return(nil)
+	nmm.mo.On("Start", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		nm.cancelCtx()
+	})
+	mha.On("IsActive").Return(false)
+	mha.On("WaitActive", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		if args[0].(context.Context) == nm.ctx {
+			return // plugins waiting to start
+		}
+		// Reads are served while the node waits to become active
+		or, err := nm.Orchestrator(nm.ctx, "default", false)
+		assert.NoError(t, err)
+		assert.Equal(t, nmm.mo, or)
+		namespaces, err := nm.GetNamespaces(nm.ctx, false, false)
+		assert.NoError(t, err)
+		assert.Len(t, namespaces, 1)
+		assert.False(t, namespaces[0].Initializing)
+	})
+
+	err := nm.startNamespacesAndPlugins(nm.namespaces, map[string]*plugin{})
+	assert.NoError(t, err)
+
+	waitInit.Wait()
+	mha.AssertExpectations(t)
+}
+
+func TestStartPassiveNamespaceCancelled(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	ns := nm.namespaces["default"]
+	ns.orchestrator = nmm.mo
+	ns.config.Multiparty.Enabled = false
+	ns.ctx = nm.ctx
+
+	nmm.mo.On("Init").Return(nil)
+	mha.On("IsActive").Return(false)
+	mha.On("WaitActive", mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := nm.initAndStartNamespace(ns)
+	assert.EqualError(t, err, "pop")
+	assert.True(t, ns.standby)
+	mha.AssertExpectations(t)
+}
+
+func TestStartPassivePlugins(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	started := make(chan struct{})
+	mha.On("IsActive").Return(false)
+	mha.On("WaitActive", mock.Anything).Return(nil)
+	nmm.mdx.On("Start").Return(nil).Run(func(args mock.Arguments) {
+		close(started)
+	})
+
+	err := nm.startNamespacesAndPlugins(map[string]*namespace{}, map[string]*plugin{
+		"ffdx": nm.plugins["ffdx"],
+	})
+	assert.NoError(t, err)
+
+	<-started
+	mha.AssertExpectations(t)
+}
+
+func TestPluginStarterNotActive(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	mha.On("WaitActive", mock.Anything).Return(fmt.Errorf("pop"))
+
+	nm.pluginStarter(map[string]*plugin{
+		"ffdx": nm.plugins["ffdx"],
+	})
+	mha.AssertExpectations(t)
+}
+
+func TestPluginStarterFail(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	mha.On("WaitActive", mock.Anything).Return(nil)
+	nmm.mdx.On("Start").Return(fmt.Errorf("pop"))
+
+	nm.pluginStarter(map[string]*plugin{
+		"ffdx": nm.plugins["ffdx"],
+	})
+	assert.Error(t, nm.ctx.Err())
+	mha.AssertExpectations(t)
+}
+
+func TestStartStopHA(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	nm.namespaces = map[string]*namespace{}
+	mha.On("Start").Return()
+	mha.On("IsActive").Return(true)
+	mha.On("WaitStop").Return()
+	nmm.mdx.On("Start").Return(nil)
+	nmm.mae.On("WaitStop").Return()
+
+	err := nm.Start()
+	assert.NoError(t, err)
+	nm.WaitStop()
+	mha.AssertExpectations(t)
+}
+
+func TestGetHAStatusDisabled(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	assert.True(t, nm.IsActive())
+	status, err := nm.GetHAStatus(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, status.Enabled)
+	assert.Equal(t, core.HAModeActive, status.Mode)
+}
+
+func TestGetHAStatus(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mha := &hamocks.Manager{}
+	nm.ha = mha
+	mha.On("IsActive").Return(false)
+	mha.On("GetStatus", mock.Anything).Return(&core.HAStatus{Enabled: true, Mode: core.HAModePassive}, nil)
+
+	assert.False(t, nm.IsActive())
+	status, err := nm.GetHAStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, core.HAModePassive, status.Mode)
+	mha.AssertExpectations(t)
+}
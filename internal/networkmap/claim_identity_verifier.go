@@ -0,0 +1,95 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// ClaimIdentityVerifier adds a new blockchain signing key to an existing identity, optionally deprecating
+// all of its other keys. The claim is signed by an existing key of the identity.
+func (nm *networkMap) ClaimIdentityVerifier(ctx context.Context, uuidStr string, dto *core.IdentityVerifierClaimDTO, waitConfirm bool) (*core.Verifier, error) {
+	id, err := fftypes.ParseUUID(ctx, uuidStr)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := nm.identity.CachedIdentityLookupByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil || identity.Namespace != nm.namespace {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	if identity.Type == core.IdentityTypeNode {
+		return nil, i18n.NewError(ctx, coremsgs.MsgVerifierClaimNodeIdentity, identity.DID)
+	}
+
+	if dto.Key == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlockchainKeyNotSet)
+	}
+	verifierRef, err := nm.identity.ResolveInputVerifierRef(ctx, &core.VerifierRef{Value: dto.Key}, blockchain.ResolveKeyIntentSign)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &core.IdentityVerifierClaim{
+		Identity: identity.IdentityBase,
+		Verifier: *verifierRef,
+	}
+	if dto.Deprecate {
+		fb := database.VerifierQueryFactory.NewFilter(ctx)
+		filter := fb.And(
+			fb.Eq("identity", identity.ID),
+			fb.Eq("type", verifierRef.Type),
+			fb.Eq("deprecated", nil),
+		)
+		existing, _, err := nm.database.GetVerifiers(ctx, nm.namespace, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range existing {
+			if v.Value != verifierRef.Value {
+				claim.Deprecate = append(claim.Deprecate, &core.VerifierRef{Type: v.Type, Value: v.Value})
+			}
+		}
+	}
+
+	var claimSigner *core.SignerRef
+	if nm.multiparty != nil {
+		// Resolve the signer of the original claim, which is updated to an active key if the original has been deprecated
+		claimSigner, err = nm.identity.ResolveIdentitySigner(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	verifier := (&core.Verifier{
+		Identity:    identity.ID,
+		Namespace:   identity.Namespace,
+		VerifierRef: *verifierRef,
+	}).Seal()
+	err = nm.defsender.ClaimIdentityVerifier(ctx, verifier, claim, claimSigner, waitConfirm)
+	return verifier, err
+}
@@ -0,0 +1,238 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/definitionsmocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClaimIdentityVerifierDeprecateOk(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+	newKey := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x67890"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, &core.VerifierRef{Value: "key2"}, blockchain.ResolveKeyIntentSign).Return(newKey, nil)
+	signerRef := &core.SignerRef{Author: identity.DID, Key: "0x12345"}
+	mim.On("ResolveIdentitySigner", nm.ctx, identity).Return(signerRef, nil)
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( identity == '%s' ) && ( type == 'ethereum_address' ) && ( deprecated == null )", identity.ID), fi.String())
+		return true
+	})).Return([]*core.Verifier{
+		testVerifier(identity, core.VerifierTypeEthAddress, "0x12345", nil),
+		testVerifier(identity, core.VerifierTypeEthAddress, "0x67890", nil),
+	}, nil, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ClaimIdentityVerifier", nm.ctx,
+		mock.MatchedBy(func(v *core.Verifier) bool {
+			return v.Value == "0x67890" && v.Identity.Equals(identity.ID) && v.Hash != nil
+		}),
+		mock.MatchedBy(func(claim *core.IdentityVerifierClaim) bool {
+			return claim.Verifier == *newKey &&
+				len(claim.Deprecate) == 1 &&
+				claim.Deprecate[0].Value == "0x12345"
+		}),
+		signerRef, true).Return(nil)
+
+	verifier, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{
+		Key:       "key2",
+		Deprecate: true,
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x67890", verifier.Value)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierNonMultiparty(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	nm.multiparty = nil
+
+	identity := testOrg("org1")
+	newKey := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x67890"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentSign).Return(newKey, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ClaimIdentityVerifier", nm.ctx, mock.Anything, mock.MatchedBy(func(claim *core.IdentityVerifierClaim) bool {
+		return claim.Deprecate == nil
+	}), (*core.SignerRef)(nil), false).Return(fmt.Errorf("pop"))
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{
+		Key: "key2",
+	}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+	mds.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierBadUUID(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, "bad", &core.IdentityVerifierClaimDTO{}, false)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestClaimIdentityVerifierLookupFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierNotFound(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+	identity.Namespace = "ns2"
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{}, false)
+	assert.Regexp(t, "FF10143", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierNode(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	node := testNode(testOrg("org1"), "node1", "")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, node.ID).Return(node, nil)
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, node.ID.String(), &core.IdentityVerifierClaimDTO{Key: "key2"}, false)
+	assert.Regexp(t, "FF10583", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierMissingKey(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{}, false)
+	assert.Regexp(t, "FF10352", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierResolveKeyFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentSign).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{Key: "key2"}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierGetVerifiersFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+	newKey := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x67890"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentSign).Return(newKey, nil)
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{Key: "key2", Deprecate: true}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestClaimIdentityVerifierResolveSignerFail(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	identity := testOrg("org1")
+	newKey := &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x67890"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", nm.ctx, identity.ID).Return(identity, nil)
+	mim.On("ResolveInputVerifierRef", nm.ctx, mock.Anything, blockchain.ResolveKeyIntentSign).Return(newKey, nil)
+	mim.On("ResolveIdentitySigner", nm.ctx, identity).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.ClaimIdentityVerifier(nm.ctx, identity.ID.String(), &core.IdentityVerifierClaimDTO{Key: "key2"}, false)
+	assert.Regexp(t, "pop", err)
+
+	mim.AssertExpectations(t)
+}
@@ -196,17 +196,36 @@ func (nm *networkMap) GetDIDDocForIndentityByID(ctx context.Context, id string)
 	if err != nil {
 		return nil, err
 	}
+	if identity.Namespace != nm.namespace {
+		// Never expose the document of an identity that belongs to another namespace
+		log.L(ctx).Warnf("Identity '%s' is in namespace '%s' not '%s'", identity.ID, identity.Namespace, nm.namespace)
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
 	return nm.generateDIDDocument(ctx, identity)
 }
 
 func (nm *networkMap) GetDIDDocForIndentityByDID(ctx context.Context, did string) (*DIDDocument, error) {
-	identity, err := nm.GetIdentityByDID(ctx, did)
+	identity, err := nm.getNamespaceIdentityByDID(ctx, did)
 	if err != nil {
 		return nil, err
 	}
 	return nm.generateDIDDocument(ctx, identity)
 }
 
+// getNamespaceIdentityByDID looks up the identity of a DID document. The DID lookup can fall back to the
+// legacy system namespace, so an identity that belongs to another namespace is treated as not found.
+func (nm *networkMap) getNamespaceIdentityByDID(ctx context.Context, did string) (*core.Identity, error) {
+	identity, err := nm.GetIdentityByDID(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Namespace != nm.namespace {
+		log.L(ctx).Warnf("Identity '%s' is in namespace '%s' not '%s'", identity.ID, identity.Namespace, nm.namespace)
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return identity, nil
+}
+
 func (nm *networkMap) GetVerifierByHash(ctx context.Context, hash string) (*core.Verifier, error) {
 	b32, err := fftypes.ParseBytes32(ctx, hash)
 	if err != nil {
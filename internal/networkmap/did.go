@@ -19,18 +19,38 @@ package networkmap
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
 
+// MaxDIDDocumentBatch is the maximum number of identities that can be resolved in a single call to ResolveDIDDocuments
+const MaxDIDDocumentBatch = 100
+
+// didResolveConcurrency bounds how many identities of a batch are resolved against the database at once
+const didResolveConcurrency = 10
+
 // DIDDocument - see https://www.w3.org/TR/did-core/#core-properties
 type DIDDocument struct {
 	Context             []string              `ffstruct:"DIDDocument" json:"@context"`
 	ID                  string                `ffstruct:"DIDDocument" json:"id"`
 	Authentication      []string              `ffstruct:"DIDDocument" json:"authentication"`
+	AssertionMethod     []string              `ffstruct:"DIDDocument" json:"assertionMethod,omitempty"`
 	VerificationMethods []*VerificationMethod `ffstruct:"DIDDocument" json:"verificationMethod"`
+	Services            []*DIDService         `ffstruct:"DIDDocument" json:"service,omitempty"`
+}
+
+// DIDService - see https://www.w3.org/TR/did-core/#services
+type DIDService struct {
+	ID              string `ffstruct:"DIDService" json:"id"`
+	Type            string `ffstruct:"DIDService" json:"type"`
+	ServiceEndpoint string `ffstruct:"DIDService" json:"serviceEndpoint"`
 }
 
 type VerificationMethod struct {
@@ -41,9 +61,44 @@ type VerificationMethod struct {
 	BlockchainAccountID string `ffstruct:"DIDVerificationMethod" json:"blockchainAcountId,omitempty"`
 	MSPIdentityString   string `ffstruct:"DIDVerificationMethod" json:"mspIdentityString,omitempty"`
 	DataExchangePeerID  string `ffstruct:"DIDVerificationMethod" json:"dataExchangePeerID,omitempty"`
+	// Validity period of the verifier - a deprecated verifier remains in the document, but cannot authenticate
+	ValidFrom  *fftypes.FFTime `ffstruct:"DIDVerificationMethod" json:"validFrom,omitempty"`
+	ValidUntil *fftypes.FFTime `ffstruct:"DIDVerificationMethod" json:"validUntil,omitempty"`
+}
+
+// DIDDocumentVerification is the result of checking a DID document against the confirmed identity claim
+type DIDDocumentVerification struct {
+	ID                  string                            `ffstruct:"DIDDocumentVerification" json:"id"`
+	Identity            *fftypes.UUID                     `ffstruct:"DIDDocumentVerification" json:"identity"`
+	Valid               bool                              `ffstruct:"DIDDocumentVerification" json:"valid"`
+	VerificationMethods []*VerificationMethodVerification `ffstruct:"DIDDocumentVerification" json:"verificationMethods"`
+}
+
+// DIDDocumentResult is the outcome of resolving one identity in a batch - either the document, or the reason it could not be resolved
+type DIDDocumentResult struct {
+	Document *DIDDocument `ffstruct:"DIDDocumentResult" json:"document,omitempty"`
+	Error    string       `ffstruct:"DIDDocumentResult" json:"error,omitempty"`
+}
+
+type VerificationMethodVerification struct {
+	ID    string `ffstruct:"DIDVerificationMethodVerification" json:"id"`
+	Valid bool   `ffstruct:"DIDVerificationMethodVerification" json:"valid"`
+	Error string `ffstruct:"DIDVerificationMethodVerification" json:"error,omitempty"`
 }
 
 func (nm *networkMap) generateDIDDocument(ctx context.Context, identity *core.Identity) (doc *DIDDocument, err error) {
+	doc, err = nm.generateDIDVerificationMethods(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	doc.Services, err = nm.generateDIDServices(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (nm *networkMap) generateDIDVerificationMethods(ctx context.Context, identity *core.Identity) (doc *DIDDocument, err error) {
 
 	fb := database.VerifierQueryFactory.NewFilter(ctx)
 	filter := fb.And(
@@ -66,13 +121,67 @@ func (nm *networkMap) generateDIDDocument(ctx context.Context, identity *core.Id
 	for _, verifier := range verifiers {
 		vm := nm.generateDIDAuthentication(ctx, identity, verifier)
 		if vm != nil {
+			vm.ValidFrom = verifier.Created
+			vm.ValidUntil = verifier.Deprecated
 			doc.VerificationMethods = append(doc.VerificationMethods, vm)
-			doc.Authentication = append(doc.Authentication, fmt.Sprintf("#%s", verifier.Hash.String()))
+			if verifier.Deprecated != nil {
+				continue
+			}
+			ref := fmt.Sprintf("#%s", verifier.Hash.String())
+			doc.Authentication = append(doc.Authentication, ref)
+			if verifier.Type != core.VerifierTypeFFDXPeerID {
+				// Blockchain keys can sign on behalf of the identity, but the DX peer ID only authenticates the node
+				doc.AssertionMethod = append(doc.AssertionMethod, ref)
+			}
 		}
 	}
 	return doc, nil
 }
 
+// generateDIDServices returns a service entry for the endpoint in the profile of each node that
+// belongs to the org that owns the identity (or the node itself, for a node identity)
+func (nm *networkMap) generateDIDServices(ctx context.Context, identity *core.Identity) ([]*DIDService, error) {
+	var nodes []*core.Identity
+	if identity.Type == core.IdentityTypeNode {
+		nodes = []*core.Identity{identity}
+	} else {
+		org := identity
+		for org.Type != core.IdentityTypeOrg && org.Parent != nil {
+			parent, err := nm.identity.CachedIdentityLookupByID(ctx, org.Parent)
+			if err != nil {
+				return nil, err
+			}
+			if parent == nil {
+				log.L(ctx).Warnf("Parent '%s' of identity '%s' not found - cannot add services to DID document", org.Parent, org.DID)
+				return nil, nil
+			}
+			org = parent
+		}
+		fb := database.IdentityQueryFactory.NewFilter(ctx)
+		filter := fb.And(
+			fb.Eq("type", core.IdentityTypeNode),
+			fb.Eq("parent", org.ID),
+		)
+		var err error
+		if nodes, _, err = nm.database.GetIdentities(ctx, nm.namespace, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	var services []*DIDService
+	for _, node := range nodes {
+		endpoint := node.Profile.GetString("endpoint")
+		if endpoint != "" {
+			services = append(services, &DIDService{
+				ID:              fmt.Sprintf("%s#%s", identity.DID, node.ID),
+				Type:            "FireFlyNodeEndpoint",
+				ServiceEndpoint: endpoint,
+			})
+		}
+	}
+	return services, nil
+}
+
 func (nm *networkMap) generateDIDAuthentication(ctx context.Context, identity *core.Identity, verifier *core.Verifier) *VerificationMethod {
 	switch verifier.Type {
 	case core.VerifierTypeEthAddress:
@@ -124,3 +233,105 @@ func (nm *networkMap) generateDXPeerIDVerifier(identity *core.Identity, verifier
 		DataExchangePeerID: verifier.Value,
 	}
 }
+
+// didMethodID returns the fully-qualified ID of a verification method, resolving a relative DID URL that is
+// just a fragment (such as "#key1"), or a bare fragment as FireFly publishes, against the given DID
+func didMethodID(did, id string) string {
+	switch {
+	case strings.HasPrefix(id, "#"):
+		return did + id
+	case !strings.Contains(id, "#"):
+		return did + "#" + id
+	default:
+		return id
+	}
+}
+
+// VerifyDIDDocument checks each verification method in a DID document that was received out-of-band,
+// against the verifiers registered for the identity in its confirmed claim.
+func (nm *networkMap) VerifyDIDDocument(ctx context.Context, doc *DIDDocument) (*DIDDocumentVerification, error) {
+	if doc.ID == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDDocumentMissingID)
+	}
+	identity, err := nm.getNamespaceIdentityByDID(ctx, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := nm.generateDIDVerificationMethods(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]*VerificationMethod, len(expected.VerificationMethods))
+	for _, vm := range expected.VerificationMethods {
+		registered[didMethodID(expected.ID, vm.ID)] = vm
+	}
+
+	result := &DIDDocumentVerification{
+		ID:                  doc.ID,
+		Identity:            identity.ID,
+		Valid:               len(doc.VerificationMethods) > 0,
+		VerificationMethods: make([]*VerificationMethodVerification, 0, len(doc.VerificationMethods)),
+	}
+	for _, vm := range doc.VerificationMethods {
+		vmResult := &VerificationMethodVerification{ID: vm.ID}
+		// The method must be one of the identity's own, so a method of another DID cannot be presented in
+		// this document by reusing the fragment of its ID
+		match, ok := registered[didMethodID(doc.ID, vm.ID)]
+		switch {
+		case !ok:
+			vmResult.Error = i18n.NewError(ctx, coremsgs.MsgDIDVerificationMethodUnknown, vm.ID, doc.ID).Error()
+		case vm.Controller != doc.ID ||
+			vm.Type != match.Type ||
+			vm.BlockchainAccountID != match.BlockchainAccountID ||
+			vm.MSPIdentityString != match.MSPIdentityString ||
+			vm.DataExchangePeerID != match.DataExchangePeerID:
+			vmResult.Error = i18n.NewError(ctx, coremsgs.MsgDIDVerificationMethodMismatch, vm.ID, doc.ID).Error()
+		default:
+			vmResult.Valid = true
+		}
+		result.Valid = result.Valid && vmResult.Valid
+		result.VerificationMethods = append(result.VerificationMethods, vmResult)
+	}
+	return result, nil
+}
+
+// ResolveDIDDocuments resolves a batch of identity UUIDs and/or DIDs concurrently, returning a result keyed
+// by each input. A failure to resolve one entry is returned in its result, rather than failing the batch.
+func (nm *networkMap) ResolveDIDDocuments(ctx context.Context, ids []string) (map[string]*DIDDocumentResult, error) {
+	if len(ids) > MaxDIDDocumentBatch {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDIDDocumentBatchTooLarge, len(ids), MaxDIDDocumentBatch)
+	}
+
+	// Build the result map up-front (which also de-duplicates the input), so each go routine only writes to its own entry
+	results := make(map[string]*DIDDocumentResult, len(ids))
+	for _, id := range ids {
+		results[id] = &DIDDocumentResult{}
+	}
+
+	slots := make(chan struct{}, didResolveConcurrency)
+	var wg sync.WaitGroup
+	for id, result := range results {
+		wg.Add(1)
+		go func(id string, result *DIDDocumentResult) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			doc, err := nm.resolveDIDDocument(ctx, id)
+			if err != nil {
+				log.L(ctx).Debugf("Failed to resolve DID document for '%s': %s", id, err)
+				result.Error = err.Error()
+				return
+			}
+			result.Document = doc
+		}(id, result)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (nm *networkMap) resolveDIDDocument(ctx context.Context, idOrDID string) (*DIDDocument, error) {
+	if !strings.HasPrefix(idOrDID, "did:") {
+		return nm.GetDIDDocForIndentityByID(ctx, idOrDID)
+	}
+	return nm.GetDIDDocForIndentityByDID(ctx, idOrDID)
+}
@@ -17,9 +17,11 @@
 package networkmap
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
@@ -80,6 +82,9 @@ func TestDIDGenerationOK(t *testing.T) {
 		Created: fftypes.Now(),
 	}).Seal()
 
+	node1 := testNode(org1, "node1", "https://node1.example.com")
+	node2 := testNode(org1, "node2", "")
+
 	mdi := nm.database.(*databasemocks.Plugin)
 	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(org1, nil)
 	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{
@@ -89,6 +94,12 @@ func TestDIDGenerationOK(t *testing.T) {
 		verifierDX,
 		verifierUnknown,
 	}, nil, nil)
+	mdi.On("GetIdentities", nm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( type == 'node' ) && ( parent == '%s' )", org1.ID), fi.String())
+		return true
+	})).Return([]*core.Identity{node1, node2}, nil, nil)
 
 	doc, err := nm.GetDIDDocForIndentityByID(nm.ctx, org1.ID.String())
 	assert.NoError(t, err)
@@ -104,24 +115,28 @@ func TestDIDGenerationOK(t *testing.T) {
 				Type:                "EcdsaSecp256k1VerificationKey2019",
 				Controller:          org1.DID,
 				BlockchainAccountID: verifierEth.Value,
+				ValidFrom:           verifierEth.Created,
 			},
 			{
 				ID:                  verifierTezos.Hash.String(),
 				Type:                "Ed25519VerificationKey2020",
 				Controller:          org1.DID,
 				BlockchainAccountID: verifierTezos.Value,
+				ValidFrom:           verifierTezos.Created,
 			},
 			{
 				ID:                verifierMSP.Hash.String(),
 				Type:              "HyperledgerFabricMSPIdentity",
 				Controller:        org1.DID,
 				MSPIdentityString: verifierMSP.Value,
+				ValidFrom:         verifierMSP.Created,
 			},
 			{
 				ID:                 verifierDX.Hash.String(),
 				Type:               "FireFlyDataExchangePeerIdentity",
 				Controller:         org1.DID,
 				DataExchangePeerID: verifierDX.Value,
+				ValidFrom:          verifierDX.Created,
 			},
 		},
 		Authentication: []string{
@@ -130,11 +145,153 @@ func TestDIDGenerationOK(t *testing.T) {
 			fmt.Sprintf("#%s", verifierMSP.Hash.String()),
 			fmt.Sprintf("#%s", verifierDX.Hash.String()),
 		},
+		AssertionMethod: []string{
+			fmt.Sprintf("#%s", verifierEth.Hash.String()),
+			fmt.Sprintf("#%s", verifierTezos.Hash.String()),
+			fmt.Sprintf("#%s", verifierMSP.Hash.String()),
+		},
+		Services: []*DIDService{
+			{
+				ID:              fmt.Sprintf("%s#%s", org1.DID, node1.ID),
+				Type:            "FireFlyNodeEndpoint",
+				ServiceEndpoint: "https://node1.example.com",
+			},
+		},
 	}, doc)
 
 	mdi.AssertExpectations(t)
 }
 
+func testNode(org *core.Identity, name, endpoint string) *core.Identity {
+	node := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:        fftypes.NewUUID(),
+			Type:      core.IdentityTypeNode,
+			Namespace: "ns1",
+			Name:      name,
+			Parent:    org.ID,
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": name,
+			},
+		},
+	}
+	if endpoint != "" {
+		node.Profile["endpoint"] = endpoint
+	}
+	node.DID, _ = node.GenerateDID(context.Background())
+	return node
+}
+
+func TestDIDGenerationNodeService(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	node1 := testNode(testOrg("org1"), "node1", "https://node1.example.com")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(node1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+
+	doc, err := nm.GetDIDDocForIndentityByID(nm.ctx, node1.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, []*DIDService{
+		{
+			ID:              fmt.Sprintf("%s#%s", node1.DID, node1.ID),
+			Type:            "FireFlyNodeEndpoint",
+			ServiceEndpoint: "https://node1.example.com",
+		},
+	}, doc.Services)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDIDGenerationCustomIdentityServices(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	node1 := testNode(org1, "node1", "https://node1.example.com")
+	parent := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeCustom, Namespace: "ns1", Parent: org1.ID, DID: "did:firefly:parent"},
+	}
+	custom := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeCustom, Namespace: "ns1", Parent: parent.ID, DID: "did:firefly:custom"},
+	}
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(custom, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	mdi.On("GetIdentities", nm.ctx, "ns1", mock.Anything).Return([]*core.Identity{node1}, nil, nil)
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupByID", nm.ctx, parent.ID).Return(parent, nil)
+	mii.On("CachedIdentityLookupByID", nm.ctx, org1.ID).Return(org1, nil)
+
+	doc, err := nm.GetDIDDocForIndentityByID(nm.ctx, custom.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, doc.Services, 1)
+	assert.Equal(t, "did:firefly:custom#"+node1.ID.String(), doc.Services[0].ID)
+
+	mdi.AssertExpectations(t)
+	mii.AssertExpectations(t)
+}
+
+func TestDIDGenerationCustomIdentityParentNotFound(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	custom := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeCustom, Namespace: "ns1", Parent: fftypes.NewUUID(), DID: "did:firefly:custom"},
+	}
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(custom, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupByID", nm.ctx, custom.Parent).Return(nil, nil)
+
+	doc, err := nm.GetDIDDocForIndentityByID(nm.ctx, custom.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, doc.Services)
+
+	mdi.AssertExpectations(t)
+	mii.AssertExpectations(t)
+}
+
+func TestDIDGenerationCustomIdentityParentFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	custom := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeCustom, Namespace: "ns1", Parent: fftypes.NewUUID(), DID: "did:firefly:custom"},
+	}
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(custom, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupByID", nm.ctx, custom.Parent).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetDIDDocForIndentityByID(nm.ctx, custom.ID.String())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestDIDGenerationGetNodesFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	mdi.On("GetIdentities", nm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetDIDDocForIndentityByID(nm.ctx, org1.ID.String())
+	assert.Regexp(t, "pop", err)
+}
+
 func TestDIDGenerationGetVerifiersFail(t *testing.T) {
 	nm, cancel := newTestNetworkmap(t)
 	defer cancel()
@@ -162,6 +319,20 @@ func TestDIDGenerationGetIdentityFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestDIDGenerationWrongNamespace(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	org1.Namespace = "ns2"
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", mock.Anything).Return(org1, nil)
+
+	_, err := nm.GetDIDDocForIndentityByID(nm.ctx, org1.ID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
 func TestDIDGenerationGetIdentityByDIDFail(t *testing.T) {
 	nm, cancel := newTestNetworkmap(t)
 	defer cancel()
@@ -184,7 +355,8 @@ func TestDIDGenerationGetIdentityByDIDFailVerifiers(t *testing.T) {
 	mii := nm.identity.(*identitymanagermocks.Manager)
 	mii.On("CachedIdentityLookupMustExist", nm.ctx, mock.Anything).Return(&core.Identity{
 		IdentityBase: core.IdentityBase{
-			ID: fftypes.NewUUID(),
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
 		},
 	}, false, nil)
 	mdi := nm.database.(*databasemocks.Plugin)
@@ -193,3 +365,281 @@ func TestDIDGenerationGetIdentityByDIDFailVerifiers(t *testing.T) {
 	_, err := nm.GetDIDDocForIndentityByDID(nm.ctx, org1.DID)
 	assert.Regexp(t, "pop", err)
 }
+
+func TestDIDGenerationGetIdentityByDIDOtherNamespace(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	// The lookup falls back to the legacy system namespace
+	legacy := testOrg("legacy")
+	legacy.Namespace = core.LegacySystemNamespace
+
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", nm.ctx, legacy.DID).Return(legacy, false, nil)
+
+	_, err := nm.GetDIDDocForIndentityByDID(nm.ctx, legacy.DID)
+	assert.Regexp(t, "FF10109", err)
+	_, err = nm.VerifyDIDDocument(nm.ctx, &DIDDocument{ID: legacy.DID})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestVerifyDIDDocument(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+
+	verifierEth := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0xc90d94dE1021fD17fAA2F1FC4F4D36Dff176120d",
+		},
+		Created: fftypes.Now(),
+	}).Seal()
+	verifierDX := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeFFDXPeerID,
+			Value: "peer1",
+		},
+		Created: fftypes.Now(),
+	}).Seal()
+
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", nm.ctx, org1.DID).Return(org1, false, nil)
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{
+		verifierEth,
+		verifierDX,
+	}, nil, nil)
+
+	result, err := nm.VerifyDIDDocument(nm.ctx, &DIDDocument{
+		ID: org1.DID,
+		VerificationMethods: []*VerificationMethod{
+			{
+				ID:                  fmt.Sprintf("%s#%s", org1.DID, verifierEth.Hash.String()),
+				Type:                "EcdsaSecp256k1VerificationKey2019",
+				Controller:          org1.DID,
+				BlockchainAccountID: verifierEth.Value,
+			},
+			{
+				ID:                 fmt.Sprintf("#%s", verifierDX.Hash.String()),
+				Type:               "FireFlyDataExchangePeerIdentity",
+				DataExchangePeerID: "peer2",
+			},
+			{
+				ID:                  "#unknown",
+				Type:                "EcdsaSecp256k1VerificationKey2019",
+				BlockchainAccountID: "0x12345",
+			},
+			{
+				ID:                  fmt.Sprintf("did:firefly:org/other#%s", verifierEth.Hash.String()),
+				Type:                "EcdsaSecp256k1VerificationKey2019",
+				Controller:          org1.DID,
+				BlockchainAccountID: verifierEth.Value,
+			},
+			{
+				ID:                  fmt.Sprintf("#%s", verifierEth.Hash.String()),
+				Type:                "EcdsaSecp256k1VerificationKey2019",
+				Controller:          "did:firefly:org/other",
+				BlockchainAccountID: verifierEth.Value,
+			},
+			{
+				ID:                  verifierEth.Hash.String(),
+				Type:                "EcdsaSecp256k1VerificationKey2019",
+				Controller:          org1.DID,
+				BlockchainAccountID: verifierEth.Value,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, org1.ID, result.Identity)
+	assert.False(t, result.Valid)
+	assert.Len(t, result.VerificationMethods, 6)
+	assert.True(t, result.VerificationMethods[0].Valid)
+	assert.Empty(t, result.VerificationMethods[0].Error)
+	assert.False(t, result.VerificationMethods[1].Valid)
+	assert.Regexp(t, "FF10480", result.VerificationMethods[1].Error)
+	assert.False(t, result.VerificationMethods[2].Valid)
+	assert.Regexp(t, "FF10479", result.VerificationMethods[2].Error)
+	assert.Regexp(t, "FF10479", result.VerificationMethods[3].Error)
+	assert.Regexp(t, "FF10480", result.VerificationMethods[4].Error)
+	assert.True(t, result.VerificationMethods[5].Valid)
+
+	mii.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestVerifyDIDDocumentAllValid(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+
+	verifierMSP := (&core.Verifier{
+		Identity:  org1.ID,
+		Namespace: org1.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  core.VerifierTypeMSPIdentity,
+			Value: "mspIdForAcme::x509::CN=fabric-ca::CN=user1",
+		},
+		Created: fftypes.Now(),
+	}).Seal()
+
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", nm.ctx, org1.DID).Return(org1, false, nil)
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{verifierMSP}, nil, nil)
+
+	result, err := nm.VerifyDIDDocument(nm.ctx, &DIDDocument{
+		ID: org1.DID,
+		VerificationMethods: []*VerificationMethod{
+			{
+				ID:                "#" + verifierMSP.Hash.String(),
+				Type:              "HyperledgerFabricMSPIdentity",
+				Controller:        org1.DID,
+				MSPIdentityString: verifierMSP.Value,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	mii.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestVerifyDIDDocumentMissingID(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.VerifyDIDDocument(nm.ctx, &DIDDocument{})
+	assert.Regexp(t, "FF10478", err)
+}
+
+func TestVerifyDIDDocumentGetIdentityFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", nm.ctx, "did:firefly:org/org1").Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := nm.VerifyDIDDocument(nm.ctx, &DIDDocument{ID: "did:firefly:org/org1"})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestVerifyDIDDocumentGetVerifiersFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", nm.ctx, org1.DID).Return(org1, false, nil)
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := nm.VerifyDIDDocument(nm.ctx, &DIDDocument{ID: org1.DID})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResolveDIDDocuments(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	org2 := testOrg("org2")
+	legacy := testOrg("legacy")
+	legacy.Namespace = core.LegacySystemNamespace
+	unknownID := fftypes.NewUUID()
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", mock.Anything, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetIdentityByID", mock.Anything, "ns1", unknownID).Return(nil, nil)
+	mdi.On("GetVerifiers", mock.Anything, "ns1", mock.Anything).Return([]*core.Verifier{}, nil, nil)
+	mdi.On("GetIdentities", mock.Anything, "ns1", mock.Anything).Return([]*core.Identity{}, nil, nil)
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", mock.Anything, org2.DID).Return(org2, false, nil)
+	mii.On("CachedIdentityLookupMustExist", mock.Anything, legacy.DID).Return(legacy, false, nil)
+	mii.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/missing").Return(nil, false, fmt.Errorf("pop"))
+
+	results, err := nm.ResolveDIDDocuments(nm.ctx, []string{
+		org1.ID.String(),
+		org2.DID,
+		org1.ID.String(),
+		unknownID.String(),
+		legacy.DID,
+		"did:firefly:org/missing",
+		"not-a-uuid",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 6)
+	assert.Equal(t, org1.DID, results[org1.ID.String()].Document.ID)
+	assert.Empty(t, results[org1.ID.String()].Error)
+	assert.Equal(t, org2.DID, results[org2.DID].Document.ID)
+	assert.Nil(t, results[unknownID.String()].Document)
+	assert.Regexp(t, "FF10109", results[unknownID.String()].Error)
+	assert.Nil(t, results[legacy.DID].Document)
+	assert.Regexp(t, "FF10109", results[legacy.DID].Error)
+	assert.Regexp(t, "pop", results["did:firefly:org/missing"].Error)
+	assert.Regexp(t, "FF00138", results["not-a-uuid"].Error)
+}
+
+func TestResolveDIDDocumentsTooMany(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	ids := make([]string, MaxDIDDocumentBatch+1)
+	for i := range ids {
+		ids[i] = fftypes.NewUUID().String()
+	}
+	_, err := nm.ResolveDIDDocuments(nm.ctx, ids)
+	assert.Regexp(t, "FF10519", err)
+}
+
+func TestResolveDIDDocumentsGetVerifiersFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+
+	mii := nm.identity.(*identitymanagermocks.Manager)
+	mii.On("CachedIdentityLookupMustExist", mock.Anything, org1.DID).Return(org1, false, nil)
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetVerifiers", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	results, err := nm.ResolveDIDDocuments(nm.ctx, []string{org1.DID})
+	assert.NoError(t, err)
+	assert.Nil(t, results[org1.DID].Document)
+	assert.Regexp(t, "pop", results[org1.DID].Error)
+}
+
+func TestDIDGenerationDeprecatedVerifier(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	verifierOld := testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.Now())
+	verifierOld.Deprecated = fftypes.Now()
+	verifierNew := testVerifier(org1, core.VerifierTypeEthAddress, "0x67890", fftypes.Now())
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{verifierOld, verifierNew}, nil, nil)
+	mdi.On("GetIdentities", nm.ctx, "ns1", mock.Anything).Return([]*core.Identity{}, nil, nil)
+
+	doc, err := nm.GetDIDDocForIndentityByID(nm.ctx, org1.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, doc.VerificationMethods, 2)
+	assert.Equal(t, verifierOld.Created, doc.VerificationMethods[0].ValidFrom)
+	assert.Equal(t, verifierOld.Deprecated, doc.VerificationMethods[0].ValidUntil)
+	assert.Nil(t, doc.VerificationMethods[1].ValidUntil)
+	newID := fmt.Sprintf("#%s", verifierNew.Hash)
+	assert.Equal(t, []string{newID}, doc.Authentication)
+	assert.Equal(t, []string{newID}, doc.AssertionMethod)
+
+	mdi.AssertExpectations(t)
+}
@@ -37,6 +37,7 @@ type Manager interface {
 	RegisterNodeOrganization(ctx context.Context, waitConfirm bool) (org *core.Identity, err error)
 	RegisterIdentity(ctx context.Context, dto *core.IdentityCreateDTO, waitConfirm bool) (identity *core.Identity, err error)
 	UpdateIdentity(ctx context.Context, id string, dto *core.IdentityUpdateDTO, waitConfirm bool) (identity *core.Identity, err error)
+	ClaimIdentityVerifier(ctx context.Context, id string, dto *core.IdentityVerifierClaimDTO, waitConfirm bool) (verifier *core.Verifier, err error)
 
 	GetOrganizationByNameOrID(ctx context.Context, nameOrID string) (*core.Identity, error)
 	GetOrganizations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Identity, *ffapi.FilterResult, error)
@@ -50,10 +51,13 @@ type Manager interface {
 	GetIdentities(ctx context.Context, filter ffapi.AndFilter) ([]*core.Identity, *ffapi.FilterResult, error)
 	GetIdentitiesWithVerifiers(ctx context.Context, filter ffapi.AndFilter) ([]*core.IdentityWithVerifiers, *ffapi.FilterResult, error)
 	GetIdentityVerifiers(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Verifier, *ffapi.FilterResult, error)
+	GetIdentityVerifierHistory(ctx context.Context, id string) ([]*VerifierHistoryEntry, error)
 	GetVerifiers(ctx context.Context, filter ffapi.AndFilter) ([]*core.Verifier, *ffapi.FilterResult, error)
 	GetVerifierByHash(ctx context.Context, hash string) (*core.Verifier, error)
 	GetDIDDocForIndentityByID(ctx context.Context, id string) (*DIDDocument, error)
 	GetDIDDocForIndentityByDID(ctx context.Context, did string) (*DIDDocument, error)
+	VerifyDIDDocument(ctx context.Context, doc *DIDDocument) (*DIDDocumentVerification, error)
+	ResolveDIDDocuments(ctx context.Context, ids []string) (map[string]*DIDDocumentResult, error)
 }
 
 type networkMap struct {
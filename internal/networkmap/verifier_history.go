@@ -0,0 +1,125 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// VerifierHistoryAction is the type of change recorded against a verifier of an identity
+type VerifierHistoryAction = fftypes.FFEnum
+
+var (
+	// VerifierHistoryActionClaimed the verifier was established by a confirmed identity claim
+	VerifierHistoryActionClaimed = fftypes.FFEnumValue("verifierhistoryaction", "claimed")
+	// VerifierHistoryActionDeprecated the verifier was deprecated by a verifier claim of the identity, and is no longer valid
+	VerifierHistoryActionDeprecated = fftypes.FFEnumValue("verifierhistoryaction", "deprecated")
+)
+
+// VerifierHistoryEntry records when a verifier became valid for an identity, and the message and
+// blockchain transaction that established it, so a signature can be checked against the keys that
+// were valid at the time it was made
+type VerifierHistoryEntry struct {
+	Action        VerifierHistoryAction `ffstruct:"VerifierHistoryEntry" json:"action" ffenum:"verifierhistoryaction"`
+	Hash          *fftypes.Bytes32      `ffstruct:"VerifierHistoryEntry" json:"hash"`
+	Verifier      core.VerifierRef      `ffstruct:"VerifierHistoryEntry" json:"verifier"`
+	Timestamp     *fftypes.FFTime       `ffstruct:"VerifierHistoryEntry" json:"timestamp"`
+	Message       *fftypes.UUID         `ffstruct:"VerifierHistoryEntry" json:"message,omitempty"`
+	Transaction   *fftypes.UUID         `ffstruct:"VerifierHistoryEntry" json:"tx,omitempty"`
+	BlockchainIDs fftypes.FFStringArray `ffstruct:"VerifierHistoryEntry" json:"blockchainIds,omitempty"`
+}
+
+// GetIdentityVerifierHistory returns the verifier changes for an identity, oldest first.
+// Verifiers are established by the identity claim, or by a later verifier claim, and are valid
+// from the claimed entry until any deprecated entry for the same verifier.
+func (nm *networkMap) GetIdentityVerifierHistory(ctx context.Context, id string) ([]*VerifierHistoryEntry, error) {
+	identity, err := nm.GetIdentityByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := database.VerifierQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("identity", identity.ID),
+	)
+	verifiers, _, err := nm.database.GetVerifiers(ctx, nm.namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*VerifierHistoryEntry, 0, len(verifiers))
+	for _, verifier := range verifiers {
+		// Verifiers added after the identity claim record their own claim message.
+		// Identities migrated from before claims were recorded have no claim message.
+		claimID := verifier.Message
+		if claimID == nil {
+			claimID = identity.Messages.Claim
+		}
+		claim, tx, err := nm.getVerifierClaim(ctx, claimID)
+		if err != nil {
+			return nil, err
+		}
+		entry := &VerifierHistoryEntry{
+			Action:    VerifierHistoryActionClaimed,
+			Hash:      verifier.Hash,
+			Verifier:  verifier.VerifierRef,
+			Timestamp: verifier.Created,
+		}
+		if claim != nil {
+			entry.Message = claim.Header.ID
+			if claim.Confirmed != nil {
+				entry.Timestamp = claim.Confirmed
+			}
+		}
+		if tx != nil {
+			entry.Transaction = tx.ID
+			entry.BlockchainIDs = tx.BlockchainIDs
+		}
+		history = append(history, entry)
+		if verifier.Deprecated != nil {
+			history = append(history, &VerifierHistoryEntry{
+				Action:    VerifierHistoryActionDeprecated,
+				Hash:      verifier.Hash,
+				Verifier:  verifier.VerifierRef,
+				Timestamp: verifier.Deprecated,
+			})
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp.UnixNano() < history[j].Timestamp.UnixNano()
+	})
+	return history, nil
+}
+
+func (nm *networkMap) getVerifierClaim(ctx context.Context, claimID *fftypes.UUID) (claim *core.Message, tx *core.Transaction, err error) {
+	if claimID != nil {
+		if claim, err = nm.database.GetMessageByID(ctx, nm.namespace, claimID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if claim != nil && claim.TransactionID != nil {
+		if tx, err = nm.database.GetTransactionByID(ctx, nm.namespace, claim.TransactionID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return claim, tx, nil
+}
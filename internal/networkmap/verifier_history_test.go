@@ -0,0 +1,227 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkmap
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testVerifier(identity *core.Identity, vType core.VerifierType, value string, created *fftypes.FFTime) *core.Verifier {
+	return (&core.Verifier{
+		Identity:  identity.ID,
+		Namespace: identity.Namespace,
+		VerifierRef: core.VerifierRef{
+			Type:  vType,
+			Value: value,
+		},
+		Created: created,
+	}).Seal()
+}
+
+func TestGetIdentityVerifierHistory(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	now := time.Now()
+	verifierDX := testVerifier(org1, core.VerifierTypeFFDXPeerID, "peer1", fftypes.UnixTime(now.Unix()+1))
+	verifierEth := testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.UnixTime(now.Unix()))
+	claim := &core.Message{
+		Header:        core.MessageHeader{ID: org1.Messages.Claim},
+		TransactionID: fftypes.NewUUID(),
+		Confirmed:     fftypes.Now(),
+	}
+	tx := &core.Transaction{
+		ID:            claim.TransactionID,
+		BlockchainIDs: fftypes.FFStringArray{"0xabcd"},
+	}
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{verifierDX, verifierEth}, nil, nil)
+	mdi.On("GetMessageByID", nm.ctx, "ns1", org1.Messages.Claim).Return(claim, nil)
+	mdi.On("GetTransactionByID", nm.ctx, "ns1", claim.TransactionID).Return(tx, nil)
+
+	history, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	for _, entry := range history {
+		assert.Equal(t, VerifierHistoryActionClaimed, entry.Action)
+		assert.Equal(t, claim.Header.ID, entry.Message)
+		assert.Equal(t, claim.Confirmed, entry.Timestamp)
+		assert.Equal(t, tx.ID, entry.Transaction)
+		assert.Equal(t, tx.BlockchainIDs, entry.BlockchainIDs)
+	}
+	assert.Equal(t, verifierDX.Hash, history[0].Hash)
+	assert.Equal(t, verifierDX.VerifierRef, history[0].Verifier)
+	assert.Equal(t, verifierEth.Hash, history[1].Hash)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetIdentityVerifierHistoryNoClaim(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	org1.Messages.Claim = nil
+	now := time.Now()
+	verifierDX := testVerifier(org1, core.VerifierTypeFFDXPeerID, "peer1", fftypes.UnixTime(now.Unix()+1))
+	verifierEth := testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.UnixTime(now.Unix()))
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{verifierDX, verifierEth}, nil, nil)
+
+	history, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, verifierEth.Hash, history[0].Hash)
+	assert.Equal(t, verifierEth.Created, history[0].Timestamp)
+	assert.Nil(t, history[0].Message)
+	assert.Nil(t, history[0].Transaction)
+	assert.Equal(t, verifierDX.Hash, history[1].Hash)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetIdentityVerifierHistoryUnconfirmedClaim(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	verifierEth := testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.Now())
+	claim := &core.Message{
+		Header: core.MessageHeader{ID: org1.Messages.Claim},
+	}
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{verifierEth}, nil, nil)
+	mdi.On("GetMessageByID", nm.ctx, "ns1", org1.Messages.Claim).Return(claim, nil)
+
+	history, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, claim.Header.ID, history[0].Message)
+	assert.Equal(t, verifierEth.Created, history[0].Timestamp)
+	assert.Nil(t, history[0].Transaction)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetIdentityVerifierHistoryGetIdentityFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	_, err := nm.GetIdentityVerifierHistory(nm.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetIdentityVerifierHistoryGetVerifiersFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetIdentityVerifierHistoryGetMessageFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{
+		testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.Now()),
+	}, nil, nil)
+	mdi.On("GetMessageByID", nm.ctx, "ns1", org1.Messages.Claim).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetIdentityVerifierHistoryGetTransactionFail(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	claim := &core.Message{
+		Header:        core.MessageHeader{ID: org1.Messages.Claim},
+		TransactionID: fftypes.NewUUID(),
+	}
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{
+		testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.Now()),
+	}, nil, nil)
+	mdi.On("GetMessageByID", nm.ctx, "ns1", org1.Messages.Claim).Return(claim, nil)
+	mdi.On("GetTransactionByID", nm.ctx, "ns1", claim.TransactionID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetIdentityVerifierHistoryRotated(t *testing.T) {
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+
+	org1 := testOrg("org1")
+	now := time.Now()
+	verifierOld := testVerifier(org1, core.VerifierTypeEthAddress, "0x12345", fftypes.UnixTime(now.Unix()))
+	verifierOld.Deprecated = fftypes.UnixTime(now.Unix() + 2)
+	verifierNew := testVerifier(org1, core.VerifierTypeEthAddress, "0x67890", fftypes.UnixTime(now.Unix()+1))
+	verifierNew.Message = fftypes.NewUUID()
+	verifierClaim := &core.Message{
+		Header: core.MessageHeader{ID: verifierNew.Message},
+	}
+
+	mdi := nm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", nm.ctx, "ns1", org1.ID).Return(org1, nil)
+	mdi.On("GetVerifiers", nm.ctx, "ns1", mock.Anything).Return([]*core.Verifier{verifierOld, verifierNew}, nil, nil)
+	mdi.On("GetMessageByID", nm.ctx, "ns1", org1.Messages.Claim).Return(nil, nil)
+	mdi.On("GetMessageByID", nm.ctx, "ns1", verifierNew.Message).Return(verifierClaim, nil)
+
+	history, err := nm.GetIdentityVerifierHistory(nm.ctx, org1.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, VerifierHistoryActionClaimed, history[0].Action)
+	assert.Equal(t, verifierOld.Hash, history[0].Hash)
+	assert.Nil(t, history[0].Message)
+	assert.Equal(t, VerifierHistoryActionClaimed, history[1].Action)
+	assert.Equal(t, verifierNew.Hash, history[1].Hash)
+	assert.Equal(t, verifierNew.Message, history[1].Message)
+	assert.Equal(t, VerifierHistoryActionDeprecated, history[2].Action)
+	assert.Equal(t, verifierOld.Hash, history[2].Hash)
+	assert.Equal(t, verifierOld.Deprecated, history[2].Timestamp)
+
+	mdi.AssertExpectations(t)
+}
@@ -0,0 +1,151 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// circuitBreaker fails operation submissions fast for a plugin that has failed repeatedly, to give it time to
+// recover. Once the cooldown has passed a single submission is let through to test the plugin, and the breaker
+// closes again if that succeeds.
+type circuitBreaker struct {
+	mux       sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	plugins   map[string]*pluginBreaker
+}
+
+type pluginBreaker struct {
+	state               core.CircuitBreakerState
+	consecutiveFailures int
+	opened              *fftypes.FFTime
+	lastError           string
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		plugins:   make(map[string]*pluginBreaker),
+	}
+}
+
+func (cb *circuitBreaker) enabled() bool {
+	return cb.threshold > 0
+}
+
+// allow returns an error if submissions to the plugin should currently fail fast
+func (cb *circuitBreaker) allow(ctx context.Context, plugin string) error {
+	if !cb.enabled() {
+		return nil
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	pb := cb.plugins[plugin]
+	if pb == nil || pb.state == core.CircuitBreakerStateClosed {
+		return nil
+	}
+	retryAt := fftypes.FFTime(pb.opened.Time().Add(cb.cooldown))
+	if pb.state == core.CircuitBreakerStateOpen && !time.Now().Before(*retryAt.Time()) {
+		log.L(ctx).Infof("Circuit breaker for plugin '%s' is half-open after cooldown - testing for recovery", plugin)
+		pb.state = core.CircuitBreakerStateHalfOpen
+		return nil
+	}
+	// Fail fast while open, and while half-open only the one submission testing for recovery is allowed through
+	return i18n.NewError(ctx, coremsgs.MsgCircuitBreakerOpen, plugin, pb.consecutiveFailures, retryAt.String())
+}
+
+// countsAsFailure is true for errors that show the plugin cannot be reached or is failing: connection
+// errors and 5xx responses from the connector. Client errors, such as a 4xx response from the connector or
+// input that FireFly rejects before submitting, show the plugin is responding.
+func countsAsFailure(err error) bool {
+	if conflictErr, ok := err.(ConflictError); ok && conflictErr.IsConflictError() {
+		return false
+	}
+	if connectorErr, ok := err.(ConnectorError); ok && connectorErr.ConnectorErrorDetail() != nil {
+		return connectorErr.ConnectorErrorDetail().StatusCode >= http.StatusInternalServerError
+	}
+	if ffErr, ok := err.(i18n.FFError); ok {
+		return ffErr.HTTPStatus() >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// record updates the breaker with the result of a submission to the plugin.
+// Errors that do not count as failures are recorded as success, as they show the plugin is responding.
+func (cb *circuitBreaker) record(ctx context.Context, plugin string, err error) {
+	if !cb.enabled() {
+		return
+	}
+	if err != nil && !countsAsFailure(err) {
+		err = nil
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	pb := cb.plugins[plugin]
+	if pb == nil {
+		pb = &pluginBreaker{state: core.CircuitBreakerStateClosed}
+		cb.plugins[plugin] = pb
+	}
+	if err == nil {
+		if pb.state != core.CircuitBreakerStateClosed {
+			log.L(ctx).Infof("Circuit breaker for plugin '%s' closed", plugin)
+		}
+		pb.state = core.CircuitBreakerStateClosed
+		pb.consecutiveFailures = 0
+		return
+	}
+	pb.consecutiveFailures++
+	pb.lastError = err.Error()
+	if pb.state == core.CircuitBreakerStateHalfOpen ||
+		(pb.state == core.CircuitBreakerStateClosed && pb.consecutiveFailures >= cb.threshold) {
+		log.L(ctx).Warnf("Circuit breaker for plugin '%s' opened after %d consecutive failures", plugin, pb.consecutiveFailures)
+		pb.state = core.CircuitBreakerStateOpen
+		pb.opened = fftypes.Now()
+	}
+}
+
+func (cb *circuitBreaker) status() []*core.CircuitBreakerStatus {
+	if !cb.enabled() {
+		return nil
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	status := make([]*core.CircuitBreakerStatus, 0, len(cb.plugins))
+	for plugin, pb := range cb.plugins {
+		status = append(status, &core.CircuitBreakerStatus{
+			Plugin:              plugin,
+			State:               pb.state,
+			ConsecutiveFailures: pb.consecutiveFailures,
+			Opened:              pb.opened,
+			LastError:           pb.lastError,
+		})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Plugin < status[j].Plugin })
+	return status
+}
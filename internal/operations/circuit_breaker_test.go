@@ -0,0 +1,167 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnectorError struct {
+	statusCode int
+}
+
+func (te *testConnectorError) Error() string {
+	return fmt.Sprintf("status %d", te.statusCode)
+}
+
+func (te *testConnectorError) ConnectorErrorDetail() *core.OperationError {
+	return &core.OperationError{StatusCode: te.statusCode}
+}
+
+func TestCircuitBreakerClientErrorsNotCounted(t *testing.T) {
+	cb := newCircuitBreaker(1, time.Minute)
+	ctx := context.Background()
+
+	cb.record(ctx, "ethereum", &testConnectorError{statusCode: 400})
+	cb.record(ctx, "ethereum", i18n.NewError(ctx, coremsgs.MsgPageCursorNotSupported, "created"))
+	cb.record(ctx, "ethereum", &mockConflictErr{err: fmt.Errorf("conflict")})
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	assert.Equal(t, 0, cb.status()[0].ConsecutiveFailures)
+
+	cb.record(ctx, "ethereum", &testConnectorError{statusCode: 503})
+	assert.Regexp(t, "FF10491", cb.allow(ctx, "ethereum"))
+}
+
+func TestCircuitBreakerServerErrorsCounted(t *testing.T) {
+	cb := newCircuitBreaker(1, time.Minute)
+	ctx := context.Background()
+
+	cb.record(ctx, "ethereum", i18n.NewError(ctx, coremsgs.MsgDXRESTErr, "connection refused"))
+	assert.Regexp(t, "FF10491", cb.allow(ctx, "ethereum"))
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	cb := newCircuitBreaker(0, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		cb.record(ctx, "ethereum", fmt.Errorf("pop"))
+	}
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	assert.Nil(t, cb.status())
+}
+
+func TestCircuitBreakerOpenHalfOpenClose(t *testing.T) {
+	cb := newCircuitBreaker(2, time.Minute)
+	ctx := context.Background()
+
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	cb.record(ctx, "ethereum", fmt.Errorf("pop"))
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	cb.record(ctx, "ethereum", fmt.Errorf("bang"))
+	cb.record(ctx, "erc20", nil)
+
+	err := cb.allow(ctx, "ethereum")
+	assert.Regexp(t, "FF10491.*ethereum.*2", err)
+	assert.NoError(t, cb.allow(ctx, "erc20"))
+
+	status := cb.status()
+	assert.Len(t, status, 2)
+	assert.Equal(t, "erc20", status[0].Plugin)
+	assert.Equal(t, core.CircuitBreakerStateClosed, status[0].State)
+	assert.Equal(t, "ethereum", status[1].Plugin)
+	assert.Equal(t, core.CircuitBreakerStateOpen, status[1].State)
+	assert.Equal(t, 2, status[1].ConsecutiveFailures)
+	assert.Equal(t, "bang", status[1].LastError)
+	assert.NotNil(t, status[1].Opened)
+
+	// Cooldown expires - a single submission is let through
+	opened := fftypes.FFTime(time.Now().Add(-2 * time.Minute))
+	cb.plugins["ethereum"].opened = &opened
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	assert.Equal(t, core.CircuitBreakerStateHalfOpen, cb.plugins["ethereum"].state)
+	assert.Regexp(t, "FF10491", cb.allow(ctx, "ethereum"))
+
+	// Test submission fails - opened again
+	cb.record(ctx, "ethereum", fmt.Errorf("pop"))
+	assert.Equal(t, core.CircuitBreakerStateOpen, cb.plugins["ethereum"].state)
+	assert.Equal(t, 3, cb.plugins["ethereum"].consecutiveFailures)
+	assert.Regexp(t, "FF10491", cb.allow(ctx, "ethereum"))
+
+	// Test submission succeeds - closed
+	cb.plugins["ethereum"].opened = &opened
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	cb.record(ctx, "ethereum", nil)
+	assert.Equal(t, core.CircuitBreakerStateClosed, cb.plugins["ethereum"].state)
+	assert.Equal(t, 0, cb.plugins["ethereum"].consecutiveFailures)
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+}
+
+func TestCircuitBreakerConflictIsSuccess(t *testing.T) {
+	cb := newCircuitBreaker(1, time.Minute)
+	ctx := context.Background()
+
+	cb.record(ctx, "ethereum", &mockConflictErr{err: fmt.Errorf("conflict")})
+	assert.NoError(t, cb.allow(ctx, "ethereum"))
+	assert.Equal(t, 0, cb.status()[0].ConsecutiveFailures)
+}
+
+func TestRunOperationCircuitBreakerOpen(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.breaker = newCircuitBreaker(1, time.Minute)
+
+	om.updater.workQueues = []chan *core.OperationUpdate{
+		make(chan *core.OperationUpdate, 2),
+	}
+
+	ctx := context.Background()
+	op := &core.PreparedOperation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Plugin:    "ethereum",
+		Type:      core.OpTypeBlockchainPinBatch,
+	}
+
+	om.RegisterHandler(ctx, &mockHandler{
+		RunErr: fmt.Errorf("pop"),
+		Phase:  core.OpPhasePending,
+	}, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.EqualError(t, err, "pop")
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusPending, update.Status)
+
+	// Second submission fails fast, without reaching the handler
+	_, err = om.RunOperation(ctx, op, true)
+	assert.Regexp(t, "FF10491", err)
+	update = <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusInitialized, update.Status)
+
+	status := om.CircuitBreakerStatus()
+	assert.Len(t, status, 1)
+	assert.Equal(t, core.CircuitBreakerStateOpen, status[0].State)
+}
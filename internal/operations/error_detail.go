@@ -0,0 +1,94 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const redactedValue = "***"
+
+type errorDetailConf struct {
+	maxSize      int
+	redactFields []string
+}
+
+// errorDetail extracts the structured connector response from a failed submission, so it can be preserved
+// on the operation. Configured secret fields are redacted and the body is capped in size before it is stored,
+// as it is returned to API callers on request.
+func (om *operationsManager) errorDetail(err error) *core.OperationError {
+	connectorErr, ok := err.(ConnectorError)
+	if !ok || connectorErr.ConnectorErrorDetail() == nil {
+		return nil
+	}
+	detail := *connectorErr.ConnectorErrorDetail()
+	detail.Body = redactJSONFields(detail.Body, om.errorDetailConf.redactFields)
+	if len(detail.Body) > om.errorDetailConf.maxSize {
+		cut := om.errorDetailConf.maxSize
+		for cut > 0 && !utf8.RuneStart(detail.Body[cut]) {
+			cut--
+		}
+		detail.Body = detail.Body[:cut]
+		detail.Truncated = true
+	}
+	return &detail
+}
+
+// redactJSONFields replaces the values of the named fields at any depth of a JSON body.
+// Bodies that are not JSON are returned unchanged.
+func redactJSONFields(body string, fields []string) string {
+	if len(fields) == 0 {
+		return body
+	}
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return body
+	}
+	redacted, _ := json.Marshal(redactValue(parsed, fields))
+	return string(redacted)
+}
+
+func redactValue(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isRedactedField(key, fields) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(child, fields)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child, fields)
+		}
+	}
+	return value
+}
+
+func isRedactedField(key string, fields []string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockConnectorErr struct {
+	err    error
+	detail *core.OperationError
+}
+
+func (ce *mockConnectorErr) Error() string {
+	return ce.err.Error()
+}
+
+func (ce *mockConnectorErr) ConnectorErrorDetail() *core.OperationError {
+	return ce.detail
+}
+
+func TestRunOperationFailConnectorDetail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	om.updater.workQueues = []chan *core.OperationUpdate{
+		make(chan *core.OperationUpdate, 1),
+	}
+
+	ctx := context.Background()
+	op := &core.PreparedOperation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.OpTypeBlockchainInvoke,
+	}
+
+	detail := &core.OperationError{StatusCode: 500, Code: "FF23021", Body: `{"error":"FF23021: EVM reverted"}`}
+	om.RegisterHandler(ctx, &mockHandler{
+		RunErr: &mockConnectorErr{err: fmt.Errorf("pop"), detail: detail},
+	}, []core.OpType{core.OpTypeBlockchainInvoke})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.EqualError(t, err, "pop")
+
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Equal(t, detail, update.ErrorDetail)
+}
+
+func TestResolveOperationErrorDetail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	om.cacheOperation(&core.Operation{ID: opID, Status: core.OpStatusPending})
+	detail := &core.OperationError{StatusCode: 500, Body: "reverted"}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		return info.SetOperations[2].Field == "lasterror" &&
+			info.SetOperations[2].Value.(fmt.Stringer).String() == `{"statusCode":500,"body":"reverted"}`
+	})).Return(true, nil)
+
+	errMsg := "pop"
+	err := om.updater.resolveOperation(context.Background(), "ns1", opID, core.OpStatusFailed, &errMsg, detail, nil)
+	assert.NoError(t, err)
+
+	op, err := om.GetOperationByIDCached(context.Background(), opID)
+	assert.NoError(t, err)
+	assert.Equal(t, detail, op.LastError)
+	mdi.AssertExpectations(t)
+}
+
+func TestErrorDetailNotConnectorError(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	assert.Nil(t, om.errorDetail(fmt.Errorf("pop")))
+	assert.Nil(t, om.errorDetail(&mockConnectorErr{err: fmt.Errorf("pop")}))
+}
+
+func TestErrorDetailRedactAndTruncate(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.errorDetailConf = errorDetailConf{
+		maxSize:      60,
+		redactFields: []string{"password", "privateKey"},
+	}
+
+	detail := om.errorDetail(&mockConnectorErr{err: fmt.Errorf("pop"), detail: &core.OperationError{
+		StatusCode: 500,
+		Body:       `{"error":"failed","request":{"PrivateKey":"0x1234","params":[{"password":"secret"}]}}`,
+	}})
+	assert.Equal(t, `{"error":"failed","request":{"PrivateKey":"***","params":[{"password":"***"}]}}`[:60], detail.Body)
+	assert.True(t, detail.Truncated)
+	assert.Equal(t, 500, detail.StatusCode)
+}
+
+func TestErrorDetailTruncateRuneBoundary(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.errorDetailConf = errorDetailConf{maxSize: 5}
+
+	detail := om.errorDetail(&mockConnectorErr{err: fmt.Errorf("pop"), detail: &core.OperationError{
+		Body: "abcd" + strings.Repeat("é", 3),
+	}})
+	assert.Equal(t, "abcd", detail.Body)
+	assert.True(t, detail.Truncated)
+}
+
+func TestRedactJSONFields(t *testing.T) {
+	assert.Equal(t, "not json", redactJSONFields("not json", []string{"password"}))
+	assert.Equal(t, `{"password":"secret"}`, redactJSONFields(`{"password":"secret"}`, nil))
+	assert.Equal(t, `[{"password":"***"},"x"]`, redactJSONFields(`[{"password":"secret"},"x"]`, []string{"password"}))
+}
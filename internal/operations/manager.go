@@ -20,16 +20,23 @@ import (
 	"context"
 	"database/sql/driver"
 	"fmt"
+	"sync"
+	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type OperationHandler interface {
@@ -44,12 +51,17 @@ type Manager interface {
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
 	RunOperation(ctx context.Context, op *core.PreparedOperation, idempotentSubmit bool) (fftypes.JSONObject, error)
 	RetryOperation(ctx context.Context, opID *fftypes.UUID) (*core.Operation, error)
+	RetryOperations(ctx context.Context, opIDs []*fftypes.UUID, filter ffapi.AndFilter) ([]*core.OperationRetryResult, error)
+	GetOperationRetryChain(ctx context.Context, opID *fftypes.UUID) ([]*core.Operation, error)
 	ResubmitOperations(ctx context.Context, txID *fftypes.UUID) (total int, resubmit []*core.Operation, err error)
 	AddOrReuseOperation(ctx context.Context, op *core.Operation, hooks ...database.PostCompletionHook) error
 	BulkInsertOperations(ctx context.Context, ops ...*core.Operation) error
 	SubmitOperationUpdate(update *core.OperationUpdate)
+	UpdateTransferProgress(ctx context.Context, nsOpID string, transferred, size int64) error
 	GetOperationByIDCached(ctx context.Context, opID *fftypes.UUID) (*core.Operation, error)
 	ResolveOperationByID(ctx context.Context, opID *fftypes.UUID, op *core.OperationUpdateDTO) error
+	NotifyOperation(ctx context.Context, opID *fftypes.UUID, input *core.OperationNotifyInput) (*core.Operation, error)
+	CircuitBreakerStatus() []*core.CircuitBreakerStatus
 	Start() error
 	WaitStop()
 }
@@ -59,6 +71,11 @@ type ConflictError interface {
 	IsConflictError() bool
 }
 
+// ConnectorError can be implemented by errors from plugins, to preserve the structured response from the connector
+type ConnectorError interface {
+	ConnectorErrorDetail() *core.OperationError
+}
+
 func ErrTernary(err error, ifErr, ifNoError core.OpPhase) core.OpPhase {
 	phase := ifErr
 	if err == nil {
@@ -75,9 +92,17 @@ type operationsManager struct {
 	txHelper  txcommon.Helper
 	updater   *operationUpdater
 	cache     cache.CInterface
+	breaker   *circuitBreaker
+	notifier  *operationNotifier
+
+	outputSchemas    map[core.OpType]*jsonschema.Schema
+	errorDetailConf  errorDetailConf
+	retryPolicies    map[core.OpType]*retryPolicy
+	progressInterval time.Duration
+	retries          sync.WaitGroup
 }
 
-func NewOperationsManager(ctx context.Context, ns string, di database.Plugin, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
+func NewOperationsManager(ctx context.Context, ns string, signingSecrets map[string][]byte, di database.Plugin, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
 	if di == nil || txHelper == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "OperationsManager")
 	}
@@ -95,12 +120,34 @@ func NewOperationsManager(ctx context.Context, ns string, di database.Plugin, tx
 		return nil, err
 	}
 
+	outputSchemas, err := loadOutputSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicies, err := loadRetryPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	om := &operationsManager{
 		ctx:       ctx,
 		namespace: ns,
 		database:  di,
 		txHelper:  txHelper,
 		handlers:  make(map[core.OpType]OperationHandler),
+		breaker: newCircuitBreaker(
+			config.GetInt(coreconfig.OperationsCircuitBreakerFailureThreshold),
+			config.GetDuration(coreconfig.OperationsCircuitBreakerCooldown),
+		),
+		outputSchemas: outputSchemas,
+		errorDetailConf: errorDetailConf{
+			maxSize:      int(config.GetByteSize(coreconfig.OperationsErrorDetailMaxSize)),
+			redactFields: config.GetStringSlice(coreconfig.OperationsErrorDetailRedactFields),
+		},
+		notifier:         newOperationNotifier(ctx, ns, signingSecrets, di),
+		retryPolicies:    retryPolicies,
+		progressInterval: config.GetDuration(coreconfig.OperationsTransferProgressInterval),
 	}
 	om.updater = newOperationUpdater(ctx, om, di, txHelper)
 	om.cache = cache
@@ -169,7 +216,18 @@ func (om *operationsManager) RunOperation(ctx context.Context, op *core.Prepared
 	}
 	log.L(ctx).Infof("Executing %s operation %s via handler %s", op.Type, op.ID, handler.Name())
 	log.L(ctx).Tracef("Operation detail: %+v", op)
-	outputs, phase, err := handler.RunOperation(ctx, op)
+	ctx, span := tracing.StartSpan(ctx, "operation "+string(op.Type),
+		attribute.String("firefly.namespace", op.Namespace),
+		attribute.String("firefly.operation.id", op.ID.String()),
+		attribute.String("firefly.plugin", op.Plugin),
+	)
+	var outputs fftypes.JSONObject
+	phase := core.OpPhaseInitializing // nothing is submitted if the circuit breaker is open
+	err := om.breaker.allow(ctx, op.Plugin)
+	if err == nil {
+		outputs, phase, err = handler.RunOperation(ctx, op)
+		om.breaker.record(ctx, op.Plugin, err)
+	}
 	if err != nil {
 		conflictErr, conflictTestOk := err.(ConflictError)
 		var failState core.OpStatus
@@ -198,6 +256,7 @@ func (om *operationsManager) RunOperation(ctx context.Context, op *core.Prepared
 			Plugin:         op.Plugin,
 			Status:         failState,
 			ErrorMessage:   err.Error(),
+			ErrorDetail:    om.errorDetail(err),
 			Output:         outputs,
 		})
 	} else {
@@ -216,18 +275,71 @@ func (om *operationsManager) RunOperation(ctx context.Context, op *core.Prepared
 			Output:         outputs,
 		})
 	}
+	tracing.EndSpan(span, err)
 	return outputs, err
 }
 
-func (om *operationsManager) findLatestRetry(ctx context.Context, opID *fftypes.UUID) (op *core.Operation, err error) {
-	op, err = om.GetOperationByIDCached(ctx, opID)
+func (om *operationsManager) CircuitBreakerStatus() []*core.CircuitBreakerStatus {
+	return om.breaker.status()
+}
+
+// RetryOperations retries each of the supplied operations - or if none are supplied, the failed operations
+// matching the filter that have not already been retried. Each operation is only retried if the latest
+// operation in its retry chain has failed, and the outcome is reported for each operation individually.
+func (om *operationsManager) RetryOperations(ctx context.Context, opIDs []*fftypes.UUID, filter ffapi.AndFilter) ([]*core.OperationRetryResult, error) {
+	if len(opIDs) == 0 {
+		fb := filter.Builder()
+		ops, _, err := om.database.GetOperations(ctx, om.namespace, filter.Condition(fb.Eq("status", core.OpStatusFailed)).Condition(fb.Eq("retry", nil)))
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range ops {
+			opIDs = append(opIDs, op.ID)
+		}
+	}
+	results := make([]*core.OperationRetryResult, len(opIDs))
+	for i, opID := range opIDs {
+		results[i] = om.retryFailedOperation(ctx, opID)
+	}
+	return results, nil
+}
+
+func (om *operationsManager) retryFailedOperation(ctx context.Context, opID *fftypes.UUID) *core.OperationRetryResult {
+	result := &core.OperationRetryResult{ID: opID}
+	latest, err := om.findLatestRetry(ctx, opID)
+	if err == nil && latest.Status != core.OpStatusFailed {
+		err = i18n.NewError(ctx, coremsgs.MsgOperationNotRetryable, latest.ID, latest.Status)
+	}
+	if err == nil {
+		result.Operation, err = om.RetryOperation(ctx, opID)
+		result.Retried = result.Operation != nil
+	}
 	if err != nil {
-		return nil, err
+		log.L(ctx).Warnf("Bulk retry of operation %s: %s", opID, err)
+		result.Error = err.Error()
 	}
-	if op.Retry == nil {
-		return op, nil
+	return result
+}
+
+// findLatestRetry follows the retry of each operation from the one with the ID, to the latest retry. The walk is
+// bounded like that of the retry chain, so corrupt linkage in the database returns an error rather than looping.
+func (om *operationsManager) findLatestRetry(ctx context.Context, opID *fftypes.UUID) (op *core.Operation, err error) {
+	w := &retryChainWalk{opID: opID, visited: map[fftypes.UUID]bool{}}
+	for nextID := opID; ; nextID = op.Retry {
+		op, err = om.GetOperationByIDCached(ctx, nextID)
+		if err != nil {
+			return nil, err
+		}
+		if op == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		if err := w.visit(ctx, op); err != nil {
+			return nil, err
+		}
+		if op.Retry == nil {
+			return op, nil
+		}
 	}
-	return om.findLatestRetry(ctx, op.Retry)
 }
 
 func (om *operationsManager) RetryOperation(ctx context.Context, opID *fftypes.UUID) (op *core.Operation, err error) {
@@ -256,17 +368,26 @@ func (om *operationsManager) RetryOperation(ctx context.Context, opID *fftypes.U
 		op.Output = nil
 		op.Created = fftypes.Now()
 		op.Updated = op.Created
+		op.RetryDepth = parent.RetryDepth + 1
+		op.RetryParent = parent.ID
+		op.RetryAt = nil
 		if err = om.database.InsertOperation(ctx, op); err != nil {
 			return err
 		}
-		om.cacheOperation(op)
 
-		// Update the old operation to point to the new one
-		update := database.OperationQueryFactory.NewUpdate(ctx).Set("retry", op.ID)
-		om.updateCachedOperation(opID, "", nil, nil, op.ID)
-		if _, err := om.database.UpdateOperation(ctx, om.namespace, opID, nil, update); err != nil {
+		// Update the latest operation in the chain to point to the new one - which might not be the
+		// operation requested, if that has already been retried. The update only applies if no retry
+		// has been linked in the meantime, so concurrent manual, bulk and automatic retries of the same
+		// operation cannot all submit it.
+		fb := database.OperationQueryFactory.NewFilter(ctx)
+		update := database.OperationQueryFactory.NewUpdate(ctx).Set("retry", op.ID).Set("retryat", nil)
+		claimed, err := om.database.UpdateOperation(ctx, om.namespace, parent.ID, fb.And(fb.Eq("retry", nil)), update)
+		if err != nil {
 			return err
 		}
+		if !claimed {
+			return i18n.NewError(ctx, coremsgs.MsgOperationAlreadyRetried, parent.ID)
+		}
 
 		po, err = om.PrepareOperation(ctx, op)
 		return err
@@ -274,6 +395,8 @@ func (om *operationsManager) RetryOperation(ctx context.Context, opID *fftypes.U
 	if err != nil {
 		return nil, err
 	}
+	om.cacheOperation(op)
+	om.updateCachedOperation(op.RetryParent, "", nil, nil, nil, op.ID)
 
 	log.L(ctx).Debugf("Retry initiation for operation %s idempotencyKey=%s", po.NamespacedIDString(), idempotencyKey)
 	_, err = om.RunOperation(ctx, po, idempotencyKey != "")
@@ -281,7 +404,21 @@ func (om *operationsManager) RetryOperation(ctx context.Context, opID *fftypes.U
 }
 
 func (om *operationsManager) ResolveOperationByID(ctx context.Context, opID *fftypes.UUID, op *core.OperationUpdateDTO) error {
-	return om.updater.resolveOperation(ctx, om.namespace, opID, op.Status, op.Error, op.Output)
+	if len(om.outputSchemas) > 0 && op.Output != nil {
+		existing, err := om.GetOperationByIDCached(ctx, opID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if err := om.validateOutput(ctx, existing.Type, op.Output); err != nil {
+				errMsg := err.Error()
+				op.Status = core.OpStatusFailed
+				op.Error = &errMsg
+				op.Output = nil
+			}
+		}
+	}
+	return om.updater.resolveOperation(ctx, om.namespace, opID, op.Status, op.Error, nil, op.Output)
 }
 
 func (om *operationsManager) SubmitOperationUpdate(update *core.OperationUpdate) {
@@ -293,13 +430,57 @@ func (om *operationsManager) SubmitOperationUpdate(update *core.OperationUpdate)
 	om.updater.SubmitOperationUpdate(om.ctx, update)
 }
 
+// UpdateTransferProgress records the bytes sent so far by a pending transfer. Progress reports can arrive
+// out of order, so only a report that is further along than the one recorded is stored. To limit database
+// writes, a report that arrives within the progress interval of the last one stored is dropped, unless it
+// reports that the transfer is complete.
+func (om *operationsManager) UpdateTransferProgress(ctx context.Context, nsOpID string, transferred, size int64) error {
+	_, opID, err := core.ParseNamespacedOpID(ctx, nsOpID)
+	if err != nil {
+		return err
+	}
+	op, err := om.GetOperationByIDCached(ctx, opID)
+	if err != nil {
+		return err
+	}
+	if op == nil || op.Status != core.OpStatusPending {
+		log.L(ctx).Debugf("Ignoring transfer progress for operation %s that is not pending", nsOpID)
+		return nil
+	}
+	if op.Progress != nil {
+		if transferred <= op.Progress.Transferred {
+			return nil
+		}
+		if transferred < size && op.Progress.Updated != nil && time.Since(*op.Progress.Updated.Time()) < om.progressInterval {
+			return nil
+		}
+	}
+
+	progress := &core.TransferProgress{
+		Transferred: transferred,
+		Size:        size,
+		Updated:     fftypes.Now(),
+	}
+	update := database.OperationQueryFactory.NewUpdate(ctx).Set("progress", progress)
+	if _, err := om.database.UpdateOperation(ctx, om.namespace, opID, nil, update); err != nil {
+		return err
+	}
+	op.Progress = progress
+	return nil
+}
+
 func (om *operationsManager) Start() error {
 	om.updater.start()
-	return nil
+	if err := om.scheduleDueRetries(om.ctx); err != nil {
+		return err
+	}
+	return om.deliverStoredNotifications(om.ctx)
 }
 
 func (om *operationsManager) WaitStop() {
 	om.updater.close()
+	om.notifier.deliveries.Wait()
+	om.retries.Wait()
 }
 
 func (om *operationsManager) GetOperationByIDCached(ctx context.Context, opID *fftypes.UUID) (*core.Operation, error) {
@@ -349,7 +530,7 @@ func (om *operationsManager) cacheOperation(op *core.Operation) {
 	om.cache.Set(op.ID.String(), op)
 }
 
-func (om *operationsManager) updateCachedOperation(id *fftypes.UUID, status core.OpStatus, errorMsg *string, output fftypes.JSONObject, retry *fftypes.UUID) {
+func (om *operationsManager) updateCachedOperation(id *fftypes.UUID, status core.OpStatus, errorMsg *string, errorDetail *core.OperationError, output fftypes.JSONObject, retry *fftypes.UUID) {
 	if cachedValue := om.cache.Get(id.String()); cachedValue != nil {
 		val := cachedValue.(*core.Operation)
 		if status != "" {
@@ -358,12 +539,24 @@ func (om *operationsManager) updateCachedOperation(id *fftypes.UUID, status core
 		if errorMsg != nil {
 			val.Error = *errorMsg
 		}
+		if errorDetail != nil {
+			val.LastError = errorDetail
+		}
 		if output != nil {
 			val.Output = output
 		}
 		if retry != nil {
 			val.Retry = retry
+			val.RetryAt = nil
 		}
 		om.cacheOperation(val)
 	}
 }
+
+func (om *operationsManager) updateCachedRetryHistory(id *fftypes.UUID, history core.OperationRetryHistory) {
+	if cachedValue := om.cache.Get(id.String()); cachedValue != nil {
+		val := cachedValue.(*core.Operation)
+		val.RetryHistory = history
+		val.RetryAt = history[len(history)-1].RetryAt
+	}
+}
@@ -95,7 +95,7 @@ func newTestOperations(t *testing.T) (*operationsManager, func()) {
 	}
 
 	ns := "ns1"
-	om, err := NewOperationsManager(ctx, ns, mdi, txHelper, cmi)
+	om, err := NewOperationsManager(ctx, ns, nil, mdi, txHelper, cmi)
 	assert.NoError(t, err)
 	cmi.AssertCalled(t, "GetCache", cache.NewCacheConfig(
 		ctx,
@@ -107,7 +107,7 @@ func newTestOperations(t *testing.T) (*operationsManager, func()) {
 }
 
 func TestInitFail(t *testing.T) {
-	_, err := NewOperationsManager(context.Background(), "ns1", nil, nil, nil)
+	_, err := NewOperationsManager(context.Background(), "ns1", nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -127,7 +127,7 @@ func TestCacheInitFail(t *testing.T) {
 	ns := "ns1"
 	ecmi := &cachemocks.Manager{}
 	ecmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
-	_, err := NewOperationsManager(ctx, ns, mdi, txHelper, ecmi)
+	_, err := NewOperationsManager(ctx, ns, nil, mdi, txHelper, ecmi)
 	assert.Equal(t, cacheInitError, err)
 }
 
@@ -324,6 +324,7 @@ func TestRetryOperationSuccess(t *testing.T) {
 		Transaction: txID,
 		Type:        core.OpTypeBlockchainPinBatch,
 		Status:      core.OpStatusFailed,
+		RetryDepth:  1,
 	}
 	po := &core.PreparedOperation{
 		ID:   op.ID,
@@ -333,23 +334,34 @@ func TestRetryOperationSuccess(t *testing.T) {
 	om.cache = cache.NewUmanagedCache(ctx, 100, 10*time.Minute)
 	om.cacheOperation(op)
 
+	var newOpID *fftypes.UUID
 	mdi := om.database.(*databasemocks.Plugin)
 	mdi.On("InsertOperation", ctx, mock.MatchedBy(func(newOp *core.Operation) bool {
 		assert.NotEqual(t, opID, newOp.ID)
+		assert.Equal(t, int64(2), newOp.RetryDepth)
+		assert.Equal(t, opID, newOp.RetryParent)
 		assert.Equal(t, "blockchain", newOp.Plugin)
 		assert.Equal(t, core.OpStatusInitialized, newOp.Status)
 		assert.Equal(t, core.OpTypeBlockchainPinBatch, newOp.Type)
+		newOpID = newOp.ID
 		return true
 	})).Return(nil)
-	mdi.On("UpdateOperation", ctx, "ns1", op.ID, mock.Anything, mock.MatchedBy(func(update ffapi.Update) bool {
+	mdi.On("UpdateOperation", ctx, "ns1", op.ID, mock.MatchedBy(func(filter ffapi.Filter) bool {
+		// The parent is only updated if no other retry has been linked to it
+		info, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( retry == null )", info.String())
+		return true
+	}), mock.MatchedBy(func(update ffapi.Update) bool {
 		info, err := update.Finalize()
 		assert.NoError(t, err)
-		assert.Equal(t, 1, len(info.SetOperations))
+		assert.Equal(t, 2, len(info.SetOperations))
 		assert.Equal(t, "retry", info.SetOperations[0].Field)
+		assert.Equal(t, "retryat", info.SetOperations[1].Field)
 		retryVal, err := info.SetOperations[0].Value.Value()
 		assert.NoError(t, err)
 		// The retry value of the parent operation should be the new operation ID
-		assert.Equal(t, op.Retry.String(), retryVal.(string))
+		assert.Equal(t, newOpID.String(), retryVal.(string))
 		// The parent ID should not change
 		assert.Equal(t, op.ID.String(), opID.String())
 		return true
@@ -365,6 +377,36 @@ func TestRetryOperationSuccess(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.NotNil(t, newOp)
+	assert.Equal(t, newOp.ID, op.Retry)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRetryOperationAlreadyRetried(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:     opID,
+		Plugin: "blockchain",
+		Type:   core.OpTypeBlockchainPinBatch,
+		Status: core.OpStatusFailed,
+	}
+
+	om.cache = cache.NewUmanagedCache(ctx, 100, 10*time.Minute)
+	om.cacheOperation(op)
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("InsertOperation", ctx, mock.Anything).Return(nil)
+	// Another retry was linked to the operation after it was read
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(false, nil)
+
+	_, err := om.RetryOperation(ctx, opID)
+	assert.Regexp(t, "FF10661", err)
+	assert.Nil(t, op.Retry)
 
 	mdi.AssertExpectations(t)
 }
@@ -469,6 +511,91 @@ func TestRetryTwiceOperationInsertFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestFindLatestRetryTooLong(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	// Every operation has been retried, so the chain never ends
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", mock.Anything).Return(func(ctx context.Context, ns string, id *fftypes.UUID) *core.Operation {
+		return &core.Operation{ID: id, Retry: fftypes.NewUUID()}
+	}, nil)
+
+	_, err := om.findLatestRetry(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10504", err)
+	mdi.AssertNumberOfCalls(t, "GetOperationByID", maxRetryChainLength+1)
+}
+
+func TestFindLatestRetryCycle(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opID1 := fftypes.NewUUID()
+	opID2 := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID1).Return(&core.Operation{ID: opID1, Retry: opID2}, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID2).Return(&core.Operation{ID: opID2, Retry: opID1}, nil)
+
+	_, err := om.findLatestRetry(context.Background(), opID1)
+	assert.Regexp(t, "FF10503", err)
+}
+
+func TestRetryOperationNotLatestInChain(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	opID2 := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:     opID,
+		Plugin: "blockchain",
+		Type:   core.OpTypeBlockchainPinBatch,
+		Status: core.OpStatusFailed,
+		Retry:  opID2,
+	}
+	op2 := &core.Operation{
+		ID:          opID2,
+		Plugin:      "blockchain",
+		Type:        core.OpTypeBlockchainPinBatch,
+		Status:      core.OpStatusFailed,
+		RetryDepth:  1,
+		RetryParent: opID,
+	}
+	po := &core.PreparedOperation{
+		ID:   op.ID,
+		Type: op.Type,
+	}
+
+	om.cache = cache.NewUmanagedCache(ctx, 100, 10*time.Minute)
+	om.cacheOperation(op)
+	om.cacheOperation(op2)
+
+	var newOpID *fftypes.UUID
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("InsertOperation", ctx, mock.MatchedBy(func(newOp *core.Operation) bool {
+		newOpID = newOp.ID
+		return newOp.RetryParent.Equals(opID2) && newOp.RetryDepth == 2
+	})).Return(nil)
+	// The latest operation in the chain is linked to the new retry, rather than the one requested
+	mdi.On("UpdateOperation", ctx, "ns1", opID2, mock.Anything, mock.Anything).Return(true, nil)
+
+	om.RegisterHandler(ctx, &mockHandler{Prepared: po}, []core.OpType{core.OpTypeBlockchainPinBatch})
+	newOp, err := om.RetryOperation(ctx, opID)
+	assert.NoError(t, err)
+	assert.Equal(t, newOpID, newOp.ID)
+
+	cached, err := om.GetOperationByIDCached(ctx, opID2)
+	assert.NoError(t, err)
+	assert.Equal(t, newOpID, cached.Retry)
+	cached, err = om.GetOperationByIDCached(ctx, opID)
+	assert.NoError(t, err)
+	assert.Equal(t, opID2, cached.Retry)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestRetryOperationInsertFail(t *testing.T) {
 	om, cancel := newTestOperations(t)
 	defer cancel()
@@ -531,6 +658,121 @@ func TestRetryOperationUpdateFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestRetryOperationNotFound(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", ctx, "ns1", opID).Return(nil, nil)
+
+	_, err := om.RetryOperation(ctx, opID)
+
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRetryOperationsByID(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	failedOp := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Plugin:    "blockchain",
+		Type:      core.OpTypeBlockchainPinBatch,
+		Status:    core.OpStatusFailed,
+	}
+	succeededOp := &core.Operation{
+		ID:     fftypes.NewUUID(),
+		Plugin: "blockchain",
+		Type:   core.OpTypeBlockchainPinBatch,
+		Status: core.OpStatusSucceeded,
+	}
+	missingOpID := fftypes.NewUUID()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", ctx, "ns1", failedOp.ID).Return(failedOp, nil).Once()
+	mdi.On("GetOperationByID", ctx, "ns1", succeededOp.ID).Return(succeededOp, nil)
+	mdi.On("GetOperationByID", ctx, "ns1", missingOpID).Return(nil, nil)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("InsertOperation", ctx, mock.Anything).Return(nil)
+	mdi.On("UpdateOperation", ctx, "ns1", failedOp.ID, mock.Anything, mock.Anything).Return(true, nil)
+
+	om.RegisterHandler(ctx, &mockHandler{Prepared: &core.PreparedOperation{
+		ID:   failedOp.ID,
+		Type: failedOp.Type,
+	}}, []core.OpType{core.OpTypeBlockchainPinBatch})
+	results, err := om.RetryOperations(ctx, []*fftypes.UUID{failedOp.ID, succeededOp.ID, missingOpID}, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.True(t, results[0].Retried)
+	assert.NotNil(t, results[0].Operation)
+	assert.Empty(t, results[0].Error)
+	assert.False(t, results[1].Retried)
+	assert.Regexp(t, "FF10499.*Succeeded", results[1].Error)
+	assert.Equal(t, missingOpID, results[2].ID)
+	assert.False(t, results[2].Retried)
+	assert.Regexp(t, "FF10109", results[2].Error)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRetryOperationsByFilter(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	op := &core.Operation{
+		ID:     fftypes.NewUUID(),
+		Plugin: "blockchain",
+		Type:   core.OpTypeBlockchainPinBatch,
+		Status: core.OpStatusFailed,
+	}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( type == 'blockchain_pin_batch' ) && ( status == 'Failed' ) && ( retry == null )", fi.String())
+		return true
+	})).Return([]*core.Operation{op}, nil, nil)
+	mdi.On("GetOperationByID", ctx, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	results, err := om.RetryOperations(ctx, nil, fb.And(fb.Eq("type", core.OpTypeBlockchainPinBatch)))
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, op.ID, results[0].ID)
+	assert.False(t, results[0].Retried)
+	assert.Regexp(t, "pop", results[0].Error)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRetryOperationsByFilterFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	_, err := om.RetryOperations(ctx, nil, fb.And())
+
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
 func TestResolveOperationByNamespacedIDOk(t *testing.T) {
 	om, cancel := newTestOperations(t)
 	defer cancel()
@@ -566,6 +808,135 @@ func TestResolveOperationByNamespacedIDOk(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestUpdateTransferProgress(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	om.cache.Set(opID.String(), &core.Operation{
+		ID:     opID,
+		Status: core.OpStatusPending,
+	})
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(true, nil).Twice()
+
+	err := om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 1024, 4096)
+	assert.NoError(t, err)
+
+	cached := om.cache.Get(opID.String()).(*core.Operation)
+	assert.Equal(t, int64(1024), cached.Progress.Transferred)
+	assert.Equal(t, int64(4096), cached.Progress.Size)
+	assert.NotNil(t, cached.Progress.Updated)
+
+	// An earlier report arriving late is ignored
+	err = om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 512, 4096)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), cached.Progress.Transferred)
+
+	// A report within the progress interval is dropped
+	err = om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 2048, 4096)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), cached.Progress.Transferred)
+
+	// Completion is always stored
+	err = om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 4096, 4096)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4096), cached.Progress.Transferred)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateTransferProgressAfterInterval(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	om.cache.Set(opID.String(), &core.Operation{
+		ID:     opID,
+		Status: core.OpStatusPending,
+		Progress: &core.TransferProgress{
+			Transferred: 1024,
+			Size:        4096,
+			Updated:     fftypes.UnixTime(time.Now().Add(-1 * time.Minute).Unix()),
+		},
+	})
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	err := om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 2048, 4096)
+	assert.NoError(t, err)
+
+	cached := om.cache.Get(opID.String()).(*core.Operation)
+	assert.Equal(t, int64(2048), cached.Progress.Transferred)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateTransferProgressNotPending(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	om.cache.Set(opID.String(), &core.Operation{
+		ID:     opID,
+		Status: core.OpStatusSucceeded,
+	})
+
+	err := om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 1024, 4096)
+	assert.NoError(t, err)
+}
+
+func TestUpdateTransferProgressBadID(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	err := om.UpdateTransferProgress(context.Background(), "bad", 1024, 4096)
+	assert.Regexp(t, "FF10411", err)
+}
+
+func TestUpdateTransferProgressLookupFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", ctx, "ns1", opID).Return(nil, fmt.Errorf("pop"))
+
+	err := om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 1024, 4096)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateTransferProgressUpdateFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	om.cache.Set(opID.String(), &core.Operation{
+		ID:     opID,
+		Status: core.OpStatusPending,
+	})
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	err := om.UpdateTransferProgress(ctx, "ns1:"+opID.String(), 1024, 4096)
+	assert.EqualError(t, err, "pop")
+
+	cached := om.cache.Get(opID.String()).(*core.Operation)
+	assert.Nil(t, cached.Progress)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestResolveOperationAlreadyResolved(t *testing.T) {
 	om, cancel := newTestOperations(t)
 	defer cancel()
@@ -0,0 +1,382 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const operationNotifySignatureHeader = "X-FireFly-Signature"
+
+// operationNotifier holds the one-shot webhooks registered against operations, and delivers each of them once
+// the operation reaches a terminal state. Registrations are stored in the database until they have been
+// delivered, so survive a restart, and are capped so that operations that never resolve cannot grow them
+// without limit. The operations with registrations are also tracked in memory, so resolving an operation
+// without any does not need to query the database.
+//
+// Signing secrets are never stored. A notification either names a signing secret from the namespace config, or
+// carries its own secret, which is only held in memory - so it is given up on if the node restarts before it is
+// delivered, rather than being sent unsigned.
+type operationNotifier struct {
+	ctx              context.Context
+	namespace        string
+	database         database.Plugin
+	client           *resty.Client
+	retry            *retry.Retry
+	maxAttempts      int
+	allowedHosts     map[string]bool
+	maxRegistrations int
+	signingSecrets   map[string][]byte
+	mux              sync.Mutex
+	registered       map[fftypes.UUID]int
+	secrets          map[fftypes.UUID][]byte
+	delivering       map[fftypes.UUID]bool
+	deliveries       sync.WaitGroup
+}
+
+func newOperationNotifier(ctx context.Context, ns string, signingSecrets map[string][]byte, di database.Plugin) *operationNotifier {
+	allowedHosts := make(map[string]bool)
+	for _, host := range config.GetStringSlice(coreconfig.OperationsNotifyAllowedHosts) {
+		allowedHosts[strings.ToLower(host)] = true
+	}
+	on := &operationNotifier{
+		ctx:       ctx,
+		namespace: ns,
+		database:  di,
+		client: ffresty.NewWithConfig(ctx, ffresty.Config{
+			HTTPConfig: ffresty.HTTPConfig{
+				HTTPRequestTimeout: fftypes.FFDuration(config.GetDuration(coreconfig.OperationsNotifyRequestTimeout)),
+			},
+		}),
+		retry: &retry.Retry{
+			InitialDelay: config.GetDuration(coreconfig.OperationsNotifyRetryInitDelay),
+			MaximumDelay: config.GetDuration(coreconfig.OperationsNotifyRetryMaxDelay),
+		},
+		maxAttempts:      config.GetInt(coreconfig.OperationsNotifyMaxAttempts),
+		allowedHosts:     allowedHosts,
+		maxRegistrations: config.GetInt(coreconfig.OperationsNotifyMaxRegistrations),
+		signingSecrets:   signingSecrets,
+		registered:       make(map[fftypes.UUID]int),
+		secrets:          make(map[fftypes.UUID][]byte),
+		delivering:       make(map[fftypes.UUID]bool),
+	}
+	// Redirects are not followed, as they could lead to a host that is not allowed
+	on.client.SetRedirectPolicy(resty.NoRedirectPolicy())
+	return on
+}
+
+// isNotifyTerminal is true once nothing more will happen to an operation without a request to retry it. A failed
+// operation is not terminal while an automatic retry is scheduled, or once it has been retried - the outcome is
+// then that of the retry.
+func isNotifyTerminal(op *core.Operation) bool {
+	switch op.Status {
+	case core.OpStatusSucceeded:
+		return true
+	case core.OpStatusFailed:
+		return op.RetryAt == nil && op.Retry == nil
+	default:
+		return false
+	}
+}
+
+// checkURL only allows absolute http or https URLs, on one of the configured hosts
+func (on *operationNotifier) checkURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidOperationNotifyURL, rawURL)
+	}
+	if !on.allowedHosts["*"] && !on.allowedHosts[strings.ToLower(u.Host)] && !on.allowedHosts[strings.ToLower(u.Hostname())] {
+		return i18n.NewError(ctx, coremsgs.MsgOperationNotifyHostNotAllowed, rawURL)
+	}
+	return nil
+}
+
+// checkSecret only allows one of an inline secret, or the name of a signing secret configured on the namespace
+func (on *operationNotifier) checkSecret(ctx context.Context, input *core.OperationNotifyInput) error {
+	if input.Secret != "" && input.SecretName != "" {
+		return i18n.NewError(ctx, coremsgs.MsgOperationNotifySecretConflict)
+	}
+	if input.SecretName != "" && on.signingSecrets[input.SecretName] == nil {
+		return i18n.NewError(ctx, coremsgs.MsgUnknownSigningSecret, input.SecretName)
+	}
+	return nil
+}
+
+func (on *operationNotifier) register(ctx context.Context, id *fftypes.UUID, input *core.OperationNotifyInput) (*core.OperationNotification, error) {
+	// Registrations from this node are serialized, so the count cannot be passed by concurrent requests
+	on.mux.Lock()
+	defer on.mux.Unlock()
+	fb := database.OperationNotificationQueryFactory.NewFilterLimit(ctx, 1)
+	_, res, err := on.database.GetOperationNotifications(ctx, on.namespace, fb.And().Count(true))
+	if err != nil {
+		return nil, err
+	}
+	if res.TotalCount != nil && *res.TotalCount >= int64(on.maxRegistrations) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotifyTooMany, on.maxRegistrations)
+	}
+	notification := &core.OperationNotification{
+		ID:           fftypes.NewUUID(),
+		Namespace:    on.namespace,
+		Operation:    id,
+		URL:          input.URL,
+		SecretName:   input.SecretName,
+		InlineSecret: input.Secret != "",
+		Created:      fftypes.Now(),
+	}
+	if err := on.database.InsertOperationNotification(ctx, notification); err != nil {
+		return nil, err
+	}
+	on.registered[*id]++
+	if notification.InlineSecret {
+		on.secrets[*notification.ID] = []byte(input.Secret)
+	}
+	return notification, nil
+}
+
+func (on *operationNotifier) unregister(ctx context.Context, notification *core.OperationNotification) {
+	if err := on.database.DeleteOperationNotification(ctx, on.namespace, notification.ID); err != nil {
+		log.L(ctx).Errorf("Failed to remove notification '%s' of operation %s: %s", notification.URL, notification.Operation, err)
+	}
+	on.mux.Lock()
+	defer on.mux.Unlock()
+	delete(on.secrets, *notification.ID)
+	if on.registered[*notification.Operation]--; on.registered[*notification.Operation] <= 0 {
+		delete(on.registered, *notification.Operation)
+	}
+}
+
+// hasRegistrations returns true if any of the operations has a webhook registered against it. With no operations,
+// it returns true if there are any registrations at all.
+func (on *operationNotifier) hasRegistrations(opIDs ...driver.Value) bool {
+	on.mux.Lock()
+	defer on.mux.Unlock()
+	if len(opIDs) == 0 {
+		return len(on.registered) > 0
+	}
+	for _, opID := range opIDs {
+		if on.registered[*opID.(*fftypes.UUID)] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// operationResolved dispatches any webhooks registered against the operation, or against the operations it is a
+// retry of, in the background - so a slow or unavailable receiver never blocks the caller that resolved the operation.
+// Nothing is sent for a failure that will be retried automatically. A failure to look up the registrations is
+// logged, and they are delivered on the next startup.
+func (om *operationsManager) operationResolved(ctx context.Context, id *fftypes.UUID) {
+	if !om.notifier.hasRegistrations() {
+		return
+	}
+	op, err := om.GetOperationByIDCached(ctx, id)
+	if err != nil {
+		log.L(ctx).Errorf("Failed to find notifications for operation %s: %s", id, err)
+		return
+	}
+	if op == nil || !isNotifyTerminal(op) {
+		return
+	}
+	opIDs := []driver.Value{id}
+	for op.RetryParent != nil {
+		opIDs = append(opIDs, op.RetryParent)
+		if op, err = om.GetOperationByIDCached(ctx, op.RetryParent); err != nil {
+			log.L(ctx).Errorf("Failed to find notifications for operation %s: %s", id, err)
+			return
+		}
+		if op == nil {
+			break
+		}
+	}
+	if !om.notifier.hasRegistrations(opIDs...) {
+		return
+	}
+	fb := database.OperationNotificationQueryFactory.NewFilter(ctx)
+	notifications, _, err := om.database.GetOperationNotifications(ctx, om.namespace, fb.In("operation", opIDs))
+	if err != nil {
+		log.L(ctx).Errorf("Failed to find notifications for operation %s: %s", id, err)
+		return
+	}
+	on := om.notifier
+	on.mux.Lock()
+	defer on.mux.Unlock()
+	for _, notification := range notifications {
+		if on.delivering[*notification.ID] {
+			continue
+		}
+		on.delivering[*notification.ID] = true
+		on.deliveries.Add(1)
+		go on.deliver(id, notification)
+	}
+}
+
+// deliverStoredNotifications delivers the webhooks stored by an earlier run for operations that resolved while
+// the node was stopped, or before their notification was delivered
+func (om *operationsManager) deliverStoredNotifications(ctx context.Context) error {
+	fb := database.OperationNotificationQueryFactory.NewFilter(ctx)
+	notifications, _, err := om.database.GetOperationNotifications(ctx, om.namespace, fb.And())
+	if err != nil {
+		return err
+	}
+	registered := make(map[fftypes.UUID]int)
+	for _, notification := range notifications {
+		registered[*notification.Operation]++
+	}
+	om.notifier.mux.Lock()
+	om.notifier.registered = registered
+	om.notifier.mux.Unlock()
+
+	checked := make(map[fftypes.UUID]bool)
+	for _, notification := range notifications {
+		if checked[*notification.Operation] {
+			continue
+		}
+		checked[*notification.Operation] = true
+		op, err := om.findLatestRetry(ctx, notification.Operation)
+		if err != nil {
+			log.L(ctx).Warnf("Unable to check operation %s for stored notifications: %s", notification.Operation, err)
+			continue
+		}
+		om.operationResolved(ctx, op.ID)
+	}
+	return nil
+}
+
+func (on *operationNotifier) deliver(id *fftypes.UUID, notification *core.OperationNotification) {
+	defer on.deliveries.Done()
+	defer func() {
+		on.mux.Lock()
+		delete(on.delivering, *notification.ID)
+		on.mux.Unlock()
+	}()
+	secret, err := on.signingSecret(on.ctx, notification)
+	if err == nil {
+		err = on.retry.Do(on.ctx, "operation notification", func(attempt int) (retry bool, err error) {
+			err = on.attemptDelivery(on.ctx, id, notification, secret)
+			return err != nil && attempt < on.maxAttempts, err
+		})
+	}
+	if err != nil {
+		log.L(on.ctx).Errorf("Giving up notifying '%s' of operation %s: %s", notification.URL, id, err)
+	} else {
+		log.L(on.ctx).Debugf("Notified '%s' of operation %s", notification.URL, id)
+	}
+	on.unregister(on.ctx, notification)
+}
+
+// signingSecret returns the secret to sign the notification with, or nil if it is not signed
+func (on *operationNotifier) signingSecret(ctx context.Context, notification *core.OperationNotification) ([]byte, error) {
+	if notification.SecretName != "" {
+		secret := on.signingSecrets[notification.SecretName]
+		if secret == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownSigningSecret, notification.SecretName)
+		}
+		return secret, nil
+	}
+	if notification.InlineSecret {
+		on.mux.Lock()
+		defer on.mux.Unlock()
+		secret := on.secrets[*notification.ID]
+		if secret == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotifySecretLost, notification.URL)
+		}
+		return secret, nil
+	}
+	return nil, nil
+}
+
+func (on *operationNotifier) attemptDelivery(ctx context.Context, id *fftypes.UUID, notification *core.OperationNotification, secret []byte) error {
+	// The update that resolved the operation might not be committed yet, so we check the stored status
+	op, err := on.database.GetOperationByID(ctx, on.namespace, id)
+	if err != nil {
+		return err
+	}
+	if op == nil || !isNotifyTerminal(op) {
+		return i18n.NewError(ctx, coremsgs.MsgOperationNotifyNotTerminal, id)
+	}
+	body, _ := json.Marshal(op)
+	req := on.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(body)
+	if secret != nil {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		req.SetHeader(operationNotifySignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	res, err := req.Post(notification.URL)
+	if err != nil {
+		return err
+	}
+	if !res.IsSuccess() {
+		return i18n.NewError(ctx, coremsgs.MsgOperationNotifyFailed, notification.URL, res.StatusCode())
+	}
+	return nil
+}
+
+func (om *operationsManager) NotifyOperation(ctx context.Context, opID *fftypes.UUID, input *core.OperationNotifyInput) (*core.Operation, error) {
+	if err := om.notifier.checkURL(ctx, input.URL); err != nil {
+		return nil, err
+	}
+	if err := om.notifier.checkSecret(ctx, input); err != nil {
+		return nil, err
+	}
+
+	// Register before checking the status, so we cannot miss an update that resolves the operation in between
+	notification, err := om.notifier.register(ctx, opID, input)
+	if err != nil {
+		return nil, err
+	}
+	op, err := om.database.GetOperationByID(ctx, om.namespace, opID)
+	if err != nil || op == nil {
+		om.notifier.unregister(ctx, notification)
+		if err == nil {
+			err = i18n.NewError(ctx, coremsgs.Msg404NoResult)
+		}
+		return nil, err
+	}
+	latest := op
+	if op.Retry != nil {
+		// The notification is for the outcome of the latest retry
+		if latest, err = om.findLatestRetry(ctx, op.Retry); err != nil {
+			return nil, err
+		}
+	}
+	// If it has already resolved, notify straight away
+	om.operationResolved(ctx, latest.ID)
+	return op, nil
+}
@@ -0,0 +1,669 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type notifyCallback struct {
+	body      []byte
+	signature string
+}
+
+func newTestNotifyServer(status int) (*httptest.Server, chan *notifyCallback) {
+	callbacks := make(chan *notifyCallback, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		callbacks <- &notifyCallback{body: body, signature: req.Header.Get(operationNotifySignatureHeader)}
+		res.WriteHeader(status)
+	}))
+	return server, callbacks
+}
+
+// testNotificationStore backs the mock database with an in-memory list of registrations. Queries return every
+// registration, so each test registers against a single operation.
+type testNotificationStore struct {
+	mux           sync.Mutex
+	notifications []*core.OperationNotification
+}
+
+func (ns *testNotificationStore) stored() []*core.OperationNotification {
+	ns.mux.Lock()
+	defer ns.mux.Unlock()
+	return append([]*core.OperationNotification{}, ns.notifications...)
+}
+
+func mockNotificationStore(mdi *databasemocks.Plugin) *testNotificationStore {
+	ns := &testNotificationStore{notifications: []*core.OperationNotification{}}
+	mdi.On("InsertOperationNotification", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ns.mux.Lock()
+		defer ns.mux.Unlock()
+		ns.notifications = append(ns.notifications, args[1].(*core.OperationNotification))
+	}).Return(nil)
+	mdi.On("DeleteOperationNotification", mock.Anything, "ns1", mock.Anything).Run(func(args mock.Arguments) {
+		ns.mux.Lock()
+		defer ns.mux.Unlock()
+		for i, n := range ns.notifications {
+			if n.ID.Equals(args[2].(*fftypes.UUID)) {
+				ns.notifications = append(ns.notifications[:i], ns.notifications[i+1:]...)
+				break
+			}
+		}
+	}).Return(nil)
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return(
+		func(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.OperationNotification, *ffapi.FilterResult, error) {
+			notifications := ns.stored()
+			count := int64(len(notifications))
+			return notifications, &ffapi.FilterResult{TotalCount: &count}, nil
+		}, nil, nil)
+	return ns
+}
+
+// newTestNotifyOperations allows notifications to example.com, and to the local test servers
+func newTestNotifyOperations(t *testing.T) (*operationsManager, func()) {
+	om, cancel := newTestOperations(t)
+	om.notifier.allowedHosts = map[string]bool{"example.com": true, "127.0.0.1": true}
+	return om, cancel
+}
+
+func TestNotifyOperationBadURL(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	for _, badURL := range []string{"::bad", "ftp://example.com", "http://", "/relative"} {
+		_, err := om.NotifyOperation(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: badURL})
+		assert.Regexp(t, "FF10508", err)
+	}
+}
+
+func TestNotifyOperationNotFound(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, nil)
+
+	_, err := om.NotifyOperation(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.Regexp(t, "FF10143", err)
+	assert.Empty(t, store.stored())
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyOperationGetFail(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	existing, err := om.notifier.register(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com/existing"})
+	assert.NoError(t, err)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, fmt.Errorf("pop"))
+
+	_, err = om.NotifyOperation(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, []*core.OperationNotification{existing}, store.stored())
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyOperationCountFail(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := om.NotifyOperation(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyOperationInsertFail(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	count := int64(0)
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, &ffapi.FilterResult{TotalCount: &count}, nil)
+	mdi.On("InsertOperationNotification", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := om.NotifyOperation(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyOperationAlreadyResolved(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(204)
+	defer server.Close()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Status: core.OpStatusSucceeded}
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+
+	res, err := om.NotifyOperation(context.Background(), op.ID, &core.OperationNotifyInput{URL: server.URL, Secret: "shh"})
+	assert.NoError(t, err)
+	assert.Equal(t, op, res)
+
+	callback := <-callbacks
+	om.notifier.deliveries.Wait()
+	var delivered core.Operation
+	err = json.Unmarshal(callback.body, &delivered)
+	assert.NoError(t, err)
+	assert.Equal(t, op.ID, delivered.ID)
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write(callback.body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), callback.signature)
+	assert.Empty(t, store.stored())
+	assert.Empty(t, om.notifier.secrets)
+}
+
+func TestNotifyOperationSecretName(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+	om.notifier.signingSecrets = map[string][]byte{"hooks": []byte("shh")}
+
+	server, callbacks := newTestNotifyServer(204)
+	defer server.Close()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Status: core.OpStatusSucceeded}
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+
+	_, err := om.NotifyOperation(context.Background(), op.ID, &core.OperationNotifyInput{URL: server.URL, SecretName: "hooks"})
+	assert.NoError(t, err)
+
+	callback := <-callbacks
+	om.notifier.deliveries.Wait()
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write(callback.body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), callback.signature)
+	assert.Empty(t, store.stored())
+}
+
+func TestNotifyOperationBadSecret(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+	om.notifier.signingSecrets = map[string][]byte{"hooks": []byte("shh")}
+
+	_, err := om.NotifyOperation(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: "http://example.com", Secret: "shh", SecretName: "hooks"})
+	assert.Regexp(t, "FF10655", err)
+	_, err = om.NotifyOperation(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: "http://example.com", SecretName: "unknown"})
+	assert.Regexp(t, "FF10625", err)
+}
+
+func TestNotifyOperationInlineSecretNotStored(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	notification, err := om.notifier.register(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: "http://example.com", Secret: "shh"})
+	assert.NoError(t, err)
+
+	stored, _ := json.Marshal(store.stored())
+	assert.NotContains(t, string(stored), "shh")
+	assert.True(t, notification.InlineSecret)
+	assert.Equal(t, []byte("shh"), om.notifier.secrets[*notification.ID])
+}
+
+func TestNotifyOperationAlreadyRetried(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(200)
+	defer server.Close()
+
+	retry := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Status: core.OpStatusSucceeded}
+	op := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Status: core.OpStatusFailed, Retry: retry.ID}
+	retry.RetryParent = op.ID
+	om.cacheOperation(retry)
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", retry.ID).Return(retry, nil)
+
+	res, err := om.NotifyOperation(context.Background(), op.ID, &core.OperationNotifyInput{URL: server.URL})
+	assert.NoError(t, err)
+	assert.Equal(t, op, res)
+
+	// The outcome of the retry is delivered
+	callback := <-callbacks
+	om.notifier.deliveries.Wait()
+	var delivered core.Operation
+	err = json.Unmarshal(callback.body, &delivered)
+	assert.NoError(t, err)
+	assert.Equal(t, retry.ID, delivered.ID)
+	assert.Empty(t, store.stored())
+}
+
+func TestNotifyOperationRetryLookupFail(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1", Status: core.OpStatusFailed, Retry: fftypes.NewUUID()}
+	mdi := om.database.(*databasemocks.Plugin)
+	mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.Retry).Return(nil, fmt.Errorf("pop"))
+
+	_, err := om.NotifyOperation(context.Background(), op.ID, &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestNotifyOperationOnResolve(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(200)
+	defer server.Close()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusPending}, nil).Twice()
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.Anything).Return(true, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusFailed}, nil)
+
+	res, err := om.NotifyOperation(context.Background(), opID, &core.OperationNotifyInput{URL: server.URL})
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusPending, res.Status)
+	assert.Len(t, store.stored(), 1)
+
+	// A pending update does not fire the callback
+	err = om.updater.resolveOperation(context.Background(), "ns1", opID, core.OpStatusPending, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, store.stored(), 1)
+
+	err = om.updater.resolveOperation(context.Background(), "ns1", opID, core.OpStatusFailed, nil, nil, nil)
+	assert.NoError(t, err)
+
+	callback := <-callbacks
+	om.notifier.deliveries.Wait()
+	var delivered core.Operation
+	err = json.Unmarshal(callback.body, &delivered)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusFailed, delivered.Status)
+	assert.Empty(t, callback.signature)
+	assert.Empty(t, store.stored())
+}
+
+func TestNotifyOperationResolvedRetryScheduled(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, RetryAt: fftypes.Now()}
+	om.cacheOperation(op)
+
+	// Nothing is looked up or sent while an automatic retry is pending
+	om.operationResolved(context.Background(), op.ID)
+	om.notifier.deliveries.Wait()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.AssertNotCalled(t, "GetOperationNotifications", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotifyOperationResolvedRetryParents(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(200)
+	defer server.Close()
+
+	first := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed}
+	second := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, RetryParent: first.ID}
+	third := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: second.ID}
+	first.Retry, second.Retry = second.ID, third.ID
+	om.cacheOperation(first)
+	om.cacheOperation(second)
+	om.cacheOperation(third)
+
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", third.ID).Return(third, nil)
+	_, err := om.notifier.register(context.Background(), first.ID, &core.OperationNotifyInput{URL: server.URL})
+	assert.NoError(t, err)
+
+	om.operationResolved(context.Background(), third.ID)
+	callback := <-callbacks
+	om.notifier.deliveries.Wait()
+	var delivered core.Operation
+	err = json.Unmarshal(callback.body, &delivered)
+	assert.NoError(t, err)
+	assert.Equal(t, third.ID, delivered.ID)
+	assert.Empty(t, store.stored())
+
+	mdi.AssertCalled(t, "GetOperationNotifications", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return fi.String() == "operation IN ['"+third.ID.String()+"','"+second.ID.String()+"','"+first.ID.String()+"']"
+	}))
+}
+
+func TestNotifyOperationResolvedErrors(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	mdi := om.database.(*databasemocks.Plugin)
+	missingID := fftypes.NewUUID()
+	om.notifier.registered[*missingID] = 1
+
+	// Operation lookup fails
+	opID := fftypes.NewUUID()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, fmt.Errorf("pop"))
+	om.operationResolved(ctx, opID)
+
+	// Operation not found
+	mdi.On("GetOperationByID", mock.Anything, "ns1", missingID).Return(nil, nil)
+	om.operationResolved(ctx, missingID)
+
+	// Parent lookup fails
+	child := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: opID}
+	om.cacheOperation(child)
+	om.operationResolved(ctx, child.ID)
+
+	// Notification lookup fails, with a parent that has been deleted
+	orphan := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: missingID}
+	om.cacheOperation(orphan)
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	om.operationResolved(ctx, orphan.ID)
+
+	// No registrations against the operation or its parents
+	other := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded}
+	om.cacheOperation(other)
+	om.operationResolved(ctx, other.ID)
+
+	om.notifier.deliveries.Wait()
+	mdi.AssertNumberOfCalls(t, "GetOperationNotifications", 1)
+}
+
+func TestNotifyOperationResolvedAlreadyDelivering(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded}
+	om.cacheOperation(op)
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	notification, err := om.notifier.register(context.Background(), op.ID, &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.NoError(t, err)
+	om.notifier.delivering[*notification.ID] = true
+
+	om.operationResolved(context.Background(), op.ID)
+	om.notifier.deliveries.Wait()
+	assert.Len(t, store.stored(), 1)
+}
+
+func TestDeliverStoredNotifications(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(200)
+	defer server.Close()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded}
+	om.cacheOperation(op)
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	for i := 0; i < 2; i++ {
+		_, err := om.notifier.register(context.Background(), op.ID, &core.OperationNotifyInput{URL: server.URL})
+		assert.NoError(t, err)
+	}
+
+	err := om.deliverStoredNotifications(context.Background())
+	assert.NoError(t, err)
+	<-callbacks
+	<-callbacks
+	om.notifier.deliveries.Wait()
+	assert.Empty(t, store.stored())
+}
+
+func TestDeliverStoredNotificationsInlineSecretLost(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(200)
+	defer server.Close()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded}
+	om.cacheOperation(op)
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	_, err := om.notifier.register(context.Background(), op.ID, &core.OperationNotifyInput{URL: server.URL, Secret: "shh"})
+	assert.NoError(t, err)
+
+	// A restart loses the secret, so the notification is given up on rather than sent unsigned
+	om.notifier.secrets = make(map[fftypes.UUID][]byte)
+	err = om.deliverStoredNotifications(context.Background())
+	assert.NoError(t, err)
+	om.notifier.deliveries.Wait()
+	assert.Empty(t, callbacks)
+	assert.Empty(t, store.stored())
+}
+
+func TestNotifySigningSecretRemoved(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	_, err := om.notifier.signingSecret(context.Background(), &core.OperationNotification{ID: fftypes.NewUUID(), SecretName: "hooks"})
+	assert.Regexp(t, "FF10625", err)
+}
+
+func TestDeliverStoredNotificationsOperationLookupFail(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, nil)
+	_, err := om.notifier.register(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com"})
+	assert.NoError(t, err)
+
+	err = om.deliverStoredNotifications(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, store.stored(), 1)
+}
+
+func TestDeliverStoredNotificationsFail(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := om.deliverStoredNotifications(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestNotifyDeliveryGivesUp(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(500)
+	defer server.Close()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusSucceeded}, nil)
+	mdi.On("DeleteOperationNotification", mock.Anything, "ns1", mock.Anything).Return(fmt.Errorf("pop"))
+
+	om.notifier.maxAttempts = 2
+	om.notifier.deliveries.Add(1)
+	om.notifier.deliver(opID, &core.OperationNotification{ID: fftypes.NewUUID(), Operation: opID, URL: server.URL})
+	assert.Len(t, callbacks, 2)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyDeliverySuccess(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	server, callbacks := newTestNotifyServer(200)
+	defer server.Close()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusSucceeded}, nil)
+	mdi.On("DeleteOperationNotification", mock.Anything, "ns1", mock.Anything).Return(nil)
+
+	om.notifier.deliveries.Add(1)
+	om.notifier.deliver(opID, &core.OperationNotification{ID: fftypes.NewUUID(), Operation: opID, URL: server.URL})
+	assert.Len(t, callbacks, 1)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyAttemptDeliveryErrors(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, fmt.Errorf("pop")).Once()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, nil).Once()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusPending}, nil).Once()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusFailed, RetryAt: fftypes.Now()}, nil).Once()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusSucceeded}, nil).Once()
+
+	notification := &core.OperationNotification{ID: fftypes.NewUUID(), Operation: opID, URL: "http://localhost:0/unreachable"}
+	err := om.notifier.attemptDelivery(context.Background(), opID, notification, nil)
+	assert.EqualError(t, err, "pop")
+	err = om.notifier.attemptDelivery(context.Background(), opID, notification, nil)
+	assert.Regexp(t, "FF10509", err)
+	err = om.notifier.attemptDelivery(context.Background(), opID, notification, nil)
+	assert.Regexp(t, "FF10509", err)
+	err = om.notifier.attemptDelivery(context.Background(), opID, notification, nil)
+	assert.Regexp(t, "FF10509", err)
+	err = om.notifier.attemptDelivery(context.Background(), opID, notification, nil)
+	assert.Error(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyOperationHostNotAllowed(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	for _, badURL := range []string{"http://169.254.169.254/latest", "http://localhost:5000", "https://example.com.evil.org"} {
+		_, err := om.NotifyOperation(context.Background(), fftypes.NewUUID(), &core.OperationNotifyInput{URL: badURL})
+		assert.Regexp(t, "FF10645", err)
+	}
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.AssertNotCalled(t, "InsertOperationNotification", mock.Anything, mock.Anything)
+}
+
+func TestNotifyCheckURLAllowedHosts(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.OperationsNotifyAllowedHosts, []string{"Example.com", "internal:8443"})
+	on := newOperationNotifier(context.Background(), "ns1", nil, &databasemocks.Plugin{})
+
+	assert.NoError(t, on.checkURL(context.Background(), "https://example.com/hook"))
+	assert.NoError(t, on.checkURL(context.Background(), "http://EXAMPLE.COM:8080/hook"))
+	assert.NoError(t, on.checkURL(context.Background(), "https://internal:8443/hook"))
+	assert.Regexp(t, "FF10645", on.checkURL(context.Background(), "https://internal:9443/hook"))
+
+	on.allowedHosts = map[string]bool{"*": true}
+	assert.NoError(t, on.checkURL(context.Background(), "https://anywhere/hook"))
+	assert.Regexp(t, "FF10508", on.checkURL(context.Background(), "ftp://anywhere/hook"))
+}
+
+func TestNotifyCheckURLNoAllowedHosts(t *testing.T) {
+	coreconfig.Reset()
+	on := newOperationNotifier(context.Background(), "ns1", nil, &databasemocks.Plugin{})
+
+	assert.Regexp(t, "FF10645", on.checkURL(context.Background(), "https://example.com/hook"))
+}
+
+func TestNotifyOperationTooMany(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	om.notifier.maxRegistrations = 1
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	store := mockNotificationStore(mdi)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusPending}, nil)
+
+	_, err := om.NotifyOperation(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com/1"})
+	assert.NoError(t, err)
+	_, err = om.NotifyOperation(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com/2"})
+	assert.Regexp(t, "FF10646", err)
+	stored := store.stored()
+	assert.Len(t, stored, 1)
+	assert.Equal(t, "http://example.com/1", stored[0].URL)
+
+	// Removing a registration makes room for another
+	om.notifier.unregister(context.Background(), stored[0])
+	_, err = om.NotifyOperation(context.Background(), opID, &core.OperationNotifyInput{URL: "http://example.com/2"})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestNotifyDeliveryDoesNotFollowRedirects(t *testing.T) {
+	om, cancel := newTestNotifyOperations(t)
+	defer cancel()
+
+	target, callbacks := newTestNotifyServer(200)
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		http.Redirect(res, req, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusSucceeded}, nil)
+
+	err := om.notifier.attemptDelivery(context.Background(), opID, &core.OperationNotification{ID: fftypes.NewUUID(), Operation: opID, URL: redirect.URL}, nil)
+	assert.Error(t, err)
+	assert.Empty(t, callbacks)
+}
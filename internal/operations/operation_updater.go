@@ -18,6 +18,7 @@ package operations
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -55,10 +56,26 @@ type operationUpdater struct {
 }
 
 type operationUpdaterConf struct {
-	workerCount  int
-	batchTimeout time.Duration
-	maxInserts   int
-	queueLength  int
+	workerCount    int
+	batchTimeout   time.Duration
+	maxInserts     int
+	queueLength    int
+	coalesce       bool
+	coalesceWindow time.Duration
+}
+
+// heldOperationUpdate is a non-terminal update held back for the coalesce window, into which any later
+// non-terminal updates to the same operation are merged until it is due
+type heldOperationUpdate struct {
+	key    string
+	update *core.OperationUpdate
+	due    time.Time
+}
+
+// heldOperationUpdates are the updates a worker is holding, in the order they are due
+type heldOperationUpdates struct {
+	list  []*heldOperationUpdate
+	byKey map[string]*heldOperationUpdate
 }
 
 func newOperationUpdater(ctx context.Context, om *operationsManager, di database.Plugin, txHelper txcommon.Helper) *operationUpdater {
@@ -67,10 +84,12 @@ func newOperationUpdater(ctx context.Context, om *operationsManager, di database
 		database: di,
 		txHelper: txHelper,
 		conf: operationUpdaterConf{
-			workerCount:  config.GetInt(coreconfig.OpUpdateWorkerCount),
-			batchTimeout: config.GetDuration(coreconfig.OpUpdateWorkerBatchTimeout),
-			maxInserts:   config.GetInt(coreconfig.OpUpdateWorkerBatchMaxInserts),
-			queueLength:  config.GetInt(coreconfig.OpUpdateWorkerQueueLength),
+			workerCount:    config.GetInt(coreconfig.OpUpdateWorkerCount),
+			batchTimeout:   config.GetDuration(coreconfig.OpUpdateWorkerBatchTimeout),
+			maxInserts:     config.GetInt(coreconfig.OpUpdateWorkerBatchMaxInserts),
+			queueLength:    config.GetInt(coreconfig.OpUpdateWorkerQueueLength),
+			coalesce:       config.GetBool(coreconfig.OpUpdateWorkerCoalesce),
+			coalesceWindow: config.GetDuration(coreconfig.OpUpdateWorkerCoalesceWindow),
 		},
 		retry: &retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.OpUpdateRetryInitDelay),
@@ -108,7 +127,7 @@ func (ou *operationUpdater) SubmitOperationUpdate(ctx context.Context, update *c
 		if update.Status == core.OpStatusFailed {
 			// We do a cache update pre-emptively, as for idempotency checking on an error status we want to
 			// see the update immediately - even though it's being asynchronously flushed to the storage
-			ou.manager.updateCachedOperation(id, update.Status, &update.ErrorMessage, update.Output, nil)
+			ou.manager.updateCachedOperation(id, update.Status, &update.ErrorMessage, update.ErrorDetail, update.Output, nil)
 		}
 
 		select {
@@ -150,6 +169,18 @@ func (ou *operationUpdater) updaterLoop(index int) {
 	ctx := log.WithLogField(ou.ctx, "opupdater", fmt.Sprintf("opu_%.3d", index))
 
 	var batch *operationUpdaterBatch
+	held := &heldOperationUpdates{byKey: make(map[string]*heldOperationUpdate)}
+	defer ou.flushHeld(ctx, held)
+	addToBatch := func(updates ...*core.OperationUpdate) {
+		if len(updates) == 0 {
+			return
+		}
+		if batch == nil {
+			batch = &operationUpdaterBatch{}
+			batch.timeoutContext, batch.timeoutCancel = context.WithTimeout(ctx, ou.conf.batchTimeout)
+		}
+		batch.updates = append(batch.updates, updates...)
+	}
 	for !ou.closed {
 		var timeoutContext context.Context
 		var timedOut bool
@@ -158,20 +189,35 @@ func (ou *operationUpdater) updaterLoop(index int) {
 		} else {
 			timeoutContext = ctx
 		}
+		var heldTimer *time.Timer
+		var heldDue <-chan time.Time
+		if len(held.list) > 0 {
+			heldTimer = time.NewTimer(time.Until(held.list[0].due))
+			heldDue = heldTimer.C
+		}
 		select {
 		case work := <-workQueue:
-			if batch == nil {
-				batch = &operationUpdaterBatch{}
-				batch.timeoutContext, batch.timeoutCancel = context.WithTimeout(ctx, ou.conf.batchTimeout)
+			if ou.conf.coalesce && ou.conf.coalesceWindow > 0 {
+				addToBatch(held.hold(work, ou.conf.coalesceWindow)...)
+			} else {
+				addToBatch(work)
 			}
-			batch.updates = append(batch.updates, work)
+		case <-heldDue:
+			addToBatch(held.release(time.Now())...)
 		case <-timeoutContext.Done():
 			timedOut = true
 		}
+		if heldTimer != nil {
+			heldTimer.Stop()
+		}
 
 		if batch != nil && (timedOut || len(batch.updates) >= ou.conf.maxInserts) {
 			batch.timeoutCancel()
-			err := ou.doBatchUpdateWithRetry(ctx, batch.updates)
+			updates := batch.updates
+			if ou.conf.coalesce {
+				updates = ou.coalesceUpdates(ctx, updates)
+			}
+			err := ou.doBatchUpdateWithRetry(ctx, updates)
 			if err != nil {
 				log.L(ctx).Debugf("Operation update worker exiting: %s", err)
 				return
@@ -181,6 +227,140 @@ func (ou *operationUpdater) updaterLoop(index int) {
 	}
 }
 
+// flushHeld writes the updates still held for the coalesce window when a worker exits, rather than dropping them.
+// The context of the worker has been cancelled by then, so a single attempt is made on a context without cancellation.
+func (ou *operationUpdater) flushHeld(ctx context.Context, held *heldOperationUpdates) {
+	updates := held.releaseAll()
+	if len(updates) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	err := ou.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		return ou.doBatchUpdate(ctx, updates)
+	})
+	if err != nil {
+		log.L(ctx).Errorf("Failed to write %d held operation updates on exit: %s", len(updates), err)
+		return
+	}
+	for _, update := range updates {
+		if update.OnComplete != nil {
+			update.OnComplete()
+		}
+	}
+}
+
+// hold holds a non-terminal update until the coalesce window from the first held update to the same operation
+// has passed, merging it into that update if there is one. A terminal update is never held, and is returned
+// to be processed straight away - after any update held for the same operation.
+func (h *heldOperationUpdates) hold(update *core.OperationUpdate, window time.Duration) []*core.OperationUpdate {
+	key := operationUpdateKey(update)
+	held := h.byKey[key]
+	if isTerminalUpdate(update) {
+		if held == nil {
+			return []*core.OperationUpdate{update}
+		}
+		h.remove(held)
+		return []*core.OperationUpdate{held.update, update}
+	}
+	if held != nil {
+		held.update = mergeOperationUpdates(held.update, update)
+		return nil
+	}
+	held = &heldOperationUpdate{key: key, update: update, due: time.Now().Add(window)}
+	h.list = append(h.list, held)
+	h.byKey[key] = held
+	return nil
+}
+
+// release returns the held updates that are due
+func (h *heldOperationUpdates) release(now time.Time) []*core.OperationUpdate {
+	var due []*core.OperationUpdate
+	for len(h.list) > 0 && !h.list[0].due.After(now) {
+		due = append(due, h.list[0].update)
+		delete(h.byKey, h.list[0].key)
+		h.list = h.list[1:]
+	}
+	return due
+}
+
+// releaseAll returns every held update, whether or not it is due
+func (h *heldOperationUpdates) releaseAll() []*core.OperationUpdate {
+	all := make([]*core.OperationUpdate, len(h.list))
+	for i, held := range h.list {
+		all[i] = held.update
+	}
+	h.list = nil
+	h.byKey = make(map[string]*heldOperationUpdate)
+	return all
+}
+
+func (h *heldOperationUpdates) remove(held *heldOperationUpdate) {
+	delete(h.byKey, held.key)
+	for i, candidate := range h.list {
+		if candidate == held {
+			h.list = append(h.list[:i], h.list[i+1:]...)
+			break
+		}
+	}
+}
+
+func operationUpdateKey(update *core.OperationUpdate) string {
+	return update.Plugin + "/" + update.NamespacedOpID
+}
+
+func isTerminalUpdate(update *core.OperationUpdate) bool {
+	return update.Status == core.OpStatusSucceeded || update.Status == core.OpStatusFailed
+}
+
+// mergeOperationUpdates merges a non-terminal update into the previous one for the same operation, keeping
+// any blockchain transaction ID and output that the later update does not set, and both OnComplete callbacks
+func mergeOperationUpdates(previous, update *core.OperationUpdate) *core.OperationUpdate {
+	merged := *update
+	if merged.BlockchainTXID == "" {
+		merged.BlockchainTXID = previous.BlockchainTXID
+	}
+	if merged.Output == nil {
+		merged.Output = previous.Output
+	}
+	if previous.OnComplete != nil {
+		prevOnComplete, nextOnComplete := previous.OnComplete, update.OnComplete
+		merged.OnComplete = func() {
+			prevOnComplete()
+			if nextOnComplete != nil {
+				nextOnComplete()
+			}
+		}
+	}
+	return &merged
+}
+
+// coalesceUpdates merges consecutive non-terminal updates for the same operation, so that only the latest
+// state is processed (and any resulting events emitted). Updates moving an operation to Succeeded or Failed
+// are never merged away. The OnComplete callbacks of all merged updates are still called.
+func (ou *operationUpdater) coalesceUpdates(ctx context.Context, updates []*core.OperationUpdate) []*core.OperationUpdate {
+	results := make([]*core.OperationUpdate, 0, len(updates))
+	pending := make(map[string]int)
+	for _, update := range updates {
+		key := operationUpdateKey(update)
+		if isTerminalUpdate(update) {
+			delete(pending, key)
+			results = append(results, update)
+			continue
+		}
+		idx, ok := pending[key]
+		if !ok {
+			pending[key] = len(results)
+			results = append(results, update)
+			continue
+		}
+		results[idx] = mergeOperationUpdates(results[idx], update)
+	}
+	if len(results) < len(updates) {
+		log.L(ctx).Debugf("Coalesced %d operation updates into %d", len(updates), len(results))
+	}
+	return results
+}
+
 func (ou *operationUpdater) doBatchUpdateWithRetry(ctx context.Context, updates []*core.OperationUpdate) error {
 	return ou.retry.Do(ctx, "operation update", func(attempt int) (retry bool, err error) {
 		err = ou.database.RunAsGroup(ctx, func(ctx context.Context) error {
@@ -281,6 +461,13 @@ func (ou *operationUpdater) doUpdate(ctx context.Context, update *core.Operation
 		}
 	}
 
+	// Do not pass on, or store, output that does not conform to the schema for the operation type
+	if err := ou.manager.validateOutput(ctx, op.Type, update.Output); err != nil {
+		update.Status = core.OpStatusFailed
+		update.ErrorMessage = err.Error()
+		update.Output = nil
+	}
+
 	if handler, ok := ou.manager.handlers[op.Type]; ok {
 		if err := handler.OnOperationUpdate(ctx, op, update); err != nil {
 			return err
@@ -294,7 +481,7 @@ func (ou *operationUpdater) doUpdate(ctx context.Context, update *core.Operation
 		}
 	}
 
-	if err := ou.resolveOperation(ctx, op.Namespace, op.ID, update.Status, &update.ErrorMessage, update.Output); err != nil {
+	if err := ou.resolveOperation(ctx, op.Namespace, op.ID, update.Status, &update.ErrorMessage, update.ErrorDetail, update.Output); err != nil {
 		return err
 	}
 
@@ -348,7 +535,7 @@ func (ou *operationUpdater) close() {
 	}
 }
 
-func (ou *operationUpdater) resolveOperation(ctx context.Context, ns string, id *fftypes.UUID, status core.OpStatus, errorMsg *string, output fftypes.JSONObject) (err error) {
+func (ou *operationUpdater) resolveOperation(ctx context.Context, ns string, id *fftypes.UUID, status core.OpStatus, errorMsg *string, errorDetail *core.OperationError, output fftypes.JSONObject) (err error) {
 	// Never move an operation from Succeeded/Failed back to Pending
 	fb := database.OperationQueryFactory.NewFilter(ctx)
 	var filter ffapi.AndFilter
@@ -366,12 +553,30 @@ func (ou *operationUpdater) resolveOperation(ctx context.Context, ns string, id
 	if errorMsg != nil {
 		update = update.Set("error", *errorMsg)
 	}
+	if errorDetail != nil {
+		detailBytes, _ := json.Marshal(errorDetail)
+		update = update.Set("lasterror", fftypes.JSONAnyPtrBytes(detailBytes))
+	}
 	if output != nil {
 		update = update.Set("output", output)
 	}
+	var retryHistory core.OperationRetryHistory
+	var retryDelay time.Duration
+	if status == core.OpStatusFailed {
+		retryHistory, retryDelay = ou.manager.recordFailure(ctx, id, errorMsg, update)
+	}
 	ok, err := ou.database.UpdateOperation(ctx, ns, id, filter, update)
 	if ok && err == nil {
-		ou.manager.updateCachedOperation(id, status, errorMsg, output, nil)
+		ou.manager.updateCachedOperation(id, status, errorMsg, errorDetail, output, nil)
+		if retryHistory != nil {
+			ou.manager.updateCachedRetryHistory(id, retryHistory)
+			if retryHistory[len(retryHistory)-1].Outcome == core.OpRetryOutcomeRetry {
+				ou.manager.scheduleRetry(id, retryDelay)
+			}
+		}
+		if status == core.OpStatusSucceeded || status == core.OpStatusFailed {
+			ou.manager.operationResolved(ctx, id)
+		}
 	}
 	return err
 }
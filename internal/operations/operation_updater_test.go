@@ -61,6 +61,7 @@ func newTestOperationUpdaterCommon(t *testing.T, dbCapabilities *database.Capabi
 		handlers:  make(map[fftypes.FFEnum]OperationHandler),
 		cache:     cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute),
 		database:  mdi,
+		notifier:  newOperationNotifier(context.Background(), "ns1", nil, mdi),
 	}
 	mdm := &datamocks.Manager{}
 	ctx := context.Background()
@@ -217,6 +218,7 @@ func TestSubmitUpdateWorkerE2ESuccess(t *testing.T) {
 		close(done)
 	}).Once()
 
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, nil, nil)
 	om.Start()
 	om.SubmitOperationUpdate(&core.OperationUpdate{
 		NamespacedOpID: "ns1:" + opID1.String(),
@@ -239,6 +241,323 @@ func TestSubmitUpdateWorkerE2ESuccess(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestSubmitUpdateWorkerE2ECoalesce(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer om.WaitStop()
+	defer cancel()
+	om.updater.conf.maxInserts = 3
+	om.updater.conf.coalesce = true
+
+	opID1 := fftypes.NewUUID()
+	om.cache = cache.NewUmanagedCache(context.Background(), 100, 10*time.Minute)
+	om.cacheOperation(
+		&core.Operation{ID: opID1, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke},
+	)
+
+	done := make(chan struct{})
+	completed := 0
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Pending"},
+		{"error", ""},
+		{"output", fftypes.JSONObject{"step": 2}.String()},
+	}))).Return(true, nil).Once()
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Succeeded"},
+		{"error", ""},
+	}))).Return(true, nil).Once()
+
+	onComplete := func() {
+		completed++
+		if completed == 3 {
+			close(done)
+		}
+	}
+
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, nil, nil)
+	om.Start()
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 1},
+		OnComplete:     onComplete,
+	})
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 2},
+		OnComplete:     onComplete,
+	})
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusSucceeded,
+		OnComplete:     onComplete,
+	})
+	<-done
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSubmitUpdateWorkerE2ECoalesceWindowSpansBatches(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer om.WaitStop()
+	defer cancel()
+	om.updater.conf.maxInserts = 1
+	om.updater.conf.coalesce = true
+	om.updater.conf.coalesceWindow = 100 * time.Millisecond
+
+	opID1 := fftypes.NewUUID()
+	opID2 := fftypes.NewUUID()
+	om.cache = cache.NewUmanagedCache(context.Background(), 100, 10*time.Minute)
+	om.cacheOperation(&core.Operation{ID: opID1, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke})
+	om.cacheOperation(&core.Operation{ID: opID2, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke})
+
+	done := make(chan struct{})
+	var written []*fftypes.UUID
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID2, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Succeeded"},
+		{"error", ""},
+	}))).Return(true, nil).Run(func(args mock.Arguments) {
+		written = append(written, opID2)
+	}).Once()
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Pending"},
+		{"error", ""},
+		{"output", fftypes.JSONObject{"step": 2}.String()},
+	}))).Return(true, nil).Run(func(args mock.Arguments) {
+		written = append(written, opID1)
+		close(done)
+	}).Once()
+
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, nil, nil)
+	om.Start()
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 1},
+	})
+	// Flushed in a batch of its own, while the update to the first operation is held
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID2.String(),
+		Status:         core.OpStatusSucceeded,
+	})
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 2},
+	})
+	<-done
+
+	assert.Equal(t, []*fftypes.UUID{opID2, opID1}, written)
+	mdi.AssertExpectations(t)
+}
+
+func TestSubmitUpdateWorkerE2ECoalesceWindowTerminalNotHeld(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer om.WaitStop()
+	defer cancel()
+	om.updater.conf.maxInserts = 2
+	om.updater.conf.coalesce = true
+	om.updater.conf.coalesceWindow = 10 * time.Minute
+
+	opID1 := fftypes.NewUUID()
+	om.cache = cache.NewUmanagedCache(context.Background(), 100, 10*time.Minute)
+	om.cacheOperation(&core.Operation{ID: opID1, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke})
+
+	done := make(chan struct{})
+	completed := 0
+	onComplete := func() {
+		completed++
+		if completed == 3 {
+			close(done)
+		}
+	}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Pending"},
+		{"error", ""},
+		{"output", fftypes.JSONObject{"step": 2}.String()},
+	}))).Return(true, nil).Once()
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Succeeded"},
+		{"error", ""},
+	}))).Return(true, nil).Once()
+
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, nil, nil)
+	om.Start()
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 1},
+		OnComplete:     onComplete,
+	})
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 2},
+		OnComplete:     onComplete,
+	})
+	// The terminal update releases the held update ahead of it, long before the window has passed
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusSucceeded,
+		OnComplete:     onComplete,
+	})
+	<-done
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSubmitUpdateWorkerE2ECoalesceWindowFlushedOnClose(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	om.updater.conf.maxInserts = 1
+	om.updater.conf.coalesce = true
+	om.updater.conf.coalesceWindow = 10 * time.Minute
+
+	opID1 := fftypes.NewUUID()
+	opID2 := fftypes.NewUUID()
+	om.cache = cache.NewUmanagedCache(context.Background(), 100, 10*time.Minute)
+	om.cacheOperation(&core.Operation{ID: opID1, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke})
+	om.cacheOperation(&core.Operation{ID: opID2, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke})
+
+	done := make(chan struct{})
+	flushed := false
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID2, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Succeeded"},
+		{"error", ""},
+	}))).Return(true, nil).Run(func(args mock.Arguments) {
+		close(done)
+	}).Once()
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Pending"},
+		{"error", ""},
+		{"output", fftypes.JSONObject{"step": 1}.String()},
+	}))).Return(true, nil).Once()
+
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, nil, nil)
+	om.Start()
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 1},
+		OnComplete:     func() { flushed = true },
+	})
+	// Once this is written the worker has taken the update to the first operation, which is held for the window
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID2.String(),
+		Status:         core.OpStatusSucceeded,
+	})
+	<-done
+
+	cancel()
+	om.WaitStop()
+	assert.True(t, flushed)
+	mdi.AssertExpectations(t)
+}
+
+func TestSubmitUpdateWorkerE2ECoalesceWindowIgnoredWithoutCoalesce(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer om.WaitStop()
+	defer cancel()
+	om.updater.conf.coalesce = false
+	om.updater.conf.coalesceWindow = 10 * time.Minute
+
+	opID1 := fftypes.NewUUID()
+	om.cache = cache.NewUmanagedCache(context.Background(), 100, 10*time.Minute)
+	om.cacheOperation(&core.Operation{ID: opID1, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke})
+
+	done := make(chan struct{})
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Pending"},
+		{"error", ""},
+		{"output", fftypes.JSONObject{"step": 1}.String()},
+	}))).Return(true, nil).Once()
+
+	mdi.On("GetOperationNotifications", mock.Anything, "ns1", mock.Anything).Return([]*core.OperationNotification{}, nil, nil)
+	om.Start()
+	om.SubmitOperationUpdate(&core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusPending,
+		Output:         fftypes.JSONObject{"step": 1},
+		OnComplete:     func() { close(done) },
+	})
+
+	<-done
+	mdi.AssertExpectations(t)
+}
+
+func TestHeldOperationUpdates(t *testing.T) {
+	held := &heldOperationUpdates{byKey: make(map[string]*heldOperationUpdate)}
+	opID1 := "ns1:" + fftypes.NewUUID().String()
+	opID2 := "ns1:" + fftypes.NewUUID().String()
+
+	assert.Empty(t, held.hold(&core.OperationUpdate{NamespacedOpID: opID1, Status: core.OpStatusPending, BlockchainTXID: "tx1"}, time.Minute))
+	assert.Empty(t, held.hold(&core.OperationUpdate{NamespacedOpID: opID2, Status: core.OpStatusPending}, time.Minute))
+	assert.Empty(t, held.hold(&core.OperationUpdate{NamespacedOpID: opID1, Status: core.OpStatusPending, Output: fftypes.JSONObject{"a": 1}}, time.Minute))
+	assert.Len(t, held.list, 2)
+	assert.Empty(t, held.release(time.Now()))
+
+	failed := &core.OperationUpdate{NamespacedOpID: opID2, Status: core.OpStatusFailed}
+	released := held.hold(failed, time.Minute)
+	assert.Len(t, released, 2)
+	assert.Equal(t, opID2, released[0].NamespacedOpID)
+	assert.Equal(t, failed, released[1])
+
+	released = held.release(time.Now().Add(time.Minute))
+	assert.Len(t, released, 1)
+	assert.Equal(t, "tx1", released[0].BlockchainTXID)
+	assert.Equal(t, fftypes.JSONObject{"a": 1}, released[0].Output)
+	assert.Empty(t, held.list)
+	assert.Empty(t, held.byKey)
+
+	succeeded := &core.OperationUpdate{NamespacedOpID: opID1, Status: core.OpStatusSucceeded}
+	assert.Equal(t, []*core.OperationUpdate{succeeded}, held.hold(succeeded, time.Minute))
+
+	assert.Empty(t, held.hold(&core.OperationUpdate{NamespacedOpID: opID1, Status: core.OpStatusPending}, time.Minute))
+	assert.Len(t, held.releaseAll(), 1)
+	assert.Empty(t, held.list)
+	assert.Empty(t, held.byKey)
+}
+
+func TestCoalesceUpdates(t *testing.T) {
+	ou := newTestOperationUpdater(t)
+	defer ou.close()
+
+	opID1 := "ns1:" + fftypes.NewUUID().String()
+	opID2 := "ns1:" + fftypes.NewUUID().String()
+	completed := 0
+	onComplete := func() { completed++ }
+
+	results := ou.coalesceUpdates(ou.ctx, []*core.OperationUpdate{
+		{NamespacedOpID: opID1, Status: core.OpStatusPending, BlockchainTXID: "tx1", Output: fftypes.JSONObject{"a": 1}, OnComplete: onComplete},
+		{NamespacedOpID: opID2, Status: core.OpStatusPending},
+		{NamespacedOpID: opID1, Status: core.OpStatusPending, OnComplete: onComplete},
+		{NamespacedOpID: opID2, Status: core.OpStatusPending, OnComplete: onComplete},
+		{NamespacedOpID: opID1, Status: core.OpStatusFailed, ErrorMessage: "pop"},
+		{NamespacedOpID: opID1, Status: core.OpStatusPending},
+	})
+	assert.Len(t, results, 4)
+	assert.Equal(t, opID1, results[0].NamespacedOpID)
+	assert.Equal(t, "tx1", results[0].BlockchainTXID)
+	assert.Equal(t, fftypes.JSONObject{"a": 1}, results[0].Output)
+	assert.Equal(t, opID2, results[1].NamespacedOpID)
+	assert.Equal(t, core.OpStatusFailed, results[2].Status)
+	assert.Equal(t, core.OpStatusPending, results[3].Status)
+
+	results[0].OnComplete()
+	assert.Equal(t, 2, completed)
+	results[1].OnComplete()
+	assert.Equal(t, 3, completed)
+}
+
 func TestUpdateLoopExitRetryCancelledContext(t *testing.T) {
 	ou := newTestOperationUpdater(t)
 	defer ou.close()
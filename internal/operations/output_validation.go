@@ -0,0 +1,77 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var outputValidationConfig = config.RootArray("operations.outputValidation")
+
+func InitConfig() {
+	outputValidationConfig.AddKnownKey(coreconfig.OperationsOutputValidationType)
+	outputValidationConfig.AddKnownKey(coreconfig.OperationsOutputValidationSchema)
+	initRetryPolicyConfig()
+}
+
+// loadOutputSchemas compiles the configured output schemas, keyed by the operation type they apply to
+func loadOutputSchemas(ctx context.Context) (map[core.OpType]*jsonschema.Schema, error) {
+	schemas := make(map[core.OpType]*jsonschema.Schema)
+	for i := 0; i < outputValidationConfig.ArraySize(); i++ {
+		conf := outputValidationConfig.ArrayEntry(i)
+		typeName := conf.GetString(coreconfig.OperationsOutputValidationType)
+		opType, err := fftypes.FFEnumParseString(ctx, "optype", typeName)
+		if err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidOutputSchema, typeName, err)
+		}
+		schema, err := jsonschema.CompileString(typeName, conf.GetString(coreconfig.OperationsOutputValidationSchema))
+		if err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidOutputSchema, typeName, err)
+		}
+		schemas[opType] = schema
+	}
+	return schemas, nil
+}
+
+// validateOutput checks the output reported for an operation against the schema for its type, if there is one
+func (om *operationsManager) validateOutput(ctx context.Context, opType core.OpType, output fftypes.JSONObject) error {
+	schema := om.outputSchemas[opType]
+	if schema == nil || output == nil {
+		return nil
+	}
+	b, _ := json.Marshal(output)
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value interface{}
+	_ = decoder.Decode(&value)
+	if err := schema.Validate(value); err != nil {
+		log.L(ctx).Warnf("Invalid output for '%s' operation: %s", opType, output)
+		return i18n.NewError(ctx, coremsgs.MsgOperationOutputInvalid, opType, err)
+	}
+	return nil
+}
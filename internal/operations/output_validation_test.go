@@ -0,0 +1,233 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTxHashSchema = `{"type":"object","required":["transactionHash"],"properties":{"transactionHash":{"type":"string"}}}`
+
+func setTestOutputSchema(opType string, schema string) {
+	InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+operations:
+  outputValidation:
+    - type: ` + opType + `
+      schema: '` + schema + `'`))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestLoadOutputSchemas(t *testing.T) {
+	coreconfig.Reset()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, schemas, 1)
+	assert.NotNil(t, schemas[core.OpTypeBlockchainInvoke])
+}
+
+func TestLoadOutputSchemasBadType(t *testing.T) {
+	coreconfig.Reset()
+	setTestOutputSchema("wrong", testTxHashSchema)
+
+	_, err := loadOutputSchemas(context.Background())
+	assert.Regexp(t, "FF10494.*wrong", err)
+}
+
+func TestLoadOutputSchemasBadSchema(t *testing.T) {
+	coreconfig.Reset()
+	setTestOutputSchema("blockchain_invoke", `{"type":12345}`)
+
+	_, err := loadOutputSchemas(context.Background())
+	assert.Regexp(t, "FF10494.*blockchain_invoke", err)
+}
+
+func TestNewOperationsManagerBadOutputSchema(t *testing.T) {
+	coreconfig.Reset()
+	setTestOutputSchema("wrong", testTxHashSchema)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(nil, nil)
+
+	_, err := NewOperationsManager(context.Background(), "ns1", nil, &databasemocks.Plugin{}, &txcommonmocks.Helper{}, cmi)
+	assert.Regexp(t, "FF10494", err)
+}
+
+func TestValidateOutput(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	om.outputSchemas = schemas
+
+	ctx := context.Background()
+	assert.NoError(t, om.validateOutput(ctx, core.OpTypeBlockchainInvoke, fftypes.JSONObject{"transactionHash": "0x123", "count": 1}))
+	assert.NoError(t, om.validateOutput(ctx, core.OpTypeBlockchainInvoke, nil))
+	assert.NoError(t, om.validateOutput(ctx, core.OpTypeBlockchainPinBatch, fftypes.JSONObject{"transactionHash": 1}))
+	err = om.validateOutput(ctx, core.OpTypeBlockchainInvoke, fftypes.JSONObject{"transactionHash": 1})
+	assert.Regexp(t, "FF10495.*blockchain_invoke", err)
+}
+
+func TestDoUpdateInvalidOutput(t *testing.T) {
+	ou := newTestOperationUpdaterNoConcurrency(t)
+	defer ou.close()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	ou.manager.outputSchemas = schemas
+
+	opID1 := fftypes.NewUUID()
+	ou.initQueues()
+
+	mdi := ou.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID1, mock.Anything, mock.Anything).Return(true, nil)
+
+	update := &core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID1.String(),
+		Status:         core.OpStatusSucceeded,
+		Output:         fftypes.JSONObject{"transactionHash": 1},
+	}
+	err = ou.doUpdate(ou.ctx, update, []*core.Operation{{
+		Namespace: "ns1",
+		ID:        opID1,
+		Type:      core.OpTypeBlockchainInvoke,
+	}}, []*core.Transaction{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Regexp(t, "FF10495", update.ErrorMessage)
+	assert.Nil(t, update.Output)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveOperationByIDInvalidOutput(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	om.outputSchemas = schemas
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	om.cache.Set(opID.String(), &core.Operation{ID: opID, Type: core.OpTypeBlockchainInvoke})
+	opUpdate := &core.OperationUpdateDTO{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{"transactionHash": 1},
+	}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(true, nil)
+
+	err = om.ResolveOperationByID(ctx, opID, opUpdate)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusFailed, opUpdate.Status)
+	assert.Regexp(t, "FF10495", *opUpdate.Error)
+	assert.Nil(t, opUpdate.Output)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveOperationByIDValidOutput(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	om.outputSchemas = schemas
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	om.cache.Set(opID.String(), &core.Operation{ID: opID, Type: core.OpTypeBlockchainInvoke})
+	opUpdate := &core.OperationUpdateDTO{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{"transactionHash": "0x123"},
+	}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(true, nil)
+
+	err = om.ResolveOperationByID(ctx, opID, opUpdate)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusSucceeded, opUpdate.Status)
+	assert.NotNil(t, opUpdate.Output)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveOperationByIDValidateLookupFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	om.outputSchemas = schemas
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", ctx, "ns1", opID).Return(nil, fmt.Errorf("pop"))
+
+	err = om.ResolveOperationByID(ctx, opID, &core.OperationUpdateDTO{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{"transactionHash": 1},
+	})
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveOperationByIDValidateNotFound(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	setTestOutputSchema("blockchain_invoke", testTxHashSchema)
+	schemas, err := loadOutputSchemas(context.Background())
+	assert.NoError(t, err)
+	om.outputSchemas = schemas
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", ctx, "ns1", opID).Return(nil, nil)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(false, nil)
+
+	err = om.ResolveOperationByID(ctx, opID, &core.OperationUpdateDTO{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{"transactionHash": 1},
+	})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
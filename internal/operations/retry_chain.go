@@ -0,0 +1,96 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// maxRetryChainLength bounds the walk of a retry chain, in case the linkage in the database is corrupt
+const maxRetryChainLength = 1000
+
+type retryChainWalk struct {
+	opID    *fftypes.UUID
+	visited map[fftypes.UUID]bool
+}
+
+func (w *retryChainWalk) visit(ctx context.Context, op *core.Operation) error {
+	if w.visited[*op.ID] {
+		return i18n.NewError(ctx, coremsgs.MsgOperationRetryChainCycle, w.opID, op.ID)
+	}
+	if len(w.visited) >= maxRetryChainLength {
+		return i18n.NewError(ctx, coremsgs.MsgOperationRetryChainTooLong, w.opID, maxRetryChainLength)
+	}
+	w.visited[*op.ID] = true
+	return nil
+}
+
+// GetOperationRetryChain returns the operations linked to an operation by retries, in order from
+// the original operation through to the latest retry. The chain is walked backwards to the original
+// operation using the retryParent of each operation, and then forwards by finding the operations that
+// were created as a retry of each one. The retryParent of an operation is set when it is created and
+// never changes, so the chain includes every retry that was submitted - even where the retry link
+// on the operation it superseded was not set. If the chain branched, because the same operation was
+// retried more than once, every branch is included and each retry follows the operation it retried.
+func (om *operationsManager) GetOperationRetryChain(ctx context.Context, opID *fftypes.UUID) ([]*core.Operation, error) {
+	op, err := om.GetOperationByIDCached(ctx, opID)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+
+	w := &retryChainWalk{opID: opID, visited: map[fftypes.UUID]bool{*op.ID: true}}
+	original := op
+	for original.RetryParent != nil {
+		prev, err := om.GetOperationByIDCached(ctx, original.RetryParent)
+		if err != nil {
+			return nil, err
+		}
+		if prev == nil {
+			break
+		}
+		if err := w.visit(ctx, prev); err != nil {
+			return nil, err
+		}
+		original = prev
+	}
+
+	w = &retryChainWalk{opID: opID, visited: map[fftypes.UUID]bool{*original.ID: true}}
+	chain := []*core.Operation{original}
+	for i := 0; i < len(chain); i++ {
+		fb := database.OperationQueryFactory.NewFilter(ctx)
+		retries, _, err := om.database.GetOperations(ctx, om.namespace, fb.And(fb.Eq("retryparent", chain[i].ID)).Sort("created"))
+		if err != nil {
+			return nil, err
+		}
+		for _, retry := range retries {
+			if err := w.visit(ctx, retry); err != nil {
+				return nil, err
+			}
+			chain = append(chain, retry)
+		}
+	}
+	return chain, nil
+}
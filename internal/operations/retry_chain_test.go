@@ -0,0 +1,239 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func retriesOf(op *core.Operation) interface{} {
+	return mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("( retryparent == '%s' ) sort=created", op.ID)
+	})
+}
+
+func TestGetOperationRetryChain(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opA := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed}
+	opB := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, RetryParent: opA.ID, RetryDepth: 1}
+	opC := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: opB.ID, RetryDepth: 2}
+	opA.Retry = opB.ID
+	opB.Retry = opC.ID
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opA.ID).Return(opA, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opB.ID).Return(opB, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opA)).Return([]*core.Operation{opB}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opB)).Return([]*core.Operation{opC}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opC)).Return([]*core.Operation{}, nil, nil)
+
+	chain, err := om.GetOperationRetryChain(context.Background(), opB.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.Operation{opA, opB, opC}, chain)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainMissingRetryLink(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	// A retry of opA after it was already retried as opB was created as a retry of opB, but was
+	// linked from opA - so opB has no retry link, but is still part of the chain
+	opA := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed}
+	opB := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, RetryParent: opA.ID, RetryDepth: 1}
+	opC := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: opB.ID, RetryDepth: 2}
+	opA.Retry = opC.ID
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opA.ID).Return(opA, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opA)).Return([]*core.Operation{opB}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opB)).Return([]*core.Operation{opC}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opC)).Return([]*core.Operation{}, nil, nil)
+
+	chain, err := om.GetOperationRetryChain(context.Background(), opA.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.Operation{opA, opB, opC}, chain)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainBranched(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	// opA was retried twice, as opB and opC, and opB was then retried as opD
+	opA := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed}
+	opB := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusFailed, RetryParent: opA.ID, RetryDepth: 1}
+	opC := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: opA.ID, RetryDepth: 1}
+	opD := &core.Operation{ID: fftypes.NewUUID(), Status: core.OpStatusSucceeded, RetryParent: opB.ID, RetryDepth: 2}
+	opA.Retry = opB.ID
+	opB.Retry = opD.ID
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opD.ID).Return(opD, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opB.ID).Return(opB, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opA.ID).Return(opA, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opA)).Return([]*core.Operation{opB, opC}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opB)).Return([]*core.Operation{opD}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opC)).Return([]*core.Operation{}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(opD)).Return([]*core.Operation{}, nil, nil)
+
+	chain, err := om.GetOperationRetryChain(context.Background(), opD.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.Operation{opA, opB, opC, opD}, chain)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainDanglingParent(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), RetryParent: fftypes.NewUUID(), Retry: fftypes.NewUUID()}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.RetryParent).Return(nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(op)).Return([]*core.Operation{}, nil, nil)
+
+	chain, err := om.GetOperationRetryChain(context.Background(), op.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.Operation{op}, chain)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainNotFound(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := om.GetOperationRetryChain(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := om.GetOperationRetryChain(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainParentFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), RetryParent: fftypes.NewUUID()}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.RetryParent).Return(nil, fmt.Errorf("pop"))
+
+	_, err := om.GetOperationRetryChain(context.Background(), op.ID)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainRetryFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Retry: fftypes.NewUUID()}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(op)).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := om.GetOperationRetryChain(context.Background(), op.ID)
+	assert.Regexp(t, "pop", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainCycle(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID()}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", retriesOf(op)).Return([]*core.Operation{op}, nil, nil)
+
+	_, err := om.GetOperationRetryChain(context.Background(), op.ID)
+	assert.Regexp(t, "FF10503", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainParentCycle(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID()}
+	op.RetryParent = op.ID
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", op.ID).Return(op, nil)
+
+	_, err := om.GetOperationRetryChain(context.Background(), op.ID)
+	assert.Regexp(t, "FF10503", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetOperationRetryChainTooLong(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", mock.Anything).Return(func(ctx context.Context, ns string, id *fftypes.UUID) *core.Operation {
+		return &core.Operation{ID: id}
+	}, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(func(ctx context.Context, ns string, filter ffapi.Filter) []*core.Operation {
+		return []*core.Operation{{ID: fftypes.NewUUID()}}
+	}, nil, nil)
+
+	_, err := om.GetOperationRetryChain(context.Background(), fftypes.NewUUID())
+	assert.Regexp(t, "FF10504", err)
+
+	mdi.AssertExpectations(t)
+}
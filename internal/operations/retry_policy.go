@@ -0,0 +1,229 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var retryPolicyConfig = config.RootArray("operations.retryPolicies")
+
+// retryPolicy controls the automatic retry of failed operations of one type
+type retryPolicy struct {
+	maxRetries      int
+	initialDelay    time.Duration
+	maxDelay        time.Duration
+	factor          float64
+	retryableErrors []*regexp.Regexp
+	terminalErrors  []*regexp.Regexp
+}
+
+func initRetryPolicyConfig() {
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyType)
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyMaxRetries, 3)
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyInitialDelay, "5s")
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyMaxDelay, "5m")
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyFactor, 2.0)
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyRetryableErrors)
+	retryPolicyConfig.AddKnownKey(coreconfig.OperationsRetryPolicyTerminalErrors)
+}
+
+// loadRetryPolicies parses the configured retry policies, keyed by the operation type they apply to
+func loadRetryPolicies(ctx context.Context) (map[core.OpType]*retryPolicy, error) {
+	policies := make(map[core.OpType]*retryPolicy)
+	for i := 0; i < retryPolicyConfig.ArraySize(); i++ {
+		conf := retryPolicyConfig.ArrayEntry(i)
+		typeName := conf.GetString(coreconfig.OperationsRetryPolicyType)
+		opType, err := fftypes.FFEnumParseString(ctx, "optype", typeName)
+		if err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidRetryPolicy, typeName, err)
+		}
+		policy := &retryPolicy{
+			maxRetries:   conf.GetInt(coreconfig.OperationsRetryPolicyMaxRetries),
+			initialDelay: conf.GetDuration(coreconfig.OperationsRetryPolicyInitialDelay),
+			maxDelay:     conf.GetDuration(coreconfig.OperationsRetryPolicyMaxDelay),
+			factor:       conf.GetFloat64(coreconfig.OperationsRetryPolicyFactor),
+		}
+		if policy.retryableErrors, err = compileErrorPatterns(conf.GetStringSlice(coreconfig.OperationsRetryPolicyRetryableErrors)); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidRetryPolicy, typeName, err)
+		}
+		if policy.terminalErrors, err = compileErrorPatterns(conf.GetStringSlice(coreconfig.OperationsRetryPolicyTerminalErrors)); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidRetryPolicy, typeName, err)
+		}
+		policies[opType] = policy
+	}
+	return policies, nil
+}
+
+func compileErrorPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled[i] = re
+	}
+	return compiled, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, errorMsg string) bool {
+	for _, re := range patterns {
+		if re.MatchString(errorMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryable classifies an error - terminal errors take precedence, and if any retryable errors
+// are configured the error must match one of them
+func (rp *retryPolicy) retryable(errorMsg string) bool {
+	if matchesAny(rp.terminalErrors, errorMsg) {
+		return false
+	}
+	return len(rp.retryableErrors) == 0 || matchesAny(rp.retryableErrors, errorMsg)
+}
+
+// delay is the exponential backoff before the automatic retry that follows the supplied number of earlier retries
+func (rp *retryPolicy) delay(retries int) time.Duration {
+	delay := float64(rp.initialDelay) * math.Pow(rp.factor, float64(retries))
+	if delay > float64(rp.maxDelay) {
+		return rp.maxDelay
+	}
+	return time.Duration(delay)
+}
+
+// recordFailure applies the retry policy for the type of an operation that is being resolved as failed. The
+// decision is added to the retry history in the supplied update, and if a retry is due its delay is returned.
+func (om *operationsManager) recordFailure(ctx context.Context, id *fftypes.UUID, errorMsg *string, update ffapi.Update) (history core.OperationRetryHistory, retryDelay time.Duration) {
+	if len(om.retryPolicies) == 0 {
+		return nil, 0
+	}
+	op, err := om.GetOperationByIDCached(ctx, id)
+	if err != nil || op == nil {
+		return nil, 0
+	}
+	policy := om.retryPolicies[op.Type]
+	if policy == nil || op.Status == core.OpStatusFailed || op.Retry != nil {
+		// No policy, or the failure of this operation has already been handled
+		return nil, 0
+	}
+
+	record := &core.OperationRetryRecord{
+		Operation: op.ID,
+		Failed:    fftypes.Now(),
+		Outcome:   core.OpRetryOutcomeTerminal,
+	}
+	if errorMsg != nil {
+		record.Error = *errorMsg
+	}
+	retries := op.RetryHistory.AutomaticRetries()
+	if policy.retryable(record.Error) {
+		if retries >= policy.maxRetries {
+			record.Outcome = core.OpRetryOutcomeExhausted
+		} else {
+			record.Outcome = core.OpRetryOutcomeRetry
+			retryDelay = policy.delay(retries)
+			retryAt := fftypes.FFTime(record.Failed.Time().Add(retryDelay))
+			record.RetryAt = &retryAt
+			update.Set("retryat", record.RetryAt)
+		}
+	}
+	log.L(ctx).Infof("Retry policy for failed %s operation %s after %d automatic retries: %s", op.Type, op.ID, retries, record.Outcome)
+
+	history = append(append(core.OperationRetryHistory{}, op.RetryHistory...), record)
+	historyBytes, _ := json.Marshal(history)
+	update.Set("retryhistory", fftypes.JSONAnyPtrBytes(historyBytes))
+	return history, retryDelay
+}
+
+// scheduleRetry retries a failed operation in the background once the delay has passed. The time the retry is due
+// is stored on the operation, so retries that had not run at shutdown are scheduled again on startup.
+func (om *operationsManager) scheduleRetry(id *fftypes.UUID, delay time.Duration) {
+	om.retries.Add(1)
+	go func() {
+		defer om.retries.Done()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			om.autoRetry(om.ctx, id)
+		case <-om.ctx.Done():
+			log.L(om.ctx).Debugf("Automatic retry of operation %s cancelled", id)
+		}
+	}()
+}
+
+// scheduleDueRetries schedules the automatic retries stored on failed operations by an earlier run, running
+// those that fell due while the node was stopped straight away
+func (om *operationsManager) scheduleDueRetries(ctx context.Context) error {
+	if len(om.retryPolicies) == 0 {
+		return nil
+	}
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := om.database.GetOperations(ctx, om.namespace, fb.And(
+		fb.Eq("status", core.OpStatusFailed),
+		fb.Gt("retryat", 0),
+	))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, op := range ops {
+		if op.Retry != nil {
+			continue
+		}
+		delay := op.RetryAt.Time().Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		log.L(ctx).Infof("Scheduling stored automatic retry of operation %s in %s", op.ID, delay)
+		om.scheduleRetry(op.ID, delay)
+	}
+	return nil
+}
+
+func (om *operationsManager) autoRetry(ctx context.Context, id *fftypes.UUID) {
+	// Check the stored state, as the operation might have been retried on request in the meantime.
+	// RetryOperation only links the retry if that is still the case when it commits.
+	op, err := om.database.GetOperationByID(ctx, om.namespace, id)
+	if err != nil || op == nil || op.Status != core.OpStatusFailed || op.Retry != nil {
+		log.L(ctx).Infof("Skipping automatic retry of operation %s: %v", id, err)
+		return
+	}
+	retry, err := om.RetryOperation(ctx, id)
+	if err != nil {
+		log.L(ctx).Errorf("Automatic retry of operation %s failed: %s", id, err)
+		return
+	}
+	log.L(ctx).Infof("Automatically retried operation %s as %s", id, retry.ID)
+}
@@ -0,0 +1,361 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setTestRetryPolicy(policyYAML string) {
+	InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+operations:
+  retryPolicies:
+    - ` + policyYAML))
+	if err != nil {
+		panic(err)
+	}
+}
+
+func testRetryPolicy() *retryPolicy {
+	return &retryPolicy{
+		maxRetries:     2,
+		initialDelay:   1 * time.Millisecond,
+		maxDelay:       1 * time.Second,
+		factor:         2,
+		terminalErrors: []*regexp.Regexp{regexp.MustCompile("reverted")},
+	}
+}
+
+func TestLoadRetryPolicies(t *testing.T) {
+	coreconfig.Reset()
+	setTestRetryPolicy(`type: blockchain_invoke
+      maxRetries: 5
+      initialDelay: 1s
+      retryableErrors: ["timeout", "FF10\\d+"]
+      terminalErrors: ["reverted"]`)
+
+	policies, err := loadRetryPolicies(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	policy := policies[core.OpTypeBlockchainInvoke]
+	assert.Equal(t, 5, policy.maxRetries)
+	assert.Equal(t, 1*time.Second, policy.initialDelay)
+	assert.Equal(t, 5*time.Minute, policy.maxDelay)
+	assert.Equal(t, 2.0, policy.factor)
+	assert.True(t, policy.retryable("request timeout"))
+	assert.True(t, policy.retryable("FF10123: failed"))
+	assert.False(t, policy.retryable("timeout: transaction reverted"))
+	assert.False(t, policy.retryable("insufficient funds"))
+}
+
+func TestLoadRetryPoliciesBadType(t *testing.T) {
+	coreconfig.Reset()
+	setTestRetryPolicy(`type: wrong`)
+
+	_, err := loadRetryPolicies(context.Background())
+	assert.Regexp(t, "FF10549.*wrong", err)
+}
+
+func TestLoadRetryPoliciesBadRetryableErrors(t *testing.T) {
+	coreconfig.Reset()
+	setTestRetryPolicy(`type: blockchain_invoke
+      retryableErrors: ["("]`)
+
+	_, err := loadRetryPolicies(context.Background())
+	assert.Regexp(t, "FF10549.*blockchain_invoke", err)
+}
+
+func TestLoadRetryPoliciesBadTerminalErrors(t *testing.T) {
+	coreconfig.Reset()
+	setTestRetryPolicy(`type: blockchain_invoke
+      terminalErrors: ["("]`)
+
+	_, err := loadRetryPolicies(context.Background())
+	assert.Regexp(t, "FF10549.*blockchain_invoke", err)
+}
+
+func TestNewOperationsManagerBadRetryPolicy(t *testing.T) {
+	coreconfig.Reset()
+	setTestRetryPolicy(`type: wrong`)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(nil, nil)
+
+	_, err := NewOperationsManager(context.Background(), "ns1", nil, &databasemocks.Plugin{}, &txcommonmocks.Helper{}, cmi)
+	assert.Regexp(t, "FF10549", err)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	rp := &retryPolicy{initialDelay: 1 * time.Second, maxDelay: 10 * time.Second, factor: 3}
+	assert.Equal(t, 1*time.Second, rp.delay(0))
+	assert.Equal(t, 3*time.Second, rp.delay(1))
+	assert.Equal(t, 9*time.Second, rp.delay(2))
+	assert.Equal(t, 10*time.Second, rp.delay(3))
+}
+
+func retryHistoryMatcher(t *testing.T, outcome core.OpRetryOutcome, entries int) func(update ffapi.Update) bool {
+	return func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		for _, so := range info.SetOperations {
+			if so.Field == "retryhistory" {
+				var history core.OperationRetryHistory
+				err := history.Scan(so.Value.(fmt.Stringer).String())
+				assert.NoError(t, err)
+				return len(history) == entries && history[entries-1].Outcome == outcome
+			}
+		}
+		return false
+	}
+}
+
+func TestResolveOperationFailedSchedulesRetry(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.retryPolicies = map[core.OpType]*retryPolicy{core.OpTypeBlockchainInvoke: testRetryPolicy()}
+
+	opID := fftypes.NewUUID()
+	om.cacheOperation(&core.Operation{ID: opID, Type: core.OpTypeBlockchainInvoke, Status: core.OpStatusPending})
+
+	retried := make(chan struct{})
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.MatchedBy(retryHistoryMatcher(t, core.OpRetryOutcomeRetry, 1))).Return(true, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{
+		ID:     opID,
+		Status: core.OpStatusSucceeded, // resolved some other way before the retry was due
+	}, nil).Run(func(args mock.Arguments) {
+		close(retried)
+	})
+
+	errMsg := "timeout"
+	err := om.updater.resolveOperation(context.Background(), "ns1", opID, core.OpStatusFailed, &errMsg, nil, nil)
+	assert.NoError(t, err)
+
+	op, err := om.GetOperationByIDCached(context.Background(), opID)
+	assert.NoError(t, err)
+	assert.Len(t, op.RetryHistory, 1)
+	assert.Equal(t, "timeout", op.RetryHistory[0].Error)
+	assert.NotNil(t, op.RetryHistory[0].RetryAt)
+	assert.Equal(t, op.RetryHistory[0].RetryAt, op.RetryAt)
+
+	<-retried
+	om.retries.Wait()
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveOperationFailedTerminal(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.retryPolicies = map[core.OpType]*retryPolicy{core.OpTypeBlockchainInvoke: testRetryPolicy()}
+
+	opID := fftypes.NewUUID()
+	om.cacheOperation(&core.Operation{ID: opID, Type: core.OpTypeBlockchainInvoke, Status: core.OpStatusPending})
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.MatchedBy(retryHistoryMatcher(t, core.OpRetryOutcomeTerminal, 1))).Return(true, nil)
+
+	errMsg := "transaction reverted"
+	err := om.updater.resolveOperation(context.Background(), "ns1", opID, core.OpStatusFailed, &errMsg, nil, nil)
+	assert.NoError(t, err)
+
+	om.retries.Wait()
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveOperationFailedExhausted(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.retryPolicies = map[core.OpType]*retryPolicy{core.OpTypeBlockchainInvoke: testRetryPolicy()}
+
+	opID := fftypes.NewUUID()
+	om.cacheOperation(&core.Operation{ID: opID, Type: core.OpTypeBlockchainInvoke, Status: core.OpStatusPending,
+		RetryHistory: core.OperationRetryHistory{
+			{Operation: fftypes.NewUUID(), Outcome: core.OpRetryOutcomeRetry},
+			{Operation: fftypes.NewUUID(), Outcome: core.OpRetryOutcomeRetry},
+		},
+	})
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.MatchedBy(retryHistoryMatcher(t, core.OpRetryOutcomeExhausted, 3))).Return(true, nil)
+
+	err := om.updater.resolveOperation(context.Background(), "ns1", opID, core.OpStatusFailed, nil, nil, nil)
+	assert.NoError(t, err)
+
+	om.retries.Wait()
+	mdi.AssertExpectations(t)
+}
+
+func TestRecordFailureNotApplicable(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	ctx := context.Background()
+	update := func() ffapi.Update { return database.OperationQueryFactory.NewUpdate(ctx).S() }
+
+	opID := fftypes.NewUUID()
+	history, _ := om.recordFailure(ctx, opID, nil, update())
+	assert.Nil(t, history)
+
+	om.retryPolicies = map[core.OpType]*retryPolicy{core.OpTypeBlockchainInvoke: testRetryPolicy()}
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, fmt.Errorf("pop")).Once()
+	history, _ = om.recordFailure(ctx, opID, nil, update())
+	assert.Nil(t, history)
+
+	om.cacheOperation(&core.Operation{ID: opID, Type: core.OpTypeBlockchainPinBatch, Status: core.OpStatusPending})
+	history, _ = om.recordFailure(ctx, opID, nil, update())
+	assert.Nil(t, history)
+
+	om.cacheOperation(&core.Operation{ID: opID, Type: core.OpTypeBlockchainInvoke, Status: core.OpStatusFailed})
+	history, _ = om.recordFailure(ctx, opID, nil, update())
+	assert.Nil(t, history)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestScheduleRetryCancelled(t *testing.T) {
+	om, cancel := newTestOperations(t)
+
+	om.scheduleRetry(fftypes.NewUUID(), 1*time.Hour)
+	cancel()
+	om.retries.Wait()
+}
+
+func TestScheduleDueRetries(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.retryPolicies = map[core.OpType]*retryPolicy{core.OpTypeBlockchainInvoke: testRetryPolicy()}
+
+	dueID := fftypes.NewUUID()
+	retriedID := fftypes.NewUUID()
+	retried := make(chan struct{})
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		fi, err := filter.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( status == 'Failed' ) && ( retryat >> 0 )", fi.String())
+		return true
+	})).Return([]*core.Operation{
+		{ID: dueID, Status: core.OpStatusFailed, RetryAt: fftypes.Now()},
+		{ID: retriedID, Status: core.OpStatusFailed, RetryAt: fftypes.Now(), Retry: fftypes.NewUUID()},
+	}, nil, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", dueID).Return(&core.Operation{
+		ID:     dueID,
+		Status: core.OpStatusSucceeded,
+	}, nil).Run(func(args mock.Arguments) {
+		close(retried)
+	})
+
+	err := om.scheduleDueRetries(context.Background())
+	assert.NoError(t, err)
+
+	<-retried
+	om.retries.Wait()
+	mdi.AssertExpectations(t)
+}
+
+func TestScheduleDueRetriesFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	om.retryPolicies = map[core.OpType]*retryPolicy{core.OpTypeBlockchainInvoke: testRetryPolicy()}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := om.scheduleDueRetries(context.Background())
+	assert.Regexp(t, "pop", err)
+	err = om.Start()
+	assert.Regexp(t, "pop", err)
+	mdi.AssertExpectations(t)
+}
+
+func TestAutoRetryLookupFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, fmt.Errorf("pop"))
+
+	om.autoRetry(context.Background(), opID)
+	mdi.AssertExpectations(t)
+}
+
+func TestAutoRetryFail(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	op := &core.Operation{ID: opID, Namespace: "ns1", Transaction: txID, Type: core.OpTypeBlockchainInvoke, Status: core.OpStatusFailed}
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(op, nil)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", txID).Return(nil, fmt.Errorf("pop"))
+
+	om.autoRetry(context.Background(), opID)
+	mdi.AssertExpectations(t)
+}
+
+func TestAutoRetrySuccess(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	ctx := context.Background()
+	opID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:          opID,
+		Namespace:   "ns1",
+		Plugin:      "blockchain",
+		Transaction: txID,
+		Type:        core.OpTypeBlockchainInvoke,
+		Status:      core.OpStatusFailed,
+		RetryHistory: core.OperationRetryHistory{
+			{Operation: opID, Error: "timeout", Outcome: core.OpRetryOutcomeRetry},
+		},
+	}
+	po := &core.PreparedOperation{ID: op.ID, Type: op.Type}
+
+	mdi := om.database.(*databasemocks.Plugin)
+	mdi.On("GetOperationByID", ctx, "ns1", opID).Return(op, nil)
+	mdi.On("GetTransactionByID", ctx, "ns1", txID).Return(&core.Transaction{ID: txID}, nil)
+	mdi.On("InsertOperation", ctx, mock.MatchedBy(func(newOp *core.Operation) bool {
+		return *newOp.RetryParent == *opID && len(newOp.RetryHistory) == 1 && newOp.Status == core.OpStatusInitialized
+	})).Return(nil)
+	mdi.On("UpdateOperation", ctx, "ns1", opID, mock.Anything, mock.Anything).Return(true, nil)
+
+	om.RegisterHandler(ctx, &mockHandler{Prepared: po}, []core.OpType{core.OpTypeBlockchainInvoke})
+	om.autoRetry(ctx, opID)
+
+	mdi.AssertExpectations(t)
+}
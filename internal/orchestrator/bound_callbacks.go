@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -45,6 +46,12 @@ func (bc *boundCallbacks) OperationUpdate(update *core.OperationUpdate) {
 	bc.o.operations.SubmitOperationUpdate(update)
 }
 
+func (bc *boundCallbacks) TransferProgress(nsOpID string, transferred, size int64) {
+	if err := bc.o.operations.UpdateTransferProgress(bc.o.ctx, nsOpID, transferred, size); err != nil {
+		log.L(bc.o.ctx).Warnf("Failed to record progress of transfer %s: %s", nsOpID, err)
+	}
+}
+
 func (bc *boundCallbacks) SharedStorageBatchDownloaded(payloadRef string, data []byte) (*fftypes.UUID, error) {
 	if err := bc.checkStopped(); err != nil {
 		return nil, err
@@ -78,6 +78,12 @@ func TestBoundCallbacks(t *testing.T) {
 	mom.On("SubmitOperationUpdate", update).Return().Once()
 	bc.OperationUpdate(update)
 
+	mom.On("UpdateTransferProgress", mock.Anything, nsOpID, int64(1024), int64(4096)).Return(nil).Once()
+	bc.TransferProgress(nsOpID, 1024, 4096)
+
+	mom.On("UpdateTransferProgress", mock.Anything, nsOpID, int64(2048), int64(4096)).Return(fmt.Errorf("pop")).Once()
+	bc.TransferProgress(nsOpID, 2048, 4096)
+
 	mei.On("SharedStorageBatchDownloaded", mss, "payload1", []byte(`{}`)).Return(nil, fmt.Errorf("pop"))
 	_, err := bc.SharedStorageBatchDownloaded("payload1", []byte(`{}`))
 	assert.EqualError(t, err, "pop")
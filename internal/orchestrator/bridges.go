@@ -0,0 +1,119 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/bridge"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// bridgeSubscription builds the durable subscription that delivers the matching messages to the bridge transport
+func bridgeSubscription(b *core.Bridge) *core.Subscription {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			Name: b.Name,
+		},
+		Transport: bridge.BridgeTransport,
+		Filter: core.SubscriptionFilter{
+			Events: string(core.EventTypeMessageConfirmed),
+			Topic:  b.Topic,
+			Message: core.MessageFilter{
+				Tag: b.Tag,
+			},
+		},
+	}
+	sub.Options.TransportOptions()[bridge.TargetOption] = b.Target
+	return sub
+}
+
+func subscriptionBridge(sub *core.Subscription) *core.Bridge {
+	return &core.Bridge{
+		ID:        sub.ID,
+		Namespace: sub.Namespace,
+		Name:      sub.Name,
+		Target:    sub.Options.TransportOptions().GetString(bridge.TargetOption),
+		Topic:     sub.Filter.Topic,
+		Tag:       sub.Filter.Message.Tag,
+		Created:   sub.Created,
+	}
+}
+
+func (or *orchestrator) CreateBridge(ctx context.Context, b *core.Bridge) (*core.Bridge, error) {
+	if b.Target == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBridgeTargetRequired)
+	}
+	if b.Target == or.namespace.Name {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBridgeTargetSameNamespace, or.namespace.Name)
+	}
+	sub, err := or.createUpdateSubscription(ctx, bridgeSubscription(b), true)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionBridge(sub), nil
+}
+
+func (or *orchestrator) GetBridges(ctx context.Context, filter ffapi.AndFilter) ([]*core.Bridge, *ffapi.FilterResult, error) {
+	subs, fr, err := or.database().GetSubscriptions(ctx, or.namespace.Name, filter.Condition(filter.Builder().Eq("transport", bridge.BridgeTransport)))
+	if err != nil {
+		return nil, nil, err
+	}
+	bridges := make([]*core.Bridge, len(subs))
+	for i, sub := range subs {
+		bridges[i] = subscriptionBridge(sub)
+	}
+	return bridges, fr, nil
+}
+
+func (or *orchestrator) getBridgeSubscription(ctx context.Context, nameOrID string) (sub *core.Subscription, err error) {
+	id, err := fftypes.ParseUUID(ctx, nameOrID)
+	if err != nil {
+		if err := fftypes.ValidateFFNameField(ctx, nameOrID, "name"); err != nil {
+			return nil, err
+		}
+		if sub, err = or.database().GetSubscriptionByName(ctx, or.namespace.Name, nameOrID); err != nil {
+			return nil, err
+		}
+	} else if sub, err = or.database().GetSubscriptionByID(ctx, or.namespace.Name, id); err != nil {
+		return nil, err
+	}
+	if sub == nil || sub.Transport != bridge.BridgeTransport {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return sub, nil
+}
+
+func (or *orchestrator) GetBridgeByNameOrID(ctx context.Context, nameOrID string) (*core.Bridge, error) {
+	sub, err := or.getBridgeSubscription(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionBridge(sub), nil
+}
+
+func (or *orchestrator) DeleteBridge(ctx context.Context, nameOrID string) error {
+	sub, err := or.getBridgeSubscription(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+	return or.events.DeleteDurableSubscription(ctx, sub)
+}
@@ -0,0 +1,205 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/events/bridge"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBridgeSubscription() *core.Subscription {
+	return bridgeSubscription(&core.Bridge{
+		Name:   "bridge1",
+		Target: "ns2",
+		Topic:  "topic1",
+		Tag:    "tag1",
+	})
+}
+
+func TestCreateBridgeOk(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mem.On("CreateUpdateDurableSubscription", mock.Anything, mock.MatchedBy(func(sub *core.Subscription) bool {
+		return sub.Filter.Events == string(core.EventTypeMessageConfirmed) &&
+			sub.Filter.Topic == "topic1" &&
+			sub.Filter.Message.Tag == "tag1" &&
+			sub.Options.TransportOptions().GetString(bridge.TargetOption) == "ns2"
+	}), true).Return(nil)
+
+	b, err := or.CreateBridge(or.ctx, &core.Bridge{
+		Name:   "bridge1",
+		Target: "ns2",
+		Topic:  "topic1",
+		Tag:    "tag1",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, b.ID)
+	assert.NotNil(t, b.Created)
+	assert.Equal(t, "ns", b.Namespace)
+	assert.Equal(t, "bridge1", b.Name)
+	assert.Equal(t, "ns2", b.Target)
+	assert.Equal(t, "topic1", b.Topic)
+	assert.Equal(t, "tag1", b.Tag)
+}
+
+func TestCreateBridgeNoTarget(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateBridge(or.ctx, &core.Bridge{Name: "bridge1"})
+	assert.Regexp(t, "FF10593", err)
+}
+
+func TestCreateBridgeSameNamespace(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateBridge(or.ctx, &core.Bridge{Name: "bridge1", Target: "ns"})
+	assert.Regexp(t, "FF10594", err)
+}
+
+func TestCreateBridgeFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateBridge(or.ctx, &core.Bridge{Name: "!bad", Target: "ns2"})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestGetBridges(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	sub := newTestBridgeSubscription()
+	sub.ID = fftypes.NewUUID()
+	or.mdi.On("GetSubscriptions", mock.Anything, "ns", mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		f, _ := filter.Finalize()
+		return f.String() == "( name == 'bridge1' ) && ( transport == 'bridge' )"
+	})).Return([]*core.Subscription{sub}, nil, nil)
+
+	fb := database.SubscriptionQueryFactory.NewFilter(context.Background())
+	bridges, _, err := or.GetBridges(or.ctx, fb.And(fb.Eq("name", "bridge1")))
+	assert.NoError(t, err)
+	assert.Len(t, bridges, 1)
+	assert.Equal(t, sub.ID, bridges[0].ID)
+	assert.Equal(t, "ns2", bridges[0].Target)
+}
+
+func TestGetBridgesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptions", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	fb := database.SubscriptionQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetBridges(or.ctx, fb.And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetBridgeByName(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns", "bridge1").Return(newTestBridgeSubscription(), nil)
+
+	b, err := or.GetBridgeByNameOrID(or.ctx, "bridge1")
+	assert.NoError(t, err)
+	assert.Equal(t, "bridge1", b.Name)
+}
+
+func TestGetBridgeByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", id).Return(newTestBridgeSubscription(), nil)
+
+	b, err := or.GetBridgeByNameOrID(or.ctx, id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "bridge1", b.Name)
+}
+
+func TestGetBridgeBadName(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetBridgeByNameOrID(or.ctx, "!bad")
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestGetBridgeByNameFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns", "bridge1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.GetBridgeByNameOrID(or.ctx, "bridge1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetBridgeByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.GetBridgeByNameOrID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetBridgeNotBridgeTransport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns", "sub1").Return(&core.Subscription{Transport: "websockets"}, nil)
+
+	_, err := or.GetBridgeByNameOrID(or.ctx, "sub1")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteBridge(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	sub := newTestBridgeSubscription()
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns", "bridge1").Return(sub, nil)
+	or.mem.On("DeleteDurableSubscription", mock.Anything, sub).Return(nil)
+
+	err := or.DeleteBridge(or.ctx, "bridge1")
+	assert.NoError(t, err)
+}
+
+func TestDeleteBridgeNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByName", mock.Anything, "ns", "bridge1").Return(nil, nil)
+
+	err := or.DeleteBridge(or.ctx, "bridge1")
+	assert.Regexp(t, "FF10109", err)
+}
@@ -56,3 +56,7 @@ func (or *orchestrator) GetChartHistogram(ctx context.Context, startTime int64,
 
 	return histogram, nil
 }
+
+func (or *orchestrator) GetChartTimeSeries(ctx context.Context, collection database.CollectionName, query *core.ChartTimeSeriesQuery) ([]*core.ChartTimeSeriesBucket, error) {
+	return or.charts.GetTimeSeries(ctx, collection, query)
+}
@@ -73,3 +73,11 @@ func TestGetHistogramSuccess(t *testing.T) {
 	_, err := or.GetChartHistogram(context.Background(), 1000000000, 1000000010, 10, database.CollectionName("test"))
 	assert.NoError(t, err)
 }
+
+func TestGetChartTimeSeries(t *testing.T) {
+	or := newTestOrchestrator()
+	query := &core.ChartTimeSeriesQuery{GroupBy: "type"}
+	or.mch.On("GetTimeSeries", mock.Anything, database.CollectionName("messages"), query).Return([]*core.ChartTimeSeriesBucket{}, nil)
+	_, err := or.GetChartTimeSeries(context.Background(), database.CollectionName("messages"), query)
+	assert.NoError(t, err)
+}
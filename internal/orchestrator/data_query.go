@@ -28,6 +28,8 @@ import (
 	"github.com/hyperledger/firefly/pkg/database"
 )
 
+const messageExportPageSize = 100
+
 func (or *orchestrator) GetNamespace(ctx context.Context) *core.Namespace {
 	return or.namespace
 }
@@ -168,6 +170,40 @@ func (or *orchestrator) GetOperationByIDWithStatus(ctx context.Context, id strin
 	return enrichedOperation, err
 }
 
+func (or *orchestrator) GetOperationProgress(ctx context.Context, id string) (*core.OperationProgress, error) {
+	op, err := or.GetOperationByID(ctx, id)
+	if op == nil || err != nil {
+		return nil, err
+	}
+	if op.Type != core.OpTypeDataExchangeSendBlob {
+		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotBlobTransfer, op.ID, op.Type)
+	}
+
+	progress := &core.OperationProgress{
+		ID:     op.ID,
+		Status: op.Status,
+	}
+	if op.Progress != nil {
+		progress.Transferred = op.Progress.Transferred
+		progress.Size = op.Progress.Size
+		progress.Updated = op.Progress.Updated
+		// The rate is averaged over the life of the operation, which includes any time spent waiting to be sent
+		if op.Created != nil && op.Progress.Updated != nil {
+			elapsed := op.Progress.Updated.Time().Sub(*op.Created.Time()).Seconds()
+			if elapsed > 0 {
+				progress.BytesPerSecond = int64(float64(progress.Transferred) / elapsed)
+			}
+		}
+	}
+	if op.Status == core.OpStatusSucceeded {
+		progress.Transferred = progress.Size
+		progress.Percent = 100
+	} else if progress.Size > 0 {
+		progress.Percent = float64(progress.Transferred) * 100 / float64(progress.Size)
+	}
+	return progress, nil
+}
+
 func (or *orchestrator) GetEventByID(ctx context.Context, id string) (*core.Event, error) {
 	u, err := fftypes.ParseUUID(ctx, id)
 	if err != nil {
@@ -201,13 +237,62 @@ func (or *orchestrator) GetMessagesWithData(ctx context.Context, filter ffapi.An
 	if err != nil {
 		return nil, nil, err
 	}
-	msgsData := make([]*core.MessageInOut, len(msgs))
+	msgsData, err := or.fetchMessagesData(ctx, msgs)
+	return msgsData, fr, err
+}
+
+func (or *orchestrator) SearchMessages(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) ([]*core.Message, *ffapi.FilterResult, error) {
+	return or.database().SearchMessages(ctx, or.namespace.Name, filter, query)
+}
+
+func (or *orchestrator) SearchMessagesWithData(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) ([]*core.MessageInOut, *ffapi.FilterResult, error) {
+	msgs, fr, err := or.database().SearchMessages(ctx, or.namespace.Name, filter, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	msgsData, err := or.fetchMessagesData(ctx, msgs)
+	return msgsData, fr, err
+}
+
+func (or *orchestrator) fetchMessagesData(ctx context.Context, msgs []*core.Message) (msgsData []*core.MessageInOut, err error) {
+	msgsData = make([]*core.MessageInOut, len(msgs))
 	for i, msg := range msgs {
 		if msgsData[i], err = or.fetchMessageData(ctx, msg); err != nil {
-			return nil, nil, err
+			return nil, err
+		}
+	}
+	return msgsData, nil
+}
+
+// ExportMessages passes every message matching the filter to the callback, along with its data if requested.
+// Messages are read from the database a page at a time in order of local sequence, resuming each page after
+// the last sequence seen, so no message is passed twice even if new messages are written during the export.
+// Any sort, skip or limit on the filter is ignored.
+func (or *orchestrator) ExportMessages(ctx context.Context, filter ffapi.AndFilter, fetchData bool, cb func(msg *core.Message, data core.DataArray) error) error {
+	lastSequence := int64(-1)
+	for {
+		fb := database.MessageQueryFactory.NewFilterLimit(ctx, messageExportPageSize)
+		page := fb.And(filter, fb.Gt("sequence", lastSequence)).Sort("sequence")
+		msgs, _, err := or.database().GetMessages(ctx, or.namespace.Name, page)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			var data core.DataArray
+			if fetchData {
+				if data, _, err = or.data.GetMessageDataCached(ctx, msg); err != nil {
+					return err
+				}
+			}
+			if err := cb(msg, data); err != nil {
+				return err
+			}
+			lastSequence = msg.Sequence
+		}
+		if len(msgs) < messageExportPageSize {
+			return nil
 		}
 	}
-	return msgsData, fr, err
 }
 
 func (or *orchestrator) GetMessageData(ctx context.Context, id string) (core.DataArray, error) {
@@ -255,6 +340,79 @@ func (or *orchestrator) GetMessageEvents(ctx context.Context, id string, filter
 	return or.database().GetEvents(ctx, or.namespace.Name, filter)
 }
 
+func (or *orchestrator) GetMessageAcks(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil || msg == nil {
+		return nil, nil, err
+	}
+	return or.database().GetMessageAcks(ctx, or.namespace.Name, filter.Condition(filter.Builder().Eq("message", msg.Header.ID)))
+}
+
+func (or *orchestrator) GetMessageProof(ctx context.Context, id string) (*core.MessageProof, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.BatchID == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotBatched, msg.Header.ID)
+	}
+	batch, err := or.database().GetBatchByID(ctx, or.namespace.Name, msg.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotBatched, msg.Header.ID)
+	}
+	// Batches written by v0.13 and older hashed the whole payload, rather than the manifest,
+	// so cannot be proven from the manifest
+	if batch.Manifest == nil || !fftypes.HashString(batch.Manifest.String()).Equals(batch.Hash) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageProofUnavailable, msg.Header.ID, batch.ID)
+	}
+	var manifest core.BatchManifest
+	if err := batch.Manifest.Unmarshal(ctx, &manifest); err != nil {
+		return nil, err
+	}
+	for i, entry := range manifest.Messages {
+		if entry.ID.Equals(msg.Header.ID) && entry.Hash.Equals(msg.Hash) {
+			pin, err := or.getBatchPinEvent(ctx, batch)
+			if err != nil {
+				return nil, err
+			}
+			return &core.MessageProof{
+				Message:   &entry.MessageRef,
+				Header:    &msg.Header,
+				Data:      msg.Data,
+				Batch:     batch.ID,
+				BatchHash: batch.Hash,
+				Index:     i,
+				Manifest:  batch.Manifest,
+				TX:        batch.TX,
+				Pin:       pin,
+			}, nil
+		}
+	}
+	return nil, i18n.NewError(ctx, coremsgs.MsgMessageProofUnavailable, msg.Header.ID, batch.ID)
+}
+
+// getBatchPinEvent finds the blockchain event that pinned the batch, which is the only event on
+// the transaction of the batch that was not delivered to a contract listener
+func (or *orchestrator) getBatchPinEvent(ctx context.Context, batch *core.BatchPersisted) (*core.BlockchainEvent, error) {
+	if batch.TX.ID == nil {
+		return nil, nil
+	}
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	events, _, err := or.database().GetBlockchainEvents(ctx, or.namespace.Name, fb.And(fb.Eq("tx.id", batch.TX.ID)))
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if event.Listener == nil {
+			return event, nil
+		}
+	}
+	return nil, nil
+}
+
 func (or *orchestrator) GetBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.BatchPersisted, *ffapi.FilterResult, error) {
 	return or.database().GetBatches(ctx, or.namespace.Name, filter)
 }
@@ -263,6 +421,10 @@ func (or *orchestrator) GetData(ctx context.Context, filter ffapi.AndFilter) (co
 	return or.database().GetData(ctx, or.namespace.Name, filter)
 }
 
+func (or *orchestrator) SearchData(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) (core.DataArray, *ffapi.FilterResult, error) {
+	return or.database().SearchData(ctx, or.namespace.Name, filter, query)
+}
+
 func (or *orchestrator) GetDataSubPaths(ctx context.Context, path string) ([]string, error) {
 	return or.database().GetDataSubPaths(ctx, or.namespace.Name, path)
 }
@@ -295,6 +457,20 @@ func (or *orchestrator) GetBlockchainEventByID(ctx context.Context, id string) (
 	return or.txHelper.GetBlockchainEventByIDCached(ctx, u)
 }
 
+func (or *orchestrator) GetBlockchainEventOutput(ctx context.Context, id string) (fftypes.JSONObject, error) {
+	event, err := or.GetBlockchainEventByID(ctx, id)
+	if err != nil || event == nil {
+		return nil, err
+	}
+	if !event.OutputTruncated {
+		if event.Output == nil {
+			return fftypes.JSONObject{}, nil
+		}
+		return event.Output, nil
+	}
+	return or.database().GetBlockchainEventOutput(ctx, or.namespace.Name, event.ID)
+}
+
 func (or *orchestrator) GetBlockchainEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
 	return or.database().GetBlockchainEvents(ctx, or.namespace.Name, filter)
 }
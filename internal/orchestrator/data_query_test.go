@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -229,6 +230,129 @@ func TestGetMessagesWithDataFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestSearchMessages(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	query := &database.DataValueQuery{Search: "acme"}
+	or.mdi.On("SearchMessages", mock.Anything, "ns", mock.Anything, query).Return([]*core.Message{}, nil, nil)
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	_, _, err := or.SearchMessages(context.Background(), fb.And(), query)
+	assert.NoError(t, err)
+}
+
+func TestSearchMessagesWithDataOk(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	query := &database.DataValueQuery{Search: "acme"}
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"acme"`)}}
+	or.mdi.On("SearchMessages", mock.Anything, "ns", mock.Anything, query).Return([]*core.Message{msg}, nil, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(data, true, nil)
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	msgs, _, err := or.SearchMessagesWithData(context.Background(), fb.And(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, `"acme"`, msgs[0].InlineData[0].Value.String())
+}
+
+func TestSearchMessagesWithDataFailMsg(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	query := &database.DataValueQuery{Search: "acme"}
+	or.mdi.On("SearchMessages", mock.Anything, "ns", mock.Anything, query).Return(nil, nil, fmt.Errorf("pop"))
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	_, _, err := or.SearchMessagesWithData(context.Background(), fb.And(), query)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportMessagesPaged(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	page1 := make([]*core.Message, messageExportPageSize)
+	for i := range page1 {
+		page1[i] = &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Sequence: int64(i + 1)}
+	}
+	page2 := []*core.Message{{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Sequence: 1000}}
+	pageAfter := func(sequence string) interface{} {
+		return mock.MatchedBy(func(f ffapi.Filter) bool {
+			fi, _ := f.Finalize()
+			return fi.String() == "( ( tag == 'tag1' ) ) && ( sequence >> "+sequence+" ) sort=sequence limit=100"
+		})
+	}
+	or.mdi.On("GetMessages", mock.Anything, "ns", pageAfter("-1")).Return(page1, nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, "ns", pageAfter("100")).Return(page2, nil, nil).Once()
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	or.mdm.On("GetMessageDataCached", mock.Anything, mock.Anything).Return(data, true, nil)
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	filter := fb.And(fb.Eq("tag", "tag1"))
+	filter.Sort("-created")
+	var exported []*core.Message
+	err := or.ExportMessages(context.Background(), filter, true, func(msg *core.Message, msgData core.DataArray) error {
+		assert.Equal(t, data, msgData)
+		exported = append(exported, msg)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, append(page1, page2...), exported)
+}
+
+func TestExportMessagesNoData(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	err := or.ExportMessages(context.Background(), fb.And(), false, func(msg *core.Message, data core.DataArray) error {
+		assert.Nil(t, data)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestExportMessagesFailRead(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	err := or.ExportMessages(context.Background(), fb.And(), false, func(msg *core.Message, data core.DataArray) error {
+		return nil
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportMessagesFailData(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(nil, false, fmt.Errorf("pop"))
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	err := or.ExportMessages(context.Background(), fb.And(), true, func(msg *core.Message, data core.DataArray) error {
+		return nil
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportMessagesFailCallback(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	err := or.ExportMessages(context.Background(), fb.And(), false, func(msg *core.Message, data core.DataArray) error {
+		return fmt.Errorf("pop")
+	})
+	assert.EqualError(t, err, "pop")
+}
+
 func TestGetMessagesForData(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -312,6 +436,182 @@ func TestGetMessageTransactionMessageNotFound(t *testing.T) {
 	assert.Regexp(t, "FF10109", err)
 }
 
+func newTestProofBatch(msg *core.Message) *core.BatchPersisted {
+	batch := &core.Batch{
+		BatchHeader: core.BatchHeader{ID: msg.BatchID},
+		Payload: core.BatchPayload{
+			TX: core.TransactionRef{Type: core.TransactionTypeBatchPin, ID: fftypes.NewUUID()},
+			Messages: []*core.Message{
+				{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Hash: fftypes.NewRandB32()},
+				msg,
+			},
+		},
+	}
+	persisted, _ := batch.Confirmed()
+	persisted.Hash = fftypes.HashString(persisted.Manifest.String())
+	return persisted
+}
+
+func TestGetMessageProofOk(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{
+		Header:  core.MessageHeader{ID: fftypes.NewUUID()},
+		Hash:    fftypes.NewRandB32(),
+		BatchID: fftypes.NewUUID(),
+	}
+	batch := newTestProofBatch(msg)
+	pinEvent := &core.BlockchainEvent{
+		ID: fftypes.NewUUID(),
+		TX: core.BlockchainTransactionRef{ID: batch.TX.ID, BlockchainID: "0x12345"},
+	}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return([]*core.BlockchainEvent{
+		{ID: fftypes.NewUUID(), Listener: fftypes.NewUUID()},
+		pinEvent,
+	}, nil, nil)
+	proof, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, proof.Index)
+	assert.Equal(t, msg.Header.ID, proof.Message.ID)
+	assert.Equal(t, msg.Hash, proof.Message.Hash)
+	assert.Equal(t, &msg.Header, proof.Header)
+	assert.Equal(t, msg.Data, proof.Data)
+	assert.Equal(t, batch.Hash, proof.BatchHash)
+	assert.Equal(t, batch.TX, proof.TX)
+	assert.Equal(t, pinEvent, proof.Pin)
+	assert.True(t, fftypes.HashString(proof.Manifest.String()).Equals(proof.BatchHash))
+}
+
+func TestGetMessageProofVerifiable(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{
+		Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic1"}},
+		Data:   core.DataRefs{{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()}},
+	}
+	err := msg.Seal(context.Background())
+	assert.NoError(t, err)
+	msg.BatchID = fftypes.NewUUID()
+	batch := newTestProofBatch(msg)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+	proof, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Nil(t, proof.Pin)
+
+	// Check the proof can be verified from the bundle alone
+	assert.Equal(t, proof.Header.DataHash, proof.Data.Hash())
+	assert.Equal(t, proof.Message.Hash, proof.Header.Hash())
+	var manifest core.BatchManifest
+	err = proof.Manifest.Unmarshal(context.Background(), &manifest)
+	assert.NoError(t, err)
+	assert.Equal(t, proof.Message.Hash, manifest.Messages[proof.Index].Hash)
+	assert.Equal(t, proof.BatchHash, fftypes.HashString(proof.Manifest.String()))
+}
+
+func TestGetMessageProofNoTX(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Hash: fftypes.NewRandB32(), BatchID: fftypes.NewUUID()}
+	batch := newTestProofBatch(msg)
+	batch.TX.ID = nil
+	batch.Manifest = fftypes.JSONAnyPtr(batch.GenManifest([]*core.Message{msg}, nil).String())
+	batch.Hash = fftypes.HashString(batch.Manifest.String())
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	proof, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Nil(t, proof.Pin)
+}
+
+func TestGetMessageProofPinEventFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Hash: fftypes.NewRandB32(), BatchID: fftypes.NewUUID()}
+	batch := newTestProofBatch(msg)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetMessageProofMessageNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(nil, nil)
+	_, err := or.GetMessageProof(context.Background(), msgID.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetMessageProofNotBatched(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10497", err)
+}
+
+func TestGetMessageProofBatchNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(nil, nil)
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10497", err)
+}
+
+func TestGetMessageProofBatchFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetMessageProofPayloadHash(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Hash: fftypes.NewRandB32(), BatchID: fftypes.NewUUID()}
+	batch := newTestProofBatch(msg)
+	batch.Hash = fftypes.NewRandB32()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10498", err)
+}
+
+func TestGetMessageProofBadManifest(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, BatchID: fftypes.NewUUID()}
+	batch := &core.BatchPersisted{Manifest: fftypes.JSONAnyPtr("!json")}
+	batch.Hash = fftypes.HashString(batch.Manifest.String())
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "invalid character", err)
+}
+
+func TestGetMessageProofNotInManifest(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Hash: fftypes.NewRandB32(), BatchID: fftypes.NewUUID()}
+	batch := newTestProofBatch(&core.Message{Header: core.MessageHeader{ID: msg.Header.ID}, Hash: fftypes.NewRandB32()})
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetBatchByID", mock.Anything, "ns", msg.BatchID).Return(batch, nil)
+	_, err := or.GetMessageProof(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10498", err)
+}
+
 func TestGetMessageData(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -378,6 +678,39 @@ func TestGetMessageEventsBadMsgID(t *testing.T) {
 	assert.Nil(t, ev)
 }
 
+func TestGetMessageAcksOk(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			Namespace: "ns",
+			ID:        fftypes.NewUUID(),
+		},
+	}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", mock.Anything).Return(msg, nil)
+	or.mdi.On("GetMessageAcks", mock.Anything, "ns", mock.Anything).Return([]*core.MessageAck{}, nil, nil)
+	fb := database.MessageAckQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Eq("type", core.MessageAckTypeDelivered))
+	_, _, err := or.GetMessageAcks(context.Background(), fftypes.NewUUID().String(), f)
+	assert.NoError(t, err)
+	calculatedFilter, err := or.mdi.Calls[1].Arguments[2].(ffapi.Filter).Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(
+		`( type == 'delivered' ) && ( message == '%s' )`, msg.Header.ID,
+	), calculatedFilter.String())
+}
+
+func TestGetMessageAcksBadMsgID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	fb := database.MessageAckQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Eq("type", core.MessageAckTypeDelivered))
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", mock.Anything).Return(nil, nil)
+	acks, _, err := or.GetMessageAcks(context.Background(), fftypes.NewUUID().String(), f)
+	assert.Regexp(t, "FF10109", err)
+	assert.Nil(t, acks)
+}
+
 func TestGetBatchByID(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -438,6 +771,16 @@ func TestGetData(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSearchData(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	query := &database.DataValueQuery{Search: "acme"}
+	or.mdi.On("SearchData", mock.Anything, "ns", mock.Anything, query).Return(core.DataArray{}, nil, nil)
+	fb := database.DataQueryFactory.NewFilter(context.Background())
+	_, _, err := or.SearchData(context.Background(), fb.And(), query)
+	assert.NoError(t, err)
+}
+
 func TestGetDataSubPaths(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -506,6 +849,93 @@ func TestGetOperationIDBadID(t *testing.T) {
 	assert.Regexp(t, "FF00138", err)
 }
 
+func TestGetOperationProgress(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	u := fftypes.NewUUID()
+	created := fftypes.FFTime(time.Unix(1000, 0))
+	updated := fftypes.FFTime(time.Unix(1004, 0))
+	or.mom.On("GetOperationByIDCached", mock.Anything, u).Return(&core.Operation{
+		ID:      u,
+		Type:    core.OpTypeDataExchangeSendBlob,
+		Status:  core.OpStatusPending,
+		Created: &created,
+		Progress: &core.TransferProgress{
+			Transferred: 1024,
+			Size:        4096,
+			Updated:     &updated,
+		},
+	}, nil)
+	progress, err := or.GetOperationProgress(context.Background(), u.String())
+	assert.NoError(t, err)
+	assert.Equal(t, &core.OperationProgress{
+		ID:             u,
+		Status:         core.OpStatusPending,
+		Transferred:    1024,
+		Size:           4096,
+		Percent:        25,
+		BytesPerSecond: 256,
+		Updated:        &updated,
+	}, progress)
+}
+
+func TestGetOperationProgressSucceeded(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	u := fftypes.NewUUID()
+	or.mom.On("GetOperationByIDCached", mock.Anything, u).Return(&core.Operation{
+		ID:     u,
+		Type:   core.OpTypeDataExchangeSendBlob,
+		Status: core.OpStatusSucceeded,
+		Progress: &core.TransferProgress{
+			Transferred: 1024,
+			Size:        4096,
+		},
+	}, nil)
+	progress, err := or.GetOperationProgress(context.Background(), u.String())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4096), progress.Transferred)
+	assert.Equal(t, float64(100), progress.Percent)
+	assert.Zero(t, progress.BytesPerSecond)
+}
+
+func TestGetOperationProgressNotStarted(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	u := fftypes.NewUUID()
+	or.mom.On("GetOperationByIDCached", mock.Anything, u).Return(&core.Operation{
+		ID:     u,
+		Type:   core.OpTypeDataExchangeSendBlob,
+		Status: core.OpStatusInitialized,
+	}, nil)
+	progress, err := or.GetOperationProgress(context.Background(), u.String())
+	assert.NoError(t, err)
+	assert.Zero(t, progress.Transferred)
+	assert.Zero(t, progress.Percent)
+}
+
+func TestGetOperationProgressNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	u := fftypes.NewUUID()
+	or.mom.On("GetOperationByIDCached", mock.Anything, u).Return(nil, nil)
+	progress, err := or.GetOperationProgress(context.Background(), u.String())
+	assert.NoError(t, err)
+	assert.Nil(t, progress)
+}
+
+func TestGetOperationProgressNotBlobTransfer(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	u := fftypes.NewUUID()
+	or.mom.On("GetOperationByIDCached", mock.Anything, u).Return(&core.Operation{
+		ID:   u,
+		Type: core.OpTypeBlockchainInvoke,
+	}, nil)
+	_, err := or.GetOperationProgress(context.Background(), u.String())
+	assert.Regexp(t, "FF10641", err)
+}
+
 type txnStatus struct {
 	TxnId string
 }
@@ -801,6 +1231,62 @@ func TestGetBlockchainEventByIDBadID(t *testing.T) {
 	assert.Regexp(t, "FF00138", err)
 }
 
+func TestGetBlockchainEventOutput(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mth.On("GetBlockchainEventByIDCached", context.Background(), id).Return(&core.BlockchainEvent{
+		ID:     id,
+		Output: fftypes.JSONObject{"value": 1},
+	}, nil)
+
+	output, err := or.GetBlockchainEventOutput(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{"value": 1}, output)
+}
+
+func TestGetBlockchainEventOutputEmpty(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mth.On("GetBlockchainEventByIDCached", context.Background(), id).Return(&core.BlockchainEvent{ID: id}, nil)
+
+	output, err := or.GetBlockchainEventOutput(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{}, output)
+}
+
+func TestGetBlockchainEventOutputTruncated(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mth.On("GetBlockchainEventByIDCached", context.Background(), id).Return(&core.BlockchainEvent{
+		ID:              id,
+		Output:          fftypes.JSONObject{"value": 1},
+		OutputTruncated: true,
+	}, nil)
+	or.mdi.On("GetBlockchainEventOutput", context.Background(), "ns", id).Return(fftypes.JSONObject{"value": 1, "data": "0xfeedbeef"}, nil)
+
+	output, err := or.GetBlockchainEventOutput(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "0xfeedbeef", output.GetString("data"))
+}
+
+func TestGetBlockchainEventOutputNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mth.On("GetBlockchainEventByIDCached", context.Background(), id).Return(nil, nil)
+
+	output, err := or.GetBlockchainEventOutput(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Nil(t, output)
+}
+
 func TestGetBlockchainEvents(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
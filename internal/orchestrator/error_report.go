@@ -0,0 +1,136 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// errorReportMaxRecords bounds the number of records read from each subsystem when building an error report
+const errorReportMaxRecords = 1000
+
+const (
+	// errorReportMaxListeners bounds the number of contract listeners checked with the blockchain connector
+	errorReportMaxListeners = 200
+	// errorReportListenerConcurrency bounds how many listener status calls are made to the connector at once
+	errorReportListenerConcurrency = 10
+)
+
+// errorReportListenerTimeout bounds the total time spent checking the status of listeners
+var errorReportListenerTimeout = 10 * time.Second
+
+func (or *orchestrator) GetErrorReport(ctx context.Context, window time.Duration) (*core.ErrorReport, error) {
+	since := fftypes.FFTime(time.Now().Add(-window))
+	report := &core.ErrorReport{
+		Since:      &since,
+		Categories: []*core.ErrorReportCategoryStatus{},
+	}
+
+	ops, err := or.getOperationErrors(ctx, &since)
+	if err != nil {
+		return nil, err
+	}
+	report.Categories = append(report.Categories, ops, or.events.DeliveryErrors(&since))
+
+	if or.blockchain() != nil {
+		indexing, err := or.getBlockchainIndexingErrors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		report.Categories = append(report.Categories, indexing)
+	}
+
+	for _, c := range report.Categories {
+		report.Total += c.Count
+	}
+	return report, nil
+}
+
+func (or *orchestrator) getOperationErrors(ctx context.Context, since *fftypes.FFTime) (*core.ErrorReportCategoryStatus, error) {
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("status", core.OpStatusFailed),
+		fb.Gte("updated", since),
+	).Sort("updated").Descending().Limit(errorReportMaxRecords)
+	ops, _, err := or.database().GetOperations(ctx, or.namespace.Name, filter)
+	if err != nil {
+		return nil, err
+	}
+	cs := core.NewErrorReportCategoryStatus(core.ErrorReportCategoryOperations)
+	for _, op := range ops {
+		cs.AddError(string(op.Type), op.Updated, op.Error)
+	}
+	cs.Truncated = len(ops) == errorReportMaxRecords
+	cs.SortGroups()
+	return cs, nil
+}
+
+// getBlockchainIndexingErrors checks the current state of each contract listener with the connector, so is not
+// bounded by the report window. The checks run concurrently, and any not answered within the timeout are reported
+// as errors.
+func (or *orchestrator) getBlockchainIndexingErrors(ctx context.Context) (*core.ErrorReportCategoryStatus, error) {
+	fb := database.ContractListenerQueryFactory.NewFilter(ctx)
+	listeners, _, err := or.database().GetContractListeners(ctx, or.namespace.Name, fb.And().Limit(errorReportMaxListeners))
+	if err != nil {
+		return nil, err
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, errorReportListenerTimeout)
+	defer cancel()
+	errs := make([]error, len(listeners))
+	slots := make(chan struct{}, errorReportListenerConcurrency)
+	var wg sync.WaitGroup
+	for i, l := range listeners {
+		wg.Add(1)
+		go func(i int, l *core.ContractListener) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-checkCtx.Done():
+			}
+			if checkCtx.Err() != nil {
+				errs[i] = i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+				return
+			}
+			_, _, _, errs[i] = or.blockchain().GetContractListenerStatus(checkCtx, or.namespace.Name, l.BackendID, false)
+		}(i, l)
+	}
+	wg.Wait()
+
+	cs := core.NewErrorReportCategoryStatus(core.ErrorReportCategoryBlockchainIndexing)
+	for i, l := range listeners {
+		if errs[i] != nil {
+			name := l.Name
+			if name == "" {
+				name = l.ID.String()
+			}
+			cs.AddError(name, fftypes.Now(), errs[i].Error())
+		}
+	}
+	cs.Truncated = len(listeners) == errorReportMaxListeners
+	cs.SortGroups()
+	return cs, nil
+}
@@ -0,0 +1,133 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetErrorReport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{
+		{Type: core.OpTypeBlockchainInvoke, Updated: fftypes.Now(), Error: "pop"},
+		{Type: core.OpTypeBlockchainInvoke, Updated: fftypes.Now(), Error: "pop"},
+		{Type: core.OpTypeDataExchangeSendBlob, Updated: fftypes.Now(), Error: "bang"},
+	}, nil, nil)
+	or.mem.On("DeliveryErrors", mock.Anything).Return(&core.ErrorReportCategoryStatus{
+		Category: core.ErrorReportCategorySubscriptionDeliveries,
+		Count:    2,
+		Groups:   []*core.ErrorReportGroup{{Name: "sub1", Count: 2, Messages: []string{"fizz"}}},
+	})
+	unnamed := fftypes.NewUUID()
+	or.mdi.On("GetContractListeners", mock.Anything, "ns", mock.Anything).Return([]*core.ContractListener{
+		{ID: fftypes.NewUUID(), Name: "listener1", BackendID: "sub1"},
+		{ID: unnamed, BackendID: "sub2"},
+	}, nil, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "sub1", false).Return(true, nil, core.ContractListenerStatusSynced, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "sub2", false).Return(false, nil, core.ContractListenerStatusUnknown, fmt.Errorf("unreachable"))
+
+	report, err := or.GetErrorReport(or.ctx, 1*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), report.Total)
+	assert.Len(t, report.Categories, 3)
+
+	ops := report.Categories[0]
+	assert.Equal(t, core.ErrorReportCategoryOperations, ops.Category)
+	assert.Equal(t, int64(3), ops.Count)
+	assert.Equal(t, core.OpTypeBlockchainInvoke.String(), ops.Groups[0].Name)
+	assert.Equal(t, []string{"pop"}, ops.Groups[0].Messages)
+
+	assert.Equal(t, core.ErrorReportCategorySubscriptionDeliveries, report.Categories[1].Category)
+
+	indexing := report.Categories[2]
+	assert.Equal(t, core.ErrorReportCategoryBlockchainIndexing, indexing.Category)
+	assert.Equal(t, int64(1), indexing.Count)
+	assert.Equal(t, unnamed.String(), indexing.Groups[0].Name)
+	assert.Equal(t, []string{"unreachable"}, indexing.Groups[0].Messages)
+}
+
+func TestGetErrorReportNoBlockchain(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.plugins.Blockchain.Plugin = nil
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mem.On("DeliveryErrors", mock.Anything).Return(core.NewErrorReportCategoryStatus(core.ErrorReportCategorySubscriptionDeliveries))
+
+	report, err := or.GetErrorReport(or.ctx, 1*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), report.Total)
+	assert.Len(t, report.Categories, 2)
+}
+
+func TestGetErrorReportOperationsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetErrorReport(or.ctx, 1*time.Hour)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetErrorReportListenersFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mem.On("DeliveryErrors", mock.Anything).Return(core.NewErrorReportCategoryStatus(core.ErrorReportCategorySubscriptionDeliveries))
+	or.mdi.On("GetContractListeners", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetErrorReport(or.ctx, 1*time.Hour)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetErrorReportListenersTimeout(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	defer func(timeout time.Duration) { errorReportListenerTimeout = timeout }(errorReportListenerTimeout)
+	errorReportListenerTimeout = 10 * time.Millisecond
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mem.On("DeliveryErrors", mock.Anything).Return(core.NewErrorReportCategoryStatus(core.ErrorReportCategorySubscriptionDeliveries))
+	listeners := make([]*core.ContractListener, errorReportListenerConcurrency+1)
+	for i := range listeners {
+		listeners[i] = &core.ContractListener{ID: fftypes.NewUUID(), Name: fmt.Sprintf("listener%d", i), BackendID: "sub"}
+	}
+	or.mdi.On("GetContractListeners", mock.Anything, "ns", mock.Anything).Return(listeners, nil, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "sub", false).Run(func(args mock.Arguments) {
+		// Every call is stuck until the timeout, so the last listener never gets a slot
+		<-args[0].(context.Context).Done()
+	}).Return(false, nil, core.ContractListenerStatusUnknown, fmt.Errorf("timeout"))
+
+	report, err := or.GetErrorReport(or.ctx, 1*time.Hour)
+	assert.NoError(t, err)
+	indexing := report.Categories[2]
+	assert.Equal(t, int64(len(listeners)), indexing.Count)
+	assert.False(t, indexing.Truncated)
+	or.mbi.AssertNumberOfCalls(t, "GetContractListenerStatus", errorReportListenerConcurrency)
+}
@@ -0,0 +1,279 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"database/sql/driver"
+	"math"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/graphql"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (or *orchestrator) QueryGraphQL(ctx context.Context, req *core.GraphQLRequest) (*core.GraphQLResponse, error) {
+	return graphql.Execute(ctx, or.graphQLSchema(), req)
+}
+
+// graphQLSchema builds the types that can be queried. Each nested field performs a lookup of the
+// related resources, so a client can fetch them in a single round trip rather than one request per resource.
+func (or *orchestrator) graphQLSchema() *graphql.Schema {
+	operation := &graphql.Object{Name: "Operation"}
+	event := &graphql.Object{Name: "Event"}
+	blockchainEvent := &graphql.Object{Name: "BlockchainEvent"}
+	data := &graphql.Object{Name: "Data"}
+	tokenPool := &graphql.Object{Name: "TokenPool"}
+
+	transaction := &graphql.Object{Name: "Transaction", Fields: map[string]*graphql.Field{
+		"operations": {Type: operation, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			fb := database.OperationQueryFactory.NewFilter(ctx)
+			filter, err := graphQLFilter(ctx, fb, args, fb.Eq("tx", source.(*core.Transaction).ID))
+			if err != nil {
+				return nil, err
+			}
+			ops, _, err := or.database().GetOperations(ctx, or.namespace.Name, filter)
+			return ops, err
+		}},
+		"events": {Type: event, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			fb := database.EventQueryFactory.NewFilter(ctx)
+			filter, err := graphQLFilter(ctx, fb, args, fb.Eq("tx", source.(*core.Transaction).ID))
+			if err != nil {
+				return nil, err
+			}
+			events, _, err := or.database().GetEvents(ctx, or.namespace.Name, filter)
+			return events, err
+		}},
+		"blockchainEvents": {Type: blockchainEvent, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+			filter, err := graphQLFilter(ctx, fb, args, fb.Eq("tx.id", source.(*core.Transaction).ID))
+			if err != nil {
+				return nil, err
+			}
+			events, _, err := or.database().GetBlockchainEvents(ctx, or.namespace.Name, filter)
+			return events, err
+		}},
+	}}
+
+	message := &graphql.Object{Name: "Message", Fields: map[string]*graphql.Field{
+		"data": {Type: data, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			data, _, err := or.data.GetMessageDataCached(ctx, source.(*core.Message))
+			return data, err
+		}},
+		"transaction": {Type: transaction, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return or.graphQLTransaction(ctx, source.(*core.Message).TransactionID)
+		}},
+		"events": {Type: event, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			// Events can refer to the message, or any data in the message - the list of references is capped
+			// like the number of results, so the size of the query does not grow with the number of data items
+			msg := source.(*core.Message)
+			maxRefs := config.GetInt(coreconfig.APIMaxFilterLimit)
+			referencedIDs := make([]driver.Value, 0, len(msg.Data)+1)
+			referencedIDs = append(referencedIDs, msg.Header.ID)
+			for _, dataRef := range msg.Data {
+				if len(referencedIDs) >= maxRefs {
+					break
+				}
+				referencedIDs = append(referencedIDs, dataRef.ID)
+			}
+			fb := database.EventQueryFactory.NewFilter(ctx)
+			filter, err := graphQLFilter(ctx, fb, args, fb.In("reference", referencedIDs))
+			if err != nil {
+				return nil, err
+			}
+			events, _, err := or.database().GetEvents(ctx, or.namespace.Name, filter)
+			return events, err
+		}},
+	}}
+
+	tokenTransfer := &graphql.Object{Name: "TokenTransfer", Fields: map[string]*graphql.Field{
+		"pool": {Type: tokenPool, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return or.database().GetTokenPoolByID(ctx, or.namespace.Name, source.(*core.TokenTransfer).Pool)
+		}},
+		"message": {Type: message, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			if msgID := source.(*core.TokenTransfer).Message; msgID != nil {
+				return or.database().GetMessageByID(ctx, or.namespace.Name, msgID)
+			}
+			return nil, nil
+		}},
+		"transaction": {Type: transaction, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return or.graphQLTransaction(ctx, source.(*core.TokenTransfer).TX.ID)
+		}},
+	}}
+
+	return &graphql.Schema{
+		Query: &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+			"message": {Type: message, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphQLIDArgument(ctx, args)
+				if err != nil {
+					return nil, err
+				}
+				return or.database().GetMessageByID(ctx, or.namespace.Name, id)
+			}},
+			"messages": {Type: message, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				filter, err := graphQLFilter(ctx, database.MessageQueryFactory.NewFilter(ctx), args)
+				if err != nil {
+					return nil, err
+				}
+				msgs, _, err := or.database().GetMessages(ctx, or.namespace.Name, filter)
+				return msgs, err
+			}},
+			"transaction": {Type: transaction, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphQLIDArgument(ctx, args)
+				if err != nil {
+					return nil, err
+				}
+				return or.graphQLTransaction(ctx, id)
+			}},
+			"transactions": {Type: transaction, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				filter, err := graphQLFilter(ctx, database.TransactionQueryFactory.NewFilter(ctx), args)
+				if err != nil {
+					return nil, err
+				}
+				txns, _, err := or.database().GetTransactions(ctx, or.namespace.Name, filter)
+				return txns, err
+			}},
+			"tokenTransfer": {Type: tokenTransfer, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphQLIDArgument(ctx, args)
+				if err != nil {
+					return nil, err
+				}
+				return or.database().GetTokenTransferByID(ctx, or.namespace.Name, id)
+			}},
+			"tokenTransfers": {Type: tokenTransfer, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				filter, err := graphQLFilter(ctx, database.TokenTransferQueryFactory.NewFilter(ctx), args)
+				if err != nil {
+					return nil, err
+				}
+				transfers, _, err := or.database().GetTokenTransfers(ctx, or.namespace.Name, filter)
+				return transfers, err
+			}},
+		}},
+	}
+}
+
+func (or *orchestrator) graphQLTransaction(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error) {
+	if id == nil {
+		return nil, nil
+	}
+	return or.txHelper.GetTransactionByIDCached(ctx, id)
+}
+
+func graphQLIDArgument(ctx context.Context, args map[string]interface{}) (*fftypes.UUID, error) {
+	id, ok := args["id"]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLMissingArgument, "id")
+	}
+	idStr, ok := id.(string)
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLInvalidArgument, "id", id)
+	}
+	return fftypes.ParseUUID(ctx, idStr)
+}
+
+// graphQLFilter builds a filter from the arguments of a list field. The limit, skip and sort arguments
+// behave as they do on the REST API, and any other argument is an equality match (or a match on any
+// of a list of values) on the field with that name in the query factory. Nested list fields pass the
+// conditions that relate the results to their parent, which are applied ahead of the arguments.
+func graphQLFilter(ctx context.Context, fb ffapi.FilterBuilder, args map[string]interface{}, conditions ...ffapi.Filter) (ffapi.AndFilter, error) {
+	limit := uint64(config.GetUint(coreconfig.APIDefaultFilterLimit))
+	maxLimit := uint64(config.GetUint(coreconfig.APIMaxFilterLimit))
+	var skip uint64
+	var sortFields []string
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var err error
+		switch value := args[name]; name {
+		case "limit":
+			if limit, err = graphQLUintArgument(ctx, name, value); err == nil && limit > maxLimit {
+				err = i18n.NewError(ctx, i18n.MsgMaxFilterLimit, maxLimit)
+			}
+		case "skip":
+			skip, err = graphQLUintArgument(ctx, name, value)
+		case "sort":
+			sortFields, err = graphQLStringsArgument(ctx, name, value)
+		default:
+			if list, isList := value.([]interface{}); isList {
+				values := make([]driver.Value, len(list))
+				for i, v := range list {
+					values[i] = graphQLDriverValue(v)
+				}
+				conditions = append(conditions, fb.In(name, values))
+			} else {
+				conditions = append(conditions, fb.Eq(name, graphQLDriverValue(value)))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filter := fb.And(conditions...)
+	filter.Limit(limit).Skip(skip)
+	if len(sortFields) > 0 {
+		filter.Sort(sortFields...)
+	}
+	return filter, nil
+}
+
+// graphQLDriverValue converts whole numbers supplied as JSON variables back to integers
+func graphQLDriverValue(v interface{}) driver.Value {
+	if f, ok := v.(float64); ok && f == math.Trunc(f) && math.Abs(f) < math.MaxInt64 {
+		return int64(f)
+	}
+	return v
+}
+
+func graphQLUintArgument(ctx context.Context, name string, value interface{}) (uint64, error) {
+	switch v := graphQLDriverValue(value).(type) {
+	case int64:
+		if v >= 0 {
+			return uint64(v), nil
+		}
+	}
+	return 0, i18n.NewError(ctx, coremsgs.MsgGraphQLInvalidArgument, name, value)
+}
+
+func graphQLStringsArgument(ctx context.Context, name string, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		strs := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLInvalidArgument, name, value)
+			}
+			strs[i] = s
+		}
+		return strs, nil
+	}
+	return nil, i18n.NewError(ctx, coremsgs.MsgGraphQLInvalidArgument, name, value)
+}
@@ -0,0 +1,238 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func filterString(t *testing.T, f interface{}) string {
+	fi, err := f.(ffapi.Filter).Finalize()
+	assert.NoError(t, err)
+	return fi.String()
+}
+
+func queryGraphQL(t *testing.T, or *testOrchestrator, req *core.GraphQLRequest) fftypes.JSONObject {
+	res, err := or.QueryGraphQL(context.Background(), req)
+	assert.NoError(t, err)
+	b, _ := json.Marshal(res)
+	var result fftypes.JSONObject
+	_ = json.Unmarshal(b, &result)
+	return result
+}
+
+func TestQueryGraphQLMessagesWithNestedResources(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	txID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header:        core.MessageHeader{ID: fftypes.NewUUID(), Tag: "tag1"},
+		Data:          core.DataRefs{{ID: fftypes.NewUUID()}},
+		TransactionID: txID,
+	}
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{msg}, nil, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, msg).Return(core.DataArray{
+		{ID: msg.Data[0].ID, Value: fftypes.JSONAnyPtr(`{"some":"value"}`)},
+	}, true, nil)
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(&core.Transaction{ID: txID}, nil)
+	opID := fftypes.NewUUID()
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{{ID: opID}}, nil, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+	txEventID := fftypes.NewUUID()
+	msgEventID := fftypes.NewUUID()
+	or.mdi.On("GetEvents", mock.Anything, "ns", mock.Anything).Return([]*core.Event{{ID: txEventID}}, nil, nil).Once()
+	or.mdi.On("GetEvents", mock.Anything, "ns", mock.Anything).Return([]*core.Event{{ID: msgEventID}}, nil, nil).Once()
+
+	result := queryGraphQL(t, or, &core.GraphQLRequest{
+		Query: `query Msgs($limit: Int) {
+			messages(tag: "tag1", topics: ["t1", "t2"], limit: $limit, skip: 1, sort: ["-created", "sequence"]) {
+				header { id tag }
+				data { value }
+				transaction { id operations { id } events { id } blockchainEvents { id } }
+				events { id }
+			}
+		}`,
+		Variables: fftypes.JSONObject{"limit": float64(10)},
+	})
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"header": map[string]interface{}{"id": msg.Header.ID.String(), "tag": "tag1"},
+			"data":   []interface{}{map[string]interface{}{"value": map[string]interface{}{"some": "value"}}},
+			"transaction": map[string]interface{}{
+				"id":               txID.String(),
+				"operations":       []interface{}{map[string]interface{}{"id": opID.String()}},
+				"events":           []interface{}{map[string]interface{}{"id": txEventID.String()}},
+				"blockchainEvents": []interface{}{},
+			},
+			"events": []interface{}{map[string]interface{}{"id": msgEventID.String()}},
+		},
+	}, result.GetObject("data")["messages"])
+
+	assert.Equal(t, "( tag == 'tag1' ) && ( topics IN ['t1','t2'] ) sort=-created,sequence skip=1 limit=10", filterString(t, or.mdi.Calls[0].Arguments[2]))
+	assert.Equal(t, fmt.Sprintf("( tx == '%s' ) limit=25", txID), filterString(t, or.mdi.Calls[1].Arguments[2]))
+	assert.Equal(t, fmt.Sprintf("( tx == '%s' ) limit=25", txID), filterString(t, or.mdi.Calls[2].Arguments[2]))
+	assert.Equal(t, fmt.Sprintf("( tx.id == '%s' ) limit=25", txID), filterString(t, or.mdi.Calls[3].Arguments[2]))
+	assert.Equal(t, fmt.Sprintf("( reference IN ['%s','%s'] ) limit=25", msg.Header.ID, msg.Data[0].ID), filterString(t, or.mdi.Calls[4].Arguments[2]))
+}
+
+func TestQueryGraphQLNestedListArguments(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	txID := fftypes.NewUUID()
+	msg := &core.Message{
+		Header:        core.MessageHeader{ID: fftypes.NewUUID()},
+		TransactionID: txID,
+	}
+	for i := 0; i < 1001; i++ {
+		msg.Data = append(msg.Data, &core.DataRef{ID: fftypes.NewUUID()})
+	}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(&core.Transaction{ID: txID}, nil)
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mdi.On("GetEvents", mock.Anything, "ns", mock.Anything).Return([]*core.Event{}, nil, nil)
+
+	result := queryGraphQL(t, or, &core.GraphQLRequest{
+		Query: fmt.Sprintf(`{
+			message(id: "%s") {
+				transaction { operations(type: "blockchain_invoke", limit: 5, skip: 10) { id } blockchainEvents(limit: 1001) { id } }
+				events(type: "message_confirmed") { id }
+			}
+		}`, msg.Header.ID),
+	})
+	errors := result["errors"].([]interface{})
+	assert.Len(t, errors, 1)
+	assert.Regexp(t, "FF00192.*1,000", errors[0].(map[string]interface{})["message"])
+
+	assert.Equal(t, fmt.Sprintf("( tx == '%s' ) && ( type == 'blockchain_invoke' ) skip=10 limit=5", txID), filterString(t, or.mdi.Calls[1].Arguments[2]))
+	// The references are capped at the maximum filter limit
+	f, err := or.mdi.Calls[2].Arguments[2].(ffapi.Filter).Finalize()
+	assert.NoError(t, err)
+	assert.Len(t, f.Children[0].Values, 1000)
+	assert.Regexp(t, "^\\( reference IN .*\\) && \\( type == 'message_confirmed' \\) limit=25$", f.String())
+}
+
+func TestQueryGraphQLResourcesByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	msgID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	poolID := fftypes.NewUUID()
+	transferID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{Header: core.MessageHeader{ID: msgID}}, nil)
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(&core.Transaction{ID: txID}, nil)
+	or.mdi.On("GetTokenTransferByID", mock.Anything, "ns", transferID).Return(&core.TokenTransfer{
+		LocalID: transferID,
+		Pool:    poolID,
+		Message: msgID,
+		TX:      core.TransactionRef{ID: txID},
+	}, nil)
+	or.mdi.On("GetTokenPoolByID", mock.Anything, "ns", poolID).Return(&core.TokenPool{ID: poolID, Name: "pool1"}, nil)
+
+	result := queryGraphQL(t, or, &core.GraphQLRequest{
+		Query: fmt.Sprintf(`{
+			message(id: "%s") { header { id } transaction { id } }
+			transaction(id: "%s") { id }
+			tokenTransfer(id: "%s") { localId pool { name } message { header { id } } transaction { id } }
+		}`, msgID, txID, transferID),
+	})
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]interface{}{
+		"message":     map[string]interface{}{"header": map[string]interface{}{"id": msgID.String()}, "transaction": nil},
+		"transaction": map[string]interface{}{"id": txID.String()},
+		"tokenTransfer": map[string]interface{}{
+			"localId":     transferID.String(),
+			"pool":        map[string]interface{}{"name": "pool1"},
+			"message":     map[string]interface{}{"header": map[string]interface{}{"id": msgID.String()}},
+			"transaction": map[string]interface{}{"id": txID.String()},
+		},
+	}, map[string]interface{}(result.GetObject("data")))
+}
+
+func TestQueryGraphQLLists(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetTransactions", mock.Anything, "ns", mock.Anything).Return([]*core.Transaction{}, nil, nil)
+	or.mdi.On("GetTokenTransfers", mock.Anything, "ns", mock.Anything).Return([]*core.TokenTransfer{{}}, nil, nil)
+
+	result := queryGraphQL(t, or, &core.GraphQLRequest{
+		Query: `{ transactions(type: "batch_pin") { id } tokenTransfers(sort: "-created") { message { header { id } } } }`,
+	})
+	assert.Nil(t, result["errors"])
+	assert.Equal(t, map[string]interface{}{
+		"transactions":   []interface{}{},
+		"tokenTransfers": []interface{}{map[string]interface{}{"message": nil}},
+	}, map[string]interface{}(result.GetObject("data")))
+	assert.Equal(t, "( type == 'batch_pin' ) limit=25", filterString(t, or.mdi.Calls[0].Arguments[2]))
+	assert.Equal(t, " sort=-created limit=25", filterString(t, or.mdi.Calls[1].Arguments[2]))
+}
+
+func TestQueryGraphQLArgumentErrors(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	result := queryGraphQL(t, or, &core.GraphQLRequest{
+		Query: `{
+			m1: message { id }
+			m2: message(id: 12345) { id }
+			m3: message(id: "bad") { id }
+			t1: transaction { id }
+			tt1: tokenTransfer { localId }
+			l1: messages(limit: -1) { id }
+			l2: messages(limit: 1001) { id }
+			l3: transactions(skip: "one") { id }
+			l4: tokenTransfers(sort: 1) { localId }
+			l5: tokenTransfers(sort: ["a", 1]) { localId }
+		}`,
+	})
+	var messages []string
+	for _, e := range result["errors"].([]interface{}) {
+		messages = append(messages, e.(map[string]interface{})["message"].(string))
+	}
+	assert.Len(t, messages, 10)
+	assert.Regexp(t, "FF10545.*id", messages[0])
+	assert.Regexp(t, "FF10544.*id.*12,345", messages[1])
+	assert.Regexp(t, "FF00138", messages[2])
+	assert.Regexp(t, "FF10545.*id", messages[3])
+	assert.Regexp(t, "FF10545.*id", messages[4])
+	assert.Regexp(t, "FF10544.*limit.*-1", messages[5])
+	assert.Regexp(t, "FF00192.*1,000", messages[6])
+	assert.Regexp(t, "FF10544.*skip.*one", messages[7])
+	assert.Regexp(t, "FF10544.*sort.*1", messages[8])
+	assert.Regexp(t, "FF10544.*sort", messages[9])
+}
+
+func TestQueryGraphQLBadQuery(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.QueryGraphQL(context.Background(), &core.GraphQLRequest{Query: `mutation { a }`})
+	assert.Regexp(t, "FF10539", err)
+}
@@ -19,9 +19,11 @@ package orchestrator
 import (
 	"context"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 func (or *orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut) (reply *core.MessageInOut, err error) {
@@ -30,3 +32,36 @@ func (or *orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut
 	}
 	return or.PrivateMessaging().RequestReply(ctx, msg)
 }
+
+func (or *orchestrator) GetScheduledMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	return or.database().GetMessages(ctx, or.namespace.Name, filter.Condition(filter.Builder().Eq("state", core.MessageStateScheduled)))
+}
+
+// CancelScheduledMessage stops a message that is waiting for its sendAfter time from ever being sent
+func (or *orchestrator) CancelScheduledMessage(ctx context.Context, id string) (*core.Message, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.State != core.MessageStateScheduled {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotScheduled, msg.Header.ID)
+	}
+
+	// The state is checked again in the update, as the batch manager might release the message at the same time
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("id", msg.Header.ID),
+		fb.Eq("state", core.MessageStateScheduled),
+	)
+	update := database.MessageQueryFactory.NewUpdate(ctx).Set("state", core.MessageStateCancelled)
+	if err := or.database().UpdateMessages(ctx, or.namespace.Name, filter, update); err != nil {
+		return nil, err
+	}
+	if msg, err = or.getMessageByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if msg.State != core.MessageStateCancelled {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotScheduled, msg.Header.ID)
+	}
+	return msg, nil
+}
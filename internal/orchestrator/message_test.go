@@ -18,10 +18,15 @@ package orchestrator
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestRequestReplyMissingGroup(t *testing.T) {
@@ -44,3 +49,104 @@ func TestRequestReply(t *testing.T) {
 	_, err := or.RequestReply(context.Background(), input)
 	assert.NoError(t, err)
 }
+
+func TestGetScheduledMessages(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "( tag == 'tag1' ) && ( state == 'scheduled' )"
+	})).Return([]*core.Message{}, nil, nil)
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetScheduledMessages(context.Background(), fb.And(fb.Eq("tag", "tag1")))
+	assert.NoError(t, err)
+}
+
+func TestCancelScheduledMessage(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateScheduled,
+	}, nil).Once()
+	or.mdi.On("UpdateMessages", mock.Anything, "ns", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == fmt.Sprintf("( id == '%s' ) && ( state == 'scheduled' )", msgID)
+	}), mock.Anything).Return(nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateCancelled,
+	}, nil).Once()
+
+	msg, err := or.CancelScheduledMessage(context.Background(), msgID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateCancelled, msg.State)
+}
+
+func TestCancelScheduledMessageBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	_, err := or.CancelScheduledMessage(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestCancelScheduledMessageNotScheduled(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateSent,
+	}, nil)
+
+	_, err := or.CancelScheduledMessage(context.Background(), msgID.String())
+	assert.Regexp(t, "FF10555", err)
+}
+
+func TestCancelScheduledMessageUpdateFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateScheduled,
+	}, nil)
+	or.mdi.On("UpdateMessages", mock.Anything, "ns", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := or.CancelScheduledMessage(context.Background(), msgID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCancelScheduledMessageRereadFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateScheduled,
+	}, nil).Once()
+	or.mdi.On("UpdateMessages", mock.Anything, "ns", mock.Anything, mock.Anything).Return(nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.CancelScheduledMessage(context.Background(), msgID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCancelScheduledMessageReleasedMeanwhile(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	msgID := fftypes.NewUUID()
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateScheduled,
+	}, nil).Once()
+	or.mdi.On("UpdateMessages", mock.Anything, "ns", mock.Anything, mock.Anything).Return(nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msgID).Return(&core.Message{
+		Header: core.MessageHeader{ID: msgID},
+		State:  core.MessageStateReady,
+	}, nil)
+
+	_, err := or.CancelScheduledMessage(context.Background(), msgID.String())
+	assert.Regexp(t, "FF10555", err)
+}
@@ -0,0 +1,194 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// networkPingPollInterval is how often the operation of each ping is checked for delivery
+var networkPingPollInterval = 100 * time.Millisecond
+
+// RunNetworkDiagnostics actively probes the connectivity of this node to the network. All checks run concurrently,
+// and each is bounded by the diagnostics timeout:
+// - a ping is sent over data exchange to each node, and must be reported as delivered by data exchange
+// - a probe is written to shared storage, and must be read back unchanged
+// - the listener for the multiparty contract must be in sync with the chain
+func (or *orchestrator) RunNetworkDiagnostics(ctx context.Context) (*core.NetworkDiagnostics, error) {
+	if or.multiparty == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	timeout := config.GetDuration(coreconfig.OrchestratorDiagnosticsTimeout)
+
+	localNode, err := or.identity.GetLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fb := database.IdentityQueryFactory.NewFilter(ctx)
+	nodes, _, err := or.database().GetIdentities(ctx, or.namespace.Name, fb.And(fb.Eq("type", core.IdentityTypeNode)).Sort("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := &core.NetworkDiagnostics{
+		Blockchain:    &core.BlockchainDiagnostics{Status: core.ContractListenerStatusUnknown},
+		SharedStorage: &core.DiagnosticCheck{},
+		Members:       make([]*core.MemberDiagnostics, len(nodes)),
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	runCheck := func(check *core.DiagnosticCheck, probe func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := probe(checkCtx)
+			check.Latency = fftypes.FFDuration(time.Since(start))
+			if err != nil {
+				check.Error = err.Error()
+			} else {
+				check.Healthy = true
+			}
+		}()
+	}
+
+	runCheck(&diagnostics.Blockchain.DiagnosticCheck, func(ctx context.Context) error {
+		return or.checkBlockchainSync(ctx, diagnostics.Blockchain)
+	})
+	runCheck(diagnostics.SharedStorage, or.checkSharedStorage)
+	for i, node := range nodes {
+		member := &core.MemberDiagnostics{
+			Node:  node.ID,
+			Name:  node.Name,
+			Org:   node.Parent,
+			Local: localNode != nil && node.ID.Equals(localNode.ID),
+		}
+		diagnostics.Members[i] = member
+		if member.Local {
+			// Data exchange cannot send to itself, so only check the local data exchange can be reached
+			runCheck(&member.DiagnosticCheck, or.dataexchange().Ping)
+		} else {
+			node := node
+			runCheck(&member.DiagnosticCheck, func(ctx context.Context) error {
+				return or.pingMember(ctx, node, member, timeout)
+			})
+		}
+	}
+	wg.Wait()
+
+	diagnostics.Healthy = diagnostics.Blockchain.Healthy && diagnostics.SharedStorage.Healthy
+	for _, member := range diagnostics.Members {
+		diagnostics.Healthy = diagnostics.Healthy && member.Healthy
+	}
+	log.L(ctx).Infof("Network diagnostics complete: healthy=%t members=%d", diagnostics.Healthy, len(diagnostics.Members))
+	return diagnostics, nil
+}
+
+func (or *orchestrator) checkBlockchainSync(ctx context.Context, result *core.BlockchainDiagnostics) error {
+	var subID string
+	if or.namespace.Contracts != nil && or.namespace.Contracts.Active != nil {
+		subID = or.namespace.Contracts.Active.Info.Subscription
+	}
+	found, checkpoint, status, err := or.blockchain().GetContractListenerStatus(ctx, or.namespace.Name, subID, true)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return i18n.NewError(ctx, coremsgs.MsgMultipartyListenerNotFound, subID)
+	}
+	result.Status = status
+	result.Checkpoint = checkpoint
+	if status != core.ContractListenerStatusSynced {
+		return i18n.NewError(ctx, coremsgs.MsgMultipartyListenerSyncing, subID)
+	}
+	return nil
+}
+
+func (or *orchestrator) checkSharedStorage(ctx context.Context) error {
+	probe, _ := json.Marshal(fftypes.JSONObject{
+		"diagnostics": fftypes.NewUUID(),
+		"namespace":   or.namespace.NetworkName,
+		"created":     fftypes.Now(),
+	})
+	payloadRef, err := or.sharedstorage().UploadData(ctx, bytes.NewReader(probe))
+	if err != nil {
+		return err
+	}
+	reader, err := or.sharedstorage().DownloadData(ctx, payloadRef)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	readBack, err := io.ReadAll(io.LimitReader(reader, int64(len(probe)+1)))
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgDownloadSharedFailed, payloadRef)
+	}
+	if !bytes.Equal(probe, readBack) {
+		return i18n.NewError(ctx, coremsgs.MsgSharedStorageProbeMismatch, payloadRef)
+	}
+	return nil
+}
+
+// pingMember sends a ping to a node over data exchange, then waits for the operation to be updated with the
+// result of the delivery reported by data exchange
+func (or *orchestrator) pingMember(ctx context.Context, node *core.Identity, member *core.MemberDiagnostics, timeout time.Duration) error {
+	op, err := or.messaging.SendPing(ctx, node)
+	if op != nil {
+		member.Operation = op.ID
+	}
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(networkPingPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return i18n.NewError(ctx, coremsgs.MsgNetworkPingTimeout, node.Name, timeout)
+		}
+		op, err := or.database().GetOperationByID(ctx, or.namespace.Name, member.Operation)
+		if err != nil {
+			return err
+		}
+		switch {
+		case op == nil:
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		case op.Status == core.OpStatusSucceeded:
+			return nil
+		case op.Status == core.OpStatusFailed:
+			return i18n.NewError(ctx, coremsgs.MsgNetworkPingFailed, node.Name, op.Error)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestDiagnosticsNode(name string) *core.Identity {
+	return &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:     fftypes.NewUUID(),
+			Type:   core.IdentityTypeNode,
+			Name:   name,
+			Parent: fftypes.NewUUID(),
+		},
+	}
+}
+
+func newTestDiagnosticsOrchestrator(t *testing.T) *testOrchestrator {
+	coreconfig.Reset()
+	networkPingPollInterval = time.Millisecond
+	or := newTestOrchestrator()
+	or.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{Info: core.MultipartyContractInfo{Subscription: "sub1"}},
+	}
+	return or
+}
+
+// mockSharedStorageRoundTrip returns whatever was uploaded, unless a different read back is given
+func mockSharedStorageRoundTrip(or *testOrchestrator, readBack []byte) {
+	var uploaded []byte
+	or.mps.On("UploadData", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		uploaded, _ = io.ReadAll(args[1].(io.Reader))
+	}).Return("ref1", nil)
+	or.mps.On("DownloadData", mock.Anything, "ref1").Return(func(ctx context.Context, payloadRef string) io.ReadCloser {
+		if readBack == nil {
+			readBack = uploaded
+		}
+		return io.NopCloser(bytes.NewReader(readBack))
+	}, nil)
+}
+
+func TestRunNetworkDiagnosticsHealthy(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+
+	local, remote := newTestDiagnosticsNode("node1"), newTestDiagnosticsNode("node2")
+	opID := fftypes.NewUUID()
+	or.mim.On("GetLocalNode", mock.Anything).Return(local, nil)
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{local, remote}, nil, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "sub1", true).Return(true, fftypes.JSONObject{"block": 12345}, core.ContractListenerStatusSynced, nil)
+	mockSharedStorageRoundTrip(or, nil)
+	or.mdx.On("Ping", mock.Anything).Return(nil)
+	or.mpm.On("SendPing", mock.Anything, remote).Return(&core.Operation{ID: opID}, nil)
+	or.mdi.On("GetOperationByID", mock.Anything, "ns", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusPending}, nil).Once()
+	or.mdi.On("GetOperationByID", mock.Anything, "ns", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusSucceeded}, nil).Once()
+
+	diagnostics, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.NoError(t, err)
+	assert.True(t, diagnostics.Healthy)
+	assert.True(t, diagnostics.Blockchain.Healthy)
+	assert.Equal(t, core.ContractListenerStatusSynced, diagnostics.Blockchain.Status)
+	assert.Equal(t, fftypes.JSONObject{"block": 12345}, diagnostics.Blockchain.Checkpoint)
+	assert.True(t, diagnostics.SharedStorage.Healthy)
+	assert.Len(t, diagnostics.Members, 2)
+	assert.True(t, diagnostics.Members[0].Local)
+	assert.True(t, diagnostics.Members[0].Healthy)
+	assert.Nil(t, diagnostics.Members[0].Operation)
+	assert.False(t, diagnostics.Members[1].Local)
+	assert.True(t, diagnostics.Members[1].Healthy)
+	assert.Equal(t, "node2", diagnostics.Members[1].Name)
+	assert.Equal(t, remote.Parent, diagnostics.Members[1].Org)
+	assert.Equal(t, opID, diagnostics.Members[1].Operation)
+}
+
+func TestRunNetworkDiagnosticsUnhealthy(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+
+	failed, missing, sendFail, queryFail := newTestDiagnosticsNode("node2"), newTestDiagnosticsNode("node3"), newTestDiagnosticsNode("node4"), newTestDiagnosticsNode("node5")
+	failedOpID, missingOpID, queryFailOpID := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	or.mim.On("GetLocalNode", mock.Anything).Return(nil, nil)
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{failed, missing, sendFail, queryFail}, nil, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "sub1", true).Return(true, nil, core.ContractListenerStatusSyncing, nil)
+	mockSharedStorageRoundTrip(or, []byte("different"))
+	or.mpm.On("SendPing", mock.Anything, failed).Return(&core.Operation{ID: failedOpID}, nil)
+	or.mpm.On("SendPing", mock.Anything, missing).Return(&core.Operation{ID: missingOpID}, nil)
+	or.mpm.On("SendPing", mock.Anything, sendFail).Return(nil, fmt.Errorf("pop"))
+	or.mpm.On("SendPing", mock.Anything, queryFail).Return(&core.Operation{ID: queryFailOpID}, nil)
+	or.mdi.On("GetOperationByID", mock.Anything, "ns", failedOpID).Return(&core.Operation{Status: core.OpStatusFailed, Error: "unreachable"}, nil)
+	or.mdi.On("GetOperationByID", mock.Anything, "ns", missingOpID).Return(nil, nil)
+	or.mdi.On("GetOperationByID", mock.Anything, "ns", queryFailOpID).Return(nil, fmt.Errorf("pop"))
+
+	diagnostics, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.NoError(t, err)
+	assert.False(t, diagnostics.Healthy)
+	assert.Regexp(t, "FF10618.*sub1", diagnostics.Blockchain.Error)
+	assert.Equal(t, core.ContractListenerStatusSyncing, diagnostics.Blockchain.Status)
+	assert.Regexp(t, "FF10616.*ref1", diagnostics.SharedStorage.Error)
+	assert.Regexp(t, "FF10619.*node2.*unreachable", diagnostics.Members[0].Error)
+	assert.Equal(t, failedOpID, diagnostics.Members[0].Operation)
+	assert.Regexp(t, "FF10109", diagnostics.Members[1].Error)
+	assert.Equal(t, "pop", diagnostics.Members[2].Error)
+	assert.Nil(t, diagnostics.Members[2].Operation)
+	assert.Equal(t, "pop", diagnostics.Members[3].Error)
+	for _, member := range diagnostics.Members {
+		assert.False(t, member.Local)
+		assert.False(t, member.Healthy)
+	}
+}
+
+func TestRunNetworkDiagnosticsTimeout(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+	config.Set(coreconfig.OrchestratorDiagnosticsTimeout, "50ms")
+	or.namespace.Contracts = nil
+
+	remote := newTestDiagnosticsNode("node2")
+	opID := fftypes.NewUUID()
+	or.mim.On("GetLocalNode", mock.Anything).Return(newTestDiagnosticsNode("node1"), nil)
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{remote}, nil, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "", true).Return(false, nil, core.ContractListenerStatusUnknown, nil)
+	or.mps.On("UploadData", mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+	or.mpm.On("SendPing", mock.Anything, remote).Return(&core.Operation{ID: opID}, nil)
+	or.mdi.On("GetOperationByID", mock.Anything, "ns", opID).Return(&core.Operation{ID: opID, Status: core.OpStatusPending}, nil)
+
+	diagnostics, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.NoError(t, err)
+	assert.False(t, diagnostics.Healthy)
+	assert.Regexp(t, "FF10617", diagnostics.Blockchain.Error)
+	assert.Equal(t, core.ContractListenerStatusUnknown, diagnostics.Blockchain.Status)
+	assert.Equal(t, "pop", diagnostics.SharedStorage.Error)
+	assert.Regexp(t, "FF10615.*node2.*50ms", diagnostics.Members[0].Error)
+}
+
+func TestRunNetworkDiagnosticsErrors(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+
+	or.mim.On("GetLocalNode", mock.Anything).Return(nil, nil)
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil)
+	or.mbi.On("GetContractListenerStatus", mock.Anything, "ns", "sub1", true).Return(false, nil, core.ContractListenerStatusUnknown, fmt.Errorf("pop"))
+	or.mps.On("UploadData", mock.Anything, mock.Anything).Return("ref1", nil)
+	or.mps.On("DownloadData", mock.Anything, "ref1").Return(nil, fmt.Errorf("pop"))
+
+	diagnostics, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.NoError(t, err)
+	assert.False(t, diagnostics.Healthy)
+	assert.Equal(t, "pop", diagnostics.Blockchain.Error)
+	assert.Equal(t, "pop", diagnostics.SharedStorage.Error)
+	assert.Empty(t, diagnostics.Members)
+}
+
+func TestRunNetworkDiagnosticsSharedStorageReadFail(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+
+	or.mps.On("UploadData", mock.Anything, mock.Anything).Return("ref1", nil)
+	or.mps.On("DownloadData", mock.Anything, "ref1").Return(io.NopCloser(iotest.ErrReader(fmt.Errorf("pop"))), nil)
+
+	err := or.checkSharedStorage(or.ctx)
+	assert.Regexp(t, "FF10376.*ref1", err)
+}
+
+func TestRunNetworkDiagnosticsGetIdentitiesFail(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+
+	or.mim.On("GetLocalNode", mock.Anything).Return(nil, nil)
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRunNetworkDiagnosticsLocalNodeFail(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+
+	or.mim.On("GetLocalNode", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRunNetworkDiagnosticsNotMultiparty(t *testing.T) {
+	or := newTestDiagnosticsOrchestrator(t)
+	defer or.cleanup(t)
+	or.multiparty = nil
+
+	_, err := or.RunNetworkDiagnostics(or.ctx)
+	assert.Regexp(t, "FF10414", err)
+}
@@ -0,0 +1,199 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const networkResyncPageSize = 50
+
+var networkResyncTags = []driver.Value{
+	core.DeprecatedSystemTagDefineOrganization,
+	core.DeprecatedSystemTagDefineNode,
+	core.SystemTagIdentityClaim,
+	core.SystemTagIdentityVerification,
+	core.SystemTagIdentityUpdate,
+	core.SystemTagIdentityVerifierClaim,
+}
+
+// ResyncNetwork rebuilds the network map by replaying every confirmed identity definition message, in the
+// order they were received, through the definition handler. Replay of a definition that is already reflected
+// in the network map is a no-op. The replay runs in a single database group while the aggregator is paused,
+// so it cannot interleave with the aggregator processing new definitions.
+func (or *orchestrator) ResyncNetwork(ctx context.Context) (*core.NetworkResync, error) {
+	if or.multiparty == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	if !or.resyncLock.TryLock() {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNetworkResyncInProgress)
+	}
+	defer or.resyncLock.Unlock()
+
+	var result *core.NetworkResync
+	err := or.events.RunWithAggregatorPaused(ctx, func(ctx context.Context) (err error) {
+		result, err = or.resyncNetwork(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Network resync complete: replayed=%d skipped=%d created=%d updated=%d unchanged=%d",
+		result.Messages, result.Skipped, len(result.Created), len(result.Updated), result.Unchanged)
+	return result, nil
+}
+
+func (or *orchestrator) resyncNetwork(ctx context.Context) (*core.NetworkResync, error) {
+	before, err := or.snapshotIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &core.NetworkResync{
+		SkippedDefinitions: []*core.NetworkResyncSkipped{},
+		Created:            []*fftypes.UUID{},
+		Updated:            []*fftypes.UUID{},
+	}
+	var page uint64
+	for {
+		fb := database.MessageQueryFactory.NewFilterLimit(ctx, networkResyncPageSize)
+		filter := fb.And(
+			fb.Eq("type", core.MessageTypeDefinition),
+			fb.Eq("state", core.MessageStateConfirmed),
+			fb.In("tag", networkResyncTags),
+		).Sort("sequence").Skip(page * networkResyncPageSize)
+		msgs, _, err := or.database().GetMessages(ctx, or.namespace.Name, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			skipReason, err := or.replayIdentityDefinition(ctx, msg)
+			if err != nil {
+				return nil, err
+			}
+			if skipReason == "" {
+				result.Messages++
+			} else {
+				log.L(ctx).Warnf("Network resync skipping definition '%s' [%s]: %s", msg.Header.Tag, msg.Header.ID, skipReason)
+				result.Skipped++
+				result.SkippedDefinitions = append(result.SkippedDefinitions, &core.NetworkResyncSkipped{
+					Message: msg.Header.ID,
+					Tag:     msg.Header.Tag,
+					Reason:  skipReason,
+				})
+			}
+		}
+		log.L(ctx).Infof("Network resync progress: replayed=%d skipped=%d", result.Messages, result.Skipped)
+		if len(msgs) < networkResyncPageSize {
+			break
+		}
+		page++
+	}
+
+	after, err := or.snapshotIdentities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for id, identity := range after {
+		previous, existed := before[id]
+		idCopy := id
+		switch {
+		case !existed:
+			result.Created = append(result.Created, &idCopy)
+		case previous != identity:
+			result.Updated = append(result.Updated, &idCopy)
+		default:
+			result.Unchanged++
+		}
+	}
+	sortUUIDs(result.Created)
+	sortUUIDs(result.Updated)
+
+	// Events are emitted for the differences found, rather than for every definition replayed
+	for _, id := range result.Created {
+		if err := or.database().InsertEvent(ctx, core.NewEvent(core.EventTypeIdentityConfirmed, or.namespace.Name, id, nil, core.SystemTopicDefinitions)); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range result.Updated {
+		if err := or.database().InsertEvent(ctx, core.NewEvent(core.EventTypeIdentityUpdated, or.namespace.Name, id, nil, core.SystemTopicDefinitions)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// replayIdentityDefinition replays a single definition, returning the reason it was skipped if it could not be replayed
+func (or *orchestrator) replayIdentityDefinition(ctx context.Context, msg *core.Message) (string, error) {
+	data, foundAll, err := or.data.GetMessageDataCached(ctx, msg)
+	if err != nil {
+		return "", err
+	}
+	if !foundAll {
+		return i18n.NewError(ctx, coremsgs.MsgNetworkResyncDataMissing).Error(), nil
+	}
+	state := &core.BatchState{
+		PendingConfirms: make(map[fftypes.UUID]*core.Message),
+	}
+	result, err := or.defhandler.HandleDefinitionBroadcast(ctx, state, msg, data, msg.TransactionID)
+	if result.Action == core.ActionRetry {
+		return "", err
+	}
+	if result.Action != core.ActionConfirm {
+		return i18n.NewError(ctx, coremsgs.MsgNetworkResyncNotAccepted, result.Action, err).Error(), nil
+	}
+	// Finalize callbacks only emit events, which are handled by the caller
+	return "", state.RunPreFinalize(ctx)
+}
+
+// snapshotIdentities returns the serialized form of each identity, excluding timestamps, for comparison
+func (or *orchestrator) snapshotIdentities(ctx context.Context) (map[fftypes.UUID]string, error) {
+	snapshot := make(map[fftypes.UUID]string)
+	var page uint64
+	for {
+		fb := database.IdentityQueryFactory.NewFilterLimit(ctx, networkResyncPageSize)
+		identities, _, err := or.database().GetIdentities(ctx, or.namespace.Name, fb.And().Sort("created").Skip(page*networkResyncPageSize))
+		if err != nil {
+			return nil, err
+		}
+		for _, identity := range identities {
+			compare := *identity
+			compare.Created = nil
+			compare.Updated = nil
+			b, _ := json.Marshal(&compare)
+			snapshot[*identity.ID] = string(b)
+		}
+		if len(identities) < networkResyncPageSize {
+			return snapshot, nil
+		}
+		page++
+	}
+}
+
+func sortUUIDs(ids []*fftypes.UUID) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+}
@@ -0,0 +1,294 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/definitions"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testResyncIdentity(id *fftypes.UUID, description string) *core.Identity {
+	return &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:   id,
+			Type: core.IdentityTypeOrg,
+			Name: "org-" + id.String(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Description: description,
+		},
+		Created: fftypes.Now(),
+	}
+}
+
+func testResyncMessage(tag string) *core.Message {
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: core.MessageTypeDefinition,
+			Tag:  tag,
+		},
+		State: core.MessageStateConfirmed,
+	}
+}
+
+func mockRunWithAggregatorPaused(or *testOrchestrator) {
+	rp := or.mem.On("RunWithAggregatorPaused", mock.Anything, mock.Anything)
+	rp.RunFn = func(a mock.Arguments) {
+		rp.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+}
+
+func TestResyncNetwork(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	updated, unchanged, created := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{
+		testResyncIdentity(updated, "old"),
+		testResyncIdentity(unchanged, "same"),
+	}, nil, nil).Once()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{
+		testResyncIdentity(updated, "new"),
+		testResyncIdentity(unchanged, "same"),
+		testResyncIdentity(created, "added"),
+	}, nil, nil).Once()
+
+	fullPage := make([]*core.Message, networkResyncPageSize)
+	for i := range fullPage {
+		fullPage[i] = testResyncMessage(core.SystemTagIdentityClaim)
+	}
+	missingData := testResyncMessage(core.SystemTagIdentityUpdate)
+	rejected := testResyncMessage(core.SystemTagIdentityVerification)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return(fullPage, nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{missingData, rejected}, nil, nil).Once()
+
+	or.mdm.On("GetMessageDataCached", mock.Anything, missingData).Return(nil, false, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, mock.Anything).Return(core.DataArray{}, true, nil)
+	or.mdh.On("HandleDefinitionBroadcast", mock.Anything, mock.Anything, rejected, mock.Anything, mock.Anything).
+		Return(definitions.HandlerResult{Action: core.ActionReject}, fmt.Errorf("rejected"))
+	preFinalized := 0
+	or.mdh.On("HandleDefinitionBroadcast", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args[1].(*core.BatchState).AddPreFinalize(func(ctx context.Context) error {
+				preFinalized++
+				return nil
+			})
+		}).
+		Return(definitions.HandlerResult{Action: core.ActionConfirm}, nil)
+
+	or.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeIdentityConfirmed && e.Reference.Equals(created)
+	})).Return(nil)
+	or.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeIdentityUpdated && e.Reference.Equals(updated)
+	})).Return(nil)
+
+	result, err := or.ResyncNetwork(or.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, networkResyncPageSize, result.Messages)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Len(t, result.SkippedDefinitions, 2)
+	assert.Equal(t, missingData.Header.ID, result.SkippedDefinitions[0].Message)
+	assert.Equal(t, core.SystemTagIdentityUpdate, result.SkippedDefinitions[0].Tag)
+	assert.Regexp(t, "FF10653", result.SkippedDefinitions[0].Reason)
+	assert.Equal(t, rejected.Header.ID, result.SkippedDefinitions[1].Message)
+	assert.Regexp(t, "FF10654.*rejected", result.SkippedDefinitions[1].Reason)
+	assert.Equal(t, []*fftypes.UUID{created}, result.Created)
+	assert.Equal(t, []*fftypes.UUID{updated}, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, networkResyncPageSize, preFinalized)
+}
+
+func TestResyncNetworkNotMultiparty(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.multiparty = nil
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestResyncNetworkInProgress(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.resyncLock.Lock()
+	defer or.resyncLock.Unlock()
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "FF10493", err)
+}
+
+func TestResyncNetworkSnapshotFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkSnapshotAfterFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil).Once()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Once()
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{}, nil, nil)
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkGetMessagesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkGetDataFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{
+		testResyncMessage(core.SystemTagIdentityClaim),
+	}, nil, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, mock.Anything).Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkHandlerRetry(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{
+		testResyncMessage(core.SystemTagIdentityClaim),
+	}, nil, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, mock.Anything).Return(core.DataArray{}, true, nil)
+	or.mdh.On("HandleDefinitionBroadcast", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(definitions.HandlerResult{Action: core.ActionRetry}, fmt.Errorf("pop"))
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkPreFinalizeFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{
+		testResyncMessage(core.SystemTagIdentityClaim),
+	}, nil, nil)
+	or.mdm.On("GetMessageDataCached", mock.Anything, mock.Anything).Return(core.DataArray{}, true, nil)
+	or.mdh.On("HandleDefinitionBroadcast", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args[1].(*core.BatchState).AddPreFinalize(func(ctx context.Context) error {
+				return fmt.Errorf("pop")
+			})
+		}).
+		Return(definitions.HandlerResult{Action: core.ActionConfirm}, nil)
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkInsertCreatedEventFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil).Once()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{
+		testResyncIdentity(fftypes.NewUUID(), "added"),
+	}, nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{}, nil, nil)
+	or.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkInsertUpdatedEventFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{
+		testResyncIdentity(id, "old"),
+	}, nil, nil).Once()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{
+		testResyncIdentity(id, "new"),
+	}, nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{}, nil, nil)
+	or.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := or.ResyncNetwork(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestResyncNetworkSnapshotPaging(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mockRunWithAggregatorPaused(or)
+
+	fullPage := make([]*core.Identity, networkResyncPageSize)
+	for i := range fullPage {
+		fullPage[i] = testResyncIdentity(fftypes.NewUUID(), "added")
+	}
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{}, nil, nil).Once()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return(fullPage, nil, nil).Once()
+	or.mdi.On("GetIdentities", mock.Anything, "ns", mock.Anything).Return([]*core.Identity{
+		testResyncIdentity(fftypes.NewUUID(), "added"),
+	}, nil, nil).Once()
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{}, nil, nil)
+	or.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
+
+	result, err := or.ResyncNetwork(or.ctx)
+	assert.NoError(t, err)
+	assert.Len(t, result.Created, networkResyncPageSize+1)
+	for i := 1; i < len(result.Created); i++ {
+		assert.Less(t, result.Created[i-1].String(), result.Created[i].String())
+	}
+}
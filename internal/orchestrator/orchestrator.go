@@ -18,9 +18,14 @@ package orchestrator
 
 import (
 	"context"
+	"database/sql/driver"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/auth"
+	"github.com/hyperledger/firefly-common/pkg/auth/basic"
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -29,25 +34,31 @@ import (
 	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/broadcast"
 	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/charts"
 	"github.com/hyperledger/firefly/internal/contracts"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/definitions"
 	"github.com/hyperledger/firefly/internal/events"
 	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/keyprovider"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/internal/networkmap"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/shareddownload"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/internal/triggers"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/internal/txwriter"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/encryption"
 	eventsplugin "github.com/hyperledger/firefly/pkg/events"
 	idplugin "github.com/hyperledger/firefly/pkg/identity"
 	"github.com/hyperledger/firefly/pkg/sharedstorage"
@@ -73,19 +84,33 @@ type Orchestrator interface {
 	NetworkMap() networkmap.Manager
 	Operations() operations.Manager
 	Identity() identity.Manager
+	Retention() retention.Manager
 
 	// Status
 	GetStatus(ctx context.Context) (*core.NamespaceStatus, error)
 	GetMultipartyStatus(ctx context.Context) (*core.NamespaceMultipartyStatus, error)
+	GetPluginsStatus(ctx context.Context) *core.NamespaceStatusPlugins
+	GetErrorReport(ctx context.Context, window time.Duration) (*core.ErrorReport, error)
+	GetReadiness(ctx context.Context) *core.NamespaceReadiness
 
 	// Subscription management
 	GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error)
 	GetSubscriptionByID(ctx context.Context, id string) (*core.Subscription, error)
 	GetSubscriptionByIDWithStatus(ctx context.Context, id string) (*core.SubscriptionWithStatus, error)
 	GetSubscriptionEventsHistorical(ctx context.Context, subscription *core.Subscription, filter ffapi.AndFilter, startSequence int, endSequence int) ([]*core.EnrichedEvent, *ffapi.FilterResult, error)
+	PreviewSubscription(ctx context.Context, input *core.SubscriptionPreviewInput) (*core.SubscriptionPreview, error)
 	CreateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error)
 	CreateUpdateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error)
 	DeleteSubscription(ctx context.Context, id string) error
+	GetSubscriptionDeadLetters(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)
+	ReplaySubscriptionDeadLetter(ctx context.Context, id, deadLetterID string) error
+	ReplaySubscription(ctx context.Context, id string, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error)
+
+	// Bridge management
+	CreateBridge(ctx context.Context, bridge *core.Bridge) (*core.Bridge, error)
+	GetBridges(ctx context.Context, filter ffapi.AndFilter) ([]*core.Bridge, *ffapi.FilterResult, error)
+	GetBridgeByNameOrID(ctx context.Context, nameOrID string) (*core.Bridge, error)
+	DeleteBridge(ctx context.Context, nameOrID string) error
 
 	// Data Query
 	GetNamespace(ctx context.Context) *core.Namespace
@@ -98,39 +123,55 @@ type Orchestrator interface {
 	GetMessageByIDWithData(ctx context.Context, id string) (*core.MessageInOut, error)
 	GetMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
 	GetMessagesWithData(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageInOut, *ffapi.FilterResult, error)
+	SearchMessages(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) ([]*core.Message, *ffapi.FilterResult, error)
+	SearchMessagesWithData(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) ([]*core.MessageInOut, *ffapi.FilterResult, error)
+	ExportMessages(ctx context.Context, filter ffapi.AndFilter, fetchData bool, cb func(msg *core.Message, data core.DataArray) error) error
 	GetMessageTransaction(ctx context.Context, id string) (*core.Transaction, error)
 	GetMessageEvents(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)
+	GetMessageAcks(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error)
+	GetMessageProof(ctx context.Context, id string) (*core.MessageProof, error)
 	GetMessageData(ctx context.Context, id string) (core.DataArray, error)
+	QueryGraphQL(ctx context.Context, req *core.GraphQLRequest) (*core.GraphQLResponse, error)
 	GetMessagesForData(ctx context.Context, dataID string, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
+	GetScheduledMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
+	CancelScheduledMessage(ctx context.Context, id string) (*core.Message, error)
 	GetBatchByID(ctx context.Context, id string) (*core.BatchPersisted, error)
 	GetBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.BatchPersisted, *ffapi.FilterResult, error)
 	GetDataByID(ctx context.Context, id string) (*core.Data, error)
 	GetData(ctx context.Context, filter ffapi.AndFilter) (core.DataArray, *ffapi.FilterResult, error)
+	SearchData(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) (core.DataArray, *ffapi.FilterResult, error)
 	GetDataSubPaths(ctx context.Context, path string) ([]string, error)
 	GetDatatypeByID(ctx context.Context, id string) (*core.Datatype, error)
 	GetDatatypeByName(ctx context.Context, name, version string) (*core.Datatype, error)
 	GetDatatypes(ctx context.Context, filter ffapi.AndFilter) ([]*core.Datatype, *ffapi.FilterResult, error)
 	GetOperationByID(ctx context.Context, id string) (*core.Operation, error)
 	GetOperationByIDWithStatus(ctx context.Context, id string) (*core.OperationWithDetail, error)
+	GetOperationProgress(ctx context.Context, id string) (*core.OperationProgress, error)
 	GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error)
 	GetEventByID(ctx context.Context, id string) (*core.Event, error)
 	GetEventByIDWithReference(ctx context.Context, id string) (*core.EnrichedEvent, error)
 	GetEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)
 	GetEventsWithReferences(ctx context.Context, filter ffapi.AndFilter) ([]*core.EnrichedEvent, *ffapi.FilterResult, error)
 	GetBlockchainEventByID(ctx context.Context, id string) (*core.BlockchainEvent, error)
+	GetBlockchainEventOutput(ctx context.Context, id string) (fftypes.JSONObject, error)
 	GetBlockchainEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
 	GetPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.Pin, *ffapi.FilterResult, error)
 	GetNextPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.NextPin, *ffapi.FilterResult, error)
 	RewindPins(ctx context.Context, rewind *core.PinRewind) (*core.PinRewind, error)
+	RewindPinsFromSequence(ctx context.Context, sequence int64, dryRun bool) (*core.PinRewindResult, error)
+	RewindPinsForBatches(ctx context.Context, rewind *core.PinRewindBatches, dryRun bool) (*core.PinRewindResult, error)
 
 	// Charts
 	GetChartHistogram(ctx context.Context, startTime int64, endTime int64, buckets int64, tableName database.CollectionName) ([]*core.ChartHistogram, error)
+	GetChartTimeSeries(ctx context.Context, collection database.CollectionName, query *core.ChartTimeSeriesQuery) ([]*core.ChartTimeSeriesBucket, error)
 
 	// Message Routing
 	RequestReply(ctx context.Context, msg *core.MessageInOut) (reply *core.MessageInOut, err error)
 
 	// Network Operations
 	SubmitNetworkAction(ctx context.Context, action *core.NetworkAction) error
+	ResyncNetwork(ctx context.Context) (*core.NetworkResync, error)
+	RunNetworkDiagnostics(ctx context.Context) (*core.NetworkDiagnostics, error)
 
 	// Authorizer
 	Authorize(ctx context.Context, authReq *fftypes.AuthReq) error
@@ -187,7 +228,9 @@ type Config struct {
 	KeyNormalization            string
 	Multiparty                  multiparty.Config
 	TokenBroadcastNames         map[string]string
+	TokenPoolConnectors         map[string]string
 	MaxHistoricalEventScanLimit int
+	Retention                   retention.Config
 }
 
 type orchestrator struct {
@@ -219,6 +262,11 @@ type orchestrator struct {
 	operations              operations.Manager
 	txHelper                txcommon.Helper
 	txWriter                txwriter.Writer
+	retention               retention.Manager
+	charts                  charts.Manager
+	triggers                triggers.Manager
+	keyProvider             encryption.KeyProvider // optional
+	resyncLock              sync.Mutex
 }
 
 func NewOrchestrator(ns *core.Namespace, config Config, plugins *Plugins, metrics metrics.Manager, cacheManager cache.Manager) Orchestrator {
@@ -296,12 +344,19 @@ func (or *orchestrator) Start() (err error) {
 	if err == nil {
 		err = or.operations.Start()
 	}
+	if err == nil {
+		err = or.triggers.Start()
+	}
 	if err == nil {
 		or.txWriter.Start()
 	}
 	if err == nil {
 		err = or.assets.Start()
 	}
+	if err == nil {
+		or.retention.Start()
+		or.charts.Start()
+	}
 
 	or.started = true
 	return err
@@ -341,6 +396,10 @@ func (or *orchestrator) WaitStop() {
 		or.events.WaitStop()
 		or.events = nil
 	}
+	if or.triggers != nil {
+		or.triggers.WaitStop()
+		or.triggers = nil
+	}
 	if or.operations != nil {
 		or.operations.WaitStop()
 		or.operations = nil
@@ -348,6 +407,14 @@ func (or *orchestrator) WaitStop() {
 	if or.txWriter != nil {
 		or.txWriter.Close()
 	}
+	if or.retention != nil {
+		or.retention.WaitStop()
+		or.retention = nil
+	}
+	if or.charts != nil {
+		or.charts.WaitStop()
+		or.charts = nil
+	}
 	or.startedLock.Lock()
 	defer or.startedLock.Unlock()
 	or.started = false
@@ -407,6 +474,10 @@ func (or *orchestrator) Identity() identity.Manager {
 	return or.identity
 }
 
+func (or *orchestrator) Retention() retention.Manager {
+	return or.retention
+}
+
 func (or *orchestrator) initHandlers(ctx context.Context) {
 	// Update all the handlers to point to this instance of the orchestrator
 	setHandlers(ctx, or.plugins, or.namespace, or.config.Multiparty.Node.Name, or, &or.bc)
@@ -468,7 +539,7 @@ func (or *orchestrator) initMultiPartyComponents(ctx context.Context) (err error
 	}
 
 	if or.messaging == nil {
-		if or.messaging, err = privatemessaging.NewPrivateMessaging(ctx, or.namespace, or.database(), or.dataexchange(), or.blockchain(), or.identity, or.batch, or.data, or.syncasync, or.multiparty, or.metrics, or.operations, or.cacheManager); err != nil {
+		if or.messaging, err = privatemessaging.NewPrivateMessaging(ctx, or.namespace, or.database(), or.dataexchange(), or.blockchain(), or.identity, or.batch, or.data, or.syncasync, or.multiparty, or.metrics, or.operations, or.triggers, or.cacheManager); err != nil {
 			return err
 		}
 	}
@@ -484,7 +555,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.operations == nil {
-		if or.operations, err = operations.NewOperationsManager(ctx, or.namespace.Name, or.database(), or.txHelper, or.cacheManager); err != nil {
+		if or.operations, err = operations.NewOperationsManager(ctx, or.namespace.Name, or.namespace.SigningSecrets, or.database(), or.txHelper, or.cacheManager); err != nil {
 			return err
 		}
 	}
@@ -493,6 +564,24 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 		or.txWriter = txwriter.NewTransactionWriter(ctx, or.namespace.Name, or.database(), or.txHelper, or.operations)
 	}
 
+	if or.triggers == nil {
+		if or.triggers, err = triggers.NewTriggerManager(ctx, or.namespace.Name, or.database(), or.txHelper, or.operations); err != nil {
+			return err
+		}
+	}
+
+	if or.retention == nil {
+		if or.retention, err = retention.NewRetentionManager(ctx, or.namespace.Name, or.config.Retention, or.database(), or.sharedstorage()); err != nil {
+			return err
+		}
+	}
+
+	if or.charts == nil {
+		if or.charts, err = charts.NewChartManager(ctx, or.namespace.Name, or.database()); err != nil {
+			return err
+		}
+	}
+
 	if or.config.Multiparty.Enabled {
 		if or.multiparty == nil {
 			or.multiparty, err = multiparty.NewMultipartyManager(or.ctx, or.namespace, or.config.Multiparty, or.database(), or.blockchain(), or.operations, or.metrics, or.txHelper)
@@ -521,14 +610,20 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.dataexchange() != nil && or.sharedstorage() != nil {
+		if or.keyProvider == nil {
+			if or.keyProvider, err = keyprovider.NewConfigKeyProvider(ctx); err != nil {
+				return err
+			}
+		}
+
 		if or.broadcast == nil {
-			if or.broadcast, err = broadcast.NewBroadcastManager(ctx, or.namespace, or.database(), or.blockchain(), or.dataexchange(), or.sharedstorage(), or.identity, or.data, or.batch, or.syncasync, or.multiparty, or.metrics, or.operations, or.txHelper); err != nil {
+			if or.broadcast, err = broadcast.NewBroadcastManager(ctx, or.namespace, or.database(), or.blockchain(), or.dataexchange(), or.sharedstorage(), or.identity, or.data, or.batch, or.syncasync, or.multiparty, or.metrics, or.operations, or.txHelper, or.triggers, or.keyProvider); err != nil {
 				return err
 			}
 		}
 
 		if or.sharedDownload == nil {
-			or.sharedDownload, err = shareddownload.NewDownloadManager(ctx, or.namespace, or.database(), or.sharedstorage(), or.dataexchange(), or.operations, &or.bc)
+			or.sharedDownload, err = shareddownload.NewDownloadManager(ctx, or.namespace, or.database(), or.sharedstorage(), or.dataexchange(), or.operations, &or.bc, or.keyProvider)
 			if err != nil {
 				return err
 			}
@@ -537,7 +632,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 
 	if or.blockchain() != nil {
 		if or.contracts == nil {
-			or.contracts, err = contracts.NewContractManager(ctx, or.namespace.Name, or.database(), or.blockchain(), or.data, or.broadcast, or.messaging, or.batch, or.identity, or.operations, or.txHelper, or.txWriter, or.syncasync, or.cacheManager)
+			or.contracts, err = contracts.NewContractManager(ctx, or.namespace.Name, or.database(), or.blockchain(), or.data, or.broadcast, or.messaging, or.batch, or.identity, or.operations, or.txHelper, or.txWriter, or.syncasync, or.triggers, or.cacheManager)
 			if err != nil {
 				return err
 			}
@@ -545,7 +640,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.assets == nil {
-		or.assets, err = assets.NewAssetManager(ctx, or.namespace.Name, or.config.KeyNormalization, or.database(), or.tokens(), or.identity, or.syncasync, or.broadcast, or.messaging, or.metrics, or.operations, or.contracts, or.txHelper, or.cacheManager)
+		or.assets, err = assets.NewAssetManager(ctx, or.namespace.Name, or.config.KeyNormalization, or.config.TokenPoolConnectors, or.database(), or.tokens(), or.identity, or.syncasync, or.broadcast, or.messaging, or.metrics, or.operations, or.contracts, or.sharedstorage(), or.txHelper, or.cacheManager)
 		if err != nil {
 			return err
 		}
@@ -593,13 +688,14 @@ func (or *orchestrator) initComponents(ctx context.Context) (err error) {
 	}
 
 	if or.events == nil {
-		or.events, err = events.NewEventManager(ctx, or.namespace, or.database(), or.blockchain(), or.identity, or.defhandler, or.data, or.defsender, or.broadcast, or.messaging, or.assets, or.sharedDownload, or.metrics, or.operations, or.txHelper, or.plugins.Events, or.multiparty, or.cacheManager)
+		or.events, err = events.NewEventManager(ctx, or.namespace, or.database(), or.blockchain(), or.identity, or.networkmap, or.defhandler, or.data, or.defsender, or.broadcast, or.messaging, or.assets, or.sharedDownload, or.metrics, or.operations, or.txHelper, or.plugins.Events, or.multiparty, or.cacheManager)
 		if err != nil {
 			return err
 		}
 	}
 
 	or.syncasync.Init(or.events)
+	or.triggers.Init(or.events, or.contracts)
 
 	return nil
 }
@@ -617,8 +713,17 @@ func (or *orchestrator) SubmitNetworkAction(ctx context.Context, action *core.Ne
 
 func (or *orchestrator) Authorize(ctx context.Context, authReq *fftypes.AuthReq) error {
 	authReq.Namespace = or.namespace.Name
-	if or.plugins.Auth.Plugin != nil {
-		return or.plugins.Auth.Plugin.Authorize(ctx, authReq)
+	if or.plugins.Auth.Plugin == nil {
+		return nil
+	}
+	if err := or.plugins.Auth.Plugin.Authorize(ctx, authReq); err != nil {
+		return err
+	}
+	if or.plugins.Auth.Plugin.Name() == basic.Name() {
+		// The basic auth plugin is common to other FireFly components, and does not record the identity itself
+		if username, _, ok := (&http.Request{Header: authReq.Header}).BasicAuth(); ok {
+			core.SetAuthenticatedIdentity(ctx, basic.Name()+":"+username)
+		}
 	}
 	return nil
 }
@@ -638,3 +743,73 @@ func (or *orchestrator) RewindPins(ctx context.Context, rewind *core.PinRewind)
 	or.events.QueueBatchRewind(rewind.Batch)
 	return rewind, nil
 }
+
+func (or *orchestrator) RewindPinsFromSequence(ctx context.Context, sequence int64, dryRun bool) (*core.PinRewindResult, error) {
+	fb := database.PinQueryFactory.NewFilter(ctx)
+	return or.rewindUndispatchedPins(ctx, fb.And(fb.Gte("sequence", sequence)), dryRun)
+}
+
+func (or *orchestrator) RewindPinsForBatches(ctx context.Context, rewind *core.PinRewindBatches, dryRun bool) (*core.PinRewindResult, error) {
+	if len(rewind.Batches) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgPinRewindBatchesRequired)
+	}
+	batchIDs := make([]driver.Value, len(rewind.Batches))
+	for i, batchID := range rewind.Batches {
+		batchIDs[i] = batchID
+	}
+	fb := database.PinQueryFactory.NewFilter(ctx)
+	return or.rewindUndispatchedPins(ctx, fb.And(fb.In("batch", batchIDs)), dryRun)
+}
+
+// rewindUndispatchedPins finds the undispatched pins matching the filter, and (unless this is a dry-run)
+// rewinds the aggregator to the earliest of them. As the aggregator only ever moves backwards on a rewind,
+// and processes all undispatched pins forwards from that point, this re-evaluates every pin returned.
+func (or *orchestrator) rewindUndispatchedPins(ctx context.Context, filter ffapi.AndFilter, dryRun bool) (*core.PinRewindResult, error) {
+	fb := database.PinQueryFactory.NewFilter(ctx)
+	pins, _, err := or.database().GetPins(ctx, or.namespace.Name, filter.
+		Condition(fb.Eq("dispatched", false)).
+		Sort("sequence").
+		Limit(config.GetUint64(coreconfig.EventAggregatorRewindQueryLimit)))
+	if err != nil {
+		return nil, err
+	}
+	result := &core.PinRewindResult{
+		DryRun:   dryRun,
+		Pins:     len(pins),
+		Batches:  []*fftypes.UUID{},
+		Messages: []*fftypes.UUID{},
+	}
+	if len(pins) == 0 {
+		return result, nil
+	}
+	result.Sequence = pins[0].Sequence
+
+	batchIDs := make([]driver.Value, 0, len(pins))
+	seen := make(map[fftypes.UUID]bool)
+	for _, pin := range pins {
+		if !seen[*pin.Batch] {
+			seen[*pin.Batch] = true
+			result.Batches = append(result.Batches, pin.Batch)
+			batchIDs = append(batchIDs, pin.Batch)
+		}
+	}
+
+	mfb := database.MessageQueryFactory.NewFilter(ctx)
+	msgIDs, err := or.database().GetMessageIDs(ctx, or.namespace.Name, mfb.And(
+		mfb.In("batch", batchIDs),
+		mfb.Neq("state", core.MessageStateConfirmed),
+		mfb.Neq("state", core.MessageStateRejected),
+	).Sort("sequence"))
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range msgIDs {
+		result.Messages = append(result.Messages, &msg.ID)
+	}
+
+	if !dryRun {
+		log.L(ctx).Infof("Rewinding aggregator to pin %d for batch %s (%d undispatched pins)", result.Sequence, pins[0].Batch, len(pins))
+		or.events.QueueBatchRewind(pins[0].Batch)
+	}
+	return result, nil
+}
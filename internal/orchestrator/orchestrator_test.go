@@ -20,19 +20,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/firefly-common/mocks/authmocks"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/keyprovider"
 	"github.com/hyperledger/firefly/mocks/assetmocks"
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/broadcastmocks"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/chartmocks"
 	"github.com/hyperledger/firefly/mocks/contractmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
@@ -46,15 +51,18 @@ import (
 	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/mocks/retentionmocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
 	"github.com/hyperledger/firefly/mocks/spieventsmocks"
 	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/mocks/triggermocks"
 	"github.com/hyperledger/firefly/mocks/txcommonmocks"
 	"github.com/hyperledger/firefly/mocks/txwritermocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -89,6 +97,9 @@ type testOrchestrator struct {
 	mmp *multipartymocks.Manager
 	mds *definitionsmocks.Sender
 	mtw *txwritermocks.Writer
+	mrm *retentionmocks.Manager
+	mch *chartmocks.Manager
+	mtm *triggermocks.Manager
 }
 
 func (tor *testOrchestrator) cleanup(t *testing.T) {
@@ -115,6 +126,9 @@ func (tor *testOrchestrator) cleanup(t *testing.T) {
 	tor.mae.AssertExpectations(t)
 	tor.mdh.AssertExpectations(t)
 	tor.mmp.AssertExpectations(t)
+	tor.mrm.AssertExpectations(t)
+	tor.mch.AssertExpectations(t)
+	tor.mtm.AssertExpectations(t)
 }
 
 func newTestOrchestrator() *testOrchestrator {
@@ -151,6 +165,9 @@ func newTestOrchestrator() *testOrchestrator {
 		mmp: &multipartymocks.Manager{},
 		mds: &definitionsmocks.Sender{},
 		mtw: &txwritermocks.Writer{},
+		mrm: &retentionmocks.Manager{},
+		mch: &chartmocks.Manager{},
+		mtm: &triggermocks.Manager{},
 	}
 	tor.orchestrator.multiparty = tor.mmp
 	tor.orchestrator.data = tor.mdm
@@ -170,6 +187,9 @@ func newTestOrchestrator() *testOrchestrator {
 	tor.orchestrator.txWriter = tor.mtw
 	tor.orchestrator.defhandler = tor.mdh
 	tor.orchestrator.defsender = tor.mds
+	tor.orchestrator.retention = tor.mrm
+	tor.orchestrator.charts = tor.mch
+	tor.orchestrator.triggers = tor.mtm
 	tor.orchestrator.config.Multiparty.Enabled = true
 	tor.orchestrator.config.MaxHistoricalEventScanLimit = 1000
 	tor.orchestrator.plugins = &Plugins{
@@ -203,6 +223,8 @@ func newTestOrchestrator() *testOrchestrator {
 	tor.mmp.On("Name").Return("mock-mp").Maybe()
 	tor.mem.On("ResolveTransportAndCapabilities", mock.Anything, mock.Anything).Return("websockets", &events.Capabilities{}, nil).Maybe()
 	tor.mds.On("Init", mock.Anything).Maybe()
+	tor.mtm.On("Init", mock.Anything, mock.Anything).Maybe()
+	tor.mom.On("CircuitBreakerStatus").Return(nil).Maybe()
 	tor.cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(tor.ctx, 100, 5*time.Minute), nil).Maybe()
 	return tor
 }
@@ -255,6 +277,7 @@ func TestInitOK(t *testing.T) {
 	assert.Equal(t, or.mcm, or.Contracts())
 	assert.Equal(t, or.mnm, or.NetworkMap())
 	assert.Equal(t, or.mmp, or.MultiParty())
+	assert.Equal(t, or.mrm, or.Retention())
 	assert.Equal(t, or.identity, or.Identity())
 }
 
@@ -389,6 +412,24 @@ func TestInitBroadcastComponentFail(t *testing.T) {
 	assert.Regexp(t, "FF10128", err)
 }
 
+func TestInitKeyProviderFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	keyprovider.InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+broadcast:
+  encryption:
+    keys:
+    - id: key1
+      key: bad`))
+	assert.NoError(t, err)
+	or.mbi.On("StartNamespace", mock.Anything, "ns").Return(nil)
+	or.mmp.On("ConfigureContract", mock.Anything, mock.Anything).Return(nil)
+	err = or.initComponents(context.Background())
+	assert.Regexp(t, "FF10550", err)
+}
+
 func TestInitDefSenderComponentFail(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -473,6 +514,59 @@ func TestInitTXWriter(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestInitRetention(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.retention = nil
+	or.config.Multiparty.Enabled = false
+	or.config.Retention.Window = 24 * time.Hour
+	err := or.initManagers(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, or.Retention())
+}
+
+func TestInitCharts(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.charts = nil
+	or.config.Multiparty.Enabled = false
+	err := or.initManagers(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, or.charts)
+}
+
+func TestInitChartsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.charts = nil
+	or.plugins.Database.Plugin = nil
+	or.config.Multiparty.Enabled = false
+	err := or.initManagers(context.Background())
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestInitTriggers(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.triggers = nil
+	or.config.Multiparty.Enabled = false
+	or.mom.On("RegisterHandler", mock.Anything, mock.Anything, []core.OpType{core.OpTypeTriggerInvoke}).Return()
+	err := or.initManagers(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, or.triggers)
+}
+
+func TestInitRetentionFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.retention = nil
+	or.plugins.SharedStorage.Plugin = nil
+	or.config.Multiparty.Enabled = false
+	or.config.Retention.Archive = true
+	err := or.initManagers(context.Background())
+	assert.Regexp(t, "FF10578", err)
+}
+
 func TestStartStopOk(t *testing.T) {
 	coreconfig.Reset()
 	or := newTestOrchestrator()
@@ -485,6 +579,9 @@ func TestStartStopOk(t *testing.T) {
 	or.mom.On("Start").Return(nil)
 	or.mtw.On("Start").Return()
 	or.mam.On("Start").Return(nil)
+	or.mrm.On("Start").Return()
+	or.mch.On("Start").Return()
+	or.mtm.On("Start").Return(nil)
 	or.mba.On("WaitStop").Return(nil)
 	or.mbm.On("WaitStop").Return(nil)
 	or.mdm.On("WaitStop").Return(nil)
@@ -492,6 +589,9 @@ func TestStartStopOk(t *testing.T) {
 	or.mom.On("WaitStop").Return(nil)
 	or.mem.On("WaitStop").Return(nil)
 	or.mtw.On("Close").Return(nil)
+	or.mrm.On("WaitStop").Return()
+	or.mch.On("WaitStop").Return()
+	or.mtm.On("WaitStop").Return()
 	or.mbi.On("StopNamespace", mock.Anything, "ns").Return(nil)
 	or.mti.On("StopNamespace", mock.Anything, "ns").Return(nil)
 	err := or.Start()
@@ -508,6 +608,9 @@ func TestStartStopOk(t *testing.T) {
 	or.mom.On("Start").Return(nil)
 	or.mtw.On("Start").Return()
 	or.mam.On("Start").Return(nil)
+	or.mrm.On("Start").Return()
+	or.mch.On("Start").Return()
+	or.mtm.On("Start").Return(nil)
 	or.mba.On("WaitStop").Return(nil)
 	or.mbm.On("WaitStop").Return(nil)
 	or.mdm.On("WaitStop").Return(nil)
@@ -515,6 +618,9 @@ func TestStartStopOk(t *testing.T) {
 	or.mom.On("WaitStop").Return(nil)
 	or.mem.On("WaitStop").Return(nil)
 	or.mtw.On("Close").Return(nil)
+	or.mrm.On("WaitStop").Return()
+	or.mch.On("WaitStop").Return()
+	or.mtm.On("WaitStop").Return()
 	or.mbi.On("StopNamespace", mock.Anything, "ns").Return(fmt.Errorf("pop"))
 	or.mti.On("StopNamespace", mock.Anything, "ns").Return(fmt.Errorf("pop"))
 	err = or.Start()
@@ -603,11 +709,35 @@ func TestAuthorize(t *testing.T) {
 	or := newTestOrchestrator()
 	auth := &authmocks.Plugin{}
 	auth.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	auth.On("Name").Return("apikey")
 	or.plugins.Auth.Plugin = auth
 	err := or.Authorize(context.Background(), &fftypes.AuthReq{})
 	assert.NoError(t, err)
 }
 
+func TestAuthorizeFail(t *testing.T) {
+	or := newTestOrchestrator()
+	auth := &authmocks.Plugin{}
+	auth.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	or.plugins.Auth.Plugin = auth
+	err := or.Authorize(context.Background(), &fftypes.AuthReq{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestAuthorizeBasicIdentity(t *testing.T) {
+	or := newTestOrchestrator()
+	auth := &authmocks.Plugin{}
+	auth.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	auth.On("Name").Return("basic")
+	or.plugins.Auth.Plugin = auth
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth("user1", "secret")
+	ctx, caller := core.WithAuthenticatedCaller(context.Background())
+	err := or.Authorize(ctx, &fftypes.AuthReq{Header: req.Header})
+	assert.NoError(t, err)
+	assert.Equal(t, "basic:user1", caller.Identity)
+}
+
 func TestAuthorizeNoPlugin(t *testing.T) {
 	or := newTestOrchestrator()
 	err := or.Authorize(context.Background(), &fftypes.AuthReq{})
@@ -651,6 +781,122 @@ func TestRewindPinsNotFound(t *testing.T) {
 	assert.Regexp(t, "FF10109", err)
 }
 
+func TestRewindPinsFromSequence(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	batch1 := fftypes.NewUUID()
+	batch2 := fftypes.NewUUID()
+	msg1 := fftypes.NewUUID()
+
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == "( sequence >= 100 ) && ( dispatched == false ) sort=sequence limit=1000"
+	})).Return([]*core.Pin{
+		{Sequence: 101, Batch: batch1},
+		{Sequence: 102, Batch: batch2},
+		{Sequence: 103, Batch: batch1},
+	}, nil, nil)
+	or.mdi.On("GetMessageIDs", mock.Anything, "ns", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("( batch IN ['%s','%s'] ) && ( state != 'confirmed' ) && ( state != 'rejected' ) sort=sequence", batch1, batch2)
+	})).Return([]*core.IDAndSequence{{ID: *msg1}}, nil)
+	or.mem.On("QueueBatchRewind", batch1).Return()
+
+	result, err := or.RewindPinsFromSequence(context.Background(), 100, false)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.PinRewindResult{
+		Sequence: 101,
+		Pins:     3,
+		Batches:  []*fftypes.UUID{batch1, batch2},
+		Messages: []*fftypes.UUID{msg1},
+	}, result)
+}
+
+func TestRewindPinsFromSequenceDryRun(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	batch1 := fftypes.NewUUID()
+
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return([]*core.Pin{
+		{Sequence: 101, Batch: batch1},
+	}, nil, nil)
+	or.mdi.On("GetMessageIDs", mock.Anything, "ns", mock.Anything).Return([]*core.IDAndSequence{}, nil)
+
+	result, err := or.RewindPinsFromSequence(context.Background(), 100, true)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, int64(101), result.Sequence)
+	assert.Equal(t, []*fftypes.UUID{batch1}, result.Batches)
+	or.mem.AssertNotCalled(t, "QueueBatchRewind", mock.Anything)
+}
+
+func TestRewindPinsFromSequenceNoPins(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return([]*core.Pin{}, nil, nil)
+
+	result, err := or.RewindPinsFromSequence(context.Background(), 100, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), result.Sequence)
+	assert.Empty(t, result.Batches)
+	assert.Empty(t, result.Messages)
+}
+
+func TestRewindPinsFromSequenceGetPinsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.RewindPinsFromSequence(context.Background(), 100, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindPinsFromSequenceGetMessagesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return([]*core.Pin{
+		{Sequence: 101, Batch: fftypes.NewUUID()},
+	}, nil, nil)
+	or.mdi.On("GetMessageIDs", mock.Anything, "ns", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.RewindPinsFromSequence(context.Background(), 100, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindPinsForBatches(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	batch1 := fftypes.NewUUID()
+	batch2 := fftypes.NewUUID()
+
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("( batch IN ['%s','%s'] ) && ( dispatched == false ) sort=sequence limit=1000", batch1, batch2)
+	})).Return([]*core.Pin{
+		{Sequence: 50, Batch: batch2},
+	}, nil, nil)
+	or.mdi.On("GetMessageIDs", mock.Anything, "ns", mock.Anything).Return([]*core.IDAndSequence{}, nil)
+	or.mem.On("QueueBatchRewind", batch2).Return()
+
+	result, err := or.RewindPinsForBatches(context.Background(), &core.PinRewindBatches{
+		Batches: []*fftypes.UUID{batch1, batch2},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), result.Sequence)
+	assert.Equal(t, []*fftypes.UUID{batch2}, result.Batches)
+}
+
+func TestRewindPinsForBatchesNoBatches(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.RewindPinsForBatches(context.Background(), &core.PinRewindBatches{}, false)
+	assert.Regexp(t, "FF10592", err)
+}
+
 func TestRewindPinsBatch(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -0,0 +1,124 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type readinessCheck struct {
+	status *core.PluginReadiness
+	ping   func(ctx context.Context) error
+}
+
+type readinessResult struct {
+	index   int
+	latency time.Duration
+	err     error
+}
+
+func (or *orchestrator) readinessChecks() []*readinessCheck {
+	checks := make([]*readinessCheck, 0)
+	add := func(category, name, pluginType string, critical bool, ping func(ctx context.Context) error) {
+		checks = append(checks, &readinessCheck{
+			status: &core.PluginReadiness{
+				Category:   category,
+				Name:       name,
+				PluginType: pluginType,
+				Critical:   critical,
+			},
+			ping: ping,
+		})
+	}
+	if p := or.plugins.Database; p.Plugin != nil {
+		add("database", p.Name, p.Plugin.Name(), true, p.Plugin.Ping)
+	}
+	if p := or.plugins.Blockchain; p.Plugin != nil {
+		add("blockchain", p.Name, p.Plugin.Name(), true, p.Plugin.Ping)
+	}
+	if p := or.plugins.DataExchange; p.Plugin != nil {
+		add("dataexchange", p.Name, p.Plugin.Name(), true, p.Plugin.Ping)
+	}
+	// A token connector being down only affects the pools it serves, so does not make the namespace unready
+	for _, p := range or.plugins.Tokens {
+		add("tokens", p.Name, p.Plugin.Name(), false, p.Plugin.Ping)
+	}
+	return checks
+}
+
+// GetReadiness pings each plugin of the namespace concurrently. Each check is bounded by the readiness timeout,
+// and a plugin that has not responded by then is reported as not ready without waiting any longer for it.
+func (or *orchestrator) GetReadiness(ctx context.Context) *core.NamespaceReadiness {
+	timeout := config.GetDuration(coreconfig.OrchestratorReadinessTimeout)
+	checks := or.readinessChecks()
+
+	results := make(chan *readinessResult, len(checks))
+	for i, c := range checks {
+		go func(index int, ping func(ctx context.Context) error) {
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			err := ping(pingCtx)
+			results <- &readinessResult{index: index, latency: time.Since(start), err: err}
+		}(i, c.ping)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	pending := len(checks)
+	for pending > 0 {
+		select {
+		case res := <-results:
+			status := checks[res.index].status
+			status.Latency = fftypes.FFDuration(res.latency)
+			if res.err != nil {
+				status.Error = res.err.Error()
+			} else {
+				status.Ready = true
+			}
+			pending--
+		case <-timer.C:
+			for _, c := range checks {
+				if !c.status.Ready && c.status.Error == "" {
+					c.status.Latency = fftypes.FFDuration(timeout)
+					c.status.Error = i18n.NewError(ctx, coremsgs.MsgPluginReadinessTimeout, timeout).Error()
+				}
+			}
+			pending = 0
+		}
+	}
+
+	readiness := &core.NamespaceReadiness{
+		Ready:   true,
+		Plugins: make([]*core.PluginReadiness, len(checks)),
+	}
+	for i, c := range checks {
+		readiness.Plugins[i] = c.status
+		if c.status.Critical && !c.status.Ready {
+			readiness.Ready = false
+		}
+	}
+	return readiness
+}
@@ -0,0 +1,116 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetReadiness(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("Ping", mock.Anything).Return(nil)
+	or.mbi.On("Ping", mock.Anything).Return(nil)
+	or.mdx.On("Ping", mock.Anything).Return(nil)
+	or.mti.On("Ping", mock.Anything).Return(nil)
+
+	readiness := or.GetReadiness(or.ctx)
+	assert.True(t, readiness.Ready)
+	assert.Len(t, readiness.Plugins, 4)
+	assert.Equal(t, "database", readiness.Plugins[0].Category)
+	assert.Equal(t, "mock-di", readiness.Plugins[0].PluginType)
+	assert.True(t, readiness.Plugins[0].Critical)
+	assert.Equal(t, "blockchain", readiness.Plugins[1].Category)
+	assert.Equal(t, "dataexchange", readiness.Plugins[2].Category)
+	assert.Equal(t, "tokens", readiness.Plugins[3].Category)
+	assert.Equal(t, "token", readiness.Plugins[3].Name)
+	assert.False(t, readiness.Plugins[3].Critical)
+	for _, p := range readiness.Plugins {
+		assert.True(t, p.Ready)
+		assert.Empty(t, p.Error)
+	}
+}
+
+func TestGetReadinessCriticalPluginDown(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("Ping", mock.Anything).Return(nil)
+	or.mbi.On("Ping", mock.Anything).Return(fmt.Errorf("pop"))
+	or.mdx.On("Ping", mock.Anything).Return(nil)
+	or.mti.On("Ping", mock.Anything).Return(nil)
+
+	readiness := or.GetReadiness(or.ctx)
+	assert.False(t, readiness.Ready)
+	assert.False(t, readiness.Plugins[1].Ready)
+	assert.Equal(t, "pop", readiness.Plugins[1].Error)
+}
+
+func TestGetReadinessTokensDown(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("Ping", mock.Anything).Return(nil)
+	or.mbi.On("Ping", mock.Anything).Return(nil)
+	or.mdx.On("Ping", mock.Anything).Return(nil)
+	or.mti.On("Ping", mock.Anything).Return(fmt.Errorf("pop"))
+
+	readiness := or.GetReadiness(or.ctx)
+	assert.True(t, readiness.Ready)
+	assert.False(t, readiness.Plugins[3].Ready)
+	assert.Equal(t, "pop", readiness.Plugins[3].Error)
+}
+
+func TestGetReadinessTimeout(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	config.Set(coreconfig.OrchestratorReadinessTimeout, "10ms")
+
+	// The plugin ignores the context, so the check must give up on it without waiting
+	unblock := make(chan struct{})
+	defer close(unblock)
+	or.mdi.On("Ping", mock.Anything).Return(nil)
+	or.mbi.On("Ping", mock.Anything).Run(func(args mock.Arguments) {
+		<-unblock
+	}).Return(nil)
+	or.mdx.On("Ping", mock.Anything).Return(nil)
+	or.mti.On("Ping", mock.Anything).Return(nil)
+
+	readiness := or.GetReadiness(or.ctx)
+	assert.False(t, readiness.Ready)
+	assert.True(t, readiness.Plugins[0].Ready)
+	assert.False(t, readiness.Plugins[1].Ready)
+	assert.Regexp(t, "FF10512", readiness.Plugins[1].Error)
+}
+
+func TestGetReadinessNoPlugins(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.plugins = &Plugins{}
+
+	readiness := or.GetReadiness(context.Background())
+	assert.True(t, readiness.Ready)
+	assert.Empty(t, readiness.Plugins)
+}
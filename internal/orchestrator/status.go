@@ -20,18 +20,21 @@ import (
 	"context"
 	"database/sql/driver"
 	"encoding/json"
+	"sort"
 
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
 
-func (or *orchestrator) getPlugins() core.NamespaceStatusPlugins {
+// Status summarizes the plugins bound to a namespace. It does not require the namespace to be started.
+func (p *Plugins) Status() core.NamespaceStatusPlugins {
 	// Plugins can have more than one name, so they must be iterated over
 	tokensArray := make([]*core.NamespaceStatusPlugin, 0)
-	for _, plugin := range or.plugins.Tokens {
+	for _, plugin := range p.Tokens {
 		tokensArray = append(tokensArray, &core.NamespaceStatusPlugin{
 			Name:       plugin.Name,
 			PluginType: plugin.Plugin.Name(),
@@ -39,48 +42,72 @@ func (or *orchestrator) getPlugins() core.NamespaceStatusPlugins {
 	}
 
 	blockchainsArray := make([]*core.NamespaceStatusPlugin, 0)
-	if or.plugins.Blockchain.Plugin != nil {
-		blockchainsArray = append(blockchainsArray, &core.NamespaceStatusPlugin{
-			Name:       or.plugins.Blockchain.Name,
-			PluginType: or.plugins.Blockchain.Plugin.Name(),
-		})
+	if p.Blockchain.Plugin != nil {
+		blockchainPlugin := &core.NamespaceStatusPlugin{
+			Name:       p.Blockchain.Name,
+			PluginType: p.Blockchain.Plugin.Name(),
+		}
+		if csr, ok := p.Blockchain.Plugin.(blockchain.ConnectorStatusReporter); ok {
+			blockchainPlugin.Connector = csr.ConnectorStatus()
+		}
+		blockchainsArray = append(blockchainsArray, blockchainPlugin)
 	}
 
 	databasesArray := make([]*core.NamespaceStatusPlugin, 0)
-	if or.plugins.Database.Plugin != nil {
+	if p.Database.Plugin != nil {
 		databasesArray = append(databasesArray, &core.NamespaceStatusPlugin{
-			Name:       or.plugins.Database.Name,
-			PluginType: or.plugins.Database.Plugin.Name(),
+			Name:       p.Database.Name,
+			PluginType: p.Database.Plugin.Name(),
 		})
 	}
 
 	sharedstorageArray := make([]*core.NamespaceStatusPlugin, 0)
-	if or.plugins.SharedStorage.Plugin != nil {
+	if p.SharedStorage.Plugin != nil {
 		sharedstorageArray = append(sharedstorageArray, &core.NamespaceStatusPlugin{
-			Name:       or.plugins.SharedStorage.Name,
-			PluginType: or.plugins.SharedStorage.Plugin.Name(),
+			Name:       p.SharedStorage.Name,
+			PluginType: p.SharedStorage.Plugin.Name(),
 		})
 	}
 
 	dataexchangeArray := make([]*core.NamespaceStatusPlugin, 0)
-	if or.plugins.DataExchange.Plugin != nil {
+	if p.DataExchange.Plugin != nil {
 		dataexchangeArray = append(dataexchangeArray, &core.NamespaceStatusPlugin{
-			Name:       or.plugins.DataExchange.Name,
-			PluginType: or.plugins.DataExchange.Plugin.Name(),
+			Name:       p.DataExchange.Name,
+			PluginType: p.DataExchange.Plugin.Name(),
 		})
 	}
 
+	eventsArray := make([]*core.NamespaceStatusPlugin, 0, len(p.Events))
+	for name := range p.Events {
+		eventsArray = append(eventsArray, &core.NamespaceStatusPlugin{
+			PluginType: name,
+		})
+	}
+	sort.Slice(eventsArray, func(i, j int) bool { return eventsArray[i].PluginType < eventsArray[j].PluginType })
+
 	return core.NamespaceStatusPlugins{
 		Blockchain:    blockchainsArray,
 		Database:      databasesArray,
 		SharedStorage: sharedstorageArray,
 		DataExchange:  dataexchangeArray,
-		Events:        or.events.GetPlugins(),
+		Events:        eventsArray,
 		Tokens:        tokensArray,
 		Identity:      []*core.NamespaceStatusPlugin{},
 	}
 }
 
+func (or *orchestrator) getPlugins() core.NamespaceStatusPlugins {
+	plugins := or.plugins.Status()
+	// The event manager reports the transports that have been initialized for delivery
+	plugins.Events = or.events.GetPlugins()
+	return plugins
+}
+
+func (or *orchestrator) GetPluginsStatus(ctx context.Context) *core.NamespaceStatusPlugins {
+	plugins := or.getPlugins()
+	return &plugins
+}
+
 func (or *orchestrator) GetStatus(ctx context.Context) (status *core.NamespaceStatus, err error) {
 
 	status = &core.NamespaceStatus{
@@ -91,6 +118,15 @@ func (or *orchestrator) GetStatus(ctx context.Context) (status *core.NamespaceSt
 		},
 	}
 
+	for pool, connector := range or.config.TokenPoolConnectors {
+		status.TokenPoolConnectors = append(status.TokenPoolConnectors, &core.TokenPoolConnectorBinding{
+			Pool:      pool,
+			Connector: connector,
+		})
+	}
+	sort.Slice(status.TokenPoolConnectors, func(i, j int) bool { return status.TokenPoolConnectors[i].Pool < status.TokenPoolConnectors[j].Pool })
+	status.CircuitBreakers = or.operations.CircuitBreakerStatus()
+
 	if or.config.Multiparty.Enabled {
 		status.Node = &core.NamespaceStatusNode{Name: or.config.Multiparty.Node.Name}
 		status.Org = &core.NamespaceStatusOrg{Name: or.config.Multiparty.Org.Name}
@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -243,6 +244,66 @@ func TestGetStatusUnregistered(t *testing.T) {
 
 }
 
+func TestGetStatusTokenPoolConnectors(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.config.Multiparty.Enabled = false
+	or.config.TokenPoolConnectors = map[string]string{
+		"pool2": "erc1155",
+		"pool1": "erc20_erc721",
+	}
+
+	or.mem.On("GetPlugins").Return(mockEventPlugins)
+
+	status, err := or.GetStatus(or.ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*core.TokenPoolConnectorBinding{
+		{Pool: "pool1", Connector: "erc20_erc721"},
+		{Pool: "pool2", Connector: "erc1155"},
+	}, status.TokenPoolConnectors)
+}
+
+type testConnectorStatusReporter struct {
+	*blockchainmocks.Plugin
+}
+
+func (tr *testConnectorStatusReporter) ConnectorStatus() *core.ConnectorStatus {
+	return &core.ConnectorStatus{Active: "http://ethconnect2"}
+}
+
+func TestGetPluginsStatusConnector(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.plugins.Blockchain.Plugin = &testConnectorStatusReporter{Plugin: or.mbi}
+	or.mem.On("GetPlugins").Return(mockEventPlugins)
+
+	plugins := or.GetPluginsStatus(or.ctx)
+	assert.Len(t, plugins.Blockchain, 1)
+	assert.Equal(t, "mock-bi", plugins.Blockchain[0].PluginType)
+	assert.Equal(t, "http://ethconnect2", plugins.Blockchain[0].Connector.Active)
+	assert.Equal(t, mockEventPlugins, plugins.Events)
+}
+
+func TestGetStatusCircuitBreakers(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.config.Multiparty.Enabled = false
+	breakers := []*core.CircuitBreakerStatus{
+		{Plugin: "ethereum", State: core.CircuitBreakerStateOpen, ConsecutiveFailures: 5, Opened: fftypes.Now(), LastError: "pop"},
+	}
+	or.mom.On("CircuitBreakerStatus").Unset()
+	or.mom.On("CircuitBreakerStatus").Return(breakers)
+	or.mem.On("GetPlugins").Return(mockEventPlugins)
+
+	status, err := or.GetStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, breakers, status.CircuitBreakers)
+}
+
 func TestGetStatusOrgOnlyRegistered(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
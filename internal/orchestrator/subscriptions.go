@@ -27,15 +27,27 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/bridge"
 	"github.com/hyperledger/firefly/internal/events/system"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
+// subscriptionPreviewDefaultLimit is the number of matching events a preview returns, if no limit is requested
+const subscriptionPreviewDefaultLimit = 25
+
 func (or *orchestrator) CreateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error) {
+	if subDef.Transport == bridge.BridgeTransport {
+		// Bridges are created with the bridges API, which checks the caller can broadcast into the target namespace
+		return nil, i18n.NewError(ctx, coremsgs.MsgBridgeTransportInternal)
+	}
 	return or.createUpdateSubscription(ctx, subDef, true)
 }
 
 func (or *orchestrator) CreateUpdateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error) {
+	if subDef.Transport == bridge.BridgeTransport {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBridgeTransportInternal)
+	}
 	return or.createUpdateSubscription(ctx, subDef, false)
 }
 
@@ -138,6 +150,69 @@ func (or *orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 	return subWithStatus, nil
 }
 
+func (or *orchestrator) GetSubscriptionDeadLetters(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return or.database().GetDeadLetters(ctx, or.namespace.Name, filter.Condition(filter.Builder().Eq("subscription", u)))
+}
+
+func (or *orchestrator) ReplaySubscriptionDeadLetter(ctx context.Context, id, deadLetterID string) error {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+	dlID, err := fftypes.ParseUUID(ctx, deadLetterID)
+	if err != nil {
+		return err
+	}
+	return or.events.ReplayDeadLetter(ctx, u, dlID)
+}
+
+func (or *orchestrator) ReplaySubscription(ctx context.Context, id string, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.ReplaySubscription(ctx, u, input)
+}
+
+// PreviewSubscription tests a subscription definition against the most recent events, up to the configured maximum
+// scan length. Filters that are invalid fail in the same way they would when the subscription is created.
+func (or *orchestrator) PreviewSubscription(ctx context.Context, input *core.SubscriptionPreviewInput) (*core.SubscriptionPreview, error) {
+	maxScan := config.GetInt(coreconfig.SubscriptionMaxHistoricalEventScanLength)
+	limit := input.Limit
+	if limit == 0 {
+		limit = subscriptionPreviewDefaultLimit
+	}
+	if limit < 0 || limit > maxScan {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionPreviewLimitInvalid, maxScan)
+	}
+
+	filter := database.EventQueryFactory.NewFilter(ctx).And()
+	filter.Sort("sequence").Descending().Limit(uint64(maxScan))
+	recentEvents, _, err := or.GetEventsWithReferences(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	matchingEvents, err := or.events.FilterHistoricalEventsOnSubscription(ctx, recentEvents, &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{Namespace: or.namespace.Name},
+		Filter:          input.Filter,
+		Options:         input.Options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matchingEvents) > limit {
+		matchingEvents = matchingEvents[:limit]
+	}
+	return &core.SubscriptionPreview{
+		Scanned: len(recentEvents),
+		Events:  matchingEvents,
+	}, nil
+}
+
 func (or *orchestrator) GetSubscriptionEventsHistorical(ctx context.Context, subscription *core.Subscription, filter ffapi.AndFilter, startSequence int, endSequence int) ([]*core.EnrichedEvent, *ffapi.FilterResult, error) {
 	if startSequence != -1 && endSequence != -1 && endSequence-startSequence > config.GetInt(coreconfig.SubscriptionMaxHistoricalEventScanLength) {
 		return nil, nil, i18n.NewError(ctx, coremsgs.MsgMaxSubscriptionEventScanLimitBreached, startSequence, endSequence)
@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/events/bridge"
 	"github.com/hyperledger/firefly/internal/events/system"
 	"github.com/hyperledger/firefly/internal/events/webhooks"
 	"github.com/hyperledger/firefly/mocks/eventmocks"
@@ -58,6 +60,22 @@ func TestCreateSubscriptionSystemTransport(t *testing.T) {
 	assert.Regexp(t, "FF10266", err)
 }
 
+func TestCreateSubscriptionBridgeTransport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	sub := &core.Subscription{
+		Transport: bridge.BridgeTransport,
+		SubscriptionRef: core.SubscriptionRef{
+			Name: "sub1",
+		},
+	}
+	_, err := or.CreateSubscription(or.ctx, sub)
+	assert.Regexp(t, "FF10662", err)
+	_, err = or.CreateUpdateSubscription(or.ctx, sub)
+	assert.Regexp(t, "FF10662", err)
+}
+
 func TestCreateSubscriptionBadBatchTimeout(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -299,6 +317,73 @@ func TestGetSubscriptionDefsByIDBadID(t *testing.T) {
 	assert.Regexp(t, "FF00138", err)
 }
 
+func TestGetSubscriptionDeadLetters(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	u := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetters", mock.Anything, "ns", mock.Anything).Return([]*core.DeadLetter{}, nil, nil)
+	fb := database.DeadLetterQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	_, _, err := or.GetSubscriptionDeadLetters(context.Background(), u.String(), f)
+	assert.NoError(t, err)
+}
+
+func TestGetSubscriptionDeadLettersBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	fb := database.DeadLetterQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSubscriptionDeadLetters(context.Background(), "", fb.And())
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestReplaySubscriptionDeadLetter(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	dlID := fftypes.NewUUID()
+	or.mem.On("ReplayDeadLetter", mock.Anything, subID, dlID).Return(nil)
+	err := or.ReplaySubscriptionDeadLetter(context.Background(), subID.String(), dlID.String())
+	assert.NoError(t, err)
+}
+
+func TestReplaySubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	input := &core.SubscriptionReplayInput{}
+	or.mem.On("ReplaySubscription", mock.Anything, subID, input).Return(&core.SubscriptionReplay{}, nil)
+	_, err := or.ReplaySubscription(context.Background(), subID.String(), input)
+	assert.NoError(t, err)
+}
+
+func TestReplaySubscriptionBadSubID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.ReplaySubscription(context.Background(), "", &core.SubscriptionReplayInput{})
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestReplaySubscriptionDeadLetterBadSubID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	err := or.ReplaySubscriptionDeadLetter(context.Background(), "", fftypes.NewUUID().String())
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestReplaySubscriptionDeadLetterBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	err := or.ReplaySubscriptionDeadLetter(context.Background(), fftypes.NewUUID().String(), "")
+	assert.Regexp(t, "FF00138", err)
+}
+
 func TestGetSGetSubscriptionsByIDWithStatus(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -613,3 +698,61 @@ func TestGetHistoricalEventsForSubscriptionNoStartOrEndSequenceFails(t *testing.
 	_, _, err := or.GetSubscriptionEventsHistorical(context.Background(), &core.Subscription{}, filter, -1, -1)
 	assert.NotNil(t, err)
 }
+
+func TestPreviewSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	baseEvents, enrichedEvents := generateFakeEvents(50)
+
+	or.mdi.On("GetEvents", mock.Anything, "ns", mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		info, _ := filter.Finalize()
+		return info.Limit == 1000 && info.Sort[0].Field == "sequence" && info.Sort[0].Descending
+	})).Return(baseEvents, nil, nil)
+	or.mem.On("EnrichEvents", mock.Anything, baseEvents).Return(enrichedEvents, nil)
+	or.mem.On("FilterHistoricalEventsOnSubscription", mock.Anything, enrichedEvents, mock.MatchedBy(func(sub *core.Subscription) bool {
+		return sub.Namespace == "ns" && sub.Filter.Topic == "Topic1"
+	})).Return(enrichedEvents[10:], nil)
+
+	preview, err := or.PreviewSubscription(context.Background(), &core.SubscriptionPreviewInput{
+		Filter: core.SubscriptionFilter{Topic: "Topic1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 50, preview.Scanned)
+	assert.Len(t, preview.Events, 25)
+	assert.Equal(t, enrichedEvents[10], preview.Events[0])
+}
+
+func TestPreviewSubscriptionBadLimit(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.PreviewSubscription(context.Background(), &core.SubscriptionPreviewInput{Limit: 1001})
+	assert.Regexp(t, "FF10639", err)
+}
+
+func TestPreviewSubscriptionGetEventsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetEvents", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.PreviewSubscription(context.Background(), &core.SubscriptionPreviewInput{Limit: 5})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPreviewSubscriptionBadFilter(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	baseEvents, enrichedEvents := generateFakeEvents(5)
+
+	or.mdi.On("GetEvents", mock.Anything, "ns", mock.Anything).Return(baseEvents, nil, nil)
+	or.mem.On("EnrichEvents", mock.Anything, baseEvents).Return(enrichedEvents, nil)
+	or.mem.On("FilterHistoricalEventsOnSubscription", mock.Anything, enrichedEvents, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.PreviewSubscription(context.Background(), &core.SubscriptionPreviewInput{
+		Filter: core.SubscriptionFilter{Topic: "[["},
+	})
+	assert.EqualError(t, err, "pop")
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"sort"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -62,6 +63,43 @@ func txBlockchainEventStatus(event *core.BlockchainEvent) *core.TransactionStatu
 	}
 }
 
+// txBatchPinsStatus summarizes the pins of a batch, which are only complete once every pin has been dispatched
+// to process the messages of the batch in order
+func (or *orchestrator) txBatchPinsStatus(ctx context.Context, batch *core.BatchPersisted) (*core.TransactionStatusDetails, error) {
+	f := database.PinQueryFactory.NewFilter(ctx)
+	pins, _, err := or.database().GetPins(ctx, or.namespace.Name, f.Eq("batch", batch.ID))
+	if err != nil {
+		return nil, err
+	}
+	if len(pins) == 0 {
+		return pendingPlaceholder(core.TransactionStatusTypePins), nil
+	}
+	details := &core.TransactionStatusDetails{
+		Type:   core.TransactionStatusTypePins,
+		Status: core.OpStatusSucceeded,
+		ID:     batch.ID,
+	}
+	dispatched := 0
+	for _, pin := range pins {
+		if !pin.Dispatched {
+			details.Status = core.OpStatusPending
+			continue
+		}
+		dispatched++
+		if details.Timestamp == nil || (pin.Created != nil && pin.Created.Time().After(*details.Timestamp.Time())) {
+			details.Timestamp = pin.Created
+		}
+	}
+	if details.Status == core.OpStatusPending {
+		details.Timestamp = nil
+	}
+	details.Info = fftypes.JSONObject{
+		"total":      len(pins),
+		"dispatched": dispatched,
+	}
+	return details, nil
+}
+
 func (or *orchestrator) GetTransactionStatus(ctx context.Context, id string) (*core.TransactionStatus, error) {
 	result := &core.TransactionStatus{
 		Status:  core.OpStatusSucceeded,
@@ -124,6 +162,12 @@ func (or *orchestrator) GetTransactionStatus(ctx context.Context, id string) (*c
 				Timestamp: batches[0].Confirmed,
 				ID:        batches[0].ID,
 			})
+			pinsStatus, err := or.txBatchPinsStatus(ctx, batches[0])
+			if err != nil {
+				return nil, err
+			}
+			result.Details = append(result.Details, pinsStatus)
+			updateStatus(result, pinsStatus.Status)
 		}
 
 	case core.TransactionTypeTokenPool:
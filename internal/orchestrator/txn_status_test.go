@@ -79,6 +79,10 @@ func TestGetTransactionStatusBatchPinSuccess(t *testing.T) {
 	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return(ops, nil, nil)
 	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return(events, nil, nil)
 	or.mdi.On("GetBatches", mock.Anything, "ns", mock.Anything).Return(batches, nil, nil)
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return([]*core.Pin{
+		{Batch: batches[0].ID, Dispatched: true, Created: fftypes.UnixTime(3)},
+		{Batch: batches[0].ID, Dispatched: true, Created: fftypes.UnixTime(2)},
+	}, nil, nil)
 
 	status, err := or.GetTransactionStatus(context.Background(), txID.String())
 	assert.NoError(t, err)
@@ -86,6 +90,13 @@ func TestGetTransactionStatusBatchPinSuccess(t *testing.T) {
 	expectedStatus := compactJSON(`{
 		"status": "Succeeded",
 		"details": [
+			{
+				"type": "Pins",
+				"status": "Succeeded",
+				"timestamp": "1970-01-01T00:00:03Z",
+				"id": "` + batches[0].ID.String() + `",
+				"info": {"dispatched": 2, "total": 2}
+			},
 			{
 				"type": "Batch",
 				"subtype": "broadcast",
@@ -117,6 +128,122 @@ func TestGetTransactionStatusBatchPinSuccess(t *testing.T) {
 	or.mdi.AssertExpectations(t)
 }
 
+func TestGetTransactionStatusBatchPinsPending(t *testing.T) {
+	or := newTestOrchestrator()
+
+	txID := fftypes.NewUUID()
+	tx := &core.Transaction{
+		Namespace: "ns1",
+		Type:      core.TransactionTypeBatchPin,
+	}
+	events := []*core.BlockchainEvent{
+		{
+			Namespace: "ns1",
+			Name:      "BatchPin",
+			ID:        fftypes.NewUUID(),
+			Timestamp: fftypes.UnixTime(1),
+		},
+	}
+	batches := []*core.BatchPersisted{
+		{
+			BatchHeader: core.BatchHeader{
+				Namespace: "ns1",
+				ID:        fftypes.NewUUID(),
+				Type:      core.BatchTypePrivate,
+			},
+			Confirmed: fftypes.UnixTime(2),
+		},
+	}
+
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(tx, nil)
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return(events, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, "ns", mock.Anything).Return(batches, nil, nil)
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return([]*core.Pin{
+		{Batch: batches[0].ID, Dispatched: true, Created: fftypes.UnixTime(3)},
+		{Batch: batches[0].ID, Dispatched: false, Created: fftypes.UnixTime(3)},
+	}, nil, nil)
+
+	status, err := or.GetTransactionStatus(context.Background(), txID.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, core.OpStatusPending, status.Status)
+	assert.Equal(t, core.TransactionStatusTypePins, status.Details[0].Type)
+	assert.Equal(t, core.OpStatusPending, status.Details[0].Status)
+	assert.Nil(t, status.Details[0].Timestamp)
+	assert.Equal(t, fftypes.JSONObject{"dispatched": 1, "total": 2}, status.Details[0].Info)
+
+	or.mdi.AssertExpectations(t)
+}
+
+func TestGetTransactionStatusBatchPinsNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+
+	txID := fftypes.NewUUID()
+	tx := &core.Transaction{
+		Namespace: "ns1",
+		Type:      core.TransactionTypeBatchPin,
+	}
+	batches := []*core.BatchPersisted{
+		{
+			BatchHeader: core.BatchHeader{
+				Namespace: "ns1",
+				ID:        fftypes.NewUUID(),
+				Type:      core.BatchTypeBroadcast,
+			},
+		},
+	}
+
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(tx, nil)
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, "ns", mock.Anything).Return(batches, nil, nil)
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return([]*core.Pin{}, nil, nil)
+
+	status, err := or.GetTransactionStatus(context.Background(), txID.String())
+	assert.NoError(t, err)
+
+	assert.Equal(t, core.OpStatusPending, status.Status)
+	assert.Len(t, status.Details, 3)
+	for _, detail := range status.Details {
+		if detail.Type == core.TransactionStatusTypePins {
+			assert.Equal(t, core.OpStatusPending, detail.Status)
+		}
+	}
+
+	or.mdi.AssertExpectations(t)
+}
+
+func TestGetTransactionStatusBatchPinsFail(t *testing.T) {
+	or := newTestOrchestrator()
+
+	txID := fftypes.NewUUID()
+	tx := &core.Transaction{
+		Namespace: "ns1",
+		Type:      core.TransactionTypeBatchPin,
+	}
+	batches := []*core.BatchPersisted{
+		{
+			BatchHeader: core.BatchHeader{
+				Namespace: "ns1",
+				ID:        fftypes.NewUUID(),
+				Type:      core.BatchTypeBroadcast,
+			},
+		},
+	}
+
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(tx, nil)
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	or.mdi.On("GetBlockchainEvents", mock.Anything, "ns", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+	or.mdi.On("GetBatches", mock.Anything, "ns", mock.Anything).Return(batches, nil, nil)
+	or.mdi.On("GetPins", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetTransactionStatus(context.Background(), txID.String())
+	assert.EqualError(t, err, "pop")
+
+	or.mdi.AssertExpectations(t)
+}
+
 func TestGetTransactionStatusBatchPinFail(t *testing.T) {
 	or := newTestOrchestrator()
 
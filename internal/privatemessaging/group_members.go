@@ -0,0 +1,153 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// CreateGroup resolves a group from a list of members in the same way as a private message, and initializes
+// it with the other members if it does not exist already
+func (pm *privateMessaging) CreateGroup(ctx context.Context, in *core.GroupCreate) (*core.Group, error) {
+	if len(in.Members) == 0 {
+		return nil, i18n.NewError(ctx, i18n.MsgGroupMustHaveMembers)
+	}
+	if err := pm.identity.ResolveInputSigningIdentity(ctx, &in.SignerRef); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgAuthorInvalid)
+	}
+
+	group, isNew, err := pm.findOrGenerateGroup(ctx, pm.namespace.NetworkName, &in.InputGroup)
+	if err != nil {
+		return nil, err
+	}
+	if isNew {
+		err = pm.groupInit(ctx, &in.SignerRef, group)
+	}
+	return group, err
+}
+
+// UpdateGroupMembers creates a new generation of a group with members added and removed, linked to the latest
+// generation of the group. Messages sent to any earlier generation are sent to the new generation, once the
+// new generation is confirmed. The signer on the local node must be a member, and must remain one.
+func (pm *privateMessaging) UpdateGroupMembers(ctx context.Context, hash string, in *core.GroupMembershipUpdate) (*core.Group, error) {
+	current, err := pm.GetGroupByID(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupNotFound, hash)
+	}
+	if current, err = pm.latestGroupGeneration(ctx, current); err != nil {
+		return nil, err
+	}
+	if err := pm.identity.ResolveInputSigningIdentity(ctx, &in.SignerRef); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgAuthorInvalid)
+	}
+
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signer := &core.Member{Identity: in.Author, Node: localNode.ID}
+	if !isMember(current.Members, signer) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupUpdateNotMember, in.Author, current.Hash)
+	}
+
+	remaining, err := pm.removeGroupMembers(ctx, current, in.Remove)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember(remaining, signer) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupUpdateNotMember, in.Author, current.Hash)
+	}
+	members := make([]core.MemberInput, 0, len(remaining)+len(in.Add))
+	for _, m := range remaining {
+		members = append(members, core.MemberInput{Identity: m.Identity, Node: m.Node.String()})
+	}
+	members = append(members, in.Add...)
+	gi, err := pm.getRecipients(ctx, current.Namespace, &core.InputGroup{Name: current.Name, Members: members})
+	if err != nil {
+		return nil, err
+	}
+
+	group := &core.Group{
+		GroupIdentity: *gi,
+		Previous:      current.Hash,
+		Created:       fftypes.Now(),
+	}
+	group.Seal()
+	existing, err := pm.database.GetGroupByHash(ctx, pm.namespace.Name, group.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupGenerationExists, group.Hash, current.Hash)
+	}
+
+	log.L(ctx).Infof("Creating generation %s of group %s with %d members", group.Hash, current.Hash, len(group.Members))
+	if err = pm.groupInit(ctx, &in.SignerRef, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (pm *privateMessaging) removeGroupMembers(ctx context.Context, group *core.Group, remove []core.MemberInput) (core.Members, error) {
+	remaining := append(core.Members{}, group.Members...)
+	for _, rInput := range remove {
+		identity, _, err := pm.identity.CachedIdentityLookupMustExist(ctx, rInput.Identity)
+		if err != nil {
+			return nil, err
+		}
+		var nodeID *fftypes.UUID
+		if rInput.Node != "" {
+			node, _, err := pm.identity.CachedIdentityLookupMustExist(ctx, rInput.Node)
+			if err != nil {
+				return nil, err
+			}
+			nodeID = node.ID
+		}
+		found := false
+		for i := 0; i < len(remaining); {
+			m := remaining[i]
+			if m.Identity == identity.DID && (nodeID == nil || m.Node.Equals(nodeID)) {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				found = true
+			} else {
+				i++
+			}
+		}
+		if !found {
+			return nil, i18n.NewError(ctx, coremsgs.MsgGroupMemberNotFound, rInput.Identity, group.Hash)
+		}
+	}
+	return remaining, nil
+}
+
+func isMember(members core.Members, member *core.Member) bool {
+	for _, m := range members {
+		if m.Identity == member.Identity && m.Node.Equals(member.Node) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,452 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testGroupMembers struct {
+	localOrg   *core.Identity
+	localNode  *core.Identity
+	remoteOrg  *core.Identity
+	remoteNode *core.Identity
+	group      *core.Group
+}
+
+func newTestGroupMembers(pm *privateMessaging) *testGroupMembers {
+	tg := &testGroupMembers{
+		localOrg:  newTestOrg("localorg"),
+		remoteOrg: newTestOrg("remoteorg"),
+	}
+	tg.localNode = newTestNode("node1", tg.localOrg)
+	tg.remoteNode = newTestNode("node2", tg.remoteOrg)
+	tg.group = &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Name:      "group1",
+			Members: core.Members{
+				{Identity: tg.localOrg.DID, Node: tg.localNode.ID},
+				{Identity: tg.remoteOrg.DID, Node: tg.remoteNode.ID},
+			},
+		},
+		LocalNamespace: "ns1",
+	}
+	tg.group.Seal()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args[1].(*core.SignerRef).Author = tg.localOrg.DID
+	}).Return(nil).Maybe()
+	mim.On("GetRootOrg", pm.ctx).Return(tg.localOrg, nil).Maybe()
+	mim.On("GetLocalNode", pm.ctx).Return(tg.localNode, nil).Maybe()
+	for _, identity := range []*core.Identity{tg.localOrg, tg.localNode, tg.remoteOrg, tg.remoteNode} {
+		mim.On("CachedIdentityLookupMustExist", pm.ctx, identity.Name).Return(identity, false, nil).Maybe()
+		mim.On("CachedIdentityLookupMustExist", pm.ctx, identity.DID).Return(identity, false, nil).Maybe()
+		mim.On("CachedIdentityLookupMustExist", pm.ctx, identity.ID.String()).Return(identity, false, nil).Maybe()
+		mim.On("CachedIdentityLookupByID", pm.ctx, identity.ID).Return(identity, nil).Maybe()
+	}
+	mim.On("ValidateNodeOwner", pm.ctx, mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	return tg
+}
+
+func TestCreateGroupNewOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentities", pm.ctx, "ns1", mock.Anything).Return([]*core.Identity{tg.remoteNode}, nil, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(nil, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertMessage", pm.ctx, mock.MatchedBy(func(msg *core.Message) bool {
+		return msg.Header.Type == core.MessageTypeGroupInit && msg.Header.Group.Equals(tg.group.Hash)
+	}), database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+
+	group, err := pm.CreateGroup(pm.ctx, &core.GroupCreate{
+		InputGroup: core.InputGroup{
+			Name:    "group1",
+			Members: []core.MemberInput{{Identity: "remoteorg"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, tg.group.Hash, group.Hash)
+	assert.Nil(t, group.Previous)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateGroupExisting(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+
+	group, err := pm.CreateGroup(pm.ctx, &core.GroupCreate{
+		InputGroup: core.InputGroup{
+			Name:    "group1",
+			Members: []core.MemberInput{{Identity: "remoteorg", Node: "node2"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, tg.group, group)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateGroupNoMembers(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.CreateGroup(pm.ctx, &core.GroupCreate{})
+	assert.Regexp(t, "FF00115", err)
+}
+
+func TestCreateGroupBadSigner(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := pm.CreateGroup(pm.ctx, &core.GroupCreate{
+		InputGroup: core.InputGroup{
+			Members: []core.MemberInput{{Identity: "remoteorg"}},
+		},
+	})
+	assert.Regexp(t, "FF10206.*pop", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestCreateGroupResolveFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	newTestGroupMembers(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := pm.CreateGroup(pm.ctx, &core.GroupCreate{
+		InputGroup: core.InputGroup{
+			Members: []core.MemberInput{{Identity: "unknown"}},
+		},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestUpdateGroupMembersOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	otherOrg := newTestOrg("otherorg")
+	otherNode := newTestNode("node3", otherOrg)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "otherorg").Return(otherOrg, false, nil)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, otherOrg.DID).Return(otherOrg, false, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, otherNode.ID).Return(otherNode, nil)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+	mdi.On("GetIdentities", pm.ctx, "ns1", mock.Anything).Return([]*core.Identity{otherNode}, nil, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.MatchedBy(func(g *core.Group) bool {
+		return g.Previous == nil // linked when the init message is confirmed
+	}), database.UpsertOptimizationNew).Return(nil)
+
+	group, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Add:    []core.MemberInput{{Identity: "otherorg"}},
+		Remove: []core.MemberInput{{Identity: "remoteorg", Node: "node2"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, tg.group.Hash, group.Previous)
+	assert.Equal(t, "group1", group.Name)
+	assert.Len(t, group.Members, 2)
+	for _, m := range group.Members {
+		assert.NotEqual(t, tg.remoteOrg.DID, m.Identity)
+	}
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersBadHash(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, "!bad", &core.GroupMembershipUpdate{})
+	assert.Regexp(t, "FF00107", err)
+}
+
+func TestUpdateGroupMembersNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, fftypes.NewRandB32().String(), &core.GroupMembershipUpdate{})
+	assert.Regexp(t, "FF10226", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersLatestFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersBadSigner(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{Hash: fftypes.NewRandB32()}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, group.Hash.String(), &core.GroupMembershipUpdate{})
+	assert.Regexp(t, "FF10206.*pop", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{Hash: fftypes.NewRandB32()}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, group.Hash.String(), &core.GroupMembershipUpdate{})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersSignerNotMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+	tg.group.Members = tg.group.Members[1:]
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Add: []core.MemberInput{{Identity: "localorg"}},
+	})
+	assert.Regexp(t, "FF10651", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersRemoveSigner(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "localorg"}},
+	})
+	assert.Regexp(t, "FF10651", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersRemoveNotMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "remoteorg", Node: "node1"}},
+	})
+	assert.Regexp(t, "FF10620", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersRemoveIdentityFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, false, fmt.Errorf("pop"))
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "unknown"}},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersRemoveNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, false, fmt.Errorf("pop"))
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "remoteorg", Node: "unknown"}},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersAddFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, false, fmt.Errorf("pop"))
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Add: []core.MemberInput{{Identity: "unknown"}},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersGenerationExists(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(&core.Group{}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "remoteorg"}},
+	})
+	assert.Regexp(t, "FF10621", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersUnchanged(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{})
+	assert.Regexp(t, "FF10621", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersLookupNewFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "remoteorg"}},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestUpdateGroupMembersInitFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tg := newTestGroupMembers(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tg.group.Hash).Return(tg.group, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	_, err := pm.UpdateGroupMembers(pm.ctx, tg.group.Hash.String(), &core.GroupMembershipUpdate{
+		Remove: []core.MemberInput{{Identity: "remoteorg"}},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
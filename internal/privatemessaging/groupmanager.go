@@ -54,6 +54,8 @@ type groupHashEntry struct {
 
 // EnsureLocalGroup is called for unpinned messages, which carry the full group definition next to the message batch.
 // The group simply needs to be validated and stored (or loaded if it exists already).
+// Any link to a previous generation is only stored once the pinned group init message is processed, as unpinned
+// messages are not ordered consistently across the nodes of the group.
 func (gm *groupManager) EnsureLocalGroup(ctx context.Context, group *core.Group, creator *core.Member) (ok bool, err error) {
 	if g, err := gm.database.GetGroupByHash(ctx, gm.namespace.Name, group.Hash); err != nil {
 		return false, err
@@ -70,7 +72,7 @@ func (gm *groupManager) EnsureLocalGroup(ctx context.Context, group *core.Group,
 		return false, nil
 	}
 
-	err = gm.database.UpsertGroup(ctx, group, database.UpsertOptimizationNew /* it could have been created by another thread, but we think we're first */)
+	err = gm.database.UpsertGroup(ctx, withoutPrevious(group), database.UpsertOptimizationNew /* it could have been created by another thread, but we think we're first */)
 	if err != nil {
 		return false, err
 	}
@@ -149,7 +151,8 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *core.SignerRef, g
 			// Write the unconfirmed group directly to our database, so it can be used straight away.
 			// We're able to do this by making the identifier of the group a hash of the identity fields
 			// (name, ledger and member list), as that is all the group contains. There's no data in there.
-			return gm.database.UpsertGroup(ctx, group, database.UpsertOptimizationNew /* we think we're first */)
+			// The link to any previous generation is stored when the init message is confirmed, in pin order.
+			return gm.database.UpsertGroup(ctx, withoutPrevious(group), database.UpsertOptimizationNew /* we think we're first */)
 		})
 		if err == nil {
 			log.L(ctx).Infof("Created new group %s", group.Hash)
@@ -240,6 +243,9 @@ func (gm *groupManager) ResolveInitGroup(ctx context.Context, msg *core.Message,
 		}
 		newGroup.Message = msg.Header.ID
 		newGroup.LocalNamespace = gm.namespace.Name
+		if err = gm.checkPreviousGeneration(ctx, &newGroup, member); err != nil {
+			return nil, err
+		}
 		err = gm.database.UpsertGroup(ctx, &newGroup, database.UpsertOptimizationNew /* we think we're first to create this */)
 		if err != nil {
 			return nil, err
@@ -272,3 +278,67 @@ func (gm *groupManager) groupContains(ctx context.Context, group *core.Group, me
 	log.L(ctx).Errorf("Group '%s' does not contain member identity=%s node=%s", group.Hash, member.Identity, member.Node)
 	return false
 }
+
+func withoutPrevious(group *core.Group) *core.Group {
+	if group.Previous == nil {
+		return group
+	}
+	unlinked := *group
+	unlinked.Previous = nil
+	return &unlinked
+}
+
+// checkPreviousGeneration ensures a group only links to a previous generation that is known locally, and that the
+// creator of the new generation was a member of. Only the first generation confirmed from any group is linked to it,
+// which is deterministic across all members as init messages are processed in pin order.
+// The link is not part of the group hash, so it is discarded (rather than the whole group being rejected) if these
+// checks fail.
+func (gm *groupManager) checkPreviousGeneration(ctx context.Context, group *core.Group, creator *core.Member) error {
+	if group.Previous == nil {
+		return nil
+	}
+	previous, err := gm.database.GetGroupByHash(ctx, gm.namespace.Name, group.Previous)
+	if err != nil {
+		return err
+	}
+	if previous == nil || !gm.groupContains(ctx, previous, creator) {
+		log.L(ctx).Warnf("Group %s discarding link to unknown previous generation %s", group.Hash, group.Previous)
+		group.Previous = nil
+		return nil
+	}
+	next, err := gm.nextGroupGeneration(ctx, previous.Hash)
+	if err != nil {
+		return err
+	}
+	if next != nil && !next.Hash.Equals(group.Hash) {
+		log.L(ctx).Warnf("Group %s discarding link to previous generation %s, which already has next generation %s", group.Hash, group.Previous, next.Hash)
+		group.Previous = nil
+	}
+	return nil
+}
+
+func (gm *groupManager) nextGroupGeneration(ctx context.Context, hash *fftypes.Bytes32) (*core.Group, error) {
+	fb := database.GroupQueryFactory.NewFilterLimit(ctx, 1)
+	next, _, err := gm.database.GetGroups(ctx, gm.namespace.Name, fb.And(fb.Eq("previous", hash)))
+	if err != nil || len(next) == 0 {
+		return nil, err
+	}
+	return next[0], nil
+}
+
+// latestGroupGeneration follows the links from a group to any later generations created by changing its members.
+// Each group has at most one next generation, as only the first one confirmed is linked.
+func (gm *groupManager) latestGroupGeneration(ctx context.Context, group *core.Group) (*core.Group, error) {
+	visited := map[fftypes.Bytes32]bool{*group.Hash: true}
+	for {
+		next, err := gm.nextGroupGeneration(ctx, group.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil || visited[*next.Hash] {
+			return group, nil
+		}
+		group = next
+		visited[*group.Hash] = true
+	}
+}
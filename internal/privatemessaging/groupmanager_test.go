@@ -822,3 +822,288 @@ func TestEnsureLocalGroupWithoutCreator(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func TestEnsureLocalGroupStoredWithoutPrevious(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	node1 := fftypes.NewUUID()
+	member := &core.Member{Node: node1, Identity: "id1"}
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+		Previous: fftypes.NewRandB32(),
+	}
+	group.Seal()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(nil, nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.MatchedBy(func(g *core.Group) bool {
+		return g.Hash.Equals(group.Hash) && g.Previous == nil
+	}), database.UpsertOptimizationNew).Return(nil)
+
+	ok, err := pm.EnsureLocalGroup(pm.ctx, group, member)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NotNil(t, group.Previous)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupNewGenerationOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	previous := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+	}
+	previous.Seal()
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member, {Identity: "abce67890", Node: fftypes.NewUUID()}},
+		},
+		Previous: previous.Hash,
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", previous.Hash).Return(previous, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{group}, nil, nil) // redelivery
+	mdi.On("UpsertGroup", pm.ctx, mock.MatchedBy(func(g *core.Group) bool {
+		return g.Previous.Equals(previous.Hash)
+	}), database.UpsertOptimizationNew).Return(nil)
+
+	resolved, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.NoError(t, err)
+	assert.Equal(t, previous.Hash, resolved.Previous)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupNewGenerationNotMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	previous := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{{Identity: "abce67890", Node: fftypes.NewUUID()}},
+		},
+	}
+	previous.Seal()
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+		Previous: previous.Hash,
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", previous.Hash).Return(previous, nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.MatchedBy(func(g *core.Group) bool {
+		return g.Previous == nil
+	}), database.UpsertOptimizationNew).Return(nil)
+
+	resolved, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.NoError(t, err)
+	assert.Nil(t, resolved.Previous)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupNewGenerationAlreadyHasNext(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	previous := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+	}
+	previous.Seal()
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member, {Identity: "abce67890", Node: fftypes.NewUUID()}},
+		},
+		Previous: previous.Hash,
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+	other := &core.Group{Hash: fftypes.NewRandB32(), Previous: previous.Hash}
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", previous.Hash).Return(previous, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{other}, nil, nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.MatchedBy(func(g *core.Group) bool {
+		return g.Previous == nil
+	}), database.UpsertOptimizationNew).Return(nil)
+
+	resolved, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.NoError(t, err)
+	assert.Nil(t, resolved.Previous)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupNewGenerationNextFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	previous := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+	}
+	previous.Seal()
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+		Previous: previous.Hash,
+	}
+	group.Name = "gen2"
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", previous.Hash).Return(previous, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupNewGenerationLookupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members:   core.Members{member},
+		},
+		Previous: fftypes.NewRandB32(),
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Previous).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestLatestGroupGenerationCycle(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group1 := &core.Group{Hash: fftypes.NewRandB32()}
+	group2 := &core.Group{Hash: fftypes.NewRandB32(), Previous: group1.Hash}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{group2}, nil, nil).Once()
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{group1}, nil, nil).Once()
+
+	latest, err := pm.latestGroupGeneration(pm.ctx, group1)
+	assert.NoError(t, err)
+	assert.Equal(t, group2, latest)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestLatestGroupGenerationFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := pm.latestGroupGeneration(pm.ctx, &core.Group{Hash: fftypes.NewRandB32()})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
@@ -18,6 +18,7 @@ package privatemessaging
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -56,6 +57,13 @@ func (pm *privateMessaging) SendMessage(ctx context.Context, in *core.MessageInO
 	return &in.Message, err
 }
 
+// SendMessages privately sends a batch of messages without waiting for confirmation, returning the outcome of each
+func (pm *privateMessaging) SendMessages(ctx context.Context, in []*core.MessageInOut) ([]*core.MessageSubmitResult, error) {
+	return syncasync.SendBatch(ctx, in, func(ctx context.Context, msg *core.MessageInOut) (*core.Message, error) {
+		return pm.SendMessage(ctx, msg, false)
+	})
+}
+
 func (pm *privateMessaging) RequestReply(ctx context.Context, in *core.MessageInOut) (*core.MessageInOut, error) {
 	if in.Header.Tag == "" {
 		return nil, i18n.NewError(ctx, coremsgs.MsgRequestReplyTagRequired)
@@ -63,6 +71,9 @@ func (pm *privateMessaging) RequestReply(ctx context.Context, in *core.MessageIn
 	if in.Header.CID != nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgRequestCannotHaveCID)
 	}
+	if in.SendAfter != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgScheduledMessageCannotWait)
+	}
 	message := pm.NewMessage(in)
 	return pm.syncasync.WaitForReply(ctx, in.Header.ID, message.Send)
 }
@@ -121,6 +132,21 @@ func (s *messageSender) setDefaults() {
 
 func (s *messageSender) resolveAndSend(ctx context.Context, method sendMethod) error {
 
+	if method == methodSendAndWait && s.msg.Message.SendAfter != nil {
+		return i18n.NewError(ctx, coremsgs.MsgScheduledMessageCannotWait)
+	}
+	if method != methodPrepare && !s.resolved && s.msg.Message.IdempotencyKey != "" {
+		// A retry of a message submitted within the idempotency window returns the original message
+		existing, err := s.mgr.data.GetIdempotentMessage(ctx, s.msg.Message.IdempotencyKey)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			log.L(ctx).Infof("Returning private message %s for duplicate idempotency key '%s'", existing.Header.ID, existing.IdempotencyKey)
+			s.msg.Message.Message = *existing
+			return nil
+		}
+	}
 	if !s.resolved {
 		if err := s.resolve(ctx); err != nil {
 			return err
@@ -148,6 +174,12 @@ func (s *messageSender) resolve(ctx context.Context) error {
 		return err
 	}
 
+	if len(msg.Triggers) > 0 {
+		if err := s.mgr.triggers.ValidateTriggers(ctx, msg.Triggers); err != nil {
+			return err
+		}
+	}
+
 	// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
 	err := s.mgr.data.ResolveInlineData(ctx, s.msg)
 	return err
@@ -174,11 +206,23 @@ func (s *messageSender) sendInternal(ctx context.Context, method sendMethod) err
 		return nil
 	}
 
+	// A message with a future sendAfter time is held back, until the batch manager releases it
+	if msg.SendAfter != nil && time.Time(*msg.SendAfter).After(time.Now()) {
+		msg.State = core.MessageStateScheduled
+	}
+
+	// Write the follow-up actions in the same transaction as the message, so they are waiting when it is confirmed
+	if triggers := s.msg.Message.Triggers; len(triggers) > 0 {
+		s.msg.PreWrite = func(ctx context.Context) error {
+			return s.mgr.triggers.AddTriggers(ctx, &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: msg.Header.ID}, triggers)
+		}
+	}
+
 	// Store the message - this asynchronously triggers the next step in process
 	if err := s.mgr.data.WriteNewMessage(ctx, s.msg); err != nil {
 		return err
 	}
-	log.L(ctx).Infof("Sent private message %s sequence=%d", msg.Header.ID, msg.Sequence)
+	log.L(ctx).Infof("Sent private message %s sequence=%d state=%s", msg.Header.ID, msg.Sequence, msg.State)
 
 	return nil
 }
@@ -0,0 +1,113 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type queuedMessageAcks struct {
+	nodeID  *fftypes.UUID
+	ackType core.MessageAckType
+	msgIDs  []*fftypes.UUID
+}
+
+// QueueMessageAcks queues acknowledgements for a set of private messages received from another node, to be sent
+// back to that node in the background, so the event processing that generates them is never blocked on data exchange.
+// This is a no-op unless acknowledgements are enabled for this node. Acks are best-effort, so they are dropped
+// with a warning if the queue is full.
+func (pm *privateMessaging) QueueMessageAcks(ctx context.Context, nodeID *fftypes.UUID, ackType core.MessageAckType, msgIDs []*fftypes.UUID) {
+	if !pm.acksEnabled || len(msgIDs) == 0 {
+		return
+	}
+	select {
+	case pm.ackQueue <- &queuedMessageAcks{nodeID: nodeID, ackType: ackType, msgIDs: msgIDs}:
+	default:
+		log.L(ctx).Warnf("Dropping %d '%s' acks to node %s, as the ack queue is full", len(msgIDs), ackType, nodeID)
+	}
+}
+
+func (pm *privateMessaging) messageAckSender() {
+	for {
+		select {
+		case <-pm.ctx.Done():
+			log.L(pm.ctx).Debugf("Message ack sender exiting")
+			return
+		case qa := <-pm.ackQueue:
+			if err := pm.sendMessageAcks(pm.ctx, qa.nodeID, qa.ackType, qa.msgIDs); err != nil {
+				log.L(pm.ctx).Errorf("Failed to send %d '%s' acks to node %s: %s", len(qa.msgIDs), qa.ackType, qa.nodeID, err)
+			}
+		}
+	}
+}
+
+// sendMessageAcks sends acknowledgements back to the node that sent the messages, unless it is this node.
+func (pm *privateMessaging) sendMessageAcks(ctx context.Context, nodeID *fftypes.UUID, ackType core.MessageAckType, msgIDs []*fftypes.UUID) error {
+
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return err
+	}
+	if localNode.ID.Equals(nodeID) {
+		return nil
+	}
+	node, err := pm.identity.CachedIdentityLookupByID(ctx, nodeID)
+	if err != nil {
+		return err
+	} else if node == nil {
+		return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	orgDID, err := pm.identity.GetRootOrgDID(ctx)
+	if err != nil {
+		return err
+	}
+
+	acks := make([]*core.MessageAck, len(msgIDs))
+	for i, msgID := range msgIDs {
+		acks[i] = &core.MessageAck{
+			ID:        fftypes.NewUUID(),
+			Namespace: pm.namespace.NetworkName,
+			Message:   msgID,
+			Type:      ackType,
+			Author:    orgDID,
+			Node:      localNode.ID,
+			Created:   fftypes.Now(),
+		}
+	}
+
+	op := core.NewOperation(
+		pm.exchange,
+		pm.namespace.Name,
+		nil,
+		core.OpTypeDataExchangeSendMessageAcks)
+	if err = addMessageAcksSendInputs(op, node.ID, acks); err == nil {
+		err = pm.operations.AddOrReuseOperation(ctx, op)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.L(ctx).Debugf("Sending %d '%s' acks to node=%s", len(acks), ackType, node.ID)
+	_, err = pm.operations.RunOperation(ctx, opSendMessageAcks(op, node, acks), false /* acks do not use idempotency keys */)
+	return err
+}
@@ -0,0 +1,310 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPrivateMessagingWithAcks(t *testing.T) (*privateMessaging, func()) {
+	return newTestPrivateMessagingCommon(t, false, true)
+}
+
+func TestSendMessageAcksOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	localNode := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	node := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	msgIDs := []*fftypes.UUID{fftypes.NewUUID(), fftypes.NewUUID()}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, node.ID).Return(node, nil)
+	mim.On("GetRootOrgDID", pm.ctx).Return("did:firefly:org/org1", nil)
+
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeDataExchangeSendMessageAcks && op.Input.GetString("node") == node.ID.String()
+	})).Return(nil)
+	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transportSendData)
+		acks := data.Transport.Acks
+		return data.Node == node && len(acks) == 2 &&
+			acks[0].Message == msgIDs[0] && acks[1].Message == msgIDs[1] &&
+			acks[0].Type == core.MessageAckTypeDelivered &&
+			acks[0].Author == "did:firefly:org/org1" &&
+			acks[0].Node == localNode.ID &&
+			acks[0].Namespace == "ns1"
+	}), false).Return(nil, nil)
+
+	err := pm.sendMessageAcks(pm.ctx, node.ID, core.MessageAckTypeDelivered, msgIDs)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestQueueMessageAcksDisabled(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	pm.QueueMessageAcks(pm.ctx, fftypes.NewUUID(), core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Empty(t, pm.ackQueue)
+}
+
+func TestQueueMessageAcksFull(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.acksEnabled = true
+	pm.ackQueue = make(chan *queuedMessageAcks)
+
+	pm.QueueMessageAcks(pm.ctx, fftypes.NewUUID(), core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+}
+
+func TestQueueMessageAcksSentInBackground(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+
+	localNode := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	sent := make(chan bool)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop")).Once()
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil).Run(func(args mock.Arguments) {
+		close(sent)
+	}).Once()
+
+	pm.QueueMessageAcks(pm.ctx, fftypes.NewUUID(), core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	pm.QueueMessageAcks(pm.ctx, localNode.ID, core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	<-sent
+	cancel()
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendMessageAcksLocalNode(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	localNode := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil)
+
+	err := pm.sendMessageAcks(pm.ctx, localNode.ID, core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendMessageAcksLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.sendMessageAcks(pm.ctx, fftypes.NewUUID(), core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendMessageAcksNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, nodeID).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.sendMessageAcks(pm.ctx, nodeID, core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendMessageAcksNodeNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, nodeID).Return(nil, nil)
+
+	err := pm.sendMessageAcks(pm.ctx, nodeID, core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF10109", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendMessageAcksOrgFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	node := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, node.ID).Return(node, nil)
+	mim.On("GetRootOrgDID", pm.ctx).Return("", fmt.Errorf("pop"))
+
+	err := pm.sendMessageAcks(pm.ctx, node.ID, core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendMessageAcksAddOperationFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessagingWithAcks(t)
+	defer cancel()
+
+	node := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, node.ID).Return(node, nil)
+	mim.On("GetRootOrgDID", pm.ctx).Return("did:firefly:org/org1", nil)
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := pm.sendMessageAcks(pm.ctx, node.ID, core.MessageAckTypeDelivered, []*fftypes.UUID{fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestPrepareAndRunMessageAcksSend(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:      core.OpTypeDataExchangeSendMessageAcks,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	node := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": "peer1",
+			},
+		},
+	}
+	localNode := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": "local1",
+			},
+		},
+	}
+	ack := &core.MessageAck{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Message:   fftypes.NewUUID(),
+		Type:      core.MessageAckTypeConfirmed,
+		Author:    "did:firefly:org/org1",
+		Node:      localNode.ID,
+		Created:   fftypes.Now(),
+	}
+	err := addMessageAcksSendInputs(op, node.ID, []*core.MessageAck{ack})
+	assert.NoError(t, err)
+
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", context.Background(), node.ID).Return(node, nil)
+	mdx.On("SendMessage", context.Background(), "ns1:"+op.ID.String(), node.Profile, localNode.Profile, mock.Anything).Return(nil)
+
+	po, err := pm.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, node, po.Data.(transportSendData).Node)
+	assert.Equal(t, []*core.MessageAck{ack}, po.Data.(transportSendData).Transport.Acks)
+
+	_, phase, err := pm.RunOperation(context.Background(), po)
+
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestPrepareOperationMessageAcksSendBadInput(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendMessageAcks,
+		Input: fftypes.JSONObject{"node": "bad"},
+	}
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00127", err)
+}
+
+func TestPrepareOperationMessageAcksSendNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendMessageAcks,
+		Input: fftypes.JSONObject{"node": nodeID.String()},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", context.Background(), nodeID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestPrepareOperationMessageAcksSendNodeNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendMessageAcks,
+		Input: fftypes.JSONObject{"node": nodeID.String()},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", context.Background(), nodeID).Return(nil, nil)
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mim.AssertExpectations(t)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/batch"
@@ -31,6 +32,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/triggermocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -145,6 +147,7 @@ func TestSendUnpinnedMessageE2EOk(t *testing.T) {
 
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
 
 	msg, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
 		Message: core.Message{
@@ -171,6 +174,220 @@ func TestSendUnpinnedMessageE2EOk(t *testing.T) {
 
 }
 
+func TestSendMessageScheduled(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.MatchedBy(func(msg *data.NewMessage) bool {
+		return msg.Message.State == core.MessageStateScheduled
+	})).Return(nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	sendAfter := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	msg, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+			SendAfter: &sendAfter,
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateScheduled, msg.State)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+
+}
+
+func TestSendMessageIdempotentWithinWindow(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	existing := &core.Message{
+		Header:         core.MessageHeader{ID: fftypes.NewUUID()},
+		IdempotencyKey: "idem1",
+	}
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetIdempotentMessage", pm.ctx, core.IdempotencyKey("idem1")).Return(existing, nil)
+
+	msg, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "idem1",
+		},
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, existing.Header.ID, msg.Header.ID)
+
+	mdm.AssertExpectations(t)
+
+}
+
+func TestSendMessageIdempotentFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetIdempotentMessage", pm.ctx, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			IdempotencyKey: "idem1",
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+
+}
+
+func TestSendMessageWithTriggers(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	triggers := []*core.TriggerAction{
+		{Type: core.TriggerActionTypeInvoke, Invoke: &core.TriggerInvoke{API: "myapi", MethodPath: "set"}},
+	}
+	mtm := pm.triggers.(*triggermocks.Manager)
+	mtm.On("ValidateTriggers", pm.ctx, triggers).Return(nil)
+	mtm.On("AddTriggers", pm.ctx, mock.MatchedBy(func(source *core.TriggerSource) bool {
+		return source.Type == core.TriggerSourceTypeMessage && source.ID != nil
+	}), triggers).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.Anything).Return(func(ctx context.Context, msg *data.NewMessage) error {
+		return msg.PreWrite(ctx)
+	}).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+		},
+		Triggers: triggers,
+	}, false)
+	assert.NoError(t, err)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+
+}
+
+func TestSendMessageTriggersInvalid(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+	mtm := pm.triggers.(*triggermocks.Manager)
+	mtm.On("ValidateTriggers", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	groupID := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+		Triggers: []*core.TriggerAction{{Type: "unknown"}},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+
+}
+
+func TestSendMessageAddTriggersFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessagingWithMetrics(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+	mtm := pm.triggers.(*triggermocks.Manager)
+	mtm.On("ValidateTriggers", pm.ctx, mock.Anything).Return(nil)
+	mtm.On("AddTriggers", pm.ctx, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.Anything).Return(func(ctx context.Context, msg *data.NewMessage) error {
+		return msg.PreWrite(ctx)
+	})
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+		Triggers: []*core.TriggerAction{{Type: core.TriggerActionTypeInvoke}},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mtm.AssertExpectations(t)
+
+}
+
+func TestSendMessageScheduledWaitConfirm(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			SendAfter: fftypes.Now(),
+		},
+	}, true)
+	assert.Regexp(t, "FF10554", err)
+
+}
+
 func TestSendMessageBadGroup(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -191,6 +408,29 @@ func TestSendMessageBadGroup(t *testing.T) {
 
 }
 
+func TestSendMessagesBadGroup(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	results, err := pm.SendMessages(pm.ctx, []*core.MessageInOut{{
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+		},
+		Group: &core.InputGroup{},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Nil(t, results[0].Message)
+	assert.Regexp(t, "FF00115", results[0].Error)
+
+	mim.AssertExpectations(t)
+
+}
+
 func TestSendMessageBadIdentity(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -288,6 +528,7 @@ func TestSendUnpinnedMessageTooLarge(t *testing.T) {
 
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
 
 	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
 		Message: core.Message{
@@ -431,6 +672,7 @@ func TestSendUnpinnedMessageInsertFail(t *testing.T) {
 
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
 
 	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
 		Message: core.Message{
@@ -591,6 +833,22 @@ func TestRequestReplyInvalidCID(t *testing.T) {
 	assert.Regexp(t, "FF10262", err)
 }
 
+func TestRequestReplyScheduled(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Tag:   "mytag",
+				Group: fftypes.NewRandB32(),
+			},
+			SendAfter: fftypes.Now(),
+		},
+	})
+	assert.Regexp(t, "FF10554", err)
+}
+
 func TestRequestReplySuccess(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
@@ -614,6 +872,7 @@ func TestRequestReplySuccess(t *testing.T) {
 
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
 
 	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{
 		Message: core.Message{
@@ -661,7 +920,7 @@ func TestDispatchedUnpinnedMessageOK(t *testing.T) {
 
 	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(nil)
 	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(batchSendData)
+		data := op.Data.(transportSendData)
 		return op.Type == core.OpTypeDataExchangeSendBatch && *data.Node.ID == *node2.ID
 	}), false).Return(nil, nil)
 
@@ -765,7 +1024,7 @@ func TestSendDataTransferFail(t *testing.T) {
 	mom := pm.operations.(*operationmocks.Manager)
 	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(nil)
 	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
-		data := op.Data.(batchSendData)
+		data := op.Data.(transportSendData)
 		return op.Type == core.OpTypeDataExchangeSendBatch && *data.Node.ID == *node2.ID
 	}), false).Return(nil, fmt.Errorf("pop"))
 
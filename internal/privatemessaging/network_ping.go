@@ -0,0 +1,56 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// SendPing sends a ping over data exchange to another node, to check connectivity. The returned operation
+// succeeds once data exchange reports the ping was delivered, and the receiving node discards the ping.
+func (pm *privateMessaging) SendPing(ctx context.Context, node *core.Identity) (*core.Operation, error) {
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ping := &core.NetworkPing{
+		ID:        fftypes.NewUUID(),
+		Namespace: pm.namespace.NetworkName,
+		Node:      localNode.ID,
+		Created:   fftypes.Now(),
+	}
+
+	op := core.NewOperation(
+		pm.exchange,
+		pm.namespace.Name,
+		nil,
+		core.OpTypeDataExchangeSendPing)
+	if err = addPingSendInputs(op, node.ID, ping); err == nil {
+		err = pm.operations.AddOrReuseOperation(ctx, op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.L(ctx).Debugf("Sending ping %s to node=%s", ping.ID, node.ID)
+	_, err = pm.operations.RunOperation(ctx, opSendPing(op, node, ping), false /* pings do not use idempotency keys */)
+	return op, err
+}
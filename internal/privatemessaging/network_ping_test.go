@@ -0,0 +1,199 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendPingOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	localNode := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+	node := &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil)
+
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeDataExchangeSendPing && op.Input.GetString("node") == node.ID.String()
+	})).Return(nil)
+	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transportSendData)
+		ping := data.Transport.Ping
+		return data.Node == node && data.Transport.Batch == nil &&
+			ping.Node == localNode.ID &&
+			ping.Namespace == "ns1"
+	}), false).Return(nil, nil)
+
+	op, err := pm.SendPing(pm.ctx, node)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpTypeDataExchangeSendPing, op.Type)
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestSendPingLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.SendPing(pm.ctx, &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendPingAddOperationFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}}, nil)
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := pm.SendPing(pm.ctx, &core.Identity{IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()}})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestPrepareAndRunPingSend(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:      core.OpTypeDataExchangeSendPing,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	node := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": "peer1",
+			},
+		},
+	}
+	localNode := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": "local1",
+			},
+		},
+	}
+	ping := &core.NetworkPing{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Node:      localNode.ID,
+		Created:   fftypes.Now(),
+	}
+	err := addPingSendInputs(op, node.ID, ping)
+	assert.NoError(t, err)
+
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", context.Background(), node.ID).Return(node, nil)
+	mdx.On("SendMessage", context.Background(), "ns1:"+op.ID.String(), node.Profile, localNode.Profile, mock.Anything).Return(nil)
+
+	po, err := pm.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, node, po.Data.(transportSendData).Node)
+	assert.Equal(t, ping, po.Data.(transportSendData).Transport.Ping)
+
+	_, phase, err := pm.RunOperation(context.Background(), po)
+
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestPrepareOperationPingSendBadInput(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendPing,
+		Input: fftypes.JSONObject{"node": "bad"},
+	}
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00127", err)
+}
+
+func TestPrepareOperationPingSendNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendPing,
+		Input: fftypes.JSONObject{"node": nodeID.String()},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", context.Background(), nodeID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestPrepareOperationPingSendNodeNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendPing,
+		Input: fftypes.JSONObject{"node": nodeID.String()},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", context.Background(), nodeID).Return(nil, nil)
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mim.AssertExpectations(t)
+}
@@ -32,7 +32,7 @@ type transferBlobData struct {
 	Blob *core.Blob     `json:"blob"`
 }
 
-type batchSendData struct {
+type transportSendData struct {
 	Node      *core.Identity         `json:"node"`
 	Transport *core.TransportWrapper `json:"transport"`
 }
@@ -80,6 +80,51 @@ func retrieveBatchSendInputs(ctx context.Context, op *core.Operation) (nodeID *f
 	return nodeID, groupHash, batchID, err
 }
 
+// messageAcksSendInputs stores the acks in full on the operation, so a retry sends exactly the same acknowledgements
+type messageAcksSendInputs struct {
+	Node *fftypes.UUID      `json:"node"`
+	Acks []*core.MessageAck `json:"acks"`
+}
+
+func addMessageAcksSendInputs(op *core.Operation, nodeID *fftypes.UUID, acks []*core.MessageAck) (err error) {
+	var inputJSON []byte
+	if inputJSON, err = json.Marshal(&messageAcksSendInputs{Node: nodeID, Acks: acks}); err == nil {
+		err = json.Unmarshal(inputJSON, &op.Input)
+	}
+	return err
+}
+
+func retrieveMessageAcksSendInputs(ctx context.Context, op *core.Operation) (nodeID *fftypes.UUID, acks []*core.MessageAck, err error) {
+	var inputs messageAcksSendInputs
+	s := op.Input.String()
+	if err := json.Unmarshal([]byte(s), &inputs); err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgJSONObjectParseFailed, s)
+	}
+	return inputs.Node, inputs.Acks, nil
+}
+
+type pingSendInputs struct {
+	Node *fftypes.UUID     `json:"node"`
+	Ping *core.NetworkPing `json:"ping"`
+}
+
+func addPingSendInputs(op *core.Operation, nodeID *fftypes.UUID, ping *core.NetworkPing) (err error) {
+	var inputJSON []byte
+	if inputJSON, err = json.Marshal(&pingSendInputs{Node: nodeID, Ping: ping}); err == nil {
+		err = json.Unmarshal(inputJSON, &op.Input)
+	}
+	return err
+}
+
+func retrievePingSendInputs(ctx context.Context, op *core.Operation) (nodeID *fftypes.UUID, ping *core.NetworkPing, err error) {
+	var inputs pingSendInputs
+	s := op.Input.String()
+	if err := json.Unmarshal([]byte(s), &inputs); err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgJSONObjectParseFailed, s)
+	}
+	return inputs.Node, inputs.Ping, nil
+}
+
 func (pm *privateMessaging) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	switch op.Type {
 	case core.OpTypeDataExchangeSendBlob:
@@ -132,6 +177,32 @@ func (pm *privateMessaging) PrepareOperation(ctx context.Context, op *core.Opera
 		transport := &core.TransportWrapper{Group: group, Batch: batch}
 		return opSendBatch(op, node, transport), nil
 
+	case core.OpTypeDataExchangeSendMessageAcks:
+		nodeID, acks, err := retrieveMessageAcksSendInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		node, err := pm.identity.CachedIdentityLookupByID(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		} else if node == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opSendMessageAcks(op, node, acks), nil
+
+	case core.OpTypeDataExchangeSendPing:
+		nodeID, ping, err := retrievePingSendInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		node, err := pm.identity.CachedIdentityLookupByID(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		} else if node == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opSendPing(op, node, ping), nil
+
 	default:
 		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotSupported, op.Type)
 	}
@@ -146,7 +217,7 @@ func (pm *privateMessaging) RunOperation(ctx context.Context, op *core.PreparedO
 		}
 		return nil, core.OpPhaseInitializing, pm.exchange.TransferBlob(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, data.Blob.PayloadRef)
 
-	case batchSendData:
+	case transportSendData:
 		localNode, err := pm.identity.GetLocalNode(ctx)
 		if err != nil {
 			return nil, core.OpPhaseInitializing, err
@@ -177,12 +248,32 @@ func opSendBlob(op *core.Operation, node *core.Identity, blob *core.Blob) *core.
 	}
 }
 
+func opSendMessageAcks(op *core.Operation, node *core.Identity, acks []*core.MessageAck) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      transportSendData{Node: node, Transport: &core.TransportWrapper{Acks: acks}},
+	}
+}
+
+func opSendPing(op *core.Operation, node *core.Identity, ping *core.NetworkPing) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      transportSendData{Node: node, Transport: &core.TransportWrapper{Ping: ping}},
+	}
+}
+
 func opSendBatch(op *core.Operation, node *core.Identity, transport *core.TransportWrapper) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
 		Namespace: op.Namespace,
 		Plugin:    op.Plugin,
 		Type:      op.Type,
-		Data:      batchSendData{Node: node, Transport: transport},
+		Data:      transportSendData{Node: node, Transport: transport},
 	}
 }
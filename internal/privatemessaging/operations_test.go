@@ -146,9 +146,9 @@ func TestPrepareAndRunBatchSend(t *testing.T) {
 
 	po, err := pm.PrepareOperation(context.Background(), op)
 	assert.NoError(t, err)
-	assert.Equal(t, node, po.Data.(batchSendData).Node)
-	assert.Equal(t, group, po.Data.(batchSendData).Transport.Group)
-	assert.Equal(t, batch, po.Data.(batchSendData).Transport.Batch)
+	assert.Equal(t, node, po.Data.(transportSendData).Node)
+	assert.Equal(t, group, po.Data.(transportSendData).Transport.Group)
+	assert.Equal(t, batch, po.Data.(transportSendData).Transport.Batch)
 
 	_, phase, err := pm.RunOperation(context.Background(), po)
 
@@ -35,6 +35,7 @@ import (
 	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/internal/triggers"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -50,7 +51,12 @@ type Manager interface {
 
 	NewMessage(msg *core.MessageInOut) syncasync.Sender
 	SendMessage(ctx context.Context, in *core.MessageInOut, waitConfirm bool) (out *core.Message, err error)
+	SendMessages(ctx context.Context, in []*core.MessageInOut) ([]*core.MessageSubmitResult, error)
 	RequestReply(ctx context.Context, request *core.MessageInOut) (reply *core.MessageInOut, err error)
+	QueueMessageAcks(ctx context.Context, nodeID *fftypes.UUID, ackType core.MessageAckType, msgIDs []*fftypes.UUID)
+	SendPing(ctx context.Context, node *core.Identity) (*core.Operation, error)
+	CreateGroup(ctx context.Context, in *core.GroupCreate) (*core.Group, error)
+	UpdateGroupMembers(ctx context.Context, hash string, in *core.GroupMembershipUpdate) (*core.Group, error)
 
 	// From operations.OperationHandler
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
@@ -73,7 +79,10 @@ type privateMessaging struct {
 	maxBatchPayloadLength int64
 	metrics               metrics.Manager
 	operations            operations.Manager
+	triggers              triggers.Manager
 	orgFirstNodes         map[string]*core.Identity
+	acksEnabled           bool
+	ackQueue              chan *queuedMessageAcks
 }
 
 type blobTransferTracker struct {
@@ -82,8 +91,8 @@ type blobTransferTracker struct {
 	op       *core.PreparedOperation
 }
 
-func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Plugin, dx dataexchange.Plugin, bi blockchain.Plugin, im identity.Manager, ba batch.Manager, dm data.Manager, sa syncasync.Bridge, mult multiparty.Manager, mm metrics.Manager, om operations.Manager, cacheManager cache.Manager) (Manager, error) {
-	if di == nil || im == nil || dx == nil || bi == nil || ba == nil || dm == nil || mm == nil || om == nil || mult == nil {
+func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Plugin, dx dataexchange.Plugin, bi blockchain.Plugin, im identity.Manager, ba batch.Manager, dm data.Manager, sa syncasync.Bridge, mult multiparty.Manager, mm metrics.Manager, om operations.Manager, tm triggers.Manager, cacheManager cache.Manager) (Manager, error) {
+	if di == nil || im == nil || dx == nil || bi == nil || ba == nil || dm == nil || mm == nil || om == nil || mult == nil || tm == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "PrivateMessaging")
 	}
 
@@ -111,7 +120,10 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 		maxBatchPayloadLength: config.GetByteSize(coreconfig.PrivateMessagingBatchPayloadLimit),
 		metrics:               mm,
 		operations:            om,
+		triggers:              tm,
 		orgFirstNodes:         make(map[string]*core.Identity),
+		acksEnabled:           config.GetBool(coreconfig.PrivateMessagingAcksEnabled),
+		ackQueue:              make(chan *queuedMessageAcks, config.GetInt(coreconfig.PrivateMessagingAcksQueueLength)),
 	}
 
 	groupCache, err := cacheManager.GetCache(
@@ -156,8 +168,14 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 	om.RegisterHandler(ctx, pm, []core.OpType{
 		core.OpTypeDataExchangeSendBlob,
 		core.OpTypeDataExchangeSendBatch,
+		core.OpTypeDataExchangeSendMessageAcks,
+		core.OpTypeDataExchangeSendPing,
 	})
 
+	if pm.acksEnabled {
+		go pm.messageAckSender()
+	}
+
 	return pm, nil
 }
 
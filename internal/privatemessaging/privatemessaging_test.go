@@ -39,15 +39,17 @@ import (
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/triggermocks"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled bool) (*privateMessaging, func()) {
+func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled, acksEnabled bool) (*privateMessaging, func()) {
 	coreconfig.Reset()
 	config.Set(coreconfig.CacheGroupLimit, "1m")
 	config.Set(coreconfig.CacheGroupTTL, 10)
+	config.Set(coreconfig.PrivateMessagingAcksEnabled, acksEnabled)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	mdi := &databasemocks.Plugin{}
@@ -85,7 +87,7 @@ func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled bool) (*privateM
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
 
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	pm, err := NewPrivateMessaging(ctx, ns, mdi, mdx, mbi, mim, mba, mdm, msa, mmp, mmi, mom, cmi)
+	pm, err := NewPrivateMessaging(ctx, ns, mdi, mdx, mbi, mim, mba, mdm, msa, mmp, mmi, mom, &triggermocks.Manager{}, cmi)
 	assert.NoError(t, err)
 	cmi.AssertCalled(t, "GetCache", cache.NewCacheConfig(
 		ctx,
@@ -121,7 +123,7 @@ func TestCacheInitFail(t *testing.T) {
 	cmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
 
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	_, err := NewPrivateMessaging(ctx, ns, mdi, mdx, mbi, mim, mba, mdm, msa, mmp, mmi, mom, cmi)
+	_, err := NewPrivateMessaging(ctx, ns, mdi, mdx, mbi, mim, mba, mdm, msa, mmp, mmi, mom, &triggermocks.Manager{}, cmi)
 	assert.Equal(t, cacheInitError, err)
 }
 
@@ -134,11 +136,11 @@ func mockRunAsGroupPassthrough(mdi *databasemocks.Plugin) {
 }
 
 func newTestPrivateMessaging(t *testing.T) (*privateMessaging, func()) {
-	return newTestPrivateMessagingCommon(t, false)
+	return newTestPrivateMessagingCommon(t, false, false)
 }
 
 func newTestPrivateMessagingWithMetrics(t *testing.T) (*privateMessaging, func()) {
-	pm, cancel := newTestPrivateMessagingCommon(t, true)
+	pm, cancel := newTestPrivateMessagingCommon(t, true, false)
 	mmi := pm.metrics.(*metricsmocks.Manager)
 	mmi.On("MessageSubmitted", mock.Anything).Return()
 	return pm, cancel
@@ -213,7 +215,7 @@ func TestDispatchBatchWithBlobs(t *testing.T) {
 		if op.Type != core.OpTypeDataExchangeSendBatch {
 			return false
 		}
-		data := op.Data.(batchSendData)
+		data := op.Data.(transportSendData)
 		return *data.Node.ID == *node2.ID
 	}), false).Return(nil, nil)
 
@@ -250,7 +252,7 @@ func TestDispatchBatchWithBlobs(t *testing.T) {
 }
 
 func TestNewPrivateMessagingMissingDeps(t *testing.T) {
-	_, err := NewPrivateMessaging(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewPrivateMessaging(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -494,7 +496,7 @@ func TestWriteTransactionSubmitBatchPinFail(t *testing.T) {
 		if op.Type != core.OpTypeDataExchangeSendBatch {
 			return false
 		}
-		data := op.Data.(batchSendData)
+		data := op.Data.(transportSendData)
 		return *data.Node.ID == *node2.ID
 	}), false).Return(nil, nil)
 
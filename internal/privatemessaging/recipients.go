@@ -38,13 +38,22 @@ func (pm *privateMessaging) resolveRecipientList(ctx context.Context, in *core.M
 		if group == nil {
 			return i18n.NewError(ctx, coremsgs.MsgGroupNotFound, in.Header.Group)
 		}
-		// We have a group already resolved
+		// If the members of the group have since been changed, the message is sent to the latest generation
+		// of the group instead. So the message is pinned in the nonce contexts of the new group.
+		latest, err := pm.latestGroupGeneration(ctx, group)
+		if err != nil {
+			return err
+		}
+		if !latest.Hash.Equals(group.Hash) {
+			log.L(ctx).Infof("Group '%s' has been replaced by generation '%s' for message", group.Hash, latest.Hash)
+			in.Header.Group = latest.Hash
+		}
 		return nil
 	}
 	if in.Group == nil || len(in.Group.Members) == 0 {
 		return i18n.NewError(ctx, i18n.MsgGroupMustHaveMembers)
 	}
-	group, isNew, err := pm.findOrGenerateGroup(ctx, in)
+	group, isNew, err := pm.findOrGenerateGroup(ctx, in.Message.Header.Namespace, in.Group)
 	if err != nil {
 		return err
 	}
@@ -106,7 +115,7 @@ func (pm *privateMessaging) resolveNode(ctx context.Context, identity *core.Iden
 	return node, nil
 }
 
-func (pm *privateMessaging) getRecipients(ctx context.Context, in *core.MessageInOut) (gi *core.GroupIdentity, err error) {
+func (pm *privateMessaging) getRecipients(ctx context.Context, namespace string, in *core.InputGroup) (gi *core.GroupIdentity, err error) {
 
 	localOrg, err := pm.identity.GetRootOrg(ctx)
 	if err != nil {
@@ -119,11 +128,11 @@ func (pm *privateMessaging) getRecipients(ctx context.Context, in *core.MessageI
 		return nil, err
 	}
 	gi = &core.GroupIdentity{
-		Namespace: in.Message.Header.Namespace,
-		Name:      in.Group.Name,
-		Members:   make(core.Members, len(in.Group.Members)),
+		Namespace: namespace,
+		Name:      in.Name,
+		Members:   make(core.Members, len(in.Members)),
 	}
-	for i, rInput := range in.Group.Members {
+	for i, rInput := range in.Members {
 		// Resolve the identity
 		identity, _, err := pm.identity.CachedIdentityLookupMustExist(ctx, rInput.Identity)
 		if err != nil {
@@ -152,8 +161,8 @@ func (pm *privateMessaging) getRecipients(ctx context.Context, in *core.MessageI
 	return gi, nil
 }
 
-func (pm *privateMessaging) findOrGenerateGroup(ctx context.Context, in *core.MessageInOut) (group *core.Group, isNew bool, err error) {
-	gi, err := pm.getRecipients(ctx, in)
+func (pm *privateMessaging) findOrGenerateGroup(ctx context.Context, namespace string, in *core.InputGroup) (group *core.Group, isNew bool, err error) {
+	gi, err := pm.getRecipients(ctx, namespace, in)
 	if err != nil {
 		return nil, false, err
 	}
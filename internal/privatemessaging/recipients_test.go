@@ -398,6 +398,7 @@ func TestResolveReceipientListExisting(t *testing.T) {
 	groupID := fftypes.NewRandB32()
 	mdi := pm.database.(*databasemocks.Plugin)
 	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil)
 
 	err := pm.resolveRecipientList(pm.ctx, &core.MessageInOut{
 		Message: core.Message{
@@ -409,6 +410,52 @@ func TestResolveReceipientListExisting(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestResolveReceipientListNewGeneration(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	group2 := &core.Group{Hash: fftypes.NewRandB32(), Previous: groupID}
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{group2}, nil, nil).Once()
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return([]*core.Group{}, nil, nil).Once()
+
+	in := &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+	}
+	err := pm.resolveRecipientList(pm.ctx, in)
+	assert.NoError(t, err)
+	assert.Equal(t, group2.Hash, in.Header.Group)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveReceipientListNewGenerationFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdi.On("GetGroups", pm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := pm.resolveRecipientList(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
 func TestResolveReceipientListEmptyList(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
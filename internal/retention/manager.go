@@ -0,0 +1,433 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
+)
+
+// Manager periodically prunes events, confirmed messages (and their data) and completed operations
+// that are older than the retention window of the namespace
+type Manager interface {
+	Start()
+	WaitStop()
+	Status() *Status
+}
+
+// Config is the retention configuration of a namespace
+type Config struct {
+	Window  time.Duration // zero disables pruning
+	Archive bool          // upload each page of records to shared storage before it is deleted
+}
+
+// maxInValues bounds the number of values in each IN query made while pruning
+const maxInValues = 100
+
+type Status struct {
+	Enabled     bool               `ffstruct:"RetentionStatus" json:"enabled"`
+	Window      fftypes.FFDuration `ffstruct:"RetentionStatus" json:"window,omitempty"`
+	Archive     bool               `ffstruct:"RetentionStatus" json:"archive"`
+	LastRun     *fftypes.FFTime    `ffstruct:"RetentionStatus" json:"lastRun,omitempty"`
+	LastError   string             `ffstruct:"RetentionStatus" json:"lastError,omitempty"`
+	LastArchive string             `ffstruct:"RetentionStatus" json:"lastArchive,omitempty"`
+	Pruned      PrunedCounts       `ffstruct:"RetentionStatus" json:"pruned"`
+}
+
+type PrunedCounts struct {
+	Events     int64 `ffstruct:"RetentionPrunedCounts" json:"events"`
+	Messages   int64 `ffstruct:"RetentionPrunedCounts" json:"messages"`
+	Operations int64 `ffstruct:"RetentionPrunedCounts" json:"operations"`
+}
+
+// archiveDocument is the format of each page of records uploaded to shared storage
+type archiveDocument struct {
+	Namespace  string          `json:"namespace"`
+	Collection string          `json:"collection"`
+	Archived   *fftypes.FFTime `json:"archived"`
+	Records    interface{}     `json:"records"`
+}
+
+// archivedRecordHash is archived in place of a record that can contain private data, such as an event
+// referring to a private message or the inputs and outputs of an operation. The hash is of the JSON
+// serialization of the record, so a copy held privately can be verified against the archive.
+type archivedRecordHash struct {
+	ID   *fftypes.UUID    `json:"id"`
+	Hash *fftypes.Bytes32 `json:"hash"`
+}
+
+func recordHash(id *fftypes.UUID, record interface{}) *archivedRecordHash {
+	b, _ := json.Marshal(record)
+	var hash fftypes.Bytes32 = sha256.Sum256(b)
+	return &archivedRecordHash{ID: id, Hash: &hash}
+}
+
+type retentionManager struct {
+	ctx           context.Context
+	cancelCtx     func()
+	namespace     string
+	conf          Config
+	database      database.Plugin
+	sharedstorage sharedstorage.Plugin // only required for archive
+	interval      time.Duration
+	batchSize     int
+	started       bool
+	done          chan struct{}
+	mux           sync.Mutex
+	status        Status
+}
+
+func NewRetentionManager(ctx context.Context, ns string, conf Config, di database.Plugin, ss sharedstorage.Plugin) (Manager, error) {
+	if di == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "RetentionManager")
+	}
+	if conf.Archive && ss == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgRetentionArchiveNoSharedStorage, ns)
+	}
+	rm := &retentionManager{
+		namespace:     ns,
+		conf:          conf,
+		database:      di,
+		sharedstorage: ss,
+		interval:      config.GetDuration(coreconfig.RetentionInterval),
+		batchSize:     config.GetInt(coreconfig.RetentionBatchSize),
+		done:          make(chan struct{}),
+		status: Status{
+			Enabled: conf.Window > 0,
+			Window:  fftypes.FFDuration(conf.Window),
+			Archive: conf.Archive,
+		},
+	}
+	rm.ctx, rm.cancelCtx = context.WithCancel(log.WithLogField(ctx, "role", "retention"))
+	return rm, nil
+}
+
+func (rm *retentionManager) Start() {
+	if rm.conf.Window <= 0 {
+		log.L(rm.ctx).Debugf("Retention disabled")
+		return
+	}
+	log.L(rm.ctx).Infof("Retention enabled: window=%s interval=%s archive=%t", rm.conf.Window, rm.interval, rm.conf.Archive)
+	rm.started = true
+	go rm.pruneLoop()
+}
+
+func (rm *retentionManager) WaitStop() {
+	rm.cancelCtx()
+	if rm.started {
+		<-rm.done
+	}
+}
+
+func (rm *retentionManager) Status() *Status {
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	status := rm.status
+	return &status
+}
+
+func (rm *retentionManager) pruneLoop() {
+	defer close(rm.done)
+	for {
+		rm.prune()
+		select {
+		case <-time.After(rm.interval):
+		case <-rm.ctx.Done():
+			log.L(rm.ctx).Debugf("Retention loop exiting")
+			return
+		}
+	}
+}
+
+func (rm *retentionManager) prune() {
+	cutoff := fftypes.FFTime(time.Now().Add(-rm.conf.Window))
+	var pruned PrunedCounts
+	var err error
+	pruned.Events, err = rm.pruneEvents(&cutoff)
+	if err == nil {
+		pruned.Messages, err = rm.pruneMessages(&cutoff)
+	}
+	if err == nil {
+		pruned.Operations, err = rm.pruneOperations(&cutoff)
+	}
+	if err != nil {
+		log.L(rm.ctx).Errorf("Retention run failed: %s", err)
+	} else {
+		log.L(rm.ctx).Infof("Retention run complete: events=%d messages=%d operations=%d", pruned.Events, pruned.Messages, pruned.Operations)
+	}
+
+	rm.mux.Lock()
+	defer rm.mux.Unlock()
+	rm.status.LastRun = fftypes.Now()
+	rm.status.LastError = ""
+	if err != nil {
+		rm.status.LastError = err.Error()
+	}
+	rm.status.Pruned.Events += pruned.Events
+	rm.status.Pruned.Messages += pruned.Messages
+	rm.status.Pruned.Operations += pruned.Operations
+}
+
+// prunePages reads, archives and deletes one page of records at a time, until a short page is read.
+// The records and IDs returned for a page can be a subset of those read, when some must be retained.
+func (rm *retentionManager) prunePages(collection string, getPage func() (records interface{}, ids []*fftypes.UUID, full bool, err error), deleteRecords func(ids []*fftypes.UUID) error) (count int64, err error) {
+	for {
+		records, ids, full, err := getPage()
+		if err != nil {
+			return count, err
+		}
+		if len(ids) > 0 {
+			if err := rm.archive(collection, records); err != nil {
+				return count, err
+			}
+			if err := deleteRecords(ids); err != nil {
+				return count, err
+			}
+			count += int64(len(ids))
+		}
+		if !full {
+			return count, nil
+		}
+	}
+}
+
+func (rm *retentionManager) archive(collection string, records interface{}) error {
+	if !rm.conf.Archive || records == nil {
+		return nil
+	}
+	b, _ := json.Marshal(&archiveDocument{
+		Namespace:  rm.namespace,
+		Collection: collection,
+		Archived:   fftypes.Now(),
+		Records:    records,
+	})
+	payloadRef, err := rm.sharedstorage.UploadData(rm.ctx, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	log.L(rm.ctx).Infof("Archived %s to shared storage: %s", collection, payloadRef)
+	rm.mux.Lock()
+	rm.status.LastArchive = payloadRef
+	rm.mux.Unlock()
+	return nil
+}
+
+// lowestSubscriptionOffset returns the lowest offset of the durable subscriptions in the namespace, so that
+// events that have not yet been delivered to every subscription are not pruned. Returns false if there is no limit.
+// A subscription that does not yet have an offset has not been delivered anything, so every event is retained.
+func (rm *retentionManager) lowestSubscriptionOffset() (int64, bool, error) {
+	subs, _, err := rm.database.GetSubscriptions(rm.ctx, rm.namespace, database.SubscriptionQueryFactory.NewFilter(rm.ctx).And())
+	if err != nil || len(subs) == 0 {
+		return -1, false, err
+	}
+	names := make([]driver.Value, len(subs))
+	for i, sub := range subs {
+		names[i] = sub.ID.String()
+	}
+	fb := database.OffsetQueryFactory.NewFilter(rm.ctx)
+	offsets, _, err := rm.database.GetOffsets(rm.ctx, fb.And(
+		fb.Eq("type", core.OffsetTypeSubscription),
+		fb.In("name", names),
+	).Sort("current"))
+	if err != nil {
+		return -1, false, err
+	}
+	if len(offsets) < len(subs) {
+		return -1, true, nil
+	}
+	return offsets[0].Current, true, nil
+}
+
+// pruneEvents deletes events that have been delivered to every subscription. Only the ID and hash of each
+// event is archived, as an event can refer to a private message.
+func (rm *retentionManager) pruneEvents(cutoff *fftypes.FFTime) (int64, error) {
+	maxSequence, limited, err := rm.lowestSubscriptionOffset()
+	if err != nil {
+		return 0, err
+	}
+	fb := database.EventQueryFactory.NewFilter(rm.ctx)
+	conditions := []ffapi.Filter{fb.Lt("created", cutoff)}
+	if limited {
+		conditions = append(conditions, fb.Lte("sequence", maxSequence))
+	}
+	return rm.prunePages("events", func() (interface{}, []*fftypes.UUID, bool, error) {
+		events, _, err := rm.database.GetEvents(rm.ctx, rm.namespace, fb.And(conditions...).Sort("sequence").Limit(uint64(rm.batchSize)))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		ids := make([]*fftypes.UUID, len(events))
+		hashes := make([]*archivedRecordHash, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+			hashes[i] = recordHash(event.ID, event)
+		}
+		return hashes, ids, len(events) == rm.batchSize, nil
+	}, func(ids []*fftypes.UUID) error {
+		return rm.database.DeleteEvents(rm.ctx, rm.namespace, ids)
+	})
+}
+
+// pruneMessages deletes confirmed messages and their data. Definition messages are always retained, as identity,
+// network and verifier history are rebuilt from them, and so are messages still referenced by an event (such as
+// one not yet delivered to every subscription). Only broadcast messages and data are archived, as shared storage
+// is readable by every member of the network.
+func (rm *retentionManager) pruneMessages(cutoff *fftypes.FFTime) (int64, error) {
+	fb := database.MessageQueryFactory.NewFilter(rm.ctx)
+	var afterSequence int64 = -1
+	var dataIDs, broadcastDataIDs []*fftypes.UUID
+	return rm.prunePages("messages", func() (interface{}, []*fftypes.UUID, bool, error) {
+		msgs, _, err := rm.database.GetMessages(rm.ctx, rm.namespace, fb.And(
+			fb.Eq("state", core.MessageStateConfirmed),
+			fb.Lt("confirmed", cutoff),
+			fb.NotIn("type", []driver.Value{string(core.MessageTypeDefinition), string(core.MessageTypeGroupInit)}),
+			fb.Gt("sequence", afterSequence),
+		).Sort("sequence").Limit(uint64(rm.batchSize)))
+		if err != nil || len(msgs) == 0 {
+			return nil, nil, false, err
+		}
+		afterSequence = msgs[len(msgs)-1].Sequence
+		full := len(msgs) == rm.batchSize
+
+		referenced, err := rm.referencedByEvents(msgs)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		ids := make([]*fftypes.UUID, 0, len(msgs))
+		archived := make([]*core.Message, 0, len(msgs))
+		dataIDs, broadcastDataIDs = []*fftypes.UUID{}, []*fftypes.UUID{}
+		for _, msg := range msgs {
+			if referenced[*msg.Header.ID] {
+				continue
+			}
+			ids = append(ids, msg.Header.ID)
+			isBroadcast := msg.Header.Group == nil
+			if isBroadcast {
+				archived = append(archived, msg)
+			}
+			for _, dataRef := range msg.Data {
+				dataIDs = append(dataIDs, dataRef.ID)
+				if isBroadcast {
+					broadcastDataIDs = append(broadcastDataIDs, dataRef.ID)
+				}
+			}
+		}
+		if len(archived) == 0 {
+			return nil, ids, full, nil
+		}
+		return archived, ids, full, nil
+	}, func(ids []*fftypes.UUID) error {
+		if len(broadcastDataIDs) > 0 && rm.conf.Archive {
+			dfb := database.DataQueryFactory.NewFilter(rm.ctx)
+			for _, page := range uuidPages(broadcastDataIDs) {
+				data, _, err := rm.database.GetData(rm.ctx, rm.namespace, dfb.In("id", page))
+				if err == nil {
+					err = rm.archive("data", data)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if err := rm.database.DeleteMessages(rm.ctx, rm.namespace, ids); err != nil {
+			return err
+		}
+		// Data shared with messages that are not being pruned is retained
+		if len(dataIDs) > 0 {
+			return rm.database.DeleteUnreferencedData(rm.ctx, rm.namespace, dataIDs)
+		}
+		return nil
+	})
+}
+
+// referencedByEvents returns the IDs of the messages that are the reference of an event that has not been pruned
+func (rm *retentionManager) referencedByEvents(msgs []*core.Message) (map[fftypes.UUID]bool, error) {
+	msgIDs := make([]*fftypes.UUID, len(msgs))
+	for i, msg := range msgs {
+		msgIDs[i] = msg.Header.ID
+	}
+	fb := database.EventQueryFactory.NewFilter(rm.ctx)
+	referenced := make(map[fftypes.UUID]bool)
+	for _, page := range uuidPages(msgIDs) {
+		events, _, err := rm.database.GetEvents(rm.ctx, rm.namespace, fb.In("reference", page))
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.Reference != nil {
+				referenced[*event.Reference] = true
+			}
+		}
+	}
+	return referenced, nil
+}
+
+// pruneOperations deletes completed operations. Only the ID and hash of each operation is archived, as the
+// input and output of an operation can contain private data.
+func (rm *retentionManager) pruneOperations(cutoff *fftypes.FFTime) (int64, error) {
+	fb := database.OperationQueryFactory.NewFilter(rm.ctx)
+	return rm.prunePages("operations", func() (interface{}, []*fftypes.UUID, bool, error) {
+		ops, _, err := rm.database.GetOperations(rm.ctx, rm.namespace, fb.And(
+			fb.In("status", []driver.Value{string(core.OpStatusSucceeded), string(core.OpStatusFailed)}),
+			fb.Lt("updated", cutoff),
+		).Sort("created").Limit(uint64(rm.batchSize)))
+		if err != nil {
+			return nil, nil, false, err
+		}
+		ids := make([]*fftypes.UUID, len(ops))
+		hashes := make([]*archivedRecordHash, len(ops))
+		for i, op := range ops {
+			ids[i] = op.ID
+			hashes[i] = recordHash(op.ID, op)
+		}
+		return hashes, ids, len(ops) == rm.batchSize, nil
+	}, func(ids []*fftypes.UUID) error {
+		return rm.database.DeleteOperations(rm.ctx, rm.namespace, ids)
+	})
+}
+
+// uuidPages splits a list of IDs into pages of at most maxInValues values, for use in IN queries
+func uuidPages(ids []*fftypes.UUID) [][]driver.Value {
+	pages := make([][]driver.Value, 0, (len(ids)+maxInValues-1)/maxInValues)
+	for start := 0; start < len(ids); start += maxInValues {
+		end := start + maxInValues
+		if end > len(ids) {
+			end = len(ids)
+		}
+		page := make([]driver.Value, end-start)
+		for i, id := range ids[start:end] {
+			page[i] = id
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
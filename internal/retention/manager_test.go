@@ -0,0 +1,382 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRetentionManager(t *testing.T, conf Config) (*retentionManager, *databasemocks.Plugin, *sharedstoragemocks.Plugin, func()) {
+	coreconfig.Reset()
+	config.Set(coreconfig.RetentionBatchSize, 2)
+	mdi := &databasemocks.Plugin{}
+	mss := &sharedstoragemocks.Plugin{}
+	rm, err := NewRetentionManager(context.Background(), "ns1", conf, mdi, mss)
+	assert.NoError(t, err)
+	return rm.(*retentionManager), mdi, mss, func() {
+		rm.WaitStop()
+		mdi.AssertExpectations(t)
+		mss.AssertExpectations(t)
+	}
+}
+
+func TestNewRetentionManagerMissingDeps(t *testing.T) {
+	_, err := NewRetentionManager(context.Background(), "ns1", Config{}, nil, nil)
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestNewRetentionManagerArchiveNoSharedStorage(t *testing.T) {
+	_, err := NewRetentionManager(context.Background(), "ns1", Config{Archive: true}, &databasemocks.Plugin{}, nil)
+	assert.Regexp(t, "FF10578", err)
+}
+
+func TestStartDisabled(t *testing.T) {
+	rm, _, _, done := newTestRetentionManager(t, Config{})
+	defer done()
+	rm.Start()
+	assert.False(t, rm.started)
+	assert.False(t, rm.Status().Enabled)
+}
+
+func TestStartPruneStop(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+
+	pruned := make(chan struct{})
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil)
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil).Run(func(args mock.Arguments) {
+		close(pruned)
+	}).Once()
+
+	rm.Start()
+	<-pruned
+	done()
+
+	status := rm.Status()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, fftypes.FFDuration(time.Hour), status.Window)
+	assert.NotNil(t, status.LastRun)
+	assert.Empty(t, status.LastError)
+}
+
+func TestPruneArchiveAll(t *testing.T) {
+	rm, mdi, mss, done := newTestRetentionManager(t, Config{Window: time.Hour, Archive: true})
+	defer done()
+
+	sub1 := fftypes.NewUUID()
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{ID: sub1}},
+	}, nil, nil)
+	mdi.On("GetOffsets", mock.Anything, mock.Anything).Return([]*core.Offset{
+		{Type: core.OffsetTypeSubscription, Name: sub1.String(), Current: 100},
+	}, nil, nil)
+
+	ev1, ev2, ev3 := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{ID: ev1}, {ID: ev2}}, nil, nil).Once()
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{ID: ev3}}, nil, nil).Once()
+	mdi.On("DeleteEvents", mock.Anything, "ns1", []*fftypes.UUID{ev1, ev2}).Return(nil)
+	mdi.On("DeleteEvents", mock.Anything, "ns1", []*fftypes.UUID{ev3}).Return(nil)
+
+	msg1, data1 := fftypes.NewUUID(), fftypes.NewUUID()
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: msg1}, Data: core.DataRefs{{ID: data1}}},
+	}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil).Once()
+	mdi.On("GetData", mock.Anything, "ns1", mock.Anything).Return(core.DataArray{{ID: data1}}, nil, nil)
+	mdi.On("DeleteMessages", mock.Anything, "ns1", []*fftypes.UUID{msg1}).Return(nil)
+	mdi.On("DeleteUnreferencedData", mock.Anything, "ns1", []*fftypes.UUID{data1}).Return(nil)
+
+	op1 := fftypes.NewUUID()
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{ID: op1, Input: fftypes.JSONObject{"secret": "value"}},
+	}, nil, nil)
+	mdi.On("DeleteOperations", mock.Anything, "ns1", []*fftypes.UUID{op1}).Return(nil)
+
+	archived := make(map[string][]map[string]interface{})
+	mss.On("UploadData", mock.Anything, mock.Anything).Return("ref1", nil).Run(func(args mock.Arguments) {
+		b, err := io.ReadAll(args[1].(io.Reader))
+		assert.NoError(t, err)
+		var doc struct {
+			Collection string                   `json:"collection"`
+			Records    []map[string]interface{} `json:"records"`
+		}
+		err = json.Unmarshal(b, &doc)
+		assert.NoError(t, err)
+		archived[doc.Collection] = append(archived[doc.Collection], doc.Records...)
+	})
+
+	rm.prune()
+
+	status := rm.Status()
+	assert.Empty(t, status.LastError)
+	assert.Equal(t, "ref1", status.LastArchive)
+	assert.Equal(t, PrunedCounts{Events: 3, Messages: 1, Operations: 1}, status.Pruned)
+	mss.AssertNumberOfCalls(t, "UploadData", 5)
+
+	// Events and operations are archived as an ID and hash only
+	assert.Len(t, archived["events"], 3)
+	for _, record := range append(archived["events"], archived["operations"]...) {
+		assert.Len(t, record, 2)
+		assert.Contains(t, record, "id")
+		assert.Contains(t, record, "hash")
+	}
+	assert.Equal(t, op1.String(), archived["operations"][0]["id"])
+	assert.Equal(t, recordHash(op1, &core.Operation{ID: op1, Input: fftypes.JSONObject{"secret": "value"}}).Hash.String(), archived["operations"][0]["hash"])
+	assert.Equal(t, msg1.String(), archived["messages"][0]["header"].(map[string]interface{})["id"])
+}
+
+func TestPruneMessagesNoData(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	msg1 := fftypes.NewUUID()
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: msg1}},
+	}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil)
+	mdi.On("DeleteMessages", mock.Anything, "ns1", []*fftypes.UUID{msg1}).Return(nil)
+
+	count, err := rm.pruneMessages(fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestPruneSubscriptionsFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	rm.prune()
+	assert.Equal(t, "pop", rm.Status().LastError)
+}
+
+func TestPruneEventsNoOffsets(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdi.On("GetOffsets", mock.Anything, mock.Anything).Return([]*core.Offset{}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "sequence <= -1")
+	})).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.pruneEvents(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestLowestSubscriptionOffsetMissingOffset(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	sub1, sub2 := fftypes.NewUUID(), fftypes.NewUUID()
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{ID: sub1}},
+		{SubscriptionRef: core.SubscriptionRef{ID: sub2}},
+	}, nil, nil)
+	mdi.On("GetOffsets", mock.Anything, mock.Anything).Return([]*core.Offset{
+		{Type: core.OffsetTypeSubscription, Name: sub1.String(), Current: 100},
+	}, nil, nil)
+
+	lowest, limited, err := rm.lowestSubscriptionOffset()
+	assert.NoError(t, err)
+	assert.True(t, limited)
+	assert.Equal(t, int64(-1), lowest)
+}
+
+func TestLowestSubscriptionOffsetFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdi.On("GetOffsets", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, _, err := rm.lowestSubscriptionOffset()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneEventsArchiveFail(t *testing.T) {
+	rm, mdi, mss, done := newTestRetentionManager(t, Config{Window: time.Hour, Archive: true})
+	defer done()
+
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{ID: fftypes.NewUUID()}}, nil, nil)
+	mss.On("UploadData", mock.Anything, mock.Anything).Return("", fmt.Errorf("pop"))
+
+	_, err := rm.pruneEvents(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneEventsDeleteFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{ID: fftypes.NewUUID()}}, nil, nil)
+	mdi.On("DeleteEvents", mock.Anything, "ns1", mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := rm.pruneEvents(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneMessagesFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.pruneMessages(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneMessagesRetainsDefinitionsAndReferenced(t *testing.T) {
+	rm, mdi, mss, done := newTestRetentionManager(t, Config{Window: time.Hour, Archive: true})
+	defer done()
+
+	msg1, msg2, msg3 := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	data2, data3 := fftypes.NewUUID(), fftypes.NewUUID()
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "type NI ['definition','groupinit']") &&
+			strings.Contains(fi.String(), "sequence >> -1")
+	})).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: msg1}, Sequence: 10},
+		{Header: core.MessageHeader{ID: msg2, Group: fftypes.NewRandB32()}, Data: core.DataRefs{{ID: data2}}, Sequence: 11},
+	}, nil, nil).Once()
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "sequence >> 11")
+	})).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: msg3}, Data: core.DataRefs{{ID: data3}}, Sequence: 12},
+	}, nil, nil).Once()
+	// msg1 has an event that has not been pruned, as it is not yet delivered to every subscription
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Reference: msg1}}, nil, nil).Once()
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil).Once()
+	// msg2 is private, so it is deleted but not archived
+	mdi.On("DeleteMessages", mock.Anything, "ns1", []*fftypes.UUID{msg2}).Return(nil)
+	mdi.On("DeleteUnreferencedData", mock.Anything, "ns1", []*fftypes.UUID{data2}).Return(nil)
+	mdi.On("GetData", mock.Anything, "ns1", mock.Anything).Return(core.DataArray{{ID: data3}}, nil, nil)
+	mdi.On("DeleteMessages", mock.Anything, "ns1", []*fftypes.UUID{msg3}).Return(nil)
+	mdi.On("DeleteUnreferencedData", mock.Anything, "ns1", []*fftypes.UUID{data3}).Return(nil)
+	mss.On("UploadData", mock.Anything, mock.Anything).Return("ref1", nil).Twice()
+
+	count, err := rm.pruneMessages(fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestPruneMessagesReferencedByEventsPaged(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+	rm.batchSize = maxInValues + 1
+
+	msgs := make([]*core.Message, maxInValues+1)
+	for i := range msgs {
+		msgs[i] = &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Sequence: int64(i)}
+	}
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return(msgs, nil, nil).Once()
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil).Once()
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Count(fi.String(), ",") == maxInValues-1
+	})).Return([]*core.Event{{Reference: msgs[0].Header.ID}}, nil, nil).Once()
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), msgs[maxInValues].Header.ID.String())
+	})).Return([]*core.Event{{Reference: msgs[maxInValues].Header.ID}}, nil, nil).Once()
+	mdi.On("DeleteMessages", mock.Anything, "ns1", mock.MatchedBy(func(ids []*fftypes.UUID) bool {
+		return len(ids) == maxInValues-1
+	})).Return(nil)
+
+	count, err := rm.pruneMessages(fftypes.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(maxInValues-1), count)
+}
+
+func TestPruneMessagesGetEventsFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.pruneMessages(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneMessagesGetDataFail(t *testing.T) {
+	rm, mdi, mss, done := newTestRetentionManager(t, Config{Window: time.Hour, Archive: true})
+	defer done()
+
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Data: core.DataRefs{{ID: fftypes.NewUUID()}}},
+	}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil)
+	mdi.On("GetData", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mss.On("UploadData", mock.Anything, mock.Anything).Return("ref1", nil).Once()
+
+	_, err := rm.pruneMessages(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneMessagesDeleteFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{
+		{Header: core.MessageHeader{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil)
+	mdi.On("DeleteMessages", mock.Anything, "ns1", mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := rm.pruneMessages(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneOperationsFail(t *testing.T) {
+	rm, mdi, _, done := newTestRetentionManager(t, Config{Window: time.Hour})
+	defer done()
+
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.pruneOperations(fftypes.Now())
+	assert.EqualError(t, err, "pop")
+}
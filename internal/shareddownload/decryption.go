@@ -0,0 +1,69 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareddownload
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/encryption"
+)
+
+// decryptBatch restores any values in a downloaded batch that were encrypted before it was published, so the
+// data hashes can be verified as normal. A batch that cannot be parsed is passed through, to be rejected later.
+// If a value is encrypted with a key this node does not hold its hash cannot be verified, so the download fails
+// and can be retried once the key is available.
+func (dm *downloadManager) decryptBatch(ctx context.Context, batchBytes []byte) ([]byte, error) {
+	var batch core.Batch
+	if err := json.Unmarshal(batchBytes, &batch); err != nil {
+		return batchBytes, nil
+	}
+	decrypted := false
+	for _, d := range batch.Payload.Data {
+		if d == nil {
+			continue
+		}
+		if ev := encryption.ParseEncryptedValue(d.Value); ev != nil {
+			value, err := encryption.DecryptValue(ctx, dm.keys, ev)
+			if err != nil {
+				return nil, err
+			}
+			d.Value = value
+			decrypted = true
+		}
+	}
+	if !decrypted {
+		return batchBytes, nil
+	}
+	log.L(ctx).Debugf("Decrypted values in batch '%s'", batch.ID)
+	return json.Marshal(&batch)
+}
+
+// decryptBlob returns a reader for the original content of a blob. An encrypted blob is decrypted one chunk at
+// a time as it streams through, while any other blob is streamed through unchanged.
+func (dm *downloadManager) decryptBlob(ctx context.Context, reader io.Reader) (io.Reader, error) {
+	bufferedReader := bufio.NewReader(reader)
+	start, _ := bufferedReader.Peek(encryption.BlobMagicLength())
+	if !encryption.IsEncryptedBlob(start) {
+		return bufferedReader, nil
+	}
+	return encryption.DecryptBlob(ctx, dm.keys, bufferedReader)
+}
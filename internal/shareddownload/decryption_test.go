@@ -0,0 +1,266 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shareddownload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/encryptionmocks"
+	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestKeyProvider() *encryptionmocks.KeyProvider {
+	mkp := &encryptionmocks.KeyProvider{}
+	mkp.On("Key", mock.Anything, "key1").Return(make([]byte, 32), nil).Maybe()
+	mkp.On("Key", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return mkp
+}
+
+func newTestEncryptedBatch(t *testing.T, mkp *encryptionmocks.KeyProvider, keyID string) (*core.Batch, []byte) {
+	batch := &core.Batch{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Payload: core.BatchPayload{
+			Data: core.DataArray{
+				{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"some":"data"}`)},
+				{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"public"`)},
+				nil,
+			},
+		},
+	}
+	encrypted, err := encryption.EncryptValue(context.Background(), mkp, "key1", batch.Payload.Data[0].Value)
+	assert.NoError(t, err)
+	if keyID != "key1" {
+		ev := encryption.ParseEncryptedValue(encrypted)
+		ev.KeyID = keyID
+		encrypted = fftypes.JSONAnyPtr(fmt.Sprintf(`{"$ffencrypted":{"keyId":"%s","ciphertext":"%s"}}`, ev.KeyID, ev.Ciphertext))
+	}
+	published := *batch
+	published.Payload.Data = core.DataArray{
+		{ID: batch.Payload.Data[0].ID, Value: encrypted},
+		batch.Payload.Data[1],
+		nil,
+	}
+	batchBytes, err := json.Marshal(&published)
+	assert.NoError(t, err)
+	return batch, batchBytes
+}
+
+func newTestEncryptedBlob(t *testing.T, keyID, content string) []byte {
+	encrypter, err := encryption.EncryptBlob(context.Background(), newTestKeyProvider(), keyID, strings.NewReader(content))
+	assert.NoError(t, err)
+	encrypted, err := io.ReadAll(encrypter)
+	assert.NoError(t, err)
+	return encrypted
+}
+
+func TestDownloadBatchDecrypted(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	mkp := newTestKeyProvider()
+	dm.keys = mkp
+
+	batch, batchBytes := newTestEncryptedBatch(t, mkp, "key1")
+	reader := ioutil.NopCloser(strings.NewReader(string(batchBytes)))
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
+
+	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
+	mci.On("SharedStorageBatchDownloaded", "ref1", mock.MatchedBy(func(b []byte) bool {
+		var downloaded core.Batch
+		err := json.Unmarshal(b, &downloaded)
+		return err == nil &&
+			downloaded.Payload.Data[0].Value.String() == `{"some":"data"}` &&
+			downloaded.Payload.Data[1].Value.String() == `"public"`
+	})).Return(batch.ID, nil)
+
+	outputs, phase, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, batch.ID, outputs["batch"])
+
+	mss.AssertExpectations(t)
+	mci.AssertExpectations(t)
+}
+
+func TestDownloadBatchDecryptKeyNotFound(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	mkp := newTestKeyProvider()
+	dm.keys = mkp
+
+	_, batchBytes := newTestEncryptedBatch(t, mkp, "unknown")
+	reader := ioutil.NopCloser(strings.NewReader(string(batchBytes)))
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
+
+	_, phase, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+	})
+	assert.Regexp(t, "FF10551", err)
+	assert.Equal(t, core.OpPhasePending, phase)
+
+	mss.AssertExpectations(t)
+}
+
+func TestDownloadBatchDecryptFail(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	mkp := &encryptionmocks.KeyProvider{}
+	mkp.On("Key", mock.Anything, "bad").Return([]byte("short"), nil)
+	dm.keys = mkp
+
+	_, batchBytes := newTestEncryptedBatch(t, newTestKeyProvider(), "bad")
+	reader := ioutil.NopCloser(strings.NewReader(string(batchBytes)))
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
+
+	_, phase, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+	})
+	assert.Regexp(t, "FF10550.*bad", err)
+	assert.Equal(t, core.OpPhasePending, phase)
+
+	mss.AssertExpectations(t)
+}
+
+func TestDecryptBatchNotEncrypted(t *testing.T) {
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	batchBytes := []byte(`{"payload":{"data":[{"value":"public"}]}}`)
+	result, err := dm.decryptBatch(dm.ctx, batchBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, batchBytes, result)
+
+	result, err = dm.decryptBatch(dm.ctx, []byte("!json"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("!json"), result)
+}
+
+func TestDownloadBlobDecrypted(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	mkp := newTestKeyProvider()
+	dm.keys = mkp
+
+	encrypted := newTestEncryptedBlob(t, "key1", "some blob")
+	reader := ioutil.NopCloser(strings.NewReader(string(encrypted)))
+	dataID := fftypes.NewUUID()
+	blobHash := fftypes.NewRandB32()
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
+
+	mdx := dm.dataexchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", mock.Anything, "ns1", *dataID, mock.MatchedBy(func(r io.Reader) bool {
+		content, _ := io.ReadAll(r)
+		return string(content) == "some blob"
+	})).Return("privateRef1", blobHash, int64(9), nil)
+
+	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
+	mci.On("SharedStorageBlobDownloaded", *blobHash, int64(9), "privateRef1", dataID).Return(nil)
+
+	_, phase, err := dm.downloadBlob(dm.ctx, downloadBlobData{
+		PayloadRef: "ref1",
+		DataID:     dataID,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+
+	mss.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mci.AssertExpectations(t)
+}
+
+func TestDownloadBlobDecryptKeyNotFound(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	encrypted := newTestEncryptedBlob(t, "key1", "some blob")
+	reader := ioutil.NopCloser(strings.NewReader(string(encrypted)))
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
+
+	_, phase, err := dm.downloadBlob(dm.ctx, downloadBlobData{
+		PayloadRef: "ref1",
+		DataID:     fftypes.NewUUID(),
+	})
+	assert.Regexp(t, "FF10551", err)
+	assert.Equal(t, core.OpPhasePending, phase)
+
+	mss.AssertExpectations(t)
+}
+
+func TestDownloadBlobDecryptFail(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	dm.keys = newTestKeyProvider()
+
+	reader := ioutil.NopCloser(strings.NewReader("FFENC1:key1\nshort"))
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
+
+	_, phase, err := dm.downloadBlob(dm.ctx, downloadBlobData{
+		PayloadRef: "ref1",
+		DataID:     fftypes.NewUUID(),
+	})
+	assert.Regexp(t, "FF10552", err)
+	assert.Equal(t, core.OpPhasePending, phase)
+
+	mss.AssertExpectations(t)
+}
+
+func TestDecryptBlobReadFail(t *testing.T) {
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	dm.keys = newTestKeyProvider()
+
+	reader := io.MultiReader(strings.NewReader("FFENC1:key1\nnonce12"), iotest.ErrReader(fmt.Errorf("pop")))
+	decrypter, err := dm.decryptBlob(dm.ctx, reader)
+	assert.NoError(t, err)
+	_, err = io.ReadAll(decrypter)
+	assert.EqualError(t, err, "pop")
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/encryption"
 	"github.com/hyperledger/firefly/pkg/sharedstorage"
 )
 
@@ -39,7 +40,7 @@ type Manager interface {
 	Start() error
 	WaitStop()
 
-	InitiateDownloadBatch(ctx context.Context, tx *fftypes.UUID, payloadRef string, idempotentSubmit bool) error
+	InitiateDownloadBatch(ctx context.Context, tx *fftypes.UUID, payloadRef string, batchHash *fftypes.Bytes32, idempotentSubmit bool) error
 	InitiateDownloadBlob(ctx context.Context, tx *fftypes.UUID, dataID *fftypes.UUID, payloadRef string, idempotentSubmit bool) error
 }
 
@@ -66,6 +67,8 @@ type downloadManager struct {
 	retryInitDelay             time.Duration
 	retryMaxDelay              time.Duration
 	retryFactor                float64
+	verifyPayloadHash          bool
+	keys                       encryption.KeyProvider // optional
 }
 
 type downloadWork struct {
@@ -80,7 +83,7 @@ type Callbacks interface {
 	SharedStorageBlobDownloaded(hash fftypes.Bytes32, size int64, payloadRef string, dataID *fftypes.UUID) error
 }
 
-func NewDownloadManager(ctx context.Context, ns *core.Namespace, di database.Plugin, ss sharedstorage.Plugin, dx dataexchange.Plugin, om operations.Manager, cb Callbacks) (Manager, error) {
+func NewDownloadManager(ctx context.Context, ns *core.Namespace, di database.Plugin, ss sharedstorage.Plugin, dx dataexchange.Plugin, om operations.Manager, cb Callbacks, kp encryption.KeyProvider) (Manager, error) {
 	if di == nil || dx == nil || ss == nil || cb == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "DownloadManager")
 	}
@@ -101,6 +104,8 @@ func NewDownloadManager(ctx context.Context, ns *core.Namespace, di database.Plu
 		retryInitDelay:             config.GetDuration(coreconfig.DownloadRetryInitDelay),
 		retryMaxDelay:              config.GetDuration(coreconfig.DownloadRetryMaxDelay),
 		retryFactor:                config.GetFloat64(coreconfig.DownloadRetryFactor),
+		verifyPayloadHash:          config.GetBool(coreconfig.DownloadVerifyPayloadHash),
+		keys:                       kp,
 	}
 	// Work queue is twice the size of the worker count
 	workQueueLength := config.GetInt(coreconfig.DownloadWorkerQueueLength)
@@ -225,10 +230,10 @@ func (dm *downloadManager) waitAndRetryDownload(work *downloadWork) {
 	dm.dispatchWork(work)
 }
 
-func (dm *downloadManager) InitiateDownloadBatch(ctx context.Context, tx *fftypes.UUID, payloadRef string, idempotentSubmit bool) error {
+func (dm *downloadManager) InitiateDownloadBatch(ctx context.Context, tx *fftypes.UUID, payloadRef string, batchHash *fftypes.Bytes32, idempotentSubmit bool) error {
 	op := core.NewOperation(dm.sharedstorage, dm.namespace.Name, tx, core.OpTypeSharedStorageDownloadBatch)
-	addDownloadBatchInputs(op, payloadRef)
-	return dm.createAndDispatchOp(ctx, op, opDownloadBatch(op, payloadRef), idempotentSubmit)
+	addDownloadBatchInputs(op, payloadRef, batchHash)
+	return dm.createAndDispatchOp(ctx, op, opDownloadBatch(op, payloadRef, batchHash), idempotentSubmit)
 }
 
 func (dm *downloadManager) InitiateDownloadBlob(ctx context.Context, tx *fftypes.UUID, dataID *fftypes.UUID, payloadRef string, idempotentSubmit bool) error {
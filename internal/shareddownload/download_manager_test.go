@@ -56,14 +56,14 @@ func newTestDownloadManager(t *testing.T) (*downloadManager, func()) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	pm, err := NewDownloadManager(ctx, ns, mdi, mss, mdx, mom, mci)
+	pm, err := NewDownloadManager(ctx, ns, mdi, mss, mdx, mom, mci, nil)
 	assert.NoError(t, err)
 
 	return pm.(*downloadManager), cancel
 }
 
 func TestNewDownloadManagerMissingDeps(t *testing.T) {
-	_, err := NewDownloadManager(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil)
+	_, err := NewDownloadManager(context.Background(), &core.Namespace{}, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -112,7 +112,7 @@ func TestDownloadBatchE2EOk(t *testing.T) {
 	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
 	mci.On("SharedStorageBatchDownloaded", "ref1", []byte("some batch data")).Return(batchID, nil)
 
-	err := dm.InitiateDownloadBatch(dm.ctx, txID, "ref1", false)
+	err := dm.InitiateDownloadBatch(dm.ctx, txID, "ref1", nil, false)
 	assert.NoError(t, err)
 
 	<-called
@@ -336,3 +336,33 @@ func TestRunOperationUnknown(t *testing.T) {
 	})
 	assert.Regexp(t, "FF10378", err)
 }
+
+func TestPrepareOperationDownloadBatchWithHash(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	batchHash := fftypes.NewRandB32()
+	op := &core.Operation{
+		Type: core.OpTypeSharedStorageDownloadBatch,
+	}
+	addDownloadBatchInputs(op, "ref1", batchHash)
+	po, err := dm.PrepareOperation(dm.ctx, op)
+	assert.NoError(t, err)
+	assert.Equal(t, downloadBatchData{PayloadRef: "ref1", BatchHash: batchHash}, po.Data)
+}
+
+func TestPrepareOperationDownloadBatchBadHash(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	_, err := dm.PrepareOperation(dm.ctx, &core.Operation{
+		Type: core.OpTypeSharedStorageDownloadBatch,
+		Input: fftypes.JSONObject{
+			"payloadRef": "ref1",
+			"batchHash":  "!wrong",
+		},
+	})
+	assert.Regexp(t, "FF00107", err)
+}
@@ -18,6 +18,7 @@ package shareddownload
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 
 	"github.com/docker/go-units"
@@ -29,7 +30,8 @@ import (
 )
 
 type downloadBatchData struct {
-	PayloadRef string `json:"payloadRef"`
+	PayloadRef string           `json:"payloadRef"`
+	BatchHash  *fftypes.Bytes32 `json:"batchHash,omitempty"`
 }
 
 type downloadBlobData struct {
@@ -37,10 +39,13 @@ type downloadBlobData struct {
 	PayloadRef string        `json:"payloadRef"`
 }
 
-func addDownloadBatchInputs(op *core.Operation, payloadRef string) {
+func addDownloadBatchInputs(op *core.Operation, payloadRef string, batchHash *fftypes.Bytes32) {
 	op.Input = fftypes.JSONObject{
 		"payloadRef": payloadRef,
 	}
+	if batchHash != nil {
+		op.Input["batchHash"] = batchHash.String()
+	}
 }
 
 func getDownloadBatchOutputs(batchID *fftypes.UUID) fftypes.JSONObject {
@@ -64,8 +69,16 @@ func getDownloadBlobOutputs(hash *fftypes.Bytes32, size int64, dxPayloadRef stri
 	}
 }
 
-func retrieveDownloadBatchInputs(op *core.Operation) (payloadRef string) {
-	return op.Input.GetString("payloadRef")
+func retrieveDownloadBatchInputs(ctx context.Context, op *core.Operation) (payloadRef string, batchHash *fftypes.Bytes32, err error) {
+	payloadRef = op.Input.GetString("payloadRef")
+	// Operations created before the hash was recorded have no batchHash, and are not verified
+	if hashStr := op.Input.GetString("batchHash"); hashStr != "" {
+		batchHash, err = fftypes.ParseBytes32(ctx, hashStr)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return payloadRef, batchHash, nil
 }
 
 func retrieveDownloadBlobInputs(ctx context.Context, op *core.Operation) (dataID *fftypes.UUID, payloadRef string, err error) {
@@ -81,8 +94,11 @@ func (dm *downloadManager) PrepareOperation(ctx context.Context, op *core.Operat
 	switch op.Type {
 
 	case core.OpTypeSharedStorageDownloadBatch:
-		payloadRef := retrieveDownloadBatchInputs(op)
-		return opDownloadBatch(op, payloadRef), nil
+		payloadRef, batchHash, err := retrieveDownloadBatchInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		return opDownloadBatch(op, payloadRef, batchHash), nil
 
 	case core.OpTypeSharedStorageDownloadBlob:
 		dataID, payloadRef, err := retrieveDownloadBlobInputs(ctx, op)
@@ -129,6 +145,17 @@ func (dm *downloadManager) downloadBatch(ctx context.Context, data downloadBatch
 		return nil, core.OpPhasePending, i18n.WrapError(ctx, err, coremsgs.MsgDownloadBatchMaxBytes, data.PayloadRef)
 	}
 
+	// Check the content has not been tampered with by the storage gateway, before we accept it
+	if dm.verifyPayloadHash && data.BatchHash != nil {
+		if err := verifyBatchHash(ctx, data, batchBytes); err != nil {
+			return nil, core.OpPhasePending, err
+		}
+	}
+
+	if batchBytes, err = dm.decryptBatch(ctx, batchBytes); err != nil {
+		return nil, core.OpPhasePending, err
+	}
+
 	// Parse and store the batch
 	batchID, err := dm.callbacks.SharedStorageBatchDownloaded(data.PayloadRef, batchBytes)
 	if err != nil {
@@ -137,6 +164,23 @@ func (dm *downloadManager) downloadBatch(ctx context.Context, data downloadBatch
 	return getDownloadBatchOutputs(batchID), core.OpPhaseComplete, nil
 }
 
+// verifyBatchHash checks that the hash of the manifest of the downloaded batch matches the hash pinned to the blockchain
+func verifyBatchHash(ctx context.Context, data downloadBatchData, batchBytes []byte) error {
+	var batch core.Batch
+	var found *fftypes.Bytes32
+	if err := json.Unmarshal(batchBytes, &batch); err == nil {
+		found = fftypes.HashString(batch.Payload.Manifest(batch.ID).String())
+		if !found.Equals(data.BatchHash) && batch.Payload.Hash().Equals(data.BatchHash) {
+			// Batches written by v0.13 and older environments are pinned with a hash of the whole payload
+			found = batch.Payload.Hash()
+		}
+	}
+	if !found.Equals(data.BatchHash) {
+		return i18n.NewError(ctx, coremsgs.MsgDownloadBatchHashMismatch, data.PayloadRef, data.BatchHash, found)
+	}
+	return nil
+}
+
 func (dm *downloadManager) downloadBlob(ctx context.Context, data downloadBlobData) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
 
 	// Stream from shared storage ...
@@ -145,9 +189,13 @@ func (dm *downloadManager) downloadBlob(ctx context.Context, data downloadBlobDa
 		return nil, core.OpPhasePending, err
 	}
 	defer reader.Close()
+	blobReader, err := dm.decryptBlob(ctx, reader)
+	if err != nil {
+		return nil, core.OpPhasePending, err
+	}
 
 	// ... to data exchange
-	dxPayloadRef, hash, blobSize, err := dm.dataexchange.UploadBlob(ctx, dm.namespace.NetworkName, *data.DataID, reader)
+	dxPayloadRef, hash, blobSize, err := dm.dataexchange.UploadBlob(ctx, dm.namespace.NetworkName, *data.DataID, blobReader)
 	if err != nil {
 		return nil, core.OpPhasePending, i18n.WrapError(ctx, err, coremsgs.MsgDownloadSharedFailed, data.PayloadRef)
 	}
@@ -165,7 +213,7 @@ func (dm *downloadManager) OnOperationUpdate(ctx context.Context, op *core.Opera
 	return nil
 }
 
-func opDownloadBatch(op *core.Operation, payloadRef string) *core.PreparedOperation {
+func opDownloadBatch(op *core.Operation, payloadRef string, batchHash *fftypes.Bytes32) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
 		Namespace: op.Namespace,
@@ -173,6 +221,7 @@ func opDownloadBatch(op *core.Operation, payloadRef string) *core.PreparedOperat
 		Type:      op.Type,
 		Data: downloadBatchData{
 			PayloadRef: payloadRef,
+			BatchHash:  batchHash,
 		},
 	}
 }
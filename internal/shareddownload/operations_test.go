@@ -19,6 +19,7 @@ package shareddownload
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -29,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -119,7 +121,7 @@ func TestDownloadBlobDownloadDataReadFail(t *testing.T) {
 	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
 
 	mdx := dm.dataexchange.(*dataexchangemocks.Plugin)
-	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, reader).Return("", nil, int64(-1), fmt.Errorf("pop"))
+	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(-1), fmt.Errorf("pop"))
 
 	_, _, err := dm.downloadBlob(dm.ctx, downloadBlobData{
 		PayloadRef: "ref1",
@@ -142,7 +144,7 @@ func TestDownloadBlobDownloadDataCBCalled(t *testing.T) {
 	mss.On("DownloadData", mock.Anything, "ref1").Return(reader, nil)
 
 	mdx := dm.dataexchange.(*dataexchangemocks.Plugin)
-	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, reader).Return("", fftypes.NewRandB32(), int64(-1), nil)
+	mdx.On("UploadBlob", mock.Anything, "ns1", mock.Anything, mock.Anything).Return("", fftypes.NewRandB32(), int64(-1), nil)
 
 	mdc := &shareddownloadmocks.Callbacks{}
 	mdc.On("SharedStorageBlobDownloaded", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
@@ -163,3 +165,134 @@ func TestOperationUpdate(t *testing.T) {
 	defer cancel()
 	assert.NoError(t, dm.OnOperationUpdate(context.Background(), nil, nil))
 }
+
+func testDownloadedBatch() (*core.Batch, []byte) {
+	batch := &core.Batch{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Payload: core.BatchPayload{
+			TX: core.TransactionRef{
+				Type: core.TransactionTypeBatchPin,
+				ID:   fftypes.NewUUID(),
+			},
+			Messages: []*core.Message{{
+				Header: core.MessageHeader{ID: fftypes.NewUUID()},
+				Hash:   fftypes.NewRandB32(),
+			}},
+		},
+	}
+	batch.Hash = fftypes.HashString(batch.Payload.Manifest(batch.ID).String())
+	batchBytes, _ := json.Marshal(batch)
+	return batch, batchBytes
+}
+
+func TestDownloadBatchVerifyHashOk(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	batch, batchBytes := testDownloadedBatch()
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(ioutil.NopCloser(bytes.NewReader(batchBytes)), nil)
+
+	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
+	mci.On("SharedStorageBatchDownloaded", "ref1", batchBytes).Return(batch.ID, nil)
+
+	_, phase, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+		BatchHash:  batch.Hash,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+
+	mss.AssertExpectations(t)
+	mci.AssertExpectations(t)
+}
+
+func TestDownloadBatchVerifyHashMismatch(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	_, batchBytes := testDownloadedBatch()
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(ioutil.NopCloser(bytes.NewReader(batchBytes)), nil)
+
+	_, phase, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+		BatchHash:  fftypes.NewRandB32(),
+	})
+	assert.Regexp(t, "FF10489", err)
+	assert.Equal(t, core.OpPhasePending, phase)
+
+	mss.AssertExpectations(t)
+}
+
+func TestDownloadBatchVerifyHashNotJSON(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(ioutil.NopCloser(strings.NewReader("!json")), nil)
+
+	_, _, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+		BatchHash:  fftypes.NewRandB32(),
+	})
+	assert.Regexp(t, "FF10489", err)
+
+	mss.AssertExpectations(t)
+}
+
+func TestDownloadBatchVerifyHashLegacyPayloadHash(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+
+	batch, _ := testDownloadedBatch()
+	batch.Hash = batch.Payload.Hash()
+	batchBytes, _ := json.Marshal(batch)
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(ioutil.NopCloser(bytes.NewReader(batchBytes)), nil)
+
+	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
+	mci.On("SharedStorageBatchDownloaded", "ref1", batchBytes).Return(batch.ID, nil)
+
+	_, _, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+		BatchHash:  batch.Hash,
+	})
+	assert.NoError(t, err)
+
+	mss.AssertExpectations(t)
+	mci.AssertExpectations(t)
+}
+
+func TestDownloadBatchVerifyHashDisabled(t *testing.T) {
+
+	dm, cancel := newTestDownloadManager(t)
+	defer cancel()
+	dm.verifyPayloadHash = false
+
+	batch, batchBytes := testDownloadedBatch()
+
+	mss := dm.sharedstorage.(*sharedstoragemocks.Plugin)
+	mss.On("DownloadData", mock.Anything, "ref1").Return(ioutil.NopCloser(bytes.NewReader(batchBytes)), nil)
+
+	mci := dm.callbacks.(*shareddownloadmocks.Callbacks)
+	mci.On("SharedStorageBatchDownloaded", "ref1", batchBytes).Return(batch.ID, nil)
+
+	_, _, err := dm.downloadBatch(dm.ctx, downloadBatchData{
+		PayloadRef: "ref1",
+		BatchHash:  fftypes.NewRandB32(),
+	})
+	assert.NoError(t, err)
+
+	mss.AssertExpectations(t)
+	mci.AssertExpectations(t)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncasync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// MaxSendBatch is the maximum number of messages that can be submitted in a single call to SendBatch
+const MaxSendBatch = 1000
+
+// sendBatchConcurrency bounds how many messages of a batch are being sent at once. Messages sent
+// concurrently are written to the database together by the message writer.
+const sendBatchConcurrency = 20
+
+// MessageSendFunction sends a single message without waiting for it to be confirmed
+type MessageSendFunction func(ctx context.Context, in *core.MessageInOut) (*core.Message, error)
+
+// SendBatch sends every message of a batch, returning a result for each in the order they were supplied.
+// A message that fails to send does not prevent the others being sent. Messages that share a topic are sent
+// one at a time in the order they were supplied, so they are sequenced in that order.
+func SendBatch(ctx context.Context, in []*core.MessageInOut, send MessageSendFunction) ([]*core.MessageSubmitResult, error) {
+	if len(in) > MaxSendBatch {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageBatchTooLarge, len(in), MaxSendBatch)
+	}
+
+	results := make([]*core.MessageSubmitResult, len(in))
+	for i := range in {
+		results[i] = &core.MessageSubmitResult{}
+	}
+	slots := make(chan struct{}, sendBatchConcurrency)
+	var wg sync.WaitGroup
+	for _, lane := range sendLanes(in) {
+		wg.Add(1)
+		go func(lane []int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			for _, i := range lane {
+				sendOne(ctx, i, in[i], results[i], send)
+			}
+		}(lane)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func sendOne(ctx context.Context, i int, msg *core.MessageInOut, result *core.MessageSubmitResult, send MessageSendFunction) {
+	if msg == nil {
+		result.Error = i18n.NewError(ctx, coremsgs.MsgMessageBatchNullEntry, i).Error()
+		return
+	}
+	out, err := send(ctx, msg)
+	if err != nil {
+		log.L(ctx).Debugf("Failed to send message %d of batch: %s", i, err)
+		result.Error = err.Error()
+		return
+	}
+	result.Message = out
+}
+
+// sendLanes groups the indexes of the messages of a batch into lanes that can be sent concurrently. Messages
+// that share any topic, directly or through other messages, are in the same lane in the order they were supplied.
+func sendLanes(in []*core.MessageInOut) [][]int {
+	parent := make([]int, len(in))
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	topicOwners := make(map[string]int)
+	for i, msg := range in {
+		parent[i] = i
+		if msg == nil {
+			continue
+		}
+		topics := []string(msg.Header.Topics)
+		if len(topics) == 0 {
+			topics = []string{core.DefaultTopic}
+		}
+		for _, topic := range topics {
+			if owner, ok := topicOwners[topic]; ok {
+				if root := find(owner); root != find(i) {
+					parent[root] = find(i)
+				}
+			} else {
+				topicOwners[topic] = i
+			}
+		}
+	}
+
+	var lanes [][]int
+	laneByRoot := make(map[int]int)
+	for i := range in {
+		root := find(i)
+		lane, ok := laneByRoot[root]
+		if !ok {
+			lane = len(lanes)
+			laneByRoot[root] = lane
+			lanes = append(lanes, nil)
+		}
+		lanes[lane] = append(lanes[lane], i)
+	}
+	return lanes
+}
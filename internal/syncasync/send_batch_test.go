@@ -0,0 +1,103 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncasync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendBatch(t *testing.T) {
+	in := []*core.MessageInOut{
+		{Message: core.Message{Header: core.MessageHeader{Tag: "ok"}}},
+		{Message: core.Message{Header: core.MessageHeader{Tag: "fail"}}},
+		nil,
+	}
+	results, err := SendBatch(context.Background(), in, func(ctx context.Context, msg *core.MessageInOut) (*core.Message, error) {
+		if msg.Header.Tag == "fail" {
+			return nil, fmt.Errorf("pop")
+		}
+		return &msg.Message, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "ok", results[0].Message.Header.Tag)
+	assert.Empty(t, results[0].Error)
+	assert.Nil(t, results[1].Message)
+	assert.Equal(t, "pop", results[1].Error)
+	assert.Nil(t, results[2].Message)
+	assert.Regexp(t, "FF10530.*2", results[2].Error)
+}
+
+func TestSendBatchTooLarge(t *testing.T) {
+	_, err := SendBatch(context.Background(), make([]*core.MessageInOut, MaxSendBatch+1), func(ctx context.Context, msg *core.MessageInOut) (*core.Message, error) {
+		panic("should not be called")
+	})
+	assert.Regexp(t, "FF10529", err)
+}
+
+func TestSendBatchSameTopicInOrder(t *testing.T) {
+	in := make([]*core.MessageInOut, 100)
+	for i := range in {
+		in[i] = &core.MessageInOut{Message: core.Message{Header: core.MessageHeader{
+			Topics: fftypes.FFStringArray{fmt.Sprintf("topic%d", i%3)},
+			Tag:    fmt.Sprintf("%d", i),
+		}}}
+	}
+	var mux sync.Mutex
+	sent := make(map[string][]string)
+	results, err := SendBatch(context.Background(), in, func(ctx context.Context, msg *core.MessageInOut) (*core.Message, error) {
+		time.Sleep(time.Millisecond)
+		mux.Lock()
+		defer mux.Unlock()
+		sent[msg.Header.Topics[0]] = append(sent[msg.Header.Topics[0]], msg.Header.Tag)
+		return &msg.Message, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 100)
+	for topic := 0; topic < 3; topic++ {
+		var expected []string
+		for i := topic; i < len(in); i += 3 {
+			expected = append(expected, fmt.Sprintf("%d", i))
+		}
+		assert.Equal(t, expected, sent[fmt.Sprintf("topic%d", topic)])
+	}
+}
+
+func TestSendLanes(t *testing.T) {
+	msg := func(topics ...string) *core.MessageInOut {
+		return &core.MessageInOut{Message: core.Message{Header: core.MessageHeader{Topics: topics}}}
+	}
+	lanes := sendLanes([]*core.MessageInOut{
+		msg("a"),
+		msg("b"),
+		msg(),
+		nil,
+		msg("c"),
+		msg("b", "c"),
+		msg(core.DefaultTopic),
+		msg("a"),
+	})
+	assert.Equal(t, [][]int{{0, 7}, {1, 4, 5}, {2, 6}, {3}}, lanes)
+}
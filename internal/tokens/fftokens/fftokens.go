@@ -34,6 +34,7 @@ import (
 	"github.com/hyperledger/firefly-signer/pkg/abi"
 	"github.com/hyperledger/firefly-signer/pkg/ffi2abi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/tracing"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/tokens"
@@ -51,6 +52,20 @@ func (ie *ConflictError) IsConflictError() bool {
 	return true
 }
 
+// connectorError preserves the response from the token connector, for diagnosing failed operations
+type connectorError struct {
+	err    error
+	detail *core.OperationError
+}
+
+func (ce *connectorError) Error() string {
+	return ce.err.Error()
+}
+
+func (ce *connectorError) ConnectorErrorDetail() *core.OperationError {
+	return ce.detail
+}
+
 type FFTokens struct {
 	ctx             context.Context
 	cancelCtx       context.CancelFunc
@@ -311,6 +326,7 @@ func (ft *FFTokens) Init(ctx context.Context, cancelCtx context.CancelFunc, name
 	if err != nil {
 		return err
 	}
+	tracing.InstrumentClient(ft.client)
 
 	if ft.wsConfig.WSKeyPath == "" {
 		ft.wsConfig.WSKeyPath = "/api/ws"
@@ -404,6 +420,17 @@ func (ft *FFTokens) Capabilities() *tokens.Capabilities {
 	return ft.capabilities
 }
 
+func (ft *FFTokens) Ping(ctx context.Context) error {
+	var errRes tokenError
+	res, err := ft.client.R().SetContext(ctx).
+		SetError(&errRes).
+		Get("/api/v1/health/liveness")
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &errRes, res, err)
+	}
+	return nil
+}
+
 func (ft *FFTokens) handleReceipt(ctx context.Context, data fftypes.JSONObject) {
 	l := log.L(ctx)
 
@@ -778,7 +805,33 @@ func wrapError(ctx context.Context, errRes *tokenError, res *resty.Response, err
 	if res != nil && res.StatusCode() == http.StatusConflict {
 		return &ConflictError{err: ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokensRESTErrConflict)}
 	}
-	return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokensRESTErr)
+	return withConnectorError(ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokensRESTErr), res)
+}
+
+// withConnectorError attaches the status and body of the connector response to the error, so client errors
+// can be told apart from failures of the connector
+func withConnectorError(err error, res *resty.Response) error {
+	if res == nil || res.StatusCode() == 0 {
+		return err
+	}
+	return &connectorError{err: err, detail: &core.OperationError{
+		StatusCode: res.StatusCode(),
+		Body:       string(res.Body()),
+	}}
+}
+
+// poolConfig returns the connector config for a pool. When the pool has opted in to a backfill, the
+// block to start indexing from is passed to the connector so that it replays the historical transfers.
+func poolConfig(pool *core.TokenPool) fftypes.JSONObject {
+	if pool.Backfill == nil {
+		return pool.Config
+	}
+	config := fftypes.JSONObject{}
+	for k, v := range pool.Config {
+		config[k] = v
+	}
+	config["blockNumber"] = pool.Backfill.FromBlock
+	return config
 }
 
 func (ft *FFTokens) CreateTokenPool(ctx context.Context, nsOpID string, pool *core.TokenPool) (phase core.OpPhase, err error) {
@@ -795,7 +848,7 @@ func (ft *FFTokens) CreateTokenPool(ctx context.Context, nsOpID string, pool *co
 			RequestID: nsOpID,
 			Signer:    pool.Key,
 			Data:      string(data),
-			Config:    pool.Config,
+			Config:    poolConfig(pool),
 			Name:      pool.Name,
 			Symbol:    pool.Symbol,
 		}).
@@ -824,7 +877,7 @@ func (ft *FFTokens) EnsureTokenPoolActive(ctx context.Context, pool *core.TokenP
 			Namespace:   pool.Namespace,
 			PoolData:    packPoolData(pool.Namespace, pool.ID),
 			PoolLocator: pool.Locator,
-			Config:      pool.Config,
+			Config:      poolConfig(pool),
 		}).
 		SetError(&errRes).
 		Post("/api/v1/activatepool")
@@ -1031,6 +1084,41 @@ func (ft *FFTokens) TransferTokens(ctx context.Context, nsOpID string, poolLocat
 	return nil
 }
 
+// SimulateTransfer uses a dedicated connector endpoint, so that a connector that does not support
+// simulation rejects the request rather than performing the transfer. The endpoint is optional for
+// connectors, so a 404 response is reported as the action not being supported.
+func (ft *FFTokens) SimulateTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) (*core.Simulation, error) {
+	var iface interface{}
+	if methods != nil {
+		iface = methods.JSONObject()["transfer"]
+	}
+
+	var sim core.Simulation
+	var errRes tokenError
+	res, err := ft.client.R().SetContext(ctx).
+		SetBody(&transferTokens{
+			Namespace:   transfer.Namespace,
+			PoolLocator: poolLocator,
+			TokenIndex:  transfer.TokenIndex,
+			From:        transfer.From,
+			To:          transfer.To,
+			Amount:      transfer.Amount.Int().String(),
+			Signer:      transfer.Key,
+			Config:      transfer.Config,
+			Interface:   iface,
+		}).
+		SetResult(&sim).
+		SetError(&errRes).
+		Post("/api/v1/transfer/simulate")
+	if err == nil && res.StatusCode() == http.StatusNotFound {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	if err != nil || !res.IsSuccess() {
+		return nil, wrapError(ctx, &errRes, res, err)
+	}
+	return &sim, nil
+}
+
 func (ft *FFTokens) TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error {
 	data, _ := json.Marshal(tokenData{
 		TX:          approval.TX.ID,
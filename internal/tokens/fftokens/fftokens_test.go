@@ -94,6 +94,28 @@ func TestUnsetHandler(t *testing.T) {
 	assert.Equal(t, 0, len(h.callbacks.opHandlers))
 }
 
+func TestPing(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/health/liveness", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"status": "ok"}))
+
+	err := h.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPingError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/health/liveness", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{"message": "pop"}))
+
+	err := h.Ping(context.Background())
+	assert.Regexp(t, "FF10274.*pop", err)
+}
+
 func TestInitBadURL(t *testing.T) {
 	coreconfig.Reset()
 	h := &FFTokens{}
@@ -430,6 +452,52 @@ func TestActivateTokenPool(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestActivateTokenPoolBackfill(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	pool := &core.TokenPool{
+		Namespace: "ns1",
+		Locator:   "N1",
+		Config: map[string]interface{}{
+			"address": "0x12345",
+		},
+		Backfill: &core.TokenPoolBackfill{
+			FromBlock: "1000",
+		},
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/activatepool", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"namespace":   "ns1",
+				"poolData":    "ns1",
+				"poolLocator": "N1",
+				"config": map[string]interface{}{
+					"address":     "0x12345",
+					"blockNumber": "1000",
+				},
+			}, body)
+
+			res := &http.Response{
+				Body: io.NopCloser(bytes.NewReader([]byte(`{"id":"1"}`))),
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+				StatusCode: 202,
+			}
+			return res, nil
+		})
+
+	phase, err := h.ActivateTokenPool(context.Background(), pool)
+	assert.Equal(t, core.OpPhasePending, phase)
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{"address": "0x12345"}, pool.Config)
+}
+
 func TestActivateTokenPoolError(t *testing.T) {
 	h, _, _, httpURL, done := newTestFFTokens(t)
 	defer done()
@@ -983,6 +1051,76 @@ func TestTransferTokensError(t *testing.T) {
 	assert.Regexp(t, "FF10274", err)
 }
 
+func TestSimulateTransfer(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	transfer := &core.TokenTransfer{
+		Namespace:  "ns1",
+		TokenIndex: "1",
+		From:       "user1",
+		To:         "user2",
+		Key:        "0x123",
+		Amount:     *fftypes.NewFFBigInt(10),
+	}
+	methods := fftypes.JSONAnyPtr(`{"transfer":{"name":"transferFrom"}}`)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer/simulate", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"namespace":   "ns1",
+				"poolLocator": "123",
+				"tokenIndex":  "1",
+				"from":        "user1",
+				"to":          "user2",
+				"amount":      "10",
+				"signer":      "0x123",
+				"config":      nil,
+				"interface": map[string]interface{}{
+					"name": "transferFrom",
+				},
+			}, body)
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"success":      false,
+				"revertReason": "ERC20: transfer amount exceeds balance",
+			})(req)
+		})
+
+	sim, err := h.SimulateTransfer(context.Background(), "123", transfer, methods)
+	assert.NoError(t, err)
+	assert.False(t, sim.Success)
+	assert.Equal(t, "ERC20: transfer amount exceeds balance", sim.RevertReason)
+}
+
+func TestSimulateTransferError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	transfer := &core.TokenTransfer{}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer/simulate", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{"error": "Internal Server Error", "message": "pop"}))
+
+	_, err := h.SimulateTransfer(context.Background(), "F1", transfer, nil)
+	assert.Regexp(t, "FF10274.*pop", err)
+}
+
+func TestSimulateTransferNotSupported(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	transfer := &core.TokenTransfer{}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer/simulate", httpURL),
+		httpmock.NewJsonResponderOrPanic(404, fftypes.JSONObject{"error": "Not Found", "message": "Cannot POST /api/v1/transfer/simulate"}))
+
+	_, err := h.SimulateTransfer(context.Background(), "F1", transfer, nil)
+	assert.Regexp(t, "FF10414", err)
+}
+
 func TestIgnoredEvents(t *testing.T) {
 	h, toServer, fromServer, _, done := newTestFFTokens(t)
 	defer done()
@@ -1878,6 +2016,20 @@ func TestErrorWrappingBodyErr(t *testing.T) {
 	assert.True(t, errInterface.IsConflictError())
 }
 
+func TestErrorWrappingConnectorError(t *testing.T) {
+	ctx := context.Background()
+	res := &resty.Response{
+		RawResponse: &http.Response{StatusCode: 400},
+	}
+	err := wrapError(ctx, &tokenError{Error: "Bad Request", Message: "Field 'x' is required"}, res, nil)
+	assert.Regexp(t, "FF10274.*Field 'x' is required", err)
+	assert.Equal(t, 400, err.(operations.ConnectorError).ConnectorErrorDetail().StatusCode)
+
+	err = wrapError(ctx, nil, nil, fmt.Errorf("pop"))
+	_, ok := err.(operations.ConnectorError)
+	assert.False(t, ok)
+}
+
 func TestHandleNamespaceStartedEnsureActive(t *testing.T) {
 	h, _, _, httpURL, done := newTestFFTokens(t)
 	defer done()
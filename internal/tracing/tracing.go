@@ -0,0 +1,200 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/hyperledger/firefly"
+
+var provider *sdktrace.TracerProvider
+
+type clientSpanKey struct{}
+
+// Init installs an OTLP exporter for all spans, and W3C propagation of trace context, if tracing is enabled.
+// When tracing is disabled the global no-op tracer is left in place, so spans cost nothing.
+func Init(ctx context.Context) error {
+	if !config.GetBool(coreconfig.TracingEnabled) {
+		return nil
+	}
+	options := []otlptracehttp.Option{}
+	if endpoint := config.GetString(coreconfig.TracingOTLPEndpoint); endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || u.Host == "" {
+			return i18n.NewError(ctx, coremsgs.MsgTracingInvalidEndpoint, endpoint)
+		}
+		options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	if config.GetBool(coreconfig.TracingOTLPInsecure) {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptrace.New(ctx, otlptracehttp.NewClient(options...))
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgTracingInitFailed)
+	}
+	serviceName := config.GetString(coreconfig.TracingServiceName)
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.GetFloat64(coreconfig.TracingSampleRatio)))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	log.L(ctx).Infof("OpenTelemetry tracing enabled for service '%s'", serviceName)
+	return nil
+}
+
+// Shutdown flushes any spans that have not yet been exported
+func Shutdown(ctx context.Context) {
+	if provider != nil {
+		if err := provider.Shutdown(ctx); err != nil {
+			log.L(ctx).Warnf("Failed to flush OpenTelemetry spans: %s", err)
+		}
+		provider = nil
+	}
+}
+
+// StartSpan starts a span, as a child of any span already in the context. When tracing is disabled
+// the context is returned unchanged, with a span that does nothing.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if provider == nil {
+		return ctx, noop.Span{}
+	}
+	return provider.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the outcome of the work covered by a span, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+// Flush allows streamed responses to be flushed through the recorder
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Middleware starts a server span for each API request, continuing any trace propagated by the caller.
+// WebSocket connections are long-lived, so are not traced.
+func Middleware(next http.Handler) http.Handler {
+	if provider == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, req)
+			return
+		}
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+		name := req.Method
+		if route := mux.CurrentRoute(req); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				name += " " + template
+			}
+		}
+		ctx, span := provider.Tracer(tracerName).Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.path", req.URL.Path),
+			),
+		)
+		defer span.End()
+
+		sr := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sr, req.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.response.status_code", sr.status))
+		if sr.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sr.status))
+		}
+	})
+}
+
+// InstrumentClient starts a client span for each request made by a REST client to a plugin connector, and
+// propagates the trace context to the connector in the request headers. The span covers all retries of the request.
+func InstrumentClient(client *resty.Client) {
+	if provider == nil {
+		return
+	}
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		ctx := req.Context()
+		if ctx.Value(clientSpanKey{}) == nil {
+			var span trace.Span
+			ctx, span = provider.Tracer(tracerName).Start(ctx, "HTTP "+req.Method,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("url.full", req.URL),
+				),
+			)
+			ctx = context.WithValue(ctx, clientSpanKey{}, span)
+			req.SetContext(ctx)
+		}
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		return nil
+	})
+	client.OnSuccess(func(_ *resty.Client, res *resty.Response) {
+		if span, ok := res.Request.Context().Value(clientSpanKey{}).(trace.Span); ok {
+			span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode()))
+			if res.IsError() {
+				span.SetStatus(codes.Error, res.Status())
+			}
+			span.End()
+		}
+	})
+	client.OnError(func(req *resty.Request, err error) {
+		if span, ok := req.Context().Value(clientSpanKey{}).(trace.Span); ok {
+			EndSpan(span, err)
+		}
+	})
+}
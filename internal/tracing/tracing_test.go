@@ -0,0 +1,256 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type failingProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+func (fp *failingProcessor) Shutdown(ctx context.Context) error {
+	return fmt.Errorf("pop")
+}
+
+func newTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		Shutdown(context.Background())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestInitDisabled(t *testing.T) {
+	coreconfig.Reset()
+	err := Init(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+}
+
+func TestInitOk(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.TracingEnabled, true)
+	config.Set(coreconfig.TracingOTLPEndpoint, "http://localhost:4318")
+	config.Set(coreconfig.TracingOTLPInsecure, true)
+	err := Init(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, provider)
+
+	Shutdown(context.Background())
+	assert.Nil(t, provider)
+}
+
+func TestInitBadEndpoint(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.TracingEnabled, true)
+	config.Set(coreconfig.TracingOTLPEndpoint, "://bad")
+	err := Init(context.Background())
+	assert.Regexp(t, "FF10623", err)
+}
+
+func TestInitExporterFail(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.TracingEnabled, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Init(ctx)
+	assert.Regexp(t, "FF10622", err)
+}
+
+func TestShutdownFail(t *testing.T) {
+	provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(&failingProcessor{SpanProcessor: tracetest.NewSpanRecorder()}))
+	Shutdown(context.Background())
+	assert.Nil(t, provider)
+}
+
+func TestStartSpanDisabled(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := StartSpan(ctx, "test")
+	assert.Equal(t, ctx, spanCtx)
+	assert.False(t, span.IsRecording())
+	EndSpan(span, nil)
+}
+
+func TestStartEndSpan(t *testing.T) {
+	recorder := newTestTracing(t)
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	_, child := StartSpan(ctx, "child", attribute.String("key", "value"))
+	EndSpan(child, fmt.Errorf("pop"))
+	EndSpan(parent, nil)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, "value", spanAttribute(spans[0], "key").AsString())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestMiddlewareDisabled(t *testing.T) {
+	next := mux.NewRouter()
+	assert.Equal(t, next, Middleware(next))
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := newTestTracing(t)
+
+	r := mux.NewRouter()
+	r.Use(Middleware)
+	r.HandleFunc("/api/v1/things/{id}", func(w http.ResponseWriter, req *http.Request) {
+		assert.True(t, trace.SpanFromContext(req.Context()).IsRecording())
+		w.WriteHeader(http.StatusInternalServerError)
+		w.(http.Flusher).Flush()
+	})
+
+	_, caller := StartSpan(context.Background(), "caller")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/things/abc", nil)
+	otel.GetTextMapPropagator().Inject(trace.ContextWithSpan(context.Background(), caller), propagation.HeaderCarrier(req.Header))
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.True(t, res.Flushed)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/v1/things/{id}", spans[0].Name())
+	assert.Equal(t, caller.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+	assert.Equal(t, int64(500), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestMiddlewareNoRoute(t *testing.T) {
+	recorder := newTestTracing(t)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/anything", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "POST", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestMiddlewareWebSocket(t *testing.T) {
+	recorder := newTestTracing(t)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.False(t, trace.SpanFromContext(req.Context()).IsRecording())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Upgrade", "websocket")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestInstrumentClientDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Empty(t, req.Header.Get("traceparent"))
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+	InstrumentClient(client)
+	_, err := client.R().Get("/ok")
+	assert.NoError(t, err)
+}
+
+func TestInstrumentClient(t *testing.T) {
+	recorder := newTestTracing(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.NotEmpty(t, req.Header.Get("traceparent"))
+		if req.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+	InstrumentClient(client)
+
+	ctx, parent := StartSpan(context.Background(), "parent")
+	res, err := client.R().SetContext(ctx).Post("/ok")
+	assert.NoError(t, err)
+	assert.True(t, res.IsSuccess())
+	res, err = client.R().SetContext(ctx).Get("/fail")
+	assert.NoError(t, err)
+	assert.True(t, res.IsError())
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "HTTP POST", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, int64(200), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "HTTP GET", spans[1].Name())
+	assert.Equal(t, int64(400), spanAttribute(spans[1], "http.response.status_code").AsInt64())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestInstrumentClientRetryError(t *testing.T) {
+	recorder := newTestTracing(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	server.Close()
+
+	client := resty.New().SetBaseURL(server.URL).SetRetryCount(2).SetRetryWaitTime(0)
+	InstrumentClient(client)
+
+	_, err := client.R().Get("/closed")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
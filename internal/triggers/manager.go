@@ -0,0 +1,367 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/system"
+	"github.com/hyperledger/firefly/internal/operations"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// loadPageSize is the number of trigger operations read at a time, when loading the pending triggers on startup
+const loadPageSize = 100
+
+// runConcurrency is the number of resolved sources whose triggers are run in parallel
+const runConcurrency = 10
+
+// Manager runs the follow-up actions declared on messages and contract invokes, once they are confirmed.
+//
+// Each action is written as an operation in the "Initialized" state, in a transaction of type "trigger",
+// before its source is submitted. The manager listens for the confirmation (or failure) of the source,
+// then runs the operations - or fails them if the source was not confirmed.
+type Manager interface {
+	core.Named
+
+	// Init is required as the contract manager that runs the actions is created after the managers that declare them
+	Init(sysevents system.EventInterface, invoker Invoker)
+
+	// ValidateTriggers checks the actions declared on a message or invoke, before anything is written
+	ValidateTriggers(ctx context.Context, actions []*core.TriggerAction) error
+	// AddTriggers writes an operation for each action, to run once the source is confirmed
+	AddTriggers(ctx context.Context, source *core.TriggerSource, actions []*core.TriggerAction) error
+
+	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
+	RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error)
+
+	Start() error
+	WaitStop()
+}
+
+// Invoker is the part of the contract manager used to run invoke actions
+type Invoker interface {
+	InvokeContract(ctx context.Context, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error)
+	InvokeContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error)
+}
+
+type triggerManager struct {
+	ctx        context.Context
+	cancelCtx  context.CancelFunc
+	namespace  string
+	database   database.Plugin
+	txHelper   txcommon.Helper
+	operations operations.Manager
+	sysevents  system.EventInterface
+	invoker    Invoker
+	pendingMux sync.Mutex
+	pending    map[fftypes.UUID][]*fftypes.UUID // trigger transactions, by the ID of the source they wait on
+	resolved   []*resolvedSource                // queued for the trigger loop, under pendingMux
+	newWork    chan struct{}
+	runSlots   chan struct{}
+	running    sync.WaitGroup
+	started    bool
+	loopDone   chan struct{}
+}
+
+// resolvedSource is a source that has been confirmed or failed, along with the trigger transactions waiting on it
+type resolvedSource struct {
+	source    *core.TriggerSource
+	txIDs     []*fftypes.UUID
+	confirmed bool
+}
+
+func NewTriggerManager(ctx context.Context, ns string, di database.Plugin, txHelper txcommon.Helper, om operations.Manager) (Manager, error) {
+	if di == nil || txHelper == nil || om == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "TriggerManager")
+	}
+	ctx, cancelCtx := context.WithCancel(log.WithLogField(ctx, "role", "triggers"))
+	tm := &triggerManager{
+		ctx:        ctx,
+		cancelCtx:  cancelCtx,
+		namespace:  ns,
+		database:   di,
+		txHelper:   txHelper,
+		operations: om,
+		pending:    make(map[fftypes.UUID][]*fftypes.UUID),
+		newWork:    make(chan struct{}, 1),
+		runSlots:   make(chan struct{}, runConcurrency),
+		loopDone:   make(chan struct{}),
+	}
+	om.RegisterHandler(ctx, tm, []core.OpType{
+		core.OpTypeTriggerInvoke,
+	})
+	return tm, nil
+}
+
+func (tm *triggerManager) Name() string {
+	return "TriggerManager"
+}
+
+func (tm *triggerManager) Init(sysevents system.EventInterface, invoker Invoker) {
+	tm.sysevents = sysevents
+	tm.invoker = invoker
+}
+
+func (tm *triggerManager) ValidateTriggers(ctx context.Context, actions []*core.TriggerAction) error {
+	for i, action := range actions {
+		switch action.Type {
+		case core.TriggerActionTypeInvoke:
+			invoke := action.Invoke
+			if invoke == nil || invoke.MethodPath == "" || (invoke.API == "" && invoke.Interface == nil) {
+				return i18n.NewError(ctx, coremsgs.MsgTriggerInvokeInvalid, i)
+			}
+		default:
+			return i18n.NewError(ctx, coremsgs.MsgTriggerActionTypeInvalid, i, action.Type)
+		}
+	}
+	return nil
+}
+
+func (tm *triggerManager) AddTriggers(ctx context.Context, source *core.TriggerSource, actions []*core.TriggerAction) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	if err := tm.ValidateTriggers(ctx, actions); err != nil {
+		return err
+	}
+
+	var txID *fftypes.UUID
+	err := tm.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
+		txID, err = tm.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTrigger, "")
+		if err != nil {
+			return err
+		}
+		ops := make([]*core.Operation, len(actions))
+		for i, action := range actions {
+			ops[i] = core.NewOperation(tm, tm.namespace, txID, core.OpTypeTriggerInvoke)
+			if err := addTriggerInputs(ops[i], source, action); err != nil {
+				return err
+			}
+		}
+		return tm.operations.BulkInsertOperations(ctx, ops...)
+	})
+	if err != nil {
+		return err
+	}
+
+	tm.pendingMux.Lock()
+	tm.pending[*source.ID] = append(tm.pending[*source.ID], txID)
+	tm.pendingMux.Unlock()
+	log.L(ctx).Infof("Added %d trigger actions in transaction %s, waiting on %s %s", len(actions), txID, source.Type, source.ID)
+	return nil
+}
+
+func (tm *triggerManager) Start() error {
+	// Listen for confirmations before loading the pending triggers, so none are missed in between
+	if err := tm.sysevents.AddSystemEventListener(tm.namespace, tm.eventCallback); err != nil {
+		return err
+	}
+	tm.started = true
+	go tm.triggerLoop()
+	return tm.loadPending()
+}
+
+func (tm *triggerManager) WaitStop() {
+	tm.cancelCtx()
+	if tm.started {
+		<-tm.loopDone
+	}
+	tm.running.Wait()
+}
+
+func (tm *triggerManager) eventCallback(event *core.EventDelivery) error {
+	switch event.Type {
+	case core.EventTypeMessageConfirmed:
+		tm.sourceResolved(&core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: event.Reference}, true)
+	case core.EventTypeMessageRejected:
+		tm.sourceResolved(&core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: event.Reference}, false)
+	case core.EventTypeBlockchainInvokeOpSucceeded:
+		tm.sourceResolved(&core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: event.Reference}, true)
+	case core.EventTypeBlockchainInvokeOpFailed:
+		tm.sourceResolved(&core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: event.Reference}, false)
+	}
+	return nil
+}
+
+// sourceResolved queues the triggers waiting on a source for the trigger loop. Removing them from the pending
+// map under the lock ensures they are only run once, even if the source is resolved by both an event and
+// the load on startup. It is called from the system event listener, so it never blocks.
+func (tm *triggerManager) sourceResolved(source *core.TriggerSource, confirmed bool) {
+	if source.ID == nil {
+		return
+	}
+	tm.pendingMux.Lock()
+	txIDs := tm.pending[*source.ID]
+	delete(tm.pending, *source.ID)
+	if len(txIDs) > 0 {
+		tm.resolved = append(tm.resolved, &resolvedSource{source: source, txIDs: txIDs, confirmed: confirmed})
+	}
+	tm.pendingMux.Unlock()
+	if len(txIDs) > 0 {
+		select {
+		case tm.newWork <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// loadPending reads the trigger operations that have not yet run, and resolves any whose source was
+// confirmed or failed while we were not listening
+func (tm *triggerManager) loadPending() error {
+	sources := make(map[fftypes.UUID]*core.TriggerSource)
+	txIDs := make(map[fftypes.UUID]map[fftypes.UUID]bool)
+	fb := database.OperationQueryFactory.NewFilter(tm.ctx)
+	for skip := uint64(0); ; skip += loadPageSize {
+		filter := fb.And(
+			fb.Eq("type", core.OpTypeTriggerInvoke),
+			fb.Eq("status", core.OpStatusInitialized),
+		).Sort("created").Skip(skip).Limit(loadPageSize)
+		ops, _, err := tm.database.GetOperations(tm.ctx, tm.namespace, filter)
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			inputs, err := retrieveTriggerInputs(tm.ctx, op)
+			if err != nil || inputs.Source == nil || inputs.Source.ID == nil {
+				log.L(tm.ctx).Errorf("Skipping trigger operation %s with invalid inputs: %v", op.ID, err)
+				continue
+			}
+			sourceID := *inputs.Source.ID
+			sources[sourceID] = inputs.Source
+			if txIDs[sourceID] == nil {
+				txIDs[sourceID] = make(map[fftypes.UUID]bool)
+			}
+			txIDs[sourceID][*op.Transaction] = true
+		}
+		if len(ops) < loadPageSize {
+			break
+		}
+	}
+
+	for sourceID, source := range sources {
+		tm.pendingMux.Lock()
+		for txID := range txIDs[sourceID] {
+			id := txID
+			tm.pending[sourceID] = append(tm.pending[sourceID], &id)
+		}
+		tm.pendingMux.Unlock()
+
+		resolved, confirmed, err := tm.checkSource(source)
+		if err != nil {
+			return err
+		}
+		if resolved {
+			tm.sourceResolved(source, confirmed)
+		}
+	}
+	log.L(tm.ctx).Infof("Loaded triggers waiting on %d sources", len(sources))
+	return nil
+}
+
+// checkSource returns whether a source has been confirmed or failed. A source that does not exist
+// is treated as failed, as the triggers were written but the submission of the source was not.
+func (tm *triggerManager) checkSource(source *core.TriggerSource) (resolved, confirmed bool, err error) {
+	switch source.Type {
+	case core.TriggerSourceTypeMessage:
+		msg, err := tm.database.GetMessageByID(tm.ctx, tm.namespace, source.ID)
+		if err != nil || msg == nil {
+			return msg == nil && err == nil, false, err
+		}
+		return msg.State == core.MessageStateConfirmed || msg.State == core.MessageStateRejected, msg.State == core.MessageStateConfirmed, nil
+	default:
+		op, err := tm.database.GetOperationByID(tm.ctx, tm.namespace, source.ID)
+		if err != nil || op == nil {
+			return op == nil && err == nil, false, err
+		}
+		return op.Status == core.OpStatusSucceeded || op.Status == core.OpStatusFailed, op.Status == core.OpStatusSucceeded, nil
+	}
+}
+
+// triggerLoop takes the resolved sources off the queue, and runs the triggers of each in the background,
+// so a slow invoke does not hold up the triggers of other sources
+func (tm *triggerManager) triggerLoop() {
+	defer close(tm.loopDone)
+	for {
+		select {
+		case <-tm.ctx.Done():
+			log.L(tm.ctx).Debugf("Trigger loop exiting")
+			return
+		case <-tm.newWork:
+		}
+		tm.pendingMux.Lock()
+		queued := tm.resolved
+		tm.resolved = nil
+		tm.pendingMux.Unlock()
+		for _, res := range queued {
+			select {
+			case tm.runSlots <- struct{}{}:
+			case <-tm.ctx.Done():
+				log.L(tm.ctx).Debugf("Trigger loop exiting")
+				return
+			}
+			tm.running.Add(1)
+			go func(res *resolvedSource) {
+				defer func() {
+					<-tm.runSlots
+					tm.running.Done()
+				}()
+				for _, txID := range res.txIDs {
+					tm.runTriggers(res, txID)
+				}
+			}(res)
+		}
+	}
+}
+
+// runTriggers runs (or fails) the operations of a trigger transaction. If the operations cannot be read,
+// they remain in the "Initialized" state, and are retried when the namespace next starts.
+func (tm *triggerManager) runTriggers(res *resolvedSource, txID *fftypes.UUID) {
+	fb := database.OperationQueryFactory.NewFilter(tm.ctx)
+	ops, _, err := tm.database.GetOperations(tm.ctx, tm.namespace, fb.And(
+		fb.Eq("tx", txID),
+		fb.Eq("status", core.OpStatusInitialized),
+	))
+	if err != nil {
+		log.L(tm.ctx).Errorf("Failed to read trigger operations for transaction %s: %s", txID, err)
+		return
+	}
+	for _, op := range ops {
+		if !res.confirmed {
+			errMsg := i18n.NewError(tm.ctx, coremsgs.MsgTriggerSourceNotConfirmed, res.source.Type, res.source.ID).Error()
+			err = tm.operations.ResolveOperationByID(tm.ctx, op.ID, &core.OperationUpdateDTO{
+				Status: core.OpStatusFailed,
+				Error:  &errMsg,
+			})
+		} else {
+			var prepared *core.PreparedOperation
+			if prepared, err = tm.PrepareOperation(tm.ctx, op); err == nil {
+				_, err = tm.operations.RunOperation(tm.ctx, prepared, false)
+			}
+		}
+		if err != nil {
+			log.L(tm.ctx).Errorf("Failed to run trigger operation %s: %s", op.ID, err)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/systemeventmocks"
+	"github.com/hyperledger/firefly/mocks/triggerinvokermocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestTriggerManager(t *testing.T) (*triggerManager, func()) {
+	mdi := &databasemocks.Plugin{}
+	mth := &txcommonmocks.Helper{}
+	mom := &operationmocks.Manager{}
+	mse := &systemeventmocks.EventInterface{}
+	mcm := &triggerinvokermocks.Invoker{}
+	mom.On("RegisterHandler", mock.Anything, mock.Anything, []core.OpType{core.OpTypeTriggerInvoke})
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything).Maybe()
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	tm, err := NewTriggerManager(context.Background(), "ns1", mdi, mth, mom)
+	assert.NoError(t, err)
+	tm.Init(mse, mcm)
+	return tm.(*triggerManager), func() {
+		tm.WaitStop()
+		mdi.AssertExpectations(t)
+		mth.AssertExpectations(t)
+		mom.AssertExpectations(t)
+		mse.AssertExpectations(t)
+		mcm.AssertExpectations(t)
+	}
+}
+
+func testInvokeAction() *core.TriggerAction {
+	return &core.TriggerAction{
+		Type: core.TriggerActionTypeInvoke,
+		Invoke: &core.TriggerInvoke{
+			API:        "myapi",
+			MethodPath: "set",
+			Input:      map[string]interface{}{"value": 1},
+		},
+	}
+}
+
+func testTriggerOp(source *core.TriggerSource, txID *fftypes.UUID) *core.Operation {
+	op := core.NewOperation(&triggerManager{}, "ns1", txID, core.OpTypeTriggerInvoke)
+	_ = addTriggerInputs(op, source, testInvokeAction())
+	return op
+}
+
+func TestNewTriggerManagerFail(t *testing.T) {
+	_, err := NewTriggerManager(context.Background(), "ns1", nil, nil, nil)
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestName(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+	assert.Equal(t, "TriggerManager", tm.Name())
+}
+
+func TestValidateTriggers(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	assert.NoError(t, tm.ValidateTriggers(context.Background(), []*core.TriggerAction{testInvokeAction()}))
+
+	err := tm.ValidateTriggers(context.Background(), []*core.TriggerAction{
+		testInvokeAction(),
+		{Type: core.TriggerActionTypeInvoke, Invoke: &core.TriggerInvoke{MethodPath: "set"}},
+	})
+	assert.Regexp(t, "FF10605.*1", err)
+
+	err = tm.ValidateTriggers(context.Background(), []*core.TriggerAction{{Type: "unknown"}})
+	assert.Regexp(t, "FF10604.*unknown", err)
+}
+
+func TestAddTriggersOk(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	source := &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: fftypes.NewUUID()}
+	txID := fftypes.NewUUID()
+
+	mth := tm.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeTrigger, core.IdempotencyKey("")).Return(txID, nil)
+	mom := tm.operations.(*operationmocks.Manager)
+	mom.On("BulkInsertOperations", mock.Anything, mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTriggerInvoke && op.Transaction.Equals(txID) && op.Status == core.OpStatusInitialized
+	})).Return(nil)
+
+	err := tm.AddTriggers(context.Background(), source, []*core.TriggerAction{testInvokeAction()})
+	assert.NoError(t, err)
+	assert.Equal(t, []*fftypes.UUID{txID}, tm.pending[*source.ID])
+}
+
+func TestAddTriggersNone(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	err := tm.AddTriggers(context.Background(), &core.TriggerSource{}, nil)
+	assert.NoError(t, err)
+}
+
+func TestAddTriggersInvalid(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	err := tm.AddTriggers(context.Background(), &core.TriggerSource{}, []*core.TriggerAction{{Type: "unknown"}})
+	assert.Regexp(t, "FF10604", err)
+}
+
+func TestAddTriggersTxFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mth := tm.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeTrigger, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
+
+	err := tm.AddTriggers(context.Background(), &core.TriggerSource{ID: fftypes.NewUUID()}, []*core.TriggerAction{testInvokeAction()})
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, tm.pending)
+}
+
+func TestAddTriggersInsertFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mth := tm.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeTrigger, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom := tm.operations.(*operationmocks.Manager)
+	mom.On("BulkInsertOperations", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := tm.AddTriggers(context.Background(), &core.TriggerSource{ID: fftypes.NewUUID()}, []*core.TriggerAction{testInvokeAction()})
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, tm.pending)
+}
+
+func TestStartListenerFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mse := tm.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := tm.Start()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestStartLoadFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mse := tm.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+	mdi := tm.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := tm.Start()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestStartRunsConfirmedTriggers(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	msgSource := &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: fftypes.NewUUID()}
+	opSource := &core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: fftypes.NewUUID()}
+	pendingSource := &core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: fftypes.NewUUID()}
+	msgTxID := fftypes.NewUUID()
+	opTxID := fftypes.NewUUID()
+	msgOp := testTriggerOp(msgSource, msgTxID)
+	opOp := testTriggerOp(opSource, opTxID)
+	badOp := &core.Operation{ID: fftypes.NewUUID(), Input: fftypes.JSONObject{}}
+
+	mse := tm.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+	mdi := tm.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "type == 'trigger_invoke'")
+	})).Return([]*core.Operation{msgOp, opOp, testTriggerOp(pendingSource, fftypes.NewUUID()), badOp}, nil, nil).Once()
+	mdi.On("GetMessageByID", mock.Anything, "ns1", msgSource.ID).Return(&core.Message{State: core.MessageStateConfirmed}, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opSource.ID).Return(nil, nil)
+	mdi.On("GetOperationByID", mock.Anything, "ns1", pendingSource.ID).Return(&core.Operation{Status: core.OpStatusPending}, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), msgTxID.String())
+	})).Return([]*core.Operation{msgOp}, nil, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), opTxID.String())
+	})).Return([]*core.Operation{opOp}, nil, nil)
+
+	done := make(chan struct{}, 2)
+	mom := tm.operations.(*operationmocks.Manager)
+	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		return op.ID.Equals(msgOp.ID)
+	}), false).Return(nil, nil).Run(func(args mock.Arguments) { done <- struct{}{} })
+	mom.On("ResolveOperationByID", mock.Anything, opOp.ID, mock.MatchedBy(func(update *core.OperationUpdateDTO) bool {
+		return update.Status == core.OpStatusFailed && *update.Error != ""
+	})).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) { done <- struct{}{} })
+
+	err := tm.Start()
+	assert.NoError(t, err)
+	<-done
+	<-done
+
+	assert.Len(t, tm.pending, 1)
+	assert.NotNil(t, tm.pending[*pendingSource.ID])
+}
+
+func TestLoadPendingPaged(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	source := &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: fftypes.NewUUID()}
+	txID := fftypes.NewUUID()
+	page := make([]*core.Operation, loadPageSize)
+	for i := range page {
+		page[i] = testTriggerOp(source, txID)
+	}
+
+	mdi := tm.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(page, nil, nil).Once()
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil).Once()
+	mdi.On("GetMessageByID", mock.Anything, "ns1", source.ID).Return(&core.Message{State: core.MessageStateSent}, nil)
+
+	err := tm.loadPending()
+	assert.NoError(t, err)
+	assert.Equal(t, []*fftypes.UUID{txID}, tm.pending[*source.ID])
+}
+
+func TestLoadPendingCheckFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	source := &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: fftypes.NewUUID()}
+
+	mdi := tm.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{testTriggerOp(source, fftypes.NewUUID())}, nil, nil)
+	mdi.On("GetMessageByID", mock.Anything, "ns1", source.ID).Return(nil, fmt.Errorf("pop"))
+
+	err := tm.loadPending()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckSource(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mdi := tm.database.(*databasemocks.Plugin)
+	msgID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", mock.Anything, "ns1", msgID).Return(&core.Message{State: core.MessageStateRejected}, nil)
+	resolved, confirmed, err := tm.checkSource(&core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: msgID})
+	assert.NoError(t, err)
+	assert.True(t, resolved)
+	assert.False(t, confirmed)
+
+	opID := fftypes.NewUUID()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(&core.Operation{Status: core.OpStatusSucceeded}, nil)
+	resolved, confirmed, err = tm.checkSource(&core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: opID})
+	assert.NoError(t, err)
+	assert.True(t, resolved)
+	assert.True(t, confirmed)
+
+	opID = fftypes.NewUUID()
+	mdi.On("GetOperationByID", mock.Anything, "ns1", opID).Return(nil, fmt.Errorf("pop"))
+	_, _, err = tm.checkSource(&core.TriggerSource{Type: core.TriggerSourceTypeOperation, ID: opID})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEventCallback(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	events := map[core.EventType]bool{
+		core.EventTypeMessageConfirmed:            true,
+		core.EventTypeMessageRejected:             false,
+		core.EventTypeBlockchainInvokeOpSucceeded: true,
+		core.EventTypeBlockchainInvokeOpFailed:    false,
+	}
+	for eventType, confirmed := range events {
+		sourceID := fftypes.NewUUID()
+		txID := fftypes.NewUUID()
+		tm.pending[*sourceID] = []*fftypes.UUID{txID}
+		err := tm.eventCallback(&core.EventDelivery{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{Type: eventType, Reference: sourceID},
+			},
+		})
+		assert.NoError(t, err)
+		<-tm.newWork
+		res := tm.resolved[len(tm.resolved)-1]
+		assert.Equal(t, sourceID, res.source.ID)
+		assert.Equal(t, []*fftypes.UUID{txID}, res.txIDs)
+		assert.Equal(t, confirmed, res.confirmed)
+		assert.Empty(t, tm.pending)
+	}
+
+	// Events for sources with no triggers are ignored
+	err := tm.eventCallback(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{Type: core.EventTypeMessageConfirmed, Reference: fftypes.NewUUID()},
+		},
+	})
+	assert.NoError(t, err)
+	err = tm.eventCallback(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{Type: core.EventTypeTransactionSubmitted},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tm.resolved, len(events))
+}
+
+func TestSourceResolvedDoesNotBlock(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	// Nothing is taking work off the queue, but the listener is not held up
+	for i := 0; i < 3; i++ {
+		sourceID := fftypes.NewUUID()
+		tm.pending[*sourceID] = []*fftypes.UUID{fftypes.NewUUID()}
+		tm.sourceResolved(&core.TriggerSource{ID: sourceID}, true)
+	}
+	assert.Len(t, tm.resolved, 3)
+	assert.Len(t, tm.newWork, 1)
+}
+
+func TestTriggerLoopCancelledWaitingToRun(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+
+	for i := 0; i < runConcurrency; i++ {
+		tm.runSlots <- struct{}{}
+	}
+	sourceID := fftypes.NewUUID()
+	tm.pending[*sourceID] = []*fftypes.UUID{fftypes.NewUUID()}
+	tm.sourceResolved(&core.TriggerSource{ID: sourceID}, true)
+
+	go tm.triggerLoop()
+	for len(tm.newWork) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	tm.cancelCtx()
+	<-tm.loopDone
+	cancel()
+}
+
+func TestRunTriggersReadFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mdi := tm.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	tm.runTriggers(&resolvedSource{confirmed: true}, fftypes.NewUUID())
+}
+
+func TestRunTriggersPrepareFail(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	mdi := tm.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{ID: fftypes.NewUUID(), Type: core.OpTypeTriggerInvoke, Input: fftypes.JSONObject{}},
+	}, nil, nil)
+
+	tm.runTriggers(&resolvedSource{confirmed: true}, fftypes.NewUUID())
+}
+
+func TestWaitStopNotStarted(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+	tm.WaitStop()
+}
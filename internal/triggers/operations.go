@@ -0,0 +1,130 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// triggerInputs are stored as the input of each trigger operation
+type triggerInputs struct {
+	Source *core.TriggerSource `json:"source"`
+	Action *core.TriggerAction `json:"action"`
+}
+
+type triggerInvokeData struct {
+	Source *core.TriggerSource
+	Invoke *core.TriggerInvoke
+}
+
+func addTriggerInputs(op *core.Operation, source *core.TriggerSource, action *core.TriggerAction) (err error) {
+	var inputJSON []byte
+	if inputJSON, err = json.Marshal(&triggerInputs{Source: source, Action: action}); err == nil {
+		d := json.NewDecoder(bytes.NewReader(inputJSON))
+		d.UseNumber()
+		err = d.Decode(&op.Input)
+	}
+	return err
+}
+
+func retrieveTriggerInputs(ctx context.Context, op *core.Operation) (*triggerInputs, error) {
+	var inputs triggerInputs
+	s := op.Input.String()
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	if err := d.Decode(&inputs); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgJSONObjectParseFailed, s)
+	}
+	return &inputs, nil
+}
+
+func (tm *triggerManager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
+	switch op.Type {
+	case core.OpTypeTriggerInvoke:
+		inputs, err := retrieveTriggerInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		if inputs.Action == nil || inputs.Action.Invoke == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTriggerInvokeInvalid, 0)
+		}
+		return opTriggerInvoke(op, inputs.Source, inputs.Action.Invoke), nil
+
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotSupported, op.Type)
+	}
+}
+
+func (tm *triggerManager) RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
+	switch data := op.Data.(type) {
+	case triggerInvokeData:
+		if tm.invoker == nil {
+			return nil, core.OpPhaseComplete, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+		}
+		invoke := data.Invoke
+		req := &core.ContractCallRequest{
+			Type:       core.CallTypeInvoke,
+			Interface:  invoke.Interface,
+			Location:   invoke.Location,
+			MethodPath: invoke.MethodPath,
+			Key:        invoke.Key,
+			Input:      invoke.Input,
+			Options:    invoke.Options,
+			// Keyed on this operation, so the invoke is not repeated if we restart before recording the outcome
+			IdempotencyKey: core.IdempotencyKey(op.ID.String()),
+		}
+		var res interface{}
+		if invoke.API != "" {
+			res, err = tm.invoker.InvokeContractAPI(ctx, invoke.API, invoke.MethodPath, req, false)
+		} else {
+			res, err = tm.invoker.InvokeContract(ctx, req, false)
+		}
+		if err != nil {
+			return nil, core.OpPhaseComplete, err
+		}
+		outputs = fftypes.JSONObject{}
+		if invokeOp, ok := res.(*core.Operation); ok {
+			outputs["operation"] = invokeOp.ID
+			outputs["tx"] = invokeOp.Transaction
+		}
+		return outputs, core.OpPhaseComplete, nil
+
+	default:
+		return nil, core.OpPhaseInitializing, i18n.NewError(ctx, coremsgs.MsgOperationDataIncorrect, op.Data)
+	}
+}
+
+func (tm *triggerManager) OnOperationUpdate(ctx context.Context, op *core.Operation, update *core.OperationUpdate) error {
+	return nil
+}
+
+func opTriggerInvoke(op *core.Operation, source *core.TriggerSource, invoke *core.TriggerInvoke) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      triggerInvokeData{Source: source, Invoke: invoke},
+	}
+}
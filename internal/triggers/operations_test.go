@@ -0,0 +1,119 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triggers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/triggerinvokermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPrepareAndRunInvokeAPI(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	source := &core.TriggerSource{Type: core.TriggerSourceTypeMessage, ID: fftypes.NewUUID()}
+	op := testTriggerOp(source, fftypes.NewUUID())
+	invokeOp := &core.Operation{ID: fftypes.NewUUID(), Transaction: fftypes.NewUUID()}
+
+	mci := tm.invoker.(*triggerinvokermocks.Invoker)
+	mci.On("InvokeContractAPI", context.Background(), "myapi", "set", mock.MatchedBy(func(req *core.ContractCallRequest) bool {
+		return req.Type == core.CallTypeInvoke && req.IdempotencyKey == core.IdempotencyKey(op.ID.String())
+	}), false).Return(invokeOp, nil)
+
+	po, err := tm.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, source.ID, po.Data.(triggerInvokeData).Source.ID)
+
+	outputs, phase, err := tm.RunOperation(context.Background(), po)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpPhaseComplete, phase)
+	assert.Equal(t, invokeOp.ID, outputs["operation"])
+	assert.Equal(t, invokeOp.Transaction, outputs["tx"])
+}
+
+func TestRunInvokeInterface(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	op := &core.Operation{ID: fftypes.NewUUID()}
+	invoke := &core.TriggerInvoke{Interface: fftypes.NewUUID(), MethodPath: "set"}
+
+	mci := tm.invoker.(*triggerinvokermocks.Invoker)
+	mci.On("InvokeContract", context.Background(), mock.MatchedBy(func(req *core.ContractCallRequest) bool {
+		return req.Interface.Equals(invoke.Interface)
+	}), false).Return(nil, fmt.Errorf("pop"))
+
+	_, phase, err := tm.RunOperation(context.Background(), opTriggerInvoke(op, &core.TriggerSource{}, invoke))
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.OpPhaseComplete, phase)
+}
+
+func TestRunInvokeNoInvoker(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+	tm.invoker = nil
+
+	_, _, err := tm.RunOperation(context.Background(), opTriggerInvoke(&core.Operation{}, &core.TriggerSource{}, &core.TriggerInvoke{}))
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestPrepareOperationNotSupported(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	_, err := tm.PrepareOperation(context.Background(), &core.Operation{Type: core.OpTypeBlockchainInvoke})
+	assert.Regexp(t, "FF10371", err)
+}
+
+func TestPrepareOperationBadInput(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	_, err := tm.PrepareOperation(context.Background(), &core.Operation{
+		Type:  core.OpTypeTriggerInvoke,
+		Input: fftypes.JSONObject{"action": "bad"},
+	})
+	assert.Regexp(t, "FF00127", err)
+
+	_, err = tm.PrepareOperation(context.Background(), &core.Operation{
+		Type:  core.OpTypeTriggerInvoke,
+		Input: fftypes.JSONObject{},
+	})
+	assert.Regexp(t, "FF10605", err)
+}
+
+func TestRunOperationNotSupported(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+
+	_, phase, err := tm.RunOperation(context.Background(), &core.PreparedOperation{})
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.Regexp(t, "FF10378", err)
+}
+
+func TestOperationUpdate(t *testing.T) {
+	tm, cancel := newTestTriggerManager(t)
+	defer cancel()
+	assert.NoError(t, tm.OnOperationUpdate(context.Background(), nil, nil))
+}
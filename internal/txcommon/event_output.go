@@ -0,0 +1,64 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txcommon
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// truncateEventOutput reduces the output of a blockchain event to the subset of its top-level fields that fit
+// within the configured maximum, returning the full output so it can be stored separately.
+// Returns nil if the output did not need truncating.
+func (t *transactionHelper) truncateEventOutput(ctx context.Context, event *core.BlockchainEvent) fftypes.JSONObject {
+	if t.maxEventDataSize <= 0 || event.Output == nil {
+		return nil
+	}
+	b, _ := json.Marshal(event.Output)
+	if int64(len(b)) <= t.maxEventDataSize {
+		return nil
+	}
+
+	keys := make([]string, 0, len(event.Output))
+	for k := range event.Output {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	truncated := fftypes.JSONObject{}
+	size := int64(2) // the enclosing braces
+	for _, k := range keys {
+		fb, _ := json.Marshal(fftypes.JSONObject{k: event.Output[k]})
+		fieldSize := int64(len(fb)) - 1 // less the braces, plus a separating comma
+		if size+fieldSize <= t.maxEventDataSize {
+			truncated[k] = event.Output[k]
+			size += fieldSize
+		}
+	}
+	log.L(ctx).Warnf("Output of blockchain event %s is %d bytes, exceeding the maximum of %d. Storing %d of %d fields on the event",
+		event.ProtocolID, len(b), t.maxEventDataSize, len(truncated), len(keys))
+
+	fullOutput := event.Output
+	event.Output = truncated
+	event.OutputTruncated = true
+	return fullOutput
+}
@@ -0,0 +1,156 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txcommon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLargeOutputEvent() *core.BlockchainEvent {
+	return &core.BlockchainEvent{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Output: fftypes.JSONObject{
+			"a":    "small",
+			"data": strings.Repeat("f", 100),
+			"z":    float64(12345),
+		},
+	}
+}
+
+func TestTruncateEventOutputDisabled(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+
+	event := newLargeOutputEvent()
+	assert.Nil(t, txHelper.truncateEventOutput(context.Background(), event))
+	assert.False(t, event.OutputTruncated)
+	assert.Len(t, event.Output, 3)
+}
+
+func TestTruncateEventOutputWithinLimit(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 1024
+
+	event := newLargeOutputEvent()
+	assert.Nil(t, txHelper.truncateEventOutput(context.Background(), event))
+	assert.False(t, event.OutputTruncated)
+}
+
+func TestTruncateEventOutput(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 30
+
+	event := newLargeOutputEvent()
+	full := txHelper.truncateEventOutput(context.Background(), event)
+	assert.Len(t, full, 3)
+	assert.True(t, event.OutputTruncated)
+	assert.Equal(t, fftypes.JSONObject{"a": "small", "z": float64(12345)}, event.Output)
+	assert.LessOrEqual(t, len(event.Output.String()), 30)
+}
+
+func TestInsertOrGetBlockchainEventTruncated(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 30
+	ctx := context.Background()
+
+	event := newLargeOutputEvent()
+	full := event.Output
+	txHelper.mdi.On("InsertOrGetBlockchainEvent", ctx, event).Return(nil, nil)
+	txHelper.mdi.On("InsertBlockchainEventOutput", ctx, "ns1", event.ID, full).Return(nil)
+
+	existing, err := txHelper.InsertOrGetBlockchainEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.True(t, event.OutputTruncated)
+}
+
+func TestInsertOrGetBlockchainEventTruncatedFail(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 30
+	ctx := context.Background()
+
+	event := newLargeOutputEvent()
+	txHelper.mdi.On("InsertOrGetBlockchainEvent", ctx, event).Return(nil, nil)
+	txHelper.mdi.On("InsertBlockchainEventOutput", ctx, "ns1", event.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := txHelper.InsertOrGetBlockchainEvent(ctx, event)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestInsertNewBlockchainEventsTruncated(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 30
+	ctx := context.Background()
+
+	event1 := newLargeOutputEvent()
+	full := event1.Output
+	event2 := &core.BlockchainEvent{ID: fftypes.NewUUID(), Namespace: "ns1"}
+	events := []*core.BlockchainEvent{event1, event2}
+	txHelper.mdi.On("InsertBlockchainEvents", ctx, events, mock.Anything).Return(nil)
+	txHelper.mdi.On("InsertBlockchainEventOutput", ctx, "ns1", event1.ID, full).Return(nil).Once()
+
+	inserted, err := txHelper.InsertNewBlockchainEvents(ctx, events)
+	assert.NoError(t, err)
+	assert.Len(t, inserted, 2)
+	assert.True(t, event1.OutputTruncated)
+	assert.False(t, event2.OutputTruncated)
+}
+
+func TestInsertNewBlockchainEventsTruncatedDuplicate(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 30
+	ctx := context.Background()
+
+	event := newLargeOutputEvent()
+	existingEvent := &core.BlockchainEvent{ID: fftypes.NewUUID()}
+	txHelper.mdi.On("InsertBlockchainEvents", ctx, []*core.BlockchainEvent{event}, mock.Anything).Return(fmt.Errorf("optimization bypass"))
+	txHelper.mdi.On("InsertOrGetBlockchainEvent", ctx, event).Return(existingEvent, nil)
+	txHelper.mdi.On("GetEvents", ctx, "ns1", mock.Anything).Return([]*core.Event{{}}, nil, nil)
+
+	inserted, err := txHelper.InsertNewBlockchainEvents(ctx, []*core.BlockchainEvent{event})
+	assert.NoError(t, err)
+	assert.Empty(t, inserted)
+}
+
+func TestInsertNewBlockchainEventsTruncatedFail(t *testing.T) {
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.maxEventDataSize = 30
+	ctx := context.Background()
+
+	event := newLargeOutputEvent()
+	txHelper.mdi.On("InsertBlockchainEvents", ctx, []*core.BlockchainEvent{event}, mock.Anything).Return(nil)
+	txHelper.mdi.On("InsertBlockchainEventOutput", ctx, "ns1", event.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := txHelper.InsertNewBlockchainEvents(ctx, []*core.BlockchainEvent{event})
+	assert.Regexp(t, "pop", err)
+}
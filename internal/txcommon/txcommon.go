@@ -20,7 +20,9 @@ import (
 	"context"
 	"database/sql/driver"
 	"strings"
+	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
@@ -41,6 +43,7 @@ type Helper interface {
 	InsertOrGetBlockchainEvent(ctx context.Context, event *core.BlockchainEvent) (existing *core.BlockchainEvent, err error)
 	InsertNewBlockchainEvents(ctx context.Context, events []*core.BlockchainEvent) (inserted []*core.BlockchainEvent, err error)
 	GetTransactionByIDCached(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error)
+	GetIdempotentTransaction(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error)
 	GetBlockchainEventByIDCached(ctx context.Context, id *fftypes.UUID) (*core.BlockchainEvent, error)
 	FindOperationInTransaction(ctx context.Context, tx *fftypes.UUID, opType core.OpType) (*core.Operation, error)
 }
@@ -51,6 +54,8 @@ type transactionHelper struct {
 	data                 data.Manager
 	transactionCache     cache.CInterface
 	blockchainEventCache cache.CInterface
+	maxEventDataSize     int64
+	idempotencyWindow    time.Duration
 }
 
 type BatchedTransactionInsert struct {
@@ -68,9 +73,11 @@ type TransactionInsertInput struct {
 
 func NewTransactionHelper(ctx context.Context, ns string, di database.Plugin, dm data.Manager, cacheManager cache.Manager) (Helper, error) {
 	t := &transactionHelper{
-		namespace: ns,
-		database:  di,
-		data:      dm,
+		namespace:         ns,
+		database:          di,
+		data:              dm,
+		maxEventDataSize:  config.GetByteSize(coreconfig.EventMaxIndexedDataSize),
+		idempotencyWindow: config.GetDuration(coreconfig.TransactionIdempotencyWindow),
 	}
 
 	transactionCache, err := cacheManager.GetCache(
@@ -121,6 +128,24 @@ func (t *transactionHelper) GetTransactionByIDCached(ctx context.Context, id *ff
 	return tx, nil
 }
 
+// GetIdempotentTransaction is called with the existing transaction for a duplicate idempotency key. The transaction is
+// returned if it was submitted within the idempotency window, so the original submission can be returned to the caller.
+// Otherwise nil is returned, and the duplicate should be reported as a conflict.
+func (t *transactionHelper) GetIdempotentTransaction(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error) {
+	if t.idempotencyWindow <= 0 {
+		return nil, nil
+	}
+	tx, err := t.GetTransactionByIDCached(ctx, id)
+	if err != nil || tx == nil || tx.Created == nil {
+		return nil, err
+	}
+	if time.Since(*tx.Created.Time()) > t.idempotencyWindow {
+		log.L(ctx).Infof("Transaction %s with idempotency key '%s' is outside of the idempotency window", tx.ID, tx.IdempotencyKey)
+		return nil, nil
+	}
+	return tx, nil
+}
+
 // SubmitNewTransaction is called when there is a new transaction being submitted by the local node
 func (t *transactionHelper) SubmitNewTransaction(ctx context.Context, txType core.TransactionType, idempotencyKey core.IdempotencyKey) (*fftypes.UUID, error) {
 
@@ -314,6 +339,7 @@ func (t *transactionHelper) GetBlockchainEventByIDCached(ctx context.Context, id
 }
 
 func (t *transactionHelper) InsertOrGetBlockchainEvent(ctx context.Context, event *core.BlockchainEvent) (existing *core.BlockchainEvent, err error) {
+	fullOutput := t.truncateEventOutput(ctx, event)
 	existing, err = t.database.InsertOrGetBlockchainEvent(ctx, event)
 	if err != nil {
 		return nil, err
@@ -322,11 +348,38 @@ func (t *transactionHelper) InsertOrGetBlockchainEvent(ctx context.Context, even
 		t.addBlockchainEventToCache(existing)
 		return existing, nil
 	}
+	if fullOutput != nil {
+		if err := t.database.InsertBlockchainEventOutput(ctx, event.Namespace, event.ID, fullOutput); err != nil {
+			return nil, err
+		}
+	}
 	t.addBlockchainEventToCache(event)
 	return nil, nil
 }
 
 func (t *transactionHelper) InsertNewBlockchainEvents(ctx context.Context, events []*core.BlockchainEvent) (inserted []*core.BlockchainEvent, err error) {
+	fullOutputs := make(map[fftypes.UUID]fftypes.JSONObject)
+	for _, event := range events {
+		if fullOutput := t.truncateEventOutput(ctx, event); fullOutput != nil {
+			fullOutputs[*event.ID] = fullOutput
+		}
+	}
+	inserted, err = t.insertNewBlockchainEvents(ctx, events)
+	if err != nil || len(fullOutputs) == 0 {
+		return inserted, err
+	}
+	// The full output is only stored for the events that were new
+	for _, event := range inserted {
+		if fullOutput, ok := fullOutputs[*event.ID]; ok {
+			if err := t.database.InsertBlockchainEventOutput(ctx, event.Namespace, event.ID, fullOutput); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return inserted, nil
+}
+
+func (t *transactionHelper) insertNewBlockchainEvents(ctx context.Context, events []*core.BlockchainEvent) (inserted []*core.BlockchainEvent, err error) {
 	// First we try and insert the whole bundle using batch insert
 	err = t.database.InsertBlockchainEvents(ctx, events, func() {
 		for _, event := range events {
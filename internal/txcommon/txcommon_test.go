@@ -504,6 +504,73 @@ func TestGetTransactionByIDCachedFail(t *testing.T) {
 
 }
 
+func TestGetIdempotentTransactionWithinWindow(t *testing.T) {
+
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.idempotencyWindow = 1 * time.Hour
+	ctx := context.Background()
+
+	txid := fftypes.NewUUID()
+	txHelper.mdi.On("GetTransactionByID", ctx, "ns1", txid).Return(&core.Transaction{
+		ID:        txid,
+		Namespace: "ns1",
+		Created:   fftypes.Now(),
+	}, nil)
+
+	tx, err := txHelper.GetIdempotentTransaction(ctx, txid)
+	assert.NoError(t, err)
+	assert.Equal(t, txid, tx.ID)
+
+}
+
+func TestGetIdempotentTransactionOutsideWindow(t *testing.T) {
+
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.idempotencyWindow = 1 * time.Hour
+	ctx := context.Background()
+
+	txid := fftypes.NewUUID()
+	created := fftypes.FFTime(time.Now().Add(-2 * time.Hour))
+	txHelper.mdi.On("GetTransactionByID", ctx, "ns1", txid).Return(&core.Transaction{
+		ID:        txid,
+		Namespace: "ns1",
+		Created:   &created,
+	}, nil)
+
+	tx, err := txHelper.GetIdempotentTransaction(ctx, txid)
+	assert.NoError(t, err)
+	assert.Nil(t, tx)
+
+}
+
+func TestGetIdempotentTransactionFail(t *testing.T) {
+
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+	txHelper.idempotencyWindow = 1 * time.Hour
+	ctx := context.Background()
+
+	txid := fftypes.NewUUID()
+	txHelper.mdi.On("GetTransactionByID", ctx, "ns1", txid).Return(nil, fmt.Errorf("pop"))
+
+	_, err := txHelper.GetIdempotentTransaction(ctx, txid)
+	assert.EqualError(t, err, "pop")
+
+}
+
+func TestGetIdempotentTransactionDisabled(t *testing.T) {
+
+	txHelper, _, _ := NewTestTransactionHelper()
+	defer txHelper.cleanup(t)
+
+	tx, err := txHelper.GetIdempotentTransaction(context.Background(), fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Nil(t, tx)
+
+}
+
 func TestGetBlockchainEventByIDCached(t *testing.T) {
 	config.Set(coreconfig.CacheEnabled, true)
 	mdi := &databasemocks.Plugin{}
@@ -57,7 +57,7 @@ func newTestTransactionWriter(t *testing.T, dbCaps *database.Capabilities, mods
 
 	txh, err := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cm)
 	assert.NoError(t, err)
-	ops, err := operations.NewOperationsManager(ctx, "ns1", mdi, txh, cm)
+	ops, err := operations.NewOperationsManager(ctx, "ns1", nil, mdi, txh, cm)
 	assert.NoError(t, err)
 	txw := NewTransactionWriter(ctx, "ns1", mdi, txh, ops).(*txWriter)
 	return ctx, txw, func() {
@@ -116,6 +116,36 @@ func (_m *Manager) DeleteTokenPool(ctx context.Context, poolNameOrID string) err
 	return r0
 }
 
+// GetToken provides a mock function with given fields: ctx, poolNameOrID, tokenIndex
+func (_m *Manager) GetToken(ctx context.Context, poolNameOrID string, tokenIndex string) (*core.Token, error) {
+	ret := _m.Called(ctx, poolNameOrID, tokenIndex)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetToken")
+	}
+
+	var r0 *core.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.Token, error)); ok {
+		return rf(ctx, poolNameOrID, tokenIndex)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.Token); ok {
+		r0 = rf(ctx, poolNameOrID, tokenIndex)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, poolNameOrID, tokenIndex)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenAccountPools provides a mock function with given fields: ctx, key, filter
 func (_m *Manager) GetTokenAccountPools(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, key, filter)
@@ -272,6 +302,45 @@ func (_m *Manager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter)
 	return r0, r1, r2
 }
 
+// GetTokenBalancesWithMetadata provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenBalancesWithMetadata(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTokenBalancesWithMetadata")
+	}
+
+	var r0 []*core.TokenBalance
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenBalance); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenConnectors provides a mock function with given fields: ctx
 func (_m *Manager) GetTokenConnectors(ctx context.Context) []*core.TokenConnector {
 	ret := _m.Called(ctx)
@@ -663,6 +732,36 @@ func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation)
 	return r0, r1, r2
 }
 
+// SimulateTransferTokens provides a mock function with given fields: ctx, transfer
+func (_m *Manager) SimulateTransferTokens(ctx context.Context, transfer *core.TokenTransferInput) (*core.Simulation, error) {
+	ret := _m.Called(ctx, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateTransferTokens")
+	}
+
+	var r0 *core.Simulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferInput) (*core.Simulation, error)); ok {
+		return rf(ctx, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferInput) *core.Simulation); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Simulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferInput) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() error {
 	ret := _m.Called()
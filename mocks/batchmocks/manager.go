@@ -7,6 +7,8 @@ import (
 
 	batch "github.com/hyperledger/firefly/internal/batch"
 
+	core "github.com/hyperledger/firefly/pkg/core"
+
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 	mock "github.com/stretchr/testify/mock"
@@ -40,6 +42,56 @@ func (_m *Manager) Close() {
 	_m.Called()
 }
 
+// Flush provides a mock function with given fields: ctx
+func (_m *Manager) Flush(ctx context.Context) ([]*fftypes.UUID, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Flush")
+	}
+
+	var r0 []*fftypes.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*fftypes.UUID, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*fftypes.UUID); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*fftypes.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConfig provides a mock function with given fields:
+func (_m *Manager) GetConfig() []*batch.DispatcherConfig {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConfig")
+	}
+
+	var r0 []*batch.DispatcherConfig
+	if rf, ok := ret.Get(0).(func() []*batch.DispatcherConfig); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*batch.DispatcherConfig)
+		}
+	}
+
+	return r0
+}
+
 // LoadContexts provides a mock function with given fields: ctx, payload
 func (_m *Manager) LoadContexts(ctx context.Context, payload *batch.DispatchPayload) error {
 	ret := _m.Called(ctx, payload)
@@ -78,11 +130,71 @@ func (_m *Manager) NewMessages() chan<- int64 {
 	return r0
 }
 
+// PreviewBatch provides a mock function with given fields: ctx, msg, sizeEstimate
+func (_m *Manager) PreviewBatch(ctx context.Context, msg *core.Message, sizeEstimate int64) (*batch.BatchPreview, error) {
+	ret := _m.Called(ctx, msg, sizeEstimate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewBatch")
+	}
+
+	var r0 *batch.BatchPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, int64) (*batch.BatchPreview, error)); ok {
+		return rf(ctx, msg, sizeEstimate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, int64) *batch.BatchPreview); ok {
+		r0 = rf(ctx, msg, sizeEstimate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*batch.BatchPreview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Message, int64) error); ok {
+		r1 = rf(ctx, msg, sizeEstimate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RegisterDispatcher provides a mock function with given fields: name, pinned, msgTypes, handler, batchOptions
 func (_m *Manager) RegisterDispatcher(name string, pinned bool, msgTypes []fftypes.FFEnum, handler batch.DispatchHandler, batchOptions batch.DispatcherOptions) {
 	_m.Called(name, pinned, msgTypes, handler, batchOptions)
 }
 
+// Restart provides a mock function with given fields: ctx
+func (_m *Manager) Restart(ctx context.Context) (*batch.RestartResult, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restart")
+	}
+
+	var r0 *batch.RestartResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*batch.RestartResult, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *batch.RestartResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*batch.RestartResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() error {
 	ret := _m.Called()
@@ -121,11 +233,73 @@ func (_m *Manager) Status() *batch.ManagerStatus {
 	return r0
 }
 
+// UpdateConfig provides a mock function with given fields: ctx, update
+func (_m *Manager) UpdateConfig(ctx context.Context, update *batch.ConfigUpdate) ([]*batch.DispatcherConfig, error) {
+	ret := _m.Called(ctx, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateConfig")
+	}
+
+	var r0 []*batch.DispatcherConfig
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *batch.ConfigUpdate) ([]*batch.DispatcherConfig, error)); ok {
+		return rf(ctx, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *batch.ConfigUpdate) []*batch.DispatcherConfig); ok {
+		r0 = rf(ctx, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*batch.DispatcherConfig)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *batch.ConfigUpdate) error); ok {
+		r1 = rf(ctx, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitStop provides a mock function with given fields:
 func (_m *Manager) WaitStop() {
 	_m.Called()
 }
 
+// WatchStatus provides a mock function with given fields:
+func (_m *Manager) WatchStatus() (<-chan struct{}, func()) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchStatus")
+	}
+
+	var r0 <-chan struct{}
+	var r1 func()
+	if rf, ok := ret.Get(0).(func() (<-chan struct{}, func())); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() <-chan struct{}); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan struct{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() func()); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	return r0, r1
+}
+
 // NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewManager(t interface {
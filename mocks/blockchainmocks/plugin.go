@@ -118,6 +118,36 @@ func (_m *Plugin) CheckOverlappingLocations(ctx context.Context, left *fftypes.J
 	return r0, r1
 }
 
+// CompileContractInterface provides a mock function with given fields: ctx, input
+func (_m *Plugin) CompileContractInterface(ctx context.Context, input *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompileContractInterface")
+	}
+
+	var r0 *fftypes.JSONAny
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.JSONAny) (*fftypes.JSONAny, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.JSONAny) *fftypes.JSONAny); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.JSONAny)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.JSONAny) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteContractListener provides a mock function with given fields: ctx, subscription, okNotFound
 func (_m *Plugin) DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error {
 	ret := _m.Called(ctx, subscription, okNotFound)
@@ -164,6 +194,36 @@ func (_m *Plugin) DeployContract(ctx context.Context, nsOpID string, signingKey
 	return r0, r1
 }
 
+// GenerateDeployDefinition provides a mock function with given fields: ctx, ffi, constructorParams
+func (_m *Plugin) GenerateDeployDefinition(ctx context.Context, ffi *fftypes.FFI, constructorParams fftypes.FFIParams) (*fftypes.JSONAny, error) {
+	ret := _m.Called(ctx, ffi, constructorParams)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateDeployDefinition")
+	}
+
+	var r0 *fftypes.JSONAny
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI, fftypes.FFIParams) (*fftypes.JSONAny, error)); ok {
+		return rf(ctx, ffi, constructorParams)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFI, fftypes.FFIParams) *fftypes.JSONAny); ok {
+		r0 = rf(ctx, ffi, constructorParams)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.JSONAny)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.FFI, fftypes.FFIParams) error); ok {
+		r1 = rf(ctx, ffi, constructorParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateErrorSignature provides a mock function with given fields: ctx, errorDef
 func (_m *Plugin) GenerateErrorSignature(ctx context.Context, errorDef *fftypes.FFIErrorDefinition) string {
 	ret := _m.Called(ctx, errorDef)
@@ -268,6 +328,41 @@ func (_m *Plugin) GenerateFFI(ctx context.Context, generationRequest *fftypes.FF
 	return r0, r1
 }
 
+// GenerateMethodSelector provides a mock function with given fields: ctx, method
+func (_m *Plugin) GenerateMethodSelector(ctx context.Context, method *fftypes.FFIMethod) (string, string, error) {
+	ret := _m.Called(ctx, method)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateMethodSelector")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFIMethod) (string, string, error)); ok {
+		return rf(ctx, method)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFIMethod) string); ok {
+		r0 = rf(ctx, method)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.FFIMethod) string); ok {
+		r1 = rf(ctx, method)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *fftypes.FFIMethod) error); ok {
+		r2 = rf(ctx, method)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetAndConvertDeprecatedContractConfig provides a mock function with given fields: ctx
 func (_m *Plugin) GetAndConvertDeprecatedContractConfig(ctx context.Context) (*fftypes.JSONAny, string, error) {
 	ret := _m.Called(ctx)
@@ -566,6 +661,24 @@ func (_m *Plugin) ParseInterface(ctx context.Context, method *fftypes.FFIMethod,
 	return r0, r1
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *Plugin) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // QueryContract provides a mock function with given fields: ctx, signingKey, location, parsedMethod, input, options
 func (_m *Plugin) QueryContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (interface{}, error) {
 	ret := _m.Called(ctx, signingKey, location, parsedMethod, input, options)
@@ -639,6 +752,36 @@ func (_m *Plugin) SetOperationHandler(namespace string, handler core.OperationCa
 	_m.Called(namespace, handler)
 }
 
+// SimulateContract provides a mock function with given fields: ctx, signingKey, location, parsedMethod, input, options
+func (_m *Plugin) SimulateContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (*core.Simulation, error) {
+	ret := _m.Called(ctx, signingKey, location, parsedMethod, input, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateContract")
+	}
+
+	var r0 *core.Simulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.JSONAny, interface{}, map[string]interface{}, map[string]interface{}) (*core.Simulation, error)); ok {
+		return rf(ctx, signingKey, location, parsedMethod, input, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.JSONAny, interface{}, map[string]interface{}, map[string]interface{}) *core.Simulation); ok {
+		r0 = rf(ctx, signingKey, location, parsedMethod, input, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Simulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.JSONAny, interface{}, map[string]interface{}, map[string]interface{}) error); ok {
+		r1 = rf(ctx, signingKey, location, parsedMethod, input, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StartNamespace provides a mock function with given fields: ctx, namespace
 func (_m *Plugin) StartNamespace(ctx context.Context, namespace string) error {
 	ret := _m.Called(ctx, namespace)
@@ -711,6 +854,24 @@ func (_m *Plugin) SubmitNetworkAction(ctx context.Context, nsOpID string, signin
 	return r0
 }
 
+// UpdateContractListener provides a mock function with given fields: ctx, subscription
+func (_m *Plugin) UpdateContractListener(ctx context.Context, subscription *core.ContractListener) error {
+	ret := _m.Called(ctx, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateContractListener")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.ContractListener) error); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ValidateInvokeRequest provides a mock function with given fields: ctx, parsedMethod, input, hasMessage
 func (_m *Plugin) ValidateInvokeRequest(ctx context.Context, parsedMethod interface{}, input map[string]interface{}, hasMessage bool) error {
 	ret := _m.Called(ctx, parsedMethod, input, hasMessage)
@@ -3,11 +3,14 @@
 package broadcastmocks
 
 import (
+	batch "github.com/hyperledger/firefly/internal/batch"
+
 	context "context"
 
-	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 	core "github.com/hyperledger/firefly/pkg/core"
 
+	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
+
 	mock "github.com/stretchr/testify/mock"
 
 	syncasync "github.com/hyperledger/firefly/internal/syncasync"
@@ -18,6 +21,36 @@ type Manager struct {
 	mock.Mock
 }
 
+// BroadcastBridgedMessage provides a mock function with given fields: ctx, in
+func (_m *Manager) BroadcastBridgedMessage(ctx context.Context, in *core.MessageInOut) (*core.Message, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BroadcastBridgedMessage")
+	}
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) (*core.Message, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) *core.Message); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.MessageInOut) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BroadcastMessage provides a mock function with given fields: ctx, in, waitConfirm
 func (_m *Manager) BroadcastMessage(ctx context.Context, in *core.MessageInOut, waitConfirm bool) (*core.Message, error) {
 	ret := _m.Called(ctx, in, waitConfirm)
@@ -48,6 +81,36 @@ func (_m *Manager) BroadcastMessage(ctx context.Context, in *core.MessageInOut,
 	return r0, r1
 }
 
+// BroadcastMessages provides a mock function with given fields: ctx, in
+func (_m *Manager) BroadcastMessages(ctx context.Context, in []*core.MessageInOut) ([]*core.MessageSubmitResult, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BroadcastMessages")
+	}
+
+	var r0 []*core.MessageSubmitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.MessageInOut) ([]*core.MessageSubmitResult, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.MessageInOut) []*core.MessageSubmitResult); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.MessageSubmitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*core.MessageInOut) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Name provides a mock function with given fields:
 func (_m *Manager) Name() string {
 	ret := _m.Called()
@@ -116,6 +179,36 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// PreviewBatch provides a mock function with given fields: ctx, in
+func (_m *Manager) PreviewBatch(ctx context.Context, in *core.MessageInOut) (*batch.BatchPreview, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewBatch")
+	}
+
+	var r0 *batch.BatchPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) (*batch.BatchPreview, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageInOut) *batch.BatchPreview); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*batch.BatchPreview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.MessageInOut) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PublishDataBlob provides a mock function with given fields: ctx, id, idempotencyKey
 func (_m *Manager) PublishDataBlob(ctx context.Context, id string, idempotencyKey core.IdempotencyKey) (*core.Data, error) {
 	ret := _m.Called(ctx, id, idempotencyKey)
@@ -5,6 +5,7 @@ package contractmocks
 import (
 	context "context"
 
+	contracts "github.com/hyperledger/firefly/internal/contracts"
 	core "github.com/hyperledger/firefly/pkg/core"
 
 	ffapi "github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -12,6 +13,8 @@ import (
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Manager is an autogenerated mock type for the Manager type
@@ -79,6 +82,24 @@ func (_m *Manager) AddContractListener(ctx context.Context, listener *core.Contr
 	return r0, r1
 }
 
+// CheckFFILifecycle provides a mock function with given fields: ctx, ref
+func (_m *Manager) CheckFFILifecycle(ctx context.Context, ref *fftypes.FFIReference) error {
+	ret := _m.Called(ctx, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckFFILifecycle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFIReference) error); ok {
+		r0 = rf(ctx, ref)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ConstructContractListenerSignature provides a mock function with given fields: ctx, listener
 func (_m *Manager) ConstructContractListenerSignature(ctx context.Context, listener *core.ContractListenerInput) (*core.ContractListenerSignatureOutput, error) {
 	ret := _m.Called(ctx, listener)
@@ -127,6 +148,54 @@ func (_m *Manager) DeleteContractAPI(ctx context.Context, apiName string) error
 	return r0
 }
 
+// DeleteContractAPIListeners provides a mock function with given fields: ctx, apiName, eventPath, filter
+func (_m *Manager) DeleteContractAPIListeners(ctx context.Context, apiName string, eventPath string, filter ffapi.AndFilter) ([]*core.ContractListenerDeletion, error) {
+	ret := _m.Called(ctx, apiName, eventPath, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteContractAPIListeners")
+	}
+
+	var r0 []*core.ContractListenerDeletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.AndFilter) ([]*core.ContractListenerDeletion, error)); ok {
+		return rf(ctx, apiName, eventPath, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.AndFilter) []*core.ContractListenerDeletion); ok {
+		r0 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ContractListenerDeletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ffapi.AndFilter) error); ok {
+		r1 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteContractListenerByID provides a mock function with given fields: ctx, id
+func (_m *Manager) DeleteContractListenerByID(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteContractListenerByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteContractListenerByNameOrID provides a mock function with given fields: ctx, nameOrID
 func (_m *Manager) DeleteContractListenerByNameOrID(ctx context.Context, nameOrID string) error {
 	ret := _m.Called(ctx, nameOrID)
@@ -223,6 +292,75 @@ func (_m *Manager) GenerateFFI(ctx context.Context, generationRequest *fftypes.F
 	return r0, r1
 }
 
+// GenerateFFIFromSource provides a mock function with given fields: ctx, generationRequest
+func (_m *Manager) GenerateFFIFromSource(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, generationRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateFFIFromSource")
+	}
+
+	var r0 *fftypes.FFI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFIGenerationRequest) (*fftypes.FFI, error)); ok {
+		return rf(ctx, generationRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.FFIGenerationRequest) *fftypes.FFI); ok {
+		r0 = rf(ctx, generationRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.FFI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.FFIGenerationRequest) error); ok {
+		r1 = rf(ctx, generationRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllContractAPIListeners provides a mock function with given fields: ctx, apiName, filter
+func (_m *Manager) GetAllContractAPIListeners(ctx context.Context, apiName string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, apiName, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllContractAPIListeners")
+	}
+
+	var r0 []*core.ContractListener
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, apiName, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.ContractListener); ok {
+		r0 = rf(ctx, apiName, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ContractListener)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, apiName, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, apiName, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetContractAPI provides a mock function with given fields: ctx, httpServerURL, apiName
 func (_m *Manager) GetContractAPI(ctx context.Context, httpServerURL string, apiName string) (*core.ContractAPI, error) {
 	ret := _m.Called(ctx, httpServerURL, apiName)
@@ -283,6 +421,45 @@ func (_m *Manager) GetContractAPIInterface(ctx context.Context, apiName string)
 	return r0, r1
 }
 
+// GetContractAPIListenerEvents provides a mock function with given fields: ctx, apiName, eventPath, filter
+func (_m *Manager) GetContractAPIListenerEvents(ctx context.Context, apiName string, eventPath string, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, apiName, eventPath, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContractAPIListenerEvents")
+	}
+
+	var r0 []*core.BlockchainEvent
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, apiName, eventPath, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.AndFilter) []*core.BlockchainEvent); ok {
+		r0 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BlockchainEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetContractAPIListeners provides a mock function with given fields: ctx, apiName, eventPath, filter
 func (_m *Manager) GetContractAPIListeners(ctx context.Context, apiName string, eventPath string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, apiName, eventPath, filter)
@@ -322,6 +499,36 @@ func (_m *Manager) GetContractAPIListeners(ctx context.Context, apiName string,
 	return r0, r1, r2
 }
 
+// GetContractAPIListenersHealth provides a mock function with given fields: ctx, apiName, window
+func (_m *Manager) GetContractAPIListenersHealth(ctx context.Context, apiName string, window time.Duration) (*core.ContractAPIListenerHealth, error) {
+	ret := _m.Called(ctx, apiName, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContractAPIListenersHealth")
+	}
+
+	var r0 *core.ContractAPIListenerHealth
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (*core.ContractAPIListenerHealth, error)); ok {
+		return rf(ctx, apiName, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) *core.ContractAPIListenerHealth); ok {
+		r0 = rf(ctx, apiName, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractAPIListenerHealth)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, apiName, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetContractAPIs provides a mock function with given fields: ctx, httpServerURL, filter
 func (_m *Manager) GetContractAPIs(ctx context.Context, httpServerURL string, filter ffapi.AndFilter) ([]*core.ContractAPI, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, httpServerURL, filter)
@@ -550,6 +757,36 @@ func (_m *Manager) GetFFIByIDWithChildren(ctx context.Context, id *fftypes.UUID)
 	return r0, r1
 }
 
+// GetFFIDiff provides a mock function with given fields: ctx, name, fromVersion, toVersion
+func (_m *Manager) GetFFIDiff(ctx context.Context, name string, fromVersion string, toVersion string) (*core.DefinitionDiff, error) {
+	ret := _m.Called(ctx, name, fromVersion, toVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFFIDiff")
+	}
+
+	var r0 *core.DefinitionDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*core.DefinitionDiff, error)); ok {
+		return rf(ctx, name, fromVersion, toVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *core.DefinitionDiff); ok {
+		r0 = rf(ctx, name, fromVersion, toVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DefinitionDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, fromVersion, toVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFFIEvents provides a mock function with given fields: ctx, id
 func (_m *Manager) GetFFIEvents(ctx context.Context, id *fftypes.UUID) ([]*fftypes.FFIEvent, error) {
 	ret := _m.Called(ctx, id)
@@ -580,6 +817,66 @@ func (_m *Manager) GetFFIEvents(ctx context.Context, id *fftypes.UUID) ([]*fftyp
 	return r0, r1
 }
 
+// GetFFILifecycle provides a mock function with given fields: ctx, name, version
+func (_m *Manager) GetFFILifecycle(ctx context.Context, name string, version string) (*core.ContractInterfaceLifecycle, error) {
+	ret := _m.Called(ctx, name, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFFILifecycle")
+	}
+
+	var r0 *core.ContractInterfaceLifecycle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.ContractInterfaceLifecycle, error)); ok {
+		return rf(ctx, name, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.ContractInterfaceLifecycle); ok {
+		r0 = rf(ctx, name, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractInterfaceLifecycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFFIMethodSelector provides a mock function with given fields: ctx, name, version, methodPath
+func (_m *Manager) GetFFIMethodSelector(ctx context.Context, name string, version string, methodPath string) (*core.ContractMethodSelector, error) {
+	ret := _m.Called(ctx, name, version, methodPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFFIMethodSelector")
+	}
+
+	var r0 *core.ContractMethodSelector
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*core.ContractMethodSelector, error)); ok {
+		return rf(ctx, name, version, methodPath)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *core.ContractMethodSelector); ok {
+		r0 = rf(ctx, name, version, methodPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractMethodSelector)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, version, methodPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFFIMethods provides a mock function with given fields: ctx, id
 func (_m *Manager) GetFFIMethods(ctx context.Context, id *fftypes.UUID) ([]*fftypes.FFIMethod, error) {
 	ret := _m.Called(ctx, id)
@@ -679,6 +976,45 @@ func (_m *Manager) GetFFIs(ctx context.Context, filter ffapi.AndFilter) ([]*ffty
 	return r0, r1, r2
 }
 
+// GetIdleContractListeners provides a mock function with given fields: ctx, minAge, filter
+func (_m *Manager) GetIdleContractListeners(ctx context.Context, minAge time.Duration, filter ffapi.AndFilter) ([]*core.IdleContractListener, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, minAge, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdleContractListeners")
+	}
+
+	var r0 []*core.IdleContractListener
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, ffapi.AndFilter) ([]*core.IdleContractListener, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, minAge, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration, ffapi.AndFilter) []*core.IdleContractListener); ok {
+		r0 = rf(ctx, minAge, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.IdleContractListener)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, minAge, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, time.Duration, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, minAge, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // InvokeContract provides a mock function with given fields: ctx, req, waitConfirm
 func (_m *Manager) InvokeContract(ctx context.Context, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error) {
 	ret := _m.Called(ctx, req, waitConfirm)
@@ -739,6 +1075,36 @@ func (_m *Manager) InvokeContractAPI(ctx context.Context, apiName string, method
 	return r0, r1
 }
 
+// IterateContractAPIListeners provides a mock function with given fields: ctx, apiName, eventPath, filter
+func (_m *Manager) IterateContractAPIListeners(ctx context.Context, apiName string, eventPath string, filter ffapi.AndFilter) (contracts.ContractListenerIterator, error) {
+	ret := _m.Called(ctx, apiName, eventPath, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterateContractAPIListeners")
+	}
+
+	var r0 contracts.ContractListenerIterator
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.AndFilter) (contracts.ContractListenerIterator, error)); ok {
+		return rf(ctx, apiName, eventPath, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.AndFilter) contracts.ContractListenerIterator); ok {
+		r0 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(contracts.ContractListenerIterator)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ffapi.AndFilter) error); ok {
+		r1 = rf(ctx, apiName, eventPath, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Name provides a mock function with given fields:
 func (_m *Manager) Name() string {
 	ret := _m.Called()
@@ -878,6 +1244,126 @@ func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation)
 	return r0, r1, r2
 }
 
+// SimulateContract provides a mock function with given fields: ctx, req
+func (_m *Manager) SimulateContract(ctx context.Context, req *core.ContractCallRequest) (*core.Simulation, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateContract")
+	}
+
+	var r0 *core.Simulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.ContractCallRequest) (*core.Simulation, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.ContractCallRequest) *core.Simulation); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Simulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.ContractCallRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SimulateContractAPI provides a mock function with given fields: ctx, apiName, methodPath, req
+func (_m *Manager) SimulateContractAPI(ctx context.Context, apiName string, methodPath string, req *core.ContractCallRequest) (*core.Simulation, error) {
+	ret := _m.Called(ctx, apiName, methodPath, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateContractAPI")
+	}
+
+	var r0 *core.Simulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.ContractCallRequest) (*core.Simulation, error)); ok {
+		return rf(ctx, apiName, methodPath, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.ContractCallRequest) *core.Simulation); ok {
+		r0 = rf(ctx, apiName, methodPath, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Simulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *core.ContractCallRequest) error); ok {
+		r1 = rf(ctx, apiName, methodPath, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateContractAPIListener provides a mock function with given fields: ctx, apiName, eventPath, update
+func (_m *Manager) UpdateContractAPIListener(ctx context.Context, apiName string, eventPath string, update *core.ContractListenerUpdate) (*core.ContractListener, error) {
+	ret := _m.Called(ctx, apiName, eventPath, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateContractAPIListener")
+	}
+
+	var r0 *core.ContractListener
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.ContractListenerUpdate) (*core.ContractListener, error)); ok {
+		return rf(ctx, apiName, eventPath, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.ContractListenerUpdate) *core.ContractListener); ok {
+		r0 = rf(ctx, apiName, eventPath, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractListener)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *core.ContractListenerUpdate) error); ok {
+		r1 = rf(ctx, apiName, eventPath, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFFILifecycle provides a mock function with given fields: ctx, name, version, update
+func (_m *Manager) UpdateFFILifecycle(ctx context.Context, name string, version string, update *core.DefinitionLifecycleUpdate) (*core.ContractInterfaceLifecycle, error) {
+	ret := _m.Called(ctx, name, version, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFFILifecycle")
+	}
+
+	var r0 *core.ContractInterfaceLifecycle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.DefinitionLifecycleUpdate) (*core.ContractInterfaceLifecycle, error)); ok {
+		return rf(ctx, name, version, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.DefinitionLifecycleUpdate) *core.ContractInterfaceLifecycle); ok {
+		r0 = rf(ctx, name, version, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractInterfaceLifecycle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *core.DefinitionLifecycleUpdate) error); ok {
+		r1 = rf(ctx, name, version, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewManager(t interface {
@@ -23,6 +23,52 @@ type Plugin struct {
 	mock.Mock
 }
 
+// AcquireLeaderLock provides a mock function with given fields: ctx, lock, now
+func (_m *Plugin) AcquireLeaderLock(ctx context.Context, lock *core.LeaderLock, now *fftypes.FFTime) (bool, error) {
+	ret := _m.Called(ctx, lock, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcquireLeaderLock")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.LeaderLock, *fftypes.FFTime) (bool, error)); ok {
+		return rf(ctx, lock, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.LeaderLock, *fftypes.FFTime) bool); ok {
+		r0 = rf(ctx, lock, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.LeaderLock, *fftypes.FFTime) error); ok {
+		r1 = rf(ctx, lock, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddChartRollups provides a mock function with given fields: ctx, namespace, rollups
+func (_m *Plugin) AddChartRollups(ctx context.Context, namespace string, rollups []*core.ChartRollup) error {
+	ret := _m.Called(ctx, namespace, rollups)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddChartRollups")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*core.ChartRollup) error); ok {
+		r0 = rf(ctx, namespace, rollups)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Capabilities provides a mock function with given fields:
 func (_m *Plugin) Capabilities() *database.Capabilities {
 	ret := _m.Called()
@@ -115,6 +161,42 @@ func (_m *Plugin) DeleteData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// DeleteDeadLetter provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEvents provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteFFI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteFFI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -133,6 +215,24 @@ func (_m *Plugin) DeleteFFI(ctx context.Context, namespace string, id *fftypes.U
 	return r0
 }
 
+// DeleteMessages provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteMessages(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMessages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteNonce provides a mock function with given fields: ctx, hash
 func (_m *Plugin) DeleteNonce(ctx context.Context, hash *fftypes.Bytes32) error {
 	ret := _m.Called(ctx, hash)
@@ -169,6 +269,42 @@ func (_m *Plugin) DeleteOffset(ctx context.Context, t fftypes.FFEnum, name strin
 	return r0
 }
 
+// DeleteOperationNotification provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteOperationNotification(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOperationNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteOperations provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteOperations(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOperations")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSubscriptionByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -259,6 +395,24 @@ func (_m *Plugin) DeleteTokenTransfers(ctx context.Context, namespace string, po
 	return r0
 }
 
+// DeleteUnreferencedData provides a mock function with given fields: ctx, namespace, ids
+func (_m *Plugin) DeleteUnreferencedData(ctx context.Context, namespace string, ids []*fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUnreferencedData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetBatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BatchPersisted, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -487,6 +641,66 @@ func (_m *Plugin) GetBlockchainEventByProtocolID(ctx context.Context, namespace
 	return r0, r1
 }
 
+// GetBlockchainEventListenerTimestamps provides a mock function with given fields: ctx, namespace, listenerIDs
+func (_m *Plugin) GetBlockchainEventListenerTimestamps(ctx context.Context, namespace string, listenerIDs []*fftypes.UUID) (map[fftypes.UUID]*fftypes.FFTime, error) {
+	ret := _m.Called(ctx, namespace, listenerIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockchainEventListenerTimestamps")
+	}
+
+	var r0 map[fftypes.UUID]*fftypes.FFTime
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) (map[fftypes.UUID]*fftypes.FFTime, error)); ok {
+		return rf(ctx, namespace, listenerIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) map[fftypes.UUID]*fftypes.FFTime); ok {
+		r0 = rf(ctx, namespace, listenerIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[fftypes.UUID]*fftypes.FFTime)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, listenerIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockchainEventOutput provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetBlockchainEventOutput(ctx context.Context, namespace string, id *fftypes.UUID) (fftypes.JSONObject, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockchainEventOutput")
+	}
+
+	var r0 fftypes.JSONObject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (fftypes.JSONObject, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) fftypes.JSONObject); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(fftypes.JSONObject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBlockchainEvents provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -526,6 +740,36 @@ func (_m *Plugin) GetBlockchainEvents(ctx context.Context, namespace string, fil
 	return r0, r1, r2
 }
 
+// GetChartCounts provides a mock function with given fields: ctx, namespace, query
+func (_m *Plugin) GetChartCounts(ctx context.Context, namespace string, query *database.ChartCountQuery) ([]*core.ChartCount, error) {
+	ret := _m.Called(ctx, namespace, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChartCounts")
+	}
+
+	var r0 []*core.ChartCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *database.ChartCountQuery) ([]*core.ChartCount, error)); ok {
+		return rf(ctx, namespace, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *database.ChartCountQuery) []*core.ChartCount); ok {
+		r0 = rf(ctx, namespace, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ChartCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *database.ChartCountQuery) error); ok {
+		r1 = rf(ctx, namespace, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChartHistogram provides a mock function with given fields: ctx, namespace, intervals, collection
 func (_m *Plugin) GetChartHistogram(ctx context.Context, namespace string, intervals []core.ChartHistogramInterval, collection database.CollectionName) ([]*core.ChartHistogram, error) {
 	ret := _m.Called(ctx, namespace, intervals, collection)
@@ -556,6 +800,64 @@ func (_m *Plugin) GetChartHistogram(ctx context.Context, namespace string, inter
 	return r0, r1
 }
 
+// GetChartNextSequence provides a mock function with given fields: ctx, namespace, collection, after, limit
+func (_m *Plugin) GetChartNextSequence(ctx context.Context, namespace string, collection database.CollectionName, after int64, limit int) (int64, error) {
+	ret := _m.Called(ctx, namespace, collection, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChartNextSequence")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, database.CollectionName, int64, int) (int64, error)); ok {
+		return rf(ctx, namespace, collection, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, database.CollectionName, int64, int) int64); ok {
+		r0 = rf(ctx, namespace, collection, after, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, database.CollectionName, int64, int) error); ok {
+		r1 = rf(ctx, namespace, collection, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChartRollups provides a mock function with given fields: ctx, namespace, collection, groupBy, startTime, endTime
+func (_m *Plugin) GetChartRollups(ctx context.Context, namespace string, collection database.CollectionName, groupBy string, startTime *fftypes.FFTime, endTime *fftypes.FFTime) ([]*core.ChartCount, error) {
+	ret := _m.Called(ctx, namespace, collection, groupBy, startTime, endTime)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChartRollups")
+	}
+
+	var r0 []*core.ChartCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, database.CollectionName, string, *fftypes.FFTime, *fftypes.FFTime) ([]*core.ChartCount, error)); ok {
+		return rf(ctx, namespace, collection, groupBy, startTime, endTime)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, database.CollectionName, string, *fftypes.FFTime, *fftypes.FFTime) []*core.ChartCount); ok {
+		r0 = rf(ctx, namespace, collection, groupBy, startTime, endTime)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ChartCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, database.CollectionName, string, *fftypes.FFTime, *fftypes.FFTime) error); ok {
+		r1 = rf(ctx, namespace, collection, groupBy, startTime, endTime)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetContractAPIByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetContractAPIByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.ContractAPI, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -1051,6 +1353,75 @@ func (_m *Plugin) GetDatatypes(ctx context.Context, namespace string, filter ffa
 	return r0, r1, r2
 }
 
+// GetDeadLetterByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetterByID")
+	}
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.DeadLetter, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.DeadLetter); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetters")
+	}
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetEventByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetEventByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Event, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -1357,6 +1728,34 @@ func (_m *Plugin) GetFFIEvents(ctx context.Context, namespace string, filter ffa
 	return r0, r1, r2
 }
 
+// GetFFILifecycle provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetFFILifecycle(ctx context.Context, namespace string, id *fftypes.UUID) (fftypes.FFEnum, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFFILifecycle")
+	}
+
+	var r0 fftypes.FFEnum
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (fftypes.FFEnum, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) fftypes.FFEnum); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Get(0).(fftypes.FFEnum)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFFIMethod provides a mock function with given fields: ctx, namespace, interfaceID, pathName
 func (_m *Plugin) GetFFIMethod(ctx context.Context, namespace string, interfaceID *fftypes.UUID, pathName string) (*fftypes.FFIMethod, error) {
 	ret := _m.Called(ctx, namespace, interfaceID, pathName)
@@ -1608,24 +2007,84 @@ func (_m *Plugin) GetIdentityByID(ctx context.Context, namespace string, id *fft
 	ret := _m.Called(ctx, namespace, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetIdentityByID")
+		panic("no return value specified for GetIdentityByID")
+	}
+
+	var r0 *core.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Identity, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Identity); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIdentityByName provides a mock function with given fields: ctx, iType, namespace, name
+func (_m *Plugin) GetIdentityByName(ctx context.Context, iType fftypes.FFEnum, namespace string, name string) (*core.Identity, error) {
+	ret := _m.Called(ctx, iType, namespace, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdentityByName")
+	}
+
+	var r0 *core.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.FFEnum, string, string) (*core.Identity, error)); ok {
+		return rf(ctx, iType, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.FFEnum, string, string) *core.Identity); ok {
+		r0 = rf(ctx, iType, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, fftypes.FFEnum, string, string) error); ok {
+		r1 = rf(ctx, iType, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLeaderLock provides a mock function with given fields: ctx, name
+func (_m *Plugin) GetLeaderLock(ctx context.Context, name string) (*core.LeaderLock, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeaderLock")
 	}
 
-	var r0 *core.Identity
+	var r0 *core.LeaderLock
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Identity, error)); ok {
-		return rf(ctx, namespace, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.LeaderLock, error)); ok {
+		return rf(ctx, name)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Identity); ok {
-		r0 = rf(ctx, namespace, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.LeaderLock); ok {
+		r0 = rf(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Identity)
+			r0 = ret.Get(0).(*core.LeaderLock)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
-		r1 = rf(ctx, namespace, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1633,34 +2092,43 @@ func (_m *Plugin) GetIdentityByID(ctx context.Context, namespace string, id *fft
 	return r0, r1
 }
 
-// GetIdentityByName provides a mock function with given fields: ctx, iType, namespace, name
-func (_m *Plugin) GetIdentityByName(ctx context.Context, iType fftypes.FFEnum, namespace string, name string) (*core.Identity, error) {
-	ret := _m.Called(ctx, iType, namespace, name)
+// GetMessageAcks provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetMessageAcks(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.MessageAck, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetIdentityByName")
+		panic("no return value specified for GetMessageAcks")
 	}
 
-	var r0 *core.Identity
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, fftypes.FFEnum, string, string) (*core.Identity, error)); ok {
-		return rf(ctx, iType, namespace, name)
+	var r0 []*core.MessageAck
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.MessageAck, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, fftypes.FFEnum, string, string) *core.Identity); ok {
-		r0 = rf(ctx, iType, namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.MessageAck); ok {
+		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Identity)
+			r0 = ret.Get(0).([]*core.MessageAck)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, fftypes.FFEnum, string, string) error); ok {
-		r1 = rf(ctx, iType, namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetMessageByID provides a mock function with given fields: ctx, namespace, id
@@ -2068,6 +2536,45 @@ func (_m *Plugin) GetOperationByID(ctx context.Context, namespace string, id *ff
 	return r0, r1
 }
 
+// GetOperationNotifications provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetOperationNotifications(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.OperationNotification, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOperationNotifications")
+	}
+
+	var r0 []*core.OperationNotification
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.OperationNotification, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.OperationNotification); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.OperationNotification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetOperations provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Operation, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -2946,6 +3453,24 @@ func (_m *Plugin) InsertBlobs(ctx context.Context, blobs []*core.Blob) error {
 	return r0
 }
 
+// InsertBlockchainEventOutput provides a mock function with given fields: ctx, namespace, id, output
+func (_m *Plugin) InsertBlockchainEventOutput(ctx context.Context, namespace string, id *fftypes.UUID, output fftypes.JSONObject) error {
+	ret := _m.Called(ctx, namespace, id, output)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertBlockchainEventOutput")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, fftypes.JSONObject) error); ok {
+		r0 = rf(ctx, namespace, id, output)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertBlockchainEvents provides a mock function with given fields: ctx, messages, hooks
 func (_m *Plugin) InsertBlockchainEvents(ctx context.Context, messages []*core.BlockchainEvent, hooks ...database.PostCompletionHook) error {
 	_va := make([]interface{}, len(hooks))
@@ -3007,6 +3532,24 @@ func (_m *Plugin) InsertDataArray(ctx context.Context, data core.DataArray) erro
 	return r0
 }
 
+// InsertDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *Plugin) InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	ret := _m.Called(ctx, deadLetter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) error); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertEvent provides a mock function with given fields: ctx, data
 func (_m *Plugin) InsertEvent(ctx context.Context, data *core.Event) error {
 	ret := _m.Called(ctx, data)
@@ -3025,6 +3568,24 @@ func (_m *Plugin) InsertEvent(ctx context.Context, data *core.Event) error {
 	return r0
 }
 
+// InsertMessageAck provides a mock function with given fields: ctx, ack
+func (_m *Plugin) InsertMessageAck(ctx context.Context, ack *core.MessageAck) error {
+	ret := _m.Called(ctx, ack)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertMessageAck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageAck) error); ok {
+		r0 = rf(ctx, ack)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertMessages provides a mock function with given fields: ctx, messages, hooks
 func (_m *Plugin) InsertMessages(ctx context.Context, messages []*core.Message, hooks ...database.PostCompletionHook) error {
 	_va := make([]interface{}, len(hooks))
@@ -3111,6 +3672,24 @@ func (_m *Plugin) InsertOperation(ctx context.Context, operation *core.Operation
 	return r0
 }
 
+// InsertOperationNotification provides a mock function with given fields: ctx, notification
+func (_m *Plugin) InsertOperationNotification(ctx context.Context, notification *core.OperationNotification) error {
+	ret := _m.Called(ctx, notification)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOperationNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.OperationNotification) error); ok {
+		r0 = rf(ctx, notification)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertOperations provides a mock function with given fields: ctx, ops, hooks
 func (_m *Plugin) InsertOperations(ctx context.Context, ops []*core.Operation, hooks ...database.PostCompletionHook) error {
 	_va := make([]interface{}, len(hooks))
@@ -3370,6 +3949,24 @@ func (_m *Plugin) InsertTransactions(ctx context.Context, txns []*core.Transacti
 	return r0
 }
 
+// IterateContractListeners provides a mock function with given fields: ctx, namespace, filter, cb
+func (_m *Plugin) IterateContractListeners(ctx context.Context, namespace string, filter ffapi.Filter, cb func(*core.ContractListener) error) error {
+	ret := _m.Called(ctx, namespace, filter, cb)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IterateContractListeners")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, func(*core.ContractListener) error) error); ok {
+		r0 = rf(ctx, namespace, filter, cb)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Name provides a mock function with given fields:
 func (_m *Plugin) Name() string {
 	ret := _m.Called()
@@ -3388,6 +3985,42 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *Plugin) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseLeaderLock provides a mock function with given fields: ctx, name, holder
+func (_m *Plugin) ReleaseLeaderLock(ctx context.Context, name string, holder string) error {
+	ret := _m.Called(ctx, name, holder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseLeaderLock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, name, holder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReplaceMessage provides a mock function with given fields: ctx, message
 func (_m *Plugin) ReplaceMessage(ctx context.Context, message *core.Message) error {
 	ret := _m.Called(ctx, message)
@@ -3424,6 +4057,84 @@ func (_m *Plugin) RunAsGroup(ctx context.Context, fn func(context.Context) error
 	return r0
 }
 
+// SearchData provides a mock function with given fields: ctx, namespace, filter, query
+func (_m *Plugin) SearchData(ctx context.Context, namespace string, filter ffapi.Filter, query *database.DataValueQuery) (core.DataArray, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchData")
+	}
+
+	var r0 core.DataArray
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) (core.DataArray, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) core.DataArray); ok {
+		r0 = rf(ctx, namespace, filter, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(core.DataArray)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) error); ok {
+		r2 = rf(ctx, namespace, filter, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SearchMessages provides a mock function with given fields: ctx, namespace, filter, query
+func (_m *Plugin) SearchMessages(ctx context.Context, namespace string, filter ffapi.Filter, query *database.DataValueQuery) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchMessages")
+	}
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) []*core.Message); ok {
+		r0 = rf(ctx, namespace, filter, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter, *database.DataValueQuery) error); ok {
+		r2 = rf(ctx, namespace, filter, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // SetHandler provides a mock function with given fields: namespace, handler
 func (_m *Plugin) SetHandler(namespace string, handler database.Callbacks) {
 	_m.Called(namespace, handler)
@@ -3483,6 +4194,60 @@ func (_m *Plugin) UpdateData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// UpdateDatatypeLifecycle provides a mock function with given fields: ctx, namespace, id, lifecycle
+func (_m *Plugin) UpdateDatatypeLifecycle(ctx context.Context, namespace string, id *fftypes.UUID, lifecycle fftypes.FFEnum) error {
+	ret := _m.Called(ctx, namespace, id, lifecycle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDatatypeLifecycle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, fftypes.FFEnum) error); ok {
+		r0 = rf(ctx, namespace, id, lifecycle)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDeadLetter provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFFILifecycle provides a mock function with given fields: ctx, namespace, id, lifecycle
+func (_m *Plugin) UpdateFFILifecycle(ctx context.Context, namespace string, id *fftypes.UUID, lifecycle fftypes.FFEnum) error {
+	ret := _m.Called(ctx, namespace, id, lifecycle)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFFILifecycle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, fftypes.FFEnum) error); ok {
+		r0 = rf(ctx, namespace, id, lifecycle)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateMessage provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateMessage(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
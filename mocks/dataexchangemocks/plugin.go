@@ -7,8 +7,6 @@ import (
 
 	config "github.com/hyperledger/firefly-common/pkg/config"
 
-	core "github.com/hyperledger/firefly/pkg/core"
-
 	dataexchange "github.com/hyperledger/firefly/pkg/dataexchange"
 
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -198,6 +196,24 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *Plugin) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendMessage provides a mock function with given fields: ctx, nsOpID, peer, sender, data
 func (_m *Plugin) SendMessage(ctx context.Context, nsOpID string, peer fftypes.JSONObject, sender fftypes.JSONObject, data []byte) error {
 	ret := _m.Called(ctx, nsOpID, peer, sender, data)
@@ -222,7 +238,7 @@ func (_m *Plugin) SetHandler(networkNamespace string, nodeName string, handler d
 }
 
 // SetOperationHandler provides a mock function with given fields: namespace, handler
-func (_m *Plugin) SetOperationHandler(namespace string, handler core.OperationCallbacks) {
+func (_m *Plugin) SetOperationHandler(namespace string, handler dataexchange.OperationCallbacks) {
 	_m.Called(namespace, handler)
 }
 
@@ -22,6 +22,36 @@ type Manager struct {
 	mock.Mock
 }
 
+// AppendBlobUpload provides a mock function with given fields: ctx, uploadID, offset, chunk
+func (_m *Manager) AppendBlobUpload(ctx context.Context, uploadID string, offset int64, chunk io.Reader) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, uploadID, offset, chunk)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendBlobUpload")
+	}
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) (*core.BlobUpload, error)); ok {
+		return rf(ctx, uploadID, offset, chunk)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) *core.BlobUpload); ok {
+		r0 = rf(ctx, uploadID, offset, chunk)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, io.Reader) error); ok {
+		r1 = rf(ctx, uploadID, offset, chunk)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BlobsEnabled provides a mock function with given fields:
 func (_m *Manager) BlobsEnabled() bool {
 	ret := _m.Called()
@@ -58,6 +88,84 @@ func (_m *Manager) CheckDatatype(ctx context.Context, datatype *core.Datatype) e
 	return r0
 }
 
+// CompleteBlobUpload provides a mock function with given fields: ctx, uploadID
+func (_m *Manager) CompleteBlobUpload(ctx context.Context, uploadID string) (*core.Data, error) {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteBlobUpload")
+	}
+
+	var r0 *core.Data
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Data, error)); ok {
+		return rf(ctx, uploadID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Data); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Data)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uploadID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateBlobUpload provides a mock function with given fields: ctx, input
+func (_m *Manager) CreateBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBlobUpload")
+	}
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUploadInput) (*core.BlobUpload, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUploadInput) *core.BlobUpload); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.BlobUploadInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBlobUpload provides a mock function with given fields: ctx, uploadID
+func (_m *Manager) DeleteBlobUpload(ctx context.Context, uploadID string) error {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBlobUpload")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteData provides a mock function with given fields: ctx, dataID
 func (_m *Manager) DeleteData(ctx context.Context, dataID string) error {
 	ret := _m.Called(ctx, dataID)
@@ -115,6 +223,96 @@ func (_m *Manager) DownloadBlob(ctx context.Context, dataID string) (*core.Blob,
 	return r0, r1, r2
 }
 
+// GetBlobUpload provides a mock function with given fields: ctx, uploadID
+func (_m *Manager) GetBlobUpload(ctx context.Context, uploadID string) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, uploadID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlobUpload")
+	}
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.BlobUpload, error)); ok {
+		return rf(ctx, uploadID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.BlobUpload); ok {
+		r0 = rf(ctx, uploadID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uploadID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDatatypeDiff provides a mock function with given fields: ctx, name, fromVersion, toVersion
+func (_m *Manager) GetDatatypeDiff(ctx context.Context, name string, fromVersion string, toVersion string) (*core.DefinitionDiff, error) {
+	ret := _m.Called(ctx, name, fromVersion, toVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDatatypeDiff")
+	}
+
+	var r0 *core.DefinitionDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*core.DefinitionDiff, error)); ok {
+		return rf(ctx, name, fromVersion, toVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *core.DefinitionDiff); ok {
+		r0 = rf(ctx, name, fromVersion, toVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DefinitionDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, fromVersion, toVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIdempotentMessage provides a mock function with given fields: ctx, idempotencyKey
+func (_m *Manager) GetIdempotentMessage(ctx context.Context, idempotencyKey core.IdempotencyKey) (*core.Message, error) {
+	ret := _m.Called(ctx, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdempotentMessage")
+	}
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, core.IdempotencyKey) (*core.Message, error)); ok {
+		return rf(ctx, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, core.IdempotencyKey) *core.Message); ok {
+		r0 = rf(ctx, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, core.IdempotencyKey) error); ok {
+		r1 = rf(ctx, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMessageDataCached provides a mock function with given fields: ctx, msg, options
 func (_m *Manager) GetMessageDataCached(ctx context.Context, msg *core.Message, options ...data.CacheReadOption) (core.DataArray, bool, error) {
 	_va := make([]interface{}, len(options))
@@ -304,6 +502,36 @@ func (_m *Manager) Start() {
 	_m.Called()
 }
 
+// UpdateDatatypeLifecycle provides a mock function with given fields: ctx, name, version, update
+func (_m *Manager) UpdateDatatypeLifecycle(ctx context.Context, name string, version string, update *core.DefinitionLifecycleUpdate) (*core.Datatype, error) {
+	ret := _m.Called(ctx, name, version, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDatatypeLifecycle")
+	}
+
+	var r0 *core.Datatype
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.DefinitionLifecycleUpdate) (*core.Datatype, error)); ok {
+		return rf(ctx, name, version, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.DefinitionLifecycleUpdate) *core.Datatype); ok {
+		r0 = rf(ctx, name, version, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Datatype)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *core.DefinitionLifecycleUpdate) error); ok {
+		r1 = rf(ctx, name, version, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateMessageCache provides a mock function with given fields: msg, _a1
 func (_m *Manager) UpdateMessageCache(msg *core.Message, _a1 core.DataArray) {
 	_m.Called(msg, _a1)
@@ -349,6 +577,36 @@ func (_m *Manager) UploadBlob(ctx context.Context, inData *core.DataRefOrValue,
 	return r0, r1
 }
 
+// UploadBlobAsync provides a mock function with given fields: ctx, inData, blob, autoMeta
+func (_m *Manager) UploadBlobAsync(ctx context.Context, inData *core.DataRefOrValue, blob *ffapi.Multipart, autoMeta bool) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, inData, blob, autoMeta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadBlobAsync")
+	}
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DataRefOrValue, *ffapi.Multipart, bool) (*core.BlobUpload, error)); ok {
+		return rf(ctx, inData, blob, autoMeta)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DataRefOrValue, *ffapi.Multipart, bool) *core.BlobUpload); ok {
+		r0 = rf(ctx, inData, blob, autoMeta)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.DataRefOrValue, *ffapi.Multipart, bool) error); ok {
+		r1 = rf(ctx, inData, blob, autoMeta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UploadJSON provides a mock function with given fields: ctx, inData
 func (_m *Manager) UploadJSON(ctx context.Context, inData *core.DataRefOrValue) (*core.Data, error) {
 	ret := _m.Called(ctx, inData)
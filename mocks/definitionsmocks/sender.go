@@ -35,6 +35,24 @@ func (_m *Sender) ClaimIdentity(ctx context.Context, def *core.IdentityClaim, si
 	return r0
 }
 
+// ClaimIdentityVerifier provides a mock function with given fields: ctx, verifier, def, signingIdentity, waitConfirm
+func (_m *Sender) ClaimIdentityVerifier(ctx context.Context, verifier *core.Verifier, def *core.IdentityVerifierClaim, signingIdentity *core.SignerRef, waitConfirm bool) error {
+	ret := _m.Called(ctx, verifier, def, signingIdentity, waitConfirm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimIdentityVerifier")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Verifier, *core.IdentityVerifierClaim, *core.SignerRef, bool) error); ok {
+		r0 = rf(ctx, verifier, def, signingIdentity, waitConfirm)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DefineContractAPI provides a mock function with given fields: ctx, httpServerURL, api, waitConfirm
 func (_m *Sender) DefineContractAPI(ctx context.Context, httpServerURL string, api *core.ContractAPI, waitConfirm bool) error {
 	ret := _m.Called(ctx, httpServerURL, api, waitConfirm)
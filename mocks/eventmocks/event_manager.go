@@ -11,6 +11,8 @@ import (
 
 	dataexchange "github.com/hyperledger/firefly/pkg/dataexchange"
 
+	events "github.com/hyperledger/firefly/internal/events"
+
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 	mock "github.com/stretchr/testify/mock"
@@ -47,6 +49,26 @@ func (_m *EventManager) AddSystemEventListener(ns string, el system.EventListene
 	return r0
 }
 
+// AggregatorStatus provides a mock function with given fields:
+func (_m *EventManager) AggregatorStatus() *events.AggregatorStatus {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AggregatorStatus")
+	}
+
+	var r0 *events.AggregatorStatus
+	if rf, ok := ret.Get(0).(func() *events.AggregatorStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*events.AggregatorStatus)
+		}
+	}
+
+	return r0
+}
+
 // BlockchainEventBatch provides a mock function with given fields: batch
 func (_m *EventManager) BlockchainEventBatch(batch []*blockchain.EventToDispatch) error {
 	ret := _m.Called(batch)
@@ -139,6 +161,26 @@ func (_m *EventManager) DeletedSubscriptions() chan<- *fftypes.UUID {
 	return r0
 }
 
+// DeliveryErrors provides a mock function with given fields: since
+func (_m *EventManager) DeliveryErrors(since *fftypes.FFTime) *core.ErrorReportCategoryStatus {
+	ret := _m.Called(since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeliveryErrors")
+	}
+
+	var r0 *core.ErrorReportCategoryStatus
+	if rf, ok := ret.Get(0).(func(*fftypes.FFTime) *core.ErrorReportCategoryStatus); ok {
+		r0 = rf(since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ErrorReportCategoryStatus)
+		}
+	}
+
+	return r0
+}
+
 // EnrichEvent provides a mock function with given fields: ctx, event
 func (_m *EventManager) EnrichEvent(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error) {
 	ret := _m.Called(ctx, event)
@@ -314,6 +356,54 @@ func (_m *EventManager) QueueBatchRewind(batchID *fftypes.UUID) {
 	_m.Called(batchID)
 }
 
+// ReplayDeadLetter provides a mock function with given fields: ctx, subID, id
+func (_m *EventManager) ReplayDeadLetter(ctx context.Context, subID *fftypes.UUID, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, subID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, subID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaySubscription provides a mock function with given fields: ctx, subID, input
+func (_m *EventManager) ReplaySubscription(ctx context.Context, subID *fftypes.UUID, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error) {
+	ret := _m.Called(ctx, subID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaySubscription")
+	}
+
+	var r0 *core.SubscriptionReplay
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error)); ok {
+		return rf(ctx, subID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.SubscriptionReplayInput) *core.SubscriptionReplay); ok {
+		r0 = rf(ctx, subID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionReplay)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, *core.SubscriptionReplayInput) error); ok {
+		r1 = rf(ctx, subID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResolveTransportAndCapabilities provides a mock function with given fields: ctx, transportName
 func (_m *EventManager) ResolveTransportAndCapabilities(ctx context.Context, transportName string) (string, *pkgevents.Capabilities, error) {
 	ret := _m.Called(ctx, transportName)
@@ -351,6 +441,24 @@ func (_m *EventManager) ResolveTransportAndCapabilities(ctx context.Context, tra
 	return r0, r1, r2
 }
 
+// RunWithAggregatorPaused provides a mock function with given fields: ctx, fn
+func (_m *EventManager) RunWithAggregatorPaused(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunWithAggregatorPaused")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SharedStorageBatchDownloaded provides a mock function with given fields: ss, payloadRef, data
 func (_m *EventManager) SharedStorageBatchDownloaded(ss sharedstorage.Plugin, payloadRef string, data []byte) (*fftypes.UUID, error) {
 	ret := _m.Called(ss, payloadRef, data)
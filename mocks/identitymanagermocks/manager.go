@@ -241,6 +241,11 @@ func (_m *Manager) GetRootOrgDID(ctx context.Context) (string, error) {
 	return r0, r1
 }
 
+// InvalidateVerifierCache provides a mock function with given fields: ctx, verifierRef
+func (_m *Manager) InvalidateVerifierCache(ctx context.Context, verifierRef *core.VerifierRef) {
+	_m.Called(ctx, verifierRef)
+}
+
 // ResolveIdentitySigner provides a mock function with given fields: ctx, _a1
 func (_m *Manager) ResolveIdentitySigner(ctx context.Context, _a1 *core.Identity) (*core.SignerRef, error) {
 	ret := _m.Called(ctx, _a1)
@@ -16,6 +16,11 @@ type Manager struct {
 	mock.Mock
 }
 
+// APIRateLimited provides a mock function with given fields: namespace, route, limit
+func (_m *Manager) APIRateLimited(namespace string, route string, limit string) {
+	_m.Called(namespace, route, limit)
+}
+
 // AddTime provides a mock function with given fields: id
 func (_m *Manager) AddTime(id string) {
 	_m.Called(id)
@@ -51,6 +56,21 @@ func (_m *Manager) DeleteTime(id string) {
 	_m.Called(id)
 }
 
+// EventAcknowledged provides a mock function with given fields: namespace, subscription, transport, latency
+func (_m *Manager) EventAcknowledged(namespace string, subscription string, transport string, latency time.Duration) {
+	_m.Called(namespace, subscription, transport, latency)
+}
+
+// EventDispatched provides a mock function with given fields: namespace, subscription, transport
+func (_m *Manager) EventDispatched(namespace string, subscription string, transport string) {
+	_m.Called(namespace, subscription, transport)
+}
+
+// EventEnrichmentCache provides a mock function with given fields: hit
+func (_m *Manager) EventEnrichmentCache(hit bool) {
+	_m.Called(hit)
+}
+
 // GetTime provides a mock function with given fields: id
 func (_m *Manager) GetTime(id string) time.Time {
 	ret := _m.Called(id)
@@ -97,6 +117,16 @@ func (_m *Manager) MessageSubmitted(msg *core.Message) {
 	_m.Called(msg)
 }
 
+// SubscriptionLag provides a mock function with given fields: namespace, subscription, lag
+func (_m *Manager) SubscriptionLag(namespace string, subscription string, lag int64) {
+	_m.Called(namespace, subscription, lag)
+}
+
+// SubscriptionOffsetCommitFailure provides a mock function with given fields: namespace, subscription
+func (_m *Manager) SubscriptionOffsetCommitFailure(namespace string, subscription string) {
+	_m.Called(namespace, subscription)
+}
+
 // TransferConfirmed provides a mock function with given fields: transfer
 func (_m *Manager) TransferConfirmed(transfer *core.TokenTransfer) {
 	_m.Called(transfer)
@@ -107,6 +137,11 @@ func (_m *Manager) TransferSubmitted(transfer *core.TokenTransfer) {
 	_m.Called(transfer)
 }
 
+// WebhookResponse provides a mock function with given fields: namespace, subscription, status
+func (_m *Manager) WebhookResponse(namespace string, subscription string, status string) {
+	_m.Called(namespace, subscription, status)
+}
+
 // NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewManager(t interface {
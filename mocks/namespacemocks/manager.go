@@ -38,9 +38,39 @@ func (_m *Manager) Authorize(ctx context.Context, authReq *fftypes.AuthReq) erro
 	return r0
 }
 
-// GetNamespaces provides a mock function with given fields: ctx, includeInitializing
-func (_m *Manager) GetNamespaces(ctx context.Context, includeInitializing bool) ([]*core.NamespaceWithInitStatus, error) {
-	ret := _m.Called(ctx, includeInitializing)
+// GetHAStatus provides a mock function with given fields: ctx
+func (_m *Manager) GetHAStatus(ctx context.Context) (*core.HAStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHAStatus")
+	}
+
+	var r0 *core.HAStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.HAStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.HAStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.HAStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNamespaces provides a mock function with given fields: ctx, includeInitializing, includePlugins
+func (_m *Manager) GetNamespaces(ctx context.Context, includeInitializing bool, includePlugins bool) ([]*core.NamespaceWithInitStatus, error) {
+	ret := _m.Called(ctx, includeInitializing, includePlugins)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNamespaces")
@@ -48,19 +78,19 @@ func (_m *Manager) GetNamespaces(ctx context.Context, includeInitializing bool)
 
 	var r0 []*core.NamespaceWithInitStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]*core.NamespaceWithInitStatus, error)); ok {
-		return rf(ctx, includeInitializing)
+	if rf, ok := ret.Get(0).(func(context.Context, bool, bool) ([]*core.NamespaceWithInitStatus, error)); ok {
+		return rf(ctx, includeInitializing, includePlugins)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, bool) []*core.NamespaceWithInitStatus); ok {
-		r0 = rf(ctx, includeInitializing)
+	if rf, ok := ret.Get(0).(func(context.Context, bool, bool) []*core.NamespaceWithInitStatus); ok {
+		r0 = rf(ctx, includeInitializing, includePlugins)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*core.NamespaceWithInitStatus)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
-		r1 = rf(ctx, includeInitializing)
+	if rf, ok := ret.Get(1).(func(context.Context, bool, bool) error); ok {
+		r1 = rf(ctx, includeInitializing, includePlugins)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -116,6 +146,24 @@ func (_m *Manager) Init(ctx context.Context, cancelCtx context.CancelFunc, reset
 	return r0
 }
 
+// IsActive provides a mock function with given fields:
+func (_m *Manager) IsActive() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsActive")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // MustOrchestrator provides a mock function with given fields: ns
 func (_m *Manager) MustOrchestrator(ns string) orchestrator.Orchestrator {
 	ret := _m.Called(ns)
@@ -166,6 +214,24 @@ func (_m *Manager) Orchestrator(ctx context.Context, ns string, includeInitializ
 	return r0, r1
 }
 
+// ReloadConfig provides a mock function with given fields: ctx
+func (_m *Manager) ReloadConfig(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReloadConfig")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Reset provides a mock function with given fields: ctx
 func (_m *Manager) Reset(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -184,6 +250,24 @@ func (_m *Manager) Reset(ctx context.Context) error {
 	return r0
 }
 
+// ResetPlugin provides a mock function with given fields: ctx, name
+func (_m *Manager) ResetPlugin(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPlugin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResolveOperationByNamespacedID provides a mock function with given fields: ctx, nsOpID, op
 func (_m *Manager) ResolveOperationByNamespacedID(ctx context.Context, nsOpID string, op *core.OperationUpdateDTO) error {
 	ret := _m.Called(ctx, nsOpID, op)
@@ -18,6 +18,36 @@ type Manager struct {
 	mock.Mock
 }
 
+// ClaimIdentityVerifier provides a mock function with given fields: ctx, id, dto, waitConfirm
+func (_m *Manager) ClaimIdentityVerifier(ctx context.Context, id string, dto *core.IdentityVerifierClaimDTO, waitConfirm bool) (*core.Verifier, error) {
+	ret := _m.Called(ctx, id, dto, waitConfirm)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimIdentityVerifier")
+	}
+
+	var r0 *core.Verifier
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.IdentityVerifierClaimDTO, bool) (*core.Verifier, error)); ok {
+		return rf(ctx, id, dto, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.IdentityVerifierClaimDTO, bool) *core.Verifier); ok {
+		r0 = rf(ctx, id, dto, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Verifier)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.IdentityVerifierClaimDTO, bool) error); ok {
+		r1 = rf(ctx, id, dto, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDIDDocForIndentityByDID provides a mock function with given fields: ctx, did
 func (_m *Manager) GetDIDDocForIndentityByDID(ctx context.Context, did string) (*networkmap.DIDDocument, error) {
 	ret := _m.Called(ctx, did)
@@ -276,6 +306,36 @@ func (_m *Manager) GetIdentityByIDWithVerifiers(ctx context.Context, id string)
 	return r0, r1
 }
 
+// GetIdentityVerifierHistory provides a mock function with given fields: ctx, id
+func (_m *Manager) GetIdentityVerifierHistory(ctx context.Context, id string) ([]*networkmap.VerifierHistoryEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdentityVerifierHistory")
+	}
+
+	var r0 []*networkmap.VerifierHistoryEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*networkmap.VerifierHistoryEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*networkmap.VerifierHistoryEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*networkmap.VerifierHistoryEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetIdentityVerifiers provides a mock function with given fields: ctx, id, filter
 func (_m *Manager) GetIdentityVerifiers(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Verifier, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, id, filter)
@@ -681,6 +741,36 @@ func (_m *Manager) RegisterOrganization(ctx context.Context, org *core.IdentityC
 	return r0, r1
 }
 
+// ResolveDIDDocuments provides a mock function with given fields: ctx, ids
+func (_m *Manager) ResolveDIDDocuments(ctx context.Context, ids []string) (map[string]*networkmap.DIDDocumentResult, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveDIDDocuments")
+	}
+
+	var r0 map[string]*networkmap.DIDDocumentResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]*networkmap.DIDDocumentResult, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]*networkmap.DIDDocumentResult); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*networkmap.DIDDocumentResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateIdentity provides a mock function with given fields: ctx, id, dto, waitConfirm
 func (_m *Manager) UpdateIdentity(ctx context.Context, id string, dto *core.IdentityUpdateDTO, waitConfirm bool) (*core.Identity, error) {
 	ret := _m.Called(ctx, id, dto, waitConfirm)
@@ -711,6 +801,36 @@ func (_m *Manager) UpdateIdentity(ctx context.Context, id string, dto *core.Iden
 	return r0, r1
 }
 
+// VerifyDIDDocument provides a mock function with given fields: ctx, doc
+func (_m *Manager) VerifyDIDDocument(ctx context.Context, doc *networkmap.DIDDocument) (*networkmap.DIDDocumentVerification, error) {
+	ret := _m.Called(ctx, doc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyDIDDocument")
+	}
+
+	var r0 *networkmap.DIDDocumentVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *networkmap.DIDDocument) (*networkmap.DIDDocumentVerification, error)); ok {
+		return rf(ctx, doc)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *networkmap.DIDDocument) *networkmap.DIDDocumentVerification); ok {
+		r0 = rf(ctx, doc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*networkmap.DIDDocumentVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *networkmap.DIDDocument) error); ok {
+		r1 = rf(ctx, doc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewManager(t interface {
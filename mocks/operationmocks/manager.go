@@ -8,6 +8,8 @@ import (
 	core "github.com/hyperledger/firefly/pkg/core"
 	database "github.com/hyperledger/firefly/pkg/database"
 
+	ffapi "github.com/hyperledger/firefly-common/pkg/ffapi"
+
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 	mock "github.com/stretchr/testify/mock"
@@ -70,6 +72,26 @@ func (_m *Manager) BulkInsertOperations(ctx context.Context, ops ...*core.Operat
 	return r0
 }
 
+// CircuitBreakerStatus provides a mock function with given fields:
+func (_m *Manager) CircuitBreakerStatus() []*core.CircuitBreakerStatus {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CircuitBreakerStatus")
+	}
+
+	var r0 []*core.CircuitBreakerStatus
+	if rf, ok := ret.Get(0).(func() []*core.CircuitBreakerStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.CircuitBreakerStatus)
+		}
+	}
+
+	return r0
+}
+
 // GetOperationByIDCached provides a mock function with given fields: ctx, opID
 func (_m *Manager) GetOperationByIDCached(ctx context.Context, opID *fftypes.UUID) (*core.Operation, error) {
 	ret := _m.Called(ctx, opID)
@@ -100,6 +122,66 @@ func (_m *Manager) GetOperationByIDCached(ctx context.Context, opID *fftypes.UUI
 	return r0, r1
 }
 
+// GetOperationRetryChain provides a mock function with given fields: ctx, opID
+func (_m *Manager) GetOperationRetryChain(ctx context.Context, opID *fftypes.UUID) ([]*core.Operation, error) {
+	ret := _m.Called(ctx, opID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOperationRetryChain")
+	}
+
+	var r0 []*core.Operation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) ([]*core.Operation, error)); ok {
+		return rf(ctx, opID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) []*core.Operation); ok {
+		r0 = rf(ctx, opID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, opID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NotifyOperation provides a mock function with given fields: ctx, opID, input
+func (_m *Manager) NotifyOperation(ctx context.Context, opID *fftypes.UUID, input *core.OperationNotifyInput) (*core.Operation, error) {
+	ret := _m.Called(ctx, opID, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyOperation")
+	}
+
+	var r0 *core.Operation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.OperationNotifyInput) (*core.Operation, error)); ok {
+		return rf(ctx, opID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.OperationNotifyInput) *core.Operation); ok {
+		r0 = rf(ctx, opID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, *core.OperationNotifyInput) error); ok {
+		r1 = rf(ctx, opID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PrepareOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	ret := _m.Called(ctx, op)
@@ -220,6 +302,36 @@ func (_m *Manager) RetryOperation(ctx context.Context, opID *fftypes.UUID) (*cor
 	return r0, r1
 }
 
+// RetryOperations provides a mock function with given fields: ctx, opIDs, filter
+func (_m *Manager) RetryOperations(ctx context.Context, opIDs []*fftypes.UUID, filter ffapi.AndFilter) ([]*core.OperationRetryResult, error) {
+	ret := _m.Called(ctx, opIDs, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryOperations")
+	}
+
+	var r0 []*core.OperationRetryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*fftypes.UUID, ffapi.AndFilter) ([]*core.OperationRetryResult, error)); ok {
+		return rf(ctx, opIDs, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*fftypes.UUID, ffapi.AndFilter) []*core.OperationRetryResult); ok {
+		r0 = rf(ctx, opIDs, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.OperationRetryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*fftypes.UUID, ffapi.AndFilter) error); ok {
+		r1 = rf(ctx, opIDs, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunOperation provides a mock function with given fields: ctx, op, idempotentSubmit
 func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation, idempotentSubmit bool) (fftypes.JSONObject, error) {
 	ret := _m.Called(ctx, op, idempotentSubmit)
@@ -273,6 +385,24 @@ func (_m *Manager) SubmitOperationUpdate(update *core.OperationUpdate) {
 	_m.Called(update)
 }
 
+// UpdateTransferProgress provides a mock function with given fields: ctx, nsOpID, transferred, size
+func (_m *Manager) UpdateTransferProgress(ctx context.Context, nsOpID string, transferred int64, size int64) error {
+	ret := _m.Called(ctx, nsOpID, transferred, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransferProgress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) error); ok {
+		r0 = rf(ctx, nsOpID, transferred, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WaitStop provides a mock function with given fields:
 func (_m *Manager) WaitStop() {
 	_m.Called()
@@ -37,6 +37,10 @@ import (
 	operations "github.com/hyperledger/firefly/internal/operations"
 
 	privatemessaging "github.com/hyperledger/firefly/internal/privatemessaging"
+
+	retention "github.com/hyperledger/firefly/internal/retention"
+
+	time "time"
 )
 
 // Orchestrator is an autogenerated mock type for the Orchestrator type
@@ -122,6 +126,36 @@ func (_m *Orchestrator) Broadcast() broadcast.Manager {
 	return r0
 }
 
+// CancelScheduledMessage provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) CancelScheduledMessage(ctx context.Context, id string) (*core.Message, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledMessage")
+	}
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Message, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Message); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Contracts provides a mock function with given fields:
 func (_m *Orchestrator) Contracts() contracts.Manager {
 	ret := _m.Called()
@@ -142,6 +176,36 @@ func (_m *Orchestrator) Contracts() contracts.Manager {
 	return r0
 }
 
+// CreateBridge provides a mock function with given fields: ctx, bridge
+func (_m *Orchestrator) CreateBridge(ctx context.Context, bridge *core.Bridge) (*core.Bridge, error) {
+	ret := _m.Called(ctx, bridge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBridge")
+	}
+
+	var r0 *core.Bridge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Bridge) (*core.Bridge, error)); ok {
+		return rf(ctx, bridge)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Bridge) *core.Bridge); ok {
+		r0 = rf(ctx, bridge)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Bridge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Bridge) error); ok {
+		r1 = rf(ctx, bridge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateSubscription provides a mock function with given fields: ctx, subDef
 func (_m *Orchestrator) CreateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error) {
 	ret := _m.Called(ctx, subDef)
@@ -242,6 +306,24 @@ func (_m *Orchestrator) DefinitionSender() definitions.Sender {
 	return r0
 }
 
+// DeleteBridge provides a mock function with given fields: ctx, nameOrID
+func (_m *Orchestrator) DeleteBridge(ctx context.Context, nameOrID string) error {
+	ret := _m.Called(ctx, nameOrID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBridge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, nameOrID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSubscription provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) DeleteSubscription(ctx context.Context, id string) error {
 	ret := _m.Called(ctx, id)
@@ -280,6 +362,24 @@ func (_m *Orchestrator) Events() events.EventManager {
 	return r0
 }
 
+// ExportMessages provides a mock function with given fields: ctx, filter, fetchData, cb
+func (_m *Orchestrator) ExportMessages(ctx context.Context, filter ffapi.AndFilter, fetchData bool, cb func(*core.Message, core.DataArray) error) error {
+	ret := _m.Called(ctx, filter, fetchData, cb)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportMessages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, bool, func(*core.Message, core.DataArray) error) error); ok {
+		r0 = rf(ctx, filter, fetchData, cb)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetBatchByID(ctx context.Context, id string) (*core.BatchPersisted, error) {
 	ret := _m.Called(ctx, id)
@@ -379,6 +479,36 @@ func (_m *Orchestrator) GetBlockchainEventByID(ctx context.Context, id string) (
 	return r0, r1
 }
 
+// GetBlockchainEventOutput provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetBlockchainEventOutput(ctx context.Context, id string) (fftypes.JSONObject, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockchainEventOutput")
+	}
+
+	var r0 fftypes.JSONObject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (fftypes.JSONObject, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) fftypes.JSONObject); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(fftypes.JSONObject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBlockchainEvents provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetBlockchainEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -418,6 +548,75 @@ func (_m *Orchestrator) GetBlockchainEvents(ctx context.Context, filter ffapi.An
 	return r0, r1, r2
 }
 
+// GetBridgeByNameOrID provides a mock function with given fields: ctx, nameOrID
+func (_m *Orchestrator) GetBridgeByNameOrID(ctx context.Context, nameOrID string) (*core.Bridge, error) {
+	ret := _m.Called(ctx, nameOrID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBridgeByNameOrID")
+	}
+
+	var r0 *core.Bridge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Bridge, error)); ok {
+		return rf(ctx, nameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Bridge); ok {
+		r0 = rf(ctx, nameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Bridge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, nameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBridges provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetBridges(ctx context.Context, filter ffapi.AndFilter) ([]*core.Bridge, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBridges")
+	}
+
+	var r0 []*core.Bridge
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Bridge, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Bridge); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Bridge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetChartHistogram provides a mock function with given fields: ctx, startTime, endTime, buckets, tableName
 func (_m *Orchestrator) GetChartHistogram(ctx context.Context, startTime int64, endTime int64, buckets int64, tableName database.CollectionName) ([]*core.ChartHistogram, error) {
 	ret := _m.Called(ctx, startTime, endTime, buckets, tableName)
@@ -448,6 +647,36 @@ func (_m *Orchestrator) GetChartHistogram(ctx context.Context, startTime int64,
 	return r0, r1
 }
 
+// GetChartTimeSeries provides a mock function with given fields: ctx, collection, query
+func (_m *Orchestrator) GetChartTimeSeries(ctx context.Context, collection database.CollectionName, query *core.ChartTimeSeriesQuery) ([]*core.ChartTimeSeriesBucket, error) {
+	ret := _m.Called(ctx, collection, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetChartTimeSeries")
+	}
+
+	var r0 []*core.ChartTimeSeriesBucket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, database.CollectionName, *core.ChartTimeSeriesQuery) ([]*core.ChartTimeSeriesBucket, error)); ok {
+		return rf(ctx, collection, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, database.CollectionName, *core.ChartTimeSeriesQuery) []*core.ChartTimeSeriesBucket); ok {
+		r0 = rf(ctx, collection, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ChartTimeSeriesBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, database.CollectionName, *core.ChartTimeSeriesQuery) error); ok {
+		r1 = rf(ctx, collection, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetData provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetData(ctx context.Context, filter ffapi.AndFilter) (core.DataArray, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -646,6 +875,36 @@ func (_m *Orchestrator) GetDatatypes(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// GetErrorReport provides a mock function with given fields: ctx, window
+func (_m *Orchestrator) GetErrorReport(ctx context.Context, window time.Duration) (*core.ErrorReport, error) {
+	ret := _m.Called(ctx, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetErrorReport")
+	}
+
+	var r0 *core.ErrorReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (*core.ErrorReport, error)); ok {
+		return rf(ctx, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) *core.ErrorReport); ok {
+		r0 = rf(ctx, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ErrorReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEventByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetEventByID(ctx context.Context, id string) (*core.Event, error) {
 	ret := _m.Called(ctx, id)
@@ -784,6 +1043,45 @@ func (_m *Orchestrator) GetEventsWithReferences(ctx context.Context, filter ffap
 	return r0, r1, r2
 }
 
+// GetMessageAcks provides a mock function with given fields: ctx, id, filter
+func (_m *Orchestrator) GetMessageAcks(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, id, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageAcks")
+	}
+
+	var r0 []*core.MessageAck
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, id, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.MessageAck); ok {
+		r0 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.MessageAck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, id, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetMessageByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetMessageByID(ctx context.Context, id string) (*core.Message, error) {
 	ret := _m.Called(ctx, id)
@@ -913,6 +1211,36 @@ func (_m *Orchestrator) GetMessageEvents(ctx context.Context, id string, filter
 	return r0, r1, r2
 }
 
+// GetMessageProof provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetMessageProof(ctx context.Context, id string) (*core.MessageProof, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageProof")
+	}
+
+	var r0 *core.MessageProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.MessageProof, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.MessageProof); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMessageTransaction provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetMessageTransaction(ctx context.Context, id string) (*core.Transaction, error) {
 	ret := _m.Called(ctx, id)
@@ -1209,31 +1537,61 @@ func (_m *Orchestrator) GetOperationByIDWithStatus(ctx context.Context, id strin
 	return r0, r1
 }
 
-// GetOperations provides a mock function with given fields: ctx, filter
-func (_m *Orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, filter)
+// GetOperationProgress provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetOperationProgress(ctx context.Context, id string) (*core.OperationProgress, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetOperations")
+		panic("no return value specified for GetOperationProgress")
 	}
 
-	var r0 []*core.Operation
-	var r1 *ffapi.FilterResult
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, filter)
+	var r0 *core.OperationProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.OperationProgress, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Operation); ok {
-		r0 = rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.OperationProgress); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Operation)
+			r0 = ret.Get(0).(*core.OperationProgress)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, filter)
-	} else {
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOperations provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOperations")
+	}
+
+	var r0 []*core.Operation
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Operation); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*ffapi.FilterResult)
 		}
@@ -1287,6 +1645,85 @@ func (_m *Orchestrator) GetPins(ctx context.Context, filter ffapi.AndFilter) ([]
 	return r0, r1, r2
 }
 
+// GetPluginsStatus provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetPluginsStatus(ctx context.Context) *core.NamespaceStatusPlugins {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPluginsStatus")
+	}
+
+	var r0 *core.NamespaceStatusPlugins
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NamespaceStatusPlugins); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NamespaceStatusPlugins)
+		}
+	}
+
+	return r0
+}
+
+// GetReadiness provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetReadiness(ctx context.Context) *core.NamespaceReadiness {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadiness")
+	}
+
+	var r0 *core.NamespaceReadiness
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NamespaceReadiness); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NamespaceReadiness)
+		}
+	}
+
+	return r0
+}
+
+// GetScheduledMessages provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetScheduledMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduledMessages")
+	}
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Message); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetStatus provides a mock function with given fields: ctx
 func (_m *Orchestrator) GetStatus(ctx context.Context) (*core.NamespaceStatus, error) {
 	ret := _m.Called(ctx)
@@ -1377,6 +1814,45 @@ func (_m *Orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 	return r0, r1
 }
 
+// GetSubscriptionDeadLetters provides a mock function with given fields: ctx, id, filter
+func (_m *Orchestrator) GetSubscriptionDeadLetters(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, id, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptionDeadLetters")
+	}
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, id, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, id, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetSubscriptionEventsHistorical provides a mock function with given fields: ctx, subscription, filter, startSequence, endSequence
 func (_m *Orchestrator) GetSubscriptionEventsHistorical(ctx context.Context, subscription *core.Subscription, filter ffapi.AndFilter, startSequence int, endSequence int) ([]*core.EnrichedEvent, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, subscription, filter, startSequence, endSequence)
@@ -1735,6 +2211,36 @@ func (_m *Orchestrator) PreInit(ctx context.Context, cancelCtx context.CancelFun
 	_m.Called(ctx, cancelCtx)
 }
 
+// PreviewSubscription provides a mock function with given fields: ctx, input
+func (_m *Orchestrator) PreviewSubscription(ctx context.Context, input *core.SubscriptionPreviewInput) (*core.SubscriptionPreview, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewSubscription")
+	}
+
+	var r0 *core.SubscriptionPreview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionPreviewInput) (*core.SubscriptionPreview, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionPreviewInput) *core.SubscriptionPreview); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionPreview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.SubscriptionPreviewInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PrivateMessaging provides a mock function with given fields:
 func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	ret := _m.Called()
@@ -1755,6 +2261,84 @@ func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	return r0
 }
 
+// QueryGraphQL provides a mock function with given fields: ctx, req
+func (_m *Orchestrator) QueryGraphQL(ctx context.Context, req *core.GraphQLRequest) (*core.GraphQLResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryGraphQL")
+	}
+
+	var r0 *core.GraphQLResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.GraphQLRequest) (*core.GraphQLResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.GraphQLRequest) *core.GraphQLResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.GraphQLResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.GraphQLRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaySubscription provides a mock function with given fields: ctx, id, input
+func (_m *Orchestrator) ReplaySubscription(ctx context.Context, id string, input *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error) {
+	ret := _m.Called(ctx, id, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaySubscription")
+	}
+
+	var r0 *core.SubscriptionReplay
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionReplayInput) (*core.SubscriptionReplay, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionReplayInput) *core.SubscriptionReplay); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionReplay)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.SubscriptionReplayInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaySubscriptionDeadLetter provides a mock function with given fields: ctx, id, deadLetterID
+func (_m *Orchestrator) ReplaySubscriptionDeadLetter(ctx context.Context, id string, deadLetterID string) error {
+	ret := _m.Called(ctx, id, deadLetterID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaySubscriptionDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, deadLetterID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RequestReply provides a mock function with given fields: ctx, msg
 func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut) (*core.MessageInOut, error) {
 	ret := _m.Called(ctx, msg)
@@ -1785,6 +2369,56 @@ func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut
 	return r0, r1
 }
 
+// ResyncNetwork provides a mock function with given fields: ctx
+func (_m *Orchestrator) ResyncNetwork(ctx context.Context) (*core.NetworkResync, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResyncNetwork")
+	}
+
+	var r0 *core.NetworkResync
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.NetworkResync, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NetworkResync); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NetworkResync)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Retention provides a mock function with given fields:
+func (_m *Orchestrator) Retention() retention.Manager {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Retention")
+	}
+
+	var r0 retention.Manager
+	if rf, ok := ret.Get(0).(func() retention.Manager); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(retention.Manager)
+		}
+	}
+
+	return r0
+}
+
 // RewindPins provides a mock function with given fields: ctx, rewind
 func (_m *Orchestrator) RewindPins(ctx context.Context, rewind *core.PinRewind) (*core.PinRewind, error) {
 	ret := _m.Called(ctx, rewind)
@@ -1815,6 +2449,213 @@ func (_m *Orchestrator) RewindPins(ctx context.Context, rewind *core.PinRewind)
 	return r0, r1
 }
 
+// RewindPinsForBatches provides a mock function with given fields: ctx, rewind, dryRun
+func (_m *Orchestrator) RewindPinsForBatches(ctx context.Context, rewind *core.PinRewindBatches, dryRun bool) (*core.PinRewindResult, error) {
+	ret := _m.Called(ctx, rewind, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewindPinsForBatches")
+	}
+
+	var r0 *core.PinRewindResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.PinRewindBatches, bool) (*core.PinRewindResult, error)); ok {
+		return rf(ctx, rewind, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.PinRewindBatches, bool) *core.PinRewindResult); ok {
+		r0 = rf(ctx, rewind, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.PinRewindResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.PinRewindBatches, bool) error); ok {
+		r1 = rf(ctx, rewind, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewindPinsFromSequence provides a mock function with given fields: ctx, sequence, dryRun
+func (_m *Orchestrator) RewindPinsFromSequence(ctx context.Context, sequence int64, dryRun bool) (*core.PinRewindResult, error) {
+	ret := _m.Called(ctx, sequence, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RewindPinsFromSequence")
+	}
+
+	var r0 *core.PinRewindResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) (*core.PinRewindResult, error)); ok {
+		return rf(ctx, sequence, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) *core.PinRewindResult); ok {
+		r0 = rf(ctx, sequence, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.PinRewindResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, bool) error); ok {
+		r1 = rf(ctx, sequence, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunNetworkDiagnostics provides a mock function with given fields: ctx
+func (_m *Orchestrator) RunNetworkDiagnostics(ctx context.Context) (*core.NetworkDiagnostics, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunNetworkDiagnostics")
+	}
+
+	var r0 *core.NetworkDiagnostics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.NetworkDiagnostics, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NetworkDiagnostics); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NetworkDiagnostics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchData provides a mock function with given fields: ctx, filter, query
+func (_m *Orchestrator) SearchData(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) (core.DataArray, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchData")
+	}
+
+	var r0 core.DataArray
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) (core.DataArray, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) core.DataArray); ok {
+		r0 = rf(ctx, filter, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(core.DataArray)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) error); ok {
+		r2 = rf(ctx, filter, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SearchMessages provides a mock function with given fields: ctx, filter, query
+func (_m *Orchestrator) SearchMessages(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchMessages")
+	}
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) []*core.Message); ok {
+		r0 = rf(ctx, filter, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) error); ok {
+		r2 = rf(ctx, filter, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SearchMessagesWithData provides a mock function with given fields: ctx, filter, query
+func (_m *Orchestrator) SearchMessagesWithData(ctx context.Context, filter ffapi.AndFilter, query *database.DataValueQuery) ([]*core.MessageInOut, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchMessagesWithData")
+	}
+
+	var r0 []*core.MessageInOut
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) ([]*core.MessageInOut, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) []*core.MessageInOut); ok {
+		r0 = rf(ctx, filter, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.MessageInOut)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter, *database.DataValueQuery) error); ok {
+		r2 = rf(ctx, filter, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Start provides a mock function with given fields:
 func (_m *Orchestrator) Start() error {
 	ret := _m.Called()
@@ -20,6 +20,36 @@ type Manager struct {
 	mock.Mock
 }
 
+// CreateGroup provides a mock function with given fields: ctx, in
+func (_m *Manager) CreateGroup(ctx context.Context, in *core.GroupCreate) (*core.Group, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateGroup")
+	}
+
+	var r0 *core.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.GroupCreate) (*core.Group, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.GroupCreate) *core.Group); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.GroupCreate) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EnsureLocalGroup provides a mock function with given fields: ctx, group, creator
 func (_m *Manager) EnsureLocalGroup(ctx context.Context, group *core.Group, creator *core.Member) (bool, error) {
 	ret := _m.Called(ctx, group, creator)
@@ -185,6 +215,11 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// QueueMessageAcks provides a mock function with given fields: ctx, nodeID, ackType, msgIDs
+func (_m *Manager) QueueMessageAcks(ctx context.Context, nodeID *fftypes.UUID, ackType fftypes.FFEnum, msgIDs []*fftypes.UUID) {
+	_m.Called(ctx, nodeID, ackType, msgIDs)
+}
+
 // RequestReply provides a mock function with given fields: ctx, request
 func (_m *Manager) RequestReply(ctx context.Context, request *core.MessageInOut) (*core.MessageInOut, error) {
 	ret := _m.Called(ctx, request)
@@ -312,6 +347,96 @@ func (_m *Manager) SendMessage(ctx context.Context, in *core.MessageInOut, waitC
 	return r0, r1
 }
 
+// SendMessages provides a mock function with given fields: ctx, in
+func (_m *Manager) SendMessages(ctx context.Context, in []*core.MessageInOut) ([]*core.MessageSubmitResult, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMessages")
+	}
+
+	var r0 []*core.MessageSubmitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.MessageInOut) ([]*core.MessageSubmitResult, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.MessageInOut) []*core.MessageSubmitResult); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.MessageSubmitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*core.MessageInOut) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendPing provides a mock function with given fields: ctx, node
+func (_m *Manager) SendPing(ctx context.Context, node *core.Identity) (*core.Operation, error) {
+	ret := _m.Called(ctx, node)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendPing")
+	}
+
+	var r0 *core.Operation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Identity) (*core.Operation, error)); ok {
+		return rf(ctx, node)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Identity) *core.Operation); ok {
+		r0 = rf(ctx, node)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Identity) error); ok {
+		r1 = rf(ctx, node)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateGroupMembers provides a mock function with given fields: ctx, hash, in
+func (_m *Manager) UpdateGroupMembers(ctx context.Context, hash string, in *core.GroupMembershipUpdate) (*core.Group, error) {
+	ret := _m.Called(ctx, hash, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGroupMembers")
+	}
+
+	var r0 *core.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.GroupMembershipUpdate) (*core.Group, error)); ok {
+		return rf(ctx, hash, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.GroupMembershipUpdate) *core.Group); ok {
+		r0 = rf(ctx, hash, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.GroupMembershipUpdate) error); ok {
+		r1 = rf(ctx, hash, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewManager(t interface {
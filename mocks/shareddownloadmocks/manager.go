@@ -14,17 +14,17 @@ type Manager struct {
 	mock.Mock
 }
 
-// InitiateDownloadBatch provides a mock function with given fields: ctx, tx, payloadRef, idempotentSubmit
-func (_m *Manager) InitiateDownloadBatch(ctx context.Context, tx *fftypes.UUID, payloadRef string, idempotentSubmit bool) error {
-	ret := _m.Called(ctx, tx, payloadRef, idempotentSubmit)
+// InitiateDownloadBatch provides a mock function with given fields: ctx, tx, payloadRef, batchHash, idempotentSubmit
+func (_m *Manager) InitiateDownloadBatch(ctx context.Context, tx *fftypes.UUID, payloadRef string, batchHash *fftypes.Bytes32, idempotentSubmit bool) error {
+	ret := _m.Called(ctx, tx, payloadRef, batchHash, idempotentSubmit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for InitiateDownloadBatch")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, string, bool) error); ok {
-		r0 = rf(ctx, tx, payloadRef, idempotentSubmit)
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, string, *fftypes.Bytes32, bool) error); ok {
+		r0 = rf(ctx, tx, payloadRef, batchHash, idempotentSubmit)
 	} else {
 		r0 = ret.Error(0)
 	}
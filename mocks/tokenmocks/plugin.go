@@ -240,6 +240,24 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// Ping provides a mock function with given fields: ctx
+func (_m *Plugin) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetHandler provides a mock function with given fields: namespace, handler
 func (_m *Plugin) SetHandler(namespace string, handler tokens.Callbacks) {
 	_m.Called(namespace, handler)
@@ -250,6 +268,36 @@ func (_m *Plugin) SetOperationHandler(namespace string, handler core.OperationCa
 	_m.Called(namespace, handler)
 }
 
+// SimulateTransfer provides a mock function with given fields: ctx, poolLocator, transfer, methods
+func (_m *Plugin) SimulateTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) (*core.Simulation, error) {
+	ret := _m.Called(ctx, poolLocator, transfer, methods)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateTransfer")
+	}
+
+	var r0 *core.Simulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenTransfer, *fftypes.JSONAny) (*core.Simulation, error)); ok {
+		return rf(ctx, poolLocator, transfer, methods)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenTransfer, *fftypes.JSONAny) *core.Simulation); ok {
+		r0 = rf(ctx, poolLocator, transfer, methods)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Simulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenTransfer, *fftypes.JSONAny) error); ok {
+		r1 = rf(ctx, poolLocator, transfer, methods)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StartNamespace provides a mock function with given fields: ctx, namespace, tokenPools
 func (_m *Plugin) StartNamespace(ctx context.Context, namespace string, tokenPools []*core.TokenPool) error {
 	ret := _m.Called(ctx, namespace, tokenPools)
@@ -96,6 +96,36 @@ func (_m *Helper) GetBlockchainEventByIDCached(ctx context.Context, id *fftypes.
 	return r0, r1
 }
 
+// GetIdempotentTransaction provides a mock function with given fields: ctx, id
+func (_m *Helper) GetIdempotentTransaction(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdempotentTransaction")
+	}
+
+	var r0 *core.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) (*core.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) *core.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionByIDCached provides a mock function with given fields: ctx, id
 func (_m *Helper) GetTransactionByIDCached(ctx context.Context, id *fftypes.UUID) (*core.Transaction, error) {
 	ret := _m.Called(ctx, id)
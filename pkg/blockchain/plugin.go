@@ -62,6 +62,9 @@ type Plugin interface {
 	// Capabilities returns capabilities - not called until after Init
 	Capabilities() *Capabilities
 
+	// Ping checks the blockchain connector can be reached, with a lightweight call that has no side effects
+	Ping(ctx context.Context) error
+
 	// VerifierType returns the verifier (key) type that is used by this blockchain
 	VerifierType() core.VerifierType
 
@@ -96,9 +99,17 @@ type Plugin interface {
 	// QueryContract executes a method via custom on-chain logic and returns the result
 	QueryContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (interface{}, error)
 
+	// SimulateContract evaluates a method call against the current state of the chain, without submitting a transaction.
+	// A call that would be rejected is returned as an unsuccessful simulation, not as an error
+	SimulateContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (*core.Simulation, error)
+
 	// AddContractListener adds a new subscription to a user-specified contract and event
 	AddContractListener(ctx context.Context, subscription *core.ContractListener, lastProtocolID string) error
 
+	// UpdateContractListener applies changed options of a previously-created subscription in place. Returns a 501 error
+	// if the connector cannot update a subscription without recreating it
+	UpdateContractListener(ctx context.Context, subscription *core.ContractListener) error
+
 	// DeleteContractListener deletes a previously-created subscription
 	DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error
 
@@ -111,6 +122,14 @@ type Plugin interface {
 	// GenerateFFI returns an FFI from a blockchain specific interface format e.g. an Ethereum ABI
 	GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error)
 
+	// CompileContractInterface converts contract source (or compiler output) into the blockchain specific interface format
+	// accepted as input by GenerateFFI, using the blockchain connector to compile the source where required
+	CompileContractInterface(ctx context.Context, input *fftypes.JSONAny) (*fftypes.JSONAny, error)
+
+	// GenerateDeployDefinition converts an FFI, and the params of the constructor, into the blockchain specific
+	// definition passed to DeployContract
+	GenerateDeployDefinition(ctx context.Context, ffi *fftypes.FFI, constructorParams fftypes.FFIParams) (*fftypes.JSONAny, error)
+
 	// NormalizeContractLocation validates and normalizes the formatting of the location JSON
 	NormalizeContractLocation(ctx context.Context, ntype NormalizeType, location *fftypes.JSONAny) (*fftypes.JSONAny, error)
 
@@ -126,6 +145,9 @@ type Plugin interface {
 	// GenerateErrorSignature generates a strigified signature for the custom error, incorporating any fields significant to identifying the error as unique
 	GenerateErrorSignature(ctx context.Context, errorDef *fftypes.FFIErrorDefinition) string
 
+	// GenerateMethodSelector returns the signature of a method as used on-chain, and the selector (if any) that identifies calls to it in raw transactions
+	GenerateMethodSelector(ctx context.Context, method *fftypes.FFIMethod) (signature, selector string, err error)
+
 	// GetNetworkVersion queries the provided contract to get the network version
 	GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (int, error)
 
@@ -190,6 +212,12 @@ type EventToDispatch struct {
 	ForListener      *EventForListener
 }
 
+// ConnectorStatusReporter is optionally implemented by blockchain plugins that can fail over between multiple connector endpoints
+type ConnectorStatusReporter interface {
+	// ConnectorStatus returns the health of the connector endpoints, or nil if failover is not configured
+	ConnectorStatus() *core.ConnectorStatus
+}
+
 // Callbacks is the interface provided to the blockchain plugin, to allow it to pass events back to firefly.
 //
 // Events must be delivered sequentially, such that event 2 is not delivered until the callback invoked for event 1
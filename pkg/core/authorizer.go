@@ -1,4 +1,4 @@
-// Copyright © 2022 Kaleido, Inc.
+// Copyright © 2024 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -20,8 +20,106 @@ import (
 	"context"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 )
 
 type Authorizer interface {
 	Authorize(ctx context.Context, authReq *fftypes.AuthReq) error
 }
+
+// APIRole is a role granted to an API caller. Each role includes the permissions of the roles below it, so an
+// admin can do everything a submitter can, and a submitter can do everything a reader can.
+type APIRole string
+
+const (
+	// APIRoleReader can query the state of a namespace
+	APIRoleReader APIRole = "reader"
+	// APIRoleSubmitter can also submit messages, transactions and token operations
+	APIRoleSubmitter APIRole = "submitter"
+	// APIRoleAdmin can also define and deploy contracts and token pools, manage the network, and administer the node
+	APIRoleAdmin APIRole = "admin"
+)
+
+var apiRoleRanks = map[APIRole]int{
+	APIRoleReader:    1,
+	APIRoleSubmitter: 2,
+	APIRoleAdmin:     3,
+}
+
+func ParseAPIRole(ctx context.Context, role string) (APIRole, error) {
+	r := APIRole(role)
+	if _, ok := apiRoleRanks[r]; !ok {
+		return "", i18n.NewError(ctx, coremsgs.MsgUnknownAPIRole, role)
+	}
+	return r, nil
+}
+
+// Grants returns true if a caller holding this role can perform an action that requires the other role
+func (r APIRole) Grants(required APIRole) bool {
+	rank := apiRoleRanks[r]
+	return rank > 0 && rank >= apiRoleRanks[required]
+}
+
+// APIGrant is the role held by an authenticated caller, and the namespaces it applies to. A grant with no
+// namespaces applies to every namespace.
+type APIGrant struct {
+	Role       APIRole
+	Namespaces []string
+}
+
+// Authorize checks the grant covers the namespace of the request, and the role required by the context
+func (g *APIGrant) Authorize(ctx context.Context, authReq *fftypes.AuthReq) error {
+	if len(g.Namespaces) > 0 && authReq.Namespace != "" {
+		found := false
+		for _, ns := range g.Namespaces {
+			if ns == authReq.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return i18n.NewError(ctx, coremsgs.MsgAPINamespaceForbidden, authReq.Namespace)
+		}
+	}
+	required := RequiredAPIRole(ctx)
+	if !g.Role.Grants(required) {
+		return i18n.NewError(ctx, coremsgs.MsgAPIRoleForbidden, required)
+	}
+	return nil
+}
+
+type requiredAPIRoleKey struct{}
+
+// WithRequiredAPIRole returns a context declaring the role an authorizer must check the caller holds
+func WithRequiredAPIRole(ctx context.Context, role APIRole) context.Context {
+	return context.WithValue(ctx, requiredAPIRoleKey{}, role)
+}
+
+// RequiredAPIRole returns the role declared on the context, defaulting to admin if none was declared
+func RequiredAPIRole(ctx context.Context) APIRole {
+	if role, ok := ctx.Value(requiredAPIRoleKey{}).(APIRole); ok {
+		return role
+	}
+	return APIRoleAdmin
+}
+
+// AuthenticatedCaller holds the identity of a caller, recorded by the authorizer that authenticated it
+type AuthenticatedCaller struct {
+	Identity string
+}
+
+type authenticatedCallerKey struct{}
+
+// WithAuthenticatedCaller returns a context an authorizer can record the identity of the caller on
+func WithAuthenticatedCaller(ctx context.Context) (context.Context, *AuthenticatedCaller) {
+	caller := &AuthenticatedCaller{}
+	return context.WithValue(ctx, authenticatedCallerKey{}, caller), caller
+}
+
+// SetAuthenticatedIdentity records the identity of an authenticated caller, if the context tracks it
+func SetAuthenticatedIdentity(ctx context.Context, identity string) {
+	if caller, ok := ctx.Value(authenticatedCallerKey{}).(*AuthenticatedCaller); ok {
+		caller.Identity = identity
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRoleGrants(t *testing.T) {
+	assert.True(t, APIRoleAdmin.Grants(APIRoleReader))
+	assert.True(t, APIRoleAdmin.Grants(APIRoleAdmin))
+	assert.True(t, APIRoleSubmitter.Grants(APIRoleReader))
+	assert.False(t, APIRoleSubmitter.Grants(APIRoleAdmin))
+	assert.False(t, APIRoleReader.Grants(APIRoleSubmitter))
+	assert.False(t, APIRole("").Grants(APIRoleReader))
+}
+
+func TestParseAPIRole(t *testing.T) {
+	role, err := ParseAPIRole(context.Background(), "submitter")
+	assert.NoError(t, err)
+	assert.Equal(t, APIRoleSubmitter, role)
+
+	_, err = ParseAPIRole(context.Background(), "superuser")
+	assert.Regexp(t, "FF10566", err)
+}
+
+func TestAPIGrantAuthorize(t *testing.T) {
+	ctx := WithRequiredAPIRole(context.Background(), APIRoleSubmitter)
+	grant := &APIGrant{Role: APIRoleSubmitter, Namespaces: []string{"ns1", "ns2"}}
+
+	assert.NoError(t, grant.Authorize(ctx, &fftypes.AuthReq{Namespace: "ns2"}))
+	assert.Regexp(t, "FF10568", grant.Authorize(ctx, &fftypes.AuthReq{Namespace: "ns3"}))
+
+	grant.Role = APIRoleReader
+	assert.Regexp(t, "FF10567.*submitter", grant.Authorize(ctx, &fftypes.AuthReq{Namespace: "ns1"}))
+
+	grant = &APIGrant{Role: APIRoleAdmin}
+	assert.NoError(t, grant.Authorize(context.Background(), &fftypes.AuthReq{Namespace: "ns3"}))
+}
+
+func TestRequiredAPIRole(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, APIRoleAdmin, RequiredAPIRole(ctx))
+	assert.Equal(t, APIRoleReader, RequiredAPIRole(WithRequiredAPIRole(ctx, APIRoleReader)))
+}
+
+func TestAuthenticatedCaller(t *testing.T) {
+	SetAuthenticatedIdentity(context.Background(), "ignored")
+
+	ctx, caller := WithAuthenticatedCaller(context.Background())
+	assert.Empty(t, caller.Identity)
+	SetAuthenticatedIdentity(ctx, "apikey:app1")
+	assert.Equal(t, "apikey:app1", caller.Identity)
+}
@@ -68,6 +68,28 @@ type BatchManifest struct {
 	Data     DataRefs                `json:"data"`
 }
 
+// MessageProof allows a verifier to confirm a message was included in a batch, without the full batch.
+// The pinned hash of the batch is the SHA-256 hash of the manifest, and the manifest contains the hash
+// of every message in the batch - so the manifest is the proof of inclusion.
+//
+// The full chain can be verified without trusting the node that returned the proof:
+// - the SHA-256 hash of the data refs is the dataHash in the message header
+// - the SHA-256 hash of the message header is the message hash
+// - the message hash is in the manifest at the given index
+// - the SHA-256 hash of the manifest is the batch hash
+// - the batch hash is in the output of the pin event, which can be looked up on the blockchain
+type MessageProof struct {
+	Message   *MessageRef      `ffstruct:"MessageProof" json:"message"`
+	Header    *MessageHeader   `ffstruct:"MessageProof" json:"header,omitempty"`
+	Data      DataRefs         `ffstruct:"MessageProof" json:"data,omitempty"`
+	Batch     *fftypes.UUID    `ffstruct:"MessageProof" json:"batch"`
+	BatchHash *fftypes.Bytes32 `ffstruct:"MessageProof" json:"batchHash"`
+	Index     int              `ffstruct:"MessageProof" json:"index"`
+	Manifest  *fftypes.JSONAny `ffstruct:"MessageProof" json:"manifest"`
+	TX        TransactionRef   `ffstruct:"MessageProof" json:"tx"`
+	Pin       *BlockchainEvent `ffstruct:"MessageProof" json:"pin,omitempty"`
+}
+
 // Batch is the full payload object used in-flight.
 type Batch struct {
 	BatchHeader
@@ -0,0 +1,60 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// BlobUploadInput starts a resumable upload, for a blob too large to send in a single request.
+// The blob is sent in chunks, and the data item is only created once the upload is completed.
+type BlobUploadInput struct {
+	Validator ValidatorType    `ffstruct:"BlobUploadInput" json:"validator,omitempty"`
+	Datatype  *DatatypeRef     `ffstruct:"BlobUploadInput" json:"datatype,omitempty"`
+	Value     *fftypes.JSONAny `ffstruct:"BlobUploadInput" json:"value,omitempty"`
+	Filename  string           `ffstruct:"BlobUploadInput" json:"filename,omitempty"`
+	Mimetype  string           `ffstruct:"BlobUploadInput" json:"mimetype,omitempty"`
+	AutoMeta  bool             `ffstruct:"BlobUploadInput" json:"autometa,omitempty"`
+	Size      int64            `ffstruct:"BlobUploadInput" json:"size,omitempty"`
+}
+
+// BlobUploadStatus is the stage an upload has reached
+type BlobUploadStatus = fftypes.FFEnum
+
+var (
+	// BlobUploadStatusReceiving is a resumable upload that is still receiving chunks
+	BlobUploadStatusReceiving = fftypes.FFEnumValue("blobuploadstatus", "receiving")
+	// BlobUploadStatusPending is an async upload that has been received, and is queued for processing
+	BlobUploadStatusPending = fftypes.FFEnumValue("blobuploadstatus", "pending")
+	// BlobUploadStatusProcessing is an async upload that is being hashed and transferred to data exchange
+	BlobUploadStatusProcessing = fftypes.FFEnumValue("blobuploadstatus", "processing")
+	// BlobUploadStatusSucceeded is an async upload for which the data has been created
+	BlobUploadStatusSucceeded = fftypes.FFEnumValue("blobuploadstatus", "succeeded")
+	// BlobUploadStatusFailed is an async upload that could not be processed
+	BlobUploadStatusFailed = fftypes.FFEnumValue("blobuploadstatus", "failed")
+)
+
+// BlobUpload is a resumable upload that is in progress, or an async upload that is being processed
+type BlobUpload struct {
+	ID        *fftypes.UUID   `ffstruct:"BlobUpload" json:"id"`
+	Namespace string          `ffstruct:"BlobUpload" json:"namespace"`
+	Created   *fftypes.FFTime `ffstruct:"BlobUpload" json:"created"`
+	BlobUploadInput
+	Offset    int64            `ffstruct:"BlobUpload" json:"offset"`
+	Status    BlobUploadStatus `ffstruct:"BlobUpload" json:"status" ffenum:"blobuploadstatus"`
+	Processed int64            `ffstruct:"BlobUpload" json:"processed,omitempty"`
+	Data      *fftypes.UUID    `ffstruct:"BlobUpload" json:"data,omitempty"`
+	Error     string           `ffstruct:"BlobUpload" json:"error,omitempty"`
+}
@@ -0,0 +1,32 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// Bridge re-broadcasts the confirmed broadcast messages of a namespace that match a topic filter,
+// into another namespace on the same node. Each bridge is backed by a durable subscription with
+// the "bridge" transport, which preserves the order of the messages across restarts.
+type Bridge struct {
+	ID        *fftypes.UUID   `ffstruct:"Bridge" json:"id,omitempty" ffexcludeinput:"true"`
+	Namespace string          `ffstruct:"Bridge" json:"namespace,omitempty" ffexcludeinput:"true"`
+	Name      string          `ffstruct:"Bridge" json:"name"`
+	Target    string          `ffstruct:"Bridge" json:"target"`
+	Topic     string          `ffstruct:"Bridge" json:"topic,omitempty"`
+	Tag       string          `ffstruct:"Bridge" json:"tag,omitempty"`
+	Created   *fftypes.FFTime `ffstruct:"Bridge" json:"created,omitempty" ffexcludeinput:"true"`
+}
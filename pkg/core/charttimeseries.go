@@ -0,0 +1,59 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+const (
+	// ChartTimeSeriesMaxBuckets max buckets that can be returned in a time series
+	ChartTimeSeriesMaxBuckets = 1000
+)
+
+// ChartTimeSeriesQuery specifies the time range, the interval of each bucket, and optionally a field to group by
+type ChartTimeSeriesQuery struct {
+	StartTime *fftypes.FFTime
+	EndTime   *fftypes.FFTime
+	Interval  fftypes.FFDuration
+	GroupBy   string
+}
+
+// ChartTimeSeriesBucket is the count of records in one interval of a time series
+type ChartTimeSeriesBucket struct {
+	Timestamp *fftypes.FFTime         `ffstruct:"ChartTimeSeriesBucket" json:"timestamp"`
+	Count     int64                   `ffstruct:"ChartTimeSeriesBucket" json:"count"`
+	Groups    []*ChartTimeSeriesGroup `ffstruct:"ChartTimeSeriesBucket" json:"groups,omitempty"`
+}
+
+// ChartTimeSeriesGroup is the count of records in a bucket with one value of the group by field
+type ChartTimeSeriesGroup struct {
+	Group string `ffstruct:"ChartTimeSeriesGroup" json:"group"`
+	Count int64  `ffstruct:"ChartTimeSeriesGroup" json:"count"`
+}
+
+// ChartCount is a count of records starting at a timestamp, with one value of the group by field
+type ChartCount struct {
+	Timestamp *fftypes.FFTime `json:"timestamp"`
+	Group     string          `json:"group"`
+	Count     int64           `json:"count"`
+}
+
+// ChartRollup is a pre-aggregated count of the records in a collection
+type ChartRollup struct {
+	Collection string `json:"collection"`
+	GroupBy    string `json:"groupBy"`
+	ChartCount
+}
@@ -51,6 +51,8 @@ const (
 	SystemTagIdentityVerification = "ff_identity_verification"
 	// SystemTagIdentityUpdate is the tag for messages that broadcast an identity update
 	SystemTagIdentityUpdate = "ff_identity_update"
+	// SystemTagIdentityVerifierClaim is the tag for messages that broadcast a claim of an additional verifier for an existing identity
+	SystemTagIdentityVerifierClaim = "ff_identity_verifier_claim"
 	// SystemTagGapFill is the tag for messages that provide a nonce gap fill for a message that failed to send
 	SystemTagGapFill = "ff_gap_fill"
 )
@@ -58,4 +60,6 @@ const (
 const (
 	// DeployContract is the header for DeployContract request
 	DeployContract = "DeployContract"
+	// CompileContract is the header for CompileContract request
+	CompileContract = "CompileContract"
 )
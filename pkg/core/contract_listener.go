@@ -33,6 +33,7 @@ type ContractListener struct {
 	BackendID string                   `ffstruct:"ContractListener" json:"backendId,omitempty" ffexcludeinput:"true"`
 	Location  *fftypes.JSONAny         `ffstruct:"ContractListener" json:"location,omitempty"`
 	Created   *fftypes.FFTime          `ffstruct:"ContractListener" json:"created,omitempty" ffexcludeinput:"true"`
+	State     ContractListenerState    `ffstruct:"ContractListener" json:"state,omitempty" ffenum:"contractlistenerstate" ffexcludeinput:"true"`
 	Event     *FFISerializedEvent      `ffstruct:"ContractListener" json:"event,omitempty"`
 	Signature string                   `ffstruct:"ContractListener" json:"signature,omitempty" ffexcludeinput:"true"`
 	Topic     string                   `ffstruct:"ContractListener" json:"topic,omitempty"`
@@ -40,12 +41,62 @@ type ContractListener struct {
 	Filters   ListenerFilters          `ffstruct:"ContractListener" json:"filters,omitempty" ffexcludeinput:"postContractAPIListeners"`
 }
 
+// ContractListenerState is whether a listener is active, or has been deleted and is kept as a tombstone
+type ContractListenerState = fftypes.FFEnum
+
+var (
+	// ContractListenerStateActive the listener is registered with the blockchain connector
+	ContractListenerStateActive = fftypes.FFEnumValue("contractlistenerstate", "active")
+	// ContractListenerStateDeleted the listener has been deregistered from the blockchain connector, and is only kept for audit
+	ContractListenerStateDeleted = fftypes.FFEnumValue("contractlistenerstate", "deleted")
+)
+
 type ContractListenerWithStatus struct {
 	ContractListener
 	Status interface{} `ffstruct:"ContractListenerWithStatus" json:"status,omitempty" ffexcludeinput:"true"`
 }
+
+// IdleContractListener is a contract listener that has not delivered any blockchain events since it was created
+type IdleContractListener struct {
+	ContractListener
+	Age fftypes.FFDuration `ffstruct:"IdleContractListener" json:"age"`
+}
+
+// ContractAPIListenerHealth is a rollup of the health of all the listeners on the events of a contract API
+type ContractAPIListenerHealth struct {
+	Total           int             `ffstruct:"ContractAPIListenerHealth" json:"total"`
+	FiredRecently   int             `ffstruct:"ContractAPIListenerHealth" json:"firedRecently"`
+	NeverFired      int             `ffstruct:"ContractAPIListenerHealth" json:"neverFired"`
+	Syncing         int             `ffstruct:"ContractAPIListenerHealth" json:"syncing"`
+	Drifted         int             `ffstruct:"ContractAPIListenerHealth" json:"drifted"`
+	StatusErrors    int             `ffstruct:"ContractAPIListenerHealth" json:"statusErrors"`
+	OldestLastEvent *fftypes.FFTime `ffstruct:"ContractAPIListenerHealth" json:"oldestLastEvent,omitempty"`
+}
+
+// ContractListenerDeletion confirms a contract listener was deleted, and deregistered from the blockchain connector
+type ContractListenerDeletion struct {
+	ID           *fftypes.UUID `ffstruct:"ContractListenerDeletion" json:"id"`
+	Name         string        `ffstruct:"ContractListenerDeletion" json:"name,omitempty"`
+	BackendID    string        `ffstruct:"ContractListenerDeletion" json:"backendId"`
+	Deregistered bool          `ffstruct:"ContractListenerDeletion" json:"deregistered"`
+}
+
+// ContractListenerUpdate is a partial update of a contract listener, identified by its ID. Only the name and the options
+// can be changed. The other fields allow a listener to be sent back as it was read, but must not differ from it.
+type ContractListenerUpdate struct {
+	ID        *fftypes.UUID            `ffstruct:"ContractListenerUpdate" json:"id"`
+	Name      string                   `ffstruct:"ContractListenerUpdate" json:"name,omitempty"`
+	Options   *ContractListenerOptions `ffstruct:"ContractListenerUpdate" json:"options,omitempty"`
+	Topic     string                   `ffstruct:"ContractListenerUpdate" json:"topic,omitempty"`
+	Signature string                   `ffstruct:"ContractListenerUpdate" json:"signature,omitempty"`
+	Location  *fftypes.JSONAny         `ffstruct:"ContractListenerUpdate" json:"location,omitempty"`
+	Event     *FFISerializedEvent      `ffstruct:"ContractListenerUpdate" json:"event,omitempty"`
+}
+
 type ContractListenerOptions struct {
-	FirstEvent string `ffstruct:"ContractListenerOptions" json:"firstEvent,omitempty"`
+	FirstEvent    string                  `ffstruct:"ContractListenerOptions" json:"firstEvent,omitempty"`
+	FromBlock     string                  `ffstruct:"ContractListenerOptions" json:"fromBlock,omitempty"`
+	OutputFilters []*ListenerOutputFilter `ffstruct:"ContractListenerOptions" json:"outputFilters,omitempty"`
 }
 
 type ListenerStatusError struct {
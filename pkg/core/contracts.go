@@ -43,15 +43,29 @@ type ContractCallRequest struct {
 	Options        map[string]interface{} `ffstruct:"ContractCallRequest" json:"options"`
 	Message        *MessageInOut          `ffstruct:"ContractCallRequest" json:"message,omitempty" ffexcludeinput:"postContractQuery,postContractAPIQuery"`
 	IdempotencyKey IdempotencyKey         `ffstruct:"ContractCallRequest" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
+	Triggers       []*TriggerAction       `ffstruct:"ContractCallRequest" json:"triggers,omitempty" ffexcludeinput:"postContractQuery,postContractAPIQuery" ffexcludeoutput:"true"`
 }
 
 type ContractDeployRequest struct {
-	Key            string                 `ffstruct:"ContractDeployRequest" json:"key,omitempty"`
-	Input          []interface{}          `ffstruct:"ContractDeployRequest" json:"input"`
-	Definition     *fftypes.JSONAny       `ffstruct:"ContractDeployRequest" json:"definition"`
-	Contract       *fftypes.JSONAny       `ffstruct:"ContractDeployRequest" json:"contract"`
-	Options        map[string]interface{} `ffstruct:"ContractDeployRequest" json:"options"`
-	IdempotencyKey IdempotencyKey         `ffstruct:"ContractDeployRequest" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
+	Key               string                 `ffstruct:"ContractDeployRequest" json:"key,omitempty"`
+	Input             []interface{}          `ffstruct:"ContractDeployRequest" json:"input"`
+	Definition        *fftypes.JSONAny       `ffstruct:"ContractDeployRequest" json:"definition"`
+	Interface         *fftypes.FFIReference  `ffstruct:"ContractDeployRequest" json:"interface,omitempty"`
+	ConstructorParams fftypes.FFIParams      `ffstruct:"ContractDeployRequest" json:"constructorParams,omitempty"`
+	Contract          *fftypes.JSONAny       `ffstruct:"ContractDeployRequest" json:"contract"`
+	API               string                 `ffstruct:"ContractDeployRequest" json:"api,omitempty"`
+	Options           map[string]interface{} `ffstruct:"ContractDeployRequest" json:"options"`
+	IdempotencyKey    IdempotencyKey         `ffstruct:"ContractDeployRequest" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
+}
+
+// ContractMethodSelector is the blockchain specific identification of an FFI method, used to correlate
+// FireFly invocations with the raw transactions submitted to the chain
+type ContractMethodSelector struct {
+	Interface *fftypes.UUID `ffstruct:"ContractMethodSelector" json:"interface"`
+	Name      string        `ffstruct:"ContractMethodSelector" json:"name"`
+	Pathname  string        `ffstruct:"ContractMethodSelector" json:"pathname"`
+	Signature string        `ffstruct:"ContractMethodSelector" json:"signature"`
+	Selector  string        `ffstruct:"ContractMethodSelector" json:"selector,omitempty"`
 }
 
 type ContractURLs struct {
@@ -36,15 +36,16 @@ var (
 
 // Datatype is the structure defining a data definition, such as a JSON schema
 type Datatype struct {
-	ID        *fftypes.UUID    `ffstruct:"Datatype" json:"id,omitempty" ffexcludeinput:"true"`
-	Message   *fftypes.UUID    `ffstruct:"Datatype" json:"message,omitempty" ffexcludeinput:"true"`
-	Validator ValidatorType    `ffstruct:"Datatype" json:"validator" ffenum:"validatortype"`
-	Namespace string           `ffstruct:"Datatype" json:"namespace,omitempty" ffexcludeinput:"true"`
-	Name      string           `ffstruct:"Datatype" json:"name,omitempty"`
-	Version   string           `ffstruct:"Datatype" json:"version,omitempty"`
-	Hash      *fftypes.Bytes32 `ffstruct:"Datatype" json:"hash,omitempty" ffexcludeinput:"true"`
-	Created   *fftypes.FFTime  `ffstruct:"Datatype" json:"created,omitempty" ffexcludeinput:"true"`
-	Value     *fftypes.JSONAny `ffstruct:"Datatype" json:"value,omitempty"`
+	ID        *fftypes.UUID       `ffstruct:"Datatype" json:"id,omitempty" ffexcludeinput:"true"`
+	Message   *fftypes.UUID       `ffstruct:"Datatype" json:"message,omitempty" ffexcludeinput:"true"`
+	Validator ValidatorType       `ffstruct:"Datatype" json:"validator" ffenum:"validatortype"`
+	Namespace string              `ffstruct:"Datatype" json:"namespace,omitempty" ffexcludeinput:"true"`
+	Name      string              `ffstruct:"Datatype" json:"name,omitempty"`
+	Version   string              `ffstruct:"Datatype" json:"version,omitempty"`
+	Hash      *fftypes.Bytes32    `ffstruct:"Datatype" json:"hash,omitempty" ffexcludeinput:"true"`
+	Created   *fftypes.FFTime     `ffstruct:"Datatype" json:"created,omitempty" ffexcludeinput:"true"`
+	Value     *fftypes.JSONAny    `ffstruct:"Datatype" json:"value,omitempty"`
+	Lifecycle DefinitionLifecycle `ffstruct:"Datatype" json:"lifecycle,omitempty" ffenum:"definitionlifecycle" ffexcludeinput:"true"`
 }
 
 func (dt *Datatype) Validate(ctx context.Context, existing bool) (err error) {
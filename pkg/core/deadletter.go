@@ -0,0 +1,32 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// DeadLetter records an event that could not be delivered to a subscription, after all
+// retries were exhausted. It is parked so it can be inspected and replayed later.
+type DeadLetter struct {
+	ID           *fftypes.UUID   `ffstruct:"DeadLetter" json:"id"`
+	Namespace    string          `ffstruct:"DeadLetter" json:"namespace"`
+	Subscription *fftypes.UUID   `ffstruct:"DeadLetter" json:"subscription"`
+	Event        *fftypes.UUID   `ffstruct:"DeadLetter" json:"event"`
+	EventType    EventType       `ffstruct:"DeadLetter" json:"eventType"`
+	Error        string          `ffstruct:"DeadLetter" json:"error,omitempty"`
+	Created      *fftypes.FFTime `ffstruct:"DeadLetter" json:"created"`
+	Updated      *fftypes.FFTime `ffstruct:"DeadLetter" json:"updated,omitempty"`
+}
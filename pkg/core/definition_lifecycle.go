@@ -0,0 +1,191 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// DefinitionLifecycle is the local governance state of a version of a datatype or contract interface
+type DefinitionLifecycle = fftypes.FFEnum
+
+var (
+	// DefinitionLifecycleActive is a version that can be used without restriction
+	DefinitionLifecycleActive = fftypes.FFEnumValue("definitionlifecycle", "active")
+	// DefinitionLifecycleDeprecated is a version that should no longer be used - new usage is warned or rejected, depending on config
+	DefinitionLifecycleDeprecated = fftypes.FFEnumValue("definitionlifecycle", "deprecated")
+	// DefinitionLifecycleRetired is a version that can no longer be used for new data or transactions
+	DefinitionLifecycleRetired = fftypes.FFEnumValue("definitionlifecycle", "retired")
+)
+
+// DefinitionLifecycleUpdate is the input to change the lifecycle state of a definition version
+type DefinitionLifecycleUpdate struct {
+	Lifecycle DefinitionLifecycle `ffstruct:"DefinitionLifecycleUpdate" json:"lifecycle" ffenum:"definitionlifecycle"`
+}
+
+// ContractInterfaceLifecycle is the lifecycle state of a version of a contract interface
+type ContractInterfaceLifecycle struct {
+	Interface *fftypes.FFIReference `ffstruct:"ContractInterfaceLifecycle" json:"interface"`
+	Lifecycle DefinitionLifecycle   `ffstruct:"ContractInterfaceLifecycle" json:"lifecycle" ffenum:"definitionlifecycle"`
+}
+
+// CheckDefinitionLifecycle verifies a definition can be used for new data or transactions. Retired definitions are
+// always rejected, and deprecated definitions are rejected if rejectDeprecated is set, or otherwise logged as a warning.
+func CheckDefinitionLifecycle(ctx context.Context, kind, ref string, lifecycle DefinitionLifecycle, rejectDeprecated bool) error {
+	switch lifecycle {
+	case DefinitionLifecycleRetired:
+		return i18n.NewError(ctx, coremsgs.MsgDefinitionRetired, kind, ref)
+	case DefinitionLifecycleDeprecated:
+		if rejectDeprecated {
+			return i18n.NewError(ctx, coremsgs.MsgDefinitionDeprecated, kind, ref)
+		}
+		log.L(ctx).Warnf("The %s '%s' is deprecated", kind, ref)
+	}
+	return nil
+}
+
+// DefinitionChangeType is the type of a single difference between two versions of a definition
+type DefinitionChangeType = fftypes.FFEnum
+
+var (
+	// DefinitionChangeTypeAdded is a value that only exists in the newer version
+	DefinitionChangeTypeAdded = fftypes.FFEnumValue("definitionchangetype", "added")
+	// DefinitionChangeTypeRemoved is a value that only exists in the older version
+	DefinitionChangeTypeRemoved = fftypes.FFEnumValue("definitionchangetype", "removed")
+	// DefinitionChangeTypeChanged is a value that differs between the two versions
+	DefinitionChangeTypeChanged = fftypes.FFEnumValue("definitionchangetype", "changed")
+)
+
+// DefinitionDiff lists the differences between two versions of a datatype or contract interface
+type DefinitionDiff struct {
+	Name    string              `ffstruct:"DefinitionDiff" json:"name"`
+	From    string              `ffstruct:"DefinitionDiff" json:"from"`
+	To      string              `ffstruct:"DefinitionDiff" json:"to"`
+	Changes []*DefinitionChange `ffstruct:"DefinitionDiff" json:"changes"`
+}
+
+// DefinitionChange is a single difference between two versions of a definition, identified by a JSON pointer
+type DefinitionChange struct {
+	Path string               `ffstruct:"DefinitionChange" json:"path"`
+	Type DefinitionChangeType `ffstruct:"DefinitionChange" json:"type" ffenum:"definitionchangetype"`
+	From *fftypes.JSONAny     `ffstruct:"DefinitionChange" json:"from,omitempty"`
+	To   *fftypes.JSONAny     `ffstruct:"DefinitionChange" json:"to,omitempty"`
+}
+
+// NewDefinitionDiff compares the JSON of two versions of a definition. Objects are compared key by key,
+// and arrays item by item, with each change reported at the JSON pointer of the deepest differing value.
+func NewDefinitionDiff(name, fromVersion, toVersion string, from, to interface{}) *DefinitionDiff {
+	diff := &DefinitionDiff{
+		Name:    name,
+		From:    fromVersion,
+		To:      toVersion,
+		Changes: []*DefinitionChange{},
+	}
+	diff.compare("", normalizeJSON(from), normalizeJSON(to))
+	return diff
+}
+
+// normalizeJSON converts a value to the generic maps, slices and primitives of unmarshalled JSON
+func normalizeJSON(v interface{}) interface{} {
+	var generic interface{}
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &generic)
+	return generic
+}
+
+func jsonAnyOf(v interface{}) *fftypes.JSONAny {
+	b, _ := json.Marshal(v)
+	return fftypes.JSONAnyPtrBytes(b)
+}
+
+func jsonPointerToken(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func (diff *DefinitionDiff) compare(path string, from, to interface{}) {
+	switch fromTyped := from.(type) {
+	case map[string]interface{}:
+		if toTyped, ok := to.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(fromTyped)+len(toTyped))
+			for k := range fromTyped {
+				keys = append(keys, k)
+			}
+			for k := range toTyped {
+				if _, ok := fromTyped[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				diff.compareEntry(path+"/"+jsonPointerToken(k), fromTyped, toTyped, k)
+			}
+			return
+		}
+	case []interface{}:
+		if toTyped, ok := to.([]interface{}); ok {
+			for i := 0; i < len(fromTyped) || i < len(toTyped); i++ {
+				itemPath := path + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(toTyped):
+					diff.add(itemPath, DefinitionChangeTypeRemoved, fromTyped[i], nil)
+				case i >= len(fromTyped):
+					diff.add(itemPath, DefinitionChangeTypeAdded, nil, toTyped[i])
+				default:
+					diff.compare(itemPath, fromTyped[i], toTyped[i])
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(from, to) {
+		diff.add(path, DefinitionChangeTypeChanged, from, to)
+	}
+}
+
+func (diff *DefinitionDiff) compareEntry(path string, from, to map[string]interface{}, key string) {
+	fromValue, inFrom := from[key]
+	toValue, inTo := to[key]
+	switch {
+	case !inTo:
+		diff.add(path, DefinitionChangeTypeRemoved, fromValue, nil)
+	case !inFrom:
+		diff.add(path, DefinitionChangeTypeAdded, nil, toValue)
+	default:
+		diff.compare(path, fromValue, toValue)
+	}
+}
+
+func (diff *DefinitionDiff) add(path string, changeType DefinitionChangeType, from, to interface{}) {
+	change := &DefinitionChange{Path: path, Type: changeType}
+	if changeType != DefinitionChangeTypeAdded {
+		change.From = jsonAnyOf(from)
+	}
+	if changeType != DefinitionChangeTypeRemoved {
+		change.To = jsonAnyOf(to)
+	}
+	diff.Changes = append(diff.Changes, change)
+}
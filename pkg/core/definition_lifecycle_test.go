@@ -0,0 +1,100 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDefinitionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, CheckDefinitionLifecycle(ctx, "datatype", "customer/1", DefinitionLifecycleActive, true))
+	assert.NoError(t, CheckDefinitionLifecycle(ctx, "datatype", "customer/1", "", true))
+	assert.NoError(t, CheckDefinitionLifecycle(ctx, "datatype", "customer/1", DefinitionLifecycleDeprecated, false))
+	assert.Regexp(t, "FF10599.*customer/1", CheckDefinitionLifecycle(ctx, "datatype", "customer/1", DefinitionLifecycleDeprecated, true))
+	assert.Regexp(t, "FF10598.*customer/1", CheckDefinitionLifecycle(ctx, "datatype", "customer/1", DefinitionLifecycleRetired, false))
+}
+
+func TestNewDefinitionDiff(t *testing.T) {
+	from := fftypes.JSONAnyPtr(`{
+		"type": "object",
+		"properties": {
+			"a/b": {"type": "string"},
+			"c~d": {"type": "string"},
+			"removed": {"type": "integer"}
+		},
+		"required": ["a/b", "c~d"],
+		"enum": ["x"]
+	}`)
+	to := fftypes.JSONAnyPtr(`{
+		"type": "object",
+		"properties": {
+			"a/b": {"type": "integer"},
+			"c~d": {"type": "string"},
+			"added": {"type": "boolean"}
+		},
+		"required": ["a/b"],
+		"enum": {"changed": "kind"}
+	}`)
+
+	diff := NewDefinitionDiff("customer", "1", "2", from, to)
+	assert.Equal(t, "customer", diff.Name)
+	assert.Equal(t, "1", diff.From)
+	assert.Equal(t, "2", diff.To)
+	assert.Len(t, diff.Changes, 5)
+
+	assert.Equal(t, "/enum", diff.Changes[0].Path)
+	assert.Equal(t, DefinitionChangeTypeChanged, diff.Changes[0].Type)
+	assert.JSONEq(t, `["x"]`, diff.Changes[0].From.String())
+	assert.JSONEq(t, `{"changed":"kind"}`, diff.Changes[0].To.String())
+
+	assert.Equal(t, "/properties/a~1b/type", diff.Changes[1].Path)
+	assert.Equal(t, DefinitionChangeTypeChanged, diff.Changes[1].Type)
+	assert.Equal(t, `"string"`, diff.Changes[1].From.String())
+	assert.Equal(t, `"integer"`, diff.Changes[1].To.String())
+
+	assert.Equal(t, "/properties/added", diff.Changes[2].Path)
+	assert.Equal(t, DefinitionChangeTypeAdded, diff.Changes[2].Type)
+	assert.Nil(t, diff.Changes[2].From)
+	assert.JSONEq(t, `{"type":"boolean"}`, diff.Changes[2].To.String())
+
+	assert.Equal(t, "/properties/removed", diff.Changes[3].Path)
+	assert.Equal(t, DefinitionChangeTypeRemoved, diff.Changes[3].Type)
+	assert.JSONEq(t, `{"type":"integer"}`, diff.Changes[3].From.String())
+	assert.Nil(t, diff.Changes[3].To)
+
+	assert.Equal(t, "/required/1", diff.Changes[4].Path)
+	assert.Equal(t, DefinitionChangeTypeRemoved, diff.Changes[4].Type)
+	assert.Equal(t, `"c~d"`, diff.Changes[4].From.String())
+}
+
+func TestNewDefinitionDiffIdentical(t *testing.T) {
+	diff := NewDefinitionDiff("customer", "1", "2", map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1})
+	assert.Empty(t, diff.Changes)
+}
+
+func TestNewDefinitionDiffArrayItemAdded(t *testing.T) {
+	diff := NewDefinitionDiff("customer", "1", "2", []string{"a"}, []string{"a", "b"})
+	assert.Len(t, diff.Changes, 1)
+	assert.Equal(t, "/1", diff.Changes[0].Path)
+	assert.Equal(t, DefinitionChangeTypeAdded, diff.Changes[0].Type)
+	assert.Equal(t, `"b"`, diff.Changes[0].To.String())
+}
@@ -0,0 +1,106 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// ErrorReportCategory is the subsystem that a set of errors in an ErrorReport was collected from
+type ErrorReportCategory = fftypes.FFEnum
+
+var (
+	// ErrorReportCategoryOperations is for operations that failed within the window, grouped by operation type
+	ErrorReportCategoryOperations = fftypes.FFEnumValue("errorreportcategory", "operations")
+	// ErrorReportCategorySubscriptionDeliveries is for events rejected by subscription transports within the window, grouped by subscription
+	ErrorReportCategorySubscriptionDeliveries = fftypes.FFEnumValue("errorreportcategory", "subscription_deliveries")
+	// ErrorReportCategoryBlockchainIndexing is for contract listeners currently reporting an error from the blockchain connector
+	ErrorReportCategoryBlockchainIndexing = fftypes.FFEnumValue("errorreportcategory", "blockchain_indexing")
+)
+
+// ErrorReportMaxMessages is the number of distinct representative messages kept for each group in an error report
+const ErrorReportMaxMessages = 3
+
+// ErrorReport is a consolidated view of recent failures across the subsystems of a namespace
+type ErrorReport struct {
+	Since      *fftypes.FFTime              `ffstruct:"ErrorReport" json:"since"`
+	Total      int64                        `ffstruct:"ErrorReport" json:"total"`
+	Categories []*ErrorReportCategoryStatus `ffstruct:"ErrorReport" json:"categories"`
+}
+
+// ErrorReportCategoryStatus summarizes the errors collected from one subsystem
+type ErrorReportCategoryStatus struct {
+	Category  ErrorReportCategory `ffstruct:"ErrorReportCategoryStatus" json:"category" ffenum:"errorreportcategory"`
+	Count     int64               `ffstruct:"ErrorReportCategoryStatus" json:"count"`
+	Truncated bool                `ffstruct:"ErrorReportCategoryStatus" json:"truncated,omitempty"`
+	Groups    []*ErrorReportGroup `ffstruct:"ErrorReportCategoryStatus" json:"groups"`
+}
+
+// ErrorReportGroup is a set of errors in a category with a common source, such as an operation type or subscription
+type ErrorReportGroup struct {
+	Name     string          `ffstruct:"ErrorReportGroup" json:"name"`
+	Count    int64           `ffstruct:"ErrorReportGroup" json:"count"`
+	LastSeen *fftypes.FFTime `ffstruct:"ErrorReportGroup" json:"lastSeen,omitempty"`
+	Messages []string        `ffstruct:"ErrorReportGroup" json:"messages"`
+}
+
+func NewErrorReportCategoryStatus(category ErrorReportCategory) *ErrorReportCategoryStatus {
+	return &ErrorReportCategoryStatus{
+		Category: category,
+		Groups:   []*ErrorReportGroup{},
+	}
+}
+
+// AddError counts an error against the named group, keeping a small number of distinct messages as representative examples
+func (cs *ErrorReportCategoryStatus) AddError(name string, when *fftypes.FFTime, message string) {
+	var group *ErrorReportGroup
+	for _, g := range cs.Groups {
+		if g.Name == name {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		group = &ErrorReportGroup{Name: name, Messages: []string{}}
+		cs.Groups = append(cs.Groups, group)
+	}
+	cs.Count++
+	group.Count++
+	if group.LastSeen == nil || (when != nil && when.Time().After(*group.LastSeen.Time())) {
+		group.LastSeen = when
+	}
+	if len(group.Messages) < ErrorReportMaxMessages {
+		for _, m := range group.Messages {
+			if m == message {
+				return
+			}
+		}
+		group.Messages = append(group.Messages, message)
+	}
+}
+
+// SortGroups orders the groups with the most frequent errors first
+func (cs *ErrorReportCategoryStatus) SortGroups() {
+	sort.SliceStable(cs.Groups, func(i, j int) bool {
+		if cs.Groups[i].Count != cs.Groups[j].Count {
+			return cs.Groups[i].Count > cs.Groups[j].Count
+		}
+		return cs.Groups[i].Name < cs.Groups[j].Name
+	})
+}
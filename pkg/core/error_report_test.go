@@ -0,0 +1,49 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorReportCategoryStatus(t *testing.T) {
+	cs := NewErrorReportCategoryStatus(ErrorReportCategoryOperations)
+
+	t1 := fftypes.FFTime(time.Now().Add(-1 * time.Minute))
+	t2 := fftypes.Now()
+	cs.AddError("b", &t1, "err1")
+	cs.AddError("a", &t1, "err1")
+	cs.AddError("c", t2, "err1")
+	cs.AddError("c", &t1, "err1")
+	cs.AddError("c", nil, "err2")
+	cs.AddError("c", &t1, "err3")
+	cs.AddError("c", &t1, "err4")
+	cs.SortGroups()
+
+	assert.Equal(t, int64(7), cs.Count)
+	assert.Len(t, cs.Groups, 3)
+	assert.Equal(t, "c", cs.Groups[0].Name)
+	assert.Equal(t, int64(5), cs.Groups[0].Count)
+	assert.Equal(t, t2, cs.Groups[0].LastSeen)
+	assert.Equal(t, []string{"err1", "err2", "err3"}, cs.Groups[0].Messages)
+	assert.Equal(t, "a", cs.Groups[1].Name)
+	assert.Equal(t, "b", cs.Groups[2].Name)
+}
@@ -61,6 +61,14 @@ var (
 	EventTypeBlockchainContractDeployOpSucceeded = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_succeeded")
 	// EventTypeBlockchainContractDeployOpFailed occurs when a contract deployment request has failed
 	EventTypeBlockchainContractDeployOpFailed = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_failed")
+	// EventTypeSubscriptionOffsetCommitFailed occurs when the offset of a subscription could not be committed, after all retries
+	EventTypeSubscriptionOffsetCommitFailed = fftypes.FFEnumValue("eventtype", "subscription_offset_commit_failed")
+	// EventTypeSubscriptionReplay is delivered to the applications connected to a subscription when it is rewound, before the replayed events
+	EventTypeSubscriptionReplay = fftypes.FFEnumValue("eventtype", "subscription_replay")
+	// EventTypeDataUploadCompleted occurs when an async data upload has been processed, and the data is ready for use
+	EventTypeDataUploadCompleted = fftypes.FFEnumValue("eventtype", "data_upload_completed")
+	// EventTypeDataUploadFailed occurs when an async data upload could not be processed
+	EventTypeDataUploadFailed = fftypes.FFEnumValue("eventtype", "data_upload_failed")
 )
 
 // Event is an activity in the system, delivered reliably to applications, that indicates something has happened in the network
@@ -96,7 +104,16 @@ type EnrichedEvent struct {
 // be dispatched to an application.
 type EventDelivery struct {
 	EnrichedEvent
-	Subscription SubscriptionRef `json:"subscription"`
+	Subscription SubscriptionRef  `json:"subscription"`
+	Enrichment   *EventEnrichment `json:"enrichment,omitempty"`
+}
+
+// EventEnrichment is the result of the enrichers configured on a subscription, added to each event delivered to it
+type EventEnrichment struct {
+	Data      DataArray                   `json:"data,omitempty"`
+	AuthorDID *fftypes.JSONAny            `json:"authorDID,omitempty"`
+	Interface *fftypes.FFIReference       `json:"interface,omitempty"`
+	FFIEvent  *fftypes.FFIEventDefinition `json:"ffiEvent,omitempty"`
 }
 
 type CombinedEventDataDelivery struct {
@@ -109,6 +126,7 @@ type CombinedEventDataDelivery struct {
 type EventDeliveryResponse struct {
 	ID           *fftypes.UUID   `json:"id"`
 	Rejected     bool            `json:"rejected,omitempty"`
+	DeadLetter   bool            `json:"deadLetter,omitempty"`
 	Info         string          `json:"info,omitempty"`
 	Subscription SubscriptionRef `json:"subscription"`
 	Reply        *MessageInOut   `json:"reply,omitempty"`
@@ -0,0 +1,38 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// GraphQLRequest is a read-only GraphQL query over the resources in a namespace
+type GraphQLRequest struct {
+	Query         string             `ffstruct:"GraphQLRequest" json:"query"`
+	OperationName string             `ffstruct:"GraphQLRequest" json:"operationName,omitempty"`
+	Variables     fftypes.JSONObject `ffstruct:"GraphQLRequest" json:"variables,omitempty"`
+}
+
+// GraphQLResponse contains the data resolved for a query, and any errors resolving individual fields
+type GraphQLResponse struct {
+	Data   fftypes.JSONObject `ffstruct:"GraphQLResponse" json:"data"`
+	Errors []*GraphQLError    `ffstruct:"GraphQLResponse" json:"errors,omitempty"`
+}
+
+// GraphQLError is an error resolving a field, with the path to the field in the response data
+type GraphQLError struct {
+	Message string        `ffstruct:"GraphQLError" json:"message"`
+	Path    []interface{} `ffstruct:"GraphQLError" json:"path,omitempty"`
+}
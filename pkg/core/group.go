@@ -38,6 +38,7 @@ type Group struct {
 	LocalNamespace string           `ffstruct:"Group" json:"localNamespace,omitempty"`
 	Message        *fftypes.UUID    `ffstruct:"Group" json:"message,omitempty"`
 	Hash           *fftypes.Bytes32 `ffstruct:"Group" json:"hash,omitempty"`
+	Previous       *fftypes.Bytes32 `ffstruct:"Group" json:"previous,omitempty"`
 	Created        *fftypes.FFTime  `ffstruct:"Group" json:"created,omitempty"`
 }
 
@@ -68,6 +69,19 @@ type MemberInput struct {
 	Node     string `ffstruct:"MemberInput" json:"node,omitempty"`
 }
 
+// GroupCreate is the input to explicitly create a group, before any messages are sent to it
+type GroupCreate struct {
+	InputGroup
+	SignerRef
+}
+
+// GroupMembershipUpdate is the input to change the members of a group, which creates a new generation of the group
+type GroupMembershipUpdate struct {
+	Add    []MemberInput `ffstruct:"GroupMembershipUpdate" json:"add,omitempty"`
+	Remove []MemberInput `ffstruct:"GroupMembershipUpdate" json:"remove,omitempty"`
+	SignerRef
+}
+
 func (man *GroupIdentity) Hash() *fftypes.Bytes32 {
 	b, _ := json.Marshal(&man)
 	hash := fftypes.Bytes32(sha256.Sum256(b))
@@ -0,0 +1,46 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// HAMode is the mode of a node in an active/passive high availability deployment
+type HAMode = fftypes.FFEnum
+
+var (
+	// HAModeActive is the single node that runs the batch manager, aggregator and event delivery
+	HAModeActive = fftypes.FFEnumValue("hamode", "active")
+	// HAModePassive is a node that only serves read requests, until it takes over from the active node
+	HAModePassive = fftypes.FFEnumValue("hamode", "passive")
+)
+
+// LeaderLock is held by the active node in a high availability deployment, and expires unless it is renewed
+type LeaderLock struct {
+	Name    string          `json:"name"`
+	Holder  string          `json:"holder"`
+	Expires *fftypes.FFTime `json:"expires"`
+}
+
+// HAStatus is the high availability status of this node
+type HAStatus struct {
+	Enabled     bool            `ffstruct:"HAStatus" json:"enabled"`
+	NodeID      string          `ffstruct:"HAStatus" json:"nodeId,omitempty"`
+	Mode        HAMode          `ffstruct:"HAStatus" json:"mode" ffenum:"hamode"`
+	Leader      string          `ffstruct:"HAStatus" json:"leader,omitempty"`
+	LeaseExpiry *fftypes.FFTime `ffstruct:"HAStatus" json:"leaseExpiry,omitempty"`
+	ModeChanged *fftypes.FFTime `ffstruct:"HAStatus" json:"modeChanged,omitempty"`
+}
@@ -19,6 +19,7 @@ package core
 import (
 	"context"
 	"database/sql/driver"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 )
@@ -27,6 +28,15 @@ import (
 // to allow multiple entries in a unique index to exist with the same un-set idempotency key.
 type IdempotencyKey string
 
+// BridgedIdempotencyKeyPrefix is reserved for the idempotency keys of messages submitted by a bridge. Idempotency
+// keys are never transferred to other members, so only a bridge on this node can have submitted such a message.
+const BridgedIdempotencyKeyPrefix = "bridge:"
+
+// Bridged is true if the key is one reserved for a message submitted by a bridge
+func (ik IdempotencyKey) Bridged() bool {
+	return strings.HasPrefix((string)(ik), BridgedIdempotencyKeyPrefix)
+}
+
 func (ik IdempotencyKey) Value() (driver.Value, error) {
 	if ik == "" {
 		return nil, nil
@@ -52,3 +52,9 @@ func TestIdempotencyKeyNilScanner(t *testing.T) {
 	err = ik.Scan(12345)
 	assert.Regexp(t, "FF00105", err)
 }
+
+func TestIdempotencyKeyBridged(t *testing.T) {
+	assert.True(t, IdempotencyKey("bridge:sub1:msg1").Bridged())
+	assert.False(t, IdempotencyKey("sub1:msg1").Bridged())
+	assert.False(t, IdempotencyKey("").Bridged())
+}
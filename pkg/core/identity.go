@@ -20,10 +20,12 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 )
 
 // IdentityType is the type of an identity
@@ -100,6 +102,13 @@ type IdentityUpdateDTO struct {
 	IdentityProfile
 }
 
+// IdentityVerifierClaimDTO is the input structure to submit to claim an additional verifier (signing key) for an identity.
+// The claim is signed by an existing verifier of the identity.
+type IdentityVerifierClaimDTO struct {
+	Key       string `ffstruct:"IdentityVerifierClaimDTO" json:"key"`
+	Deprecate bool   `ffstruct:"IdentityVerifierClaimDTO" json:"deprecate,omitempty"`
+}
+
 // SignerRef is the nested structure representing the identity that signed a message.
 // It might comprise a resolvable by FireFly identity DID, a blockchain signing key, or both.
 type SignerRef struct {
@@ -131,6 +140,15 @@ type IdentityUpdate struct {
 	Updates  IdentityProfile `ffstruct:"IdentityUpdate" json:"updates,omitempty"`
 }
 
+// IdentityVerifierClaim is the data payload used in a message to broadcast a new verifier for an existing identity.
+// The broadcast must be signed by the identity itself, using one of its existing (non-deprecated) verifiers.
+// Any verifiers listed for deprecation can no longer be used to sign on behalf of the identity once the claim is confirmed.
+type IdentityVerifierClaim struct {
+	Identity  IdentityBase   `ffstruct:"IdentityVerifierClaim" json:"identity"`
+	Verifier  VerifierRef    `ffstruct:"IdentityVerifierClaim" json:"verifier"`
+	Deprecate []*VerifierRef `ffstruct:"IdentityVerifierClaim" json:"deprecate,omitempty"`
+}
+
 func (ic *IdentityClaim) Topic() string {
 	return ic.Identity.Topic()
 }
@@ -156,6 +174,14 @@ func (iu *IdentityUpdate) SetBroadcastMessage(msgID *fftypes.UUID) {
 	// nop-op here, as the IdentityUpdate doesn't have a reference to the original Identity to set this.
 }
 
+func (ivc *IdentityVerifierClaim) Topic() string {
+	return ivc.Identity.Topic()
+}
+
+func (ivc *IdentityVerifierClaim) SetBroadcastMessage(msgID *fftypes.UUID) {
+	// nop-op here, the definition handler sets the message ID on the new verifier.
+}
+
 func (i *IdentityBase) Topic() string {
 	h := sha256.New()
 	h.Write([]byte(i.DID))
@@ -204,6 +230,27 @@ func (i *IdentityBase) GenerateDID(ctx context.Context) (string, error) {
 	}
 }
 
+// ParseFireFlyDIDNamespace checks the DID uses the FireFly DID method, and returns the namespace
+// embedded in it. Only legacy custom identity DIDs of the form "did:firefly:ns/<namespace>/<name>"
+// include a namespace, so an empty string is returned for all other FireFly DIDs.
+func ParseFireFlyDIDNamespace(ctx context.Context, did string) (string, error) {
+	if !strings.HasPrefix(did, FireFlyDIDPrefix) {
+		return "", i18n.NewError(ctx, coremsgs.MsgDIDResolverUnknown, did)
+	}
+	didSuffix := strings.TrimPrefix(did, FireFlyDIDPrefix)
+	if !strings.HasPrefix(didSuffix, "ns/") {
+		return "", nil
+	}
+	nsSplit := strings.Split(didSuffix, "/")
+	if len(nsSplit) != 3 {
+		return "", i18n.NewError(ctx, coremsgs.MsgDIDResolverUnknown, did)
+	}
+	if err := fftypes.ValidateFFNameField(ctx, nsSplit[1], "namespace"); err != nil {
+		return "", err
+	}
+	return nsSplit[1], nil
+}
+
 func (i *IdentityBase) Equals(ctx context.Context, i2 *IdentityBase) bool {
 	if err := i.Validate(ctx); err != nil {
 		log.L(ctx).Warnf("Comparing invalid identity (source) %s (%v): %s", i.DID, i.ID, err)
@@ -149,6 +149,32 @@ func TestIdentityValidationCustom(t *testing.T) {
 
 }
 
+func TestParseFireFlyDIDNamespace(t *testing.T) {
+
+	ctx := context.Background()
+	ns, err := ParseFireFlyDIDNamespace(ctx, "did:firefly:org/org1")
+	assert.NoError(t, err)
+	assert.Empty(t, ns)
+
+	ns, err = ParseFireFlyDIDNamespace(ctx, "did:firefly:custom1")
+	assert.NoError(t, err)
+	assert.Empty(t, ns)
+
+	ns, err = ParseFireFlyDIDNamespace(ctx, "did:firefly:ns/ns1/custom1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", ns)
+
+	_, err = ParseFireFlyDIDNamespace(ctx, "did:web:example.com")
+	assert.Regexp(t, "FF10349", err)
+
+	_, err = ParseFireFlyDIDNamespace(ctx, "did:firefly:ns/ns1")
+	assert.Regexp(t, "FF10349", err)
+
+	_, err = ParseFireFlyDIDNamespace(ctx, "did:firefly:ns/!bad/custom1")
+	assert.Regexp(t, "FF00140", err)
+
+}
+
 func TestIdentityCompare(t *testing.T) {
 
 	ctx := context.Background()
@@ -225,4 +251,10 @@ func TestDefinitionObjects(t *testing.T) {
 	updateMsg := fftypes.NewUUID()
 	iu.SetBroadcastMessage(updateMsg)
 
+	var ivc Definition = &IdentityVerifierClaim{
+		Identity: o.IdentityBase,
+	}
+	assert.Equal(t, o.Topic(), ivc.Topic())
+	ivc.SetBroadcastMessage(fftypes.NewUUID())
+
 }
@@ -0,0 +1,165 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// ListenerOutputFilter is a condition on a field of the decoded output of an event. A contract listener only
+// records an event, and delivers it to subscriptions, if the event meets all of the output filters of the listener.
+type ListenerOutputFilter struct {
+	Field string                 `ffstruct:"ListenerOutputFilter" json:"field"`
+	Op    ListenerOutputFilterOp `ffstruct:"ListenerOutputFilter" json:"op" ffenum:"listeneroutputfilterop"`
+	Value string                 `ffstruct:"ListenerOutputFilter" json:"value"`
+}
+
+// ListenerOutputFilterOp is the comparison an output filter makes between the field and the value
+type ListenerOutputFilterOp = fftypes.FFEnum
+
+var (
+	// ListenerOutputFilterOpEq the field equals the value - numerically if both are numbers
+	ListenerOutputFilterOpEq = fftypes.FFEnumValue("listeneroutputfilterop", "eq")
+	// ListenerOutputFilterOpNeq the field does not equal the value - numerically if both are numbers
+	ListenerOutputFilterOpNeq = fftypes.FFEnumValue("listeneroutputfilterop", "neq")
+	// ListenerOutputFilterOpGt the field is a number greater than the value
+	ListenerOutputFilterOpGt = fftypes.FFEnumValue("listeneroutputfilterop", "gt")
+	// ListenerOutputFilterOpGte the field is a number greater than or equal to the value
+	ListenerOutputFilterOpGte = fftypes.FFEnumValue("listeneroutputfilterop", "gte")
+	// ListenerOutputFilterOpLt the field is a number less than the value
+	ListenerOutputFilterOpLt = fftypes.FFEnumValue("listeneroutputfilterop", "lt")
+	// ListenerOutputFilterOpLte the field is a number less than or equal to the value
+	ListenerOutputFilterOpLte = fftypes.FFEnumValue("listeneroutputfilterop", "lte")
+)
+
+// ValidateOutputFilters checks every output filter can be evaluated, so a mistake is reported when the
+// listener is created rather than silently discarding all of its events
+func (o *ContractListenerOptions) ValidateOutputFilters(ctx context.Context) error {
+	for i, f := range o.OutputFilters {
+		if f == nil || f.Field == "" {
+			return i18n.NewError(ctx, coremsgs.MsgListenerOutputFilterNoField, i)
+		}
+		switch f.Op {
+		case ListenerOutputFilterOpEq, ListenerOutputFilterOpNeq:
+		case ListenerOutputFilterOpGt, ListenerOutputFilterOpGte, ListenerOutputFilterOpLt, ListenerOutputFilterOpLte:
+			if parseOutputNumber(f.Value) == nil {
+				return i18n.NewError(ctx, coremsgs.MsgListenerOutputFilterNotNumeric, i, f.Op, f.Value)
+			}
+		default:
+			return i18n.NewError(ctx, coremsgs.MsgListenerOutputFilterBadOp, i, f.Op, fftypes.FFEnumValues("listeneroutputfilterop"))
+		}
+	}
+	return nil
+}
+
+// MatchesOutput returns true if the decoded output of an event meets all of the output filters
+func (o *ContractListenerOptions) MatchesOutput(output fftypes.JSONObject) bool {
+	if o == nil {
+		return true
+	}
+	for _, f := range o.OutputFilters {
+		if !f.matches(output) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *ListenerOutputFilter) matches(output fftypes.JSONObject) bool {
+	actual, ok := outputFieldString(output, f.Field)
+	if !ok {
+		// A field that is missing, or is not a scalar, cannot meet any condition
+		return false
+	}
+	actualNum := parseOutputNumber(actual)
+	valueNum := parseOutputNumber(f.Value)
+	switch f.Op {
+	case ListenerOutputFilterOpEq, ListenerOutputFilterOpNeq:
+		equal := actual == f.Value
+		if actualNum != nil && valueNum != nil {
+			equal = actualNum.Cmp(valueNum) == 0
+		}
+		return equal == (f.Op == ListenerOutputFilterOpEq)
+	}
+	if actualNum == nil || valueNum == nil {
+		return false
+	}
+	cmp := actualNum.Cmp(valueNum)
+	switch f.Op {
+	case ListenerOutputFilterOpGt:
+		return cmp > 0
+	case ListenerOutputFilterOpGte:
+		return cmp >= 0
+	case ListenerOutputFilterOpLt:
+		return cmp < 0
+	case ListenerOutputFilterOpLte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// outputFieldString finds a field in the event output by a dot separated path, where any array is indexed
+// by number, and returns it as a string
+func outputFieldString(output fftypes.JSONObject, path string) (string, bool) {
+	var current interface{} = map[string]interface{}(output)
+	for _, segment := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			current = v[segment]
+		case fftypes.JSONObject:
+			current = v[segment]
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", false
+			}
+			current = v[i]
+		default:
+			return "", false
+		}
+	}
+	switch v := current.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// parseOutputNumber parses a decimal or hex number, with enough precision for 256 bit integers, returning nil if it is not a number
+func parseOutputNumber(s string) *big.Float {
+	f, ok := new(big.Float).SetPrec(512).SetString(s)
+	if !ok {
+		return nil
+	}
+	return f
+}
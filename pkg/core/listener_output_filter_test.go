@@ -0,0 +1,95 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOutputFilters(t *testing.T) {
+	ctx := context.Background()
+
+	o := &ContractListenerOptions{
+		OutputFilters: []*ListenerOutputFilter{
+			{Field: "value", Op: ListenerOutputFilterOpGte, Value: "0x10"},
+			{Field: "from", Op: ListenerOutputFilterOpNeq, Value: "0x123"},
+		},
+	}
+	assert.NoError(t, o.ValidateOutputFilters(ctx))
+
+	o.OutputFilters = []*ListenerOutputFilter{nil}
+	assert.Regexp(t, "FF10546", o.ValidateOutputFilters(ctx))
+
+	o.OutputFilters = []*ListenerOutputFilter{{Field: "value", Op: "like", Value: "1"}}
+	assert.Regexp(t, "FF10547.*like", o.ValidateOutputFilters(ctx))
+
+	o.OutputFilters = []*ListenerOutputFilter{{Field: "value", Op: ListenerOutputFilterOpLt, Value: "abc"}}
+	assert.Regexp(t, "FF10548.*lt.*abc", o.ValidateOutputFilters(ctx))
+}
+
+func TestMatchesOutput(t *testing.T) {
+	var output fftypes.JSONObject
+	err := json.Unmarshal([]byte(`{
+		"value": "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		"amount": 1500,
+		"from": "0xAbC",
+		"flag": true,
+		"nested": {"items": [{"id": "a"}, {"id": "b"}]},
+		"object": {"key": "val"}
+	}`), &output)
+	assert.NoError(t, err)
+	output["number"] = json.Number("42")
+	output["typed"] = fftypes.JSONObject{"key": "val"}
+
+	for _, test := range []struct {
+		filter  ListenerOutputFilter
+		matches bool
+	}{
+		{ListenerOutputFilter{"value", ListenerOutputFilterOpGt, "1000"}, true},
+		{ListenerOutputFilter{"value", ListenerOutputFilterOpLte, "1000"}, false},
+		{ListenerOutputFilter{"amount", ListenerOutputFilterOpGte, "1500"}, true},
+		{ListenerOutputFilter{"amount", ListenerOutputFilterOpGt, "0x5dc"}, false},
+		{ListenerOutputFilter{"amount", ListenerOutputFilterOpLt, "1500.5"}, true},
+		{ListenerOutputFilter{"amount", ListenerOutputFilterOpEq, "1500.0"}, true},
+		{ListenerOutputFilter{"amount", ListenerOutputFilterOpNeq, "1500"}, false},
+		{ListenerOutputFilter{"number", ListenerOutputFilterOpEq, "42"}, true},
+		{ListenerOutputFilter{"from", ListenerOutputFilterOpEq, "0xAbC"}, true},
+		{ListenerOutputFilter{"from", ListenerOutputFilterOpNeq, "0xdef"}, true},
+		{ListenerOutputFilter{"from", ListenerOutputFilterOpGt, "1"}, true},
+		{ListenerOutputFilter{"flag", ListenerOutputFilterOpEq, "true"}, true},
+		{ListenerOutputFilter{"flag", ListenerOutputFilterOpGt, "1"}, false},
+		{ListenerOutputFilter{"nested.items.1.id", ListenerOutputFilterOpEq, "b"}, true},
+		{ListenerOutputFilter{"nested.items.2.id", ListenerOutputFilterOpEq, "b"}, false},
+		{ListenerOutputFilter{"nested.items.x", ListenerOutputFilterOpEq, "b"}, false},
+		{ListenerOutputFilter{"typed.key", ListenerOutputFilterOpEq, "val"}, true},
+		{ListenerOutputFilter{"object", ListenerOutputFilterOpNeq, "val"}, false},
+		{ListenerOutputFilter{"value.deeper", ListenerOutputFilterOpEq, "val"}, false},
+		{ListenerOutputFilter{"missing", ListenerOutputFilterOpNeq, "val"}, false},
+		{ListenerOutputFilter{"amount", "unknown", "1500"}, false},
+	} {
+		o := &ContractListenerOptions{OutputFilters: []*ListenerOutputFilter{&test.filter}}
+		assert.Equal(t, test.matches, o.MatchesOutput(output), "%s %s %s", test.filter.Field, test.filter.Op, test.filter.Value)
+	}
+
+	var nilOptions *ContractListenerOptions
+	assert.True(t, nilOptions.MatchesOutput(output))
+}
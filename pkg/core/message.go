@@ -58,6 +58,8 @@ type MessageState = fftypes.FFEnum
 var (
 	// MessageStateStaged is a message created locally which is not ready to send
 	MessageStateStaged = fftypes.FFEnumValue("messagestate", "staged")
+	// MessageStateScheduled is a message created locally which will be ready to send at its sendAfter time
+	MessageStateScheduled = fftypes.FFEnumValue("messagestate", "scheduled")
 	// MessageStateReady is a message created locally which is ready to send
 	MessageStateReady = fftypes.FFEnumValue("messagestate", "ready")
 	// MessageStateSent is a message created locally which has been sent in a batch
@@ -104,6 +106,7 @@ type Message struct {
 	Data           DataRefs              `ffstruct:"Message" json:"data" ffexcludeinput:"true"`
 	Pins           fftypes.FFStringArray `ffstruct:"Message" json:"pins,omitempty" ffexcludeinput:"true"`
 	IdempotencyKey IdempotencyKey        `ffstruct:"Message" json:"idempotencyKey,omitempty"`
+	SendAfter      *fftypes.FFTime       `ffstruct:"Message" json:"sendAfter,omitempty"`
 	Sequence       int64                 `ffstruct:"Message" json:"-"` // Local database sequence used internally for batch assembly
 }
 
@@ -128,8 +131,15 @@ func (m *Message) BatchMessage() *Message {
 // will be broken out and stored separately during the call.
 type MessageInOut struct {
 	Message
-	InlineData InlineData  `ffstruct:"MessageInOut" json:"data,omitempty"`
-	Group      *InputGroup `ffstruct:"MessageInOut" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
+	InlineData InlineData       `ffstruct:"MessageInOut" json:"data,omitempty"`
+	Group      *InputGroup      `ffstruct:"MessageInOut" json:"group,omitempty" ffexclude:"postNewMessageBroadcast,postNewMessageBroadcastBatch"`
+	Triggers   []*TriggerAction `ffstruct:"MessageInOut" json:"triggers,omitempty" ffexcludeoutput:"true"`
+}
+
+// MessageSubmitResult is the outcome of submitting one message in a batch - either the message as accepted, or the reason it was rejected
+type MessageSubmitResult struct {
+	Message *Message `ffstruct:"MessageSubmitResult" json:"message,omitempty"`
+	Error   string   `ffstruct:"MessageSubmitResult" json:"error,omitempty"`
 }
 
 // InputGroup declares a group in-line for automatic resolution, without having to define a group up-front
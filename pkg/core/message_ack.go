@@ -0,0 +1,43 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+type MessageAckType = fftypes.FFEnum
+
+var (
+	// MessageAckTypeDelivered is sent by a recipient once it has received and persisted a private message
+	MessageAckTypeDelivered = fftypes.FFEnumValue("messageacktype", "delivered")
+	// MessageAckTypeConfirmed is sent by a recipient once it has processed and confirmed a private message
+	MessageAckTypeConfirmed = fftypes.FFEnumValue("messageacktype", "confirmed")
+	// MessageAckTypeRejected is sent by a recipient once it has processed and rejected a private message
+	MessageAckTypeRejected = fftypes.FFEnumValue("messageacktype", "rejected")
+)
+
+// MessageAck is an acknowledgement sent back over data exchange by the recipient of a private message,
+// so the sender knows the message has been delivered to, and processed by, each member of the group.
+// Acks are not signed. They are authenticated by the data exchange peer identity they are received from.
+type MessageAck struct {
+	ID        *fftypes.UUID   `ffstruct:"MessageAck" json:"id"`
+	Namespace string          `ffstruct:"MessageAck" json:"namespace"`
+	Message   *fftypes.UUID   `ffstruct:"MessageAck" json:"message"`
+	Type      MessageAckType  `ffstruct:"MessageAck" json:"type" ffenum:"messageacktype"`
+	Author    string          `ffstruct:"MessageAck" json:"author"`
+	Node      *fftypes.UUID   `ffstruct:"MessageAck" json:"node"`
+	Created   *fftypes.FFTime `ffstruct:"MessageAck" json:"created"`
+}
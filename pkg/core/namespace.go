@@ -29,18 +29,22 @@ import (
 // Namespace is an isolated set of named resources, to allow multiple applications to co-exist in the same network, with the same named objects.
 // Can be used for use case segregation, or multi-tenancy.
 type Namespace struct {
-	Name        string                 `ffstruct:"Namespace" json:"name"`
-	NetworkName string                 `ffstruct:"Namespace" json:"networkName"`
-	Description string                 `ffstruct:"Namespace" json:"description"`
-	Created     *fftypes.FFTime        `ffstruct:"Namespace" json:"created" ffexcludeinput:"true"`
-	Contracts   *MultipartyContracts   `ffstruct:"Namespace" json:"-"`
-	TLSConfigs  map[string]*tls.Config `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	Name           string                 `ffstruct:"Namespace" json:"name"`
+	NetworkName    string                 `ffstruct:"Namespace" json:"networkName"`
+	Description    string                 `ffstruct:"Namespace" json:"description"`
+	Created        *fftypes.FFTime        `ffstruct:"Namespace" json:"created" ffexcludeinput:"true"`
+	Contracts      *MultipartyContracts   `ffstruct:"Namespace" json:"-"`
+	TLSConfigs     map[string]*tls.Config `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	SigningSecrets map[string][]byte      `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
 }
 
 type NamespaceWithInitStatus struct {
 	*Namespace
-	Initializing        bool   `ffstruct:"NamespaceWithInitStatus" json:"initializing,omitempty"`
-	InitializationError string `ffstruct:"NamespaceWithInitStatus" json:"initializationError,omitempty"`
+	Initializing        bool                    `ffstruct:"NamespaceWithInitStatus" json:"initializing,omitempty"`
+	InitializationError string                  `ffstruct:"NamespaceWithInitStatus" json:"initializationError,omitempty"`
+	Multiparty          *bool                   `ffstruct:"NamespaceWithInitStatus" json:"multiparty,omitempty"`
+	DefaultKey          string                  `ffstruct:"NamespaceWithInitStatus" json:"defaultKey,omitempty"`
+	Plugins             *NamespaceStatusPlugins `ffstruct:"NamespaceWithInitStatus" json:"plugins,omitempty"`
 }
 
 // MultipartyContracts represent the currently active and any terminated FireFly multiparty contract(s)
@@ -96,6 +100,23 @@ type NetworkAction struct {
 	Type NetworkActionType `ffstruct:"NetworkAction" json:"type" ffenum:"networkactiontype"`
 }
 
+// NetworkResync summarizes a rebuild of the local network map from the identity definitions received by this node
+type NetworkResync struct {
+	Messages           int                     `ffstruct:"NetworkResync" json:"messages"`
+	Skipped            int                     `ffstruct:"NetworkResync" json:"skipped"`
+	SkippedDefinitions []*NetworkResyncSkipped `ffstruct:"NetworkResync" json:"skippedDefinitions"`
+	Created            []*fftypes.UUID         `ffstruct:"NetworkResync" json:"created"`
+	Updated            []*fftypes.UUID         `ffstruct:"NetworkResync" json:"updated"`
+	Unchanged          int                     `ffstruct:"NetworkResync" json:"unchanged"`
+}
+
+// NetworkResyncSkipped is an identity definition message that a network resync could not replay
+type NetworkResyncSkipped struct {
+	Message *fftypes.UUID `ffstruct:"NetworkResyncSkipped" json:"message"`
+	Tag     string        `ffstruct:"NetworkResyncSkipped" json:"tag"`
+	Reason  string        `ffstruct:"NetworkResyncSkipped" json:"reason"`
+}
+
 // Scan implements sql.Scanner
 func (fc *MultipartyContracts) Scan(src interface{}) error {
 	switch src := src.(type) {
@@ -0,0 +1,37 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// NamespaceReadiness is the result of checking that each plugin of a namespace can be reached.
+// The namespace is ready only if every critical plugin is ready.
+type NamespaceReadiness struct {
+	Ready   bool               `ffstruct:"NamespaceReadiness" json:"ready"`
+	Plugins []*PluginReadiness `ffstruct:"NamespaceReadiness" json:"plugins"`
+}
+
+// PluginReadiness is the result of checking a single plugin of a namespace
+type PluginReadiness struct {
+	Category   string             `ffstruct:"PluginReadiness" json:"category"`
+	Name       string             `ffstruct:"PluginReadiness" json:"name"`
+	PluginType string             `ffstruct:"PluginReadiness" json:"pluginType"`
+	Critical   bool               `ffstruct:"PluginReadiness" json:"critical"`
+	Ready      bool               `ffstruct:"PluginReadiness" json:"ready"`
+	Latency    fftypes.FFDuration `ffstruct:"PluginReadiness" json:"latency"`
+	Error      string             `ffstruct:"PluginReadiness" json:"error,omitempty"`
+}
@@ -20,11 +20,39 @@ import "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 // NamespaceStatus is a set of information that represents the configuration and status of a given namespace
 type NamespaceStatus struct {
-	Namespace  *Namespace                `ffstruct:"NamespaceStatus" json:"namespace"`
-	Node       *NamespaceStatusNode      `ffstruct:"NamespaceStatus" json:"node,omitempty"`
-	Org        *NamespaceStatusOrg       `ffstruct:"NamespaceStatus" json:"org,omitempty"`
-	Plugins    NamespaceStatusPlugins    `ffstruct:"NamespaceStatus" json:"plugins"`
-	Multiparty NamespaceStatusMultiparty `ffstruct:"NamespaceStatus" json:"multiparty"`
+	Namespace           *Namespace                   `ffstruct:"NamespaceStatus" json:"namespace"`
+	Node                *NamespaceStatusNode         `ffstruct:"NamespaceStatus" json:"node,omitempty"`
+	Org                 *NamespaceStatusOrg          `ffstruct:"NamespaceStatus" json:"org,omitempty"`
+	Plugins             NamespaceStatusPlugins       `ffstruct:"NamespaceStatus" json:"plugins"`
+	Multiparty          NamespaceStatusMultiparty    `ffstruct:"NamespaceStatus" json:"multiparty"`
+	TokenPoolConnectors []*TokenPoolConnectorBinding `ffstruct:"NamespaceStatus" json:"tokenPoolConnectors,omitempty"`
+	CircuitBreakers     []*CircuitBreakerStatus      `ffstruct:"NamespaceStatus" json:"circuitBreakers,omitempty"`
+}
+
+// TokenPoolConnectorBinding is a configured route from a token pool to the connector that handles its operations
+type TokenPoolConnectorBinding struct {
+	Pool      string `ffstruct:"TokenPoolConnectorBinding" json:"pool"`
+	Connector string `ffstruct:"TokenPoolConnectorBinding" json:"connector"`
+}
+
+type CircuitBreakerState = fftypes.FFEnum
+
+var (
+	// CircuitBreakerStateClosed operations are being submitted to the plugin as normal
+	CircuitBreakerStateClosed = fftypes.FFEnumValue("circuitbreakerstate", "closed")
+	// CircuitBreakerStateOpen operations fail fast without being submitted to the plugin, until the cooldown expires
+	CircuitBreakerStateOpen = fftypes.FFEnumValue("circuitbreakerstate", "open")
+	// CircuitBreakerStateHalfOpen a single operation is being submitted to the plugin, to test whether it has recovered
+	CircuitBreakerStateHalfOpen = fftypes.FFEnumValue("circuitbreakerstate", "half_open")
+)
+
+// CircuitBreakerStatus is the state of the breaker protecting operation submission to a plugin
+type CircuitBreakerStatus struct {
+	Plugin              string              `ffstruct:"CircuitBreakerStatus" json:"plugin"`
+	State               CircuitBreakerState `ffstruct:"CircuitBreakerStatus" json:"state" ffenum:"circuitbreakerstate"`
+	ConsecutiveFailures int                 `ffstruct:"CircuitBreakerStatus" json:"consecutiveFailures"`
+	Opened              *fftypes.FFTime     `ffstruct:"CircuitBreakerStatus" json:"opened,omitempty"`
+	LastError           string              `ffstruct:"CircuitBreakerStatus" json:"lastError,omitempty"`
 }
 
 type NamespaceRegistrationStatus = fftypes.FFEnum
@@ -69,8 +97,32 @@ type NamespaceStatusPlugins struct {
 
 // NamespaceStatusPlugin is information about a plugin
 type NamespaceStatusPlugin struct {
-	Name       string `ffstruct:"NamespaceStatusPlugin" json:"name,omitempty"`
-	PluginType string `ffstruct:"NamespaceStatusPlugin" json:"pluginType"`
+	Name       string           `ffstruct:"NamespaceStatusPlugin" json:"name,omitempty"`
+	PluginType string           `ffstruct:"NamespaceStatusPlugin" json:"pluginType"`
+	Connector  *ConnectorStatus `ffstruct:"NamespaceStatusPlugin" json:"connector,omitempty"`
+}
+
+// ConnectorStatus is the health of the endpoints of a plugin that is configured to fail over between multiple connectors
+type ConnectorStatus struct {
+	Active    string                     `ffstruct:"ConnectorStatus" json:"active"`
+	Endpoints []*ConnectorEndpointStatus `ffstruct:"ConnectorStatus" json:"endpoints"`
+	Failovers []*ConnectorFailover       `ffstruct:"ConnectorStatus" json:"failovers"`
+}
+
+// ConnectorEndpointStatus is the result of the latest health check of a connector endpoint
+type ConnectorEndpointStatus struct {
+	URL         string          `ffstruct:"ConnectorEndpointStatus" json:"url"`
+	Healthy     bool            `ffstruct:"ConnectorEndpointStatus" json:"healthy"`
+	LastChecked *fftypes.FFTime `ffstruct:"ConnectorEndpointStatus" json:"lastChecked,omitempty"`
+	Error       string          `ffstruct:"ConnectorEndpointStatus" json:"error,omitempty"`
+}
+
+// ConnectorFailover records a switch of the active connector endpoint
+type ConnectorFailover struct {
+	From      string          `ffstruct:"ConnectorFailover" json:"from"`
+	To        string          `ffstruct:"ConnectorFailover" json:"to"`
+	Reason    string          `ffstruct:"ConnectorFailover" json:"reason"`
+	Timestamp *fftypes.FFTime `ffstruct:"ConnectorFailover" json:"timestamp"`
 }
 
 // NamespaceStatusMultiparty is information about multiparty mode and any associated multiparty contracts
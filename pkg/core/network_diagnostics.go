@@ -0,0 +1,61 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// NetworkPing is sent over data exchange to another node to check connectivity, and is discarded on receipt
+type NetworkPing struct {
+	ID        *fftypes.UUID   `json:"id"`
+	Namespace string          `json:"namespace"`
+	Node      *fftypes.UUID   `json:"node"`
+	Created   *fftypes.FFTime `json:"created"`
+}
+
+// NetworkDiagnostics is the result of actively probing the connectivity of this node to the rest of the network.
+// The network is healthy only if every check passed.
+type NetworkDiagnostics struct {
+	Healthy       bool                   `ffstruct:"NetworkDiagnostics" json:"healthy"`
+	Blockchain    *BlockchainDiagnostics `ffstruct:"NetworkDiagnostics" json:"blockchain"`
+	SharedStorage *DiagnosticCheck       `ffstruct:"NetworkDiagnostics" json:"sharedStorage"`
+	Members       []*MemberDiagnostics   `ffstruct:"NetworkDiagnostics" json:"members"`
+}
+
+// DiagnosticCheck is the result of a single probe
+type DiagnosticCheck struct {
+	Healthy bool               `ffstruct:"DiagnosticCheck" json:"healthy"`
+	Latency fftypes.FFDuration `ffstruct:"DiagnosticCheck" json:"latency"`
+	Error   string             `ffstruct:"DiagnosticCheck" json:"error,omitempty"`
+}
+
+// BlockchainDiagnostics is the result of checking the blockchain connector is in sync with the chain,
+// for the listener that receives the events of the multiparty contract
+type BlockchainDiagnostics struct {
+	DiagnosticCheck
+	Status     ContractListenerStatus `ffstruct:"BlockchainDiagnostics" json:"status"`
+	Checkpoint interface{}            `ffstruct:"BlockchainDiagnostics" json:"checkpoint,omitempty"`
+}
+
+// MemberDiagnostics is the result of sending a ping over data exchange to a node in the network
+type MemberDiagnostics struct {
+	DiagnosticCheck
+	Node      *fftypes.UUID `ffstruct:"MemberDiagnostics" json:"node"`
+	Name      string        `ffstruct:"MemberDiagnostics" json:"name"`
+	Org       *fftypes.UUID `ffstruct:"MemberDiagnostics" json:"org,omitempty"`
+	Local     bool          `ffstruct:"MemberDiagnostics" json:"local"`
+	Operation *fftypes.UUID `ffstruct:"MemberDiagnostics" json:"operation,omitempty"`
+}
@@ -27,6 +27,8 @@ var (
 	OffsetTypeAggregator = fftypes.FFEnumValue("offsettype", "aggregator")
 	// OffsetTypeSubscription is an offeset stored by a dispatcher on the events table
 	OffsetTypeSubscription = fftypes.FFEnumValue("offsettype", "subscription")
+	// OffsetTypeChartRollup is an offset stored by the chart manager on each collection it pre-aggregates
+	OffsetTypeChartRollup = fftypes.FFEnumValue("offsettype", "chartrollup")
 )
 
 // Offset is a simple stored data structure that records a sequence position within another collection
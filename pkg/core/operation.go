@@ -18,6 +18,8 @@ package core
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -53,6 +55,10 @@ var (
 	OpTypeDataExchangeSendBatch = fftypes.FFEnumValue("optype", "dataexchange_send_batch")
 	// OpTypeDataExchangeSendBlob is a private send of a blob
 	OpTypeDataExchangeSendBlob = fftypes.FFEnumValue("optype", "dataexchange_send_blob")
+	// OpTypeDataExchangeSendMessageAcks is a private send of acknowledgements for received messages
+	OpTypeDataExchangeSendMessageAcks = fftypes.FFEnumValue("optype", "dataexchange_send_message_acks")
+	// OpTypeDataExchangeSendPing is a private send of a ping to check connectivity to another node
+	OpTypeDataExchangeSendPing = fftypes.FFEnumValue("optype", "dataexchange_send_ping")
 	// OpTypeTokenCreatePool is a token pool creation
 	OpTypeTokenCreatePool = fftypes.FFEnumValue("optype", "token_create_pool")
 	// OpTypeTokenActivatePool is a token pool activation
@@ -61,6 +67,8 @@ var (
 	OpTypeTokenTransfer = fftypes.FFEnumValue("optype", "token_transfer")
 	// OpTypeTokenApproval is a token approval
 	OpTypeTokenApproval = fftypes.FFEnumValue("optype", "token_approval")
+	// OpTypeTriggerInvoke is a contract invoke that runs when the message or invoke that declared it is confirmed
+	OpTypeTriggerInvoke = fftypes.FFEnumValue("optype", "trigger_invoke")
 )
 
 func (op *Operation) IsBlockchainOperation() bool {
@@ -76,11 +84,12 @@ func (op *Operation) IsTokenOperation() bool {
 
 func (op *Operation) DeepCopy() *Operation {
 	cop := &Operation{
-		Namespace: op.Namespace,
-		Type:      op.Type,
-		Status:    op.Status,
-		Plugin:    op.Plugin,
-		Error:     op.Error,
+		Namespace:  op.Namespace,
+		Type:       op.Type,
+		Status:     op.Status,
+		Plugin:     op.Plugin,
+		Error:      op.Error,
+		RetryDepth: op.RetryDepth,
 	}
 	if op.ID != nil {
 		idCopy := *op.ID
@@ -102,12 +111,35 @@ func (op *Operation) DeepCopy() *Operation {
 		retryCopy := *op.Retry
 		cop.Retry = &retryCopy
 	}
+	if op.RetryParent != nil {
+		retryParentCopy := *op.RetryParent
+		cop.RetryParent = &retryParentCopy
+	}
 	if op.Input != nil {
 		cop.Input = deepCopyMap(op.Input)
 	}
 	if op.Output != nil {
 		cop.Output = deepCopyMap(op.Output)
 	}
+	if op.LastError != nil {
+		lastErrorCopy := *op.LastError
+		cop.LastError = &lastErrorCopy
+	}
+	if op.RetryHistory != nil {
+		cop.RetryHistory = make(OperationRetryHistory, len(op.RetryHistory))
+		for i, record := range op.RetryHistory {
+			recordCopy := *record
+			cop.RetryHistory[i] = &recordCopy
+		}
+	}
+	if op.Progress != nil {
+		progressCopy := *op.Progress
+		cop.Progress = &progressCopy
+	}
+	if op.RetryAt != nil {
+		retryAtCopy := *op.RetryAt
+		cop.RetryAt = &retryAtCopy
+	}
 	return cop
 }
 
@@ -184,18 +216,153 @@ func NewOperation(plugin Named, namespace string, tx *fftypes.UUID, opType OpTyp
 
 // Operation is a description of an action performed as part of a transaction submitted by this node
 type Operation struct {
-	ID          *fftypes.UUID      `ffstruct:"Operation" json:"id" ffexcludeinput:"true"`
-	Namespace   string             `ffstruct:"Operation" json:"namespace" ffexcludeinput:"true"`
-	Transaction *fftypes.UUID      `ffstruct:"Operation" json:"tx" ffexcludeinput:"true"`
-	Type        OpType             `ffstruct:"Operation" json:"type" ffenum:"optype" ffexcludeinput:"true"`
-	Status      OpStatus           `ffstruct:"Operation" json:"status"`
-	Plugin      string             `ffstruct:"Operation" json:"plugin" ffexcludeinput:"true"`
-	Input       fftypes.JSONObject `ffstruct:"Operation" json:"input,omitempty" ffexcludeinput:"true"`
-	Output      fftypes.JSONObject `ffstruct:"Operation" json:"output,omitempty"`
-	Error       string             `ffstruct:"Operation" json:"error,omitempty"`
-	Created     *fftypes.FFTime    `ffstruct:"Operation" json:"created,omitempty" ffexcludeinput:"true"`
-	Updated     *fftypes.FFTime    `ffstruct:"Operation" json:"updated,omitempty" ffexcludeinput:"true"`
-	Retry       *fftypes.UUID      `ffstruct:"Operation" json:"retry,omitempty" ffexcludeinput:"true"`
+	ID           *fftypes.UUID         `ffstruct:"Operation" json:"id" ffexcludeinput:"true"`
+	Namespace    string                `ffstruct:"Operation" json:"namespace" ffexcludeinput:"true"`
+	Transaction  *fftypes.UUID         `ffstruct:"Operation" json:"tx" ffexcludeinput:"true"`
+	Type         OpType                `ffstruct:"Operation" json:"type" ffenum:"optype" ffexcludeinput:"true"`
+	Status       OpStatus              `ffstruct:"Operation" json:"status"`
+	Plugin       string                `ffstruct:"Operation" json:"plugin" ffexcludeinput:"true"`
+	Input        fftypes.JSONObject    `ffstruct:"Operation" json:"input,omitempty" ffexcludeinput:"true"`
+	Output       fftypes.JSONObject    `ffstruct:"Operation" json:"output,omitempty"`
+	Error        string                `ffstruct:"Operation" json:"error,omitempty"`
+	Created      *fftypes.FFTime       `ffstruct:"Operation" json:"created,omitempty" ffexcludeinput:"true"`
+	Updated      *fftypes.FFTime       `ffstruct:"Operation" json:"updated,omitempty" ffexcludeinput:"true"`
+	Retry        *fftypes.UUID         `ffstruct:"Operation" json:"retry,omitempty" ffexcludeinput:"true"`
+	RetryDepth   int64                 `ffstruct:"Operation" json:"retryDepth" ffexcludeinput:"true"`
+	RetryParent  *fftypes.UUID         `ffstruct:"Operation" json:"retryParent,omitempty" ffexcludeinput:"true"`
+	RetryHistory OperationRetryHistory `ffstruct:"Operation" json:"retryHistory,omitempty" ffexcludeinput:"true"`
+	RetryAt      *fftypes.FFTime       `ffstruct:"Operation" json:"retryAt,omitempty" ffexcludeinput:"true"`
+	Progress     *TransferProgress     `ffstruct:"Operation" json:"progress,omitempty" ffexcludeinput:"true"`
+	// LastError is only returned on request, in OperationWithDetail, as the connector payload can be large
+	LastError *OperationError `json:"-"`
+}
+
+// OperationError is the structured error response from a connector, preserved when submitting an operation fails
+type OperationError struct {
+	StatusCode int    `ffstruct:"OperationError" json:"statusCode,omitempty"`
+	Code       string `ffstruct:"OperationError" json:"code,omitempty"`
+	Body       string `ffstruct:"OperationError" json:"body,omitempty"`
+	Truncated  bool   `ffstruct:"OperationError" json:"truncated,omitempty"`
+}
+
+// Scan implements sql.Scanner
+func (oe *OperationError) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		oe = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), &oe)
+	case []byte:
+		return json.Unmarshal(src, &oe)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, oe)
+	}
+}
+
+func (oe OperationError) Value() (driver.Value, error) {
+	bytes, _ := json.Marshal(oe)
+	return bytes, nil
+}
+
+// TransferProgress is how much of a blob a data exchange transfer has sent to the recipient
+type TransferProgress struct {
+	Transferred int64           `ffstruct:"TransferProgress" json:"transferred"`
+	Size        int64           `ffstruct:"TransferProgress" json:"size"`
+	Updated     *fftypes.FFTime `ffstruct:"TransferProgress" json:"updated"`
+}
+
+// Scan implements sql.Scanner
+func (tp *TransferProgress) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), tp)
+	case []byte:
+		return json.Unmarshal(src, tp)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, tp)
+	}
+}
+
+// Value implements sql.Valuer
+func (tp *TransferProgress) Value() (driver.Value, error) {
+	if tp == nil {
+		return nil, nil
+	}
+	bytes, _ := json.Marshal(tp)
+	return bytes, nil
+}
+
+// OperationProgress is the progress of a blob transfer operation, with the average rate it has been sent at
+type OperationProgress struct {
+	ID             *fftypes.UUID   `ffstruct:"OperationProgress" json:"id"`
+	Status         OpStatus        `ffstruct:"OperationProgress" json:"status"`
+	Transferred    int64           `ffstruct:"OperationProgress" json:"transferred"`
+	Size           int64           `ffstruct:"OperationProgress" json:"size"`
+	Percent        float64         `ffstruct:"OperationProgress" json:"percent"`
+	BytesPerSecond int64           `ffstruct:"OperationProgress" json:"bytesPerSecond"`
+	Updated        *fftypes.FFTime `ffstruct:"OperationProgress" json:"updated,omitempty"`
+}
+
+// OpRetryOutcome is the decision made by the automatic retry policy for an operation type, when an operation fails
+type OpRetryOutcome = fftypes.FFEnum
+
+var (
+	// OpRetryOutcomeRetry the error was retryable, so a retry of the operation was scheduled
+	OpRetryOutcomeRetry = fftypes.FFEnumValue("opretryoutcome", "retry")
+	// OpRetryOutcomeTerminal the error was classified as terminal, so the operation was not retried
+	OpRetryOutcomeTerminal = fftypes.FFEnumValue("opretryoutcome", "terminal")
+	// OpRetryOutcomeExhausted the error was retryable, but the maximum number of automatic retries had been reached
+	OpRetryOutcomeExhausted = fftypes.FFEnumValue("opretryoutcome", "exhausted")
+)
+
+// OperationRetryRecord records a failure of an operation in a retry chain, and what the automatic retry policy did about it
+type OperationRetryRecord struct {
+	Operation *fftypes.UUID   `ffstruct:"OperationRetryRecord" json:"operation"`
+	Failed    *fftypes.FFTime `ffstruct:"OperationRetryRecord" json:"failed"`
+	Error     string          `ffstruct:"OperationRetryRecord" json:"error,omitempty"`
+	Outcome   OpRetryOutcome  `ffstruct:"OperationRetryRecord" json:"outcome" ffenum:"opretryoutcome"`
+	RetryAt   *fftypes.FFTime `ffstruct:"OperationRetryRecord" json:"retryAt,omitempty"`
+}
+
+// OperationRetryHistory is the list of failures earlier in the retry chain of an operation, including its own failure
+type OperationRetryHistory []*OperationRetryRecord
+
+// Scan implements sql.Scanner
+func (h *OperationRetryHistory) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), h)
+	case []byte:
+		return json.Unmarshal(src, h)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, h)
+	}
+}
+
+// Value implements sql.Valuer
+func (h OperationRetryHistory) Value() (driver.Value, error) {
+	if len(h) == 0 {
+		return nil, nil
+	}
+	bytes, _ := json.Marshal(h)
+	return bytes, nil
+}
+
+// AutomaticRetries is the number of automatic retries in the history
+func (h OperationRetryHistory) AutomaticRetries() int {
+	retries := 0
+	for _, record := range h {
+		if record.Outcome == OpRetryOutcomeRetry {
+			retries++
+		}
+	}
+	return retries
 }
 
 // OperationUpdateDTO is the subset of fields on an operation that are mutable, via the SPI
@@ -205,6 +372,39 @@ type OperationUpdateDTO struct {
 	Error  *string            `ffstruct:"Operation" json:"error,omitempty"`
 }
 
+// OperationRetryRequest is the input to a bulk retry of failed operations
+type OperationRetryRequest struct {
+	IDs []*fftypes.UUID `ffstruct:"OperationRetryRequest" json:"ids,omitempty"`
+}
+
+// OperationRetryResult is the outcome of retrying an individual operation, as part of a bulk retry
+type OperationRetryResult struct {
+	ID        *fftypes.UUID `ffstruct:"OperationRetryResult" json:"id"`
+	Retried   bool          `ffstruct:"OperationRetryResult" json:"retried"`
+	Operation *Operation    `ffstruct:"OperationRetryResult" json:"operation,omitempty"`
+	Error     string        `ffstruct:"OperationRetryResult" json:"error,omitempty"`
+}
+
+// OperationNotifyInput registers a one-shot webhook, that is called with the operation when it reaches a terminal state
+type OperationNotifyInput struct {
+	URL        string `ffstruct:"OperationNotifyInput" json:"url"`
+	Secret     string `ffstruct:"OperationNotifyInput" json:"secret,omitempty"`
+	SecretName string `ffstruct:"OperationNotifyInput" json:"secretName,omitempty"`
+}
+
+// OperationNotification is a one-shot webhook registered against an operation. It is stored until it has been
+// delivered, so that registrations survive a restart. A secret supplied on registration is never stored - only
+// the name of a namespace signing secret is.
+type OperationNotification struct {
+	ID           *fftypes.UUID   `ffstruct:"OperationNotification" json:"id"`
+	Namespace    string          `ffstruct:"OperationNotification" json:"namespace"`
+	Operation    *fftypes.UUID   `ffstruct:"OperationNotification" json:"operation"`
+	URL          string          `ffstruct:"OperationNotification" json:"url"`
+	SecretName   string          `ffstruct:"OperationNotification" json:"secretName,omitempty"`
+	InlineSecret bool            `ffstruct:"OperationNotification" json:"inlineSecret,omitempty"`
+	Created      *fftypes.FFTime `ffstruct:"OperationNotification" json:"created"`
+}
+
 // PreparedOperation is an operation that has gathered all the raw data ready to send to a plugin
 // It is never stored, but it should always be possible for the owning Manager to generate a
 // PreparedOperation from an Operation. Data is defined by the Manager, but should be JSON-serializable
@@ -258,6 +458,7 @@ type OperationUpdate struct {
 	Status         OpStatus
 	BlockchainTXID string
 	ErrorMessage   string
+	ErrorDetail    *OperationError
 	Output         fftypes.JSONObject
 	VerifyManifest bool
 	DXManifest     string
@@ -271,5 +472,6 @@ type OperationDetailError struct {
 
 type OperationWithDetail struct {
 	Operation
-	Detail interface{} `ffstruct:"OperationWithDetail" json:"detail,omitempty" ffexcludeinput:"true"`
+	Detail    interface{}     `ffstruct:"OperationWithDetail" json:"detail,omitempty" ffexcludeinput:"true"`
+	LastError *OperationError `ffstruct:"OperationWithDetail" json:"lastError,omitempty" ffexcludeinput:"true"`
 }
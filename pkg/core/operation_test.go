@@ -112,6 +112,14 @@ func TestOperationDeepCopy(t *testing.T) {
 		Created:     fftypes.Now(),
 		Updated:     fftypes.Now(),
 		Retry:       fftypes.NewUUID(),
+		RetryDepth:  3,
+		RetryParent: fftypes.NewUUID(),
+		LastError:   &OperationError{StatusCode: 500, Body: "reverted"},
+		RetryHistory: OperationRetryHistory{
+			{Operation: fftypes.NewUUID(), Failed: fftypes.Now(), Error: "pop", Outcome: OpRetryOutcomeRetry},
+		},
+		Progress: &TransferProgress{Transferred: 10, Size: 100, Updated: fftypes.Now()},
+		RetryAt:  fftypes.Now(),
 	}
 
 	copyOp := op.DeepCopy()
@@ -130,6 +138,12 @@ func TestOperationDeepCopy(t *testing.T) {
 	assert.Equal(t, op.Created, copyOp.Created)
 	assert.Equal(t, op.Updated, copyOp.Updated)
 	assert.Equal(t, op.Retry, copyOp.Retry)
+	assert.Equal(t, op.RetryDepth, copyOp.RetryDepth)
+	assert.Equal(t, op.RetryParent, copyOp.RetryParent)
+	assert.Equal(t, op.LastError, copyOp.LastError)
+	assert.Equal(t, op.RetryHistory, copyOp.RetryHistory)
+	assert.Equal(t, op.Progress, copyOp.Progress)
+	assert.Equal(t, op.RetryAt, copyOp.RetryAt)
 
 	// Modify the original and ensure the copy is not modified
 	*op.ID = *fftypes.NewUUID()
@@ -146,6 +160,10 @@ func TestOperationDeepCopy(t *testing.T) {
 	assert.NotSame(t, copyOp.Retry, op.Retry)
 	assert.NotSame(t, copyOp.Input, op.Input)
 	assert.NotSame(t, copyOp.Output, op.Output)
+	assert.NotSame(t, copyOp.LastError, op.LastError)
+	assert.NotSame(t, copyOp.RetryHistory[0], op.RetryHistory[0])
+	assert.NotSame(t, copyOp.Progress, op.Progress)
+	assert.NotSame(t, copyOp.RetryAt, op.RetryAt)
 
 	// showcasing that the shallow copy is a shallow copy and the copied object value changed as well the pointer has the same address as the original
 	assert.Equal(t, shallowCopy.ID, op.ID)
@@ -153,8 +171,88 @@ func TestOperationDeepCopy(t *testing.T) {
 
 	// Ensure no new fields are added to the Operation struct
 	// If a new field is added, this test will fail and the DeepCopy function should be updated
-	assert.Equal(t, 12, reflect.TypeOf(Operation{}).NumField())
+	assert.Equal(t, 18, reflect.TypeOf(Operation{}).NumField())
 }
+
+func TestOperationErrorDatabaseSerialization(t *testing.T) {
+	oe := &OperationError{StatusCode: 500, Code: "FF23021", Body: `{"error":"FF23021: EVM reverted"}`}
+	b, err := oe.Value()
+	assert.NoError(t, err)
+
+	oe1 := &OperationError{}
+	err = oe1.Scan(b)
+	assert.NoError(t, err)
+	assert.Equal(t, oe, oe1)
+
+	oe2 := &OperationError{}
+	err = oe2.Scan(string(b.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, oe, oe2)
+
+	err = oe2.Scan(nil)
+	assert.NoError(t, err)
+
+	err = oe2.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
+
+func TestOperationRetryHistoryDatabaseSerialization(t *testing.T) {
+	h := OperationRetryHistory{
+		{Operation: fftypes.NewUUID(), Error: "pop", Outcome: OpRetryOutcomeRetry},
+		{Operation: fftypes.NewUUID(), Error: "reverted", Outcome: OpRetryOutcomeTerminal},
+	}
+	b, err := h.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, h.AutomaticRetries())
+
+	var h1 OperationRetryHistory
+	err = h1.Scan(b)
+	assert.NoError(t, err)
+	assert.Equal(t, h, h1)
+
+	var h2 OperationRetryHistory
+	err = h2.Scan(string(b.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, h, h2)
+
+	err = h2.Scan(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, h2)
+
+	err = h2.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+
+	b, err = OperationRetryHistory{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, b)
+}
+
+func TestTransferProgressDatabaseSerialization(t *testing.T) {
+	tp := &TransferProgress{Transferred: 1024, Size: 4096, Updated: fftypes.Now()}
+	b, err := tp.Value()
+	assert.NoError(t, err)
+
+	tp1 := &TransferProgress{}
+	err = tp1.Scan(b)
+	assert.NoError(t, err)
+	assert.Equal(t, tp, tp1)
+
+	tp2 := &TransferProgress{}
+	err = tp2.Scan(string(b.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, tp, tp2)
+
+	err = tp2.Scan(nil)
+	assert.NoError(t, err)
+
+	err = tp2.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+
+	b, err = (*TransferProgress)(nil).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, b)
+}
+
 func TestParseNamespacedOpID(t *testing.T) {
 
 	ctx := context.Background()
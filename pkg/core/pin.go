@@ -58,3 +58,17 @@ type PinRewind struct {
 	Sequence int64         `ffstruct:"PinRewind" json:"sequence"`
 	Batch    *fftypes.UUID `ffstruct:"PinRewind" json:"batch"`
 }
+
+type PinRewindBatches struct {
+	Batches []*fftypes.UUID `ffstruct:"PinRewindBatches" json:"batches"`
+}
+
+// PinRewindResult describes the undispatched pins the event aggregator re-evaluates
+// after a rewind (or would re-evaluate, in the case of a dry-run)
+type PinRewindResult struct {
+	Sequence int64           `ffstruct:"PinRewindResult" json:"sequence"`
+	DryRun   bool            `ffstruct:"PinRewindResult" json:"dryRun"`
+	Pins     int             `ffstruct:"PinRewindResult" json:"pins"`
+	Batches  []*fftypes.UUID `ffstruct:"PinRewindResult" json:"batches"`
+	Messages []*fftypes.UUID `ffstruct:"PinRewindResult" json:"messages"`
+}
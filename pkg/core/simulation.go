@@ -0,0 +1,28 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// Simulation is the result of a dry-run of a contract invocation or token transfer, evaluated by the
+// connector without submitting a transaction
+type Simulation struct {
+	Success      bool              `ffstruct:"Simulation" json:"success"`
+	GasEstimate  *fftypes.FFBigInt `ffstruct:"Simulation" json:"gasEstimate,omitempty"`
+	RevertReason string            `ffstruct:"Simulation" json:"revertReason,omitempty"`
+	Output       interface{}       `ffstruct:"Simulation" json:"output,omitempty"`
+}
@@ -0,0 +1,30 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// SSEConnected is the first event sent on a server-sent events stream, identifying the connection for acknowledgements
+type SSEConnected struct {
+	Connection string `json:"connection"`
+}
+
+// SSEAck acknowledges an event received over a server-sent events stream (not applicable in AutoAck mode)
+type SSEAck struct {
+	Connection string        `ffstruct:"SSEAck" json:"connection"`
+	ID         *fftypes.UUID `ffstruct:"SSEAck" json:"id,omitempty"`
+}
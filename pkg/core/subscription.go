@@ -87,6 +87,19 @@ const (
 	SubOptsFirstEventNewest SubOptsFirstEvent = "newest"
 )
 
+// SubOptsEnricher is an additional lookup made on each event delivered to a subscription, so the application receives
+// a self-contained event rather than making further REST calls to process it
+type SubOptsEnricher = fftypes.FFEnum
+
+var (
+	// SubOptsEnricherMessageData includes the full data of a message inline in the event
+	SubOptsEnricherMessageData = fftypes.FFEnumValue("subenricher", "message_data")
+	// SubOptsEnricherAuthorDID includes the DID document of the author of a message
+	SubOptsEnricherAuthorDID = fftypes.FFEnumValue("subenricher", "author_did")
+	// SubOptsEnricherBlockchainEventFFI includes the FFI definition of the event a blockchain event was decoded with
+	SubOptsEnricherBlockchainEventFFI = fftypes.FFEnumValue("subenricher", "blockchain_event_ffi")
+)
+
 // SubscriptionCoreOptions are the core options that apply across all transports
 // REMEMBER TO ADD OPTIONS HERE TO MarshalJSON()
 type SubscriptionCoreOptions struct {
@@ -95,6 +108,7 @@ type SubscriptionCoreOptions struct {
 	WithData     *bool              `ffstruct:"SubscriptionCoreOptions" json:"withData,omitempty"`
 	Batch        *bool              `ffstruct:"SubscriptionCoreOptions" json:"batch,omitempty"`
 	BatchTimeout *string            `ffstruct:"SubscriptionCoreOptions" json:"batchTimeout,omitempty"`
+	Enrich       []SubOptsEnricher  `ffstruct:"SubscriptionCoreOptions" json:"enrich,omitempty"`
 }
 
 // SubscriptionOptions customize the behavior of subscriptions
@@ -134,6 +148,32 @@ type SubscriptionStatus struct {
 	CurrentOffset int64 `ffstruct:"SubscriptionStatus" json:"currentOffset,omitempty" ffexcludeinout:"true"`
 }
 
+// SubscriptionReplayInput is the point to rewind a subscription to - either an event sequence, or a timestamp
+type SubscriptionReplayInput struct {
+	Sequence  *int64          `ffstruct:"SubscriptionReplayInput" json:"sequence,omitempty"`
+	Timestamp *fftypes.FFTime `ffstruct:"SubscriptionReplayInput" json:"timestamp,omitempty"`
+}
+
+// SubscriptionReplay is the result of rewinding a subscription
+type SubscriptionReplay struct {
+	Subscription SubscriptionRef `ffstruct:"SubscriptionReplay" json:"subscription"`
+	Offset       int64           `ffstruct:"SubscriptionReplay" json:"offset"`
+	Marker       *fftypes.UUID   `ffstruct:"SubscriptionReplay" json:"marker"`
+}
+
+// SubscriptionPreviewInput is a subscription definition to test against recent events, without creating the subscription
+type SubscriptionPreviewInput struct {
+	Filter  SubscriptionFilter  `ffstruct:"SubscriptionPreviewInput" json:"filter"`
+	Options SubscriptionOptions `ffstruct:"SubscriptionPreviewInput" json:"options"`
+	Limit   int                 `ffstruct:"SubscriptionPreviewInput" json:"limit,omitempty"`
+}
+
+// SubscriptionPreview is the most recent events that would have been delivered to a subscription
+type SubscriptionPreview struct {
+	Scanned int              `ffstruct:"SubscriptionPreview" json:"scanned"`
+	Events  []*EnrichedEvent `ffstruct:"SubscriptionPreview" json:"events"`
+}
+
 func (so *SubscriptionOptions) UnmarshalJSON(b []byte) error {
 	so.additionalOptions = fftypes.JSONObject{}
 	err := json.Unmarshal(b, &so.additionalOptions)
@@ -149,6 +189,7 @@ func (so *SubscriptionOptions) UnmarshalJSON(b []byte) error {
 	delete(so.additionalOptions, "firstEvent")
 	delete(so.additionalOptions, "readAhead")
 	delete(so.additionalOptions, "withData")
+	delete(so.additionalOptions, "enrich")
 	return nil
 }
 
@@ -168,12 +209,18 @@ func (so SubscriptionOptions) MarshalJSON() ([]byte, error) {
 	if so.TLSConfigName != "" {
 		so.additionalOptions["tlsConfigName"] = so.TLSConfigName
 	}
+	if so.Signing != nil {
+		so.additionalOptions["signing"] = so.Signing
+	}
 	if so.Batch != nil {
 		so.additionalOptions["batch"] = so.Batch
 	}
 	if so.BatchTimeout != nil {
 		so.additionalOptions["batchTimeout"] = so.BatchTimeout
 	}
+	if len(so.Enrich) > 0 {
+		so.additionalOptions["enrich"] = so.Enrich
+	}
 
 	return json.Marshal(&so.additionalOptions)
 }
@@ -37,9 +37,12 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 				WithData:     &yes,
 				Batch:        &yes,
 				BatchTimeout: &oneSec,
+				Enrich:       []SubOptsEnricher{SubOptsEnricherAuthorDID},
 			},
 			WebhookSubOptions: WebhookSubOptions{
 				TLSConfigName: "myconfig",
+				Signing:       &WebhookSigningOptions{SecretName: "mysecret"},
+				SigningSecret: []byte("shh"),
 			},
 		},
 		Filter: SubscriptionFilter{},
@@ -60,9 +63,11 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 		},
 		"readAhead":50,
 		"tlsConfigName":"myconfig",
+		"signing":{"secretName":"mysecret"},
 		"withData":true,
 		"batch":true,
-		"batchTimeout":"1s"
+		"batchTimeout":"1s",
+		"enrich":["author_did"]
 	}`, string(b1.([]byte)))
 
 	f1, err := sub1.Filter.Value()
@@ -79,12 +84,16 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 	assert.Equal(t, SubOptsFirstEventNewest, *sub2.Options.FirstEvent)
 	assert.Equal(t, uint16(50), *sub2.Options.ReadAhead)
 	assert.Equal(t, "myconfig", sub2.Options.TLSConfigName)
+	assert.Equal(t, "mysecret", sub2.Options.Signing.SecretName)
+	assert.Nil(t, sub2.Options.SigningSecret)
+	assert.Equal(t, []SubOptsEnricher{SubOptsEnricherAuthorDID}, sub2.Options.Enrich)
 	assert.Equal(t, string(b1.([]byte)), string(b2.([]byte)))
 
 	// Confirm we don't pass core options, to transports
 	assert.Nil(t, sub2.Options.TransportOptions()["withData"])
 	assert.Nil(t, sub2.Options.TransportOptions()["firstEvent"])
 	assert.Nil(t, sub2.Options.TransportOptions()["readAhead"])
+	assert.Nil(t, sub2.Options.TransportOptions()["enrich"])
 
 	// Confirm we get back the transport options
 	assert.Equal(t, float64(12345), sub2.Options.TransportOptions().GetObject("my-nested-opts")["myopt1"])
@@ -27,6 +27,7 @@ type TokenBalance struct {
 	Key        string           `ffstruct:"TokenBalance" json:"key,omitempty"`
 	Balance    fftypes.FFBigInt `ffstruct:"TokenBalance" json:"balance"`
 	Updated    *fftypes.FFTime  `ffstruct:"TokenBalance" json:"updated,omitempty"`
+	Metadata   *TokenMetadata   `ffstruct:"TokenBalance" json:"metadata,omitempty"`
 }
 
 func TokenBalanceIdentifier(pool *fftypes.UUID, tokenIndex, identity string) string {
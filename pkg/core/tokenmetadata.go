@@ -0,0 +1,35 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenMetadata is the metadata of a token, resolved from the document its token URI refers to
+type TokenMetadata struct {
+	Name        string             `ffstruct:"TokenMetadata" json:"name,omitempty"`
+	Description string             `ffstruct:"TokenMetadata" json:"description,omitempty"`
+	Image       string             `ffstruct:"TokenMetadata" json:"image,omitempty"`
+	Document    fftypes.JSONObject `ffstruct:"TokenMetadata" json:"document,omitempty"`
+}
+
+// Token is a single token within a non-fungible token pool
+type Token struct {
+	Pool       *fftypes.UUID  `ffstruct:"Token" json:"pool,omitempty"`
+	TokenIndex string         `ffstruct:"Token" json:"tokenIndex,omitempty"`
+	URI        string         `ffstruct:"Token" json:"uri,omitempty"`
+	Metadata   *TokenMetadata `ffstruct:"Token" json:"metadata,omitempty"`
+}
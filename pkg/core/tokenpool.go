@@ -18,8 +18,13 @@ package core
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"math/big"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 )
 
 type TokenType = fftypes.FFEnum
@@ -63,9 +68,16 @@ type TokenPool struct {
 	InterfaceFormat TokenInterfaceFormat  `ffstruct:"TokenPool" json:"interfaceFormat,omitempty" ffenum:"tokeninterfaceformat" ffexcludeinput:"true"`
 	Methods         *fftypes.JSONAny      `ffstruct:"TokenPool" json:"methods,omitempty" ffexcludeinput:"true"`
 	Published       bool                  `ffstruct:"TokenPool" json:"published" ffexcludeinput:"true"`
+	Backfill        *TokenPoolBackfill    `ffstruct:"TokenPool" json:"backfill,omitempty"`
 	PluginData      string                `ffstruct:"TokenPool" json:"-" ffexcludeinput:"true"` // reserved for internal plugin use (not returned on API)
 }
 
+// TokenPoolBackfill opts a pool on an existing token contract in to replaying the historical transfer
+// events of that contract, so the transfers and balances from before the pool was created are indexed
+type TokenPoolBackfill struct {
+	FromBlock string `ffstruct:"TokenPoolBackfill" json:"fromBlock"`
+}
+
 type TokenPoolDefinition struct {
 	Pool *TokenPool `json:"pool"`
 }
@@ -79,9 +91,44 @@ func (t *TokenPool) Validate(ctx context.Context) (err error) {
 			return err
 		}
 	}
+	if t.Backfill != nil {
+		return t.Backfill.Validate(ctx)
+	}
 	return nil
 }
 
+// Validate checks the start block is a non-negative integer, defaulting it to the first block of the chain
+func (b *TokenPoolBackfill) Validate(ctx context.Context) error {
+	if b.FromBlock == "" {
+		b.FromBlock = "0"
+	}
+	i, ok := new(big.Int).SetString(b.FromBlock, 10)
+	if !ok || i.Sign() < 0 {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidBackfillFromBlock, b.FromBlock)
+	}
+	b.FromBlock = i.String()
+	return nil
+}
+
+// Scan implements sql.Scanner
+func (b *TokenPoolBackfill) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), &b)
+	case []byte:
+		return json.Unmarshal(src, &b)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, b)
+	}
+}
+
+func (b TokenPoolBackfill) Value() (driver.Value, error) {
+	bytes, _ := json.Marshal(b)
+	return bytes, nil
+}
+
 func (t *TokenPoolDefinition) Topic() string {
 	return fftypes.TypeNamespaceNameTopicHash("tokenpool", t.Pool.Namespace, t.Pool.NetworkName)
 }
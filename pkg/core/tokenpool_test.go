@@ -43,9 +43,49 @@ func TestTokenPoolValidation(t *testing.T) {
 	pool = &TokenPool{
 		Namespace: "ok",
 		Name:      "ok",
+		Backfill:  &TokenPoolBackfill{FromBlock: "-1"},
+	}
+	err = pool.Validate(context.Background())
+	assert.Regexp(t, "FF10556", err)
+
+	pool = &TokenPool{
+		Namespace: "ok",
+		Name:      "ok",
+		Backfill:  &TokenPoolBackfill{},
 	}
 	err = pool.Validate(context.Background())
 	assert.NoError(t, err)
+	assert.Equal(t, "0", pool.Backfill.FromBlock)
+
+	pool = &TokenPool{
+		Namespace: "ok",
+		Name:      "ok",
+	}
+	err = pool.Validate(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestTokenPoolBackfillDatabaseSerialization(t *testing.T) {
+	b1 := &TokenPoolBackfill{FromBlock: "12345"}
+	b1v, err := b1.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"fromBlock":"12345"}`, string(b1v.([]byte)))
+
+	b2 := &TokenPoolBackfill{}
+	err = b2.Scan(b1v)
+	assert.NoError(t, err)
+	assert.Equal(t, b1, b2)
+
+	b3 := &TokenPoolBackfill{}
+	err = b3.Scan(string(b1v.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, b1, b3)
+
+	err = b3.Scan(nil)
+	assert.NoError(t, err)
+
+	err = b3.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
 }
 
 func TestTokenPoolDefinition(t *testing.T) {
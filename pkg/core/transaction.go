@@ -45,6 +45,8 @@ var (
 	TransactionTypeTokenApproval = fftypes.FFEnumValue("txtype", "token_approval")
 	// TransactionTypeDataPublish represents a publish to shared storage
 	TransactionTypeDataPublish = fftypes.FFEnumValue("txtype", "data_publish")
+	// TransactionTypeTrigger represents the follow-up actions declared on a message or contract invoke
+	TransactionTypeTrigger = fftypes.FFEnumValue("txtype", "trigger")
 )
 
 // TransactionRef refers to a transaction, in other types
@@ -77,6 +79,7 @@ var (
 	TransactionStatusTypeOperation       TransactionStatusType = "Operation"
 	TransactionStatusTypeBlockchainEvent TransactionStatusType = "BlockchainEvent"
 	TransactionStatusTypeBatch           TransactionStatusType = "Batch"
+	TransactionStatusTypePins            TransactionStatusType = "Pins"
 	TransactionStatusTypeTokenPool       TransactionStatusType = "TokenPool"
 	TransactionStatusTypeTokenTransfer   TransactionStatusType = "TokenTransfer"
 	TransactionStatusTypeTokenApproval   TransactionStatusType = "TokenApproval"
@@ -27,6 +27,8 @@ var (
 
 // TransportWrapper wraps paylaods over data exchange transfers, for easy deserialization at target
 type TransportWrapper struct {
-	Group *Group `json:"group,omitempty"`
-	Batch *Batch `json:"batch,omitempty"`
+	Group *Group        `json:"group,omitempty"`
+	Batch *Batch        `json:"batch,omitempty"`
+	Acks  []*MessageAck `json:"acks,omitempty"`
+	Ping  *NetworkPing  `json:"ping,omitempty"`
 }
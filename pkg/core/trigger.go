@@ -0,0 +1,63 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TriggerActionType is the type of action run by a trigger
+type TriggerActionType = fftypes.FFEnum
+
+var (
+	// TriggerActionTypeInvoke invokes a method of a smart contract, or of a contract API
+	TriggerActionTypeInvoke = fftypes.FFEnumValue("triggeractiontype", "invoke")
+)
+
+// TriggerSourceType is the type of record a trigger waits on to be confirmed
+type TriggerSourceType = fftypes.FFEnum
+
+var (
+	// TriggerSourceTypeMessage waits for a message to be confirmed or rejected
+	TriggerSourceTypeMessage = fftypes.FFEnumValue("triggersourcetype", "message")
+	// TriggerSourceTypeOperation waits for a blockchain invoke operation to succeed or fail
+	TriggerSourceTypeOperation = fftypes.FFEnumValue("triggersourcetype", "operation")
+)
+
+// TriggerAction is a follow-up action declared on a message or contract invoke, which runs
+// once that message or invoke is confirmed. Each action is recorded as an operation of its own,
+// in a "trigger" transaction, so its progress and outcome can be tracked and retried.
+type TriggerAction struct {
+	Type   TriggerActionType `ffstruct:"TriggerAction" json:"type" ffenum:"triggeractiontype"`
+	Invoke *TriggerInvoke    `ffstruct:"TriggerAction" json:"invoke,omitempty"`
+}
+
+// TriggerInvoke is the contract invoke run by a trigger action, either against a contract API by name,
+// or against an interface and location
+type TriggerInvoke struct {
+	API        string                 `ffstruct:"TriggerInvoke" json:"api,omitempty"`
+	Interface  *fftypes.UUID          `ffstruct:"TriggerInvoke" json:"interface,omitempty"`
+	Location   *fftypes.JSONAny       `ffstruct:"TriggerInvoke" json:"location,omitempty"`
+	MethodPath string                 `ffstruct:"TriggerInvoke" json:"methodPath"`
+	Key        string                 `ffstruct:"TriggerInvoke" json:"key,omitempty"`
+	Input      map[string]interface{} `ffstruct:"TriggerInvoke" json:"input"`
+	Options    map[string]interface{} `ffstruct:"TriggerInvoke" json:"options,omitempty"`
+}
+
+// TriggerSource is the message or operation that trigger actions wait on
+type TriggerSource struct {
+	Type TriggerSourceType `json:"type"`
+	ID   *fftypes.UUID     `json:"id"`
+}
@@ -42,13 +42,16 @@ type VerifierRef struct {
 	Value string       `ffstruct:"Verifier" json:"value"`
 }
 
-// Verifier is an identity verification system that has been established for this identity, such as a blockchain signing key identifier
+// Verifier is an identity verification system that has been established for this identity, such as a blockchain signing key identifier.
+// A verifier is valid from when it is created, until it is deprecated.
 type Verifier struct {
 	Hash      *fftypes.Bytes32 `ffstruct:"Verifier" json:"hash"` // Used to ensure the same ID is generated on each node, but not critical for verification. In v0.13 migration was set to the ID of the parent.
 	Identity  *fftypes.UUID    `ffstruct:"Verifier" json:"identity,omitempty"`
 	Namespace string           `ffstruct:"Verifier" json:"namespace,omitempty"`
 	VerifierRef
-	Created *fftypes.FFTime `ffstruct:"Verifier" json:"created,omitempty"`
+	Message    *fftypes.UUID   `ffstruct:"Verifier" json:"message,omitempty"` // Only set for verifiers added to an existing identity
+	Created    *fftypes.FFTime `ffstruct:"Verifier" json:"created,omitempty"`
+	Deprecated *fftypes.FFTime `ffstruct:"Verifier" json:"deprecated,omitempty"`
 }
 
 // Seal updates the hash to be deterministically generated from the namespace+type+value, such that
@@ -23,28 +23,38 @@ import (
 )
 
 type WebhookSubOptions struct {
-	Fastack       bool                `ffstruct:"WebhookSubOptions" json:"fastack,omitempty"`
-	URL           string              `ffstruct:"WebhookSubOptions" json:"url,omitempty"`
-	Method        string              `ffstruct:"WebhookSubOptions" json:"method,omitempty"`
-	JSON          bool                `ffstruct:"WebhookSubOptions" json:"json,omitempty"`
-	Reply         bool                `ffstruct:"WebhookSubOptions" json:"reply,omitempty"`
-	ReplyTag      string              `ffstruct:"WebhookSubOptions" json:"replytag,omitempty"`
-	ReplyTX       string              `ffstruct:"WebhookSubOptions" json:"replytx,omitempty"`
-	Headers       map[string]string   `ffstruct:"WebhookSubOptions" json:"headers,omitempty"`
-	Query         map[string]string   `ffstruct:"WebhookSubOptions" json:"query,omitempty"`
-	TLSConfigName string              `ffstruct:"WebhookSubOptions" json:"tlsConfigName,omitempty"`
-	TLSConfig     *tls.Config         `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
-	Input         WebhookInputOptions `ffstruct:"WebhookSubOptions" json:"input,omitempty"`
-	Retry         WebhookRetryOptions `ffstruct:"WebhookSubOptions" json:"retry,omitempty"`
-	HTTPOptions   WebhookHTTPOptions  `ffstruct:"WebhookSubOptions" json:"httpOptions,omitempty"`
-	RestyClient   *resty.Client       `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
+	Fastack       bool                   `ffstruct:"WebhookSubOptions" json:"fastack,omitempty"`
+	URL           string                 `ffstruct:"WebhookSubOptions" json:"url,omitempty"`
+	Method        string                 `ffstruct:"WebhookSubOptions" json:"method,omitempty"`
+	JSON          bool                   `ffstruct:"WebhookSubOptions" json:"json,omitempty"`
+	Reply         bool                   `ffstruct:"WebhookSubOptions" json:"reply,omitempty"`
+	ReplyTag      string                 `ffstruct:"WebhookSubOptions" json:"replytag,omitempty"`
+	ReplyTX       string                 `ffstruct:"WebhookSubOptions" json:"replytx,omitempty"`
+	Headers       map[string]string      `ffstruct:"WebhookSubOptions" json:"headers,omitempty"`
+	Query         map[string]string      `ffstruct:"WebhookSubOptions" json:"query,omitempty"`
+	TLSConfigName string                 `ffstruct:"WebhookSubOptions" json:"tlsConfigName,omitempty"`
+	TLSConfig     *tls.Config            `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
+	Signing       *WebhookSigningOptions `ffstruct:"WebhookSubOptions" json:"signing,omitempty"`
+	SigningSecret []byte                 `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
+	Input         WebhookInputOptions    `ffstruct:"WebhookSubOptions" json:"input,omitempty"`
+	Retry         WebhookRetryOptions    `ffstruct:"WebhookSubOptions" json:"retry,omitempty"`
+	HTTPOptions   WebhookHTTPOptions     `ffstruct:"WebhookSubOptions" json:"httpOptions,omitempty"`
+	RestyClient   *resty.Client          `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
+}
+
+type WebhookSigningOptions struct {
+	SecretName string `ffstruct:"WebhookSigningOptions" json:"secretName,omitempty"`
+	Header     string `ffstruct:"WebhookSigningOptions" json:"header,omitempty"`
 }
 
 type WebhookRetryOptions struct {
-	Enabled      bool   `ffstruct:"WebhookRetryOptions" json:"enabled,omitempty"`
-	Count        int    `ffstruct:"WebhookRetryOptions" json:"count,omitempty"`
-	InitialDelay string `ffstruct:"WebhookRetryOptions" json:"initialDelay,omitempty"`
-	MaximumDelay string `ffstruct:"WebhookRetryOptions" json:"maxDelay,omitempty"`
+	Enabled      bool    `ffstruct:"WebhookRetryOptions" json:"enabled,omitempty"`
+	Count        int     `ffstruct:"WebhookRetryOptions" json:"count,omitempty"`
+	InitialDelay string  `ffstruct:"WebhookRetryOptions" json:"initialDelay,omitempty"`
+	MaximumDelay string  `ffstruct:"WebhookRetryOptions" json:"maxDelay,omitempty"`
+	Factor       float64 `ffstruct:"WebhookRetryOptions" json:"factor,omitempty"`
+	Jitter       float64 `ffstruct:"WebhookRetryOptions" json:"jitter,omitempty"`
+	DeadLetter   bool    `ffstruct:"WebhookRetryOptions" json:"deadLetter,omitempty"`
 }
 
 type WebhookHTTPOptions struct {
@@ -32,6 +32,12 @@ var (
 
 	// WSEventBatchType is the type set when the message contains an array of events
 	WSEventBatchType = fftypes.FFEnumValue("wstype", "event_batch")
+
+	// WSEventReferenceType is the type set when an event was too large to send, and must be fetched by the client
+	WSEventReferenceType = fftypes.FFEnumValue("wstype", "event_reference")
+
+	// WSMessageFragmentType is the type set when a message was too large to send, and has been split into fragments
+	WSMessageFragmentType = fftypes.FFEnumValue("wstype", "message_fragment")
 )
 
 // WSActionBase is the base fields of all client actions sent on the websocket
@@ -72,4 +78,24 @@ type WSEventBatch struct {
 	ID           *fftypes.UUID       `ffstruct:"WSEventBatch" json:"id"`
 	Subscription SubscriptionRef     `ffstruct:"WSEventBatch" json:"subscription"`
 	Events       []*EventDelivery    `ffstruct:"WSEventBatch" json:"events"`
+	References   bool                `ffstruct:"WSEventBatch" json:"references,omitempty"`
+}
+
+// WSEventReference is sent in place of an event that exceeded the maximum message size of the connection.
+// The client fetches the full event via the REST API, and acks it using the event ID as normal.
+type WSEventReference struct {
+	Type         WSClientPayloadType `ffstruct:"WSEventReference" json:"type" ffenum:"wstype"`
+	Event        *Event              `ffstruct:"WSEventReference" json:"event"`
+	Subscription SubscriptionRef     `ffstruct:"WSEventReference" json:"subscription"`
+	Size         int64               `ffstruct:"WSEventReference" json:"size"`
+}
+
+// WSMessageFragment carries part of a message that exceeded the maximum message size of the connection.
+// The client concatenates the decoded data of fragments 0..count-1 with the same ID to obtain the original message.
+type WSMessageFragment struct {
+	Type  WSClientPayloadType `ffstruct:"WSMessageFragment" json:"type" ffenum:"wstype"`
+	ID    *fftypes.UUID       `ffstruct:"WSMessageFragment" json:"id"`
+	Index int                 `ffstruct:"WSMessageFragment" json:"index"`
+	Count int                 `ffstruct:"WSMessageFragment" json:"count"`
+	Data  []byte              `ffstruct:"WSMessageFragment" json:"data"`
 }
@@ -27,10 +27,12 @@ type WSSubscriptionStatus struct {
 }
 
 type WSConnectionStatus struct {
-	ID            string                  `ffstruct:"WSConnectionStatus" json:"id"`
-	RemoteAddress string                  `ffstruct:"WSConnectionStatus" json:"remoteAddress"`
-	UserAgent     string                  `ffstruct:"WSConnectionStatus" json:"userAgent"`
-	Subscriptions []*WSSubscriptionStatus `ffstruct:"WSConnectionStatus" json:"subscriptions"`
+	ID             string                  `ffstruct:"WSConnectionStatus" json:"id"`
+	RemoteAddress  string                  `ffstruct:"WSConnectionStatus" json:"remoteAddress"`
+	UserAgent      string                  `ffstruct:"WSConnectionStatus" json:"userAgent"`
+	MaxMessageSize int64                   `ffstruct:"WSConnectionStatus" json:"maxMessageSize,omitempty"`
+	OversizeMode   string                  `ffstruct:"WSConnectionStatus" json:"oversizeMode,omitempty"`
+	Subscriptions  []*WSSubscriptionStatus `ffstruct:"WSConnectionStatus" json:"subscriptions"`
 }
 
 type WebSocketStatus struct {
@@ -18,6 +18,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -65,6 +66,9 @@ type Plugin interface {
 
 	// Capabilities returns capabilities - not called until after Init
 	Capabilities() *Capabilities
+
+	// Ping checks the database can be reached, without any side effects
+	Ping(ctx context.Context) error
 }
 
 type iNamespaceCollection interface {
@@ -106,11 +110,17 @@ type iMessageCollection interface {
 	// GetMessagesForData - List messages where there is a data reference to the specified ID
 	GetMessagesForData(ctx context.Context, namespace string, dataID *fftypes.UUID, filter ffapi.Filter) (message []*core.Message, res *ffapi.FilterResult, err error)
 
+	// SearchMessages - List messages with at least one data item whose value matches the query, in addition to the filter
+	SearchMessages(ctx context.Context, namespace string, filter ffapi.Filter, query *DataValueQuery) (message []*core.Message, res *ffapi.FilterResult, err error)
+
 	// GetBatchIDsForMessages - an optimized query to retrieve any non-null batch IDs for a list of message IDs
 	GetBatchIDsForMessages(ctx context.Context, namespace string, msgIDs []*fftypes.UUID) (batchIDs []*fftypes.UUID, err error)
 
 	// GetBatchIDsForDataAttachments - an optimized query to retrieve any non-null batch IDs for a list of data IDs that might be attached to messages in batches
 	GetBatchIDsForDataAttachments(ctx context.Context, namespace string, dataIDs []*fftypes.UUID) (batchIDs []*fftypes.UUID, err error)
+
+	// DeleteMessages - Deletes messages by ID, along with their data references (but not the data itself)
+	DeleteMessages(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error)
 }
 
 type iDataCollection interface {
@@ -131,6 +141,9 @@ type iDataCollection interface {
 	// GetData - Get data
 	GetData(ctx context.Context, namespace string, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error)
 
+	// SearchData - Get data whose value matches the query, in addition to the filter
+	SearchData(ctx context.Context, namespace string, filter ffapi.Filter, query *DataValueQuery) (message core.DataArray, res *ffapi.FilterResult, err error)
+
 	// GetDataSubPaths - returns unique paths that have files in them, under the specified path.
 	// Requires DB specific processing of the blob.path field.
 	GetDataSubPaths(ctx context.Context, namespace, path string) (subPaths []string, err error)
@@ -140,6 +153,9 @@ type iDataCollection interface {
 
 	// DeleteData - Deletes a data record by ID
 	DeleteData(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
+
+	// DeleteUnreferencedData - Deletes the data records with the supplied IDs that are no longer referenced by any message
+	DeleteUnreferencedData(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error)
 }
 
 type iBatchCollection interface {
@@ -185,6 +201,9 @@ type iDatatypeCollection interface {
 
 	// GetDatatypes - Get data definitions
 	GetDatatypes(ctx context.Context, namespace string, filter ffapi.Filter) (datadef []*core.Datatype, res *ffapi.FilterResult, err error)
+
+	// UpdateDatatypeLifecycle - Update the lifecycle state of a data definition
+	UpdateDatatypeLifecycle(ctx context.Context, namespace string, id *fftypes.UUID, lifecycle core.DefinitionLifecycle) (err error)
 }
 
 type iOffsetCollection interface {
@@ -233,6 +252,9 @@ type iOperationCollection interface {
 
 	// GetOperations - Get operation
 	GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) (operation []*core.Operation, res *ffapi.FilterResult, err error)
+
+	// DeleteOperations - Deletes operations by ID
+	DeleteOperations(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error)
 }
 
 type iSubscriptionCollection interface {
@@ -256,6 +278,42 @@ type iSubscriptionCollection interface {
 	DeleteSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
 }
 
+type iDeadLetterCollection interface {
+	// InsertDeadLetter - insert a dead-letter entry for an undeliverable event
+	InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (err error)
+
+	// UpdateDeadLetter - update a dead-letter entry
+	UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error)
+
+	// GetDeadLetterByID - get a dead-letter entry by id
+	GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (deadLetter *core.DeadLetter, err error)
+
+	// GetDeadLetters - get dead-letter entries
+	GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) (deadLetters []*core.DeadLetter, res *ffapi.FilterResult, err error)
+
+	// DeleteDeadLetter - delete a dead-letter entry, once it has been replayed
+	DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
+}
+
+type iOperationNotificationCollection interface {
+	// InsertOperationNotification - insert a webhook registered against an operation
+	InsertOperationNotification(ctx context.Context, notification *core.OperationNotification) (err error)
+
+	// GetOperationNotifications - get the webhooks registered against operations
+	GetOperationNotifications(ctx context.Context, namespace string, filter ffapi.Filter) (notifications []*core.OperationNotification, res *ffapi.FilterResult, err error)
+
+	// DeleteOperationNotification - delete a webhook registration, once it has been delivered
+	DeleteOperationNotification(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
+}
+
+type iMessageAckCollection interface {
+	// InsertMessageAck - insert an acknowledgement received from a recipient of a private message
+	InsertMessageAck(ctx context.Context, ack *core.MessageAck) (err error)
+
+	// GetMessageAcks - get message acknowledgements
+	GetMessageAcks(ctx context.Context, namespace string, filter ffapi.Filter) (acks []*core.MessageAck, res *ffapi.FilterResult, err error)
+}
+
 type iEventCollection interface {
 	// InsertEvent - Insert an event. The order of the sequences added to the database, must match the order that
 	//               the rows/objects appear available to the event dispatcher. For a concurrency enabled database
@@ -271,6 +329,9 @@ type iEventCollection interface {
 
 	// GetEventsInSequenceRange - Get a range of events between 2 sequence values
 	GetEventsInSequenceRange(ctx context.Context, namespace string, filter ffapi.Filter, startSequence int, endSequence int) (message []*core.Event, res *ffapi.FilterResult, err error)
+
+	// DeleteEvents - Deletes events by ID
+	DeleteEvents(ctx context.Context, namespace string, ids []*fftypes.UUID) (err error)
 }
 
 type iIdentitiesCollection interface {
@@ -464,6 +525,12 @@ type iFFICollection interface {
 
 	// DeleteFFI - Delete an FFI
 	DeleteFFI(ctx context.Context, namespace string, id *fftypes.UUID) error
+
+	// UpdateFFILifecycle - Update the lifecycle state of an FFI
+	UpdateFFILifecycle(ctx context.Context, namespace string, id *fftypes.UUID, lifecycle core.DefinitionLifecycle) error
+
+	// GetFFILifecycle - Get the lifecycle state of an FFI
+	GetFFILifecycle(ctx context.Context, namespace string, id *fftypes.UUID) (core.DefinitionLifecycle, error)
 }
 
 type iFFIMethodCollection interface {
@@ -542,6 +609,11 @@ type iContractListenerCollection interface {
 	// GetContractListeners - get contract listeners
 	GetContractListeners(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.ContractListener, *ffapi.FilterResult, error)
 
+	// IterateContractListeners - pass each contract listener matching the filter to the callback, reading them in bounded
+	// pages rather than loading the whole result or holding a cursor open while the callback runs. Stops at the first
+	// error from the callback, or when the context is cancelled
+	IterateContractListeners(ctx context.Context, namespace string, filter ffapi.Filter, cb func(listener *core.ContractListener) error) error
+
 	// DeleteContractListener - delete a contract listener
 	DeleteContractListenerByID(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
 }
@@ -563,12 +635,57 @@ type iBlockchainEventCollection interface {
 
 	// GetBlockchainEvents - get blockchain events
 	GetBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
+
+	// GetBlockchainEventListenerTimestamps - get the timestamp of the most recent blockchain event of each of the listeners,
+	// in a single grouped query. Listeners that have never recorded an event are absent from the map.
+	GetBlockchainEventListenerTimestamps(ctx context.Context, namespace string, listenerIDs []*fftypes.UUID) (map[fftypes.UUID]*fftypes.FFTime, error)
+
+	// InsertBlockchainEventOutput - store the full output of a blockchain event that was truncated when indexed
+	InsertBlockchainEventOutput(ctx context.Context, namespace string, id *fftypes.UUID, output fftypes.JSONObject) error
+
+	// GetBlockchainEventOutput - get the full output of a blockchain event that was truncated when indexed
+	GetBlockchainEventOutput(ctx context.Context, namespace string, id *fftypes.UUID) (fftypes.JSONObject, error)
 }
 
 // PersistenceInterface are the operations that must be implemented by a database interface plugin.
 type iChartCollection interface {
 	// GetChartHistogram - Get charting data for a histogram
 	GetChartHistogram(ctx context.Context, namespace string, intervals []core.ChartHistogramInterval, collection CollectionName) ([]*core.ChartHistogram, error)
+
+	// GetChartCounts - Count the records of a collection in each interval, optionally grouped by a field
+	GetChartCounts(ctx context.Context, namespace string, query *ChartCountQuery) ([]*core.ChartCount, error)
+
+	// GetChartNextSequence - Get the sequence of the last record in the next page of a collection after a sequence,
+	// or of the last record in the collection if the limit is 0
+	GetChartNextSequence(ctx context.Context, namespace string, collection CollectionName, after int64, limit int) (int64, error)
+
+	// AddChartRollups - Add counts to the pre-aggregated counts of collections
+	AddChartRollups(ctx context.Context, namespace string, rollups []*core.ChartRollup) error
+
+	// GetChartRollups - Get the pre-aggregated counts of a collection within a time range
+	GetChartRollups(ctx context.Context, namespace string, collection CollectionName, groupBy string, startTime, endTime *fftypes.FFTime) ([]*core.ChartCount, error)
+}
+
+type iLeaderLockCollection interface {
+	// AcquireLeaderLock - Take or renew a leader lock, if it is not held, is held by the same holder, or has expired
+	AcquireLeaderLock(ctx context.Context, lock *core.LeaderLock, now *fftypes.FFTime) (acquired bool, err error)
+
+	// GetLeaderLock - Get a leader lock by name
+	GetLeaderLock(ctx context.Context, name string) (*core.LeaderLock, error)
+
+	// ReleaseLeaderLock - Release a leader lock, if it is held by the holder
+	ReleaseLeaderLock(ctx context.Context, name, holder string) error
+}
+
+// ChartCountQuery selects the records of a collection to count, and the intervals to count them in
+type ChartCountQuery struct {
+	Collection    CollectionName
+	GroupBy       string          // field to group the counts by, or empty for a single count per interval
+	StartTime     *fftypes.FFTime // start of the first interval - nil to align intervals to the unix epoch, with no lower bound
+	EndTime       *fftypes.FFTime // nil for no upper bound
+	Interval      time.Duration
+	AfterSequence int64 // only count records with a higher sequence
+	MaxSequence   int64 // if non-zero, only count records up to and including this sequence
 }
 
 // PeristenceInterface are the operations that must be implemented by a database interface plugin.
@@ -615,6 +732,9 @@ type PersistenceInterface interface {
 	iPinCollection
 	iOperationCollection
 	iSubscriptionCollection
+	iDeadLetterCollection
+	iMessageAckCollection
+	iOperationNotificationCollection
 	iEventCollection
 	iIdentitiesCollection
 	iVerifiersCollection
@@ -634,6 +754,7 @@ type PersistenceInterface interface {
 	iContractListenerCollection
 	iBlockchainEventCollection
 	iChartCollection
+	iLeaderLockCollection
 }
 
 // CollectionName represents all collections
@@ -680,6 +801,8 @@ const (
 	CollectionContractAPIs      UUIDCollectionNS = "contractapis"
 	CollectionContractListeners UUIDCollectionNS = "contractlisteners"
 	CollectionIdentities        UUIDCollectionNS = "identities"
+	CollectionDeadLetters       UUIDCollectionNS = "deadletters"
+	CollectionMessageAcks       UUIDCollectionNS = "message_acks"
 )
 
 // HashCollectionNS is a collection where the primary key is a hash, such that it can
@@ -739,6 +862,24 @@ type Callbacks interface {
 // Capabilities defines the capabilities a plugin can report as implementing or not
 type Capabilities struct {
 	Concurrency bool
+	// FullTextSearch is true if search terms in a DataValueQuery are matched against an index of the words in the
+	// data values. Otherwise the search terms are matched as a case-insensitive substring of the value.
+	FullTextSearch bool
+}
+
+// DataValueQuery matches data on the contents of its JSON value
+type DataValueQuery struct {
+	// Matches must all be true of the value
+	Matches []*JSONPathMatch
+	// Search is a set of search terms, that must all be in the value
+	Search string
+}
+
+// JSONPathMatch is true if the JSON value at a path of object keys within the data value is equal to the given
+// value. If the value is an object or array, it is true if the JSON at the path contains the given value.
+type JSONPathMatch struct {
+	Path  []string
+	Value *fftypes.JSONAny
 }
 
 // MessageQueryFactory filter fields for messages
@@ -759,6 +900,7 @@ var MessageQueryFactory = &ffapi.QueryFields{
 	"state":          &ffapi.StringField{},
 	"confirmed":      &ffapi.TimeField{},
 	"rejectreason":   &ffapi.StringField{},
+	"sendafter":      &ffapi.TimeField{},
 	"sequence":       &ffapi.Int64Field{},
 	"txtype":         &ffapi.StringField{},
 	"batch":          &ffapi.UUIDField{},
@@ -817,6 +959,7 @@ var DatatypeQueryFactory = &ffapi.QueryFields{
 	"name":      &ffapi.StringField{},
 	"version":   &ffapi.StringField{},
 	"created":   &ffapi.TimeField{},
+	"lifecycle": &ffapi.StringField{},
 }
 
 // OffsetQueryFactory filter fields for data offsets
@@ -828,17 +971,23 @@ var OffsetQueryFactory = &ffapi.QueryFields{
 
 // OperationQueryFactory filter fields for data operations
 var OperationQueryFactory = &ffapi.QueryFields{
-	"id":      &ffapi.UUIDField{},
-	"tx":      &ffapi.UUIDField{},
-	"type":    &ffapi.StringField{},
-	"status":  &ffapi.StringField{},
-	"error":   &ffapi.StringField{},
-	"plugin":  &ffapi.StringField{},
-	"input":   &ffapi.JSONField{},
-	"output":  &ffapi.JSONField{},
-	"created": &ffapi.TimeField{},
-	"updated": &ffapi.TimeField{},
-	"retry":   &ffapi.UUIDField{},
+	"id":           &ffapi.UUIDField{},
+	"tx":           &ffapi.UUIDField{},
+	"type":         &ffapi.StringField{},
+	"status":       &ffapi.StringField{},
+	"error":        &ffapi.StringField{},
+	"plugin":       &ffapi.StringField{},
+	"input":        &ffapi.JSONField{},
+	"output":       &ffapi.JSONField{},
+	"created":      &ffapi.TimeField{},
+	"updated":      &ffapi.TimeField{},
+	"retry":        &ffapi.UUIDField{},
+	"retrydepth":   &ffapi.Int64Field{},
+	"retryparent":  &ffapi.UUIDField{},
+	"lasterror":    &ffapi.JSONField{},
+	"retryhistory": &ffapi.JSONField{},
+	"retryat":      &ffapi.TimeField{},
+	"progress":     &ffapi.JSONField{},
 }
 
 // SubscriptionQueryFactory filter fields for data subscriptions
@@ -852,6 +1001,35 @@ var SubscriptionQueryFactory = &ffapi.QueryFields{
 	"created":   &ffapi.TimeField{},
 }
 
+// DeadLetterQueryFactory filter fields for dead-letter entries
+var DeadLetterQueryFactory = &ffapi.QueryFields{
+	"id":           &ffapi.UUIDField{},
+	"subscription": &ffapi.UUIDField{},
+	"event":        &ffapi.UUIDField{},
+	"eventtype":    &ffapi.StringField{},
+	"error":        &ffapi.StringField{},
+	"created":      &ffapi.TimeField{},
+	"updated":      &ffapi.TimeField{},
+}
+
+// MessageAckQueryFactory filter fields for message acknowledgements
+var MessageAckQueryFactory = &ffapi.QueryFields{
+	"id":      &ffapi.UUIDField{},
+	"message": &ffapi.UUIDField{},
+	"type":    &ffapi.StringField{},
+	"author":  &ffapi.StringField{},
+	"node":    &ffapi.UUIDField{},
+	"created": &ffapi.TimeField{},
+}
+
+// OperationNotificationQueryFactory filter fields for webhooks registered against operations
+var OperationNotificationQueryFactory = &ffapi.QueryFields{
+	"id":        &ffapi.UUIDField{},
+	"operation": &ffapi.UUIDField{},
+	"url":       &ffapi.StringField{},
+	"created":   &ffapi.TimeField{},
+}
+
 // EventQueryFactory filter fields for data events
 var EventQueryFactory = &ffapi.QueryFields{
 	"id":         &ffapi.UUIDField{},
@@ -875,6 +1053,32 @@ var PinQueryFactory = &ffapi.QueryFields{
 	"created":    &ffapi.TimeField{},
 }
 
+// SequenceField is the field holding the local sequence of a sequenced collection
+const SequenceField = "sequence"
+
+// IsSequenced returns true for collections that are returned newest first by their local sequence when no
+// sort is specified. These can be paged efficiently through keyset pagination on the sequence.
+func IsSequenced(qf ffapi.QueryFactory) bool {
+	return qf == EventQueryFactory || qf == PinQueryFactory
+}
+
+// UniqueField returns a field that is unique across the items of a collection, which can be added to any sort
+// to give the items a stable total order. An empty string is returned for collections with no such field.
+func UniqueField(qf ffapi.QueryFactory) string {
+	switch {
+	case IsSequenced(qf):
+		return SequenceField
+	case qf == GroupQueryFactory || qf == VerifierQueryFactory || qf == NonceQueryFactory:
+		return "hash"
+	case qf == TokenTransferQueryFactory || qf == TokenApprovalQueryFactory:
+		return "localid"
+	}
+	if fields, ok := qf.(*ffapi.QueryFields); ok && (*fields)["id"] != nil {
+		return "id"
+	}
+	return ""
+}
+
 // IdentityQueryFactory filter fields for identities
 var IdentityQueryFactory = &ffapi.QueryFields{
 	"id":                    &ffapi.UUIDField{},
@@ -893,11 +1097,13 @@ var IdentityQueryFactory = &ffapi.QueryFields{
 
 // VerifierQueryFactory filter fields for identities
 var VerifierQueryFactory = &ffapi.QueryFields{
-	"hash":     &ffapi.Bytes32Field{},
-	"identity": &ffapi.UUIDField{},
-	"type":     &ffapi.StringField{},
-	"value":    &ffapi.StringField{},
-	"created":  &ffapi.TimeField{},
+	"hash":       &ffapi.Bytes32Field{},
+	"identity":   &ffapi.UUIDField{},
+	"type":       &ffapi.StringField{},
+	"value":      &ffapi.StringField{},
+	"message":    &ffapi.UUIDField{},
+	"created":    &ffapi.TimeField{},
+	"deprecated": &ffapi.TimeField{},
 }
 
 // GroupQueryFactory filter fields for groups
@@ -906,6 +1112,7 @@ var GroupQueryFactory = &ffapi.QueryFields{
 	"message":     &ffapi.UUIDField{},
 	"description": &ffapi.StringField{},
 	"ledger":      &ffapi.UUIDField{},
+	"previous":    &ffapi.Bytes32Field{},
 	"created":     &ffapi.TimeField{},
 }
 
@@ -1022,6 +1229,7 @@ var FFIQueryFactory = &ffapi.QueryFields{
 	"networkname": &ffapi.StringField{},
 	"version":     &ffapi.StringField{},
 	"published":   &ffapi.BoolField{},
+	"lifecycle":   &ffapi.StringField{},
 }
 
 // FFIMethodQueryFactory filter fields for contract methods
@@ -1062,8 +1270,9 @@ var ContractListenerQueryFactory = &ffapi.QueryFields{
 	"backendid": &ffapi.StringField{},
 	"created":   &ffapi.TimeField{},
 	"updated":   &ffapi.TimeField{},
-	"state":     &ffapi.JSONField{},
+	"state":     &ffapi.StringField{},
 	"filters":   &ffapi.JSONField{},
+	"options":   &ffapi.JSONField{},
 }
 
 // BlockchainEventQueryFactory filter fields for contract events
@@ -1077,6 +1286,8 @@ var BlockchainEventQueryFactory = &ffapi.QueryFields{
 	"tx.id":           &ffapi.UUIDField{},
 	"tx.blockchainid": &ffapi.StringField{},
 	"timestamp":       &ffapi.TimeField{},
+	"outputtruncated": &ffapi.BoolField{},
+	"blocknumber":     &ffapi.Int64Field{},
 }
 
 // ContractAPIQueryFactory filter fields for Contract APIs
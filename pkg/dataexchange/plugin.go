@@ -66,9 +66,9 @@ type Plugin interface {
 	// Plugin will attempt (but is not guaranteed) to deliver events only for the given namespace and node
 	SetHandler(networkNamespace, nodeName string, handler Callbacks)
 
-	// SetOperationHandler registers a handler to receive async operation status
+	// SetOperationHandler registers a handler to receive async operation status, and the progress of blob transfers
 	// If namespace is set, plugin will attempt to deliver only events for that namespace
-	SetOperationHandler(namespace string, handler core.OperationCallbacks)
+	SetOperationHandler(namespace string, handler OperationCallbacks)
 
 	// Data exchange interface must not deliver any events until start is called
 	Start() error
@@ -76,6 +76,9 @@ type Plugin interface {
 	// Capabilities returns capabilities - not called until after Init
 	Capabilities() *Capabilities
 
+	// Ping checks the data exchange can be reached, with a lightweight call that has no side effects
+	Ping(ctx context.Context) error
+
 	// GetEndpointInfo returns the information about the local endpoint
 	GetEndpointInfo(ctx context.Context, nodeName string) (peer fftypes.JSONObject, err error)
 
@@ -103,6 +106,15 @@ type Plugin interface {
 	GetPeerID(peer fftypes.JSONObject) string
 }
 
+// OperationCallbacks is the interface provided to the data exchange plugin, to report the status of operations
+type OperationCallbacks interface {
+	core.OperationCallbacks
+
+	// TransferProgress reports the number of bytes of a blob sent to the recipient so far, before the transfer
+	// completes. Progress is informational - it is not acknowledged, and may be reported any number of times.
+	TransferProgress(nsOpID string, transferred, size int64)
+}
+
 // Callbacks is the interface provided to the data exchange plugin, to allow it to pass events back to firefly.
 type Callbacks interface {
 	// Event has sub-types as defined below, and can be processed and ack'd asynchronously
@@ -0,0 +1,156 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bufio"
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// blobChunkSize is the size of each chunk of plaintext that is encrypted separately, so a blob can be streamed
+// through encryption and decryption without holding the whole content in memory
+const blobChunkSize = 64 * 1024
+
+// blobNoncePrefixLength is the length of the random prefix of the nonce of each chunk. The rest of the 12 byte
+// nonce is the big-endian index of the chunk, and a final byte set to 1 only on the last chunk. This ensures
+// chunks cannot be reordered, and that a blob cannot be truncated at a chunk boundary without detection.
+const blobNoncePrefixLength = 7
+
+type blobChunker struct {
+	ctx         context.Context
+	gcm         cipher.AEAD
+	keyID       string
+	noncePrefix []byte
+	reader      *bufio.Reader
+	chunk       []byte
+	buf         []byte
+	out         []byte
+	index       uint32
+	done        bool
+}
+
+func newBlobChunker(ctx context.Context, gcm cipher.AEAD, keyID string, noncePrefix []byte, reader io.Reader, readSize int) *blobChunker {
+	bufferedReader, ok := reader.(*bufio.Reader)
+	if !ok {
+		bufferedReader = bufio.NewReader(reader)
+	}
+	return &blobChunker{
+		ctx:         ctx,
+		gcm:         gcm,
+		keyID:       keyID,
+		noncePrefix: noncePrefix,
+		reader:      bufferedReader,
+		chunk:       make([]byte, readSize),
+	}
+}
+
+// next reads the next chunk from the underlying reader, returning whether it is the last chunk
+func (bc *blobChunker) next() (chunk []byte, last bool, err error) {
+	n, err := io.ReadFull(bc.reader, bc.chunk)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return bc.chunk[:n], true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err = bc.reader.Peek(1); err == io.EOF {
+		return bc.chunk, true, nil
+	}
+	return bc.chunk, false, err
+}
+
+func (bc *blobChunker) nonce(last bool) []byte {
+	nonce := make([]byte, 0, len(bc.noncePrefix)+5)
+	nonce = append(nonce, bc.noncePrefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, bc.index)
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+	bc.index++
+	return nonce
+}
+
+// drain copies the output of the current chunk, returning false if there is no output left
+func (bc *blobChunker) drain(p []byte) (int, bool) {
+	if len(bc.out) == 0 {
+		return 0, false
+	}
+	n := copy(p, bc.out)
+	bc.out = bc.out[n:]
+	return n, true
+}
+
+type blobEncrypter struct {
+	*blobChunker
+	pending []byte
+}
+
+func (be *blobEncrypter) Read(p []byte) (int, error) {
+	if len(be.pending) > 0 {
+		n := copy(p, be.pending)
+		be.pending = be.pending[n:]
+		return n, nil
+	}
+	for {
+		if n, ok := be.drain(p); ok {
+			return n, nil
+		}
+		if be.done {
+			return 0, io.EOF
+		}
+		chunk, last, err := be.next()
+		if err != nil {
+			return 0, err
+		}
+		be.buf = be.gcm.Seal(be.buf[:0], be.nonce(last), chunk, []byte(be.keyID))
+		be.out = be.buf
+		be.done = last
+	}
+}
+
+type blobDecrypter struct {
+	*blobChunker
+}
+
+func (bd *blobDecrypter) Read(p []byte) (int, error) {
+	for {
+		if n, ok := bd.drain(p); ok {
+			return n, nil
+		}
+		if bd.done {
+			return 0, io.EOF
+		}
+		chunk, last, err := bd.next()
+		if err != nil {
+			return 0, err
+		}
+		bd.buf, err = bd.gcm.Open(bd.buf[:0], bd.nonce(last), chunk, []byte(bd.keyID))
+		if err != nil {
+			return 0, i18n.NewError(bd.ctx, coremsgs.MsgDecryptionFailed, bd.keyID, err)
+		}
+		bd.out = bd.buf
+		bd.done = last
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encryptTestBlob(t *testing.T, keyID string, content []byte) []byte {
+	reader, err := EncryptBlob(context.Background(), newTestKeyProvider(), keyID, bytes.NewReader(content))
+	assert.NoError(t, err)
+	encrypted, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return encrypted
+}
+
+func TestEncryptDecryptBlobOk(t *testing.T) {
+	ctx := context.Background()
+	kp := newTestKeyProvider()
+
+	for _, size := range []int{0, 9, blobChunkSize - 1, blobChunkSize, 2 * blobChunkSize, 3*blobChunkSize + 5} {
+		content := bytes.Repeat([]byte("some blob "), size/10+1)[:size]
+		encrypted := encryptTestBlob(t, "key2", content)
+		assert.True(t, IsEncryptedBlob(encrypted[:BlobMagicLength()]))
+		if size > 0 {
+			assert.False(t, bytes.Contains(encrypted, content))
+		}
+
+		reader, err := DecryptBlob(ctx, kp, iotest.OneByteReader(bytes.NewReader(encrypted)))
+		assert.NoError(t, err)
+		blob, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, content, blob, "size %d", size)
+	}
+}
+
+func TestEncryptBlobSmallReads(t *testing.T) {
+	ctx := context.Background()
+	kp := newTestKeyProvider()
+
+	reader, err := EncryptBlob(ctx, kp, "key1", bytes.NewReader([]byte("some blob")))
+	assert.NoError(t, err)
+	encrypted, err := io.ReadAll(iotest.OneByteReader(reader))
+	assert.NoError(t, err)
+
+	reader, err = DecryptBlob(ctx, kp, bufio.NewReader(bytes.NewReader(encrypted)))
+	assert.NoError(t, err)
+	blob, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "some blob", string(blob))
+}
+
+func TestEncryptBlobKeyNotFound(t *testing.T) {
+	_, err := EncryptBlob(context.Background(), newTestKeyProvider(), "unknown", strings.NewReader("some blob"))
+	assert.Regexp(t, "FF10551", err)
+}
+
+func TestEncryptBlobBadKey(t *testing.T) {
+	_, err := EncryptBlob(context.Background(), newTestKeyProvider(), "bad", strings.NewReader("some blob"))
+	assert.Regexp(t, "FF10550", err)
+}
+
+func TestEncryptBlobNonceFail(t *testing.T) {
+	defer func(r io.Reader) { nonceSource = r }(nonceSource)
+	nonceSource = iotest.ErrReader(fmt.Errorf("pop"))
+	_, err := EncryptBlob(context.Background(), newTestKeyProvider(), "key1", strings.NewReader("some blob"))
+	assert.Regexp(t, "pop", err)
+}
+
+func TestEncryptBlobReadFail(t *testing.T) {
+	reader, err := EncryptBlob(context.Background(), newTestKeyProvider(), "key1", iotest.ErrReader(fmt.Errorf("pop")))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestEncryptBlobReadFailAfterChunk(t *testing.T) {
+	reader, err := EncryptBlob(context.Background(), newTestKeyProvider(), "key1", io.MultiReader(
+		bytes.NewReader(make([]byte, blobChunkSize)),
+		iotest.ErrReader(fmt.Errorf("pop")),
+	))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestDecryptBlobNotEncrypted(t *testing.T) {
+	assert.False(t, IsEncryptedBlob([]byte("some blob")))
+	_, err := DecryptBlob(context.Background(), newTestKeyProvider(), strings.NewReader("some blob"))
+	assert.Regexp(t, "FF10552.*not encrypted", err)
+}
+
+func TestDecryptBlobMissingKeyID(t *testing.T) {
+	_, err := DecryptBlob(context.Background(), newTestKeyProvider(), strings.NewReader("FFENC1:key1"))
+	assert.Regexp(t, "FF10552.*missing key ID", err)
+}
+
+func TestDecryptBlobKeyIDTooLong(t *testing.T) {
+	_, err := DecryptBlob(context.Background(), newTestKeyProvider(), strings.NewReader("FFENC1:"+strings.Repeat("a", maxKeyIDLength+1)+"\n"))
+	assert.Regexp(t, "FF10552.*missing key ID", err)
+}
+
+func TestDecryptBlobKeyNotFound(t *testing.T) {
+	encrypted := encryptTestBlob(t, "key1", []byte("some blob"))
+	_, err := DecryptBlob(context.Background(), &testKeyProvider{}, bytes.NewReader(encrypted))
+	assert.True(t, IsKeyNotFound(err))
+}
+
+func TestDecryptBlobBadKey(t *testing.T) {
+	_, err := DecryptBlob(context.Background(), newTestKeyProvider(), strings.NewReader("FFENC1:bad\n"))
+	assert.Regexp(t, "FF10550", err)
+}
+
+func TestDecryptBlobNoNonce(t *testing.T) {
+	_, err := DecryptBlob(context.Background(), newTestKeyProvider(), strings.NewReader("FFENC1:key1\n"))
+	assert.Regexp(t, "FF10552.*too short", err)
+}
+
+func TestDecryptBlobTampered(t *testing.T) {
+	ctx := context.Background()
+	encrypted := encryptTestBlob(t, "key1", []byte("some blob"))
+	encrypted[len(encrypted)-1] ^= 0xff
+
+	reader, err := DecryptBlob(ctx, newTestKeyProvider(), bytes.NewReader(encrypted))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Regexp(t, "FF10552", err)
+}
+
+func TestDecryptBlobTruncatedAtChunk(t *testing.T) {
+	ctx := context.Background()
+	encrypted := encryptTestBlob(t, "key1", make([]byte, 2*blobChunkSize))
+	headerLen := len(blobMagic) + len("key1\n") + blobNoncePrefixLength
+	truncated := encrypted[:headerLen+blobChunkSize+16]
+
+	reader, err := DecryptBlob(ctx, newTestKeyProvider(), bytes.NewReader(truncated))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Regexp(t, "FF10552", err)
+}
+
+func TestDecryptBlobReadFail(t *testing.T) {
+	ctx := context.Background()
+	encrypted := encryptTestBlob(t, "key1", make([]byte, 2*blobChunkSize))
+	headerLen := len(blobMagic) + len("key1\n") + blobNoncePrefixLength
+
+	reader, err := DecryptBlob(ctx, newTestKeyProvider(), io.MultiReader(
+		bytes.NewReader(encrypted[:headerLen+blobChunkSize+16]),
+		iotest.ErrReader(fmt.Errorf("pop")),
+	))
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Regexp(t, "pop", err)
+}
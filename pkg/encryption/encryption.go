@@ -0,0 +1,249 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// KeyProvider supplies the symmetric keys used to encrypt the data of broadcast messages before it is
+// published to shared storage, and to decrypt it again when it is downloaded by each member of the network.
+type KeyProvider interface {
+	// KeyForMessage returns the ID of the key to encrypt the data of a broadcast message with, such as a key
+	// shared by the members of a group or the subscribers to a topic, or an empty string to publish it unencrypted
+	KeyForMessage(ctx context.Context, header *core.MessageHeader) (keyID string, err error)
+
+	// Key returns the 32 byte AES-256 key with the supplied ID, or nil if this node does not hold the key
+	Key(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// blobMagic prefixes an encrypted blob in shared storage, followed by the key ID and a newline, then the nonce
+// prefix for the chunks of the blob
+var blobMagic = []byte("FFENC1:")
+
+// maxKeyIDLength bounds the key ID read from the header of an encrypted blob
+const maxKeyIDLength = 1024
+
+// valueField is the only field in the JSON object that replaces an encrypted value in shared storage
+const valueField = "$ffencrypted"
+
+// nonceSource is the source of the random nonce for each encryption
+var nonceSource = rand.Reader
+
+// EncryptedValue is the JSON envelope that replaces an encrypted value in shared storage
+type EncryptedValue struct {
+	KeyID      string `json:"keyId"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+func getKey(ctx context.Context, kp KeyProvider, keyID string) ([]byte, error) {
+	var key []byte
+	var err error
+	if kp != nil {
+		key, err = kp.Key(ctx, keyID)
+	}
+	if err == nil && key == nil {
+		err = i18n.NewError(ctx, coremsgs.MsgEncryptionKeyNotFound, keyID)
+	}
+	return key, err
+}
+
+// IsKeyNotFound returns true if an error is because this node does not hold the key, rather than a failure
+// to read or decrypt the content
+func IsKeyNotFound(err error) bool {
+	var ffErr i18n.FFError
+	return errors.As(err, &ffErr) && ffErr.MessageKey() == coremsgs.MsgEncryptionKeyNotFound
+}
+
+func newGCM(ctx context.Context, keyID string, key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEncryptionKey, keyID)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts with AES-256-GCM, returning the random nonce followed by the ciphertext. The key ID is
+// authenticated along with the data, so that an envelope cannot be relabelled with a different key.
+func seal(ctx context.Context, kp KeyProvider, keyID string, plaintext []byte) ([]byte, error) {
+	key, err := getKey(ctx, kp, keyID)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(ctx, keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(nonceSource, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, []byte(keyID)), nil
+}
+
+func open(ctx context.Context, kp KeyProvider, keyID string, sealed []byte) ([]byte, error) {
+	key, err := getKey(ctx, kp, keyID)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(ctx, keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDecryptionFailed, keyID, "too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDecryptionFailed, keyID, err)
+	}
+	return plaintext, nil
+}
+
+// EncryptValue replaces a JSON value with an envelope holding the value encrypted with the key
+func EncryptValue(ctx context.Context, kp KeyProvider, keyID string, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	sealed, err := seal(ctx, kp, keyID, value.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	envelope, _ := json.Marshal(map[string]*EncryptedValue{
+		valueField: {
+			KeyID:      keyID,
+			Ciphertext: base64.StdEncoding.EncodeToString(sealed),
+		},
+	})
+	return fftypes.JSONAnyPtrBytes(envelope), nil
+}
+
+// ParseEncryptedValue returns the envelope if the value is encrypted, or nil if it is not
+func ParseEncryptedValue(value *fftypes.JSONAny) *EncryptedValue {
+	var envelope map[string]*EncryptedValue
+	if value == nil || json.Unmarshal(value.Bytes(), &envelope) != nil || len(envelope) != 1 {
+		return nil
+	}
+	ev := envelope[valueField]
+	if ev == nil || ev.KeyID == "" || ev.Ciphertext == "" {
+		return nil
+	}
+	return ev
+}
+
+// DecryptValue returns the original JSON value from an encrypted value envelope
+func DecryptValue(ctx context.Context, kp KeyProvider, ev *EncryptedValue) (*fftypes.JSONAny, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ev.Ciphertext)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDecryptionFailed, ev.KeyID, err)
+	}
+	plaintext, err := open(ctx, kp, ev.KeyID, sealed)
+	if err != nil {
+		return nil, err
+	}
+	return fftypes.JSONAnyPtrBytes(plaintext), nil
+}
+
+// EncryptBlob returns a reader that encrypts the content of a blob with the key as it is read, one chunk at a
+// time, prefixed with the ID of the key
+func EncryptBlob(ctx context.Context, kp KeyProvider, keyID string, reader io.Reader) (io.Reader, error) {
+	key, err := getKey(ctx, kp, keyID)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(ctx, keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, len(blobMagic)+len(keyID)+1+blobNoncePrefixLength)
+	header = append(header, blobMagic...)
+	header = append(header, keyID...)
+	header = append(header, '\n')
+	noncePrefix := make([]byte, blobNoncePrefixLength)
+	if _, err := io.ReadFull(nonceSource, noncePrefix); err != nil {
+		return nil, err
+	}
+	return &blobEncrypter{
+		blobChunker: newBlobChunker(ctx, gcm, keyID, noncePrefix, reader, blobChunkSize),
+		pending:     append(header, noncePrefix...),
+	}, nil
+}
+
+// IsEncryptedBlob returns true if the start of the content of a blob shows it is encrypted
+func IsEncryptedBlob(start []byte) bool {
+	return bytes.HasPrefix(start, blobMagic)
+}
+
+// BlobMagicLength is the number of bytes at the start of the content of a blob that IsEncryptedBlob requires
+func BlobMagicLength() int {
+	return len(blobMagic)
+}
+
+// BlobKeyID returns the ID of the key an encrypted blob is encrypted with, from the header at the start of the
+// content. The header is peeked rather than read, so the reader still returns the whole of the encrypted blob.
+func BlobKeyID(ctx context.Context, reader *bufio.Reader) (string, error) {
+	header, _ := reader.Peek(len(blobMagic) + maxKeyIDLength + 1)
+	if !IsEncryptedBlob(header) {
+		return "", i18n.NewError(ctx, coremsgs.MsgDecryptionFailed, "", "not encrypted")
+	}
+	idEnd := bytes.IndexByte(header[len(blobMagic):], '\n')
+	if idEnd < 0 {
+		return "", i18n.NewError(ctx, coremsgs.MsgDecryptionFailed, "", "missing key ID")
+	}
+	return string(header[len(blobMagic) : len(blobMagic)+idEnd]), nil
+}
+
+// DecryptBlob returns a reader for the original content of an encrypted blob, which is decrypted one chunk at a
+// time as it is read. Each chunk is authenticated before it is returned, and a blob that has been truncated or
+// reordered fails with an error before the end of the content is reached.
+func DecryptBlob(ctx context.Context, kp KeyProvider, reader io.Reader) (io.Reader, error) {
+	bufferedReader, ok := reader.(*bufio.Reader)
+	if !ok {
+		bufferedReader = bufio.NewReader(reader)
+	}
+	keyID, err := BlobKeyID(ctx, bufferedReader)
+	if err != nil {
+		return nil, err
+	}
+	key, err := getKey(ctx, kp, keyID)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(ctx, keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = bufferedReader.Discard(len(blobMagic) + len(keyID) + 1)
+	noncePrefix := make([]byte, blobNoncePrefixLength)
+	if _, err := io.ReadFull(bufferedReader, noncePrefix); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDecryptionFailed, keyID, "too short")
+	}
+	return &blobDecrypter{
+		blobChunker: newBlobChunker(ctx, gcm, keyID, noncePrefix, bufferedReader, blobChunkSize+gcm.Overhead()),
+	}, nil
+}
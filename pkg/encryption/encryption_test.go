@@ -0,0 +1,144 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKeyProvider struct {
+	keys map[string][]byte
+	err  error
+}
+
+func (tkp *testKeyProvider) KeyForMessage(ctx context.Context, header *core.MessageHeader) (string, error) {
+	return "", nil
+}
+
+func (tkp *testKeyProvider) Key(ctx context.Context, keyID string) ([]byte, error) {
+	return tkp.keys[keyID], tkp.err
+}
+
+func newTestKeyProvider() *testKeyProvider {
+	return &testKeyProvider{
+		keys: map[string][]byte{
+			"key1": make([]byte, 32),
+			"key2": []byte("01234567890123456789012345678901"),
+			"bad":  []byte("short"),
+		},
+	}
+}
+
+func TestEncryptDecryptValueOk(t *testing.T) {
+	ctx := context.Background()
+	kp := newTestKeyProvider()
+
+	encrypted, err := EncryptValue(ctx, kp, "key1", fftypes.JSONAnyPtr(`{"some":"data"}`))
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted.String(), "some")
+
+	ev := ParseEncryptedValue(encrypted)
+	assert.NotNil(t, ev)
+	assert.Equal(t, "key1", ev.KeyID)
+
+	value, err := DecryptValue(ctx, kp, ev)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"some":"data"}`, value.String())
+}
+
+func TestEncryptValueKeyNotFound(t *testing.T) {
+	_, err := EncryptValue(context.Background(), newTestKeyProvider(), "unknown", fftypes.JSONAnyPtr(`"data"`))
+	assert.Regexp(t, "FF10551", err)
+}
+
+func TestEncryptValueNoKeyProvider(t *testing.T) {
+	_, err := EncryptValue(context.Background(), nil, "key1", fftypes.JSONAnyPtr(`"data"`))
+	assert.Regexp(t, "FF10551", err)
+}
+
+func TestEncryptValueKeyProviderError(t *testing.T) {
+	kp := newTestKeyProvider()
+	kp.err = fmt.Errorf("pop")
+	_, err := EncryptValue(context.Background(), kp, "key1", fftypes.JSONAnyPtr(`"data"`))
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEncryptValueBadKey(t *testing.T) {
+	_, err := EncryptValue(context.Background(), newTestKeyProvider(), "bad", fftypes.JSONAnyPtr(`"data"`))
+	assert.Regexp(t, "FF10550", err)
+}
+
+func TestEncryptValueNonceFail(t *testing.T) {
+	defer func(r io.Reader) { nonceSource = r }(nonceSource)
+	nonceSource = iotest.ErrReader(fmt.Errorf("pop"))
+	_, err := EncryptValue(context.Background(), newTestKeyProvider(), "key1", fftypes.JSONAnyPtr(`"data"`))
+	assert.EqualError(t, err, "pop")
+}
+
+func TestParseEncryptedValueNotEncrypted(t *testing.T) {
+	assert.Nil(t, ParseEncryptedValue(nil))
+	assert.Nil(t, ParseEncryptedValue(fftypes.JSONAnyPtr(`"string"`)))
+	assert.Nil(t, ParseEncryptedValue(fftypes.JSONAnyPtr(`{"some":"data"}`)))
+	assert.Nil(t, ParseEncryptedValue(fftypes.JSONAnyPtr(`{"$ffencrypted":{"keyId":"key1"}}`)))
+	assert.Nil(t, ParseEncryptedValue(fftypes.JSONAnyPtr(`{"$ffencrypted":{"keyId":"key1","ciphertext":"abc"},"other":{}}`)))
+}
+
+func TestDecryptValueBadBase64(t *testing.T) {
+	_, err := DecryptValue(context.Background(), newTestKeyProvider(), &EncryptedValue{KeyID: "key1", Ciphertext: "!"})
+	assert.Regexp(t, "FF10552", err)
+}
+
+func TestDecryptValueTooShort(t *testing.T) {
+	_, err := DecryptValue(context.Background(), newTestKeyProvider(), &EncryptedValue{KeyID: "key1", Ciphertext: "AAAA"})
+	assert.Regexp(t, "FF10552.*too short", err)
+}
+
+func TestDecryptValueWrongKey(t *testing.T) {
+	ctx := context.Background()
+	kp := newTestKeyProvider()
+	encrypted, err := EncryptValue(ctx, kp, "key1", fftypes.JSONAnyPtr(`"data"`))
+	assert.NoError(t, err)
+
+	ev := ParseEncryptedValue(encrypted)
+	ev.KeyID = "key2"
+	_, err = DecryptValue(ctx, kp, ev)
+	assert.Regexp(t, "FF10552", err)
+}
+
+func TestDecryptValueKeyNotFound(t *testing.T) {
+	ciphertext := base64.StdEncoding.EncodeToString(make([]byte, 64))
+	_, err := DecryptValue(context.Background(), newTestKeyProvider(), &EncryptedValue{KeyID: "unknown", Ciphertext: ciphertext})
+	assert.Regexp(t, "FF10551", err)
+	assert.True(t, IsKeyNotFound(err))
+}
+
+func TestDecryptValueBadKey(t *testing.T) {
+	ciphertext := base64.StdEncoding.EncodeToString(make([]byte, 64))
+	_, err := DecryptValue(context.Background(), newTestKeyProvider(), &EncryptedValue{KeyID: "bad", Ciphertext: ciphertext})
+	assert.Regexp(t, "FF10550", err)
+	assert.False(t, IsKeyNotFound(err))
+	assert.False(t, IsKeyNotFound(fmt.Errorf("pop")))
+}
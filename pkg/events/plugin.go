@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
@@ -62,6 +63,40 @@ type Plugin interface {
 
 type SubscriptionMatcher func(core.SubscriptionRef) bool
 
+// DeadLetterReplayer is implemented by plugins that move undeliverable events to the dead-letter queue
+// of a subscription, by setting DeadLetter on the DeliveryResponse. It makes a synchronous attempt to deliver
+// one of those events again, returning the reply to send if the subscription is configured to reply.
+type DeadLetterReplayer interface {
+	ReplayDelivery(ctx context.Context, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) (*core.MessageInOut, error)
+}
+
+// MetricsReporter is implemented by plugins that report transport specific metrics, such as the
+// status codes returned by webhooks. It is called once after Init.
+// DeletedSubscriptionHandler is implemented by a transport that holds resources for each durable subscription,
+// such as a topic on a broker, which it needs to release once the subscription is deleted
+type DeletedSubscriptionHandler interface {
+	SubscriptionDeleted(sub *core.Subscription)
+}
+
+type MetricsReporter interface {
+	SetMetrics(metrics metrics.Manager)
+}
+
+// NamespaceBridge is implemented by plugins that deliver events by broadcasting messages into other
+// namespaces on the same node. It is called once after Init.
+type NamespaceBridge interface {
+	SetLocalNamespaces(namespaces LocalNamespaces)
+}
+
+// LocalNamespaces provides access to the other namespaces running on this node
+type LocalNamespaces interface {
+	// CheckBroadcastNamespace verifies the namespace exists, and supports broadcasting messages
+	CheckBroadcastNamespace(ctx context.Context, namespace string) error
+
+	// BroadcastMessage submits a broadcast message in the namespace, without waiting for it to be confirmed
+	BroadcastMessage(ctx context.Context, namespace string, msg *core.MessageInOut) (*core.Message, error)
+}
+
 type Callbacks interface {
 
 	// RegisterConnection can be fired as often as required.
@@ -84,6 +119,8 @@ type Callbacks interface {
 	//   * If a message is included in the response, then that will be automatically sent with the correct CID
 	// - Reject it: This resets the associated subscription back to the last committed offset
 	//   * Note all message since the last committed offet will be redelivered, so additional messages to be redelivered if streaming ahead
+	// - Dead-letter it: The event is recorded in the dead-letter queue of the subscription, then acknowledged
+	//   * If recording it fails, the event is rejected instead
 	DeliveryResponse(connID string, inflight *core.EventDeliveryResponse)
 }
 
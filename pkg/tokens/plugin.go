@@ -52,6 +52,9 @@ type Plugin interface {
 	// Capabilities returns capabilities - not called until after Init
 	Capabilities() *Capabilities
 
+	// Ping checks the token connector can be reached, with a lightweight call that has no side effects
+	Ping(ctx context.Context) error
+
 	// ConnectorName returns the configured connector name (plugin instance)
 	ConnectorName() string
 
@@ -76,6 +79,9 @@ type Plugin interface {
 	// TransferTokens transfers tokens within a pool from one account to another
 	TransferTokens(ctx context.Context, nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) error
 
+	// SimulateTransfer asks the connector whether a transfer would succeed, without submitting it
+	SimulateTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) (*core.Simulation, error)
+
 	// TokenApproval approves an operator to transfer tokens on the owner's behalf
 	TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error
 }